@@ -52,6 +52,46 @@ type MultipleSnapshotResponse struct {
 	Items []storage.SnapshotExternal `json:"items"`
 }
 
+type MultipleVolumeMigrationResponse struct {
+	Items []storage.VolumeMigrationExternal `json:"items"`
+}
+
+type MultipleVolumeUsageResponse struct {
+	Items []storage.VolumeUsage `json:"items"`
+}
+
+// VolumeUsageSummary compares a volume's provisioned size with the space it consumes, which is
+// omitted if it hasn't been reported.
+type VolumeUsageSummary struct {
+	Volume           string               `json:"volume"`
+	ProvisionedBytes uint64               `json:"provisionedBytes"`
+	Usage            *storage.VolumeUsage `json:"usage,omitempty"`
+}
+
+type MultipleVolumeUsageSummaryResponse struct {
+	Items []VolumeUsageSummary `json:"items"`
+}
+
+type MultipleDriftResponse struct {
+	Items []storage.Drift `json:"items"`
+}
+
+type MultipleGarbageResponse struct {
+	Items []storage.Garbage `json:"items"`
+}
+
+type MultipleStateIssueResponse struct {
+	Items []storage.StateIssue `json:"items"`
+}
+
+type MultipleVolumeDeletionResponse struct {
+	Items []storage.VolumeDeletion `json:"items"`
+}
+
+type MultipleVolumeGroupResponse struct {
+	Items []storage.VolumeGroupExternal `json:"items"`
+}
+
 type Version struct {
 	Version       string `json:"version"`
 	MajorVersion  uint   `json:"majorVersion"`
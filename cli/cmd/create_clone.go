@@ -0,0 +1,114 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/netapp/trident/cli/api"
+	"github.com/netapp/trident/frontend/rest"
+	"github.com/netapp/trident/storage"
+)
+
+var (
+	createCloneSource       string
+	createCloneSnapshot     string
+	createCloneName         string
+	createCloneSplitOnClone bool
+	createClonePool         string
+)
+
+func init() {
+	createCmd.AddCommand(createCloneCmd)
+	createCloneCmd.Flags().StringVar(&createCloneSource, "source", "", "Name of the volume to clone")
+	createCloneCmd.Flags().StringVar(&createCloneSnapshot, "snapshot", "",
+		"Name of the source volume's snapshot to clone, instead of the volume's current contents")
+	createCloneCmd.Flags().StringVar(&createCloneName, "name", "", "Name of the new volume")
+	createCloneCmd.Flags().BoolVar(&createCloneSplitOnClone, "split-on-clone", false,
+		"Split the clone from its source, overriding the setting of the source's pool")
+	createCloneCmd.Flags().StringVar(&createClonePool, "pool", "",
+		"Pool of the source's backend to place the clone in, instead of the source's pool")
+}
+
+var createCloneCmd = &cobra.Command{
+	Use:   "clone --source <volume> [--snapshot <snapshot>] --name <name>",
+	Short: "Clone a volume or one of its snapshots",
+	Long: "Clone a volume, or one of its snapshots, into a new volume on the same backend.  " +
+		"The clone has the volume mode and storage class of its source.",
+	Aliases: []string{"c"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		splitOnClone := ""
+		if cmd.Flags().Changed("split-on-clone") {
+			splitOnClone = strconv.FormatBool(createCloneSplitOnClone)
+		}
+		if OperatingMode == ModeTunnel {
+			command := []string{"create", "clone", "--source", createCloneSource, "--name", createCloneName}
+			if createCloneSnapshot != "" {
+				command = append(command, "--snapshot", createCloneSnapshot)
+			}
+			if splitOnClone != "" {
+				command = append(command, "--split-on-clone="+splitOnClone)
+			}
+			if createClonePool != "" {
+				command = append(command, "--pool", createClonePool)
+			}
+			TunnelCommand(append(command, args...))
+			return nil
+		} else {
+			return cloneCreate(args, splitOnClone)
+		}
+	},
+}
+
+func cloneCreate(args []string, splitOnClone string) error {
+
+	if len(args) > 0 {
+		return errors.New("the source and clone are specified with --source and --name")
+	}
+	if createCloneSource == "" {
+		return errors.New("source volume not specified")
+	}
+
+	request := storage.VolumeCloneRequest{
+		Name:         createCloneName,
+		Snapshot:     createCloneSnapshot,
+		SplitOnClone: splitOnClone,
+		Pool:         createClonePool,
+	}
+	if err := request.Validate(); err != nil {
+		return err
+	}
+	requestBytes, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	url := BaseURL() + "/volume/" + createCloneSource + "/clone"
+
+	response, responseBody, err := api.InvokeRESTAPI("POST", url, requestBytes, Debug)
+	if err != nil {
+		return err
+	} else if response.StatusCode != http.StatusCreated {
+		return fmt.Errorf("could not clone volume %s: %v", createCloneSource,
+			GetErrorFromHTTPResponse(response, responseBody))
+	}
+
+	var cloneVolumeResponse rest.CloneVolumeResponse
+	err = json.Unmarshal(responseBody, &cloneVolumeResponse)
+	if err != nil {
+		return err
+	}
+	if cloneVolumeResponse.Volume == nil {
+		return fmt.Errorf("could not clone volume %s: no volume returned", createCloneSource)
+	}
+
+	WriteVolumes([]storage.VolumeExternal{*cloneVolumeResponse.Volume})
+
+	return nil
+}
@@ -0,0 +1,106 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/spf13/cobra"
+
+	"github.com/netapp/trident/cli/api"
+	"github.com/netapp/trident/frontend/rest"
+	"github.com/netapp/trident/storage"
+)
+
+var (
+	migrationBackend     string
+	migrationStoragePool string
+)
+
+func init() {
+	createCmd.AddCommand(createMigrationCmd)
+	createMigrationCmd.Flags().StringVar(&migrationBackend, "backend", "", "Destination backend")
+	createMigrationCmd.Flags().StringVar(&migrationStoragePool, "pool", "",
+		"Destination storage pool (defaults to a pool matching the volume's storage class)")
+}
+
+var createMigrationCmd = &cobra.Command{
+	Use:     "migration <volume> --backend <backend> [--pool <pool>]",
+	Short:   "Migrate a volume to another backend",
+	Aliases: []string{"m"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if OperatingMode == ModeTunnel {
+			command := []string{"create", "migration", "--backend", migrationBackend}
+			if migrationStoragePool != "" {
+				command = append(command, "--pool", migrationStoragePool)
+			}
+			TunnelCommand(append(command, args...))
+			return nil
+		} else {
+			return migrationCreate(args)
+		}
+	},
+}
+
+func migrationCreate(volumeNames []string) error {
+
+	switch len(volumeNames) {
+	case 0:
+		return errors.New("volume name not specified")
+	case 1:
+		break
+	default:
+		return errors.New("multiple volume names specified")
+	}
+
+	request := storage.VolumeMigrationRequest{
+		Volume:      volumeNames[0],
+		Backend:     migrationBackend,
+		StoragePool: migrationStoragePool,
+	}
+	if err := request.Validate(); err != nil {
+		return err
+	}
+	migration, err := CreateMigration(request)
+	if err != nil {
+		return err
+	}
+
+	WriteMigrations([]storage.VolumeMigrationExternal{migration})
+
+	return nil
+}
+
+// CreateMigration starts migrating a volume to another backend, or moving it to another storage pool.
+func CreateMigration(request storage.VolumeMigrationRequest) (storage.VolumeMigrationExternal, error) {
+
+	requestBytes, err := json.Marshal(request)
+	if err != nil {
+		return storage.VolumeMigrationExternal{}, err
+	}
+
+	url := BaseURL() + "/migration"
+
+	response, responseBody, err := api.InvokeRESTAPI("POST", url, requestBytes, Debug)
+	if err != nil {
+		return storage.VolumeMigrationExternal{}, err
+	} else if response.StatusCode != http.StatusCreated {
+		return storage.VolumeMigrationExternal{}, fmt.Errorf("could not migrate volume %s: %v", request.Volume,
+			GetErrorFromHTTPResponse(response, responseBody))
+	}
+
+	var addMigrationResponse rest.AddMigrationResponse
+	err = json.Unmarshal(responseBody, &addMigrationResponse)
+	if err != nil {
+		return storage.VolumeMigrationExternal{}, err
+	}
+	if addMigrationResponse.Migration == nil {
+		return storage.VolumeMigrationExternal{}, fmt.Errorf("could not migrate volume %s: no migration returned",
+			request.Volume)
+	}
+
+	return *addMigrationResponse.Migration, nil
+}
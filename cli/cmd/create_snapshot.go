@@ -0,0 +1,114 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/spf13/cobra"
+
+	"github.com/netapp/trident/cli/api"
+	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/frontend/rest"
+	"github.com/netapp/trident/storage"
+)
+
+var (
+	createSnapshotType            string
+	createSnapshotSnapmirrorLabel string
+	createSnapshotRetention       string
+)
+
+func init() {
+	createCmd.AddCommand(createSnapshotCmd)
+	createSnapshotCmd.Flags().StringVar(&createSnapshotType, "type", "",
+		"Snapshot type (snapshot or backup)")
+	createSnapshotCmd.Flags().StringVar(&createSnapshotSnapmirrorLabel, "snapmirror-label", "",
+		"Label that replication and backup policies use to select the snapshot")
+	createSnapshotCmd.Flags().StringVar(&createSnapshotRetention, "retention", "",
+		"How long backup tooling should keep the snapshot, such as 168h")
+}
+
+var createSnapshotCmd = &cobra.Command{
+	Use:     "snapshot <volume/snapshot>",
+	Short:   "Create a snapshot of a volume",
+	Aliases: []string{"s", "snap"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if OperatingMode == ModeTunnel {
+			command := []string{"create", "snapshot"}
+			if createSnapshotType != "" {
+				command = append(command, "--type", createSnapshotType)
+			}
+			if createSnapshotSnapmirrorLabel != "" {
+				command = append(command, "--snapmirror-label", createSnapshotSnapmirrorLabel)
+			}
+			if createSnapshotRetention != "" {
+				command = append(command, "--retention", createSnapshotRetention)
+			}
+			TunnelCommand(append(command, args...))
+			return nil
+		} else {
+			return snapshotCreate(args)
+		}
+	},
+}
+
+func snapshotCreate(args []string) error {
+
+	switch len(args) {
+	case 0:
+		return errors.New("volume/snapshot not specified")
+	case 1:
+	default:
+		return errors.New("only one snapshot may be created at a time")
+	}
+
+	volumeName, snapshotName, err := storage.ParseSnapshotID(args[0])
+	if err != nil {
+		return err
+	}
+
+	snapshotConfig := storage.SnapshotConfig{
+		Version:         config.OrchestratorAPIVersion,
+		Name:            snapshotName,
+		VolumeName:      volumeName,
+		Type:            storage.SnapshotType(createSnapshotType),
+		SnapmirrorLabel: createSnapshotSnapmirrorLabel,
+		Retention:       createSnapshotRetention,
+	}
+	if err = snapshotConfig.Validate(); err != nil {
+		return err
+	}
+	requestBytes, err := json.Marshal(snapshotConfig)
+	if err != nil {
+		return err
+	}
+
+	url := BaseURL() + "/snapshot"
+
+	response, responseBody, err := api.InvokeRESTAPI("POST", url, requestBytes, Debug)
+	if err != nil {
+		return err
+	} else if response.StatusCode != http.StatusCreated {
+		return fmt.Errorf("could not create snapshot %s: %v", snapshotConfig.ID(),
+			GetErrorFromHTTPResponse(response, responseBody))
+	}
+
+	var addSnapshotResponse rest.AddSnapshotResponse
+	err = json.Unmarshal(responseBody, &addSnapshotResponse)
+	if err != nil {
+		return err
+	}
+
+	snapshot, err := GetSnapshot(addSnapshotResponse.SnapshotID)
+	if err != nil {
+		return err
+	}
+
+	WriteSnapshots([]storage.SnapshotExternal{snapshot})
+
+	return nil
+}
@@ -0,0 +1,80 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/spf13/cobra"
+
+	"github.com/netapp/trident/cli/api"
+	"github.com/netapp/trident/frontend/rest"
+	"github.com/netapp/trident/storage"
+)
+
+func init() {
+	createCmd.AddCommand(createVolumeGroupCmd)
+}
+
+var createVolumeGroupCmd = &cobra.Command{
+	Use:     "volumegroup <name> <volume> [<volume>...]",
+	Short:   "Group volumes so they can be snapshotted and cloned together",
+	Aliases: []string{"vg"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if OperatingMode == ModeTunnel {
+			command := []string{"create", "volumegroup"}
+			TunnelCommand(append(command, args...))
+			return nil
+		} else {
+			return volumeGroupCreate(args)
+		}
+	},
+}
+
+func volumeGroupCreate(args []string) error {
+
+	switch len(args) {
+	case 0:
+		return errors.New("volume group name not specified")
+	case 1:
+		return errors.New("volume names not specified")
+	}
+
+	groupConfig := storage.VolumeGroupConfig{
+		Name:    args[0],
+		Volumes: args[1:],
+	}
+	if err := groupConfig.Validate(); err != nil {
+		return err
+	}
+	requestBytes, err := json.Marshal(groupConfig)
+	if err != nil {
+		return err
+	}
+
+	url := BaseURL() + "/volumegroup"
+
+	response, responseBody, err := api.InvokeRESTAPI("POST", url, requestBytes, Debug)
+	if err != nil {
+		return err
+	} else if response.StatusCode != http.StatusCreated {
+		return fmt.Errorf("could not create volume group %s: %v", groupConfig.Name,
+			GetErrorFromHTTPResponse(response, responseBody))
+	}
+
+	var volumeGroupResponse rest.VolumeGroupResponse
+	err = json.Unmarshal(responseBody, &volumeGroupResponse)
+	if err != nil {
+		return err
+	}
+	if volumeGroupResponse.VolumeGroup == nil {
+		return fmt.Errorf("could not create volume group %s: no volume group returned", groupConfig.Name)
+	}
+
+	WriteVolumeGroups([]storage.VolumeGroupExternal{*volumeGroupResponse.VolumeGroup})
+
+	return nil
+}
@@ -11,11 +11,16 @@ import (
 	"github.com/spf13/cobra"
 )
 
-var allBackends bool
+var (
+	allBackends         bool
+	forceDeleteBackends bool
+)
 
 func init() {
 	deleteCmd.AddCommand(deleteBackendCmd)
 	deleteBackendCmd.Flags().BoolVarP(&allBackends, "all", "", false, "Delete all backends")
+	deleteBackendCmd.Flags().BoolVar(&forceDeleteBackends, "force", false,
+		"Remove the custom resources of backends that Trident no longer manages, even if finalizers are keeping them")
 }
 
 var deleteBackendCmd = &cobra.Command{
@@ -28,6 +33,9 @@ var deleteBackendCmd = &cobra.Command{
 			if allBackends {
 				command = append(command, "--all")
 			}
+			if forceDeleteBackends {
+				command = append(command, "--force")
+			}
 			TunnelCommand(append(command, args...))
 			return nil
 		} else {
@@ -45,6 +53,10 @@ func backendDelete(backendNames []string) error {
 		if len(backendNames) > 0 {
 			return errors.New("cannot use --all switch and specify individual backends")
 		}
+		// Forcing only applies to the resources of backends that Trident no longer manages
+		if forceDeleteBackends {
+			return errors.New("cannot use --all switch with --force")
+		}
 
 		// Get list of backend names so we can delete them all
 		backendNames, err = GetBackends()
@@ -65,6 +77,9 @@ func backendDelete(backendNames []string) error {
 		}
 
 		url := BaseURL() + "/backend/" + backendName
+		if forceDeleteBackends {
+			url += "?force=true"
+		}
 
 		response, responseBody, err := api.InvokeRESTAPI("DELETE", url, nil, Debug)
 		if err != nil {
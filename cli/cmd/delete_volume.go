@@ -11,11 +11,16 @@ import (
 	"github.com/spf13/cobra"
 )
 
-var allVolumes bool
+var (
+	allVolumes         bool
+	forceDeleteVolumes bool
+)
 
 func init() {
 	deleteCmd.AddCommand(deleteVolumeCmd)
 	deleteVolumeCmd.Flags().BoolVarP(&allVolumes, "all", "", false, "Delete all volumes")
+	deleteVolumeCmd.Flags().BoolVar(&forceDeleteVolumes, "force", false,
+		"Remove the custom resources of volumes that Trident no longer manages, even if finalizers are keeping them")
 }
 
 var deleteVolumeCmd = &cobra.Command{
@@ -28,6 +33,9 @@ var deleteVolumeCmd = &cobra.Command{
 			if allVolumes {
 				command = append(command, "--all")
 			}
+			if forceDeleteVolumes {
+				command = append(command, "--force")
+			}
 			TunnelCommand(append(command, args...))
 			return nil
 		} else {
@@ -45,6 +53,10 @@ func volumeDelete(volumeNames []string) error {
 		if len(volumeNames) > 0 {
 			return errors.New("cannot use --all switch and specify individual volumes")
 		}
+		// Forcing only applies to the resources of volumes that Trident no longer manages
+		if forceDeleteVolumes {
+			return errors.New("cannot use --all switch with --force")
+		}
 
 		// Get list of volume names so we can delete them all
 		volumeNames, err = GetVolumes()
@@ -60,6 +72,9 @@ func volumeDelete(volumeNames []string) error {
 
 	for _, volumeName := range volumeNames {
 		url := BaseURL() + "/volume/" + volumeName
+		if forceDeleteVolumes {
+			url += "?force=true"
+		}
 
 		response, responseBody, err := api.InvokeRESTAPI("DELETE", url, nil, Debug)
 		if err != nil {
@@ -0,0 +1,53 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/spf13/cobra"
+
+	"github.com/netapp/trident/cli/api"
+)
+
+func init() {
+	deleteCmd.AddCommand(deleteVolumeGroupCmd)
+}
+
+var deleteVolumeGroupCmd = &cobra.Command{
+	Use:     "volumegroup <name> [<name>...]",
+	Short:   "Delete one or more volume groups from Trident, leaving their volumes and snapshots in place",
+	Aliases: []string{"vg", "volumegroups"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if OperatingMode == ModeTunnel {
+			command := []string{"delete", "volumegroup"}
+			TunnelCommand(append(command, args...))
+			return nil
+		} else {
+			return volumeGroupDelete(args)
+		}
+	},
+}
+
+func volumeGroupDelete(groupNames []string) error {
+
+	if len(groupNames) == 0 {
+		return errors.New("volume group name not specified")
+	}
+
+	for _, groupName := range groupNames {
+		url := BaseURL() + "/volumegroup/" + groupName
+
+		response, responseBody, err := api.InvokeRESTAPI("DELETE", url, nil, Debug)
+		if err != nil {
+			return err
+		} else if response.StatusCode != http.StatusOK {
+			return fmt.Errorf("could not delete volume group %s: %v", groupName,
+				GetErrorFromHTTPResponse(response, responseBody))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/spf13/cobra"
+
+	"github.com/netapp/trident/cli/api"
+	"github.com/netapp/trident/frontend/rest"
+	persistentstore "github.com/netapp/trident/persistent_store"
+)
+
+var exportStateFilename string
+
+func init() {
+	exportCmd.AddCommand(exportStateCmd)
+	exportStateCmd.Flags().StringVarP(&exportStateFilename, "filename", "f", "",
+		"Path of the file to write the state bundle to, instead of standard output")
+}
+
+var exportStateCmd = &cobra.Command{
+	Use:   "state",
+	Short: "Export Trident's state for disaster recovery",
+	Long: `Export Trident's state for disaster recovery
+
+The state bundle contains Trident's backends, storage classes, volumes,
+snapshots, nodes and transactions, and may be imported into a fresh
+Trident install with 'tridentctl import state'.  The bundle includes
+backend credentials, so it must be stored securely.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+
+		var bundleJSON []byte
+		var err error
+
+		if OperatingMode == ModeTunnel {
+			bundleJSON, err = TunnelCommandRaw([]string{"export", "state"})
+		} else {
+			bundleJSON, err = exportState()
+		}
+		if err != nil {
+			return err
+		}
+
+		if exportStateFilename == "" || exportStateFilename == "-" {
+			fmt.Println(string(bundleJSON))
+			return nil
+		}
+		return ioutil.WriteFile(exportStateFilename, bundleJSON, 0600)
+	},
+}
+
+// exportState returns Trident's state bundle as JSON.
+func exportState() ([]byte, error) {
+
+	url := BaseURL() + "/state"
+
+	response, responseBody, err := api.InvokeRESTAPI("GET", url, nil, Debug)
+	if err != nil {
+		return nil, err
+	} else if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not export state: %v", GetErrorFromHTTPResponse(response, responseBody))
+	}
+
+	var exportStateResponse rest.ExportStateResponse
+	if err = json.Unmarshal(responseBody, &exportStateResponse); err != nil {
+		return nil, err
+	}
+	if exportStateResponse.State == nil {
+		return nil, errors.New("could not export state: no state returned")
+	}
+
+	return json.MarshalIndent(exportStateResponse.State, "", "  ")
+}
+
+// parseStateBundle checks that the data read from a file is a state bundle.
+func parseStateBundle(bundleJSON []byte) (*persistentstore.StateBundle, error) {
+
+	bundle := &persistentstore.StateBundle{}
+	if err := json.Unmarshal(bundleJSON, bundle); err != nil {
+		return nil, fmt.Errorf("invalid state bundle; %v", err)
+	}
+	if err := bundle.Validate(); err != nil {
+		return nil, err
+	}
+	return bundle, nil
+}
@@ -3,11 +3,19 @@
 package cmd
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"text/tabwriter"
 
 	"github.com/ghodss/yaml"
 	"github.com/spf13/cobra"
+	"k8s.io/client-go/util/jsonpath"
 )
 
 func init() {
@@ -18,6 +26,9 @@ var getCmd = &cobra.Command{
 	Use:   "get",
 	Short: "Get one or more resources from Trident",
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := validateOutputFormat(); err != nil {
+			return err
+		}
 		err := discoverOperatingMode(cmd)
 		return err
 	},
@@ -35,3 +46,183 @@ func WriteYAML(out interface{}) {
 	yamlBytes, _ := yaml.JSONToYAML(jsonBytes)
 	fmt.Println(string(yamlBytes))
 }
+
+// WriteCustom writes a list of items in the custom-columns or jsonpath output format.  Fields are
+// named as they are in the JSON output, so the list's items are selected by {.items[*]}.
+func WriteCustom(out interface{}) {
+
+	if err := writeCustom(os.Stdout, OutputFormat, out); err != nil {
+		fmt.Fprintln(os.Stderr, "Error: "+err.Error())
+	}
+}
+
+// outputFormatName returns the output format without its template, such as jsonpath for
+// jsonpath={.items[*].name}.
+func outputFormatName() string {
+	return strings.SplitN(OutputFormat, "=", 2)[0]
+}
+
+// outputFormatTemplate returns the template of a custom-columns or jsonpath output format.
+func outputFormatTemplate(format string) string {
+	parts := strings.SplitN(format, "=", 2)
+	if len(parts) < 2 {
+		return ""
+	}
+	return parts[1]
+}
+
+// validateOutputFormat checks the template of a custom-columns or jsonpath output format before
+// anything is fetched from Trident.
+func validateOutputFormat() error {
+
+	var err error
+
+	switch outputFormatName() {
+	case FormatCustomColumns:
+		_, err = parseCustomColumns(outputFormatTemplate(OutputFormat))
+	case FormatJSONPath:
+		_, err = parseJSONPath("jsonpath", outputFormatTemplate(OutputFormat))
+	}
+	return err
+}
+
+type customColumn struct {
+	header string
+	path   *jsonpath.JSONPath
+}
+
+// parseCustomColumns parses a list of columns like NAME:.name,STATE:.state, in which each field may
+// be given with or without its leading dot and enclosing braces, as kubectl allows.
+func parseCustomColumns(spec string) ([]customColumn, error) {
+
+	if spec == "" {
+		return nil, errors.New("custom-columns format requires a list of columns, such as NAME:.name")
+	}
+
+	columns := make([]customColumn, 0)
+	for _, column := range strings.Split(spec, ",") {
+		parts := strings.SplitN(column, ":", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("invalid custom column %s; expected <header>:<field>", column)
+		}
+
+		field := strings.TrimSuffix(strings.TrimPrefix(parts[1], "{"), "}")
+		if !strings.HasPrefix(field, ".") {
+			field = "." + field
+		}
+		path, err := parseJSONPath(parts[0], "{"+field+"}")
+		if err != nil {
+			return nil, err
+		}
+		columns = append(columns, customColumn{header: parts[0], path: path})
+	}
+	return columns, nil
+}
+
+func parseJSONPath(name, template string) (*jsonpath.JSONPath, error) {
+
+	if template == "" {
+		return nil, errors.New("jsonpath format requires a template, such as {.items[*].name}")
+	}
+
+	path := jsonpath.New(name).AllowMissingKeys(true)
+	if err := path.Parse(template); err != nil {
+		return nil, fmt.Errorf("invalid jsonpath template %s; %v", template, err)
+	}
+	return path, nil
+}
+
+func writeCustom(w io.Writer, format string, out interface{}) error {
+
+	// Walk the JSON form of the items, so that fields are named as they are in the JSON output and
+	// numbers aren't reformatted
+	jsonBytes, err := json.Marshal(out)
+	if err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(jsonBytes))
+	decoder.UseNumber()
+	var data interface{}
+	if err = decoder.Decode(&data); err != nil {
+		return err
+	}
+
+	template := outputFormatTemplate(format)
+
+	switch strings.SplitN(format, "=", 2)[0] {
+	case FormatJSONPath:
+		path, err := parseJSONPath("jsonpath", template)
+		if err != nil {
+			return err
+		}
+		if err = path.Execute(w, data); err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w)
+		return err
+
+	case FormatCustomColumns:
+		columns, err := parseCustomColumns(template)
+		if err != nil {
+			return err
+		}
+
+		items := []interface{}{data}
+		if list, ok := data.(map[string]interface{}); ok {
+			if listItems, ok := list["items"].([]interface{}); ok {
+				items = listItems
+			}
+		}
+
+		tw := tabwriter.NewWriter(w, 0, 8, 3, ' ', 0)
+		headers := make([]string, 0, len(columns))
+		for _, column := range columns {
+			headers = append(headers, column.header)
+		}
+		fmt.Fprintln(tw, strings.Join(headers, "\t"))
+
+		for _, item := range items {
+			values := make([]string, 0, len(columns))
+			for _, column := range columns {
+				values = append(values, customColumnValue(column.path, item))
+			}
+			fmt.Fprintln(tw, strings.Join(values, "\t"))
+		}
+		return tw.Flush()
+	}
+
+	return fmt.Errorf("unsupported output format %s", format)
+}
+
+// customColumnValue returns the values a column's field selects from an item, separated by commas,
+// or <none> if the item doesn't have the field.
+func customColumnValue(path *jsonpath.JSONPath, item interface{}) string {
+
+	results, err := path.FindResults(item)
+	if err != nil {
+		return "<none>"
+	}
+
+	values := make([]string, 0)
+	for _, result := range results {
+		for _, value := range result {
+			for value.Kind() == reflect.Interface {
+				value = value.Elem()
+			}
+			if !value.IsValid() {
+				continue
+			}
+			switch value.Kind() {
+			case reflect.Map, reflect.Slice:
+				valueBytes, _ := json.Marshal(value.Interface())
+				values = append(values, string(valueBytes))
+			default:
+				values = append(values, fmt.Sprintf("%v", value.Interface()))
+			}
+		}
+	}
+	if len(values) == 0 {
+		return "<none>"
+	}
+	return strings.Join(values, ",")
+}
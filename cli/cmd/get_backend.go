@@ -4,10 +4,12 @@ package cmd
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/netapp/trident/cli/api"
 	"github.com/netapp/trident/frontend/rest"
@@ -40,6 +42,11 @@ func backendList(backendNames []string) error {
 
 	var err error
 
+	// A sanitized config is meant to be created as a backend elsewhere, so only one may be exported
+	if outputFormatName() == FormatSanitizedJSON && len(backendNames) != 1 {
+		return errors.New("the sanitized-json output format requires exactly one backend name")
+	}
+
 	// If no backends were specified, we'll get all of them
 	if len(backendNames) == 0 {
 		backendNames, err = GetBackends()
@@ -129,18 +136,44 @@ func GetBackendByBackendUUID(backendUUID string) (storage.BackendExternal, error
 }
 
 func WriteBackends(backends []storage.BackendExternal) {
-	switch OutputFormat {
+	switch outputFormatName() {
 	case FormatJSON:
 		WriteJSON(api.MultipleBackendResponse{Items: backends})
 	case FormatYAML:
 		WriteYAML(api.MultipleBackendResponse{Items: backends})
+	case FormatCustomColumns, FormatJSONPath:
+		WriteCustom(api.MultipleBackendResponse{Items: backends})
 	case FormatName:
 		writeBackendNames(backends)
+	case FormatSanitizedJSON:
+		for _, backend := range backends {
+			writeSanitizedBackendConfig(backend)
+		}
 	default:
 		writeBackendTable(backends)
 	}
 }
 
+// writeSanitizedBackendConfig writes a backend's config without its credentials, so that it may be
+// shared with support or created on another cluster once that cluster's credentials are added.
+func writeSanitizedBackendConfig(backend storage.BackendExternal) {
+
+	config, ok := backend.Config.(map[string]interface{})
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: could not read the config of backend %s\n", backend.Name)
+		return
+	}
+	config["backendName"] = backend.Name
+
+	removed := drivers.SanitizeConfigForExport(config)
+	WriteJSON(config)
+
+	if len(removed) > 0 {
+		fmt.Fprintf(os.Stderr, "Removed from the config of backend %s: %s.  Supply credentials, or a "+
+			"credentials secret, before creating the backend.\n", backend.Name, strings.Join(removed, ", "))
+	}
+}
+
 func getESeriesStorageDriverConfig(configAsMap map[string]interface{}) (*drivers.ESeriesStorageDriverConfig, error) {
 	jsonBytes, marshalError := json.MarshalIndent(configAsMap, "", "  ")
 	if marshalError != nil {
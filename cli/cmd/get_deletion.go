@@ -0,0 +1,180 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/netapp/trident/cli/api"
+	"github.com/netapp/trident/frontend/rest"
+	"github.com/netapp/trident/storage"
+)
+
+func init() {
+	getCmd.AddCommand(getDeletionCmd)
+}
+
+var getDeletionCmd = &cobra.Command{
+	Use:     "deletion [<volume>...]",
+	Short:   "Get the volume deletions that failed and are being retried by Trident",
+	Aliases: []string{"del", "deletions"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if OperatingMode == ModeTunnel {
+			command := []string{"get", "deletion"}
+			TunnelCommand(append(command, args...))
+			return nil
+		} else {
+			return deletionList(args)
+		}
+	},
+}
+
+func deletionList(volumeNames []string) error {
+
+	var err error
+
+	// If no volumes were specified, we'll get all pending deletions
+	if len(volumeNames) == 0 {
+		volumeNames, err = GetDeletions()
+		if err != nil {
+			return err
+		}
+	}
+
+	deletions := make([]storage.VolumeDeletion, 0, 10)
+
+	for _, volumeName := range volumeNames {
+		deletion, err := GetDeletion(volumeName)
+		if err != nil {
+			return err
+		}
+		deletions = append(deletions, deletion)
+	}
+
+	WriteDeletions(deletions)
+
+	return nil
+}
+
+func GetDeletions() ([]string, error) {
+
+	url := BaseURL() + "/deletion"
+
+	response, responseBody, err := api.InvokeRESTAPI("GET", url, nil, Debug)
+	if err != nil {
+		return nil, err
+	} else if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not get pending deletions: %v",
+			GetErrorFromHTTPResponse(response, responseBody))
+	}
+
+	var listDeletionsResponse rest.ListDeletionsResponse
+	err = json.Unmarshal(responseBody, &listDeletionsResponse)
+	if err != nil {
+		return nil, err
+	}
+
+	return listDeletionsResponse.Deletions, nil
+}
+
+func GetDeletion(volumeName string) (storage.VolumeDeletion, error) {
+
+	url := BaseURL() + "/deletion/" + volumeName
+
+	response, responseBody, err := api.InvokeRESTAPI("GET", url, nil, Debug)
+	if err != nil {
+		return storage.VolumeDeletion{}, err
+	} else if response.StatusCode != http.StatusOK {
+		return storage.VolumeDeletion{}, fmt.Errorf("could not get pending deletion of volume %s: %v",
+			volumeName, GetErrorFromHTTPResponse(response, responseBody))
+	}
+
+	var getDeletionResponse rest.GetDeletionResponse
+	err = json.Unmarshal(responseBody, &getDeletionResponse)
+	if err != nil {
+		return storage.VolumeDeletion{}, err
+	}
+
+	return *getDeletionResponse.Deletion, nil
+}
+
+func WriteDeletions(deletions []storage.VolumeDeletion) {
+	switch outputFormatName() {
+	case FormatJSON:
+		WriteJSON(api.MultipleVolumeDeletionResponse{Items: deletions})
+	case FormatYAML:
+		WriteYAML(api.MultipleVolumeDeletionResponse{Items: deletions})
+	case FormatCustomColumns, FormatJSONPath:
+		WriteCustom(api.MultipleVolumeDeletionResponse{Items: deletions})
+	case FormatName:
+		writeDeletionVolumeNames(deletions)
+	case FormatWide:
+		writeWideDeletionTable(deletions)
+	default:
+		writeDeletionTable(deletions)
+	}
+}
+
+func writeDeletionTable(deletions []storage.VolumeDeletion) {
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Volume", "Backend", "State", "Attempts", "Next attempt"})
+
+	for _, deletion := range deletions {
+
+		table.Append([]string{
+			deletion.VolumeName,
+			deletion.Backend,
+			string(deletion.State),
+			strconv.Itoa(deletion.Attempts),
+			deletion.NextAttempt,
+		})
+	}
+
+	table.Render()
+}
+
+func writeWideDeletionTable(deletions []storage.VolumeDeletion) {
+
+	table := tablewriter.NewWriter(os.Stdout)
+	header := []string{
+		"Volume",
+		"Backend",
+		"State",
+		"Attempts",
+		"First attempt",
+		"Last attempt",
+		"Next attempt",
+		"Last error",
+	}
+	table.SetHeader(header)
+
+	for _, deletion := range deletions {
+
+		table.Append([]string{
+			deletion.VolumeName,
+			deletion.Backend,
+			string(deletion.State),
+			strconv.Itoa(deletion.Attempts),
+			deletion.FirstAttempt,
+			deletion.LastAttempt,
+			deletion.NextAttempt,
+			deletion.LastError,
+		})
+	}
+
+	table.Render()
+}
+
+func writeDeletionVolumeNames(deletions []storage.VolumeDeletion) {
+	for _, d := range deletions {
+		fmt.Println(d.VolumeName)
+	}
+}
@@ -0,0 +1,168 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/netapp/trident/cli/api"
+	"github.com/netapp/trident/frontend/rest"
+	"github.com/netapp/trident/storage"
+)
+
+var detectDrift bool
+
+func init() {
+	getCmd.AddCommand(getDriftCmd)
+	getDriftCmd.Flags().BoolVar(&detectDrift, "detect", false,
+		"Compare Trident's state with the backends now, instead of showing the last periodic result")
+}
+
+var getDriftCmd = &cobra.Command{
+	Use:   "drift",
+	Short: "Get the discrepancies between Trident's state and its backends",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if OperatingMode == ModeTunnel {
+			command := []string{"get", "drift"}
+			if detectDrift {
+				command = append(command, "--detect")
+			}
+			TunnelCommand(command)
+			return nil
+		} else {
+			return driftList()
+		}
+	},
+}
+
+func driftList() error {
+
+	report, err := GetDriftReport(detectDrift)
+	if err != nil {
+		return err
+	}
+
+	for _, message := range report.Errors {
+		fmt.Fprintln(os.Stderr, "Warning: "+message)
+	}
+
+	drifts := make([]storage.Drift, 0, len(report.Drifts))
+	for _, drift := range report.Drifts {
+		drifts = append(drifts, *drift)
+	}
+
+	WriteDrifts(drifts)
+
+	return nil
+}
+
+func GetDriftReport(detect bool) (*storage.DriftReport, error) {
+
+	url := BaseURL() + "/drift"
+
+	method := "GET"
+	expectedStatusCode := http.StatusOK
+	if detect {
+		method = "POST"
+		expectedStatusCode = http.StatusCreated
+	}
+
+	response, responseBody, err := api.InvokeRESTAPI(method, url, nil, Debug)
+	if err != nil {
+		return nil, err
+	} else if response.StatusCode != expectedStatusCode {
+		return nil, fmt.Errorf("could not get drift report: %v", GetErrorFromHTTPResponse(response, responseBody))
+	}
+
+	var driftReportResponse rest.DriftReportResponse
+	err = json.Unmarshal(responseBody, &driftReportResponse)
+	if err != nil {
+		return nil, err
+	}
+
+	return driftReportResponse.Report, nil
+}
+
+func WriteDrifts(drifts []storage.Drift) {
+	switch outputFormatName() {
+	case FormatJSON:
+		WriteJSON(api.MultipleDriftResponse{Items: drifts})
+	case FormatYAML:
+		WriteYAML(api.MultipleDriftResponse{Items: drifts})
+	case FormatCustomColumns, FormatJSONPath:
+		WriteCustom(api.MultipleDriftResponse{Items: drifts})
+	case FormatName:
+		writeDriftInternalNames(drifts)
+	case FormatWide:
+		writeWideDriftTable(drifts)
+	default:
+		writeDriftTable(drifts)
+	}
+}
+
+func writeDriftTable(drifts []storage.Drift) {
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Type", "Backend", "Volume", "Snapshot", "Internal Name", "Repaired"})
+
+	for _, drift := range drifts {
+
+		table.Append([]string{
+			string(drift.Type),
+			drift.Backend,
+			drift.Volume,
+			drift.Snapshot,
+			drift.InternalName,
+			strconv.FormatBool(drift.Repaired),
+		})
+	}
+
+	table.Render()
+}
+
+func writeWideDriftTable(drifts []storage.Drift) {
+
+	table := tablewriter.NewWriter(os.Stdout)
+	header := []string{
+		"Type",
+		"Backend",
+		"Volume",
+		"Snapshot",
+		"Internal Name",
+		"Expected",
+		"Actual",
+		"Repaired",
+		"Message",
+	}
+	table.SetHeader(header)
+
+	for _, drift := range drifts {
+
+		table.Append([]string{
+			string(drift.Type),
+			drift.Backend,
+			drift.Volume,
+			drift.Snapshot,
+			drift.InternalName,
+			drift.Expected,
+			drift.Actual,
+			strconv.FormatBool(drift.Repaired),
+			drift.Message,
+		})
+	}
+
+	table.Render()
+}
+
+func writeDriftInternalNames(drifts []storage.Drift) {
+	for _, drift := range drifts {
+		fmt.Println(drift.InternalName)
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/netapp/trident/cli/api"
+	"github.com/netapp/trident/frontend/rest"
+	"github.com/netapp/trident/storage"
+)
+
+var collectGarbage bool
+
+func init() {
+	getCmd.AddCommand(getGarbageCmd)
+	getGarbageCmd.Flags().BoolVar(&collectGarbage, "collect", false,
+		"Search Trident's state for garbage now, instead of showing the last periodic result")
+}
+
+var getGarbageCmd = &cobra.Command{
+	Use:   "garbage",
+	Short: "Get the orphaned transactions and stale objects in Trident's state",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if OperatingMode == ModeTunnel {
+			command := []string{"get", "garbage"}
+			if collectGarbage {
+				command = append(command, "--collect")
+			}
+			TunnelCommand(command)
+			return nil
+		} else {
+			return garbageList()
+		}
+	},
+}
+
+func garbageList() error {
+
+	report, err := GetGarbageReport(collectGarbage)
+	if err != nil {
+		return err
+	}
+
+	for _, message := range report.Errors {
+		fmt.Fprintln(os.Stderr, "Warning: "+message)
+	}
+
+	garbage := make([]storage.Garbage, 0, len(report.Garbage))
+	for _, item := range report.Garbage {
+		garbage = append(garbage, *item)
+	}
+
+	WriteGarbage(garbage)
+
+	return nil
+}
+
+func GetGarbageReport(collect bool) (*storage.GarbageReport, error) {
+
+	url := BaseURL() + "/garbage"
+
+	method := "GET"
+	expectedStatusCode := http.StatusOK
+	if collect {
+		method = "POST"
+		expectedStatusCode = http.StatusCreated
+	}
+
+	response, responseBody, err := api.InvokeRESTAPI(method, url, nil, Debug)
+	if err != nil {
+		return nil, err
+	} else if response.StatusCode != expectedStatusCode {
+		return nil, fmt.Errorf("could not get garbage report: %v", GetErrorFromHTTPResponse(response, responseBody))
+	}
+
+	var garbageReportResponse rest.GarbageReportResponse
+	err = json.Unmarshal(responseBody, &garbageReportResponse)
+	if err != nil {
+		return nil, err
+	}
+
+	return garbageReportResponse.Report, nil
+}
+
+func WriteGarbage(garbage []storage.Garbage) {
+	switch outputFormatName() {
+	case FormatJSON:
+		WriteJSON(api.MultipleGarbageResponse{Items: garbage})
+	case FormatYAML:
+		WriteYAML(api.MultipleGarbageResponse{Items: garbage})
+	case FormatCustomColumns, FormatJSONPath:
+		WriteCustom(api.MultipleGarbageResponse{Items: garbage})
+	case FormatName:
+		writeGarbageNames(garbage)
+	case FormatWide:
+		writeWideGarbageTable(garbage)
+	default:
+		writeGarbageTable(garbage)
+	}
+}
+
+func writeGarbageTable(garbage []storage.Garbage) {
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Type", "Name", "Volume", "Node", "Cleaned"})
+
+	for _, item := range garbage {
+
+		table.Append([]string{
+			string(item.Type),
+			item.Name,
+			item.Volume,
+			item.Node,
+			strconv.FormatBool(item.Cleaned),
+		})
+	}
+
+	table.Render()
+}
+
+func writeWideGarbageTable(garbage []storage.Garbage) {
+
+	table := tablewriter.NewWriter(os.Stdout)
+	header := []string{
+		"Type",
+		"Name",
+		"Operation",
+		"Volume",
+		"Snapshot",
+		"Node",
+		"Cleaned",
+		"Message",
+	}
+	table.SetHeader(header)
+
+	for _, item := range garbage {
+
+		table.Append([]string{
+			string(item.Type),
+			item.Name,
+			string(item.Operation),
+			item.Volume,
+			item.Snapshot,
+			item.Node,
+			strconv.FormatBool(item.Cleaned),
+			item.Message,
+		})
+	}
+
+	table.Render()
+}
+
+func writeGarbageNames(garbage []storage.Garbage) {
+	for _, item := range garbage {
+		fmt.Println(item.Name)
+	}
+}
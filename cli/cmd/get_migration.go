@@ -0,0 +1,187 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/dustin/go-humanize"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/netapp/trident/cli/api"
+	"github.com/netapp/trident/frontend/rest"
+	"github.com/netapp/trident/storage"
+)
+
+func init() {
+	getCmd.AddCommand(getMigrationCmd)
+}
+
+var getMigrationCmd = &cobra.Command{
+	Use:     "migration [<volume>...]",
+	Short:   "Get the status of one or more volume migrations and moves from Trident",
+	Aliases: []string{"m", "migrations", "move", "moves"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if OperatingMode == ModeTunnel {
+			command := []string{"get", "migration"}
+			TunnelCommand(append(command, args...))
+			return nil
+		} else {
+			return migrationList(args)
+		}
+	},
+}
+
+func migrationList(volumeNames []string) error {
+
+	var err error
+
+	// If no volumes were specified, we'll get all migrations
+	if len(volumeNames) == 0 {
+		volumeNames, err = GetMigrations()
+		if err != nil {
+			return err
+		}
+	}
+
+	migrations := make([]storage.VolumeMigrationExternal, 0, 10)
+
+	for _, volumeName := range volumeNames {
+		migration, err := GetMigration(volumeName)
+		if err != nil {
+			return err
+		}
+		migrations = append(migrations, migration)
+	}
+
+	WriteMigrations(migrations)
+
+	return nil
+}
+
+func GetMigrations() ([]string, error) {
+
+	url := BaseURL() + "/migration"
+
+	response, responseBody, err := api.InvokeRESTAPI("GET", url, nil, Debug)
+	if err != nil {
+		return nil, err
+	} else if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not get migrations: %v", GetErrorFromHTTPResponse(response, responseBody))
+	}
+
+	var listMigrationsResponse rest.ListMigrationsResponse
+	err = json.Unmarshal(responseBody, &listMigrationsResponse)
+	if err != nil {
+		return nil, err
+	}
+
+	return listMigrationsResponse.Migrations, nil
+}
+
+func GetMigration(volumeName string) (storage.VolumeMigrationExternal, error) {
+
+	url := BaseURL() + "/migration/" + volumeName
+
+	response, responseBody, err := api.InvokeRESTAPI("GET", url, nil, Debug)
+	if err != nil {
+		return storage.VolumeMigrationExternal{}, err
+	} else if response.StatusCode != http.StatusOK {
+		return storage.VolumeMigrationExternal{}, fmt.Errorf("could not get migration of volume %s: %v",
+			volumeName, GetErrorFromHTTPResponse(response, responseBody))
+	}
+
+	var getMigrationResponse rest.GetMigrationResponse
+	err = json.Unmarshal(responseBody, &getMigrationResponse)
+	if err != nil {
+		return storage.VolumeMigrationExternal{}, err
+	}
+
+	return *getMigrationResponse.Migration, nil
+}
+
+func WriteMigrations(migrations []storage.VolumeMigrationExternal) {
+	switch outputFormatName() {
+	case FormatJSON:
+		WriteJSON(api.MultipleVolumeMigrationResponse{Items: migrations})
+	case FormatYAML:
+		WriteYAML(api.MultipleVolumeMigrationResponse{Items: migrations})
+	case FormatCustomColumns, FormatJSONPath:
+		WriteCustom(api.MultipleVolumeMigrationResponse{Items: migrations})
+	case FormatName:
+		writeMigrationVolumeNames(migrations)
+	case FormatWide:
+		writeWideMigrationTable(migrations)
+	default:
+		writeMigrationTable(migrations)
+	}
+}
+
+func writeMigrationTable(migrations []storage.VolumeMigrationExternal) {
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Volume", "Source", "Destination", "Method", "State", "Progress"})
+
+	for _, migration := range migrations {
+
+		table.Append([]string{
+			migration.VolumeName,
+			migration.SourceBackend,
+			migration.DestinationBackend,
+			string(migration.Method),
+			string(migration.State),
+			strconv.Itoa(migration.Progress) + "%",
+		})
+	}
+
+	table.Render()
+}
+
+func writeWideMigrationTable(migrations []storage.VolumeMigrationExternal) {
+
+	table := tablewriter.NewWriter(os.Stdout)
+	header := []string{
+		"Volume",
+		"Source",
+		"Destination",
+		"Pool",
+		"Method",
+		"State",
+		"Progress",
+		"Copied",
+		"Started",
+		"Finished",
+		"Message",
+	}
+	table.SetHeader(header)
+
+	for _, migration := range migrations {
+
+		table.Append([]string{
+			migration.VolumeName,
+			migration.SourceBackend,
+			migration.DestinationBackend,
+			migration.DestinationPool,
+			string(migration.Method),
+			string(migration.State),
+			strconv.Itoa(migration.Progress) + "%",
+			humanize.IBytes(migration.BytesTransferred) + " / " + humanize.IBytes(migration.BytesTotal),
+			migration.StartTime,
+			migration.EndTime,
+			migration.Message,
+		})
+	}
+
+	table.Render()
+}
+
+func writeMigrationVolumeNames(migrations []storage.VolumeMigrationExternal) {
+	for _, m := range migrations {
+		fmt.Println(m.VolumeName)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"strconv"
 
 	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
@@ -24,7 +25,6 @@ var getNodeCmd = &cobra.Command{
 	Use:     "node [<name>...]",
 	Short:   "Get one or more CSI provider nodes from Trident",
 	Aliases: []string{"n", "nodes"},
-	Hidden:  true,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if OperatingMode == ModeTunnel {
 			command := []string{"get", "node"}
@@ -108,11 +108,13 @@ func GetNode(nodeName string) (*utils.Node, error) {
 }
 
 func WriteNodes(nodes []utils.Node) {
-	switch OutputFormat {
+	switch outputFormatName() {
 	case FormatJSON:
 		WriteJSON(api.MultipleNodeResponse{Items: nodes})
 	case FormatYAML:
 		WriteYAML(api.MultipleNodeResponse{Items: nodes})
+	case FormatCustomColumns, FormatJSONPath:
+		WriteCustom(api.MultipleNodeResponse{Items: nodes})
 	case FormatName:
 		writeNodeNames(nodes)
 	case FormatWide:
@@ -142,14 +144,45 @@ func writeWideNodeTable(nodes []utils.Node) {
 	header := []string{
 		"Name",
 		"IQN",
+		"NQN",
+		"Last Seen",
+		"Volumes",
+		"iSCSI Sessions",
+		"Failed Paths",
+		"Multipath",
 	}
 	table.SetHeader(header)
 
 	for _, node := range nodes {
 
+		sessions, failedPaths := "", ""
+		if node.Health != nil {
+			sessions = fmt.Sprintf("%d/%d up", node.Health.ISCSISessions-node.Health.ISCSISessionsDown,
+				node.Health.ISCSISessions)
+			failedPaths = strconv.Itoa(node.Health.FailedPaths)
+		}
+
+		multipath := ""
+		if node.Multipath != nil {
+			switch {
+			case node.Multipath.Compatible && node.Multipath.Remediated:
+				multipath = "remediated"
+			case node.Multipath.Compatible:
+				multipath = "compatible"
+			default:
+				multipath = "incompatible"
+			}
+		}
+
 		table.Append([]string{
 			node.Name,
 			node.IQN,
+			node.NQN,
+			node.LastSeen,
+			strconv.Itoa(node.PublishedVolumes),
+			sessions,
+			failedPaths,
+			multipath,
 		})
 	}
 
@@ -118,11 +118,13 @@ func GetSnapshot(snapshotID string) (storage.SnapshotExternal, error) {
 }
 
 func WriteSnapshots(snapshots []storage.SnapshotExternal) {
-	switch OutputFormat {
+	switch outputFormatName() {
 	case FormatJSON:
 		WriteJSON(api.MultipleSnapshotResponse{Items: snapshots})
 	case FormatYAML:
 		WriteYAML(api.MultipleSnapshotResponse{Items: snapshots})
+	case FormatCustomColumns, FormatJSONPath:
+		WriteCustom(api.MultipleSnapshotResponse{Items: snapshots})
 	case FormatName:
 		writeSnapshotIDs(snapshots)
 	case FormatWide:
@@ -156,17 +158,25 @@ func writeWideSnapshotTable(snapshots []storage.SnapshotExternal) {
 		"Volume",
 		"Created",
 		"Size",
+		"Type",
 		"State",
 	}
 	table.SetHeader(header)
 
 	for _, snapshot := range snapshots {
 
+		snapshotType := snapshot.Config.Type
+		if snapshotType == "" {
+			snapshotType = storage.SnapshotTypeSnapshot
+		}
+
 		table.Append([]string{
 			snapshot.Config.Name,
 			snapshot.Config.VolumeName,
 			snapshot.Created,
 			humanize.IBytes(uint64(snapshot.SizeBytes)),
+			string(snapshotType),
+			string(snapshot.State),
 		})
 	}
 
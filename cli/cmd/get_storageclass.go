@@ -105,11 +105,13 @@ func GetStorageClass(storageClassName string) (api.StorageClass, error) {
 }
 
 func WriteStorageClasses(storageClasses []api.StorageClass) {
-	switch OutputFormat {
+	switch outputFormatName() {
 	case FormatJSON:
 		WriteJSON(api.MultipleStorageClassResponse{Items: storageClasses})
 	case FormatYAML:
 		WriteYAML(api.MultipleStorageClassResponse{Items: storageClasses})
+	case FormatCustomColumns, FormatJSONPath:
+		WriteCustom(api.MultipleStorageClassResponse{Items: storageClasses})
 	case FormatName:
 		writeStorageClassNames(storageClasses)
 	default:
@@ -0,0 +1,63 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netapp/trident/cli/api"
+	"github.com/netapp/trident/utils"
+)
+
+func TestWriteCustom(t *testing.T) {
+
+	nodes := api.MultipleNodeResponse{Items: []utils.Node{
+		{Name: "node1", IQN: "iqn.node1", IPs: []string{"1.1.1.1", "2.2.2.2"}, PublishedVolumes: 2},
+		{Name: "node2", Health: &utils.NodeHealth{FailedPaths: 1}},
+	}}
+
+	tests := map[string]struct {
+		format string
+		output string
+	}{
+		"jsonpath": {
+			format: "jsonpath={.items[*].name}",
+			output: "node1 node2\n",
+		},
+		"jsonpath range": {
+			format: `jsonpath={range .items[*]}{.name}={.publishedVolumes}{"\n"}{end}`,
+			output: "node1=2\nnode2=\n\n",
+		},
+		"custom columns": {
+			format: "custom-columns=NAME:.name,IQN:iqn,IPS:{.ips},FAILED PATHS:.health.failedPaths",
+			output: "NAME    IQN         IPS                     FAILED PATHS\n" +
+				"node1   iqn.node1   [\"1.1.1.1\",\"2.2.2.2\"]   <none>\n" +
+				"node2   <none>      <none>                  1\n",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var out bytes.Buffer
+			assert.NoError(t, writeCustom(&out, test.format, nodes))
+			assert.Equal(t, test.output, out.String())
+		})
+	}
+}
+
+func TestValidateOutputFormat(t *testing.T) {
+
+	savedFormat := OutputFormat
+	defer func() { OutputFormat = savedFormat }()
+
+	for _, format := range []string{"", FormatJSON, FormatWide, "jsonpath={.items[*].name}", "custom-columns=NAME:.name"} {
+		OutputFormat = format
+		assert.NoError(t, validateOutputFormat(), format)
+	}
+	for _, format := range []string{"jsonpath", "jsonpath={.items[*", "custom-columns=", "custom-columns=NAME"} {
+		OutputFormat = format
+		assert.Error(t, validateOutputFormat(), format)
+	}
+}
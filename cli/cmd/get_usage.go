@@ -0,0 +1,191 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/dustin/go-humanize"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/netapp/trident/cli/api"
+	"github.com/netapp/trident/frontend/rest"
+	"github.com/netapp/trident/storage"
+)
+
+func init() {
+	getCmd.AddCommand(getUsageCmd)
+}
+
+var getUsageCmd = &cobra.Command{
+	Use:     "usage [<volume>...]",
+	Short:   "Get the space consumed by one or more volumes from Trident",
+	Aliases: []string{"u"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if OperatingMode == ModeTunnel {
+			command := []string{"get", "usage"}
+			TunnelCommand(append(command, args...))
+			return nil
+		} else {
+			return usageList(args)
+		}
+	},
+}
+
+func usageList(volumeNames []string) error {
+
+	var err error
+
+	// If no volumes were specified, we'll get the usage of all volumes
+	if len(volumeNames) == 0 {
+		volumeNames, err = GetVolumeUsageNames()
+		if err != nil {
+			return err
+		}
+	}
+
+	usage := make([]storage.VolumeUsage, 0, 10)
+
+	for _, volumeName := range volumeNames {
+		volumeUsage, err := GetVolumeUsage(volumeName)
+		if err != nil {
+			return err
+		}
+		usage = append(usage, volumeUsage)
+	}
+
+	WriteVolumeUsage(usage)
+
+	return nil
+}
+
+func GetVolumeUsageNames() ([]string, error) {
+
+	url := BaseURL() + "/usage"
+
+	response, responseBody, err := api.InvokeRESTAPI("GET", url, nil, Debug)
+	if err != nil {
+		return nil, err
+	} else if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not get volume usage: %v", GetErrorFromHTTPResponse(response, responseBody))
+	}
+
+	var listVolumeUsageResponse rest.ListVolumeUsageResponse
+	err = json.Unmarshal(responseBody, &listVolumeUsageResponse)
+	if err != nil {
+		return nil, err
+	}
+
+	return listVolumeUsageResponse.Volumes, nil
+}
+
+func GetVolumeUsage(volumeName string) (storage.VolumeUsage, error) {
+
+	url := BaseURL() + "/usage/" + volumeName
+
+	response, responseBody, err := api.InvokeRESTAPI("GET", url, nil, Debug)
+	if err != nil {
+		return storage.VolumeUsage{}, err
+	} else if response.StatusCode != http.StatusOK {
+		return storage.VolumeUsage{}, fmt.Errorf("could not get usage of volume %s: %v",
+			volumeName, GetErrorFromHTTPResponse(response, responseBody))
+	}
+
+	var getVolumeUsageResponse rest.GetVolumeUsageResponse
+	err = json.Unmarshal(responseBody, &getVolumeUsageResponse)
+	if err != nil {
+		return storage.VolumeUsage{}, err
+	}
+
+	return *getVolumeUsageResponse.Usage, nil
+}
+
+func WriteVolumeUsage(usage []storage.VolumeUsage) {
+	switch outputFormatName() {
+	case FormatJSON:
+		WriteJSON(api.MultipleVolumeUsageResponse{Items: usage})
+	case FormatYAML:
+		WriteYAML(api.MultipleVolumeUsageResponse{Items: usage})
+	case FormatCustomColumns, FormatJSONPath:
+		WriteCustom(api.MultipleVolumeUsageResponse{Items: usage})
+	case FormatName:
+		writeVolumeUsageNames(usage)
+	case FormatWide:
+		writeWideVolumeUsageTable(usage)
+	default:
+		writeVolumeUsageTable(usage)
+	}
+}
+
+func writeVolumeUsageTable(usage []storage.VolumeUsage) {
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Volume", "Size", "Used", "Available", "Used %"})
+
+	for _, u := range usage {
+
+		table.Append([]string{
+			u.VolumeName,
+			humanize.IBytes(u.TotalBytes),
+			humanize.IBytes(u.UsedBytes),
+			humanize.IBytes(u.AvailableBytes),
+			strconv.Itoa(u.UsedPercent()) + "%",
+		})
+	}
+
+	table.Render()
+}
+
+func writeWideVolumeUsageTable(usage []storage.VolumeUsage) {
+
+	table := tablewriter.NewWriter(os.Stdout)
+	header := []string{
+		"Volume",
+		"Size",
+		"Used",
+		"Available",
+		"Used %",
+		"Snapshots",
+		"Saved",
+		"Inodes",
+		"Source",
+		"Node",
+		"Updated",
+	}
+	table.SetHeader(header)
+
+	for _, u := range usage {
+
+		inodes := ""
+		if u.TotalInodes > 0 {
+			inodes = strconv.FormatUint(u.UsedInodes, 10) + " / " + strconv.FormatUint(u.TotalInodes, 10)
+		}
+
+		table.Append([]string{
+			u.VolumeName,
+			humanize.IBytes(u.TotalBytes),
+			humanize.IBytes(u.UsedBytes),
+			humanize.IBytes(u.AvailableBytes),
+			strconv.Itoa(u.UsedPercent()) + "%",
+			humanize.IBytes(u.SnapshotBytes),
+			humanize.IBytes(u.SavedBytes),
+			inodes,
+			string(u.Source),
+			u.Node,
+			u.Updated,
+		})
+	}
+
+	table.Render()
+}
+
+func writeVolumeUsageNames(usage []storage.VolumeUsage) {
+	for _, u := range usage {
+		fmt.Println(u.VolumeName)
+	}
+}
@@ -6,23 +6,42 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 	"os"
+	"sort"
 	"strconv"
+	"sync"
 
 	"github.com/dustin/go-humanize"
 	"github.com/netapp/trident/cli/api"
 	"github.com/netapp/trident/frontend/rest"
 	"github.com/netapp/trident/storage"
+	"github.com/netapp/trident/utils"
 	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
 )
 
+const (
+	// volumePageSize is the number of volume names requested at a time when listing volumes
+	volumePageSize = 500
+	// volumeFetchWorkers is the number of volumes requested at once
+	volumeFetchWorkers = 16
+	// volumeProgressInterval is the number of volumes fetched between progress updates
+	volumeProgressInterval = 100
+)
+
 var (
 	backendsByUUID map[string]*storage.BackendExternal
+	getVolumeUsage bool
+	getVolumeLimit int
 )
 
 func init() {
 	getCmd.AddCommand(getVolumeCmd)
+	getVolumeCmd.Flags().BoolVar(&getVolumeUsage, "usage", false,
+		"Show the provisioned and consumed space of the volumes")
+	getVolumeCmd.Flags().IntVar(&getVolumeLimit, "limit", 0,
+		"Maximum number of volumes to get when listing them, or 0 for all of them")
 	backendsByUUID = make(map[string]*storage.BackendExternal)
 }
 
@@ -33,8 +52,16 @@ var getVolumeCmd = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if OperatingMode == ModeTunnel {
 			command := []string{"get", "volume"}
+			if getVolumeUsage {
+				command = append(command, "--usage")
+			}
+			if getVolumeLimit > 0 {
+				command = append(command, "--limit", strconv.Itoa(getVolumeLimit))
+			}
 			TunnelCommand(append(command, args...))
 			return nil
+		} else if getVolumeUsage {
+			return volumeUsageSummaryList(args)
 		} else {
 			return volumeList(args)
 		}
@@ -43,28 +70,40 @@ var getVolumeCmd = &cobra.Command{
 
 func volumeList(volumeNames []string) error {
 
-	var err error
+	if getVolumeLimit < 0 {
+		return fmt.Errorf("invalid limit %d", getVolumeLimit)
+	}
 
-	// If no volumes were specified, we'll get all of them
+	// If no volumes were specified, we'll get all of them, a page at a time
+	forEachPage := func(visit func([]string) bool) error {
+		visit(volumeNames)
+		return nil
+	}
 	if len(volumeNames) == 0 {
-		volumeNames, err = GetVolumes()
-		if err != nil {
-			return err
+		forEachPage = func(visit func([]string) bool) error {
+			return listVolumePages(getVolumeLimit, visit)
 		}
-	}
 
-	volumes := make([]storage.VolumeExternal, 0, 10)
-
-	// Get the actual volume objects
-	for _, volumeName := range volumeNames {
-
-		volume, err := GetVolume(volumeName)
-		if err != nil {
-			return err
+		// Names are all that's needed to list them
+		if outputFormatName() == FormatName {
+			return forEachPage(func(names []string) bool {
+				for _, name := range names {
+					fmt.Println(name)
+				}
+				return true
+			})
 		}
+	}
+
+	// Volumes deleted since they were listed are skipped, but those named must exist
+	volumes, err := getVolumesConcurrently(forEachPage, len(volumeNames) == 0)
+	if err != nil && len(volumes) == 0 {
+		return err
+	}
 
-		if OutputFormat == FormatWide {
-			// look up and cache the backends by UUID
+	if OutputFormat == FormatWide {
+		// look up and cache the backends by UUID
+		for _, volume := range volumes {
 			if backendsByUUID[volume.BackendUUID] == nil {
 				backend, err := GetBackendByBackendUUID(volume.BackendUUID)
 				if err != nil {
@@ -73,13 +112,149 @@ func volumeList(volumeNames []string) error {
 				backendsByUUID[volume.BackendUUID] = &backend
 			}
 		}
-
-		volumes = append(volumes, volume)
 	}
 
 	WriteVolumes(volumes)
 
-	return nil
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Showing the %d volumes read before an error.\n", len(volumes))
+	}
+	return err
+}
+
+// listVolumePages passes the names of Trident's volumes to visit a page at a time, up to limit names
+// if limit is positive, until visit returns false.
+func listVolumePages(limit int, visit func([]string) bool) error {
+
+	continueToken := ""
+	for {
+		pageSize := volumePageSize
+		if limit > 0 && limit < pageSize {
+			pageSize = limit
+		}
+
+		names, nextToken, err := GetVolumePage(pageSize, continueToken)
+		if err != nil {
+			return err
+		}
+		if !visit(names) || nextToken == "" {
+			return nil
+		}
+		if limit > 0 {
+			if limit -= len(names); limit <= 0 {
+				return nil
+			}
+		}
+		continueToken = nextToken
+	}
+}
+
+type volumeFetch struct {
+	index int
+	name  string
+}
+
+type volumeFetchResult struct {
+	index  int
+	volume storage.VolumeExternal
+	err    error
+}
+
+// getVolumesConcurrently gets the volumes named by forEachPage several at a time, starting while the
+// names are still being listed, and returns them in the order they were named.  With skipMissing,
+// volumes deleted since they were named are left out.  If a volume can't be read, or the names can't
+// be listed, the volumes read before the error are returned with it.
+func getVolumesConcurrently(
+	forEachPage func(visit func([]string) bool) error, skipMissing bool,
+) ([]storage.VolumeExternal, error) {
+
+	fetches := make(chan volumeFetch)
+	results := make(chan volumeFetchResult)
+	done := make(chan struct{})
+
+	// Number the names so the volumes can be returned in order
+	var listErr error
+	go func() {
+		defer close(fetches)
+		index := 0
+		listErr = forEachPage(func(names []string) bool {
+			for _, name := range names {
+				select {
+				case fetches <- volumeFetch{index: index, name: name}:
+					index++
+				case <-done:
+					return false
+				}
+			}
+			return true
+		})
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < volumeFetchWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for fetch := range fetches {
+				volume, err := GetVolume(fetch.name)
+				results <- volumeFetchResult{index: fetch.index, volume: volume, err: err}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// Progress is only shown to people, and would interleave with debug output
+	showProgress := !Debug && isTerminal(os.Stderr)
+	progressShown := false
+
+	var err error
+	fetched := make(map[int]storage.VolumeExternal)
+	for result := range results {
+		if result.err != nil {
+			if skipMissing && utils.IsNotFoundError(result.err) {
+				continue
+			}
+			if err == nil {
+				err = result.err
+				close(done)
+			}
+			continue
+		}
+		fetched[result.index] = result.volume
+		if showProgress && len(fetched)%volumeProgressInterval == 0 {
+			fmt.Fprintf(os.Stderr, "\rGot %d volumes...", len(fetched))
+			progressShown = true
+		}
+	}
+	if progressShown {
+		fmt.Fprint(os.Stderr, "\r\033[K")
+	}
+
+	// The names are no longer being listed once every fetch is done
+	if err == nil {
+		err = listErr
+	}
+
+	indexes := make([]int, 0, len(fetched))
+	for index := range fetched {
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+	volumes := make([]storage.VolumeExternal, 0, len(indexes))
+	for _, index := range indexes {
+		volumes = append(volumes, fetched[index])
+	}
+
+	return volumes, err
+}
+
+// isTerminal reports whether a file is a terminal rather than a pipe or a regular file.
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
 }
 
 func GetVolumes() ([]string, error) {
@@ -103,6 +278,32 @@ func GetVolumes() ([]string, error) {
 	return listVolumesResponse.Volumes, nil
 }
 
+// GetVolumePage returns up to limit volume names, starting after the page that returned continueToken
+// if it is set, along with the token for the next page, which is empty after the last page.
+func GetVolumePage(limit int, continueToken string) ([]string, string, error) {
+
+	query := url.Values{}
+	query.Set("limit", strconv.Itoa(limit))
+	if continueToken != "" {
+		query.Set("continue", continueToken)
+	}
+
+	response, responseBody, err := api.InvokeRESTAPI("GET", BaseURL()+"/volume?"+query.Encode(), nil, Debug)
+	if err != nil {
+		return nil, "", err
+	} else if response.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("could not get volumes: %v",
+			GetErrorFromHTTPResponse(response, responseBody))
+	}
+
+	var listVolumesResponse rest.ListVolumesResponse
+	if err = json.Unmarshal(responseBody, &listVolumesResponse); err != nil {
+		return nil, "", err
+	}
+
+	return listVolumesResponse.Volumes, listVolumesResponse.Continue, nil
+}
+
 func GetVolume(volumeName string) (storage.VolumeExternal, error) {
 
 	url := BaseURL() + "/volume/" + volumeName
@@ -110,6 +311,9 @@ func GetVolume(volumeName string) (storage.VolumeExternal, error) {
 	response, responseBody, err := api.InvokeRESTAPI("GET", url, nil, Debug)
 	if err != nil {
 		return storage.VolumeExternal{}, err
+	} else if response.StatusCode == http.StatusNotFound {
+		return storage.VolumeExternal{}, utils.NotFoundError(fmt.Sprintf("could not get volume %s: %v",
+			volumeName, GetErrorFromHTTPResponse(response, responseBody)))
 	} else if response.StatusCode != http.StatusOK {
 		return storage.VolumeExternal{}, fmt.Errorf("could not get volume %s: %v", volumeName,
 			GetErrorFromHTTPResponse(response, responseBody))
@@ -125,11 +329,13 @@ func GetVolume(volumeName string) (storage.VolumeExternal, error) {
 }
 
 func WriteVolumes(volumes []storage.VolumeExternal) {
-	switch OutputFormat {
+	switch outputFormatName() {
 	case FormatJSON:
 		WriteJSON(api.MultipleVolumeResponse{Items: volumes})
 	case FormatYAML:
 		WriteYAML(api.MultipleVolumeResponse{Items: volumes})
+	case FormatCustomColumns, FormatJSONPath:
+		WriteCustom(api.MultipleVolumeResponse{Items: volumes})
 	case FormatName:
 		writeVolumeNames(volumes)
 	case FormatWide:
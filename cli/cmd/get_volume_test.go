@@ -0,0 +1,103 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/frontend/rest"
+	"github.com/netapp/trident/storage"
+)
+
+// newVolumeServer serves the named volumes, a page at a time, except that missing volumes are listed
+// but can't be read, and failing volumes can't be read at all.
+func newVolumeServer(names []string, missing, failing string) *httptest.Server {
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		volumeURL := config.BaseURL + "/volume"
+		if r.URL.Path == volumeURL {
+			limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+			start, _ := strconv.Atoi(r.URL.Query().Get("continue"))
+			response := rest.ListVolumesResponse{Volumes: names[start:]}
+			if limit > 0 && start+limit < len(names) {
+				response.Volumes = names[start : start+limit]
+				response.Continue = strconv.Itoa(start + limit)
+			}
+			_ = json.NewEncoder(w).Encode(response)
+			return
+		}
+
+		name := strings.TrimPrefix(r.URL.Path, volumeURL+"/")
+		switch name {
+		case missing:
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(rest.GetVolumeResponse{Error: "volume not found"})
+		case failing:
+			w.WriteHeader(http.StatusInternalServerError)
+			_ = json.NewEncoder(w).Encode(rest.GetVolumeResponse{Error: "failed"})
+		default:
+			_ = json.NewEncoder(w).Encode(rest.GetVolumeResponse{
+				Volume: &storage.VolumeExternal{Config: &storage.VolumeConfig{Name: name}},
+			})
+		}
+	}))
+}
+
+func volumeNamesOf(volumes []storage.VolumeExternal) []string {
+	names := make([]string, 0, len(volumes))
+	for _, volume := range volumes {
+		names = append(names, volume.Config.Name)
+	}
+	return names
+}
+
+func TestGetVolumesConcurrently(t *testing.T) {
+
+	names := make([]string, 0, 2*volumePageSize+10)
+	for i := 0; i < cap(names); i++ {
+		names = append(names, fmt.Sprintf("vol%04d", i))
+	}
+	server := newVolumeServer(names, "vol0007", "vol0900")
+	defer server.Close()
+
+	oldServer := Server
+	defer func() { Server = oldServer }()
+	Server = strings.TrimPrefix(server.URL, "http://")
+
+	listAll := func(limit int) func(func([]string) bool) error {
+		return func(visit func([]string) bool) error {
+			return listVolumePages(limit, visit)
+		}
+	}
+	listNames := func(names ...string) func(func([]string) bool) error {
+		return func(visit func([]string) bool) error {
+			visit(names)
+			return nil
+		}
+	}
+
+	// Volumes deleted since they were listed are skipped, and the rest keep their order
+	volumes, err := getVolumesConcurrently(listAll(600), true)
+	assert.NoError(t, err)
+	expected := append(append([]string{}, names[:7]...), names[8:600]...)
+	assert.Equal(t, expected, volumeNamesOf(volumes))
+
+	// Named volumes must exist
+	_, err = getVolumesConcurrently(listNames("vol0001", "vol0007"), false)
+	assert.Error(t, err)
+
+	// Volumes read before an error are returned with it
+	volumes, err = getVolumesConcurrently(listAll(0), true)
+	assert.Error(t, err)
+	assert.NotEmpty(t, volumes)
+	assert.NotContains(t, volumeNamesOf(volumes), "vol0900")
+}
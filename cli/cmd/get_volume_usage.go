@@ -0,0 +1,184 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/dustin/go-humanize"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/netapp/trident/cli/api"
+	"github.com/netapp/trident/frontend/rest"
+	"github.com/netapp/trident/storage"
+)
+
+func init() {
+	getVolumeCmd.AddCommand(getVolumeUsageCmd)
+}
+
+var getVolumeUsageCmd = &cobra.Command{
+	Use:   "usage [<volume>...]",
+	Short: "Get the provisioned and consumed space of one or more volumes from Trident",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if OperatingMode == ModeTunnel {
+			command := []string{"get", "volume", "usage"}
+			TunnelCommand(append(command, args...))
+			return nil
+		} else {
+			return volumeUsageSummaryList(args)
+		}
+	},
+}
+
+func volumeUsageSummaryList(volumeNames []string) error {
+
+	var err error
+
+	// If no volumes were specified, we'll summarize all of them, or as many as are requested
+	if len(volumeNames) == 0 {
+		err = listVolumePages(getVolumeLimit, func(names []string) bool {
+			volumeNames = append(volumeNames, names...)
+			return true
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	summaries := make([]api.VolumeUsageSummary, 0, 10)
+
+	for _, volumeName := range volumeNames {
+
+		volume, err := GetVolume(volumeName)
+		if err != nil {
+			return err
+		}
+		provisionedBytes, _ := strconv.ParseUint(volume.Config.Size, 10, 64)
+
+		usage, err := getReportedVolumeUsage(volumeName)
+		if err != nil {
+			return err
+		}
+
+		summaries = append(summaries, api.VolumeUsageSummary{
+			Volume:           volumeName,
+			ProvisionedBytes: provisionedBytes,
+			Usage:            usage,
+		})
+	}
+
+	WriteVolumeUsageSummaries(summaries)
+
+	return nil
+}
+
+// getReportedVolumeUsage returns the space consumed by a volume, or nil if it hasn't been reported yet.
+func getReportedVolumeUsage(volumeName string) (*storage.VolumeUsage, error) {
+
+	url := BaseURL() + "/usage/" + volumeName
+
+	response, responseBody, err := api.InvokeRESTAPI("GET", url, nil, Debug)
+	if err != nil {
+		return nil, err
+	} else if response.StatusCode == http.StatusNotFound {
+		return nil, nil
+	} else if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not get usage of volume %s: %v",
+			volumeName, GetErrorFromHTTPResponse(response, responseBody))
+	}
+
+	var getVolumeUsageResponse rest.GetVolumeUsageResponse
+	err = json.Unmarshal(responseBody, &getVolumeUsageResponse)
+	if err != nil {
+		return nil, err
+	}
+
+	return getVolumeUsageResponse.Usage, nil
+}
+
+func WriteVolumeUsageSummaries(summaries []api.VolumeUsageSummary) {
+	switch outputFormatName() {
+	case FormatJSON:
+		WriteJSON(api.MultipleVolumeUsageSummaryResponse{Items: summaries})
+	case FormatYAML:
+		WriteYAML(api.MultipleVolumeUsageSummaryResponse{Items: summaries})
+	case FormatCustomColumns, FormatJSONPath:
+		WriteCustom(api.MultipleVolumeUsageSummaryResponse{Items: summaries})
+	case FormatName:
+		writeVolumeUsageSummaryNames(summaries)
+	case FormatWide:
+		writeWideVolumeUsageSummaryTable(summaries)
+	default:
+		writeVolumeUsageSummaryTable(summaries)
+	}
+}
+
+// volumeUsageColumns returns the used, available, used percentage, snapshot and saved space of a
+// volume, or blanks if its usage hasn't been reported.
+func volumeUsageColumns(usage *storage.VolumeUsage) []string {
+	if usage == nil {
+		return []string{"", "", "", "", ""}
+	}
+	return []string{
+		humanize.IBytes(usage.UsedBytes),
+		humanize.IBytes(usage.AvailableBytes),
+		strconv.Itoa(usage.UsedPercent()) + "%",
+		humanize.IBytes(usage.SnapshotBytes),
+		humanize.IBytes(usage.SavedBytes),
+	}
+}
+
+func writeVolumeUsageSummaryTable(summaries []api.VolumeUsageSummary) {
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Volume", "Provisioned", "Used", "Available", "Used %", "Snapshots", "Saved"})
+
+	for _, s := range summaries {
+		row := []string{s.Volume, humanize.IBytes(s.ProvisionedBytes)}
+		table.Append(append(row, volumeUsageColumns(s.Usage)...))
+	}
+
+	table.Render()
+}
+
+func writeWideVolumeUsageSummaryTable(summaries []api.VolumeUsageSummary) {
+
+	table := tablewriter.NewWriter(os.Stdout)
+	header := []string{
+		"Volume",
+		"Provisioned",
+		"Used",
+		"Available",
+		"Used %",
+		"Snapshots",
+		"Saved",
+		"Source",
+		"Updated",
+	}
+	table.SetHeader(header)
+
+	for _, s := range summaries {
+		row := []string{s.Volume, humanize.IBytes(s.ProvisionedBytes)}
+		row = append(row, volumeUsageColumns(s.Usage)...)
+		if s.Usage != nil {
+			row = append(row, string(s.Usage.Source), s.Usage.Updated)
+		} else {
+			row = append(row, "", "")
+		}
+		table.Append(row)
+	}
+
+	table.Render()
+}
+
+func writeVolumeUsageSummaryNames(summaries []api.VolumeUsageSummary) {
+	for _, s := range summaries {
+		fmt.Println(s.Volume)
+	}
+}
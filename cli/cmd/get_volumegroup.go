@@ -0,0 +1,171 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/netapp/trident/cli/api"
+	"github.com/netapp/trident/frontend/rest"
+	"github.com/netapp/trident/storage"
+)
+
+func init() {
+	getCmd.AddCommand(getVolumeGroupCmd)
+}
+
+var getVolumeGroupCmd = &cobra.Command{
+	Use:     "volumegroup [<name>...]",
+	Short:   "Get one or more volume groups from Trident",
+	Aliases: []string{"vg", "volumegroups"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if OperatingMode == ModeTunnel {
+			command := []string{"get", "volumegroup"}
+			TunnelCommand(append(command, args...))
+			return nil
+		} else {
+			return volumeGroupList(args)
+		}
+	},
+}
+
+func volumeGroupList(groupNames []string) error {
+
+	var err error
+
+	// If no groups were specified, we'll get all of them
+	if len(groupNames) == 0 {
+		groupNames, err = GetVolumeGroups()
+		if err != nil {
+			return err
+		}
+	}
+
+	volumeGroups := make([]storage.VolumeGroupExternal, 0, 10)
+
+	for _, groupName := range groupNames {
+		volumeGroup, err := GetVolumeGroup(groupName)
+		if err != nil {
+			return err
+		}
+		volumeGroups = append(volumeGroups, volumeGroup)
+	}
+
+	WriteVolumeGroups(volumeGroups)
+
+	return nil
+}
+
+func GetVolumeGroups() ([]string, error) {
+
+	url := BaseURL() + "/volumegroup"
+
+	response, responseBody, err := api.InvokeRESTAPI("GET", url, nil, Debug)
+	if err != nil {
+		return nil, err
+	} else if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not get volume groups: %v",
+			GetErrorFromHTTPResponse(response, responseBody))
+	}
+
+	var listVolumeGroupsResponse rest.ListVolumeGroupsResponse
+	err = json.Unmarshal(responseBody, &listVolumeGroupsResponse)
+	if err != nil {
+		return nil, err
+	}
+
+	return listVolumeGroupsResponse.VolumeGroups, nil
+}
+
+func GetVolumeGroup(groupName string) (storage.VolumeGroupExternal, error) {
+
+	url := BaseURL() + "/volumegroup/" + groupName
+
+	response, responseBody, err := api.InvokeRESTAPI("GET", url, nil, Debug)
+	if err != nil {
+		return storage.VolumeGroupExternal{}, err
+	} else if response.StatusCode != http.StatusOK {
+		return storage.VolumeGroupExternal{}, fmt.Errorf("could not get volume group %s: %v",
+			groupName, GetErrorFromHTTPResponse(response, responseBody))
+	}
+
+	var getVolumeGroupResponse rest.GetVolumeGroupResponse
+	err = json.Unmarshal(responseBody, &getVolumeGroupResponse)
+	if err != nil {
+		return storage.VolumeGroupExternal{}, err
+	}
+
+	return *getVolumeGroupResponse.VolumeGroup, nil
+}
+
+func WriteVolumeGroups(volumeGroups []storage.VolumeGroupExternal) {
+	switch outputFormatName() {
+	case FormatJSON:
+		WriteJSON(api.MultipleVolumeGroupResponse{Items: volumeGroups})
+	case FormatYAML:
+		WriteYAML(api.MultipleVolumeGroupResponse{Items: volumeGroups})
+	case FormatCustomColumns, FormatJSONPath:
+		WriteCustom(api.MultipleVolumeGroupResponse{Items: volumeGroups})
+	case FormatName:
+		writeVolumeGroupNames(volumeGroups)
+	case FormatWide:
+		writeWideVolumeGroupTable(volumeGroups)
+	default:
+		writeVolumeGroupTable(volumeGroups)
+	}
+}
+
+func writeVolumeGroupTable(volumeGroups []storage.VolumeGroupExternal) {
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Name", "Volumes", "Snapshots"})
+
+	for _, volumeGroup := range volumeGroups {
+
+		table.Append([]string{
+			volumeGroup.Name,
+			strconv.Itoa(len(volumeGroup.Volumes)),
+			strconv.Itoa(len(volumeGroup.Snapshots)),
+		})
+	}
+
+	table.Render()
+}
+
+func writeWideVolumeGroupTable(volumeGroups []storage.VolumeGroupExternal) {
+
+	table := tablewriter.NewWriter(os.Stdout)
+	header := []string{
+		"Name",
+		"Volumes",
+		"Backends",
+		"Snapshots",
+	}
+	table.SetHeader(header)
+
+	for _, volumeGroup := range volumeGroups {
+
+		table.Append([]string{
+			volumeGroup.Name,
+			strings.Join(volumeGroup.Volumes, "\n"),
+			strings.Join(volumeGroup.Backends, "\n"),
+			strings.Join(volumeGroup.Snapshots, "\n"),
+		})
+	}
+
+	table.Render()
+}
+
+func writeVolumeGroupNames(volumeGroups []storage.VolumeGroupExternal) {
+	for _, vg := range volumeGroups {
+		fmt.Println(vg.Name)
+	}
+}
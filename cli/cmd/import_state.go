@@ -0,0 +1,134 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/netapp/trident/cli/api"
+	"github.com/netapp/trident/frontend/rest"
+	persistentstore "github.com/netapp/trident/persistent_store"
+)
+
+var (
+	importStateFilename   string
+	importStateBase64Data string
+)
+
+func init() {
+	importCmd.AddCommand(importStateCmd)
+	importStateCmd.Flags().StringVarP(&importStateFilename, "filename", "f", "",
+		"Path of a state bundle written by 'tridentctl export state'")
+	importStateCmd.Flags().StringVarP(&importStateBase64Data, "base64", "", "", "Base64 encoding")
+	importStateCmd.Flags().MarkHidden("base64")
+}
+
+var importStateCmd = &cobra.Command{
+	Use:   "state",
+	Short: "Import Trident's state from a disaster recovery bundle",
+	Long: `Import Trident's state from a disaster recovery bundle
+
+The bundle's volumes are bound to the Trident backends of the same name,
+and any backends that don't exist yet are created from the bundle, so
+the volumes need not be imported one at a time.  Volumes and snapshots
+that already exist are never replaced.  Nodes and transactions are not
+imported.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+
+		bundleJSON, err := getStateBundleData(importStateFilename, importStateBase64Data)
+		if err != nil {
+			return err
+		}
+
+		if OperatingMode == ModeTunnel {
+			command := []string{"import", "state", "--base64", base64.StdEncoding.EncodeToString(bundleJSON)}
+			TunnelCommand(command)
+			return nil
+		} else {
+			return stateImport(bundleJSON)
+		}
+	},
+}
+
+func getStateBundleData(filename, b64Data string) ([]byte, error) {
+
+	var err error
+	var rawData []byte
+
+	if b64Data == "" && filename == "" {
+		return nil, errors.New("no state bundle was specified")
+	}
+
+	if b64Data != "" {
+		rawData, err = base64.StdEncoding.DecodeString(b64Data)
+	} else if filename == "-" {
+		rawData, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		rawData, err = ioutil.ReadFile(filename)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the bundle here, so a bad file is reported without contacting Trident
+	if _, err = parseStateBundle(rawData); err != nil {
+		return nil, err
+	}
+
+	return rawData, nil
+}
+
+func stateImport(bundleJSON []byte) error {
+
+	url := BaseURL() + "/state"
+
+	response, responseBody, err := api.InvokeRESTAPI("POST", url, bundleJSON, Debug)
+	if err != nil {
+		return err
+	}
+
+	var importStateResponse rest.ImportStateResponse
+	if err = json.Unmarshal(responseBody, &importStateResponse); err != nil {
+		return err
+	}
+
+	if importStateResponse.Result != nil {
+		writeStateImportResult(importStateResponse.Result)
+	}
+
+	if response.StatusCode != http.StatusCreated {
+		return fmt.Errorf("could not import state: %v", GetErrorFromHTTPResponse(response, responseBody))
+	}
+
+	return nil
+}
+
+func writeStateImportResult(result *persistentstore.StateImportResult) {
+	switch OutputFormat {
+	case FormatJSON:
+		WriteJSON(result)
+	case FormatYAML:
+		WriteYAML(result)
+	default:
+		fmt.Printf("Added backends: %d\n", len(result.AddedBackends))
+		fmt.Printf("Rebound backends: %d\n", len(result.ReboundBackends))
+		fmt.Printf("Storage classes: %d\n", len(result.StorageClasses))
+		fmt.Printf("Volumes: %d\n", len(result.Volumes))
+		fmt.Printf("Snapshots: %d\n", len(result.Snapshots))
+		if len(result.Skipped) > 0 {
+			fmt.Println("Skipped:")
+			for _, skipped := range result.Skipped {
+				fmt.Printf("  - %s\n", skipped)
+			}
+		}
+	}
+}
@@ -23,12 +23,15 @@ var (
 	importFilename   string
 	importBase64Data string
 	importNoManage   bool
+	importDryRun     bool
 )
 
 func init() {
 	importCmd.AddCommand(importVolumeCmd)
 	importVolumeCmd.Flags().StringVarP(&importFilename, "filename", "f", "", "Path to YAML or JSON PVC file")
 	importVolumeCmd.Flags().BoolVarP(&importNoManage, "no-manage", "", false, "Create PV/PVC only, don't assume volume lifecycle management")
+	importVolumeCmd.Flags().BoolVarP(&importDryRun, "dry-run", "", false,
+		"Check the volume and report what importing it would change, without importing it")
 	importVolumeCmd.Flags().StringVarP(&importBase64Data, "base64", "", "", "Base64 encoding")
 	importVolumeCmd.Flags().MarkHidden("base64")
 }
@@ -56,10 +59,13 @@ Volume path').`,
 			if importNoManage {
 				command = append(command, "--no-manage")
 			}
+			if importDryRun {
+				command = append(command, "--dry-run")
+			}
 			TunnelCommand(append(command, args...))
 			return nil
 		} else {
-			return volumeImport(args[0], args[1], importNoManage, pvcDataJSON)
+			return volumeImport(args[0], args[1], importNoManage, importDryRun, pvcDataJSON)
 		}
 	},
 }
@@ -94,18 +100,41 @@ func getPVCData(filename, b64Data string) ([]byte, error) {
 	return jsonData, nil
 }
 
-func volumeImport(backendName, internalVolumeName string, noManage bool, pvcDataJSON []byte) error {
+func volumeImport(backendName, internalVolumeName string, noManage, dryRun bool, pvcDataJSON []byte) error {
+
+	importVolumeResponse, err := importVolume(backendName, internalVolumeName, noManage, dryRun, pvcDataJSON)
+	if err != nil {
+		return err
+	}
+
+	if importVolumeResponse.DryRun {
+		writeImportDryRun(*importVolumeResponse)
+		return nil
+	}
+
+	volumes := make([]storage.VolumeExternal, 0, 10)
+	volumes = append(volumes, *importVolumeResponse.Volume)
+	WriteVolumes(volumes)
+
+	return nil
+}
+
+// importVolume asks Trident to import a single volume and returns its response.
+func importVolume(
+	backendName, internalVolumeName string, noManage, dryRun bool, pvcDataJSON []byte,
+) (*rest.ImportVolumeResponse, error) {
 
 	request := &storage.ImportVolumeRequest{
 		Backend:      backendName,
 		InternalName: internalVolumeName,
 		NoManage:     noManage,
+		DryRun:       dryRun,
 		PVCData:      base64.StdEncoding.EncodeToString(pvcDataJSON),
 	}
 
 	requestBytes, err := json.Marshal(request)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Send the request to Trident
@@ -113,21 +142,39 @@ func volumeImport(backendName, internalVolumeName string, noManage bool, pvcData
 
 	response, responseBody, err := api.InvokeRESTAPI("POST", url, requestBytes, Debug)
 	if err != nil {
-		return err
+		return nil, err
 	} else if response.StatusCode != http.StatusCreated {
-		return fmt.Errorf("could not import volume: %v", GetErrorFromHTTPResponse(response, responseBody))
+		return nil, fmt.Errorf("could not import volume: %v", GetErrorFromHTTPResponse(response, responseBody))
 	}
 
 	var importVolumeResponse rest.ImportVolumeResponse
-	err = json.Unmarshal(responseBody, &importVolumeResponse)
-	if err != nil {
-		return err
+	if err = json.Unmarshal(responseBody, &importVolumeResponse); err != nil {
+		return nil, err
+	}
+	if importVolumeResponse.Volume == nil && !importVolumeResponse.DryRun {
+		return nil, errors.New("could not import volume: no volume returned")
 	}
-	volume := *importVolumeResponse.Volume
 
-	volumes := make([]storage.VolumeExternal, 0, 10)
-	volumes = append(volumes, volume)
-	WriteVolumes(volumes)
+	return &importVolumeResponse, nil
+}
 
-	return nil
+func writeImportDryRun(response rest.ImportVolumeResponse) {
+	switch OutputFormat {
+	case FormatJSON:
+		WriteJSON(response)
+	case FormatYAML:
+		WriteYAML(response)
+	default:
+		if response.Volume != nil {
+			WriteVolumes([]storage.VolumeExternal{*response.Volume})
+		}
+		if len(response.Changes) == 0 {
+			fmt.Println("Dry run: importing the volume would not change it on the storage.")
+			return
+		}
+		fmt.Println("Dry run: importing the volume would make these changes on the storage:")
+		for _, change := range response.Changes {
+			fmt.Printf("  - %s\n", change)
+		}
+	}
 }
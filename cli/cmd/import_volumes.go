@@ -0,0 +1,441 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ghodss/yaml"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/netapp/trident/cli/api"
+	"github.com/netapp/trident/frontend/rest"
+)
+
+const (
+	defaultBulkImportParallelism = 4
+	maxBulkImportParallelism     = 32
+
+	BulkImportStatusImported = "imported"
+	BulkImportStatusChecked  = "checked"
+	BulkImportStatusSkipped  = "skipped"
+	BulkImportStatusFailed   = "failed"
+)
+
+var (
+	bulkImportFilename    string
+	bulkImportStatusFile  string
+	bulkImportParallelism int
+	bulkImportDryRun      bool
+)
+
+func init() {
+	importCmd.AddCommand(importVolumesCmd)
+	importVolumesCmd.Flags().StringVarP(&bulkImportFilename, "filename", "f", "",
+		"Path to YAML or JSON manifest of volumes to import")
+	importVolumesCmd.Flags().StringVar(&bulkImportStatusFile, "status-file", "",
+		"Path to the file recording the result of each import (defaults to <filename>.status.json)")
+	importVolumesCmd.Flags().IntVar(&bulkImportParallelism, "parallel", defaultBulkImportParallelism,
+		"Number of volumes to import at the same time")
+	importVolumesCmd.Flags().BoolVar(&bulkImportDryRun, "dry-run", false,
+		"Check each volume and report what importing it would change, without importing it")
+}
+
+var importVolumesCmd = &cobra.Command{
+	Use:   "volumes -f <manifest>",
+	Short: "Import many existing volumes to Trident",
+	Long: `Import many existing volumes to Trident
+
+The manifest lists the volumes to import, each with the name of its Trident
+backend, the name that uniquely identifies the volume on the storage, and the
+PVC to create for it, either inline ('pvc') or as a path relative to the
+manifest ('pvcFile'):
+
+  volumes:
+  - backend: ontap_nas
+    volume: flexvol_001
+    pvcFile: pvcs/flexvol_001.yaml
+  - backend: ontap_nas
+    volume: flexvol_002
+    noManage: true
+    pvc:
+      kind: PersistentVolumeClaim
+      ...
+
+The result of each import is recorded in a status file as soon as it is known.
+Running the same command again skips the volumes that were already imported,
+so an interrupted or partially failed run may simply be repeated.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return volumesImport()
+	},
+}
+
+// BulkImportManifest lists the volumes imported by 'tridentctl import volumes'.
+type BulkImportManifest struct {
+	Volumes []BulkImportItem `json:"volumes"`
+}
+
+type BulkImportItem struct {
+	Backend  string          `json:"backend"`
+	Volume   string          `json:"volume"`
+	NoManage bool            `json:"noManage,omitempty"`
+	PVC      json.RawMessage `json:"pvc,omitempty"`
+	PVCFile  string          `json:"pvcFile,omitempty"`
+}
+
+func (i *BulkImportItem) key() string {
+	return i.Backend + "/" + i.Volume
+}
+
+// BulkImportResult is the outcome of importing one volume from a manifest.
+type BulkImportResult struct {
+	Backend string   `json:"backend"`
+	Volume  string   `json:"volume"`
+	Status  string   `json:"status"`
+	PVName  string   `json:"pvName,omitempty"`
+	Changes []string `json:"changes,omitempty"`
+	Error   string   `json:"error,omitempty"`
+}
+
+type BulkImportStatus struct {
+	Items []BulkImportResult `json:"items"`
+}
+
+func volumesImport() error {
+
+	if bulkImportFilename == "" {
+		return errors.New("no manifest file was specified")
+	}
+	if bulkImportParallelism < 1 || bulkImportParallelism > maxBulkImportParallelism {
+		return fmt.Errorf("parallel must be between 1 and %d", maxBulkImportParallelism)
+	}
+
+	var rawManifest []byte
+	var err error
+	if bulkImportFilename == "-" {
+		rawManifest, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		rawManifest, err = ioutil.ReadFile(bulkImportFilename)
+	}
+	if err != nil {
+		return err
+	}
+
+	manifestDir := "."
+	if bulkImportFilename != "-" {
+		manifestDir = filepath.Dir(bulkImportFilename)
+	}
+	items, pvcData, err := parseBulkImportManifest(rawManifest, manifestDir)
+	if err != nil {
+		return err
+	}
+
+	statusFile := bulkImportStatusFile
+	if statusFile == "" && bulkImportFilename != "-" {
+		statusFile = bulkImportFilename + ".status.json"
+	}
+	tracker, err := newBulkImportTracker(statusFile, bulkImportDryRun)
+	if err != nil {
+		return err
+	}
+
+	results := make([]BulkImportResult, len(items))
+	semaphore := make(chan struct{}, bulkImportParallelism)
+	var wg sync.WaitGroup
+
+	for index := range items {
+		item := items[index]
+		if tracker.imported(item) {
+			results[index] = BulkImportResult{
+				Backend: item.Backend,
+				Volume:  item.Volume,
+				Status:  BulkImportStatusSkipped,
+				PVName:  tracker.pvName(item),
+			}
+			continue
+		}
+
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(index int) {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+			results[index] = importBulkItem(items[index], pvcData[index], bulkImportDryRun)
+			if err := tracker.record(results[index]); err != nil {
+				fmt.Fprintf(os.Stderr, "Could not update status file %s; %v\n", statusFile, err)
+			}
+		}(index)
+	}
+	wg.Wait()
+
+	writeBulkImportResults(results)
+
+	failed := 0
+	for _, result := range results {
+		if result.Status == BulkImportStatusFailed {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d volumes could not be imported", failed, len(results))
+	}
+	return nil
+}
+
+// parseBulkImportManifest validates a manifest and returns its items along with the JSON PVC data for each,
+// reading any PVC files relative to manifestDir.
+func parseBulkImportManifest(rawManifest []byte, manifestDir string) ([]BulkImportItem, [][]byte, error) {
+
+	jsonManifest, err := yaml.YAMLToJSON(rawManifest)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var manifest BulkImportManifest
+	if err = json.Unmarshal(jsonManifest, &manifest); err != nil {
+		return nil, nil, fmt.Errorf("invalid manifest; %v", err)
+	}
+	if len(manifest.Volumes) == 0 {
+		return nil, nil, errors.New("the manifest does not list any volumes")
+	}
+
+	seen := make(map[string]bool)
+	pvcData := make([][]byte, len(manifest.Volumes))
+
+	for index, item := range manifest.Volumes {
+		if item.Backend == "" || item.Volume == "" {
+			return nil, nil, fmt.Errorf("volume %d in the manifest must specify a backend and a volume", index+1)
+		}
+		if seen[item.key()] {
+			return nil, nil, fmt.Errorf("volume %s is listed more than once in the manifest", item.key())
+		}
+		seen[item.key()] = true
+
+		hasInlinePVC := len(item.PVC) > 0 && string(item.PVC) != "null"
+		switch {
+		case hasInlinePVC && item.PVCFile != "":
+			return nil, nil, fmt.Errorf("volume %s specifies both pvc and pvcFile", item.key())
+		case hasInlinePVC:
+			pvcData[index] = item.PVC
+		case item.PVCFile != "":
+			pvcFile := item.PVCFile
+			if !filepath.IsAbs(pvcFile) {
+				pvcFile = filepath.Join(manifestDir, pvcFile)
+			}
+			if pvcData[index], err = getPVCData(pvcFile, ""); err != nil {
+				return nil, nil, fmt.Errorf("could not read the PVC for volume %s; %v", item.key(), err)
+			}
+		default:
+			return nil, nil, fmt.Errorf("volume %s must specify a pvc or a pvcFile", item.key())
+		}
+	}
+
+	return manifest.Volumes, pvcData, nil
+}
+
+// bulkImportTracker remembers which volumes have been imported, persisting each result to a status
+// file as it arrives so that an interrupted bulk import may be resumed.
+type bulkImportTracker struct {
+	mutex    sync.Mutex
+	filename string
+	dryRun   bool
+	results  map[string]BulkImportResult
+	order    []string
+}
+
+func newBulkImportTracker(filename string, dryRun bool) (*bulkImportTracker, error) {
+
+	tracker := &bulkImportTracker{
+		filename: filename,
+		dryRun:   dryRun,
+		results:  make(map[string]BulkImportResult),
+	}
+	if filename == "" {
+		return tracker, nil
+	}
+
+	statusBytes, err := ioutil.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return tracker, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var status BulkImportStatus
+	if err = json.Unmarshal(statusBytes, &status); err != nil {
+		return nil, fmt.Errorf("could not parse status file %s; %v", filename, err)
+	}
+	for _, result := range status.Items {
+		key := result.Backend + "/" + result.Volume
+		if _, ok := tracker.results[key]; !ok {
+			tracker.order = append(tracker.order, key)
+		}
+		tracker.results[key] = result
+	}
+
+	return tracker, nil
+}
+
+func (t *bulkImportTracker) imported(item BulkImportItem) bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.results[item.key()].Status == BulkImportStatusImported
+}
+
+func (t *bulkImportTracker) pvName(item BulkImportItem) string {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.results[item.key()].PVName
+}
+
+// record saves a result and rewrites the status file. Dry runs change nothing, so they are not recorded.
+func (t *bulkImportTracker) record(result BulkImportResult) error {
+
+	if t.dryRun {
+		return nil
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	key := result.Backend + "/" + result.Volume
+	if _, ok := t.results[key]; !ok {
+		t.order = append(t.order, key)
+	}
+	t.results[key] = result
+
+	if t.filename == "" {
+		return nil
+	}
+
+	status := BulkImportStatus{Items: make([]BulkImportResult, 0, len(t.order))}
+	for _, key := range t.order {
+		status.Items = append(status.Items, t.results[key])
+	}
+	statusBytes, err := json.MarshalIndent(status, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file and rename it so an interrupted write can't corrupt the status
+	tempFile := t.filename + ".tmp"
+	if err = ioutil.WriteFile(tempFile, statusBytes, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tempFile, t.filename)
+}
+
+// importBulkItem imports one volume, either directly or through the Trident pod, and reports the outcome.
+func importBulkItem(item BulkImportItem, pvcDataJSON []byte, dryRun bool) BulkImportResult {
+
+	result := BulkImportResult{Backend: item.Backend, Volume: item.Volume}
+
+	var response *rest.ImportVolumeResponse
+	var err error
+	if OperatingMode == ModeTunnel {
+		response, err = tunnelImportVolume(item, pvcDataJSON, dryRun)
+	} else {
+		response, err = importVolume(item.Backend, item.Volume, item.NoManage, dryRun, pvcDataJSON)
+	}
+	if err != nil {
+		result.Status = BulkImportStatusFailed
+		result.Error = err.Error()
+		return result
+	}
+
+	if dryRun {
+		result.Status = BulkImportStatusChecked
+		result.Changes = response.Changes
+	} else {
+		result.Status = BulkImportStatusImported
+	}
+	if response.Volume != nil && response.Volume.Config != nil {
+		result.PVName = response.Volume.Config.Name
+	}
+	return result
+}
+
+func tunnelImportVolume(item BulkImportItem, pvcDataJSON []byte, dryRun bool) (*rest.ImportVolumeResponse, error) {
+
+	command := []string{"--output", FormatJSON, "import", "volume",
+		"--base64", base64.StdEncoding.EncodeToString(pvcDataJSON)}
+	if item.NoManage {
+		command = append(command, "--no-manage")
+	}
+	if dryRun {
+		command = append(command, "--dry-run")
+	}
+	command = append(command, item.Backend, item.Volume)
+
+	output, err := tunnelCommandOutput(command)
+	if err != nil {
+		return nil, errors.New(strings.TrimPrefix(strings.TrimSpace(string(output)), "Error: "))
+	}
+
+	// A dry run returns the import response, while an import returns the volume list
+	if dryRun {
+		var response rest.ImportVolumeResponse
+		if err = json.Unmarshal(output, &response); err != nil {
+			return nil, fmt.Errorf("could not parse the import response; %v", err)
+		}
+		return &response, nil
+	}
+
+	var volumes api.MultipleVolumeResponse
+	if err = json.Unmarshal(output, &volumes); err != nil {
+		return nil, fmt.Errorf("could not parse the import response; %v", err)
+	}
+	if len(volumes.Items) != 1 {
+		return nil, errors.New("could not import volume: no volume returned")
+	}
+	return &rest.ImportVolumeResponse{Volume: &volumes.Items[0]}, nil
+}
+
+func writeBulkImportResults(results []BulkImportResult) {
+	switch OutputFormat {
+	case FormatJSON:
+		WriteJSON(BulkImportStatus{Items: results})
+	case FormatYAML:
+		WriteYAML(BulkImportStatus{Items: results})
+	default:
+		writeBulkImportTable(results)
+	}
+}
+
+func writeBulkImportTable(results []BulkImportResult) {
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Backend", "Volume", "Status", "PV Name", "Details"})
+
+	for _, result := range results {
+		details := result.Error
+		if result.Status == BulkImportStatusChecked {
+			details = strconv.Itoa(len(result.Changes)) + " change(s)"
+			if len(result.Changes) > 0 {
+				details += ": " + strings.Join(result.Changes, "; ")
+			}
+		}
+		table.Append([]string{
+			result.Backend,
+			result.Volume,
+			result.Status,
+			result.PVName,
+			details,
+		})
+	}
+
+	table.Render()
+}
@@ -0,0 +1,101 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseBulkImportManifest(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "bulk-import")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	pvcFile := "kind: PersistentVolumeClaim\nmetadata:\n  name: pvc2\n"
+	if err = ioutil.WriteFile(filepath.Join(dir, "pvc2.yaml"), []byte(pvcFile), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	manifest := `
+volumes:
+- backend: nas
+  volume: vol1
+  pvc:
+    kind: PersistentVolumeClaim
+    metadata:
+      name: pvc1
+- backend: nas
+  volume: vol2
+  noManage: true
+  pvcFile: pvc2.yaml
+`
+	items, pvcData, err := parseBulkImportManifest([]byte(manifest), dir)
+	assert.NoError(t, err)
+	assert.Len(t, items, 2)
+	assert.False(t, items[0].NoManage)
+	assert.True(t, items[1].NoManage)
+	assert.JSONEq(t, `{"kind":"PersistentVolumeClaim","metadata":{"name":"pvc1"}}`, string(pvcData[0]))
+	assert.JSONEq(t, `{"kind":"PersistentVolumeClaim","metadata":{"name":"pvc2"}}`, string(pvcData[1]))
+
+	invalidManifests := map[string]string{
+		"empty":      "volumes: []",
+		"no backend": "volumes:\n- volume: vol1\n  pvcFile: pvc2.yaml",
+		"no pvc":     "volumes:\n- backend: nas\n  volume: vol1",
+		"both pvcs":  "volumes:\n- backend: nas\n  volume: vol1\n  pvcFile: pvc2.yaml\n  pvc: {kind: x}",
+		"duplicate": "volumes:\n- backend: nas\n  volume: vol1\n  pvcFile: pvc2.yaml\n" +
+			"- backend: nas\n  volume: vol1\n  pvcFile: pvc2.yaml",
+		"missing file": "volumes:\n- backend: nas\n  volume: vol1\n  pvcFile: missing.yaml",
+	}
+	for name, manifest := range invalidManifests {
+		_, _, err = parseBulkImportManifest([]byte(manifest), dir)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestBulkImportTrackerResume(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "bulk-import")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	statusFile := filepath.Join(dir, "status.json")
+
+	imported := BulkImportItem{Backend: "nas", Volume: "vol1"}
+	failed := BulkImportItem{Backend: "nas", Volume: "vol2"}
+
+	tracker, err := newBulkImportTracker(statusFile, false)
+	assert.NoError(t, err)
+	assert.NoError(t, tracker.record(BulkImportResult{
+		Backend: "nas", Volume: "vol1", Status: BulkImportStatusImported, PVName: "pvc-1",
+	}))
+	assert.NoError(t, tracker.record(BulkImportResult{
+		Backend: "nas", Volume: "vol2", Status: BulkImportStatusFailed, Error: "not found",
+	}))
+
+	// A new run picks up where the last one stopped
+	tracker, err = newBulkImportTracker(statusFile, false)
+	assert.NoError(t, err)
+	assert.True(t, tracker.imported(imported))
+	assert.Equal(t, "pvc-1", tracker.pvName(imported))
+	assert.False(t, tracker.imported(failed))
+
+	// Dry runs leave the status file alone
+	dryRunTracker, err := newBulkImportTracker(statusFile, true)
+	assert.NoError(t, err)
+	assert.NoError(t, dryRunTracker.record(BulkImportResult{
+		Backend: "nas", Volume: "vol2", Status: BulkImportStatusChecked,
+	}))
+	tracker, err = newBulkImportTracker(statusFile, false)
+	assert.NoError(t, err)
+	assert.False(t, tracker.imported(failed))
+	assert.Equal(t, BulkImportStatusFailed, tracker.results[failed.key()].Status)
+}
@@ -9,6 +9,7 @@ import (
 	"os"
 	"path"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -38,13 +39,18 @@ const (
 	DefaultPVName      = tridentconfig.OrchestratorName
 
 	// CRD names
-	BackendCRDName      = "tridentbackends.trident.netapp.io"
-	NodeCRDName         = "tridentnodes.trident.netapp.io"
-	StorageClassCRDName = "tridentstorageclasses.trident.netapp.io"
-	TransactionCRDName  = "tridenttransactions.trident.netapp.io"
-	VersionCRDName      = "tridentversions.trident.netapp.io"
-	VolumeCRDName       = "tridentvolumes.trident.netapp.io"
-	SnapshotCRDName     = "tridentsnapshots.trident.netapp.io"
+	BackendCRDName       = "tridentbackends.trident.netapp.io"
+	NodeCRDName          = "tridentnodes.trident.netapp.io"
+	StorageClassCRDName  = "tridentstorageclasses.trident.netapp.io"
+	TransactionCRDName   = "tridenttransactions.trident.netapp.io"
+	VersionCRDName       = "tridentversions.trident.netapp.io"
+	VolumeCRDName        = "tridentvolumes.trident.netapp.io"
+	SnapshotCRDName      = "tridentsnapshots.trident.netapp.io"
+	ScheduleCRDName      = "tridentsnapshotschedules.trident.netapp.io"
+	VolumeGroupCRDName   = "tridentvolumegroups.trident.netapp.io"
+	CloneGrantCRDName    = "tridentclonegrants.trident.netapp.io"
+	QuotaCRDName         = "tridentquotas.trident.netapp.io"
+	BackendConfigCRDName = "tridentbackendconfigs.trident.netapp.io"
 
 	NamespaceFilename          = "trident-namespace.yaml"
 	ServiceAccountFilename     = "trident-serviceaccount.yaml"
@@ -53,6 +59,7 @@ const (
 	DeploymentFilename         = "trident-deployment.yaml"
 	ServiceFilename            = "trident-service.yaml"
 	DaemonSetFilename          = "trident-daemonset.yaml"
+	WindowsDaemonSetFilename   = "trident-daemonset-windows.yaml"
 	CRDsFilename               = "trident-crds.yaml"
 	PodSecurityPolicyFilename  = "trident-podsecuritypolicy.yaml"
 
@@ -78,9 +85,16 @@ var (
 	kubeletDir      string
 	imageRegistry   string
 	logFormat       string
+	fsGroupPolicy   string
 	k8sTimeout      time.Duration
 	migratorTimeout time.Duration
 
+	controllerReplicas int
+
+	enableStorageCapacity bool
+	enableSELinuxMount    bool
+	windows               bool
+
 	// CLI-based K8S client
 	client k8sclient.Interface
 
@@ -95,6 +109,7 @@ var (
 	deploymentPath         string
 	csiServicePath         string
 	csiDaemonSetPath       string
+	windowsDaemonSetPath   string
 	podSecurityPolicyPath  string
 	setupYAMLPaths         []string
 
@@ -113,6 +128,11 @@ var (
 		VersionCRDName,
 		VolumeCRDName,
 		SnapshotCRDName,
+		ScheduleCRDName,
+		VolumeGroupCRDName,
+		CloneGrantCRDName,
+		QuotaCRDName,
+		BackendConfigCRDName,
 	}
 
 	useCRDv1 bool
@@ -134,6 +154,11 @@ func init() {
 	installCmd.Flags().StringVar(&logFormat, "log-format", "text", "The Trident logging format (text, json).")
 	installCmd.Flags().StringVar(&kubeletDir, "kubelet-dir", "/var/lib/kubelet", "The host location of kubelet's internal state.")
 	installCmd.Flags().StringVar(&imageRegistry, "image-registry", "", "The address/port of an internal image registry.")
+	installCmd.Flags().StringVar(&fsGroupPolicy, "fs-group-policy", "", "The CSI driver's fsGroupPolicy (None, File, ReadWriteOnceWithFSType), which controls whether kubelet applies a pod's fsGroup to Trident volumes.")
+	installCmd.Flags().BoolVar(&enableStorageCapacity, "enable-storage-capacity", false, "Have the Kubernetes scheduler consult the storage capacity Trident publishes for each storage class.")
+	installCmd.Flags().BoolVar(&enableSELinuxMount, "enable-selinux-mount", false, "Have kubelet mount Trident volumes with each pod's SELinux context instead of relabeling their files.")
+	installCmd.Flags().BoolVar(&windows, "windows", false, "Also install the node plugin on Windows nodes, which must be running CSI Proxy.")
+	installCmd.Flags().IntVar(&controllerReplicas, "controller-replicas", 1, "The number of CSI Trident controller replicas, of which one is elected leader and the others stand by.")
 
 	installCmd.Flags().DurationVar(&k8sTimeout, "k8s-timeout", 180*time.Second, "The timeout for all Kubernetes operations.")
 	installCmd.Flags().DurationVar(&migratorTimeout, "migrator-timeout", 300*time.Minute, "The timeout for etcd-to-CRD migration.")
@@ -371,6 +396,14 @@ func validateInstallationArguments() error {
 		return fmt.Errorf("'%s' is not a valid log format", logFormat)
 	}
 
+	if controllerReplicas < 1 {
+		return fmt.Errorf("'%d' is not a valid number of controller replicas", controllerReplicas)
+	}
+
+	if !k8sclient.IsValidFSGroupPolicy(fsGroupPolicy) {
+		return fmt.Errorf("'%s' is not a valid fsGroup policy", fsGroupPolicy)
+	}
+
 	return nil
 }
 
@@ -394,11 +427,12 @@ func prepareYAMLFilePaths() error {
 	deploymentPath = path.Join(setupPath, DeploymentFilename)
 	csiServicePath = path.Join(setupPath, ServiceFilename)
 	csiDaemonSetPath = path.Join(setupPath, DaemonSetFilename)
+	windowsDaemonSetPath = path.Join(setupPath, WindowsDaemonSetFilename)
 	podSecurityPolicyPath = path.Join(setupPath, PodSecurityPolicyFilename)
 
 	setupYAMLPaths = []string{
 		namespacePath, serviceAccountPath, clusterRolePath, clusterRoleBindingPath, crdsPath,
-		deploymentPath, csiServicePath, csiDaemonSetPath, podSecurityPolicyPath,
+		deploymentPath, csiServicePath, csiDaemonSetPath, windowsDaemonSetPath, podSecurityPolicyPath,
 	}
 
 	return nil
@@ -509,17 +543,30 @@ func prepareCSIYAMLFiles() error {
 	}
 
 	deploymentYAML := k8sclient.GetCSIDeploymentYAML(getDeploymentName(true),
-		tridentImage, csiSidecarRegistry, logFormat, []string{}, labels, nil, Debug, useIPv6, client.ServerVersion())
+		tridentImage, csiSidecarRegistry, logFormat, controllerReplicas, []string{}, labels, nil, nil, Debug,
+		useIPv6, client.ServerVersion())
 	if err = writeFile(deploymentPath, deploymentYAML); err != nil {
 		return fmt.Errorf("could not write deployment YAML file; %v", err)
 	}
 
 	daemonSetYAML := k8sclient.GetCSIDaemonSetYAML(getDaemonSetName(),
-		tridentImage, csiSidecarRegistry, kubeletDir, logFormat, []string{}, daemonSetlabels, nil, Debug, client.ServerVersion())
+		tridentImage, csiSidecarRegistry, kubeletDir, logFormat, []string{}, daemonSetlabels, nil, nil, Debug,
+		client.ServerVersion())
 	if err = writeFile(csiDaemonSetPath, daemonSetYAML); err != nil {
 		return fmt.Errorf("could not write daemonset YAML file; %v", err)
 	}
 
+	if windows {
+		windowsDaemonSetLabels := make(map[string]string)
+		windowsDaemonSetLabels[appLabelKey] = TridentWindowsNodeLabelValue
+
+		windowsDaemonSetYAML := k8sclient.GetCSIWindowsDaemonSetYAML(getWindowsDaemonSetName(),
+			tridentImage, csiSidecarRegistry, logFormat, []string{}, windowsDaemonSetLabels, nil, nil, Debug)
+		if err = writeFile(windowsDaemonSetPath, windowsDaemonSetYAML); err != nil {
+			return fmt.Errorf("could not write Windows daemonset YAML file; %v", err)
+		}
+	}
+
 	podSecurityPolicyYAML := k8sclient.GetPrivilegedPodSecurityPolicyYAML(getPSPName(), nil, nil)
 	if err = writeFile(podSecurityPolicyPath, podSecurityPolicyYAML); err != nil {
 		return fmt.Errorf("could not write pod security policy YAML file; %v", err)
@@ -886,9 +933,10 @@ func installTrident() (returnError error) {
 		}
 		log.WithFields(logFields).Info("Created Trident service.")
 
-		// Create the certificates for the CSI controller's HTTPS REST interface
+		// Create the certificates for the CSI controller's HTTPS REST interface and admission webhook
 		certInfo, err := utils.MakeHTTPCertInfo(
-			tridentconfig.CACertName, tridentconfig.ServerCertName, tridentconfig.ClientCertName)
+			tridentconfig.CACertName, tridentconfig.ServerCertName, tridentconfig.ClientCertName,
+			getServiceDNSNames())
 		if err != nil {
 			returnError = fmt.Errorf("could not create Trident X509 certificates; %v", err)
 			return
@@ -911,6 +959,22 @@ func installTrident() (returnError error) {
 		}
 		log.WithFields(logFields).Info("Created Trident secret.")
 
+		// Register the storage class admission webhook, which is served by the CSI controller
+		returnError = createK8SValidatingWebhook(certInfo.CACert)
+		if returnError != nil {
+			returnError = fmt.Errorf("could not create the storage class admission webhook; %v", returnError)
+			return
+		}
+
+		// Have the API server convert Trident's custom resources by calling the CSI controller
+		if useCRDv1 {
+			returnError = enableCRDConversionWebhook(certInfo.CACert)
+			if returnError != nil {
+				returnError = fmt.Errorf("could not register the CRD conversion webhook; %v", returnError)
+				return
+			}
+		}
+
 		// Create the deployment
 		if useYAML && fileExists(deploymentPath) {
 			returnError = validateTridentDeployment()
@@ -923,7 +987,8 @@ func installTrident() (returnError error) {
 		} else {
 			returnError = client.CreateObjectByYAML(
 				k8sclient.GetCSIDeploymentYAML(getDeploymentName(true),
-					tridentImage, csiSidecarRegistry, logFormat, []string{}, labels, nil, Debug, useIPv6, client.ServerVersion()))
+					tridentImage, csiSidecarRegistry, logFormat, controllerReplicas, []string{}, labels, nil, nil,
+					Debug, useIPv6, client.ServerVersion()))
 			logFields = log.Fields{}
 		}
 		if returnError != nil {
@@ -934,7 +999,7 @@ func installTrident() (returnError error) {
 
 		// Create the daemonset
 		if useYAML && fileExists(csiDaemonSetPath) {
-			returnError = validateTridentDaemonSet()
+			returnError = validateTridentDaemonSet(csiDaemonSetPath, TridentNodeLabelValue)
 			if returnError != nil {
 				returnError = fmt.Errorf("please correct the daemonset YAML file; %v", returnError)
 				return
@@ -947,8 +1012,8 @@ func installTrident() (returnError error) {
 
 			returnError = client.CreateObjectByYAML(
 				k8sclient.GetCSIDaemonSetYAML(getDaemonSetName(),
-					tridentImage, csiSidecarRegistry, kubeletDir, logFormat, []string{}, daemonSetlabels, nil, Debug,
-					client.ServerVersion()))
+					tridentImage, csiSidecarRegistry, kubeletDir, logFormat, []string{}, daemonSetlabels, nil, nil,
+					Debug, client.ServerVersion()))
 			logFields = log.Fields{}
 		}
 		if returnError != nil {
@@ -956,6 +1021,33 @@ func installTrident() (returnError error) {
 			return
 		}
 		log.WithFields(logFields).Info("Created Trident daemonset.")
+
+		// Create the Windows daemonset
+		if windows {
+			if useYAML && fileExists(windowsDaemonSetPath) {
+				returnError = validateTridentDaemonSet(windowsDaemonSetPath, TridentWindowsNodeLabelValue)
+				if returnError != nil {
+					returnError = fmt.Errorf("please correct the Windows daemonset YAML file; %v", returnError)
+					return
+				}
+				returnError = client.CreateObjectByFile(windowsDaemonSetPath)
+				logFields = log.Fields{"path": windowsDaemonSetPath}
+			} else {
+				windowsDaemonSetLabels := make(map[string]string)
+				windowsDaemonSetLabels[appLabelKey] = TridentWindowsNodeLabelValue
+
+				returnError = client.CreateObjectByYAML(
+					k8sclient.GetCSIWindowsDaemonSetYAML(getWindowsDaemonSetName(),
+						tridentImage, csiSidecarRegistry, logFormat, []string{}, windowsDaemonSetLabels, nil, nil,
+						Debug))
+				logFields = log.Fields{}
+			}
+			if returnError != nil {
+				returnError = fmt.Errorf("could not create Trident Windows daemonset; %v", returnError)
+				return
+			}
+			log.WithFields(logFields).Info("Created Trident Windows daemonset.")
+		}
 	}
 
 	// Wait for Trident pod to be running
@@ -1378,18 +1470,81 @@ func createK8SCSIDriver() error {
 		return nil
 	}
 
+	// The fsGroupPolicy field is only known to Kubernetes 1.19+
+	policy := fsGroupPolicy
+	if policy != "" && client.ServerVersion().MinorVersion() < 19 {
+		log.WithField("fsGroupPolicy", policy).Warning(
+			"The CSI driver fsGroupPolicy requires Kubernetes 1.19 or later and will be ignored.")
+		policy = ""
+	}
+
+	// CSIStorageCapacity objects are only served by default in Kubernetes 1.21+
+	storageCapacity := enableStorageCapacity
+	if storageCapacity && client.ServerVersion().MinorVersion() < 21 {
+		log.Warning("Storage capacity tracking requires Kubernetes 1.21 or later and will not be enabled.")
+		storageCapacity = false
+	}
+
+	// The CSIDriver seLinuxMount field is only known to Kubernetes 1.25+
+	seLinuxMount := enableSELinuxMount
+	if seLinuxMount && client.ServerVersion().MinorVersion() < 25 {
+		log.Warning("SELinux mounts require Kubernetes 1.25 or later and will not be enabled.")
+		seLinuxMount = false
+	}
+
 	// Delete the object in case it already exists and we need to update it
-	if err := client.DeleteObjectByYAML(k8sclient.GetCSIDriverCRYAML(getCSIDriverName(), nil, nil), true); err != nil {
+	if err := client.DeleteObjectByYAML(k8sclient.GetCSIDriverCRYAML(getCSIDriverName(), "", false, false, nil,
+		nil), true); err != nil {
 		return fmt.Errorf("could not delete csidriver custom resource; %v", err)
 	}
 
-	if err := client.CreateObjectByYAML(k8sclient.GetCSIDriverCRYAML(getCSIDriverName(), nil, nil)); err != nil {
+	if err := client.CreateObjectByYAML(k8sclient.GetCSIDriverCRYAML(getCSIDriverName(), policy, storageCapacity,
+		seLinuxMount, nil, nil)); err != nil {
 		return fmt.Errorf("could not create csidriver custom resource; %v", err)
 	}
 
 	return nil
 }
 
+func createK8SValidatingWebhook(caBundle string) error {
+
+	// The API server only calls webhooks through a service on Kubernetes 1.14+
+	if client.ServerVersion().MajorVersion() != 1 || client.ServerVersion().MinorVersion() < 14 {
+		return nil
+	}
+
+	webhookYAML := k8sclient.GetValidatingWebhookConfigurationYAML(getValidatingWebhookName(), getServiceName(),
+		TridentPodNamespace, caBundle, client.ServerVersion(), nil, nil)
+
+	// Delete the object in case it already exists with an old CA bundle
+	if err := client.DeleteObjectByYAML(webhookYAML, true); err != nil {
+		return fmt.Errorf("could not delete validating webhook configuration; %v", err)
+	}
+
+	if err := client.CreateObjectByYAML(webhookYAML); err != nil {
+		return fmt.Errorf("could not create validating webhook configuration; %v", err)
+	}
+	log.WithField("webhook", getValidatingWebhookName()).Info("Created validating webhook configuration.")
+
+	return nil
+}
+
+// enableCRDConversionWebhook points each Trident CRD at the conversion webhook.  The API server only
+// calls the webhook once a CRD serves more than one version, so this is harmless until then.
+func enableCRDConversionWebhook(caBundle string) error {
+
+	patch := k8sclient.GetCRDConversionPatch(getServiceName(), TridentPodNamespace, caBundle)
+
+	for _, crdName := range CRDnames {
+		if err := client.PatchCRD(crdName, []byte(patch)); err != nil {
+			return fmt.Errorf("could not patch CRD %s; %v", crdName, err)
+		}
+	}
+	log.Info("Registered the CRD conversion webhook.")
+
+	return nil
+}
+
 func createRBACObjects() (returnError error) {
 
 	var logFields log.Fields
@@ -1612,25 +1767,25 @@ func validateTridentService() error {
 	return nil
 }
 
-func validateTridentDaemonSet() error {
+func validateTridentDaemonSet(daemonSetPath, labelValue string) error {
 
-	daemonset, err := readDaemonSetFromFile(csiDaemonSetPath)
+	daemonset, err := readDaemonSetFromFile(daemonSetPath)
 	if err != nil {
 		return fmt.Errorf("could not load daemonset YAML file; %v", err)
 	}
 
 	// Check the daemonset label
 	labels := daemonset.Labels
-	if labels[TridentNodeLabelKey] != TridentNodeLabelValue {
+	if labels[TridentNodeLabelKey] != labelValue {
 		return fmt.Errorf("the Trident daemonset must have the label \"%s: %s\"",
-			TridentNodeLabelKey, TridentNodeLabelValue)
+			TridentNodeLabelKey, labelValue)
 	}
 
 	// Check the pod label
 	labels = daemonset.Spec.Template.Labels
-	if labels[TridentNodeLabelKey] != TridentNodeLabelValue {
+	if labels[TridentNodeLabelKey] != labelValue {
 		return fmt.Errorf("the Trident daemonset's pod template must have the label \"%s: %s\"",
-			TridentNodeLabelKey, TridentNodeLabelValue)
+			TridentNodeLabelKey, labelValue)
 	}
 
 	tridentImage := ""
@@ -1651,8 +1806,11 @@ func waitForTridentPod() (*v1.Pod, error) {
 	var pod *v1.Pod
 
 	checkPodRunning := func() error {
-		var podError error
-		pod, podError = client.GetPodByLabel(appLabel, false)
+		// Of several controller replicas, wait for the elected leader
+		pods, podError := client.GetPodsByLabel(appLabel, false)
+		if podError == nil {
+			pod, podError = k8sclient.SelectLeaderPod(pods, appLabel)
+		}
 		if podError != nil || pod.Status.Phase != v1.PodRunning {
 			return errors.New("pod not running")
 		}
@@ -1932,6 +2090,21 @@ func installTridentInCluster() (returnError error) {
 	if useIPv6 {
 		commandArgs = append(commandArgs, "--use-ipv6")
 	}
+	if controllerReplicas != 1 {
+		commandArgs = append(commandArgs, "--controller-replicas", strconv.Itoa(controllerReplicas))
+	}
+	if fsGroupPolicy != "" {
+		commandArgs = append(commandArgs, "--fs-group-policy", fsGroupPolicy)
+	}
+	if enableStorageCapacity {
+		commandArgs = append(commandArgs, "--enable-storage-capacity")
+	}
+	if enableSELinuxMount {
+		commandArgs = append(commandArgs, "--enable-selinux-mount")
+	}
+	if windows {
+		commandArgs = append(commandArgs, "--windows")
+	}
 	if pvcName != "" {
 		commandArgs = append(commandArgs, "--pvc")
 		commandArgs = append(commandArgs, pvcName)
@@ -2630,6 +2803,19 @@ func getSecretName() string {
 	return TridentCSI
 }
 
+// getServiceDNSNames returns the names by which the API server and pods may reach the Trident service.
+func getServiceDNSNames() []string {
+	return []string{
+		getServiceName(),
+		getServiceName() + "." + TridentPodNamespace,
+		getServiceName() + "." + TridentPodNamespace + ".svc",
+	}
+}
+
+func getValidatingWebhookName() string {
+	return TridentCSI
+}
+
 func getDeploymentName(csi bool) string {
 	if csi {
 		return TridentCSI
@@ -2642,6 +2828,10 @@ func getDaemonSetName() string {
 	return TridentCSI
 }
 
+func getWindowsDaemonSetName() string {
+	return TridentCSI + "-windows"
+}
+
 func getCSIDriverName() string {
 	return CSIDriver
 }
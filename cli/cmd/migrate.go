@@ -68,8 +68,8 @@ var migrateCmd = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 
 		if err := migrator.Run(); err != nil {
-			log.Errorf("Migration failed; %v.  Resolve the issue and try again.", err)
-			return fmt.Errorf("migration failed; %v.  Resolve the issue and try again.", err)
+			log.Errorf("Migration failed; %v.  Resolve the issue and try again to resume the migration.", err)
+			return fmt.Errorf("migration failed; %v.  Resolve the issue and try again to resume the migration.", err)
 		}
 
 		return nil
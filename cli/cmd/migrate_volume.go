@@ -0,0 +1,216 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/netapp/trident/cli/api"
+	"github.com/netapp/trident/frontend/rest"
+	"github.com/netapp/trident/storage"
+)
+
+const migrateVolumePollInterval = 5 * time.Second
+
+var (
+	migrateVolumeBackend     string
+	migrateVolumeStoragePool string
+	migrateVolumeDryRun      bool
+	migrateVolumeWait        bool
+)
+
+func init() {
+	migrateCmd.AddCommand(migrateVolumeCmd)
+	migrateVolumeCmd.Flags().StringVar(&migrateVolumeBackend, "backend", "", "Destination backend")
+	migrateVolumeCmd.Flags().StringVar(&migrateVolumeStoragePool, "pool", "",
+		"Destination storage pool (defaults to a pool matching the volume's storage class)")
+	migrateVolumeCmd.Flags().BoolVar(&migrateVolumeDryRun, "dry-run", false,
+		"Show how the volume would be migrated, without migrating it")
+	migrateVolumeCmd.Flags().BoolVar(&migrateVolumeWait, "wait", false, "Wait for the migration to finish")
+}
+
+var migrateVolumeCmd = &cobra.Command{
+	Use:   "volume <volume> --backend <backend> [--pool <pool>]",
+	Short: "Migrate an unpublished volume to a backend with another driver",
+	Long: "Migrate a volume offline to another backend, such as one with another driver on the same " +
+		"storage system.  The migration is planned first, showing the drivers, storage pool and copy method it " +
+		"would use, and it starts only if no check fails.  The volume must not be published to any " +
+		"node, and it can't be published until the migration finishes.",
+	Aliases: []string{"v", "vol"},
+	// The parent command migrates Trident's data from etcd, so this one needs none of its setup
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return discoverOperatingMode(cmd)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if OperatingMode == ModeTunnel {
+			command := []string{"migrate", "volume", "--backend", migrateVolumeBackend}
+			if migrateVolumeStoragePool != "" {
+				command = append(command, "--pool", migrateVolumeStoragePool)
+			}
+			if migrateVolumeDryRun {
+				command = append(command, "--dry-run")
+			}
+			if migrateVolumeWait {
+				command = append(command, "--wait")
+			}
+			TunnelCommand(append(command, args...))
+			return nil
+		} else {
+			return volumeMigrate(args)
+		}
+	},
+}
+
+func volumeMigrate(volumeNames []string) error {
+
+	switch len(volumeNames) {
+	case 0:
+		return errors.New("volume name not specified")
+	case 1:
+		break
+	default:
+		return errors.New("multiple volume names specified")
+	}
+
+	request := storage.VolumeMigrationRequest{
+		Volume:      volumeNames[0],
+		Backend:     migrateVolumeBackend,
+		StoragePool: migrateVolumeStoragePool,
+	}
+	if err := request.Validate(); err != nil {
+		return err
+	}
+
+	plan, err := GetMigrationPlan(request)
+	if err != nil {
+		return err
+	}
+
+	if migrateVolumeDryRun {
+		writeMigrationPlan(plan)
+		if !plan.Preflight.Ready {
+			return fmt.Errorf("volume %s cannot be migrated", plan.VolumeName)
+		}
+		return nil
+	}
+
+	// The plan and its warnings are shown without mixing them into the output
+	fmt.Fprintf(os.Stderr, "Migrating volume %s from backend %s (%s) to backend %s (%s) by %s.\n",
+		plan.VolumeName, plan.SourceBackend, plan.SourceDriver, plan.DestinationBackend, plan.DestinationDriver,
+		plan.Method)
+	for _, check := range plan.Preflight.Checks {
+		if check.Status == storage.PreflightPassed {
+			continue
+		}
+		for _, finding := range check.Findings {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", check.Status, finding)
+		}
+	}
+	if !plan.Preflight.Ready {
+		return fmt.Errorf("volume %s cannot be migrated; resolve the failed checks, which --dry-run shows "+
+			"with their remediations", plan.VolumeName)
+	}
+
+	migration, err := CreateMigration(request)
+	if err != nil {
+		return err
+	}
+
+	if migrateVolumeWait {
+		if migration, err = waitForMigration(migration); err != nil {
+			return err
+		}
+	}
+
+	WriteMigrations([]storage.VolumeMigrationExternal{migration})
+
+	return nil
+}
+
+// GetMigrationPlan returns how Trident would migrate a volume, and whether the migration may proceed.
+func GetMigrationPlan(request storage.VolumeMigrationRequest) (*storage.VolumeMigrationPlan, error) {
+
+	query := url.Values{}
+	if request.Backend != "" {
+		query.Set("backend", request.Backend)
+	}
+	if request.StoragePool != "" {
+		query.Set("pool", request.StoragePool)
+	}
+	planURL := BaseURL() + "/migration/" + request.Volume + "/plan?" + query.Encode()
+
+	response, responseBody, err := api.InvokeRESTAPI("GET", planURL, nil, Debug)
+	if err != nil {
+		return nil, err
+	} else if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not plan migration of volume %s: %v", request.Volume,
+			GetErrorFromHTTPResponse(response, responseBody))
+	}
+
+	var planResponse rest.MigrationPlanResponse
+	if err = json.Unmarshal(responseBody, &planResponse); err != nil {
+		return nil, err
+	}
+	if planResponse.Plan == nil || planResponse.Plan.Preflight == nil {
+		return nil, fmt.Errorf("could not plan migration of volume %s: no plan returned", request.Volume)
+	}
+
+	return planResponse.Plan, nil
+}
+
+// waitForMigration polls a migration until it completes or fails, showing its progress.
+func waitForMigration(migration storage.VolumeMigrationExternal) (storage.VolumeMigrationExternal, error) {
+
+	var err error
+	for !migration.State.IsDone() {
+		time.Sleep(migrateVolumePollInterval)
+		if migration, err = GetMigration(migration.VolumeName); err != nil {
+			return migration, err
+		}
+		fmt.Fprintf(os.Stderr, "%s: %s, %d%%\n", migration.VolumeName, migration.State, migration.Progress)
+	}
+
+	if migration.State == storage.MigrationStateFailed {
+		return migration, fmt.Errorf("migration of volume %s failed: %s", migration.VolumeName, migration.Message)
+	}
+	return migration, nil
+}
+
+func writeMigrationPlan(plan *storage.VolumeMigrationPlan) {
+	switch OutputFormat {
+	case FormatJSON:
+		WriteJSON(plan)
+	case FormatYAML:
+		WriteYAML(plan)
+	default:
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Volume", "Source", "Source driver", "Destination", "Destination driver",
+			"Pool", "Method"})
+		table.Append([]string{
+			plan.VolumeName,
+			plan.SourceBackend,
+			plan.SourceDriver,
+			plan.DestinationBackend,
+			plan.DestinationDriver,
+			plan.DestinationPool,
+			string(plan.Method),
+		})
+		table.Render()
+
+		writePreflightTable(plan.Preflight)
+		if plan.Preflight.Ready {
+			fmt.Printf("Volume %s can be migrated.\n", plan.VolumeName)
+		} else {
+			fmt.Printf("Volume %s cannot be migrated.\n", plan.VolumeName)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/spf13/cobra"
+
+	"github.com/netapp/trident/cli/api"
+	"github.com/netapp/trident/frontend/rest"
+	"github.com/netapp/trident/storage"
+)
+
+var (
+	moveVolumeAggregate string
+	moveVolumeBackend   string
+)
+
+func init() {
+	moveCmd.AddCommand(moveVolumeCmd)
+	moveVolumeCmd.Flags().StringVar(&moveVolumeAggregate, "aggregate", "",
+		"Destination aggregate, or other storage pool, of the volume's backend")
+	moveVolumeCmd.Flags().StringVar(&moveVolumeBackend, "backend", "",
+		"Destination backend, to migrate the volume to another backend")
+}
+
+var moveVolumeCmd = &cobra.Command{
+	Use:   "volume <name> --aggregate <aggregate> [--backend <backend>]",
+	Short: "Move a volume to another aggregate or backend",
+	Long: "Move a volume to another aggregate or storage pool of its backend, which the storage system " +
+		"does while the volume stays in use, or migrate it to another backend.  The move runs in the " +
+		"background; use 'tridentctl get move' to follow it.",
+	Aliases: []string{"v"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if OperatingMode == ModeTunnel {
+			command := []string{"move", "volume"}
+			if moveVolumeAggregate != "" {
+				command = append(command, "--aggregate", moveVolumeAggregate)
+			}
+			if moveVolumeBackend != "" {
+				command = append(command, "--backend", moveVolumeBackend)
+			}
+			TunnelCommand(append(command, args...))
+			return nil
+		} else {
+			return volumeMove(args)
+		}
+	},
+}
+
+func volumeMove(volumeNames []string) error {
+
+	switch len(volumeNames) {
+	case 0:
+		return errors.New("volume name not specified")
+	case 1:
+		break
+	default:
+		return errors.New("multiple volume names specified")
+	}
+
+	if moveVolumeAggregate == "" && moveVolumeBackend == "" {
+		return errors.New("destination not specified; use --aggregate, --backend, or both")
+	}
+
+	request := storage.VolumeMigrationRequest{
+		Volume:      volumeNames[0],
+		Backend:     moveVolumeBackend,
+		StoragePool: moveVolumeAggregate,
+	}
+	if err := request.Validate(); err != nil {
+		return err
+	}
+	requestBytes, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	url := BaseURL() + "/migration"
+
+	response, responseBody, err := api.InvokeRESTAPI("POST", url, requestBytes, Debug)
+	if err != nil {
+		return err
+	} else if response.StatusCode != http.StatusCreated {
+		return fmt.Errorf("could not move volume %s: %v", volumeNames[0],
+			GetErrorFromHTTPResponse(response, responseBody))
+	}
+
+	var addMigrationResponse rest.AddMigrationResponse
+	err = json.Unmarshal(responseBody, &addMigrationResponse)
+	if err != nil {
+		return err
+	}
+	if addMigrationResponse.Migration == nil {
+		return fmt.Errorf("could not move volume %s: no move returned", volumeNames[0])
+	}
+
+	WriteMigrations([]storage.VolumeMigrationExternal{*addMigrationResponse.Migration})
+
+	return nil
+}
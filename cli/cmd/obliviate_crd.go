@@ -121,6 +121,26 @@ func deleteCRs() error {
 		return err
 	}
 
+	if err := deleteSnapshotSchedules(); err != nil {
+		return err
+	}
+
+	if err := deleteVolumeGroups(); err != nil {
+		return err
+	}
+
+	if err := deleteCloneGrants(); err != nil {
+		return err
+	}
+
+	if err := deleteQuotas(); err != nil {
+		return err
+	}
+
+	if err := deleteBackendConfigs(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -523,6 +543,193 @@ func deleteSnapshots() error {
 	return nil
 }
 
+func deleteSnapshotSchedules() error {
+
+	crd := "tridentsnapshotschedules.trident.netapp.io"
+	logFields := log.Fields{"CRD": crd}
+
+	// See if CRD exists
+	exists, err := kubeClient.CheckCRDExists(crd)
+	if err != nil {
+		return err
+	} else if !exists {
+		log.WithField("CRD", crd).Debug("CRD not present.")
+		return nil
+	}
+
+	schedules, err := crdClientset.TridentV1().TridentSnapshotSchedules(resetNamespace).List(ctx(), listOpts)
+	if err != nil {
+		return err
+	} else if len(schedules.Items) == 0 {
+		log.WithFields(logFields).Info("Resources not present.")
+		return nil
+	}
+
+	// Snapshot schedules have no finalizers, so they may be deleted directly
+	for _, schedule := range schedules.Items {
+		deleteFunc := crdClientset.TridentV1().TridentSnapshotSchedules(resetNamespace).Delete
+		if err := deleteWithRetry(deleteFunc, ctx(), schedule.Name, nil); err != nil {
+			log.Errorf("Problem deleting resource: %v", err)
+			return err
+		}
+	}
+
+	log.WithFields(logFields).Info("Resources deleted.")
+	return nil
+}
+
+func deleteVolumeGroups() error {
+
+	crd := "tridentvolumegroups.trident.netapp.io"
+	logFields := log.Fields{"CRD": crd}
+
+	// See if CRD exists
+	exists, err := kubeClient.CheckCRDExists(crd)
+	if err != nil {
+		return err
+	} else if !exists {
+		log.WithField("CRD", crd).Debug("CRD not present.")
+		return nil
+	}
+
+	volumeGroups, err := crdClientset.TridentV1().TridentVolumeGroups(resetNamespace).List(ctx(), listOpts)
+	if err != nil {
+		return err
+	} else if len(volumeGroups.Items) == 0 {
+		log.WithFields(logFields).Info("Resources not present.")
+		return nil
+	}
+
+	// Volume groups have no finalizers, so they may be deleted directly
+	for _, volumeGroup := range volumeGroups.Items {
+		deleteFunc := crdClientset.TridentV1().TridentVolumeGroups(resetNamespace).Delete
+		if err := deleteWithRetry(deleteFunc, ctx(), volumeGroup.Name, nil); err != nil {
+			log.Errorf("Problem deleting resource: %v", err)
+			return err
+		}
+	}
+
+	log.WithFields(logFields).Info("Resources deleted.")
+	return nil
+}
+
+func deleteCloneGrants() error {
+
+	crd := "tridentclonegrants.trident.netapp.io"
+	logFields := log.Fields{"CRD": crd}
+
+	// See if CRD exists
+	exists, err := kubeClient.CheckCRDExists(crd)
+	if err != nil {
+		return err
+	} else if !exists {
+		log.WithField("CRD", crd).Debug("CRD not present.")
+		return nil
+	}
+
+	cloneGrants, err := crdClientset.TridentV1().TridentCloneGrants(resetNamespace).List(ctx(), listOpts)
+	if err != nil {
+		return err
+	} else if len(cloneGrants.Items) == 0 {
+		log.WithFields(logFields).Info("Resources not present.")
+		return nil
+	}
+
+	// Clone grants have no finalizers, so they may be deleted directly
+	for _, cloneGrant := range cloneGrants.Items {
+		deleteFunc := crdClientset.TridentV1().TridentCloneGrants(resetNamespace).Delete
+		if err := deleteWithRetry(deleteFunc, ctx(), cloneGrant.Name, nil); err != nil {
+			log.Errorf("Problem deleting resource: %v", err)
+			return err
+		}
+	}
+
+	log.WithFields(logFields).Info("Resources deleted.")
+	return nil
+}
+
+func deleteQuotas() error {
+
+	crd := "tridentquotas.trident.netapp.io"
+	logFields := log.Fields{"CRD": crd}
+
+	// See if CRD exists
+	exists, err := kubeClient.CheckCRDExists(crd)
+	if err != nil {
+		return err
+	} else if !exists {
+		log.WithField("CRD", crd).Debug("CRD not present.")
+		return nil
+	}
+
+	quotas, err := crdClientset.TridentV1().TridentQuotas(resetNamespace).List(ctx(), listOpts)
+	if err != nil {
+		return err
+	} else if len(quotas.Items) == 0 {
+		log.WithFields(logFields).Info("Resources not present.")
+		return nil
+	}
+
+	// Quotas have no finalizers, so they may be deleted directly
+	for _, quota := range quotas.Items {
+		deleteFunc := crdClientset.TridentV1().TridentQuotas(resetNamespace).Delete
+		if err := deleteWithRetry(deleteFunc, ctx(), quota.Name, nil); err != nil {
+			log.Errorf("Problem deleting resource: %v", err)
+			return err
+		}
+	}
+
+	log.WithFields(logFields).Info("Resources deleted.")
+	return nil
+}
+
+func deleteBackendConfigs() error {
+
+	crd := "tridentbackendconfigs.trident.netapp.io"
+	logFields := log.Fields{"CRD": crd}
+
+	// See if CRD exists
+	exists, err := kubeClient.CheckCRDExists(crd)
+	if err != nil {
+		return err
+	} else if !exists {
+		log.WithFields(logFields).Debug("CRD not present.")
+		return nil
+	}
+
+	backendConfigs, err := crdClientset.TridentV1().TridentBackendConfigs(resetNamespace).List(ctx(), listOpts)
+	if err != nil {
+		return err
+	} else if len(backendConfigs.Items) == 0 {
+		log.WithFields(logFields).Info("Resources not present.")
+		return nil
+	}
+
+	// The backends are removed with the rest of Trident's state, so just remove the finalizers
+	for _, backendConfig := range backendConfigs.Items {
+		if backendConfig.HasTridentFinalizers() {
+			crCopy := backendConfig.DeepCopy()
+			crCopy.RemoveTridentFinalizers()
+			_, err := crdClientset.TridentV1().TridentBackendConfigs(resetNamespace).Update(ctx(), crCopy, updateOpts)
+			if isNotFoundError(err) {
+				continue
+			} else if err != nil {
+				log.Errorf("Problem removing finalizers: %v", err)
+				return err
+			}
+		}
+
+		deleteFunc := crdClientset.TridentV1().TridentBackendConfigs(resetNamespace).Delete
+		if err := deleteWithRetry(deleteFunc, ctx(), backendConfig.Name, nil); err != nil {
+			log.Errorf("Problem deleting resource: %v", err)
+			return err
+		}
+	}
+
+	log.WithFields(logFields).Info("Resources deleted.")
+	return nil
+}
+
 func deleteCRDs() error {
 
 	crdNames := []string{
@@ -533,6 +740,11 @@ func deleteCRDs() error {
 		"tridentnodes.trident.netapp.io",
 		"tridenttransactions.trident.netapp.io",
 		"tridentsnapshots.trident.netapp.io",
+		"tridentsnapshotschedules.trident.netapp.io",
+		"tridentvolumegroups.trident.netapp.io",
+		"tridentclonegrants.trident.netapp.io",
+		"tridentquotas.trident.netapp.io",
+		"tridentbackendconfigs.trident.netapp.io",
 	}
 
 	for _, crdName := range crdNames {
@@ -0,0 +1,94 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/netapp/trident/cli/api"
+	"github.com/netapp/trident/frontend/rest"
+	"github.com/netapp/trident/storage"
+)
+
+var resizeVolumeSize string
+
+func init() {
+	resizeCmd.AddCommand(resizeVolumeCmd)
+	resizeVolumeCmd.Flags().StringVar(&resizeVolumeSize, "size", "", "New size of the volume, such as 200Gi")
+}
+
+var resizeVolumeCmd = &cobra.Command{
+	Use:   "volume <name> --size <size>",
+	Short: "Resize a volume",
+	Long: "Resize a volume on its backend.  This is meant for Docker and other contexts without " +
+		"Kubernetes, where volumes are resized by editing their PVCs instead.",
+	Aliases: []string{"v"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if OperatingMode == ModeTunnel {
+			command := []string{"resize", "volume"}
+			if resizeVolumeSize != "" {
+				command = append(command, "--size", resizeVolumeSize)
+			}
+			TunnelCommand(append(command, args...))
+			return nil
+		} else {
+			return volumeResize(args)
+		}
+	},
+}
+
+func volumeResize(volumeNames []string) error {
+
+	switch len(volumeNames) {
+	case 0:
+		return errors.New("volume name not specified")
+	case 1:
+		break
+	default:
+		return errors.New("multiple volume names specified")
+	}
+
+	request := storage.ResizeVolumeRequest{Size: resizeVolumeSize}
+	if err := request.Validate(); err != nil {
+		return err
+	}
+	requestBytes, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	url := BaseURL() + "/volume/" + volumeNames[0] + "/resize"
+
+	response, responseBody, err := api.InvokeRESTAPI("POST", url, requestBytes, Debug)
+	if err != nil {
+		return err
+	} else if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("could not resize volume %s: %v", volumeNames[0],
+			GetErrorFromHTTPResponse(response, responseBody))
+	}
+
+	var resizeVolumeResponse rest.ResizeVolumeResponse
+	if err = json.Unmarshal(responseBody, &resizeVolumeResponse); err != nil {
+		return err
+	}
+	if resizeVolumeResponse.Volume == nil {
+		return fmt.Errorf("could not resize volume %s: no volume returned", volumeNames[0])
+	}
+
+	WriteVolumes([]storage.VolumeExternal{*resizeVolumeResponse.Volume})
+
+	if len(resizeVolumeResponse.RescanNodes) > 0 {
+		fmt.Fprintf(os.Stderr, "Volume %s is attached to %s; rescan its LUN and grow its filesystem "+
+			"on those hosts to use the new size.\n", volumeNames[0],
+			strings.Join(resizeVolumeResponse.RescanNodes, ", "))
+	}
+
+	return nil
+}
@@ -0,0 +1,164 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/netapp/trident/cli/api"
+	"github.com/netapp/trident/frontend/rest"
+	"github.com/netapp/trident/storage"
+)
+
+var (
+	restoreSnapshotForce  bool
+	restoreSnapshotDryRun bool
+)
+
+func init() {
+	restoreCmd.AddCommand(restoreSnapshotCmd)
+	restoreSnapshotCmd.Flags().BoolVar(&restoreSnapshotForce, "force", false,
+		"Restore the snapshot even if the volume is published to nodes, such as when those nodes are gone")
+	restoreSnapshotCmd.Flags().BoolVar(&restoreSnapshotDryRun, "dry-run", false,
+		"Check whether the snapshot can be restored, without restoring it")
+}
+
+var restoreSnapshotCmd = &cobra.Command{
+	Use:   "snapshot <volume> <snapshot> | <volume/snapshot>",
+	Short: "Revert a volume to one of its snapshots",
+	Long: "Revert a volume to one of its snapshots.  The restore is checked first, and the newer " +
+		"snapshots it could discard are listed.  The volume must not be published to any node, unless " +
+		"--force is used because those nodes are gone.",
+	Aliases: []string{"s", "snap"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if OperatingMode == ModeTunnel {
+			command := []string{"restore", "snapshot"}
+			if restoreSnapshotForce {
+				command = append(command, "--force")
+			}
+			if restoreSnapshotDryRun {
+				command = append(command, "--dry-run")
+			}
+			TunnelCommand(append(command, args...))
+			return nil
+		} else {
+			return snapshotRestore(args)
+		}
+	},
+}
+
+func snapshotRestore(args []string) error {
+
+	var volumeName, snapshotName string
+	var err error
+
+	switch len(args) {
+	case 0:
+		return errors.New("volume and snapshot not specified")
+	case 1:
+		if volumeName, snapshotName, err = storage.ParseSnapshotID(args[0]); err != nil {
+			return err
+		}
+	case 2:
+		volumeName, snapshotName = args[0], args[1]
+	default:
+		return errors.New("only one snapshot may be restored at a time")
+	}
+	snapshotID := storage.MakeSnapshotID(volumeName, snapshotName)
+
+	report, err := GetSnapshotRestoreReport(volumeName, snapshotName)
+	if err != nil {
+		return err
+	}
+
+	if restoreSnapshotDryRun {
+		writeSnapshotRestoreReport(snapshotID, report)
+		if !report.Ready {
+			return fmt.Errorf("snapshot %s cannot be restored", snapshotID)
+		}
+		return nil
+	}
+
+	// Warnings, such as newer snapshots that may be lost, are shown without mixing them into the output
+	for _, check := range report.Checks {
+		if check.Status == storage.PreflightPassed {
+			continue
+		}
+		for _, finding := range check.Findings {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", check.Status, finding)
+		}
+	}
+	if !report.Ready && !restoreSnapshotForce {
+		return fmt.Errorf("snapshot %s cannot be restored; resolve the failed checks, or use --force if the "+
+			"volume is published to nodes that are gone", snapshotID)
+	}
+
+	url := BaseURL() + "/snapshot/" + snapshotID + "/restore"
+	if restoreSnapshotForce {
+		url += "?force=true"
+	}
+
+	response, responseBody, err := api.InvokeRESTAPI("POST", url, nil, Debug)
+	if err != nil {
+		return err
+	} else if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("could not restore snapshot %s: %v", snapshotID,
+			GetErrorFromHTTPResponse(response, responseBody))
+	}
+
+	snapshot, err := GetSnapshot(snapshotID)
+	if err != nil {
+		return err
+	}
+
+	WriteSnapshots([]storage.SnapshotExternal{snapshot})
+
+	return nil
+}
+
+// GetSnapshotRestoreReport returns Trident's preflight report for restoring a snapshot.
+func GetSnapshotRestoreReport(volumeName, snapshotName string) (*storage.PreflightReport, error) {
+
+	snapshotID := storage.MakeSnapshotID(volumeName, snapshotName)
+	url := BaseURL() + "/snapshot/" + snapshotID + "/restore"
+
+	response, responseBody, err := api.InvokeRESTAPI("GET", url, nil, Debug)
+	if err != nil {
+		return nil, err
+	} else if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not check restore of snapshot %s: %v", snapshotID,
+			GetErrorFromHTTPResponse(response, responseBody))
+	}
+
+	var preflightResponse rest.SnapshotRestorePreflightResponse
+	if err = json.Unmarshal(responseBody, &preflightResponse); err != nil {
+		return nil, err
+	}
+	if preflightResponse.Report == nil {
+		return nil, fmt.Errorf("could not check restore of snapshot %s: no report returned", snapshotID)
+	}
+
+	return preflightResponse.Report, nil
+}
+
+func writeSnapshotRestoreReport(snapshotID string, report *storage.PreflightReport) {
+	switch OutputFormat {
+	case FormatJSON:
+		WriteJSON(report)
+	case FormatYAML:
+		WriteYAML(report)
+	default:
+		writePreflightTable(report)
+		if report.Ready {
+			fmt.Printf("Snapshot %s can be restored.\n", snapshotID)
+		} else {
+			fmt.Printf("Snapshot %s cannot be restored.\n", snapshotID)
+		}
+	}
+}
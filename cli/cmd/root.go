@@ -20,6 +20,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/netapp/trident/cli/api"
+	k8sclient "github.com/netapp/trident/cli/k8s_client"
 	"github.com/netapp/trident/config"
 
 	// Load all auth plugins
@@ -27,10 +28,13 @@ import (
 )
 
 const (
-	FormatJSON = "json"
-	FormatName = "name"
-	FormatWide = "wide"
-	FormatYAML = "yaml"
+	FormatJSON          = "json"
+	FormatName          = "name"
+	FormatWide          = "wide"
+	FormatYAML          = "yaml"
+	FormatCustomColumns = "custom-columns"
+	FormatJSONPath      = "jsonpath"
+	FormatSanitizedJSON = "sanitized-json"
 
 	ModeDirect  = "direct"
 	ModeTunnel  = "tunnel"
@@ -56,6 +60,10 @@ const (
 	TridentNodeLabelValue = "node.csi.trident.netapp.io"
 	TridentNodeLabel      = TridentNodeLabelKey + "=" + TridentNodeLabelValue
 
+	TridentWindowsNodeLabelKey   = "app"
+	TridentWindowsNodeLabelValue = "windows-node.csi.trident.netapp.io"
+	TridentWindowsNodeLabel      = TridentWindowsNodeLabelKey + "=" + TridentWindowsNodeLabelValue
+
 	TridentInstallerLabelKey   = "app"
 	TridentInstallerLabelValue = "trident-installer.netapp.io"
 	TridentInstallerLabel      = TridentInstallerLabelKey + "=" + TridentInstallerLabelValue
@@ -97,7 +105,8 @@ var RootCmd = &cobra.Command{
 func init() {
 	RootCmd.PersistentFlags().BoolVarP(&Debug, "debug", "d", false, "Debug output")
 	RootCmd.PersistentFlags().StringVarP(&Server, "server", "s", "", "Address/port of Trident REST interface")
-	RootCmd.PersistentFlags().StringVarP(&OutputFormat, "output", "o", "", "Output format. One of json|yaml|name|wide|ps (default)")
+	RootCmd.PersistentFlags().StringVarP(&OutputFormat, "output", "o", "",
+		"Output format. One of json|yaml|name|wide|custom-columns=...|jsonpath=...|sanitized-json|ps (default)")
 	RootCmd.PersistentFlags().StringVarP(&TridentPodNamespace, "namespace", "n", "", "Namespace of Trident deployment")
 }
 
@@ -285,13 +294,19 @@ func getTridentPod(namespace, appLabel string) (string, error) {
 		return "", err
 	}
 
-	if len(tridentPod.Items) != 1 {
+	if len(tridentPod.Items) == 0 {
 		return "", fmt.Errorf("could not find a Trident pod in the %s namespace. "+
 			"You may need to use the -n option to specify the correct namespace", namespace)
 	}
 
+	// Of several controller replicas, only the elected leader serves requests
+	pod, err := k8sclient.SelectLeaderPod(tridentPod.Items, appLabel)
+	if err != nil {
+		return "", err
+	}
+
 	// Get Trident pod name & namespace
-	name := tridentPod.Items[0].ObjectMeta.Name
+	name := pod.ObjectMeta.Name
 
 	return name, nil
 }
@@ -496,6 +511,16 @@ func TunnelCommand(commandArgs []string) {
 
 func TunnelCommandRaw(commandArgs []string) ([]byte, error) {
 
+	output, err := tunnelCommandOutput(commandArgs)
+
+	SetExitCodeFromError(err)
+	return output, err
+}
+
+// tunnelCommandOutput invokes tridentctl inside the Trident pod and returns its output without
+// touching the exit code, so it is safe to call from several goroutines at once.
+func tunnelCommandOutput(commandArgs []string) ([]byte, error) {
+
 	// Build tunnel command to exec command in container
 	execCommand := []string{"exec", TridentPodName, "-n", TridentPodNamespace, "-c", config.ContainerTrident, "--"}
 
@@ -511,10 +536,7 @@ func TunnelCommandRaw(commandArgs []string) ([]byte, error) {
 	}
 
 	// Invoke tridentctl inside the Trident pod
-	output, err := exec.Command(KubernetesCLI, execCommand...).CombinedOutput()
-
-	SetExitCodeFromError(err)
-	return output, err
+	return exec.Command(KubernetesCLI, execCommand...).CombinedOutput()
 }
 
 func GetErrorFromHTTPResponse(response *http.Response, responseBody []byte) error {
@@ -283,6 +283,35 @@ func uninstallTrident() error {
 		}
 	}
 
+	// The Windows daemonset is only present if Trident was installed for Windows nodes
+	if daemonset, err := client.GetDaemonSetByLabel(TridentWindowsNodeLabel, true); err != nil {
+
+		log.WithFields(log.Fields{
+			"label": TridentWindowsNodeLabel,
+			"error": err,
+		}).Debug("Trident Windows daemonset not found.")
+
+	} else {
+
+		// Daemonset found by label, so ensure there isn't a namespace clash
+		if TridentPodNamespace != daemonset.Namespace {
+			return fmt.Errorf("a Trident Windows daemonset was found in namespace '%s', "+
+				"not in specified namespace '%s'", daemonset.Namespace, TridentPodNamespace)
+		}
+
+		if err = client.DeleteDaemonSetByLabel(TridentWindowsNodeLabel); err != nil {
+			log.WithFields(log.Fields{
+				"daemonset": daemonset.Name,
+				"namespace": daemonset.Namespace,
+				"label":     TridentWindowsNodeLabel,
+				"error":     err,
+			}).Warning("Could not delete Trident Windows daemonset.")
+			anyErrors = true
+		} else {
+			log.Info("Deleted Trident Windows daemonset.")
+		}
+	}
+
 	if service, err := client.GetServiceByLabel(TridentCSILabel, true); err != nil {
 
 		log.WithFields(log.Fields{
@@ -366,7 +395,7 @@ func uninstallTrident() error {
 	}
 
 	if csi {
-		CSIDriverYAML := k8sclient.GetCSIDriverCRYAML(getCSIDriverName(), nil, nil)
+		CSIDriverYAML := k8sclient.GetCSIDriverCRYAML(getCSIDriverName(), "", false, false, nil, nil)
 
 		if err = client.DeleteObjectByYAML(CSIDriverYAML, true); err != nil {
 			log.WithField("error", err).Warning("Could not delete csidriver custom resource.")
@@ -374,6 +403,35 @@ func uninstallTrident() error {
 		} else {
 			log.WithField("CSIDriver", getCSIDriverName()).Info("Deleted csidriver custom resource.")
 		}
+
+		if client.ServerVersion().MinorVersion() >= 14 {
+			webhookYAML := k8sclient.GetValidatingWebhookConfigurationYAML(getValidatingWebhookName(),
+				getServiceName(), TridentPodNamespace, "", client.ServerVersion(), nil, nil)
+
+			if err = client.DeleteObjectByYAML(webhookYAML, true); err != nil {
+				log.WithField("error", err).Warning("Could not delete validating webhook configuration.")
+				anyErrors = true
+			} else {
+				log.WithField("webhook", getValidatingWebhookName()).Info(
+					"Deleted validating webhook configuration.")
+			}
+		}
+	}
+
+	// The CRDs are kept, so make sure the API server no longer expects Trident to convert their resources
+	if csi {
+		for _, crdName := range CRDnames {
+			if exists, err := client.CheckCRDExists(crdName); err != nil || !exists {
+				continue
+			}
+			if err = client.PatchCRD(crdName, []byte(k8sclient.CRDConversionRemovalPatch)); err != nil {
+				log.WithFields(log.Fields{
+					"CRD":   crdName,
+					"error": err,
+				}).Warning("Could not unregister the CRD conversion webhook.")
+				anyErrors = true
+			}
+		}
 	}
 
 	log.Info("The uninstaller did not delete Trident's namespace in case it is going to be reused.")
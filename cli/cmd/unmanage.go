@@ -0,0 +1,18 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import "github.com/spf13/cobra"
+
+func init() {
+	RootCmd.AddCommand(unmanageCmd)
+}
+
+var unmanageCmd = &cobra.Command{
+	Use:   "unmanage",
+	Short: "Remove a resource from Trident without deleting its storage",
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		err := discoverOperatingMode(cmd)
+		return err
+	},
+}
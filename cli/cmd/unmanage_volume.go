@@ -0,0 +1,83 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/spf13/cobra"
+
+	"github.com/netapp/trident/cli/api"
+	"github.com/netapp/trident/frontend/rest"
+	"github.com/netapp/trident/storage"
+)
+
+var unmanageVolumeStorageName string
+
+func init() {
+	unmanageCmd.AddCommand(unmanageVolumeCmd)
+	unmanageVolumeCmd.Flags().StringVar(&unmanageVolumeStorageName, "storage-name", "",
+		"Name to give the volume on the storage system")
+}
+
+var unmanageVolumeCmd = &cobra.Command{
+	Use:   "volume <name>",
+	Short: "Remove a volume from Trident without deleting its data",
+	Long: "Remove a volume and its snapshots from Trident without deleting any data, the inverse of " +
+		"importing it.  An imported volume is renamed back to its original name on the storage system, " +
+		"unless --storage-name is set.  Other volumes keep their internal names.",
+	Aliases: []string{"v"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if OperatingMode == ModeTunnel {
+			command := []string{"unmanage", "volume"}
+			if unmanageVolumeStorageName != "" {
+				command = append(command, "--storage-name", unmanageVolumeStorageName)
+			}
+			TunnelCommand(append(command, args...))
+			return nil
+		} else {
+			return volumeUnmanage(args)
+		}
+	},
+}
+
+func volumeUnmanage(volumeNames []string) error {
+
+	switch len(volumeNames) {
+	case 0:
+		return errors.New("volume name not specified")
+	case 1:
+		break
+	default:
+		return errors.New("multiple volume names specified")
+	}
+
+	request := storage.UnmanageVolumeRequest{StorageName: unmanageVolumeStorageName}
+	requestBytes, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	url := BaseURL() + "/volume/" + volumeNames[0] + "/unmanage"
+
+	response, responseBody, err := api.InvokeRESTAPI("POST", url, requestBytes, Debug)
+	if err != nil {
+		return err
+	} else if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("could not unmanage volume %s: %v", volumeNames[0],
+			GetErrorFromHTTPResponse(response, responseBody))
+	}
+
+	var unmanageVolumeResponse rest.UnmanageVolumeResponse
+	if err = json.Unmarshal(responseBody, &unmanageVolumeResponse); err != nil {
+		return err
+	}
+
+	fmt.Printf("Volume %s is no longer managed by Trident; its storage is named %s.\n", volumeNames[0],
+		unmanageVolumeResponse.StorageName)
+
+	return nil
+}
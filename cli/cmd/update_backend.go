@@ -8,7 +8,10 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"os"
+	"strings"
 
+	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
 
 	"github.com/netapp/trident/cli/api"
@@ -19,6 +22,7 @@ import (
 var (
 	updateFilename   string
 	updateBase64Data string
+	updateDryRun     bool
 )
 
 func init() {
@@ -26,6 +30,10 @@ func init() {
 	updateBackendCmd.Flags().StringVarP(&updateFilename, "filename", "f", "", "Path to YAML or JSON file")
 	updateBackendCmd.Flags().StringVarP(&updateBase64Data, "base64", "", "", "Base64 encoding")
 	updateBackendCmd.Flags().MarkHidden("base64")
+	updateBackendCmd.Flags().BoolVar(&updateDryRun, "dry-run", false,
+		"Show the changes and affected volumes, but don't update the backend.")
+	updateBackendCmd.Flags().StringVarP(&backendState, "state", "", "",
+		"New backend state (online, cordoned, draining)")
 }
 
 var updateBackendCmd = &cobra.Command{
@@ -34,6 +42,25 @@ var updateBackendCmd = &cobra.Command{
 	Aliases: []string{"b"},
 	RunE: func(cmd *cobra.Command, args []string) error {
 
+		if backendState != "" {
+			if updateFilename != "" || updateBase64Data != "" {
+				return errors.New("a backend's config and state cannot be updated together")
+			}
+
+			newBackendState, err := getBackendState()
+			if err != nil {
+				return err
+			}
+
+			if OperatingMode == ModeTunnel {
+				command := []string{"update", "backend", "--state", newBackendState}
+				TunnelCommand(append(command, args...))
+				return nil
+			} else {
+				return backendUpdateState(args, newBackendState)
+			}
+		}
+
 		jsonData, err := getBackendData(updateFilename, updateBase64Data)
 		if err != nil {
 			return err
@@ -44,8 +71,13 @@ var updateBackendCmd = &cobra.Command{
 				"update", "backend",
 				"--base64", base64.StdEncoding.EncodeToString(jsonData),
 			}
+			if updateDryRun {
+				command = append(command, "--dry-run")
+			}
 			TunnelCommand(append(command, args...))
 			return nil
+		} else if updateDryRun {
+			return backendUpdatePreview(args, jsonData)
 		} else {
 			return backendUpdate(args, jsonData)
 		}
@@ -94,3 +126,87 @@ func backendUpdate(backendNames []string, postData []byte) error {
 
 	return nil
 }
+
+func backendUpdatePreview(backendNames []string, postData []byte) error {
+
+	switch len(backendNames) {
+	case 0:
+		return errors.New("backend name not specified")
+	case 1:
+		break
+	default:
+		return errors.New("multiple backend names specified")
+	}
+
+	url := BaseURL() + "/backend/" + backendNames[0] + "/preview"
+
+	response, responseBody, err := api.InvokeRESTAPI("POST", url, postData, Debug)
+	if err != nil {
+		return err
+	} else if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("could not preview update of backend %s: %v", backendNames[0],
+			GetErrorFromHTTPResponse(response, responseBody))
+	}
+
+	var previewResponse rest.PreviewBackendUpdateResponse
+	err = json.Unmarshal(responseBody, &previewResponse)
+	if err != nil {
+		return err
+	}
+
+	WriteBackendUpdatePreview(previewResponse.Preview)
+
+	return nil
+}
+
+func WriteBackendUpdatePreview(preview *storage.BackendUpdatePreview) {
+	switch OutputFormat {
+	case FormatJSON:
+		WriteJSON(preview)
+	case FormatYAML:
+		WriteYAML(preview)
+	default:
+		writeBackendUpdatePreview(preview)
+	}
+}
+
+func writeBackendUpdatePreview(preview *storage.BackendUpdatePreview) {
+
+	if preview.Allowed {
+		fmt.Printf("Backend %s can be updated.\n", preview.Backend)
+	} else {
+		fmt.Printf("Backend %s cannot be updated: %s\n", preview.Backend, preview.Error)
+	}
+	if len(preview.UpdateTypes) > 0 {
+		fmt.Printf("Update types: %s\n", strings.Join(preview.UpdateTypes, ", "))
+	}
+
+	if len(preview.Changes) == 0 {
+		fmt.Println("No config changes.")
+	} else {
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Field", "Old", "New"})
+		for _, change := range preview.Changes {
+			table.Append([]string{change.Field, formatConfigValue(change.Old), formatConfigValue(change.New)})
+		}
+		table.Render()
+	}
+
+	if len(preview.AffectedVolumes) == 0 {
+		fmt.Println("No volumes affected.")
+	} else {
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Volume", "Impact"})
+		for _, impact := range preview.AffectedVolumes {
+			table.Append([]string{impact.Volume, impact.Impact})
+		}
+		table.Render()
+	}
+}
+
+func formatConfigValue(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	return fmt.Sprintf("%v", value)
+}
@@ -0,0 +1,73 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/spf13/cobra"
+
+	"github.com/netapp/trident/cli/api"
+	"github.com/netapp/trident/frontend/rest"
+	"github.com/netapp/trident/storage"
+)
+
+func init() {
+	updateBackendCmd.AddCommand(updateBackendReencryptCmd)
+}
+
+var updateBackendReencryptCmd = &cobra.Command{
+	Use:   "reencrypt",
+	Short: "Re-encrypt the credentials of all backends with the active encryption key",
+	Long: "Re-encrypt the secrets holding the credentials of all backends with the active key of the " +
+		"configured encryption provider.  Run this after rotating the key, so that the old key may be " +
+		"retired, or after enabling encryption, so that existing credentials are encrypted.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if OperatingMode == ModeTunnel {
+			command := []string{"update", "backend", "reencrypt"}
+			TunnelCommand(append(command, args...))
+			return nil
+		} else {
+			return backendReencrypt(args)
+		}
+	},
+}
+
+func backendReencrypt(args []string) error {
+
+	if len(args) > 0 {
+		return errors.New("the credentials of all backends are re-encrypted, so no backend may be specified")
+	}
+
+	url := BaseURL() + "/backend/secrets/reencrypt"
+
+	response, responseBody, err := api.InvokeRESTAPI("POST", url, nil, Debug)
+	if err != nil {
+		return err
+	} else if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("could not re-encrypt backend secrets: %v",
+			GetErrorFromHTTPResponse(response, responseBody))
+	}
+
+	var reencryptResponse rest.ReencryptSecretsResponse
+	if err = json.Unmarshal(responseBody, &reencryptResponse); err != nil {
+		return err
+	}
+
+	// Retrieve the backends and write to stdout
+	backends := make([]storage.BackendExternal, 0, len(reencryptResponse.Backends))
+	for _, backendName := range reencryptResponse.Backends {
+		backend, err := GetBackend(backendName)
+		if err != nil {
+			return err
+		}
+		backends = append(backends, backend)
+	}
+
+	WriteBackends(backends)
+
+	return nil
+}
@@ -0,0 +1,98 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/netapp/trident/cli/api"
+	"github.com/netapp/trident/frontend/rest"
+	"github.com/netapp/trident/storage"
+)
+
+var debugTraceFlags []string
+
+func init() {
+	updateBackendCmd.AddCommand(updateBackendTracingCmd)
+	updateBackendTracingCmd.Flags().StringSliceVar(&debugTraceFlags, "debug-trace-flags", []string{},
+		"Debug trace flags to enable, such as method,api; any others are disabled")
+}
+
+var updateBackendTracingCmd = &cobra.Command{
+	Use:   "tracing <name>",
+	Short: "Update a backend's debug trace flags in Trident",
+	Long: `Update a backend's debug trace flags in Trident
+
+The backend is reinitialized with the new flags, so no volumes are affected
+and the backend need not be added again.  The flags are saved with the
+backend's config.  Leaving out --debug-trace-flags disables all tracing.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if OperatingMode == ModeTunnel {
+			command := []string{
+				"update", "backend", "tracing", "--debug-trace-flags=" + strings.Join(debugTraceFlags, ","),
+			}
+			TunnelCommand(append(command, args...))
+			return nil
+		} else {
+			return backendUpdateTracing(args, debugTraceFlags)
+		}
+	},
+}
+
+func backendUpdateTracing(backendNames []string, traceFlags []string) error {
+
+	switch len(backendNames) {
+	case 0:
+		return errors.New("backend name not specified")
+	case 1:
+		break
+	default:
+		return errors.New("multiple backend names specified")
+	}
+
+	// Send the new trace flags to Trident
+	url := BaseURL() + "/backend/" + backendNames[0] + "/tracing"
+
+	request := storage.UpdateBackendTracingRequest{
+		DebugTraceFlags: make(map[string]bool),
+	}
+	for _, flag := range traceFlags {
+		if flag = strings.TrimSpace(flag); flag != "" {
+			request.DebugTraceFlags[flag] = true
+		}
+	}
+	requestBytes, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	response, responseBody, err := api.InvokeRESTAPI("POST", url, requestBytes, Debug)
+	if err != nil {
+		return err
+	} else if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("could not update trace flags for backend %s: %v", backendNames[0],
+			GetErrorFromHTTPResponse(response, responseBody))
+	}
+
+	var updateBackendResponse rest.UpdateBackendResponse
+	err = json.Unmarshal(responseBody, &updateBackendResponse)
+	if err != nil {
+		return err
+	}
+
+	// Retrieve the updated backend and write to stdout
+	backend, err := GetBackend(updateBackendResponse.BackendID)
+	if err != nil {
+		return err
+	}
+
+	WriteBackends([]storage.BackendExternal{backend})
+
+	return nil
+}
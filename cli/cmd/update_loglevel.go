@@ -0,0 +1,64 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/spf13/cobra"
+
+	"github.com/netapp/trident/cli/api"
+	"github.com/netapp/trident/frontend/rest"
+)
+
+func init() {
+	updateCmd.AddCommand(updateLogLevelCmd)
+}
+
+var updateLogLevelCmd = &cobra.Command{
+	Use:   "loglevel <level>",
+	Short: "Update Trident's log level",
+	Long: `Update Trident's log level
+
+The log level (debug, info, warn, error, fatal) of the Trident controller is
+changed at once, without restarting it.  The change lasts until the
+controller restarts, when the level reverts to the one it was deployed with.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if OperatingMode == ModeTunnel {
+			command := []string{"update", "loglevel"}
+			TunnelCommand(append(command, args...))
+			return nil
+		} else {
+			return updateLogLevel(args[0])
+		}
+	},
+}
+
+func updateLogLevel(logLevel string) error {
+
+	url := BaseURL() + "/logging/level"
+
+	requestBytes, err := json.Marshal(rest.LogLevelRequest{LogLevel: logLevel})
+	if err != nil {
+		return err
+	}
+
+	response, responseBody, err := api.InvokeRESTAPI("POST", url, requestBytes, Debug)
+	if err != nil {
+		return err
+	} else if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("could not update log level: %v", GetErrorFromHTTPResponse(response, responseBody))
+	}
+
+	var logLevelResponse rest.LogLevelResponse
+	if err = json.Unmarshal(responseBody, &logLevelResponse); err != nil {
+		return err
+	}
+
+	fmt.Printf("Log level is %s.\n", logLevelResponse.LogLevel)
+
+	return nil
+}
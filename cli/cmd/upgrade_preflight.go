@@ -0,0 +1,257 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	apiextensionv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+
+	"github.com/netapp/trident/cli/api"
+	k8sclient "github.com/netapp/trident/cli/k8s_client"
+	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/frontend/rest"
+	"github.com/netapp/trident/storage"
+)
+
+var preflightReportOnly bool
+
+func init() {
+	upgradeCmd.AddCommand(upgradePreflightCmd)
+	upgradePreflightCmd.Flags().BoolVar(&preflightReportOnly, "report-only", false,
+		"Write Trident's report as JSON without failing if it is not ready")
+	upgradePreflightCmd.Flags().MarkHidden("report-only")
+}
+
+var upgradePreflightCmd = &cobra.Command{
+	Use:   "preflight",
+	Short: "Check whether Trident is ready to be upgraded",
+	Long: `Check whether Trident is ready to be upgraded
+
+Trident's state is checked for interrupted operations, deprecated backend
+settings, and backends, volumes and snapshots that aren't online.  When
+tridentctl runs outside the Trident pod, the Trident CRDs and the node
+DaemonSet are checked as well.  A failed check means the upgrade should
+wait until the check's remediation has been followed, and tridentctl
+exits with a nonzero status.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+
+		var report *storage.PreflightReport
+		var err error
+
+		if OperatingMode == ModeTunnel {
+			if report, err = getTunneledPreflightReport(); err != nil {
+				return err
+			}
+
+			client, err := initClient()
+			if err != nil {
+				return fmt.Errorf("could not initialize Kubernetes client; %v", err)
+			}
+			client.SetNamespace(TridentPodNamespace)
+
+			report.AddCheck(checkCRDHealth(client))
+			report.AddCheck(checkNodeDaemonSet(client))
+		} else {
+			if report, err = GetPreflightReport(); err != nil {
+				return err
+			}
+		}
+
+		if preflightReportOnly {
+			WriteJSON(report)
+			return nil
+		}
+
+		WritePreflightReport(report)
+
+		if !report.Ready {
+			return errors.New("trident is not ready to be upgraded")
+		}
+		return nil
+	},
+}
+
+// getTunneledPreflightReport returns the report made by Trident inside its pod.
+func getTunneledPreflightReport() (*storage.PreflightReport, error) {
+
+	output, err := TunnelCommandRaw([]string{"upgrade", "preflight", "--report-only"})
+	if err != nil {
+		return nil, fmt.Errorf("could not check Trident; %v; %s", err, string(output))
+	}
+
+	report := &storage.PreflightReport{}
+	if err = json.Unmarshal(output, report); err != nil {
+		return nil, fmt.Errorf("could not parse Trident's preflight report; %v", err)
+	}
+	return report, nil
+}
+
+func GetPreflightReport() (*storage.PreflightReport, error) {
+
+	url := BaseURL() + "/upgrade/preflight"
+
+	response, responseBody, err := api.InvokeRESTAPI("GET", url, nil, Debug)
+	if err != nil {
+		return nil, err
+	} else if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not check Trident: %v", GetErrorFromHTTPResponse(response, responseBody))
+	}
+
+	var preflightResponse rest.UpgradePreflightResponse
+	if err = json.Unmarshal(responseBody, &preflightResponse); err != nil {
+		return nil, err
+	}
+	if preflightResponse.Report == nil {
+		return nil, errors.New("could not check Trident: no report returned")
+	}
+
+	return preflightResponse.Report, nil
+}
+
+// checkCRDHealth fails if a Trident CRD is missing, isn't established, or is being deleted.
+func checkCRDHealth(client k8sclient.Interface) *storage.PreflightCheck {
+
+	check := &storage.PreflightCheck{Name: "crds"}
+
+	for _, crdName := range CRDnames {
+		crd, err := client.GetCRD(crdName)
+		if err != nil {
+			check.Findings = append(check.Findings, fmt.Sprintf("CRD %s could not be read; %v", crdName, err))
+			continue
+		}
+		if crd.DeletionTimestamp != nil {
+			check.Findings = append(check.Findings, fmt.Sprintf("CRD %s is being deleted.", crdName))
+			continue
+		}
+		if !isCRDEstablished(crd) {
+			check.Findings = append(check.Findings, fmt.Sprintf("CRD %s is not established.", crdName))
+		}
+	}
+
+	if len(check.Findings) > 0 {
+		check.Remediation = "Check the CRDs with 'kubectl describe crd'.  A CRD that is being deleted is " +
+			"waiting for its custom resources' finalizers; reinstall Trident only once the CRD is gone."
+	}
+	return check
+}
+
+func isCRDEstablished(crd *apiextensionv1beta1.CustomResourceDefinition) bool {
+	for _, condition := range crd.Status.Conditions {
+		if condition.Type == apiextensionv1beta1.Established {
+			return condition.Status == apiextensionv1beta1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// checkNodeDaemonSet fails if the Trident node pods don't all run the controller's version, as an
+// upgrade would then start from a mix of versions.
+func checkNodeDaemonSet(client k8sclient.Interface) *storage.PreflightCheck {
+
+	check := &storage.PreflightCheck{Name: "nodeDaemonSet"}
+
+	deployment, err := client.GetDeploymentByLabel(TridentCSILabel, false)
+	if err != nil {
+		check.Findings = append(check.Findings, fmt.Sprintf("The Trident deployment could not be read; %v", err))
+	}
+	daemonSet, err := client.GetDaemonSetByLabel(TridentNodeLabel, false)
+	if err != nil {
+		check.Findings = append(check.Findings, fmt.Sprintf("The Trident DaemonSet could not be read; %v", err))
+	}
+
+	if deployment != nil && daemonSet != nil {
+		controllerImage := tridentContainerImage(deployment.Spec.Template.Spec.Containers)
+		nodeImage := tridentContainerImage(daemonSet.Spec.Template.Spec.Containers)
+		if controllerImage != nodeImage {
+			check.Findings = append(check.Findings, fmt.Sprintf(
+				"The node DaemonSet runs %s, but the controller runs %s.", nodeImage, controllerImage))
+		}
+		check.Findings = append(check.Findings, daemonSetRolloutFindings(daemonSet)...)
+	}
+
+	if len(check.Findings) > 0 {
+		check.Remediation = "Wait for the node DaemonSet to finish rolling out, and check the node pods " +
+			"that aren't ready with 'kubectl describe pod'.  If the images differ, finish the previous " +
+			"install or upgrade of Trident first."
+	}
+	return check
+}
+
+func tridentContainerImage(containers []v1.Container) string {
+	for _, container := range containers {
+		if container.Name == config.ContainerTrident {
+			return container.Image
+		}
+	}
+	return ""
+}
+
+// daemonSetRolloutFindings describes a DaemonSet whose pods aren't all updated and ready.
+func daemonSetRolloutFindings(daemonSet *appsv1.DaemonSet) []string {
+
+	findings := make([]string, 0)
+	status := daemonSet.Status
+
+	if status.ObservedGeneration < daemonSet.Generation {
+		findings = append(findings, "The node DaemonSet's latest spec has not been rolled out yet.")
+	}
+	if status.UpdatedNumberScheduled < status.DesiredNumberScheduled {
+		findings = append(findings, fmt.Sprintf("Only %d of %d node pods run the DaemonSet's latest spec.",
+			status.UpdatedNumberScheduled, status.DesiredNumberScheduled))
+	}
+	if status.NumberReady < status.DesiredNumberScheduled {
+		findings = append(findings, fmt.Sprintf("Only %d of %d node pods are ready.",
+			status.NumberReady, status.DesiredNumberScheduled))
+	}
+	return findings
+}
+
+func WritePreflightReport(report *storage.PreflightReport) {
+	switch OutputFormat {
+	case FormatJSON:
+		WriteJSON(report)
+	case FormatYAML:
+		WriteYAML(report)
+	default:
+		writePreflightTable(report)
+		if report.Ready {
+			fmt.Println("Trident is ready to be upgraded.")
+		} else {
+			fmt.Println("Trident is not ready to be upgraded.")
+		}
+	}
+}
+
+func writePreflightTable(report *storage.PreflightReport) {
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Check", "Status", "Findings"})
+	table.SetAutoWrapText(false)
+
+	for _, check := range report.Checks {
+		table.Append([]string{
+			check.Name,
+			string(check.Status),
+			strings.Join(check.Findings, "\n"),
+		})
+	}
+
+	table.Render()
+
+	for _, check := range report.Checks {
+		if check.Status != storage.PreflightPassed && check.Remediation != "" {
+			fmt.Printf("%s: %s\n", check.Name, check.Remediation)
+		}
+	}
+}
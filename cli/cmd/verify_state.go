@@ -0,0 +1,184 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/netapp/trident/cli/api"
+	"github.com/netapp/trident/frontend/rest"
+	"github.com/netapp/trident/storage"
+)
+
+var stateRepair string
+
+func init() {
+	verifyCmd.AddCommand(verifyStateCmd)
+	verifyStateCmd.Flags().StringVar(&stateRepair, "repair", "",
+		"Repair each issue it applies to (readopt, prune or rebuild)")
+}
+
+var verifyStateCmd = &cobra.Command{
+	Use:   "state",
+	Short: "Check Trident's state for corruption, optionally repairing it",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		repair, err := storage.ParseStateRepair(stateRepair)
+		if err != nil {
+			return err
+		}
+
+		if OperatingMode == ModeTunnel {
+			command := []string{"verify", "state"}
+			if repair != "" {
+				command = append(command, "--repair", string(repair))
+			}
+			TunnelCommand(command)
+			return nil
+		} else {
+			return stateVerify(repair)
+		}
+	},
+}
+
+func stateVerify(repair storage.StateRepair) error {
+
+	verification, err := VerifyState(repair)
+	if err != nil {
+		return err
+	}
+
+	for _, message := range verification.Errors {
+		fmt.Fprintln(os.Stderr, "Warning: "+message)
+	}
+
+	issues := make([]storage.StateIssue, 0, len(verification.Issues))
+	for _, issue := range verification.Issues {
+		issues = append(issues, *issue)
+	}
+
+	WriteStateIssues(issues)
+
+	if repair == "" && len(issues) > 0 {
+		fmt.Fprintln(os.Stderr, "Run 'tridentctl verify state --repair <repair>' to apply one of each "+
+			"issue's repairs.")
+	}
+
+	return nil
+}
+
+func VerifyState(repair storage.StateRepair) (*storage.StateVerification, error) {
+
+	url := BaseURL() + "/state/verify"
+
+	body, err := json.Marshal(storage.StateVerifyRequest{Repair: repair})
+	if err != nil {
+		return nil, err
+	}
+
+	response, responseBody, err := api.InvokeRESTAPI("POST", url, body, Debug)
+	if err != nil {
+		return nil, err
+	} else if response.StatusCode != http.StatusCreated {
+		return nil, fmt.Errorf("could not verify state: %v", GetErrorFromHTTPResponse(response, responseBody))
+	}
+
+	var verificationResponse rest.StateVerificationResponse
+	err = json.Unmarshal(responseBody, &verificationResponse)
+	if err != nil {
+		return nil, err
+	}
+
+	return verificationResponse.Verification, nil
+}
+
+func WriteStateIssues(issues []storage.StateIssue) {
+	switch outputFormatName() {
+	case FormatJSON:
+		WriteJSON(api.MultipleStateIssueResponse{Items: issues})
+	case FormatYAML:
+		WriteYAML(api.MultipleStateIssueResponse{Items: issues})
+	case FormatCustomColumns, FormatJSONPath:
+		WriteCustom(api.MultipleStateIssueResponse{Items: issues})
+	case FormatName:
+		writeStateIssueNames(issues)
+	case FormatWide:
+		writeWideStateIssueTable(issues)
+	default:
+		writeStateIssueTable(issues)
+	}
+}
+
+func stateRepairsString(repairs []storage.StateRepair) string {
+	names := make([]string, 0, len(repairs))
+	for _, repair := range repairs {
+		names = append(names, string(repair))
+	}
+	return strings.Join(names, ",")
+}
+
+func writeStateIssueTable(issues []storage.StateIssue) {
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Type", "Kind", "Name", "Reference", "Repairs", "Repaired"})
+
+	for _, issue := range issues {
+
+		table.Append([]string{
+			string(issue.Type),
+			issue.Kind,
+			issue.Name,
+			issue.Reference,
+			stateRepairsString(issue.Repairs),
+			string(issue.Repaired),
+		})
+	}
+
+	table.Render()
+}
+
+func writeWideStateIssueTable(issues []storage.StateIssue) {
+
+	table := tablewriter.NewWriter(os.Stdout)
+	header := []string{
+		"Type",
+		"Kind",
+		"Name",
+		"Reference",
+		"Expected",
+		"Actual",
+		"Repairs",
+		"Repaired",
+		"Message",
+	}
+	table.SetHeader(header)
+
+	for _, issue := range issues {
+
+		table.Append([]string{
+			string(issue.Type),
+			issue.Kind,
+			issue.Name,
+			issue.Reference,
+			issue.Expected,
+			issue.Actual,
+			stateRepairsString(issue.Repairs),
+			string(issue.Repaired),
+			issue.Message,
+		})
+	}
+
+	table.Render()
+}
+
+func writeStateIssueNames(issues []storage.StateIssue) {
+	for _, issue := range issues {
+		fmt.Println(issue.Kind + "/" + issue.Name)
+	}
+}
@@ -1164,6 +1164,21 @@ func (c *KubectlClient) DeleteCRD(crdName string) error {
 	return nil
 }
 
+// PatchCRD applies a JSON merge patch to a CRD.  CRDs don't support strategic merge patches.  The
+// v1beta1 API is named explicitly so that patches have the same schema as with KubeClient.
+func (c *KubectlClient) PatchCRD(crdName string, patchBytes []byte) error {
+
+	args := []string{"patch", "customresourcedefinitions.v1beta1.apiextensions.k8s.io", crdName, "-p", string(patchBytes), "--type", "merge"}
+	out, err := exec.Command(c.cli, args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s; %v", string(out), err)
+	}
+
+	log.WithField("CRD", crdName).Debug("Patched CRD.")
+
+	return nil
+}
+
 // GetPodSecurityPolicyByLabel returns a pod security policy object matching the specified label if it is unique
 func (c *KubectlClient) GetPodSecurityPolicyByLabel(label string) (*v1beta1.PodSecurityPolicy, error) {
 
@@ -39,6 +39,7 @@ import (
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/remotecommand"
 
+	"github.com/netapp/trident/config"
 	crdclient "github.com/netapp/trident/persistent_store/crd/client/clientset/versioned"
 	"github.com/netapp/trident/utils"
 )
@@ -123,6 +124,7 @@ type Interface interface {
 	GetCRD(crdName string) (*apiextensionv1beta1.CustomResourceDefinition, error)
 	CheckCRDExists(crdName string) (bool, error)
 	DeleteCRD(crdName string) error
+	PatchCRD(crdName string, patchBytes []byte) error
 	GetPodSecurityPolicyByLabel(label string) (*v1beta1.PodSecurityPolicy, error)
 	GetPodSecurityPoliciesByLabel(label string) ([]v1beta1.PodSecurityPolicy, error)
 	CheckPodSecurityPolicyExistsByLabel(label string) (bool, string, error)
@@ -956,6 +958,24 @@ func (k *KubeClient) GetPodsByLabel(label string, allNamespaces bool) ([]v1.Pod,
 	return podList.Items, nil
 }
 
+// SelectLeaderPod returns the only pod in a list, or, if several Trident controller replicas are running,
+// the one labeled as the elected leader.
+func SelectLeaderPod(pods []v1.Pod, label string) (*v1.Pod, error) {
+
+	if len(pods) == 1 {
+		return &pods[0], nil
+	} else if len(pods) == 0 {
+		return nil, utils.NotFoundError(fmt.Sprintf("no pods have the label %s", label))
+	}
+
+	for i := range pods {
+		if pods[i].Labels[config.LeaderLabelKey] == config.LeaderLabelValue {
+			return &pods[i], nil
+		}
+	}
+	return nil, fmt.Errorf("none of the %d pods with the label %s is the elected leader", len(pods), label)
+}
+
 // CheckPodExistsByLabel returns true if one or more pod objects
 // matching the specified label exist.
 func (k *KubeClient) CheckPodExistsByLabel(label string, allNamespaces bool) (bool, string, error) {
@@ -1158,6 +1178,19 @@ func (k *KubeClient) DeleteCRD(crdName string) error {
 	return k.extClientset.ApiextensionsV1beta1().CustomResourceDefinitions().Delete(ctx(), crdName, k.deleteOptions())
 }
 
+// PatchCRD applies a JSON merge patch to a CRD.  CRDs don't support strategic merge patches.
+func (k *KubeClient) PatchCRD(crdName string, patchBytes []byte) error {
+
+	if _, err := k.extClientset.ApiextensionsV1beta1().CustomResourceDefinitions().Patch(ctx(), crdName,
+		commontypes.MergePatchType, patchBytes, patchOpts); err != nil {
+		return err
+	}
+
+	log.WithField("CRD", crdName).Debug("Patched CRD.")
+
+	return nil
+}
+
 // GetPodSecurityPolicyByLabel returns a pod security policy object matching the specified label if it is unique
 func (k *KubeClient) GetPodSecurityPolicyByLabel(label string) (*v1beta1.PodSecurityPolicy, error) {
 
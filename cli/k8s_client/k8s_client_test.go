@@ -0,0 +1,42 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package k8sclient
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/utils"
+)
+
+func TestSelectLeaderPod(t *testing.T) {
+
+	label := "app=controller.csi.trident.netapp.io"
+	newPod := func(name string, leader bool) v1.Pod {
+		pod := v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{}}}
+		if leader {
+			pod.Labels[config.LeaderLabelKey] = config.LeaderLabelValue
+		}
+		return pod
+	}
+
+	_, err := SelectLeaderPod(nil, label)
+	assert.True(t, utils.IsNotFoundError(err))
+
+	// A single pod is selected even before it is labeled as the leader
+	pod, err := SelectLeaderPod([]v1.Pod{newPod("a", false)}, label)
+	assert.NoError(t, err)
+	assert.Equal(t, "a", pod.Name)
+
+	// Of several pods, only the leader is selected
+	_, err = SelectLeaderPod([]v1.Pod{newPod("a", false), newPod("b", false)}, label)
+	assert.Error(t, err)
+
+	pod, err = SelectLeaderPod([]v1.Pod{newPod("a", false), newPod("b", true), newPod("c", false)}, label)
+	assert.NoError(t, err)
+	assert.Equal(t, "b", pod.Name)
+}
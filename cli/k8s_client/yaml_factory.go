@@ -4,15 +4,47 @@ package k8sclient
 
 import (
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
 
+	"github.com/ghodss/yaml"
+	v1 "k8s.io/api/core/v1"
+
 	"github.com/netapp/trident/utils"
 )
 
 const (
 	TridentAppLabelKey = "app"
+
+	// Values of the CSIDriver fsGroupPolicy, which controls whether kubelet changes the ownership
+	// and permissions of a volume to match a pod's fsGroup
+	FSGroupPolicyNone                    = "None"
+	FSGroupPolicyFile                    = "File"
+	FSGroupPolicyReadWriteOnceWithFSType = "ReadWriteOnceWithFSType"
 )
 
+// PodScheduling holds the user-specified placement and resources of a Trident pod.  Node selector
+// labels are added to Trident's own, while tolerations and affinity replace Trident's defaults.
+// Resources apply to the trident-main container.
+type PodScheduling struct {
+	NodeSelector map[string]string
+	Tolerations  []v1.Toleration
+	Affinity     *v1.Affinity
+	Resources    *v1.ResourceRequirements
+}
+
+// IsValidFSGroupPolicy returns true if the specified CSIDriver fsGroupPolicy is supported.  An empty
+// policy is valid and leaves the choice to Kubernetes.
+func IsValidFSGroupPolicy(fsGroupPolicy string) bool {
+	switch fsGroupPolicy {
+	case "", FSGroupPolicyNone, FSGroupPolicyFile, FSGroupPolicyReadWriteOnceWithFSType:
+		return true
+	default:
+		return false
+	}
+}
+
 func GetNamespaceYAML(namespace string) string {
 	return strings.ReplaceAll(namespaceYAMLTemplate, "{NAMESPACE}", namespace)
 }
@@ -114,7 +146,7 @@ rules:
     resources: ["customresourcedefinitions"]
     verbs: ["get", "list", "watch"]
   - apiGroups: ["trident.netapp.io"]
-    resources: ["tridentversions", "tridentbackends", "tridentstorageclasses", "tridentvolumes","tridentnodes", "tridenttransactions", "tridentsnapshots"]
+    resources: ["tridentversions", "tridentbackends", "tridentstorageclasses", "tridentvolumes","tridentnodes", "tridenttransactions", "tridentsnapshots", "tridentsnapshotschedules", "tridentvolumegroups", "tridentclonegrants", "tridentquotas", "tridentbackendconfigs", "tridentbackendconfigs/status", "tridentbackends/status", "tridentvolumes/status", "tridentsnapshotschedules/status", "tridentvolumegroups/status", "tridentclonegrants/status", "tridentquotas/status"]
     verbs: ["get", "list", "watch", "create", "delete", "update", "patch"]
   - apiGroups: ["policy"]
     resources: ["podsecuritypolicies"]
@@ -149,14 +181,20 @@ rules:
   - apiGroups: [""]
     resources: ["pods"]
     verbs: ["get", "list", "watch", "create", "delete", "update", "patch"]
+  - apiGroups: [""]
+    resources: ["namespaces"]
+    verbs: ["get"]
   - apiGroups: [""]
     resources: ["nodes"]
     verbs: ["get", "list", "watch", "update"]
   - apiGroups: ["storage.k8s.io"]
     resources: ["volumeattachments"]
-    verbs: ["get", "list", "watch", "update", "patch"]
+    verbs: ["get", "list", "watch", "update", "patch", "delete"]
+  - apiGroups: ["snapshot.storage.k8s.io"]
+    resources: ["volumesnapshots"]
+    verbs: ["get", "list", "watch", "create", "delete", "update", "patch"]
   - apiGroups: ["snapshot.storage.k8s.io"]
-    resources: ["volumesnapshots", "volumesnapshotclasses"]
+    resources: ["volumesnapshotclasses"]
     verbs: ["get", "list", "watch", "update", "patch"]
   - apiGroups: ["snapshot.storage.k8s.io"]
     resources: ["volumesnapshots/status", "volumesnapshotcontents/status"]
@@ -170,12 +208,21 @@ rules:
   - apiGroups: ["storage.k8s.io"]
     resources: ["csidrivers", "csinodes"]
     verbs: ["get", "list", "watch", "create", "delete", "update", "patch"]
+  - apiGroups: ["storage.k8s.io"]
+    resources: ["csistoragecapacities"]
+    verbs: ["get", "list", "watch", "create", "delete", "update", "patch"]
+  - apiGroups: ["gateway.networking.k8s.io"]
+    resources: ["referencegrants"]
+    verbs: ["get", "list", "watch"]
   - apiGroups: ["apiextensions.k8s.io"]
     resources: ["customresourcedefinitions"]
     verbs: ["get", "list", "watch", "create", "delete", "update", "patch"]
   - apiGroups: ["trident.netapp.io"]
-    resources: ["tridentversions", "tridentbackends", "tridentstorageclasses", "tridentvolumes","tridentnodes", "tridenttransactions", "tridentsnapshots"]
+    resources: ["tridentversions", "tridentbackends", "tridentstorageclasses", "tridentvolumes","tridentnodes", "tridenttransactions", "tridentsnapshots", "tridentsnapshotschedules", "tridentvolumegroups", "tridentclonegrants", "tridentquotas", "tridentbackendconfigs", "tridentbackendconfigs/status", "tridentbackends/status", "tridentvolumes/status", "tridentsnapshotschedules/status", "tridentvolumegroups/status", "tridentclonegrants/status", "tridentquotas/status"]
     verbs: ["get", "list", "watch", "create", "delete", "update", "patch"]
+  - apiGroups: ["coordination.k8s.io"]
+    resources: ["leases"]
+    verbs: ["get", "list", "watch", "create", "update", "patch"]
   - apiGroups: ["policy"]
     resources: ["podsecuritypolicies"]
     verbs: ["use"]
@@ -320,6 +367,7 @@ metadata:
 spec:
   selector:
     app: {LABEL_APP}
+    trident.netapp.io/leader: "true"
   ports:
   - name: https
     protocol: TCP
@@ -329,11 +377,15 @@ spec:
     protocol: TCP
     port: 9220
     targetPort: 8001
+  - name: webhook
+    protocol: TCP
+    port: 443
+    targetPort: 8444
 `
 
-func GetCSIDeploymentYAML(deploymentName, tridentImage, imageRegistry, logFormat string,
+func GetCSIDeploymentYAML(deploymentName, tridentImage, imageRegistry, logFormat string, replicas int,
 	imagePullSecrets []string, labels,
-	controllingCRDetails map[string]string,
+	controllingCRDetails map[string]string, scheduling *PodScheduling,
 	debug, useIPv6 bool, version *utils.Version) string {
 
 	var debugLine, logLevel, ipLocalhost string
@@ -350,6 +402,10 @@ func GetCSIDeploymentYAML(deploymentName, tridentImage, imageRegistry, logFormat
 	} else {
 		ipLocalhost = "127.0.0.1"
 	}
+	// Replicas beyond the first stand by to take over as the leader
+	if replicas < 1 {
+		replicas = 1
+	}
 
 	var deploymentYAML string
 	switch version.MinorVersion() {
@@ -365,6 +421,7 @@ func GetCSIDeploymentYAML(deploymentName, tridentImage, imageRegistry, logFormat
 		deploymentYAML = csiDeployment117YAMLTemplate
 	}
 
+	deploymentYAML = replacePodScheduling(deploymentYAML, scheduling, csiDeploymentAffinityYAML, "")
 	deploymentYAML = strings.ReplaceAll(deploymentYAML, "{TRIDENT_IMAGE}", tridentImage)
 	deploymentYAML = strings.ReplaceAll(deploymentYAML, "{DEPLOYMENT_NAME}", deploymentName)
 	deploymentYAML = strings.ReplaceAll(deploymentYAML, "{CSI_SIDECAR_REGISTRY}", imageRegistry)
@@ -373,11 +430,24 @@ func GetCSIDeploymentYAML(deploymentName, tridentImage, imageRegistry, logFormat
 	deploymentYAML = strings.ReplaceAll(deploymentYAML, "{LOG_LEVEL}", logLevel)
 	deploymentYAML = strings.ReplaceAll(deploymentYAML, "{LOG_FORMAT}", logFormat)
 	deploymentYAML = strings.ReplaceAll(deploymentYAML, "{IP_LOCALHOST}", ipLocalhost)
+	deploymentYAML = strings.ReplaceAll(deploymentYAML, "{REPLICAS}", strconv.Itoa(replicas))
 	deploymentYAML = replaceMultiline(deploymentYAML, labels, controllingCRDetails, imagePullSecrets)
 
 	return deploymentYAML
 }
 
+// csiDeploymentAffinityYAML spreads the controller replicas across nodes
+const csiDeploymentAffinityYAML = `affinity:
+  podAntiAffinity:
+    preferredDuringSchedulingIgnoredDuringExecution:
+    - weight: 100
+      podAffinityTerm:
+        labelSelector:
+          matchLabels:
+            app: {LABEL_APP}
+        topologyKey: kubernetes.io/hostname
+`
+
 const csiDeployment113YAMLTemplate = `---
 apiVersion: apps/v1
 kind: Deployment
@@ -386,7 +456,7 @@ metadata:
   {LABELS}
   {OWNER_REF}
 spec:
-  replicas: 1
+  replicas: {REPLICAS}
   strategy:
     type: Recreate
   selector:
@@ -398,12 +468,15 @@ spec:
         app: {LABEL_APP}
     spec:
       serviceAccount: trident-csi
+      {AFFINITY}
       containers:
       - name: trident-main
         image: {TRIDENT_IMAGE}
+        {RESOURCES}
         ports:
         - containerPort: 8443
         - containerPort: 8001
+        - containerPort: 8444
         command:
         - /usr/local/bin/trident_orchestrator
         args:
@@ -411,25 +484,35 @@ spec:
         - "--k8s_pod"
         - "--https_rest"
         - "--https_port=8443"
+        - "--webhook"
+        - "--webhook_port=8444"
         - "--csi_node_name=$(KUBE_NODE_NAME)"
         - "--csi_endpoint=$(CSI_ENDPOINT)"
         - "--csi_role=controller"
         - "--log_format={LOG_FORMAT}"
         - "--address={IP_LOCALHOST}"
         - "--metrics"
+        - "--leader_election"
         {DEBUG}
         livenessProbe:
-          exec:
-            command:
-            - tridentctl
-            - -s
-            - "{IP_LOCALHOST}:8000"
-            - version
+          httpGet:
+            path: /health
+            port: 8001
           failureThreshold: 2
           initialDelaySeconds: 120
           periodSeconds: 120
           timeoutSeconds: 90
         env:
+        - name: POD_NAME
+          valueFrom:
+            fieldRef:
+              apiVersion: v1
+              fieldPath: metadata.name
+        - name: POD_NAMESPACE
+          valueFrom:
+            fieldRef:
+              apiVersion: v1
+              fieldPath: metadata.namespace
         - name: KUBE_NODE_NAME
           valueFrom:
             fieldRef:
@@ -451,9 +534,17 @@ spec:
         - "--v={LOG_LEVEL}"
         - "--connection-timeout=24h"
         - "--csi-address=$(ADDRESS)"
+        - "--enable-leader-election"
+        - "--leader-election-type=leases"
+        - "--leader-election-namespace=$(POD_NAMESPACE)"
         env:
         - name: ADDRESS
           value: /var/lib/csi/sockets/pluginproxy/csi.sock
+        - name: POD_NAMESPACE
+          valueFrom:
+            fieldRef:
+              apiVersion: v1
+              fieldPath: metadata.namespace
         volumeMounts:
         - name: socket-dir
           mountPath: /var/lib/csi/sockets/pluginproxy/
@@ -464,9 +555,16 @@ spec:
         - "--connection-timeout=24h"
         - "--timeout=60s"
         - "--csi-address=$(ADDRESS)"
+        - "--leader-election"
+        - "--leader-election-namespace=$(POD_NAMESPACE)"
         env:
         - name: ADDRESS
           value: /var/lib/csi/sockets/pluginproxy/csi.sock
+        - name: POD_NAMESPACE
+          valueFrom:
+            fieldRef:
+              apiVersion: v1
+              fieldPath: metadata.namespace
         volumeMounts:
         - name: socket-dir
           mountPath: /var/lib/csi/sockets/pluginproxy/
@@ -486,6 +584,8 @@ spec:
       nodeSelector:
         beta.kubernetes.io/os: linux
         beta.kubernetes.io/arch: amd64
+        {NODE_SELECTOR}
+      {TOLERATIONS}
       volumes:
       - name: socket-dir
         emptyDir:
@@ -502,7 +602,7 @@ metadata:
   {LABELS}
   {OWNER_REF}
 spec:
-  replicas: 1
+  replicas: {REPLICAS}
   strategy:
     type: Recreate
   selector:
@@ -514,12 +614,15 @@ spec:
         app: {LABEL_APP}
     spec:
       serviceAccount: trident-csi
+      {AFFINITY}
       containers:
       - name: trident-main
         image: {TRIDENT_IMAGE}
+        {RESOURCES}
         ports:
         - containerPort: 8443
         - containerPort: 8001
+        - containerPort: 8444
         command:
         - /usr/local/bin/trident_orchestrator
         args:
@@ -527,25 +630,35 @@ spec:
         - "--k8s_pod"
         - "--https_rest"
         - "--https_port=8443"
+        - "--webhook"
+        - "--webhook_port=8444"
         - "--csi_node_name=$(KUBE_NODE_NAME)"
         - "--csi_endpoint=$(CSI_ENDPOINT)"
         - "--csi_role=controller"
         - "--log_format={LOG_FORMAT}"
         - "--address={IP_LOCALHOST}"
         - "--metrics"
+        - "--leader_election"
         {DEBUG}
         livenessProbe:
-          exec:
-            command:
-            - tridentctl
-            - -s
-            - "{IP_LOCALHOST}:8000"
-            - version
+          httpGet:
+            path: /health
+            port: 8001
           failureThreshold: 2
           initialDelaySeconds: 120
           periodSeconds: 120
           timeoutSeconds: 90
         env:
+        - name: POD_NAME
+          valueFrom:
+            fieldRef:
+              apiVersion: v1
+              fieldPath: metadata.name
+        - name: POD_NAMESPACE
+          valueFrom:
+            fieldRef:
+              apiVersion: v1
+              fieldPath: metadata.namespace
         - name: KUBE_NODE_NAME
           valueFrom:
             fieldRef:
@@ -567,9 +680,17 @@ spec:
         - "--v={LOG_LEVEL}"
         - "--timeout=600s"
         - "--csi-address=$(ADDRESS)"
+        - "--leader-election"
+        - "--leader-election-namespace=$(POD_NAMESPACE)"
+        - "--feature-gates=Topology=true"
         env:
         - name: ADDRESS
           value: /var/lib/csi/sockets/pluginproxy/csi.sock
+        - name: POD_NAMESPACE
+          valueFrom:
+            fieldRef:
+              apiVersion: v1
+              fieldPath: metadata.namespace
         volumeMounts:
         - name: socket-dir
           mountPath: /var/lib/csi/sockets/pluginproxy/
@@ -580,9 +701,16 @@ spec:
         - "--timeout=60s"
         - "--retry-interval-start=10s"
         - "--csi-address=$(ADDRESS)"
+        - "--leader-election"
+        - "--leader-election-namespace=$(POD_NAMESPACE)"
         env:
         - name: ADDRESS
           value: /var/lib/csi/sockets/pluginproxy/csi.sock
+        - name: POD_NAMESPACE
+          valueFrom:
+            fieldRef:
+              apiVersion: v1
+              fieldPath: metadata.namespace
         volumeMounts:
         - name: socket-dir
           mountPath: /var/lib/csi/sockets/pluginproxy/
@@ -590,6 +718,8 @@ spec:
       nodeSelector:
         kubernetes.io/os: linux
         kubernetes.io/arch: amd64
+        {NODE_SELECTOR}
+      {TOLERATIONS}
       volumes:
       - name: socket-dir
         emptyDir:
@@ -606,7 +736,7 @@ metadata:
   {LABELS}
   {OWNER_REF}
 spec:
-  replicas: 1
+  replicas: {REPLICAS}
   strategy:
     type: Recreate
   selector:
@@ -618,12 +748,15 @@ spec:
         app: {LABEL_APP}
     spec:
       serviceAccount: trident-csi
+      {AFFINITY}
       containers:
       - name: trident-main
         image: {TRIDENT_IMAGE}
+        {RESOURCES}
         ports:
         - containerPort: 8443
         - containerPort: 8001
+        - containerPort: 8444
         command:
         - /usr/local/bin/trident_orchestrator
         args:
@@ -631,25 +764,35 @@ spec:
         - "--k8s_pod"
         - "--https_rest"
         - "--https_port=8443"
+        - "--webhook"
+        - "--webhook_port=8444"
         - "--csi_node_name=$(KUBE_NODE_NAME)"
         - "--csi_endpoint=$(CSI_ENDPOINT)"
         - "--csi_role=controller"
         - "--log_format={LOG_FORMAT}"
         - "--address={IP_LOCALHOST}"
         - "--metrics"
+        - "--leader_election"
         {DEBUG}
         livenessProbe:
-          exec:
-            command:
-            - tridentctl
-            - -s
-            - "{IP_LOCALHOST}:8000"
-            - version
+          httpGet:
+            path: /health
+            port: 8001
           failureThreshold: 2
           initialDelaySeconds: 120
           periodSeconds: 120
           timeoutSeconds: 90
         env:
+        - name: POD_NAME
+          valueFrom:
+            fieldRef:
+              apiVersion: v1
+              fieldPath: metadata.name
+        - name: POD_NAMESPACE
+          valueFrom:
+            fieldRef:
+              apiVersion: v1
+              fieldPath: metadata.namespace
         - name: KUBE_NODE_NAME
           valueFrom:
             fieldRef:
@@ -671,9 +814,17 @@ spec:
         - "--v={LOG_LEVEL}"
         - "--timeout=600s"
         - "--csi-address=$(ADDRESS)"
+        - "--leader-election"
+        - "--leader-election-namespace=$(POD_NAMESPACE)"
+        - "--feature-gates=Topology=true"
         env:
         - name: ADDRESS
           value: /var/lib/csi/sockets/pluginproxy/csi.sock
+        - name: POD_NAMESPACE
+          valueFrom:
+            fieldRef:
+              apiVersion: v1
+              fieldPath: metadata.namespace
         volumeMounts:
         - name: socket-dir
           mountPath: /var/lib/csi/sockets/pluginproxy/
@@ -684,9 +835,16 @@ spec:
         - "--timeout=60s"
         - "--retry-interval-start=10s"
         - "--csi-address=$(ADDRESS)"
+        - "--leader-election"
+        - "--leader-election-namespace=$(POD_NAMESPACE)"
         env:
         - name: ADDRESS
           value: /var/lib/csi/sockets/pluginproxy/csi.sock
+        - name: POD_NAMESPACE
+          valueFrom:
+            fieldRef:
+              apiVersion: v1
+              fieldPath: metadata.namespace
         volumeMounts:
         - name: socket-dir
           mountPath: /var/lib/csi/sockets/pluginproxy/
@@ -696,9 +854,16 @@ spec:
         - "--v={LOG_LEVEL}"
         - "--csiTimeout=300s"
         - "--csi-address=$(ADDRESS)"
+        - "--leader-election"
+        - "--leader-election-namespace=$(POD_NAMESPACE)"
         env:
         - name: ADDRESS
           value: /var/lib/csi/sockets/pluginproxy/csi.sock
+        - name: POD_NAMESPACE
+          valueFrom:
+            fieldRef:
+              apiVersion: v1
+              fieldPath: metadata.namespace
         volumeMounts:
         - name: socket-dir
           mountPath: /var/lib/csi/sockets/pluginproxy/
@@ -706,6 +871,8 @@ spec:
       nodeSelector:
         kubernetes.io/os: linux
         kubernetes.io/arch: amd64
+        {NODE_SELECTOR}
+      {TOLERATIONS}
       volumes:
       - name: socket-dir
         emptyDir:
@@ -722,7 +889,7 @@ metadata:
   {LABELS}
   {OWNER_REF}
 spec:
-  replicas: 1
+  replicas: {REPLICAS}
   strategy:
     type: Recreate
   selector:
@@ -734,12 +901,15 @@ spec:
         app: {LABEL_APP}
     spec:
       serviceAccount: trident-csi
+      {AFFINITY}
       containers:
       - name: trident-main
         image: {TRIDENT_IMAGE}
+        {RESOURCES}
         ports:
         - containerPort: 8443
         - containerPort: 8001
+        - containerPort: 8444
         command:
         - /usr/local/bin/trident_orchestrator
         args:
@@ -747,25 +917,35 @@ spec:
         - "--k8s_pod"
         - "--https_rest"
         - "--https_port=8443"
+        - "--webhook"
+        - "--webhook_port=8444"
         - "--csi_node_name=$(KUBE_NODE_NAME)"
         - "--csi_endpoint=$(CSI_ENDPOINT)"
         - "--csi_role=controller"
         - "--log_format={LOG_FORMAT}"
         - "--address={IP_LOCALHOST}"
         - "--metrics"
+        - "--leader_election"
         {DEBUG}
         livenessProbe:
-          exec:
-            command:
-            - tridentctl
-            - -s
-            - "{IP_LOCALHOST}:8000"
-            - version
+          httpGet:
+            path: /health
+            port: 8001
           failureThreshold: 2
           initialDelaySeconds: 120
           periodSeconds: 120
           timeoutSeconds: 90
         env:
+        - name: POD_NAME
+          valueFrom:
+            fieldRef:
+              apiVersion: v1
+              fieldPath: metadata.name
+        - name: POD_NAMESPACE
+          valueFrom:
+            fieldRef:
+              apiVersion: v1
+              fieldPath: metadata.namespace
         - name: KUBE_NODE_NAME
           valueFrom:
             fieldRef:
@@ -787,9 +967,17 @@ spec:
         - "--v={LOG_LEVEL}"
         - "--timeout=600s"
         - "--csi-address=$(ADDRESS)"
+        - "--leader-election"
+        - "--leader-election-namespace=$(POD_NAMESPACE)"
+        - "--feature-gates=Topology=true"
         env:
         - name: ADDRESS
           value: /var/lib/csi/sockets/pluginproxy/csi.sock
+        - name: POD_NAMESPACE
+          valueFrom:
+            fieldRef:
+              apiVersion: v1
+              fieldPath: metadata.namespace
         volumeMounts:
         - name: socket-dir
           mountPath: /var/lib/csi/sockets/pluginproxy/
@@ -800,9 +988,16 @@ spec:
         - "--timeout=60s"
         - "--retry-interval-start=10s"
         - "--csi-address=$(ADDRESS)"
+        - "--leader-election"
+        - "--leader-election-namespace=$(POD_NAMESPACE)"
         env:
         - name: ADDRESS
           value: /var/lib/csi/sockets/pluginproxy/csi.sock
+        - name: POD_NAMESPACE
+          valueFrom:
+            fieldRef:
+              apiVersion: v1
+              fieldPath: metadata.namespace
         volumeMounts:
         - name: socket-dir
           mountPath: /var/lib/csi/sockets/pluginproxy/
@@ -812,9 +1007,16 @@ spec:
         - "--v={LOG_LEVEL}"
         - "--csiTimeout=300s"
         - "--csi-address=$(ADDRESS)"
+        - "--leader-election"
+        - "--leader-election-namespace=$(POD_NAMESPACE)"
         env:
         - name: ADDRESS
           value: /var/lib/csi/sockets/pluginproxy/csi.sock
+        - name: POD_NAMESPACE
+          valueFrom:
+            fieldRef:
+              apiVersion: v1
+              fieldPath: metadata.namespace
         volumeMounts:
         - name: socket-dir
           mountPath: /var/lib/csi/sockets/pluginproxy/
@@ -824,9 +1026,16 @@ spec:
         - "--v={LOG_LEVEL}"
         - "--timeout=300s"
         - "--csi-address=$(ADDRESS)"
+        - "--leader-election"
+        - "--leader-election-namespace=$(POD_NAMESPACE)"
         env:
         - name: ADDRESS
           value: /var/lib/csi/sockets/pluginproxy/csi.sock
+        - name: POD_NAMESPACE
+          valueFrom:
+            fieldRef:
+              apiVersion: v1
+              fieldPath: metadata.namespace
         volumeMounts:
         - name: socket-dir
           mountPath: /var/lib/csi/sockets/pluginproxy/
@@ -834,6 +1043,8 @@ spec:
       nodeSelector:
         kubernetes.io/os: linux
         kubernetes.io/arch: amd64
+        {NODE_SELECTOR}
+      {TOLERATIONS}
       volumes:
       - name: socket-dir
         emptyDir:
@@ -843,8 +1054,8 @@ spec:
 `
 
 func GetCSIDaemonSetYAML(daemonsetName, tridentImage, imageRegistry, kubeletDir, logFormat string,
-	imagePullSecrets []string, labels, controllingCRDetails map[string]string, debug bool,
-	version *utils.Version) string {
+	imagePullSecrets []string, labels, controllingCRDetails map[string]string, scheduling *PodScheduling,
+	debug bool, version *utils.Version) string {
 
 	var debugLine, logLevel string
 
@@ -864,6 +1075,7 @@ func GetCSIDaemonSetYAML(daemonsetName, tridentImage, imageRegistry, kubeletDir,
 	}
 
 	kubeletDir = strings.TrimRight(kubeletDir, "/")
+	daemonSetYAML = replacePodScheduling(daemonSetYAML, scheduling, "", daemonSetTolerationsYAML)
 	daemonSetYAML = strings.ReplaceAll(daemonSetYAML, "{TRIDENT_IMAGE}", tridentImage)
 	daemonSetYAML = strings.ReplaceAll(daemonSetYAML, "{DAEMONSET_NAME}", daemonsetName)
 	daemonSetYAML = strings.ReplaceAll(daemonSetYAML, "{CSI_SIDECAR_REGISTRY}", imageRegistry)
@@ -877,6 +1089,14 @@ func GetCSIDaemonSetYAML(daemonsetName, tridentImage, imageRegistry, kubeletDir,
 	return daemonSetYAML
 }
 
+// daemonSetTolerationsYAML lets the node plugin run on every node, regardless of its taints
+const daemonSetTolerationsYAML = `tolerations:
+- effect: NoExecute
+  operator: Exists
+- effect: NoSchedule
+  operator: Exists
+`
+
 const daemonSet113YAMLTemplate = `---
 apiVersion: apps/v1
 kind: DaemonSet
@@ -898,6 +1118,7 @@ spec:
       hostIPC: true
       hostPID: true
       dnsPolicy: ClusterFirstWithHostNet
+      {AFFINITY}
       containers:
       - name: trident-main
         securityContext:
@@ -906,6 +1127,7 @@ spec:
             add: ["SYS_ADMIN"]
           allowPrivilegeEscalation: true
         image: {TRIDENT_IMAGE}
+        {RESOURCES}
         command:
         - /usr/local/bin/trident_orchestrator
         args:
@@ -971,11 +1193,8 @@ spec:
       nodeSelector:
         beta.kubernetes.io/os: linux
         beta.kubernetes.io/arch: amd64
-      tolerations:
-      - effect: NoExecute
-        operator: Exists
-      - effect: NoSchedule
-        operator: Exists
+        {NODE_SELECTOR}
+      {TOLERATIONS}
       volumes:
       - name: plugin-dir
         hostPath:
@@ -1035,6 +1254,7 @@ spec:
       hostIPC: true
       hostPID: true
       dnsPolicy: ClusterFirstWithHostNet
+      {AFFINITY}
       containers:
       - name: trident-main
         securityContext:
@@ -1043,6 +1263,7 @@ spec:
             add: ["SYS_ADMIN"]
           allowPrivilegeEscalation: true
         image: {TRIDENT_IMAGE}
+        {RESOURCES}
         command:
         - /usr/local/bin/trident_orchestrator
         args:
@@ -1107,11 +1328,8 @@ spec:
       nodeSelector:
         kubernetes.io/os: linux
         kubernetes.io/arch: amd64
-      tolerations:
-      - effect: NoExecute
-        operator: Exists
-      - effect: NoSchedule
-        operator: Exists
+        {NODE_SELECTOR}
+      {TOLERATIONS}
       volumes:
       - name: plugin-dir
         hostPath:
@@ -1150,6 +1368,158 @@ spec:
           secretName: trident-csi
 `
 
+// GetCSIWindowsDaemonSetYAML returns the node plugin DaemonSet for Windows nodes.  Windows containers can't
+// be privileged, so the plugin reaches the host through the CSI Proxy named pipes instead of host mounts.
+func GetCSIWindowsDaemonSetYAML(daemonsetName, tridentImage, imageRegistry, logFormat string,
+	imagePullSecrets []string, labels, controllingCRDetails map[string]string, scheduling *PodScheduling,
+	debug bool) string {
+
+	var debugLine, logLevel string
+
+	if debug {
+		debugLine = "- -debug"
+		logLevel = "9"
+	} else {
+		debugLine = "#- -debug"
+		logLevel = "2"
+	}
+
+	daemonSetYAML := replacePodScheduling(daemonSetWindowsYAMLTemplate, scheduling, "", daemonSetTolerationsYAML)
+	daemonSetYAML = strings.ReplaceAll(daemonSetYAML, "{TRIDENT_IMAGE}", tridentImage)
+	daemonSetYAML = strings.ReplaceAll(daemonSetYAML, "{DAEMONSET_NAME}", daemonsetName)
+	daemonSetYAML = strings.ReplaceAll(daemonSetYAML, "{CSI_SIDECAR_REGISTRY}", imageRegistry)
+	daemonSetYAML = strings.ReplaceAll(daemonSetYAML, "{LABEL_APP}", labels[TridentAppLabelKey])
+	daemonSetYAML = strings.ReplaceAll(daemonSetYAML, "{DEBUG}", debugLine)
+	daemonSetYAML = strings.ReplaceAll(daemonSetYAML, "{LOG_LEVEL}", logLevel)
+	daemonSetYAML = strings.ReplaceAll(daemonSetYAML, "{LOG_FORMAT}", logFormat)
+	daemonSetYAML = replaceMultiline(daemonSetYAML, labels, controllingCRDetails, imagePullSecrets)
+
+	return daemonSetYAML
+}
+
+const daemonSetWindowsYAMLTemplate = `---
+apiVersion: apps/v1
+kind: DaemonSet
+metadata:
+  name: {DAEMONSET_NAME}
+  {LABELS}
+  {OWNER_REF}
+spec:
+  selector:
+    matchLabels:
+      app: {LABEL_APP}
+  template:
+    metadata:
+      labels:
+        app: {LABEL_APP}
+    spec:
+      serviceAccount: trident-csi
+      {AFFINITY}
+      containers:
+      - name: trident-main
+        image: {TRIDENT_IMAGE}
+        {RESOURCES}
+        command:
+        - trident_orchestrator.exe
+        args:
+        - "--no_persistence"
+        - "--rest=false"
+        - "--csi_node_name=$(KUBE_NODE_NAME)"
+        - "--csi_endpoint=$(CSI_ENDPOINT)"
+        - "--csi_role=node"
+        - "--log_format={LOG_FORMAT}"
+        {DEBUG}
+        env:
+        - name: KUBE_NODE_NAME
+          valueFrom:
+            fieldRef:
+              apiVersion: v1
+              fieldPath: spec.nodeName
+        - name: CSI_ENDPOINT
+          value: unix://C:\csi\csi.sock
+        volumeMounts:
+        - name: plugin-dir
+          mountPath: C:\csi
+        - name: kubelet-dir
+          mountPath: C:\var\lib\kubelet
+        - name: trident-tracking-dir
+          mountPath: C:\var\lib\trident\tracking
+        - name: certs
+          mountPath: C:\certs
+          readOnly: true
+        - name: csi-proxy-filesystem-pipe
+          mountPath: \\.\pipe\csi-proxy-filesystem-v1
+        - name: csi-proxy-smb-pipe
+          mountPath: \\.\pipe\csi-proxy-smb-v1
+        - name: csi-proxy-iscsi-pipe
+          mountPath: \\.\pipe\csi-proxy-iscsi-v1alpha2
+        - name: csi-proxy-disk-pipe
+          mountPath: \\.\pipe\csi-proxy-disk-v1
+        - name: csi-proxy-volume-pipe
+          mountPath: \\.\pipe\csi-proxy-volume-v1
+      - name: driver-registrar
+        image: {CSI_SIDECAR_REGISTRY}/k8scsi/csi-node-driver-registrar:v1.3.0
+        args:
+        - "--v={LOG_LEVEL}"
+        - "--csi-address=$(ADDRESS)"
+        - "--kubelet-registration-path=$(REGISTRATION_PATH)"
+        env:
+        - name: ADDRESS
+          value: unix://C:\csi\csi.sock
+        - name: REGISTRATION_PATH
+          value: C:\var\lib\kubelet\plugins\csi.trident.netapp.io\csi.sock
+        - name: KUBE_NODE_NAME
+          valueFrom:
+            fieldRef:
+              fieldPath: spec.nodeName
+        volumeMounts:
+        - name: plugin-dir
+          mountPath: C:\csi
+        - name: registration-dir
+          mountPath: C:\registration
+      {IMAGE_PULL_SECRETS}
+      nodeSelector:
+        kubernetes.io/os: windows
+        kubernetes.io/arch: amd64
+        {NODE_SELECTOR}
+      {TOLERATIONS}
+      volumes:
+      - name: plugin-dir
+        hostPath:
+          path: C:\var\lib\kubelet\plugins\csi.trident.netapp.io\
+          type: DirectoryOrCreate
+      - name: registration-dir
+        hostPath:
+          path: C:\var\lib\kubelet\plugins_registry\
+          type: Directory
+      - name: kubelet-dir
+        hostPath:
+          path: C:\var\lib\kubelet
+          type: Directory
+      - name: trident-tracking-dir
+        hostPath:
+          path: C:\var\lib\trident\tracking
+          type: DirectoryOrCreate
+      - name: certs
+        secret:
+          secretName: trident-csi
+      - name: csi-proxy-filesystem-pipe
+        hostPath:
+          path: \\.\pipe\csi-proxy-filesystem-v1
+      - name: csi-proxy-smb-pipe
+        hostPath:
+          path: \\.\pipe\csi-proxy-smb-v1
+      - name: csi-proxy-iscsi-pipe
+        hostPath:
+          path: \\.\pipe\csi-proxy-iscsi-v1alpha2
+      - name: csi-proxy-disk-pipe
+        hostPath:
+          path: \\.\pipe\csi-proxy-disk-v1
+      - name: csi-proxy-volume-pipe
+        hostPath:
+          path: \\.\pipe\csi-proxy-volume-v1
+`
+
 func GetInstallerServiceAccountYAML() string {
 
 	return installerServiceAccountYAML
@@ -1192,7 +1562,10 @@ rules:
     resources: ["clusterroles", "clusterrolebindings"]
     verbs: ["*"]
   - apiGroups: ["storage.k8s.io"]
-    resources: ["storageclasses", "volumeattachments"]
+    resources: ["storageclasses", "volumeattachments", "csistoragecapacities"]
+    verbs: ["*"]
+  - apiGroups: ["gateway.networking.k8s.io"]
+    resources: ["referencegrants"]
     verbs: ["*"]
   - apiGroups: ["metrics.k8s.io"]
     resources: ["*"]
@@ -1204,11 +1577,14 @@ rules:
     resources: ["customresourcedefinitions"]
     verbs: ["*"]
   - apiGroups: ["trident.netapp.io"]
-    resources: ["tridentversions", "tridentbackends", "tridentstorageclasses", "tridentvolumes","tridentnodes", "tridenttransactions", "tridentsnapshots"]
+    resources: ["tridentversions", "tridentbackends", "tridentstorageclasses", "tridentvolumes","tridentnodes", "tridenttransactions", "tridentsnapshots", "tridentsnapshotschedules", "tridentvolumegroups", "tridentclonegrants", "tridentquotas", "tridentbackendconfigs", "tridentbackendconfigs/status", "tridentbackends/status", "tridentvolumes/status", "tridentsnapshotschedules/status", "tridentvolumegroups/status", "tridentclonegrants/status", "tridentquotas/status"]
     verbs: ["*"]
   - apiGroups: ["policy"]
     resources: ["podsecuritypolicies"]
     verbs: ["*"]
+  - apiGroups: ["admissionregistration.k8s.io"]
+    resources: ["validatingwebhookconfigurations"]
+    verbs: ["*"]
 `
 
 const installerClusterRoleKubernetesYAMLTemplate = `---
@@ -1230,7 +1606,7 @@ rules:
     resources: ["clusterroles", "clusterrolebindings"]
     verbs: ["*"]
   - apiGroups: ["storage.k8s.io"]
-    resources: ["storageclasses", "volumeattachments", "csidrivers", "csinodes"]
+    resources: ["storageclasses", "volumeattachments", "csidrivers", "csinodes", "csistoragecapacities"]
     verbs: ["*"]
   - apiGroups: ["metrics.k8s.io"]
     resources: ["*"]
@@ -1238,6 +1614,9 @@ rules:
   - apiGroups: ["snapshot.storage.k8s.io"]
     resources: ["volumesnapshots", "volumesnapshotclasses", "volumesnapshotcontents"]
     verbs: ["*"]
+  - apiGroups: ["gateway.networking.k8s.io"]
+    resources: ["referencegrants"]
+    verbs: ["*"]
   - apiGroups: ["apiextensions.k8s.io"]
     resources: ["customresourcedefinitions"]
     verbs: ["*"]
@@ -1245,11 +1624,14 @@ rules:
     resources: ["csidrivers", "csinodeinfos"]
     verbs: ["*"]
   - apiGroups: ["trident.netapp.io"]
-    resources: ["tridentversions", "tridentbackends", "tridentstorageclasses", "tridentvolumes","tridentnodes", "tridenttransactions", "tridentsnapshots"]
+    resources: ["tridentversions", "tridentbackends", "tridentstorageclasses", "tridentvolumes","tridentnodes", "tridenttransactions", "tridentsnapshots", "tridentsnapshotschedules", "tridentvolumegroups", "tridentclonegrants", "tridentquotas", "tridentbackendconfigs", "tridentbackendconfigs/status", "tridentbackends/status", "tridentvolumes/status", "tridentsnapshotschedules/status", "tridentvolumegroups/status", "tridentclonegrants/status", "tridentquotas/status"]
     verbs: ["*"]
   - apiGroups: ["policy"]
     resources: ["podsecuritypolicies"]
     verbs: ["*"]
+  - apiGroups: ["admissionregistration.k8s.io"]
+    resources: ["validatingwebhookconfigurations"]
+    verbs: ["*"]
 `
 
 func GetInstallerClusterRoleBindingYAML(namespace string, flavor OrchestratorFlavor) string {
@@ -1623,6 +2005,9 @@ func GetCRDNames() []string {
 		"tridentnodes.trident.netapp.io",
 		"tridenttransactions.trident.netapp.io",
 		"tridentsnapshots.trident.netapp.io",
+		"tridentsnapshotschedules.trident.netapp.io",
+		"tridentvolumegroups.trident.netapp.io",
+		"tridentclonegrants.trident.netapp.io",
 	}
 }
 
@@ -1690,6 +2075,46 @@ func GetSnapshotCRDYAML(useCRDv1 bool) string {
 	}
 }
 
+func GetSnapshotScheduleCRDYAML(useCRDv1 bool) string {
+	if useCRDv1 {
+		return tridentSnapshotScheduleCRDYAML_v1
+	} else {
+		return tridentSnapshotScheduleCRDYAML_v1beta1
+	}
+}
+
+func GetVolumeGroupCRDYAML(useCRDv1 bool) string {
+	if useCRDv1 {
+		return tridentVolumeGroupCRDYAML_v1
+	} else {
+		return tridentVolumeGroupCRDYAML_v1beta1
+	}
+}
+
+func GetCloneGrantCRDYAML(useCRDv1 bool) string {
+	if useCRDv1 {
+		return tridentCloneGrantCRDYAML_v1
+	} else {
+		return tridentCloneGrantCRDYAML_v1beta1
+	}
+}
+
+func GetQuotaCRDYAML(useCRDv1 bool) string {
+	if useCRDv1 {
+		return tridentQuotaCRDYAML_v1
+	} else {
+		return tridentQuotaCRDYAML_v1beta1
+	}
+}
+
+func GetBackendConfigCRDYAML(useCRDv1 bool) string {
+	if useCRDv1 {
+		return tridentBackendConfigCRDYAML_v1
+	} else {
+		return tridentBackendConfigCRDYAML_v1beta1
+	}
+}
+
 /*
 kubectl delete crd tridentversions.trident.netapp.io --wait=false
 kubectl delete crd tridentbackends.trident.netapp.io --wait=false
@@ -1698,6 +2123,9 @@ kubectl delete crd tridentvolumes.trident.netapp.io --wait=false
 kubectl delete crd tridentnodes.trident.netapp.io --wait=false
 kubectl delete crd tridenttransactions.trident.netapp.io --wait=false
 kubectl delete crd tridentsnapshots.trident.netapp.io --wait=false
+kubectl delete crd tridentsnapshotschedules.trident.netapp.io --wait=false
+kubectl delete crd tridentvolumegroups.trident.netapp.io --wait=false
+kubectl delete crd tridentclonegrants.trident.netapp.io --wait=false
 
 kubectl patch crd tridentversions.trident.netapp.io -p '{"metadata":{"finalizers": []}}' --type=merge
 kubectl patch crd tridentbackends.trident.netapp.io -p '{"metadata":{"finalizers": []}}' --type=merge
@@ -1706,6 +2134,9 @@ kubectl patch crd tridentvolumes.trident.netapp.io -p '{"metadata":{"finalizers"
 kubectl patch crd tridentnodes.trident.netapp.io -p '{"metadata":{"finalizers": []}}' --type=merge
 kubectl patch crd tridenttransactions.trident.netapp.io -p '{"metadata":{"finalizers": []}}' --type=merge
 kubectl patch crd tridentsnapshots.trident.netapp.io -p '{"metadata":{"finalizers": []}}' --type=merge
+kubectl patch crd tridentsnapshotschedules.trident.netapp.io -p '{"metadata":{"finalizers": []}}' --type=merge
+kubectl patch crd tridentvolumegroups.trident.netapp.io -p '{"metadata":{"finalizers": []}}' --type=merge
+kubectl patch crd tridentclonegrants.trident.netapp.io -p '{"metadata":{"finalizers": []}}' --type=merge
 
 kubectl delete crd tridentversions.trident.netapp.io
 kubectl delete crd tridentbackends.trident.netapp.io
@@ -1714,6 +2145,9 @@ kubectl delete crd tridentvolumes.trident.netapp.io
 kubectl delete crd tridentnodes.trident.netapp.io
 kubectl delete crd tridenttransactions.trident.netapp.io
 kubectl delete crd tridentsnapshots.trident.netapp.io
+kubectl delete crd tridentsnapshotschedules.trident.netapp.io
+kubectl delete crd tridentvolumegroups.trident.netapp.io
+kubectl delete crd tridentclonegrants.trident.netapp.io
 */
 
 const tridentVersionCRDYAML_v1beta1 = `
@@ -1769,6 +2203,8 @@ spec:
     categories:
     - trident
     - trident-internal
+  subresources:
+    status: {}
   additionalPrinterColumns:
     - name: Backend
       type: string
@@ -1779,7 +2215,22 @@ spec:
       type: string
       description: The backend UUID
       priority: 0
-      JSONPath: .backendUUID`
+      JSONPath: .backendUUID
+    - name: State
+      type: string
+      description: The backend's state
+      priority: 0
+      JSONPath: .status.state
+    - name: Reachable
+      type: string
+      description: Whether Trident can reach the backend's storage system
+      priority: 1
+      JSONPath: .status.conditions[?(@.type=="Reachable")].status
+    - name: Last Error
+      type: string
+      description: The last error from the backend's storage system
+      priority: 1
+      JSONPath: .status.lastError`
 
 const tridentStorageClassCRDYAML_v1beta1 = `
 apiVersion: apiextensions.k8s.io/v1beta1
@@ -1828,6 +2279,8 @@ spec:
     categories:
     - trident
     - trident-internal
+  subresources:
+    status: {}
   additionalPrinterColumns:
     - name: Age
       type: date
@@ -1862,7 +2315,12 @@ spec:
       type: string
       description: The volume's pool
       priority: 1
-      JSONPath: .pool`
+      JSONPath: .pool
+    - name: Ready
+      type: string
+      description: Whether the volume is ready
+      priority: 1
+      JSONPath: .status.conditions[?(@.type=="Ready")].status`
 
 const tridentNodeCRDYAML_v1beta1 = `
 apiVersion: apiextensions.k8s.io/v1beta1
@@ -1941,25 +2399,257 @@ spec:
       priority: 1
       JSONPath: .state`
 
-const customResourceDefinitionYAML_v1beta1 = tridentVersionCRDYAML_v1beta1 + "\n---" + tridentBackendCRDYAML_v1beta1 +
-	"\n---" + tridentStorageClassCRDYAML_v1beta1 + "\n---" + tridentVolumeCRDYAML_v1beta1 + "\n---" +
-	tridentNodeCRDYAML_v1beta1 + "\n---" + tridentTransactionCRDYAML_v1beta1 + "\n---" + tridentSnapshotCRDYAML_v1beta1
-
-const tridentVersionCRDYAML_v1 = `
-apiVersion: apiextensions.k8s.io/v1
+const tridentSnapshotScheduleCRDYAML_v1beta1 = `
+apiVersion: apiextensions.k8s.io/v1beta1
 kind: CustomResourceDefinition
 metadata:
-  name: tridentversions.trident.netapp.io
+  name: tridentsnapshotschedules.trident.netapp.io
 spec:
   group: trident.netapp.io
+  version: v1
   versions:
     - name: v1
       served: true
       storage: true
-      schema:
+  scope: Namespaced
+  names:
+    plural: tridentsnapshotschedules
+    singular: tridentsnapshotschedule
+    kind: TridentSnapshotSchedule
+    shortNames:
+    - tsched
+    - tsnapschedule
+    categories:
+    - trident
+  subresources:
+    status: {}
+  additionalPrinterColumns:
+    - name: Schedule
+      type: string
+      description: The schedule's cron expression
+      JSONPath: .spec.schedule
+    - name: Retain
+      type: integer
+      description: The number of snapshots kept for each volume
+      JSONPath: .spec.retain
+    - name: Last Run
+      type: string
+      description: The time the schedule last ran
+      JSONPath: .status.lastScheduleTime
+    - name: Suspended
+      type: boolean
+      description: Whether the schedule is suspended
+      priority: 1
+      JSONPath: .spec.suspend`
+
+const customResourceDefinitionYAML_v1beta1 = tridentVersionCRDYAML_v1beta1 + "\n---" + tridentBackendCRDYAML_v1beta1 +
+	"\n---" + tridentStorageClassCRDYAML_v1beta1 + "\n---" + tridentVolumeCRDYAML_v1beta1 + "\n---" +
+	tridentNodeCRDYAML_v1beta1 + "\n---" + tridentTransactionCRDYAML_v1beta1 + "\n---" + tridentSnapshotCRDYAML_v1beta1 +
+	"\n---" + tridentSnapshotScheduleCRDYAML_v1beta1 + "\n---" + tridentVolumeGroupCRDYAML_v1beta1 + "\n---" +
+	tridentCloneGrantCRDYAML_v1beta1 + "\n---" + tridentQuotaCRDYAML_v1beta1 + "\n---" +
+	tridentBackendConfigCRDYAML_v1beta1
+
+const tridentVolumeGroupCRDYAML_v1beta1 = `
+apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+metadata:
+  name: tridentvolumegroups.trident.netapp.io
+spec:
+  group: trident.netapp.io
+  version: v1
+  versions:
+    - name: v1
+      served: true
+      storage: true
+  scope: Namespaced
+  names:
+    plural: tridentvolumegroups
+    singular: tridentvolumegroup
+    kind: TridentVolumeGroup
+    shortNames:
+    - tvg
+    - tvolumegroup
+    categories:
+    - trident
+  subresources:
+    status: {}
+  additionalPrinterColumns:
+    - name: PVCs
+      type: string
+      description: The PVCs in the volume group
+      JSONPath: .spec.pvcs
+    - name: Volume Group
+      type: string
+      description: The name of the volume group in Trident
+      JSONPath: .status.volumeGroup
+    - name: Snapshots
+      type: string
+      description: The group snapshots that can be cloned
+      priority: 1
+      JSONPath: .status.snapshots
+    - name: Message
+      type: string
+      description: Any errors from the last reconciliation
+      priority: 1
+      JSONPath: .status.message`
+
+const tridentCloneGrantCRDYAML_v1beta1 = `
+apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+metadata:
+  name: tridentclonegrants.trident.netapp.io
+spec:
+  group: trident.netapp.io
+  version: v1
+  versions:
+    - name: v1
+      served: true
+      storage: true
+  scope: Namespaced
+  names:
+    plural: tridentclonegrants
+    singular: tridentclonegrant
+    kind: TridentCloneGrant
+    shortNames:
+    - tcg
+    - tclonegrant
+    categories:
+    - trident
+  subresources:
+    status: {}
+  additionalPrinterColumns:
+    - name: PVCs
+      type: string
+      description: The PVCs that may be cloned, or all PVCs if empty
+      JSONPath: .spec.pvcs
+    - name: Target Namespaces
+      type: string
+      description: The namespaces the PVCs may be cloned into
+      JSONPath: .spec.targetNamespaces
+    - name: Message
+      type: string
+      description: Any errors from the last reconciliation
+      priority: 1
+      JSONPath: .status.message`
+
+const tridentQuotaCRDYAML_v1beta1 = `
+apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+metadata:
+  name: tridentquotas.trident.netapp.io
+spec:
+  group: trident.netapp.io
+  version: v1
+  versions:
+    - name: v1
+      served: true
+      storage: true
+  scope: Namespaced
+  names:
+    plural: tridentquotas
+    singular: tridentquota
+    kind: TridentQuota
+    shortNames:
+    - tquota
+    categories:
+    - trident
+  subresources:
+    status: {}
+  additionalPrinterColumns:
+    - name: Storage Class
+      type: string
+      description: The storage class the quota is limited to, or all classes if empty
+      JSONPath: .spec.storageClass
+    - name: Used Capacity
+      type: string
+      description: The total size of the volumes counted by the quota
+      JSONPath: .status.usedCapacity
+    - name: Max Capacity
+      type: string
+      description: The total size the volumes may have
+      JSONPath: .spec.maxCapacity
+    - name: Used Volumes
+      type: integer
+      description: The number of volumes counted by the quota
+      JSONPath: .status.usedVolumes
+    - name: Max Volumes
+      type: integer
+      description: The number of volumes there may be
+      JSONPath: .spec.maxVolumes
+    - name: Message
+      type: string
+      description: Any errors from the last reconciliation
+      priority: 1
+      JSONPath: .status.message`
+
+const tridentBackendConfigCRDYAML_v1beta1 = `
+apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+metadata:
+  name: tridentbackendconfigs.trident.netapp.io
+spec:
+  group: trident.netapp.io
+  version: v1
+  versions:
+    - name: v1
+      served: true
+      storage: true
+  scope: Namespaced
+  names:
+    plural: tridentbackendconfigs
+    singular: tridentbackendconfig
+    kind: TridentBackendConfig
+    shortNames:
+    - tbc
+    - tbconfig
+    categories:
+    - trident
+  subresources:
+    status: {}
+  additionalPrinterColumns:
+    - name: Backend Name
+      type: string
+      description: The name of the backend created for the config
+      JSONPath: .status.backendName
+    - name: Backend UUID
+      type: string
+      description: The UUID of the backend created for the config
+      JSONPath: .status.backendUUID
+    - name: Phase
+      type: string
+      description: Whether the backend has been created
+      JSONPath: .status.phase
+    - name: Validated
+      type: string
+      description: Whether the config was last applied successfully
+      JSONPath: .status.conditions[?(@.type=="Validated")].status
+    - name: Message
+      type: string
+      description: The result of the last validation
+      priority: 1
+      JSONPath: .status.conditions[?(@.type=="Validated")].message`
+
+const tridentVersionCRDYAML_v1 = `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: tridentversions.trident.netapp.io
+spec:
+  group: trident.netapp.io
+  versions:
+    - name: v1
+      served: true
+      storage: true
+      schema:
           openAPIV3Schema:
               type: object
-              x-kubernetes-preserve-unknown-fields: true
+              properties:
+                trident_version:
+                  type: string
+                trident_store_version:
+                  type: string
+                trident_api_version:
+                  type: string
       additionalPrinterColumns:
       - name: Version
         type: string
@@ -1992,7 +2682,54 @@ spec:
       schema:
           openAPIV3Schema:
               type: object
-              x-kubernetes-preserve-unknown-fields: true
+              properties:
+                config:
+                  type: object
+                  nullable: true
+                  x-kubernetes-preserve-unknown-fields: true
+                backendName:
+                  type: string
+                backendUUID:
+                  type: string
+                version:
+                  type: string
+                online:
+                  type: boolean
+                state:
+                  type: string
+                status:
+                  type: object
+                  properties:
+                    observedGeneration:
+                      type: integer
+                      format: int64
+                    state:
+                      type: string
+                    lastError:
+                      type: string
+                    lastErrorTime:
+                      type: string
+                    conditions:
+                      type: array
+                      items:
+                        type: object
+                        properties:
+                          type:
+                            type: string
+                          status:
+                            type: string
+                          observedGeneration:
+                            type: integer
+                            format: int64
+                          lastTransitionTime:
+                            type: string
+                            format: date-time
+                          reason:
+                            type: string
+                          message:
+                            type: string
+      subresources:
+        status: {}
       additionalPrinterColumns:
       - name: Backend
         type: string
@@ -2004,6 +2741,21 @@ spec:
         description: The backend UUID
         priority: 0
         jsonPath: .backendUUID
+      - name: State
+        type: string
+        description: The backend's state
+        priority: 0
+        jsonPath: .status.state
+      - name: Reachable
+        type: string
+        description: Whether Trident can reach the backend's storage system
+        priority: 1
+        jsonPath: .status.conditions[?(@.type=="Reachable")].status
+      - name: Last Error
+        type: string
+        description: The last error from the backend's storage system
+        priority: 1
+        jsonPath: .status.lastError
   scope: Namespaced
   names:
     plural: tridentbackends
@@ -2030,7 +2782,11 @@ spec:
       schema:
           openAPIV3Schema:
               type: object
-              x-kubernetes-preserve-unknown-fields: true
+              properties:
+                spec:
+                  type: object
+                  nullable: true
+                  x-kubernetes-preserve-unknown-fields: true
   scope: Namespaced
   names:
     plural: tridentstorageclasses
@@ -2057,7 +2813,48 @@ spec:
       schema:
           openAPIV3Schema:
               type: object
-              x-kubernetes-preserve-unknown-fields: true
+              properties:
+                config:
+                  type: object
+                  nullable: true
+                  x-kubernetes-preserve-unknown-fields: true
+                backendUUID:
+                  type: string
+                pool:
+                  type: string
+                orphaned:
+                  type: boolean
+                state:
+                  type: string
+                status:
+                  type: object
+                  properties:
+                    observedGeneration:
+                      type: integer
+                      format: int64
+                    state:
+                      type: string
+                    conditions:
+                      type: array
+                      items:
+                        type: object
+                        properties:
+                          type:
+                            type: string
+                          status:
+                            type: string
+                          observedGeneration:
+                            type: integer
+                            format: int64
+                          lastTransitionTime:
+                            type: string
+                            format: date-time
+                          reason:
+                            type: string
+                          message:
+                            type: string
+      subresources:
+        status: {}
       additionalPrinterColumns:
       - name: Age
         type: date
@@ -2093,6 +2890,11 @@ spec:
         description: The volume's pool
         priority: 1
         jsonPath: .pool
+      - name: Ready
+        type: string
+        description: Whether the volume is ready
+        priority: 1
+        jsonPath: .status.conditions[?(@.type=="Ready")].status
   scope: Namespaced
   names:
     plural: tridentvolumes
@@ -2119,7 +2921,32 @@ spec:
       schema:
           openAPIV3Schema:
               type: object
-              x-kubernetes-preserve-unknown-fields: true
+              properties:
+                name:
+                  type: string
+                iqn:
+                  type: string
+                ips:
+                  type: array
+                  items:
+                    type: string
+                lastSeen:
+                  type: string
+                multipath:
+                  type: object
+                  properties:
+                    daemonRunning:
+                      type: boolean
+                    findMultipaths:
+                      type: string
+                    compatible:
+                      type: boolean
+                    remediated:
+                      type: boolean
+                    problems:
+                      type: array
+                      items:
+                        type: string
   scope: Namespaced
   names:
     plural: tridentnodes
@@ -2145,7 +2972,11 @@ spec:
       schema:
           openAPIV3Schema:
               type: object
-              x-kubernetes-preserve-unknown-fields: true
+              properties:
+                transaction:
+                  type: object
+                  nullable: true
+                  x-kubernetes-preserve-unknown-fields: true
   scope: Namespaced
   names:
     plural: tridenttransactions
@@ -2171,7 +3002,18 @@ spec:
       schema:
           openAPIV3Schema:
               type: object
-              x-kubernetes-preserve-unknown-fields: true
+              properties:
+                spec:
+                  type: object
+                  nullable: true
+                  x-kubernetes-preserve-unknown-fields: true
+                dateCreated:
+                  type: string
+                size:
+                  type: integer
+                  format: int64
+                state:
+                  type: string
       additionalPrinterColumns:
       - name: State
         type: string
@@ -2191,9 +3033,249 @@ spec:
     - trident
     - trident-internal`
 
+const tridentSnapshotScheduleCRDYAML_v1 = `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: tridentsnapshotschedules.trident.netapp.io
+spec:
+  group: trident.netapp.io
+  versions:
+    - name: v1
+      served: true
+      storage: true
+      schema:
+          openAPIV3Schema:
+              type: object
+              x-kubernetes-preserve-unknown-fields: true
+      subresources:
+        status: {}
+      additionalPrinterColumns:
+      - name: Schedule
+        type: string
+        description: The schedule's cron expression
+        jsonPath: .spec.schedule
+      - name: Retain
+        type: integer
+        description: The number of snapshots kept for each volume
+        jsonPath: .spec.retain
+      - name: Last Run
+        type: string
+        description: The time the schedule last ran
+        jsonPath: .status.lastScheduleTime
+      - name: Suspended
+        type: boolean
+        description: Whether the schedule is suspended
+        priority: 1
+        jsonPath: .spec.suspend
+  scope: Namespaced
+  names:
+    plural: tridentsnapshotschedules
+    singular: tridentsnapshotschedule
+    kind: TridentSnapshotSchedule
+    shortNames:
+    - tsched
+    - tsnapschedule
+    categories:
+    - trident`
+
 const customResourceDefinitionYAML_v1 = tridentVersionCRDYAML_v1 + "\n---" + tridentBackendCRDYAML_v1 +
 	"\n---" + tridentStorageClassCRDYAML_v1 + "\n---" + tridentVolumeCRDYAML_v1 + "\n---" +
-	tridentNodeCRDYAML_v1 + "\n---" + tridentTransactionCRDYAML_v1 + "\n---" + tridentSnapshotCRDYAML_v1 + "\n"
+	tridentNodeCRDYAML_v1 + "\n---" + tridentTransactionCRDYAML_v1 + "\n---" + tridentSnapshotCRDYAML_v1 +
+	"\n---" + tridentSnapshotScheduleCRDYAML_v1 + "\n---" + tridentVolumeGroupCRDYAML_v1 + "\n---" +
+	tridentCloneGrantCRDYAML_v1 + "\n---" + tridentQuotaCRDYAML_v1 + "\n---" + tridentBackendConfigCRDYAML_v1 + "\n"
+
+const tridentVolumeGroupCRDYAML_v1 = `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: tridentvolumegroups.trident.netapp.io
+spec:
+  group: trident.netapp.io
+  versions:
+    - name: v1
+      served: true
+      storage: true
+      schema:
+          openAPIV3Schema:
+              type: object
+              x-kubernetes-preserve-unknown-fields: true
+      subresources:
+        status: {}
+      additionalPrinterColumns:
+      - name: PVCs
+        type: string
+        description: The PVCs in the volume group
+        jsonPath: .spec.pvcs
+      - name: Volume Group
+        type: string
+        description: The name of the volume group in Trident
+        jsonPath: .status.volumeGroup
+      - name: Snapshots
+        type: string
+        description: The group snapshots that can be cloned
+        priority: 1
+        jsonPath: .status.snapshots
+      - name: Message
+        type: string
+        description: Any errors from the last reconciliation
+        priority: 1
+        jsonPath: .status.message
+  scope: Namespaced
+  names:
+    plural: tridentvolumegroups
+    singular: tridentvolumegroup
+    kind: TridentVolumeGroup
+    shortNames:
+    - tvg
+    - tvolumegroup
+    categories:
+    - trident`
+
+const tridentCloneGrantCRDYAML_v1 = `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: tridentclonegrants.trident.netapp.io
+spec:
+  group: trident.netapp.io
+  versions:
+    - name: v1
+      served: true
+      storage: true
+      schema:
+          openAPIV3Schema:
+              type: object
+              x-kubernetes-preserve-unknown-fields: true
+      subresources:
+        status: {}
+      additionalPrinterColumns:
+      - name: PVCs
+        type: string
+        description: The PVCs that may be cloned, or all PVCs if empty
+        jsonPath: .spec.pvcs
+      - name: Target Namespaces
+        type: string
+        description: The namespaces the PVCs may be cloned into
+        jsonPath: .spec.targetNamespaces
+      - name: Message
+        type: string
+        description: Any errors from the last reconciliation
+        priority: 1
+        jsonPath: .status.message
+  scope: Namespaced
+  names:
+    plural: tridentclonegrants
+    singular: tridentclonegrant
+    kind: TridentCloneGrant
+    shortNames:
+    - tcg
+    - tclonegrant
+    categories:
+    - trident`
+
+const tridentQuotaCRDYAML_v1 = `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: tridentquotas.trident.netapp.io
+spec:
+  group: trident.netapp.io
+  versions:
+    - name: v1
+      served: true
+      storage: true
+      schema:
+          openAPIV3Schema:
+              type: object
+              x-kubernetes-preserve-unknown-fields: true
+      subresources:
+        status: {}
+      additionalPrinterColumns:
+      - name: Storage Class
+        type: string
+        description: The storage class the quota is limited to, or all classes if empty
+        jsonPath: .spec.storageClass
+      - name: Used Capacity
+        type: string
+        description: The total size of the volumes counted by the quota
+        jsonPath: .status.usedCapacity
+      - name: Max Capacity
+        type: string
+        description: The total size the volumes may have
+        jsonPath: .spec.maxCapacity
+      - name: Used Volumes
+        type: integer
+        description: The number of volumes counted by the quota
+        jsonPath: .status.usedVolumes
+      - name: Max Volumes
+        type: integer
+        description: The number of volumes there may be
+        jsonPath: .spec.maxVolumes
+      - name: Message
+        type: string
+        description: Any errors from the last reconciliation
+        priority: 1
+        jsonPath: .status.message
+  scope: Namespaced
+  names:
+    plural: tridentquotas
+    singular: tridentquota
+    kind: TridentQuota
+    shortNames:
+    - tquota
+    categories:
+    - trident`
+
+const tridentBackendConfigCRDYAML_v1 = `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: tridentbackendconfigs.trident.netapp.io
+spec:
+  group: trident.netapp.io
+  versions:
+    - name: v1
+      served: true
+      storage: true
+      schema:
+          openAPIV3Schema:
+              type: object
+              x-kubernetes-preserve-unknown-fields: true
+      subresources:
+        status: {}
+      additionalPrinterColumns:
+      - name: Backend Name
+        type: string
+        description: The name of the backend created for the config
+        jsonPath: .status.backendName
+      - name: Backend UUID
+        type: string
+        description: The UUID of the backend created for the config
+        jsonPath: .status.backendUUID
+      - name: Phase
+        type: string
+        description: Whether the backend has been created
+        jsonPath: .status.phase
+      - name: Validated
+        type: string
+        description: Whether the config was last applied successfully
+        jsonPath: .status.conditions[?(@.type=="Validated")].status
+      - name: Message
+        type: string
+        description: The result of the last validation
+        priority: 1
+        jsonPath: .status.conditions[?(@.type=="Validated")].message
+  scope: Namespaced
+  names:
+    plural: tridentbackendconfigs
+    singular: tridentbackendconfig
+    kind: TridentBackendConfig
+    shortNames:
+    - tbc
+    - tbconfig
+    categories:
+    - trident`
 
 func GetCSIDriverCRDYAML() string {
 	return CSIDriverCRDYAML
@@ -2291,15 +3373,42 @@ spec:
   version: v1alpha1
 `
 
-func GetCSIDriverCRYAML(name string, labels, controllingCRDetails map[string]string) string {
+func GetCSIDriverCRYAML(
+	name, fsGroupPolicy string, storageCapacity, seLinuxMount bool, labels, controllingCRDetails map[string]string,
+) string {
+
+	// Without a policy, Kubernetes applies its default of ReadWriteOnceWithFSType
+	fsGroupPolicyLine := "#fsGroupPolicy: " + FSGroupPolicyReadWriteOnceWithFSType
+	if fsGroupPolicy != "" {
+		fsGroupPolicyLine = "fsGroupPolicy: " + fsGroupPolicy
+	}
+
+	// The scheduler only consults Trident's CSIStorageCapacity objects if the driver asks it to
+	storageCapacityLine := "#storageCapacity: false"
+	if storageCapacity {
+		storageCapacityLine = "storageCapacity: true"
+	}
+
+	// Kubelet passes a pod's SELinux context as a mount option, instead of relabeling every file, only if
+	// the driver asks it to.  The field is only served by the v1 API.
+	apiVersion := "storage.k8s.io/v1beta1"
+	seLinuxMountLine := "#seLinuxMount: false"
+	if seLinuxMount {
+		apiVersion = "storage.k8s.io/v1"
+		seLinuxMountLine = "seLinuxMount: true"
+	}
 
-	CSIDriverCR := strings.ReplaceAll(CSIDriverCRYAML, "{NAME}", name)
+	CSIDriverCR := strings.ReplaceAll(CSIDriverCRYAML, "{API_VERSION}", apiVersion)
+	CSIDriverCR = strings.ReplaceAll(CSIDriverCR, "{NAME}", name)
+	CSIDriverCR = strings.ReplaceAll(CSIDriverCR, "{FS_GROUP_POLICY}", fsGroupPolicyLine)
+	CSIDriverCR = strings.ReplaceAll(CSIDriverCR, "{STORAGE_CAPACITY}", storageCapacityLine)
+	CSIDriverCR = strings.ReplaceAll(CSIDriverCR, "{SELINUX_MOUNT}", seLinuxMountLine)
 	CSIDriverCR = replaceMultiline(CSIDriverCR, labels, controllingCRDetails, nil)
 	return CSIDriverCR
 }
 
 const CSIDriverCRYAML = `
-apiVersion: storage.k8s.io/v1beta1
+apiVersion: {API_VERSION}
 kind: CSIDriver
 metadata:
   name: {NAME}
@@ -2307,8 +3416,83 @@ metadata:
   {OWNER_REF}
 spec:
   attachRequired: true
+  podInfoOnMount: true
+  volumeLifecycleModes:
+  - Persistent
+  - Ephemeral
+  {FS_GROUP_POLICY}
+  {STORAGE_CAPACITY}
+  {SELINUX_MOUNT}
+`
+
+func GetValidatingWebhookConfigurationYAML(
+	name, serviceName, namespace, caBundle string, version *utils.Version, labels,
+	controllingCRDetails map[string]string,
+) string {
+
+	// admissionregistration.k8s.io/v1 is only served by Kubernetes 1.16+
+	apiVersion := "admissionregistration.k8s.io/v1beta1"
+	if version != nil && version.AtLeast(utils.MustParseSemantic("v1.16.0")) {
+		apiVersion = "admissionregistration.k8s.io/v1"
+	}
+
+	webhookYAML := strings.ReplaceAll(validatingWebhookConfigurationYAMLTemplate, "{API_VERSION}", apiVersion)
+	webhookYAML = strings.ReplaceAll(webhookYAML, "{NAME}", name)
+	webhookYAML = strings.ReplaceAll(webhookYAML, "{SERVICE_NAME}", serviceName)
+	webhookYAML = strings.ReplaceAll(webhookYAML, "{NAMESPACE}", namespace)
+	webhookYAML = strings.ReplaceAll(webhookYAML, "{CA_BUNDLE}", caBundle)
+	webhookYAML = replaceMultiline(webhookYAML, labels, controllingCRDetails, nil)
+	return webhookYAML
+}
+
+// The webhook fails open, so storage classes may still be created while Trident is unavailable.
+const validatingWebhookConfigurationYAMLTemplate = `---
+apiVersion: {API_VERSION}
+kind: ValidatingWebhookConfiguration
+metadata:
+  name: {NAME}
+  {LABELS}
+  {OWNER_REF}
+webhooks:
+- name: storageclasses.trident.netapp.io
+  clientConfig:
+    service:
+      name: {SERVICE_NAME}
+      namespace: {NAMESPACE}
+      path: /validate/storageclasses
+    caBundle: {CA_BUNDLE}
+  rules:
+  - apiGroups: ["storage.k8s.io"]
+    apiVersions: ["v1", "v1beta1"]
+    operations: ["CREATE"]
+    resources: ["storageclasses"]
+  failurePolicy: Ignore
+  sideEffects: None
+  admissionReviewVersions: ["v1", "v1beta1"]
+  timeoutSeconds: 5
 `
 
+// GetCRDConversionPatch returns a merge patch for a CRD, in the v1beta1 CRD schema, that has the API server
+// convert the CRD's custom resources between versions by calling Trident's conversion webhook.
+func GetCRDConversionPatch(serviceName, namespace, caBundle string) string {
+
+	patch := strings.ReplaceAll(crdConversionPatchTemplate, "{SERVICE_NAME}", serviceName)
+	patch = strings.ReplaceAll(patch, "{NAMESPACE}", namespace)
+	patch = strings.ReplaceAll(patch, "{CA_BUNDLE}", caBundle)
+	return patch
+}
+
+const crdConversionPatchTemplate = `{"spec":{"conversion":{
+"strategy":"Webhook",
+"webhookClientConfig":{
+"service":{"name":"{SERVICE_NAME}","namespace":"{NAMESPACE}","path":"/convert/crds"},
+"caBundle":"{CA_BUNDLE}"},
+"conversionReviewVersions":["v1","v1beta1"]}}}`
+
+// CRDConversionRemovalPatch is a merge patch that stops the API server calling Trident's conversion webhook
+// for a CRD, so that the CRD's custom resources remain readable after Trident is uninstalled.
+const CRDConversionRemovalPatch = `{"spec":{"conversion":{"strategy":"None","webhookClientConfig":null}}}`
+
 func GetPrivilegedPodSecurityPolicyYAML(pspName string, labels, controllingCRDetails map[string]string) string {
 
 	pspYAML := strings.ReplaceAll(PrivilegedPodSecurityPolicyYAML, "{PSP_NAME}", pspName)
@@ -2422,6 +3606,74 @@ func replaceMultiline(originalYAML string, labels, ownerRef map[string]string, i
 	return originalYAML
 }
 
+// replacePodScheduling fills in the scheduling and resource tags of a Trident pod template.  The
+// default affinity and tolerations are used unless others are specified.
+func replacePodScheduling(
+	podYAML string, scheduling *PodScheduling, defaultAffinity, defaultTolerations string,
+) string {
+
+	if scheduling == nil {
+		scheduling = &PodScheduling{}
+	}
+
+	var nodeSelector string
+	if len(scheduling.NodeSelector) != 0 {
+		nodeSelector = constructYAML(scheduling.NodeSelector)
+	}
+
+	affinity := defaultAffinity
+	if scheduling.Affinity != nil {
+		affinity = constructYAML(map[string]interface{}{"affinity": scheduling.Affinity})
+	}
+
+	tolerations := defaultTolerations
+	if len(scheduling.Tolerations) != 0 {
+		tolerations = constructYAML(map[string]interface{}{"tolerations": scheduling.Tolerations})
+	}
+
+	var resources string
+	if scheduling.Resources != nil {
+		resources = constructYAML(map[string]interface{}{"resources": scheduling.Resources})
+	}
+
+	podYAML = replaceYAMLTag(podYAML, "NODE_SELECTOR", nodeSelector)
+	podYAML = replaceYAMLTag(podYAML, "AFFINITY", affinity)
+	podYAML = replaceYAMLTag(podYAML, "TOLERATIONS", tolerations)
+	podYAML = replaceYAMLTag(podYAML, "RESOURCES", resources)
+
+	return podYAML
+}
+
+// replaceYAMLTag replaces a tag on a line of its own with a YAML fragment, indenting the fragment to
+// match the tag.  An empty fragment removes the tag's line.
+func replaceYAMLTag(originalYAML, tag, fragment string) string {
+
+	tagRegex := regexp.MustCompile(`(?m)^([\t ]*){` + tag + `}\n`)
+	match := tagRegex.FindStringSubmatch(originalYAML)
+	if match == nil {
+		return originalYAML
+	}
+
+	var indentedFragment string
+	if fragment != "" {
+		for _, line := range strings.Split(strings.TrimRight(fragment, "\n"), "\n") {
+			indentedFragment += match[1] + line + "\n"
+		}
+	}
+
+	return strings.Replace(originalYAML, match[0], indentedFragment, 1)
+}
+
+// constructYAML returns the YAML representation of an object, or an empty string if it cannot be marshaled.
+func constructYAML(object interface{}) string {
+
+	objectYAML, err := yaml.Marshal(object)
+	if err != nil {
+		return ""
+	}
+	return string(objectYAML)
+}
+
 func createSpaces(spaceCount int) string {
 	return strings.Repeat(" ", spaceCount)
 }
@@ -3,9 +3,23 @@
 package k8sclient
 
 import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
 	"testing"
 
 	"github.com/ghodss/yaml"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/netapp/trident/config"
+	netappv1 "github.com/netapp/trident/persistent_store/crd/apis/netapp/v1"
+	"github.com/netapp/trident/utils"
 )
 
 const (
@@ -78,3 +92,355 @@ func TestYAMLFactory(t *testing.T) {
 		//fmt.Printf("json: %v", string(jsonData))
 	}
 }
+
+// TestGetCSIDeploymentYAML validates the leader election settings of the CSI deployment
+func TestGetCSIDeploymentYAML(t *testing.T) {
+
+	labels := map[string]string{"app": "controller.csi.trident.netapp.io"}
+
+	for _, version := range []string{"1.13.0", "1.14.0", "1.16.0", "1.17.0"} {
+		for _, replicas := range []int{0, 3} {
+			deploymentYAML := GetCSIDeploymentYAML(Name, ImageName, "", LogFormat, replicas, nil, labels, nil,
+				nil, false, false, utils.MustParseSemantic(version))
+
+			var deployment appsv1.Deployment
+			if err := yaml.Unmarshal([]byte(deploymentYAML), &deployment); err != nil {
+				t.Fatalf("expected deployment YAML for Kubernetes %s to be valid; %v", version, err)
+			}
+
+			expectedReplicas := int32(replicas)
+			if replicas < 1 {
+				expectedReplicas = 1
+			}
+			assert.Equal(t, expectedReplicas, *deployment.Spec.Replicas, version)
+
+			pod := deployment.Spec.Template.Spec
+			assert.NotNil(t, pod.Affinity.PodAntiAffinity, version)
+			container := pod.Containers[0]
+			assert.Contains(t, container.Args, "--leader_election", version)
+			assert.Contains(t, container.Args, "--webhook", version)
+
+			env := make(map[string]string)
+			for _, envVar := range container.Env {
+				if envVar.ValueFrom != nil && envVar.ValueFrom.FieldRef != nil {
+					env[envVar.Name] = envVar.ValueFrom.FieldRef.FieldPath
+				}
+			}
+			assert.Equal(t, "metadata.name", env["POD_NAME"], version)
+			assert.Equal(t, "metadata.namespace", env["POD_NAMESPACE"], version)
+
+			// The sidecars of standby replicas must not act on PVCs and attachments either
+			sidecars := 0
+			for _, sidecar := range pod.Containers[1:] {
+				if sidecar.Name == "csi-cluster-driver-registrar" {
+					continue
+				}
+				sidecars++
+				assert.True(t, utils.SliceContainsString(sidecar.Args, "--leader-election") ||
+					utils.SliceContainsString(sidecar.Args, "--enable-leader-election"),
+					"%s %s should elect a leader", version, sidecar.Name)
+				assert.Contains(t, sidecar.Args, "--leader-election-namespace=$(POD_NAMESPACE)",
+					"%s %s", version, sidecar.Name)
+
+				sidecarEnv := make(map[string]string)
+				for _, envVar := range sidecar.Env {
+					if envVar.ValueFrom != nil && envVar.ValueFrom.FieldRef != nil {
+						sidecarEnv[envVar.Name] = envVar.ValueFrom.FieldRef.FieldPath
+					}
+				}
+				assert.Equal(t, "metadata.namespace", sidecarEnv["POD_NAMESPACE"], "%s %s", version, sidecar.Name)
+			}
+			assert.True(t, sidecars >= 2, version)
+		}
+	}
+}
+
+// TestGetCSIDeploymentYAMLScheduling validates the user-specified placement and resources of the CSI deployment
+func TestGetCSIDeploymentYAMLScheduling(t *testing.T) {
+
+	labels := map[string]string{"app": "controller.csi.trident.netapp.io"}
+	scheduling := &PodScheduling{
+		NodeSelector: map[string]string{"node-role.kubernetes.io/infra": "true"},
+		Tolerations: []v1.Toleration{
+			{Key: "infra", Operator: v1.TolerationOpEqual, Value: "reserved", Effect: v1.TaintEffectNoSchedule},
+		},
+		Resources: &v1.ResourceRequirements{
+			Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("100m")},
+			Limits:   v1.ResourceList{v1.ResourceMemory: resource.MustParse("256Mi")},
+		},
+	}
+
+	for _, version := range []string{"1.13.0", "1.14.0", "1.16.0", "1.17.0"} {
+		deploymentYAML := GetCSIDeploymentYAML(Name, ImageName, "", LogFormat, 1, nil, labels, nil,
+			scheduling, false, false, utils.MustParseSemantic(version))
+
+		var deployment appsv1.Deployment
+		if err := yaml.Unmarshal([]byte(deploymentYAML), &deployment); err != nil {
+			t.Fatalf("expected deployment YAML for Kubernetes %s to be valid; %v", version, err)
+		}
+
+		pod := deployment.Spec.Template.Spec
+		assert.Len(t, pod.NodeSelector, 3, version)
+		assert.Equal(t, "true", pod.NodeSelector["node-role.kubernetes.io/infra"], version)
+		assert.Equal(t, scheduling.Tolerations, pod.Tolerations, version)
+		assert.NotNil(t, pod.Affinity.PodAntiAffinity, version)
+		assert.Equal(t, "100m", pod.Containers[0].Resources.Requests.Cpu().String(), version)
+		assert.Equal(t, "256Mi", pod.Containers[0].Resources.Limits.Memory().String(), version)
+		assert.Empty(t, pod.Containers[1].Resources, version)
+	}
+}
+
+// TestGetCSIDaemonSetYAMLScheduling validates the default and user-specified placement of the CSI daemonset
+func TestGetCSIDaemonSetYAMLScheduling(t *testing.T) {
+
+	labels := map[string]string{"app": "node.csi.trident.netapp.io"}
+	affinity := &v1.Affinity{
+		NodeAffinity: &v1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &v1.NodeSelector{
+				NodeSelectorTerms: []v1.NodeSelectorTerm{{
+					MatchExpressions: []v1.NodeSelectorRequirement{{
+						Key:      "storage",
+						Operator: v1.NodeSelectorOpIn,
+						Values:   []string{"netapp"},
+					}},
+				}},
+			},
+		},
+	}
+
+	for _, version := range []string{"1.13.0", "1.17.0"} {
+		var daemonSet appsv1.DaemonSet
+		daemonSetYAML := GetCSIDaemonSetYAML(Name, ImageName, "", "/var/lib/kubelet", LogFormat, nil, labels, nil,
+			nil, false, utils.MustParseSemantic(version))
+		if err := yaml.Unmarshal([]byte(daemonSetYAML), &daemonSet); err != nil {
+			t.Fatalf("expected daemonset YAML for Kubernetes %s to be valid; %v", version, err)
+		}
+
+		pod := daemonSet.Spec.Template.Spec
+		assert.Len(t, pod.NodeSelector, 2, version)
+		assert.Len(t, pod.Tolerations, 2, version)
+		assert.Nil(t, pod.Affinity, version)
+		assert.Empty(t, pod.Containers[0].Resources, version)
+
+		tolerations := []v1.Toleration{{Key: "gpu", Operator: v1.TolerationOpExists}}
+		daemonSetYAML = GetCSIDaemonSetYAML(Name, ImageName, "", "/var/lib/kubelet", LogFormat, nil, labels, nil,
+			&PodScheduling{Tolerations: tolerations, Affinity: affinity}, false, utils.MustParseSemantic(version))
+		daemonSet = appsv1.DaemonSet{}
+		if err := yaml.Unmarshal([]byte(daemonSetYAML), &daemonSet); err != nil {
+			t.Fatalf("expected daemonset YAML for Kubernetes %s to be valid; %v", version, err)
+		}
+
+		pod = daemonSet.Spec.Template.Spec
+		assert.Equal(t, tolerations, pod.Tolerations, version)
+		assert.Equal(t, affinity, pod.Affinity, version)
+	}
+}
+
+func TestGetCSIWindowsDaemonSetYAML(t *testing.T) {
+
+	labels := map[string]string{"app": "windows-node.csi.trident.netapp.io"}
+	nodeSelector := map[string]string{"storage": "netapp"}
+
+	var daemonSet appsv1.DaemonSet
+	daemonSetYAML := GetCSIWindowsDaemonSetYAML(Name, ImageName, "quay.io", LogFormat, []string{"secret"}, labels,
+		nil, &PodScheduling{NodeSelector: nodeSelector}, true)
+	if err := yaml.Unmarshal([]byte(daemonSetYAML), &daemonSet); err != nil {
+		t.Fatalf("expected Windows daemonset YAML to be valid; %v", err)
+	}
+
+	assert.Equal(t, labels, daemonSet.Spec.Selector.MatchLabels)
+	pod := daemonSet.Spec.Template.Spec
+	assert.Equal(t, "windows", pod.NodeSelector["kubernetes.io/os"])
+	assert.Equal(t, "netapp", pod.NodeSelector["storage"])
+	assert.Len(t, pod.Tolerations, 2)
+	assert.False(t, pod.HostNetwork, "Windows pods can't use the host network")
+	assert.Equal(t, []v1.LocalObjectReference{{Name: "secret"}}, pod.ImagePullSecrets)
+
+	if assert.Len(t, pod.Containers, 2) {
+		main, registrar := pod.Containers[0], pod.Containers[1]
+		assert.Nil(t, main.SecurityContext, "Windows containers can't be privileged")
+		assert.Equal(t, ImageName, main.Image)
+		assert.Contains(t, main.Args, "--csi_role=node")
+		assert.Contains(t, main.Args, "-debug")
+		assert.Equal(t, "quay.io/k8scsi/csi-node-driver-registrar:v1.3.0", registrar.Image)
+		assert.Contains(t, registrar.Args, "--v=9")
+
+		env := make(map[string]string)
+		for _, variable := range append(main.Env, registrar.Env...) {
+			env[variable.Name] = variable.Value
+		}
+		assert.Equal(t, `unix://C:\csi\csi.sock`, env["CSI_ENDPOINT"])
+		assert.Equal(t, `unix://C:\csi\csi.sock`, env["ADDRESS"])
+		assert.Equal(t, `C:\var\lib\kubelet\plugins\csi.trident.netapp.io\csi.sock`, env["REGISTRATION_PATH"])
+
+		// The plugin reaches the host through each of the CSI Proxy API groups' pipes
+		mounts := make(map[string]string)
+		for _, mount := range main.VolumeMounts {
+			mounts[mount.Name] = mount.MountPath
+		}
+		for _, api := range []string{"filesystem-v1", "smb-v1", "iscsi-v1alpha2", "disk-v1", "volume-v1"} {
+			name := "csi-proxy-" + strings.Split(api, "-")[0] + "-pipe"
+			assert.Equal(t, `\\.\pipe\csi-proxy-`+api, mounts[name], api)
+		}
+	}
+
+	hostPaths := make(map[string]string)
+	for _, volume := range pod.Volumes {
+		if volume.HostPath != nil {
+			hostPaths[volume.Name] = volume.HostPath.Path
+		}
+	}
+	assert.Equal(t, `\\.\pipe\csi-proxy-iscsi-v1alpha2`, hostPaths["csi-proxy-iscsi-pipe"])
+	assert.Equal(t, `C:\var\lib\trident\tracking`, hostPaths["trident-tracking-dir"])
+}
+
+// TestGetCSIServiceYAML validates that the CSI service routes only to the elected leader
+func TestGetCSIServiceYAML(t *testing.T) {
+
+	var service v1.Service
+	if err := yaml.Unmarshal([]byte(GetCSIServiceYAML(Name, map[string]string{"app": "trident"}, nil)),
+		&service); err != nil {
+		t.Fatalf("expected service YAML to be valid; %v", err)
+	}
+	assert.Equal(t, "trident", service.Spec.Selector["app"])
+	assert.Equal(t, config.LeaderLabelValue, service.Spec.Selector[config.LeaderLabelKey])
+
+	ports := make(map[string]int32)
+	for _, port := range service.Spec.Ports {
+		ports[port.Name] = port.TargetPort.IntVal
+	}
+	assert.Equal(t, int32(8444), ports["webhook"])
+}
+
+// TestGetCSIDriverCRYAML validates that the CSI driver sets the requested fsGroupPolicy, storageCapacity and
+// seLinuxMount, and supports ephemeral volumes
+func TestGetCSIDriverCRYAML(t *testing.T) {
+
+	for _, fsGroupPolicy := range []string{"", FSGroupPolicyNone, FSGroupPolicyFile} {
+		var csiDriver map[string]interface{}
+		csiDriverYAML := GetCSIDriverCRYAML(Name, fsGroupPolicy, false, false, nil, nil)
+		if err := yaml.Unmarshal([]byte(csiDriverYAML), &csiDriver); err != nil {
+			t.Fatalf("expected CSI driver YAML to be valid; %v", err)
+		}
+		spec := csiDriver["spec"].(map[string]interface{})
+		assert.Equal(t, true, spec["attachRequired"], fsGroupPolicy)
+		if fsGroupPolicy == "" {
+			assert.NotContains(t, spec, "fsGroupPolicy")
+		} else {
+			assert.Equal(t, fsGroupPolicy, spec["fsGroupPolicy"])
+		}
+		assert.NotContains(t, spec, "storageCapacity")
+		assert.NotContains(t, spec, "seLinuxMount")
+		assert.Equal(t, "storage.k8s.io/v1beta1", csiDriver["apiVersion"])
+	}
+
+	var csiDriver map[string]interface{}
+	if err := yaml.Unmarshal([]byte(GetCSIDriverCRYAML(Name, "", true, false, nil, nil)), &csiDriver); err != nil {
+		t.Fatalf("expected CSI driver YAML to be valid; %v", err)
+	}
+	assert.Equal(t, true, csiDriver["spec"].(map[string]interface{})["storageCapacity"])
+
+	if err := yaml.Unmarshal([]byte(GetCSIDriverCRYAML(Name, "", false, true, nil, nil)), &csiDriver); err != nil {
+		t.Fatalf("expected CSI driver YAML to be valid; %v", err)
+	}
+	assert.Equal(t, true, csiDriver["spec"].(map[string]interface{})["seLinuxMount"])
+	assert.Equal(t, "storage.k8s.io/v1", csiDriver["apiVersion"])
+
+	// Pods may use CSI inline ephemeral volumes, which need the pod's identity
+	assert.Equal(t, true, csiDriver["spec"].(map[string]interface{})["podInfoOnMount"])
+	assert.Equal(t, []interface{}{"Persistent", "Ephemeral"},
+		csiDriver["spec"].(map[string]interface{})["volumeLifecycleModes"])
+
+	assert.True(t, IsValidFSGroupPolicy(""))
+	assert.True(t, IsValidFSGroupPolicy(FSGroupPolicyReadWriteOnceWithFSType))
+	assert.False(t, IsValidFSGroupPolicy("Always"))
+}
+
+// TestGetValidatingWebhookConfigurationYAML validates that the webhook reaches the CSI service and fails open
+func TestGetValidatingWebhookConfigurationYAML(t *testing.T) {
+
+	for version, apiVersion := range map[string]string{
+		"v1.14.0": "admissionregistration.k8s.io/v1beta1",
+		"v1.18.0": "admissionregistration.k8s.io/v1",
+	} {
+		var webhookConfig map[string]interface{}
+		webhookYAML := GetValidatingWebhookConfigurationYAML(Name, "trident-csi", Namespace, "Y2E=",
+			utils.MustParseSemantic(version), nil, nil)
+		if err := yaml.Unmarshal([]byte(webhookYAML), &webhookConfig); err != nil {
+			t.Fatalf("expected webhook configuration YAML to be valid; %v", err)
+		}
+		assert.Equal(t, apiVersion, webhookConfig["apiVersion"], version)
+
+		webhook := webhookConfig["webhooks"].([]interface{})[0].(map[string]interface{})
+		clientConfig := webhook["clientConfig"].(map[string]interface{})
+		assert.Equal(t, "Y2E=", clientConfig["caBundle"])
+		assert.Equal(t, map[string]interface{}{
+			"name": "trident-csi", "namespace": Namespace, "path": "/validate/storageclasses",
+		}, clientConfig["service"])
+		assert.Equal(t, "Ignore", webhook["failurePolicy"])
+	}
+}
+
+// TestStructuralCRDSchemas validates that each structural CRD schema names every field of its custom resource,
+// as the API server prunes fields that aren't in the schema.
+func TestStructuralCRDSchemas(t *testing.T) {
+
+	for crdYAML, resource := range map[string]interface{}{
+		tridentVersionCRDYAML_v1:      netappv1.TridentVersion{},
+		tridentBackendCRDYAML_v1:      netappv1.TridentBackend{},
+		tridentStorageClassCRDYAML_v1: netappv1.TridentStorageClass{},
+		tridentVolumeCRDYAML_v1:       netappv1.TridentVolume{},
+		tridentNodeCRDYAML_v1:         netappv1.TridentNode{},
+		tridentTransactionCRDYAML_v1:  netappv1.TridentTransaction{},
+		tridentSnapshotCRDYAML_v1:     netappv1.TridentSnapshot{},
+	} {
+		var crd apiextensionsv1.CustomResourceDefinition
+		if err := yaml.Unmarshal([]byte(crdYAML), &crd); err != nil {
+			t.Fatalf("expected CRD YAML to be valid; %v", err)
+		}
+		schema := crd.Spec.Versions[0].Schema.OpenAPIV3Schema
+		assert.Nil(t, schema.XPreserveUnknownFields, crd.Name)
+
+		schemaFields := make([]string, 0)
+		for field := range schema.Properties {
+			schemaFields = append(schemaFields, field)
+		}
+
+		resourceFields := make([]string, 0)
+		resourceType := reflect.TypeOf(resource)
+		for i := 0; i < resourceType.NumField(); i++ {
+			field := strings.Split(resourceType.Field(i).Tag.Get("json"), ",")[0]
+			if field != "" && field != "metadata" {
+				resourceFields = append(resourceFields, field)
+			}
+		}
+
+		sort.Strings(schemaFields)
+		sort.Strings(resourceFields)
+		assert.Equal(t, resourceFields, schemaFields, crd.Name)
+	}
+}
+
+// TestGetCRDConversionPatch validates that the conversion webhook is reached through the CSI service
+func TestGetCRDConversionPatch(t *testing.T) {
+
+	var patch apiextensionsv1beta1.CustomResourceDefinition
+	if err := json.Unmarshal([]byte(GetCRDConversionPatch("trident-csi", Namespace, "Y2E=")), &patch); err != nil {
+		t.Fatalf("expected conversion patch to be valid; %v", err)
+	}
+
+	conversion := patch.Spec.Conversion
+	if assert.NotNil(t, conversion) && assert.NotNil(t, conversion.WebhookClientConfig) {
+		assert.Equal(t, apiextensionsv1beta1.WebhookConverter, conversion.Strategy)
+		assert.Equal(t, []byte("ca"), conversion.WebhookClientConfig.CABundle)
+		if assert.NotNil(t, conversion.WebhookClientConfig.Service) {
+			assert.Equal(t, "trident-csi", conversion.WebhookClientConfig.Service.Name)
+			assert.Equal(t, Namespace, conversion.WebhookClientConfig.Service.Namespace)
+			assert.Equal(t, "/convert/crds", *conversion.WebhookClientConfig.Service.Path)
+		}
+	}
+
+	if err := json.Unmarshal([]byte(CRDConversionRemovalPatch), &patch); err != nil {
+		t.Fatalf("expected conversion removal patch to be valid; %v", err)
+	}
+}
@@ -64,6 +64,9 @@ const (
 	ClientKeyPath  = certsPath + ClientKeyFile
 	ClientCertPath = certsPath + ClientCertFile
 
+	// SecretEncryptionKeyDir is where the secret holding the keys that encrypt backend secrets is mounted
+	SecretEncryptionKeyDir = "/encryption-keys/"
+
 	/* Protocol constants. This value denotes a volume's backing storage protocol. For example,
 	a Trident volume with  'file' protocol is most likely NFS, while a 'block' protocol volume is probably iSCSI. */
 	File        Protocol = "file"
@@ -98,11 +101,18 @@ const (
 
 	/* REST frontend constants */
 	MaxRESTRequestSize = 10240
+	MaxStateBundleSize = 64 * 1024 * 1024
 
 	/* Kubernetes deployment constants */
 	ContainerTrident = "trident-main"
 	ContainerEtcd    = "etcd"
 
+	/* Controller leader election constants.  Only the elected controller replica serves requests, and
+	its pod is labeled so that the Trident service routes to it alone. */
+	LeaderElectionLeaseName = "trident-csi-controller"
+	LeaderLabelKey          = "trident.netapp.io/leader"
+	LeaderLabelValue        = "true"
+
 	ContextDocker     DriverContext = "docker"
 	ContextKubernetes DriverContext = "kubernetes"
 	ContextCSI        DriverContext = "csi"
@@ -171,6 +181,22 @@ var (
 	StorageClassURL = "/" + OrchestratorName + "/v" + OrchestratorAPIVersion + "/storageclass"
 	NodeURL         = "/" + OrchestratorName + "/v" + OrchestratorAPIVersion + "/node"
 	SnapshotURL     = "/" + OrchestratorName + "/v" + OrchestratorAPIVersion + "/snapshot"
+	MigrationURL    = "/" + OrchestratorName + "/v" + OrchestratorAPIVersion + "/migration"
+	UsageURL        = "/" + OrchestratorName + "/v" + OrchestratorAPIVersion + "/usage"
+	ConditionURL    = "/" + OrchestratorName + "/v" + OrchestratorAPIVersion + "/condition"
+	DriftURL        = "/" + OrchestratorName + "/v" + OrchestratorAPIVersion + "/drift"
+	GarbageURL      = "/" + OrchestratorName + "/v" + OrchestratorAPIVersion + "/garbage"
+	DeletionURL     = "/" + OrchestratorName + "/v" + OrchestratorAPIVersion + "/deletion"
+	VolumeGroupURL  = "/" + OrchestratorName + "/v" + OrchestratorAPIVersion + "/volumegroup"
+	CloneGrantURL   = "/" + OrchestratorName + "/v" + OrchestratorAPIVersion + "/clonegrant"
+	QuotaURL        = "/" + OrchestratorName + "/v" + OrchestratorAPIVersion + "/quota"
+	StateURL        = "/" + OrchestratorName + "/v" + OrchestratorAPIVersion + "/state"
+	PreflightURL    = "/" + OrchestratorName + "/v" + OrchestratorAPIVersion + "/upgrade/preflight"
+	LogLevelURL     = "/" + OrchestratorName + "/v" + OrchestratorAPIVersion + "/logging/level"
+	EphemeralURL    = "/" + OrchestratorName + "/v" + OrchestratorAPIVersion + "/ephemeral"
+	HealthURL       = "/" + OrchestratorName + "/v" + OrchestratorAPIVersion + "/health"
+	EventsURL       = "/" + OrchestratorName + "/v" + OrchestratorAPIVersion + "/events"
+	OpenAPIURL      = "/" + OrchestratorName + "/v" + OrchestratorAPIVersion + "/openapi.json"
 	StoreURL        = "/" + OrchestratorName + "/store"
 
 	UsingPassthroughStore bool
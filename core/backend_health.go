@@ -0,0 +1,227 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netapp/trident/config"
+	persistentstore "github.com/netapp/trident/persistent_store"
+	"github.com/netapp/trident/storage"
+	"github.com/netapp/trident/utils"
+)
+
+const backendHealthPeriod = 1 * time.Minute
+
+// recordBackendOperation counts the outcome of a storage operation on a specific backend, and records
+// it in the backend's health.
+func (o *TridentOrchestrator) recordBackendOperation(backend *storage.Backend, operation string, err error) {
+	success := "true"
+	if err != nil {
+		success = "false"
+	}
+	backendOperationsTotal.WithLabelValues(backend.Name, backend.GetDriverName(), operation, success).Inc()
+
+	o.recordBackendHealth(backend, err, false)
+}
+
+// recordBackendHealth records the outcome of a call to a backend's storage system.  A failed call only
+// marks the backend unreachable if it was made to check the backend's health, or if it was rejected
+// for its credentials.  The health is guarded by its own lock, so the caller may or may not hold the
+// orchestrator lock.
+func (o *TridentOrchestrator) recordBackendHealth(backend *storage.Backend, err error, healthCheck bool) {
+
+	o.healthMutex.Lock()
+	defer o.healthMutex.Unlock()
+
+	health, ok := o.backendHealth[backend.BackendUUID]
+	if !ok {
+		health = &storage.BackendHealth{AuthStatus: storage.AuthStatusUnknown}
+		o.backendHealth[backend.BackendUUID] = health
+	}
+
+	now := time.Now()
+	if err == nil {
+		health.RecordSuccess(now)
+		return
+	}
+
+	wasReachable := health.Reachable
+	health.RecordFailure(now, err, healthCheck)
+	if wasReachable && !health.Reachable {
+		log.WithFields(log.Fields{
+			"backend": backend.Name,
+			"error":   err,
+		}).Warning("Backend is unreachable.")
+	}
+}
+
+// GetHealth reports whether Trident is running, and whether it can reach its persistent store and
+// the storage systems of its backends.
+func (o *TridentOrchestrator) GetHealth() (report *storage.HealthReport, err error) {
+
+	defer recordTiming("health_get", &err)()
+
+	report = &storage.HealthReport{
+		Status:   storage.HealthStatusHealthy,
+		Version:  config.OrchestratorVersion.String(),
+		Backends: make([]*storage.BackendHealth, 0),
+	}
+
+	// Trident can't report on its backends until it has bootstrapped
+	if o.bootstrapError != nil {
+		report.Error = o.bootstrapError.Error()
+		if utils.IsNotReadyError(o.bootstrapError) {
+			report.Status = storage.HealthStatusStarting
+		} else {
+			report.Status = storage.HealthStatusFailed
+		}
+		return report, nil
+	}
+
+	report.Store = &storage.StoreHealth{Type: string(o.storeClient.GetType()), Reachable: true}
+	if _, storeErr := o.storeClient.GetVersion(); storeErr != nil {
+		report.Store.Reachable = false
+		report.Store.Error = storeErr.Error()
+		report.Status = storage.HealthStatusDegraded
+	}
+
+	o.mutex.Lock()
+	backends := make([]*storage.Backend, 0, len(o.backends))
+	for _, backend := range o.backends {
+		backends = append(backends, backend)
+	}
+	o.mutex.Unlock()
+
+	o.healthMutex.Lock()
+	defer o.healthMutex.Unlock()
+
+	for _, backend := range backends {
+		health := &storage.BackendHealth{AuthStatus: storage.AuthStatusUnknown}
+		if recorded, ok := o.backendHealth[backend.BackendUUID]; ok {
+			healthCopy := *recorded
+			health = &healthCopy
+		} else if backend.State.IsServing() {
+			// A backend that initialized but hasn't been called since Trident started was reachable then
+			health.Reachable = true
+		}
+		health.Name = backend.Name
+		health.BackendUUID = backend.BackendUUID
+		health.State = backend.State
+
+		// A backend whose driver failed to initialize can't be reached regardless of its history
+		if backend.State.IsFailed() || backend.State.IsOffline() {
+			health.Reachable = false
+		}
+		if !health.Reachable {
+			report.Status = storage.HealthStatusDegraded
+		}
+		report.Backends = append(report.Backends, health)
+	}
+	sort.Slice(report.Backends, func(i, j int) bool { return report.Backends[i].Name < report.Backends[j].Name })
+
+	return report, nil
+}
+
+// StartBackendHealthMonitor starts the thread that periodically checks whether the backends'
+// storage systems are reachable.
+func (o *TridentOrchestrator) StartBackendHealthMonitor(period time.Duration) {
+
+	o.healthMonitorTicker = time.NewTicker(period)
+	o.healthMonitorChannel = make(chan struct{})
+
+	go func() {
+		log.Debug("Backend health monitor started.")
+
+		for {
+			select {
+			case tick := <-o.healthMonitorTicker.C:
+				log.WithField("tick", tick).Debug("Backend health monitor running.")
+				o.checkBackendHealth()
+			case <-o.healthMonitorChannel:
+				log.Debugf("Backend health monitor stopped.")
+				return
+			}
+		}
+	}()
+}
+
+// StopBackendHealthMonitor stops the thread that checks the backends' health.
+func (o *TridentOrchestrator) StopBackendHealthMonitor() {
+	if o.healthMonitorTicker != nil {
+		o.healthMonitorTicker.Stop()
+	}
+	if o.healthMonitorChannel != nil && !o.healthMonitorStopped {
+		close(o.healthMonitorChannel)
+		o.healthMonitorStopped = true
+	}
+	log.Debug("Backend health monitor stopped.")
+}
+
+// checkBackendHealth calls the storage system of each serving backend whose driver can report its
+// capacity, which any reachable storage system answers, and records the outcome in the backend's
+// health.  Other backends are judged by the outcome of their storage operations.  The storage
+// systems are queried without holding the orchestrator lock.
+func (o *TridentOrchestrator) checkBackendHealth() {
+
+	if o.bootstrapError != nil {
+		log.WithField("error", o.bootstrapError).Errorf("Backend health monitor blocked by bootstrap error.")
+		return
+	}
+
+	o.mutex.Lock()
+	backends := make([]*storage.Backend, 0, len(o.backends))
+	for _, backend := range o.backends {
+		if backend.State.IsServing() && backend.CanReportStorageCapacity() {
+			backends = append(backends, backend)
+		}
+	}
+
+	// Forget the health of deleted backends
+	o.healthMutex.Lock()
+	for backendUUID := range o.backendHealth {
+		if _, ok := o.backends[backendUUID]; !ok {
+			delete(o.backendHealth, backendUUID)
+		}
+	}
+	o.healthMutex.Unlock()
+	o.mutex.Unlock()
+
+	for _, backend := range backends {
+		_, err := backend.GetStorageCapacity()
+		if err != nil {
+			err = fmt.Errorf("health check failed; %v", err)
+		}
+		o.recordBackendHealth(backend, err, true)
+	}
+
+	o.publishBackendHealth()
+}
+
+// publishBackendHealth passes the health of each backend to a store that publishes it, such as in
+// the status of the backends' custom resources, where cluster tooling can watch it.
+func (o *TridentOrchestrator) publishBackendHealth() {
+
+	publisher, ok := o.storeClient.(persistentstore.StatusPublisher)
+	if !ok {
+		return
+	}
+
+	report, err := o.GetHealth()
+	if err != nil {
+		log.WithField("error", err).Warning("Could not determine backend health to publish.")
+		return
+	}
+	for _, health := range report.Backends {
+		if err := publisher.PublishBackendHealth(health); err != nil {
+			log.WithFields(log.Fields{
+				"backend": health.Name,
+				"error":   err,
+			}).Warning("Could not publish backend health.")
+		}
+	}
+}
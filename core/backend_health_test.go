@@ -0,0 +1,117 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netapp/trident/config"
+	persistentstore "github.com/netapp/trident/persistent_store"
+	"github.com/netapp/trident/storage"
+)
+
+func TestGetHealth(t *testing.T) {
+	const backendName = "healthBackend"
+
+	orchestrator := getOrchestrator()
+	defer cleanup(t, orchestrator)
+
+	addBackend(t, orchestrator, backendName, config.File)
+	backend, err := orchestrator.getBackendByBackendName(backendName)
+	if err != nil {
+		t.Fatal("Unable to get backend: ", err)
+	}
+
+	// A backend that hasn't been called since it initialized is assumed reachable
+	report, err := orchestrator.GetHealth()
+	if assert.NoError(t, err) {
+		assert.Equal(t, storage.HealthStatusHealthy, report.Status)
+		assert.True(t, report.Store.Reachable)
+		if assert.Len(t, report.Backends, 1) {
+			assert.Equal(t, backendName, report.Backends[0].Name)
+			assert.True(t, report.Backends[0].Reachable)
+			assert.Equal(t, storage.AuthStatusUnknown, report.Backends[0].AuthStatus)
+		}
+	}
+
+	orchestrator.checkBackendHealth()
+	report, err = orchestrator.GetHealth()
+	if assert.NoError(t, err) && assert.Len(t, report.Backends, 1) {
+		assert.Equal(t, storage.AuthStatusOK, report.Backends[0].AuthStatus)
+		assert.NotEmpty(t, report.Backends[0].LastSuccess)
+	}
+
+	// A rejected request doesn't mean the storage system is unreachable
+	orchestrator.recordBackendOperation(backend, "volume_create", fmt.Errorf("insufficient space"))
+	report, err = orchestrator.GetHealth()
+	if assert.NoError(t, err) && assert.Len(t, report.Backends, 1) {
+		assert.Equal(t, storage.HealthStatusHealthy, report.Status)
+		assert.True(t, report.Backends[0].Reachable)
+		assert.Equal(t, "insufficient space", report.Backends[0].LastError)
+	}
+
+	// Rejected credentials and failed health checks do
+	orchestrator.recordBackendOperation(backend, "volume_create", fmt.Errorf("API status: failed, "+
+		"Reason: Unauthorized"))
+	report, err = orchestrator.GetHealth()
+	if assert.NoError(t, err) && assert.Len(t, report.Backends, 1) {
+		assert.Equal(t, storage.HealthStatusDegraded, report.Status)
+		assert.False(t, report.Backends[0].Reachable)
+		assert.Equal(t, storage.AuthStatusFailed, report.Backends[0].AuthStatus)
+	}
+
+	orchestrator.recordBackendHealth(backend, nil, true)
+	orchestrator.recordBackendHealth(backend, fmt.Errorf("connection refused"), true)
+	report, err = orchestrator.GetHealth()
+	if assert.NoError(t, err) && assert.Len(t, report.Backends, 1) {
+		assert.Equal(t, storage.HealthStatusDegraded, report.Status)
+		assert.False(t, report.Backends[0].Reachable)
+		assert.Equal(t, storage.AuthStatusOK, report.Backends[0].AuthStatus)
+	}
+
+	// Trident reports that it failed, rather than its backends, if it couldn't bootstrap
+	orchestrator.bootstrapError = fmt.Errorf("bootstrap failed")
+	report, err = orchestrator.GetHealth()
+	orchestrator.bootstrapError = nil
+	if assert.NoError(t, err) {
+		assert.Equal(t, storage.HealthStatusFailed, report.Status)
+		assert.Empty(t, report.Backends)
+	}
+}
+
+// healthPublishingStore records the backend health published to it.
+type healthPublishingStore struct {
+	persistentstore.Client
+	published map[string]*storage.BackendHealth
+}
+
+func (s *healthPublishingStore) PublishBackendHealth(health *storage.BackendHealth) error {
+	s.published[health.Name] = health
+	return nil
+}
+
+func TestPublishBackendHealth(t *testing.T) {
+	const backendName = "publishHealthBackend"
+
+	orchestrator := getOrchestrator()
+	defer cleanup(t, orchestrator)
+
+	addBackend(t, orchestrator, backendName, config.File)
+
+	store := &healthPublishingStore{
+		Client:    orchestrator.storeClient,
+		published: make(map[string]*storage.BackendHealth),
+	}
+	orchestrator.storeClient = store
+	defer func() { orchestrator.storeClient = store.Client }()
+
+	// Each health check publishes the backends' health to stores that support it
+	orchestrator.checkBackendHealth()
+	if assert.Contains(t, store.published, backendName) {
+		assert.True(t, store.published[backendName].Reachable)
+		assert.Equal(t, storage.AuthStatusOK, store.published[backendName].AuthStatus)
+	}
+}
@@ -0,0 +1,39 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	log "github.com/sirupsen/logrus"
+
+	persistentstore "github.com/netapp/trident/persistent_store"
+	"github.com/netapp/trident/utils"
+)
+
+// ReencryptBackendSecrets rewrites the secrets holding the backends' credentials with the active
+// encryption key, so that a rotated key may be retired.  It returns the names of the backends whose
+// secrets were rewritten.
+func (o *TridentOrchestrator) ReencryptBackendSecrets() (backendNames []string, err error) {
+	if o.bootstrapError != nil {
+		return nil, o.bootstrapError
+	}
+
+	defer recordTiming("backend_secrets_reencrypt", &err)()
+
+	crdClient, ok := o.storeClient.(persistentstore.CRDClient)
+	if !ok {
+		return nil, utils.UnsupportedError("backend secrets are only encrypted in the CRD persistent store")
+	}
+
+	// Backend updates rewrite the secrets, so they must wait until the secrets are re-encrypted
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	backendNames, err = crdClient.ReencryptBackendSecrets()
+
+	log.WithFields(log.Fields{
+		"backends": backendNames,
+		"count":    len(backendNames),
+	}).Info("Re-encrypted backend secrets.")
+
+	return backendNames, err
+}
@@ -0,0 +1,21 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netapp/trident/utils"
+)
+
+func TestReencryptBackendSecretsUnsupportedStore(t *testing.T) {
+
+	orchestrator := getOrchestrator()
+	defer cleanup(t, orchestrator)
+
+	backendNames, err := orchestrator.ReencryptBackendSecrets()
+	assert.Nil(t, backendNames)
+	assert.True(t, utils.IsUnsupportedError(err), "only the CRD store encrypts backend secrets")
+}
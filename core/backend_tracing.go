@@ -0,0 +1,119 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netapp/trident/storage"
+)
+
+/////////////////////////////////////////////////////////////////////////////
+//
+// This file contains the runtime changes to a backend's debug trace flags.
+// Drivers only read their trace flags when they are initialized, so the
+// backend is rebuilt from its stored config with the new flags, just as if
+// its config had been updated.  The flags are persisted with the config,
+// so they survive a restart of Trident.
+//
+/////////////////////////////////////////////////////////////////////////////
+
+// debugTraceFlagNames are the trace flags understood by one or more drivers
+var debugTraceFlagNames = map[string]bool{
+	"api":             true,
+	"api_get_volumes": true,
+	"method":          true,
+	"sensitive":       true,
+	"trace":           true,
+	"hardwareInfo":    true,
+}
+
+// UpdateBackendTracing replaces the debug trace flags of a backend.
+func (o *TridentOrchestrator) UpdateBackendTracing(backendName string, debugTraceFlags map[string]bool) (
+	backendExternal *storage.BackendExternal, err error) {
+	if o.bootstrapError != nil {
+		return nil, o.bootstrapError
+	}
+
+	defer recordTiming("backend_update_tracing", &err)()
+
+	for flag := range debugTraceFlags {
+		if !debugTraceFlagNames[flag] {
+			return nil, fmt.Errorf("unknown debug trace flag '%s'; must be one of %s", flag,
+				strings.Join(traceFlagNames(), ", "))
+		}
+	}
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	defer o.updateMetrics()
+
+	backend, err := o.getBackendByBackendName(backendName)
+	if err != nil {
+		return nil, err
+	}
+
+	configJSON, err := backend.ConstructPersistent().MarshalConfig()
+	if err != nil {
+		return nil, err
+	}
+	configJSON, err = replaceDebugTraceFlags(configJSON, debugTraceFlags)
+	if err != nil {
+		return nil, fmt.Errorf("could not update the config of backend %s; %v", backendName, err)
+	}
+
+	log.WithFields(log.Fields{
+		"backend":         backendName,
+		"debugTraceFlags": debugTraceFlags,
+	}).Info("Updating backend debug trace flags.")
+
+	backendExternal, err = o.updateBackendByBackendUUID(backendName, configJSON, backend.BackendUUID)
+	if err != nil {
+		return backendExternal, err
+	}
+
+	b, err := o.getBackendByBackendUUID(backend.BackendUUID)
+	if err != nil {
+		return backendExternal, err
+	}
+	if err = o.reconcileNodeAccessOnBackend(b); err != nil {
+		return backendExternal, err
+	}
+
+	return backendExternal, nil
+}
+
+// replaceDebugTraceFlags sets the debug trace flags in a backend config, leaving all else as it was.
+func replaceDebugTraceFlags(configJSON string, debugTraceFlags map[string]bool) (string, error) {
+
+	var config map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(configJSON), &config); err != nil {
+		return "", err
+	}
+
+	flagsJSON, err := json.Marshal(debugTraceFlags)
+	if err != nil {
+		return "", err
+	}
+	config["debugTraceFlags"] = flagsJSON
+
+	bytes, err := json.Marshal(config)
+	if err != nil {
+		return "", err
+	}
+	return string(bytes), nil
+}
+
+func traceFlagNames() []string {
+	names := make([]string, 0, len(debugTraceFlagNames))
+	for name := range debugTraceFlagNames {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,67 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netapp/trident/config"
+	drivers "github.com/netapp/trident/storage_drivers"
+	"github.com/netapp/trident/utils"
+)
+
+func TestUpdateBackendTracing(t *testing.T) {
+	const backendName = "tracingBackend"
+
+	orchestrator := getOrchestrator()
+	addBackend(t, orchestrator, backendName, config.File)
+
+	original, err := orchestrator.GetBackend(backendName)
+	if err != nil {
+		t.Fatal("Unable to get backend: ", err)
+	}
+
+	backend, err := orchestrator.UpdateBackendTracing(backendName, map[string]bool{"method": true, "api": true})
+	if assert.NoError(t, err) {
+		assert.Equal(t, original.BackendUUID, backend.BackendUUID, "the backend should not be replaced")
+		assert.Equal(t, backendName, backend.Name)
+	}
+
+	persistentBackend, err := orchestrator.storeClient.GetBackend(backendName)
+	if err != nil {
+		t.Fatal("Unable to get stored backend: ", err)
+	}
+	configJSON, err := persistentBackend.MarshalConfig()
+	if err != nil {
+		t.Fatal("Unable to marshal stored backend config: ", err)
+	}
+	commonConfig := new(drivers.CommonStorageDriverConfig)
+	if err = json.Unmarshal([]byte(configJSON), commonConfig); err != nil {
+		t.Fatal("Unable to unmarshal stored backend config: ", err)
+	}
+	assert.Equal(t, map[string]bool{"method": true, "api": true}, commonConfig.DebugTraceFlags,
+		"the new trace flags should be stored")
+
+	_, err = orchestrator.UpdateBackendTracing(backendName, map[string]bool{"everything": true})
+	assert.Error(t, err, "unknown trace flags should be rejected")
+
+	_, err = orchestrator.UpdateBackendTracing("noSuchBackend", map[string]bool{"method": true})
+	assert.True(t, utils.IsNotFoundError(err), "unknown backends should not be found")
+
+	cleanup(t, orchestrator)
+}
+
+func TestReplaceDebugTraceFlags(t *testing.T) {
+
+	configJSON, err := replaceDebugTraceFlags(
+		`{"version":1,"debugTraceFlags":null,"limitVolumeSize":"10Gi"}`, map[string]bool{"method": true})
+	if assert.NoError(t, err) {
+		assert.JSONEq(t, `{"version":1,"debugTraceFlags":{"method":true},"limitVolumeSize":"10Gi"}`, configJSON)
+	}
+
+	_, err = replaceDebugTraceFlags("not json", map[string]bool{"method": true})
+	assert.Error(t, err)
+}
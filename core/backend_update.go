@@ -0,0 +1,146 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/RoaringBitmap/roaring"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netapp/trident/storage"
+	"github.com/netapp/trident/storage/factory"
+	fakestorage "github.com/netapp/trident/storage/fake"
+	"github.com/netapp/trident/storage_drivers/fake"
+	"github.com/netapp/trident/utils"
+)
+
+// PreviewBackendUpdate reports what updating a backend with a new config would change, without
+// changing anything.  The new config is validated against the storage as for an update, so the
+// preview also catches configs that would fail to initialize.  The candidate backend is initialized
+// and its volumes are looked up without the orchestrator lock, since that means calls to the storage.
+func (o *TridentOrchestrator) PreviewBackendUpdate(backendName, configJSON string) (
+	preview *storage.BackendUpdatePreview, err error,
+) {
+	if o.bootstrapError != nil {
+		return nil, o.bootstrapError
+	}
+
+	defer recordTiming("backend_update_preview", &err)()
+
+	// Note what the preview needs from the current backend
+	o.mutex.Lock()
+	originalBackend, err := o.getBackendByBackendName(backendName)
+	if err != nil {
+		o.mutex.Unlock()
+		return nil, err
+	}
+	volumes := make([]*storage.Volume, 0, len(originalBackend.Volumes))
+	for _, volume := range originalBackend.Volumes {
+		volumeCopy := *volume
+		volumes = append(volumes, &volumeCopy)
+	}
+	var fakeVolumes map[string]fakestorage.Volume
+	if originalFakeDriver, ok := originalBackend.Driver.(*fake.StorageDriver); ok {
+		fakeVolumes = make(map[string]fakestorage.Volume, len(originalFakeDriver.Volumes))
+		for name, volume := range originalFakeDriver.Volumes {
+			fakeVolumes[name] = volume
+		}
+	}
+	o.mutex.Unlock()
+
+	backend, err := factory.NewStorageBackendForConfig(configJSON)
+	if err != nil {
+		return nil, err
+	}
+	defer backend.Terminate()
+	backend.BackendUUID = originalBackend.BackendUUID
+
+	// The fake driver has no storage of its own, so it needs the volumes copied forward
+	if fakeDriver, ok := backend.Driver.(*fake.StorageDriver); ok && fakeVolumes != nil {
+		fakeDriver.CopyVolumes(fakeVolumes)
+	}
+
+	preview = &storage.BackendUpdatePreview{
+		Backend:         originalBackend.Name,
+		BackendUUID:     originalBackend.BackendUUID,
+		Allowed:         true,
+		AffectedVolumes: make([]storage.BackendUpdateVolumeImpact, 0),
+	}
+
+	if preview.Changes, err = storage.DiffBackendConfigs(originalBackend.Driver.GetExternalConfig(),
+		backend.Driver.GetExternalConfig()); err != nil {
+		return nil, fmt.Errorf("could not compare backend configs; %v", err)
+	}
+
+	updateCode := backend.GetUpdateType(originalBackend)
+	preview.UpdateTypes = storage.UpdateTypeNames(updateCode)
+
+	// Find the volumes whose records the update would change, as updateBackendByBackendUUID would
+	for _, volume := range volumes {
+		volumeName := volume.Config.Name
+		switch volumeExists := backend.Driver.Get(volume.Config.InternalName) == nil; {
+		case !volumeExists && !volume.Orphaned:
+			preview.AffectedVolumes = append(preview.AffectedVolumes,
+				storage.BackendUpdateVolumeImpact{Volume: volumeName, Impact: storage.VolumeImpactOrphaned})
+		case volumeExists && volume.Orphaned:
+			preview.AffectedVolumes = append(preview.AffectedVolumes,
+				storage.BackendUpdateVolumeImpact{Volume: volumeName, Impact: storage.VolumeImpactNoLongerOrphan})
+		case updateCode.Contains(storage.BackendRename):
+			preview.AffectedVolumes = append(preview.AffectedVolumes,
+				storage.BackendUpdateVolumeImpact{Volume: volumeName, Impact: storage.VolumeImpactBackendRename})
+		}
+	}
+	sort.Slice(preview.AffectedVolumes, func(i, j int) bool {
+		return preview.AffectedVolumes[i].Volume < preview.AffectedVolumes[j].Volume
+	})
+
+	// Check the update against the orchestrator's current state
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	if o.backends[originalBackend.BackendUUID] != originalBackend {
+		return nil, fmt.Errorf("backend %s changed while its update was previewed; please retry", backendName)
+	}
+	if err = o.checkBackendUpdate(originalBackend, backend, updateCode); err != nil {
+		preview.Allowed = false
+		preview.Error = err.Error()
+	}
+
+	log.WithFields(log.Fields{
+		"backend":         preview.Backend,
+		"allowed":         preview.Allowed,
+		"changes":         len(preview.Changes),
+		"updateTypes":     preview.UpdateTypes,
+		"affectedVolumes": len(preview.AffectedVolumes),
+	}).Debug("Previewed backend update.")
+
+	return preview, nil
+}
+
+// checkBackendUpdate returns the error with which updateBackendByBackendUUID would reject an update.
+// The caller should hold the orchestrator lock.
+func (o *TridentOrchestrator) checkBackendUpdate(
+	originalBackend, backend *storage.Backend, updateCode *roaring.Bitmap,
+) error {
+
+	if err := o.validateBackendUpdate(originalBackend, backend); err != nil {
+		return err
+	}
+
+	switch {
+	case updateCode.Contains(storage.InvalidUpdate):
+		return fmt.Errorf("invalid backend update")
+	case updateCode.Contains(storage.VolumeAccessInfoChange):
+		return fmt.Errorf("updating the data plane IP address isn't currently supported")
+	case updateCode.Contains(storage.BackendRename):
+		checkingBackend, err := o.getBackendByBackendName(backend.Name)
+		if err == nil {
+			return fmt.Errorf("backend name %v is already in use by %v", backend.Name, checkingBackend.BackendUUID)
+		} else if !utils.IsNotFoundError(err) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/storage"
+	"github.com/netapp/trident/storage/fake"
+	sa "github.com/netapp/trident/storage_attribute"
+	fakedriver "github.com/netapp/trident/storage_drivers/fake"
+	tu "github.com/netapp/trident/storage_drivers/fake/test_utils"
+	"github.com/netapp/trident/utils"
+)
+
+func TestPreviewBackendUpdate(t *testing.T) {
+	const (
+		backendName = "previewBackend"
+		scName      = "previewSC"
+	)
+
+	orchestrator := getOrchestrator()
+	addBackendStorageClass(t, orchestrator, backendName, scName, config.File)
+	if _, err := orchestrator.AddVolume(ctx(), tu.GenerateVolumeConfig("previewVolume", 1, scName, config.File)); err != nil {
+		t.Fatal("Unable to create volume: ", err)
+	}
+	addBackend(t, orchestrator, "otherBackend", config.File)
+
+	pools := map[string]*fake.StoragePool{
+		"primary": {
+			Attrs: map[string]sa.Offer{
+				sa.Media:            sa.NewStringOffer("hdd"),
+				sa.ProvisioningType: sa.NewStringOffer("thick", "thin"),
+				sa.TestingAttribute: sa.NewBoolOffer(true),
+			},
+			Bytes: 100 * 1024 * 1024 * 1024,
+		},
+	}
+
+	// Renaming the backend changes the records of all its volumes
+	renameConfigJSON, err := fakedriver.NewFakeStorageDriverConfigJSON("renamedBackend", config.File, pools, nil)
+	if err != nil {
+		t.Fatal("Unable to create mock driver config JSON: ", err)
+	}
+	preview, err := orchestrator.PreviewBackendUpdate(backendName, renameConfigJSON)
+	if assert.NoError(t, err) {
+		assert.True(t, preview.Allowed)
+		assert.Equal(t, []string{storage.UpdateTypeBackendRename}, preview.UpdateTypes)
+		assert.Contains(t, preview.Changes, storage.BackendConfigChange{
+			Field: "instanceName", Old: backendName, New: "renamedBackend",
+		})
+		assert.Equal(t, []storage.BackendUpdateVolumeImpact{
+			{Volume: "previewVolume", Impact: storage.VolumeImpactBackendRename},
+		}, preview.AffectedVolumes)
+	}
+
+	// Nothing is changed by a preview
+	_, err = orchestrator.GetBackend(backendName)
+	assert.NoError(t, err)
+	_, err = orchestrator.GetBackend("renamedBackend")
+	assert.True(t, utils.IsNotFoundError(err))
+
+	// Updates that would be rejected are reported as such
+	conflictConfigJSON, err := fakedriver.NewFakeStorageDriverConfigJSON("otherBackend", config.File, pools, nil)
+	if err != nil {
+		t.Fatal("Unable to create mock driver config JSON: ", err)
+	}
+	preview, err = orchestrator.PreviewBackendUpdate(backendName, conflictConfigJSON)
+	if assert.NoError(t, err) {
+		assert.False(t, preview.Allowed)
+		assert.Contains(t, preview.Error, "already in use")
+	}
+
+	_, err = orchestrator.PreviewBackendUpdate("missingBackend", renameConfigJSON)
+	assert.True(t, utils.IsNotFoundError(err))
+
+	cleanup(t, orchestrator)
+}
@@ -0,0 +1,171 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"fmt"
+	"sort"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netapp/trident/storage"
+	"github.com/netapp/trident/utils"
+)
+
+/////////////////////////////////////////////////////////////////////////////
+//
+// This file contains the clone grant operations.  A volume that records the
+// namespace owning it may only be cloned into another namespace if a clone
+// grant allows it, and the same goes for restoring a snapshot of the volume.
+// Grants are kept in memory only, as they are supplied by a frontend (such as
+// from TridentCloneGrant or ReferenceGrant CRs) that resynchronizes them after
+// a restart.
+//
+/////////////////////////////////////////////////////////////////////////////
+
+// AddCloneGrant adds a clone grant, or replaces the grant with the same namespace and name.
+func (o *TridentOrchestrator) AddCloneGrant(grant *storage.CloneGrant) (err error) {
+
+	if o.bootstrapError != nil {
+		return o.bootstrapError
+	}
+
+	defer recordTiming("clone_grant_add", &err)()
+
+	if err = grant.Validate(); err != nil {
+		return err
+	}
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	grantCopy := *grant
+	if _, ok := o.cloneGrants[grant.ID()]; !ok {
+		log.WithFields(log.Fields{
+			"grant":            grant.ID(),
+			"volumes":          grant.Volumes,
+			"targetNamespaces": grant.TargetNamespaces,
+		}).Info("Added clone grant.")
+	}
+	o.cloneGrants[grant.ID()] = &grantCopy
+	return nil
+}
+
+// DeleteCloneGrant removes a clone grant.  Clones already made under the grant are not affected.
+func (o *TridentOrchestrator) DeleteCloneGrant(namespace, name string) (err error) {
+
+	if o.bootstrapError != nil {
+		return o.bootstrapError
+	}
+
+	defer recordTiming("clone_grant_delete", &err)()
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	grantID := storage.MakeCloneGrantID(namespace, name)
+	if _, ok := o.cloneGrants[grantID]; !ok {
+		return utils.NotFoundError(fmt.Sprintf("clone grant %s not found", grantID))
+	}
+	delete(o.cloneGrants, grantID)
+
+	log.WithField("grant", grantID).Info("Deleted clone grant.")
+	return nil
+}
+
+// GetCloneGrant returns a clone grant.
+func (o *TridentOrchestrator) GetCloneGrant(namespace, name string) (grant *storage.CloneGrant, err error) {
+
+	if o.bootstrapError != nil {
+		return nil, o.bootstrapError
+	}
+
+	defer recordTiming("clone_grant_get", &err)()
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	grantID := storage.MakeCloneGrantID(namespace, name)
+	existingGrant, ok := o.cloneGrants[grantID]
+	if !ok {
+		return nil, utils.NotFoundError(fmt.Sprintf("clone grant %s not found", grantID))
+	}
+	grantCopy := *existingGrant
+	return &grantCopy, nil
+}
+
+// ListCloneGrants returns all clone grants.
+func (o *TridentOrchestrator) ListCloneGrants() (grants []*storage.CloneGrant, err error) {
+
+	if o.bootstrapError != nil {
+		return nil, o.bootstrapError
+	}
+
+	defer recordTiming("clone_grant_list", &err)()
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	grants = make([]*storage.CloneGrant, 0, len(o.cloneGrants))
+	for _, grant := range o.cloneGrants {
+		grantCopy := *grant
+		grants = append(grants, &grantCopy)
+	}
+	sort.Slice(grants, func(i, j int) bool { return grants[i].ID() < grants[j].ID() })
+	return grants, nil
+}
+
+// checkCloneGrant returns an error if a clone would copy a volume into a namespace other than
+// its own without a clone grant allowing it.  The source namespace supplied with the request
+// must match the namespace recorded on the source volume, if any.  Volumes without namespaces,
+// such as those created outside Kubernetes, are not restricted.  The caller should hold the
+// orchestrator lock.
+func (o *TridentOrchestrator) checkCloneGrant(volumeConfig *storage.VolumeConfig, sourceVolume *storage.Volume) error {
+
+	sourceNamespace := sourceVolume.Config.Namespace
+	if volumeConfig.CloneSourceNamespace != "" {
+		if sourceNamespace != "" && sourceNamespace != volumeConfig.CloneSourceNamespace {
+			return fmt.Errorf("source volume %s belongs to namespace %s, not %s", sourceVolume.Config.Name,
+				sourceNamespace, volumeConfig.CloneSourceNamespace)
+		}
+		sourceNamespace = volumeConfig.CloneSourceNamespace
+	}
+
+	targetNamespace := volumeConfig.Namespace
+	if sourceNamespace == "" || targetNamespace == "" || sourceNamespace == targetNamespace {
+		return nil
+	}
+
+	// Restoring a snapshot needs a grant covering the snapshot or its volume
+	snapshotID := ""
+	if volumeConfig.CloneSourceSnapshot != "" {
+		snapshotID = storage.MakeSnapshotID(sourceVolume.Config.Name, volumeConfig.CloneSourceSnapshot)
+	}
+
+	for _, grant := range o.cloneGrants {
+		var allowed bool
+		if snapshotID != "" {
+			allowed = grant.AllowsSnapshot(sourceNamespace, snapshotID, targetNamespace)
+		} else {
+			allowed = grant.Allows(sourceNamespace, sourceVolume.Config.Name, targetNamespace)
+		}
+		if allowed {
+			log.WithFields(log.Fields{
+				"sourceVolume":    sourceVolume.Config.Name,
+				"sourceSnapshot":  volumeConfig.CloneSourceSnapshot,
+				"volume":          volumeConfig.Name,
+				"sourceNamespace": sourceNamespace,
+				"namespace":       targetNamespace,
+				"grant":           grant.ID(),
+			}).Info("Cross-namespace clone allowed by clone grant.")
+			return nil
+		}
+	}
+
+	if snapshotID != "" {
+		return fmt.Errorf("restoring snapshot %s from namespace %s into namespace %s requires a clone grant "+
+			"in namespace %s", snapshotID, sourceNamespace, targetNamespace, sourceNamespace)
+	}
+	return fmt.Errorf("cloning volume %s from namespace %s into namespace %s requires a clone grant in "+
+		"namespace %s", sourceVolume.Config.Name, sourceNamespace, targetNamespace, sourceNamespace)
+}
@@ -0,0 +1,168 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/storage"
+	sa "github.com/netapp/trident/storage_attribute"
+	storageclass "github.com/netapp/trident/storage_class"
+	tu "github.com/netapp/trident/storage_drivers/fake/test_utils"
+	"github.com/netapp/trident/utils"
+)
+
+func TestCrossNamespaceClone(t *testing.T) {
+	const (
+		backendName = "grantBackend"
+		scName      = "grantSC"
+	)
+
+	orchestrator := getOrchestrator()
+	addBackend(t, orchestrator, backendName, config.File)
+	if _, err := orchestrator.AddStorageClass(&storageclass.Config{
+		Name:       scName,
+		Attributes: map[string]sa.Request{sa.TestingAttribute: sa.NewBoolRequest(true)},
+	}); err != nil {
+		t.Fatal("Unable to add storage class: ", err)
+	}
+	for _, volumeName := range []string{"golden", "other"} {
+		volumeConfig := tu.GenerateVolumeConfig(volumeName, 1, scName, config.File)
+		volumeConfig.Namespace = "datasets"
+		if _, err := orchestrator.AddVolume(ctx(), volumeConfig); err != nil {
+			t.Fatal("Unable to create volume: ", err)
+		}
+	}
+
+	cloneConfig := func(name, source, namespace string) *storage.VolumeConfig {
+		volumeConfig := tu.GenerateVolumeConfig(name, 1, scName, config.File)
+		volumeConfig.CloneSourceVolume = source
+		volumeConfig.Namespace = namespace
+		return volumeConfig
+	}
+
+	// Clones within the source's namespace don't need a grant
+	clone, err := orchestrator.CloneVolume(ctx(), cloneConfig("copy", "golden", "datasets"))
+	if assert.NoError(t, err) {
+		assert.Equal(t, "datasets", clone.Config.Namespace)
+	}
+
+	_, err = orchestrator.CloneVolume(ctx(), cloneConfig("tenant1-golden", "golden", "tenant1"))
+	assert.Error(t, err, "cross-namespace clones should require a grant")
+
+	assert.Error(t, orchestrator.AddCloneGrant(&storage.CloneGrant{Name: "publish", Namespace: "datasets"}),
+		"grants should name a target namespace")
+	assert.NoError(t, orchestrator.AddCloneGrant(&storage.CloneGrant{
+		Name:             "publish",
+		Namespace:        "datasets",
+		Volumes:          []string{"golden"},
+		TargetNamespaces: []string{"tenant1"},
+	}))
+
+	clone, err = orchestrator.CloneVolume(ctx(), cloneConfig("tenant1-golden", "golden", "tenant1"))
+	if assert.NoError(t, err) {
+		assert.Equal(t, "tenant1", clone.Config.Namespace)
+	}
+	_, err = orchestrator.CloneVolume(ctx(), cloneConfig("tenant1-other", "other", "tenant1"))
+	assert.Error(t, err, "the grant doesn't cover this volume")
+	_, err = orchestrator.CloneVolume(ctx(), cloneConfig("tenant2-golden", "golden", "tenant2"))
+	assert.Error(t, err, "the grant doesn't cover this namespace")
+
+	// The source namespace named by the caller must be the source volume's
+	mismatchConfig := cloneConfig("tenant1-golden2", "golden", "tenant1")
+	mismatchConfig.CloneSourceNamespace = "tenant1"
+	_, err = orchestrator.CloneVolume(ctx(), mismatchConfig)
+	assert.Error(t, err)
+
+	// Replacing the grant with wildcards allows all volumes into any namespace
+	assert.NoError(t, orchestrator.AddCloneGrant(&storage.CloneGrant{
+		Name:             "publish",
+		Namespace:        "datasets",
+		Volumes:          []string{storage.CloneGrantAll},
+		TargetNamespaces: []string{storage.CloneGrantAll},
+	}))
+	_, err = orchestrator.CloneVolume(ctx(), cloneConfig("tenant2-other", "other", "tenant2"))
+	assert.NoError(t, err)
+
+	grants, err := orchestrator.ListCloneGrants()
+	if assert.NoError(t, err) && assert.Len(t, grants, 1) {
+		assert.Equal(t, "datasets/publish", grants[0].ID())
+	}
+
+	// Deleting the grant doesn't affect existing clones, but prevents new ones
+	assert.NoError(t, orchestrator.DeleteCloneGrant("datasets", "publish"))
+	assert.True(t, utils.IsNotFoundError(orchestrator.DeleteCloneGrant("datasets", "publish")))
+	_, err = orchestrator.GetCloneGrant("datasets", "publish")
+	assert.True(t, utils.IsNotFoundError(err))
+	_, err = orchestrator.GetVolume("tenant2-other")
+	assert.NoError(t, err)
+	_, err = orchestrator.CloneVolume(ctx(), cloneConfig("tenant3-other", "other", "tenant3"))
+	assert.Error(t, err)
+
+	cleanup(t, orchestrator)
+}
+
+func TestCrossNamespaceSnapshotRestore(t *testing.T) {
+	const scName = "grantSC"
+
+	orchestrator := getOrchestrator()
+	addBackend(t, orchestrator, "grantBackend", config.File)
+	if _, err := orchestrator.AddStorageClass(&storageclass.Config{
+		Name:       scName,
+		Attributes: map[string]sa.Request{sa.TestingAttribute: sa.NewBoolRequest(true)},
+	}); err != nil {
+		t.Fatal("Unable to add storage class: ", err)
+	}
+	volumeConfig := tu.GenerateVolumeConfig("golden", 1, scName, config.File)
+	volumeConfig.Namespace = "datasets"
+	if _, err := orchestrator.AddVolume(ctx(), volumeConfig); err != nil {
+		t.Fatal("Unable to create volume: ", err)
+	}
+	for _, snapshotName := range []string{"snap1", "snap2"} {
+		if _, err := orchestrator.CreateSnapshot(ctx(), &storage.SnapshotConfig{
+			Version: "1", Name: snapshotName, VolumeName: "golden",
+		}); err != nil {
+			t.Fatal("Unable to create snapshot: ", err)
+		}
+	}
+
+	restoreConfig := func(name, snapshot string) *storage.VolumeConfig {
+		volumeConfig := tu.GenerateVolumeConfig(name, 1, scName, config.File)
+		volumeConfig.CloneSourceVolume = "golden"
+		volumeConfig.CloneSourceSnapshot = snapshot
+		volumeConfig.Namespace = "tenant1"
+		return volumeConfig
+	}
+
+	_, err := orchestrator.CloneVolume(ctx(), restoreConfig("restore1", "snap1"))
+	assert.Error(t, err, "cross-namespace restores should require a grant")
+
+	// A grant for a snapshot covers neither the volume nor its other snapshots
+	assert.NoError(t, orchestrator.AddCloneGrant(&storage.CloneGrant{
+		Name:             "snapshots",
+		Namespace:        "datasets",
+		Snapshots:        []string{"golden/snap1"},
+		TargetNamespaces: []string{"tenant1"},
+	}))
+	_, err = orchestrator.CloneVolume(ctx(), restoreConfig("restore1", "snap1"))
+	assert.NoError(t, err)
+	_, err = orchestrator.CloneVolume(ctx(), restoreConfig("restore2", "snap2"))
+	assert.Error(t, err)
+	_, err = orchestrator.CloneVolume(ctx(), restoreConfig("clone1", ""))
+	assert.Error(t, err)
+
+	// A grant for a volume covers all of its snapshots
+	assert.NoError(t, orchestrator.AddCloneGrant(&storage.CloneGrant{
+		Name:             "volumes",
+		Namespace:        "datasets",
+		Volumes:          []string{"golden"},
+		TargetNamespaces: []string{"tenant1"},
+	}))
+	_, err = orchestrator.CloneVolume(ctx(), restoreConfig("restore2", "snap2"))
+	assert.NoError(t, err)
+
+	cleanup(t, orchestrator)
+}
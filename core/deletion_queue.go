@@ -0,0 +1,310 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netapp/trident/storage"
+	"github.com/netapp/trident/utils"
+)
+
+const (
+	deletionRetryPeriod             = 1 * time.Minute
+	deletionRetryInitialBackoff     = 1 * time.Minute
+	deletionRetryMaxBackoff         = 1 * time.Hour
+	defaultDeletionRetryMaxAttempts = 10
+)
+
+// SetDeletionRetryMaxAttempts sets the number of times a volume deletion is attempted before
+// Trident stops retrying it automatically.
+func (o *TridentOrchestrator) SetDeletionRetryMaxAttempts(maxAttempts int) {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	o.deletionRetryMaxAttempts = maxAttempts
+}
+
+// deletionBackoff returns the delay before retrying a deletion that has failed the given number
+// of times.  The delay doubles with each attempt, up to a maximum.
+func deletionBackoff(attempts int) time.Duration {
+	backoff := deletionRetryInitialBackoff
+	for i := 1; i < attempts; i++ {
+		backoff *= 2
+		if backoff >= deletionRetryMaxBackoff {
+			return deletionRetryMaxBackoff
+		}
+	}
+	return backoff
+}
+
+// deferVolumeDeletion queues a volume whose deletion failed so it is retried later.  The volume is
+// left in the deleting state, and its delete transaction is kept and updated with the retry
+// schedule.  The caller should hold the volume's lock and the orchestrator lock.
+func (o *TridentOrchestrator) deferVolumeDeletion(volTxn *storage.VolumeTransaction, deleteErr error) error {
+
+	volume := o.volumes[volTxn.Config.Name]
+
+	deletion := &storage.VolumeDeletion{
+		VolumeName:  volume.Config.Name,
+		BackendUUID: volume.BackendUUID,
+	}
+	if backend, ok := o.backends[volume.BackendUUID]; ok {
+		deletion.Backend = backend.Name
+	}
+	volTxn.VolumeDeletion = deletion
+	o.pendingDeletions[volume.Config.Name] = volTxn
+
+	if !volume.State.IsDeleting() {
+		volume.State = storage.VolumeStateDeleting
+		if err := o.updateVolumeOnPersistentStore(volume); err != nil {
+			log.WithFields(log.Fields{
+				"volume": volume.Config.Name,
+				"error":  err,
+			}).Error("Unable to update the volume's state to deleting in the persistent store.")
+		}
+	}
+
+	o.recordDeletionFailure(volTxn, deleteErr)
+
+	return fmt.Errorf("volume %s could not be deleted and the deletion will be retried; %v",
+		volume.Config.Name, deleteErr)
+}
+
+// queueVolumeDeletion queues a volume to be deleted by the deletion retry monitor as soon as it
+// next runs.  The deletion has a delete transaction, as if DeleteVolume had deferred it, so it is
+// completed even if Trident restarts first.  The caller should hold the orchestrator lock.
+func (o *TridentOrchestrator) queueVolumeDeletion(volume *storage.Volume) error {
+
+	volTxn := &storage.VolumeTransaction{
+		Config: volume.Config,
+		Op:     storage.DeleteVolume,
+		VolumeDeletion: &storage.VolumeDeletion{
+			VolumeName:  volume.Config.Name,
+			BackendUUID: volume.BackendUUID,
+			State:       storage.DeletionStatePending,
+		},
+	}
+	if backend, ok := o.backends[volume.BackendUUID]; ok {
+		volTxn.VolumeDeletion.Backend = backend.Name
+	}
+	if err := o.AddVolumeTransaction(volTxn); err != nil {
+		return err
+	}
+	o.pendingDeletions[volume.Config.Name] = volTxn
+
+	return nil
+}
+
+// recordDeletionFailure schedules the next attempt of a queued deletion and persists it.  The
+// caller should hold the orchestrator lock.
+func (o *TridentOrchestrator) recordDeletionFailure(volTxn *storage.VolumeTransaction, deleteErr error) {
+
+	deletion := volTxn.VolumeDeletion
+	deletion.RecordFailure(deleteErr, deletionBackoff(deletion.Attempts+1), o.deletionRetryMaxAttempts)
+
+	logFields := log.Fields{
+		"volume":   deletion.VolumeName,
+		"backend":  deletion.Backend,
+		"attempts": deletion.Attempts,
+		"error":    deleteErr,
+	}
+	if deletion.State == storage.DeletionStateFailed {
+		log.WithFields(logFields).Error("Volume deletion failed too many times and will not be retried " +
+			"automatically. Delete the volume again to retry.")
+	} else {
+		logFields["nextAttempt"] = deletion.NextAttempt
+		log.WithFields(logFields).Warning("Volume deletion failed and will be retried.")
+	}
+
+	if err := o.storeClient.UpdateVolumeTransaction(volTxn); err != nil {
+		log.WithFields(log.Fields{
+			"volume": deletion.VolumeName,
+			"error":  err,
+		}).Error("Unable to persist the volume deletion retry.")
+	}
+}
+
+// retryVolumeDeletion attempts a queued deletion again.  If the deletion succeeds, or the volume
+// no longer exists, the volume is removed from the queue.  The caller should hold the volume's
+// lock and the orchestrator lock.
+func (o *TridentOrchestrator) retryVolumeDeletion(volTxn *storage.VolumeTransaction) error {
+
+	volumeName := volTxn.Config.Name
+
+	var err error
+	if _, ok := o.volumes[volumeName]; ok {
+		ctx, cancel := o.operationContext(context.Background(), OperationDeleteVolume)
+		err = o.deleteVolume(ctx, volumeName)
+		cancel()
+	}
+	if err != nil {
+		if _, ok := o.volumes[volumeName]; ok {
+			o.recordDeletionFailure(volTxn, err)
+			return err
+		}
+	}
+
+	delete(o.pendingDeletions, volumeName)
+	if errTxn := o.DeleteVolumeTransaction(volTxn); errTxn != nil {
+		log.WithFields(log.Fields{
+			"volume": volumeName,
+			"error":  errTxn,
+		}).Warning("Unable to delete volume transaction.")
+	}
+	log.WithFields(log.Fields{
+		"volume":   volumeName,
+		"attempts": volTxn.VolumeDeletion.Attempts + 1,
+	}).Info("Deferred volume deletion succeeded.")
+
+	return err
+}
+
+// restoreVolumeDeletion queues a deletion found in the persistent store during bootstrap.  The
+// caller should hold the orchestrator lock.
+func (o *TridentOrchestrator) restoreVolumeDeletion(volTxn *storage.VolumeTransaction) error {
+
+	if _, ok := o.volumes[volTxn.Config.Name]; !ok {
+		log.WithField("volume", volTxn.Config.Name).Info("Volume for the deferred deletion wasn't found.")
+		if err := o.DeleteVolumeTransaction(volTxn); err != nil {
+			return fmt.Errorf("failed to clean up volume deletion transaction: %v", err)
+		}
+		return nil
+	}
+
+	o.pendingDeletions[volTxn.Config.Name] = volTxn
+	log.WithFields(log.Fields{
+		"volume":      volTxn.VolumeDeletion.VolumeName,
+		"state":       volTxn.VolumeDeletion.State,
+		"attempts":    volTxn.VolumeDeletion.Attempts,
+		"nextAttempt": volTxn.VolumeDeletion.NextAttempt,
+	}).Info("Restored deferred volume deletion.")
+	return nil
+}
+
+// retryPendingDeletions is called periodically by the deletion retry monitor to retry the queued
+// deletions whose backoff has expired.
+func (o *TridentOrchestrator) retryPendingDeletions() {
+
+	o.mutex.Lock()
+	dueVolumes := make([]string, 0)
+	for volumeName, volTxn := range o.pendingDeletions {
+		if volTxn.VolumeDeletion.IsDue() {
+			dueVolumes = append(dueVolumes, volumeName)
+		}
+	}
+	o.mutex.Unlock()
+
+	sort.Strings(dueVolumes)
+	for _, volumeName := range dueVolumes {
+		o.retryPendingDeletion(volumeName)
+	}
+}
+
+// retryPendingDeletion retries one queued deletion, unless it was completed or retried meanwhile.
+func (o *TridentOrchestrator) retryPendingDeletion(volumeName string) {
+
+	o.volumeLocks.Lock(volumeName)
+	defer o.volumeLocks.Unlock(volumeName)
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	defer o.updateMetrics()
+
+	volTxn, ok := o.pendingDeletions[volumeName]
+	if !ok || !volTxn.VolumeDeletion.IsDue() {
+		return
+	}
+	log.WithFields(log.Fields{
+		"volume":   volumeName,
+		"attempts": volTxn.VolumeDeletion.Attempts,
+	}).Debug("Retrying volume deletion.")
+
+	_ = o.retryVolumeDeletion(volTxn)
+}
+
+// StartDeletionRetryMonitor starts the thread that retries failed volume deletions.
+func (o *TridentOrchestrator) StartDeletionRetryMonitor(period time.Duration) {
+
+	o.deletionMonitorTicker = time.NewTicker(period)
+	o.deletionMonitorChannel = make(chan struct{})
+
+	go func() {
+		log.Debug("Deletion retry monitor started.")
+
+		for {
+			select {
+			case tick := <-o.deletionMonitorTicker.C:
+				log.WithField("tick", tick).Debug("Deletion retry monitor running.")
+				if o.bootstrapError != nil {
+					log.WithField("error", o.bootstrapError).Errorf(
+						"Deletion retry monitor blocked by bootstrap error.")
+					continue
+				}
+				o.retryPendingDeletions()
+			case <-o.deletionMonitorChannel:
+				log.Debugf("Deletion retry monitor stopped.")
+				return
+			}
+		}
+	}()
+}
+
+// StopDeletionRetryMonitor stops the thread that retries failed volume deletions.
+func (o *TridentOrchestrator) StopDeletionRetryMonitor() {
+	if o.deletionMonitorTicker != nil {
+		o.deletionMonitorTicker.Stop()
+	}
+	if o.deletionMonitorChannel != nil && !o.deletionMonitorStopped {
+		close(o.deletionMonitorChannel)
+		o.deletionMonitorStopped = true
+	}
+	log.Debug("Deletion retry monitor stopped.")
+}
+
+// GetVolumeDeletion returns the queued deletion of a volume.
+func (o *TridentOrchestrator) GetVolumeDeletion(volumeName string) (deletion *storage.VolumeDeletion, err error) {
+	if o.bootstrapError != nil {
+		return nil, o.bootstrapError
+	}
+
+	defer recordTiming("volume_deletion_get", &err)()
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	volTxn, ok := o.pendingDeletions[volumeName]
+	if !ok {
+		return nil, utils.NotFoundError(fmt.Sprintf("pending deletion of volume %s not found", volumeName))
+	}
+	deletionCopy := *volTxn.VolumeDeletion
+	return &deletionCopy, nil
+}
+
+// ListVolumeDeletions returns the volume deletions that failed and are queued for retry.
+func (o *TridentOrchestrator) ListVolumeDeletions() (deletions []*storage.VolumeDeletion, err error) {
+	if o.bootstrapError != nil {
+		return nil, o.bootstrapError
+	}
+
+	defer recordTiming("volume_deletion_list", &err)()
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	deletions = make([]*storage.VolumeDeletion, 0, len(o.pendingDeletions))
+	for _, volTxn := range o.pendingDeletions {
+		deletionCopy := *volTxn.VolumeDeletion
+		deletions = append(deletions, &deletionCopy)
+	}
+	sort.Slice(deletions, func(i, j int) bool { return deletions[i].VolumeName < deletions[j].VolumeName })
+	return deletions, nil
+}
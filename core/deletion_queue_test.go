@@ -0,0 +1,130 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/storage"
+	sa "github.com/netapp/trident/storage_attribute"
+	storageclass "github.com/netapp/trident/storage_class"
+	tu "github.com/netapp/trident/storage_drivers/fake/test_utils"
+)
+
+func TestDeletionBackoff(t *testing.T) {
+	assert.Equal(t, 1*time.Minute, deletionBackoff(1))
+	assert.Equal(t, 2*time.Minute, deletionBackoff(2))
+	assert.Equal(t, 32*time.Minute, deletionBackoff(6))
+	assert.Equal(t, 1*time.Hour, deletionBackoff(7))
+	assert.Equal(t, 1*time.Hour, deletionBackoff(100))
+}
+
+func TestDeferredVolumeDeletion(t *testing.T) {
+	const (
+		backendName = "deletionBackend"
+		scName      = "deletionSC"
+		volumeName  = "retried"
+	)
+
+	orchestrator := getOrchestrator()
+	orchestrator.SetDeletionRetryMaxAttempts(4)
+	addBackend(t, orchestrator, backendName, config.File)
+	if _, err := orchestrator.AddStorageClass(&storageclass.Config{
+		Name:       scName,
+		Attributes: map[string]sa.Request{sa.TestingAttribute: sa.NewBoolRequest(true)},
+	}); err != nil {
+		t.Fatal("Unable to add storage class: ", err)
+	}
+	if _, err := orchestrator.AddVolume(ctx(), tu.GenerateVolumeConfig(volumeName, 1, scName, config.File)); err != nil {
+		t.Fatal("Unable to create volume: ", err)
+	}
+
+	// Make the backend unable to delete volumes
+	var backend *storage.Backend
+	for _, b := range orchestrator.backends {
+		backend = b
+	}
+	backend.State = storage.Offline
+
+	makeDue := func() {
+		orchestrator.pendingDeletions[volumeName].VolumeDeletion.NextAttempt =
+			time.Now().Add(-time.Second).UTC().Format(time.RFC3339)
+	}
+
+	assert.Error(t, orchestrator.DeleteVolume(ctx(), volumeName))
+	assert.True(t, orchestrator.volumes[volumeName].State.IsDeleting())
+
+	deletion, err := orchestrator.GetVolumeDeletion(volumeName)
+	if assert.NoError(t, err) {
+		assert.Equal(t, storage.DeletionStatePending, deletion.State)
+		assert.Equal(t, backendName, deletion.Backend)
+		assert.Equal(t, 1, deletion.Attempts)
+		assert.NotEmpty(t, deletion.LastError)
+		assert.False(t, deletion.IsDue(), "retry should wait for the backoff")
+	}
+
+	// The queued deletion is persisted with the volume's transaction
+	txn, err := orchestrator.storeClient.GetExistingVolumeTransaction(orchestrator.pendingDeletions[volumeName])
+	if assert.NoError(t, err) && assert.NotNil(t, txn) {
+		assert.Equal(t, storage.DeleteVolume, txn.Op)
+		assert.Equal(t, 1, txn.VolumeDeletion.Attempts)
+
+		// and restored after a restart
+		orchestrator.pendingDeletions = make(map[string]*storage.VolumeTransaction)
+		assert.NoError(t, orchestrator.handleFailedTransaction(txn))
+		assert.Contains(t, orchestrator.pendingDeletions, volumeName)
+	}
+
+	_, err = orchestrator.CloneVolume(ctx(), tu.GenerateVolumeConfig("clone", 1, scName, config.File))
+	assert.Error(t, err)
+
+	// Deleting the volume again before the backoff has expired doesn't retry it
+	assert.Error(t, orchestrator.DeleteVolume(ctx(), volumeName))
+	deletion, _ = orchestrator.GetVolumeDeletion(volumeName)
+	assert.Equal(t, 1, deletion.Attempts)
+
+	makeDue()
+	assert.Error(t, orchestrator.DeleteVolume(ctx(), volumeName))
+	deletion, _ = orchestrator.GetVolumeDeletion(volumeName)
+	assert.Equal(t, 2, deletion.Attempts)
+	assert.False(t, deletion.IsDue())
+
+	makeDue()
+	orchestrator.retryPendingDeletions()
+	deletion, _ = orchestrator.GetVolumeDeletion(volumeName)
+	assert.Equal(t, 3, deletion.Attempts)
+	assert.Equal(t, storage.DeletionStatePending, deletion.State)
+
+	// Automatic retries stop after the maximum number of attempts
+	makeDue()
+	orchestrator.retryPendingDeletions()
+	deletion, _ = orchestrator.GetVolumeDeletion(volumeName)
+	assert.Equal(t, 4, deletion.Attempts)
+	assert.Equal(t, storage.DeletionStateFailed, deletion.State)
+	assert.False(t, deletion.IsDue())
+
+	deletions, err := orchestrator.ListVolumeDeletions()
+	assert.NoError(t, err)
+	assert.Len(t, deletions, 1)
+
+	// Deleting the volume again retries at once
+	backend.State = storage.Online
+	assert.NoError(t, orchestrator.DeleteVolume(ctx(), volumeName))
+	assert.NotContains(t, orchestrator.volumes, volumeName)
+	assert.Empty(t, orchestrator.pendingDeletions)
+	_, err = orchestrator.GetVolumeDeletion(volumeName)
+	assert.Error(t, err)
+
+	txn, err = orchestrator.storeClient.GetExistingVolumeTransaction(&storage.VolumeTransaction{
+		Config: &storage.VolumeConfig{Name: volumeName},
+		Op:     storage.DeleteVolume,
+	})
+	assert.NoError(t, err)
+	assert.Nil(t, txn)
+
+	cleanup(t, orchestrator)
+}
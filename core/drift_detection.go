@@ -0,0 +1,422 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netapp/trident/storage"
+	"github.com/netapp/trident/utils"
+)
+
+const (
+	driftDetectionPeriod = 30 * time.Minute
+	// driftSizeTolerancePercent ignores size differences caused by the storage system rounding
+	// volume sizes.
+	driftSizeTolerancePercent = 1
+)
+
+// SetDriftAutoRepair determines whether drift detection repairs the discrepancies that only
+// require correcting Trident's records.
+func (o *TridentOrchestrator) SetDriftAutoRepair(autoRepair bool) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	o.driftAutoRepair = autoRepair
+}
+
+// DetectDrift compares Trident's volumes and snapshots with what the backends report.
+func (o *TridentOrchestrator) DetectDrift() (report *storage.DriftReport, err error) {
+	if o.bootstrapError != nil {
+		return nil, o.bootstrapError
+	}
+
+	defer recordTiming("drift_detect", &err)()
+
+	return o.detectDrift(), nil
+}
+
+// GetDriftReport returns the result of the most recent drift detection.
+func (o *TridentOrchestrator) GetDriftReport() (report *storage.DriftReport, err error) {
+	if o.bootstrapError != nil {
+		return nil, o.bootstrapError
+	}
+
+	defer recordTiming("drift_get", &err)()
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	if o.driftReport == nil {
+		return nil, utils.NotFoundError("drift detection has not run")
+	}
+	return o.driftReport, nil
+}
+
+// StartDriftMonitor starts the thread that periodically detects drift.
+func (o *TridentOrchestrator) StartDriftMonitor(period time.Duration) {
+
+	o.driftMonitorTicker = time.NewTicker(period)
+	o.driftMonitorChannel = make(chan struct{})
+
+	go func() {
+		log.Debug("Drift monitor started.")
+
+		for {
+			select {
+			case tick := <-o.driftMonitorTicker.C:
+				log.WithField("tick", tick).Debug("Drift monitor running.")
+				if o.bootstrapError != nil {
+					log.WithField("error", o.bootstrapError).Errorf("Drift monitor blocked by bootstrap error.")
+					continue
+				}
+				o.detectDrift()
+			case <-o.driftMonitorChannel:
+				log.Debugf("Drift monitor stopped.")
+				return
+			}
+		}
+	}()
+}
+
+// StopDriftMonitor stops the thread that detects drift.
+func (o *TridentOrchestrator) StopDriftMonitor() {
+	if o.driftMonitorTicker != nil {
+		o.driftMonitorTicker.Stop()
+	}
+	if o.driftMonitorChannel != nil && !o.driftMonitorStopped {
+		close(o.driftMonitorChannel)
+		o.driftMonitorStopped = true
+	}
+	log.Debug("Drift monitor stopped.")
+}
+
+// driftCheck holds what Trident expects to find on one backend.
+type driftCheck struct {
+	backend *storage.Backend
+	volumes []*storage.Volume
+	// snapshots are keyed by volume name
+	snapshots map[string][]*storage.SnapshotConfig
+	// knownNames are the internal names of the volumes Trident manages on the backend
+	knownNames map[string]bool
+}
+
+// detectDrift compares the state of every online backend with Trident's records, repairs the
+// discrepancies that are safe to repair if auto-repair is enabled, and saves the report.  The
+// backends are queried without holding the orchestrator lock.
+func (o *TridentOrchestrator) detectDrift() *storage.DriftReport {
+
+	// Only one detection runs at a time, whether periodic or requested
+	o.driftMutex.Lock()
+	defer o.driftMutex.Unlock()
+
+	report := &storage.DriftReport{
+		Started: time.Now().UTC().Format(time.RFC3339),
+		Drifts:  make([]*storage.Drift, 0),
+	}
+
+	checks := o.getDriftChecks()
+
+	// Volumes being created or imported exist on a backend before Trident records them
+	pendingNames := make(map[string]bool)
+	checkOrphans := true
+	if txns, err := o.storeClient.GetVolumeTransactions(); err != nil {
+		log.WithField("error", err).Warning("Could not read transactions, skipping orphaned volume detection.")
+		checkOrphans = false
+	} else {
+		for _, txn := range txns {
+			if txn.Config != nil {
+				pendingNames[txn.Config.InternalName] = true
+			}
+			if txn.VolumeMigration != nil {
+				pendingNames[txn.VolumeMigration.DestinationInternalName] = true
+			}
+		}
+	}
+
+	for _, check := range checks {
+		drifts, errs := detectBackendDrift(check, pendingNames, checkOrphans)
+		for _, err := range errs {
+			report.Errors = append(report.Errors, err.Error())
+		}
+		report.Drifts = append(report.Drifts, drifts...)
+	}
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	defer o.updateMetrics()
+
+	for _, drift := range report.Drifts {
+		if o.driftAutoRepair {
+			o.repairDrift(drift)
+		}
+		log.WithFields(log.Fields{
+			"type":         drift.Type,
+			"backend":      drift.Backend,
+			"volume":       drift.Volume,
+			"snapshot":     drift.Snapshot,
+			"internalName": drift.InternalName,
+			"repaired":     drift.Repaired,
+		}).Warning(drift.Message)
+	}
+
+	report.Finished = time.Now().UTC().Format(time.RFC3339)
+	o.driftReport = report
+
+	log.WithFields(log.Fields{
+		"drifts": len(report.Drifts),
+		"errors": len(report.Errors),
+	}).Info("Drift detection complete.")
+
+	return report
+}
+
+// getDriftChecks returns what Trident expects to find on each backend that serves its volumes,
+// including cordoned and draining backends.  Volumes that are migrating or not online are skipped,
+// since their state is expected to change.
+func (o *TridentOrchestrator) getDriftChecks() []*driftCheck {
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	checksByUUID := make(map[string]*driftCheck)
+	for backendUUID, backend := range o.backends {
+		if backend.State.IsServing() {
+			checksByUUID[backendUUID] = &driftCheck{
+				backend:    backend,
+				volumes:    make([]*storage.Volume, 0),
+				snapshots:  make(map[string][]*storage.SnapshotConfig),
+				knownNames: make(map[string]bool),
+			}
+		}
+	}
+
+	for volumeName, volume := range o.volumes {
+		check, ok := checksByUUID[volume.BackendUUID]
+		if !ok {
+			continue
+		}
+		check.knownNames[volume.Config.InternalName] = true
+		if !volume.State.IsOnline() || o.volumeMigrationInProgress(volumeName) {
+			continue
+		}
+		volumeConfig := *volume.Config
+		check.volumes = append(check.volumes, storage.NewVolume(&volumeConfig, volume.BackendUUID,
+			volume.Pool, volume.Orphaned))
+	}
+
+	for _, migration := range o.migrations {
+		if check, ok := checksByUUID[migration.DestinationBackendUUID]; ok {
+			check.knownNames[migration.DestinationInternalName] = true
+		}
+	}
+
+	for _, snapshot := range o.snapshots {
+		volume, ok := o.volumes[snapshot.Config.VolumeName]
+		if !ok || snapshot.State.IsMissingBackend() || snapshot.State.IsMissingVolume() {
+			continue
+		}
+		if check, ok := checksByUUID[volume.BackendUUID]; ok {
+			snapshotConfig := *snapshot.Config
+			check.snapshots[volume.Config.Name] = append(check.snapshots[volume.Config.Name], &snapshotConfig)
+		}
+	}
+
+	checks := make([]*driftCheck, 0, len(checksByUUID))
+	for _, check := range checksByUUID {
+		sort.Slice(check.volumes, func(i, j int) bool {
+			return check.volumes[i].Config.Name < check.volumes[j].Config.Name
+		})
+		checks = append(checks, check)
+	}
+	sort.Slice(checks, func(i, j int) bool { return checks[i].backend.Name < checks[j].backend.Name })
+
+	return checks
+}
+
+// detectBackendDrift compares one backend's volumes and snapshots with Trident's records.  A volume
+// is only reported missing if the backend says it doesn't exist; volumes the backend couldn't be
+// asked about are returned as errors instead.
+func detectBackendDrift(
+	check *driftCheck, pendingNames map[string]bool, checkOrphans bool,
+) ([]*storage.Drift, []error) {
+
+	backend := check.backend
+	drifts := make([]*storage.Drift, 0)
+	errs := make([]error, 0)
+
+	for _, volume := range check.volumes {
+
+		internalName := volume.Config.InternalName
+
+		if err := backend.Driver.Get(internalName); utils.IsNotFoundError(err) {
+			drifts = append(drifts, &storage.Drift{
+				Type:         storage.DriftMissingVolume,
+				Backend:      backend.Name,
+				Volume:       volume.Config.Name,
+				InternalName: internalName,
+				Message:      fmt.Sprintf("Volume %s was not found on backend %s.", internalName, backend.Name),
+			})
+			continue
+		} else if err != nil {
+			log.WithFields(log.Fields{
+				"volume":  volume.Config.Name,
+				"backend": backend.Name,
+				"error":   err,
+			}).Warning("Could not read volume during drift detection.")
+			errs = append(errs, fmt.Errorf("could not read volume %s on backend %s; %v",
+				internalName, backend.Name, err))
+			continue
+		}
+
+		volExternal, err := backend.Driver.GetVolumeExternal(internalName)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"volume":  volume.Config.Name,
+				"backend": backend.Name,
+				"error":   err,
+			}).Warning("Could not read volume during drift detection.")
+		} else if sizeDiffers(volume.Config.Size, volExternal.Config.Size) {
+			drifts = append(drifts, &storage.Drift{
+				Type:         storage.DriftSizeMismatch,
+				Backend:      backend.Name,
+				Volume:       volume.Config.Name,
+				InternalName: internalName,
+				Expected:     volume.Config.Size,
+				Actual:       volExternal.Config.Size,
+				Message: fmt.Sprintf("Volume %s is %s bytes on backend %s, but Trident recorded %s bytes.",
+					internalName, volExternal.Config.Size, backend.Name, volume.Config.Size),
+			})
+		}
+
+		snapshotConfigs := check.snapshots[volume.Config.Name]
+		if len(snapshotConfigs) == 0 {
+			continue
+		}
+		snapshots, err := backend.GetSnapshots(volume.Config)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"volume":  volume.Config.Name,
+				"backend": backend.Name,
+				"error":   err,
+			}).Warning("Could not read snapshots during drift detection.")
+			continue
+		}
+		snapshotNames := make(map[string]bool)
+		for _, snapshot := range snapshots {
+			snapshotNames[snapshot.Config.InternalName] = true
+		}
+		for _, snapshotConfig := range snapshotConfigs {
+			if !snapshotNames[snapshotConfig.InternalName] {
+				drifts = append(drifts, &storage.Drift{
+					Type:         storage.DriftMissingSnapshot,
+					Backend:      backend.Name,
+					Volume:       volume.Config.Name,
+					Snapshot:     snapshotConfig.Name,
+					InternalName: snapshotConfig.InternalName,
+					Message: fmt.Sprintf("Snapshot %s of volume %s was not found on backend %s.",
+						snapshotConfig.InternalName, internalName, backend.Name),
+				})
+			}
+		}
+	}
+
+	if !checkOrphans {
+		return drifts, errs
+	}
+
+	// Volumes owned by the driver that Trident doesn't know about
+	orphans := make([]string, 0)
+	var listErr error
+	volumeChannel := make(chan *storage.VolumeExternalWrapper)
+	go backend.Driver.GetVolumeExternalWrappers(volumeChannel)
+	for wrapper := range volumeChannel {
+		if wrapper.Error != nil {
+			listErr = wrapper.Error
+			continue
+		}
+		internalName := wrapper.Volume.Config.InternalName
+		if !check.knownNames[internalName] && !pendingNames[internalName] {
+			orphans = append(orphans, internalName)
+		}
+	}
+	if listErr != nil {
+		return drifts, append(errs, fmt.Errorf("could not list the volumes on backend %s; %v", backend.Name, listErr))
+	}
+
+	sort.Strings(orphans)
+	for _, internalName := range orphans {
+		drifts = append(drifts, &storage.Drift{
+			Type:         storage.DriftOrphanedVolume,
+			Backend:      backend.Name,
+			InternalName: internalName,
+			Message:      fmt.Sprintf("Volume %s on backend %s is not managed by Trident.", internalName, backend.Name),
+		})
+	}
+
+	return drifts, errs
+}
+
+// sizeDiffers reports whether two volume sizes in bytes differ by more than the tolerance.
+// Sizes that can't be parsed never differ.
+func sizeDiffers(expected, actual string) bool {
+	expectedBytes, err := strconv.ParseUint(expected, 10, 64)
+	if err != nil || expectedBytes == 0 {
+		return false
+	}
+	actualBytes, err := strconv.ParseUint(actual, 10, 64)
+	if err != nil {
+		return false
+	}
+	difference := expectedBytes - actualBytes
+	if actualBytes > expectedBytes {
+		difference = actualBytes - expectedBytes
+	}
+	return difference*100 > expectedBytes*driftSizeTolerancePercent
+}
+
+// repairDrift corrects Trident's records where the backend is authoritative and nothing is
+// changed on the backend: missing volumes are marked orphaned, and volumes expanded outside
+// Trident have their size updated.  The caller should hold the orchestrator lock.
+func (o *TridentOrchestrator) repairDrift(drift *storage.Drift) {
+
+	volume, ok := o.volumes[drift.Volume]
+	if !ok || volume.Config.InternalName != drift.InternalName {
+		return
+	}
+
+	switch drift.Type {
+	case storage.DriftMissingVolume:
+		if volume.Orphaned {
+			drift.Repaired = true
+			return
+		}
+		volume.Orphaned = true
+
+	case storage.DriftSizeMismatch:
+		expectedBytes, _ := strconv.ParseUint(drift.Expected, 10, 64)
+		actualBytes, _ := strconv.ParseUint(drift.Actual, 10, 64)
+		if volume.Config.Size != drift.Expected || actualBytes < expectedBytes {
+			// Shrinking the recorded size could hide lost data, so it is left for an administrator
+			return
+		}
+		volume.Config.Size = drift.Actual
+
+	default:
+		return
+	}
+
+	if err := o.updateVolumeOnPersistentStore(volume); err != nil {
+		log.WithFields(log.Fields{
+			"volume": volume.Config.Name,
+			"error":  err,
+		}).Error("Could not repair drift.")
+		return
+	}
+	drift.Repaired = true
+}
@@ -0,0 +1,163 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/storage"
+	sa "github.com/netapp/trident/storage_attribute"
+	storageclass "github.com/netapp/trident/storage_class"
+	fakedriver "github.com/netapp/trident/storage_drivers/fake"
+	tu "github.com/netapp/trident/storage_drivers/fake/test_utils"
+)
+
+func TestSizeDiffers(t *testing.T) {
+	assert.False(t, sizeDiffers("1000", "1000"))
+	assert.False(t, sizeDiffers("1000", "1009"))
+	assert.True(t, sizeDiffers("1000", "1011"))
+	assert.True(t, sizeDiffers("1000", "989"))
+	assert.False(t, sizeDiffers("", "1000"))
+	assert.False(t, sizeDiffers("1000", "unknown"))
+}
+
+func TestDetectDrift(t *testing.T) {
+	const (
+		backendName = "driftBackend"
+		scName      = "driftSC"
+	)
+
+	orchestrator := getOrchestrator()
+	addBackend(t, orchestrator, backendName, config.File)
+
+	if _, err := orchestrator.AddStorageClass(&storageclass.Config{
+		Name:       scName,
+		Attributes: map[string]sa.Request{sa.TestingAttribute: sa.NewBoolRequest(true)},
+	}); err != nil {
+		t.Fatal("Unable to add storage class: ", err)
+	}
+
+	internalNames := make(map[string]string)
+	for _, volumeName := range []string{"intact", "missing", "expanded", "shrunk"} {
+		volume, err := orchestrator.AddVolume(ctx(), tu.GenerateVolumeConfig(volumeName, 1, scName, config.File))
+		if err != nil {
+			t.Fatal("Unable to create volume: ", err)
+		}
+		internalNames[volumeName] = volume.Config.InternalName
+	}
+	if _, err := orchestrator.CreateSnapshot(ctx(), &storage.SnapshotConfig{
+		Name:               "snap",
+		VolumeName:         "intact",
+		VolumeInternalName: internalNames["intact"],
+	}); err != nil {
+		t.Fatal("Unable to create snapshot: ", err)
+	}
+
+	_, err := orchestrator.GetDriftReport()
+	assert.Error(t, err, "expected no report before detection")
+
+	// Change the backend behind Trident's back
+	var driver *fakedriver.StorageDriver
+	for _, backend := range orchestrator.backends {
+		driver = backend.Driver.(*fakedriver.StorageDriver)
+	}
+	delete(driver.Volumes, internalNames["missing"])
+	for volumeName, sizeBytes := range map[string]uint64{"expanded": 2147483648, "shrunk": 536870912} {
+		volume := driver.Volumes[internalNames[volumeName]]
+		volume.SizeBytes = sizeBytes
+		driver.Volumes[internalNames[volumeName]] = volume
+	}
+	driver.Snapshots[internalNames["intact"]] = nil
+
+	orchestrator.SetDriftAutoRepair(true)
+	report, err := orchestrator.DetectDrift()
+	assert.NoError(t, err)
+	assert.Empty(t, report.Errors)
+
+	driftsByType := make(map[storage.DriftType][]*storage.Drift)
+	for _, drift := range report.Drifts {
+		driftsByType[drift.Type] = append(driftsByType[drift.Type], drift)
+	}
+
+	if assert.Len(t, driftsByType[storage.DriftMissingVolume], 1) {
+		assert.Equal(t, "missing", driftsByType[storage.DriftMissingVolume][0].Volume)
+		assert.True(t, driftsByType[storage.DriftMissingVolume][0].Repaired)
+	}
+	assert.True(t, orchestrator.volumes["missing"].Orphaned)
+
+	if assert.Len(t, driftsByType[storage.DriftSizeMismatch], 2) {
+		for _, drift := range driftsByType[storage.DriftSizeMismatch] {
+			// Only volumes that grew outside Trident are repaired
+			assert.Equal(t, drift.Volume == "expanded", drift.Repaired, drift.Volume)
+		}
+	}
+	assert.Equal(t, "2147483648", orchestrator.volumes["expanded"].Config.Size)
+	assert.Equal(t, "1073741824", orchestrator.volumes["shrunk"].Config.Size)
+
+	if assert.Len(t, driftsByType[storage.DriftMissingSnapshot], 1) {
+		assert.Equal(t, "snap", driftsByType[storage.DriftMissingSnapshot][0].Snapshot)
+		assert.False(t, driftsByType[storage.DriftMissingSnapshot][0].Repaired)
+	}
+
+	// The volumes the fake backend was created with aren't managed by Trident
+	orphans := make([]string, 0)
+	for _, drift := range driftsByType[storage.DriftOrphanedVolume] {
+		orphans = append(orphans, drift.InternalName)
+	}
+	assert.Equal(t, []string{"origVolume01", "origVolume02"}, orphans)
+
+	saved, err := orchestrator.GetDriftReport()
+	assert.NoError(t, err)
+	assert.Equal(t, report, saved)
+
+	cleanup(t, orchestrator)
+}
+
+// unreachableDriver fails to look up volumes, like a driver whose storage system can't be reached.
+type unreachableDriver struct {
+	*fakedriver.StorageDriver
+}
+
+func (d *unreachableDriver) Get(name string) error {
+	return fmt.Errorf("could not connect to the storage system")
+}
+
+func TestDetectDriftUnreachableVolume(t *testing.T) {
+	const (
+		backendName = "driftUnreachableBackend"
+		scName      = "driftUnreachableSC"
+		volumeName  = "driftUnreachableVolume"
+	)
+
+	orchestrator := getOrchestrator()
+	addBackendStorageClass(t, orchestrator, backendName, scName, config.File)
+	volumeConfig := tu.GenerateVolumeConfig(volumeName, 1, scName, config.File)
+	if _, err := orchestrator.AddVolume(ctx(), volumeConfig); err != nil {
+		t.Fatal("Unable to create volume: ", err)
+	}
+
+	// Cordoned backends still serve their volumes, so they are checked too
+	backend, err := orchestrator.getBackendByBackendName(backendName)
+	if err != nil {
+		t.Fatal("Unable to find backend: ", err)
+	}
+	driver := backend.Driver.(*fakedriver.StorageDriver)
+	backend.Driver = &unreachableDriver{driver}
+	backend.State = storage.Cordoned
+
+	report, err := orchestrator.DetectDrift()
+	assert.NoError(t, err)
+	for _, drift := range report.Drifts {
+		assert.NotEqual(t, storage.DriftMissingVolume, drift.Type, "a volume that couldn't be read isn't missing")
+	}
+	assert.Len(t, report.Errors, 1)
+	assert.False(t, orchestrator.volumes[volumeName].Orphaned)
+
+	backend.Driver = driver
+	backend.State = storage.Online
+	cleanup(t, orchestrator)
+}
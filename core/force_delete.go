@@ -0,0 +1,123 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	persistentstore "github.com/netapp/trident/persistent_store"
+	"github.com/netapp/trident/utils"
+)
+
+// ForceDeleteBackend removes a backend's custom resource, even if Trident's finalizers are keeping
+// it, such as one left behind by a disaster cleanup.  The backend must no longer be managed by
+// Trident, and none of the persisted volumes may be on it.
+func (o *TridentOrchestrator) ForceDeleteBackend(backendName string) (err error) {
+	if o.bootstrapError != nil {
+		return o.bootstrapError
+	}
+
+	defer recordTiming("backend_force_delete", &err)()
+
+	crdClient, ok := o.storeClient.(persistentstore.CRDClient)
+	if !ok {
+		return utils.UnsupportedError("only the custom resources of the CRD persistent store may be force deleted")
+	}
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	if _, err = o.getBackendByBackendName(backendName); err == nil {
+		return fmt.Errorf("backend %s is managed by Trident; delete it without forcing", backendName)
+	}
+
+	backendUUID, err := crdClient.GetBackendUUID(backendName)
+	if err != nil {
+		if persistentstore.MatchKeyNotFoundErr(err) {
+			return utils.NotFoundError(fmt.Sprintf("backend %s was not found", backendName))
+		}
+		return err
+	}
+	if backend, ok := o.backends[backendUUID]; ok {
+		return fmt.Errorf("backend %s is managed by Trident as backend %s", backendName, backend.Name)
+	}
+
+	// A volume still on the backend needs it, whether or not Trident has loaded the volume
+	for _, volume := range o.volumes {
+		if volume.BackendUUID == backendUUID {
+			return fmt.Errorf("volume %s is still on backend %s", volume.Config.Name, backendName)
+		}
+	}
+	volumes, err := o.storeClient.GetVolumes()
+	if err != nil {
+		return fmt.Errorf("could not verify that no volumes are on backend %s; %v", backendName, err)
+	}
+	for _, volume := range volumes {
+		if volume.BackendUUID == backendUUID {
+			return fmt.Errorf("volume %s is still on backend %s", volume.Config.Name, backendName)
+		}
+	}
+
+	if err = crdClient.ForceDeleteBackend(backendName); err != nil {
+		return err
+	}
+
+	log.WithFields(log.Fields{
+		"backend":     backendName,
+		"backendUUID": backendUUID,
+	}).Warning("Force deleted backend.")
+
+	return nil
+}
+
+// ForceDeleteVolume removes a volume's custom resource, even if Trident's finalizers are keeping it,
+// such as one left behind by a disaster cleanup.  The volume must no longer be managed by Trident,
+// and if its backend is, the backend must report that the volume is gone.
+func (o *TridentOrchestrator) ForceDeleteVolume(volumeName string) (err error) {
+	if o.bootstrapError != nil {
+		return o.bootstrapError
+	}
+
+	defer recordTiming("volume_force_delete", &err)()
+
+	crdClient, ok := o.storeClient.(persistentstore.CRDClient)
+	if !ok {
+		return utils.UnsupportedError("only the custom resources of the CRD persistent store may be force deleted")
+	}
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	if _, ok := o.volumes[volumeName]; ok {
+		return fmt.Errorf("volume %s is managed by Trident; delete it without forcing", volumeName)
+	}
+
+	volume, err := o.storeClient.GetVolume(volumeName)
+	if err != nil {
+		if persistentstore.MatchKeyNotFoundErr(err) || persistentstore.IsStatusNotFoundError(err) {
+			return utils.NotFoundError(fmt.Sprintf("volume %s was not found", volumeName))
+		}
+		return err
+	}
+
+	// The volume's storage would be leaked if it still exists on its backend
+	if backend, ok := o.backends[volume.BackendUUID]; ok {
+		if !backend.State.IsServing() {
+			return fmt.Errorf("could not verify that volume %s is gone; backend %s is %s",
+				volumeName, backend.Name, backend.State)
+		}
+		if err = backend.Driver.Get(volume.Config.InternalName); err == nil {
+			return fmt.Errorf("volume %s still exists on backend %s", volumeName, backend.Name)
+		}
+	}
+
+	if err = crdClient.ForceDeleteVolume(volumeName); err != nil {
+		return err
+	}
+
+	log.WithField("volume", volumeName).Warning("Force deleted volume.")
+
+	return nil
+}
@@ -0,0 +1,23 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netapp/trident/utils"
+)
+
+func TestForceDeleteUnsupportedStore(t *testing.T) {
+
+	orchestrator := getOrchestrator()
+	defer cleanup(t, orchestrator)
+
+	err := orchestrator.ForceDeleteBackend("backend")
+	assert.True(t, utils.IsUnsupportedError(err), "only the CRD store has finalizers")
+
+	err = orchestrator.ForceDeleteVolume("volume")
+	assert.True(t, utils.IsUnsupportedError(err), "only the CRD store has finalizers")
+}
@@ -0,0 +1,66 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	persistentstore "github.com/netapp/trident/persistent_store"
+	"github.com/netapp/trident/utils"
+)
+
+// ForceDetachNode revokes a node's access to volumes on every backend and deregisters the node.  It is
+// used when a node was shut down without unpublishing its volumes, so that the volumes may be published
+// to other nodes without waiting for the node to return.
+func (o *TridentOrchestrator) ForceDetachNode(nodeName string) (err error) {
+	if o.bootstrapError != nil {
+		return o.bootstrapError
+	}
+
+	defer recordTiming("node_force_detach", &err)()
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	defer o.updateMetrics()
+
+	node, found := o.nodes[nodeName]
+	if !found {
+		return utils.NotFoundError(fmt.Sprintf("node %s not found", nodeName))
+	}
+
+	revokeErrors := make([]error, 0)
+	for _, backend := range o.backends {
+		if err := backend.RevokeNodeAccess(node); err != nil {
+			log.WithFields(log.Fields{
+				"node":    nodeName,
+				"backend": backend.Name,
+			}).Errorf("Could not revoke node access. %v", err)
+			revokeErrors = append(revokeErrors, fmt.Errorf("backend %s: %v", backend.Name, err))
+		}
+	}
+	if len(revokeErrors) > 0 {
+		return fmt.Errorf("could not revoke access of node %s; %v", nodeName, revokeErrors)
+	}
+
+	// The node's access is gone, so its volumes may be published elsewhere
+	err = persistentstore.Atomically(o.storeClient, func(client persistentstore.Client) error {
+		for _, volume := range o.volumes {
+			if volume.RemovePublishedNode(nodeName) {
+				if err := client.UpdateVolume(volume); err != nil {
+					return err
+				}
+			}
+		}
+		return client.DeleteNode(node)
+	})
+	if err != nil {
+		return err
+	}
+	delete(o.nodes, nodeName)
+
+	log.WithField("node", nodeName).Warning("Force-detached volumes from out-of-service node.")
+
+	return o.reconcileNodeAccessOnAllBackends()
+}
@@ -0,0 +1,37 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/utils"
+)
+
+func TestForceDetachNode(t *testing.T) {
+
+	orchestrator := getOrchestrator()
+	defer cleanup(t, orchestrator)
+
+	addBackend(t, orchestrator, "fakeBackend", config.Block)
+	assert.NoError(t, orchestrator.AddNode(&utils.Node{Name: "failed", IQN: "iqn.failed"}))
+	assert.NoError(t, orchestrator.AddNode(&utils.Node{Name: "healthy", IQN: "iqn.healthy"}))
+
+	err := orchestrator.ForceDetachNode("unknown")
+	assert.True(t, utils.IsNotFoundError(err), "unknown node should not be found")
+
+	// Backends whose drivers don't grant access at publish time have nothing to revoke
+	assert.NoError(t, orchestrator.ForceDetachNode("failed"))
+
+	_, err = orchestrator.GetNode("failed")
+	assert.True(t, utils.IsNotFoundError(err))
+	_, err = orchestrator.GetNode("healthy")
+	assert.NoError(t, err)
+
+	persistentNodes, err := orchestrator.storeClient.GetNodes()
+	assert.NoError(t, err)
+	assert.Len(t, persistentNodes, 1)
+}
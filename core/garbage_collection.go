@@ -0,0 +1,416 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netapp/trident/storage"
+	"github.com/netapp/trident/utils"
+)
+
+const garbageCollectionPeriod = 60 * time.Minute
+
+// SetGarbageCollectionAutoClean determines whether garbage collection cleans up the garbage it
+// finds, or only reports it.
+func (o *TridentOrchestrator) SetGarbageCollectionAutoClean(autoClean bool) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	o.gcAutoClean = autoClean
+}
+
+// CollectGarbage searches Trident's persistent store for orphaned transactions and stale objects.
+func (o *TridentOrchestrator) CollectGarbage() (report *storage.GarbageReport, err error) {
+	if o.bootstrapError != nil {
+		return nil, o.bootstrapError
+	}
+
+	defer recordTiming("garbage_collect", &err)()
+
+	return o.collectGarbage(), nil
+}
+
+// GetGarbageReport returns the result of the most recent garbage collection.
+func (o *TridentOrchestrator) GetGarbageReport() (report *storage.GarbageReport, err error) {
+	if o.bootstrapError != nil {
+		return nil, o.bootstrapError
+	}
+
+	defer recordTiming("garbage_get", &err)()
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	if o.gcReport == nil {
+		return nil, utils.NotFoundError("garbage collection has not run")
+	}
+	return o.gcReport, nil
+}
+
+// StartGarbageCollectionMonitor starts the thread that periodically collects garbage.
+func (o *TridentOrchestrator) StartGarbageCollectionMonitor(period time.Duration) {
+
+	o.gcMonitorTicker = time.NewTicker(period)
+	o.gcMonitorChannel = make(chan struct{})
+
+	go func() {
+		log.Debug("Garbage collection monitor started.")
+
+		for {
+			select {
+			case tick := <-o.gcMonitorTicker.C:
+				log.WithField("tick", tick).Debug("Garbage collection monitor running.")
+				if o.bootstrapError != nil {
+					log.WithField("error", o.bootstrapError).Errorf(
+						"Garbage collection monitor blocked by bootstrap error.")
+					continue
+				}
+				o.collectGarbage()
+			case <-o.gcMonitorChannel:
+				log.Debugf("Garbage collection monitor stopped.")
+				return
+			}
+		}
+	}()
+}
+
+// StopGarbageCollectionMonitor stops the thread that collects garbage.
+func (o *TridentOrchestrator) StopGarbageCollectionMonitor() {
+	if o.gcMonitorTicker != nil {
+		o.gcMonitorTicker.Stop()
+	}
+	if o.gcMonitorChannel != nil && !o.gcMonitorStopped {
+		close(o.gcMonitorChannel)
+		o.gcMonitorStopped = true
+	}
+	log.Debug("Garbage collection monitor stopped.")
+}
+
+// setTransactionActive records whether a transaction belongs to a running operation.
+func (o *TridentOrchestrator) setTransactionActive(txn *storage.VolumeTransaction, active bool) {
+	o.txnMutex.Lock()
+	defer o.txnMutex.Unlock()
+
+	if active {
+		o.activeTransactions[txn.Name()] = true
+	} else {
+		delete(o.activeTransactions, txn.Name())
+	}
+}
+
+// isTransactionActive reports whether the named transaction belongs to a running operation.
+func (o *TridentOrchestrator) isTransactionActive(name string) bool {
+	o.txnMutex.Lock()
+	defer o.txnMutex.Unlock()
+
+	return o.activeTransactions[name]
+}
+
+// isSynchronousTransaction reports whether a transaction only exists while its operation runs.
+// Transactions for volumes being created by their backends, for queued deletions and for
+// migrations outlive the requests that started them, and are handled by their own monitors.
+func isSynchronousTransaction(txn *storage.VolumeTransaction) bool {
+	switch txn.Op {
+	case storage.AddVolume, storage.ImportVolume, storage.ResizeVolume, storage.AddSnapshot,
+		storage.DeleteSnapshot:
+		return true
+	case storage.DeleteVolume:
+		return txn.VolumeDeletion == nil
+	default:
+		return false
+	}
+}
+
+// collectGarbage finds the orphaned transactions, stale snapshots, stale attachments and stale
+// ephemeral volumes in the persistent store, cleans them up if auto-clean is enabled, and saves
+// the report.
+func (o *TridentOrchestrator) collectGarbage() *storage.GarbageReport {
+
+	// Only one collection runs at a time, whether periodic or requested
+	o.gcMutex.Lock()
+	defer o.gcMutex.Unlock()
+
+	report := &storage.GarbageReport{
+		Started: time.Now().UTC().Format(time.RFC3339),
+		Garbage: make([]*storage.Garbage, 0),
+	}
+
+	// Transactions are read before checking which are active, so that a transaction added after
+	// the read can't be mistaken for an orphan
+	txns, txnErr := o.storeClient.GetVolumeTransactions()
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	defer o.updateMetrics()
+
+	if txnErr != nil {
+		report.Errors = append(report.Errors, fmt.Sprintf("could not read transactions; %v", txnErr))
+	} else {
+		o.collectOrphanedTransactions(report, txns)
+	}
+
+	if volumes, err := o.storeClient.GetVolumes(); err != nil {
+		report.Errors = append(report.Errors, fmt.Sprintf("could not read volumes; %v", err))
+	} else {
+		o.collectStaleSnapshots(report, volumes)
+		o.collectStaleAttachments(report, volumes)
+		o.collectStaleEphemeralVolumes(report, volumes)
+	}
+
+	for _, garbage := range report.Garbage {
+		log.WithFields(log.Fields{
+			"type":    garbage.Type,
+			"name":    garbage.Name,
+			"volume":  garbage.Volume,
+			"node":    garbage.Node,
+			"cleaned": garbage.Cleaned,
+		}).Warning(garbage.Message)
+	}
+
+	report.Finished = time.Now().UTC().Format(time.RFC3339)
+	o.gcReport = report
+
+	log.WithFields(log.Fields{
+		"garbage": len(report.Garbage),
+		"errors":  len(report.Errors),
+	}).Info("Garbage collection complete.")
+
+	return report
+}
+
+// collectOrphanedTransactions finds the transactions of synchronous operations that aren't
+// running.  An operation's transaction may be read just before the operation deletes it, so a
+// transaction is only reported once two consecutive collections have found it orphaned.  The
+// caller must hold the orchestrator lock.
+func (o *TridentOrchestrator) collectOrphanedTransactions(
+	report *storage.GarbageReport, txns []*storage.VolumeTransaction,
+) {
+
+	suspects := make(map[string]bool)
+
+	for _, txn := range txns {
+		if !isSynchronousTransaction(txn) {
+			continue
+		}
+		name := txn.Name()
+		if o.isTransactionActive(name) {
+			continue
+		}
+		suspects[name] = true
+		if !o.gcSuspects[name] {
+			continue
+		}
+
+		garbage := &storage.Garbage{
+			Type:      storage.GarbageOrphanedTransaction,
+			Name:      name,
+			Operation: txn.Op,
+			Message:   fmt.Sprintf("The %s transaction for %s has no running operation.", txn.Op, name),
+		}
+		if txn.SnapshotConfig != nil {
+			garbage.Volume = txn.SnapshotConfig.VolumeName
+			garbage.Snapshot = txn.SnapshotConfig.Name
+		} else if txn.Config != nil {
+			garbage.Volume = txn.Config.Name
+		}
+
+		// An orphaned transaction is recovered just as if Trident had restarted during its operation
+		if o.gcAutoClean {
+			if err := o.handleFailedTransaction(txn); err != nil {
+				report.Errors = append(report.Errors, fmt.Sprintf("could not clean up the %s transaction "+
+					"for %s; %v", txn.Op, name, err))
+			} else {
+				garbage.Cleaned = true
+				delete(suspects, name)
+			}
+		}
+
+		report.Garbage = append(report.Garbage, garbage)
+	}
+
+	o.gcSuspects = suspects
+}
+
+// collectStaleSnapshots finds the persisted snapshots of volumes that are no longer persisted.
+// The caller must hold the orchestrator lock.
+func (o *TridentOrchestrator) collectStaleSnapshots(
+	report *storage.GarbageReport, volumes []*storage.VolumeExternal,
+) {
+
+	snapshots, err := o.storeClient.GetSnapshots()
+	if err != nil {
+		report.Errors = append(report.Errors, fmt.Sprintf("could not read snapshots; %v", err))
+		return
+	}
+
+	volumeNames := make(map[string]bool, len(volumes))
+	for _, volume := range volumes {
+		volumeNames[volume.Config.Name] = true
+	}
+
+	for _, snapshot := range snapshots {
+		volumeName := snapshot.Config.VolumeName
+		if volumeNames[volumeName] {
+			continue
+		}
+
+		garbage := &storage.Garbage{
+			Type:     storage.GarbageStaleSnapshot,
+			Name:     snapshot.ID(),
+			Volume:   volumeName,
+			Snapshot: snapshot.Config.Name,
+			Message: fmt.Sprintf("Snapshot %s refers to volume %s, which no longer exists.",
+				snapshot.ID(), volumeName),
+		}
+
+		if o.gcAutoClean {
+			if err := o.storeClient.DeleteSnapshot(&snapshot.Snapshot); err != nil {
+				report.Errors = append(report.Errors, fmt.Sprintf("could not delete snapshot %s; %v",
+					snapshot.ID(), err))
+			} else {
+				delete(o.snapshots, snapshot.ID())
+				garbage.Cleaned = true
+			}
+		}
+
+		report.Garbage = append(report.Garbage, garbage)
+	}
+}
+
+// collectStaleAttachments finds the records of persisted volumes being published to nodes that
+// are no longer registered.  The nodes' access was revoked when they were deregistered, so only
+// the records remain.  Inline ephemeral volumes are left to collectStaleEphemeralVolumes.  The
+// caller must hold the orchestrator lock.
+func (o *TridentOrchestrator) collectStaleAttachments(
+	report *storage.GarbageReport, volumes []*storage.VolumeExternal,
+) {
+
+	nodes, err := o.storeClient.GetNodes()
+	if err != nil {
+		report.Errors = append(report.Errors, fmt.Sprintf("could not read nodes; %v", err))
+		return
+	}
+
+	nodeNames := make(map[string]bool, len(nodes))
+	for _, node := range nodes {
+		nodeNames[node.Name] = true
+	}
+
+	for _, volumeExternal := range volumes {
+		if volumeExternal.Config.EphemeralNode != "" {
+			continue
+		}
+		volumeName := volumeExternal.Config.Name
+		for _, nodeName := range volumeExternal.PublishedNodes {
+			if nodeNames[nodeName] {
+				continue
+			}
+
+			garbage := &storage.Garbage{
+				Type:   storage.GarbageStaleAttachment,
+				Name:   volumeName + "/" + nodeName,
+				Volume: volumeName,
+				Node:   nodeName,
+				Message: fmt.Sprintf("Volume %s is published to node %s, which is not registered.",
+					volumeName, nodeName),
+			}
+
+			if o.gcAutoClean {
+				if err := o.removeStaleAttachment(volumeName, nodeName); err != nil {
+					report.Errors = append(report.Errors, err.Error())
+				} else {
+					garbage.Cleaned = true
+				}
+			}
+
+			report.Garbage = append(report.Garbage, garbage)
+		}
+	}
+}
+
+// collectStaleEphemeralVolumes finds the CSI inline ephemeral volumes of nodes that are no longer
+// registered.  Such a node can't ask for its volumes to be deleted when their pods are done with
+// them, so they are unpublished and queued for deletion.  The caller must hold the orchestrator lock.
+func (o *TridentOrchestrator) collectStaleEphemeralVolumes(
+	report *storage.GarbageReport, volumes []*storage.VolumeExternal,
+) {
+
+	nodes, err := o.storeClient.GetNodes()
+	if err != nil {
+		report.Errors = append(report.Errors, fmt.Sprintf("could not read nodes; %v", err))
+		return
+	}
+
+	nodeNames := make(map[string]bool, len(nodes))
+	for _, node := range nodes {
+		nodeNames[node.Name] = true
+	}
+
+	for _, volumeExternal := range volumes {
+		volumeName := volumeExternal.Config.Name
+		nodeName := volumeExternal.Config.EphemeralNode
+		if nodeName == "" || nodeNames[nodeName] || o.volumeDeleteInProgress(volumeName) {
+			continue
+		}
+
+		garbage := &storage.Garbage{
+			Type:   storage.GarbageStaleEphemeralVolume,
+			Name:   volumeName,
+			Volume: volumeName,
+			Node:   nodeName,
+			Message: fmt.Sprintf("Ephemeral volume %s belongs to node %s, which is not registered.",
+				volumeName, nodeName),
+		}
+
+		if o.gcAutoClean {
+			if err := o.removeStaleEphemeralVolume(volumeName, nodeName); err != nil {
+				report.Errors = append(report.Errors, err.Error())
+			} else {
+				garbage.Cleaned = true
+			}
+		}
+
+		report.Garbage = append(report.Garbage, garbage)
+	}
+}
+
+// removeStaleEphemeralVolume removes the record of an inline ephemeral volume being published to
+// its deregistered node and queues the volume for deletion.  The caller must hold the orchestrator
+// lock.
+func (o *TridentOrchestrator) removeStaleEphemeralVolume(volumeName, nodeName string) error {
+
+	if err := o.removeStaleAttachment(volumeName, nodeName); err != nil {
+		return err
+	}
+	if err := o.queueVolumeDeletion(o.volumes[volumeName]); err != nil {
+		return fmt.Errorf("could not queue the deletion of ephemeral volume %s; %v", volumeName, err)
+	}
+	return nil
+}
+
+// removeStaleAttachment removes the record of a volume being published to a deregistered node.
+// The caller must hold the orchestrator lock.
+func (o *TridentOrchestrator) removeStaleAttachment(volumeName, nodeName string) error {
+
+	volume, ok := o.volumes[volumeName]
+	if !ok {
+		return fmt.Errorf("could not remove the attachment of volume %s to node %s; volume not found",
+			volumeName, nodeName)
+	}
+
+	// The volume is written even if Trident's record of it doesn't have the node, since the
+	// persisted volume does
+	removed := volume.RemovePublishedNode(nodeName)
+	if err := o.storeClient.UpdateVolume(volume); err != nil {
+		if removed {
+			volume.AddPublishedNode(nodeName)
+		}
+		return fmt.Errorf("could not remove the attachment of volume %s to node %s; %v",
+			volumeName, nodeName, err)
+	}
+	return nil
+}
@@ -0,0 +1,247 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/storage"
+	sa "github.com/netapp/trident/storage_attribute"
+	storageclass "github.com/netapp/trident/storage_class"
+	tu "github.com/netapp/trident/storage_drivers/fake/test_utils"
+	"github.com/netapp/trident/utils"
+)
+
+func TestIsSynchronousTransaction(t *testing.T) {
+	tests := []struct {
+		txn      *storage.VolumeTransaction
+		expected bool
+	}{
+		{&storage.VolumeTransaction{Op: storage.AddVolume}, true},
+		{&storage.VolumeTransaction{Op: storage.AddSnapshot}, true},
+		{&storage.VolumeTransaction{Op: storage.DeleteVolume}, true},
+		{&storage.VolumeTransaction{Op: storage.DeleteVolume, VolumeDeletion: &storage.VolumeDeletion{}}, false},
+		{&storage.VolumeTransaction{Op: storage.VolumeCreating}, false},
+		{&storage.VolumeTransaction{Op: storage.MigrateVolume}, false},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, isSynchronousTransaction(test.txn), string(test.txn.Op))
+	}
+}
+
+func TestCollectOrphanedTransactions(t *testing.T) {
+	orchestrator := getOrchestrator()
+
+	orphan := &storage.VolumeTransaction{
+		Op:     storage.ResizeVolume,
+		Config: &storage.VolumeConfig{Name: "orphan", Size: "1GB"},
+	}
+	active := &storage.VolumeTransaction{
+		Op:     storage.ResizeVolume,
+		Config: &storage.VolumeConfig{Name: "active", Size: "1GB"},
+	}
+	for _, txn := range []*storage.VolumeTransaction{orphan, active} {
+		if err := orchestrator.storeClient.AddVolumeTransaction(txn); err != nil {
+			t.Fatal("Unable to add transaction: ", err)
+		}
+	}
+	orchestrator.setTransactionActive(active, true)
+	orchestrator.SetGarbageCollectionAutoClean(true)
+
+	_, err := orchestrator.GetGarbageReport()
+	assert.True(t, utils.IsNotFoundError(err), "expected no report before collection")
+
+	// An orphan is only reported once it has been seen twice
+	report, err := orchestrator.CollectGarbage()
+	assert.NoError(t, err)
+	assert.Empty(t, report.Garbage)
+
+	report, err = orchestrator.CollectGarbage()
+	assert.NoError(t, err)
+	assert.Empty(t, report.Errors)
+	if assert.Len(t, report.Garbage, 1) {
+		assert.Equal(t, storage.GarbageOrphanedTransaction, report.Garbage[0].Type)
+		assert.Equal(t, "orphan", report.Garbage[0].Volume)
+		assert.Equal(t, storage.ResizeVolume, report.Garbage[0].Operation)
+		assert.True(t, report.Garbage[0].Cleaned)
+	}
+
+	txns, err := orchestrator.storeClient.GetVolumeTransactions()
+	assert.NoError(t, err)
+	if assert.Len(t, txns, 1) {
+		assert.Equal(t, "active", txns[0].Name())
+	}
+
+	saved, err := orchestrator.GetGarbageReport()
+	assert.NoError(t, err)
+	assert.Equal(t, report, saved)
+
+	orchestrator.setTransactionActive(active, false)
+	if err = orchestrator.storeClient.DeleteVolumeTransaction(active); err != nil {
+		t.Fatal("Unable to delete transaction: ", err)
+	}
+	cleanup(t, orchestrator)
+}
+
+func TestCollectStaleObjects(t *testing.T) {
+	const (
+		backendName = "gcBackend"
+		scName      = "gcSC"
+	)
+
+	orchestrator := getOrchestrator()
+	addBackend(t, orchestrator, backendName, config.File)
+
+	if _, err := orchestrator.AddStorageClass(&storageclass.Config{
+		Name:       scName,
+		Attributes: map[string]sa.Request{sa.TestingAttribute: sa.NewBoolRequest(true)},
+	}); err != nil {
+		t.Fatal("Unable to add storage class: ", err)
+	}
+
+	volume, err := orchestrator.AddVolume(ctx(), tu.GenerateVolumeConfig("attached", 1, scName, config.File))
+	if err != nil {
+		t.Fatal("Unable to create volume: ", err)
+	}
+
+	// A snapshot whose volume was deleted
+	staleSnapshot := storage.NewSnapshot(&storage.SnapshotConfig{
+		Name:               "snap",
+		VolumeName:         "deleted",
+		VolumeInternalName: "deleted",
+	}, "", 0)
+	if err = orchestrator.storeClient.AddSnapshot(staleSnapshot); err != nil {
+		t.Fatal("Unable to add snapshot: ", err)
+	}
+
+	// A volume published to a registered node and to a deregistered one
+	if err = orchestrator.storeClient.AddOrUpdateNode(&utils.Node{Name: "registered"}); err != nil {
+		t.Fatal("Unable to add node: ", err)
+	}
+	trackedVolume := orchestrator.volumes[volume.Config.Name]
+	trackedVolume.AddPublishedNode("registered")
+	trackedVolume.AddPublishedNode("deregistered")
+	if err = orchestrator.storeClient.UpdateVolume(trackedVolume); err != nil {
+		t.Fatal("Unable to update volume: ", err)
+	}
+
+	// Without auto-clean, garbage is only reported
+	report, err := orchestrator.CollectGarbage()
+	assert.NoError(t, err)
+	assert.Empty(t, report.Errors)
+	assert.Len(t, report.Garbage, 2)
+	for _, garbage := range report.Garbage {
+		assert.False(t, garbage.Cleaned, garbage.Name)
+	}
+
+	orchestrator.SetGarbageCollectionAutoClean(true)
+	report, err = orchestrator.CollectGarbage()
+	assert.NoError(t, err)
+	assert.Empty(t, report.Errors)
+
+	garbageByType := make(map[storage.GarbageType][]*storage.Garbage)
+	for _, garbage := range report.Garbage {
+		garbageByType[garbage.Type] = append(garbageByType[garbage.Type], garbage)
+	}
+
+	if assert.Len(t, garbageByType[storage.GarbageStaleSnapshot], 1) {
+		assert.Equal(t, staleSnapshot.ID(), garbageByType[storage.GarbageStaleSnapshot][0].Name)
+		assert.True(t, garbageByType[storage.GarbageStaleSnapshot][0].Cleaned)
+	}
+	snapshots, err := orchestrator.storeClient.GetSnapshots()
+	assert.NoError(t, err)
+	assert.Empty(t, snapshots)
+
+	if assert.Len(t, garbageByType[storage.GarbageStaleAttachment], 1) {
+		assert.Equal(t, "deregistered", garbageByType[storage.GarbageStaleAttachment][0].Node)
+		assert.True(t, garbageByType[storage.GarbageStaleAttachment][0].Cleaned)
+	}
+	persistedVolume, err := orchestrator.storeClient.GetVolume(volume.Config.Name)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"registered"}, persistedVolume.PublishedNodes)
+
+	// Nothing is left for the next collection
+	report, err = orchestrator.CollectGarbage()
+	assert.NoError(t, err)
+	assert.Empty(t, report.Garbage)
+
+	if err = orchestrator.storeClient.DeleteNode(&utils.Node{Name: "registered"}); err != nil {
+		t.Fatal("Unable to delete node: ", err)
+	}
+	cleanup(t, orchestrator)
+}
+
+func TestCollectStaleEphemeralVolumes(t *testing.T) {
+	const (
+		backendName = "gcBackend"
+		scName      = "gcSC"
+	)
+
+	orchestrator := getOrchestrator()
+	addBackend(t, orchestrator, backendName, config.File)
+
+	if _, err := orchestrator.AddStorageClass(&storageclass.Config{
+		Name:       scName,
+		Attributes: map[string]sa.Request{sa.TestingAttribute: sa.NewBoolRequest(true)},
+	}); err != nil {
+		t.Fatal("Unable to add storage class: ", err)
+	}
+	if err := orchestrator.storeClient.AddOrUpdateNode(&utils.Node{Name: "registered"}); err != nil {
+		t.Fatal("Unable to add node: ", err)
+	}
+
+	// Inline ephemeral volumes of a registered node and of a deregistered one
+	for volumeName, nodeName := range map[string]string{"live": "registered", "stale": "deregistered"} {
+		volumeConfig := tu.GenerateVolumeConfig(volumeName, 1, scName, config.File)
+		volumeConfig.EphemeralNode = nodeName
+		if _, err := orchestrator.AddVolume(ctx(), volumeConfig); err != nil {
+			t.Fatal("Unable to create volume: ", err)
+		}
+		volume := orchestrator.volumes[volumeName]
+		volume.AddPublishedNode(nodeName)
+		if err := orchestrator.storeClient.UpdateVolume(volume); err != nil {
+			t.Fatal("Unable to update volume: ", err)
+		}
+	}
+
+	// Without auto-clean, garbage is only reported
+	report, err := orchestrator.CollectGarbage()
+	assert.NoError(t, err)
+	assert.Empty(t, report.Errors)
+	if assert.Len(t, report.Garbage, 1) {
+		assert.Equal(t, storage.GarbageStaleEphemeralVolume, report.Garbage[0].Type)
+		assert.Equal(t, "stale", report.Garbage[0].Volume)
+		assert.False(t, report.Garbage[0].Cleaned)
+	}
+
+	// The stale volume is unpublished and queued for deletion
+	orchestrator.SetGarbageCollectionAutoClean(true)
+	report, err = orchestrator.CollectGarbage()
+	assert.NoError(t, err)
+	assert.Empty(t, report.Errors)
+	if assert.Len(t, report.Garbage, 1) {
+		assert.True(t, report.Garbage[0].Cleaned)
+	}
+	_, ok := orchestrator.pendingDeletions["stale"]
+	assert.True(t, ok, "stale ephemeral volume should be queued for deletion")
+
+	orchestrator.retryPendingDeletions()
+	_, err = orchestrator.GetVolume("stale")
+	assert.True(t, utils.IsNotFoundError(err), "stale ephemeral volume should be deleted")
+	_, err = orchestrator.GetVolume("live")
+	assert.NoError(t, err)
+
+	// Nothing is left for the next collection
+	report, err = orchestrator.CollectGarbage()
+	assert.NoError(t, err)
+	assert.Empty(t, report.Garbage)
+
+	if err = orchestrator.storeClient.DeleteNode(&utils.Node{Name: "registered"}); err != nil {
+		t.Fatal("Unable to delete node: ", err)
+	}
+	cleanup(t, orchestrator)
+}
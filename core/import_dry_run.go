@@ -0,0 +1,61 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netapp/trident/storage"
+	drivers "github.com/netapp/trident/storage_drivers"
+	"github.com/netapp/trident/utils"
+)
+
+// ImportVolumeDryRun runs all of the checks for importing a volume, and reports the volume the import
+// would create along with the changes it would make on the storage system, without renaming the
+// volume, persisting anything, or starting a transaction.
+func (o *TridentOrchestrator) ImportVolumeDryRun(
+	volumeConfig *storage.VolumeConfig,
+) (result *storage.ImportVolumeDryRunResult, err error) {
+
+	if o.bootstrapError != nil {
+		return nil, o.bootstrapError
+	}
+	if volumeConfig.ImportBackendUUID == "" {
+		return nil, fmt.Errorf("no backend specified for import")
+	}
+	if volumeConfig.ImportOriginalName == "" {
+		return nil, fmt.Errorf("original name not specified")
+	}
+
+	defer recordTiming("volume_import_dry_run", &err)()
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	log.WithFields(log.Fields{
+		"volumeConfig": volumeConfig,
+		"backendUUID":  volumeConfig.ImportBackendUUID,
+	}).Debug("Orchestrator#ImportVolumeDryRun")
+
+	backend, ok := o.backends[volumeConfig.ImportBackendUUID]
+	if !ok {
+		return nil, utils.NotFoundError(fmt.Sprintf("backend %s not found", volumeConfig.ImportBackendUUID))
+	}
+
+	if err = o.validateImportVolume(volumeConfig); err != nil {
+		return nil, err
+	}
+
+	changes, err := backend.ImportVolumeDryRun(volumeConfig, o.getImportStoragePool(volumeConfig, backend))
+	if err != nil {
+		return nil, fmt.Errorf("volume %s on backend %s cannot be imported: %v", volumeConfig.ImportOriginalName,
+			backend.Name, err)
+	}
+
+	volExternal := storage.NewVolume(volumeConfig, backend.BackendUUID, drivers.UnsetPool, false).ConstructExternal()
+	volExternal.Backend = backend.Name
+
+	return &storage.ImportVolumeDryRunResult{Volume: volExternal, Changes: changes}, nil
+}
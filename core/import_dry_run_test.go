@@ -0,0 +1,60 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netapp/trident/config"
+)
+
+func TestImportVolumeDryRun(t *testing.T) {
+	const (
+		backendName     = "dryRunBackend"
+		scName          = "dryRunSC"
+		volumeName      = "dryRunVolume"
+		originalName    = "origVolume01"
+		backendProtocol = config.File
+	)
+
+	orchestrator, volumeConfig := importVolumeSetup(t, backendName, scName, volumeName, originalName, backendProtocol)
+	defer cleanup(t, orchestrator)
+
+	result, err := orchestrator.ImportVolumeDryRun(volumeConfig)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	assert.Equal(t, volumeName, result.Volume.Config.Name)
+	assert.Equal(t, backendName, result.Volume.Backend)
+	assert.Equal(t, "1000000000", result.Volume.Config.Size)
+	assert.Len(t, result.Changes, 1)
+	assert.Contains(t, result.Changes[0], originalName)
+
+	// Nothing was renamed or persisted
+	backend, err := orchestrator.getBackendByBackendName(backendName)
+	assert.NoError(t, err)
+	_, err = backend.Driver.GetVolumeExternal(originalName)
+	assert.NoError(t, err, "volume should not have been renamed")
+	_, ok := orchestrator.volumes[volumeName]
+	assert.False(t, ok, "volume should not have been added")
+	persistedVolume, _ := orchestrator.storeClient.GetVolume(volumeName)
+	assert.Nil(t, persistedVolume, "volume should not have been persisted")
+	transactions, _ := orchestrator.storeClient.GetVolumeTransactions()
+	assert.Empty(t, transactions, "no transaction should have been added")
+
+	// An unmanaged import changes nothing
+	notManagedVolConfig := volumeConfig.ConstructClone()
+	notManagedVolConfig.ImportNotManaged = true
+	result, err = orchestrator.ImportVolumeDryRun(notManagedVolConfig)
+	assert.NoError(t, err)
+	assert.Empty(t, result.Changes)
+	assert.Equal(t, originalName, result.Volume.Config.InternalName)
+
+	// A missing volume fails the checks
+	missingVolConfig := volumeConfig.ConstructClone()
+	missingVolConfig.ImportOriginalName = "missing"
+	_, err = orchestrator.ImportVolumeDryRun(missingVolConfig)
+	assert.Error(t, err)
+}
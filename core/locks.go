@@ -0,0 +1,202 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netapp/trident/storage"
+)
+
+// Locking order
+//
+// The orchestrator mutex protects Trident's in-memory state and is never held while waiting
+// for a slow storage operation in the volume create and delete paths.  Those paths instead hold
+// a lock on the volume's name for the whole operation and a shared lock on the volume's backend
+// while calling its driver.  Operations that delete or change the state of a backend take its
+// lock exclusively, so a backend is never changed under a running driver call.
+//
+// Updating a backend doesn't wait for its running driver calls.  The update replaces the backend
+// with a new instance of the next generation, which new operations use at once, while the running
+// calls finish on the old instance.  The old instance is terminated when its last call finishes.
+//
+// Locks are always acquired in this order, and a goroutine holding a backend lock never waits
+// for the orchestrator mutex:
+//
+//   volume lock -> orchestrator mutex -> backend lock
+//
+// The health mutex only guards the backends' health, which is recorded with or without the
+// orchestrator mutex, so it is always acquired last.
+//
+// The number of driver calls made without the orchestrator mutex is bounded by the operation
+// slots, so a burst of requests can't overwhelm the backends.
+
+const defaultMaxConcurrentVolumeOperations = 10
+
+// lockTable provides a read/write lock for each of a set of names, such as volumes or backends.
+// Locks are created on demand and discarded when no goroutine holds or waits for them.
+type lockTable struct {
+	mutex sync.Mutex
+	locks map[string]*namedLock
+}
+
+type namedLock struct {
+	sync.RWMutex
+	refs int
+}
+
+func newLockTable() *lockTable {
+	return &lockTable{locks: make(map[string]*namedLock)}
+}
+
+// acquire returns the lock for a name, creating it if necessary, and records that it is in use.
+func (t *lockTable) acquire(name string) *namedLock {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	lock, ok := t.locks[name]
+	if !ok {
+		lock = &namedLock{}
+		t.locks[name] = lock
+	}
+	lock.refs++
+	return lock
+}
+
+// release records that a lock is no longer in use, and discards it if nothing else uses it.
+func (t *lockTable) release(name string) *namedLock {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	lock := t.locks[name]
+	lock.refs--
+	if lock.refs == 0 {
+		delete(t.locks, name)
+	}
+	return lock
+}
+
+// Lock locks a name exclusively.
+func (t *lockTable) Lock(name string) {
+	t.acquire(name).Lock()
+}
+
+// Unlock releases an exclusive lock on a name.
+func (t *lockTable) Unlock(name string) {
+	t.release(name).Unlock()
+}
+
+// RLock locks a name for shared use.
+func (t *lockTable) RLock(name string) {
+	t.acquire(name).RLock()
+}
+
+// RUnlock releases a shared lock on a name.
+func (t *lockTable) RUnlock(name string) {
+	t.release(name).RUnlock()
+}
+
+// len returns the number of names that are locked or waited for.
+func (t *lockTable) len() int {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	return len(t.locks)
+}
+
+// SetMaxConcurrentVolumeOperations sets the number of volume creates and deletes whose storage
+// operations may run at the same time.
+func (o *TridentOrchestrator) SetMaxConcurrentVolumeOperations(max int) {
+	if max < 1 {
+		max = 1
+	}
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	o.operationSlots = make(chan struct{}, max)
+}
+
+// withBackendUnlocked runs a storage operation on a backend without holding the orchestrator
+// mutex, which the caller must hold.  The operation holds a shared lock on the backend and one of
+// the operation slots, and the backend can't be deleted or terminated while it runs.  The mutex is
+// held again when this returns, at which point the backend may have been updated, so callers should
+// use currentBackend to find its latest instance.
+func (o *TridentOrchestrator) withBackendUnlocked(backend *storage.Backend, operation func()) {
+
+	backendUUID := backend.BackendUUID
+	o.pendingBackendOperations[backendUUID]++
+	o.instanceOperations[backend]++
+	slots := o.operationSlots
+	o.mutex.Unlock()
+
+	slots <- struct{}{}
+	o.backendLocks.RLock(backendUUID)
+
+	defer func() {
+		o.backendLocks.RUnlock(backendUUID)
+		<-slots
+
+		o.mutex.Lock()
+		o.pendingBackendOperations[backendUUID]--
+		if o.pendingBackendOperations[backendUUID] == 0 {
+			delete(o.pendingBackendOperations, backendUUID)
+		}
+		o.instanceOperations[backend]--
+		if o.instanceOperations[backend] == 0 {
+			delete(o.instanceOperations, backend)
+			if o.retiredBackends[backend] {
+				delete(o.retiredBackends, backend)
+				log.WithFields(log.Fields{
+					"backend":    backend.Name,
+					"generation": backend.Generation,
+				}).Debug("Terminating retired backend instance.")
+				backend.Terminate()
+			}
+		}
+	}()
+
+	operation()
+}
+
+// currentBackend returns the latest instance of a backend, which differs from the given instance
+// if the backend was updated, or the given instance if the backend no longer exists.  The caller
+// must hold the orchestrator mutex.
+func (o *TridentOrchestrator) currentBackend(backend *storage.Backend) *storage.Backend {
+	if current, ok := o.backends[backend.BackendUUID]; ok {
+		return current
+	}
+	return backend
+}
+
+// retireBackend terminates an instance of a backend that has been replaced by an update, or, if
+// storage operations are still running on it, arranges for it to be terminated once they finish.
+// The caller must hold the orchestrator mutex.
+func (o *TridentOrchestrator) retireBackend(backend *storage.Backend) {
+	if operations := o.instanceOperations[backend]; operations > 0 {
+		log.WithFields(log.Fields{
+			"backend":    backend.Name,
+			"generation": backend.Generation,
+			"operations": operations,
+		}).Debug("Retiring backend instance after its running operations finish.")
+		o.retiredBackends[backend] = true
+		return
+	}
+	backend.Terminate()
+}
+
+// lockBackendExclusive waits for the running storage operations on a backend to finish and locks
+// it, so it can be deleted or have its state changed.  The caller must hold the
+// orchestrator mutex and call the returned function to release the backend.
+func (o *TridentOrchestrator) lockBackendExclusive(backendUUID string) func() {
+	o.backendLocks.Lock(backendUUID)
+	return func() { o.backendLocks.Unlock(backendUUID) }
+}
+
+// volumeDeleteInProgress returns true if a volume's storage is being destroyed, or its deletion
+// failed and is queued for retry.  The caller should hold the orchestrator lock.
+func (o *TridentOrchestrator) volumeDeleteInProgress(volumeName string) bool {
+	_, pending := o.pendingDeletions[volumeName]
+	return o.deletingVolumes[volumeName] || pending
+}
@@ -0,0 +1,210 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/storage"
+	sa "github.com/netapp/trident/storage_attribute"
+	storageclass "github.com/netapp/trident/storage_class"
+	tu "github.com/netapp/trident/storage_drivers/fake/test_utils"
+)
+
+func TestLockTable(t *testing.T) {
+
+	locks := newLockTable()
+
+	locks.Lock("a")
+	// Other names aren't blocked
+	locks.Lock("b")
+	locks.Unlock("b")
+
+	acquired := make(chan struct{})
+	go func() {
+		locks.Lock("a")
+		close(acquired)
+		locks.Unlock("a")
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("Lock acquired while held by another goroutine.")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	locks.Unlock("a")
+	<-acquired
+
+	// Shared locks don't block each other
+	locks.RLock("c")
+	locks.RLock("c")
+	locks.RUnlock("c")
+	locks.RUnlock("c")
+
+	assert.Equal(t, 0, locks.len(), "unused locks should be discarded")
+}
+
+// isLocked reports whether a lock is held, by trying to take it from another goroutine.  If the lock
+// is held, that goroutine takes and releases it once it is unlocked.
+func isLocked(lock sync.Locker) bool {
+
+	acquired := make(chan struct{})
+	go func() {
+		lock.Lock()
+		close(acquired)
+		lock.Unlock()
+	}()
+
+	select {
+	case <-acquired:
+		return false
+	case <-time.After(50 * time.Millisecond):
+		return true
+	}
+}
+
+func TestWithBackendUnlocked(t *testing.T) {
+
+	orchestrator := NewTridentOrchestrator(nil)
+	backend := &storage.Backend{BackendUUID: "backendUUID"}
+
+	orchestrator.mutex.Lock()
+	orchestrator.withBackendUnlocked(backend, func() {
+		assert.False(t, isLocked(orchestrator.mutex), "orchestrator mutex should not be held")
+		orchestrator.mutex.Lock()
+		assert.Equal(t, 1, orchestrator.pendingBackendOperations["backendUUID"])
+		assert.Equal(t, 1, orchestrator.instanceOperations[backend])
+		orchestrator.mutex.Unlock()
+	})
+	assert.True(t, isLocked(orchestrator.mutex), "orchestrator mutex should be held again")
+	assert.Empty(t, orchestrator.pendingBackendOperations)
+	assert.Empty(t, orchestrator.instanceOperations)
+	orchestrator.mutex.Unlock()
+}
+
+func TestBackendUpdateDuringOperation(t *testing.T) {
+	const backendName = "generationBackend"
+
+	orchestrator := getOrchestrator()
+	addBackend(t, orchestrator, backendName, config.File)
+
+	original, err := orchestrator.getBackendByBackendName(backendName)
+	if err != nil {
+		t.Fatal("Unable to get backend: ", err)
+	}
+	configJSON, err := original.ConstructPersistent().MarshalConfig()
+	if err != nil {
+		t.Fatal("Unable to marshal backend config: ", err)
+	}
+
+	// Hold a storage operation on the original instance of the backend
+	started := make(chan struct{})
+	release := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		orchestrator.mutex.Lock()
+		orchestrator.withBackendUnlocked(original, func() {
+			close(started)
+			<-release
+		})
+		orchestrator.mutex.Unlock()
+		close(finished)
+	}()
+	<-started
+
+	// The update doesn't wait for the operation, which finishes on the original instance
+	updated, err := orchestrator.UpdateBackend(backendName, configJSON)
+	if assert.NoError(t, err) {
+		assert.Equal(t, int64(1), updated.Generation)
+	}
+	current, err := orchestrator.getBackendByBackendName(backendName)
+	if err != nil {
+		t.Fatal("Unable to get backend: ", err)
+	}
+	assert.NotEqual(t, original, current, "the backend should be a new instance")
+	assert.True(t, original.Driver.Initialized(), "the original instance should run until its operation ends")
+
+	close(release)
+	<-finished
+
+	orchestrator.mutex.Lock()
+	assert.False(t, original.Driver.Initialized(), "the original instance should be terminated")
+	assert.True(t, current.Driver.Initialized())
+	assert.Empty(t, orchestrator.retiredBackends)
+	assert.Equal(t, current, orchestrator.currentBackend(original))
+	orchestrator.mutex.Unlock()
+
+	persistentBackend, err := orchestrator.storeClient.GetBackend(backendName)
+	if assert.NoError(t, err) {
+		assert.Equal(t, int64(1), persistentBackend.Generation, "the generation should be persisted")
+	}
+
+	cleanup(t, orchestrator)
+}
+
+func TestConcurrentVolumeOperations(t *testing.T) {
+	const scName = "concurrentSC"
+
+	orchestrator := getOrchestrator()
+	orchestrator.SetMaxConcurrentVolumeOperations(2)
+	for _, backendName := range []string{"concurrentBackend1", "concurrentBackend2"} {
+		addBackend(t, orchestrator, backendName, config.File)
+	}
+	if _, err := orchestrator.AddStorageClass(&storageclass.Config{
+		Name:       scName,
+		Attributes: map[string]sa.Request{sa.TestingAttribute: sa.NewBoolRequest(true)},
+	}); err != nil {
+		t.Fatal("Unable to add storage class: ", err)
+	}
+
+	const volumeCount = 8
+	errors := make(chan error, 2*volumeCount)
+	var wg sync.WaitGroup
+
+	for i := 0; i < volumeCount; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			volumeName := fmt.Sprintf("concurrent%d", i)
+			_, err := orchestrator.AddVolume(ctx(), tu.GenerateVolumeConfig(volumeName, 1, scName, config.File))
+			errors <- err
+		}(i)
+	}
+	wg.Wait()
+
+	volumes, err := orchestrator.ListVolumes()
+	assert.NoError(t, err)
+	assert.Len(t, volumes, volumeCount)
+
+	for i := 0; i < volumeCount; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errors <- orchestrator.DeleteVolume(ctx(), fmt.Sprintf("concurrent%d", i))
+		}(i)
+	}
+	wg.Wait()
+	close(errors)
+
+	for err := range errors {
+		assert.NoError(t, err)
+	}
+
+	volumes, err = orchestrator.ListVolumes()
+	assert.NoError(t, err)
+	assert.Empty(t, volumes)
+	assert.Empty(t, orchestrator.pendingBackendOperations)
+	assert.Empty(t, orchestrator.deletingVolumes)
+	for _, backend := range orchestrator.backends {
+		assert.False(t, backend.HasVolumes(), "backend %s should have no volumes", backend.Name)
+	}
+
+	cleanup(t, orchestrator)
+}
@@ -111,4 +111,104 @@ var (
 		},
 		[]string{"op", "success"},
 	)
+	volumeOperationDurationHistogram = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: config.OrchestratorName,
+			Subsystem: "core",
+			Name:      "volume_operation_duration_seconds",
+			Help:      "The duration of volume create, clone, import, delete, and resize operations",
+			Buckets:   prometheus.ExponentialBuckets(0.25, 2, 12),
+		},
+		[]string{"op", "success"},
+	)
+	backendOperationsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: config.OrchestratorName,
+			Subsystem: "core",
+			Name:      "backend_operation_total",
+			Help:      "The total number of storage operations by backend and outcome",
+		},
+		[]string{"backend", "type", "op", "success"},
+	)
+	backendStateGauge = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: config.OrchestratorName,
+			Subsystem: "core",
+			Name:      "backend_state",
+			Help:      "The state of each backend, as a gauge set to 1 for the current state",
+		},
+		[]string{"backend", "type", "state"},
+	)
+	backendProvisionedBytesGauge = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: config.OrchestratorName,
+			Subsystem: "core",
+			Name:      "backend_provisioned_bytes",
+			Help:      "The total number of bytes provisioned in volumes on each backend",
+		},
+		[]string{"backend", "type"},
+	)
+	volumeUsedBytesGauge = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: config.OrchestratorName,
+			Subsystem: "core",
+			Name:      "volume_used_bytes",
+			Help:      "The number of bytes used in each volume, as last reported by its driver or node",
+		},
+		[]string{"volume", "backend"},
+	)
+	volumeAvailableBytesGauge = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: config.OrchestratorName,
+			Subsystem: "core",
+			Name:      "volume_available_bytes",
+			Help:      "The number of bytes available in each volume, as last reported by its driver or node",
+		},
+		[]string{"volume", "backend"},
+	)
+	backendUsedBytesGauge = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: config.OrchestratorName,
+			Subsystem: "core",
+			Name:      "backend_used_bytes",
+			Help:      "The total number of bytes used in the volumes on each backend",
+		},
+		[]string{"backend", "type"},
+	)
+	driftGauge = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: config.OrchestratorName,
+			Subsystem: "core",
+			Name:      "drift_count",
+			Help:      "The number of unrepaired discrepancies between Trident and each backend, by type",
+		},
+		[]string{"backend", "type"},
+	)
+	garbageGauge = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: config.OrchestratorName,
+			Subsystem: "core",
+			Name:      "garbage_count",
+			Help:      "The number of uncleaned objects found by the last garbage collection, by type",
+		},
+		[]string{"type"},
+	)
+	volumeDeletionsByStateGauge = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: config.OrchestratorName,
+			Subsystem: "core",
+			Name:      "volume_deletion_retry_count",
+			Help:      "The number of volume deletions queued for retry, by state",
+		},
+		[]string{"state"},
+	)
 )
+
+// volumeOperations are the core operations whose latency is also recorded in volumeOperationDurationHistogram.
+var volumeOperations = map[string]struct{}{
+	"volume_add":    {},
+	"volume_clone":  {},
+	"volume_import": {},
+	"volume_delete": {},
+	"volume_resize": {},
+}
@@ -0,0 +1,143 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"reflect"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netapp/trident/utils"
+)
+
+const nodePrunePeriod = 5 * time.Minute
+
+// SetNodeTTL sets how long a node may go without registering with the controller before it is
+// deregistered.  A TTL of zero disables pruning.
+func (o *TridentOrchestrator) SetNodeTTL(ttl time.Duration) {
+	if ttl < 0 {
+		ttl = 0
+	}
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	o.nodeTTL = ttl
+}
+
+// nodeLastSeen returns the time a node last registered, or the zero time if it is unknown.
+func nodeLastSeen(node *utils.Node) time.Time {
+	lastSeen, err := time.Parse(time.RFC3339, node.LastSeen)
+	if err != nil {
+		return time.Time{}
+	}
+	return lastSeen
+}
+
+// nodeAccessChanged reports whether a node registration changes anything that backends use to
+// grant the node access to volumes.
+func nodeAccessChanged(known, registered *utils.Node) bool {
+	return known.IQN != registered.IQN || !reflect.DeepEqual(known.IPs, registered.IPs)
+}
+
+// updateNodePublishedVolumes refreshes the number of volumes published to a node.  The caller must
+// hold the orchestrator lock.
+func (o *TridentOrchestrator) updateNodePublishedVolumes(node *utils.Node) *utils.Node {
+	node.PublishedVolumes = 0
+	for _, volume := range o.volumes {
+		if volume.IsPublishedToNode(node.Name) {
+			node.PublishedVolumes++
+		}
+	}
+	return node
+}
+
+// pruneStaleNodes deregisters the nodes that haven't registered within the TTL, so that the
+// backends stop granting their initiators and addresses access to volumes.  Staleness is measured
+// from when the controller bootstrapped if that is later, since nodes can't register while it is
+// down, and nodes that still have volumes published to them are kept, so their I/O isn't cut off.
+func (o *TridentOrchestrator) pruneStaleNodes() (pruned []string) {
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	if o.nodeTTL == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	for name, node := range o.nodes {
+		lastSeen := nodeLastSeen(node)
+		if lastSeen.IsZero() {
+			continue
+		}
+		if o.bootstrapTime.After(lastSeen) {
+			lastSeen = o.bootstrapTime
+		}
+		if now.Sub(lastSeen) <= o.nodeTTL {
+			continue
+		}
+
+		logFields := log.Fields{"node": name, "lastSeen": node.LastSeen, "ttl": o.nodeTTL}
+		if published := o.updateNodePublishedVolumes(node).PublishedVolumes; published > 0 {
+			log.WithFields(logFields).WithField("publishedVolumes", published).Warning(
+				"Node stopped registering with the controller but still has published volumes, " +
+					"not deregistering it.")
+			continue
+		}
+
+		if err := o.storeClient.DeleteNode(node); err != nil {
+			log.WithFields(logFields).Errorf("Could not deregister stale node. %v", err)
+			continue
+		}
+		delete(o.nodes, name)
+		pruned = append(pruned, name)
+		log.WithFields(logFields).Warning("Deregistered node that stopped registering with the controller.")
+	}
+
+	if len(pruned) > 0 {
+		o.updateMetrics()
+		if err := o.reconcileNodeAccessOnAllBackends(); err != nil {
+			log.WithField("error", err).Error("Could not reconcile node access after pruning stale nodes.")
+		}
+	}
+	return pruned
+}
+
+// StartNodePruneMonitor starts the thread that deregisters stale nodes.
+func (o *TridentOrchestrator) StartNodePruneMonitor(period time.Duration) {
+
+	o.nodePruneTicker = time.NewTicker(period)
+	o.nodePruneChannel = make(chan struct{})
+
+	go func() {
+		log.Debug("Node prune monitor started.")
+
+		for {
+			select {
+			case tick := <-o.nodePruneTicker.C:
+				log.WithField("tick", tick).Debug("Node prune monitor running.")
+				if o.bootstrapError != nil {
+					log.WithField("error", o.bootstrapError).Errorf("Node prune monitor blocked by bootstrap error.")
+					continue
+				}
+				o.pruneStaleNodes()
+			case <-o.nodePruneChannel:
+				log.Debugf("Node prune monitor stopped.")
+				return
+			}
+		}
+	}()
+}
+
+// StopNodePruneMonitor stops the thread that deregisters stale nodes.
+func (o *TridentOrchestrator) StopNodePruneMonitor() {
+	if o.nodePruneTicker != nil {
+		o.nodePruneTicker.Stop()
+	}
+	if o.nodePruneChannel != nil && !o.nodePruneStopped {
+		close(o.nodePruneChannel)
+		o.nodePruneStopped = true
+	}
+	log.Debug("Node prune monitor stopped.")
+}
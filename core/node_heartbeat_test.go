@@ -0,0 +1,104 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netapp/trident/storage"
+	"github.com/netapp/trident/utils"
+)
+
+func TestNodeAccessChanged(t *testing.T) {
+	known := &utils.Node{Name: "n1", IQN: "iqn.1", IPs: []string{"1.1.1.1"}, LastSeen: "2020-01-01T00:00:00Z"}
+
+	assert.False(t, nodeAccessChanged(known, &utils.Node{Name: "n1", IQN: "iqn.1", IPs: []string{"1.1.1.1"}}))
+	assert.True(t, nodeAccessChanged(known, &utils.Node{Name: "n1", IQN: "iqn.2", IPs: []string{"1.1.1.1"}}))
+	assert.True(t, nodeAccessChanged(known, &utils.Node{Name: "n1", IQN: "iqn.1", IPs: []string{"2.2.2.2"}}))
+}
+
+func TestPruneStaleNodes(t *testing.T) {
+
+	orchestrator := getOrchestrator()
+	defer cleanup(t, orchestrator)
+
+	assert.NoError(t, orchestrator.AddNode(&utils.Node{Name: "fresh", IQN: "iqn.fresh"}))
+	assert.NoError(t, orchestrator.AddNode(&utils.Node{Name: "stale", IQN: "iqn.stale"}))
+
+	node, err := orchestrator.GetNode("fresh")
+	assert.NoError(t, err)
+	assert.False(t, nodeLastSeen(node).IsZero(), "registration should record when the node was seen")
+
+	orchestrator.nodes["stale"].LastSeen = time.Now().Add(-2 * time.Hour).UTC().Format(time.RFC3339)
+
+	// Pruning is disabled without a TTL
+	assert.Empty(t, orchestrator.pruneStaleNodes())
+
+	orchestrator.SetNodeTTL(time.Hour)
+
+	// Nodes get a full TTL from bootstrap, since they can't register while the controller is down
+	orchestrator.bootstrapTime = time.Now().Add(-30 * time.Minute)
+	assert.Empty(t, orchestrator.pruneStaleNodes())
+	orchestrator.bootstrapTime = time.Now().Add(-2 * time.Hour)
+
+	// Nodes that still have volumes published to them are kept
+	orchestrator.volumes["vol1"] = &storage.Volume{PublishedNodes: []string{"stale"}}
+	assert.Empty(t, orchestrator.pruneStaleNodes())
+	delete(orchestrator.volumes, "vol1")
+
+	assert.Equal(t, []string{"stale"}, orchestrator.pruneStaleNodes())
+
+	_, err = orchestrator.GetNode("stale")
+	assert.True(t, utils.IsNotFoundError(err))
+	persistentNodes, err := orchestrator.storeClient.GetNodes()
+	assert.NoError(t, err)
+	assert.Len(t, persistentNodes, 1)
+
+	// A pruned node that comes back registers again
+	assert.NoError(t, orchestrator.AddNode(&utils.Node{Name: "stale", IQN: "iqn.stale"}))
+	_, err = orchestrator.GetNode("stale")
+	assert.NoError(t, err)
+}
+
+func TestBootstrapNodeLastSeen(t *testing.T) {
+
+	orchestrator := getOrchestrator()
+	defer cleanup(t, orchestrator)
+
+	// Nodes stored before heartbeats were tracked get a full TTL from bootstrap
+	assert.NoError(t, orchestrator.storeClient.AddOrUpdateNode(&utils.Node{Name: "legacy", IQN: "iqn.legacy"}))
+
+	newOrchestrator := NewTridentOrchestrator(orchestrator.storeClient)
+	assert.NoError(t, newOrchestrator.Bootstrap())
+	defer newOrchestrator.Stop()
+
+	node, err := newOrchestrator.GetNode("legacy")
+	assert.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), nodeLastSeen(node), time.Minute)
+}
+
+func TestNodePublishedVolumes(t *testing.T) {
+
+	orchestrator := getOrchestrator()
+	defer cleanup(t, orchestrator)
+
+	assert.NoError(t, orchestrator.AddNode(&utils.Node{Name: "node1", IQN: "iqn.node1"}))
+	assert.NoError(t, orchestrator.AddNode(&utils.Node{Name: "node2", IQN: "iqn.node2"}))
+	orchestrator.volumes["vol1"] = &storage.Volume{PublishedNodes: []string{"node1"}}
+	orchestrator.volumes["vol2"] = &storage.Volume{PublishedNodes: []string{"node1", "node2"}}
+
+	node, err := orchestrator.GetNode("node1")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, node.PublishedVolumes)
+
+	nodes, err := orchestrator.ListNodes()
+	assert.NoError(t, err)
+	counts := make(map[string]int)
+	for _, node := range nodes {
+		counts[node.Name] = node.PublishedVolumes
+	}
+	assert.Equal(t, map[string]int{"node1": 2, "node2": 1}, counts)
+}
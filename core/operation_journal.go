@@ -0,0 +1,120 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"context"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/storage"
+)
+
+/////////////////////////////////////////////////////////////////////////////
+//
+// This file contains the handling of operation journals, which record the
+// steps a driver takes to create a volume in the volume's AddVolume
+// transaction.  If the create is interrupted, the journal says which backend
+// and which steps were involved, so the create is undone step by step on
+// that backend alone, or, if it had already succeeded on the backend,
+// finished by registering the volume, rather than deleting a volume of that
+// name on every backend.
+//
+/////////////////////////////////////////////////////////////////////////////
+
+// newVolumeJournal returns a journal for creating a volume in a pool, which is saved by updating
+// the volume's transaction.
+func (o *TridentOrchestrator) newVolumeJournal(
+	txn *storage.VolumeTransaction, backend *storage.Backend, pool *storage.Pool,
+) *storage.OperationJournal {
+	return storage.NewOperationJournal(backend.BackendUUID, pool.Name, func() error {
+		return o.storeClient.UpdateVolumeTransaction(txn)
+	})
+}
+
+// rollbackVolumeJournal undoes the steps of a failed volume create on its backend, or destroys the
+// volume if the backend's driver doesn't journal its steps.  The caller should hold the orchestrator lock.
+func (o *TridentOrchestrator) rollbackVolumeJournal(
+	backend *storage.Backend, volConfig *storage.VolumeConfig, journal *storage.OperationJournal,
+) error {
+
+	logFields := log.Fields{
+		"volume":  volConfig.Name,
+		"backend": backend.Name,
+		"steps":   len(journal.Steps),
+	}
+
+	// The rollback doesn't use the caller's context, which may be why the create failed
+	ctx, cancel := o.operationContext(context.Background(), OperationDeleteVolume)
+	defer cancel()
+
+	if err := backend.RollbackVolume(ctx, volConfig, journal); err != nil {
+		log.WithFields(logFields).WithField("error", err).Warning("Could not roll back volume create.")
+		return fmt.Errorf("could not roll back the create of volume %s on backend %s; %v",
+			volConfig.Name, backend.Name, err)
+	}
+
+	log.WithFields(logFields).Info("Rolled back volume create.")
+
+	return journal.Reset()
+}
+
+// recoverJournaledVolume finishes or undoes a volume create that was interrupted after its journal
+// was started.  A create that succeeded on its backend is finished by registering the volume, so a
+// retry of the create finds it, and any other create is rolled back.  The transaction is left in
+// place if its backend isn't available, so that recovery is attempted again at the next restart.
+// The caller should hold the orchestrator lock.
+func (o *TridentOrchestrator) recoverJournaledVolume(txn *storage.VolumeTransaction) (bool, error) {
+
+	journal := txn.Journal
+	logFields := log.Fields{
+		"volume":      txn.Config.Name,
+		"backendUUID": journal.BackendUUID,
+		"committed":   journal.Committed,
+		"steps":       len(journal.Steps),
+	}
+
+	backend, ok := o.backends[journal.BackendUUID]
+	if !ok {
+		log.WithFields(logFields).Warning("Backend of interrupted volume create not found.")
+		return true, nil
+	}
+	if !backend.State.IsServing() && !backend.State.IsDeleting() {
+		log.WithFields(logFields).Warning("Backend of interrupted volume create is offline, " +
+			"recovery will be retried at the next restart.")
+		return false, nil
+	}
+
+	// A create that was committed and persisted needs only its transaction removed
+	if _, ok := o.volumes[txn.Config.Name]; ok && journal.Committed {
+		log.WithFields(logFields).Info("Interrupted volume create was already finished.")
+		return true, nil
+	}
+
+	if journal.Committed {
+		vol := storage.NewVolume(txn.Config, backend.BackendUUID, journal.Pool, false)
+		if vol.Config.Protocol == config.ProtocolAny {
+			vol.Config.Protocol = backend.GetProtocol()
+		}
+		if err := o.storeClient.AddVolume(vol); err != nil {
+			return false, fmt.Errorf("unable to finish creating volume %s: %v", txn.Config.Name, err)
+		}
+		o.volumes[vol.Config.Name] = vol
+		backend.AddCachedVolume(vol)
+
+		log.WithFields(logFields).Info("Finished interrupted volume create.")
+		return true, nil
+	}
+
+	// A driver that journals its steps took none, so there is nothing to undo
+	if _, ok := backend.Driver.(storage.JournalingDriver); ok && len(journal.Steps) == 0 {
+		return true, nil
+	}
+
+	if err := o.rollbackVolumeJournal(backend, txn.Config, journal); err != nil {
+		return false, err
+	}
+	return true, nil
+}
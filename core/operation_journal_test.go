@@ -0,0 +1,127 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/storage"
+	fakedriver "github.com/netapp/trident/storage_drivers/fake"
+	tu "github.com/netapp/trident/storage_drivers/fake/test_utils"
+)
+
+// addJournaledVolumeTransaction stores the transaction of a create on a backend that was
+// interrupted after the journal recorded the volume's create step.
+func addJournaledVolumeTransaction(
+	t *testing.T, o *TridentOrchestrator, backendName, volumeName, scName string, committed bool,
+) *storage.VolumeConfig {
+
+	backend, err := o.getBackendByBackendName(backendName)
+	require.NoError(t, err)
+
+	volumeConfig := tu.GenerateVolumeConfig(volumeName, 1, scName, config.File)
+	volumeConfig.InternalName = backend.Driver.GetInternalVolumeName(volumeName)
+
+	journal := storage.NewOperationJournal(backend.BackendUUID, "primary", nil)
+	require.NoError(t, journal.Begin("createVolume", volumeConfig.InternalName))
+	journal.Complete("createVolume", volumeConfig.InternalName)
+	journal.Committed = committed
+
+	txn := &storage.VolumeTransaction{Config: volumeConfig, Op: storage.AddVolume, Journal: journal}
+	require.NoError(t, o.storeClient.AddVolumeTransaction(txn))
+	return volumeConfig
+}
+
+func TestJournaledAddVolumeRollback(t *testing.T) {
+	const (
+		backendName      = "journalRollbackBackend"
+		otherBackendName = "journalRollbackOtherBackend"
+		scName           = "journalRollbackSC"
+		volumeName       = "journalRollbackVolume"
+	)
+
+	orchestrator := getOrchestrator()
+	defer cleanup(t, orchestrator)
+	prepRecoveryTest(t, orchestrator, backendName, scName)
+	addBackend(t, orchestrator, otherBackendName, config.File)
+
+	volumeConfig := addJournaledVolumeTransaction(t, orchestrator, backendName, volumeName, scName, false)
+
+	// The create is undone on its own backend only
+	newOrchestrator := getOrchestrator()
+	newOrchestrator.mutex.Lock()
+	defer newOrchestrator.mutex.Unlock()
+
+	backend, err := newOrchestrator.getBackendByBackendName(backendName)
+	require.NoError(t, err)
+	assert.True(t, backend.Driver.(*fakedriver.StorageDriver).DestroyedVolumes[volumeConfig.InternalName])
+
+	otherBackend, err := newOrchestrator.getBackendByBackendName(otherBackendName)
+	require.NoError(t, err)
+	assert.False(t, otherBackend.Driver.(*fakedriver.StorageDriver).DestroyedVolumes[volumeConfig.InternalName])
+
+	assert.NotContains(t, newOrchestrator.volumes, volumeName)
+	txns, err := newOrchestrator.storeClient.GetVolumeTransactions()
+	require.NoError(t, err)
+	assert.Empty(t, txns)
+}
+
+func TestJournaledAddVolumeRollForward(t *testing.T) {
+	const (
+		backendName = "journalRollForwardBackend"
+		scName      = "journalRollForwardSC"
+		volumeName  = "journalRollForwardVolume"
+	)
+
+	orchestrator := getOrchestrator()
+	defer cleanup(t, orchestrator)
+	prepRecoveryTest(t, orchestrator, backendName, scName)
+
+	addJournaledVolumeTransaction(t, orchestrator, backendName, volumeName, scName, true)
+
+	// A create that succeeded on its backend is finished
+	newOrchestrator := getOrchestrator()
+	newOrchestrator.mutex.Lock()
+	defer newOrchestrator.mutex.Unlock()
+
+	backend, err := newOrchestrator.getBackendByBackendName(backendName)
+	require.NoError(t, err)
+	require.Contains(t, newOrchestrator.volumes, volumeName)
+	assert.Equal(t, backend.BackendUUID, newOrchestrator.volumes[volumeName].BackendUUID)
+	assert.Equal(t, "primary", newOrchestrator.volumes[volumeName].Pool)
+	assert.Contains(t, backend.Volumes, volumeName)
+
+	storedVolume, err := newOrchestrator.storeClient.GetVolume(volumeName)
+	require.NoError(t, err)
+	assert.Equal(t, backend.BackendUUID, storedVolume.BackendUUID)
+
+	txns, err := newOrchestrator.storeClient.GetVolumeTransactions()
+	require.NoError(t, err)
+	assert.Empty(t, txns)
+}
+
+func TestJournaledAddVolume(t *testing.T) {
+	const (
+		backendName = "journalBackend"
+		scName      = "journalSC"
+		volumeName  = "journalVolume"
+	)
+
+	orchestrator := getOrchestrator()
+	defer cleanup(t, orchestrator)
+	prepRecoveryTest(t, orchestrator, backendName, scName)
+
+	_, err := orchestrator.AddVolume(ctx(), tu.GenerateVolumeConfig(volumeName, 1, scName, config.File))
+	require.NoError(t, err)
+
+	// The journal is discarded with the transaction once the volume is registered
+	txns, err := orchestrator.storeClient.GetVolumeTransactions()
+	require.NoError(t, err)
+	assert.Empty(t, txns)
+	_, err = orchestrator.storeClient.GetVolume(volumeName)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,117 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+/////////////////////////////////////////////////////////////////////////////
+//
+// This file contains the per-operation timeouts that bound how long a
+// storage operation may run on a backend.  Each operation's context is
+// derived from its caller's, so the operation is also canceled when the
+// caller, such as a CSI RPC, gives up.  Cleanup of a failed operation uses
+// its own context, as it must run even when the operation was canceled.
+//
+/////////////////////////////////////////////////////////////////////////////
+
+// Names of the operations whose timeouts may be configured
+const (
+	OperationCreateVolume    = "createVolume"
+	OperationCloneVolume     = "cloneVolume"
+	OperationImportVolume    = "importVolume"
+	OperationDeleteVolume    = "deleteVolume"
+	OperationPublishVolume   = "publishVolume"
+	OperationResizeVolume    = "resizeVolume"
+	OperationCreateSnapshot  = "createSnapshot"
+	OperationDeleteSnapshot  = "deleteSnapshot"
+	OperationRestoreSnapshot = "restoreSnapshot"
+)
+
+var defaultOperationTimeouts = map[string]time.Duration{
+	OperationCreateVolume:    10 * time.Minute,
+	OperationCloneVolume:     10 * time.Minute,
+	OperationImportVolume:    10 * time.Minute,
+	OperationDeleteVolume:    5 * time.Minute,
+	OperationPublishVolume:   2 * time.Minute,
+	OperationResizeVolume:    5 * time.Minute,
+	OperationCreateSnapshot:  5 * time.Minute,
+	OperationDeleteSnapshot:  5 * time.Minute,
+	OperationRestoreSnapshot: 10 * time.Minute,
+}
+
+// ParseOperationTimeouts parses a list of operation timeouts, such as createVolume=15m,publishVolume=1m.
+// A timeout of zero lets an operation run until its caller gives up.
+func ParseOperationTimeouts(value string) (map[string]time.Duration, error) {
+
+	timeouts := make(map[string]time.Duration)
+
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return timeouts, nil
+	}
+
+	for _, setting := range strings.Split(value, ",") {
+		parts := strings.SplitN(strings.TrimSpace(setting), "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid operation timeout '%s'", setting)
+		}
+		operation := strings.TrimSpace(parts[0])
+		if _, ok := defaultOperationTimeouts[operation]; !ok {
+			return nil, fmt.Errorf("unknown operation '%s'; must be one of %s", operation,
+				strings.Join(operationNames(), ", "))
+		}
+		timeout, err := time.ParseDuration(strings.TrimSpace(parts[1]))
+		if err != nil || timeout < 0 {
+			return nil, fmt.Errorf("invalid duration in operation timeout '%s'", setting)
+		}
+		timeouts[operation] = timeout
+	}
+
+	return timeouts, nil
+}
+
+func operationNames() []string {
+	names := make([]string, 0, len(defaultOperationTimeouts))
+	for name := range defaultOperationTimeouts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetOperationTimeouts overrides the default timeouts of the operations in the map.
+func (o *TridentOrchestrator) SetOperationTimeouts(timeouts map[string]time.Duration) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	o.operationTimeouts = make(map[string]time.Duration)
+	for operation, timeout := range defaultOperationTimeouts {
+		o.operationTimeouts[operation] = timeout
+	}
+	for operation, timeout := range timeouts {
+		o.operationTimeouts[operation] = timeout
+	}
+}
+
+// operationContext returns the context in which an operation runs on a backend, which ends when
+// either the operation's timeout passes or the caller's context ends.  The caller must call the
+// returned function once the operation is done.
+func (o *TridentOrchestrator) operationContext(
+	ctx context.Context, operation string,
+) (context.Context, context.CancelFunc) {
+
+	timeout, ok := o.operationTimeouts[operation]
+	if !ok {
+		timeout = defaultOperationTimeouts[operation]
+	}
+	if timeout == 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
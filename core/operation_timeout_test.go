@@ -0,0 +1,98 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netapp/trident/config"
+	sa "github.com/netapp/trident/storage_attribute"
+	storageclass "github.com/netapp/trident/storage_class"
+	tu "github.com/netapp/trident/storage_drivers/fake/test_utils"
+)
+
+func TestParseOperationTimeouts(t *testing.T) {
+
+	timeouts, err := ParseOperationTimeouts("")
+	if assert.NoError(t, err) {
+		assert.Empty(t, timeouts)
+	}
+
+	timeouts, err = ParseOperationTimeouts("createVolume=15m, publishVolume=0")
+	if assert.NoError(t, err) {
+		assert.Equal(t, map[string]time.Duration{
+			OperationCreateVolume:  15 * time.Minute,
+			OperationPublishVolume: 0,
+		}, timeouts)
+	}
+
+	for _, value := range []string{"createVolume", "mountVolume=1m", "createVolume=soon", "createVolume=-1m"} {
+		_, err = ParseOperationTimeouts(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestOperationContext(t *testing.T) {
+	orchestrator := getOrchestrator()
+
+	opCtx, cancel := orchestrator.operationContext(ctx(), OperationCreateVolume)
+	deadline, ok := opCtx.Deadline()
+	cancel()
+	if assert.True(t, ok, "operations should time out by default") {
+		assert.WithinDuration(t, time.Now().Add(defaultOperationTimeouts[OperationCreateVolume]), deadline,
+			time.Minute)
+	}
+
+	orchestrator.SetOperationTimeouts(map[string]time.Duration{OperationCreateVolume: 0})
+
+	opCtx, cancel = orchestrator.operationContext(ctx(), OperationCreateVolume)
+	_, ok = opCtx.Deadline()
+	assert.False(t, ok, "a timeout of zero should wait for the caller")
+	cancel()
+	assert.Error(t, opCtx.Err(), "the operation should end with its caller")
+
+	opCtx, cancel = orchestrator.operationContext(ctx(), OperationDeleteVolume)
+	_, ok = opCtx.Deadline()
+	cancel()
+	assert.True(t, ok, "operations that aren't overridden should keep their defaults")
+}
+
+func TestAddVolumeCanceled(t *testing.T) {
+	const (
+		backendName = "cancelBackend"
+		scName      = "cancelSC"
+	)
+
+	orchestrator := getOrchestrator()
+	addBackend(t, orchestrator, backendName, config.File)
+	if _, err := orchestrator.AddStorageClass(&storageclass.Config{
+		Name:       scName,
+		Attributes: map[string]sa.Request{sa.TestingAttribute: sa.NewBoolRequest(true)},
+	}); err != nil {
+		t.Fatal("Unable to add storage class: ", err)
+	}
+
+	canceledCtx, cancel := context.WithCancel(ctx())
+	cancel()
+
+	volumeConfig := tu.GenerateVolumeConfig("canceledVolume", 1, scName, config.File)
+	_, err := orchestrator.AddVolume(canceledCtx, volumeConfig)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "canceled")
+	}
+
+	volume, err := orchestrator.GetVolume(volumeConfig.Name)
+	assert.Error(t, err, "the canceled volume should not exist")
+	assert.Nil(t, volume)
+
+	// The canceled operation leaves nothing behind, so it may simply be retried
+	volumeConfig = tu.GenerateVolumeConfig("canceledVolume", 1, scName, config.File)
+	_, err = orchestrator.AddVolume(ctx(), volumeConfig)
+	assert.NoError(t, err)
+
+	cleanup(t, orchestrator)
+}
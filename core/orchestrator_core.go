@@ -3,9 +3,9 @@
 package core
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"math/rand"
 	"os"
 	"sort"
 	"strconv"
@@ -41,6 +41,9 @@ func recordTiming(operation string, err *error) func() {
 			success = "false"
 		}
 		operationDurationInMsSummary.WithLabelValues(operation, success).Observe(endTimeMS)
+		if _, ok := volumeOperations[operation]; ok {
+			volumeOperationDurationHistogram.WithLabelValues(operation, success).Observe(endTime.Seconds())
+		}
 	}
 }
 
@@ -66,16 +69,96 @@ type TridentOrchestrator struct {
 	backends          map[string]*storage.Backend // key is UUID, not name
 	volumes           map[string]*storage.Volume
 	frontends         map[string]frontend.Plugin
-	mutex             *sync.Mutex
+	mutex             *sync.Mutex // see locks.go for the locking order
 	storageClasses    map[string]*storageclass.StorageClass
 	nodes             map[string]*utils.Node
 	snapshots         map[string]*storage.Snapshot
+	migrations        map[string]*storage.VolumeMigration // key is volume name
 	storeClient       persistentstore.Client
 	bootstrapped      bool
 	bootstrapError    error
+	standby           bool
 	txnMonitorTicker  *time.Ticker
 	txnMonitorChannel chan struct{}
 	txnMonitorStopped bool
+
+	snapshotRetention       *storage.SnapshotRetentionPolicy
+	retentionMonitorTicker  *time.Ticker
+	retentionMonitorChannel chan struct{}
+	retentionMonitorStopped bool
+
+	volumeUsage         map[string]*storage.VolumeUsage // key is volume name
+	usageMonitorTicker  *time.Ticker
+	usageMonitorChannel chan struct{}
+	usageMonitorStopped bool
+
+	// volumeConditions holds the latest condition reported by each source, keyed by volume name
+	volumeConditions map[string]map[storage.VolumeUsageSource]*storage.VolumeCondition
+
+	driftAutoRepair     bool
+	driftReport         *storage.DriftReport
+	driftMutex          sync.Mutex
+	driftMonitorTicker  *time.Ticker
+	driftMonitorChannel chan struct{}
+	driftMonitorStopped bool
+
+	pendingDeletions         map[string]*storage.VolumeTransaction // key is volume name
+	deletionRetryMaxAttempts int
+	deletionMonitorTicker    *time.Ticker
+	deletionMonitorChannel   chan struct{}
+	deletionMonitorStopped   bool
+
+	nodeTTL          time.Duration
+	bootstrapTime    time.Time
+	nodePruneTicker  *time.Ticker
+	nodePruneChannel chan struct{}
+	nodePruneStopped bool
+
+	volumeLocks              *lockTable
+	backendLocks             *lockTable
+	operationSlots           chan struct{}
+	pendingBackendOperations map[string]int  // key is backend UUID
+	deletingVolumes          map[string]bool // key is volume name
+
+	// instanceOperations counts the running storage operations on each instance of a backend, so
+	// an instance replaced by a backend update can be terminated once its operations finish
+	instanceOperations map[*storage.Backend]int
+	retiredBackends    map[*storage.Backend]bool
+
+	cloneGrants map[string]*storage.CloneGrant // key is ID
+
+	quotas            map[string]*storage.Quota        // key is ID
+	quotaReservations map[string]*storage.VolumeConfig // key is volume name
+
+	operationTimeouts map[string]time.Duration // key is operation name
+
+	// hostVolumeAccess is set if this host can mount volumes, so their files can be copied here
+	hostVolumeAccess bool
+
+	backendHealth        map[string]*storage.BackendHealth // key is backend UUID
+	healthMutex          sync.Mutex
+	healthMonitorTicker  *time.Ticker
+	healthMonitorChannel chan struct{}
+	healthMonitorStopped bool
+
+	stateBackup        *StateBackupConfig
+	lastStateBackup    time.Time
+	stateBackupTicker  *time.Ticker
+	stateBackupChannel chan struct{}
+	stateBackupStopped bool
+
+	// activeTransactions holds the names of the transactions of running operations
+	activeTransactions map[string]bool
+	txnMutex           sync.Mutex
+
+	gcAutoClean bool
+	gcReport    *storage.GarbageReport
+	// gcSuspects holds the names of the transactions found orphaned by the previous collection
+	gcSuspects       map[string]bool
+	gcMutex          sync.Mutex
+	gcMonitorTicker  *time.Ticker
+	gcMonitorChannel chan struct{}
+	gcMonitorStopped bool
 }
 
 // NewTridentOrchestrator returns a storage orchestrator instance
@@ -87,10 +170,29 @@ func NewTridentOrchestrator(client persistentstore.Client) *TridentOrchestrator
 		storageClasses: make(map[string]*storageclass.StorageClass),
 		nodes:          make(map[string]*utils.Node),
 		snapshots:      make(map[string]*storage.Snapshot), // key is ID, not name
+		migrations:     make(map[string]*storage.VolumeMigration),
+		volumeUsage:    make(map[string]*storage.VolumeUsage),
+		volumeLocks:    newLockTable(),
+		backendLocks:   newLockTable(),
+		operationSlots: make(chan struct{}, defaultMaxConcurrentVolumeOperations),
 		mutex:          &sync.Mutex{},
 		storeClient:    client,
 		bootstrapped:   false,
 		bootstrapError: utils.NotReadyError(),
+
+		pendingBackendOperations: make(map[string]int),
+		instanceOperations:       make(map[*storage.Backend]int),
+		retiredBackends:          make(map[*storage.Backend]bool),
+		volumeConditions:         make(map[string]map[storage.VolumeUsageSource]*storage.VolumeCondition),
+		deletingVolumes:          make(map[string]bool),
+		pendingDeletions:         make(map[string]*storage.VolumeTransaction),
+		deletionRetryMaxAttempts: defaultDeletionRetryMaxAttempts,
+		cloneGrants:              make(map[string]*storage.CloneGrant),
+		quotas:                   make(map[string]*storage.Quota),
+		quotaReservations:        make(map[string]*storage.VolumeConfig),
+		backendHealth:            make(map[string]*storage.BackendHealth),
+		activeTransactions:       make(map[string]bool),
+		gcSuspects:               make(map[string]bool),
 	}
 }
 
@@ -144,6 +246,7 @@ func (o *TridentOrchestrator) Bootstrap() error {
 	if len(o.frontends) == 0 {
 		log.Warning("Trident is bootstrapping with no frontend.")
 	}
+	o.standby = false
 
 	// Transform persistent state, if necessary
 	if err = o.transformPersistentState(); err != nil {
@@ -160,6 +263,32 @@ func (o *TridentOrchestrator) Bootstrap() error {
 	// Start transaction monitor
 	o.StartTransactionMonitor(txnMonitorPeriod, txnMonitorMaxAge)
 
+	// Start snapshot retention monitor
+	o.StartSnapshotRetentionMonitor(snapshotRetentionPeriod)
+
+	// Start volume usage monitor
+	o.StartVolumeUsageMonitor(volumeUsagePeriod)
+
+	// Start drift monitor
+	o.StartDriftMonitor(driftDetectionPeriod)
+
+	// Start deletion retry monitor
+	o.StartDeletionRetryMonitor(deletionRetryPeriod)
+
+	// Start node prune monitor.  Nodes can't register while the controller is down, so they are given
+	// a full TTL from now before they are considered stale.
+	o.bootstrapTime = time.Now()
+	o.StartNodePruneMonitor(nodePrunePeriod)
+
+	// Start backend health monitor
+	o.StartBackendHealthMonitor(backendHealthPeriod)
+
+	// Start state backup monitor
+	o.StartStateBackupMonitor(stateBackupPeriod)
+
+	// Start garbage collection monitor
+	o.StartGarbageCollectionMonitor(garbageCollectionPeriod)
+
 	o.bootstrapped = true
 	o.bootstrapError = nil
 	log.Infof("%s bootstrapped successfully.", strings.Title(config.OrchestratorName))
@@ -232,12 +361,11 @@ func (o *TridentOrchestrator) bootstrapBackends() error {
 		newBackend, found := o.backends[b.BackendUUID]
 		if found {
 			newBackend.Online = b.Online
+			newBackend.Generation = b.Generation
 			if backendErr != nil {
 				newBackend.State = storage.Failed
-			} else {
-				if b.State == storage.Deleting {
-					newBackend.State = storage.Deleting
-				}
+			} else if b.State.IsDeleting() || b.State.IsCordoned() || b.State.IsDraining() {
+				newBackend.State = b.State
 			}
 			log.WithFields(log.Fields{
 				"backend":                        newBackend.Name,
@@ -302,6 +430,7 @@ func (o *TridentOrchestrator) bootstrapVolumes() error {
 		var ok bool
 		var vol *storage.Volume
 		vol = storage.NewVolume(v.Config, v.BackendUUID, v.Pool, v.Orphaned)
+		vol.PublishedNodes = v.PublishedNodes
 		o.volumes[vol.Config.Name] = vol
 
 		if backend, ok = o.backends[v.BackendUUID]; !ok {
@@ -387,11 +516,16 @@ func (o *TridentOrchestrator) bootstrapNodes() error {
 	if err != nil {
 		return err
 	}
+	now := time.Now().UTC().Format(time.RFC3339)
 	for _, n := range nodes {
 		log.WithFields(log.Fields{
 			"node":    n.Name,
 			"handler": "Bootstrap",
 		}).Info("Added an existing node.")
+		// Nodes registered before heartbeats were tracked are given a full TTL to register again
+		if n.LastSeen == "" {
+			n.LastSeen = now
+		}
 		o.nodes[n.Name] = n
 	}
 	err = o.reconcileNodeAccessOnAllBackends()
@@ -438,6 +572,13 @@ func (o *TridentOrchestrator) bootstrap() error {
 		}
 	}
 
+	// Resume draining any backends that were draining when Trident stopped
+	for _, backend := range o.backends {
+		if backend.State.IsDraining() {
+			o.drainBackend(backend)
+		}
+	}
+
 	// If nothing failed during bootstrapping, initialize the core metrics
 	o.updateMetrics()
 
@@ -449,6 +590,30 @@ func (o *TridentOrchestrator) Stop() {
 
 	// Stop transaction monitor
 	o.StopTransactionMonitor()
+
+	// Stop snapshot retention monitor
+	o.StopSnapshotRetentionMonitor()
+
+	// Stop volume usage monitor
+	o.StopVolumeUsageMonitor()
+
+	// Stop drift monitor
+	o.StopDriftMonitor()
+
+	// Stop deletion retry monitor
+	o.StopDeletionRetryMonitor()
+
+	// Stop node prune monitor
+	o.StopNodePruneMonitor()
+
+	// Stop backend health monitor
+	o.StopBackendHealthMonitor()
+
+	// Stop state backup monitor
+	o.StopStateBackupMonitor()
+
+	// Stop garbage collection monitor
+	o.StopGarbageCollectionMonitor()
 }
 
 // updateMetrics updates the metrics that track the core objects.
@@ -458,9 +623,13 @@ func (o *TridentOrchestrator) updateMetrics() {
 	backendsGauge.Set(float64(len(o.backends)))
 	backendsByTypeGauge.Reset()
 	backendsByStateGauge.Reset()
+	backendStateGauge.Reset()
+	backendProvisionedBytesGauge.Reset()
 	for _, backend := range o.backends {
 		backendsByTypeGauge.WithLabelValues(backend.GetDriverName()).Inc()
 		backendsByStateGauge.WithLabelValues(string(backend.State)).Inc()
+		backendStateGauge.WithLabelValues(backend.Name, backend.GetDriverName(), string(backend.State)).Set(1)
+		backendProvisionedBytesGauge.WithLabelValues(backend.Name, backend.GetDriverName()).Set(0)
 	}
 
 	volumesGauge.Set(float64(len(o.volumes)))
@@ -475,11 +644,52 @@ func (o *TridentOrchestrator) updateMetrics() {
 			driverName := backend.GetDriverName()
 			volumesByBackendGauge.WithLabelValues(driverName).Inc()
 			volumesTotalBytesByBackendGauge.WithLabelValues(driverName).Add(bytes)
+			backendProvisionedBytesGauge.WithLabelValues(backend.Name, driverName).Add(bytes)
 		}
 		volumesByStateGauge.WithLabelValues(string(volume.State)).Inc()
 	}
 	volumesTotalBytesGauge.Set(volumesTotalBytes)
 
+	volumeUsedBytesGauge.Reset()
+	volumeAvailableBytesGauge.Reset()
+	backendUsedBytesGauge.Reset()
+	for volumeName, usage := range o.volumeUsage {
+		volume, ok := o.volumes[volumeName]
+		if !ok {
+			continue
+		}
+		backendName, driverName := "", ""
+		if backend, ok := o.backends[volume.BackendUUID]; ok {
+			backendName, driverName = backend.Name, backend.GetDriverName()
+			backendUsedBytesGauge.WithLabelValues(backendName, driverName).Add(float64(usage.UsedBytes))
+		}
+		volumeUsedBytesGauge.WithLabelValues(volumeName, backendName).Set(float64(usage.UsedBytes))
+		volumeAvailableBytesGauge.WithLabelValues(volumeName, backendName).Set(float64(usage.AvailableBytes))
+	}
+
+	driftGauge.Reset()
+	if o.driftReport != nil {
+		for _, drift := range o.driftReport.Drifts {
+			if !drift.Repaired {
+				driftGauge.WithLabelValues(drift.Backend, string(drift.Type)).Inc()
+			}
+		}
+	}
+
+	garbageGauge.Reset()
+	if o.gcReport != nil {
+		for _, garbage := range o.gcReport.Garbage {
+			if !garbage.Cleaned {
+				garbageGauge.WithLabelValues(string(garbage.Type)).Inc()
+			}
+		}
+	}
+
+	volumeDeletionsByStateGauge.Reset()
+	for _, volTxn := range o.pendingDeletions {
+		volumeDeletionsByStateGauge.WithLabelValues(string(volTxn.VolumeDeletion.State)).Inc()
+	}
+
 	scGauge.Set(float64(len(o.storageClasses)))
 	nodeGauge.Set(float64(len(o.nodes)))
 	snapshotGauge.Set(float64(len(o.snapshots)))
@@ -515,6 +725,13 @@ func (o *TridentOrchestrator) handleFailedTransaction(v *storage.VolumeTransacti
 			"backendUUID": v.VolumeCreatingConfig.BackendUUID,
 			"op":          v.Op,
 		}).Info("Processed volume creating transaction log.")
+	case storage.MigrateVolume:
+		log.WithFields(log.Fields{
+			"volume":             v.Config.Name,
+			"sourceBackend":      v.VolumeMigration.SourceBackend,
+			"destinationBackend": v.VolumeMigration.DestinationBackend,
+			"op":                 v.Op,
+		}).Info("Processed volume migration transaction log.")
 	}
 
 	switch v.Op {
@@ -524,11 +741,19 @@ func (o *TridentOrchestrator) handleFailedTransaction(v *storage.VolumeTransacti
 		// 1) Volume transaction created only
 		// 2) Volume created on backend
 		// 3) Volume created in the store.
-		if _, ok := o.volumes[v.Config.Name]; ok {
+		// Recovery isn't on behalf of a caller, so it runs in a context of its own.
+		// If the create was journaled, it is finished or undone on its own backend instead.
+		ctx, cancel := o.operationContext(context.Background(), OperationDeleteVolume)
+		defer cancel()
+		if v.Journal != nil && (v.Journal.Committed || o.volumes[v.Config.Name] == nil) {
+			if recovered, err := o.recoverJournaledVolume(v); err != nil || !recovered {
+				return err
+			}
+		} else if _, ok := o.volumes[v.Config.Name]; ok {
 			// If the volume was added to the store, we will have loaded the
 			// volume into memory, and we can just delete it normally.
 			// Handles case 3)
-			err := o.deleteVolume(v.Config.Name)
+			err := o.deleteVolume(ctx, v.Config.Name)
 			if err != nil {
 				return fmt.Errorf("unable to clean up volume %s: %v", v.Config.Name, err)
 			}
@@ -542,13 +767,13 @@ func (o *TridentOrchestrator) handleFailedTransaction(v *storage.VolumeTransacti
 			for _, backend := range o.backends {
 				// Backend offlining is serialized with volume creation,
 				// so we can safely skip offline backends.
-				if !backend.State.IsOnline() && !backend.State.IsDeleting() {
+				if !backend.State.IsServing() && !backend.State.IsDeleting() {
 					continue
 				}
 				// Volume deletion is an idempotent operation, so it's safe to
 				// delete an already deleted volume.
 
-				if err := backend.RemoveVolume(v.Config); err != nil {
+				if err := backend.RemoveVolume(ctx, v.Config); err != nil {
 					return fmt.Errorf("error attempting to clean up volume %s from backend %s: %v", v.Config.Name,
 						backend.Name, err)
 				}
@@ -565,10 +790,21 @@ func (o *TridentOrchestrator) handleFailedTransaction(v *storage.VolumeTransacti
 		// it from the backend, we need to take any special measures only when
 		// the volume is still in the persistent store. In this case, the
 		// volume should have been loaded into memory when we bootstrapped.
+		// A deletion that already failed on its backend is queued to be retried
+		// on its schedule.
+		if v.VolumeDeletion != nil {
+			return o.restoreVolumeDeletion(v)
+		}
 		if _, ok := o.volumes[v.Config.Name]; ok {
 
-			err := o.deleteVolume(v.Config.Name)
+			ctx, cancel := o.operationContext(context.Background(), OperationDeleteVolume)
+			err := o.deleteVolume(ctx, v.Config.Name)
+			cancel()
 			if err != nil {
+				if _, ok := o.volumes[v.Config.Name]; ok {
+					_ = o.deferVolumeDeletion(v, err)
+					return nil
+				}
 				log.WithFields(log.Fields{
 					"volume": v.Config.Name,
 					"error":  err,
@@ -590,11 +826,13 @@ func (o *TridentOrchestrator) handleFailedTransaction(v *storage.VolumeTransacti
 		// 1) Snapshot transaction created only
 		// 2) Snapshot created on backend
 		// 3) Snapshot created in persistent store
+		ctx, cancel := o.operationContext(context.Background(), OperationDeleteSnapshot)
+		defer cancel()
 		if _, ok := o.snapshots[v.SnapshotConfig.ID()]; ok {
 			// If the snapshot was added to the store, we will have loaded the
 			// snapshot into memory, and we can just delete it normally.
 			// Handles case 3)
-			if err := o.deleteSnapshot(v.SnapshotConfig); err != nil {
+			if err := o.deleteSnapshot(ctx, v.SnapshotConfig); err != nil {
 				return fmt.Errorf("unable to clean up snapshot %s: %v", v.SnapshotConfig.Name, err)
 			}
 		} else {
@@ -605,12 +843,12 @@ func (o *TridentOrchestrator) handleFailedTransaction(v *storage.VolumeTransacti
 			// Handles case 2)
 			for _, backend := range o.backends {
 				// Skip backends that aren't ready to accept a snapshot delete operation
-				if !backend.State.IsOnline() && !backend.State.IsDeleting() {
+				if !backend.State.IsServing() && !backend.State.IsDeleting() {
 					continue
 				}
 				// Snapshot deletion is an idempotent operation, so it's safe to
 				// delete an already deleted snapshot.
-				if err := backend.DeleteSnapshot(v.SnapshotConfig, v.Config); err != nil {
+				if err := backend.DeleteSnapshot(ctx, v.SnapshotConfig, v.Config); err != nil {
 					return fmt.Errorf("error attempting to clean up snapshot %s from backend %s: %v",
 						v.SnapshotConfig.Name, backend.Name, err)
 				}
@@ -630,7 +868,9 @@ func (o *TridentOrchestrator) handleFailedTransaction(v *storage.VolumeTransacti
 
 		logFields := log.Fields{"volume": v.SnapshotConfig.VolumeName, "snapshot": v.SnapshotConfig.Name}
 
-		if err := o.deleteSnapshot(v.SnapshotConfig); err != nil {
+		ctx, cancel := o.operationContext(context.Background(), OperationDeleteSnapshot)
+		defer cancel()
+		if err := o.deleteSnapshot(ctx, v.SnapshotConfig); err != nil {
 			if utils.IsNotFoundError(err) {
 				log.WithFields(logFields).Info("Snapshot for the delete transaction wasn't found.")
 			} else {
@@ -653,7 +893,9 @@ func (o *TridentOrchestrator) handleFailedTransaction(v *storage.VolumeTransacti
 		var err error
 		vol, ok := o.volumes[v.Config.Name]
 		if ok {
-			err = o.resizeVolume(vol, v.Config.Size)
+			ctx, cancel := o.operationContext(context.Background(), OperationResizeVolume)
+			err = o.resizeVolume(ctx, vol, v.Config.Size)
+			cancel()
 			if err != nil {
 				log.WithFields(log.Fields{
 					"volume": v.Config.Name,
@@ -715,6 +957,9 @@ func (o *TridentOrchestrator) handleFailedTransaction(v *storage.VolumeTransacti
 			return fmt.Errorf("failed to clean up volume addition transaction: %v", err)
 		}
 
+	case storage.MigrateVolume:
+		return o.handleFailedMigration(v)
+
 	case storage.UpgradeVolume, storage.VolumeCreating:
 		// Do nothing
 	}
@@ -771,6 +1016,10 @@ func (o *TridentOrchestrator) validateBackendUpdate(
 }
 
 func (o *TridentOrchestrator) GetVersion() (string, error) {
+	// A standby controller is healthy while it waits to be elected and bootstrapped
+	if o.standby && utils.IsNotReadyError(o.bootstrapError) {
+		return config.OrchestratorVersion.String(), nil
+	}
 	return config.OrchestratorVersion.String(), o.bootstrapError
 }
 
@@ -998,9 +1247,14 @@ func (o *TridentOrchestrator) updateBackendByBackendUUID(backendName, configJSON
 		return nil, err
 	}
 	backend.BackendUUID = backendUUID
+	backend.Generation = originalBackend.Generation + 1
 	if err = o.validateBackendUpdate(originalBackend, backend); err != nil {
 		return nil, err
 	}
+	// A cordon is administrative, so it survives changes to the backend config
+	if originalBackend.State.IsCordoned() || originalBackend.State.IsDraining() {
+		backend.State = originalBackend.State
+	}
 	log.WithFields(log.Fields{
 		"originalBackend.Name":        originalBackend.Name,
 		"originalBackend.BackendUUID": originalBackend.BackendUUID,
@@ -1064,7 +1318,7 @@ func (o *TridentOrchestrator) updateBackendByBackendUUID(backendName, configJSON
 			log.Debug("Copied volumes forward.")
 		}
 	}
-	originalBackend.Terminate()
+	o.retireBackend(originalBackend)
 	o.backends[backend.BackendUUID] = backend
 
 	// Update the volume state in memory
@@ -1170,19 +1424,34 @@ func (o *TridentOrchestrator) updateBackendState(backendName, backendState strin
 		return nil, utils.NotFoundError(fmt.Sprintf("backend %v was not found", backendName))
 	}
 
+	defer o.lockBackendExclusive(backendUUID)()
+
 	newBackendState := storage.BackendState(backendState)
 
-	// Limit the command to Failed
-	if !newBackendState.IsFailed() {
+	switch {
+	case newBackendState.IsFailed():
+		backend.Terminate()
+	case newBackendState.IsOnline(), newBackendState.IsCordoned(), newBackendState.IsDraining():
+		// Cordoning only changes whether new volumes are placed on a backend, so it can't revive
+		// a backend that isn't serving its volumes.
+		if !backend.State.IsServing() {
+			return nil, fmt.Errorf("cannot change the state of backend %s from %s to %s",
+				backendName, backend.State, newBackendState)
+		}
+	default:
 		return nil, fmt.Errorf("unsupported backend state: %s", newBackendState)
 	}
+	backend.State = newBackendState
 
-	if !newBackendState.IsOnline() {
-		backend.Terminate()
+	if err = o.storeClient.UpdateBackend(backend); err != nil {
+		return backend.ConstructExternal(), err
 	}
-	backend.State = newBackendState
 
-	return backend.ConstructExternal(), o.storeClient.UpdateBackend(backend)
+	if newBackendState.IsDraining() {
+		o.drainBackend(backend)
+	}
+
+	return backend.ConstructExternal(), nil
 }
 
 func (o *TridentOrchestrator) getBackendUUIDByBackendName(backendName string) (string, error) {
@@ -1341,6 +1610,8 @@ func (o *TridentOrchestrator) deleteBackendByBackendUUID(backendName, backendUUI
 		return utils.NotFoundError(fmt.Sprintf("backend %s not found", backendName))
 	}
 
+	defer o.lockBackendExclusive(backendUUID)()
+
 	backend.Online = false // TODO eventually remove
 	backend.State = storage.Deleting
 	storageClasses := make(map[string]*storageclass.StorageClass, 0)
@@ -1353,7 +1624,8 @@ func (o *TridentOrchestrator) deleteBackendByBackendUUID(backendName, backendUUI
 	for _, sc := range storageClasses {
 		sc.RemovePoolsForBackend(backend)
 	}
-	if !backend.HasVolumes() {
+	// Volumes still being created or deleted keep the backend until they are done
+	if !backend.HasVolumes() && o.pendingBackendOperations[backendUUID] == 0 {
 		backend.Terminate()
 		delete(o.backends, backendUUID)
 		return o.storeClient.DeleteBackend(backend)
@@ -1368,7 +1640,7 @@ func (o *TridentOrchestrator) deleteBackendByBackendUUID(backendName, backendUUI
 	return o.storeClient.UpdateBackend(backend)
 }
 
-func (o *TridentOrchestrator) AddVolume(volumeConfig *storage.VolumeConfig) (
+func (o *TridentOrchestrator) AddVolume(ctx context.Context, volumeConfig *storage.VolumeConfig) (
 	externalVol *storage.VolumeExternal, err error) {
 
 	if o.bootstrapError != nil {
@@ -1377,6 +1649,9 @@ func (o *TridentOrchestrator) AddVolume(volumeConfig *storage.VolumeConfig) (
 
 	defer recordTiming("volume_add", &err)()
 
+	o.volumeLocks.Lock(volumeConfig.Name)
+	defer o.volumeLocks.Unlock(volumeConfig.Name)
+
 	o.mutex.Lock()
 	defer o.mutex.Unlock()
 	defer o.updateMetrics()
@@ -1387,23 +1662,27 @@ func (o *TridentOrchestrator) AddVolume(volumeConfig *storage.VolumeConfig) (
 		return nil, fmt.Errorf("volume %s already exists", volumeConfig.Name)
 	}
 
+	ctx, cancel := o.operationContext(ctx, OperationCreateVolume)
+	defer cancel()
+
 	// If a volume is already being created, retry the operation with the same backend
 	// instead of continuing here and potentially starting over on a different backend.
 	// Otherwise, treat this as a new volume creation workflow.
 	if retryTxn, err := o.GetVolumeCreatingTransaction(volumeConfig); err != nil {
 		return nil, err
 	} else if retryTxn != nil {
-		return o.addVolumeRetry(retryTxn)
+		return o.addVolumeRetry(ctx, retryTxn, nil)
 	}
 
-	return o.addVolumeInitial(volumeConfig)
+	return o.addVolumeInitial(ctx, volumeConfig, nil)
 }
 
-// addVolumeInitial continues the volume creation operation.
+// addVolumeInitial continues the volume creation operation.  If a populate function is supplied, it
+// fills the new volume before the volume is registered, and the volume is deleted if it fails.
 // This method should only be called from AddVolume, as it does not take locks or otherwise do much validation
 // of the volume config.
 func (o *TridentOrchestrator) addVolumeInitial(
-	volumeConfig *storage.VolumeConfig,
+	ctx context.Context, volumeConfig *storage.VolumeConfig, populate volumePopulator,
 ) (externalVol *storage.VolumeExternal, err error) {
 
 	var (
@@ -1419,13 +1698,81 @@ func (o *TridentOrchestrator) addVolumeInitial(
 		return nil, err
 	}
 
+	if err = setRawBlockFileSystem(volumeConfig); err != nil {
+		return nil, err
+	}
+
 	sc, ok := o.storageClasses[volumeConfig.StorageClass]
 	if !ok {
 		return nil, fmt.Errorf("unknown storage class: %s", volumeConfig.StorageClass)
 	}
+	if volumeConfig.MountOptions == "" {
+		volumeConfig.MountOptions = sc.GetMountOptions()
+	}
+	if volumeConfig.RootOwnership == "" {
+		volumeConfig.RootOwnership = sc.GetRootOwnership()
+	}
+	if volumeConfig.FormatOptions == "" {
+		volumeConfig.FormatOptions = sc.GetFormatOptions()
+	}
+	if volumeConfig.FsckPolicy == "" {
+		volumeConfig.FsckPolicy = sc.GetFsckPolicy()
+	}
+	if volumeConfig.IOScheduler == "" {
+		volumeConfig.IOScheduler = sc.GetIOScheduler()
+	}
+	if volumeConfig.ReadAheadKB == "" {
+		volumeConfig.ReadAheadKB = sc.GetReadAheadKB()
+	}
+	if volumeConfig.NrRequests == "" {
+		volumeConfig.NrRequests = sc.GetNrRequests()
+	}
+	if volumeConfig.ExportPolicy == "" {
+		volumeConfig.ExportPolicy = sc.GetExportPolicy()
+	}
+	if volumeConfig.IgroupName == "" {
+		volumeConfig.IgroupName = sc.GetIgroupName()
+	}
+	if err = resolveDeletionPolicy(volumeConfig, sc); err != nil {
+		return nil, err
+	}
+
+	releaseQuota, err := o.reserveQuota(volumeConfig, quotaVolumeSize(volumeConfig.Size))
+	if err != nil {
+		return nil, err
+	}
+	defer releaseQuota()
+
 	poolsByBackend := sc.GetStoragePoolsForProtocolByBackend(protocol)
+
+	// backendErrors records why each backend couldn't create the volume, so a frontend can report it
+	backendErrors := make(map[string]string)
+
+	// Cordoned and draining backends keep their storage class pools but don't get new volumes, and
+	// backends restricted to other namespaces don't get volumes from this one
+	for backendName, backendPoolInfo := range poolsByBackend {
+		if len(backendPoolInfo.Pools) == 0 {
+			continue
+		}
+		poolBackend := backendPoolInfo.Pools[0].Backend
+		if !poolBackend.State.AcceptsNewVolumes() {
+			backendErrors[backendName] = fmt.Sprintf("backend is %s", poolBackend.State.String())
+			delete(poolsByBackend, backendName)
+		} else if !poolBackend.ServesNamespace(volumeConfig.Namespace, volumeConfig.NamespaceLabels) {
+			backendErrors[backendName] = fmt.Sprintf("backend does not serve namespace %s", volumeConfig.Namespace)
+			delete(poolsByBackend, backendName)
+		}
+	}
+	filterPoolsByStorageClassTopology(poolsByBackend, sc, backendErrors)
+	filterPoolsByTopology(poolsByBackend, volumeConfig, backendErrors)
+	filterPoolsByName(poolsByBackend, volumeConfig, backendErrors)
+
 	if len(poolsByBackend) == 0 {
-		return nil, fmt.Errorf("no available backends for storage class %s", volumeConfig.StorageClass)
+		message := fmt.Sprintf("no available backends for storage class %s", volumeConfig.StorageClass)
+		if len(backendErrors) > 0 {
+			message += "; " + formatBackendErrors(backendErrors)
+		}
+		return nil, utils.VolumeCreateFailedError(message, backendErrors)
 	}
 
 	// Add a transaction to clean out any existing transactions
@@ -1452,12 +1799,8 @@ func (o *TridentOrchestrator) addVolumeInitial(
 	// Keep trying until we run out of matching backends/pools
 	for len(poolsByBackend) > 0 {
 
-		// Choose a backend at random from the pool map
-		backendNames := make([]string, 0)
-		for backendName := range poolsByBackend {
-			backendNames = append(backendNames, backendName)
-		}
-		backendName := backendNames[rand.Intn(len(backendNames))]
+		// Choose a backend at random from the pool map, favoring those in the preferred topologies
+		backendName := chooseBackend(poolsByBackend, volumeConfig.PreferredTopologies)
 
 		// The pool lists are already shuffled, so just pick the first one from the chosen backend and
 		// pop it from the list.  If the backend has no more eligible pools, remove it from the map so
@@ -1473,19 +1816,29 @@ func (o *TridentOrchestrator) addVolumeInitial(
 		// Add volume to the backend of the selected pool
 		backend = pool.Backend
 
-		// CreatePrepare has a side effect that updates the volumeConfig with the backend-specific internal name
-		backend.Driver.CreatePrepare(volumeConfig)
+		// CreatePrepare has a side effect that updates the volumeConfig with the pool-specific internal name
+		backend.CreatePrepare(volumeConfig, pool)
+		volumeConfig.AllowedTopologies = pool.AccessibleTopologies(volumeConfig.RequisiteTopologies)
 
-		// Update transaction with updated volumeConfig
+		// Update transaction with updated volumeConfig and a journal of the create in this pool
 		txn = &storage.VolumeTransaction{
 			Config: volumeConfig,
 			Op:     storage.AddVolume,
 		}
+		txn.Journal = o.newVolumeJournal(txn, backend, pool)
 		if err = o.storeClient.UpdateVolumeTransaction(txn); err != nil {
 			return nil, err
 		}
 
-		vol, err = backend.AddVolume(volumeConfig, pool, sc.GetAttributes(), false)
+		// The volume is created without holding the orchestrator lock, so a slow backend
+		// doesn't block operations on other volumes
+		volAttributes := sc.GetAttributes()
+		journalCtx := storage.ContextWithJournal(ctx, txn.Journal)
+		o.withBackendUnlocked(backend, func() {
+			vol, err = backend.CreateVolume(journalCtx, volumeConfig, pool, volAttributes, false)
+		})
+		backend = o.currentBackend(backend)
+		o.recordBackendOperation(backend, "volume_create", err)
 		if err != nil {
 
 			logFields := log.Fields{
@@ -1503,11 +1856,30 @@ func (o *TridentOrchestrator) addVolumeInitial(
 				return nil, err
 			}
 
+			// Undo any steps the driver journaled but couldn't undo itself, so that nothing is left
+			// behind on this backend.  If that fails, the transaction is kept for recovery.
+			if len(txn.Journal.Steps) > 0 {
+				if rollbackErr := o.rollbackVolumeJournal(backend, volumeConfig, txn.Journal); rollbackErr != nil {
+					return nil, fmt.Errorf("%v; %v", err, rollbackErr)
+				}
+			}
+
+			// If the operation timed out or its caller gave up, don't try another pool.
+			if ctx.Err() != nil {
+				log.WithFields(logFields).Warn("Volume creation was canceled.")
+				return nil, fmt.Errorf("volume creation canceled; %v", err)
+			}
+
 			// Log failure and continue for loop to find a backend that can create the volume.
 			log.WithFields(logFields).Warn("Failed to create the volume on this backend.")
 			errorMessages = append(errorMessages,
 				fmt.Sprintf("[Failed to create volume %s on storage pool %s from backend %s: %s]",
 					volumeConfig.Name, pool.Name, backend.Name, err.Error()))
+			backendError := fmt.Sprintf("storage pool %s: %s", pool.Name, err.Error())
+			if previousErrors, ok := backendErrors[backend.Name]; ok {
+				backendError = previousErrors + "; " + backendError
+			}
+			backendErrors[backend.Name] = backendError
 
 			// If this backend cannot handle the new volume on any pool, remove it from further consideration.
 			if drivers.IsBackendIneligibleError(err) {
@@ -1523,6 +1895,19 @@ func (o *TridentOrchestrator) addVolumeInitial(
 				}
 			}
 		} else {
+			if populate != nil {
+				err = populate(vol, backend)
+				backend = o.currentBackend(backend)
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			// From here on, an interrupted create is finished rather than undone
+			if err = txn.Journal.Commit(); err != nil {
+				return nil, err
+			}
+
 			// Volume creation succeeded, so register it and return the result
 			return o.addVolumeFinish(txn, vol, backend)
 		}
@@ -1530,19 +1915,76 @@ func (o *TridentOrchestrator) addVolumeInitial(
 
 	externalVol = nil
 	if len(errorMessages) == 0 {
-		err = fmt.Errorf("no suitable %s backend with \"%s\" storage class and %s of free space was found",
-			protocol, volumeConfig.StorageClass, volumeConfig.Size)
+		err = utils.VolumeCreateFailedError(fmt.Sprintf(
+			"no suitable %s backend with \"%s\" storage class and %s of free space was found",
+			protocol, volumeConfig.StorageClass, volumeConfig.Size), backendErrors)
 	} else {
-		err = fmt.Errorf("encountered error(s) in creating the volume: %s", strings.Join(errorMessages, ", "))
+		err = utils.VolumeCreateFailedError(fmt.Sprintf("encountered error(s) in creating the volume: %s",
+			strings.Join(errorMessages, ", ")), backendErrors)
 	}
 	return nil, err
 }
 
+// filterPoolsByName removes the pools other than the one a volume is pinned to, if any.  A volume may be
+// pinned to a storage class pool by name, or to a physical pool, such as an ONTAP aggregate, that backs
+// one of the storage class's virtual pools.  Backends left without pools are removed, and the reason is
+// recorded in backendErrors.
+func filterPoolsByName(
+	poolsByBackend map[string]*storageclass.BackendPoolInfo, volumeConfig *storage.VolumeConfig,
+	backendErrors map[string]string,
+) {
+	if volumeConfig.Pool == "" {
+		return
+	}
+
+	for backendName, backendPoolInfo := range poolsByBackend {
+
+		pools := make([]*storage.Pool, 0, 1)
+		for _, pool := range backendPoolInfo.Pools {
+			if pool.Name == volumeConfig.Pool {
+				pools = append(pools, pool)
+			}
+		}
+
+		// Otherwise the driver chooses the pinned physical pool when creating the volume in any virtual pool
+		if len(pools) == 0 {
+			if _, ok := backendPoolInfo.PhysicalPoolNames[volumeConfig.Pool]; ok {
+				pools = backendPoolInfo.Pools
+				backendPoolInfo.PhysicalPoolNames = map[string]struct{}{volumeConfig.Pool: {}}
+			}
+		}
+
+		if len(pools) == 0 {
+			backendErrors[backendName] = fmt.Sprintf("backend has no pool %s in storage class %s",
+				volumeConfig.Pool, volumeConfig.StorageClass)
+			delete(poolsByBackend, backendName)
+			continue
+		}
+		backendPoolInfo.Pools = pools
+	}
+}
+
+// formatBackendErrors returns the reasons backends couldn't create a volume, sorted by backend name.
+func formatBackendErrors(backendErrors map[string]string) string {
+	backendNames := make([]string, 0, len(backendErrors))
+	for backendName := range backendErrors {
+		backendNames = append(backendNames, backendName)
+	}
+	sort.Strings(backendNames)
+
+	messages := make([]string, 0, len(backendNames))
+	for _, backendName := range backendNames {
+		messages = append(messages, fmt.Sprintf("backend %s: %s", backendName, backendErrors[backendName]))
+	}
+	return strings.Join(messages, ", ")
+}
+
 // addVolumeRetry continues a volume creation operation that previously failed with a VolumeCreatingError.
+// The populate function, if supplied, is used as it is by addVolumeInitial.
 // This method should only be called from AddVolume, as it does not take locks or otherwise do much validation
 // of the volume config.
 func (o *TridentOrchestrator) addVolumeRetry(
-	txn *storage.VolumeTransaction,
+	ctx context.Context, txn *storage.VolumeTransaction, populate volumePopulator,
 ) (externalVol *storage.VolumeExternal, err error) {
 
 	var (
@@ -1578,7 +2020,11 @@ func (o *TridentOrchestrator) addVolumeRetry(
 		err = o.addVolumeRetryCleanup(err, backend, pool, vol, txn, volumeConfig)
 	}()
 
-	vol, err = backend.AddVolume(volumeConfig, pool, make(map[string]sa.Request), true)
+	o.withBackendUnlocked(backend, func() {
+		vol, err = backend.CreateVolume(ctx, volumeConfig, pool, make(map[string]sa.Request), true)
+	})
+	backend = o.currentBackend(backend)
+	o.recordBackendOperation(backend, "volume_create", err)
 	if err != nil {
 
 		logFields := log.Fields{
@@ -1600,6 +2046,14 @@ func (o *TridentOrchestrator) addVolumeRetry(
 		return nil, err
 	}
 
+	if populate != nil {
+		err = populate(vol, backend)
+		backend = o.currentBackend(backend)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Volume creation succeeded, so register it and return the result
 	return o.addVolumeFinish(txn, vol, backend)
 }
@@ -1621,14 +2075,18 @@ func (o *TridentOrchestrator) addVolumeFinish(
 		return nil, err
 	}
 
-	// Update internal cache and return external form of the new volume
+	// Update internal cache and return external form of the new volume.  The backend may have
+	// been updated while the volume was created, so cache the volume on the current backend.
 	o.volumes[vol.Config.Name] = vol
+	if currentBackend, ok := o.backends[vol.BackendUUID]; ok {
+		currentBackend.AddCachedVolume(vol)
+	}
 	externalVol = vol.ConstructExternal()
 	return externalVol, nil
 }
 
 func (o *TridentOrchestrator) CloneVolume(
-	volumeConfig *storage.VolumeConfig,
+	ctx context.Context, volumeConfig *storage.VolumeConfig,
 ) (externalVol *storage.VolumeExternal, err error) {
 
 	if o.bootstrapError != nil {
@@ -1637,6 +2095,16 @@ func (o *TridentOrchestrator) CloneVolume(
 
 	defer recordTiming("volume_clone", &err)()
 
+	if volumeConfig.CloneSourceVolume == volumeConfig.Name {
+		return nil, fmt.Errorf("volume %s cannot be cloned from itself", volumeConfig.Name)
+	}
+
+	// The source can't be deleted while it is cloned, which may take a while if its files are copied
+	o.volumeLocks.Lock(volumeConfig.Name)
+	defer o.volumeLocks.Unlock(volumeConfig.Name)
+	o.volumeLocks.RLock(volumeConfig.CloneSourceVolume)
+	defer o.volumeLocks.RUnlock(volumeConfig.CloneSourceVolume)
+
 	o.mutex.Lock()
 	defer o.mutex.Unlock()
 	defer o.updateMetrics()
@@ -1647,20 +2115,26 @@ func (o *TridentOrchestrator) CloneVolume(
 
 	volumeConfig.Version = config.OrchestratorAPIVersion
 
+	ctx, cancel := o.operationContext(ctx, OperationCloneVolume)
+	defer cancel()
+
 	// If a volume is already being cloned, retry the operation with the same backend
 	// instead of continuing here and potentially starting over on a different backend.
 	// Otherwise, treat this as a new volume creation workflow.
 	if retryTxn, err := o.GetVolumeCreatingTransaction(volumeConfig); err != nil {
 		return nil, err
 	} else if retryTxn != nil {
-		return o.cloneVolumeRetry(retryTxn)
+		if copySource := retryTxn.VolumeCreatingConfig.CopySourceVolume; copySource != "" {
+			return o.addVolumeRetry(ctx, retryTxn, o.volumeCopier(copySource))
+		}
+		return o.cloneVolumeRetry(ctx, retryTxn)
 	}
 
-	return o.cloneVolumeInitial(volumeConfig)
+	return o.cloneVolumeInitial(ctx, volumeConfig)
 }
 
 func (o *TridentOrchestrator) cloneVolumeInitial(
-	volumeConfig *storage.VolumeConfig,
+	ctx context.Context, volumeConfig *storage.VolumeConfig,
 ) (externalVol *storage.VolumeExternal, err error) {
 
 	var (
@@ -1675,7 +2149,18 @@ func (o *TridentOrchestrator) cloneVolumeInitial(
 	if !found {
 		return nil, utils.NotFoundError(fmt.Sprintf("source volume not found: %s", volumeConfig.CloneSourceVolume))
 	}
+	if o.volumeMigrationInProgress(volumeConfig.CloneSourceVolume) {
+		return nil, fmt.Errorf("source volume %s is being migrated", volumeConfig.CloneSourceVolume)
+	}
+	if o.volumeDeleteInProgress(volumeConfig.CloneSourceVolume) {
+		return nil, fmt.Errorf("source volume %s is being deleted", volumeConfig.CloneSourceVolume)
+	}
+	if err = o.checkCloneGrant(volumeConfig, sourceVolume); err != nil {
+		return nil, err
+	}
 
+	// A clone requested larger than its source is created at the source's size and then expanded
+	cloneTargetSize := ""
 	if volumeConfig.Size != "" {
 		cloneSourceVolumeSize, err := strconv.ParseInt(sourceVolume.Config.Size, 10, 64)
 		if err != nil {
@@ -1692,9 +2177,10 @@ func (o *TridentOrchestrator) cloneVolumeInitial(
 				"source_volume": sourceVolume.Config.Name,
 				"volume":        volumeConfig.Name,
 				"backendUUID":   sourceVolume.BackendUUID,
-			}).Error("requested PVC size is too large for the clone source")
-			return nil, fmt.Errorf("requested PVC size '%d' is too large for the clone source '%d'",
-				cloneVolumeSize, cloneSourceVolumeSize)
+				"sourceSize":    cloneSourceVolumeSize,
+				"size":          cloneVolumeSize,
+			}).Info("Clone will be expanded beyond the size of its source.")
+			cloneTargetSize = volumeConfig.Size
 		}
 	}
 
@@ -1706,6 +2192,14 @@ func (o *TridentOrchestrator) cloneVolumeInitial(
 		}).Warnf("Clone operation is likely to fail with an orphaned source volume.")
 	}
 
+	// Backups are stored outside of the backend, so the backend cannot clone from them
+	if volumeConfig.CloneSourceSnapshot != "" {
+		snapshotID := storage.MakeSnapshotID(volumeConfig.CloneSourceVolume, volumeConfig.CloneSourceSnapshot)
+		if snapshot, ok := o.snapshots[snapshotID]; ok && snapshot.Config.IsBackup() {
+			return nil, fmt.Errorf("cannot clone volume %s from backup %s", volumeConfig.Name, snapshotID)
+		}
+	}
+
 	sourceVolumeMode := sourceVolume.Config.VolumeMode
 	if sourceVolumeMode != "" && sourceVolumeMode != volumeConfig.VolumeMode {
 		return nil, utils.NotFoundError(fmt.Sprintf("source volume's volume-mode ("+
@@ -1713,6 +2207,24 @@ func (o *TridentOrchestrator) cloneVolumeInitial(
 			volumeConfig.VolumeMode))
 	}
 
+	// A backend can only clone its own volumes, so a clone whose storage class uses another protocol is
+	// created on one of the storage class's backends and filled with a copy of the source's files
+	sourceBackend, ok := o.backends[sourceVolume.BackendUUID]
+	if ok && o.cloneRequiresCopy(volumeConfig, sourceBackend) {
+		return o.cloneVolumeByCopy(ctx, volumeConfig, sourceVolume)
+	}
+
+	// A clone is the size of its source unless a size is requested
+	cloneSize := volumeConfig.Size
+	if cloneSize == "" {
+		cloneSize = sourceVolume.Config.Size
+	}
+	releaseQuota, err := o.reserveQuota(volumeConfig, quotaVolumeSize(cloneSize))
+	if err != nil {
+		return nil, err
+	}
+	defer releaseQuota()
+
 	// Get the source backend
 	if backend, found = o.backends[sourceVolume.BackendUUID]; !found {
 		// Should never get here but just to be safe
@@ -1733,6 +2245,30 @@ func (o *TridentOrchestrator) cloneVolumeInitial(
 	cloneConfig.CloneSourceSnapshot = volumeConfig.CloneSourceSnapshot
 	cloneConfig.QoS = volumeConfig.QoS
 	cloneConfig.QoSType = volumeConfig.QoSType
+	cloneConfig.VolumeGroup = ""
+	cloneConfig.Namespace = volumeConfig.Namespace
+	cloneConfig.CloneSourceNamespace = volumeConfig.CloneSourceNamespace
+	cloneConfig.CloneTargetSize = cloneTargetSize
+
+	// Whether a clone outlives its deletion is up to the clone's requester, not the source's
+	cloneConfig.DeletionPolicy = volumeConfig.DeletionPolicy
+	if err = resolveDeletionPolicy(cloneConfig, o.storageClasses[volumeConfig.StorageClass]); err != nil {
+		return nil, err
+	}
+
+	// A clone is created alongside its source, so it is only accessible from the source's topologies
+	cloneConfig.RequisiteTopologies = volumeConfig.RequisiteTopologies
+	cloneConfig.PreferredTopologies = volumeConfig.PreferredTopologies
+	sourceAccess := &storage.Pool{SupportedTopologies: sourceVolume.Config.AllowedTopologies}
+	if !sourceAccess.SupportsTopologies(volumeConfig.RequisiteTopologies) {
+		return nil, fmt.Errorf("source volume %s is not accessible from the requested topologies %v",
+			volumeConfig.CloneSourceVolume, volumeConfig.RequisiteTopologies)
+	}
+	sc, ok := o.storageClasses[volumeConfig.StorageClass]
+	if ok && !sourceAccess.WithinTopologies(sc.GetAllowedTopologies()) {
+		return nil, fmt.Errorf("source volume %s is not accessible from the topologies allowed by storage "+
+			"class %s", volumeConfig.CloneSourceVolume, volumeConfig.StorageClass)
+	}
 
 	// Override this value only if SplitOnClone has been defined in clone volume's config
 	if volumeConfig.SplitOnClone != "" {
@@ -1753,8 +2289,19 @@ func (o *TridentOrchestrator) cloneVolumeInitial(
 		}
 	}
 
+	// A clone may instead be placed in another of its source backend's pools, whose attributes apply to it
+	if volumeConfig.Pool != "" {
+		requestedPool, ok := backend.Storage[volumeConfig.Pool]
+		if !ok {
+			return nil, utils.NotFoundError(fmt.Sprintf("backend %s of source volume %s has no pool %s",
+				backend.Name, volumeConfig.CloneSourceVolume, volumeConfig.Pool))
+		}
+		pool = requestedPool
+		cloneConfig.Pool = volumeConfig.Pool
+	}
+
 	// Create the backend-specific internal names so they are saved in the transaction
-	backend.Driver.CreatePrepare(cloneConfig)
+	backend.CreatePrepare(cloneConfig, pool)
 
 	// Add transaction in case the operation must be rolled back later
 	txn = &storage.VolumeTransaction{
@@ -1774,7 +2321,9 @@ func (o *TridentOrchestrator) cloneVolumeInitial(
 	}()
 
 	// Create the clone
-	if vol, err = backend.CloneVolume(cloneConfig, pool, false); err != nil {
+	vol, err = backend.CloneVolume(ctx, cloneConfig, pool, false)
+	o.recordBackendOperation(backend, "volume_clone", err)
+	if err != nil {
 
 		logFields := log.Fields{
 			"backend":      backend.Name,
@@ -1796,12 +2345,16 @@ func (o *TridentOrchestrator) cloneVolumeInitial(
 			cloneConfig.Name, backend.Name, err)
 	}
 
+	if err = o.expandClone(ctx, vol, backend); err != nil {
+		return nil, err
+	}
+
 	// Volume creation succeeded, so register it and return the result
 	return o.addVolumeFinish(txn, vol, backend)
 }
 
 func (o *TridentOrchestrator) cloneVolumeRetry(
-	txn *storage.VolumeTransaction,
+	ctx context.Context, txn *storage.VolumeTransaction,
 ) (externalVol *storage.VolumeExternal, err error) {
 
 	var (
@@ -1845,7 +2398,9 @@ func (o *TridentOrchestrator) cloneVolumeRetry(
 	}()
 
 	// Create the clone
-	if vol, err = backend.CloneVolume(cloneConfig, pool, true); err != nil {
+	vol, err = backend.CloneVolume(ctx, cloneConfig, pool, true)
+	o.recordBackendOperation(backend, "volume_clone", err)
+	if err != nil {
 
 		logFields := log.Fields{
 			"backend":      backend.Name,
@@ -1866,10 +2421,44 @@ func (o *TridentOrchestrator) cloneVolumeRetry(
 		return nil, err
 	}
 
+	if err = o.expandClone(ctx, vol, backend); err != nil {
+		return nil, err
+	}
+
 	// Volume creation succeeded, so register it and return the result
 	return o.addVolumeFinish(txn, vol, backend)
 }
 
+// expandClone resizes a clone that was requested larger than its source, as a volume restored from a
+// snapshot into a larger PVC is, since the clone is created at the size of its source.  The filesystem
+// of a block clone is grown to fill the clone when the clone is first staged on a node.
+func (o *TridentOrchestrator) expandClone(ctx context.Context, vol *storage.Volume, backend *storage.Backend) error {
+
+	targetSize := vol.Config.CloneTargetSize
+	if targetSize == "" {
+		return nil
+	}
+
+	err := backend.ResizeVolume(ctx, vol.Config, targetSize)
+	o.recordBackendOperation(backend, "volume_resize", err)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"backend":  backend.Name,
+			"volume":   vol.Config.Name,
+			"size":     vol.Config.Size,
+			"new_size": targetSize,
+			"error":    err,
+		}).Error("Unable to expand the cloned volume.")
+		return fmt.Errorf("unable to expand cloned volume %s to %s: %v", vol.Config.Name, targetSize, err)
+	}
+
+	vol.Config.CloneTargetSize = ""
+	if backend.GetProtocol() == config.Block && vol.Config.VolumeMode != config.RawBlock {
+		vol.Config.GrowFilesystem = true
+	}
+	return nil
+}
+
 // This func is used by volume import so it doesn't check core's o.volumes to see if the
 // volume exists or not. Instead it asks the driver if the volume exists before requesting
 // the volume size. Returns the VolumeExternal representation of the volume.
@@ -1906,6 +2495,15 @@ func (o *TridentOrchestrator) GetVolumeExternal(volumeName string, backendName s
 	return volExternal, nil
 }
 
+// resolveDeletionPolicy defaults a volume's deletion policy to that of its storage class, if any,
+// and ensures the policy is valid.
+func resolveDeletionPolicy(volumeConfig *storage.VolumeConfig, sc *storageclass.StorageClass) error {
+	if volumeConfig.DeletionPolicy == "" && sc != nil {
+		volumeConfig.DeletionPolicy = sc.GetDeletionPolicy()
+	}
+	return storage.ValidateDeletionPolicy(volumeConfig.DeletionPolicy)
+}
+
 func (o *TridentOrchestrator) validateImportVolume(volumeConfig *storage.VolumeConfig) error {
 
 	backend, err := o.getBackendByBackendUUID(volumeConfig.ImportBackendUUID)
@@ -1931,6 +2529,24 @@ func (o *TridentOrchestrator) validateImportVolume(volumeConfig *storage.VolumeC
 		return fmt.Errorf("storageClass %s does not match any storage pools for backend %s", volumeConfig.StorageClass, backend.Name)
 	}
 
+	if err = resolveDeletionPolicy(volumeConfig, sc); err != nil {
+		return err
+	}
+
+	// Imported volumes are checked and tuned when staged, as the storage class's new volumes are
+	if volumeConfig.FsckPolicy == "" {
+		volumeConfig.FsckPolicy = sc.GetFsckPolicy()
+	}
+	if volumeConfig.IOScheduler == "" {
+		volumeConfig.IOScheduler = sc.GetIOScheduler()
+	}
+	if volumeConfig.ReadAheadKB == "" {
+		volumeConfig.ReadAheadKB = sc.GetReadAheadKB()
+	}
+	if volumeConfig.NrRequests == "" {
+		volumeConfig.NrRequests = sc.GetNrRequests()
+	}
+
 	if backend.Driver.Get(originalName) != nil {
 		return utils.NotFoundError(fmt.Sprintf("volume %s was not found", originalName))
 	}
@@ -1957,17 +2573,50 @@ func (o *TridentOrchestrator) validateImportVolume(volumeConfig *storage.VolumeC
 	}
 
 	// Make sure that for the Raw-block volume import we do not have ext3, ext4 or xfs filesystem specified
-	if volumeConfig.VolumeMode == config.RawBlock {
-		if volumeConfig.FileSystem != "" && volumeConfig.FileSystem != drivers.FsRaw {
-			return fmt.Errorf("cannot create raw-block volume %s with the filesystem %s", originalName, volumeConfig.FileSystem)
+	return setRawBlockFileSystem(volumeConfig)
+}
+
+// getImportStoragePool returns the pool of an imported volume's storage class on its backend, which
+// determines the volume's new name.  If the class matches several of the backend's pools, the first by
+// name is used, so the choice is repeatable.
+func (o *TridentOrchestrator) getImportStoragePool(
+	volumeConfig *storage.VolumeConfig, backend *storage.Backend,
+) *storage.Pool {
+
+	sc, ok := o.storageClasses[volumeConfig.StorageClass]
+	if !ok {
+		return nil
+	}
+
+	var importPool *storage.Pool
+	for _, pool := range sc.Pools() {
+		if pool.Backend != backend {
+			continue
+		}
+		if importPool == nil || pool.Name < importPool.Name {
+			importPool = pool
 		}
 	}
+	return importPool
+}
 
+// setRawBlockFileSystem records a raw-block volume's lack of a filesystem in its config, so the driver
+// and the node never format or mount it.  Raw-block volumes may not specify any other filesystem.
+func setRawBlockFileSystem(volumeConfig *storage.VolumeConfig) error {
+	if volumeConfig.VolumeMode != config.RawBlock {
+		return nil
+	}
+	if volumeConfig.FileSystem != "" && volumeConfig.FileSystem != drivers.FsRaw {
+		return fmt.Errorf("cannot create raw-block volume %s with the filesystem %s", volumeConfig.Name,
+			volumeConfig.FileSystem)
+	}
+	volumeConfig.FileSystem = drivers.FsRaw
 	return nil
 }
 
 func (o *TridentOrchestrator) LegacyImportVolume(
-	volumeConfig *storage.VolumeConfig, backendName string, notManaged bool, createPVandPVC VolumeCallback,
+	ctx context.Context, volumeConfig *storage.VolumeConfig, backendName string, notManaged bool,
+	createPVandPVC VolumeCallback,
 ) (externalVol *storage.VolumeExternal, err error) {
 
 	if o.bootstrapError != nil {
@@ -2009,7 +2658,11 @@ func (o *TridentOrchestrator) LegacyImportVolume(
 		err = o.importVolumeCleanup(err, volumeConfig, volTxn)
 	}()
 
-	volume, err := backend.ImportVolume(volumeConfig)
+	ctx, cancel := o.operationContext(ctx, OperationImportVolume)
+	defer cancel()
+
+	volume, err := backend.ImportVolume(ctx, volumeConfig, o.getImportStoragePool(volumeConfig, backend))
+	o.recordBackendOperation(backend, "volume_import", err)
 	if err != nil {
 		return nil, fmt.Errorf("failed to import volume %s on backend %s: %v",
 			volumeConfig.ImportOriginalName, backendName, err)
@@ -2057,7 +2710,7 @@ func (o *TridentOrchestrator) LegacyImportVolume(
 }
 
 func (o *TridentOrchestrator) ImportVolume(
-	volumeConfig *storage.VolumeConfig,
+	ctx context.Context, volumeConfig *storage.VolumeConfig,
 ) (externalVol *storage.VolumeExternal, err error) {
 
 	if o.bootstrapError != nil {
@@ -2105,7 +2758,11 @@ func (o *TridentOrchestrator) ImportVolume(
 		err = o.importVolumeCleanup(err, volumeConfig, volTxn)
 	}()
 
-	volume, err := backend.ImportVolume(volumeConfig)
+	ctx, cancel := o.operationContext(ctx, OperationImportVolume)
+	defer cancel()
+
+	volume, err := backend.ImportVolume(ctx, volumeConfig, o.getImportStoragePool(volumeConfig, backend))
+	o.recordBackendOperation(backend, "volume_import", err)
 	if err != nil {
 		return nil, fmt.Errorf("failed to import volume %s on backend %s: %v", volumeConfig.ImportOriginalName,
 			volumeConfig.ImportBackendUUID, err)
@@ -2167,6 +2824,9 @@ func (o *TridentOrchestrator) AddVolumeTransaction(volTxn *storage.VolumeTransac
 		return err
 	}
 	if oldTxn != nil {
+		if oldTxn.Op == storage.DeleteVolume && oldTxn.VolumeDeletion != nil {
+			return fmt.Errorf("volume %s is pending deletion", volTxn.Config.Name)
+		}
 		if oldTxn.Op != storage.UpgradeVolume && oldTxn.Op != storage.VolumeCreating {
 			err = o.handleFailedTransaction(oldTxn)
 			if err != nil {
@@ -2184,7 +2844,13 @@ func (o *TridentOrchestrator) AddVolumeTransaction(volTxn *storage.VolumeTransac
 		}
 	}
 
-	return o.storeClient.AddVolumeTransaction(volTxn)
+	// Record that the transaction belongs to a running operation, so it isn't collected as garbage
+	o.setTransactionActive(volTxn, true)
+	if err = o.storeClient.AddVolumeTransaction(volTxn); err != nil {
+		o.setTransactionActive(volTxn, false)
+		return err
+	}
+	return nil
 }
 
 func (o *TridentOrchestrator) GetVolumeCreatingTransaction(
@@ -2216,6 +2882,8 @@ func (o *TridentOrchestrator) GetVolumeTransaction(
 // DeleteVolumeTransaction deletes a volume transaction created by
 // addVolumeTransaction.
 func (o *TridentOrchestrator) DeleteVolumeTransaction(volTxn *storage.VolumeTransaction) error {
+	// A transaction that can't be deleted no longer belongs to a running operation
+	defer o.setTransactionActive(volTxn, false)
 	return o.storeClient.DeleteVolumeTransaction(volTxn)
 }
 
@@ -2244,12 +2912,17 @@ func (o *TridentOrchestrator) addVolumeCleanup(
 		//     to remove the volume from the backend.
 		if backend != nil && vol != nil {
 			// We succeeded in adding the volume to the backend; now
-			// delete it.
-			cleanupErr = backend.RemoveVolume(vol.Config)
+			// delete it, even if the creation was canceled.
+			ctx, cancel := o.operationContext(context.Background(), OperationDeleteVolume)
+			cleanupErr = backend.RemoveVolume(ctx, vol.Config)
+			cancel()
 			if cleanupErr != nil {
 				cleanupErr = fmt.Errorf("unable to delete volume "+
 					"from backend during cleanup:  %v", cleanupErr)
 			}
+		} else if backend != nil && volTxn.Journal != nil && len(volTxn.Journal.Steps) > 0 {
+			// The create failed partway, and its steps couldn't be undone earlier
+			cleanupErr = o.rollbackVolumeJournal(backend, volumeConfig, volTxn.Journal)
 		}
 	}
 	if cleanupErr == nil {
@@ -2503,26 +3176,74 @@ func (o *TridentOrchestrator) volumeSnapshots(volumeName string) ([]*storage.Sna
 	return result, nil
 }
 
+// dependentClones returns the names of any clones that share storage with the specified volume
+func (o *TridentOrchestrator) dependentClones(volumeName string) ([]string, error) {
+
+	result := make([]string, 0)
+	for _, volume := range o.volumes {
+		if volume.Config.CloneSourceVolume != volumeName {
+			continue
+		}
+		backend, ok := o.backends[volume.BackendUUID]
+		if !ok {
+			continue
+		}
+		dependent, err := backend.CloneDependsOnSource(volume)
+		if err != nil {
+			return nil, fmt.Errorf("could not determine whether clone %s depends on volume %s; %v",
+				volume.Config.Name, volumeName, err)
+		}
+		if dependent {
+			result = append(result, volume.Config.Name)
+		}
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// volumeDependents describes the snapshots and clones that prevent the specified volume from being destroyed
+func (o *TridentOrchestrator) volumeDependents(volumeName string) ([]string, error) {
+
+	snapshots, err := o.volumeSnapshots(volumeName)
+	if err != nil {
+		return nil, err
+	}
+	clones, err := o.dependentClones(volumeName)
+	if err != nil {
+		return nil, err
+	}
+
+	dependents := make([]string, 0, len(snapshots)+len(clones))
+	for _, snapshot := range snapshots {
+		dependents = append(dependents, "snapshot "+snapshot.Config.Name)
+	}
+	sort.Strings(dependents)
+	for _, clone := range clones {
+		dependents = append(dependents, "clone "+clone)
+	}
+	return dependents, nil
+}
+
 // deleteVolume does the necessary work to delete a volume entirely.  It does
 // not construct a transaction, nor does it take locks; it assumes that the
 // caller will take care of both of these.  It also assumes that the volume
 // exists in memory.
-func (o *TridentOrchestrator) deleteVolume(volumeName string) error {
+func (o *TridentOrchestrator) deleteVolume(ctx context.Context, volumeName string) error {
 	volume := o.volumes[volumeName]
 	volumeBackend := o.backends[volume.BackendUUID]
 
-	// if there are any snapshots for this volume, we need to "soft" delete.
-	// only hard delete this volume when its last snapshot is deleted.
-	snapshotsForVolume, err := o.volumeSnapshots(volumeName)
+	// if there are any snapshots or clones that depend on this volume, we need to "soft" delete.
+	// only hard delete this volume when its last dependent is deleted.
+	dependents, err := o.volumeDependents(volumeName)
 	if err != nil {
 		return err
 	}
-	if len(snapshotsForVolume) > 0 {
+	if len(dependents) > 0 {
 		log.WithFields(log.Fields{
-			"volume":                  volumeName,
-			"backendUUID":             volume.BackendUUID,
-			"len(snapshotsForVolume)": len(snapshotsForVolume),
-		}).Debug("Soft deleting.")
+			"volume":      volumeName,
+			"backendUUID": volume.BackendUUID,
+			"dependents":  strings.Join(dependents, ", "),
+		}).Info("Soft deleting volume, which will be deleted once its dependent snapshots and clones are deleted.")
 		volume.State = storage.VolumeStateDeleting
 		if updateErr := o.updateVolumeOnPersistentStore(volume); updateErr != nil {
 			log.WithFields(log.Fields{
@@ -2542,13 +3263,27 @@ func (o *TridentOrchestrator) deleteVolume(volumeName string) error {
 			return err
 		}
 		delete(o.volumes, volumeName)
+		delete(o.volumeUsage, volumeName)
+		delete(o.volumeConditions, volumeName)
 		return nil
 	}
 
 	// Note that this call will only return an error if the backend actually
 	// fails to delete the volume.  If the volume does not exist on the backend,
 	// the driver will not return an error.  Thus, we're fine.
-	if err := volumeBackend.RemoveVolume(volume.Config); err != nil {
+	// The volume is destroyed without holding the orchestrator lock, so a slow backend doesn't
+	// block operations on other volumes.  The backend may be updated meanwhile, so look it up again.
+	o.deletingVolumes[volumeName] = true
+	o.withBackendUnlocked(volumeBackend, func() {
+		err = volumeBackend.DestroyVolume(ctx, volume.Config)
+	})
+	delete(o.deletingVolumes, volumeName)
+	volumeBackend = o.currentBackend(volumeBackend)
+	if err == nil {
+		volumeBackend.RemoveCachedVolume(volumeName)
+	}
+	o.recordBackendOperation(volumeBackend, "volume_delete", err)
+	if err != nil {
 		if _, ok := err.(*storage.NotManagedError); !ok {
 			log.WithFields(log.Fields{
 				"volume":      volumeName,
@@ -2568,23 +3303,71 @@ func (o *TridentOrchestrator) deleteVolume(volumeName string) error {
 		return err
 	}
 
-	if volumeBackend.State.IsDeleting() && !volumeBackend.HasVolumes() {
-		if err := o.storeClient.DeleteBackend(volumeBackend); err != nil {
-			log.WithFields(log.Fields{
-				"backendUUID": volume.BackendUUID,
-				"volume":      volumeName,
-			}).Error("Unable to delete offline backend from the backing store" +
-				" after its last volume was deleted.  Delete the volume again" +
-				" to remove the backend.")
-			return err
-		}
-		volumeBackend.Terminate()
-		delete(o.backends, volume.BackendUUID)
+	if err := o.deleteBackendWithoutVolumes(volumeBackend, volumeName); err != nil {
+		return err
 	}
 	delete(o.volumes, volumeName)
+	delete(o.migrations, volumeName)
+	delete(o.volumeUsage, volumeName)
+	delete(o.volumeConditions, volumeName)
+
+	o.deleteSoftDeletedCloneSource(volume)
+	return nil
+}
+
+// deleteBackendWithoutVolumes deletes a backend that is being deleted once its last volume is gone.
+func (o *TridentOrchestrator) deleteBackendWithoutVolumes(backend *storage.Backend, volumeName string) error {
+
+	if !backend.State.IsDeleting() || backend.HasVolumes() || o.pendingBackendOperations[backend.BackendUUID] > 0 {
+		return nil
+	}
+	if err := o.storeClient.DeleteBackend(backend); err != nil {
+		log.WithFields(log.Fields{
+			"backendUUID": backend.BackendUUID,
+			"volume":      volumeName,
+		}).Error("Unable to delete offline backend from the backing store" +
+			" after its last volume was deleted.  Delete the volume again" +
+			" to remove the backend.")
+		return err
+	}
+	backend.Terminate()
+	delete(o.backends, backend.BackendUUID)
 	return nil
 }
 
+// deleteSoftDeletedCloneSource queues the hard deletion of the source of a deleted clone if the
+// source was soft deleted and the clone was its last dependent.  The source is deleted by the
+// deletion retry monitor, under the source's own lock and with a delete transaction, so a failure
+// is retried like that of any other deletion.
+func (o *TridentOrchestrator) deleteSoftDeletedCloneSource(clone *storage.Volume) {
+
+	sourceName := clone.Config.CloneSourceVolume
+	source, ok := o.volumes[sourceName]
+	if !ok || !source.State.IsDeleting() || o.volumeDeleteInProgress(sourceName) {
+		return
+	}
+
+	dependents, err := o.volumeDependents(sourceName)
+	if err != nil || len(dependents) > 0 {
+		return
+	}
+
+	log.WithFields(log.Fields{
+		"volume":      sourceName,
+		"backendUUID": source.BackendUUID,
+		"clone":       clone.Config.Name,
+	}).Debug("Queueing hard deletion of volume after its last dependent clone was deleted.")
+
+	if err = o.queueVolumeDeletion(source); err != nil {
+		log.WithFields(log.Fields{
+			"volume": sourceName,
+			"clone":  clone.Config.Name,
+			"error":  err,
+		}).Warnf("Unable to queue deletion of volume after its last dependent clone was deleted. Delete "+
+			"the volume again using %s.", config.OrchestratorClientName)
+	}
+}
+
 func (o *TridentOrchestrator) deleteVolumeFromPersistentStoreIgnoreError(volume *storage.Volume) error {
 	// Ignore failures to find the volume being deleted, as this may be called
 	// during recovery of a volume that has already been deleted from the store.
@@ -2602,13 +3385,16 @@ func (o *TridentOrchestrator) deleteVolumeFromPersistentStoreIgnoreError(volume
 // DeleteVolume does the necessary set up to delete a volume during the course
 // of normal operation, verifying that the volume is present in Trident and
 // creating a transaction to ensure that the delete eventually completes.
-func (o *TridentOrchestrator) DeleteVolume(volumeName string) (err error) {
+func (o *TridentOrchestrator) DeleteVolume(ctx context.Context, volumeName string) (err error) {
 	if o.bootstrapError != nil {
 		return o.bootstrapError
 	}
 
 	defer recordTiming("volume_delete", &err)()
 
+	o.volumeLocks.Lock(volumeName)
+	defer o.volumeLocks.Unlock(volumeName)
+
 	o.mutex.Lock()
 	defer o.mutex.Unlock()
 	defer o.updateMetrics()
@@ -2623,6 +3409,30 @@ func (o *TridentOrchestrator) DeleteVolume(volumeName string) (err error) {
 			"backendUUID": volume.BackendUUID,
 		}).Warnf("Delete operation is likely to fail with an orphaned volume.")
 	}
+	if o.volumeMigrationInProgress(volumeName) {
+		return fmt.Errorf("volume %s is being migrated", volumeName)
+	}
+
+	// Deleting a volume whose deletion failed earlier retries it once its backoff has expired, so
+	// repeated requests from the container orchestrator don't use up the attempts.  Automatic
+	// retries that were exhausted are resumed at once.
+	if volTxn, ok := o.pendingDeletions[volumeName]; ok {
+		deletion := volTxn.VolumeDeletion
+		if deletion.State == storage.DeletionStateFailed {
+			deletion.State = storage.DeletionStatePending
+			deletion.Attempts = 0
+			deletion.NextAttempt = ""
+		}
+		if !deletion.IsDue() {
+			return fmt.Errorf("deletion of volume %s is queued and will be retried at %s; %s",
+				volumeName, deletion.NextAttempt, deletion.LastError)
+		}
+		return o.retryVolumeDeletion(volTxn)
+	}
+
+	if o.volumeDeleteInProgress(volumeName) {
+		return fmt.Errorf("volume %s is being deleted", volumeName)
+	}
 
 	volTxn := &storage.VolumeTransaction{
 		Config: volume.Config,
@@ -2633,6 +3443,10 @@ func (o *TridentOrchestrator) DeleteVolume(volumeName string) (err error) {
 	}
 
 	defer func() {
+		// A deferred deletion keeps its transaction until it completes
+		if _, ok := o.pendingDeletions[volumeName]; ok {
+			return
+		}
 		errTxn := o.DeleteVolumeTransaction(volTxn)
 		if errTxn != nil {
 			log.WithFields(log.Fields{
@@ -2653,7 +3467,114 @@ func (o *TridentOrchestrator) DeleteVolume(volumeName string) (err error) {
 		}
 	}()
 
-	return o.deleteVolume(volumeName)
+	ctx, cancel := o.operationContext(ctx, OperationDeleteVolume)
+	defer cancel()
+
+	if err = o.deleteVolume(ctx, volumeName); err != nil {
+		// If the volume still exists, its storage couldn't be deleted, so try again later
+		if _, ok := o.volumes[volumeName]; ok {
+			return o.deferVolumeDeletion(volTxn, err)
+		}
+	}
+	return err
+}
+
+// UnmanageVolume removes a volume and its snapshots from Trident without deleting their storage, the
+// inverse of importing the volume, so it may be handed back to the storage administrator.  It returns
+// the name of the volume on the storage system, which is storageName if that is set, the original
+// name of an imported volume, or else the volume's internal name.
+func (o *TridentOrchestrator) UnmanageVolume(volumeName, storageName string) (name string, err error) {
+	if o.bootstrapError != nil {
+		return "", o.bootstrapError
+	}
+
+	defer recordTiming("volume_unmanage", &err)()
+
+	o.volumeLocks.Lock(volumeName)
+	defer o.volumeLocks.Unlock(volumeName)
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	defer o.updateMetrics()
+
+	volume, ok := o.volumes[volumeName]
+	if !ok {
+		return "", utils.NotFoundError(fmt.Sprintf("volume %s not found", volumeName))
+	}
+	if volume.State.IsDeleting() || o.volumeDeleteInProgress(volumeName) {
+		return "", utils.VolumeDeletingError(fmt.Sprintf("volume %s is being deleted", volumeName))
+	}
+	if _, ok := o.pendingDeletions[volumeName]; ok {
+		return "", fmt.Errorf("volume %s is being deleted", volumeName)
+	}
+	if o.volumeMigrationInProgress(volumeName) {
+		return "", fmt.Errorf("volume %s is being migrated", volumeName)
+	}
+	if len(volume.PublishedNodes) > 0 {
+		return "", fmt.Errorf("volume %s is published to nodes %s; unpublish it before unmanaging it",
+			volumeName, strings.Join(volume.PublishedNodes, ", "))
+	}
+	clones, err := o.dependentClones(volumeName)
+	if err != nil {
+		return "", err
+	}
+	if len(clones) > 0 {
+		return "", fmt.Errorf("volume %s has dependent clones %s; unmanage or delete them first",
+			volumeName, strings.Join(clones, ", "))
+	}
+	snapshots, err := o.volumeSnapshots(volumeName)
+	if err != nil {
+		return "", err
+	}
+
+	// A volume whose backend is gone has no storage to rename
+	name = volume.Config.InternalName
+	backend, backendFound := o.backends[volume.BackendUUID]
+	if backendFound {
+		if name, err = backend.UnmanageVolume(volume.Config, storageName); err != nil {
+			return "", err
+		}
+	}
+
+	for _, snapshot := range snapshots {
+		if err = o.deleteSnapshotFromPersistentStoreIgnoreError(snapshot); err != nil {
+			return "", err
+		}
+		delete(o.snapshots, snapshot.ID())
+	}
+	if err = o.deleteVolumeFromPersistentStoreIgnoreError(volume); err != nil {
+		// Leave the storage as Trident expects to find it
+		if backendFound {
+			if name != volume.Config.InternalName {
+				if renameErr := backend.Driver.Rename(name, volume.Config.InternalName); renameErr != nil {
+					log.WithFields(log.Fields{
+						"volume": volumeName,
+						"name":   name,
+						"error":  renameErr,
+					}).Error("Unable to rename volume back after failing to unmanage it.")
+				}
+			}
+			backend.AddCachedVolume(volume)
+		}
+		return "", err
+	}
+	delete(o.volumes, volumeName)
+	delete(o.migrations, volumeName)
+	delete(o.volumeUsage, volumeName)
+	delete(o.volumeConditions, volumeName)
+
+	log.WithFields(log.Fields{
+		"volume":      volumeName,
+		"storageName": name,
+		"snapshots":   len(snapshots),
+	}).Info("Unmanaged volume.")
+
+	if backendFound {
+		if err = o.deleteBackendWithoutVolumes(backend, volumeName); err != nil {
+			return "", err
+		}
+	}
+	return name, nil
 }
 
 func (o *TridentOrchestrator) ListVolumesByPlugin(pluginName string) (volumes []*storage.VolumeExternal, err error) {
@@ -2679,7 +3600,7 @@ func (o *TridentOrchestrator) ListVolumesByPlugin(pluginName string) (volumes []
 }
 
 func (o *TridentOrchestrator) PublishVolume(
-	volumeName string, publishInfo *utils.VolumePublishInfo,
+	ctx context.Context, volumeName string, publishInfo *utils.VolumePublishInfo,
 ) (err error) {
 	if o.bootstrapError != nil {
 		return o.bootstrapError
@@ -2697,6 +3618,24 @@ func (o *TridentOrchestrator) PublishVolume(
 	if volume.State.IsDeleting() {
 		return utils.VolumeDeletingError(fmt.Sprintf("volume %s is deleting", volumeName))
 	}
+	// Writes made while a volume is copied to another backend would be lost
+	if migration, ok := o.migrations[volumeName]; ok && !migration.State.IsDone() &&
+		migration.Method != storage.MigrationMethodVolumeMove {
+		return fmt.Errorf("volume %s is being migrated to backend %s; it may be published once the "+
+			"migration completes", volumeName, migration.DestinationBackend)
+	}
+
+	// Only volumes that tolerate concurrent access may be published to a second node
+	nodeName := publishInfo.HostName
+	if nodeName != "" && !volume.Config.AllowsMultiNodePublish() {
+		for _, publishedNode := range volume.PublishedNodes {
+			if publishedNode != nodeName {
+				return utils.VolumePublishedError(fmt.Sprintf("volume %s is already published to node %s; "+
+					"only raw-block volumes with a ReadWriteMany or ReadOnlyMany access mode may be published "+
+					"to several nodes", volumeName, publishedNode))
+			}
+		}
+	}
 
 	nodes := make([]*utils.Node, 0)
 	for _, node := range o.nodes {
@@ -2704,7 +3643,83 @@ func (o *TridentOrchestrator) PublishVolume(
 	}
 	publishInfo.Nodes = nodes
 	publishInfo.BackendUUID = volume.BackendUUID
-	return o.backends[volume.BackendUUID].PublishVolume(volume.Config, publishInfo)
+
+	ctx, cancel := o.operationContext(ctx, OperationPublishVolume)
+	defer cancel()
+
+	if err = o.backends[volume.BackendUUID].PublishVolume(ctx, volume.Config, publishInfo); err != nil {
+		return err
+	}
+
+	if nodeName != "" && volume.AddPublishedNode(nodeName) {
+		if err = o.storeClient.UpdateVolume(volume); err != nil {
+			volume.RemovePublishedNode(nodeName)
+			return err
+		}
+	}
+	return nil
+}
+
+// UnpublishVolume records that a volume is no longer published to a node, and revokes the node's access
+// to the volume if its backend grants access per node.  An empty node name unpublishes the volume from
+// every node.
+func (o *TridentOrchestrator) UnpublishVolume(ctx context.Context, volumeName, nodeName string) (err error) {
+	if o.bootstrapError != nil {
+		return o.bootstrapError
+	}
+
+	defer recordTiming("volume_unpublish", &err)()
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	volume, ok := o.volumes[volumeName]
+	if !ok {
+		return utils.NotFoundError(fmt.Sprintf("volume %s not found", volumeName))
+	}
+
+	nodeNames := []string{nodeName}
+	if nodeName == "" {
+		nodeNames = append([]string{}, volume.PublishedNodes...)
+	}
+
+	ctx, cancel := o.operationContext(ctx, OperationPublishVolume)
+	defer cancel()
+
+	for _, name := range nodeNames {
+		if err = o.unpublishVolumeFromNode(ctx, volume, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (o *TridentOrchestrator) unpublishVolumeFromNode(
+	ctx context.Context, volume *storage.Volume, nodeName string,
+) error {
+
+	publishInfo := &utils.VolumePublishInfo{
+		HostName:    nodeName,
+		BackendUUID: volume.BackendUUID,
+	}
+	if node, ok := o.nodes[nodeName]; ok {
+		publishInfo.HostIQN = []string{node.IQN}
+		publishInfo.HostIP = node.IPs
+	}
+
+	if backend, ok := o.backends[volume.BackendUUID]; ok {
+		if err := backend.UnpublishVolume(ctx, volume.Config, publishInfo); err != nil {
+			return err
+		}
+	}
+
+	if volume.RemovePublishedNode(nodeName) {
+		if err := o.storeClient.UpdateVolume(volume); err != nil {
+			volume.AddPublishedNode(nodeName)
+			return err
+		}
+	}
+	return nil
 }
 
 // AttachVolume mounts a volume to the local host.  This method is currently only used by Docker,
@@ -2837,7 +3852,7 @@ func (o *TridentOrchestrator) SetVolumeState(volumeName string, state storage.Vo
 
 // CreateSnapshot creates a snapshot of the given volume
 func (o *TridentOrchestrator) CreateSnapshot(
-	snapshotConfig *storage.SnapshotConfig,
+	ctx context.Context, snapshotConfig *storage.SnapshotConfig,
 ) (externalSnapshot *storage.SnapshotExternal, err error) {
 
 	var (
@@ -2870,6 +3885,12 @@ func (o *TridentOrchestrator) CreateSnapshot(
 	if volume.State.IsDeleting() {
 		return nil, utils.VolumeDeletingError(fmt.Sprintf("source volume %s is deleting", snapshotConfig.VolumeName))
 	}
+	if o.volumeMigrationInProgress(snapshotConfig.VolumeName) {
+		return nil, fmt.Errorf("source volume %s is being migrated", snapshotConfig.VolumeName)
+	}
+	if o.volumeDeleteInProgress(snapshotConfig.VolumeName) {
+		return nil, fmt.Errorf("source volume %s is being deleted", snapshotConfig.VolumeName)
+	}
 
 	// Get the backend
 	if backend, ok = o.backends[volume.BackendUUID]; !ok {
@@ -2896,8 +3917,12 @@ func (o *TridentOrchestrator) CreateSnapshot(
 		err = o.addSnapshotCleanup(err, backend, snapshot, txn, snapshotConfig)
 	}()
 
+	ctx, cancel := o.operationContext(ctx, OperationCreateSnapshot)
+	defer cancel()
+
 	// Create the snapshot
-	snapshot, err = backend.CreateSnapshot(snapshotConfig, volume.Config)
+	snapshot, err = backend.CreateSnapshot(ctx, snapshotConfig, volume.Config)
+	o.recordBackendOperation(backend, "snapshot_create", err)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create snapshot %s for volume %s on backend %s: %v",
 			snapshotConfig.Name, snapshotConfig.VolumeName, backend.Name, err)
@@ -2929,8 +3954,11 @@ func (o *TridentOrchestrator) addSnapshotCleanup(
 		// 2.  We failed to save the snapshot to the persistent store.
 		//     In this case, we need to remove the snapshot from the backend.
 		if backend != nil && snapshot != nil {
-			// We succeeded in adding the snapshot to the backend; now delete it.
-			cleanupErr = backend.DeleteSnapshot(snapshot.Config, volTxn.Config)
+			// We succeeded in adding the snapshot to the backend; now delete it, even if the
+			// creation was canceled.
+			ctx, cancel := o.operationContext(context.Background(), OperationDeleteSnapshot)
+			cleanupErr = backend.DeleteSnapshot(ctx, snapshot.Config, volTxn.Config)
+			cancel()
 			if cleanupErr != nil {
 				cleanupErr = fmt.Errorf("unable to delete snapshot from backend during cleanup:  %v", cleanupErr)
 			}
@@ -2979,13 +4007,190 @@ func (o *TridentOrchestrator) GetSnapshot(volumeName, snapshotName string) (
 	if !found {
 		return nil, utils.NotFoundError(fmt.Sprintf("snapshot %v was not found", snapshotName))
 	}
+	if snapshot.State.IsCreating() {
+		if snapshot, found = o.refreshSnapshotState(snapshot); !found {
+			return nil, utils.NotFoundError(fmt.Sprintf("snapshot %v was not found", snapshotName))
+		}
+	}
 	return snapshot.ConstructExternal(), nil
 }
 
+// refreshSnapshotState reads the state of a snapshot that is still being created, such as an FSx backup,
+// from its backend, and persists the snapshot if its state has changed.  The caller must hold the
+// orchestrator lock, which is released while the backend is read, so the snapshot is looked up again
+// and returned, or not found if it was deleted meanwhile.
+func (o *TridentOrchestrator) refreshSnapshotState(snapshot *storage.Snapshot) (*storage.Snapshot, bool) {
+
+	volume, ok := o.volumes[snapshot.Config.VolumeName]
+	if !ok {
+		return snapshot, true
+	}
+	backend, ok := o.backends[volume.BackendUUID]
+	if !ok {
+		return snapshot, true
+	}
+
+	var discovered *storage.Snapshot
+	var err error
+	snapConfig := snapshot.ConstructClone().Config
+	o.withBackendUnlocked(backend, func() {
+		discovered, err = backend.GetSnapshot(snapConfig)
+	})
+
+	current, ok := o.snapshots[snapshot.ID()]
+	if !ok {
+		return nil, false
+	}
+	if err != nil {
+		log.WithFields(log.Fields{
+			"snapshot": current.ID(),
+			"error":    err,
+		}).Warning("Could not refresh snapshot state.")
+		return current, true
+	}
+
+	// Drivers that create snapshots synchronously may not report a state
+	state := discovered.State
+	if state == "" {
+		state = storage.SnapshotStateOnline
+	}
+	if state == current.State {
+		return current, true
+	}
+
+	log.WithFields(log.Fields{
+		"snapshot": current.ID(),
+		"oldState": current.State,
+		"newState": state,
+	}).Info("Snapshot state changed.")
+
+	current.State = state
+	current.Created = discovered.Created
+	current.SizeBytes = discovered.SizeBytes
+	if err := o.rewriteSnapshot(current, true); err != nil {
+		log.WithFields(log.Fields{
+			"snapshot": current.ID(),
+			"error":    err,
+		}).Error("Could not persist snapshot state.")
+	}
+	return current, true
+}
+
+// RestoreSnapshot reverts a volume to one of its snapshots, discarding the changes made to the volume since
+// the snapshot was created.  The volume must not be published, since its filesystem would change under the
+// nodes using it.  Storage systems such as ONTAP delete the snapshots newer than the restored one, so those
+// snapshots are forgotten if their backend no longer reports them.
+// RestoreSnapshot reverts a volume to one of its snapshots, unless the restore's preflight checks fail.
+// With force, a volume that is still published to nodes is restored anyway.
+func (o *TridentOrchestrator) RestoreSnapshot(
+	ctx context.Context, volumeName, snapshotName string, force bool,
+) (err error) {
+	if o.bootstrapError != nil {
+		return o.bootstrapError
+	}
+
+	defer recordTiming("snapshot_restore", &err)()
+
+	o.volumeLocks.Lock(volumeName)
+	defer o.volumeLocks.Unlock(volumeName)
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	defer o.updateMetrics()
+
+	snapshot, volume, err := o.getSnapshotToRestore(volumeName, snapshotName)
+	if err != nil {
+		return err
+	}
+	backend, ok := o.backends[volume.BackendUUID]
+	if !ok {
+		return utils.NotFoundError(fmt.Sprintf("backend %s for volume %s not found", volume.BackendUUID,
+			volumeName))
+	}
+	if err = snapshotRestoreError(o.checkSnapshotRestore(snapshot, volume), force); err != nil {
+		return err
+	}
+	if len(volume.PublishedNodes) > 0 {
+		log.WithFields(log.Fields{
+			"volume":   volumeName,
+			"snapshot": snapshotName,
+			"nodes":    strings.Join(volume.PublishedNodes, ", "),
+		}).Warning("Forcing restore of a published volume.")
+	}
+
+	ctx, cancel := o.operationContext(ctx, OperationRestoreSnapshot)
+	defer cancel()
+
+	err = backend.RestoreSnapshot(ctx, snapshot.Config, volume.Config)
+	o.recordBackendOperation(backend, "snapshot_restore", err)
+	if err != nil {
+		return fmt.Errorf("failed to restore snapshot %s of volume %s on backend %s: %v", snapshotName,
+			volumeName, backend.Name, err)
+	}
+
+	log.WithFields(log.Fields{
+		"volume":   volumeName,
+		"snapshot": snapshotName,
+		"backend":  backend.Name,
+	}).Info("Restored volume from snapshot.")
+
+	o.forgetSnapshotsNewerThan(snapshot, volume, backend)
+	return nil
+}
+
+// forgetSnapshotsNewerThan removes from Trident's records the snapshots of a restored volume that were
+// created after the restored snapshot and that its backend no longer reports.  Failures are only logged,
+// as the restore succeeded and drift detection will report any snapshot that is left behind.  The caller
+// must hold the orchestrator lock.
+func (o *TridentOrchestrator) forgetSnapshotsNewerThan(
+	restored *storage.Snapshot, volume *storage.Volume, backend *storage.Backend,
+) {
+
+	restoredTime, err := time.Parse(time.RFC3339, restored.Created)
+	if err != nil {
+		return
+	}
+
+	backendSnapshots, err := backend.GetSnapshots(volume.Config)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"volume": volume.Config.Name,
+			"error":  err,
+		}).Warning("Could not check for snapshots newer than restored snapshot.")
+		return
+	}
+	reported := make(map[string]bool)
+	for _, backendSnapshot := range backendSnapshots {
+		reported[backendSnapshot.Config.InternalName] = true
+	}
+
+	for _, snapshot := range o.snapshots {
+		if snapshot.Config.VolumeName != restored.Config.VolumeName || reported[snapshot.Config.InternalName] {
+			continue
+		}
+		created, err := time.Parse(time.RFC3339, snapshot.Created)
+		if err != nil || !created.After(restoredTime) {
+			continue
+		}
+
+		logFields := log.Fields{
+			"volume":   snapshot.Config.VolumeName,
+			"snapshot": snapshot.Config.Name,
+		}
+		if err = o.deleteSnapshotFromPersistentStoreIgnoreError(snapshot); err != nil {
+			log.WithFields(logFields).WithField("error", err).Warning(
+				"Could not forget snapshot deleted by restore.")
+			continue
+		}
+		delete(o.snapshots, snapshot.ID())
+		log.WithFields(logFields).Info("Forgot snapshot deleted by restoring an older snapshot.")
+	}
+}
+
 // deleteSnapshot does the necessary work to delete a snapshot entirely.  It does
 // not construct a transaction, nor does it take locks; it assumes that the caller will
 // take care of both of these.
-func (o *TridentOrchestrator) deleteSnapshot(snapshotConfig *storage.SnapshotConfig) error {
+func (o *TridentOrchestrator) deleteSnapshot(ctx context.Context, snapshotConfig *storage.SnapshotConfig) error {
 
 	snapshotID := snapshotConfig.ID()
 	snapshot, ok := o.snapshots[snapshotID]
@@ -3007,7 +4212,9 @@ func (o *TridentOrchestrator) deleteSnapshot(snapshotConfig *storage.SnapshotCon
 	// Note that this call will only return an error if the backend actually
 	// fails to delete the snapshot.  If the snapshot does not exist on the backend,
 	// the driver will not return an error.  Thus, we're fine.
-	if err := backend.DeleteSnapshot(snapshot.Config, volume.Config); err != nil {
+	err := backend.DeleteSnapshot(ctx, snapshot.Config, volume.Config)
+	o.recordBackendOperation(backend, "snapshot_delete", err)
+	if err != nil {
 		log.WithFields(log.Fields{
 			"volume":   snapshot.Config.VolumeName,
 			"snapshot": snapshot.Config.Name,
@@ -3033,7 +4240,7 @@ func (o *TridentOrchestrator) deleteSnapshot(snapshotConfig *storage.SnapshotCon
 			"backendUUID":               volume.BackendUUID,
 			"volume.State":              volume.State,
 		}).Debug("Hard deleting volume.")
-		return o.deleteVolume(snapshotConfig.VolumeName)
+		return o.deleteVolume(ctx, snapshotConfig.VolumeName)
 	}
 
 	return nil
@@ -3055,7 +4262,7 @@ func (o *TridentOrchestrator) deleteSnapshotFromPersistentStoreIgnoreError(snaps
 }
 
 // DeleteSnapshot deletes a snapshot of the given volume
-func (o *TridentOrchestrator) DeleteSnapshot(volumeName, snapshotName string) (err error) {
+func (o *TridentOrchestrator) DeleteSnapshot(ctx context.Context, volumeName, snapshotName string) (err error) {
 	if o.bootstrapError != nil {
 		return o.bootstrapError
 	}
@@ -3149,8 +4356,11 @@ func (o *TridentOrchestrator) DeleteSnapshot(volumeName, snapshotName string) (e
 		}
 	}()
 
+	ctx, cancel := o.operationContext(ctx, OperationDeleteSnapshot)
+	defer cancel()
+
 	// Delete the snapshot
-	return o.deleteSnapshot(snapshot.Config)
+	return o.deleteSnapshot(ctx, snapshot.Config)
 }
 
 func (o *TridentOrchestrator) ListSnapshots() (snapshots []*storage.SnapshotExternal, err error) {
@@ -3285,7 +4495,7 @@ func (o *TridentOrchestrator) ReloadVolumes() (err error) {
 }
 
 // ResizeVolume resizes a volume to the new size.
-func (o *TridentOrchestrator) ResizeVolume(volumeName, newSize string) (err error) {
+func (o *TridentOrchestrator) ResizeVolume(ctx context.Context, volumeName, newSize string) (err error) {
 	if o.bootstrapError != nil {
 		return o.bootstrapError
 	}
@@ -3309,6 +4519,16 @@ func (o *TridentOrchestrator) ResizeVolume(volumeName, newSize string) (err erro
 	if volume.State.IsDeleting() {
 		return utils.VolumeDeletingError(fmt.Sprintf("volume %s is deleting", volumeName))
 	}
+	if o.volumeMigrationInProgress(volumeName) {
+		return fmt.Errorf("volume %s is being migrated", volumeName)
+	}
+	if o.volumeDeleteInProgress(volumeName) {
+		return fmt.Errorf("volume %s is being deleted", volumeName)
+	}
+	growth := quotaVolumeSize(newSize) - quotaVolumeSize(volume.Config.Size)
+	if err = o.checkQuotas(volume.Config, growth, 0); err != nil {
+		return err
+	}
 
 	// Create a new config for the volume transaction
 	cloneConfig := volume.Config.ConstructClone()
@@ -3333,15 +4553,18 @@ func (o *TridentOrchestrator) ResizeVolume(volumeName, newSize string) (err erro
 		err = o.resizeVolumeCleanup(err, volume, volTxn)
 	}()
 
+	ctx, cancel := o.operationContext(ctx, OperationResizeVolume)
+	defer cancel()
+
 	// Resize the volume.
-	return o.resizeVolume(volume, newSize)
+	return o.resizeVolume(ctx, volume, newSize)
 }
 
 // resizeVolume does the necessary work to resize a volume. It doesn't
 // construct a transaction, nor does it take locks; it assumes that the
 // caller will take care of both of these. It also assumes that the volume
 // exists in memory.
-func (o *TridentOrchestrator) resizeVolume(volume *storage.Volume, newSize string) error {
+func (o *TridentOrchestrator) resizeVolume(ctx context.Context, volume *storage.Volume, newSize string) error {
 	volumeBackend, found := o.backends[volume.BackendUUID]
 	if !found {
 		log.WithFields(log.Fields{
@@ -3354,7 +4577,9 @@ func (o *TridentOrchestrator) resizeVolume(volume *storage.Volume, newSize strin
 	if volume.Config.Size != newSize {
 		// If the resize is successful the driver updates the volume.Config.Size, as a side effect, with the actual
 		// byte size of the expanded volume.
-		if err := volumeBackend.ResizeVolume(volume.Config, newSize); err != nil {
+		err := volumeBackend.ResizeVolume(ctx, volume.Config, newSize)
+		o.recordBackendOperation(volumeBackend, "volume_resize", err)
+		if err != nil {
 			log.WithFields(log.Fields{
 				"volume":          volume.Config.Name,
 				"volume_internal": volume.Config.InternalName,
@@ -3448,6 +4673,10 @@ func (o *TridentOrchestrator) resizeVolumeCleanup(
 //  RawBlock                RWX           NFS                           **Error**
 //  RawBlock                RWX           iSCSI                         iSCSI
 //
+// Block volumes with a filesystem may be created with the ReadOnlyMany access mode, but like all block
+// volumes other than ReadWriteMany and ReadOnlyMany raw-block volumes, they may only be published to one
+// node at a time.  See VolumeConfig.AllowsMultiNodePublish.
+//
 func (o *TridentOrchestrator) getProtocol(
 	volumeMode config.VolumeMode, accessMode config.AccessMode, protocol config.Protocol,
 ) (config.Protocol, error) {
@@ -3505,6 +4734,42 @@ func (o *TridentOrchestrator) AddStorageClass(scConfig *storageclass.Config) (
 	if _, ok := o.storageClasses[sc.GetName()]; ok {
 		return nil, fmt.Errorf("storage class %s already exists", sc.GetName())
 	}
+	if err = utils.ValidateMountOptions(scConfig.MountOptions); err != nil {
+		return nil, fmt.Errorf("invalid mount options for storage class %s; %v", sc.GetName(), err)
+	}
+	if scConfig.RootOwnership != "" {
+		if _, _, err = utils.ParseOwnership(scConfig.RootOwnership); err != nil {
+			return nil, fmt.Errorf("invalid root ownership for storage class %s; %v", sc.GetName(), err)
+		}
+	}
+	if err = utils.ValidateFormatOptions(scConfig.FormatOptions); err != nil {
+		return nil, fmt.Errorf("invalid format options for storage class %s; %v", sc.GetName(), err)
+	}
+	if err = utils.ValidateFsckPolicy(scConfig.FsckPolicy); err != nil {
+		return nil, fmt.Errorf("invalid fsck policy for storage class %s; %v", sc.GetName(), err)
+	}
+	if err = utils.ValidateIOScheduler(scConfig.IOScheduler); err != nil {
+		return nil, fmt.Errorf("invalid I/O scheduler for storage class %s; %v", sc.GetName(), err)
+	}
+	if err = utils.ValidateReadAheadKB(scConfig.ReadAheadKB); err != nil {
+		return nil, fmt.Errorf("invalid read-ahead for storage class %s; %v", sc.GetName(), err)
+	}
+	if err = utils.ValidateNrRequests(scConfig.NrRequests); err != nil {
+		return nil, fmt.Errorf("invalid request queue depth for storage class %s; %v", sc.GetName(), err)
+	}
+	if scConfig.ExportPolicy != "" {
+		if err = utils.ValidateExportPolicyName(scConfig.ExportPolicy); err != nil {
+			return nil, fmt.Errorf("invalid export policy for storage class %s; %v", sc.GetName(), err)
+		}
+	}
+	if scConfig.IgroupName != "" {
+		if err = utils.ValidateIgroupName(scConfig.IgroupName); err != nil {
+			return nil, fmt.Errorf("invalid igroup for storage class %s; %v", sc.GetName(), err)
+		}
+	}
+	if err = storage.ValidateDeletionPolicy(scConfig.DeletionPolicy); err != nil {
+		return nil, fmt.Errorf("invalid deletion policy for storage class %s; %v", sc.GetName(), err)
+	}
 	err = o.storeClient.AddStorageClass(sc)
 	if err != nil {
 		return nil, err
@@ -3635,11 +4900,19 @@ func (o *TridentOrchestrator) AddNode(node *utils.Node) (err error) {
 	defer o.mutex.Unlock()
 	defer o.updateMetrics()
 
+	// Nodes register periodically, so a registration that changes nothing but the time the node
+	// was last seen doesn't need the backends to reconcile node access
+	node.LastSeen = time.Now().UTC().Format(time.RFC3339)
+	knownNode, found := o.nodes[node.Name]
+
 	if err := o.storeClient.AddOrUpdateNode(node); err != nil {
 		return err
 	}
 	o.nodes[node.Name] = node
 
+	if found && !nodeAccessChanged(knownNode, node) {
+		return nil
+	}
 	return o.reconcileNodeAccessOnAllBackends()
 }
 
@@ -3657,7 +4930,7 @@ func (o *TridentOrchestrator) GetNode(nName string) (node *utils.Node, err error
 	if !found {
 		return nil, utils.NotFoundError(fmt.Sprintf("node %v was not found", nName))
 	}
-	return node, nil
+	return o.updateNodePublishedVolumes(node), nil
 }
 
 func (o *TridentOrchestrator) ListNodes() (nodes []*utils.Node, err error) {
@@ -3672,7 +4945,7 @@ func (o *TridentOrchestrator) ListNodes() (nodes []*utils.Node, err error) {
 
 	nodes = make([]*utils.Node, 0, len(o.nodes))
 	for _, node := range o.nodes {
-		nodes = append(nodes, node)
+		nodes = append(nodes, o.updateNodePublishedVolumes(node))
 	}
 	return nodes, nil
 }
@@ -3,6 +3,7 @@
 package core
 
 import (
+	"context"
 	"encoding/json"
 	"flag"
 	"fmt"
@@ -14,6 +15,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	log "github.com/sirupsen/logrus"
 
 	"github.com/netapp/trident/config"
@@ -22,6 +24,7 @@ import (
 	"github.com/netapp/trident/storage/fake"
 	sa "github.com/netapp/trident/storage_attribute"
 	storageclass "github.com/netapp/trident/storage_class"
+	drivers "github.com/netapp/trident/storage_drivers"
 	fakedriver "github.com/netapp/trident/storage_drivers/fake"
 	tu "github.com/netapp/trident/storage_drivers/fake/test_utils"
 	"github.com/netapp/trident/utils"
@@ -33,6 +36,8 @@ var (
 	debug  = flag.Bool("debug", false, "Enable debugging output")
 
 	inMemoryClient *persistentstore.InMemoryClient
+
+	ctx = context.Background
 )
 
 func init() {
@@ -83,6 +88,16 @@ func cleanup(t *testing.T, o *TridentOrchestrator) {
 	if err != nil && !persistentstore.MatchKeyNotFoundErr(err) {
 		t.Fatal("Unable to clean up snapshots:  ", err)
 	}
+	nodes, err := o.storeClient.GetNodes()
+	if err != nil && !persistentstore.MatchKeyNotFoundErr(err) {
+		t.Fatal("Unable to retrieve nodes:  ", err)
+	} else if err == nil {
+		for _, node := range nodes {
+			if err := o.storeClient.DeleteNode(node); err != nil {
+				t.Fatalf("Unable to clean up node %s:  %v", node.Name, err)
+			}
+		}
+	}
 	if *etcdV2 == "" && *etcdV3 == "" {
 		// Clear the InMemoryClient state so that it looks like we're
 		// bootstrapping afresh next time.
@@ -253,7 +268,7 @@ func runDeleteTest(
 		}
 		orchestrator.mutex.Unlock()
 	}
-	err := orchestrator.DeleteVolume(d.name)
+	err := orchestrator.DeleteVolume(ctx(), d.name)
 	if err == nil && !d.expectedSuccess {
 		t.Errorf("%s:  volume delete succeeded when it should not have.", d.name)
 	} else if err != nil && d.expectedSuccess {
@@ -746,7 +761,7 @@ func TestAddStorageClassVolumes(t *testing.T) {
 			deleteAfterSC: false,
 		},
 	} {
-		vol, err := orchestrator.AddVolume(s.config)
+		vol, err := orchestrator.AddVolume(ctx(), s.config)
 		if err != nil && s.expectedSuccess {
 			t.Errorf("%s:  got unexpected error %v", s.name, err)
 			continue
@@ -1094,7 +1109,7 @@ func TestCloneVolumes(t *testing.T) {
 		},
 	} {
 		// Create the source volume
-		_, err := orchestrator.AddVolume(s.config)
+		_, err := orchestrator.AddVolume(ctx(), s.config)
 		if err != nil {
 			t.Errorf("%s:  got unexpected error %v", s.name, err)
 			continue
@@ -1107,7 +1122,7 @@ func TestCloneVolumes(t *testing.T) {
 			StorageClass:      s.config.StorageClass,
 			CloneSourceVolume: s.config.Name,
 		}
-		cloneResult, err := orchestrator.CloneVolume(cloneConfig)
+		cloneResult, err := orchestrator.CloneVolume(ctx(), cloneConfig)
 		if err != nil {
 			t.Errorf("%s:  got unexpected error %v", s.name, err)
 			continue
@@ -1154,12 +1169,131 @@ func TestCloneVolumes(t *testing.T) {
 	cleanup(t, orchestrator)
 }
 
+func TestCloneVolumeLargerThanSource(t *testing.T) {
+	const (
+		fileBackendName  = "expandCloneFile"
+		blockBackendName = "expandCloneBlock"
+		fileSCName       = "expandCloneFileSC"
+		blockSCName      = "expandCloneBlockSC"
+	)
+
+	orchestrator := getOrchestrator()
+	addBackendStorageClass(t, orchestrator, fileBackendName, fileSCName, config.File)
+	addBackend(t, orchestrator, blockBackendName, config.Block)
+	if _, err := orchestrator.AddStorageClass(&storageclass.Config{
+		Name:       blockSCName,
+		Attributes: map[string]sa.Request{sa.TestingAttribute: sa.NewBoolRequest(true)},
+		Pools:      map[string][]string{blockBackendName: {"primary"}},
+	}); err != nil {
+		t.Fatal("Unable to add storage class: ", err)
+	}
+
+	for _, c := range []struct {
+		scName         string
+		protocol       config.Protocol
+		growFilesystem bool
+	}{
+		{scName: fileSCName, protocol: config.File, growFilesystem: false},
+		{scName: blockSCName, protocol: config.Block, growFilesystem: true},
+	} {
+		sourceName := c.scName + "-source"
+		if _, err := orchestrator.AddVolume(ctx(), tu.GenerateVolumeConfig(sourceName, 1, c.scName,
+			c.protocol)); err != nil {
+			t.Fatal("Unable to create volume: ", err)
+		}
+
+		cloneConfig := tu.GenerateVolumeConfig(c.scName+"-clone", 2, c.scName, c.protocol)
+		cloneConfig.CloneSourceVolume = sourceName
+		clone, err := orchestrator.CloneVolume(ctx(), cloneConfig)
+		if !assert.NoError(t, err, "a clone may be larger than its source") {
+			continue
+		}
+		assert.Equal(t, cloneConfig.Size, clone.Config.Size, "the clone should be expanded")
+		assert.Empty(t, clone.Config.CloneTargetSize)
+		assert.Equal(t, c.growFilesystem, clone.Config.GrowFilesystem)
+
+		stored, err := orchestrator.storeClient.GetVolume(clone.Config.Name)
+		if assert.NoError(t, err) {
+			assert.Equal(t, cloneConfig.Size, stored.Config.Size)
+		}
+
+		// A clone of the clone has its source's filesystem, which may not have been grown yet
+		recloneConfig := tu.GenerateVolumeConfig(c.scName+"-reclone", 2, c.scName, c.protocol)
+		recloneConfig.CloneSourceVolume = clone.Config.Name
+		reclone, err := orchestrator.CloneVolume(ctx(), recloneConfig)
+		if assert.NoError(t, err) {
+			assert.Equal(t, c.growFilesystem, reclone.Config.GrowFilesystem)
+		}
+	}
+
+	cleanup(t, orchestrator)
+}
+
+func TestCloneVolumeIntoPool(t *testing.T) {
+	const (
+		backendName = "clonePoolBackend"
+		scName      = "clonePoolSC"
+		sourceName  = "clonePoolSource"
+	)
+
+	orchestrator := getOrchestrator()
+	pool := func() *fake.StoragePool {
+		return &fake.StoragePool{
+			Attrs: map[string]sa.Offer{sa.TestingAttribute: sa.NewBoolOffer(true)},
+			Bytes: 100 * 1024 * 1024 * 1024,
+		}
+	}
+	configJSON, err := fakedriver.NewFakeStorageDriverConfigJSON(backendName, config.File,
+		map[string]*fake.StoragePool{"primary": pool(), "secondary": pool()}, []fake.Volume{})
+	if err != nil {
+		t.Fatal("Unable to create mock driver config JSON: ", err)
+	}
+	if _, err = orchestrator.AddBackend(configJSON); err != nil {
+		t.Fatal("Unable to add backend: ", err)
+	}
+	if _, err = orchestrator.AddStorageClass(&storageclass.Config{
+		Name:  scName,
+		Pools: map[string][]string{backendName: {"primary"}},
+	}); err != nil {
+		t.Fatal("Unable to add storage class: ", err)
+	}
+	if _, err = orchestrator.AddVolume(ctx(), tu.GenerateVolumeConfig(sourceName, 1, scName,
+		config.File)); err != nil {
+		t.Fatal("Unable to create volume: ", err)
+	}
+
+	cloneConfig := tu.GenerateVolumeConfig("clonePoolMissing", 1, scName, config.File)
+	cloneConfig.CloneSourceVolume = sourceName
+	cloneConfig.Pool = "missing"
+	_, err = orchestrator.CloneVolume(ctx(), cloneConfig)
+	assert.True(t, utils.IsNotFoundError(err), "a clone can't be placed in a pool its backend lacks")
+
+	cloneConfig = tu.GenerateVolumeConfig("clonePoolSecondary", 1, scName, config.File)
+	cloneConfig.CloneSourceVolume = sourceName
+	cloneConfig.Pool = "secondary"
+	cloneConfig.SplitOnClone = "true"
+	clone, err := orchestrator.CloneVolume(ctx(), cloneConfig)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "secondary", clone.Pool, "the clone should be placed in the requested pool")
+		assert.Equal(t, "true", clone.Config.SplitOnClone)
+	}
+
+	cloneConfig = tu.GenerateVolumeConfig("clonePoolDefault", 1, scName, config.File)
+	cloneConfig.CloneSourceVolume = sourceName
+	clone, err = orchestrator.CloneVolume(ctx(), cloneConfig)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "primary", clone.Pool, "the clone should be placed in its source's pool")
+	}
+
+	cleanup(t, orchestrator)
+}
+
 func addBackend(
 	t *testing.T, orchestrator *TridentOrchestrator, backendName string, backendProtocol config.Protocol,
 ) {
 	volumes := []fake.Volume{
-		{"origVolume01", "primary", "primary", 1000000000},
-		{"origVolume02", "primary", "primary", 1000000000},
+		{Name: "origVolume01", RequestedPool: "primary", PhysicalPool: "primary", SizeBytes: 1000000000},
+		{Name: "origVolume02", RequestedPool: "primary", PhysicalPool: "primary", SizeBytes: 1000000000},
 	}
 	configJSON, err := fakedriver.NewFakeStorageDriverConfigJSON(
 		backendName,
@@ -1232,7 +1366,7 @@ func TestBackendUpdateAndDelete(t *testing.T) {
 	}
 	orchestrator.mutex.Unlock()
 
-	_, err := orchestrator.AddVolume(tu.GenerateVolumeConfig(volumeName, 50, scName, config.File))
+	_, err := orchestrator.AddVolume(ctx(), tu.GenerateVolumeConfig(volumeName, 50, scName, config.File))
 	if err != nil {
 		t.Fatal("Unable to create volume: ", err)
 	}
@@ -1411,7 +1545,7 @@ func TestBackendUpdateAndDelete(t *testing.T) {
 	if !backend.Driver.Initialized() {
 		t.Errorf("Deleted backend with volumes %s is not initialized.", backendName)
 	}
-	_, err = orchestrator.AddVolume(tu.GenerateVolumeConfig(offlineVolumeName, 50, scName, config.File))
+	_, err = orchestrator.AddVolume(ctx(), tu.GenerateVolumeConfig(offlineVolumeName, 50, scName, config.File))
 	if err == nil {
 		t.Error("Created volume volume on offline backend.")
 	}
@@ -1487,7 +1621,7 @@ func TestBackendUpdateAndDelete(t *testing.T) {
 	newOrchestrator.mutex.Unlock()
 
 	// Test that deleting the volume causes the backend to be deleted.
-	err = orchestrator.DeleteVolume(volumeName)
+	err = orchestrator.DeleteVolume(ctx(), volumeName)
 	if err != nil {
 		t.Fatal("Unable to delete volume for offline backend:  ", err)
 	}
@@ -1556,7 +1690,7 @@ func TestBootstrapSnapshotMissingVolume(t *testing.T) {
 	orchestrator := getOrchestrator()
 	defer cleanup(t, orchestrator)
 	addBackendStorageClass(t, orchestrator, offlineBackendName, scName, backendProtocol)
-	_, err := orchestrator.AddVolume(tu.GenerateVolumeConfig(volumeName, 50,
+	_, err := orchestrator.AddVolume(ctx(), tu.GenerateVolumeConfig(volumeName, 50,
 		scName, config.File))
 	if err != nil {
 		t.Fatal("Unable to create volume: ", err)
@@ -1564,7 +1698,7 @@ func TestBootstrapSnapshotMissingVolume(t *testing.T) {
 
 	// For the full test, we create everything and recreate the AddSnapshot transaction.
 	snapshotConfig := generateSnapshotConfig(snapName, volumeName, volumeName)
-	if _, err := orchestrator.CreateSnapshot(snapshotConfig); err != nil {
+	if _, err := orchestrator.CreateSnapshot(ctx(), snapshotConfig); err != nil {
 		t.Fatal("Unable to add snapshot: ", err)
 	}
 
@@ -1589,12 +1723,47 @@ func TestBootstrapSnapshotMissingVolume(t *testing.T) {
 		t.Error("Unexpected snapshot state.")
 	}
 	//delete volume in missing_volume state
-	err = newOrchestrator.DeleteSnapshot(volumeName, snapName)
+	err = newOrchestrator.DeleteSnapshot(ctx(), volumeName, snapName)
 	if err != nil {
 		t.Error("could not delete snapshot with missing volume")
 	}
 }
 
+func TestGetSnapshotRefreshesCreatingState(t *testing.T) {
+	const (
+		backendName = "snapCreatingBackend"
+		scName      = "snapCreatingSC"
+		volumeName  = "snapCreatingVolume"
+		snapName    = "snapCreatingSnapshot"
+	)
+
+	orchestrator := getOrchestrator()
+	defer cleanup(t, orchestrator)
+	addBackendStorageClass(t, orchestrator, backendName, scName, config.File)
+	_, err := orchestrator.AddVolume(ctx(), tu.GenerateVolumeConfig(volumeName, 50, scName, config.File))
+	if err != nil {
+		t.Fatal("Unable to create volume: ", err)
+	}
+	snapshotConfig := generateSnapshotConfig(snapName, volumeName, volumeName)
+	if _, err := orchestrator.CreateSnapshot(ctx(), snapshotConfig); err != nil {
+		t.Fatal("Unable to add snapshot: ", err)
+	}
+
+	// Simulate a snapshot the backend was still creating when it was added
+	snapshotID := storage.MakeSnapshotID(volumeName, snapName)
+	creating := orchestrator.snapshots[snapshotID].ConstructClone()
+	creating.State = storage.SnapshotStateCreating
+	orchestrator.snapshots[snapshotID] = creating
+
+	snapshot, err := orchestrator.GetSnapshot(volumeName, snapName)
+	assert.NoError(t, err)
+	assert.Equal(t, storage.SnapshotStateOnline, snapshot.State, "the backend's state should be read")
+
+	persistent, err := orchestrator.storeClient.GetSnapshot(volumeName, snapName)
+	assert.NoError(t, err)
+	assert.Equal(t, storage.SnapshotStateOnline, persistent.State, "the new state should be persisted")
+}
+
 func TestBootstrapSnapshotMissingBackend(t *testing.T) {
 	const (
 		offlineBackendName = "snapNoBackBackend"
@@ -1607,7 +1776,7 @@ func TestBootstrapSnapshotMissingBackend(t *testing.T) {
 	orchestrator := getOrchestrator()
 	defer cleanup(t, orchestrator)
 	addBackendStorageClass(t, orchestrator, offlineBackendName, scName, backendProtocol)
-	_, err := orchestrator.AddVolume(tu.GenerateVolumeConfig(volumeName, 50,
+	_, err := orchestrator.AddVolume(ctx(), tu.GenerateVolumeConfig(volumeName, 50,
 		scName, config.File))
 	if err != nil {
 		t.Fatal("Unable to create volume: ", err)
@@ -1615,7 +1784,7 @@ func TestBootstrapSnapshotMissingBackend(t *testing.T) {
 
 	// For the full test, we create everything and recreate the AddSnapshot transaction.
 	snapshotConfig := generateSnapshotConfig(snapName, volumeName, volumeName)
-	if _, err := orchestrator.CreateSnapshot(snapshotConfig); err != nil {
+	if _, err := orchestrator.CreateSnapshot(ctx(), snapshotConfig); err != nil {
 		t.Fatal("Unable to add snapshot: ", err)
 	}
 
@@ -1640,7 +1809,7 @@ func TestBootstrapSnapshotMissingBackend(t *testing.T) {
 		t.Error("Unexpected snapshot state.")
 	}
 	//delete snapshot in missing_backend state
-	err = newOrchestrator.DeleteSnapshot(volumeName, snapName)
+	err = newOrchestrator.DeleteSnapshot(ctx(), volumeName, snapName)
 	if err != nil {
 		t.Error("could not delete snapshot with missing backend")
 	}
@@ -1657,7 +1826,7 @@ func TestBootstrapVolumeMissingBackend(t *testing.T) {
 	orchestrator := getOrchestrator()
 	defer cleanup(t, orchestrator)
 	addBackendStorageClass(t, orchestrator, offlineBackendName, scName, backendProtocol)
-	_, err := orchestrator.AddVolume(tu.GenerateVolumeConfig(volumeName, 50,
+	_, err := orchestrator.AddVolume(ctx(), tu.GenerateVolumeConfig(volumeName, 50,
 		scName, config.File))
 	if err != nil {
 		t.Fatal("Unable to create volume: ", err)
@@ -1685,7 +1854,7 @@ func TestBootstrapVolumeMissingBackend(t *testing.T) {
 	}
 
 	//delete volume in missing_backend state
-	err = newOrchestrator.DeleteVolume(volumeName)
+	err = newOrchestrator.DeleteVolume(ctx(), volumeName)
 	if err != nil {
 		t.Error("could not delete volume with missing backend")
 	}
@@ -1702,7 +1871,7 @@ func TestBackendCleanup(t *testing.T) {
 
 	orchestrator := getOrchestrator()
 	addBackendStorageClass(t, orchestrator, offlineBackendName, scName, backendProtocol)
-	_, err := orchestrator.AddVolume(tu.GenerateVolumeConfig(volumeName, 50,
+	_, err := orchestrator.AddVolume(ctx(), tu.GenerateVolumeConfig(volumeName, 50,
 		scName, config.File))
 	if err != nil {
 		t.Fatal("Unable to create volume: ", err)
@@ -1773,6 +1942,8 @@ func TestLoadBackend(t *testing.T) {
 		t.Fatal("Unable to marshal config from stored backend:  ", err)
 	}
 	newBackend, err := orchestrator.AddBackend(newConfig)
+	// As an update, reloading the config should only advance the backend's generation
+	originalBackend.Generation++
 	if err != nil {
 		t.Error("Unable to update backend from config:  ", err)
 	} else if !reflect.DeepEqual(newBackend, originalBackend) {
@@ -1897,7 +2068,7 @@ func TestAddVolumeRecovery(t *testing.T) {
 	// afterwards
 	fullVolumeConfig := tu.GenerateVolumeConfig(fullVolumeName, 50, scName,
 		config.File)
-	_, err := orchestrator.AddVolume(fullVolumeConfig)
+	_, err := orchestrator.AddVolume(ctx(), fullVolumeConfig)
 	if err != nil {
 		t.Fatal("Unable to add volume: ", err)
 	}
@@ -1924,15 +2095,15 @@ func TestDeleteVolumeRecovery(t *testing.T) {
 
 	// For the full test, we delete everything but the ending transaction.
 	fullVolumeConfig := tu.GenerateVolumeConfig(fullVolumeName, 50, scName, config.File)
-	if _, err := orchestrator.AddVolume(fullVolumeConfig); err != nil {
+	if _, err := orchestrator.AddVolume(ctx(), fullVolumeConfig); err != nil {
 		t.Fatal("Unable to add volume: ", err)
 	}
-	if err := orchestrator.DeleteVolume(fullVolumeName); err != nil {
+	if err := orchestrator.DeleteVolume(ctx(), fullVolumeName); err != nil {
 		t.Fatal("Unable to remove full volume:  ", err)
 	}
 
 	txOnlyVolumeConfig := tu.GenerateVolumeConfig(txOnlyVolumeName, 50, scName, config.File)
-	if _, err := orchestrator.AddVolume(txOnlyVolumeConfig); err != nil {
+	if _, err := orchestrator.AddVolume(ctx(), txOnlyVolumeConfig); err != nil {
 		t.Fatal("Unable to add tx only volume: ", err)
 	}
 
@@ -2026,13 +2197,13 @@ func TestAddSnapshotRecovery(t *testing.T) {
 
 	// It's easier to add the volume/snapshot and then reinject the transaction again afterwards.
 	volumeConfig := tu.GenerateVolumeConfig(volumeName, 50, scName, config.File)
-	if _, err := orchestrator.AddVolume(volumeConfig); err != nil {
+	if _, err := orchestrator.AddVolume(ctx(), volumeConfig); err != nil {
 		t.Fatal("Unable to add volume: ", err)
 	}
 
 	// For the full test, we create everything and recreate the AddSnapshot transaction.
 	fullSnapshotConfig := generateSnapshotConfig(fullSnapshotName, volumeName, volumeName)
-	if _, err := orchestrator.CreateSnapshot(fullSnapshotConfig); err != nil {
+	if _, err := orchestrator.CreateSnapshot(ctx(), fullSnapshotConfig); err != nil {
 		t.Fatal("Unable to add snapshot: ", err)
 	}
 
@@ -2063,21 +2234,21 @@ func TestDeleteSnapshotRecovery(t *testing.T) {
 
 	// For the full test, we delete everything and recreate the delete transaction.
 	volumeConfig := tu.GenerateVolumeConfig(volumeName, 50, scName, config.File)
-	if _, err := orchestrator.AddVolume(volumeConfig); err != nil {
+	if _, err := orchestrator.AddVolume(ctx(), volumeConfig); err != nil {
 		t.Fatal("Unable to add volume: ", err)
 	}
 	fullSnapshotConfig := generateSnapshotConfig(fullSnapshotName, volumeName, volumeName)
-	if _, err := orchestrator.CreateSnapshot(fullSnapshotConfig); err != nil {
+	if _, err := orchestrator.CreateSnapshot(ctx(), fullSnapshotConfig); err != nil {
 		t.Fatal("Unable to add snapshot: ", err)
 	}
-	if err := orchestrator.DeleteSnapshot(volumeName, fullSnapshotName); err != nil {
+	if err := orchestrator.DeleteSnapshot(ctx(), volumeName, fullSnapshotName); err != nil {
 		t.Fatal("Unable to remove full snapshot: ", err)
 	}
 
 	// For the partial test, we ensure the snapshot will be restored during bootstrapping,
 	// and the delete transaction will ensure everything is deleted.
 	txOnlySnapshotConfig := generateSnapshotConfig(txOnlySnapshotName, volumeName, volumeName)
-	if _, err := orchestrator.CreateSnapshot(txOnlySnapshotConfig); err != nil {
+	if _, err := orchestrator.CreateSnapshot(ctx(), txOnlySnapshotConfig); err != nil {
 		t.Fatal("Unable to add snapshot: ", err)
 	}
 
@@ -2287,12 +2458,12 @@ func TestOrchestratorNotReady(t *testing.T) {
 		t.Errorf("Expected DeleteBackend to return an error.")
 	}
 
-	volume, err = orchestrator.AddVolume(nil)
+	volume, err = orchestrator.AddVolume(ctx(), nil)
 	if volume != nil || !utils.IsNotReadyError(err) {
 		t.Errorf("Expected AddVolume to return an error.")
 	}
 
-	volume, err = orchestrator.CloneVolume(nil)
+	volume, err = orchestrator.CloneVolume(ctx(), nil)
 	if volume != nil || !utils.IsNotReadyError(err) {
 		t.Errorf("Expected CloneVolume to return an error.")
 	}
@@ -2317,7 +2488,7 @@ func TestOrchestratorNotReady(t *testing.T) {
 		t.Errorf("Expected ListVolumes to return an error.")
 	}
 
-	err = orchestrator.DeleteVolume("")
+	err = orchestrator.DeleteVolume(ctx(), "")
 	if !utils.IsNotReadyError(err) {
 		t.Errorf("Expected DeleteVolume to return an error.")
 	}
@@ -2337,7 +2508,7 @@ func TestOrchestratorNotReady(t *testing.T) {
 		t.Errorf("Expected DetachVolume to return an error.")
 	}
 
-	snapshot, err = orchestrator.CreateSnapshot(nil)
+	snapshot, err = orchestrator.CreateSnapshot(ctx(), nil)
 	if snapshot != nil || !utils.IsNotReadyError(err) {
 		t.Errorf("Expected CreateSnapshot to return an error.")
 	}
@@ -2357,7 +2528,7 @@ func TestOrchestratorNotReady(t *testing.T) {
 		t.Errorf("Expected ReadSnapshotsForVolume to return an error.")
 	}
 
-	err = orchestrator.DeleteSnapshot("", "")
+	err = orchestrator.DeleteSnapshot(ctx(), "", "")
 	if !utils.IsNotReadyError(err) {
 		t.Errorf("Expected DeleteSnapshot to return an error.")
 	}
@@ -2434,7 +2605,7 @@ func TestImportVolumeFailures(t *testing.T) {
 
 	orchestrator, volumeConfig := importVolumeSetup(t, backendName, scName, volumeName, originalName01, backendProtocol)
 
-	_, err := orchestrator.LegacyImportVolume(volumeConfig, backendName, false, createPVandPVCError)
+	_, err := orchestrator.LegacyImportVolume(ctx(), volumeConfig, backendName, false, createPVandPVCError)
 
 	// verify that importVolumeCleanup renamed volume to originalName
 	backend, _ := orchestrator.getBackendByBackendName(backendName)
@@ -2500,7 +2671,7 @@ func TestLegacyImportVolume(t *testing.T) {
 			createFunc: createPVandPVCNoOp, expectedInternalName: originalName02},
 	} {
 		// The test code
-		volExternal, err := orchestrator.LegacyImportVolume(c.volumeConfig, backendName, c.notManaged, c.createFunc)
+		volExternal, err := orchestrator.LegacyImportVolume(ctx(), c.volumeConfig, backendName, c.notManaged, c.createFunc)
 		if err != nil {
 			t.Errorf("%s: unexpected error %v", c.name, err)
 		} else {
@@ -2550,7 +2721,7 @@ func TestImportVolume(t *testing.T) {
 		{name: "notManaged", volumeConfig: notManagedVolConfig, expectedInternalName: originalName02},
 	} {
 		// The test code
-		volExternal, err := orchestrator.ImportVolume(c.volumeConfig)
+		volExternal, err := orchestrator.ImportVolume(ctx(), c.volumeConfig)
 		if err != nil {
 			t.Errorf("%s: unexpected error %v", c.name, err)
 		} else {
@@ -2567,6 +2738,73 @@ func TestImportVolume(t *testing.T) {
 	cleanup(t, orchestrator)
 }
 
+func TestUnmanageVolume(t *testing.T) {
+	const (
+		backendName  = "unmanageBackend"
+		scName       = "unmanageSC"
+		volumeName   = "importedVolume"
+		originalName = "origVolume01"
+	)
+
+	orchestrator, volumeConfig := importVolumeSetup(t, backendName, scName, volumeName, originalName, config.File)
+	defer cleanup(t, orchestrator)
+
+	imported, err := orchestrator.ImportVolume(ctx(), volumeConfig)
+	if err != nil {
+		t.Fatal("Unable to import volume: ", err)
+	}
+	if _, err = orchestrator.CreateSnapshot(ctx(), &storage.SnapshotConfig{
+		Version:    "1",
+		Name:       "snap",
+		VolumeName: volumeName,
+	}); err != nil {
+		t.Fatal("Unable to create snapshot: ", err)
+	}
+	created, err := orchestrator.AddVolume(ctx(), tu.GenerateVolumeConfig("createdVolume", 1, scName, config.File))
+	if err != nil {
+		t.Fatal("Unable to create volume: ", err)
+	}
+
+	backend, err := orchestrator.getBackendByBackendName(backendName)
+	if err != nil {
+		t.Fatal("Unable to get backend: ", err)
+	}
+	driver := backend.Driver.(*fakedriver.StorageDriver)
+
+	_, err = orchestrator.UnmanageVolume("missingVolume", "")
+	assert.True(t, utils.IsNotFoundError(err), "expected not found error")
+
+	// Published volumes are refused
+	orchestrator.volumes["createdVolume"].PublishedNodes = []string{"node1"}
+	_, err = orchestrator.UnmanageVolume("createdVolume", "")
+	assert.Error(t, err, "expected error for published volume")
+	orchestrator.volumes["createdVolume"].PublishedNodes = nil
+
+	// An imported volume is renamed back to its original name, and its snapshots are forgotten
+	name, err := orchestrator.UnmanageVolume(volumeName, "")
+	assert.NoError(t, err)
+	assert.Equal(t, originalName, name)
+	_, ok := orchestrator.volumes[volumeName]
+	assert.False(t, ok, "unmanaged volume should be removed from Trident")
+	_, ok = orchestrator.snapshots[storage.MakeSnapshotID(volumeName, "snap")]
+	assert.False(t, ok, "unmanaged volume's snapshots should be removed from Trident")
+	_, ok = driver.Volumes[originalName]
+	assert.True(t, ok, "unmanaged volume should have its original name")
+	_, ok = driver.Volumes[imported.Config.InternalName]
+	assert.False(t, ok, "unmanaged volume should not keep its internal name")
+	_, ok = backend.Volumes[volumeName]
+	assert.False(t, ok, "unmanaged volume should be removed from its backend")
+
+	// Other volumes keep their internal names unless a name is given
+	name, err = orchestrator.UnmanageVolume("createdVolume", "handedBack")
+	assert.NoError(t, err)
+	assert.Equal(t, "handedBack", name)
+	_, ok = driver.Volumes["handedBack"]
+	assert.True(t, ok, "unmanaged volume should be renamed")
+	_, ok = driver.Volumes[created.Config.InternalName]
+	assert.False(t, ok, "unmanaged volume should not keep its internal name")
+}
+
 func TestValidateImportVolumeNasBackend(t *testing.T) {
 	const (
 		backendName     = "backend01"
@@ -2578,7 +2816,7 @@ func TestValidateImportVolumeNasBackend(t *testing.T) {
 
 	orchestrator, volumeConfig := importVolumeSetup(t, backendName, scName, volumeName, originalName, backendProtocol)
 
-	_, err := orchestrator.AddVolume(volumeConfig)
+	_, err := orchestrator.AddVolume(ctx(), volumeConfig)
 	if err != nil {
 		t.Fatal("Unable to add volume: ", err)
 	}
@@ -2651,7 +2889,7 @@ func TestValidateImportVolumeSanBackend(t *testing.T) {
 
 	orchestrator, volumeConfig := importVolumeSetup(t, backendName, scName, volumeName, originalName, backendProtocol)
 
-	_, err := orchestrator.AddVolume(volumeConfig)
+	_, err := orchestrator.AddVolume(ctx(), volumeConfig)
 	if err != nil {
 		t.Fatal("Unable to add volume: ", err)
 	}
@@ -2696,6 +2934,39 @@ func TestValidateImportVolumeSanBackend(t *testing.T) {
 	cleanup(t, orchestrator)
 }
 
+func TestAddRawBlockVolume(t *testing.T) {
+	const (
+		backendName = "rawBlockBackend"
+		scName      = "rawBlockSC"
+	)
+
+	orchestrator := getOrchestrator()
+	addBackendStorageClass(t, orchestrator, backendName, scName, config.Block)
+
+	volumeConfig := tu.GenerateVolumeConfig("rawBlockVolume", 50, scName, config.Block)
+	volumeConfig.VolumeMode = config.RawBlock
+	volumeConfig.FileSystem = ""
+
+	volume, err := orchestrator.AddVolume(ctx(), volumeConfig)
+	if err != nil {
+		t.Fatal("Unable to add raw-block volume: ", err)
+	}
+	assert.Equal(t, drivers.FsRaw, volume.Config.FileSystem, "raw-block volume should have no filesystem")
+
+	ext4VolumeConfig := tu.GenerateVolumeConfig("ext4RawBlockVolume", 50, scName, config.Block)
+	ext4VolumeConfig.VolumeMode = config.RawBlock
+	ext4VolumeConfig.FileSystem = "ext4"
+
+	_, err = orchestrator.AddVolume(ctx(), ext4VolumeConfig)
+	if err == nil {
+		t.Error("Raw-block volume with a filesystem should not be created")
+	} else {
+		assert.Contains(t, err.Error(), "cannot create raw-block volume")
+	}
+
+	cleanup(t, orchestrator)
+}
+
 func TestAddNode(t *testing.T) {
 	node := &utils.Node{
 		Name: "testNode",
@@ -2943,7 +3214,7 @@ func TestSnapshotVolumes(t *testing.T) {
 		},
 	} {
 		// Create the source volume
-		_, err := orchestrator.AddVolume(s.config)
+		_, err := orchestrator.AddVolume(ctx(), s.config)
 		if err != nil {
 			t.Errorf("%s: could not add volume: %v", s.name, err)
 			continue
@@ -2964,7 +3235,7 @@ func TestSnapshotVolumes(t *testing.T) {
 			Name:       snapshotName,
 			VolumeName: volume.Config.Name,
 		}
-		snapshotExternal, err := orchestrator.CreateSnapshot(snapshotConfig)
+		snapshotExternal, err := orchestrator.CreateSnapshot(ctx(), snapshotConfig)
 		if err != nil {
 			t.Fatalf("%s: got unexpected error creating snapshot: %v", s.name, err)
 		}
@@ -2991,7 +3262,7 @@ func TestSnapshotVolumes(t *testing.T) {
 		}
 		orchestrator.mutex.Unlock()
 
-		err = orchestrator.DeleteSnapshot(volume.Config.Name, snapshotName)
+		err = orchestrator.DeleteSnapshot(ctx(), volume.Config.Name, snapshotName)
 		if err != nil {
 			t.Fatalf("%s: got unexpected error deleting snapshot: %v", s.name, err)
 		}
@@ -3069,3 +3340,580 @@ func TestGetProtocol(t *testing.T) {
 		assert.True(t, tc.expected == protocolLocal, "expected both the protocols to be equal!")
 	}
 }
+
+func TestBackendMetrics(t *testing.T) {
+	const (
+		backendName = "metricsBackend"
+		scName      = "metricsBackendSC"
+		volumeName  = "metricsVolume"
+	)
+
+	orchestrator := getOrchestrator()
+	addBackendStorageClass(t, orchestrator, backendName, scName, config.File)
+
+	if _, err := orchestrator.AddVolume(ctx(), tu.GenerateVolumeConfig(volumeName, 1, scName, config.File)); err != nil {
+		t.Fatal("Unable to create volume: ", err)
+	}
+
+	created := testutil.ToFloat64(
+		backendOperationsTotal.WithLabelValues(backendName, drivers.FakeStorageDriverName, "volume_create", "true"))
+	assert.Equal(t, float64(1), created, "volume creation was not counted for the backend")
+
+	online := testutil.ToFloat64(
+		backendStateGauge.WithLabelValues(backendName, drivers.FakeStorageDriverName, string(storage.Online)))
+	assert.Equal(t, float64(1), online, "backend state was not reported")
+
+	provisioned := testutil.ToFloat64(
+		backendProvisionedBytesGauge.WithLabelValues(backendName, drivers.FakeStorageDriverName))
+	assert.Equal(t, float64(1073741824), provisioned, "provisioned capacity was not reported")
+
+	if err := orchestrator.DeleteVolume(ctx(), volumeName); err != nil {
+		t.Fatal("Unable to delete volume: ", err)
+	}
+
+	deleted := testutil.ToFloat64(
+		backendOperationsTotal.WithLabelValues(backendName, drivers.FakeStorageDriverName, "volume_delete", "true"))
+	assert.Equal(t, float64(1), deleted, "volume deletion was not counted for the backend")
+
+	cleanup(t, orchestrator)
+}
+
+func TestBackendCordon(t *testing.T) {
+	const (
+		backendA = "cordonBackendA"
+		backendB = "cordonBackendB"
+		scName   = "cordonSC"
+	)
+
+	orchestrator := getOrchestrator()
+	addBackendStorageClass(t, orchestrator, backendA, scName, config.File)
+	addBackend(t, orchestrator, backendB, config.File)
+	backendAUUID, _ := orchestrator.getBackendUUIDByBackendName(backendA)
+	backendBUUID, _ := orchestrator.getBackendUUIDByBackendName(backendB)
+
+	backend, err := orchestrator.UpdateBackendState(backendA, string(storage.Cordoned))
+	assert.NoError(t, err)
+	assert.Equal(t, storage.Cordoned, backend.State)
+
+	// New volumes avoid the cordoned backend
+	for i := 0; i < 5; i++ {
+		volume, err := orchestrator.AddVolume(ctx(),
+			tu.GenerateVolumeConfig(fmt.Sprintf("cordonVolume%d", i), 1, scName, config.File))
+		if err != nil {
+			t.Fatal("Unable to create volume: ", err)
+		}
+		assert.Equal(t, backendBUUID, volume.BackendUUID)
+	}
+
+	// A draining backend serves its volumes but doesn't accept new ones
+	backend, err = orchestrator.UpdateBackendState(backendB, string(storage.Draining))
+	assert.NoError(t, err)
+	assert.Equal(t, storage.Draining, backend.State)
+	_, err = orchestrator.GetVolume("cordonVolume0")
+	assert.NoError(t, err)
+	_, err = orchestrator.AddVolume(ctx(), tu.GenerateVolumeConfig("cordonVolume5", 1, scName, config.File))
+	assert.Error(t, err, "expected an error with no backend accepting new volumes")
+	assert.Equal(t, map[string]string{
+		backendA: "backend is cordoned",
+		backendB: "backend is draining",
+	}, utils.GetVolumeCreateBackendErrors(err))
+	cloneConfig := tu.GenerateVolumeConfig("cordonClone", 1, scName, config.File)
+	cloneConfig.CloneSourceVolume = "cordonVolume0"
+	_, err = orchestrator.CloneVolume(ctx(), cloneConfig)
+	assert.Error(t, err, "expected an error cloning a volume on a draining backend")
+
+	// Uncordoning a backend makes it eligible again
+	_, err = orchestrator.UpdateBackendState(backendA, string(storage.Online))
+	assert.NoError(t, err)
+	volume, err := orchestrator.AddVolume(ctx(), tu.GenerateVolumeConfig("cordonVolume5", 1, scName, config.File))
+	if err != nil {
+		t.Fatal("Unable to create volume: ", err)
+	}
+	assert.Equal(t, backendAUUID, volume.BackendUUID)
+
+	_, err = orchestrator.UpdateBackendState(backendA, "bogus")
+	assert.Error(t, err)
+
+	// Failed backends can't be cordoned or brought back online
+	_, err = orchestrator.UpdateBackendState(backendA, string(storage.Failed))
+	assert.NoError(t, err)
+	_, err = orchestrator.UpdateBackendState(backendA, string(storage.Online))
+	assert.Error(t, err)
+	_, err = orchestrator.UpdateBackendState(backendA, string(storage.Cordoned))
+	assert.Error(t, err)
+
+	cleanup(t, orchestrator)
+}
+
+func TestStorageClassMountOptions(t *testing.T) {
+	const (
+		backendName = "mountOptionsBackend"
+		scName      = "mountOptionsSC"
+	)
+
+	orchestrator := getOrchestrator()
+	defer cleanup(t, orchestrator)
+	addBackend(t, orchestrator, backendName, config.File)
+
+	// Conflicting mount options are rejected
+	_, err := orchestrator.AddStorageClass(&storageclass.Config{
+		Name:         "invalidMountOptionsSC",
+		Attributes:   map[string]sa.Request{sa.TestingAttribute: sa.NewBoolRequest(true)},
+		MountOptions: "hard,soft",
+	})
+	assert.Error(t, err, "expected error for conflicting mount options")
+
+	if _, err = orchestrator.AddStorageClass(&storageclass.Config{
+		Name:         scName,
+		Attributes:   map[string]sa.Request{sa.TestingAttribute: sa.NewBoolRequest(true)},
+		MountOptions: "nfsvers=4.1,hard",
+	}); err != nil {
+		t.Fatal("Unable to add storage class: ", err)
+	}
+
+	// Volumes without their own mount options inherit the storage class's
+	volume, err := orchestrator.AddVolume(ctx(), tu.GenerateVolumeConfig("inheritsMountOptions", 1, scName, config.File))
+	if err != nil {
+		t.Fatal("Unable to create volume: ", err)
+	}
+	assert.Equal(t, "nfsvers=4.1,hard", volume.Config.MountOptions)
+
+	volumeConfig := tu.GenerateVolumeConfig("ownMountOptions", 1, scName, config.File)
+	volumeConfig.MountOptions = "nfsvers=3"
+	volume, err = orchestrator.AddVolume(ctx(), volumeConfig)
+	if err != nil {
+		t.Fatal("Unable to create volume: ", err)
+	}
+	assert.Equal(t, "nfsvers=3", volume.Config.MountOptions)
+}
+
+func TestStorageClassRootOwnership(t *testing.T) {
+	const (
+		backendName = "rootOwnershipBackend"
+		scName      = "rootOwnershipSC"
+	)
+
+	orchestrator := getOrchestrator()
+	defer cleanup(t, orchestrator)
+	addBackend(t, orchestrator, backendName, config.File)
+
+	_, err := orchestrator.AddStorageClass(&storageclass.Config{
+		Name:          "invalidRootOwnershipSC",
+		Attributes:    map[string]sa.Request{sa.TestingAttribute: sa.NewBoolRequest(true)},
+		RootOwnership: "root",
+	})
+	assert.Error(t, err, "expected error for invalid root ownership")
+
+	if _, err = orchestrator.AddStorageClass(&storageclass.Config{
+		Name:          scName,
+		Attributes:    map[string]sa.Request{sa.TestingAttribute: sa.NewBoolRequest(true)},
+		RootOwnership: "0:2000",
+	}); err != nil {
+		t.Fatal("Unable to add storage class: ", err)
+	}
+
+	volume, err := orchestrator.AddVolume(ctx(), tu.GenerateVolumeConfig("rootOwnershipVolume", 1, scName, config.File))
+	if err != nil {
+		t.Fatal("Unable to create volume: ", err)
+	}
+	assert.Equal(t, "0:2000", volume.Config.RootOwnership)
+}
+
+func TestStorageClassFormatOptions(t *testing.T) {
+	const (
+		backendName = "formatOptionsBackend"
+		scName      = "formatOptionsSC"
+	)
+
+	orchestrator := getOrchestrator()
+	defer cleanup(t, orchestrator)
+	addBackend(t, orchestrator, backendName, config.Block)
+
+	_, err := orchestrator.AddStorageClass(&storageclass.Config{
+		Name:          "invalidFormatOptionsSC",
+		Attributes:    map[string]sa.Request{sa.TestingAttribute: sa.NewBoolRequest(true)},
+		FormatOptions: "nodiscard",
+	})
+	assert.Error(t, err, "expected error for invalid format options")
+
+	if _, err = orchestrator.AddStorageClass(&storageclass.Config{
+		Name:          scName,
+		Attributes:    map[string]sa.Request{sa.TestingAttribute: sa.NewBoolRequest(true)},
+		FormatOptions: "-E nodiscard",
+	}); err != nil {
+		t.Fatal("Unable to add storage class: ", err)
+	}
+
+	volumeConfig := tu.GenerateVolumeConfig("formatOptionsVolume", 1, scName, config.Block)
+	volume, err := orchestrator.AddVolume(ctx(), volumeConfig)
+	if err != nil {
+		t.Fatal("Unable to create volume: ", err)
+	}
+	assert.Equal(t, "-E nodiscard", volume.Config.FormatOptions)
+}
+
+func TestStorageClassFsckPolicy(t *testing.T) {
+	const (
+		backendName = "fsckPolicyBackend"
+		scName      = "fsckPolicySC"
+	)
+
+	orchestrator := getOrchestrator()
+	defer cleanup(t, orchestrator)
+	addBackend(t, orchestrator, backendName, config.Block)
+
+	_, err := orchestrator.AddStorageClass(&storageclass.Config{
+		Name:       "invalidFsckPolicySC",
+		Attributes: map[string]sa.Request{sa.TestingAttribute: sa.NewBoolRequest(true)},
+		FsckPolicy: "always",
+	})
+	assert.Error(t, err, "expected error for invalid fsck policy")
+
+	if _, err = orchestrator.AddStorageClass(&storageclass.Config{
+		Name:       scName,
+		Attributes: map[string]sa.Request{sa.TestingAttribute: sa.NewBoolRequest(true)},
+		FsckPolicy: utils.FsckPolicyAuto,
+	}); err != nil {
+		t.Fatal("Unable to add storage class: ", err)
+	}
+
+	volume, err := orchestrator.AddVolume(ctx(), tu.GenerateVolumeConfig("fsckPolicyVolume", 1, scName, config.Block))
+	if err != nil {
+		t.Fatal("Unable to create volume: ", err)
+	}
+	assert.Equal(t, utils.FsckPolicyAuto, volume.Config.FsckPolicy)
+}
+
+func TestStorageClassBlockDeviceTuning(t *testing.T) {
+	const (
+		backendName = "tuningBackend"
+		scName      = "tuningSC"
+	)
+
+	orchestrator := getOrchestrator()
+	defer cleanup(t, orchestrator)
+	addBackend(t, orchestrator, backendName, config.Block)
+
+	_, err := orchestrator.AddStorageClass(&storageclass.Config{
+		Name:        "invalidTuningSC",
+		Attributes:  map[string]sa.Request{sa.TestingAttribute: sa.NewBoolRequest(true)},
+		IOScheduler: "fastest",
+	})
+	assert.Error(t, err, "expected error for invalid I/O scheduler")
+
+	if _, err = orchestrator.AddStorageClass(&storageclass.Config{
+		Name:        scName,
+		Attributes:  map[string]sa.Request{sa.TestingAttribute: sa.NewBoolRequest(true)},
+		IOScheduler: "none",
+		ReadAheadKB: "128",
+		NrRequests:  "256",
+	}); err != nil {
+		t.Fatal("Unable to add storage class: ", err)
+	}
+
+	volume, err := orchestrator.AddVolume(ctx(), tu.GenerateVolumeConfig("tuningVolume", 1, scName, config.Block))
+	if err != nil {
+		t.Fatal("Unable to create volume: ", err)
+	}
+	assert.Equal(t, "none", volume.Config.IOScheduler)
+	assert.Equal(t, "128", volume.Config.ReadAheadKB)
+	assert.Equal(t, "256", volume.Config.NrRequests)
+}
+
+func TestStorageClassAccessControl(t *testing.T) {
+	const (
+		backendName = "accessControlBackend"
+		scName      = "accessControlSC"
+	)
+
+	orchestrator := getOrchestrator()
+	defer cleanup(t, orchestrator)
+	addBackend(t, orchestrator, backendName, config.File)
+
+	_, err := orchestrator.AddStorageClass(&storageclass.Config{
+		Name:         "invalidExportPolicySC",
+		Attributes:   map[string]sa.Request{sa.TestingAttribute: sa.NewBoolRequest(true)},
+		ExportPolicy: "team a",
+	})
+	assert.Error(t, err, "expected error for invalid export policy")
+
+	_, err = orchestrator.AddStorageClass(&storageclass.Config{
+		Name:       "invalidIgroupSC",
+		Attributes: map[string]sa.Request{sa.TestingAttribute: sa.NewBoolRequest(true)},
+		IgroupName: "team/a",
+	})
+	assert.Error(t, err, "expected error for invalid igroup")
+
+	if _, err = orchestrator.AddStorageClass(&storageclass.Config{
+		Name:         scName,
+		Attributes:   map[string]sa.Request{sa.TestingAttribute: sa.NewBoolRequest(true)},
+		ExportPolicy: "team-a",
+		IgroupName:   "team-a",
+	}); err != nil {
+		t.Fatal("Unable to add storage class: ", err)
+	}
+
+	volume, err := orchestrator.AddVolume(ctx(), tu.GenerateVolumeConfig("accessControlVolume", 1, scName, config.File))
+	if err != nil {
+		t.Fatal("Unable to create volume: ", err)
+	}
+	assert.Equal(t, "team-a", volume.Config.ExportPolicy)
+	assert.Equal(t, "team-a", volume.Config.IgroupName)
+
+	// A volume's own export policy wins over its storage class's
+	volumeConfig := tu.GenerateVolumeConfig("ownExportPolicy", 1, scName, config.File)
+	volumeConfig.ExportPolicy = "team-b"
+	volume, err = orchestrator.AddVolume(ctx(), volumeConfig)
+	if err != nil {
+		t.Fatal("Unable to create volume: ", err)
+	}
+	assert.Equal(t, "team-b", volume.Config.ExportPolicy)
+}
+
+func TestStorageClassDeletionPolicy(t *testing.T) {
+	const (
+		backendName = "deletionPolicyBackend"
+		scName      = "deletionPolicySC"
+	)
+
+	orchestrator := getOrchestrator()
+	defer cleanup(t, orchestrator)
+	addBackend(t, orchestrator, backendName, config.File)
+
+	_, err := orchestrator.AddStorageClass(&storageclass.Config{
+		Name:           "invalidDeletionPolicySC",
+		Attributes:     map[string]sa.Request{sa.TestingAttribute: sa.NewBoolRequest(true)},
+		DeletionPolicy: "retain",
+	})
+	assert.Error(t, err, "expected error for invalid deletion policy")
+
+	if _, err = orchestrator.AddStorageClass(&storageclass.Config{
+		Name:           scName,
+		Attributes:     map[string]sa.Request{sa.TestingAttribute: sa.NewBoolRequest(true)},
+		DeletionPolicy: storage.DeletionPolicyUnmanage,
+	}); err != nil {
+		t.Fatal("Unable to add storage class: ", err)
+	}
+
+	invalidConfig := tu.GenerateVolumeConfig("invalidPolicyVolume", 1, scName, config.File)
+	invalidConfig.DeletionPolicy = "retain"
+	_, err = orchestrator.AddVolume(ctx(), invalidConfig)
+	assert.Error(t, err, "expected error for invalid deletion policy")
+
+	unmanaged, err := orchestrator.AddVolume(ctx(), tu.GenerateVolumeConfig("unmanagedVolume", 1, scName,
+		config.File))
+	if err != nil {
+		t.Fatal("Unable to create volume: ", err)
+	}
+	assert.Equal(t, storage.DeletionPolicyUnmanage, unmanaged.Config.DeletionPolicy)
+
+	deletedConfig := tu.GenerateVolumeConfig("deletedVolume", 1, scName, config.File)
+	deletedConfig.DeletionPolicy = storage.DeletionPolicyDelete
+	deleted, err := orchestrator.AddVolume(ctx(), deletedConfig)
+	if err != nil {
+		t.Fatal("Unable to create volume: ", err)
+	}
+	assert.Equal(t, storage.DeletionPolicyDelete, deleted.Config.DeletionPolicy)
+
+	// Only the volume whose storage should be destroyed is removed from the backend
+	assert.NoError(t, orchestrator.DeleteVolume(ctx(), "unmanagedVolume"))
+	assert.NoError(t, orchestrator.DeleteVolume(ctx(), "deletedVolume"))
+
+	_, ok := orchestrator.volumes["unmanagedVolume"]
+	assert.False(t, ok, "unmanaged volume should be removed from Trident")
+
+	backend, err := orchestrator.getBackendByBackendName(backendName)
+	if err != nil {
+		t.Fatal("Unable to get backend: ", err)
+	}
+	driver := backend.Driver.(*fakedriver.StorageDriver)
+	_, ok = driver.Volumes[unmanaged.Config.InternalName]
+	assert.True(t, ok, "unmanaged volume's storage should be left intact")
+	_, ok = driver.Volumes[deleted.Config.InternalName]
+	assert.False(t, ok, "deleted volume's storage should be destroyed")
+}
+
+func TestNamespaceRestrictedBackends(t *testing.T) {
+	const scName = "tenantSC"
+
+	orchestrator := getOrchestrator()
+	pools := map[string]*fake.StoragePool{
+		"primary": {
+			Attrs: map[string]sa.Offer{sa.TestingAttribute: sa.NewBoolOffer(true)},
+			Bytes: 100 * 1024 * 1024 * 1024,
+		},
+	}
+	backendUUIDs := make(map[string]string)
+	for _, tenant := range []string{"a", "b"} {
+		configJSON, err := fakedriver.NewFakeStorageDriverConfigJSON("tenant-"+tenant, config.File, pools, nil)
+		if err != nil {
+			t.Fatal("Unable to create mock driver config JSON: ", err)
+		}
+		backendConfig := make(map[string]interface{})
+		if err = json.Unmarshal([]byte(configJSON), &backendConfig); err != nil {
+			t.Fatal("Unable to parse mock driver config JSON: ", err)
+		}
+		backendConfig["allowedNamespaces"] = []string{"tenant-" + tenant}
+		backendConfig["namespaceSelector"] = "tenant=" + tenant
+		restrictedJSON, _ := json.Marshal(backendConfig)
+		backend, err := orchestrator.AddBackend(string(restrictedJSON))
+		if err != nil {
+			t.Fatal("Unable to add backend: ", err)
+		}
+		backendUUIDs[tenant] = backend.BackendUUID
+	}
+	if _, err := orchestrator.AddStorageClass(&storageclass.Config{
+		Name:       scName,
+		Attributes: map[string]sa.Request{sa.TestingAttribute: sa.NewBoolRequest(true)},
+	}); err != nil {
+		t.Fatal("Unable to add storage class: ", err)
+	}
+
+	volumeConfig := func(name, namespace string, namespaceLabels map[string]string) *storage.VolumeConfig {
+		volumeConfig := tu.GenerateVolumeConfig(name, 1, scName, config.File)
+		volumeConfig.Namespace = namespace
+		volumeConfig.NamespaceLabels = namespaceLabels
+		return volumeConfig
+	}
+
+	// Volumes only land on the backend serving their namespace
+	for i := 0; i < 5; i++ {
+		volume, err := orchestrator.AddVolume(ctx(), volumeConfig(fmt.Sprintf("tenant-a-vol%d", i), "tenant-a", nil))
+		if assert.NoError(t, err) {
+			assert.Equal(t, backendUUIDs["a"], volume.BackendUUID)
+		}
+	}
+	volume, err := orchestrator.AddVolume(ctx(), volumeConfig("labeled-vol", "team-b", map[string]string{"tenant": "b"}))
+	if assert.NoError(t, err) {
+		assert.Equal(t, backendUUIDs["b"], volume.BackendUUID)
+	}
+
+	_, err = orchestrator.AddVolume(ctx(), volumeConfig("other-vol", "tenant-c", map[string]string{"tenant": "c"}))
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "backend does not serve namespace tenant-c")
+	}
+	_, err = orchestrator.AddVolume(ctx(), volumeConfig("no-namespace-vol", "", nil))
+	assert.Error(t, err, "restricted backends shouldn't serve volumes without a namespace")
+
+	// Clones stay on the source's backend, which must serve the clone's namespace
+	cloneConfig := volumeConfig("tenant-b-clone", "tenant-b", nil)
+	cloneConfig.CloneSourceVolume = "tenant-a-vol0"
+	cloneConfig.CloneSourceNamespace = "tenant-a"
+	assert.NoError(t, orchestrator.AddCloneGrant(&storage.CloneGrant{
+		Name:             "share",
+		Namespace:        "tenant-a",
+		Volumes:          []string{"*"},
+		TargetNamespaces: []string{"tenant-b"},
+	}))
+	_, err = orchestrator.CloneVolume(ctx(), cloneConfig)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "does not serve namespace tenant-b")
+	}
+}
+
+func TestAddVolumeInPinnedPool(t *testing.T) {
+	const scName = "pinnedSC"
+
+	orchestrator := getOrchestrator()
+	defer cleanup(t, orchestrator)
+
+	pool := func() *fake.StoragePool {
+		return &fake.StoragePool{
+			Attrs: map[string]sa.Offer{sa.TestingAttribute: sa.NewBoolOffer(true)},
+			Bytes: 100 * 1024 * 1024 * 1024,
+		}
+	}
+	backendPools := map[string]map[string]*fake.StoragePool{
+		"pinnedBackendA": {"aggr1": pool(), "aggr2": pool()},
+		"pinnedBackendB": {"aggr3": pool()},
+	}
+	for backendName, pools := range backendPools {
+		configJSON, err := fakedriver.NewFakeStorageDriverConfigJSON(backendName, config.File, pools, nil)
+		if err != nil {
+			t.Fatal("Unable to create mock driver config JSON: ", err)
+		}
+		if _, err = orchestrator.AddBackend(configJSON); err != nil {
+			t.Fatal("Unable to add backend: ", err)
+		}
+	}
+	if _, err := orchestrator.AddStorageClass(&storageclass.Config{
+		Name:       scName,
+		Attributes: map[string]sa.Request{sa.TestingAttribute: sa.NewBoolRequest(true)},
+	}); err != nil {
+		t.Fatal("Unable to add storage class: ", err)
+	}
+
+	// A pinned volume always lands in its pool
+	for i, poolName := range []string{"aggr2", "aggr2", "aggr3", "aggr1"} {
+		volumeConfig := tu.GenerateVolumeConfig(fmt.Sprintf("pinned%d", i), 1, scName, config.File)
+		volumeConfig.Pool = poolName
+		volume, err := orchestrator.AddVolume(ctx(), volumeConfig)
+		if assert.NoError(t, err, poolName) {
+			assert.Equal(t, poolName, volume.Pool)
+		}
+	}
+
+	// A pool outside the storage class is rejected with the reason
+	volumeConfig := tu.GenerateVolumeConfig("unknownPool", 1, scName, config.File)
+	volumeConfig.Pool = "aggr9"
+	_, err := orchestrator.AddVolume(ctx(), volumeConfig)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "backend has no pool aggr9 in storage class "+scName)
+	}
+}
+
+func TestDeleteVolumeWithDependents(t *testing.T) {
+	const (
+		backendName = "dependentsBackend"
+		scName      = "dependentsSC"
+	)
+
+	orchestrator := getOrchestrator()
+	defer cleanup(t, orchestrator)
+	addBackendStorageClass(t, orchestrator, backendName, scName, config.File)
+
+	if _, err := orchestrator.AddVolume(ctx(), tu.GenerateVolumeConfig("source", 1, scName,
+		config.File)); err != nil {
+		t.Fatal("Unable to create volume: ", err)
+	}
+	if _, err := orchestrator.CreateSnapshot(ctx(), generateSnapshotConfig("snap", "source",
+		orchestrator.volumes["source"].Config.InternalName)); err != nil {
+		t.Fatal("Unable to create snapshot: ", err)
+	}
+	for name, splitOnClone := range map[string]string{"dependentClone": "false", "independentClone": ""} {
+		cloneConfig := tu.GenerateVolumeConfig(name, 1, scName, config.File)
+		cloneConfig.CloneSourceVolume = "source"
+		cloneConfig.SplitOnClone = splitOnClone
+		if _, err := orchestrator.CloneVolume(ctx(), cloneConfig); err != nil {
+			t.Fatal("Unable to clone volume: ", err)
+		}
+	}
+
+	dependents, err := orchestrator.volumeDependents("source")
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"snapshot snap", "clone dependentClone"}, dependents)
+	}
+
+	// The volume is soft deleted while its snapshot and unsplit clone exist
+	assert.NoError(t, orchestrator.DeleteVolume(ctx(), "source"))
+	if volume, ok := orchestrator.volumes["source"]; assert.True(t, ok, "volume should be soft deleted") {
+		assert.Equal(t, storage.VolumeStateDeleting, volume.State)
+	}
+
+	assert.NoError(t, orchestrator.DeleteSnapshot(ctx(), "source", "snap"))
+	_, ok := orchestrator.volumes["source"]
+	assert.True(t, ok, "volume should outlive its dependent clone")
+
+	// Deleting the last dependent queues the deletion, which the deletion retry monitor completes
+	assert.NoError(t, orchestrator.DeleteVolume(ctx(), "dependentClone"))
+	_, ok = orchestrator.pendingDeletions["source"]
+	assert.True(t, ok, "volume deletion should be queued after its last dependent is deleted")
+	orchestrator.retryPendingDeletions()
+	_, ok = orchestrator.volumes["source"]
+	assert.False(t, ok, "volume should be deleted after its last dependent")
+	transactions, err := orchestrator.storeClient.GetVolumeTransactions()
+	assert.NoError(t, err)
+	assert.Empty(t, transactions)
+	_, ok = orchestrator.volumes["independentClone"]
+	assert.True(t, ok, "independent clones should be unaffected")
+}
@@ -3,6 +3,7 @@
 package core
 
 import (
+	"context"
 	"fmt"
 	"math/rand"
 	"reflect"
@@ -16,6 +17,7 @@ import (
 
 	"github.com/netapp/trident/config"
 	"github.com/netapp/trident/frontend"
+	persistentstore "github.com/netapp/trident/persistent_store"
 	"github.com/netapp/trident/storage"
 	"github.com/netapp/trident/storage/factory"
 	storageclass "github.com/netapp/trident/storage_class"
@@ -59,6 +61,9 @@ type MockOrchestrator struct {
 	storageClasses     map[string]*storageclass.StorageClass
 	volumes            map[string]*storage.Volume
 	nodes              map[string]*utils.Node
+	volumeConditions   map[string]*storage.VolumeCondition
+	capacities         []*storage.StorageClassCapacity
+	quotas             map[string]*storage.Quota
 	mutex              *sync.Mutex
 }
 
@@ -81,20 +86,18 @@ func (m *MockOrchestrator) GetVersion() (string, error) {
 // TODO:  Add extra methods to add backends without needing to provide a valid,
 // stringified JSON config.
 func (m *MockOrchestrator) AddBackend(configJSON string) (*storage.BackendExternal, error) {
-	// We need to do this to determine if the backend is NFS or not.
-	backend := &storage.Backend{
-		Name:        fmt.Sprintf("mock-%d", len(m.backendsByUUID)),
-		BackendUUID: uuid.New().String(),
-		Driver:      nil,
-		Online:      true,
-		State:       storage.Online,
-		Storage:     make(map[string]*storage.Pool),
+	backend, err := factory.NewStorageBackendForConfig(configJSON)
+	if err != nil {
+		return nil, err
 	}
+	backend.BackendUUID = uuid.New().String()
+
+	// We need to do this to determine if the backend is NFS or not.
 	mock := newMockBackend(backend.GetProtocol())
+	mock.name = backend.Name
+	mock.backendUUID = backend.BackendUUID
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
-	//m.backends[backend.Name] = backend
-	//m.mockBackends[backend.Name] = mock
 	m.backendsByUUID[backend.BackendUUID] = backend
 	m.mockBackendsByUUID[backend.BackendUUID] = mock
 	return backend.ConstructExternal(), nil
@@ -210,10 +213,20 @@ func (m *MockOrchestrator) UpdateBackend(backendName, configJSON string) (
 	return m.UpdateBackendByBackendUUID(backendName, backend.BackendUUID, configJSON)
 }
 
+// PreviewBackendUpdate reports what updating a backend would change
+func (m *MockOrchestrator) PreviewBackendUpdate(backendName, configJSON string) (
+	*storage.BackendUpdatePreview, error) {
+	//TODO
+	return nil, fmt.Errorf("operation not currently supported")
+}
+
 // UpdateBackendByBackendUUID updates an existing backend
 func (m *MockOrchestrator) UpdateBackendByBackendUUID(backendName, configJSON, backendUUID string) (
 	storageBackendExternal *storage.BackendExternal, err error) {
 
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
 	originalBackend, found := m.backendsByUUID[backendUUID]
 	if !found {
 		m.dumpKnownBackends()
@@ -226,7 +239,8 @@ func (m *MockOrchestrator) UpdateBackendByBackendUUID(backendName, configJSON, b
 	}
 
 	originalBackend.Terminate()
-	m.backendsByUUID[backendName] = newBackend
+	newBackend.BackendUUID = backendUUID
+	m.backendsByUUID[backendUUID] = newBackend
 	return newBackend.ConstructExternal(), nil
 }
 
@@ -237,6 +251,18 @@ func (m *MockOrchestrator) UpdateBackendState(backendName, backendState string)
 	return nil, fmt.Errorf("operation not currently supported")
 }
 
+// UpdateBackendTracing replaces the debug trace flags of an existing backend
+func (m *MockOrchestrator) UpdateBackendTracing(backendName string, debugTraceFlags map[string]bool) (
+	storageBackendExternal *storage.BackendExternal, err error) {
+	//TODO
+	return nil, fmt.Errorf("operation not currently supported")
+}
+
+// ReencryptBackendSecrets rewrites the backend secrets with the active encryption key
+func (m *MockOrchestrator) ReencryptBackendSecrets() ([]string, error) {
+	return nil, utils.UnsupportedError("backend secret encryption is not configured")
+}
+
 func (m *MockOrchestrator) dumpKnownBackends() {
 	log.Debug(">>>MockOrchestrator#dumpKnownBackends")
 	defer log.Debug("<<<MockOrchestrator#dumpKnownBackends")
@@ -323,7 +349,17 @@ func (m *MockOrchestrator) DeleteBackendByBackendUUID(backendName, backendUUID s
 	return nil
 }
 
-func (m *MockOrchestrator) AddVolume(volumeConfig *storage.VolumeConfig) (*storage.VolumeExternal, error) {
+func (m *MockOrchestrator) ForceDeleteBackend(backendName string) error {
+	return utils.UnsupportedError("only the custom resources of the CRD persistent store may be force deleted")
+}
+
+func (m *MockOrchestrator) ForceDeleteVolume(volumeName string) error {
+	return utils.UnsupportedError("only the custom resources of the CRD persistent store may be force deleted")
+}
+
+func (m *MockOrchestrator) AddVolume(
+	ctx context.Context, volumeConfig *storage.VolumeConfig,
+) (*storage.VolumeExternal, error) {
 	var mockBackends map[string]*mockBackend
 
 	// Don't bother with actually getting the backends from the storage class;
@@ -389,7 +425,9 @@ func (m *MockOrchestrator) AddVolume(volumeConfig *storage.VolumeConfig) (*stora
 	return volume.ConstructExternal(), nil
 }
 
-func (m *MockOrchestrator) CloneVolume(volumeConfig *storage.VolumeConfig) (*storage.VolumeExternal, error) {
+func (m *MockOrchestrator) CloneVolume(
+	ctx context.Context, volumeConfig *storage.VolumeConfig,
+) (*storage.VolumeExternal, error) {
 	// TODO: write this method to enable CloneVolume unit tests
 	return nil, nil
 }
@@ -400,7 +438,8 @@ func (m *MockOrchestrator) GetVolumeExternal(volumeName string, backendName stri
 }
 
 func (m *MockOrchestrator) LegacyImportVolume(
-	volumeConfig *storage.VolumeConfig, backendName string, notManaged bool, createPVandPVC VolumeCallback,
+	ctx context.Context, volumeConfig *storage.VolumeConfig, backendName string, notManaged bool,
+	createPVandPVC VolumeCallback,
 ) (externalVol *storage.VolumeExternal, err error) {
 
 	// TODO: write this method to enable GetVolumeExternal unit tests
@@ -408,13 +447,21 @@ func (m *MockOrchestrator) LegacyImportVolume(
 }
 
 func (m *MockOrchestrator) ImportVolume(
-	volumeConfig *storage.VolumeConfig,
+	ctx context.Context, volumeConfig *storage.VolumeConfig,
 ) (externalVol *storage.VolumeExternal, err error) {
 
 	// TODO: write this method to enable GetVolumeExternal unit tests
 	return nil, nil
 }
 
+func (m *MockOrchestrator) ImportVolumeDryRun(
+	volumeConfig *storage.VolumeConfig,
+) (*storage.ImportVolumeDryRunResult, error) {
+
+	// TODO: write this method to enable ImportVolumeDryRun unit tests
+	return nil, nil
+}
+
 func (m *MockOrchestrator) ValidateVolumes(
 	t *testing.T,
 	expectedConfigs []*storage.VolumeConfig,
@@ -506,7 +553,7 @@ func (m *MockOrchestrator) ListVolumes() ([]*storage.VolumeExternal, error) {
 	return volumes, nil
 }
 
-func (m *MockOrchestrator) DeleteVolume(volumeName string) error {
+func (m *MockOrchestrator) DeleteVolume(ctx context.Context, volumeName string) error {
 
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
@@ -523,6 +570,25 @@ func (m *MockOrchestrator) DeleteVolume(volumeName string) error {
 	return nil
 }
 
+func (m *MockOrchestrator) UnmanageVolume(volumeName, storageName string) (string, error) {
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	volume, ok := m.volumes[volumeName]
+	if !ok {
+		return "", utils.NotFoundError(fmt.Sprintf("volume %s not found", volumeName))
+	}
+
+	delete(m.mockBackendsByUUID[volume.BackendUUID].volumes, volume.Config.Name)
+	delete(m.volumes, volume.Config.Name)
+
+	if storageName == "" {
+		storageName = volume.Config.InternalName
+	}
+	return storageName, nil
+}
+
 func (m *MockOrchestrator) ListVolumesByPlugin(pluginName string) ([]*storage.VolumeExternal, error) {
 	// Currently returns nil, since this is backend agnostic.  Change this
 	// if we ever have non-apiserver functionality depend on this function.
@@ -538,11 +604,27 @@ func (m *MockOrchestrator) DetachVolume(volumeName, mountpoint string) error {
 }
 
 func (m *MockOrchestrator) PublishVolume(
-	volumeName string, publishInfo *utils.VolumePublishInfo) error {
+	ctx context.Context, volumeName string, publishInfo *utils.VolumePublishInfo) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if volume, ok := m.volumes[volumeName]; ok && publishInfo.HostName != "" {
+		volume.AddPublishedNode(publishInfo.HostName)
+	}
 	return nil
 }
 
-func (m *MockOrchestrator) CreateSnapshot(snapshotConfig *storage.SnapshotConfig) (*storage.SnapshotExternal, error) {
+func (m *MockOrchestrator) UnpublishVolume(ctx context.Context, volumeName, nodeName string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if volume, ok := m.volumes[volumeName]; ok {
+		volume.RemovePublishedNode(nodeName)
+	}
+	return nil
+}
+
+func (m *MockOrchestrator) CreateSnapshot(
+	ctx context.Context, snapshotConfig *storage.SnapshotConfig,
+) (*storage.SnapshotExternal, error) {
 	return nil, nil
 }
 
@@ -566,18 +648,273 @@ func (m *MockOrchestrator) ReadSnapshotsForVolume(volumeName string) ([]*storage
 	return make([]*storage.SnapshotExternal, 0), nil
 }
 
-func (m *MockOrchestrator) DeleteSnapshot(volumeName, snapshotName string) error {
+func (m *MockOrchestrator) DeleteSnapshot(ctx context.Context, volumeName, snapshotName string) error {
+	return nil
+}
+
+func (m *MockOrchestrator) RestoreSnapshot(ctx context.Context, volumeName, snapshotName string, force bool) error {
 	return nil
 }
 
+func (m *MockOrchestrator) CheckSnapshotRestore(volumeName, snapshotName string) (*storage.PreflightReport, error) {
+	return storage.NewPreflightReport(), nil
+}
+
 func (m *MockOrchestrator) ReloadVolumes() error {
 	return nil
 }
 
-func (m *MockOrchestrator) ResizeVolume(volumeName, newSize string) error {
+func (m *MockOrchestrator) ResizeVolume(ctx context.Context, volumeName, newSize string) error {
+	return nil
+}
+
+func (m *MockOrchestrator) MigrateVolume(
+	request *storage.VolumeMigrationRequest,
+) (*storage.VolumeMigrationExternal, error) {
+	return nil, nil
+}
+
+func (m *MockOrchestrator) PlanVolumeMigration(
+	request *storage.VolumeMigrationRequest,
+) (*storage.VolumeMigrationPlan, error) {
+	return &storage.VolumeMigrationPlan{VolumeName: request.Volume, Preflight: storage.NewPreflightReport()}, nil
+}
+
+func (m *MockOrchestrator) GetVolumeMigration(volumeName string) (*storage.VolumeMigrationExternal, error) {
+	return nil, nil
+}
+
+func (m *MockOrchestrator) ListVolumeMigrations() ([]*storage.VolumeMigrationExternal, error) {
+	return make([]*storage.VolumeMigrationExternal, 0), nil
+}
+
+func (m *MockOrchestrator) UpdateVolumeUsage(usage *storage.VolumeUsage) error {
+	return nil
+}
+
+func (m *MockOrchestrator) GetVolumeUsage(volumeName string) (*storage.VolumeUsage, error) {
+	return nil, utils.NotFoundError(fmt.Sprintf("usage of volume %s not found", volumeName))
+}
+
+func (m *MockOrchestrator) ListVolumeUsage() ([]*storage.VolumeUsage, error) {
+	return make([]*storage.VolumeUsage, 0), nil
+}
+
+func (m *MockOrchestrator) UpdateVolumeCondition(condition *storage.VolumeCondition) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	conditionCopy := *condition
+	m.volumeConditions[condition.VolumeName] = &conditionCopy
 	return nil
 }
 
+func (m *MockOrchestrator) GetVolumeCondition(volumeName string) (*storage.VolumeCondition, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if condition, ok := m.volumeConditions[volumeName]; ok {
+		conditionCopy := *condition
+		return &conditionCopy, nil
+	}
+	return &storage.VolumeCondition{VolumeName: volumeName}, nil
+}
+
+func (m *MockOrchestrator) ListVolumeConditions() ([]*storage.VolumeCondition, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	conditions := make([]*storage.VolumeCondition, 0, len(m.volumeConditions))
+	for _, condition := range m.volumeConditions {
+		conditionCopy := *condition
+		conditions = append(conditions, &conditionCopy)
+	}
+	return conditions, nil
+}
+
+// SetStorageClassCapacities sets the capacities returned by ListStorageClassCapacities.
+func (m *MockOrchestrator) SetStorageClassCapacities(capacities []*storage.StorageClassCapacity) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.capacities = capacities
+}
+
+func (m *MockOrchestrator) ListStorageClassCapacities() ([]*storage.StorageClassCapacity, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	capacities := make([]*storage.StorageClassCapacity, 0, len(m.capacities))
+	for _, capacity := range m.capacities {
+		capacityCopy := *capacity
+		capacities = append(capacities, &capacityCopy)
+	}
+	return capacities, nil
+}
+
+func (m *MockOrchestrator) GetBackendCapacity(backendName string) (*storage.BackendCapacity, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	backend, err := m.getBackendByName(backendName)
+	if err != nil {
+		return nil, err
+	}
+	capacity := &storage.BackendCapacity{Backend: backend.Name, Pools: make([]storage.PoolCapacity, 0)}
+	for poolName := range backend.Storage {
+		capacity.Pools = append(capacity.Pools, storage.PoolCapacity{Name: poolName})
+	}
+	return capacity, nil
+}
+
+func (m *MockOrchestrator) DetectDrift() (*storage.DriftReport, error) {
+	return &storage.DriftReport{Drifts: make([]*storage.Drift, 0)}, nil
+}
+
+func (m *MockOrchestrator) GetDriftReport() (*storage.DriftReport, error) {
+	return nil, utils.NotFoundError("drift detection has not run")
+}
+
+func (m *MockOrchestrator) CollectGarbage() (*storage.GarbageReport, error) {
+	return &storage.GarbageReport{Garbage: make([]*storage.Garbage, 0)}, nil
+}
+
+func (m *MockOrchestrator) GetGarbageReport() (*storage.GarbageReport, error) {
+	return nil, utils.NotFoundError("garbage collection has not run")
+}
+
+func (m *MockOrchestrator) CheckUpgradeReadiness() (*storage.PreflightReport, error) {
+	return storage.NewPreflightReport(), nil
+}
+
+func (m *MockOrchestrator) GetHealth() (*storage.HealthReport, error) {
+	report := &storage.HealthReport{
+		Status:   storage.HealthStatusHealthy,
+		Version:  config.OrchestratorVersion.String(),
+		Backends: make([]*storage.BackendHealth, 0),
+	}
+	for _, backend := range m.backendsByUUID {
+		report.Backends = append(report.Backends, &storage.BackendHealth{
+			Name:        backend.Name,
+			BackendUUID: backend.BackendUUID,
+			State:       backend.State,
+			Reachable:   true,
+			AuthStatus:  storage.AuthStatusUnknown,
+		})
+	}
+	return report, nil
+}
+
+func (m *MockOrchestrator) GetVolumeDeletion(volumeName string) (*storage.VolumeDeletion, error) {
+	return nil, utils.NotFoundError(fmt.Sprintf("pending deletion of volume %s not found", volumeName))
+}
+
+func (m *MockOrchestrator) ListVolumeDeletions() ([]*storage.VolumeDeletion, error) {
+	return make([]*storage.VolumeDeletion, 0), nil
+}
+
+func (m *MockOrchestrator) AddCloneGrant(grant *storage.CloneGrant) error {
+	return nil
+}
+
+func (m *MockOrchestrator) DeleteCloneGrant(namespace, name string) error {
+	return utils.NotFoundError(fmt.Sprintf("clone grant %s not found", storage.MakeCloneGrantID(namespace, name)))
+}
+
+func (m *MockOrchestrator) GetCloneGrant(namespace, name string) (*storage.CloneGrant, error) {
+	return nil, utils.NotFoundError(fmt.Sprintf("clone grant %s not found", storage.MakeCloneGrantID(namespace, name)))
+}
+
+func (m *MockOrchestrator) ListCloneGrants() ([]*storage.CloneGrant, error) {
+	return make([]*storage.CloneGrant, 0), nil
+}
+
+func (m *MockOrchestrator) AddQuota(quota *storage.Quota) error {
+	if err := quota.Validate(); err != nil {
+		return err
+	}
+	quotaCopy := *quota
+	m.quotas[quota.ID()] = &quotaCopy
+	return nil
+}
+
+func (m *MockOrchestrator) DeleteQuota(namespace, name string) error {
+	quotaID := storage.MakeQuotaID(namespace, name)
+	if _, ok := m.quotas[quotaID]; !ok {
+		return utils.NotFoundError(fmt.Sprintf("quota %s not found", quotaID))
+	}
+	delete(m.quotas, quotaID)
+	return nil
+}
+
+func (m *MockOrchestrator) GetQuota(namespace, name string) (*storage.QuotaExternal, error) {
+	quotaID := storage.MakeQuotaID(namespace, name)
+	quota, ok := m.quotas[quotaID]
+	if !ok {
+		return nil, utils.NotFoundError(fmt.Sprintf("quota %s not found", quotaID))
+	}
+	return &storage.QuotaExternal{Quota: *quota}, nil
+}
+
+func (m *MockOrchestrator) ListQuotas() ([]*storage.QuotaExternal, error) {
+	quotas := make([]*storage.QuotaExternal, 0, len(m.quotas))
+	for _, quota := range m.quotas {
+		quotas = append(quotas, &storage.QuotaExternal{Quota: *quota})
+	}
+	return quotas, nil
+}
+
+func (m *MockOrchestrator) ExportState() (*persistentstore.StateBundle, error) {
+	return nil, fmt.Errorf("state export is not supported by the mock orchestrator")
+}
+
+func (m *MockOrchestrator) ImportState(
+	bundle *persistentstore.StateBundle,
+) (*persistentstore.StateImportResult, error) {
+	return nil, fmt.Errorf("state import is not supported by the mock orchestrator")
+}
+
+func (m *MockOrchestrator) VerifyState(repair storage.StateRepair) (*storage.StateVerification, error) {
+	return &storage.StateVerification{Issues: make([]*storage.StateIssue, 0)}, nil
+}
+
+func (m *MockOrchestrator) AddVolumeGroup(
+	groupConfig *storage.VolumeGroupConfig,
+) (*storage.VolumeGroupExternal, error) {
+	return nil, fmt.Errorf("volume groups are not supported by the mock orchestrator")
+}
+
+func (m *MockOrchestrator) UpdateVolumeGroup(
+	groupConfig *storage.VolumeGroupConfig,
+) (*storage.VolumeGroupExternal, error) {
+	return nil, utils.NotFoundError(fmt.Sprintf("volume group %s not found", groupConfig.Name))
+}
+
+func (m *MockOrchestrator) DeleteVolumeGroup(groupName string) error {
+	return utils.NotFoundError(fmt.Sprintf("volume group %s not found", groupName))
+}
+
+func (m *MockOrchestrator) GetVolumeGroup(groupName string) (*storage.VolumeGroupExternal, error) {
+	return nil, utils.NotFoundError(fmt.Sprintf("volume group %s not found", groupName))
+}
+
+func (m *MockOrchestrator) ListVolumeGroups() ([]*storage.VolumeGroupExternal, error) {
+	return make([]*storage.VolumeGroupExternal, 0), nil
+}
+
+func (m *MockOrchestrator) CreateVolumeGroupSnapshot(
+	ctx context.Context, groupName string, request *storage.VolumeGroupSnapshotRequest,
+) (*storage.VolumeGroupSnapshot, error) {
+	return nil, utils.NotFoundError(fmt.Sprintf("volume group %s not found", groupName))
+}
+
+func (m *MockOrchestrator) CreateGroupSnapshot(
+	ctx context.Context, snapConfigs []*storage.SnapshotConfig,
+) (*storage.VolumeGroupSnapshot, error) {
+	return nil, fmt.Errorf("group snapshots are not supported by the mock orchestrator")
+}
+
+func (m *MockOrchestrator) CloneVolumeGroup(
+	ctx context.Context, groupName string, request *storage.VolumeGroupCloneRequest,
+) (*storage.VolumeGroupExternal, error) {
+	return nil, utils.NotFoundError(fmt.Sprintf("volume group %s not found", groupName))
+}
+
 func NewMockOrchestrator() *MockOrchestrator {
 	return &MockOrchestrator{
 		backendsByUUID:     make(map[string]*storage.Backend),
@@ -586,7 +923,11 @@ func NewMockOrchestrator() *MockOrchestrator {
 		// mockBackends:   make(map[string]*mockBackend),
 		storageClasses: make(map[string]*storageclass.StorageClass),
 		volumes:        make(map[string]*storage.Volume),
+		nodes:          make(map[string]*utils.Node),
 		mutex:          &sync.Mutex{},
+
+		volumeConditions: make(map[string]*storage.VolumeCondition),
+		quotas:           make(map[string]*storage.Quota),
 	}
 }
 
@@ -662,6 +1003,10 @@ func (m *MockOrchestrator) DeleteNode(nName string) error {
 	return nil
 }
 
+func (m *MockOrchestrator) ForceDetachNode(nodeName string) error {
+	return m.DeleteNode(nodeName)
+}
+
 func (m *MockOrchestrator) AddVolumeTransaction(volTxn *storage.VolumeTransaction) error {
 	return nil
 }
@@ -0,0 +1,215 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netapp/trident/storage"
+	"github.com/netapp/trident/utils"
+)
+
+/////////////////////////////////////////////////////////////////////////////
+//
+// This file contains the quota operations.  A quota limits the total size
+// and number of the volumes Trident provisions for a namespace, optionally
+// only counting volumes of one storage class.  Creates, clones and resizes
+// that would exceed a quota fail before any storage is touched.  Volumes
+// without a namespace, such as those created outside Kubernetes, are not
+// counted.  Quotas are kept in memory only, as they are supplied by a
+// frontend (such as from TridentQuota CRs) that resynchronizes them after a
+// restart.
+//
+/////////////////////////////////////////////////////////////////////////////
+
+// AddQuota adds a quota, or replaces the quota with the same namespace and name.
+func (o *TridentOrchestrator) AddQuota(quota *storage.Quota) (err error) {
+
+	if o.bootstrapError != nil {
+		return o.bootstrapError
+	}
+
+	defer recordTiming("quota_add", &err)()
+
+	if err = quota.Validate(); err != nil {
+		return err
+	}
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	quotaCopy := *quota
+	if existingQuota, ok := o.quotas[quota.ID()]; !ok || *existingQuota != quotaCopy {
+		log.WithFields(log.Fields{
+			"quota":        quota.ID(),
+			"storageClass": quota.StorageClass,
+			"maxBytes":     quota.MaxBytes,
+			"maxVolumes":   quota.MaxVolumes,
+		}).Info("Set quota.")
+	}
+	o.quotas[quota.ID()] = &quotaCopy
+	return nil
+}
+
+// DeleteQuota removes a quota.  Volumes it counted are not affected.
+func (o *TridentOrchestrator) DeleteQuota(namespace, name string) (err error) {
+
+	if o.bootstrapError != nil {
+		return o.bootstrapError
+	}
+
+	defer recordTiming("quota_delete", &err)()
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	quotaID := storage.MakeQuotaID(namespace, name)
+	if _, ok := o.quotas[quotaID]; !ok {
+		return utils.NotFoundError(fmt.Sprintf("quota %s not found", quotaID))
+	}
+	delete(o.quotas, quotaID)
+
+	log.WithField("quota", quotaID).Info("Deleted quota.")
+	return nil
+}
+
+// GetQuota returns a quota along with the usage it counts.
+func (o *TridentOrchestrator) GetQuota(namespace, name string) (quota *storage.QuotaExternal, err error) {
+
+	if o.bootstrapError != nil {
+		return nil, o.bootstrapError
+	}
+
+	defer recordTiming("quota_get", &err)()
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	quotaID := storage.MakeQuotaID(namespace, name)
+	existingQuota, ok := o.quotas[quotaID]
+	if !ok {
+		return nil, utils.NotFoundError(fmt.Sprintf("quota %s not found", quotaID))
+	}
+	return o.getQuotaExternal(existingQuota), nil
+}
+
+// ListQuotas returns all quotas along with the usage each counts.
+func (o *TridentOrchestrator) ListQuotas() (quotas []*storage.QuotaExternal, err error) {
+
+	if o.bootstrapError != nil {
+		return nil, o.bootstrapError
+	}
+
+	defer recordTiming("quota_list", &err)()
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	quotas = make([]*storage.QuotaExternal, 0, len(o.quotas))
+	for _, quota := range o.quotas {
+		quotas = append(quotas, o.getQuotaExternal(quota))
+	}
+	sort.Slice(quotas, func(i, j int) bool { return quotas[i].ID() < quotas[j].ID() })
+	return quotas, nil
+}
+
+// getQuotaExternal returns a copy of a quota with its usage.  The caller should hold the orchestrator lock.
+func (o *TridentOrchestrator) getQuotaExternal(quota *storage.Quota) *storage.QuotaExternal {
+	usedBytes, usedVolumes := o.getQuotaUsage(quota)
+	return &storage.QuotaExternal{Quota: *quota, UsedBytes: usedBytes, UsedVolumes: usedVolumes}
+}
+
+// getQuotaUsage returns the total size and number of the volumes a quota counts, including volumes
+// being created.  The caller should hold the orchestrator lock.
+func (o *TridentOrchestrator) getQuotaUsage(quota *storage.Quota) (usedBytes int64, usedVolumes int) {
+
+	for _, volume := range o.volumes {
+		if quota.Counts(volume.Config.Namespace, volume.Config.StorageClass) {
+			usedBytes += quotaVolumeSize(volume.Config.Size)
+			usedVolumes++
+		}
+	}
+	for volumeName, volumeConfig := range o.quotaReservations {
+		if _, ok := o.volumes[volumeName]; ok {
+			continue
+		}
+		if quota.Counts(volumeConfig.Namespace, volumeConfig.StorageClass) {
+			usedBytes += quotaVolumeSize(volumeConfig.Size)
+			usedVolumes++
+		}
+	}
+	return usedBytes, usedVolumes
+}
+
+// checkQuotas returns a QuotaExceededError if adding the given number of bytes and volumes to the
+// namespace and storage class of a volume would exceed any quota.  The caller should hold the
+// orchestrator lock.
+func (o *TridentOrchestrator) checkQuotas(
+	volumeConfig *storage.VolumeConfig, addBytes int64, addVolumes int,
+) error {
+
+	if volumeConfig.Namespace == "" {
+		return nil
+	}
+
+	quotaIDs := make([]string, 0)
+	for quotaID, quota := range o.quotas {
+		if quota.Counts(volumeConfig.Namespace, volumeConfig.StorageClass) {
+			quotaIDs = append(quotaIDs, quotaID)
+		}
+	}
+	sort.Strings(quotaIDs)
+
+	for _, quotaID := range quotaIDs {
+		quota := o.quotas[quotaID]
+		usedBytes, usedVolumes := o.getQuotaUsage(quota)
+		if err := quota.Check(usedBytes, usedVolumes, addBytes, addVolumes); err != nil {
+			log.WithFields(log.Fields{
+				"volume":      volumeConfig.Name,
+				"namespace":   volumeConfig.Namespace,
+				"quota":       quotaID,
+				"usedBytes":   usedBytes,
+				"usedVolumes": usedVolumes,
+				"addBytes":    addBytes,
+			}).Warning("Volume operation exceeds quota.")
+			return utils.QuotaExceededError(fmt.Sprintf("volume %s exceeds quota; %v", volumeConfig.Name, err))
+		}
+	}
+	return nil
+}
+
+// reserveQuota checks that a new volume of the given size fits within the quotas of its namespace, and
+// if so counts it until it is created or fails, since volumes are created without holding the
+// orchestrator lock.  The caller should hold the orchestrator lock, and call the returned function once
+// the volume is created or has failed.
+func (o *TridentOrchestrator) reserveQuota(volumeConfig *storage.VolumeConfig, sizeBytes int64) (func(), error) {
+
+	if err := o.checkQuotas(volumeConfig, sizeBytes, 1); err != nil {
+		return nil, err
+	}
+	if volumeConfig.Namespace == "" {
+		return func() {}, nil
+	}
+
+	reservation := volumeConfig.ConstructClone()
+	reservation.Size = strconv.FormatInt(sizeBytes, 10)
+	o.quotaReservations[volumeConfig.Name] = reservation
+	return func() { delete(o.quotaReservations, volumeConfig.Name) }, nil
+}
+
+// quotaVolumeSize returns the size of a volume in bytes, or zero if it isn't known.
+func quotaVolumeSize(size string) int64 {
+	sizeBytes, err := utils.ConvertSizeToBytes(size)
+	if err != nil {
+		return 0
+	}
+	sizeInt, err := strconv.ParseInt(sizeBytes, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return sizeInt
+}
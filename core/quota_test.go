@@ -0,0 +1,133 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/storage"
+	sa "github.com/netapp/trident/storage_attribute"
+	storageclass "github.com/netapp/trident/storage_class"
+	tu "github.com/netapp/trident/storage_drivers/fake/test_utils"
+	"github.com/netapp/trident/utils"
+)
+
+func TestQuotas(t *testing.T) {
+	const (
+		backendName = "quotaBackend"
+		scName      = "quotaSC"
+		gib         = 1024 * 1024 * 1024
+	)
+
+	orchestrator := getOrchestrator()
+	addBackend(t, orchestrator, backendName, config.File)
+	if _, err := orchestrator.AddStorageClass(&storageclass.Config{
+		Name:       scName,
+		Attributes: map[string]sa.Request{sa.TestingAttribute: sa.NewBoolRequest(true)},
+	}); err != nil {
+		t.Fatal("Unable to add storage class: ", err)
+	}
+
+	volumeConfig := func(name string, gb int, namespace string) *storage.VolumeConfig {
+		volumeConfig := tu.GenerateVolumeConfig(name, gb, scName, config.File)
+		volumeConfig.Namespace = namespace
+		return volumeConfig
+	}
+
+	assert.Error(t, orchestrator.AddQuota(&storage.Quota{Name: "tenant", Namespace: "tenant1"}),
+		"quotas should set a limit")
+	assert.NoError(t, orchestrator.AddQuota(&storage.Quota{
+		Name:       "tenant",
+		Namespace:  "tenant1",
+		MaxBytes:   3 * gib,
+		MaxVolumes: 2,
+	}))
+
+	_, err := orchestrator.AddVolume(ctx(), volumeConfig("vol1", 2, "tenant1"))
+	assert.NoError(t, err)
+	_, err = orchestrator.AddVolume(ctx(), volumeConfig("vol2", 2, "tenant1"))
+	assert.True(t, utils.IsQuotaExceededError(err), "the volume should exceed the byte limit")
+	_, err = orchestrator.AddVolume(ctx(), volumeConfig("vol2", 1, "tenant1"))
+	assert.NoError(t, err)
+
+	quota, err := orchestrator.GetQuota("tenant1", "tenant")
+	if assert.NoError(t, err) {
+		assert.Equal(t, int64(3*gib), quota.UsedBytes)
+		assert.Equal(t, 2, quota.UsedVolumes)
+	}
+
+	// Other namespaces and volumes without a namespace aren't limited
+	_, err = orchestrator.AddVolume(ctx(), volumeConfig("vol3", 4, "tenant2"))
+	assert.NoError(t, err)
+	_, err = orchestrator.AddVolume(ctx(), volumeConfig("vol4", 4, ""))
+	assert.NoError(t, err)
+
+	cloneConfig := volumeConfig("clone1", 1, "tenant1")
+	cloneConfig.CloneSourceVolume = "vol2"
+	_, err = orchestrator.CloneVolume(ctx(), cloneConfig)
+	assert.True(t, utils.IsQuotaExceededError(err), "the clone should exceed the volume limit")
+
+	err = orchestrator.ResizeVolume(ctx(), "vol1", "3Gi")
+	assert.True(t, utils.IsQuotaExceededError(err), "the resize should exceed the byte limit")
+	assert.NoError(t, orchestrator.ResizeVolume(ctx(), "vol3", "5Gi"))
+
+	// Deleting a volume frees its share of the quota
+	assert.NoError(t, orchestrator.DeleteVolume(ctx(), "vol2"))
+	assert.NoError(t, orchestrator.ResizeVolume(ctx(), "vol1", "3Gi"))
+
+	quotas, err := orchestrator.ListQuotas()
+	if assert.NoError(t, err) && assert.Len(t, quotas, 1) {
+		assert.Equal(t, int64(3*gib), quotas[0].UsedBytes)
+		assert.Equal(t, 1, quotas[0].UsedVolumes)
+	}
+
+	assert.NoError(t, orchestrator.DeleteQuota("tenant1", "tenant"))
+	assert.True(t, utils.IsNotFoundError(orchestrator.DeleteQuota("tenant1", "tenant")))
+	_, err = orchestrator.AddVolume(ctx(), volumeConfig("vol5", 4, "tenant1"))
+	assert.NoError(t, err)
+}
+
+func TestStorageClassQuota(t *testing.T) {
+	const (
+		backendName = "scQuotaBackend"
+		scName      = "goldSC"
+	)
+
+	orchestrator := getOrchestrator()
+	addBackend(t, orchestrator, backendName, config.File)
+	for _, name := range []string{scName, "silverSC"} {
+		if _, err := orchestrator.AddStorageClass(&storageclass.Config{
+			Name:       name,
+			Attributes: map[string]sa.Request{sa.TestingAttribute: sa.NewBoolRequest(true)},
+		}); err != nil {
+			t.Fatal("Unable to add storage class: ", err)
+		}
+	}
+
+	assert.NoError(t, orchestrator.AddQuota(&storage.Quota{
+		Name:         "gold",
+		Namespace:    "tenant1",
+		StorageClass: scName,
+		MaxVolumes:   1,
+	}))
+
+	for _, name := range []string{"scVol1", "scVol2"} {
+		volumeConfig := tu.GenerateVolumeConfig(name, 1, "silverSC", config.File)
+		volumeConfig.Namespace = "tenant1"
+		_, err := orchestrator.AddVolume(ctx(), volumeConfig)
+		assert.NoError(t, err, "volumes of other storage classes shouldn't be limited")
+	}
+
+	volumeConfig := tu.GenerateVolumeConfig("scVol3", 1, scName, config.File)
+	volumeConfig.Namespace = "tenant1"
+	_, err := orchestrator.AddVolume(ctx(), volumeConfig)
+	assert.NoError(t, err)
+
+	volumeConfig = tu.GenerateVolumeConfig("scVol4", 1, scName, config.File)
+	volumeConfig.Namespace = "tenant1"
+	_, err = orchestrator.AddVolume(ctx(), volumeConfig)
+	assert.True(t, utils.IsQuotaExceededError(err))
+}
@@ -0,0 +1,148 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/netapp/trident/storage"
+	"github.com/netapp/trident/utils"
+)
+
+/////////////////////////////////////////////////////////////////////////////
+//
+// This file contains the preflight check for restoring a snapshot, which
+// reports what would stop the restore and what it would discard.  A failed
+// check blocks the restore, except that a volume still published to nodes
+// may be restored with force, for when those nodes are known to be gone.
+//
+/////////////////////////////////////////////////////////////////////////////
+
+const (
+	snapshotRestoreCheckVolume         = "volume"
+	snapshotRestoreCheckPublications   = "publications"
+	snapshotRestoreCheckNewerSnapshots = "newerSnapshots"
+	snapshotRestoreCheckBackend        = "backend"
+)
+
+// CheckSnapshotRestore returns a report of whether a volume may be reverted to one of its snapshots,
+// and of the newer snapshots the restore could discard.
+func (o *TridentOrchestrator) CheckSnapshotRestore(
+	volumeName, snapshotName string,
+) (report *storage.PreflightReport, err error) {
+	if o.bootstrapError != nil {
+		return nil, o.bootstrapError
+	}
+
+	defer recordTiming("snapshot_restore_check", &err)()
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	snapshot, volume, err := o.getSnapshotToRestore(volumeName, snapshotName)
+	if err != nil {
+		return nil, err
+	}
+	return o.checkSnapshotRestore(snapshot, volume), nil
+}
+
+// getSnapshotToRestore returns a snapshot and its volume.  The caller must hold the orchestrator lock.
+func (o *TridentOrchestrator) getSnapshotToRestore(
+	volumeName, snapshotName string,
+) (*storage.Snapshot, *storage.Volume, error) {
+
+	snapshot, ok := o.snapshots[storage.MakeSnapshotID(volumeName, snapshotName)]
+	if !ok {
+		return nil, nil, utils.NotFoundError(fmt.Sprintf("snapshot %s of volume %s not found", snapshotName,
+			volumeName))
+	}
+	volume, ok := o.volumes[volumeName]
+	if !ok {
+		return nil, nil, utils.NotFoundError(fmt.Sprintf("volume %s not found", volumeName))
+	}
+	return snapshot, volume, nil
+}
+
+// checkSnapshotRestore returns the preflight report for restoring a snapshot.  The caller must hold
+// the orchestrator lock.
+func (o *TridentOrchestrator) checkSnapshotRestore(
+	snapshot *storage.Snapshot, volume *storage.Volume,
+) *storage.PreflightReport {
+
+	volumeName := volume.Config.Name
+	report := storage.NewPreflightReport()
+
+	volumeCheck := &storage.PreflightCheck{Name: snapshotRestoreCheckVolume}
+	if volume.State.IsDeleting() || o.volumeDeleteInProgress(volumeName) {
+		volumeCheck.Findings = append(volumeCheck.Findings, fmt.Sprintf("Volume %s is being deleted.", volumeName))
+	}
+	if o.volumeMigrationInProgress(volumeName) {
+		volumeCheck.Findings = append(volumeCheck.Findings, fmt.Sprintf("Volume %s is being migrated.", volumeName))
+		volumeCheck.Remediation = "Wait for the volume's migration to finish."
+	}
+	report.AddCheck(volumeCheck)
+
+	backendCheck := &storage.PreflightCheck{Name: snapshotRestoreCheckBackend}
+	if backend, ok := o.backends[volume.BackendUUID]; !ok {
+		backendCheck.Findings = []string{fmt.Sprintf("Backend %s of volume %s was not found.",
+			volume.BackendUUID, volumeName)}
+	} else if !backend.State.IsServing() {
+		backendCheck.Findings = []string{fmt.Sprintf("Backend %s is %s.", backend.Name, backend.State)}
+		backendCheck.Remediation = "Bring the backend online."
+	}
+	report.AddCheck(backendCheck)
+
+	publicationsCheck := &storage.PreflightCheck{Name: snapshotRestoreCheckPublications}
+	if len(volume.PublishedNodes) > 0 {
+		publicationsCheck.Findings = []string{fmt.Sprintf(
+			"Volume %s is published to nodes %s; unpublish it before restoring a snapshot.", volumeName,
+			strings.Join(volume.PublishedNodes, ", "))}
+		publicationsCheck.Remediation = "Stop the applications using the volume so it is unpublished, or " +
+			"restore with force if its nodes are gone."
+	}
+	report.AddCheck(publicationsCheck)
+
+	// Storage systems such as ONTAP delete the snapshots taken after the one that is restored
+	newerCheck := &storage.PreflightCheck{Name: snapshotRestoreCheckNewerSnapshots, Status: storage.PreflightPassed}
+	if restoredTime, err := time.Parse(time.RFC3339, snapshot.Created); err == nil {
+		for _, other := range o.snapshots {
+			if other.Config.VolumeName != volumeName {
+				continue
+			}
+			if created, err := time.Parse(time.RFC3339, other.Created); err == nil && created.After(restoredTime) {
+				newerCheck.Findings = append(newerCheck.Findings, fmt.Sprintf(
+					"Snapshot %s is newer and may be deleted by the storage system.", other.Config.Name))
+			}
+		}
+	}
+	sort.Strings(newerCheck.Findings)
+	if len(newerCheck.Findings) > 0 {
+		newerCheck.Status = storage.PreflightWarning
+		newerCheck.Remediation = "Clone the volume from the newer snapshots first to keep their data."
+	}
+	report.AddCheck(newerCheck)
+
+	return report
+}
+
+// snapshotRestoreError returns the findings of the checks that block a restore, or nil if it may
+// proceed.  With force, a volume's publications don't block it.
+func snapshotRestoreError(report *storage.PreflightReport, force bool) error {
+
+	findings := make([]string, 0)
+	for _, check := range report.Checks {
+		if check.Status != storage.PreflightFailed || (force && check.Name == snapshotRestoreCheckPublications) {
+			continue
+		}
+		for _, finding := range check.Findings {
+			findings = append(findings, strings.TrimSuffix(finding, "."))
+		}
+	}
+	if len(findings) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%s", strings.Join(findings, "; "))
+}
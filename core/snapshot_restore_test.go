@@ -0,0 +1,107 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/storage"
+	sa "github.com/netapp/trident/storage_attribute"
+	storageclass "github.com/netapp/trident/storage_class"
+	fakedriver "github.com/netapp/trident/storage_drivers/fake"
+	tu "github.com/netapp/trident/storage_drivers/fake/test_utils"
+	"github.com/netapp/trident/utils"
+)
+
+func TestRestoreSnapshot(t *testing.T) {
+	const (
+		backendName = "restoreBackend"
+		scName      = "restoreSC"
+		volumeName  = "restoreVolume"
+	)
+
+	orchestrator := getOrchestrator()
+	defer cleanup(t, orchestrator)
+	addBackend(t, orchestrator, backendName, config.File)
+	if _, err := orchestrator.AddStorageClass(&storageclass.Config{
+		Name:       scName,
+		Attributes: map[string]sa.Request{sa.TestingAttribute: sa.NewBoolRequest(true)},
+	}); err != nil {
+		t.Fatal("Unable to add storage class: ", err)
+	}
+	volume, err := orchestrator.AddVolume(ctx(), tu.GenerateVolumeConfig(volumeName, 1, scName, config.File))
+	if err != nil {
+		t.Fatal("Unable to create volume: ", err)
+	}
+
+	created := time.Date(2020, 10, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		snapshot, err := orchestrator.CreateSnapshot(ctx(), &storage.SnapshotConfig{
+			Name:               fmt.Sprintf("snap%d", i),
+			VolumeName:         volumeName,
+			VolumeInternalName: volume.Config.InternalName,
+		})
+		if err != nil {
+			t.Fatal("Unable to create snapshot: ", err)
+		}
+		orchestrator.snapshots[snapshot.ID()].Created = created.Add(time.Duration(i) * time.Hour).Format(time.RFC3339)
+	}
+
+	err = orchestrator.RestoreSnapshot(ctx(), volumeName, "missing", false)
+	assert.True(t, utils.IsNotFoundError(err), "restoring a missing snapshot should fail")
+
+	_, err = orchestrator.CheckSnapshotRestore(volumeName, "missing")
+	assert.True(t, utils.IsNotFoundError(err), "checking a missing snapshot should fail")
+
+	// A published volume's filesystem can't be reverted under its nodes
+	assert.NoError(t, orchestrator.AddNode(&utils.Node{Name: "node1", IQN: "iqn.node1"}))
+	assert.NoError(t, orchestrator.PublishVolume(ctx(), volumeName, &utils.VolumePublishInfo{HostName: "node1"}))
+	err = orchestrator.RestoreSnapshot(ctx(), volumeName, "snap0", false)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "unpublish it before restoring")
+	}
+
+	// The preflight report explains why, and lists the snapshots the restore could discard
+	report, err := orchestrator.CheckSnapshotRestore(volumeName, "snap0")
+	if assert.NoError(t, err) {
+		assert.False(t, report.Ready)
+		statuses := make(map[string]storage.PreflightStatus)
+		for _, check := range report.Checks {
+			statuses[check.Name] = check.Status
+			if check.Name == snapshotRestoreCheckNewerSnapshots {
+				assert.Len(t, check.Findings, 2)
+			}
+		}
+		assert.Equal(t, storage.PreflightFailed, statuses[snapshotRestoreCheckPublications])
+		assert.Equal(t, storage.PreflightWarning, statuses[snapshotRestoreCheckNewerSnapshots])
+		assert.Equal(t, storage.PreflightPassed, statuses[snapshotRestoreCheckVolume])
+	}
+
+	// Force restores a published volume whose nodes are gone
+	assert.NoError(t, orchestrator.RestoreSnapshot(ctx(), volumeName, "snap2", true))
+	assert.NoError(t, orchestrator.UnpublishVolume(ctx(), volumeName, "node1"))
+
+	// Snapshots the storage system deleted while restoring are forgotten, and the others are kept
+	backend := orchestrator.backends[volume.BackendUUID]
+	driver := backend.Driver.(*fakedriver.StorageDriver)
+	snap2 := orchestrator.snapshots[storage.MakeSnapshotID(volumeName, "snap2")]
+	delete(driver.Snapshots[volume.Config.InternalName], snap2.Config.InternalName)
+
+	assert.NoError(t, orchestrator.RestoreSnapshot(ctx(), volumeName, "snap0", false))
+
+	snapshots, err := orchestrator.ListSnapshotsForVolume(volumeName)
+	assert.NoError(t, err)
+	names := make([]string, 0)
+	for _, snapshot := range snapshots {
+		names = append(names, snapshot.Config.Name)
+	}
+	assert.ElementsMatch(t, []string{"snap0", "snap1"}, names)
+
+	persistent, err := orchestrator.storeClient.GetSnapshot(volumeName, "snap2")
+	assert.True(t, err != nil || persistent == nil, "a forgotten snapshot should be removed from the store")
+}
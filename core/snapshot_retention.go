@@ -0,0 +1,131 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netapp/trident/storage"
+)
+
+const snapshotRetentionPeriod = 15 * time.Minute
+
+// SetSnapshotRetentionPolicy sets the retention policy applied to the snapshots of volumes whose
+// storage class doesn't specify one.  A nil policy keeps every snapshot.
+func (o *TridentOrchestrator) SetSnapshotRetentionPolicy(policy *storage.SnapshotRetentionPolicy) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	o.snapshotRetention = policy
+}
+
+// StartSnapshotRetentionMonitor starts the thread that deletes snapshots expired by the
+// snapshot retention policies.
+func (o *TridentOrchestrator) StartSnapshotRetentionMonitor(period time.Duration) {
+
+	o.retentionMonitorTicker = time.NewTicker(period)
+	o.retentionMonitorChannel = make(chan struct{})
+
+	go func() {
+		log.Debug("Snapshot retention monitor started.")
+
+		for {
+			select {
+			case tick := <-o.retentionMonitorTicker.C:
+				log.WithField("tick", tick).Debug("Snapshot retention monitor running.")
+				o.pruneExpiredSnapshots()
+			case <-o.retentionMonitorChannel:
+				log.Debugf("Snapshot retention monitor stopped.")
+				return
+			}
+		}
+	}()
+}
+
+// StopSnapshotRetentionMonitor stops the thread that deletes expired snapshots.
+func (o *TridentOrchestrator) StopSnapshotRetentionMonitor() {
+	if o.retentionMonitorTicker != nil {
+		o.retentionMonitorTicker.Stop()
+	}
+	if o.retentionMonitorChannel != nil && !o.retentionMonitorStopped {
+		close(o.retentionMonitorChannel)
+		o.retentionMonitorStopped = true
+	}
+	log.Debug("Snapshot retention monitor stopped.")
+}
+
+// pruneExpiredSnapshots deletes the snapshots expired by the retention policy of each volume.
+// Each snapshot is deleted like any other, so a failure leaves the remaining snapshots for the
+// next run.
+func (o *TridentOrchestrator) pruneExpiredSnapshots() {
+
+	if o.bootstrapError != nil {
+		log.WithField("error", o.bootstrapError).Errorf("Snapshot retention monitor blocked by bootstrap error.")
+		return
+	}
+
+	for _, snapshotConfig := range o.getExpiredSnapshots() {
+
+		logFields := log.Fields{
+			"volume":   snapshotConfig.VolumeName,
+			"snapshot": snapshotConfig.Name,
+		}
+
+		if err := o.DeleteSnapshot(context.Background(), snapshotConfig.VolumeName, snapshotConfig.Name); err != nil {
+			log.WithFields(logFields).WithField("error", err).Warning("Could not delete expired snapshot.")
+			continue
+		}
+		log.WithFields(logFields).Info("Deleted expired snapshot.")
+	}
+}
+
+// getExpiredSnapshots returns the snapshots expired by the retention policy of each volume.  A
+// volume's storage class policy takes precedence over the orchestrator's default policy.  Only
+// snapshots Trident owns are considered, since deleting a snapshot created for a Kubernetes
+// VolumeSnapshot would leave that object pointing at nothing.
+func (o *TridentOrchestrator) getExpiredSnapshots() []*storage.SnapshotConfig {
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	snapshotsByVolume := make(map[string][]*storage.Snapshot)
+	for _, snapshot := range o.snapshots {
+		if !snapshot.Config.TridentOwned {
+			continue
+		}
+		if !snapshot.State.IsMissingBackend() && !snapshot.State.IsMissingVolume() {
+			snapshotsByVolume[snapshot.Config.VolumeName] = append(
+				snapshotsByVolume[snapshot.Config.VolumeName], snapshot)
+		}
+	}
+
+	volumeNames := make([]string, 0, len(snapshotsByVolume))
+	for volumeName := range snapshotsByVolume {
+		volumeNames = append(volumeNames, volumeName)
+	}
+	sort.Strings(volumeNames)
+
+	expired := make([]*storage.SnapshotConfig, 0)
+	for _, volumeName := range volumeNames {
+
+		volume, ok := o.volumes[volumeName]
+		if !ok || o.volumeMigrationInProgress(volumeName) {
+			continue
+		}
+
+		policy := o.snapshotRetention
+		if sc, ok := o.storageClasses[volume.Config.StorageClass]; ok && sc.GetSnapshotRetentionPolicy() != nil {
+			policy = sc.GetSnapshotRetentionPolicy()
+		}
+
+		for _, snapshot := range policy.ExpiredSnapshots(snapshotsByVolume[volumeName]) {
+			expired = append(expired, snapshot.Config)
+		}
+	}
+
+	return expired
+}
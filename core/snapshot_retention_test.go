@@ -0,0 +1,97 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/storage"
+	sa "github.com/netapp/trident/storage_attribute"
+	storageclass "github.com/netapp/trident/storage_class"
+	tu "github.com/netapp/trident/storage_drivers/fake/test_utils"
+)
+
+func TestPruneExpiredSnapshots(t *testing.T) {
+	const (
+		backendName    = "retentionBackend"
+		defaultSCName  = "retentionDefaultSC"
+		overrideSCName = "retentionOverrideSC"
+	)
+
+	orchestrator := getOrchestrator()
+	addBackend(t, orchestrator, backendName, config.File)
+
+	for _, scConfig := range []*storageclass.Config{
+		{Name: defaultSCName},
+		{Name: overrideSCName, SnapshotRetention: &storage.SnapshotRetentionPolicy{KeepLast: 1}},
+	} {
+		scConfig.Attributes = map[string]sa.Request{sa.TestingAttribute: sa.NewBoolRequest(true)}
+		if _, err := orchestrator.AddStorageClass(scConfig); err != nil {
+			t.Fatal("Unable to add storage class: ", err)
+		}
+	}
+
+	created := time.Date(2020, 10, 1, 0, 0, 0, 0, time.UTC)
+	for _, volumeName := range []string{"defaultVolume", "overrideVolume"} {
+		scName := defaultSCName
+		if volumeName == "overrideVolume" {
+			scName = overrideSCName
+		}
+		volume, err := orchestrator.AddVolume(ctx(), tu.GenerateVolumeConfig(volumeName, 1, scName, config.File))
+		if err != nil {
+			t.Fatal("Unable to create volume: ", err)
+		}
+		for i := 0; i < 3; i++ {
+			snapshot, err := orchestrator.CreateSnapshot(ctx(), &storage.SnapshotConfig{
+				Name:               fmt.Sprintf("snap%d", i),
+				VolumeName:         volumeName,
+				VolumeInternalName: volume.Config.InternalName,
+				TridentOwned:       true,
+			})
+			if err != nil {
+				t.Fatal("Unable to create snapshot: ", err)
+			}
+			orchestrator.snapshots[snapshot.ID()].Created = created.Add(time.Duration(i) * time.Hour).Format(time.RFC3339)
+		}
+
+		// The oldest snapshot belongs to a VolumeSnapshot, so no policy may delete it
+		snapshot, err := orchestrator.CreateSnapshot(ctx(), &storage.SnapshotConfig{
+			Name:               "csiSnap",
+			VolumeName:         volumeName,
+			VolumeInternalName: volume.Config.InternalName,
+		})
+		if err != nil {
+			t.Fatal("Unable to create snapshot: ", err)
+		}
+		orchestrator.snapshots[snapshot.ID()].Created = created.Add(-time.Hour).Format(time.RFC3339)
+	}
+
+	snapshotNames := func(volumeName string) []string {
+		snapshots, err := orchestrator.ListSnapshotsForVolume(volumeName)
+		if err != nil {
+			t.Fatal("Unable to list snapshots: ", err)
+		}
+		names := make([]string, 0)
+		for _, snapshot := range snapshots {
+			names = append(names, snapshot.Config.Name)
+		}
+		return names
+	}
+
+	// Without a default policy, only the storage class policy applies
+	orchestrator.pruneExpiredSnapshots()
+	assert.ElementsMatch(t, []string{"csiSnap", "snap0", "snap1", "snap2"}, snapshotNames("defaultVolume"))
+	assert.ElementsMatch(t, []string{"csiSnap", "snap2"}, snapshotNames("overrideVolume"))
+
+	orchestrator.SetSnapshotRetentionPolicy(&storage.SnapshotRetentionPolicy{KeepLast: 2})
+	orchestrator.pruneExpiredSnapshots()
+	assert.ElementsMatch(t, []string{"csiSnap", "snap1", "snap2"}, snapshotNames("defaultVolume"))
+	assert.ElementsMatch(t, []string{"csiSnap", "snap2"}, snapshotNames("overrideVolume"))
+
+	cleanup(t, orchestrator)
+}
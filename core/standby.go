@@ -0,0 +1,15 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package core
+
+// SetStandby marks the orchestrator as a standby controller replica that is waiting to be elected
+// leader before it bootstraps.  A standby orchestrator reports its version, so that it passes health
+// checks, but otherwise fails requests as not ready.  Bootstrapping ends standby.
+func (o *TridentOrchestrator) SetStandby() {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	if !o.bootstrapped {
+		o.standby = true
+	}
+}
@@ -0,0 +1,36 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	persistentstore "github.com/netapp/trident/persistent_store"
+	"github.com/netapp/trident/utils"
+)
+
+func TestStandby(t *testing.T) {
+
+	orchestrator := NewTridentOrchestrator(persistentstore.NewInMemoryClient())
+
+	// Before bootstrapping, the orchestrator isn't ready
+	_, err := orchestrator.GetVersion()
+	assert.True(t, utils.IsNotReadyError(err))
+
+	// A standby orchestrator reports its version, but still fails other requests
+	orchestrator.SetStandby()
+	_, err = orchestrator.GetVersion()
+	assert.NoError(t, err)
+	_, err = orchestrator.ListBackends()
+	assert.True(t, utils.IsNotReadyError(err))
+
+	// Bootstrapping ends standby
+	assert.NoError(t, orchestrator.Bootstrap())
+	assert.False(t, orchestrator.standby)
+	_, err = orchestrator.GetVersion()
+	assert.NoError(t, err)
+
+	orchestrator.Stop()
+}
@@ -0,0 +1,234 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netapp/trident/utils"
+)
+
+const (
+	stateBackupPeriod = time.Minute
+
+	stateBackupKeyPrefix  = "trident-state-"
+	stateBackupKeySuffix  = ".json"
+	stateBackupTimeFormat = "20060102T150405Z"
+)
+
+// StateBackupStore is an object store to which backups of Trident's state are written, such as an
+// S3 bucket accessed with a utils.S3Client.
+type StateBackupStore interface {
+	PutObject(key string, data []byte) error
+	ListObjects(prefix string) ([]string, error)
+	DeleteObject(key string) error
+}
+
+// StateBackupConfig describes where and when Trident's state is backed up.
+type StateBackupConfig struct {
+	Store    StateBackupStore
+	Schedule *utils.CronSchedule
+
+	// Prefix is prepended to the key of each backup, so that several Trident instances may share a bucket
+	Prefix string
+
+	// Retention is the number of backups kept, or 0 to keep every backup
+	Retention int
+}
+
+// SetStateBackup enables periodic backups of Trident's state.  A nil config disables them.
+func (o *TridentOrchestrator) SetStateBackup(config *StateBackupConfig) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	o.stateBackup = config
+}
+
+// stateBackupKey returns the key of the backup taken at the specified time.  The keys of a
+// config's backups sort in the order they were taken.
+func stateBackupKey(config *StateBackupConfig, taken time.Time) string {
+	return config.Prefix + stateBackupKeyPrefix + taken.UTC().Format(stateBackupTimeFormat) + stateBackupKeySuffix
+}
+
+// listStateBackups returns the keys of a config's backups, oldest first.
+func listStateBackups(config *StateBackupConfig) ([]string, error) {
+
+	keys, err := config.Store.ListObjects(config.Prefix + stateBackupKeyPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("could not list state backups; %v", err)
+	}
+
+	backupKeys := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if _, err := stateBackupTime(config, key); err == nil {
+			backupKeys = append(backupKeys, key)
+		}
+	}
+	sort.Strings(backupKeys)
+
+	return backupKeys, nil
+}
+
+// stateBackupTime returns the time a backup was taken, which is recorded in its key.
+func stateBackupTime(config *StateBackupConfig, key string) (time.Time, error) {
+	timestamp := strings.TrimPrefix(key, config.Prefix+stateBackupKeyPrefix)
+	timestamp = strings.TrimSuffix(timestamp, stateBackupKeySuffix)
+	return time.Parse(stateBackupTimeFormat, timestamp)
+}
+
+// BackupState writes a bundle of Trident's state, as exported by ExportState, to the backup store
+// and then deletes the oldest backups beyond the retention count.  It returns the backup's key.
+func (o *TridentOrchestrator) BackupState() (key string, err error) {
+
+	if o.bootstrapError != nil {
+		return "", o.bootstrapError
+	}
+
+	o.mutex.Lock()
+	config := o.stateBackup
+	o.mutex.Unlock()
+
+	if config == nil {
+		return "", utils.UnsupportedError("state backups are not configured")
+	}
+
+	defer recordTiming("state_backup", &err)()
+
+	bundle, err := o.ExportState()
+	if err != nil {
+		return "", err
+	}
+	bundleJSON, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("could not marshal state bundle; %v", err)
+	}
+
+	taken := time.Now()
+	key = stateBackupKey(config, taken)
+	if err = config.Store.PutObject(key, bundleJSON); err != nil {
+		return "", fmt.Errorf("could not write state backup %s; %v", key, err)
+	}
+	o.lastStateBackup = taken
+
+	log.WithFields(log.Fields{
+		"key":     key,
+		"size":    len(bundleJSON),
+		"volumes": len(bundle.Volumes),
+	}).Info("Backed up Trident state.")
+
+	// A failure to prune is only logged, as the backup itself succeeded
+	if pruned, err := pruneStateBackups(config); err != nil {
+		log.WithField("error", err).Warning("Could not delete old state backups.")
+	} else if len(pruned) > 0 {
+		log.WithField("keys", pruned).Debug("Deleted old state backups.")
+	}
+
+	return key, nil
+}
+
+// pruneStateBackups deletes the oldest backups beyond the retention count and returns their keys.
+func pruneStateBackups(config *StateBackupConfig) ([]string, error) {
+
+	if config.Retention <= 0 {
+		return nil, nil
+	}
+
+	keys, err := listStateBackups(config)
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) <= config.Retention {
+		return nil, nil
+	}
+
+	pruned := make([]string, 0)
+	for _, key := range keys[:len(keys)-config.Retention] {
+		if err = config.Store.DeleteObject(key); err != nil {
+			return pruned, fmt.Errorf("could not delete state backup %s; %v", key, err)
+		}
+		pruned = append(pruned, key)
+	}
+
+	return pruned, nil
+}
+
+// runScheduledStateBackup backs up Trident's state if a backup is due at the specified time.  The
+// time of the last backup is read from the store after a restart, so runs missed while Trident was
+// down are collapsed into one, and a failed backup is retried on each run until it succeeds.
+func (o *TridentOrchestrator) runScheduledStateBackup(now time.Time) {
+
+	if o.bootstrapError != nil {
+		log.WithField("error", o.bootstrapError).Errorf("State backup monitor blocked by bootstrap error.")
+		return
+	}
+
+	o.mutex.Lock()
+	config := o.stateBackup
+	o.mutex.Unlock()
+
+	if config == nil || config.Schedule == nil {
+		return
+	}
+
+	if o.lastStateBackup.IsZero() {
+		keys, err := listStateBackups(config)
+		if err != nil {
+			log.WithField("error", err).Warning("Could not find the last state backup.")
+			return
+		}
+		if len(keys) > 0 {
+			o.lastStateBackup, _ = stateBackupTime(config, keys[len(keys)-1])
+		}
+	}
+
+	// With no earlier backup, one is taken right away
+	if !o.lastStateBackup.IsZero() {
+		if next := config.Schedule.Next(o.lastStateBackup.UTC()); next.IsZero() || next.After(now.UTC()) {
+			return
+		}
+	}
+
+	if _, err := o.BackupState(); err != nil {
+		log.WithField("error", err).Warning("Could not back up Trident state.")
+	}
+}
+
+// StartStateBackupMonitor starts the thread that backs up Trident's state on its schedule.
+func (o *TridentOrchestrator) StartStateBackupMonitor(period time.Duration) {
+
+	o.stateBackupTicker = time.NewTicker(period)
+	o.stateBackupChannel = make(chan struct{})
+
+	go func() {
+		log.Debug("State backup monitor started.")
+
+		for {
+			select {
+			case tick := <-o.stateBackupTicker.C:
+				log.WithField("tick", tick).Trace("State backup monitor running.")
+				o.runScheduledStateBackup(tick)
+			case <-o.stateBackupChannel:
+				log.Debugf("State backup monitor stopped.")
+				return
+			}
+		}
+	}()
+}
+
+// StopStateBackupMonitor stops the thread that backs up Trident's state.
+func (o *TridentOrchestrator) StopStateBackupMonitor() {
+	if o.stateBackupTicker != nil {
+		o.stateBackupTicker.Stop()
+	}
+	if o.stateBackupChannel != nil && !o.stateBackupStopped {
+		close(o.stateBackupChannel)
+		o.stateBackupStopped = true
+	}
+	log.Debug("State backup monitor stopped.")
+}
@@ -0,0 +1,119 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netapp/trident/config"
+	persistentstore "github.com/netapp/trident/persistent_store"
+	"github.com/netapp/trident/utils"
+)
+
+type fakeStateBackupStore struct {
+	objects map[string][]byte
+	putErr  error
+}
+
+func (s *fakeStateBackupStore) PutObject(key string, data []byte) error {
+	if s.putErr != nil {
+		return s.putErr
+	}
+	s.objects[key] = data
+	return nil
+}
+
+func (s *fakeStateBackupStore) ListObjects(prefix string) ([]string, error) {
+	keys := make([]string, 0)
+	for key := range s.objects {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+func (s *fakeStateBackupStore) DeleteObject(key string) error {
+	delete(s.objects, key)
+	return nil
+}
+
+func TestBackupState(t *testing.T) {
+
+	o := newStateOrchestrator(t)
+	addBackendStorageClass(t, o, "backupBackend", "backupSC", config.File)
+
+	_, err := o.BackupState()
+	assert.True(t, utils.IsUnsupportedError(err), "backups should fail until configured")
+
+	store := &fakeStateBackupStore{objects: map[string][]byte{"cluster1/other.json": {}}}
+	backupConfig := &StateBackupConfig{Store: store, Prefix: "cluster1/", Retention: 2}
+	o.SetStateBackup(backupConfig)
+
+	// Keep more backups than the retention count, as if taken earlier
+	for _, taken := range []time.Time{
+		time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC),
+	} {
+		store.objects[stateBackupKey(backupConfig, taken)] = []byte("{}")
+	}
+
+	key, err := o.BackupState()
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(key, "cluster1/trident-state-"))
+
+	bundle := &persistentstore.StateBundle{}
+	require.NoError(t, json.Unmarshal(store.objects[key], bundle))
+	assert.NoError(t, bundle.Validate())
+	assert.Len(t, bundle.Backends, 1)
+
+	// The oldest backup is pruned, and other objects are left alone
+	keys, err := listStateBackups(backupConfig)
+	require.NoError(t, err)
+	assert.Equal(t, []string{stateBackupKey(backupConfig, time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)), key}, keys)
+	assert.Contains(t, store.objects, "cluster1/other.json")
+}
+
+func TestRunScheduledStateBackup(t *testing.T) {
+
+	o := newStateOrchestrator(t)
+
+	schedule, err := utils.ParseCronSchedule("@daily")
+	require.NoError(t, err)
+	store := &fakeStateBackupStore{objects: make(map[string][]byte)}
+	backupConfig := &StateBackupConfig{Store: store, Schedule: schedule}
+
+	// The last backup is read from the store after a restart
+	lastBackup := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+	store.objects[stateBackupKey(backupConfig, lastBackup)] = []byte("{}")
+	o.SetStateBackup(backupConfig)
+
+	o.runScheduledStateBackup(lastBackup.Add(time.Minute))
+	assert.Len(t, store.objects, 1, "no backup should be due yet")
+	assert.Equal(t, lastBackup, o.lastStateBackup)
+
+	// Failed backups are retried
+	store.putErr = errors.New("bucket unavailable")
+	o.runScheduledStateBackup(lastBackup.Add(25 * time.Hour))
+	assert.Len(t, store.objects, 1)
+
+	store.putErr = nil
+	o.runScheduledStateBackup(lastBackup.Add(25 * time.Hour))
+	assert.Len(t, store.objects, 2)
+	assert.True(t, o.lastStateBackup.After(lastBackup))
+
+	// With no earlier backup, one is taken right away
+	o.lastStateBackup = time.Time{}
+	store.objects = make(map[string][]byte)
+	o.runScheduledStateBackup(time.Now())
+	assert.Len(t, store.objects, 1)
+}
@@ -0,0 +1,209 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	persistentstore "github.com/netapp/trident/persistent_store"
+	"github.com/netapp/trident/storage"
+	storageclass "github.com/netapp/trident/storage_class"
+	drivers "github.com/netapp/trident/storage_drivers"
+	"github.com/netapp/trident/storage_drivers/fake"
+	"github.com/netapp/trident/utils"
+)
+
+/////////////////////////////////////////////////////////////////////////////
+//
+// This file contains the state export and import operations, which allow
+// Trident's state to be moved to a fresh Trident install in another cluster
+// for disaster recovery.  An import rebinds the bundle's volumes to the
+// backends of the same name that already exist, adding any backends that
+// don't, so that volumes need not be imported one at a time.  Nodes and
+// transactions are not imported, as nodes register themselves again and a
+// transaction can't be safely replayed in another cluster.
+//
+/////////////////////////////////////////////////////////////////////////////
+
+// ExportState returns a bundle containing all of Trident's persistent state.
+func (o *TridentOrchestrator) ExportState() (bundle *persistentstore.StateBundle, err error) {
+
+	if o.bootstrapError != nil {
+		return nil, o.bootstrapError
+	}
+
+	defer recordTiming("state_export", &err)()
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	return persistentstore.ExportState(o.storeClient)
+}
+
+// ImportState adds the backends, storage classes, volumes and snapshots in a bundle.  The bundle is
+// checked in full before anything is changed, so an import either fails without side effects or
+// fails partway only if a backend or the persistent store returns an error.
+func (o *TridentOrchestrator) ImportState(
+	bundle *persistentstore.StateBundle,
+) (result *persistentstore.StateImportResult, err error) {
+
+	if o.bootstrapError != nil {
+		return nil, o.bootstrapError
+	}
+
+	defer recordTiming("state_import", &err)()
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	defer o.updateMetrics()
+
+	if err = bundle.Validate(); err != nil {
+		return nil, err
+	}
+
+	result = &persistentstore.StateImportResult{
+		AddedBackends:   make([]string, 0),
+		ReboundBackends: make([]string, 0),
+		StorageClasses:  make([]string, 0),
+		Volumes:         make([]string, 0),
+		Snapshots:       make([]string, 0),
+		Skipped:         make([]string, 0),
+	}
+
+	// Map each of the bundle's backends to a backend in this instance, if there is one
+	backendConfigs := make(map[string]string)
+	backendUUIDs := make(map[string]string)
+	for _, bundleBackend := range bundle.Backends {
+		configJSON, err := bundleBackend.MarshalConfig()
+		if err != nil {
+			return nil, err
+		}
+		backendConfigs[bundleBackend.BackendUUID] = configJSON
+
+		backend, _ := o.getBackendByBackendName(bundleBackend.Name)
+		if backend == nil {
+			continue
+		}
+		commonConfig := &drivers.CommonStorageDriverConfig{}
+		if err = json.Unmarshal([]byte(configJSON), commonConfig); err != nil {
+			return nil, fmt.Errorf("invalid config for backend %s; %v",
+				bundleBackend.Name, err)
+		}
+		if commonConfig.StorageDriverName != backend.GetDriverName() {
+			return nil, fmt.Errorf(
+				"backend %s uses the %s driver, but the bundle's backend of that name uses the %s driver",
+				backend.Name, backend.GetDriverName(), commonConfig.StorageDriverName)
+		}
+		backendUUIDs[bundleBackend.BackendUUID] = backend.BackendUUID
+	}
+
+	// Volumes and snapshots may not be replaced
+	for _, volume := range bundle.Volumes {
+		if _, ok := o.volumes[volume.Config.Name]; ok {
+			return nil, utils.FoundError(fmt.Sprintf("volume %s already exists", volume.Config.Name))
+		}
+	}
+	for _, snapshot := range bundle.Snapshots {
+		snapshotID := storage.MakeSnapshotID(snapshot.Config.VolumeName, snapshot.Config.Name)
+		if _, ok := o.snapshots[snapshotID]; ok {
+			return nil, utils.FoundError(fmt.Sprintf("snapshot %s already exists", snapshotID))
+		}
+	}
+
+	// Add the backends that don't exist yet, keeping their UUIDs so their volumes need not be changed
+	for _, bundleBackend := range bundle.Backends {
+		if backendUUID, ok := backendUUIDs[bundleBackend.BackendUUID]; ok {
+			result.ReboundBackends = append(result.ReboundBackends, bundleBackend.Name)
+			log.WithFields(log.Fields{
+				"backend":     bundleBackend.Name,
+				"backendUUID": backendUUID,
+			}).Info("Rebinding volumes to existing backend.")
+			continue
+		}
+		backendExternal, err := o.addBackend(backendConfigs[bundleBackend.BackendUUID], bundleBackend.BackendUUID)
+		if err != nil {
+			return result, fmt.Errorf("could not add backend %s; %v", bundleBackend.Name, err)
+		}
+		backendUUIDs[bundleBackend.BackendUUID] = backendExternal.BackendUUID
+		result.AddedBackends = append(result.AddedBackends, bundleBackend.Name)
+	}
+
+	for _, psc := range bundle.StorageClasses {
+		sc := storageclass.NewFromPersistent(psc)
+		if _, ok := o.storageClasses[sc.GetName()]; ok {
+			result.Skipped = append(result.Skipped,
+				fmt.Sprintf("storage class %s already exists", sc.GetName()))
+			continue
+		}
+		if err = o.storeClient.AddStorageClass(sc); err != nil {
+			return result, fmt.Errorf("could not add storage class %s; %v", sc.GetName(), err)
+		}
+		o.storageClasses[sc.GetName()] = sc
+		for _, backend := range o.backends {
+			sc.CheckAndAddBackend(backend)
+		}
+		result.StorageClasses = append(result.StorageClasses, sc.GetName())
+	}
+
+	for _, volumeExternal := range bundle.Volumes {
+		backendUUID := backendUUIDs[volumeExternal.BackendUUID]
+		volume := storage.NewVolume(volumeExternal.Config, backendUUID, volumeExternal.Pool,
+			volumeExternal.Orphaned)
+		if err = o.storeClient.AddVolume(volume); err != nil {
+			return result, fmt.Errorf("could not add volume %s; %v", volume.Config.Name, err)
+		}
+		o.volumes[volume.Config.Name] = volume
+		if backend, ok := o.backends[backendUUID]; ok {
+			backend.Volumes[volume.Config.Name] = volume
+			if fakeDriver, ok := backend.Driver.(*fake.StorageDriver); ok {
+				fakeDriver.BootstrapVolume(volume)
+			}
+		}
+		result.Volumes = append(result.Volumes, volume.Config.Name)
+	}
+
+	for _, snapshotPersistent := range bundle.Snapshots {
+		snapshot := storage.NewSnapshot(snapshotPersistent.Config, snapshotPersistent.Created,
+			snapshotPersistent.SizeBytes)
+		volume, ok := o.volumes[snapshot.Config.VolumeName]
+		if !ok {
+			result.Skipped = append(result.Skipped, fmt.Sprintf("snapshot %s of missing volume %s",
+				snapshot.Config.Name, snapshot.Config.VolumeName))
+			continue
+		}
+		if err = o.storeClient.AddSnapshot(snapshot); err != nil {
+			return result, fmt.Errorf("could not add snapshot %s; %v", snapshot.ID(), err)
+		}
+		o.snapshots[snapshot.ID()] = snapshot
+		if backend, ok := o.backends[volume.BackendUUID]; ok {
+			if fakeDriver, ok := backend.Driver.(*fake.StorageDriver); ok {
+				fakeDriver.BootstrapSnapshot(snapshot)
+			}
+		}
+		result.Snapshots = append(result.Snapshots, snapshot.ID())
+	}
+
+	for _, node := range bundle.Nodes {
+		result.Skipped = append(result.Skipped, fmt.Sprintf("node %s", node.Name))
+	}
+	for _, txn := range bundle.Transactions {
+		if txn.Config != nil {
+			result.Skipped = append(result.Skipped, fmt.Sprintf("%s transaction for volume %s", txn.Op,
+				txn.Config.Name))
+		}
+	}
+
+	log.WithFields(log.Fields{
+		"addedBackends":   len(result.AddedBackends),
+		"reboundBackends": len(result.ReboundBackends),
+		"storageClasses":  len(result.StorageClasses),
+		"volumes":         len(result.Volumes),
+		"snapshots":       len(result.Snapshots),
+		"skipped":         len(result.Skipped),
+	}).Info("Imported state bundle.")
+
+	return result, nil
+}
@@ -0,0 +1,130 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netapp/trident/config"
+	persistentstore "github.com/netapp/trident/persistent_store"
+	"github.com/netapp/trident/storage"
+	tu "github.com/netapp/trident/storage_drivers/fake/test_utils"
+	"github.com/netapp/trident/utils"
+)
+
+func newStateOrchestrator(t *testing.T) *TridentOrchestrator {
+	o := NewTridentOrchestrator(persistentstore.NewInMemoryClient())
+	if err := o.Bootstrap(); err != nil {
+		t.Fatal("Unable to bootstrap orchestrator: ", err)
+	}
+	return o
+}
+
+func TestExportImportState(t *testing.T) {
+	const (
+		sharedBackend = "drShared"
+		sourceBackend = "drSource"
+		scName        = "drSC"
+		volumeName    = "drVolume"
+		snapName      = "drSnapshot"
+	)
+
+	source := newStateOrchestrator(t)
+	addBackendStorageClass(t, source, sharedBackend, scName, config.File)
+	if _, err := source.AddVolume(ctx(), tu.GenerateVolumeConfig(volumeName, 1, scName, config.File)); err != nil {
+		t.Fatal("Unable to create volume: ", err)
+	}
+	if _, err := source.CreateSnapshot(ctx(), generateSnapshotConfig(snapName, volumeName, volumeName)); err != nil {
+		t.Fatal("Unable to create snapshot: ", err)
+	}
+	addBackend(t, source, sourceBackend, config.File)
+
+	bundle, err := source.ExportState()
+	if err != nil {
+		t.Fatal("Unable to export state: ", err)
+	}
+	assert.Equal(t, persistentstore.StateBundleVersion, bundle.Version)
+	assert.Len(t, bundle.Backends, 2)
+	assert.Len(t, bundle.Volumes, 1)
+	assert.Len(t, bundle.Snapshots, 1)
+
+	// Bundles must survive being written out
+	bundleJSON, err := json.Marshal(bundle)
+	if err != nil {
+		t.Fatal("Unable to marshal state bundle: ", err)
+	}
+	bundle = &persistentstore.StateBundle{}
+	if err = json.Unmarshal(bundleJSON, bundle); err != nil {
+		t.Fatal("Unable to unmarshal state bundle: ", err)
+	}
+
+	// The destination already has a backend for the volume's storage, with its own UUID
+	destination := newStateOrchestrator(t)
+	addBackend(t, destination, sharedBackend, config.File)
+	existingBackend, err := destination.GetBackend(sharedBackend)
+	if err != nil {
+		t.Fatal("Unable to get backend: ", err)
+	}
+
+	result, err := destination.ImportState(bundle)
+	if err != nil {
+		t.Fatal("Unable to import state: ", err)
+	}
+	assert.Equal(t, []string{sharedBackend}, result.ReboundBackends)
+	assert.Equal(t, []string{sourceBackend}, result.AddedBackends)
+	assert.Equal(t, []string{scName}, result.StorageClasses)
+	assert.Equal(t, []string{volumeName}, result.Volumes)
+	assert.Len(t, result.Snapshots, 1)
+
+	volume, err := destination.GetVolume(volumeName)
+	if assert.NoError(t, err) {
+		assert.Equal(t, existingBackend.BackendUUID, volume.BackendUUID)
+	}
+	_, err = destination.GetSnapshot(volumeName, snapName)
+	assert.NoError(t, err)
+	addedBackend, err := destination.GetBackend(sourceBackend)
+	if assert.NoError(t, err) {
+		for _, backend := range bundle.Backends {
+			if backend.Name == sourceBackend {
+				assert.Equal(t, backend.BackendUUID, addedBackend.BackendUUID)
+			}
+		}
+	}
+	_, err = destination.GetStorageClass(scName)
+	assert.NoError(t, err)
+
+	// The imported state is persistent
+	restarted := NewTridentOrchestrator(destination.storeClient)
+	if err = restarted.Bootstrap(); err != nil {
+		t.Fatal("Unable to bootstrap orchestrator: ", err)
+	}
+	volume, err = restarted.GetVolume(volumeName)
+	if assert.NoError(t, err) {
+		assert.Equal(t, existingBackend.BackendUUID, volume.BackendUUID)
+	}
+
+	// Volumes are never replaced
+	_, err = destination.ImportState(bundle)
+	assert.True(t, utils.IsFoundError(err), "expected found error, got %v", err)
+}
+
+func TestImportStateInvalidBundle(t *testing.T) {
+	o := newStateOrchestrator(t)
+
+	_, err := o.ImportState(&persistentstore.StateBundle{Version: "0"})
+	assert.Error(t, err)
+
+	bundle, err := o.ExportState()
+	if err != nil {
+		t.Fatal("Unable to export state: ", err)
+	}
+	bundle.Volumes = append(bundle.Volumes, &storage.VolumeExternal{
+		Config:      tu.GenerateVolumeConfig("orphan", 1, "", config.File),
+		BackendUUID: "missingUUID",
+	})
+	_, err = o.ImportState(bundle)
+	assert.Error(t, err)
+}
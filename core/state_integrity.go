@@ -0,0 +1,462 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	persistentstore "github.com/netapp/trident/persistent_store"
+	"github.com/netapp/trident/storage"
+	"github.com/netapp/trident/utils"
+)
+
+// stateRepairs are the functions that apply each repair offered for a state issue.
+type stateRepairs map[storage.StateRepair]func() error
+
+// stateVerifier collects the issues found while verifying Trident's persistent state.
+type stateVerifier struct {
+	verification *storage.StateVerification
+	repairs      []stateRepairs
+}
+
+func (s *stateVerifier) report(issue *storage.StateIssue, repairs stateRepairs) {
+	issue.Repairs = make([]storage.StateRepair, 0)
+	for _, repair := range []storage.StateRepair{
+		storage.StateRepairReadopt, storage.StateRepairPrune, storage.StateRepairRebuild,
+	} {
+		if _, ok := repairs[repair]; ok {
+			issue.Repairs = append(issue.Repairs, repair)
+		}
+	}
+	s.verification.Issues = append(s.verification.Issues, issue)
+	s.repairs = append(s.repairs, repairs)
+}
+
+// VerifyState checks the cross-references of Trident's persistent state, and the checksums of the
+// persisted objects against Trident's record of them.  If a repair is given, it is applied to each
+// issue it may be applied to.
+func (o *TridentOrchestrator) VerifyState(
+	repair storage.StateRepair,
+) (verification *storage.StateVerification, err error) {
+	if o.bootstrapError != nil {
+		return nil, o.bootstrapError
+	}
+
+	defer recordTiming("state_verify", &err)()
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	defer o.updateMetrics()
+
+	verifier := &stateVerifier{
+		verification: &storage.StateVerification{
+			Started: time.Now().UTC().Format(time.RFC3339),
+			Issues:  make([]*storage.StateIssue, 0),
+		},
+	}
+	verification = verifier.verification
+
+	// A missing key only means that there are no objects of that kind
+	backends, err := o.storeClient.GetBackends()
+	if err != nil && !persistentstore.MatchKeyNotFoundErr(err) {
+		return nil, fmt.Errorf("could not read backends; %v", err)
+	}
+	volumes, err := o.storeClient.GetVolumes()
+	if err != nil && !persistentstore.MatchKeyNotFoundErr(err) {
+		return nil, fmt.Errorf("could not read volumes; %v", err)
+	}
+	snapshots, err := o.storeClient.GetSnapshots()
+	if err != nil && !persistentstore.MatchKeyNotFoundErr(err) {
+		return nil, fmt.Errorf("could not read snapshots; %v", err)
+	}
+	nodes, err := o.storeClient.GetNodes()
+	if err != nil && !persistentstore.MatchKeyNotFoundErr(err) {
+		return nil, fmt.Errorf("could not read nodes; %v", err)
+	}
+	err = nil
+
+	verification.Backends = len(backends)
+	verification.Volumes = len(volumes)
+	verification.Snapshots = len(snapshots)
+	verification.Nodes = len(nodes)
+
+	o.verifyBackends(verifier, backends)
+	o.verifyVolumes(verifier, backends, volumes, nodes)
+	o.verifySnapshots(verifier, volumes, snapshots)
+
+	for i, issue := range verification.Issues {
+		if repair != "" && issue.HasRepair(repair) {
+			if repairErr := verifier.repairs[i][repair](); repairErr != nil {
+				verification.Errors = append(verification.Errors, fmt.Sprintf("could not %s %s %s; %v",
+					repair, issue.Kind, issue.Name, repairErr))
+			} else {
+				issue.Repaired = repair
+			}
+		}
+
+		log.WithFields(log.Fields{
+			"type":      issue.Type,
+			"kind":      issue.Kind,
+			"name":      issue.Name,
+			"reference": issue.Reference,
+			"repaired":  issue.Repaired,
+		}).Warning(issue.Message)
+	}
+
+	verification.Finished = time.Now().UTC().Format(time.RFC3339)
+
+	log.WithFields(log.Fields{
+		"issues": len(verification.Issues),
+		"errors": len(verification.Errors),
+		"repair": repair,
+	}).Info("State verification complete.")
+
+	return verification, nil
+}
+
+// verifyBackends checks that Trident's backends are persisted as Trident knows them.  The caller
+// must hold the orchestrator lock.
+func (o *TridentOrchestrator) verifyBackends(verifier *stateVerifier, backends []*storage.BackendPersistent) {
+
+	persistedBackends := make(map[string]*storage.BackendPersistent, len(backends))
+	for _, backend := range backends {
+		persistedBackends[backend.BackendUUID] = backend
+	}
+
+	for backendUUID, backend := range o.backends {
+		backend := backend
+		expected := backend.ConstructPersistent()
+
+		persisted, ok := persistedBackends[backendUUID]
+		if !ok {
+			verifier.report(&storage.StateIssue{
+				Type:    storage.StateIssueNotPersisted,
+				Kind:    "backend",
+				Name:    backend.Name,
+				Message: fmt.Sprintf("Backend %s is not persisted.", backend.Name),
+			}, stateRepairs{
+				storage.StateRepairReadopt: func() error { return o.storeClient.AddBackend(backend) },
+			})
+			continue
+		}
+
+		if actual := persisted.Checksum(); actual != expected.Checksum() {
+			verifier.report(&storage.StateIssue{
+				Type:     storage.StateIssueChecksumMismatch,
+				Kind:     "backend",
+				Name:     backend.Name,
+				Expected: expected.Checksum(),
+				Actual:   actual,
+				Message:  fmt.Sprintf("Persisted backend %s differs from Trident's record of it.", backend.Name),
+			}, stateRepairs{
+				storage.StateRepairReadopt: func() error { return o.storeClient.UpdateBackend(backend) },
+			})
+		}
+	}
+}
+
+// verifyVolumes checks that the persisted volumes refer to persisted backends and registered nodes,
+// and that Trident's volumes are persisted as Trident knows them.  The caller must hold the
+// orchestrator lock.
+func (o *TridentOrchestrator) verifyVolumes(
+	verifier *stateVerifier, backends []*storage.BackendPersistent, volumes []*storage.VolumeExternal,
+	nodes []*utils.Node,
+) {
+
+	backendUUIDs := make(map[string]bool, len(backends))
+	for _, backend := range backends {
+		backendUUIDs[backend.BackendUUID] = true
+	}
+	nodeNames := make(map[string]bool, len(nodes))
+	for _, node := range nodes {
+		nodeNames[node.Name] = true
+	}
+	volumeNames := make(map[string]bool, len(volumes))
+
+	for _, persisted := range volumes {
+		persisted := persisted
+		volumeName := persisted.Config.Name
+		volumeNames[volumeName] = true
+
+		if !backendUUIDs[persisted.BackendUUID] {
+			verifier.report(&storage.StateIssue{
+				Type:      storage.StateIssueMissingBackend,
+				Kind:      "volume",
+				Name:      volumeName,
+				Reference: persisted.BackendUUID,
+				Message: fmt.Sprintf("Volume %s refers to backend %s, which is not persisted.",
+					volumeName, persisted.BackendUUID),
+			}, stateRepairs{
+				storage.StateRepairReadopt: func() error { return o.readoptVolumeBackend(volumeName) },
+				storage.StateRepairPrune:   func() error { return o.pruneVolume(persisted) },
+			})
+		}
+
+		for _, nodeName := range persisted.PublishedNodes {
+			nodeName := nodeName
+			if nodeNames[nodeName] {
+				continue
+			}
+			verifier.report(&storage.StateIssue{
+				Type:      storage.StateIssueMissingNode,
+				Kind:      "volume",
+				Name:      volumeName,
+				Reference: nodeName,
+				Message: fmt.Sprintf("Volume %s is published to node %s, which is not registered.",
+					volumeName, nodeName),
+			}, stateRepairs{
+				storage.StateRepairPrune: func() error { return o.removeStaleAttachment(volumeName, nodeName) },
+			})
+		}
+
+		volume, ok := o.volumes[volumeName]
+		if !ok {
+			verifier.report(&storage.StateIssue{
+				Type:    storage.StateIssueNotLoaded,
+				Kind:    "volume",
+				Name:    volumeName,
+				Message: fmt.Sprintf("Persisted volume %s is not known to Trident.", volumeName),
+			}, stateRepairs{
+				storage.StateRepairReadopt: func() error { o.loadVolume(persisted); return nil },
+				storage.StateRepairPrune:   func() error { return o.pruneVolume(persisted) },
+			})
+			continue
+		}
+
+		expected := volume.ConstructExternal().Checksum()
+		if actual := persisted.Checksum(); actual != expected {
+			verifier.report(&storage.StateIssue{
+				Type:     storage.StateIssueChecksumMismatch,
+				Kind:     "volume",
+				Name:     volumeName,
+				Expected: expected,
+				Actual:   actual,
+				Message:  fmt.Sprintf("Persisted volume %s differs from Trident's record of it.", volumeName),
+			}, stateRepairs{
+				storage.StateRepairReadopt: func() error { return o.storeClient.UpdateVolume(volume) },
+				storage.StateRepairRebuild: func() error { return o.rebuildVolume(volume, true) },
+			})
+		}
+	}
+
+	for volumeName, volume := range o.volumes {
+		volume := volume
+		if volumeNames[volumeName] {
+			continue
+		}
+		verifier.report(&storage.StateIssue{
+			Type:    storage.StateIssueNotPersisted,
+			Kind:    "volume",
+			Name:    volumeName,
+			Message: fmt.Sprintf("Volume %s is not persisted.", volumeName),
+		}, stateRepairs{
+			storage.StateRepairReadopt: func() error { return o.storeClient.AddVolume(volume) },
+			storage.StateRepairRebuild: func() error { return o.rebuildVolume(volume, false) },
+		})
+	}
+}
+
+// verifySnapshots checks that the persisted snapshots refer to persisted volumes, and that Trident's
+// snapshots are persisted as Trident knows them.  The caller must hold the orchestrator lock.
+func (o *TridentOrchestrator) verifySnapshots(
+	verifier *stateVerifier, volumes []*storage.VolumeExternal, snapshots []*storage.SnapshotPersistent,
+) {
+
+	volumeNames := make(map[string]bool, len(volumes))
+	for _, volume := range volumes {
+		volumeNames[volume.Config.Name] = true
+	}
+	snapshotIDs := make(map[string]bool, len(snapshots))
+
+	for _, persisted := range snapshots {
+		persisted := persisted
+		snapshotID := persisted.ID()
+		snapshotIDs[snapshotID] = true
+
+		if volumeName := persisted.Config.VolumeName; !volumeNames[volumeName] {
+			verifier.report(&storage.StateIssue{
+				Type:      storage.StateIssueMissingVolume,
+				Kind:      "snapshot",
+				Name:      snapshotID,
+				Reference: volumeName,
+				Message: fmt.Sprintf("Snapshot %s refers to volume %s, which is not persisted.",
+					snapshotID, volumeName),
+			}, stateRepairs{
+				storage.StateRepairPrune: func() error { return o.pruneSnapshot(persisted) },
+			})
+			continue
+		}
+
+		snapshot, ok := o.snapshots[snapshotID]
+		if !ok {
+			continue
+		}
+		expected := snapshot.ConstructPersistent().Checksum()
+		if actual := persisted.Checksum(); actual != expected {
+			verifier.report(&storage.StateIssue{
+				Type:     storage.StateIssueChecksumMismatch,
+				Kind:     "snapshot",
+				Name:     snapshotID,
+				Expected: expected,
+				Actual:   actual,
+				Message:  fmt.Sprintf("Persisted snapshot %s differs from Trident's record of it.", snapshotID),
+			}, stateRepairs{
+				storage.StateRepairReadopt: func() error { return o.rewriteSnapshot(snapshot, true) },
+				storage.StateRepairRebuild: func() error { return o.rebuildSnapshot(snapshot, true) },
+			})
+		}
+	}
+
+	for snapshotID, snapshot := range o.snapshots {
+		snapshot := snapshot
+		if snapshotIDs[snapshotID] {
+			continue
+		}
+		verifier.report(&storage.StateIssue{
+			Type:    storage.StateIssueNotPersisted,
+			Kind:    "snapshot",
+			Name:    snapshotID,
+			Message: fmt.Sprintf("Snapshot %s is not persisted.", snapshotID),
+		}, stateRepairs{
+			storage.StateRepairReadopt: func() error { return o.rewriteSnapshot(snapshot, false) },
+			storage.StateRepairRebuild: func() error { return o.rebuildSnapshot(snapshot, false) },
+		})
+	}
+}
+
+// readoptVolumeBackend binds a volume whose backend is missing to the serving backend that reports
+// the volume's storage.  The caller must hold the orchestrator lock.
+func (o *TridentOrchestrator) readoptVolumeBackend(volumeName string) error {
+
+	volume, ok := o.volumes[volumeName]
+	if !ok {
+		return fmt.Errorf("volume %s is not known to Trident", volumeName)
+	}
+
+	for _, backend := range o.backends {
+		if !backend.State.IsServing() || backend.Driver.Get(volume.Config.InternalName) != nil {
+			continue
+		}
+
+		oldBackendUUID, oldState := volume.BackendUUID, volume.State
+		volume.BackendUUID = backend.BackendUUID
+		volume.State = storage.VolumeStateOnline
+		if err := o.storeClient.UpdateVolume(volume); err != nil {
+			volume.BackendUUID, volume.State = oldBackendUUID, oldState
+			return err
+		}
+		backend.Volumes[volumeName] = volume
+		return nil
+	}
+
+	return fmt.Errorf("no online backend reports volume %s", volume.Config.InternalName)
+}
+
+// loadVolume adds a persisted volume to Trident's record, just as bootstrap would.  The caller must
+// hold the orchestrator lock.
+func (o *TridentOrchestrator) loadVolume(persisted *storage.VolumeExternal) {
+
+	volume := storage.NewVolume(persisted.Config, persisted.BackendUUID, persisted.Pool, persisted.Orphaned)
+	volume.PublishedNodes = persisted.PublishedNodes
+	o.volumes[volume.Config.Name] = volume
+
+	if backend, ok := o.backends[persisted.BackendUUID]; ok {
+		backend.Volumes[volume.Config.Name] = volume
+	} else {
+		volume.State = storage.VolumeStateMissingBackend
+	}
+}
+
+// pruneVolume deletes a persisted volume and Trident's record of it, leaving any storage on its
+// backend alone.  The caller must hold the orchestrator lock.
+func (o *TridentOrchestrator) pruneVolume(persisted *storage.VolumeExternal) error {
+
+	volumeName := persisted.Config.Name
+	volume, ok := o.volumes[volumeName]
+	if !ok {
+		volume = storage.NewVolume(persisted.Config, persisted.BackendUUID, persisted.Pool, persisted.Orphaned)
+	}
+
+	// A volume may have several issues, so it may already have been pruned
+	if err := o.storeClient.DeleteVolume(volume); err != nil && !persistentstore.MatchKeyNotFoundErr(err) {
+		return err
+	}
+
+	if backend, ok := o.backends[volume.BackendUUID]; ok {
+		delete(backend.Volumes, volumeName)
+	}
+	delete(o.volumes, volumeName)
+	return nil
+}
+
+// rebuildVolume persists a volume with the size its backend reports.  The caller must hold the
+// orchestrator lock.
+func (o *TridentOrchestrator) rebuildVolume(volume *storage.Volume, persisted bool) error {
+
+	backend, ok := o.backends[volume.BackendUUID]
+	if !ok {
+		return fmt.Errorf("backend %s is not known to Trident", volume.BackendUUID)
+	}
+
+	discovered, err := backend.GetVolumeExternal(volume.Config.InternalName)
+	if err != nil {
+		return err
+	}
+	if discovered.Config != nil && discovered.Config.Size != "" {
+		volume.Config.Size = discovered.Config.Size
+	}
+
+	if persisted {
+		return o.storeClient.UpdateVolume(volume)
+	}
+	return o.storeClient.AddVolume(volume)
+}
+
+// pruneSnapshot deletes a persisted snapshot and Trident's record of it.  The caller must hold the
+// orchestrator lock.
+func (o *TridentOrchestrator) pruneSnapshot(persisted *storage.SnapshotPersistent) error {
+
+	if err := o.storeClient.DeleteSnapshot(&persisted.Snapshot); err != nil {
+		return err
+	}
+	delete(o.snapshots, persisted.ID())
+	return nil
+}
+
+// rewriteSnapshot persists Trident's record of a snapshot.  The store can't update snapshots, so a
+// persisted snapshot is deleted and added again.  The caller must hold the orchestrator lock.
+func (o *TridentOrchestrator) rewriteSnapshot(snapshot *storage.Snapshot, persisted bool) error {
+
+	if persisted {
+		if err := o.storeClient.DeleteSnapshot(snapshot); err != nil {
+			return err
+		}
+	}
+	return o.storeClient.AddSnapshot(snapshot)
+}
+
+// rebuildSnapshot persists a snapshot with the creation time and size its backend reports.  The
+// caller must hold the orchestrator lock.
+func (o *TridentOrchestrator) rebuildSnapshot(snapshot *storage.Snapshot, persisted bool) error {
+
+	volume, ok := o.volumes[snapshot.Config.VolumeName]
+	if !ok {
+		return fmt.Errorf("volume %s is not known to Trident", snapshot.Config.VolumeName)
+	}
+	backend, ok := o.backends[volume.BackendUUID]
+	if !ok {
+		return fmt.Errorf("backend %s is not known to Trident", volume.BackendUUID)
+	}
+
+	discovered, err := backend.GetSnapshot(snapshot.Config)
+	if err != nil {
+		return err
+	}
+	snapshot.Created = discovered.Created
+	snapshot.SizeBytes = discovered.SizeBytes
+
+	return o.rewriteSnapshot(snapshot, persisted)
+}
@@ -0,0 +1,184 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/storage"
+	sa "github.com/netapp/trident/storage_attribute"
+	storageclass "github.com/netapp/trident/storage_class"
+	tu "github.com/netapp/trident/storage_drivers/fake/test_utils"
+)
+
+func stateIssuesByType(verification *storage.StateVerification) map[storage.StateIssueType][]*storage.StateIssue {
+	issues := make(map[storage.StateIssueType][]*storage.StateIssue)
+	for _, issue := range verification.Issues {
+		issues[issue.Type] = append(issues[issue.Type], issue)
+	}
+	return issues
+}
+
+func TestVerifyState(t *testing.T) {
+	const (
+		backendName = "verifyBackend"
+		scName      = "verifySC"
+	)
+
+	orchestrator := getOrchestrator()
+	addBackend(t, orchestrator, backendName, config.File)
+
+	if _, err := orchestrator.AddStorageClass(&storageclass.Config{
+		Name:       scName,
+		Attributes: map[string]sa.Request{sa.TestingAttribute: sa.NewBoolRequest(true)},
+	}); err != nil {
+		t.Fatal("Unable to add storage class: ", err)
+	}
+
+	volume, err := orchestrator.AddVolume(ctx(), tu.GenerateVolumeConfig("verified", 1, scName, config.File))
+	if err != nil {
+		t.Fatal("Unable to create volume: ", err)
+	}
+
+	verification, err := orchestrator.VerifyState("")
+	assert.NoError(t, err)
+	assert.Empty(t, verification.Issues, "expected no issues in a consistent state")
+	assert.Equal(t, 1, verification.Backends)
+	assert.Equal(t, 1, verification.Volumes)
+
+	// A snapshot whose volume is missing
+	staleSnapshot := storage.NewSnapshot(&storage.SnapshotConfig{
+		Name:               "snap",
+		VolumeName:         "deleted",
+		VolumeInternalName: "deleted",
+	}, "", 0)
+	if err = orchestrator.storeClient.AddSnapshot(staleSnapshot); err != nil {
+		t.Fatal("Unable to add snapshot: ", err)
+	}
+
+	// A persisted volume that Trident doesn't know about, on a missing backend
+	unknownConfig := tu.GenerateVolumeConfig("unknown", 1, scName, config.File)
+	if err = orchestrator.storeClient.AddVolume(storage.NewVolume(unknownConfig, "missing", "", false)); err != nil {
+		t.Fatal("Unable to add volume: ", err)
+	}
+
+	// A persisted volume that differs from Trident's record, and is published to a missing node
+	trackedVolume := orchestrator.volumes[volume.Config.Name]
+	corruptConfig := *trackedVolume.Config
+	corruptConfig.Size = "2147483648"
+	corruptVolume := storage.NewVolume(&corruptConfig, trackedVolume.BackendUUID, trackedVolume.Pool, false)
+	corruptVolume.AddPublishedNode("deregistered")
+	if err = orchestrator.storeClient.UpdateVolume(corruptVolume); err != nil {
+		t.Fatal("Unable to update volume: ", err)
+	}
+
+	// Without a repair, the issues are only reported
+	verification, err = orchestrator.VerifyState("")
+	assert.NoError(t, err)
+	assert.Empty(t, verification.Errors)
+	issues := stateIssuesByType(verification)
+	if assert.Len(t, issues[storage.StateIssueMissingVolume], 1) {
+		assert.Equal(t, staleSnapshot.ID(), issues[storage.StateIssueMissingVolume][0].Name)
+		assert.Equal(t, []storage.StateRepair{storage.StateRepairPrune},
+			issues[storage.StateIssueMissingVolume][0].Repairs)
+	}
+	if assert.Len(t, issues[storage.StateIssueMissingBackend], 1) {
+		assert.Equal(t, "unknown", issues[storage.StateIssueMissingBackend][0].Name)
+		assert.Equal(t, "missing", issues[storage.StateIssueMissingBackend][0].Reference)
+	}
+	if assert.Len(t, issues[storage.StateIssueNotLoaded], 1) {
+		assert.Equal(t, "unknown", issues[storage.StateIssueNotLoaded][0].Name)
+	}
+	if assert.Len(t, issues[storage.StateIssueMissingNode], 1) {
+		assert.Equal(t, "verified", issues[storage.StateIssueMissingNode][0].Name)
+		assert.Equal(t, "deregistered", issues[storage.StateIssueMissingNode][0].Reference)
+	}
+	if assert.Len(t, issues[storage.StateIssueChecksumMismatch], 1) {
+		mismatch := issues[storage.StateIssueChecksumMismatch][0]
+		assert.Equal(t, "verified", mismatch.Name)
+		assert.NotEqual(t, mismatch.Expected, mismatch.Actual)
+	}
+	for _, issue := range verification.Issues {
+		assert.Empty(t, issue.Repaired, issue.Name)
+	}
+
+	// Pruning removes the stale snapshot, the unknown volume and the stale attachment
+	verification, err = orchestrator.VerifyState(storage.StateRepairPrune)
+	assert.NoError(t, err)
+	assert.Empty(t, verification.Errors)
+	issues = stateIssuesByType(verification)
+	assert.Equal(t, storage.StateRepairPrune, issues[storage.StateIssueMissingVolume][0].Repaired)
+	assert.Equal(t, storage.StateRepairPrune, issues[storage.StateIssueMissingNode][0].Repaired)
+	assert.Empty(t, issues[storage.StateIssueChecksumMismatch][0].Repaired)
+
+	snapshots, err := orchestrator.storeClient.GetSnapshots()
+	assert.NoError(t, err)
+	assert.Empty(t, snapshots)
+	_, err = orchestrator.storeClient.GetVolume("unknown")
+	assert.Error(t, err)
+
+	// Removing the attachment rewrote the volume as Trident knows it
+	verification, err = orchestrator.VerifyState("")
+	assert.NoError(t, err)
+	assert.Empty(t, verification.Issues, "expected no issues after pruning")
+
+	// Readopting rewrites the volume as Trident knows it
+	corruptVolume.PublishedNodes = nil
+	if err = orchestrator.storeClient.UpdateVolume(corruptVolume); err != nil {
+		t.Fatal("Unable to update volume: ", err)
+	}
+	verification, err = orchestrator.VerifyState(storage.StateRepairReadopt)
+	assert.NoError(t, err)
+	assert.Empty(t, verification.Errors)
+	if assert.Len(t, verification.Issues, 1) {
+		assert.Equal(t, storage.StateIssueChecksumMismatch, verification.Issues[0].Type)
+		assert.Equal(t, storage.StateRepairReadopt, verification.Issues[0].Repaired)
+	}
+
+	persistedVolume, err := orchestrator.storeClient.GetVolume(volume.Config.Name)
+	assert.NoError(t, err)
+	assert.Equal(t, trackedVolume.Config.Size, persistedVolume.Config.Size)
+
+	verification, err = orchestrator.VerifyState("")
+	assert.NoError(t, err)
+	assert.Empty(t, verification.Issues, "expected no issues after repairs")
+
+	cleanup(t, orchestrator)
+}
+
+func TestVerifyStateReadoptUnloadedVolume(t *testing.T) {
+	const backendName = "verifyReadoptBackend"
+
+	orchestrator := getOrchestrator()
+	addBackend(t, orchestrator, backendName, config.File)
+
+	var backendUUID string
+	for uuid := range orchestrator.backends {
+		backendUUID = uuid
+	}
+
+	unloadedConfig := tu.GenerateVolumeConfig("unloaded", 1, "", config.File)
+	unloaded := storage.NewVolume(unloadedConfig, backendUUID, "", false)
+	if err := orchestrator.storeClient.AddVolume(unloaded); err != nil {
+		t.Fatal("Unable to add volume: ", err)
+	}
+
+	verification, err := orchestrator.VerifyState(storage.StateRepairReadopt)
+	assert.NoError(t, err)
+	assert.Empty(t, verification.Errors)
+	if assert.Len(t, verification.Issues, 1) {
+		assert.Equal(t, storage.StateIssueNotLoaded, verification.Issues[0].Type)
+		assert.Equal(t, storage.StateRepairReadopt, verification.Issues[0].Repaired)
+	}
+
+	loaded, ok := orchestrator.volumes["unloaded"]
+	if assert.True(t, ok, "expected the volume to be loaded") {
+		assert.Equal(t, backendUUID, loaded.BackendUUID)
+		assert.Contains(t, orchestrator.backends[backendUUID].Volumes, "unloaded")
+	}
+
+	cleanup(t, orchestrator)
+}
@@ -0,0 +1,195 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netapp/trident/storage"
+	"github.com/netapp/trident/utils"
+)
+
+// ListStorageClassCapacities returns the space available to new volumes of each storage class, as
+// reported live by the storage systems behind the classes' pools.  A class is omitted if any of its
+// pools is on a backend that can't report capacity, since its capacity would be understated.  Pools
+// on backends that aren't accepting new volumes offer no capacity.
+func (o *TridentOrchestrator) ListStorageClassCapacities() (capacities []*storage.StorageClassCapacity, err error) {
+	if o.bootstrapError != nil {
+		return nil, o.bootstrapError
+	}
+
+	defer recordTiming("storage_capacity_list", &err)()
+
+	type classPool struct {
+		backendUUID string
+		pool        string
+	}
+
+	classPools := make(map[string][]classPool)
+	reporting := make(map[string]*storage.Backend)
+
+	o.mutex.Lock()
+	for scName, sc := range o.storageClasses {
+		pools := make([]classPool, 0)
+		known := true
+		for _, pool := range sc.Pools() {
+			backend := pool.Backend
+			if backend == nil || !backend.State.AcceptsNewVolumes() {
+				continue
+			}
+			if !backend.CanReportStorageCapacity() {
+				known = false
+				break
+			}
+			reporting[backend.BackendUUID] = backend
+			pools = append(pools, classPool{backend.BackendUUID, pool.Name})
+		}
+		if !known {
+			log.WithField("storageClass", scName).Debug("Storage class uses a backend that can't report capacity.")
+			continue
+		}
+		classPools[scName] = pools
+	}
+	o.mutex.Unlock()
+
+	// Query the storage systems without holding the orchestrator lock
+	backendCapacities := make(map[string]*storage.StorageCapacity, len(reporting))
+	for backendUUID, backend := range reporting {
+		capacity, err := backend.GetStorageCapacity()
+		if err != nil {
+			log.WithFields(log.Fields{
+				"backend": backend.Name,
+				"error":   err,
+			}).Warning("Could not get storage capacity.")
+			continue
+		}
+		backendCapacities[backendUUID] = capacity
+	}
+
+	capacities = make([]*storage.StorageClassCapacity, 0, len(classPools))
+	for scName, pools := range classPools {
+
+		// Count each source once per backend, however many of the class's pools draw on it
+		sources := make(map[string]map[string]bool)
+		known := true
+		for _, pool := range pools {
+			capacity, ok := backendCapacities[pool.backendUUID]
+			if !ok {
+				known = false
+				break
+			}
+			if _, ok := sources[pool.backendUUID]; !ok {
+				sources[pool.backendUUID] = make(map[string]bool)
+			}
+			for _, source := range capacity.PoolSources[pool.pool] {
+				sources[pool.backendUUID][source] = true
+			}
+		}
+		if !known {
+			continue
+		}
+
+		classCapacity := &storage.StorageClassCapacity{StorageClass: scName}
+		for backendUUID, backendSources := range sources {
+			for source := range backendSources {
+				free := backendCapacities[backendUUID].SourceFreeBytes[source]
+				if free <= 0 {
+					continue
+				}
+				classCapacity.CapacityBytes += free
+				if free > classCapacity.MaximumVolumeSizeBytes {
+					classCapacity.MaximumVolumeSizeBytes = free
+				}
+			}
+		}
+		capacities = append(capacities, classCapacity)
+	}
+	sort.Slice(capacities, func(i, j int) bool { return capacities[i].StorageClass < capacities[j].StorageClass })
+
+	return capacities, nil
+}
+
+// GetBackendCapacity returns the space and volumes in each of a backend's pools.  The space is
+// reported live by the storage system, if the backend's driver can report it.
+func (o *TridentOrchestrator) GetBackendCapacity(backendName string) (capacity *storage.BackendCapacity, err error) {
+	if o.bootstrapError != nil {
+		return nil, o.bootstrapError
+	}
+
+	defer recordTiming("backend_capacity_get", &err)()
+
+	o.mutex.Lock()
+	backendUUID, err := o.getBackendUUIDByBackendName(backendName)
+	if err != nil {
+		o.mutex.Unlock()
+		return nil, err
+	}
+	backend, found := o.backends[backendUUID]
+	if !found {
+		o.mutex.Unlock()
+		return nil, utils.NotFoundError(fmt.Sprintf("backend %v was not found", backendName))
+	}
+
+	pools := make(map[string]*storage.PoolCapacity)
+	for poolName := range backend.Storage {
+		pools[poolName] = &storage.PoolCapacity{Name: poolName}
+	}
+	for _, volume := range o.volumes {
+		if volume.BackendUUID != backendUUID {
+			continue
+		}
+		// Volumes may remain in pools that are no longer configured
+		pool, ok := pools[volume.Pool]
+		if !ok {
+			pool = &storage.PoolCapacity{Name: volume.Pool}
+			pools[volume.Pool] = pool
+		}
+		pool.Volumes++
+		if size, err := strconv.ParseInt(volume.Config.Size, 10, 64); err == nil {
+			pool.ProvisionedBytes += size
+		}
+	}
+	o.mutex.Unlock()
+
+	capacity = &storage.BackendCapacity{
+		Backend: backend.Name,
+		Pools:   make([]storage.PoolCapacity, 0, len(pools)),
+	}
+
+	// Query the storage system without holding the orchestrator lock
+	var storageCapacity *storage.StorageCapacity
+	if backend.CanReportStorageCapacity() {
+		if storageCapacity, err = backend.GetStorageCapacity(); err != nil {
+			return nil, fmt.Errorf("could not get capacity of backend %s; %v", backend.Name, err)
+		}
+		capacity.CapacityReported = true
+	}
+
+	for _, pool := range pools {
+		if storageCapacity != nil {
+			pool.Sources = append([]string{}, storageCapacity.PoolSources[pool.Name]...)
+			sort.Strings(pool.Sources)
+
+			sizeKnown := len(pool.Sources) > 0
+			for _, source := range pool.Sources {
+				pool.AvailableBytes += storageCapacity.SourceFreeBytes[source]
+				size, ok := storageCapacity.SourceTotalBytes[source]
+				sizeKnown = sizeKnown && ok
+				pool.TotalBytes += size
+			}
+			if !sizeKnown {
+				pool.TotalBytes = 0
+			} else if pool.TotalBytes > pool.AvailableBytes {
+				pool.UsedBytes = pool.TotalBytes - pool.AvailableBytes
+			}
+		}
+		capacity.Pools = append(capacity.Pools, *pool)
+	}
+	sort.Slice(capacity.Pools, func(i, j int) bool { return capacity.Pools[i].Name < capacity.Pools[j].Name })
+
+	return capacity, nil
+}
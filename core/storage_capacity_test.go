@@ -0,0 +1,94 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netapp/trident/config"
+	sa "github.com/netapp/trident/storage_attribute"
+	storageclass "github.com/netapp/trident/storage_class"
+	tu "github.com/netapp/trident/storage_drivers/fake/test_utils"
+)
+
+func TestListStorageClassCapacities(t *testing.T) {
+	const (
+		scName      = "capacitySC"
+		emptySCName = "noPoolsSC"
+	)
+
+	orchestrator := getOrchestrator()
+	defer cleanup(t, orchestrator)
+	addBackend(t, orchestrator, "capacityBackend1", config.File)
+	addBackend(t, orchestrator, "capacityBackend2", config.File)
+
+	for _, scConfig := range []*storageclass.Config{
+		{Name: scName, Attributes: map[string]sa.Request{sa.TestingAttribute: sa.NewBoolRequest(true)}},
+		{Name: emptySCName, Attributes: map[string]sa.Request{sa.Media: sa.NewStringRequest("ssd")}},
+	} {
+		if _, err := orchestrator.AddStorageClass(scConfig); err != nil {
+			t.Fatal("Unable to add storage class: ", err)
+		}
+	}
+
+	// Each backend starts with a 100 GiB pool holding two 1 GB volumes
+	poolFree := int64(100*1024*1024*1024 - 2*1000000000)
+
+	capacities, err := orchestrator.ListStorageClassCapacities()
+	assert.NoError(t, err)
+	if assert.Len(t, capacities, 2) {
+		assert.Equal(t, scName, capacities[0].StorageClass)
+		assert.Equal(t, 2*poolFree, capacities[0].CapacityBytes)
+		assert.Equal(t, poolFree, capacities[0].MaximumVolumeSizeBytes)
+
+		assert.Equal(t, emptySCName, capacities[1].StorageClass)
+		assert.Zero(t, capacities[1].CapacityBytes)
+		assert.Zero(t, capacities[1].MaximumVolumeSizeBytes)
+	}
+
+	// New volumes reduce the capacity
+	if _, err = orchestrator.AddVolume(ctx(), tu.GenerateVolumeConfig("capacityVolume", 10, scName, config.File)); err != nil {
+		t.Fatal("Unable to create volume: ", err)
+	}
+	capacities, err = orchestrator.ListStorageClassCapacities()
+	assert.NoError(t, err)
+	if assert.Len(t, capacities, 2) {
+		assert.Equal(t, 2*poolFree-10*1024*1024*1024, capacities[0].CapacityBytes)
+		assert.Equal(t, poolFree, capacities[0].MaximumVolumeSizeBytes)
+	}
+}
+
+func TestGetBackendCapacity(t *testing.T) {
+	const (
+		backendName = "capacityBackend"
+		scName      = "capacitySC"
+	)
+
+	orchestrator := getOrchestrator()
+	defer cleanup(t, orchestrator)
+	addBackendStorageClass(t, orchestrator, backendName, scName, config.File)
+
+	if _, err := orchestrator.AddVolume(ctx(), tu.GenerateVolumeConfig("capacityVolume", 10, scName, config.File)); err != nil {
+		t.Fatal("Unable to create volume: ", err)
+	}
+
+	capacity, err := orchestrator.GetBackendCapacity(backendName)
+	assert.NoError(t, err)
+	assert.Equal(t, backendName, capacity.Backend)
+	assert.True(t, capacity.CapacityReported)
+	if assert.Len(t, capacity.Pools, 1) {
+		pool := capacity.Pools[0]
+		assert.Equal(t, "primary", pool.Name)
+		assert.Equal(t, []string{"primary"}, pool.Sources)
+		assert.Equal(t, int64(100*1024*1024*1024), pool.TotalBytes)
+		assert.Equal(t, int64(2*1000000000+10*1024*1024*1024), pool.UsedBytes)
+		assert.Equal(t, pool.TotalBytes-pool.UsedBytes, pool.AvailableBytes)
+		assert.Equal(t, 1, pool.Volumes)
+		assert.Equal(t, int64(10*1024*1024*1024), pool.ProvisionedBytes)
+	}
+
+	_, err = orchestrator.GetBackendCapacity("unknownBackend")
+	assert.Error(t, err)
+}
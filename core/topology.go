@@ -0,0 +1,107 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+
+	"github.com/netapp/trident/storage"
+	storageclass "github.com/netapp/trident/storage_class"
+)
+
+/////////////////////////////////////////////////////////////////////////////
+//
+// This file contains the topology-aware placement of new volumes.  A
+// container orchestrator may require a volume to be accessible from some
+// topology segments, such as the zone a pod is scheduled in, and may prefer
+// some of them.  Pools declare the segments their volumes are reachable
+// from, so volumes are only placed in pools reachable from a requisite
+// segment, and pools reachable from a preferred segment are tried first.
+// A storage class may also restrict its volumes to some segments, in which
+// case pools outside of them are never used for the class's volumes.
+//
+/////////////////////////////////////////////////////////////////////////////
+
+// filterPoolsByTopology removes the pools that can't provision a volume accessible from its requisite
+// topologies, and orders each backend's remaining pools so the ones in its preferred topologies come
+// first.  Backends left without pools are removed, and the reason is recorded in backendErrors.
+func filterPoolsByTopology(
+	poolsByBackend map[string]*storageclass.BackendPoolInfo, volumeConfig *storage.VolumeConfig,
+	backendErrors map[string]string,
+) {
+	for backendName, backendPoolInfo := range poolsByBackend {
+
+		pools := make([]*storage.Pool, 0, len(backendPoolInfo.Pools))
+		for _, pool := range backendPoolInfo.Pools {
+			if pool.SupportsTopologies(volumeConfig.RequisiteTopologies) {
+				pools = append(pools, pool)
+			}
+		}
+		if len(pools) == 0 {
+			backendErrors[backendName] = fmt.Sprintf("backend has no pools accessible from topologies %v",
+				volumeConfig.RequisiteTopologies)
+			delete(poolsByBackend, backendName)
+			continue
+		}
+
+		// Keep the shuffled order among pools that are equally preferred
+		sort.SliceStable(pools, func(i, j int) bool {
+			return pools[i].PrefersTopologies(volumeConfig.PreferredTopologies) &&
+				!pools[j].PrefersTopologies(volumeConfig.PreferredTopologies)
+		})
+		backendPoolInfo.Pools = pools
+	}
+}
+
+// filterPoolsByStorageClassTopology removes the pools whose volumes aren't accessible from the topologies
+// a storage class allows.  Backends left without pools are removed, and the reason is recorded in
+// backendErrors.
+func filterPoolsByStorageClassTopology(
+	poolsByBackend map[string]*storageclass.BackendPoolInfo, sc *storageclass.StorageClass,
+	backendErrors map[string]string,
+) {
+	allowedTopologies := sc.GetAllowedTopologies()
+	if len(allowedTopologies) == 0 {
+		return
+	}
+
+	for backendName, backendPoolInfo := range poolsByBackend {
+
+		pools := make([]*storage.Pool, 0, len(backendPoolInfo.Pools))
+		for _, pool := range backendPoolInfo.Pools {
+			if pool.WithinTopologies(allowedTopologies) {
+				pools = append(pools, pool)
+			}
+		}
+		if len(pools) == 0 {
+			backendErrors[backendName] = fmt.Sprintf("backend has no pools in topologies %v allowed by "+
+				"storage class %s", allowedTopologies, sc.GetName())
+			delete(poolsByBackend, backendName)
+			continue
+		}
+		backendPoolInfo.Pools = pools
+	}
+}
+
+// chooseBackend picks a random backend to try creating a volume on, choosing among those whose next
+// pool is in one of the volume's preferred topologies if there are any.
+func chooseBackend(
+	poolsByBackend map[string]*storageclass.BackendPoolInfo, preferredTopologies []map[string]string,
+) string {
+
+	backendNames := make([]string, 0)
+	preferredBackendNames := make([]string, 0)
+	for backendName, backendPoolInfo := range poolsByBackend {
+		backendNames = append(backendNames, backendName)
+		if len(backendPoolInfo.Pools) > 0 && backendPoolInfo.Pools[0].PrefersTopologies(preferredTopologies) {
+			preferredBackendNames = append(preferredBackendNames, backendName)
+		}
+	}
+
+	if len(preferredBackendNames) > 0 {
+		return preferredBackendNames[rand.Intn(len(preferredBackendNames))]
+	}
+	return backendNames[rand.Intn(len(backendNames))]
+}
@@ -0,0 +1,149 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netapp/trident/config"
+	sa "github.com/netapp/trident/storage_attribute"
+	storageclass "github.com/netapp/trident/storage_class"
+	tu "github.com/netapp/trident/storage_drivers/fake/test_utils"
+)
+
+func TestAddVolumeWithTopology(t *testing.T) {
+	const scName = "topologySC"
+
+	orchestrator := getOrchestrator()
+	defer cleanup(t, orchestrator)
+
+	zones := map[string]string{"topologyBackendA": "a", "topologyBackendB": "b"}
+	backendUUIDs := make(map[string]string)
+	for backendName, zone := range zones {
+		addBackend(t, orchestrator, backendName, config.File)
+		backend, err := orchestrator.getBackendByBackendName(backendName)
+		if err != nil {
+			t.Fatal("Unable to get backend: ", err)
+		}
+		backend.Storage["primary"].SupportedTopologies = []map[string]string{{"topology.kubernetes.io/zone": zone}}
+		backendUUIDs[zone] = backend.BackendUUID
+	}
+	scConfig := &storageclass.Config{
+		Name:       scName,
+		Attributes: map[string]sa.Request{sa.TestingAttribute: sa.NewBoolRequest(true)},
+	}
+	if _, err := orchestrator.AddStorageClass(scConfig); err != nil {
+		t.Fatal("Unable to add storage class: ", err)
+	}
+
+	zoneA := map[string]string{"topology.kubernetes.io/region": "r1", "topology.kubernetes.io/zone": "a"}
+	zoneB := map[string]string{"topology.kubernetes.io/region": "r1", "topology.kubernetes.io/zone": "b"}
+	zoneC := map[string]string{"topology.kubernetes.io/region": "r1", "topology.kubernetes.io/zone": "c"}
+
+	// A volume is only placed in a pool reachable from its requisite topologies
+	volumeConfig := tu.GenerateVolumeConfig("zoneBVolume", 1, scName, config.File)
+	volumeConfig.RequisiteTopologies = []map[string]string{zoneB}
+	volume, err := orchestrator.AddVolume(ctx(), volumeConfig)
+	if assert.NoError(t, err) {
+		assert.Equal(t, backendUUIDs["b"], volume.BackendUUID)
+		assert.Equal(t, []map[string]string{{"topology.kubernetes.io/zone": "b"}}, volume.Config.AllowedTopologies)
+	}
+
+	// Pools in a preferred topology are tried first
+	for i, name := range []string{"preferredVolume1", "preferredVolume2", "preferredVolume3"} {
+		volumeConfig = tu.GenerateVolumeConfig(name, 1, scName, config.File)
+		volumeConfig.RequisiteTopologies = []map[string]string{zoneA, zoneB}
+		volumeConfig.PreferredTopologies = []map[string]string{zoneA}
+		volume, err = orchestrator.AddVolume(ctx(), volumeConfig)
+		if assert.NoError(t, err, "volume %d", i) {
+			assert.Equal(t, backendUUIDs["a"], volume.BackendUUID)
+		}
+	}
+
+	// No pool is reachable from zone c
+	volumeConfig = tu.GenerateVolumeConfig("zoneCVolume", 1, scName, config.File)
+	volumeConfig.RequisiteTopologies = []map[string]string{zoneC}
+	_, err = orchestrator.AddVolume(ctx(), volumeConfig)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "no pools accessible from topologies")
+	}
+
+	// A clone can only be accessible from where its source is
+	cloneConfig := tu.GenerateVolumeConfig("zoneAClone", 1, scName, config.File)
+	cloneConfig.CloneSourceVolume = "zoneBVolume"
+	cloneConfig.RequisiteTopologies = []map[string]string{zoneA}
+	_, err = orchestrator.CloneVolume(ctx(), cloneConfig)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "not accessible from the requested topologies")
+	}
+
+	cloneConfig = tu.GenerateVolumeConfig("zoneBClone", 1, scName, config.File)
+	cloneConfig.CloneSourceVolume = "zoneBVolume"
+	cloneConfig.RequisiteTopologies = []map[string]string{zoneB}
+	clone, err := orchestrator.CloneVolume(ctx(), cloneConfig)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []map[string]string{{"topology.kubernetes.io/zone": "b"}}, clone.Config.AllowedTopologies)
+	}
+}
+
+func TestAddVolumeWithStorageClassTopology(t *testing.T) {
+	const (
+		scName      = "allowedTopologySC"
+		zoneBSCName = "zoneBSC"
+	)
+
+	orchestrator := getOrchestrator()
+	defer cleanup(t, orchestrator)
+
+	zones := map[string]string{"allowedBackendA": "a", "allowedBackendB": "b"}
+	backendUUIDs := make(map[string]string)
+	for backendName, zone := range zones {
+		addBackend(t, orchestrator, backendName, config.File)
+		backend, err := orchestrator.getBackendByBackendName(backendName)
+		if err != nil {
+			t.Fatal("Unable to get backend: ", err)
+		}
+		backend.Storage["primary"].SupportedTopologies = []map[string]string{
+			{"topology.kubernetes.io/region": "r1", "topology.kubernetes.io/zone": zone},
+		}
+		backendUUIDs[zone] = backend.BackendUUID
+	}
+	for name, zone := range map[string]string{scName: "a", zoneBSCName: "b"} {
+		scConfig := &storageclass.Config{
+			Name:              name,
+			Attributes:        map[string]sa.Request{sa.TestingAttribute: sa.NewBoolRequest(true)},
+			AllowedTopologies: []map[string]string{{"topology.kubernetes.io/zone": zone}},
+		}
+		if _, err := orchestrator.AddStorageClass(scConfig); err != nil {
+			t.Fatal("Unable to add storage class: ", err)
+		}
+	}
+
+	// Without requisite topologies, a volume is still only placed in the storage class's topologies
+	for _, name := range []string{"allowedVolume1", "allowedVolume2", "allowedVolume3"} {
+		volume, err := orchestrator.AddVolume(ctx(), tu.GenerateVolumeConfig(name, 1, scName, config.File))
+		if assert.NoError(t, err) {
+			assert.Equal(t, backendUUIDs["a"], volume.BackendUUID)
+		}
+	}
+
+	// The storage class's restriction applies along with the volume's own
+	volumeConfig := tu.GenerateVolumeConfig("disallowedVolume", 1, scName, config.File)
+	volumeConfig.RequisiteTopologies = []map[string]string{
+		{"topology.kubernetes.io/region": "r1", "topology.kubernetes.io/zone": "b"},
+	}
+	_, err := orchestrator.AddVolume(ctx(), volumeConfig)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "no available backends")
+	}
+
+	// A clone can't be placed outside of its storage class's topologies
+	cloneConfig := tu.GenerateVolumeConfig("disallowedClone", 1, zoneBSCName, config.File)
+	cloneConfig.CloneSourceVolume = "allowedVolume1"
+	_, err = orchestrator.CloneVolume(ctx(), cloneConfig)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "not accessible from the topologies allowed by storage class")
+	}
+}
@@ -3,6 +3,7 @@
 package core
 
 import (
+	"context"
 	"time"
 
 	log "github.com/sirupsen/logrus"
@@ -134,7 +135,10 @@ func (o *TridentOrchestrator) reapLongRunningTransaction(txn *storage.VolumeTran
 
 		// Delete the volume.  This should be safe since the transaction was left around and Trident doesn't
 		// know anything about the volume.
-		if err := backend.RemoveVolume(&txn.VolumeCreatingConfig.VolumeConfig); err != nil {
+		ctx, cancel := o.operationContext(context.Background(), OperationDeleteVolume)
+		err := backend.RemoveVolume(ctx, &txn.VolumeCreatingConfig.VolumeConfig)
+		cancel()
+		if err != nil {
 
 			log.WithFields(log.Fields{
 				"backendUUID": txn.VolumeCreatingConfig.BackendUUID,
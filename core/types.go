@@ -3,8 +3,11 @@
 package core
 
 import (
+	"context"
+
 	"github.com/netapp/trident/config"
 	"github.com/netapp/trident/frontend"
+	persistentstore "github.com/netapp/trident/persistent_store"
 	"github.com/netapp/trident/storage"
 	storageclass "github.com/netapp/trident/storage_class"
 	"github.com/netapp/trident/utils"
@@ -23,32 +26,99 @@ type Orchestrator interface {
 	GetBackendByBackendUUID(backendUUID string) (*storage.BackendExternal, error)
 	ListBackends() ([]*storage.BackendExternal, error)
 	UpdateBackend(backendName, configJSON string) (storageBackendExternal *storage.BackendExternal, err error)
+	PreviewBackendUpdate(backendName, configJSON string) (*storage.BackendUpdatePreview, error)
 	UpdateBackendByBackendUUID(backendName, configJSON, backendUUID string) (storageBackendExternal *storage.BackendExternal, err error)
 	UpdateBackendState(backendName, backendState string) (storageBackendExternal *storage.BackendExternal, err error)
+	UpdateBackendTracing(backendName string, debugTraceFlags map[string]bool) (
+		storageBackendExternal *storage.BackendExternal, err error)
+	ReencryptBackendSecrets() ([]string, error)
+	ForceDeleteBackend(backendName string) error
 
-	AddVolume(volumeConfig *storage.VolumeConfig) (*storage.VolumeExternal, error)
+	AddVolume(ctx context.Context, volumeConfig *storage.VolumeConfig) (*storage.VolumeExternal, error)
 	AttachVolume(volumeName, mountpoint string, publishInfo *utils.VolumePublishInfo) error
-	CloneVolume(volumeConfig *storage.VolumeConfig) (*storage.VolumeExternal, error)
+	CloneVolume(ctx context.Context, volumeConfig *storage.VolumeConfig) (*storage.VolumeExternal, error)
 	DetachVolume(volumeName, mountpoint string) error
-	DeleteVolume(volume string) error
+	DeleteVolume(ctx context.Context, volume string) error
+	ForceDeleteVolume(volumeName string) error
+	UnmanageVolume(volumeName, storageName string) (string, error)
 	GetVolume(volume string) (*storage.VolumeExternal, error)
 	GetVolumeExternal(volumeName string, backendName string) (*storage.VolumeExternal, error)
 	GetVolumeType(vol *storage.VolumeExternal) (config.VolumeType, error)
-	LegacyImportVolume(volumeConfig *storage.VolumeConfig, backendName string, notManaged bool, createPVandPVC VolumeCallback) (*storage.VolumeExternal, error)
-	ImportVolume(volumeConfig *storage.VolumeConfig) (*storage.VolumeExternal, error)
+	LegacyImportVolume(ctx context.Context, volumeConfig *storage.VolumeConfig, backendName string, notManaged bool, createPVandPVC VolumeCallback) (*storage.VolumeExternal, error)
+	ImportVolume(ctx context.Context, volumeConfig *storage.VolumeConfig) (*storage.VolumeExternal, error)
+	ImportVolumeDryRun(volumeConfig *storage.VolumeConfig) (*storage.ImportVolumeDryRunResult, error)
 	ListVolumes() ([]*storage.VolumeExternal, error)
 	ListVolumesByPlugin(pluginName string) ([]*storage.VolumeExternal, error)
-	PublishVolume(volumeName string, publishInfo *utils.VolumePublishInfo) error
-	ResizeVolume(volumeName, newSize string) error
+	PublishVolume(ctx context.Context, volumeName string, publishInfo *utils.VolumePublishInfo) error
+	UnpublishVolume(ctx context.Context, volumeName, nodeName string) error
+	ResizeVolume(ctx context.Context, volumeName, newSize string) error
 	SetVolumeState(volumeName string, state storage.VolumeState) error
 
-	CreateSnapshot(snapshotConfig *storage.SnapshotConfig) (*storage.SnapshotExternal, error)
+	MigrateVolume(request *storage.VolumeMigrationRequest) (*storage.VolumeMigrationExternal, error)
+	PlanVolumeMigration(request *storage.VolumeMigrationRequest) (*storage.VolumeMigrationPlan, error)
+	GetVolumeMigration(volumeName string) (*storage.VolumeMigrationExternal, error)
+	ListVolumeMigrations() ([]*storage.VolumeMigrationExternal, error)
+
+	UpdateVolumeUsage(usage *storage.VolumeUsage) error
+	GetVolumeUsage(volumeName string) (*storage.VolumeUsage, error)
+	ListVolumeUsage() ([]*storage.VolumeUsage, error)
+	UpdateVolumeCondition(condition *storage.VolumeCondition) error
+	GetVolumeCondition(volumeName string) (*storage.VolumeCondition, error)
+	ListVolumeConditions() ([]*storage.VolumeCondition, error)
+	ListStorageClassCapacities() ([]*storage.StorageClassCapacity, error)
+	GetBackendCapacity(backendName string) (*storage.BackendCapacity, error)
+
+	DetectDrift() (*storage.DriftReport, error)
+	GetDriftReport() (*storage.DriftReport, error)
+
+	CollectGarbage() (*storage.GarbageReport, error)
+	GetGarbageReport() (*storage.GarbageReport, error)
+
+	CheckUpgradeReadiness() (*storage.PreflightReport, error)
+
+	GetHealth() (*storage.HealthReport, error)
+
+	GetVolumeDeletion(volumeName string) (*storage.VolumeDeletion, error)
+	ListVolumeDeletions() ([]*storage.VolumeDeletion, error)
+
+	AddCloneGrant(grant *storage.CloneGrant) error
+	DeleteCloneGrant(namespace, name string) error
+	GetCloneGrant(namespace, name string) (*storage.CloneGrant, error)
+	ListCloneGrants() ([]*storage.CloneGrant, error)
+
+	AddQuota(quota *storage.Quota) error
+	DeleteQuota(namespace, name string) error
+	GetQuota(namespace, name string) (*storage.QuotaExternal, error)
+	ListQuotas() ([]*storage.QuotaExternal, error)
+
+	ExportState() (*persistentstore.StateBundle, error)
+	ImportState(bundle *persistentstore.StateBundle) (*persistentstore.StateImportResult, error)
+	VerifyState(repair storage.StateRepair) (*storage.StateVerification, error)
+
+	AddVolumeGroup(groupConfig *storage.VolumeGroupConfig) (*storage.VolumeGroupExternal, error)
+	UpdateVolumeGroup(groupConfig *storage.VolumeGroupConfig) (*storage.VolumeGroupExternal, error)
+	DeleteVolumeGroup(groupName string) error
+	GetVolumeGroup(groupName string) (*storage.VolumeGroupExternal, error)
+	ListVolumeGroups() ([]*storage.VolumeGroupExternal, error)
+	CreateVolumeGroupSnapshot(
+		ctx context.Context, groupName string, request *storage.VolumeGroupSnapshotRequest,
+	) (*storage.VolumeGroupSnapshot, error)
+	CreateGroupSnapshot(
+		ctx context.Context, snapConfigs []*storage.SnapshotConfig,
+	) (*storage.VolumeGroupSnapshot, error)
+	CloneVolumeGroup(
+		ctx context.Context, groupName string, request *storage.VolumeGroupCloneRequest,
+	) (*storage.VolumeGroupExternal, error)
+
+	CreateSnapshot(ctx context.Context, snapshotConfig *storage.SnapshotConfig) (*storage.SnapshotExternal, error)
 	GetSnapshot(volumeName, snapshotName string) (*storage.SnapshotExternal, error)
 	ListSnapshots() ([]*storage.SnapshotExternal, error)
 	ListSnapshotsByName(snapshotName string) ([]*storage.SnapshotExternal, error)
 	ListSnapshotsForVolume(volumeName string) ([]*storage.SnapshotExternal, error)
 	ReadSnapshotsForVolume(volumeName string) ([]*storage.SnapshotExternal, error)
-	DeleteSnapshot(volumeName, snapshotName string) error
+	DeleteSnapshot(ctx context.Context, volumeName, snapshotName string) error
+	RestoreSnapshot(ctx context.Context, volumeName, snapshotName string, force bool) error
+	CheckSnapshotRestore(volumeName, snapshotName string) (*storage.PreflightReport, error)
 
 	GetDriverTypeForVolume(vol *storage.VolumeExternal) (string, error)
 	ReloadVolumes() error
@@ -62,6 +132,7 @@ type Orchestrator interface {
 	GetNode(nName string) (*utils.Node, error)
 	ListNodes() ([]*utils.Node, error)
 	DeleteNode(nName string) error
+	ForceDetachNode(nodeName string) error
 
 	AddVolumeTransaction(volTxn *storage.VolumeTransaction) error
 	GetVolumeTransaction(volTxn *storage.VolumeTransaction) (*storage.VolumeTransaction, error)
@@ -0,0 +1,211 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/netapp/trident/config"
+	persistentstore "github.com/netapp/trident/persistent_store"
+	"github.com/netapp/trident/storage"
+	drivers "github.com/netapp/trident/storage_drivers"
+)
+
+/////////////////////////////////////////////////////////////////////////////
+//
+// This file contains the upgrade preflight check, which looks for anything
+// in Trident's state that should be resolved before Trident is upgraded.
+// A failed check means the upgrade should wait, as the new version could
+// otherwise act on an operation that the old version left half done.  The
+// checks of the cluster itself, such as CRD and node DaemonSet health, are
+// made by tridentctl, which can see the cluster as a whole.
+//
+/////////////////////////////////////////////////////////////////////////////
+
+// CheckUpgradeReadiness returns a report of whether Trident's state is ready for an upgrade.
+func (o *TridentOrchestrator) CheckUpgradeReadiness() (report *storage.PreflightReport, err error) {
+	if o.bootstrapError != nil {
+		return nil, o.bootstrapError
+	}
+
+	defer recordTiming("upgrade_preflight", &err)()
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	report = storage.NewPreflightReport()
+
+	check, err := o.checkPersistentStateVersion()
+	if err != nil {
+		return nil, err
+	}
+	report.AddCheck(check)
+
+	check, err = o.checkTransactions()
+	if err != nil {
+		return nil, err
+	}
+	report.AddCheck(check)
+
+	report.AddCheck(o.checkBackendConfigs())
+	report.AddCheck(o.checkBackendStates())
+	report.AddCheck(o.checkVolumeStates())
+
+	return report, nil
+}
+
+// checkPersistentStateVersion fails if the persistent store wasn't fully migrated to the current
+// store and API versions.
+func (o *TridentOrchestrator) checkPersistentStateVersion() (*storage.PreflightCheck, error) {
+
+	check := &storage.PreflightCheck{
+		Name:        "persistentState",
+		Remediation: "Restart the Trident controller so it completes the migration of its persistent state.",
+	}
+
+	version, err := o.storeClient.GetVersion()
+	if err != nil {
+		return nil, fmt.Errorf("could not read the persistent state version; %v", err)
+	}
+
+	if version.PersistentStoreVersion != string(o.storeClient.GetType()) {
+		check.Findings = append(check.Findings, fmt.Sprintf("The persistent store version is %s, not %s.",
+			version.PersistentStoreVersion, o.storeClient.GetType()))
+	}
+	if version.OrchestratorAPIVersion != config.OrchestratorAPIVersion {
+		check.Findings = append(check.Findings, fmt.Sprintf("The Trident API version is %s, not %s.",
+			version.OrchestratorAPIVersion, config.OrchestratorAPIVersion))
+	}
+
+	if len(check.Findings) == 0 {
+		check.Remediation = ""
+	}
+	return check, nil
+}
+
+// checkTransactions fails if a synchronous operation's transaction remains, as the operation is
+// either in progress or was interrupted.  Long-running operations resume after a restart, so
+// their transactions only warrant a warning.
+func (o *TridentOrchestrator) checkTransactions() (*storage.PreflightCheck, error) {
+
+	check := &storage.PreflightCheck{Name: "transactions"}
+
+	txns, err := o.storeClient.GetVolumeTransactions()
+	if err != nil && !persistentstore.MatchKeyNotFoundErr(err) {
+		return nil, fmt.Errorf("could not read the volume transactions; %v", err)
+	}
+
+	longRunning := make([]string, 0)
+	for _, txn := range txns {
+		switch {
+		case txn.Op == storage.VolumeCreating || txn.Op == storage.MigrateVolume:
+			longRunning = append(longRunning, fmt.Sprintf("The %s operation on %s is in progress.",
+				txn.Op, txn.Name()))
+		case txn.Op == storage.DeleteVolume && txn.VolumeDeletion != nil:
+			longRunning = append(longRunning, fmt.Sprintf(
+				"The deletion of volume %s is %s after %d attempts; last error: %s",
+				txn.Name(), txn.VolumeDeletion.State, txn.VolumeDeletion.Attempts, txn.VolumeDeletion.LastError))
+		default:
+			check.Findings = append(check.Findings, fmt.Sprintf("The %s operation on %s is in progress "+
+				"or was interrupted.", txn.Op, txn.Name()))
+		}
+	}
+	sort.Strings(check.Findings)
+	sort.Strings(longRunning)
+
+	if len(check.Findings) > 0 {
+		check.Status = storage.PreflightFailed
+		check.Remediation = "Wait for the operations to finish and check again.  If the transactions " +
+			"remain, restart the Trident controller, which rolls back interrupted operations as it starts."
+		check.Findings = append(check.Findings, longRunning...)
+	} else if len(longRunning) > 0 {
+		check.Status = storage.PreflightWarning
+		check.Remediation = "These operations continue after the upgrade, but waiting for them to finish, " +
+			"or resolving the failed volume deletions, keeps the upgrade simple."
+		check.Findings = longRunning
+	}
+	return check, nil
+}
+
+// checkBackendConfigs warns about backends whose configs use deprecated settings.
+func (o *TridentOrchestrator) checkBackendConfigs() *storage.PreflightCheck {
+
+	check := &storage.PreflightCheck{Name: "backendConfigs", Status: storage.PreflightPassed}
+
+	for _, backend := range o.backends {
+		configJSON, err := backend.ConstructPersistent().MarshalConfig()
+		if err != nil {
+			check.Findings = append(check.Findings, fmt.Sprintf("The config of backend %s could not be read; %v",
+				backend.Name, err))
+			continue
+		}
+		deprecated, err := drivers.FindDeprecatedSettings(configJSON)
+		if err != nil {
+			check.Findings = append(check.Findings, fmt.Sprintf("The config of backend %s could not be read; %v",
+				backend.Name, err))
+			continue
+		}
+		for setting, guidance := range deprecated {
+			check.Findings = append(check.Findings, fmt.Sprintf("Backend %s sets the deprecated setting %s.  %s",
+				backend.Name, setting, guidance))
+		}
+	}
+	sort.Strings(check.Findings)
+
+	if len(check.Findings) > 0 {
+		check.Status = storage.PreflightWarning
+		check.Remediation = "Update each backend's config with 'tridentctl update backend', as a later " +
+			"release may no longer accept the deprecated settings."
+	}
+	return check
+}
+
+// checkBackendStates warns about backends that can't serve their volumes, as the volumes can't be
+// managed until the backends recover.  Cordoned and draining backends still serve their volumes.
+func (o *TridentOrchestrator) checkBackendStates() *storage.PreflightCheck {
+
+	check := &storage.PreflightCheck{Name: "backends", Status: storage.PreflightPassed}
+
+	for _, backend := range o.backends {
+		if !backend.State.IsServing() {
+			check.Findings = append(check.Findings, fmt.Sprintf("Backend %s is %s.", backend.Name, backend.State))
+		}
+	}
+	sort.Strings(check.Findings)
+
+	if len(check.Findings) > 0 {
+		check.Status = storage.PreflightWarning
+		check.Remediation = "Bring the backends online, or delete the backends that are no longer needed, " +
+			"with 'tridentctl update backend' or 'tridentctl delete backend'."
+	}
+	return check
+}
+
+// checkVolumeStates warns about volumes and snapshots that aren't online.  A deleting volume is
+// only waiting for its snapshots to be deleted, so it isn't reported.
+func (o *TridentOrchestrator) checkVolumeStates() *storage.PreflightCheck {
+
+	check := &storage.PreflightCheck{Name: "volumes", Status: storage.PreflightPassed}
+
+	for _, volume := range o.volumes {
+		if !volume.State.IsOnline() && !volume.State.IsDeleting() {
+			check.Findings = append(check.Findings, fmt.Sprintf("Volume %s is %s.", volume.Config.Name,
+				volume.State))
+		}
+	}
+	for _, snapshot := range o.snapshots {
+		if !snapshot.State.IsOnline() {
+			check.Findings = append(check.Findings, fmt.Sprintf("Snapshot %s is %s.", snapshot.ID(),
+				snapshot.State))
+		}
+	}
+	sort.Strings(check.Findings)
+
+	if len(check.Findings) > 0 {
+		check.Status = storage.PreflightWarning
+		check.Remediation = "Restore the missing backends, or delete the volumes and snapshots that are no " +
+			"longer needed."
+	}
+	return check
+}
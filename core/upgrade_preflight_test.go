@@ -0,0 +1,100 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/storage"
+)
+
+func getPreflightCheck(t *testing.T, report *storage.PreflightReport, name string) *storage.PreflightCheck {
+	for _, check := range report.Checks {
+		if check.Name == name {
+			return check
+		}
+	}
+	t.Fatalf("Preflight check %s not found.", name)
+	return nil
+}
+
+func TestCheckUpgradeReadiness(t *testing.T) {
+	o := newStateOrchestrator(t)
+
+	report, err := o.CheckUpgradeReadiness()
+	if assert.NoError(t, err) {
+		assert.True(t, report.Ready)
+		for _, check := range report.Checks {
+			assert.Equal(t, storage.PreflightPassed, check.Status, check.Name)
+		}
+	}
+
+	// A long-running operation only warrants a warning
+	creatingTxn := &storage.VolumeTransaction{
+		Op:                   storage.VolumeCreating,
+		VolumeCreatingConfig: &storage.VolumeCreatingConfig{VolumeConfig: storage.VolumeConfig{Name: "creating"}},
+	}
+	if err = o.storeClient.AddVolumeTransaction(creatingTxn); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err = o.CheckUpgradeReadiness()
+	if assert.NoError(t, err) {
+		assert.True(t, report.Ready)
+		check := getPreflightCheck(t, report, "transactions")
+		assert.Equal(t, storage.PreflightWarning, check.Status)
+		assert.Len(t, check.Findings, 1)
+	}
+
+	// An interrupted synchronous operation blocks the upgrade
+	addTxn := &storage.VolumeTransaction{Op: storage.AddVolume, Config: &storage.VolumeConfig{Name: "interrupted"}}
+	if err = o.storeClient.AddVolumeTransaction(addTxn); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err = o.CheckUpgradeReadiness()
+	if assert.NoError(t, err) {
+		assert.False(t, report.Ready)
+		check := getPreflightCheck(t, report, "transactions")
+		assert.Equal(t, storage.PreflightFailed, check.Status)
+		assert.Len(t, check.Findings, 2)
+		assert.Contains(t, check.Findings[0], "interrupted")
+		assert.NotEmpty(t, check.Remediation)
+	}
+}
+
+func TestCheckUpgradeReadinessBackendStates(t *testing.T) {
+	o := newStateOrchestrator(t)
+	addBackend(t, o, "preflightBackend", config.File)
+
+	o.mutex.Lock()
+	for _, backend := range o.backends {
+		backend.State = storage.Offline
+	}
+	o.mutex.Unlock()
+
+	report, err := o.CheckUpgradeReadiness()
+	if assert.NoError(t, err) {
+		assert.True(t, report.Ready)
+		check := getPreflightCheck(t, report, "backends")
+		assert.Equal(t, storage.PreflightWarning, check.Status)
+		assert.Equal(t, []string{"Backend preflightBackend is offline."}, check.Findings)
+	}
+
+	// Cordoned backends still serve their volumes
+	o.mutex.Lock()
+	for _, backend := range o.backends {
+		backend.State = storage.Cordoned
+	}
+	o.mutex.Unlock()
+
+	report, err = o.CheckUpgradeReadiness()
+	if assert.NoError(t, err) {
+		check := getPreflightCheck(t, report, "backends")
+		assert.Equal(t, storage.PreflightPassed, check.Status)
+		assert.Empty(t, check.Findings)
+	}
+}
@@ -0,0 +1,195 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netapp/trident/storage"
+	"github.com/netapp/trident/utils"
+)
+
+// UpdateVolumeCondition records the condition of a volume as seen by a node where it is mounted, such
+// as a mount path that can no longer be read.
+func (o *TridentOrchestrator) UpdateVolumeCondition(condition *storage.VolumeCondition) (err error) {
+	if o.bootstrapError != nil {
+		return o.bootstrapError
+	}
+
+	defer recordTiming("volume_condition_update", &err)()
+
+	if err = condition.Validate(); err != nil {
+		return err
+	}
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	if _, ok := o.volumes[condition.VolumeName]; !ok {
+		return utils.NotFoundError(fmt.Sprintf("volume %s not found", condition.VolumeName))
+	}
+
+	conditionCopy := *condition
+	conditionCopy.Source = storage.VolumeUsageSourceNode
+	if conditionCopy.UpdatedTime().IsZero() {
+		conditionCopy.Updated = time.Now().UTC().Format(time.RFC3339)
+	}
+	o.setVolumeCondition(&conditionCopy)
+	return nil
+}
+
+// setVolumeCondition stores a condition report by its source.  The caller should hold the orchestrator lock.
+func (o *TridentOrchestrator) setVolumeCondition(condition *storage.VolumeCondition) {
+	if _, ok := o.volumeConditions[condition.VolumeName]; !ok {
+		o.volumeConditions[condition.VolumeName] = make(map[storage.VolumeUsageSource]*storage.VolumeCondition)
+	}
+	o.volumeConditions[condition.VolumeName][condition.Source] = condition
+}
+
+// GetVolumeCondition returns the condition of a volume, combining the reports of its driver and nodes
+// with its most recent usage.
+func (o *TridentOrchestrator) GetVolumeCondition(volumeName string) (condition *storage.VolumeCondition, err error) {
+	if o.bootstrapError != nil {
+		return nil, o.bootstrapError
+	}
+
+	defer recordTiming("volume_condition_get", &err)()
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	if _, ok := o.volumes[volumeName]; !ok {
+		return nil, utils.NotFoundError(fmt.Sprintf("volume %s not found", volumeName))
+	}
+
+	return o.getVolumeCondition(volumeName, time.Now()), nil
+}
+
+// ListVolumeConditions returns the condition of each volume, sorted by volume name.
+func (o *TridentOrchestrator) ListVolumeConditions() (conditions []*storage.VolumeCondition, err error) {
+	if o.bootstrapError != nil {
+		return nil, o.bootstrapError
+	}
+
+	defer recordTiming("volume_condition_list", &err)()
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	now := time.Now()
+	conditions = make([]*storage.VolumeCondition, 0, len(o.volumes))
+	for volumeName := range o.volumes {
+		conditions = append(conditions, o.getVolumeCondition(volumeName, now))
+	}
+	sort.Slice(conditions, func(i, j int) bool { return conditions[i].VolumeName < conditions[j].VolumeName })
+
+	return conditions, nil
+}
+
+// getVolumeCondition combines the problems reported for a volume.  A node's report expires if the node
+// stops reporting, such as when the volume is no longer mounted there.  The caller should hold the
+// orchestrator lock.
+func (o *TridentOrchestrator) getVolumeCondition(volumeName string, now time.Time) *storage.VolumeCondition {
+
+	condition := &storage.VolumeCondition{VolumeName: volumeName}
+	problems := make([]string, 0)
+
+	addReport := func(report *storage.VolumeCondition) {
+		if report.UpdatedTime().After(condition.UpdatedTime()) {
+			condition.Updated = report.Updated
+		}
+		if report.Abnormal {
+			problems = append(problems, report.Message)
+			if report.Node != "" {
+				condition.Node = report.Node
+			}
+		}
+	}
+
+	if report, ok := o.volumeConditions[volumeName][storage.VolumeUsageSourceDriver]; ok {
+		addReport(report)
+	}
+	if report, ok := o.volumeConditions[volumeName][storage.VolumeUsageSourceNode]; ok &&
+		now.Sub(report.UpdatedTime()) < nodeUsageMaxAge {
+		addReport(report)
+	}
+	if usage, ok := o.volumeUsage[volumeName]; ok {
+		if usage.TotalBytes > 0 && usage.AvailableBytes == 0 {
+			addReport(&storage.VolumeCondition{Abnormal: true, Message: "volume is full", Updated: usage.Updated})
+		} else if usage.TotalInodes > 0 && usage.UsedInodes >= usage.TotalInodes {
+			addReport(&storage.VolumeCondition{
+				Abnormal: true, Message: "volume has no free inodes", Updated: usage.Updated,
+			})
+		}
+	}
+
+	condition.Abnormal = len(problems) > 0
+	condition.Message = strings.Join(problems, "; ")
+	return condition
+}
+
+// collectVolumeConditions asks the drivers for the condition of each volume.  As with usage, the storage
+// systems are queried without holding the orchestrator lock.
+func (o *TridentOrchestrator) collectVolumeConditions() {
+
+	if o.bootstrapError != nil {
+		return
+	}
+
+	type conditionRequest struct {
+		volConfig *storage.VolumeConfig
+		backend   *storage.Backend
+	}
+
+	requests := make([]conditionRequest, 0)
+
+	o.mutex.Lock()
+	for volumeName, volume := range o.volumes {
+		if !volume.State.IsOnline() || o.volumeMigrationInProgress(volumeName) {
+			continue
+		}
+		backend, ok := o.backends[volume.BackendUUID]
+		if !ok || !backend.State.IsServing() || !backend.CanReportVolumeCondition() {
+			continue
+		}
+		volConfig := *volume.Config
+		requests = append(requests, conditionRequest{&volConfig, backend})
+	}
+	o.mutex.Unlock()
+
+	collected := make([]*storage.VolumeCondition, 0, len(requests))
+	for _, request := range requests {
+		condition, err := request.backend.GetVolumeCondition(request.volConfig)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"volume":  request.volConfig.Name,
+				"backend": request.backend.Name,
+				"error":   err,
+			}).Warning("Could not get volume condition.")
+			continue
+		}
+		if condition.Abnormal {
+			log.WithFields(log.Fields{
+				"volume":  request.volConfig.Name,
+				"backend": request.backend.Name,
+			}).Warningf("Volume condition is abnormal; %s.", condition.Message)
+		}
+		collected = append(collected, condition)
+	}
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	for _, condition := range collected {
+		// The volume may have been deleted since the driver was queried
+		if _, ok := o.volumes[condition.VolumeName]; !ok {
+			continue
+		}
+		o.setVolumeCondition(condition)
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/storage"
+	sa "github.com/netapp/trident/storage_attribute"
+	storageclass "github.com/netapp/trident/storage_class"
+	fakedriver "github.com/netapp/trident/storage_drivers/fake"
+	tu "github.com/netapp/trident/storage_drivers/fake/test_utils"
+	"github.com/netapp/trident/utils"
+)
+
+func TestVolumeCondition(t *testing.T) {
+	const (
+		backendName = "conditionBackend"
+		scName      = "conditionSC"
+		volumeName  = "conditionVolume"
+	)
+
+	orchestrator := getOrchestrator()
+	defer cleanup(t, orchestrator)
+	addBackend(t, orchestrator, backendName, config.File)
+
+	if _, err := orchestrator.AddStorageClass(&storageclass.Config{
+		Name:       scName,
+		Attributes: map[string]sa.Request{sa.TestingAttribute: sa.NewBoolRequest(true)},
+	}); err != nil {
+		t.Fatal("Unable to add storage class: ", err)
+	}
+	volume, err := orchestrator.AddVolume(ctx(), tu.GenerateVolumeConfig(volumeName, 1, scName, config.File))
+	if err != nil {
+		t.Fatal("Unable to create volume: ", err)
+	}
+
+	_, err = orchestrator.GetVolumeCondition("missing")
+	assert.True(t, utils.IsNotFoundError(err), "expected not found error for unknown volume")
+
+	orchestrator.collectVolumeConditions()
+	condition, err := orchestrator.GetVolumeCondition(volumeName)
+	assert.NoError(t, err)
+	assert.False(t, condition.Abnormal)
+
+	// The driver reports a volume it can't find as abnormal
+	backend, err := orchestrator.getBackendByBackendName(backendName)
+	assert.NoError(t, err)
+	fakeDriver := backend.Driver.(*fakedriver.StorageDriver)
+	fakeVolume := fakeDriver.Volumes[volume.Config.InternalName]
+	delete(fakeDriver.Volumes, volume.Config.InternalName)
+	orchestrator.collectVolumeConditions()
+	condition, err = orchestrator.GetVolumeCondition(volumeName)
+	assert.NoError(t, err)
+	assert.True(t, condition.Abnormal)
+	assert.Contains(t, condition.Message, "not found")
+	fakeDriver.Volumes[volume.Config.InternalName] = fakeVolume
+	orchestrator.collectVolumeConditions()
+
+	// A node reports a broken path until it reads the volume's filesystem again
+	assert.Error(t, orchestrator.UpdateVolumeCondition(&storage.VolumeCondition{VolumeName: volumeName, Abnormal: true}))
+	assert.NoError(t, orchestrator.UpdateVolumeCondition(&storage.VolumeCondition{
+		VolumeName: volumeName, Abnormal: true, Message: "volume path is broken", Node: "node1",
+	}))
+	conditions, err := orchestrator.ListVolumeConditions()
+	assert.NoError(t, err)
+	assert.Len(t, conditions, 1)
+	assert.True(t, conditions[0].Abnormal)
+	assert.Equal(t, "volume path is broken", conditions[0].Message)
+	assert.Equal(t, "node1", conditions[0].Node)
+
+	assert.NoError(t, orchestrator.UpdateVolumeUsage(&storage.VolumeUsage{
+		VolumeName: volumeName, TotalBytes: 1000, UsedBytes: 400, AvailableBytes: 600, Node: "node1",
+	}))
+	condition, err = orchestrator.GetVolumeCondition(volumeName)
+	assert.NoError(t, err)
+	assert.False(t, condition.Abnormal)
+
+	// A node report expires if the node stops reporting
+	assert.NoError(t, orchestrator.UpdateVolumeCondition(&storage.VolumeCondition{
+		VolumeName: volumeName, Abnormal: true, Message: "volume path is broken",
+		Updated: time.Now().Add(-2 * nodeUsageMaxAge).UTC().Format(time.RFC3339),
+	}))
+	condition, err = orchestrator.GetVolumeCondition(volumeName)
+	assert.NoError(t, err)
+	assert.False(t, condition.Abnormal)
+
+	// A full volume is abnormal
+	assert.NoError(t, orchestrator.UpdateVolumeUsage(&storage.VolumeUsage{
+		VolumeName: volumeName, TotalBytes: 1000, UsedBytes: 1000, Node: "node1",
+	}))
+	condition, err = orchestrator.GetVolumeCondition(volumeName)
+	assert.NoError(t, err)
+	assert.True(t, condition.Abnormal)
+	assert.Equal(t, "volume is full", condition.Message)
+
+	assert.NoError(t, orchestrator.DeleteVolume(ctx(), volumeName))
+	assert.Empty(t, orchestrator.volumeConditions)
+}
@@ -0,0 +1,128 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"context"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/storage"
+	"github.com/netapp/trident/utils"
+)
+
+// SetHostVolumeAccess records whether this host can mount volumes, as it can with Docker or the all-in-one
+// CSI plugin.  Volumes are copied on the host when they are migrated between backends that can't replicate
+// them, or cloned to a backend that uses another protocol.
+func (o *TridentOrchestrator) SetHostVolumeAccess(enabled bool) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	o.hostVolumeAccess = enabled
+}
+
+// volumePopulator fills a new volume before it is registered.  It is called with the orchestrator lock
+// held, and the volume is deleted if it returns an error.
+type volumePopulator func(vol *storage.Volume, backend *storage.Backend) error
+
+// cloneRequiresCopy returns true if a clone can't be created by its source's backend because the clone
+// needs another protocol, either by request or because its storage class only has pools that use
+// another protocol.  The caller must hold the orchestrator lock.
+func (o *TridentOrchestrator) cloneRequiresCopy(
+	volumeConfig *storage.VolumeConfig, sourceBackend *storage.Backend,
+) bool {
+
+	// Invalid combinations are left to the source's backend to reject, as they always were
+	protocol, err := o.getProtocol(volumeConfig.VolumeMode, volumeConfig.AccessMode, volumeConfig.Protocol)
+	if err != nil {
+		return false
+	}
+
+	sourceProtocol := sourceBackend.GetProtocol()
+	if protocol != config.ProtocolAny && protocol != sourceProtocol {
+		return true
+	}
+
+	sc, ok := o.storageClasses[volumeConfig.StorageClass]
+	if !ok {
+		return false
+	}
+	return len(sc.GetStoragePoolsForProtocol(sourceProtocol)) == 0 && len(sc.GetStoragePoolsForProtocol(protocol)) > 0
+}
+
+// cloneVolumeByCopy creates a clone as a new volume in its storage class and copies the files of its
+// source into it, so a volume may be cloned to a backend that uses another protocol.  The files are
+// copied by mounting both volumes on this host, so this host must be able to mount them.  The caller must
+// hold the orchestrator lock and the clone's volume lock.
+func (o *TridentOrchestrator) cloneVolumeByCopy(
+	ctx context.Context, volumeConfig *storage.VolumeConfig, sourceVolume *storage.Volume,
+) (*storage.VolumeExternal, error) {
+
+	if volumeConfig.CloneSourceSnapshot != "" {
+		return nil, fmt.Errorf("cannot clone snapshot %s of volume %s to a backend that uses another protocol",
+			volumeConfig.CloneSourceSnapshot, volumeConfig.CloneSourceVolume)
+	}
+	if !o.hostVolumeAccess {
+		return nil, fmt.Errorf("cannot clone volume %s to storage class %s, which uses another protocol, "+
+			"because volumes can't be copied on this host", volumeConfig.CloneSourceVolume,
+			volumeConfig.StorageClass)
+	}
+
+	copyConfig := volumeConfig.ConstructClone()
+	copyConfig.CopySourceVolume = volumeConfig.CloneSourceVolume
+	copyConfig.CloneSourceVolume = ""
+	copyConfig.CloneSourceVolumeInternal = ""
+	copyConfig.CloneSourceSnapshot = ""
+	if copyConfig.Size == "" {
+		copyConfig.Size = sourceVolume.Config.Size
+	}
+
+	log.WithFields(log.Fields{
+		"volume":       copyConfig.Name,
+		"sourceVolume": copyConfig.CopySourceVolume,
+		"storageClass": copyConfig.StorageClass,
+	}).Info("Cloning volume by copying its files.")
+
+	return o.addVolumeInitial(ctx, copyConfig, o.volumeCopier(copyConfig.CopySourceVolume))
+}
+
+// volumeCopier returns a function that fills a new volume with the files of a source volume.  The
+// orchestrator lock is released while the files are copied, and writes to a source with a block
+// filesystem are suspended meanwhile so that the copy is consistent.
+func (o *TridentOrchestrator) volumeCopier(sourceName string) volumePopulator {
+	return func(vol *storage.Volume, backend *storage.Backend) error {
+
+		sourceVolume, ok := o.volumes[sourceName]
+		if !ok {
+			return utils.NotFoundError(fmt.Sprintf("source volume not found: %s", sourceName))
+		}
+		sourceBackend, ok := o.backends[sourceVolume.BackendUUID]
+		if !ok {
+			return utils.NotFoundError(fmt.Sprintf("backend %s for the source volume not found: %s",
+				sourceVolume.BackendUUID, sourceName))
+		}
+
+		logFields := log.Fields{
+			"volume":       vol.Config.Name,
+			"sourceVolume": sourceName,
+		}
+
+		o.mutex.Unlock()
+		defer o.mutex.Lock()
+
+		err := o.copyVolumeData(sourceVolume.Config, vol.Config, sourceBackend, backend, true,
+			func(transferred, total uint64) {
+				if total > 0 {
+					log.WithFields(logFields).Infof("Copying %d bytes from source volume.", total)
+				}
+			})
+		if err != nil {
+			return fmt.Errorf("could not copy volume %s to volume %s; %v", sourceName, vol.Config.Name, err)
+		}
+
+		log.WithFields(logFields).Info("Copied source volume.")
+		return nil
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/storage"
+	sa "github.com/netapp/trident/storage_attribute"
+	storageclass "github.com/netapp/trident/storage_class"
+	tu "github.com/netapp/trident/storage_drivers/fake/test_utils"
+)
+
+func TestCloneVolumeAcrossProtocols(t *testing.T) {
+	const (
+		fileBackendName  = "copyFile"
+		blockBackendName = "copyBlock"
+		fileSCName       = "copyFileSC"
+		blockSCName      = "copyBlockSC"
+		sourceName       = "copySource"
+	)
+
+	orchestrator := getOrchestrator()
+	addBackendStorageClass(t, orchestrator, fileBackendName, fileSCName, config.File)
+	addBackend(t, orchestrator, blockBackendName, config.Block)
+	if _, err := orchestrator.AddStorageClass(&storageclass.Config{
+		Name:       blockSCName,
+		Attributes: map[string]sa.Request{sa.TestingAttribute: sa.NewBoolRequest(true)},
+		Pools:      map[string][]string{blockBackendName: {"primary"}},
+	}); err != nil {
+		t.Fatal("Unable to add storage class: ", err)
+	}
+	if _, err := orchestrator.AddVolume(ctx(), tu.GenerateVolumeConfig(sourceName, 1, fileSCName,
+		config.File)); err != nil {
+		t.Fatal("Unable to create volume: ", err)
+	}
+
+	cloneConfig := func(name, scName string, protocol config.Protocol) *storage.VolumeConfig {
+		volumeConfig := tu.GenerateVolumeConfig(name, 1, scName, protocol)
+		volumeConfig.CloneSourceVolume = sourceName
+		return volumeConfig
+	}
+
+	sourceBackend := orchestrator.backends[orchestrator.volumes[sourceName].BackendUUID]
+	assert.False(t, orchestrator.cloneRequiresCopy(cloneConfig("c1", fileSCName, config.ProtocolAny), sourceBackend))
+	assert.False(t, orchestrator.cloneRequiresCopy(cloneConfig("c2", fileSCName, config.File), sourceBackend))
+	assert.True(t, orchestrator.cloneRequiresCopy(cloneConfig("c3", fileSCName, config.Block), sourceBackend),
+		"a clone requesting another protocol must be copied")
+	assert.True(t, orchestrator.cloneRequiresCopy(cloneConfig("c4", blockSCName, config.ProtocolAny), sourceBackend),
+		"a clone whose storage class only uses another protocol must be copied")
+
+	// Clones of the same protocol are still made by the source's backend
+	clone, err := orchestrator.CloneVolume(ctx(), cloneConfig("sameProtocolClone", fileSCName, config.ProtocolAny))
+	if assert.NoError(t, err) {
+		assert.Equal(t, sourceName, clone.Config.CloneSourceVolume)
+		assert.Equal(t, sourceBackend.BackendUUID, clone.BackendUUID)
+	}
+
+	// Copying volumes needs a host that can mount them, and snapshots can't be copied
+	_, err = orchestrator.CloneVolume(ctx(), cloneConfig("blockClone", blockSCName, config.ProtocolAny))
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "can't be copied on this host")
+	}
+	snapshotConfig := cloneConfig("blockSnapshotClone", blockSCName, config.ProtocolAny)
+	snapshotConfig.CloneSourceSnapshot = "snap"
+	_, err = orchestrator.CloneVolume(ctx(), snapshotConfig)
+	assert.Error(t, err)
+
+	_, err = orchestrator.GetVolume("blockClone")
+	assert.Error(t, err, "a failed copy clone should not leave a volume behind")
+	transactions, err := orchestrator.storeClient.GetVolumeTransactions()
+	assert.NoError(t, err)
+	assert.Empty(t, transactions)
+	assert.Equal(t, 0, orchestrator.volumeLocks.len())
+
+	cleanup(t, orchestrator)
+}
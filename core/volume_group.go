@@ -0,0 +1,560 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	log "github.com/sirupsen/logrus"
+
+	persistentstore "github.com/netapp/trident/persistent_store"
+	"github.com/netapp/trident/storage"
+	"github.com/netapp/trident/utils"
+)
+
+/////////////////////////////////////////////////////////////////////////////
+//
+// This file contains the volume group operations.  A volume group is a set of
+// volumes, such as those used by one application, that are snapshotted and
+// cloned together.  Group membership is recorded in each member volume's
+// config, so groups need no persistent objects of their own.  Group snapshots
+// are ordinary snapshots with the same name on every member.
+//
+/////////////////////////////////////////////////////////////////////////////
+
+// AddVolumeGroup creates a volume group from a set of volumes that don't belong to another group.
+func (o *TridentOrchestrator) AddVolumeGroup(
+	groupConfig *storage.VolumeGroupConfig,
+) (externalGroup *storage.VolumeGroupExternal, err error) {
+
+	if o.bootstrapError != nil {
+		return nil, o.bootstrapError
+	}
+
+	defer recordTiming("volume_group_add", &err)()
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	if err = groupConfig.Validate(); err != nil {
+		return nil, err
+	}
+	if len(o.getVolumeGroupMembers(groupConfig.Name)) > 0 {
+		return nil, utils.FoundError(fmt.Sprintf("volume group %s already exists", groupConfig.Name))
+	}
+
+	if err = o.setVolumeGroupMembers(groupConfig); err != nil {
+		return nil, err
+	}
+	return o.getVolumeGroup(groupConfig.Name)
+}
+
+// UpdateVolumeGroup replaces the members of an existing volume group.
+func (o *TridentOrchestrator) UpdateVolumeGroup(
+	groupConfig *storage.VolumeGroupConfig,
+) (externalGroup *storage.VolumeGroupExternal, err error) {
+
+	if o.bootstrapError != nil {
+		return nil, o.bootstrapError
+	}
+
+	defer recordTiming("volume_group_update", &err)()
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	if err = groupConfig.Validate(); err != nil {
+		return nil, err
+	}
+	if len(o.getVolumeGroupMembers(groupConfig.Name)) == 0 {
+		return nil, utils.NotFoundError(fmt.Sprintf("volume group %s not found", groupConfig.Name))
+	}
+
+	if err = o.setVolumeGroupMembers(groupConfig); err != nil {
+		return nil, err
+	}
+	return o.getVolumeGroup(groupConfig.Name)
+}
+
+// DeleteVolumeGroup removes all volumes from a volume group.  The volumes and their snapshots
+// are not affected.
+func (o *TridentOrchestrator) DeleteVolumeGroup(groupName string) (err error) {
+
+	if o.bootstrapError != nil {
+		return o.bootstrapError
+	}
+
+	defer recordTiming("volume_group_delete", &err)()
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	members := o.getVolumeGroupMembers(groupName)
+	if len(members) == 0 {
+		return utils.NotFoundError(fmt.Sprintf("volume group %s not found", groupName))
+	}
+
+	for _, volume := range members {
+		if err = o.setVolumeGroup(volume, ""); err != nil {
+			return err
+		}
+	}
+
+	log.WithField("volumeGroup", groupName).Info("Deleted volume group.")
+	return nil
+}
+
+// GetVolumeGroup returns a volume group and the snapshots that can be cloned from it.
+func (o *TridentOrchestrator) GetVolumeGroup(groupName string) (
+	externalGroup *storage.VolumeGroupExternal, err error,
+) {
+	if o.bootstrapError != nil {
+		return nil, o.bootstrapError
+	}
+
+	defer recordTiming("volume_group_get", &err)()
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	return o.getVolumeGroup(groupName)
+}
+
+// ListVolumeGroups returns all volume groups.
+func (o *TridentOrchestrator) ListVolumeGroups() (externalGroups []*storage.VolumeGroupExternal, err error) {
+
+	if o.bootstrapError != nil {
+		return nil, o.bootstrapError
+	}
+
+	defer recordTiming("volume_group_list", &err)()
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	groupNames := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, volume := range o.volumes {
+		if groupName := volume.Config.VolumeGroup; groupName != "" && !seen[groupName] {
+			seen[groupName] = true
+			groupNames = append(groupNames, groupName)
+		}
+	}
+	sort.Strings(groupNames)
+
+	externalGroups = make([]*storage.VolumeGroupExternal, 0, len(groupNames))
+	for _, groupName := range groupNames {
+		externalGroup, err := o.getVolumeGroup(groupName)
+		if err != nil {
+			return nil, err
+		}
+		externalGroups = append(externalGroups, externalGroup)
+	}
+	return externalGroups, nil
+}
+
+// CreateVolumeGroupSnapshot snapshots all members of a volume group at the same point in time.  The
+// members must be on one backend whose driver supports group snapshots, since snapshots taken one
+// after another wouldn't be consistent with each other.
+func (o *TridentOrchestrator) CreateVolumeGroupSnapshot(
+	ctx context.Context, groupName string, request *storage.VolumeGroupSnapshotRequest,
+) (groupSnapshot *storage.VolumeGroupSnapshot, err error) {
+
+	if o.bootstrapError != nil {
+		return nil, o.bootstrapError
+	}
+
+	defer recordTiming("volume_group_snapshot_create", &err)()
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	defer o.updateMetrics()
+
+	if request.Name == "" {
+		return nil, fmt.Errorf("a group snapshot requires a name")
+	}
+
+	members := o.getVolumeGroupMembers(groupName)
+	if len(members) == 0 {
+		return nil, utils.NotFoundError(fmt.Sprintf("volume group %s not found", groupName))
+	}
+
+	snapConfigs := make([]*storage.SnapshotConfig, 0, len(members))
+	for _, volume := range members {
+		snapConfigs = append(snapConfigs, &storage.SnapshotConfig{
+			Version:      volume.Config.Version,
+			Name:         request.Name,
+			VolumeName:   volume.Config.Name,
+			TridentOwned: request.TridentOwned,
+		})
+	}
+
+	return o.createGroupSnapshot(groupName, members, snapConfigs)
+}
+
+// CreateGroupSnapshot snapshots several volumes at the same point in time, whether or not they belong
+// to a volume group, as for a CSI group snapshot of the volumes selected by a label.  Each snapshot
+// config names one volume, and all of them must have the same snapshot name.
+func (o *TridentOrchestrator) CreateGroupSnapshot(
+	ctx context.Context, snapConfigs []*storage.SnapshotConfig,
+) (groupSnapshot *storage.VolumeGroupSnapshot, err error) {
+
+	if o.bootstrapError != nil {
+		return nil, o.bootstrapError
+	}
+
+	defer recordTiming("group_snapshot_create", &err)()
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	defer o.updateMetrics()
+
+	if len(snapConfigs) == 0 {
+		return nil, fmt.Errorf("a group snapshot requires at least one volume")
+	}
+	if snapConfigs[0].Name == "" {
+		return nil, fmt.Errorf("a group snapshot requires a name")
+	}
+
+	members := make([]*storage.Volume, 0, len(snapConfigs))
+	isMember := make(map[string]bool, len(snapConfigs))
+	for _, snapConfig := range snapConfigs {
+		if snapConfig.Name != snapConfigs[0].Name {
+			return nil, fmt.Errorf("the snapshots of a group snapshot must have the same name")
+		}
+		if isMember[snapConfig.VolumeName] {
+			return nil, fmt.Errorf("volume %s is listed more than once", snapConfig.VolumeName)
+		}
+		isMember[snapConfig.VolumeName] = true
+		volume, ok := o.volumes[snapConfig.VolumeName]
+		if !ok {
+			return nil, utils.NotFoundError(fmt.Sprintf("source volume %s not found", snapConfig.VolumeName))
+		}
+		members = append(members, volume)
+	}
+
+	return o.createGroupSnapshot("", members, snapConfigs)
+}
+
+// createGroupSnapshot snapshots a set of volumes, which may be the members of a volume group, at the
+// same point in time.  The snapshot configs and volumes are matched by index.  The volumes must be on
+// one backend whose driver supports group snapshots.  If any snapshot fails, those already taken are
+// deleted.  The caller must hold the orchestrator lock.
+func (o *TridentOrchestrator) createGroupSnapshot(
+	groupName string, members []*storage.Volume, snapConfigs []*storage.SnapshotConfig,
+) (groupSnapshot *storage.VolumeGroupSnapshot, err error) {
+
+	snapshotName := snapConfigs[0].Name
+	description := "the requested volumes"
+	if groupName != "" {
+		description = "volume group " + groupName
+	}
+
+	// Check all members before snapshotting any of them
+	var groupBackend *storage.Backend
+	volConfigs := make([]*storage.VolumeConfig, 0, len(members))
+	for i, volume := range members {
+		volumeName := volume.Config.Name
+		snapConfigs[i].VolumeInternalName = volume.Config.InternalName
+		if _, ok := o.snapshots[snapConfigs[i].ID()]; ok {
+			return nil, fmt.Errorf("snapshot %s already exists", snapConfigs[i].ID())
+		}
+		if volume.State.IsDeleting() {
+			return nil, utils.VolumeDeletingError(fmt.Sprintf("source volume %s is deleting", volumeName))
+		}
+		if o.volumeMigrationInProgress(volumeName) {
+			return nil, fmt.Errorf("source volume %s is being migrated", volumeName)
+		}
+		if o.volumeDeleteInProgress(volumeName) {
+			return nil, fmt.Errorf("source volume %s is being deleted", volumeName)
+		}
+		backend, ok := o.backends[volume.BackendUUID]
+		if !ok {
+			// Should never get here but just to be safe
+			return nil, utils.NotFoundError(fmt.Sprintf("backend %s for the source volume not found: %s",
+				volume.BackendUUID, volumeName))
+		}
+		if groupBackend == nil {
+			groupBackend = backend
+		} else if groupBackend != backend {
+			return nil, fmt.Errorf("%s are on more than one backend, so they can't be snapshotted at the "+
+				"same point in time", description)
+		}
+		volConfigs = append(volConfigs, volume.Config)
+	}
+	if !groupBackend.CanCreateGroupSnapshot() {
+		return nil, utils.UnsupportedError(fmt.Sprintf("backend %s can't snapshot %s at the same point in "+
+			"time", groupBackend.Name, description))
+	}
+
+	// Add transactions in case the operation must be rolled back later
+	txns := make([]*storage.VolumeTransaction, 0, len(members))
+	snapshots := make([]*storage.Snapshot, len(members))
+	defer func() {
+		for i, txn := range txns {
+			err = o.addSnapshotCleanup(err, groupBackend, snapshots[i], txn, snapConfigs[i])
+		}
+	}()
+	for i, volume := range members {
+		txn := &storage.VolumeTransaction{
+			Config:         volume.Config,
+			SnapshotConfig: snapConfigs[i],
+			Op:             storage.AddSnapshot,
+		}
+		if err = o.AddVolumeTransaction(txn); err != nil {
+			return nil, err
+		}
+		txns = append(txns, txn)
+	}
+
+	groupSnapshots, err := groupBackend.CreateGroupSnapshot(snapConfigs, volConfigs)
+	o.recordBackendOperation(groupBackend, "snapshot_group_create", err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create group snapshot %s of %s on backend %s: %v",
+			snapshotName, description, groupBackend.Name, err)
+	}
+	if len(groupSnapshots) != len(members) {
+		return nil, fmt.Errorf("backend %s returned %d snapshots for %d volumes", groupBackend.Name,
+			len(groupSnapshots), len(members))
+	}
+	copy(snapshots, groupSnapshots)
+
+	// Save references to the new snapshots, all together if the store allows
+	err = persistentstore.Atomically(o.storeClient, func(client persistentstore.Client) error {
+		for i, snapshot := range snapshots {
+			if addErr := client.AddSnapshot(snapshot); addErr != nil {
+				for _, saved := range snapshots[:i] {
+					_ = client.DeleteSnapshotIgnoreNotFound(saved)
+				}
+				return addErr
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, snapshot := range snapshots {
+		o.snapshots[snapshot.ID()] = snapshot
+	}
+
+	groupSnapshot = &storage.VolumeGroupSnapshot{
+		Name:        snapshotName,
+		VolumeGroup: groupName,
+		Snapshots:   make([]*storage.SnapshotExternal, 0, len(snapshots)),
+	}
+	for _, snapshot := range snapshots {
+		groupSnapshot.Snapshots = append(groupSnapshot.Snapshots, snapshot.ConstructExternal())
+	}
+
+	log.WithFields(log.Fields{
+		"volumeGroup": groupName,
+		"snapshot":    snapshotName,
+		"volumes":     len(snapshots),
+	}).Info("Created group snapshot.")
+
+	return groupSnapshot, nil
+}
+
+// CloneVolumeGroup clones every member of a volume group from one of the group's snapshots, and
+// makes the clones a new group.  If any clone fails, those already created are deleted.
+func (o *TridentOrchestrator) CloneVolumeGroup(
+	ctx context.Context, groupName string, request *storage.VolumeGroupCloneRequest,
+) (externalGroup *storage.VolumeGroupExternal, err error) {
+
+	if o.bootstrapError != nil {
+		return nil, o.bootstrapError
+	}
+
+	cloneConfigs, err := o.getVolumeGroupCloneConfigs(groupName, request)
+	if err != nil {
+		return nil, err
+	}
+
+	// The clones are created one at a time, without holding the orchestrator lock across all of them.
+	// The cleanup must run even if the caller gave up, so it doesn't use the caller's context.
+	clonedVolumes := make([]string, 0, len(cloneConfigs))
+	defer func() {
+		if err == nil {
+			return
+		}
+		for _, volumeName := range clonedVolumes {
+			if deleteErr := o.DeleteVolume(context.Background(), volumeName); deleteErr != nil {
+				log.WithFields(log.Fields{
+					"volume": volumeName,
+					"error":  deleteErr,
+				}).Error("Unable to delete volume group clone during cleanup.")
+			}
+		}
+	}()
+
+	groupConfig := &storage.VolumeGroupConfig{Name: request.VolumeGroup}
+	for _, cloneConfig := range cloneConfigs {
+		if _, err = o.CloneVolume(ctx, cloneConfig); err != nil {
+			return nil, fmt.Errorf("failed to clone volume %s from snapshot %s: %v",
+				cloneConfig.CloneSourceVolume, request.Snapshot, err)
+		}
+		clonedVolumes = append(clonedVolumes, cloneConfig.Name)
+		groupConfig.Volumes = append(groupConfig.Volumes, cloneConfig.Name)
+	}
+
+	return o.AddVolumeGroup(groupConfig)
+}
+
+// getVolumeGroupCloneConfigs checks a group clone request and returns the configs of the clones.
+func (o *TridentOrchestrator) getVolumeGroupCloneConfigs(
+	groupName string, request *storage.VolumeGroupCloneRequest,
+) ([]*storage.VolumeConfig, error) {
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	if request.Snapshot == "" || request.VolumeGroup == "" {
+		return nil, fmt.Errorf("a group clone requires a snapshot and a new volume group name")
+	}
+	if len(o.getVolumeGroupMembers(request.VolumeGroup)) > 0 {
+		return nil, utils.FoundError(fmt.Sprintf("volume group %s already exists", request.VolumeGroup))
+	}
+
+	members := o.getVolumeGroupMembers(groupName)
+	if len(members) == 0 {
+		return nil, utils.NotFoundError(fmt.Sprintf("volume group %s not found", groupName))
+	}
+
+	cloneConfigs := make([]*storage.VolumeConfig, 0, len(members))
+	for _, volume := range members {
+		snapshotID := storage.MakeSnapshotID(volume.Config.Name, request.Snapshot)
+		if _, ok := o.snapshots[snapshotID]; !ok {
+			return nil, utils.NotFoundError(fmt.Sprintf("snapshot %s of volume group %s not found on volume %s",
+				request.Snapshot, groupName, volume.Config.Name))
+		}
+		cloneName := storage.GroupCloneVolumeName(request.VolumeGroup, volume.Config.Name)
+		if _, ok := o.volumes[cloneName]; ok {
+			return nil, fmt.Errorf("volume %s already exists", cloneName)
+		}
+		cloneConfigs = append(cloneConfigs, &storage.VolumeConfig{
+			Name:                cloneName,
+			VolumeMode:          volume.Config.VolumeMode,
+			CloneSourceVolume:   volume.Config.Name,
+			CloneSourceSnapshot: request.Snapshot,
+		})
+	}
+	return cloneConfigs, nil
+}
+
+// getVolumeGroupMembers returns the volumes in a group, sorted by name.  The caller should hold
+// the orchestrator lock.
+func (o *TridentOrchestrator) getVolumeGroupMembers(groupName string) []*storage.Volume {
+	members := make([]*storage.Volume, 0)
+	if groupName == "" {
+		return members
+	}
+	for _, volume := range o.volumes {
+		if volume.Config.VolumeGroup == groupName {
+			members = append(members, volume)
+		}
+	}
+	sort.Slice(members, func(i, j int) bool { return members[i].Config.Name < members[j].Config.Name })
+	return members
+}
+
+// getVolumeGroup returns a volume group and the snapshots present on all of its members.  The
+// caller should hold the orchestrator lock.
+func (o *TridentOrchestrator) getVolumeGroup(groupName string) (*storage.VolumeGroupExternal, error) {
+
+	members := o.getVolumeGroupMembers(groupName)
+	if len(members) == 0 {
+		return nil, utils.NotFoundError(fmt.Sprintf("volume group %s not found", groupName))
+	}
+
+	externalGroup := &storage.VolumeGroupExternal{
+		Name:      groupName,
+		Volumes:   make([]string, 0, len(members)),
+		Backends:  make([]string, 0),
+		Snapshots: make([]string, 0),
+	}
+
+	backends := make(map[string]bool)
+	snapshotCounts := make(map[string]int)
+	for _, volume := range members {
+		externalGroup.Volumes = append(externalGroup.Volumes, volume.Config.Name)
+		if backend, ok := o.backends[volume.BackendUUID]; ok && !backends[backend.Name] {
+			backends[backend.Name] = true
+			externalGroup.Backends = append(externalGroup.Backends, backend.Name)
+		}
+	}
+	for _, snapshot := range o.snapshots {
+		if volume, ok := o.volumes[snapshot.Config.VolumeName]; ok && volume.Config.VolumeGroup == groupName {
+			snapshotCounts[snapshot.Config.Name]++
+		}
+	}
+	for snapshotName, count := range snapshotCounts {
+		if count == len(members) {
+			externalGroup.Snapshots = append(externalGroup.Snapshots, snapshotName)
+		}
+	}
+	sort.Strings(externalGroup.Backends)
+	sort.Strings(externalGroup.Snapshots)
+
+	return externalGroup, nil
+}
+
+// setVolumeGroupMembers makes the specified volumes the members of a group, removing any other
+// volumes from it.  The caller should hold the orchestrator lock.
+func (o *TridentOrchestrator) setVolumeGroupMembers(groupConfig *storage.VolumeGroupConfig) error {
+
+	volumes := make([]*storage.Volume, 0, len(groupConfig.Volumes))
+	isMember := make(map[string]bool, len(groupConfig.Volumes))
+	for _, volumeName := range groupConfig.Volumes {
+		volume, ok := o.volumes[volumeName]
+		if !ok {
+			return utils.NotFoundError(fmt.Sprintf("volume %s not found", volumeName))
+		}
+		if volume.State.IsDeleting() {
+			return utils.VolumeDeletingError(fmt.Sprintf("volume %s is deleting", volumeName))
+		}
+		if volume.Config.VolumeGroup != "" && volume.Config.VolumeGroup != groupConfig.Name {
+			return fmt.Errorf("volume %s belongs to volume group %s", volumeName, volume.Config.VolumeGroup)
+		}
+		volumes = append(volumes, volume)
+		isMember[volumeName] = true
+	}
+
+	for _, volume := range o.getVolumeGroupMembers(groupConfig.Name) {
+		if !isMember[volume.Config.Name] {
+			if err := o.setVolumeGroup(volume, ""); err != nil {
+				return err
+			}
+		}
+	}
+	for _, volume := range volumes {
+		if err := o.setVolumeGroup(volume, groupConfig.Name); err != nil {
+			return err
+		}
+	}
+
+	log.WithFields(log.Fields{
+		"volumeGroup": groupConfig.Name,
+		"volumes":     groupConfig.Volumes,
+	}).Info("Updated volume group.")
+	return nil
+}
+
+// setVolumeGroup changes the group of a volume and persists it.  The caller should hold the
+// orchestrator lock.
+func (o *TridentOrchestrator) setVolumeGroup(volume *storage.Volume, groupName string) error {
+
+	if volume.Config.VolumeGroup == groupName {
+		return nil
+	}
+
+	oldGroupName := volume.Config.VolumeGroup
+	volume.Config.VolumeGroup = groupName
+	if err := o.updateVolumeOnPersistentStore(volume); err != nil {
+		volume.Config.VolumeGroup = oldGroupName
+		return fmt.Errorf("could not update volume group of volume %s; %v", volume.Config.Name, err)
+	}
+	return nil
+}
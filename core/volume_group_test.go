@@ -0,0 +1,216 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/storage"
+	sa "github.com/netapp/trident/storage_attribute"
+	storageclass "github.com/netapp/trident/storage_class"
+	tu "github.com/netapp/trident/storage_drivers/fake/test_utils"
+	"github.com/netapp/trident/utils"
+)
+
+func TestVolumeGroup(t *testing.T) {
+	const (
+		backendName = "groupBackend"
+		scName      = "groupSC"
+	)
+
+	orchestrator := getOrchestrator()
+	addBackend(t, orchestrator, backendName, config.File)
+	if _, err := orchestrator.AddStorageClass(&storageclass.Config{
+		Name:       scName,
+		Attributes: map[string]sa.Request{sa.TestingAttribute: sa.NewBoolRequest(true)},
+	}); err != nil {
+		t.Fatal("Unable to add storage class: ", err)
+	}
+	for _, volumeName := range []string{"data", "logs", "other"} {
+		if _, err := orchestrator.AddVolume(ctx(), tu.GenerateVolumeConfig(volumeName, 1, scName, config.File)); err != nil {
+			t.Fatal("Unable to create volume: ", err)
+		}
+	}
+
+	group, err := orchestrator.AddVolumeGroup(&storage.VolumeGroupConfig{Name: "app", Volumes: []string{"logs", "data"}})
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"data", "logs"}, group.Volumes)
+		assert.Equal(t, []string{backendName}, group.Backends)
+		assert.Empty(t, group.Snapshots)
+	}
+
+	// Membership is persisted with the volumes
+	storedVolume, err := orchestrator.storeClient.GetVolume("data")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "app", storedVolume.Config.VolumeGroup)
+	}
+
+	_, err = orchestrator.AddVolumeGroup(&storage.VolumeGroupConfig{Name: "app", Volumes: []string{"other"}})
+	assert.True(t, utils.IsFoundError(err), "group names should be unique")
+	_, err = orchestrator.AddVolumeGroup(&storage.VolumeGroupConfig{Name: "app2", Volumes: []string{"data"}})
+	assert.Error(t, err, "a volume should belong to only one group")
+	_, err = orchestrator.AddVolumeGroup(&storage.VolumeGroupConfig{Name: "app2", Volumes: []string{"missing"}})
+	assert.True(t, utils.IsNotFoundError(err))
+
+	// Group snapshots are taken at the same point in time
+	groupSnapshot, err := orchestrator.CreateVolumeGroupSnapshot(ctx(), "app", &storage.VolumeGroupSnapshotRequest{Name: "snap1"})
+	if assert.NoError(t, err) {
+		assert.Len(t, groupSnapshot.Snapshots, 2)
+		assert.Equal(t, groupSnapshot.Snapshots[0].Created, groupSnapshot.Snapshots[1].Created)
+	}
+	_, err = orchestrator.GetSnapshot("logs", "snap1")
+	assert.NoError(t, err)
+	_, err = orchestrator.CreateVolumeGroupSnapshot(ctx(), "app", &storage.VolumeGroupSnapshotRequest{Name: "snap1"})
+	assert.Error(t, err, "group snapshot names should be unique")
+
+	group, err = orchestrator.GetVolumeGroup("app")
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"snap1"}, group.Snapshots)
+	}
+
+	// A snapshot missing from any member isn't a group snapshot
+	group, err = orchestrator.UpdateVolumeGroup(&storage.VolumeGroupConfig{
+		Name:    "app",
+		Volumes: []string{"data", "logs", "other"},
+	})
+	if assert.NoError(t, err) {
+		assert.Len(t, group.Volumes, 3)
+		assert.Empty(t, group.Snapshots)
+	}
+	group, err = orchestrator.UpdateVolumeGroup(&storage.VolumeGroupConfig{Name: "app", Volumes: []string{"data", "logs"}})
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"data", "logs"}, group.Volumes)
+		assert.Equal(t, "", orchestrator.volumes["other"].Config.VolumeGroup)
+	}
+
+	// Cloning a group snapshot creates a new group
+	clonedGroup, err := orchestrator.CloneVolumeGroup(ctx(), "app", &storage.VolumeGroupCloneRequest{
+		Snapshot:    "snap1",
+		VolumeGroup: "app-copy",
+	})
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"app-copy-data", "app-copy-logs"}, clonedGroup.Volumes)
+		assert.Equal(t, "data", orchestrator.volumes["app-copy-data"].Config.CloneSourceVolume)
+	}
+	_, err = orchestrator.CloneVolumeGroup(ctx(), "app", &storage.VolumeGroupCloneRequest{
+		Snapshot:    "missing",
+		VolumeGroup: "app-copy2",
+	})
+	assert.True(t, utils.IsNotFoundError(err))
+
+	groups, err := orchestrator.ListVolumeGroups()
+	if assert.NoError(t, err) && assert.Len(t, groups, 2) {
+		assert.Equal(t, "app", groups[0].Name)
+		assert.Equal(t, "app-copy", groups[1].Name)
+	}
+
+	// Deleting a group leaves its volumes and snapshots
+	assert.NoError(t, orchestrator.DeleteVolumeGroup("app"))
+	_, err = orchestrator.GetVolumeGroup("app")
+	assert.True(t, utils.IsNotFoundError(err))
+	assert.Contains(t, orchestrator.volumes, "data")
+	_, err = orchestrator.GetSnapshot("data", "snap1")
+	assert.NoError(t, err)
+
+	cleanup(t, orchestrator)
+}
+
+func TestVolumeGroupSnapshotAcrossBackends(t *testing.T) {
+	const scName = "groupSC"
+
+	orchestrator := getOrchestrator()
+	addBackend(t, orchestrator, "groupBackend1", config.File)
+	if _, err := orchestrator.AddStorageClass(&storageclass.Config{
+		Name:       scName,
+		Attributes: map[string]sa.Request{sa.TestingAttribute: sa.NewBoolRequest(true)},
+	}); err != nil {
+		t.Fatal("Unable to add storage class: ", err)
+	}
+	if _, err := orchestrator.AddVolume(ctx(), tu.GenerateVolumeConfig("vol1", 1, scName, config.File)); err != nil {
+		t.Fatal("Unable to create volume: ", err)
+	}
+	addBackend(t, orchestrator, "groupBackend2", config.File)
+	var firstBackend *storage.Backend
+	for _, backend := range orchestrator.backends {
+		if backend.Name == "groupBackend1" {
+			firstBackend = backend
+		}
+	}
+	firstBackend.State = storage.Offline
+	if _, err := orchestrator.AddVolume(ctx(), tu.GenerateVolumeConfig("vol2", 1, scName, config.File)); err != nil {
+		t.Fatal("Unable to create volume: ", err)
+	}
+	firstBackend.State = storage.Online
+
+	if _, err := orchestrator.AddVolumeGroup(&storage.VolumeGroupConfig{
+		Name:    "spread",
+		Volumes: []string{"vol1", "vol2"},
+	}); err != nil {
+		t.Fatal("Unable to create volume group: ", err)
+	}
+
+	// Snapshots on different backends can't be taken at the same point in time
+	_, err := orchestrator.CreateVolumeGroupSnapshot(ctx(), "spread", &storage.VolumeGroupSnapshotRequest{Name: "snap"})
+	assert.Error(t, err)
+	for _, volumeName := range []string{"vol1", "vol2"} {
+		_, err = orchestrator.GetSnapshot(volumeName, "snap")
+		assert.True(t, utils.IsNotFoundError(err))
+		backend := orchestrator.backends[orchestrator.volumes[volumeName].BackendUUID]
+		snapshots, err := backend.Driver.GetSnapshots(orchestrator.volumes[volumeName].Config)
+		assert.NoError(t, err)
+		assert.Empty(t, snapshots)
+	}
+
+	cleanup(t, orchestrator)
+}
+
+func TestCreateGroupSnapshot(t *testing.T) {
+	const scName = "groupSC"
+
+	orchestrator := getOrchestrator()
+	addBackend(t, orchestrator, "groupBackend", config.File)
+	if _, err := orchestrator.AddStorageClass(&storageclass.Config{
+		Name:       scName,
+		Attributes: map[string]sa.Request{sa.TestingAttribute: sa.NewBoolRequest(true)},
+	}); err != nil {
+		t.Fatal("Unable to add storage class: ", err)
+	}
+	for _, volumeName := range []string{"vol1", "vol2"} {
+		volumeConfig := tu.GenerateVolumeConfig(volumeName, 1, scName, config.File)
+		if _, err := orchestrator.AddVolume(ctx(), volumeConfig); err != nil {
+			t.Fatal("Unable to create volume: ", err)
+		}
+	}
+	snapConfig := func(volumeName, snapshotName string) *storage.SnapshotConfig {
+		return &storage.SnapshotConfig{Version: "1", Name: snapshotName, VolumeName: volumeName}
+	}
+
+	// Volumes outside any volume group may be snapshotted together
+	groupSnapshot, err := orchestrator.CreateGroupSnapshot(ctx(),
+		[]*storage.SnapshotConfig{snapConfig("vol1", "snap"), snapConfig("vol2", "snap")})
+	if assert.NoError(t, err) {
+		assert.Empty(t, groupSnapshot.VolumeGroup)
+		assert.Len(t, groupSnapshot.Snapshots, 2)
+	}
+	_, err = orchestrator.GetSnapshot("vol2", "snap")
+	assert.NoError(t, err)
+
+	_, err = orchestrator.CreateGroupSnapshot(ctx(), nil)
+	assert.Error(t, err)
+	_, err = orchestrator.CreateGroupSnapshot(ctx(),
+		[]*storage.SnapshotConfig{snapConfig("vol1", "snap2"), snapConfig("vol2", "snap3")})
+	assert.Error(t, err, "member snapshots should have the same name")
+	_, err = orchestrator.CreateGroupSnapshot(ctx(),
+		[]*storage.SnapshotConfig{snapConfig("vol1", "snap2"), snapConfig("vol1", "snap2")})
+	assert.Error(t, err, "a volume should be snapshotted once")
+	_, err = orchestrator.CreateGroupSnapshot(ctx(),
+		[]*storage.SnapshotConfig{snapConfig("vol1", "snap2"), snapConfig("missing", "snap2")})
+	assert.True(t, utils.IsNotFoundError(err))
+	_, err = orchestrator.GetSnapshot("vol1", "snap2")
+	assert.True(t, utils.IsNotFoundError(err), "a failed group snapshot should leave no snapshots")
+
+	cleanup(t, orchestrator)
+}
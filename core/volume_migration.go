@@ -0,0 +1,821 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/storage"
+	sa "github.com/netapp/trident/storage_attribute"
+	"github.com/netapp/trident/utils"
+)
+
+const (
+	migrationPollInterval = 10 * time.Second
+	hostMountPointPrefix  = "trident-copy-"
+)
+
+// migrationSuffix is appended to the name of a migrated volume if its internal name is already
+// taken on the destination backend, as happens when both backends share an SVM.
+const migrationSuffix = "_migrated"
+
+// MigrateVolume starts moving a volume offline to another backend.  Data is copied using storage-native
+// replication when the destination backend supports it, or by mounting both volumes on the
+// Trident host otherwise.  The volume must not be published to any node, and it can't be published
+// while its data is copied; once the copy is done, Trident switches the volume to the destination
+// backend and deletes the source volume.  A volume may also be moved to another storage pool of its
+// own backend if the backend supports it, which it may be while in use.
+func (o *TridentOrchestrator) MigrateVolume(
+	request *storage.VolumeMigrationRequest,
+) (migrationExternal *storage.VolumeMigrationExternal, err error) {
+	if o.bootstrapError != nil {
+		return nil, o.bootstrapError
+	}
+
+	defer recordTiming("volume_migrate", &err)()
+
+	if err = request.Validate(); err != nil {
+		return nil, err
+	}
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	return o.migrateVolume(request)
+}
+
+// migrateVolume validates a migration request and starts the migration.  The caller must hold the
+// orchestrator lock.
+func (o *TridentOrchestrator) migrateVolume(
+	request *storage.VolumeMigrationRequest,
+) (*storage.VolumeMigrationExternal, error) {
+
+	volume, ok := o.volumes[request.Volume]
+	if !ok {
+		return nil, utils.NotFoundError(fmt.Sprintf("volume %s not found", request.Volume))
+	}
+	if volume.State.IsDeleting() {
+		return nil, utils.VolumeDeletingError(fmt.Sprintf("volume %s is deleting", request.Volume))
+	}
+	if volume.Config.ImportNotManaged {
+		return nil, fmt.Errorf("volume %s is not managed by %s", request.Volume, config.OrchestratorName)
+	}
+	if o.volumeMigrationInProgress(request.Volume) {
+		return nil, utils.FoundError(fmt.Sprintf("volume %s is already being migrated", request.Volume))
+	}
+
+	sourceBackend, ok := o.backends[volume.BackendUUID]
+	if !ok {
+		return nil, utils.NotFoundError(fmt.Sprintf("backend for volume %s not found", request.Volume))
+	}
+	destBackend := sourceBackend
+	if request.Backend != "" {
+		var err error
+		if destBackend, err = o.getBackendByBackendName(request.Backend); err != nil {
+			return nil, err
+		}
+	}
+	if destBackend.BackendUUID == sourceBackend.BackendUUID {
+		return o.moveVolumeWithinBackend(volume, sourceBackend, request.StoragePool)
+	}
+
+	// Snapshots stay with a volume moved within its backend, but aren't copied to another backend
+	snapshots, err := o.volumeSnapshots(request.Volume)
+	if err != nil {
+		return nil, err
+	}
+	if len(snapshots) > 0 {
+		return nil, fmt.Errorf("volume %s has snapshots; delete them before migrating the volume", request.Volume)
+	}
+	// Nodes using the volume would keep using the source volume, which is deleted after the cutover
+	if len(volume.PublishedNodes) > 0 {
+		return nil, utils.VolumePublishedError(fmt.Sprintf("volume %s is published to nodes %s; unpublish it "+
+			"before migrating it to another backend", request.Volume, strings.Join(volume.PublishedNodes, ", ")))
+	}
+	if !destBackend.State.IsOnline() {
+		return nil, fmt.Errorf("backend %s is not online", destBackend.Name)
+	}
+	if destBackend.GetProtocol() != sourceBackend.GetProtocol() {
+		return nil, fmt.Errorf("backend %s does not support protocol %s required by volume %s",
+			destBackend.Name, sourceBackend.GetProtocol(), request.Volume)
+	}
+
+	pool, err := o.getMigrationStoragePool(volume, destBackend, request.StoragePool)
+	if err != nil {
+		return nil, err
+	}
+
+	method := storage.MigrationMethodHostCopy
+	if destBackend.CanReplicateFrom(sourceBackend) {
+		method = storage.MigrationMethodReplication
+	} else if !o.hostVolumeAccess {
+		return nil, fmt.Errorf("backend %s cannot replicate volumes from backend %s, and volumes can't be "+
+			"copied on this host", destBackend.Name, sourceBackend.Name)
+	}
+
+	// Prepare the destination volume, which keeps the volume's name but may need a new internal name
+	destConfig := volume.Config.ConstructClone()
+	destConfig.AccessInfo = utils.VolumeAccessInfo{}
+	destBackend.CreatePrepare(destConfig, pool)
+	if destBackend.Driver.Get(destConfig.InternalName) == nil {
+		destConfig.InternalName = destBackend.Driver.GetInternalVolumeName(volume.Config.Name + migrationSuffix)
+		if destBackend.Driver.Get(destConfig.InternalName) == nil {
+			return nil, fmt.Errorf("volume %s already exists on backend %s",
+				destConfig.InternalName, destBackend.Name)
+		}
+	}
+
+	migration := &storage.VolumeMigration{
+		VolumeName:              volume.Config.Name,
+		SourceBackend:           sourceBackend.Name,
+		SourceBackendUUID:       sourceBackend.BackendUUID,
+		SourceInternalName:      volume.Config.InternalName,
+		DestinationBackend:      destBackend.Name,
+		DestinationBackendUUID:  destBackend.BackendUUID,
+		DestinationPool:         pool.Name,
+		DestinationInternalName: destConfig.InternalName,
+		Method:                  method,
+		State:                   storage.MigrationStatePending,
+		StartTime:               time.Now().UTC().Format(time.RFC3339),
+	}
+	if size, err := strconv.ParseUint(volume.Config.Size, 10, 64); err == nil {
+		migration.BytesTotal = size
+	}
+
+	volTxn := &storage.VolumeTransaction{
+		Config:          destConfig,
+		VolumeMigration: migration,
+		Op:              storage.MigrateVolume,
+	}
+	if err = o.AddVolumeTransaction(volTxn); err != nil {
+		return nil, err
+	}
+
+	log.WithFields(log.Fields{
+		"volume":             volume.Config.Name,
+		"sourceBackend":      sourceBackend.Name,
+		"destinationBackend": destBackend.Name,
+		"destinationPool":    pool.Name,
+		"method":             method,
+	}).Info("Starting volume migration.")
+
+	o.migrations[volume.Config.Name] = migration
+	go o.runVolumeMigration(volTxn, volume.Config, sourceBackend, destBackend, pool)
+
+	return migration.ConstructExternal(), nil
+}
+
+// moveVolumeWithinBackend starts moving a volume to another storage pool of its backend, such as another
+// aggregate of an ONTAP backend.  The storage system moves the volume while it stays in use, so the move
+// needs neither replication nor this host.  The caller must hold the orchestrator lock.
+func (o *TridentOrchestrator) moveVolumeWithinBackend(
+	volume *storage.Volume, backend *storage.Backend, poolName string,
+) (*storage.VolumeMigrationExternal, error) {
+
+	if poolName == "" {
+		return nil, fmt.Errorf("volume %s is already on backend %s; specify a storage pool to move it within "+
+			"the backend", volume.Config.Name, backend.Name)
+	}
+	if poolName == volume.Pool {
+		return nil, fmt.Errorf("volume %s is already in storage pool %s", volume.Config.Name, poolName)
+	}
+	pool, ok := backend.Storage[poolName]
+	if !ok {
+		return nil, utils.NotFoundError(fmt.Sprintf("storage pool %s not found on backend %s",
+			poolName, backend.Name))
+	}
+	if !backend.State.IsOnline() {
+		return nil, fmt.Errorf("backend %s is not online", backend.Name)
+	}
+	if !backend.CanMoveVolumes() {
+		return nil, fmt.Errorf("backend %s cannot move volumes between its storage pools", backend.Name)
+	}
+
+	migration := &storage.VolumeMigration{
+		VolumeName:              volume.Config.Name,
+		SourceBackend:           backend.Name,
+		SourceBackendUUID:       backend.BackendUUID,
+		SourceInternalName:      volume.Config.InternalName,
+		DestinationBackend:      backend.Name,
+		DestinationBackendUUID:  backend.BackendUUID,
+		DestinationPool:         pool.Name,
+		DestinationInternalName: volume.Config.InternalName,
+		Method:                  storage.MigrationMethodVolumeMove,
+		State:                   storage.MigrationStatePending,
+		StartTime:               time.Now().UTC().Format(time.RFC3339),
+	}
+	if size, err := strconv.ParseUint(volume.Config.Size, 10, 64); err == nil {
+		migration.BytesTotal = size
+	}
+
+	volTxn := &storage.VolumeTransaction{
+		Config:          volume.Config.ConstructClone(),
+		VolumeMigration: migration,
+		Op:              storage.MigrateVolume,
+	}
+	if err := o.AddVolumeTransaction(volTxn); err != nil {
+		return nil, err
+	}
+
+	log.WithFields(log.Fields{
+		"volume":          volume.Config.Name,
+		"backend":         backend.Name,
+		"sourcePool":      volume.Pool,
+		"destinationPool": pool.Name,
+	}).Info("Starting volume move.")
+
+	o.migrations[volume.Config.Name] = migration
+	go o.runVolumeMove(volTxn, backend, pool)
+
+	return migration.ConstructExternal(), nil
+}
+
+// runVolumeMove moves a volume to another storage pool of its backend, waits for the storage system to
+// finish the move, and records the volume's new pool.  A move that fails leaves the volume where it was.
+func (o *TridentOrchestrator) runVolumeMove(
+	volTxn *storage.VolumeTransaction, backend *storage.Backend, pool *storage.Pool,
+) {
+	migration := volTxn.VolumeMigration
+
+	o.setMigrationState(migration, storage.MigrationStateCopying)
+
+	err := backend.MoveVolume(volTxn.Config, pool)
+	for err == nil {
+		transferred, total, done, progressErr := backend.GetVolumeMoveProgress(volTxn.Config)
+		if progressErr != nil {
+			err = progressErr
+			break
+		}
+		o.setMigrationProgress(migration, transferred, total)
+		if done {
+			break
+		}
+		time.Sleep(migrationPollInterval)
+	}
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	defer o.updateMetrics()
+
+	if err == nil {
+		err = o.cutOverVolumeMove(migration)
+	}
+
+	logFields := log.Fields{
+		"volume":          migration.VolumeName,
+		"backend":         migration.SourceBackend,
+		"destinationPool": migration.DestinationPool,
+	}
+	if err != nil {
+		log.WithFields(logFields).Errorf("Volume move failed; %v", err)
+	} else {
+		log.WithFields(logFields).Info("Volume move completed.")
+	}
+
+	o.recordBackendOperation(backend, "volume_move", err)
+
+	if txnErr := o.DeleteVolumeTransaction(volTxn); txnErr != nil {
+		log.WithFields(logFields).Warnf("Could not delete volume move transaction; %v", txnErr)
+	}
+
+	migration.Finish(err)
+}
+
+// cutOverVolumeMove records that a moved volume is in its destination pool.  The caller must hold the
+// orchestrator lock.
+func (o *TridentOrchestrator) cutOverVolumeMove(migration *storage.VolumeMigration) error {
+
+	volume, ok := o.volumes[migration.VolumeName]
+	if !ok {
+		return utils.NotFoundError(fmt.Sprintf("volume %s not found", migration.VolumeName))
+	}
+
+	movedVolume := *volume
+	movedVolume.Pool = migration.DestinationPool
+	if err := o.updateVolumeOnPersistentStore(&movedVolume); err != nil {
+		return err
+	}
+
+	if backend, ok := o.backends[volume.BackendUUID]; ok {
+		backend.Volumes[migration.VolumeName] = &movedVolume
+	}
+	o.volumes[migration.VolumeName] = &movedVolume
+
+	return nil
+}
+
+// getMigrationStoragePool returns the storage pool on the destination backend that will hold a
+// migrated volume.  If no pool is named, the volume's storage class chooses one.
+func (o *TridentOrchestrator) getMigrationStoragePool(
+	volume *storage.Volume, destBackend *storage.Backend, poolName string,
+) (*storage.Pool, error) {
+
+	if poolName != "" {
+		pool, ok := destBackend.Storage[poolName]
+		if !ok {
+			return nil, utils.NotFoundError(fmt.Sprintf("storage pool %s not found on backend %s",
+				poolName, destBackend.Name))
+		}
+		return pool, nil
+	}
+
+	if sc, ok := o.storageClasses[volume.Config.StorageClass]; ok {
+		for _, pool := range sc.GetStoragePoolsForProtocol(destBackend.GetProtocol()) {
+			if pool.Backend.BackendUUID == destBackend.BackendUUID {
+				return pool, nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("no storage pool on backend %s matches storage class %s; specify a storage pool",
+		destBackend.Name, volume.Config.StorageClass)
+}
+
+// drainBackend starts migrating every volume on a draining backend to another backend that
+// satisfies the volume's storage class, preferring backends that can replicate the volume.  Volumes
+// that can't be migrated stay where they are, and setting the backend's state to draining again
+// retries them.  The caller must hold the orchestrator lock.
+func (o *TridentOrchestrator) drainBackend(backend *storage.Backend) {
+
+	for volumeName, volume := range backend.Volumes {
+
+		if o.volumeMigrationInProgress(volumeName) {
+			continue
+		}
+
+		logFields := log.Fields{"backend": backend.Name, "volume": volumeName}
+
+		pool := o.getDrainDestination(volume, backend)
+		if pool == nil {
+			log.WithFields(logFields).Warn("No other backend can hold the volume, so it remains on the " +
+				"draining backend.")
+			continue
+		}
+
+		request := &storage.VolumeMigrationRequest{
+			Volume:      volumeName,
+			Backend:     pool.Backend.Name,
+			StoragePool: pool.Name,
+		}
+		if _, err := o.migrateVolume(request); err != nil {
+			log.WithFields(logFields).Warnf("Could not migrate volume from the draining backend; %v", err)
+		}
+	}
+}
+
+// getDrainDestination returns a storage pool on another backend that can accept a volume from a
+// draining backend, or nil if there is none.
+func (o *TridentOrchestrator) getDrainDestination(volume *storage.Volume, source *storage.Backend) *storage.Pool {
+
+	sc, ok := o.storageClasses[volume.Config.StorageClass]
+	if !ok {
+		return nil
+	}
+
+	var hostCopyPool *storage.Pool
+	for _, pool := range sc.GetStoragePoolsForProtocol(source.GetProtocol()) {
+		if pool.Backend.BackendUUID == source.BackendUUID || !pool.Backend.State.AcceptsNewVolumes() {
+			continue
+		}
+		if !pool.Backend.ServesNamespace(volume.Config.Namespace, volume.Config.NamespaceLabels) {
+			continue
+		}
+		if pool.Backend.CanReplicateFrom(source) {
+			return pool
+		}
+		if hostCopyPool == nil {
+			hostCopyPool = pool
+		}
+	}
+	return hostCopyPool
+}
+
+// volumeMigrationInProgress returns true if the volume is being migrated.  The caller must hold
+// the orchestrator lock.
+func (o *TridentOrchestrator) volumeMigrationInProgress(volumeName string) bool {
+	migration, ok := o.migrations[volumeName]
+	return ok && !migration.State.IsDone()
+}
+
+// setMigrationState updates a running migration while holding the orchestrator lock, so that
+// readers see a consistent migration.
+func (o *TridentOrchestrator) setMigrationState(migration *storage.VolumeMigration, state storage.MigrationState) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	migration.State = state
+}
+
+func (o *TridentOrchestrator) setMigrationProgress(migration *storage.VolumeMigration, transferred, total uint64) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	migration.BytesTransferred = transferred
+	if total > 0 {
+		migration.BytesTotal = total
+	}
+}
+
+// runVolumeMigration copies a volume to its destination backend, switches the volume over to the
+// destination, and deletes the source volume.  If anything fails before the switch, the partial
+// destination volume is deleted and the volume stays where it was.  A replicated volume's source is
+// quiesced by its driver before the final transfer, so no write is lost in the switch.
+func (o *TridentOrchestrator) runVolumeMigration(
+	volTxn *storage.VolumeTransaction, sourceConfig *storage.VolumeConfig,
+	sourceBackend, destBackend *storage.Backend, pool *storage.Pool,
+) {
+	migration := volTxn.VolumeMigration
+	destConfig := volTxn.Config
+
+	o.setMigrationState(migration, storage.MigrationStateCopying)
+
+	var err error
+	if migration.Method == storage.MigrationMethodReplication {
+		err = o.replicateVolumeForMigration(migration, sourceConfig, destConfig, sourceBackend, destBackend, pool)
+	} else {
+		err = o.copyVolumeForMigration(migration, sourceConfig, destConfig, sourceBackend, destBackend, pool)
+	}
+
+	if err == nil {
+		o.setMigrationState(migration, storage.MigrationStateCuttingOver)
+		if migration.Method == storage.MigrationMethodReplication {
+			err = destBackend.PromoteReplica(destConfig, sourceConfig, sourceBackend)
+		}
+	}
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	defer o.updateMetrics()
+
+	if err == nil {
+		err = o.cutOverVolumeMigration(migration, destConfig, destBackend, pool)
+	}
+
+	logFields := log.Fields{
+		"volume":             migration.VolumeName,
+		"sourceBackend":      migration.SourceBackend,
+		"destinationBackend": migration.DestinationBackend,
+	}
+
+	if err != nil {
+		log.WithFields(logFields).Errorf("Volume migration failed; %v", err)
+		if cleanupErr := o.deleteMigrationDestination(migration, destConfig); cleanupErr != nil {
+			log.WithFields(logFields).Warnf("Could not delete destination volume %s; it must be deleted "+
+				"manually. %v", destConfig.InternalName, cleanupErr)
+		}
+	} else {
+		// The volume now lives on the destination backend, and cutOverVolumeMigration made sure no
+		// node uses it, so the source volume is retired.  A failure to delete it doesn't fail the
+		// migration.
+		ctx, cancel := o.operationContext(context.Background(), OperationDeleteVolume)
+		removeErr := sourceBackend.RemoveVolume(ctx, sourceConfig)
+		cancel()
+		o.recordBackendOperation(sourceBackend, "volume_delete", removeErr)
+		if removeErr != nil {
+			log.WithFields(logFields).Warnf("Could not delete source volume %s; it must be deleted "+
+				"manually. %v", sourceConfig.InternalName, removeErr)
+		}
+		log.WithFields(logFields).Info("Volume migration completed.")
+	}
+
+	o.recordBackendOperation(destBackend, "volume_migrate", err)
+
+	if txnErr := o.DeleteVolumeTransaction(volTxn); txnErr != nil {
+		log.WithFields(logFields).Warnf("Could not delete volume migration transaction; %v", txnErr)
+	}
+
+	migration.Finish(err)
+}
+
+// replicateVolumeForMigration creates a replica of the source volume on the destination backend and
+// waits for the baseline transfer to complete.
+func (o *TridentOrchestrator) replicateVolumeForMigration(
+	migration *storage.VolumeMigration, sourceConfig, destConfig *storage.VolumeConfig,
+	sourceBackend, destBackend *storage.Backend, pool *storage.Pool,
+) error {
+
+	if err := destBackend.CreateReplica(destConfig, sourceConfig, pool, sourceBackend); err != nil {
+		return err
+	}
+
+	for {
+		transferred, done, err := destBackend.GetReplicaProgress(destConfig, sourceBackend)
+		if err != nil {
+			return err
+		}
+		o.setMigrationProgress(migration, transferred, 0)
+		if done {
+			return nil
+		}
+		time.Sleep(migrationPollInterval)
+	}
+}
+
+// copyVolumeForMigration creates an empty volume on the destination backend, mounts the source and
+// destination volumes on this host, and copies the volume's files.  The destination volume is created
+// without holding the orchestrator lock.
+func (o *TridentOrchestrator) copyVolumeForMigration(
+	migration *storage.VolumeMigration, sourceConfig, destConfig *storage.VolumeConfig,
+	sourceBackend, destBackend *storage.Backend, pool *storage.Pool,
+) error {
+
+	o.mutex.Lock()
+	volAttributes := make(map[string]sa.Request)
+	if sc, ok := o.storageClasses[sourceConfig.StorageClass]; ok {
+		volAttributes = sc.GetAttributes()
+	}
+	ctx, cancel := o.operationContext(context.Background(), OperationCreateVolume)
+	var err error
+	o.withBackendUnlocked(destBackend, func() {
+		_, err = destBackend.AddVolume(ctx, destConfig, pool, volAttributes, false)
+	})
+	cancel()
+	// The destination volume isn't part of the backend until the volume is cut over
+	o.currentBackend(destBackend).RemoveCachedVolume(destConfig.Name)
+	o.mutex.Unlock()
+	if err != nil {
+		return err
+	}
+
+	return o.copyVolumeData(sourceConfig, destConfig, sourceBackend, destBackend, false,
+		func(transferred, total uint64) {
+			o.setMigrationProgress(migration, transferred, total)
+		})
+}
+
+// copyVolumeData mounts the source and destination volumes on this host and copies the source's files.
+// If freeze is set, writes to a source with a block filesystem are suspended during the copy.  The
+// progress function is called with the total number of bytes to copy, and then with the number of bytes
+// copied after each file.  The caller must not hold the orchestrator lock.
+func (o *TridentOrchestrator) copyVolumeData(
+	sourceConfig, destConfig *storage.VolumeConfig, sourceBackend, destBackend *storage.Backend, freeze bool,
+	progress func(transferred, total uint64),
+) error {
+
+	sourceMountpoint, sourceFilesystem, err := o.mountVolumeOnHost(sourceConfig, sourceBackend)
+	if err != nil {
+		return err
+	}
+	defer o.unmountVolumeOnHost(sourceMountpoint)
+
+	destMountpoint, _, err := o.mountVolumeOnHost(destConfig, destBackend)
+	if err != nil {
+		return err
+	}
+	defer o.unmountVolumeOnHost(destMountpoint)
+
+	// A block filesystem mounted twice on one host is shared by its mounts, so freezing this mount also
+	// quiesces the volume's other users on this host.  NFS can't be frozen, so its writers must be stopped.
+	if freeze && sourceFilesystem != "nfs" {
+		if err = utils.FreezeFilesystem(sourceMountpoint); err != nil {
+			return err
+		}
+		defer func() {
+			if thawErr := utils.ThawFilesystem(sourceMountpoint); thawErr != nil {
+				log.WithField("volume", sourceConfig.Name).Errorf("Could not thaw volume; %v", thawErr)
+			}
+		}()
+	}
+
+	total, err := utils.DirectorySize(sourceMountpoint)
+	if err != nil {
+		return err
+	}
+	progress(0, uint64(total))
+
+	return utils.CopyDirectory(sourceMountpoint, destMountpoint, func(bytesCopied int64) {
+		progress(uint64(bytesCopied), 0)
+	})
+}
+
+// mountVolumeOnHost publishes a volume to this host and mounts it on a temporary directory.  It returns
+// the mount point and the volume's filesystem type.  The volume is published without holding the
+// orchestrator lock.
+func (o *TridentOrchestrator) mountVolumeOnHost(
+	volConfig *storage.VolumeConfig, backend *storage.Backend,
+) (string, string, error) {
+
+	publishInfo := &utils.VolumePublishInfo{Localhost: true}
+
+	o.mutex.Lock()
+	nodes := make([]*utils.Node, 0)
+	for _, node := range o.nodes {
+		nodes = append(nodes, node)
+	}
+	publishInfo.Nodes = nodes
+	publishInfo.BackendUUID = backend.BackendUUID
+	ctx, cancel := o.operationContext(context.Background(), OperationPublishVolume)
+	var err error
+	o.withBackendUnlocked(backend, func() {
+		err = backend.PublishVolume(ctx, volConfig, publishInfo)
+	})
+	cancel()
+	o.mutex.Unlock()
+	if err != nil {
+		return "", "", fmt.Errorf("error publishing volume %s; %v", volConfig.InternalName, err)
+	}
+
+	mountpoint, err := ioutil.TempDir("", hostMountPointPrefix)
+	if err != nil {
+		return "", "", err
+	}
+
+	if publishInfo.FilesystemType == "nfs" {
+		err = utils.AttachNFSVolume(volConfig.InternalName, mountpoint, publishInfo)
+	} else {
+		err = utils.AttachISCSIVolume(volConfig.InternalName, mountpoint, publishInfo)
+	}
+	if err != nil {
+		_ = os.Remove(mountpoint)
+		return "", "", fmt.Errorf("error mounting volume %s; %v", volConfig.InternalName, err)
+	}
+
+	return mountpoint, publishInfo.FilesystemType, nil
+}
+
+func (o *TridentOrchestrator) unmountVolumeOnHost(mountpoint string) {
+	if err := utils.Umount(mountpoint); err != nil {
+		log.WithField("mountpoint", mountpoint).Warnf("Could not unmount volume; %v", err)
+		return
+	}
+	if err := os.Remove(mountpoint); err != nil {
+		log.WithField("mountpoint", mountpoint).Warnf("Could not remove mount point; %v", err)
+	}
+}
+
+// cutOverVolumeMigration switches a volume to its copy on the destination backend.  The caller
+// must hold the orchestrator lock.
+func (o *TridentOrchestrator) cutOverVolumeMigration(
+	migration *storage.VolumeMigration, destConfig *storage.VolumeConfig,
+	destBackend *storage.Backend, pool *storage.Pool,
+) error {
+
+	volume, ok := o.volumes[migration.VolumeName]
+	if !ok {
+		return utils.NotFoundError(fmt.Sprintf("volume %s not found", migration.VolumeName))
+	}
+	if len(volume.PublishedNodes) > 0 {
+		return utils.VolumePublishedError(fmt.Sprintf("volume %s was published to nodes %s during its "+
+			"migration", migration.VolumeName, strings.Join(volume.PublishedNodes, ", ")))
+	}
+
+	// The destination volume has no publications, so nodes get its access info when it is next published
+	newVolume := storage.NewVolume(destConfig, destBackend.BackendUUID, pool.Name, false)
+	newVolume.State = volume.State
+	if err := o.updateVolumeOnPersistentStore(newVolume); err != nil {
+		return err
+	}
+
+	if sourceBackend, ok := o.backends[migration.SourceBackendUUID]; ok {
+		sourceBackend.RemoveCachedVolume(migration.VolumeName)
+	}
+	destBackend.Volumes[migration.VolumeName] = newVolume
+	o.volumes[migration.VolumeName] = newVolume
+
+	return nil
+}
+
+// deleteMigrationDestination deletes the destination volume of a migration that didn't complete.
+// The caller must hold the orchestrator lock.
+func (o *TridentOrchestrator) deleteMigrationDestination(
+	migration *storage.VolumeMigration, destConfig *storage.VolumeConfig,
+) error {
+
+	destBackend, ok := o.backends[migration.DestinationBackendUUID]
+	if !ok {
+		return utils.NotFoundError(fmt.Sprintf("backend %s not found", migration.DestinationBackend))
+	}
+
+	if migration.Method == storage.MigrationMethodReplication {
+		sourceBackend, ok := o.backends[migration.SourceBackendUUID]
+		if !ok {
+			return utils.NotFoundError(fmt.Sprintf("backend %s not found", migration.SourceBackend))
+		}
+		sourceConfig := destConfig.ConstructClone()
+		sourceConfig.InternalName = migration.SourceInternalName
+		return destBackend.DeleteReplica(destConfig, sourceConfig, sourceBackend)
+	}
+
+	ctx, cancel := o.operationContext(context.Background(), OperationDeleteVolume)
+	err := destBackend.RemoveVolume(ctx, destConfig)
+	cancel()
+	o.recordBackendOperation(destBackend, "volume_delete", err)
+	return err
+}
+
+// handleFailedMigration finishes a migration that was interrupted by a restart.  If the volume was
+// already switched to the destination backend, only the source volume remains to be deleted;
+// otherwise the destination volume is deleted and the volume stays on its source backend.
+func (o *TridentOrchestrator) handleFailedMigration(v *storage.VolumeTransaction) error {
+
+	migration := v.VolumeMigration
+	logFields := log.Fields{
+		"volume":             migration.VolumeName,
+		"sourceBackend":      migration.SourceBackend,
+		"destinationBackend": migration.DestinationBackend,
+	}
+
+	if migration.Method == storage.MigrationMethodVolumeMove {
+		o.handleFailedVolumeMove(v)
+	} else if volume, ok := o.volumes[v.Config.Name]; ok && volume.BackendUUID == migration.DestinationBackendUUID {
+		if sourceBackend, ok := o.backends[migration.SourceBackendUUID]; ok {
+			sourceConfig := v.Config.ConstructClone()
+			sourceConfig.InternalName = migration.SourceInternalName
+			ctx, cancel := o.operationContext(context.Background(), OperationDeleteVolume)
+			err := sourceBackend.RemoveVolume(ctx, sourceConfig)
+			cancel()
+			if err != nil {
+				log.WithFields(logFields).Warnf("Could not delete source volume %s; it must be deleted "+
+					"manually. %v", migration.SourceInternalName, err)
+			}
+		}
+		migration.Finish(nil)
+	} else {
+		if err := o.deleteMigrationDestination(migration, v.Config); err != nil {
+			log.WithFields(logFields).Warnf("Could not delete destination volume %s; it must be deleted "+
+				"manually. %v", migration.DestinationInternalName, err)
+		}
+		migration.Finish(fmt.Errorf("migration was interrupted by a restart of %s", config.OrchestratorName))
+	}
+	o.migrations[migration.VolumeName] = migration
+
+	if err := o.DeleteVolumeTransaction(v); err != nil {
+		return fmt.Errorf("failed to clean up volume migration transaction: %v", err)
+	}
+	return nil
+}
+
+// handleFailedVolumeMove finishes a move within a backend that was interrupted by a restart.  The
+// storage system carries on with a move regardless of Trident, so the volume's pool is updated if the
+// move completed, and the migration fails otherwise.  The caller must hold the orchestrator lock.
+func (o *TridentOrchestrator) handleFailedVolumeMove(v *storage.VolumeTransaction) {
+
+	migration := v.VolumeMigration
+
+	var err error
+	if backend, ok := o.backends[migration.SourceBackendUUID]; !ok {
+		err = utils.NotFoundError(fmt.Sprintf("backend %s not found", migration.SourceBackend))
+	} else if _, _, done, progressErr := backend.GetVolumeMoveProgress(v.Config); progressErr != nil {
+		err = progressErr
+	} else if !done {
+		err = fmt.Errorf("%s restarted while the volume was moving; the storage system may still complete "+
+			"the move", config.OrchestratorName)
+	} else {
+		err = o.cutOverVolumeMove(migration)
+	}
+
+	if err != nil {
+		log.WithFields(log.Fields{
+			"volume":          migration.VolumeName,
+			"backend":         migration.SourceBackend,
+			"destinationPool": migration.DestinationPool,
+		}).Warnf("Could not finish volume move; %v", err)
+	}
+	migration.Finish(err)
+}
+
+// GetVolumeMigration returns the most recent migration of a volume.
+func (o *TridentOrchestrator) GetVolumeMigration(
+	volumeName string,
+) (migrationExternal *storage.VolumeMigrationExternal, err error) {
+	if o.bootstrapError != nil {
+		return nil, o.bootstrapError
+	}
+
+	defer recordTiming("volume_migration_get", &err)()
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	migration, ok := o.migrations[volumeName]
+	if !ok {
+		return nil, utils.NotFoundError(fmt.Sprintf("migration of volume %s not found", volumeName))
+	}
+	return migration.ConstructExternal(), nil
+}
+
+// ListVolumeMigrations returns the most recent migration of each volume that has been migrated
+// since Trident started.
+func (o *TridentOrchestrator) ListVolumeMigrations() (migrations []*storage.VolumeMigrationExternal, err error) {
+	if o.bootstrapError != nil {
+		return nil, o.bootstrapError
+	}
+
+	defer recordTiming("volume_migration_list", &err)()
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	migrations = make([]*storage.VolumeMigrationExternal, 0, len(o.migrations))
+	for _, migration := range o.migrations {
+		migrations = append(migrations, migration.ConstructExternal())
+	}
+	return migrations, nil
+}
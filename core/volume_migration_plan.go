@@ -0,0 +1,194 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/storage"
+	"github.com/netapp/trident/utils"
+)
+
+/////////////////////////////////////////////////////////////////////////////
+//
+// This file contains the plan for migrating a volume, which reports the
+// drivers, storage pool and copy method a migration would use, and whether
+// anything stops it.  It lets a volume be moved from one driver to another
+// on the same storage system, such as from ontap-san to ontap-san-economy,
+// knowing beforehand how its data will be copied.
+//
+/////////////////////////////////////////////////////////////////////////////
+
+const (
+	migrationCheckVolume       = "volume"
+	migrationCheckBackend      = "backend"
+	migrationCheckPool         = "pool"
+	migrationCheckSnapshots    = "snapshots"
+	migrationCheckPublications = "publications"
+	migrationCheckMethod       = "method"
+)
+
+// PlanVolumeMigration returns how a volume would be migrated to another backend or storage pool, and a
+// report of whether the migration may proceed.
+func (o *TridentOrchestrator) PlanVolumeMigration(
+	request *storage.VolumeMigrationRequest,
+) (plan *storage.VolumeMigrationPlan, err error) {
+	if o.bootstrapError != nil {
+		return nil, o.bootstrapError
+	}
+
+	defer recordTiming("volume_migration_plan", &err)()
+
+	if err = request.Validate(); err != nil {
+		return nil, err
+	}
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	return o.planVolumeMigration(request)
+}
+
+// planVolumeMigration returns the plan for a migration.  The caller must hold the orchestrator lock.
+func (o *TridentOrchestrator) planVolumeMigration(
+	request *storage.VolumeMigrationRequest,
+) (*storage.VolumeMigrationPlan, error) {
+
+	volume, ok := o.volumes[request.Volume]
+	if !ok {
+		return nil, utils.NotFoundError(fmt.Sprintf("volume %s not found", request.Volume))
+	}
+	sourceBackend, ok := o.backends[volume.BackendUUID]
+	if !ok {
+		return nil, utils.NotFoundError(fmt.Sprintf("backend for volume %s not found", request.Volume))
+	}
+	destBackend := sourceBackend
+	if request.Backend != "" {
+		var err error
+		if destBackend, err = o.getBackendByBackendName(request.Backend); err != nil {
+			return nil, err
+		}
+	}
+	withinBackend := destBackend.BackendUUID == sourceBackend.BackendUUID
+
+	plan := &storage.VolumeMigrationPlan{
+		VolumeName:         volume.Config.Name,
+		SourceBackend:      sourceBackend.Name,
+		SourceDriver:       sourceBackend.GetDriverName(),
+		DestinationBackend: destBackend.Name,
+		DestinationDriver:  destBackend.GetDriverName(),
+		Preflight:          storage.NewPreflightReport(),
+	}
+	report := plan.Preflight
+
+	volumeCheck := &storage.PreflightCheck{Name: migrationCheckVolume}
+	if volume.State.IsDeleting() {
+		volumeCheck.Findings = append(volumeCheck.Findings, fmt.Sprintf("Volume %s is being deleted.",
+			volume.Config.Name))
+	}
+	if volume.Config.ImportNotManaged {
+		volumeCheck.Findings = append(volumeCheck.Findings, fmt.Sprintf("Volume %s is not managed by %s.",
+			volume.Config.Name, config.OrchestratorName))
+	}
+	if o.volumeMigrationInProgress(volume.Config.Name) {
+		volumeCheck.Findings = append(volumeCheck.Findings, fmt.Sprintf("Volume %s is already being migrated.",
+			volume.Config.Name))
+		volumeCheck.Remediation = "Wait for the volume's migration to finish."
+	}
+	report.AddCheck(volumeCheck)
+
+	backendCheck := &storage.PreflightCheck{Name: migrationCheckBackend}
+	if !destBackend.State.IsOnline() {
+		backendCheck.Findings = append(backendCheck.Findings, fmt.Sprintf("Backend %s is %s.",
+			destBackend.Name, destBackend.State))
+		backendCheck.Remediation = "Bring the backend online."
+	}
+	if destBackend.GetProtocol() != sourceBackend.GetProtocol() {
+		backendCheck.Findings = append(backendCheck.Findings, fmt.Sprintf(
+			"Backend %s does not support protocol %s required by volume %s.", destBackend.Name,
+			sourceBackend.GetProtocol(), volume.Config.Name))
+	}
+	report.AddCheck(backendCheck)
+
+	poolCheck := &storage.PreflightCheck{Name: migrationCheckPool}
+	if withinBackend {
+		plan.DestinationPool = request.StoragePool
+		if request.StoragePool == "" {
+			poolCheck.Findings = []string{fmt.Sprintf("Volume %s is already on backend %s.", volume.Config.Name,
+				destBackend.Name)}
+			poolCheck.Remediation = "Specify a storage pool to move the volume within its backend."
+		} else if request.StoragePool == volume.Pool {
+			poolCheck.Findings = []string{fmt.Sprintf("Volume %s is already in storage pool %s.",
+				volume.Config.Name, volume.Pool)}
+		} else if _, ok := destBackend.Storage[request.StoragePool]; !ok {
+			poolCheck.Findings = []string{fmt.Sprintf("Storage pool %s was not found on backend %s.",
+				request.StoragePool, destBackend.Name)}
+		}
+	} else if pool, err := o.getMigrationStoragePool(volume, destBackend, request.StoragePool); err != nil {
+		poolCheck.Findings = []string{err.Error()}
+		poolCheck.Remediation = "Specify a storage pool of the destination backend."
+	} else {
+		plan.DestinationPool = pool.Name
+	}
+	report.AddCheck(poolCheck)
+
+	methodCheck := &storage.PreflightCheck{Name: migrationCheckMethod}
+	if withinBackend {
+		// The storage system moves the volume while it stays in use, snapshots included
+		plan.Method = storage.MigrationMethodVolumeMove
+		if !destBackend.CanMoveVolumes() {
+			methodCheck.Findings = []string{fmt.Sprintf("Backend %s cannot move volumes between its "+
+				"storage pools.", destBackend.Name)}
+		}
+		report.AddCheck(methodCheck)
+		return plan, nil
+	}
+
+	if destBackend.CanReplicateFrom(sourceBackend) {
+		plan.Method = storage.MigrationMethodReplication
+	} else {
+		plan.Method = storage.MigrationMethodHostCopy
+		if !o.hostVolumeAccess {
+			methodCheck.Findings = []string{fmt.Sprintf("Backend %s cannot replicate volumes from backend %s, "+
+				"and volumes can't be copied on the %s host.", destBackend.Name, sourceBackend.Name,
+				config.OrchestratorName)}
+			methodCheck.Remediation = "Migrate the volume to a backend with the same driver, which can " +
+				"replicate it, or run " + config.OrchestratorName + " with access to the volumes."
+		} else {
+			methodCheck.Status = storage.PreflightWarning
+			methodCheck.Findings = []string{fmt.Sprintf("Backend %s cannot replicate volumes from backend %s, "+
+				"so the volume's files will be copied on the %s host.", destBackend.Name, sourceBackend.Name,
+				config.OrchestratorName)}
+		}
+	}
+	report.AddCheck(methodCheck)
+
+	// Snapshots stay with a volume moved within its backend, but aren't copied to another backend
+	snapshotsCheck := &storage.PreflightCheck{Name: migrationCheckSnapshots}
+	for _, snapshot := range o.snapshots {
+		if snapshot.Config.VolumeName == volume.Config.Name {
+			snapshotsCheck.Findings = append(snapshotsCheck.Findings, fmt.Sprintf(
+				"Snapshot %s would not be migrated.", snapshot.Config.Name))
+		}
+	}
+	sort.Strings(snapshotsCheck.Findings)
+	if len(snapshotsCheck.Findings) > 0 {
+		snapshotsCheck.Remediation = "Clone the snapshots to keep their data, then delete them."
+	}
+	report.AddCheck(snapshotsCheck)
+
+	// Nodes using the volume would keep writing to the source volume, which is deleted once the
+	// volume is switched to the destination
+	publicationsCheck := &storage.PreflightCheck{Name: migrationCheckPublications}
+	if len(volume.PublishedNodes) > 0 {
+		publicationsCheck.Findings = []string{fmt.Sprintf("Volume %s is published to nodes %s.",
+			volume.Config.Name, strings.Join(volume.PublishedNodes, ", "))}
+		publicationsCheck.Remediation = "Stop the applications using the volume so it is unpublished."
+	}
+	report.AddCheck(publicationsCheck)
+
+	return plan, nil
+}
@@ -0,0 +1,307 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/storage"
+	"github.com/netapp/trident/storage/fake"
+	sa "github.com/netapp/trident/storage_attribute"
+	storageclass "github.com/netapp/trident/storage_class"
+	fakedriver "github.com/netapp/trident/storage_drivers/fake"
+	tu "github.com/netapp/trident/storage_drivers/fake/test_utils"
+	"github.com/netapp/trident/utils"
+)
+
+// migrationSetup creates two file backends and a volume, and it returns the backend that holds the
+// volume followed by the other backend.
+func migrationSetup(
+	t *testing.T, backendPrefix, scName, volumeName string,
+) (*TridentOrchestrator, *storage.Backend, *storage.Backend) {
+
+	orchestrator := getOrchestrator()
+	addBackendStorageClass(t, orchestrator, backendPrefix+"A", scName, config.File)
+	addBackend(t, orchestrator, backendPrefix+"B", config.File)
+
+	if _, err := orchestrator.AddVolume(ctx(), tu.GenerateVolumeConfig(volumeName, 1, scName, config.File)); err != nil {
+		t.Fatal("Unable to create volume: ", err)
+	}
+
+	backendA, _ := orchestrator.getBackendByBackendName(backendPrefix + "A")
+	backendB, _ := orchestrator.getBackendByBackendName(backendPrefix + "B")
+	if orchestrator.volumes[volumeName].BackendUUID == backendA.BackendUUID {
+		return orchestrator, backendA, backendB
+	}
+	return orchestrator, backendB, backendA
+}
+
+func TestMigrateVolumeValidation(t *testing.T) {
+	const (
+		blockBackendName = "migrationBlock"
+		scName           = "migrationSC"
+		volumeName       = "migrationVolume"
+	)
+
+	orchestrator, sourceBackend, destBackend := migrationSetup(t, "migration", scName, volumeName)
+	sourceBackendName := sourceBackend.Name
+	destBackendName := destBackend.Name
+	addBackend(t, orchestrator, blockBackendName, config.Block)
+
+	volume, err := orchestrator.GetVolume(volumeName)
+	if err != nil {
+		t.Fatal("Unable to get volume: ", err)
+	}
+
+	migrate := func(volume, backend, pool string) error {
+		_, err := orchestrator.MigrateVolume(&storage.VolumeMigrationRequest{
+			Volume: volume, Backend: backend, StoragePool: pool,
+		})
+		return err
+	}
+
+	assert.Error(t, migrate(volumeName, "", ""), "expected an error when the backend is missing")
+	assert.True(t, utils.IsNotFoundError(migrate("missing", destBackendName, "")))
+	assert.True(t, utils.IsNotFoundError(migrate(volumeName, "missing", "")))
+	assert.True(t, utils.IsNotFoundError(migrate(volumeName, destBackendName, "missing")))
+	assert.Error(t, migrate(volumeName, sourceBackendName, ""), "expected an error for the same backend")
+	assert.Error(t, migrate(volumeName, blockBackendName, ""), "expected an error for a different protocol")
+
+	// Fake backends can't replicate, and this host can't mount volumes to copy them
+	assert.Error(t, migrate(volumeName, destBackendName, "primary"))
+
+	// Volumes being migrated can't be changed or migrated again
+	orchestrator.migrations[volumeName] = &storage.VolumeMigration{
+		VolumeName: volumeName,
+		State:      storage.MigrationStateCopying,
+	}
+	assert.True(t, utils.IsFoundError(migrate(volumeName, destBackendName, "primary")))
+	assert.Error(t, orchestrator.DeleteVolume(ctx(), volumeName))
+	assert.Error(t, orchestrator.ResizeVolume(ctx(), volumeName, "2GiB"))
+	_, err = orchestrator.CreateSnapshot(ctx(), &storage.SnapshotConfig{
+		Name: "snap", VolumeName: volumeName, VolumeInternalName: volume.Config.InternalName,
+	})
+	assert.Error(t, err)
+
+	migration, err := orchestrator.GetVolumeMigration(volumeName)
+	assert.NoError(t, err)
+	assert.Equal(t, storage.MigrationStateCopying, migration.State)
+	migrations, err := orchestrator.ListVolumeMigrations()
+	assert.NoError(t, err)
+	assert.Len(t, migrations, 1)
+
+	// Deleting the volume forgets its migrations
+	orchestrator.migrations[volumeName].State = storage.MigrationStateFailed
+	assert.NoError(t, orchestrator.DeleteVolume(ctx(), volumeName))
+	_, err = orchestrator.GetVolumeMigration(volumeName)
+	assert.True(t, utils.IsNotFoundError(err))
+
+	cleanup(t, orchestrator)
+}
+
+func TestMigrateVolumeRecovery(t *testing.T) {
+	const (
+		scName     = "migrationRecoverySC"
+		volumeName = "migrationRecoveryVolume"
+	)
+
+	orchestrator, sourceBackend, destBackend := migrationSetup(t, "migrationRecovery", scName, volumeName)
+	volume := orchestrator.volumes[volumeName]
+
+	newTxn := func() *storage.VolumeTransaction {
+		destConfig := volume.Config.ConstructClone()
+		destConfig.InternalName = volume.Config.InternalName + migrationSuffix
+		return &storage.VolumeTransaction{
+			Config: destConfig,
+			Op:     storage.MigrateVolume,
+			VolumeMigration: &storage.VolumeMigration{
+				VolumeName:              volumeName,
+				SourceBackend:           sourceBackend.Name,
+				SourceBackendUUID:       sourceBackend.BackendUUID,
+				SourceInternalName:      volume.Config.InternalName,
+				DestinationBackend:      destBackend.Name,
+				DestinationBackendUUID:  destBackend.BackendUUID,
+				DestinationInternalName: destConfig.InternalName,
+				Method:                  storage.MigrationMethodHostCopy,
+				State:                   storage.MigrationStateCopying,
+			},
+		}
+	}
+
+	// A migration interrupted before cutover deletes the destination volume
+	txn := newTxn()
+	assert.NoError(t, orchestrator.AddVolumeTransaction(txn))
+	assert.NoError(t, orchestrator.handleFailedTransaction(txn))
+	assert.True(t, destBackend.Driver.(*fakedriver.StorageDriver).DestroyedVolumes[txn.Config.InternalName])
+	assert.False(t, sourceBackend.Driver.(*fakedriver.StorageDriver).DestroyedVolumes[volume.Config.InternalName])
+	migration, err := orchestrator.GetVolumeMigration(volumeName)
+	assert.NoError(t, err)
+	assert.Equal(t, storage.MigrationStateFailed, migration.State)
+	storedTxn, err := orchestrator.GetVolumeTransaction(txn)
+	assert.NoError(t, err)
+	assert.Nil(t, storedTxn)
+
+	// A migration interrupted after cutover deletes the source volume
+	txn = newTxn()
+	assert.NoError(t, orchestrator.AddVolumeTransaction(txn))
+	volume.BackendUUID = destBackend.BackendUUID
+	assert.NoError(t, orchestrator.handleFailedTransaction(txn))
+	assert.True(t, sourceBackend.Driver.(*fakedriver.StorageDriver).DestroyedVolumes[volume.Config.InternalName])
+	migration, err = orchestrator.GetVolumeMigration(volumeName)
+	assert.NoError(t, err)
+	assert.Equal(t, storage.MigrationStateCompleted, migration.State)
+	assert.Equal(t, 100, migration.Progress)
+
+	volume.BackendUUID = sourceBackend.BackendUUID
+	cleanup(t, orchestrator)
+}
+
+func TestMoveVolumeWithinBackend(t *testing.T) {
+	const (
+		backendName = "moveBackend"
+		scName      = "moveSC"
+		volumeName  = "moveVolume"
+	)
+
+	orchestrator := getOrchestrator()
+	defer cleanup(t, orchestrator)
+
+	poolAttrs := map[string]sa.Offer{sa.TestingAttribute: sa.NewBoolOffer(true)}
+	configJSON, err := fakedriver.NewFakeStorageDriverConfigJSON(backendName, config.File,
+		map[string]*fake.StoragePool{
+			"primary":   {Attrs: poolAttrs, Bytes: 100 * 1024 * 1024 * 1024},
+			"secondary": {Attrs: poolAttrs, Bytes: 100 * 1024 * 1024 * 1024},
+		}, []fake.Volume{})
+	if err != nil {
+		t.Fatal("Unable to create mock driver config JSON: ", err)
+	}
+	if _, err = orchestrator.AddBackend(configJSON); err != nil {
+		t.Fatal("Unable to add backend: ", err)
+	}
+	if _, err = orchestrator.AddStorageClass(&storageclass.Config{
+		Name:       scName,
+		Attributes: map[string]sa.Request{sa.TestingAttribute: sa.NewBoolRequest(true)},
+	}); err != nil {
+		t.Fatal("Unable to add storage class: ", err)
+	}
+	volume, err := orchestrator.AddVolume(ctx(), tu.GenerateVolumeConfig(volumeName, 1, scName, config.File))
+	if err != nil {
+		t.Fatal("Unable to create volume: ", err)
+	}
+	destPool := "secondary"
+	if volume.Pool == destPool {
+		destPool = "primary"
+	}
+
+	move := func(pool string) error {
+		_, err := orchestrator.MigrateVolume(&storage.VolumeMigrationRequest{
+			Volume: volumeName, Backend: backendName, StoragePool: pool,
+		})
+		return err
+	}
+
+	assert.Error(t, move(""), "expected an error when no pool is named")
+	assert.Error(t, move(volume.Pool), "expected an error for the volume's own pool")
+	assert.True(t, utils.IsNotFoundError(move("missing")))
+
+	// Snapshots stay with a volume moved within its backend
+	_, err = orchestrator.CreateSnapshot(ctx(), &storage.SnapshotConfig{
+		Name: "snap", VolumeName: volumeName, VolumeInternalName: volume.Config.InternalName,
+	})
+	assert.NoError(t, err)
+
+	assert.NoError(t, move(destPool))
+	assert.Eventually(t, func() bool {
+		migration, err := orchestrator.GetVolumeMigration(volumeName)
+		return err == nil && migration.State.IsDone()
+	}, 5*time.Second, 10*time.Millisecond)
+
+	migration, err := orchestrator.GetVolumeMigration(volumeName)
+	assert.NoError(t, err)
+	assert.Equal(t, storage.MigrationStateCompleted, migration.State)
+	assert.Equal(t, storage.MigrationMethodVolumeMove, migration.Method)
+
+	moved, err := orchestrator.GetVolume(volumeName)
+	assert.NoError(t, err)
+	assert.Equal(t, destPool, moved.Pool)
+	assert.Equal(t, volume.BackendUUID, moved.BackendUUID)
+	driver := orchestrator.backends[volume.BackendUUID].Driver.(*fakedriver.StorageDriver)
+	assert.Equal(t, destPool, driver.Volumes[volume.Config.InternalName].PhysicalPool)
+	assert.False(t, driver.DestroyedVolumes[volume.Config.InternalName], "a moved volume should not be deleted")
+}
+
+func TestPlanVolumeMigration(t *testing.T) {
+	const (
+		blockBackendName = "migrationPlanBlock"
+		scName           = "migrationPlanSC"
+		volumeName       = "migrationPlanVolume"
+	)
+
+	orchestrator, sourceBackend, destBackend := migrationSetup(t, "migrationPlan", scName, volumeName)
+	defer cleanup(t, orchestrator)
+	addBackend(t, orchestrator, blockBackendName, config.Block)
+
+	plan := func(backend, pool string) *storage.VolumeMigrationPlan {
+		plan, err := orchestrator.PlanVolumeMigration(&storage.VolumeMigrationRequest{
+			Volume: volumeName, Backend: backend, StoragePool: pool,
+		})
+		if err != nil {
+			t.Fatal("Unable to plan migration: ", err)
+		}
+		return plan
+	}
+	checkStatus := func(plan *storage.VolumeMigrationPlan, name string) storage.PreflightStatus {
+		for _, check := range plan.Preflight.Checks {
+			if check.Name == name {
+				return check.Status
+			}
+		}
+		return ""
+	}
+
+	_, err := orchestrator.PlanVolumeMigration(&storage.VolumeMigrationRequest{
+		Volume: "missing", Backend: destBackend.Name,
+	})
+	assert.True(t, utils.IsNotFoundError(err))
+
+	// Fake backends can't replicate, so the volume's files must be copied on this host
+	migrationPlan := plan(destBackend.Name, "")
+	assert.False(t, migrationPlan.Preflight.Ready)
+	assert.Equal(t, storage.MigrationMethodHostCopy, migrationPlan.Method)
+	assert.Equal(t, sourceBackend.GetDriverName(), migrationPlan.SourceDriver)
+	assert.Equal(t, storage.PreflightFailed, checkStatus(migrationPlan, migrationCheckMethod))
+
+	orchestrator.hostVolumeAccess = true
+	migrationPlan = plan(destBackend.Name, "")
+	assert.True(t, migrationPlan.Preflight.Ready)
+	assert.NotEmpty(t, migrationPlan.DestinationPool)
+	assert.Equal(t, storage.PreflightWarning, checkStatus(migrationPlan, migrationCheckMethod))
+
+	assert.False(t, plan(destBackend.Name, "missing").Preflight.Ready)
+	assert.Equal(t, storage.PreflightFailed, checkStatus(plan(blockBackendName, ""), migrationCheckBackend))
+	assert.Equal(t, storage.PreflightFailed, checkStatus(plan(sourceBackend.Name, ""), migrationCheckPool))
+
+	// Published volumes can't be migrated to another backend, as their nodes would keep using the source
+	volume := orchestrator.volumes[volumeName]
+	volume.PublishedNodes = []string{"node1"}
+	assert.Equal(t, storage.PreflightFailed, checkStatus(plan(destBackend.Name, ""), migrationCheckPublications))
+	_, err = orchestrator.MigrateVolume(&storage.VolumeMigrationRequest{Volume: volumeName, Backend: destBackend.Name})
+	assert.True(t, utils.IsVolumePublishedError(err))
+	volume.PublishedNodes = nil
+
+	// Nor may a volume be published while it is copied to another backend
+	orchestrator.migrations[volumeName] = &storage.VolumeMigration{
+		VolumeName:         volumeName,
+		DestinationBackend: destBackend.Name,
+		Method:             storage.MigrationMethodHostCopy,
+		State:              storage.MigrationStateCopying,
+	}
+	assert.Error(t, orchestrator.PublishVolume(ctx(), volumeName, &utils.VolumePublishInfo{HostName: "node1"}))
+	assert.False(t, plan(destBackend.Name, "").Preflight.Ready)
+	orchestrator.migrations[volumeName].State = storage.MigrationStateFailed
+	assert.NoError(t, orchestrator.PublishVolume(ctx(), volumeName, &utils.VolumePublishInfo{HostName: "node1"}))
+}
@@ -0,0 +1,79 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netapp/trident/config"
+	sa "github.com/netapp/trident/storage_attribute"
+	storageclass "github.com/netapp/trident/storage_class"
+	tu "github.com/netapp/trident/storage_drivers/fake/test_utils"
+	"github.com/netapp/trident/utils"
+)
+
+func TestPublishVolumeToNodes(t *testing.T) {
+	const scName = "publishSC"
+
+	orchestrator := getOrchestrator()
+	defer cleanup(t, orchestrator)
+	addBackend(t, orchestrator, "publishBackend", config.Block)
+	if _, err := orchestrator.AddStorageClass(&storageclass.Config{
+		Name:       scName,
+		Attributes: map[string]sa.Request{sa.TestingAttribute: sa.NewBoolRequest(true)},
+	}); err != nil {
+		t.Fatal("Unable to add storage class: ", err)
+	}
+	assert.NoError(t, orchestrator.AddNode(&utils.Node{Name: "node1", IQN: "iqn.node1"}))
+	assert.NoError(t, orchestrator.AddNode(&utils.Node{Name: "node2", IQN: "iqn.node2"}))
+
+	// A LUN with a filesystem may only be published to one node at a time
+	fsConfig := tu.GenerateVolumeConfig("fsVolume", 1, scName, config.Block)
+	fsConfig.AccessMode = config.ReadOnlyMany
+	_, err := orchestrator.AddVolume(ctx(), fsConfig)
+	assert.NoError(t, err)
+
+	assert.NoError(t, orchestrator.PublishVolume(ctx(), "fsVolume", &utils.VolumePublishInfo{HostName: "node1"}))
+	assert.NoError(t, orchestrator.PublishVolume(ctx(), "fsVolume", &utils.VolumePublishInfo{HostName: "node1"}))
+	err = orchestrator.PublishVolume(ctx(), "fsVolume", &utils.VolumePublishInfo{HostName: "node2"})
+	assert.True(t, utils.IsVolumePublishedError(err), "second node should not be allowed")
+
+	volume, err := orchestrator.GetVolume("fsVolume")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"node1"}, volume.PublishedNodes)
+
+	// Once unpublished from the first node, the volume may move
+	assert.NoError(t, orchestrator.UnpublishVolume(ctx(), "fsVolume", "node1"))
+	assert.NoError(t, orchestrator.PublishVolume(ctx(), "fsVolume", &utils.VolumePublishInfo{HostName: "node2"}))
+
+	// Shared raw-block volumes may be published to several nodes
+	blockConfig := tu.GenerateVolumeConfig("blockVolume", 1, scName, config.Block)
+	blockConfig.AccessMode = config.ReadWriteMany
+	blockConfig.VolumeMode = config.RawBlock
+	_, err = orchestrator.AddVolume(ctx(), blockConfig)
+	assert.NoError(t, err)
+
+	assert.NoError(t, orchestrator.PublishVolume(ctx(), "blockVolume", &utils.VolumePublishInfo{HostName: "node1"}))
+	assert.NoError(t, orchestrator.PublishVolume(ctx(), "blockVolume", &utils.VolumePublishInfo{HostName: "node2"}))
+
+	persistentVolume, err := orchestrator.storeClient.GetVolume("blockVolume")
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"node1", "node2"}, persistentVolume.PublishedNodes)
+
+	// Force-detaching a node forgets its attachments
+	assert.NoError(t, orchestrator.ForceDetachNode("node1"))
+	volume, err = orchestrator.GetVolume("blockVolume")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"node2"}, volume.PublishedNodes)
+
+	// Unpublishing without a node unpublishes from every node
+	assert.NoError(t, orchestrator.UnpublishVolume(ctx(), "blockVolume", ""))
+	volume, err = orchestrator.GetVolume("blockVolume")
+	assert.NoError(t, err)
+	assert.Empty(t, volume.PublishedNodes)
+
+	err = orchestrator.UnpublishVolume(ctx(), "missing", "node2")
+	assert.True(t, utils.IsNotFoundError(err))
+}
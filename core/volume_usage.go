@@ -0,0 +1,209 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netapp/trident/storage"
+	"github.com/netapp/trident/utils"
+)
+
+const (
+	volumeUsagePeriod = 5 * time.Minute
+	// nodeUsageMaxAge is how long a node's report takes precedence over the driver's, since it
+	// reflects the filesystem as seen by the workload.
+	nodeUsageMaxAge = 2 * volumeUsagePeriod
+)
+
+// UpdateVolumeUsage records the space consumed by a volume, as reported by a node or driver.
+func (o *TridentOrchestrator) UpdateVolumeUsage(usage *storage.VolumeUsage) (err error) {
+	if o.bootstrapError != nil {
+		return o.bootstrapError
+	}
+
+	defer recordTiming("volume_usage_update", &err)()
+
+	if err = usage.Validate(); err != nil {
+		return err
+	}
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	defer o.updateMetrics()
+
+	if _, ok := o.volumes[usage.VolumeName]; !ok {
+		return utils.NotFoundError(fmt.Sprintf("volume %s not found", usage.VolumeName))
+	}
+
+	o.setVolumeUsage(usage)
+
+	// A node that can read the volume's filesystem no longer has a problem with its mount path
+	if usage.Source == "" || usage.Source == storage.VolumeUsageSourceNode {
+		delete(o.volumeConditions[usage.VolumeName], storage.VolumeUsageSourceNode)
+	}
+	return nil
+}
+
+// setVolumeUsage stores a copy of a usage report.  The caller should hold the orchestrator lock.
+func (o *TridentOrchestrator) setVolumeUsage(usage *storage.VolumeUsage) {
+
+	usageCopy := *usage
+	if usageCopy.Source == "" {
+		usageCopy.Source = storage.VolumeUsageSourceNode
+	}
+	if usageCopy.UpdatedTime().IsZero() {
+		usageCopy.Updated = time.Now().UTC().Format(time.RFC3339)
+	}
+	o.volumeUsage[usage.VolumeName] = &usageCopy
+}
+
+// GetVolumeUsage returns the most recently reported space consumed by a volume.
+func (o *TridentOrchestrator) GetVolumeUsage(volumeName string) (usage *storage.VolumeUsage, err error) {
+	if o.bootstrapError != nil {
+		return nil, o.bootstrapError
+	}
+
+	defer recordTiming("volume_usage_get", &err)()
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	if _, ok := o.volumes[volumeName]; !ok {
+		return nil, utils.NotFoundError(fmt.Sprintf("volume %s not found", volumeName))
+	}
+
+	volumeUsage, ok := o.volumeUsage[volumeName]
+	if !ok {
+		return nil, utils.NotFoundError(fmt.Sprintf("usage of volume %s not found", volumeName))
+	}
+
+	usageCopy := *volumeUsage
+	return &usageCopy, nil
+}
+
+// ListVolumeUsage returns the most recently reported space consumed by each volume, sorted by
+// volume name.  Volumes whose usage hasn't been reported are omitted.
+func (o *TridentOrchestrator) ListVolumeUsage() (usage []*storage.VolumeUsage, err error) {
+	if o.bootstrapError != nil {
+		return nil, o.bootstrapError
+	}
+
+	defer recordTiming("volume_usage_list", &err)()
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	usage = make([]*storage.VolumeUsage, 0, len(o.volumeUsage))
+	for _, volumeUsage := range o.volumeUsage {
+		usageCopy := *volumeUsage
+		usage = append(usage, &usageCopy)
+	}
+	sort.Slice(usage, func(i, j int) bool { return usage[i].VolumeName < usage[j].VolumeName })
+
+	return usage, nil
+}
+
+// StartVolumeUsageMonitor starts the thread that collects volume usage from the storage drivers.
+func (o *TridentOrchestrator) StartVolumeUsageMonitor(period time.Duration) {
+
+	o.usageMonitorTicker = time.NewTicker(period)
+	o.usageMonitorChannel = make(chan struct{})
+
+	go func() {
+		log.Debug("Volume usage monitor started.")
+
+		for {
+			select {
+			case tick := <-o.usageMonitorTicker.C:
+				log.WithField("tick", tick).Debug("Volume usage monitor running.")
+				o.collectVolumeUsage()
+				o.collectVolumeConditions()
+			case <-o.usageMonitorChannel:
+				log.Debugf("Volume usage monitor stopped.")
+				return
+			}
+		}
+	}()
+}
+
+// StopVolumeUsageMonitor stops the thread that collects volume usage.
+func (o *TridentOrchestrator) StopVolumeUsageMonitor() {
+	if o.usageMonitorTicker != nil {
+		o.usageMonitorTicker.Stop()
+	}
+	if o.usageMonitorChannel != nil && !o.usageMonitorStopped {
+		close(o.usageMonitorChannel)
+		o.usageMonitorStopped = true
+	}
+	log.Debug("Volume usage monitor stopped.")
+}
+
+// collectVolumeUsage asks the drivers for the space consumed by each volume.  The storage systems
+// are queried without holding the orchestrator lock, so a slow backend doesn't block other operations.
+func (o *TridentOrchestrator) collectVolumeUsage() {
+
+	if o.bootstrapError != nil {
+		log.WithField("error", o.bootstrapError).Errorf("Volume usage monitor blocked by bootstrap error.")
+		return
+	}
+
+	type usageRequest struct {
+		volConfig *storage.VolumeConfig
+		backend   *storage.Backend
+	}
+
+	requests := make([]usageRequest, 0)
+	now := time.Now()
+
+	o.mutex.Lock()
+	for volumeName, volume := range o.volumes {
+		if !volume.State.IsOnline() || o.volumeMigrationInProgress(volumeName) {
+			continue
+		}
+		if usage, ok := o.volumeUsage[volumeName]; ok && usage.Source == storage.VolumeUsageSourceNode &&
+			now.Sub(usage.UpdatedTime()) < nodeUsageMaxAge {
+			continue
+		}
+		backend, ok := o.backends[volume.BackendUUID]
+		if !ok || !backend.State.IsServing() || !backend.CanReportVolumeUsage() {
+			continue
+		}
+		volConfig := *volume.Config
+		requests = append(requests, usageRequest{&volConfig, backend})
+	}
+	o.mutex.Unlock()
+
+	collected := make([]*storage.VolumeUsage, 0, len(requests))
+	for _, request := range requests {
+		usage, err := request.backend.GetVolumeUsage(request.volConfig)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"volume":  request.volConfig.Name,
+				"backend": request.backend.Name,
+				"error":   err,
+			}).Warning("Could not get volume usage.")
+			continue
+		}
+		collected = append(collected, usage)
+	}
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	defer o.updateMetrics()
+
+	for _, usage := range collected {
+		// The volume may have been deleted, or a node may have reported since the driver was queried
+		if _, ok := o.volumes[usage.VolumeName]; !ok {
+			continue
+		}
+		if current, ok := o.volumeUsage[usage.VolumeName]; ok && current.UpdatedTime().After(usage.UpdatedTime()) {
+			continue
+		}
+		o.setVolumeUsage(usage)
+	}
+}
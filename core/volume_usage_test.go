@@ -0,0 +1,91 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/storage"
+	sa "github.com/netapp/trident/storage_attribute"
+	storageclass "github.com/netapp/trident/storage_class"
+	tu "github.com/netapp/trident/storage_drivers/fake/test_utils"
+	"github.com/netapp/trident/utils"
+)
+
+func TestVolumeUsage(t *testing.T) {
+	const (
+		backendName = "usageBackend"
+		scName      = "usageSC"
+		volumeName  = "usageVolume"
+	)
+
+	orchestrator := getOrchestrator()
+	addBackend(t, orchestrator, backendName, config.File)
+
+	if _, err := orchestrator.AddStorageClass(&storageclass.Config{
+		Name:       scName,
+		Attributes: map[string]sa.Request{sa.TestingAttribute: sa.NewBoolRequest(true)},
+	}); err != nil {
+		t.Fatal("Unable to add storage class: ", err)
+	}
+	if _, err := orchestrator.AddVolume(ctx(), tu.GenerateVolumeConfig(volumeName, 1, scName, config.File)); err != nil {
+		t.Fatal("Unable to create volume: ", err)
+	}
+
+	_, err := orchestrator.GetVolumeUsage(volumeName)
+	assert.True(t, utils.IsNotFoundError(err), "expected no usage before collection")
+
+	// The fake driver reports the whole volume as available, even while its backend is cordoned
+	for _, backend := range orchestrator.backends {
+		backend.State = storage.Cordoned
+	}
+	orchestrator.collectVolumeUsage()
+	usage, err := orchestrator.GetVolumeUsage(volumeName)
+	assert.NoError(t, err)
+	assert.Equal(t, storage.VolumeUsageSourceDriver, usage.Source)
+	assert.Equal(t, uint64(1073741824), usage.TotalBytes)
+	assert.Equal(t, usage.TotalBytes, usage.AvailableBytes)
+
+	// A recent node report takes precedence over the driver
+	nodeUsage := &storage.VolumeUsage{
+		VolumeName:     volumeName,
+		TotalBytes:     1000,
+		UsedBytes:      400,
+		AvailableBytes: 600,
+		Node:           "node1",
+	}
+	assert.NoError(t, orchestrator.UpdateVolumeUsage(nodeUsage))
+	orchestrator.collectVolumeUsage()
+	usage, err = orchestrator.GetVolumeUsage(volumeName)
+	assert.NoError(t, err)
+	assert.Equal(t, storage.VolumeUsageSourceNode, usage.Source)
+	assert.Equal(t, uint64(400), usage.UsedBytes)
+	assert.Equal(t, 40, usage.UsedPercent())
+
+	// A stale node report is replaced
+	orchestrator.volumeUsage[volumeName].Updated = time.Now().Add(-2 * nodeUsageMaxAge).UTC().Format(time.RFC3339)
+	orchestrator.collectVolumeUsage()
+	usage, err = orchestrator.GetVolumeUsage(volumeName)
+	assert.NoError(t, err)
+	assert.Equal(t, storage.VolumeUsageSourceDriver, usage.Source)
+
+	assert.Error(t, orchestrator.UpdateVolumeUsage(&storage.VolumeUsage{VolumeName: volumeName, UsedBytes: 1}))
+	err = orchestrator.UpdateVolumeUsage(&storage.VolumeUsage{VolumeName: "missing"})
+	assert.True(t, utils.IsNotFoundError(err), "expected not found error for unknown volume")
+
+	usageList, err := orchestrator.ListVolumeUsage()
+	assert.NoError(t, err)
+	assert.Len(t, usageList, 1)
+
+	// Deleting the volume forgets its usage
+	assert.NoError(t, orchestrator.DeleteVolume(ctx(), volumeName))
+	usageList, err = orchestrator.ListVolumeUsage()
+	assert.NoError(t, err)
+	assert.Empty(t, usageList)
+
+	cleanup(t, orchestrator)
+}
@@ -116,6 +116,9 @@ type TridentCrdController struct {
 
 	// recorder is an event recorder for recording Event resources to the Kubernetes API.
 	recorder record.EventRecorder
+
+	// finalizerPolicy determines when Trident's finalizers are removed from resources being deleted
+	finalizerPolicy FinalizerPolicy
 }
 
 func NewTridentCrdController(o core.Orchestrator, apiServerIP, kubeConfigPath string) (*TridentCrdController, error) {
@@ -223,6 +226,7 @@ func newTridentCrdControllerImpl(
 		snapshotsSynced:       snapshotInformer.Informer().HasSynced,
 		workqueue:             workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "TridentBackends"),
 		recorder:              recorder,
+		finalizerPolicy:       FinalizerPolicyRelease,
 	}
 
 	// Set up event handlers for when our Trident CRDs change
@@ -662,7 +666,7 @@ func (c *TridentCrdController) removeFinalizers(obj interface{}, force bool) {
 
 	switch crd := obj.(type) {
 	case *tridentv1.TridentBackend:
-		if force || !crd.ObjectMeta.DeletionTimestamp.IsZero() {
+		if force || (!crd.ObjectMeta.DeletionTimestamp.IsZero() && c.canReleaseBackend(crd)) {
 			c.removeBackendFinalizers(crd)
 		}
 	case *tridentv1.TridentNode:
@@ -682,7 +686,7 @@ func (c *TridentCrdController) removeFinalizers(obj interface{}, force bool) {
 			c.removeVersionFinalizers(crd)
 		}
 	case *tridentv1.TridentVolume:
-		if force || !crd.ObjectMeta.DeletionTimestamp.IsZero() {
+		if force || (!crd.ObjectMeta.DeletionTimestamp.IsZero() && c.canReleaseVolume(crd)) {
 			c.removeVolumeFinalizers(crd)
 		}
 	case *tridentv1.TridentSnapshot:
@@ -0,0 +1,101 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package crd
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	tridentv1 "github.com/netapp/trident/persistent_store/crd/apis/netapp/v1"
+	"github.com/netapp/trident/utils"
+)
+
+// FinalizerPolicy determines when the CRD controller removes Trident's finalizers from custom
+// resources that are being deleted.
+type FinalizerPolicy string
+
+const (
+	// FinalizerPolicyRelease removes the finalizers from any resource being deleted
+	FinalizerPolicyRelease = FinalizerPolicy("release")
+	// FinalizerPolicyRetain keeps the finalizers of backend and volume resources until Trident no
+	// longer manages their backends and volumes, so deleting a resource out of band doesn't remove it
+	FinalizerPolicyRetain = FinalizerPolicy("retain")
+)
+
+// ParseFinalizerPolicy returns the finalizer policy with the specified name.  The empty name
+// selects the release policy.
+func ParseFinalizerPolicy(name string) (FinalizerPolicy, error) {
+	switch policy := FinalizerPolicy(name); policy {
+	case "":
+		return FinalizerPolicyRelease, nil
+	case FinalizerPolicyRelease, FinalizerPolicyRetain:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("unknown finalizer policy %s; expected %s or %s", name,
+			FinalizerPolicyRelease, FinalizerPolicyRetain)
+	}
+}
+
+// SetFinalizerPolicy determines when the controller removes Trident's finalizers from resources
+// that are being deleted.
+func (c *TridentCrdController) SetFinalizerPolicy(policy FinalizerPolicy) {
+	c.finalizerPolicy = policy
+}
+
+// canReleaseBackend reports whether the finalizers may be removed from a backend resource that is
+// being deleted.  With the retain policy, they are kept while Trident manages the backend.
+func (c *TridentCrdController) canReleaseBackend(backend *tridentv1.TridentBackend) bool {
+
+	if c.finalizerPolicy != FinalizerPolicyRetain {
+		return true
+	}
+
+	managed, err := c.orchestrator.GetBackend(backend.BackendName)
+	if err != nil && !utils.IsNotFoundError(err) {
+		log.WithFields(log.Fields{
+			"backend": backend.BackendName,
+			"error":   err,
+		}).Warning("Could not determine whether Trident manages a deleted backend resource.")
+		return false
+	}
+	if managed != nil && managed.BackendUUID == backend.BackendUUID {
+		log.WithField("backend", backend.BackendName).Debug(
+			"Keeping finalizers of deleted resource of a backend that Trident manages.")
+		return false
+	}
+	return true
+}
+
+// canReleaseVolume reports whether the finalizers may be removed from a volume resource that is
+// being deleted.  With the retain policy, they are kept while Trident manages the volume.
+func (c *TridentCrdController) canReleaseVolume(volume *tridentv1.TridentVolume) bool {
+
+	if c.finalizerPolicy != FinalizerPolicyRetain {
+		return true
+	}
+
+	persistent, err := volume.Persistent()
+	if err != nil {
+		log.WithFields(log.Fields{
+			"resource": volume.Name,
+			"error":    err,
+		}).Warning("Could not read a deleted volume resource.")
+		return false
+	}
+
+	managed, err := c.orchestrator.GetVolume(persistent.Config.Name)
+	if err != nil && !utils.IsNotFoundError(err) {
+		log.WithFields(log.Fields{
+			"volume": persistent.Config.Name,
+			"error":  err,
+		}).Warning("Could not determine whether Trident manages a deleted volume resource.")
+		return false
+	}
+	if managed != nil {
+		log.WithField("volume", persistent.Config.Name).Debug(
+			"Keeping finalizers of deleted resource of a volume that Trident manages.")
+		return false
+	}
+	return true
+}
@@ -0,0 +1,95 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package crd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/core"
+	tridentv1 "github.com/netapp/trident/persistent_store/crd/apis/netapp/v1"
+	"github.com/netapp/trident/storage"
+	storageclass "github.com/netapp/trident/storage_class"
+)
+
+func TestParseFinalizerPolicy(t *testing.T) {
+
+	for name, expected := range map[string]FinalizerPolicy{
+		"":        FinalizerPolicyRelease,
+		"release": FinalizerPolicyRelease,
+		"retain":  FinalizerPolicyRetain,
+	} {
+		policy, err := ParseFinalizerPolicy(name)
+		assert.NoError(t, err, name)
+		assert.Equal(t, expected, policy, name)
+	}
+
+	_, err := ParseFinalizerPolicy("keep")
+	assert.Error(t, err)
+}
+
+func TestFinalizerPolicyRetain(t *testing.T) {
+
+	orchestrator := core.NewMockOrchestrator()
+	crdController, err := newTridentCrdControllerImpl(orchestrator, "trident", GetTestKubernetesClientset(),
+		GetTestCrdClientset())
+	if err != nil {
+		t.Fatalf("cannot create Trident CRD controller frontend, error: %v", err.Error())
+	}
+
+	configJSON, err := newFakeStorageDriverConfigJSON("managed")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	backend, err := orchestrator.AddBackend(configJSON)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if _, err = orchestrator.AddStorageClass(&storageclass.Config{Name: "sc"}); err != nil {
+		t.Fatal(err.Error())
+	}
+	volume, err := orchestrator.AddVolume(context.TODO(), &storage.VolumeConfig{
+		Name:         "managed",
+		Size:         "1GB",
+		StorageClass: "sc",
+		Protocol:     config.File,
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	deleted := metav1.Now()
+	managedBackend := &tridentv1.TridentBackend{
+		ObjectMeta:  metav1.ObjectMeta{Name: "tbe-managed", DeletionTimestamp: &deleted},
+		BackendName: backend.Name,
+		BackendUUID: backend.BackendUUID,
+	}
+	unmanagedBackend := managedBackend.DeepCopy()
+	unmanagedBackend.BackendName = "unmanaged"
+	unmanagedBackend.BackendUUID = "unmanaged"
+
+	managedVolume, err := tridentv1.NewTridentVolume(volume)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	unmanagedVolume, err := tridentv1.NewTridentVolume(&storage.VolumeExternal{
+		Config: &storage.VolumeConfig{Name: "unmanaged"},
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	// The release policy removes the finalizers of any deleted resource
+	assert.True(t, crdController.canReleaseBackend(managedBackend))
+	assert.True(t, crdController.canReleaseVolume(managedVolume))
+
+	crdController.SetFinalizerPolicy(FinalizerPolicyRetain)
+	assert.False(t, crdController.canReleaseBackend(managedBackend))
+	assert.True(t, crdController.canReleaseBackend(unmanagedBackend))
+	assert.False(t, crdController.canReleaseVolume(managedVolume))
+	assert.True(t, crdController.canReleaseVolume(unmanagedVolume))
+}
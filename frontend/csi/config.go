@@ -12,4 +12,10 @@ const (
 	// CSI supported features
 	CSIBlockVolumes  helpers.Feature = "CSI_BLOCK_VOLUMES"
 	ExpandCSIVolumes helpers.Feature = "EXPAND_CSI_VOLUMES"
+
+	// Keys of the CHAP credentials in a volume's node publish secret
+	chapUsernameKey              = "chapUsername"
+	chapInitiatorSecretKey       = "chapInitiatorSecret"
+	chapTargetUsernameKey        = "chapTargetUsername"
+	chapTargetInitiatorSecretKey = "chapTargetInitiatorSecret"
 )
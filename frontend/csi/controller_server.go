@@ -19,6 +19,7 @@ import (
 
 	tridentconfig "github.com/netapp/trident/config"
 	"github.com/netapp/trident/frontend/csi/helpers"
+	"github.com/netapp/trident/logging"
 	"github.com/netapp/trident/storage"
 	"github.com/netapp/trident/utils"
 )
@@ -159,8 +160,17 @@ func (p *Plugin) CreateVolume(
 		return nil, p.getCSIErrorForOrchestratorError(err)
 	}
 
-	// Check if CSI asked for a clone (overrides trident.netapp.io/cloneFromPVC PVC annotation, if present)
+	// Place the volume where the CO will schedule its pods, such as the zone of a pod's node
+	if requirements := req.GetAccessibilityRequirements(); requirements != nil {
+		volConfig.RequisiteTopologies = getTopologySegments(requirements.GetRequisite())
+		volConfig.PreferredTopologies = getTopologySegments(requirements.GetPreferred())
+	}
+
+	// Check if CSI asked for a clone (overrides trident.netapp.io/cloneFromPVC PVC annotation, if present).
+	// CSI doesn't say which namespace a content source is in, so the core checks the namespace recorded
+	// on the source volume, which may differ if the CO allows cross-namespace data sources.
 	if req.VolumeContentSource != nil {
+		volConfig.CloneSourceNamespace = ""
 		switch contentSource := req.VolumeContentSource.Type.(type) {
 
 		case *csi.VolumeContentSource_Volume:
@@ -190,16 +200,28 @@ func (p *Plugin) CreateVolume(
 
 	// Invoke the orchestrator to create or clone the new volume
 	var newVolume *storage.VolumeExternal
+	operation := "CreateVolume"
 	if volConfig.CloneSourceVolume != "" {
-		newVolume, err = p.orchestrator.CloneVolume(volConfig)
+		operation = "CloneVolume"
+		newVolume, err = p.orchestrator.CloneVolume(ctx, volConfig)
 	} else if volConfig.ImportOriginalName != "" {
-		newVolume, err = p.orchestrator.ImportVolume(volConfig)
+		operation = "ImportVolume"
+		newVolume, err = p.orchestrator.ImportVolume(ctx, volConfig)
 	} else {
-		newVolume, err = p.orchestrator.AddVolume(volConfig)
+		newVolume, err = p.orchestrator.AddVolume(ctx, volConfig)
 	}
+	auditCSIOperation(operation, logging.AuditResourceVolume, req.Name, volConfig.Namespace, err)
 
 	if err != nil {
-		p.helper.RecordVolumeEvent(req.Name, helpers.EventTypeNormal, "ProvisioningFailed", err.Error())
+		if utils.IsQuotaExceededError(err) {
+			p.helper.RecordVolumeEvent(req.Name, helpers.EventTypeWarning, "QuotaExceeded", err.Error())
+		} else {
+			p.helper.RecordVolumeEvent(req.Name, helpers.EventTypeNormal, "ProvisioningFailed", err.Error())
+		}
+		for backendName, backendError := range utils.GetVolumeCreateBackendErrors(err) {
+			p.helper.RecordBackendEvent(backendName, helpers.EventTypeWarning, "VolumeCreateFailed",
+				fmt.Sprintf("could not create volume %s: %s", req.Name, backendError))
+		}
 		return nil, p.getCSIErrorForOrchestratorError(err)
 	} else {
 		p.helper.RecordVolumeEvent(req.Name, v1.EventTypeNormal, "ProvisioningSuccess", "provisioned a volume")
@@ -225,7 +247,11 @@ func (p *Plugin) DeleteVolume(
 		return nil, status.Error(codes.InvalidArgument, "no volume ID provided")
 	}
 
-	if err := p.orchestrator.DeleteVolume(req.VolumeId); err != nil {
+	err := p.orchestrator.DeleteVolume(ctx, req.VolumeId)
+	if !utils.IsNotFoundError(err) {
+		auditCSIOperation("DeleteVolume", logging.AuditResourceVolume, req.VolumeId, "", err)
+	}
+	if err != nil {
 
 		log.WithFields(log.Fields{
 			"volumeName": req.VolumeId,
@@ -298,9 +324,25 @@ func (p *Plugin) ControllerPublishVolume(
 		Unmanaged: volume.Config.ImportNotManaged,
 	}
 
+	// Each tenant's volumes may log in with their own CHAP identity, from the storage class's node publish secret
+	if volume.Config.Protocol == tridentconfig.Block {
+		secrets, err := p.helper.GetVolumePublishSecrets(volume.Config.Name)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		if err = setVolumeCHAPCredentials(volumePublishInfo, secrets); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
 	// Update NFS export rules (?), add node IQN to igroup, etc.
-	err = p.orchestrator.PublishVolume(volume.Config.Name, volumePublishInfo)
+	err = p.orchestrator.PublishVolume(ctx, volume.Config.Name, volumePublishInfo)
 	if err != nil {
+		p.helper.RecordVolumeEvent(volume.Config.Name, helpers.EventTypeWarning, "PublishFailed",
+			fmt.Sprintf("could not publish the volume to node %s: %v", nodeID, err))
+		if utils.IsVolumePublishedError(err) {
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
@@ -321,19 +363,29 @@ func (p *Plugin) ControllerPublishVolume(
 	if volume.Config.Protocol == tridentconfig.File {
 		publishInfo["nfsServerIp"] = volume.Config.AccessInfo.NfsServerIP
 		publishInfo["nfsPath"] = volume.Config.AccessInfo.NfsPath
+		if volume.Config.AccessInfo.SMBPath != "" {
+			publishInfo["smbPath"] = volume.Config.AccessInfo.SMBPath
+		}
 	} else if volume.Config.Protocol == tridentconfig.Block {
 		stashIscsiTargetPortals(publishInfo, volumePublishInfo)
 		publishInfo["iscsiTargetIqn"] = volume.Config.AccessInfo.IscsiTargetIQN
 		publishInfo["iscsiLunNumber"] = strconv.Itoa(int(volume.Config.AccessInfo.IscsiLunNumber))
 		publishInfo["iscsiInterface"] = volume.Config.AccessInfo.IscsiInterface
+		publishInfo["iscsiNetworkInterface"] = volumePublishInfo.IscsiNetworkInterface
 		publishInfo["iscsiIgroup"] = volume.Config.AccessInfo.IscsiIgroup
 		publishInfo["iscsiUsername"] = volumePublishInfo.IscsiUsername               //volume.Config.AccessInfo.IscsiUsername
 		publishInfo["iscsiInitiatorSecret"] = volumePublishInfo.IscsiInitiatorSecret //volume.Config.AccessInfo.IscsiInitiatorSecret
 		publishInfo["iscsiTargetUsername"] = volumePublishInfo.IscsiTargetUsername   //volume.Config.AccessInfo.IscsiTargetUsername
 		publishInfo["iscsiTargetSecret"] = volumePublishInfo.IscsiTargetSecret       //volume.Config.AccessInfo.IscsiTargetSecret
 		publishInfo["filesystemType"] = volumePublishInfo.FilesystemType
+		publishInfo["formatOptions"] = volume.Config.FormatOptions
+		publishInfo["fsckPolicy"] = volume.Config.FsckPolicy
+		publishInfo["ioScheduler"] = volume.Config.IOScheduler
+		publishInfo["readAheadKB"] = volume.Config.ReadAheadKB
+		publishInfo["nrRequests"] = volume.Config.NrRequests
 		publishInfo["useCHAP"] = strconv.FormatBool(volumePublishInfo.UseCHAP)
 		publishInfo["sharedTarget"] = strconv.FormatBool(volumePublishInfo.SharedTarget)
+		publishInfo["growFilesystem"] = strconv.FormatBool(volume.Config.GrowFilesystem)
 	}
 
 	return &csi.ControllerPublishVolumeResponse{PublishContext: publishInfo}, nil
@@ -352,12 +404,12 @@ func (p *Plugin) ControllerUnpublishVolume(
 		return nil, status.Error(codes.InvalidArgument, "no volume ID provided")
 	}
 
-	// Check if volume exists.  If not, return success.
-	if _, err := p.orchestrator.GetVolume(volumeID); err != nil && !utils.IsNotFoundError(err) {
+	// Revoke the node's access, if its backend grants access per node.  If the volume doesn't exist, return success.
+	if err := p.orchestrator.UnpublishVolume(ctx, volumeID, req.GetNodeId()); err != nil &&
+		!utils.IsNotFoundError(err) {
 		return nil, p.getCSIErrorForOrchestratorError(err)
 	}
 
-	// Apart from validation, Trident has nothing to do for this entry point
 	return &csi.ControllerUnpublishVolumeResponse{}, nil
 }
 
@@ -432,6 +484,15 @@ func (p *Plugin) ListVolumes(
 		return nil, p.getCSIErrorForOrchestratorError(err)
 	}
 
+	conditionList, err := p.orchestrator.ListVolumeConditions()
+	if err != nil {
+		return nil, p.getCSIErrorForOrchestratorError(err)
+	}
+	conditions := make(map[string]*storage.VolumeCondition, len(conditionList))
+	for _, condition := range conditionList {
+		conditions[condition.VolumeName] = condition
+	}
+
 	entries := make([]*csi.ListVolumesResponse_Entry, 0)
 	maxPageEntries := int(req.MaxEntries)
 	encounteredStartingToken := req.StartingToken == ""
@@ -456,7 +517,13 @@ func (p *Plugin) ListVolumes(
 
 		if len(entries) < maxPageEntries {
 			if csiVolume, err := p.getCSIVolumeFromTridentVolume(volume); err == nil {
-				entries = append(entries, &csi.ListVolumesResponse_Entry{Volume: csiVolume})
+				entries = append(entries, &csi.ListVolumesResponse_Entry{
+					Volume: csiVolume,
+					Status: &csi.ListVolumesResponse_VolumeStatus{
+						PublishedNodeIds: volume.PublishedNodes,
+						VolumeCondition:  getCSIVolumeCondition(conditions[volume.Config.Name]),
+					},
+				})
 			}
 		} else {
 			nextToken = volume.Config.Name
@@ -467,6 +534,60 @@ func (p *Plugin) ListVolumes(
 	return &csi.ListVolumesResponse{Entries: entries, NextToken: nextToken}, nil
 }
 
+// ControllerGetVolume returns a volume along with the nodes it is published to and its condition, as
+// reported by its storage driver and the nodes where it is mounted.
+func (p *Plugin) ControllerGetVolume(
+	ctx context.Context, req *csi.ControllerGetVolumeRequest,
+) (*csi.ControllerGetVolumeResponse, error) {
+
+	fields := log.Fields{"Method": "ControllerGetVolume", "Type": "CSI_Controller"}
+	log.WithFields(fields).Debug(">>>> ControllerGetVolume")
+	defer log.WithFields(fields).Debug("<<<< ControllerGetVolume")
+
+	volumeID := req.GetVolumeId()
+	if volumeID == "" {
+		return nil, status.Error(codes.InvalidArgument, "no volume ID provided")
+	}
+
+	volume, err := p.orchestrator.GetVolume(volumeID)
+	if err != nil {
+		if utils.IsNotFoundError(err) {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+		return nil, p.getCSIErrorForOrchestratorError(err)
+	}
+
+	csiVolume, err := p.getCSIVolumeFromTridentVolume(volume)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	condition, err := p.orchestrator.GetVolumeCondition(volumeID)
+	if err != nil {
+		if utils.IsNotFoundError(err) {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+		return nil, p.getCSIErrorForOrchestratorError(err)
+	}
+
+	return &csi.ControllerGetVolumeResponse{
+		Volume: csiVolume,
+		Status: &csi.ControllerGetVolumeResponse_VolumeStatus{
+			PublishedNodeIds: volume.PublishedNodes,
+			VolumeCondition:  getCSIVolumeCondition(condition),
+		},
+	}, nil
+}
+
+// getCSIVolumeCondition converts a Trident volume condition to a CSI one.  A volume with no reported
+// condition is considered normal.
+func getCSIVolumeCondition(condition *storage.VolumeCondition) *csi.VolumeCondition {
+	if condition == nil || !condition.Abnormal {
+		return &csi.VolumeCondition{Abnormal: false, Message: "volume is healthy"}
+	}
+	return &csi.VolumeCondition{Abnormal: true, Message: condition.Message}
+}
+
 func (p *Plugin) GetCapacity(
 	ctx context.Context, req *csi.GetCapacityRequest,
 ) (*csi.GetCapacityResponse, error) {
@@ -510,8 +631,10 @@ func (p *Plugin) CreateSnapshot(
 		return nil, p.getCSIErrorForOrchestratorError(err)
 	}
 
-	// If pre-existing snapshot found, just return it
-	if existingSnapshot != nil {
+	// If pre-existing snapshot found, just return it, unless the backend failed to create it
+	if existingSnapshot != nil && existingSnapshot.State.IsFailed() {
+		return nil, status.Errorf(codes.Internal, "snapshot %s failed", existingSnapshot.ID())
+	} else if existingSnapshot != nil {
 		if csiSnapshot, err := p.getCSISnapshotFromTridentSnapshot(existingSnapshot); err != nil {
 			return nil, status.Error(codes.Internal, err.Error())
 		} else {
@@ -533,14 +656,15 @@ func (p *Plugin) CreateSnapshot(
 	}
 
 	// Convert snapshot creation options into a Trident snapshot config
-	snapshotConfig, err := p.helper.GetSnapshotConfig(volumeName, snapshotName)
+	snapshotConfig, err := p.helper.GetSnapshotConfig(volumeName, snapshotName, req.GetParameters())
 	if err != nil {
 		p.helper.RecordVolumeEvent(req.Name, helpers.EventTypeNormal, "ProvisioningFailed", err.Error())
-		return nil, p.getCSIErrorForOrchestratorError(err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
 	// Create the snapshot
-	newSnapshot, err := p.orchestrator.CreateSnapshot(snapshotConfig)
+	newSnapshot, err := p.orchestrator.CreateSnapshot(ctx, snapshotConfig)
+	auditCSIOperation("CreateSnapshot", logging.AuditResourceSnapshot, snapshotConfig.ID(), "", err)
 	if err != nil {
 		if utils.IsNotFoundError(err) {
 			return nil, status.Error(codes.NotFound, err.Error())
@@ -576,7 +700,11 @@ func (p *Plugin) DeleteSnapshot(
 	}
 
 	// Delete the snapshot
-	if err = p.orchestrator.DeleteSnapshot(volumeName, snapshotName); err != nil {
+	err = p.orchestrator.DeleteSnapshot(ctx, volumeName, snapshotName)
+	if !utils.IsNotFoundError(err) {
+		auditCSIOperation("DeleteSnapshot", logging.AuditResourceSnapshot, snapshotID, "", err)
+	}
+	if err != nil {
 
 		log.WithFields(log.Fields{
 			"volumeName":   volumeName,
@@ -733,12 +861,20 @@ func (p *Plugin) ControllerExpandVolume(
 		return nil, p.getCSIErrorForOrchestratorError(err)
 	}
 
-	if err = p.orchestrator.ResizeVolume(volume.Config.Name, newSize); err != nil {
+	err = p.orchestrator.ResizeVolume(ctx, volume.Config.Name, newSize)
+	auditCSIOperation("ResizeVolume", logging.AuditResourceVolume, volume.Config.Name, volume.Config.Namespace, err)
+	if err != nil {
 		log.WithFields(log.Fields{
 			"volumeId":          volumeId,
 			"requestedCapacity": newSize,
 			"error":             err,
 		}).Error("Could not resize volume.")
+		reason := "ResizeFailed"
+		if utils.IsQuotaExceededError(err) {
+			reason = "QuotaExceeded"
+		}
+		p.helper.RecordVolumeEvent(volume.Config.Name, helpers.EventTypeWarning, reason,
+			fmt.Sprintf("could not resize the volume to %s bytes: %v", newSize, err))
 		return nil, p.getCSIErrorForOrchestratorError(err)
 	}
 
@@ -762,6 +898,9 @@ func (p *Plugin) ControllerExpandVolume(
 
 	}
 
+	p.helper.RecordVolumeEvent(volume.Config.Name, helpers.EventTypeNormal, "ResizeSuccess",
+		fmt.Sprintf("resized the volume to %s bytes.", resizedVolume.Config.Size))
+
 	response := csi.ControllerExpandVolumeResponse{
 		CapacityBytes:         responseSize,
 		NodeExpansionRequired: nodeExpansionRequired,
@@ -788,13 +927,31 @@ func (p *Plugin) getCSIVolumeFromTridentVolume(volume *storage.VolumeExternal) (
 		"protocol":     string(volume.Config.Protocol),
 	}
 
+	accessibleTopologies := make([]*csi.Topology, 0, len(volume.Config.AllowedTopologies))
+	for _, segments := range volume.Config.AllowedTopologies {
+		accessibleTopologies = append(accessibleTopologies, &csi.Topology{Segments: segments})
+	}
+
 	return &csi.Volume{
-		CapacityBytes: capacity,
-		VolumeId:      volume.Config.Name,
-		VolumeContext: attributes,
+		CapacityBytes:      capacity,
+		VolumeId:           volume.Config.Name,
+		VolumeContext:      attributes,
+		AccessibleTopology: accessibleTopologies,
 	}, nil
 }
 
+// getTopologySegments converts CSI topologies to the topology segments recorded in a volume config.
+func getTopologySegments(topologies []*csi.Topology) []map[string]string {
+	if len(topologies) == 0 {
+		return nil
+	}
+	segments := make([]map[string]string, 0, len(topologies))
+	for _, topology := range topologies {
+		segments = append(segments, topology.GetSegments())
+	}
+	return segments
+}
+
 func (p *Plugin) getCSISnapshotFromTridentSnapshot(snapshot *storage.SnapshotExternal) (*csi.Snapshot, error) {
 
 	createdSeconds, err := time.Parse(time.RFC3339, snapshot.Created)
@@ -808,7 +965,7 @@ func (p *Plugin) getCSISnapshotFromTridentSnapshot(snapshot *storage.SnapshotExt
 		SnapshotId:     storage.MakeSnapshotID(snapshot.Config.VolumeName, snapshot.Config.Name),
 		SourceVolumeId: snapshot.Config.VolumeName,
 		CreationTime:   &timestamp.Timestamp{Seconds: createdSeconds.Unix()},
-		ReadyToUse:     true,
+		ReadyToUse:     !snapshot.State.IsCreating(),
 	}, nil
 }
 
@@ -875,3 +1032,15 @@ func (p *Plugin) hasBackendForProtocol(protocol tridentconfig.Protocol) bool {
 
 	return false
 }
+
+// auditCSIOperation records an audit event for a change requested by the container orchestrator.
+func auditCSIOperation(operation, resource, name, namespace string, err error) {
+	logging.Audit(&logging.AuditEvent{
+		Source:    logging.AuditSourceCSI,
+		Namespace: namespace,
+		Operation: operation,
+		Resource:  resource,
+		Name:      name,
+		Error:     logging.AuditError(err),
+	})
+}
@@ -0,0 +1,304 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package csi
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	tridentconfig "github.com/netapp/trident/config"
+	"github.com/netapp/trident/storage"
+	"github.com/netapp/trident/utils"
+)
+
+const (
+	// Volume context keys set by Kubernetes for CSI inline ephemeral volumes
+	ephemeralContextKey    = "csi.storage.k8s.io/ephemeral"
+	podNameContextKey      = "csi.storage.k8s.io/pod.name"
+	podNamespaceContextKey = "csi.storage.k8s.io/pod.namespace"
+
+	// Volume attributes a pod may set on a CSI inline ephemeral volume
+	ephemeralStorageClassAttribute = "storageClass"
+	ephemeralSizeAttribute         = "size"
+	ephemeralProtocolAttribute     = "protocol"
+	ephemeralFsTypeAttribute       = "fsType"
+	ephemeralMountOptionsAttribute = "mountOptions"
+
+	ephemeralTrackingFilePrefix = "ephemeral-"
+)
+
+// ephemeralVolumeInfo is what a node records about a CSI inline ephemeral volume, so that it can detach and
+// delete the volume when the pod is done with it, even if the node plugin restarts in the meantime.
+type ephemeralVolumeInfo struct {
+	TargetPath   string                   `json:"targetPath"`
+	InternalName string                   `json:"internalName"`
+	PublishInfo  *utils.VolumePublishInfo `json:"publishInfo"`
+	// Detached is set once the volume is no longer attached to the node, leaving only its deletion
+	Detached bool `json:"detached,omitempty"`
+}
+
+// isEphemeralVolumeRequest reports whether Kubernetes asked for a CSI inline ephemeral volume.
+func isEphemeralVolumeRequest(req *csi.NodePublishVolumeRequest) bool {
+	return req.GetVolumeContext()[ephemeralContextKey] == "true"
+}
+
+// getEphemeralVolumeRequest builds the controller request for a CSI inline ephemeral volume from the
+// attributes the pod set on it.
+func (p *Plugin) getEphemeralVolumeRequest(req *csi.NodePublishVolumeRequest) *storage.EphemeralVolumeRequest {
+
+	volumeContext := req.GetVolumeContext()
+
+	fsType := volumeContext[ephemeralFsTypeAttribute]
+	if mount := req.GetVolumeCapability().GetMount(); fsType == "" && mount != nil {
+		fsType = mount.GetFsType()
+	}
+
+	return &storage.EphemeralVolumeRequest{
+		Name:         req.GetVolumeId(),
+		Node:         p.nodeName,
+		StorageClass: volumeContext[ephemeralStorageClassAttribute],
+		Size:         volumeContext[ephemeralSizeAttribute],
+		Protocol:     tridentconfig.Protocol(volumeContext[ephemeralProtocolAttribute]),
+		FileSystem:   fsType,
+		MountOptions: volumeContext[ephemeralMountOptionsAttribute],
+		Pod:          volumeContext[podNameContextKey],
+		Namespace:    volumeContext[podNamespaceContextKey],
+	}
+}
+
+// nodePublishEphemeralVolume provisions a CSI inline ephemeral volume and mounts it at the target path.
+// Kubernetes doesn't stage these volumes or call the controller for them, so the node asks the controller
+// to create and publish the volume directly, and attaches it in a single step.
+func (p *Plugin) nodePublishEphemeralVolume(
+	ctx context.Context, req *csi.NodePublishVolumeRequest,
+) (*csi.NodePublishVolumeResponse, error) {
+
+	volumeId := req.GetVolumeId()
+	if volumeId == "" {
+		return nil, status.Error(codes.InvalidArgument, "no volume ID provided")
+	}
+	targetPath := req.GetTargetPath()
+	if targetPath == "" {
+		return nil, status.Error(codes.InvalidArgument, "no target path provided")
+	}
+	if req.GetVolumeCapability().GetBlock() != nil {
+		return nil, status.Error(codes.InvalidArgument, "ephemeral volumes must be mounted as a filesystem")
+	}
+
+	if err := os.MkdirAll(targetPath, 0750); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	notMnt, err := utils.IsLikelyNotMountPoint(targetPath)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if !notMnt {
+		return &csi.NodePublishVolumeResponse{}, nil
+	}
+
+	request := p.getEphemeralVolumeRequest(req)
+	if err := request.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	fields := log.Fields{"volume": volumeId, "pod": request.Pod, "namespace": request.Namespace}
+	log.WithFields(fields).Debug("Creating ephemeral volume.")
+
+	ephemeralVolume, err := p.restClient.CreateEphemeralVolume(request)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	info := &ephemeralVolumeInfo{
+		TargetPath:   targetPath,
+		InternalName: ephemeralVolume.Volume.Config.InternalName,
+		PublishInfo:  ephemeralVolume.PublishInfo,
+	}
+	if req.GetReadonly() {
+		mountOptions := strings.Split(info.PublishInfo.MountOptions, ",")
+		mountOptions = append(mountOptions, "ro")
+		info.PublishInfo.MountOptions = strings.Join(mountOptions, ",")
+	}
+
+	// Record the volume before attaching it, so it is cleaned up even if attaching fails part way
+	if err := p.writeEphemeralVolumeInfo(volumeId, info); err != nil {
+		p.deleteEphemeralVolume(volumeId, info)
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	switch ephemeralVolume.Volume.Config.Protocol {
+	case tridentconfig.File:
+		err = utils.AttachNFSVolume(info.InternalName, targetPath, info.PublishInfo)
+	case tridentconfig.Block:
+		err = utils.AttachISCSIVolume(info.InternalName, targetPath, info.PublishInfo)
+	default:
+		err = status.Errorf(codes.InvalidArgument, "unknown protocol for ephemeral volume %s", volumeId)
+	}
+	if err != nil {
+		log.WithFields(fields).WithError(err).Error("Could not attach ephemeral volume.")
+		if umountErr := p.unmountEphemeralVolume(targetPath); umountErr != nil {
+			log.WithFields(fields).WithError(umountErr).Warning("Could not unmount ephemeral volume.")
+		}
+		p.detachEphemeralVolume(info)
+		p.deleteEphemeralVolume(volumeId, info)
+		if os.IsPermission(err) {
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	// Keep the device path the attach discovered, so the device can be removed later
+	if err := p.writeEphemeralVolumeInfo(volumeId, info); err != nil {
+		log.WithFields(fields).WithError(err).Warning("Could not update ephemeral volume tracking file.")
+	}
+
+	log.WithFields(fields).Info("Published ephemeral volume.")
+	return &csi.NodePublishVolumeResponse{}, nil
+}
+
+// nodeUnpublishEphemeralVolume unmounts and detaches a CSI inline ephemeral volume whose pod is done with it,
+// and deletes the volume.  Once the volume is detached, the pod may terminate even if the controller can't
+// delete the volume yet; the node keeps retrying the deletion in the background.
+func (p *Plugin) nodeUnpublishEphemeralVolume(
+	ctx context.Context, req *csi.NodeUnpublishVolumeRequest, info *ephemeralVolumeInfo,
+) (*csi.NodeUnpublishVolumeResponse, error) {
+
+	volumeId := req.GetVolumeId()
+
+	if !info.Detached {
+		if err := p.unmountEphemeralVolume(info.TargetPath); err != nil {
+			return nil, status.Errorf(codes.Internal, "unable to unmount ephemeral volume; %s", err)
+		}
+		p.detachEphemeralVolume(info)
+		info.Detached = true
+		if err := p.writeEphemeralVolumeInfo(volumeId, info); err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		if err := utils.DeleteResourceAtPath(info.TargetPath); err != nil {
+			log.Debugf("Unable to delete resource at target path: %s; %s", info.TargetPath, err)
+		}
+	}
+
+	p.deleteEphemeralVolume(volumeId, info)
+
+	return &csi.NodeUnpublishVolumeResponse{}, nil
+}
+
+func (p *Plugin) unmountEphemeralVolume(targetPath string) error {
+
+	notMnt, err := utils.IsLikelyNotMountPoint(targetPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if notMnt {
+		return nil
+	}
+	return utils.Umount(targetPath)
+}
+
+// detachEphemeralVolume removes an ephemeral volume's device, if any, from the node.
+func (p *Plugin) detachEphemeralVolume(info *ephemeralVolumeInfo) {
+	if info.PublishInfo.IscsiTargetIQN != "" {
+		if err := p.detachISCSIDevice(info.PublishInfo); err != nil {
+			log.WithError(err).Warning("Could not detach ephemeral volume.")
+		}
+	}
+}
+
+// deleteEphemeralVolume asks the controller to delete an ephemeral volume.  The tracking file is only
+// removed once the controller has deleted the volume, so that a failed deletion is retried later.
+func (p *Plugin) deleteEphemeralVolume(volumeId string, info *ephemeralVolumeInfo) {
+
+	if err := p.restClient.DeleteEphemeralVolume(volumeId); err != nil {
+		log.WithFields(log.Fields{
+			"volume": volumeId,
+			"error":  err,
+		}).Warning("Could not delete ephemeral volume, will retry.")
+		if !info.Detached {
+			info.Detached = true
+			if err := p.writeEphemeralVolumeInfo(volumeId, info); err != nil {
+				log.WithField("volume", volumeId).WithError(err).Error(
+					"Could not update ephemeral volume tracking file.")
+			}
+		}
+		return
+	}
+
+	if err := p.clearEphemeralVolumeInfo(volumeId); err != nil && !os.IsNotExist(err) {
+		log.WithField("volume", volumeId).WithError(err).Error("Could not remove ephemeral volume tracking file.")
+	}
+}
+
+// deleteDetachedEphemeralVolumes retries the deletion of ephemeral volumes that were detached from the node
+// but that the controller couldn't delete at the time.
+func (p *Plugin) deleteDetachedEphemeralVolumes() {
+
+	files, err := ioutil.ReadDir(tridentDeviceInfoPath)
+	if err != nil {
+		log.WithError(err).Debug("Could not list tracking files.")
+		return
+	}
+
+	for _, file := range files {
+		if !strings.HasPrefix(file.Name(), ephemeralTrackingFilePrefix) || !strings.HasSuffix(file.Name(), ".json") {
+			continue
+		}
+		volumeId := strings.TrimSuffix(strings.TrimPrefix(file.Name(), ephemeralTrackingFilePrefix), ".json")
+		info, err := p.readEphemeralVolumeInfo(volumeId)
+		if err != nil || !info.Detached {
+			continue
+		}
+		p.deleteEphemeralVolume(volumeId, info)
+	}
+}
+
+func ephemeralTrackingFilename(volumeId string) string {
+	return path.Join(tridentDeviceInfoPath, ephemeralTrackingFilePrefix+volumeId+".json")
+}
+
+func (p *Plugin) writeEphemeralVolumeInfo(volumeId string, info *ephemeralVolumeInfo) error {
+
+	infoBytes, err := json.Marshal(info)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(ephemeralTrackingFilename(volumeId), infoBytes, 0600)
+}
+
+// readEphemeralVolumeInfo returns what the node recorded about an ephemeral volume, or a not found error
+// if the volume isn't an ephemeral volume on this node.
+func (p *Plugin) readEphemeralVolumeInfo(volumeId string) (*ephemeralVolumeInfo, error) {
+
+	infoBytes, err := ioutil.ReadFile(ephemeralTrackingFilename(volumeId))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, utils.NotFoundError("ephemeral tracking file not found")
+		}
+		return nil, err
+	}
+
+	info := &ephemeralVolumeInfo{}
+	if err := json.Unmarshal(infoBytes, info); err != nil {
+		return nil, err
+	}
+	if info.PublishInfo == nil {
+		info.PublishInfo = &utils.VolumePublishInfo{}
+	}
+	return info, nil
+}
+
+func (p *Plugin) clearEphemeralVolumeInfo(volumeId string) error {
+	return os.Remove(ephemeralTrackingFilename(volumeId))
+}
@@ -0,0 +1,234 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package csi
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/netapp/trident/logging"
+	"github.com/netapp/trident/storage"
+	"github.com/netapp/trident/utils"
+)
+
+// A CSI group snapshot is a set of same-named Trident snapshots taken together on each of its source
+// volumes, so its ID is that snapshot name, and its members have the usual Trident snapshot IDs.
+
+func (p *Plugin) GroupControllerGetCapabilities(
+	ctx context.Context, req *GroupControllerGetCapabilitiesRequest,
+) (*GroupControllerGetCapabilitiesResponse, error) {
+
+	fields := log.Fields{"Method": "GroupControllerGetCapabilities", "Type": "CSI_GroupController"}
+	log.WithFields(fields).Debug(">>>> GroupControllerGetCapabilities")
+	defer log.WithFields(fields).Debug("<<<< GroupControllerGetCapabilities")
+
+	return &GroupControllerGetCapabilitiesResponse{
+		Capabilities: []*GroupControllerServiceCapability{
+			{
+				Type: &GroupControllerServiceCapability_Rpc{
+					Rpc: &GroupControllerServiceCapability_RPC{
+						Type: GroupControllerServiceCapability_RPC_CREATE_DELETE_GET_VOLUME_GROUP_SNAPSHOT,
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+func (p *Plugin) CreateVolumeGroupSnapshot(
+	ctx context.Context, req *CreateVolumeGroupSnapshotRequest,
+) (*CreateVolumeGroupSnapshotResponse, error) {
+
+	fields := log.Fields{"Method": "CreateVolumeGroupSnapshot", "Type": "CSI_GroupController", "name": req.Name}
+	log.WithFields(fields).Debug(">>>> CreateVolumeGroupSnapshot")
+	defer log.WithFields(fields).Debug("<<<< CreateVolumeGroupSnapshot")
+
+	groupSnapshotName := req.Name
+	if groupSnapshotName == "" {
+		return nil, status.Error(codes.InvalidArgument, "no group snapshot name provided")
+	}
+	if len(req.SourceVolumeIds) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "no source volume IDs provided")
+	}
+
+	// Check for a pre-existing group snapshot with the same name on the same volumes
+	existingSnapshots := make([]*storage.SnapshotExternal, 0, len(req.SourceVolumeIds))
+	for _, volumeName := range req.SourceVolumeIds {
+		snapshot, err := p.orchestrator.GetSnapshot(volumeName, groupSnapshotName)
+		if err != nil && !utils.IsNotFoundError(err) {
+			return nil, p.getCSIErrorForOrchestratorError(err)
+		}
+		if snapshot != nil {
+			existingSnapshots = append(existingSnapshots, snapshot)
+		}
+	}
+
+	// Check for pre-existing snapshots with the same name on other volumes
+	snapshots, err := p.orchestrator.ListSnapshotsByName(groupSnapshotName)
+	if err != nil {
+		return nil, p.getCSIErrorForOrchestratorError(err)
+	}
+
+	// If the whole group snapshot was found, and it has no other members, just return it
+	if len(existingSnapshots) == len(req.SourceVolumeIds) && len(snapshots) == len(existingSnapshots) {
+		return &CreateVolumeGroupSnapshotResponse{
+			GroupSnapshot: p.getCSIGroupSnapshotFromTridentSnapshots(groupSnapshotName, existingSnapshots),
+		}, nil
+	} else if len(snapshots) > 0 {
+		for _, s := range snapshots {
+			log.Debugf("Found existing snapshot %s on volume %s.", s.Config.Name, s.Config.VolumeName)
+		}
+		return nil, status.Error(codes.AlreadyExists, "group snapshot exists with a different set of volumes")
+	}
+
+	// Convert the group snapshot options into a Trident snapshot config for each volume
+	snapshotConfigs := make([]*storage.SnapshotConfig, 0, len(req.SourceVolumeIds))
+	for _, volumeName := range req.SourceVolumeIds {
+		snapshotConfig, err := p.helper.GetSnapshotConfig(volumeName, groupSnapshotName, req.Parameters)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		snapshotConfigs = append(snapshotConfigs, snapshotConfig)
+	}
+
+	// Create the group snapshot
+	groupSnapshot, err := p.orchestrator.CreateGroupSnapshot(ctx, snapshotConfigs)
+	auditCSIOperation("CreateVolumeGroupSnapshot", logging.AuditResourceSnapshot, groupSnapshotName, "", err)
+	if err != nil {
+		if utils.IsNotFoundError(err) {
+			return nil, status.Error(codes.NotFound, err.Error())
+		} else if utils.IsUnsupportedError(err) {
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &CreateVolumeGroupSnapshotResponse{
+		GroupSnapshot: p.getCSIGroupSnapshotFromTridentSnapshots(groupSnapshotName, groupSnapshot.Snapshots),
+	}, nil
+}
+
+func (p *Plugin) DeleteVolumeGroupSnapshot(
+	ctx context.Context, req *DeleteVolumeGroupSnapshotRequest,
+) (*DeleteVolumeGroupSnapshotResponse, error) {
+
+	fields := log.Fields{"Method": "DeleteVolumeGroupSnapshot", "Type": "CSI_GroupController"}
+	log.WithFields(fields).Debug(">>>> DeleteVolumeGroupSnapshot")
+	defer log.WithFields(fields).Debug("<<<< DeleteVolumeGroupSnapshot")
+
+	if req.GroupSnapshotId == "" {
+		return nil, status.Error(codes.InvalidArgument, "no group snapshot ID provided")
+	}
+
+	for _, snapshotID := range req.SnapshotIds {
+
+		volumeName, snapshotName, err := storage.ParseSnapshotID(snapshotID)
+		if err != nil {
+			// An invalid ID is treated an a non-existent snapshot, so we log the error and go on
+			log.Error(err)
+			continue
+		}
+		if snapshotName != req.GroupSnapshotId {
+			return nil, status.Errorf(codes.InvalidArgument, "snapshot %s is not a member of group snapshot %s",
+				snapshotID, req.GroupSnapshotId)
+		}
+
+		// Delete the member snapshot
+		err = p.orchestrator.DeleteSnapshot(ctx, volumeName, snapshotName)
+		if !utils.IsNotFoundError(err) {
+			auditCSIOperation("DeleteVolumeGroupSnapshot", logging.AuditResourceSnapshot, snapshotID, "", err)
+		}
+
+		// In CSI, delete is idempotent, so don't return an error if the snapshot doesn't exist
+		if err != nil && !utils.IsNotFoundError(err) {
+			log.WithFields(log.Fields{
+				"volumeName":   volumeName,
+				"snapshotName": snapshotName,
+				"error":        err,
+			}).Debugf("Could not delete snapshot.")
+			return nil, p.getCSIErrorForOrchestratorError(err)
+		}
+	}
+
+	return &DeleteVolumeGroupSnapshotResponse{}, nil
+}
+
+func (p *Plugin) GetVolumeGroupSnapshot(
+	ctx context.Context, req *GetVolumeGroupSnapshotRequest,
+) (*GetVolumeGroupSnapshotResponse, error) {
+
+	fields := log.Fields{"Method": "GetVolumeGroupSnapshot", "Type": "CSI_GroupController"}
+	log.WithFields(fields).Debug(">>>> GetVolumeGroupSnapshot")
+	defer log.WithFields(fields).Debug("<<<< GetVolumeGroupSnapshot")
+
+	if req.GroupSnapshotId == "" {
+		return nil, status.Error(codes.InvalidArgument, "no group snapshot ID provided")
+	}
+
+	snapshots := make([]*storage.SnapshotExternal, 0, len(req.SnapshotIds))
+	for _, snapshotID := range req.SnapshotIds {
+
+		volumeName, snapshotName, err := storage.ParseSnapshotID(snapshotID)
+		if err != nil || snapshotName != req.GroupSnapshotId {
+			return nil, status.Errorf(codes.NotFound, "snapshot %s of group snapshot %s not found",
+				snapshotID, req.GroupSnapshotId)
+		}
+
+		snapshot, err := p.orchestrator.GetSnapshot(volumeName, snapshotName)
+		if err != nil {
+			return nil, p.getCSIErrorForOrchestratorError(err)
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+
+	return &GetVolumeGroupSnapshotResponse{
+		GroupSnapshot: p.getCSIGroupSnapshotFromTridentSnapshots(req.GroupSnapshotId, snapshots),
+	}, nil
+}
+
+// getCSIGroupSnapshotFromTridentSnapshots returns a CSI group snapshot made of the member snapshots of
+// a Trident group snapshot.  The group snapshot was created when its oldest member was.
+func (p *Plugin) getCSIGroupSnapshotFromTridentSnapshots(
+	groupSnapshotID string, snapshots []*storage.SnapshotExternal,
+) *VolumeGroupSnapshot {
+
+	groupSnapshot := &VolumeGroupSnapshot{
+		GroupSnapshotId: groupSnapshotID,
+		Snapshots:       make([]*GroupMemberSnapshot, 0, len(snapshots)),
+		ReadyToUse:      true,
+	}
+
+	var groupCreated time.Time
+	for _, snapshot := range snapshots {
+
+		created, err := time.Parse(time.RFC3339, snapshot.Created)
+		if err != nil {
+			log.WithField("time", snapshot.Created).Error("Could not parse RFC3339 snapshot time.")
+			created = time.Now()
+		}
+		if groupCreated.IsZero() || created.Before(groupCreated) {
+			groupCreated = created
+		}
+
+		groupSnapshot.Snapshots = append(groupSnapshot.Snapshots, &GroupMemberSnapshot{
+			SizeBytes:       snapshot.SizeBytes,
+			SnapshotId:      storage.MakeSnapshotID(snapshot.Config.VolumeName, snapshot.Config.Name),
+			SourceVolumeId:  snapshot.Config.VolumeName,
+			CreationTime:    &timestamp.Timestamp{Seconds: created.Unix()},
+			ReadyToUse:      true,
+			GroupSnapshotId: groupSnapshotID,
+		})
+	}
+
+	if groupCreated.IsZero() {
+		groupCreated = time.Now()
+	}
+	groupSnapshot.CreationTime = &timestamp.Timestamp{Seconds: groupCreated.Unix()}
+
+	return groupSnapshot
+}
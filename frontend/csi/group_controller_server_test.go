@@ -0,0 +1,367 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package csi
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/core"
+	"github.com/netapp/trident/frontend/csi/helpers"
+	"github.com/netapp/trident/storage"
+	"github.com/netapp/trident/utils"
+)
+
+const snapshotCreated = "2020-09-13T12:26:40Z"
+
+// groupSnapshotOrchestrator keeps the snapshots made by group snapshots, and fails group snapshots
+// of volumes it doesn't know, or of volumes whose backends can't take them.
+type groupSnapshotOrchestrator struct {
+	*core.MockOrchestrator
+	volumes             map[string]bool
+	unsupportedVolumes  map[string]bool
+	snapshots           map[string]*storage.SnapshotExternal
+	createGroupRequests int
+}
+
+func newGroupSnapshotOrchestrator(volumeNames ...string) *groupSnapshotOrchestrator {
+	o := &groupSnapshotOrchestrator{
+		MockOrchestrator:   core.NewMockOrchestrator(),
+		volumes:            make(map[string]bool),
+		unsupportedVolumes: make(map[string]bool),
+		snapshots:          make(map[string]*storage.SnapshotExternal),
+	}
+	for _, volumeName := range volumeNames {
+		o.volumes[volumeName] = true
+	}
+	return o
+}
+
+func (o *groupSnapshotOrchestrator) GetSnapshot(volumeName, snapshotName string) (*storage.SnapshotExternal, error) {
+	if snapshot, ok := o.snapshots[storage.MakeSnapshotID(volumeName, snapshotName)]; ok {
+		return snapshot, nil
+	}
+	return nil, utils.NotFoundError(fmt.Sprintf("snapshot %v was not found", snapshotName))
+}
+
+func (o *groupSnapshotOrchestrator) ListSnapshotsByName(snapshotName string) ([]*storage.SnapshotExternal, error) {
+	snapshots := make([]*storage.SnapshotExternal, 0)
+	for _, snapshot := range o.snapshots {
+		if snapshot.Config.Name == snapshotName {
+			snapshots = append(snapshots, snapshot)
+		}
+	}
+	return snapshots, nil
+}
+
+func (o *groupSnapshotOrchestrator) CreateGroupSnapshot(
+	ctx context.Context, snapConfigs []*storage.SnapshotConfig,
+) (*storage.VolumeGroupSnapshot, error) {
+
+	o.createGroupRequests++
+
+	for _, snapConfig := range snapConfigs {
+		if !o.volumes[snapConfig.VolumeName] {
+			return nil, utils.NotFoundError(fmt.Sprintf("source volume %s not found", snapConfig.VolumeName))
+		}
+		if o.unsupportedVolumes[snapConfig.VolumeName] {
+			return nil, utils.UnsupportedError("group snapshots are not supported by this backend")
+		}
+	}
+
+	groupSnapshot := &storage.VolumeGroupSnapshot{Name: snapConfigs[0].Name}
+	for _, snapConfig := range snapConfigs {
+		snapshot := storage.NewSnapshot(snapConfig, snapshotCreated, 1024).ConstructExternal()
+		o.snapshots[snapshot.ID()] = snapshot
+		groupSnapshot.Snapshots = append(groupSnapshot.Snapshots, snapshot)
+	}
+	return groupSnapshot, nil
+}
+
+func (o *groupSnapshotOrchestrator) DeleteSnapshot(ctx context.Context, volumeName, snapshotName string) error {
+	snapshotID := storage.MakeSnapshotID(volumeName, snapshotName)
+	if _, ok := o.snapshots[snapshotID]; !ok {
+		return utils.NotFoundError(fmt.Sprintf("snapshot %s not found on volume %s", snapshotName, volumeName))
+	}
+	delete(o.snapshots, snapshotID)
+	return nil
+}
+
+// groupSnapshotHelper makes snapshot configs the way the plain CSI helper does.
+type groupSnapshotHelper struct{}
+
+func (h *groupSnapshotHelper) GetVolumeConfig(
+	name string, sizeBytes int64, parameters map[string]string,
+	protocol config.Protocol, accessModes []config.AccessMode, volumeMode config.VolumeMode, fsType string,
+) (*storage.VolumeConfig, error) {
+	return nil, fmt.Errorf("volumes are not supported by the group snapshot helper")
+}
+
+func (h *groupSnapshotHelper) GetSnapshotConfig(
+	volumeName, snapshotName string, parameters map[string]string,
+) (*storage.SnapshotConfig, error) {
+
+	snapshotConfig := &storage.SnapshotConfig{
+		Version:    config.OrchestratorAPIVersion,
+		Name:       snapshotName,
+		VolumeName: volumeName,
+	}
+	if err := snapshotConfig.ApplyParameters(parameters); err != nil {
+		return nil, err
+	}
+	return snapshotConfig, nil
+}
+
+func (h *groupSnapshotHelper) RecordVolumeEvent(name, eventType, reason, message string) {}
+
+func (h *groupSnapshotHelper) RecordBackendEvent(backendName, eventType, reason, message string) {}
+
+func (h *groupSnapshotHelper) GetVolumePublishSecrets(volumeName string) (map[string]string, error) {
+	return nil, nil
+}
+
+func (h *groupSnapshotHelper) SupportsFeature(feature helpers.Feature) bool {
+	return false
+}
+
+func (h *groupSnapshotHelper) Version() string {
+	return "test"
+}
+
+func newGroupSnapshotPlugin(orchestrator core.Orchestrator) *Plugin {
+	return &Plugin{orchestrator: orchestrator, helper: &groupSnapshotHelper{}}
+}
+
+// newGroupControllerClient serves a plugin's GroupController over an in-memory connection, so the
+// requests and responses go through the service descriptor and the protobuf codec.
+func newGroupControllerClient(t *testing.T, p *Plugin) (*grpc.ClientConn, func()) {
+
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	RegisterGroupControllerServer(server, p)
+	go func() { _ = server.Serve(listener) }()
+
+	conn, err := grpc.Dial("bufnet", grpc.WithInsecure(),
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.Dial()
+		}))
+	if err != nil {
+		t.Fatalf("could not connect to the GroupController server; %v", err)
+	}
+
+	return conn, func() {
+		_ = conn.Close()
+		server.Stop()
+	}
+}
+
+func TestGroupControllerGetCapabilities(t *testing.T) {
+
+	conn, stop := newGroupControllerClient(t, newGroupSnapshotPlugin(newGroupSnapshotOrchestrator()))
+	defer stop()
+
+	resp := &GroupControllerGetCapabilitiesResponse{}
+	err := conn.Invoke(context.Background(), "/csi.v1.GroupController/GroupControllerGetCapabilities",
+		&GroupControllerGetCapabilitiesRequest{}, resp)
+
+	assert.NoError(t, err)
+	if assert.Len(t, resp.Capabilities, 1) {
+		assert.Equal(t, GroupControllerServiceCapability_RPC_CREATE_DELETE_GET_VOLUME_GROUP_SNAPSHOT,
+			resp.Capabilities[0].GetRpc().Type)
+	}
+}
+
+func TestGroupControllerLifecycle(t *testing.T) {
+
+	orchestrator := newGroupSnapshotOrchestrator("pvc-1", "pvc-2")
+	conn, stop := newGroupControllerClient(t, newGroupSnapshotPlugin(orchestrator))
+	defer stop()
+	ctx := context.Background()
+
+	// Create the group snapshot
+	createReq := &CreateVolumeGroupSnapshotRequest{Name: "group1", SourceVolumeIds: []string{"pvc-1", "pvc-2"}}
+	createResp := &CreateVolumeGroupSnapshotResponse{}
+	err := conn.Invoke(ctx, "/csi.v1.GroupController/CreateVolumeGroupSnapshot", createReq, createResp)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, orchestrator.createGroupRequests)
+
+	groupSnapshot := createResp.GroupSnapshot
+	if assert.NotNil(t, groupSnapshot) {
+		assert.Equal(t, "group1", groupSnapshot.GroupSnapshotId)
+		assert.True(t, groupSnapshot.ReadyToUse)
+		assert.Equal(t, int64(1600000000), groupSnapshot.CreationTime.Seconds)
+		assert.Len(t, groupSnapshot.Snapshots, 2)
+		for _, member := range groupSnapshot.Snapshots {
+			assert.Equal(t, storage.MakeSnapshotID(member.SourceVolumeId, "group1"), member.SnapshotId)
+			assert.Equal(t, "group1", member.GroupSnapshotId)
+			assert.Equal(t, int64(1024), member.SizeBytes)
+			assert.True(t, member.ReadyToUse)
+		}
+	}
+
+	// Get it back from its member IDs
+	getReq := &GetVolumeGroupSnapshotRequest{
+		GroupSnapshotId: "group1",
+		SnapshotIds:     []string{"pvc-1/group1", "pvc-2/group1"},
+	}
+	getResp := &GetVolumeGroupSnapshotResponse{}
+	err = conn.Invoke(ctx, "/csi.v1.GroupController/GetVolumeGroupSnapshot", getReq, getResp)
+	assert.NoError(t, err)
+	if assert.NotNil(t, getResp.GroupSnapshot) {
+		assert.Equal(t, "group1", getResp.GroupSnapshot.GroupSnapshotId)
+		assert.Len(t, getResp.GroupSnapshot.Snapshots, 2)
+	}
+
+	// Delete it, then delete it again
+	deleteReq := &DeleteVolumeGroupSnapshotRequest{
+		GroupSnapshotId: "group1",
+		SnapshotIds:     []string{"pvc-1/group1", "pvc-2/group1"},
+	}
+	err = conn.Invoke(ctx, "/csi.v1.GroupController/DeleteVolumeGroupSnapshot", deleteReq,
+		&DeleteVolumeGroupSnapshotResponse{})
+	assert.NoError(t, err)
+	assert.Empty(t, orchestrator.snapshots)
+
+	err = conn.Invoke(ctx, "/csi.v1.GroupController/DeleteVolumeGroupSnapshot", deleteReq,
+		&DeleteVolumeGroupSnapshotResponse{})
+	assert.NoError(t, err, "delete should be idempotent")
+
+	// It is gone
+	err = conn.Invoke(ctx, "/csi.v1.GroupController/GetVolumeGroupSnapshot", getReq, getResp)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestCreateVolumeGroupSnapshotRetry(t *testing.T) {
+
+	orchestrator := newGroupSnapshotOrchestrator("pvc-1", "pvc-2")
+	p := newGroupSnapshotPlugin(orchestrator)
+	req := &CreateVolumeGroupSnapshotRequest{Name: "group1", SourceVolumeIds: []string{"pvc-1", "pvc-2"}}
+
+	first, err := p.CreateVolumeGroupSnapshot(context.Background(), req)
+	assert.NoError(t, err)
+
+	second, err := p.CreateVolumeGroupSnapshot(context.Background(), req)
+	assert.NoError(t, err, "a retried create should succeed")
+	assert.Equal(t, 1, orchestrator.createGroupRequests, "a retried create should not take new snapshots")
+	assert.Equal(t, first.GroupSnapshot.GroupSnapshotId, second.GroupSnapshot.GroupSnapshotId)
+	assert.ElementsMatch(t, first.GroupSnapshot.Snapshots, second.GroupSnapshot.Snapshots)
+}
+
+func TestCreateVolumeGroupSnapshotErrors(t *testing.T) {
+
+	tests := []struct {
+		name         string
+		volumeIDs    []string
+		unsupported  []string
+		expectedCode codes.Code
+	}{
+		{"NoName", []string{"pvc-1"}, nil, codes.InvalidArgument},
+		{"NoVolumes", nil, nil, codes.InvalidArgument},
+		{"FewerVolumes", []string{"pvc-1"}, nil, codes.AlreadyExists},
+		{"MoreVolumes", []string{"pvc-1", "pvc-2", "pvc-3"}, nil, codes.AlreadyExists},
+		{"OtherVolumes", []string{"pvc-3"}, nil, codes.AlreadyExists},
+		{"MissingVolume", []string{"pvc-4"}, nil, codes.NotFound},
+		{"UnsupportedBackend", []string{"pvc-4"}, []string{"pvc-4"}, codes.FailedPrecondition},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			// Every case starts with group1 on pvc-1 and pvc-2
+			orchestrator := newGroupSnapshotOrchestrator("pvc-1", "pvc-2", "pvc-3")
+			p := newGroupSnapshotPlugin(orchestrator)
+			_, err := p.CreateVolumeGroupSnapshot(context.Background(), &CreateVolumeGroupSnapshotRequest{
+				Name: "group1", SourceVolumeIds: []string{"pvc-1", "pvc-2"},
+			})
+			assert.NoError(t, err)
+
+			for _, volumeName := range test.unsupported {
+				orchestrator.volumes[volumeName] = true
+				orchestrator.unsupportedVolumes[volumeName] = true
+			}
+
+			name := "group1"
+			if test.expectedCode != codes.AlreadyExists {
+				name = "group2"
+			}
+			if test.name == "NoName" {
+				name = ""
+			}
+
+			_, err = p.CreateVolumeGroupSnapshot(context.Background(), &CreateVolumeGroupSnapshotRequest{
+				Name: name, SourceVolumeIds: test.volumeIDs,
+			})
+			assert.Equal(t, test.expectedCode, status.Code(err), "unexpected error: %v", err)
+			assert.Len(t, orchestrator.snapshots, 2, "a failed create should not leave snapshots")
+		})
+	}
+}
+
+func TestDeleteVolumeGroupSnapshotErrors(t *testing.T) {
+
+	orchestrator := newGroupSnapshotOrchestrator("pvc-1", "pvc-2")
+	p := newGroupSnapshotPlugin(orchestrator)
+	_, err := p.CreateVolumeGroupSnapshot(context.Background(), &CreateVolumeGroupSnapshotRequest{
+		Name: "group1", SourceVolumeIds: []string{"pvc-1", "pvc-2"},
+	})
+	assert.NoError(t, err)
+
+	_, err = p.DeleteVolumeGroupSnapshot(context.Background(), &DeleteVolumeGroupSnapshotRequest{
+		SnapshotIds: []string{"pvc-1/group1"},
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err), "a group snapshot ID is required")
+
+	_, err = p.DeleteVolumeGroupSnapshot(context.Background(), &DeleteVolumeGroupSnapshotRequest{
+		GroupSnapshotId: "group2",
+		SnapshotIds:     []string{"pvc-1/group1"},
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err), "members of other groups should not be deleted")
+	assert.Len(t, orchestrator.snapshots, 2)
+
+	_, err = p.DeleteVolumeGroupSnapshot(context.Background(), &DeleteVolumeGroupSnapshotRequest{
+		GroupSnapshotId: "group1",
+		SnapshotIds:     []string{"not-a-snapshot-id", "pvc-1/group1"},
+	})
+	assert.NoError(t, err, "invalid member IDs should be treated as deleted")
+	assert.Len(t, orchestrator.snapshots, 1)
+}
+
+func TestGetVolumeGroupSnapshotErrors(t *testing.T) {
+
+	orchestrator := newGroupSnapshotOrchestrator("pvc-1", "pvc-2")
+	p := newGroupSnapshotPlugin(orchestrator)
+	_, err := p.CreateVolumeGroupSnapshot(context.Background(), &CreateVolumeGroupSnapshotRequest{
+		Name: "group1", SourceVolumeIds: []string{"pvc-1", "pvc-2"},
+	})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		groupID      string
+		snapshotIDs  []string
+		expectedCode codes.Code
+	}{
+		{"NoGroupID", "", []string{"pvc-1/group1"}, codes.InvalidArgument},
+		{"InvalidMemberID", "group1", []string{"not-a-snapshot-id"}, codes.NotFound},
+		{"ForeignMember", "group2", []string{"pvc-1/group1"}, codes.NotFound},
+		{"MissingMember", "group1", []string{"pvc-1/group1", "pvc-3/group1"}, codes.NotFound},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := p.GetVolumeGroupSnapshot(context.Background(), &GetVolumeGroupSnapshotRequest{
+				GroupSnapshotId: test.groupID, SnapshotIds: test.snapshotIDs,
+			})
+			assert.Equal(t, test.expectedCode, status.Code(err), "unexpected error: %v", err)
+		})
+	}
+}
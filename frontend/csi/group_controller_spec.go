@@ -0,0 +1,247 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package csi
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"google.golang.org/grpc"
+)
+
+/////////////////////////////////////////////////////////////////////////////
+//
+// This file contains the messages and service descriptor of the CSI
+// GroupController service, which the CSI spec added in v1.9.0 as an alpha
+// service.  Trident's CSI spec and gRPC versions predate it, so the messages
+// are declared here with the field numbers of csi.v1 in csi.proto, which is
+// all the protobuf wire format needs.  They can be replaced by the spec's
+// generated code once the spec is upgraded.
+//
+/////////////////////////////////////////////////////////////////////////////
+
+// PluginCapability_Service_GROUP_CONTROLLER_SERVICE is the plugin capability with which a CSI driver
+// reports that it serves the GroupController service.
+const PluginCapability_Service_GROUP_CONTROLLER_SERVICE = 3
+
+type GroupControllerServiceCapability_RPC_Type int32
+
+const (
+	GroupControllerServiceCapability_RPC_UNKNOWN                                 GroupControllerServiceCapability_RPC_Type = 0
+	GroupControllerServiceCapability_RPC_CREATE_DELETE_GET_VOLUME_GROUP_SNAPSHOT GroupControllerServiceCapability_RPC_Type = 1
+)
+
+type GroupControllerGetCapabilitiesRequest struct{}
+
+func (m *GroupControllerGetCapabilitiesRequest) Reset()         { *m = GroupControllerGetCapabilitiesRequest{} }
+func (m *GroupControllerGetCapabilitiesRequest) String() string { return proto.CompactTextString(m) }
+func (*GroupControllerGetCapabilitiesRequest) ProtoMessage()    {}
+
+type GroupControllerGetCapabilitiesResponse struct {
+	Capabilities []*GroupControllerServiceCapability `protobuf:"bytes,1,rep,name=capabilities,proto3"`
+}
+
+func (m *GroupControllerGetCapabilitiesResponse) Reset() {
+	*m = GroupControllerGetCapabilitiesResponse{}
+}
+func (m *GroupControllerGetCapabilitiesResponse) String() string { return proto.CompactTextString(m) }
+func (*GroupControllerGetCapabilitiesResponse) ProtoMessage()    {}
+
+type GroupControllerServiceCapability struct {
+	Type isGroupControllerServiceCapability_Type `protobuf_oneof:"type"`
+}
+
+func (m *GroupControllerServiceCapability) Reset()         { *m = GroupControllerServiceCapability{} }
+func (m *GroupControllerServiceCapability) String() string { return proto.CompactTextString(m) }
+func (*GroupControllerServiceCapability) ProtoMessage()    {}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*GroupControllerServiceCapability) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*GroupControllerServiceCapability_Rpc)(nil),
+	}
+}
+
+func (m *GroupControllerServiceCapability) GetRpc() *GroupControllerServiceCapability_RPC {
+	if x, ok := m.Type.(*GroupControllerServiceCapability_Rpc); ok {
+		return x.Rpc
+	}
+	return nil
+}
+
+type isGroupControllerServiceCapability_Type interface {
+	isGroupControllerServiceCapability_Type()
+}
+
+type GroupControllerServiceCapability_Rpc struct {
+	Rpc *GroupControllerServiceCapability_RPC `protobuf:"bytes,1,opt,name=rpc,proto3,oneof"`
+}
+
+func (*GroupControllerServiceCapability_Rpc) isGroupControllerServiceCapability_Type() {}
+
+type GroupControllerServiceCapability_RPC struct {
+	Type GroupControllerServiceCapability_RPC_Type `protobuf:"varint,1,opt,name=type,proto3"`
+}
+
+func (m *GroupControllerServiceCapability_RPC) Reset()         { *m = GroupControllerServiceCapability_RPC{} }
+func (m *GroupControllerServiceCapability_RPC) String() string { return proto.CompactTextString(m) }
+func (*GroupControllerServiceCapability_RPC) ProtoMessage()    {}
+
+type CreateVolumeGroupSnapshotRequest struct {
+	Name            string            `protobuf:"bytes,1,opt,name=name,proto3"`
+	SourceVolumeIds []string          `protobuf:"bytes,2,rep,name=source_volume_ids,json=sourceVolumeIds,proto3"`
+	Secrets         map[string]string `protobuf:"bytes,3,rep,name=secrets,proto3" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Parameters      map[string]string `protobuf:"bytes,4,rep,name=parameters,proto3" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (m *CreateVolumeGroupSnapshotRequest) Reset()         { *m = CreateVolumeGroupSnapshotRequest{} }
+func (m *CreateVolumeGroupSnapshotRequest) String() string { return proto.CompactTextString(m) }
+func (*CreateVolumeGroupSnapshotRequest) ProtoMessage()    {}
+
+type CreateVolumeGroupSnapshotResponse struct {
+	GroupSnapshot *VolumeGroupSnapshot `protobuf:"bytes,1,opt,name=group_snapshot,json=groupSnapshot,proto3"`
+}
+
+func (m *CreateVolumeGroupSnapshotResponse) Reset()         { *m = CreateVolumeGroupSnapshotResponse{} }
+func (m *CreateVolumeGroupSnapshotResponse) String() string { return proto.CompactTextString(m) }
+func (*CreateVolumeGroupSnapshotResponse) ProtoMessage()    {}
+
+type VolumeGroupSnapshot struct {
+	GroupSnapshotId string                 `protobuf:"bytes,1,opt,name=group_snapshot_id,json=groupSnapshotId,proto3"`
+	Snapshots       []*GroupMemberSnapshot `protobuf:"bytes,2,rep,name=snapshots,proto3"`
+	CreationTime    *timestamp.Timestamp   `protobuf:"bytes,3,opt,name=creation_time,json=creationTime,proto3"`
+	ReadyToUse      bool                   `protobuf:"varint,4,opt,name=ready_to_use,json=readyToUse,proto3"`
+}
+
+func (m *VolumeGroupSnapshot) Reset()         { *m = VolumeGroupSnapshot{} }
+func (m *VolumeGroupSnapshot) String() string { return proto.CompactTextString(m) }
+func (*VolumeGroupSnapshot) ProtoMessage()    {}
+
+// GroupMemberSnapshot is csi.v1.Snapshot as of CSI 1.9, which added the ID of the group snapshot a
+// snapshot belongs to.
+type GroupMemberSnapshot struct {
+	SizeBytes       int64                `protobuf:"varint,1,opt,name=size_bytes,json=sizeBytes,proto3"`
+	SnapshotId      string               `protobuf:"bytes,2,opt,name=snapshot_id,json=snapshotId,proto3"`
+	SourceVolumeId  string               `protobuf:"bytes,3,opt,name=source_volume_id,json=sourceVolumeId,proto3"`
+	CreationTime    *timestamp.Timestamp `protobuf:"bytes,4,opt,name=creation_time,json=creationTime,proto3"`
+	ReadyToUse      bool                 `protobuf:"varint,5,opt,name=ready_to_use,json=readyToUse,proto3"`
+	GroupSnapshotId string               `protobuf:"bytes,6,opt,name=group_snapshot_id,json=groupSnapshotId,proto3"`
+}
+
+func (m *GroupMemberSnapshot) Reset()         { *m = GroupMemberSnapshot{} }
+func (m *GroupMemberSnapshot) String() string { return proto.CompactTextString(m) }
+func (*GroupMemberSnapshot) ProtoMessage()    {}
+
+type DeleteVolumeGroupSnapshotRequest struct {
+	GroupSnapshotId string            `protobuf:"bytes,1,opt,name=group_snapshot_id,json=groupSnapshotId,proto3"`
+	SnapshotIds     []string          `protobuf:"bytes,2,rep,name=snapshot_ids,json=snapshotIds,proto3"`
+	Secrets         map[string]string `protobuf:"bytes,3,rep,name=secrets,proto3" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (m *DeleteVolumeGroupSnapshotRequest) Reset()         { *m = DeleteVolumeGroupSnapshotRequest{} }
+func (m *DeleteVolumeGroupSnapshotRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteVolumeGroupSnapshotRequest) ProtoMessage()    {}
+
+type DeleteVolumeGroupSnapshotResponse struct{}
+
+func (m *DeleteVolumeGroupSnapshotResponse) Reset()         { *m = DeleteVolumeGroupSnapshotResponse{} }
+func (m *DeleteVolumeGroupSnapshotResponse) String() string { return proto.CompactTextString(m) }
+func (*DeleteVolumeGroupSnapshotResponse) ProtoMessage()    {}
+
+type GetVolumeGroupSnapshotRequest struct {
+	GroupSnapshotId string            `protobuf:"bytes,1,opt,name=group_snapshot_id,json=groupSnapshotId,proto3"`
+	SnapshotIds     []string          `protobuf:"bytes,2,rep,name=snapshot_ids,json=snapshotIds,proto3"`
+	Secrets         map[string]string `protobuf:"bytes,3,rep,name=secrets,proto3" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (m *GetVolumeGroupSnapshotRequest) Reset()         { *m = GetVolumeGroupSnapshotRequest{} }
+func (m *GetVolumeGroupSnapshotRequest) String() string { return proto.CompactTextString(m) }
+func (*GetVolumeGroupSnapshotRequest) ProtoMessage()    {}
+
+type GetVolumeGroupSnapshotResponse struct {
+	GroupSnapshot *VolumeGroupSnapshot `protobuf:"bytes,1,opt,name=group_snapshot,json=groupSnapshot,proto3"`
+}
+
+func (m *GetVolumeGroupSnapshotResponse) Reset()         { *m = GetVolumeGroupSnapshotResponse{} }
+func (m *GetVolumeGroupSnapshotResponse) String() string { return proto.CompactTextString(m) }
+func (*GetVolumeGroupSnapshotResponse) ProtoMessage()    {}
+
+// GroupControllerServer is the server API for the CSI GroupController service.
+type GroupControllerServer interface {
+	GroupControllerGetCapabilities(
+		context.Context, *GroupControllerGetCapabilitiesRequest,
+	) (*GroupControllerGetCapabilitiesResponse, error)
+	CreateVolumeGroupSnapshot(
+		context.Context, *CreateVolumeGroupSnapshotRequest,
+	) (*CreateVolumeGroupSnapshotResponse, error)
+	DeleteVolumeGroupSnapshot(
+		context.Context, *DeleteVolumeGroupSnapshotRequest,
+	) (*DeleteVolumeGroupSnapshotResponse, error)
+	GetVolumeGroupSnapshot(context.Context, *GetVolumeGroupSnapshotRequest) (*GetVolumeGroupSnapshotResponse, error)
+}
+
+// RegisterGroupControllerServer registers a GroupController service with a gRPC server.
+func RegisterGroupControllerServer(s *grpc.Server, srv GroupControllerServer) {
+	s.RegisterService(&groupControllerServiceDesc, srv)
+}
+
+var groupControllerServiceDesc = grpc.ServiceDesc{
+	ServiceName: "csi.v1.GroupController",
+	HandlerType: (*GroupControllerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		groupControllerMethod("GroupControllerGetCapabilities",
+			func() interface{} { return new(GroupControllerGetCapabilitiesRequest) },
+			func(srv GroupControllerServer, ctx context.Context, req interface{}) (interface{}, error) {
+				return srv.GroupControllerGetCapabilities(ctx, req.(*GroupControllerGetCapabilitiesRequest))
+			}),
+		groupControllerMethod("CreateVolumeGroupSnapshot",
+			func() interface{} { return new(CreateVolumeGroupSnapshotRequest) },
+			func(srv GroupControllerServer, ctx context.Context, req interface{}) (interface{}, error) {
+				return srv.CreateVolumeGroupSnapshot(ctx, req.(*CreateVolumeGroupSnapshotRequest))
+			}),
+		groupControllerMethod("DeleteVolumeGroupSnapshot",
+			func() interface{} { return new(DeleteVolumeGroupSnapshotRequest) },
+			func(srv GroupControllerServer, ctx context.Context, req interface{}) (interface{}, error) {
+				return srv.DeleteVolumeGroupSnapshot(ctx, req.(*DeleteVolumeGroupSnapshotRequest))
+			}),
+		groupControllerMethod("GetVolumeGroupSnapshot",
+			func() interface{} { return new(GetVolumeGroupSnapshotRequest) },
+			func(srv GroupControllerServer, ctx context.Context, req interface{}) (interface{}, error) {
+				return srv.GetVolumeGroupSnapshot(ctx, req.(*GetVolumeGroupSnapshotRequest))
+			}),
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "csi.proto",
+}
+
+// groupControllerMethod returns the descriptor of a unary GroupController method, which decodes a
+// request created by newRequest and passes it to call through any interceptor.
+func groupControllerMethod(
+	name string, newRequest func() interface{},
+	call func(srv GroupControllerServer, ctx context.Context, req interface{}) (interface{}, error),
+) grpc.MethodDesc {
+
+	return grpc.MethodDesc{
+		MethodName: name,
+		Handler: func(
+			srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor,
+		) (interface{}, error) {
+			in := newRequest()
+			if err := dec(in); err != nil {
+				return nil, err
+			}
+			if interceptor == nil {
+				return call(srv.(GroupControllerServer), ctx, in)
+			}
+			info := &grpc.UnaryServerInfo{
+				Server:     srv,
+				FullMethod: "/csi.v1.GroupController/" + name,
+			}
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				return call(srv.(GroupControllerServer), ctx, req)
+			}
+			return interceptor(ctx, in, info, handler)
+		},
+	}
+}
@@ -0,0 +1,193 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package csi
+
+import (
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/stretchr/testify/assert"
+)
+
+// The expected encodings below are built by hand from the field numbers and types of the messages in
+// csi.proto (CSI spec v1.9.0), so they check the hand-written messages against the spec rather than
+// against themselves.
+
+const (
+	wireVarint = 0
+	wireBytes  = 2
+)
+
+// wireMessage builds the protobuf encoding of a message one field at a time.
+type wireMessage struct {
+	buffer proto.Buffer
+}
+
+func (w *wireMessage) key(field, wireType uint64) {
+	_ = w.buffer.EncodeVarint(field<<3 | wireType)
+}
+
+func (w *wireMessage) varint(field, value uint64) *wireMessage {
+	w.key(field, wireVarint)
+	_ = w.buffer.EncodeVarint(value)
+	return w
+}
+
+func (w *wireMessage) string(field uint64, value string) *wireMessage {
+	w.key(field, wireBytes)
+	_ = w.buffer.EncodeStringBytes(value)
+	return w
+}
+
+func (w *wireMessage) message(field uint64, value *wireMessage) *wireMessage {
+	w.key(field, wireBytes)
+	_ = w.buffer.EncodeRawBytes(value.bytes())
+	return w
+}
+
+func (w *wireMessage) mapEntry(field uint64, key, value string) *wireMessage {
+	return w.message(field, new(wireMessage).string(1, key).string(2, value))
+}
+
+func (w *wireMessage) bytes() []byte {
+	return w.buffer.Bytes()
+}
+
+// assertWireCompatible checks that a message decodes from the spec's encoding, and that it encodes to
+// something that decodes back to the same message.
+func assertWireCompatible(t *testing.T, specEncoding *wireMessage, expected, decoded proto.Message) {
+
+	assert.NoError(t, proto.Unmarshal(specEncoding.bytes(), decoded), "message should decode")
+	assert.True(t, proto.Equal(expected, decoded), "decoded %v, expected %v", decoded, expected)
+
+	encoded, err := proto.Marshal(expected)
+	assert.NoError(t, err, "message should encode")
+	decoded.Reset()
+	assert.NoError(t, proto.Unmarshal(encoded, decoded), "message should decode")
+	assert.True(t, proto.Equal(expected, decoded), "round trip gave %v, expected %v", decoded, expected)
+}
+
+func TestGroupControllerCapabilitiesWireFormat(t *testing.T) {
+
+	// message GroupControllerServiceCapability { message RPC { Type type = 1; } oneof type { RPC rpc = 1; } }
+	specEncoding := new(wireMessage).message(1,
+		new(wireMessage).message(1, new(wireMessage).varint(1, 1)))
+
+	expected := &GroupControllerGetCapabilitiesResponse{
+		Capabilities: []*GroupControllerServiceCapability{
+			{
+				Type: &GroupControllerServiceCapability_Rpc{
+					Rpc: &GroupControllerServiceCapability_RPC{
+						Type: GroupControllerServiceCapability_RPC_CREATE_DELETE_GET_VOLUME_GROUP_SNAPSHOT,
+					},
+				},
+			},
+		},
+	}
+	assertWireCompatible(t, specEncoding, expected, &GroupControllerGetCapabilitiesResponse{})
+
+	// PluginCapability.Service.Type GROUP_CONTROLLER_SERVICE = 3
+	pluginCapability := &csi.PluginCapability_Service{Type: PluginCapability_Service_GROUP_CONTROLLER_SERVICE}
+	encoded, err := proto.Marshal(pluginCapability)
+	assert.NoError(t, err)
+	assert.Equal(t, new(wireMessage).varint(1, 3).bytes(), encoded)
+}
+
+func TestCreateVolumeGroupSnapshotWireFormat(t *testing.T) {
+
+	specEncoding := new(wireMessage).
+		string(1, "group1").
+		string(2, "pvc-1").
+		string(2, "pvc-2").
+		mapEntry(3, "user", "admin").
+		mapEntry(4, "snapshotReserve", "10")
+
+	expected := &CreateVolumeGroupSnapshotRequest{
+		Name:            "group1",
+		SourceVolumeIds: []string{"pvc-1", "pvc-2"},
+		Secrets:         map[string]string{"user": "admin"},
+		Parameters:      map[string]string{"snapshotReserve": "10"},
+	}
+	assertWireCompatible(t, specEncoding, expected, &CreateVolumeGroupSnapshotRequest{})
+}
+
+func TestVolumeGroupSnapshotWireFormat(t *testing.T) {
+
+	// message Timestamp { int64 seconds = 1; int32 nanos = 2; }
+	created := new(wireMessage).varint(1, 1600000000)
+
+	// message Snapshot { int64 size_bytes = 1; string snapshot_id = 2; string source_volume_id = 3;
+	//   Timestamp creation_time = 4; bool ready_to_use = 5; string group_snapshot_id = 6; }
+	member := new(wireMessage).
+		varint(1, 1024).
+		string(2, "pvc-1/group1").
+		string(3, "pvc-1").
+		message(4, created).
+		varint(5, 1).
+		string(6, "group1")
+
+	// message VolumeGroupSnapshot { string group_snapshot_id = 1; repeated Snapshot snapshots = 2;
+	//   Timestamp creation_time = 3; bool ready_to_use = 4; }
+	groupSnapshot := new(wireMessage).
+		string(1, "group1").
+		message(2, member).
+		message(3, created).
+		varint(4, 1)
+
+	expectedMember := &GroupMemberSnapshot{
+		SizeBytes:       1024,
+		SnapshotId:      "pvc-1/group1",
+		SourceVolumeId:  "pvc-1",
+		CreationTime:    &timestamp.Timestamp{Seconds: 1600000000},
+		ReadyToUse:      true,
+		GroupSnapshotId: "group1",
+	}
+	expectedGroupSnapshot := &VolumeGroupSnapshot{
+		GroupSnapshotId: "group1",
+		Snapshots:       []*GroupMemberSnapshot{expectedMember},
+		CreationTime:    &timestamp.Timestamp{Seconds: 1600000000},
+		ReadyToUse:      true,
+	}
+
+	// Create and Get return the group snapshot as field 1
+	assertWireCompatible(t, new(wireMessage).message(1, groupSnapshot),
+		&CreateVolumeGroupSnapshotResponse{GroupSnapshot: expectedGroupSnapshot},
+		&CreateVolumeGroupSnapshotResponse{})
+	assertWireCompatible(t, new(wireMessage).message(1, groupSnapshot),
+		&GetVolumeGroupSnapshotResponse{GroupSnapshot: expectedGroupSnapshot},
+		&GetVolumeGroupSnapshotResponse{})
+
+	// A member is a csi.v1.Snapshot, which older CSI clients read without the group snapshot ID
+	snapshot := &csi.Snapshot{}
+	assert.NoError(t, proto.Unmarshal(member.bytes(), snapshot))
+	assert.Equal(t, int64(1024), snapshot.SizeBytes)
+	assert.Equal(t, "pvc-1/group1", snapshot.SnapshotId)
+	assert.Equal(t, "pvc-1", snapshot.SourceVolumeId)
+	assert.Equal(t, int64(1600000000), snapshot.CreationTime.Seconds)
+	assert.True(t, snapshot.ReadyToUse)
+}
+
+func TestDeleteAndGetVolumeGroupSnapshotWireFormat(t *testing.T) {
+
+	// Delete and Get requests share their layout: string group_snapshot_id = 1;
+	// repeated string snapshot_ids = 2; map<string, string> secrets = 3;
+	specEncoding := new(wireMessage).
+		string(1, "group1").
+		string(2, "pvc-1/group1").
+		string(2, "pvc-2/group1").
+		mapEntry(3, "user", "admin")
+
+	assertWireCompatible(t, specEncoding, &DeleteVolumeGroupSnapshotRequest{
+		GroupSnapshotId: "group1",
+		SnapshotIds:     []string{"pvc-1/group1", "pvc-2/group1"},
+		Secrets:         map[string]string{"user": "admin"},
+	}, &DeleteVolumeGroupSnapshotRequest{})
+
+	assertWireCompatible(t, specEncoding, &GetVolumeGroupSnapshotRequest{
+		GroupSnapshotId: "group1",
+		SnapshotIds:     []string{"pvc-1/group1", "pvc-2/group1"},
+		Secrets:         map[string]string{"user": "admin"},
+	}, &GetVolumeGroupSnapshotRequest{})
+}
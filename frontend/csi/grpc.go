@@ -87,6 +87,10 @@ func (s *nonBlockingGRPCServer) serve(endpoint string, ids csi.IdentityServer, c
 	if cs != nil {
 		csi.RegisterControllerServer(server, cs)
 		log.Debug("Registered CSI controller server.")
+		if gcs, ok := cs.(GroupControllerServer); ok {
+			RegisterGroupControllerServer(server, gcs)
+			log.Debug("Registered CSI group controller server.")
+		}
 	}
 	if ns != nil {
 		csi.RegisterNodeServer(server, ns)
@@ -0,0 +1,229 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package kubernetes
+
+import (
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/netapp/trident/frontend/csi"
+	"github.com/netapp/trident/frontend/csi/helpers"
+	"github.com/netapp/trident/logging"
+	"github.com/netapp/trident/storage"
+	"github.com/netapp/trident/utils"
+)
+
+/////////////////////////////////////////////////////////////////////////////
+//
+// This file contains the controller that reconciles the nodes Trident has
+// published its volumes to with the cluster's VolumeAttachments.  A
+// publication without an attachment, as left by a missed unpublish call, is
+// unpublished so that the node's igroup or export policy access is revoked.
+// An attachment to a node that no longer exists, as left by a crashed node
+// that was removed from the cluster, is deleted so that the CSI attacher
+// detaches the volume.  An inline ephemeral volume whose pod or node no
+// longer exists, as left by a node that never unpublished it, is deleted.
+//
+/////////////////////////////////////////////////////////////////////////////
+
+const staleAttachmentReason = "StaleAttachmentRemoved"
+
+// runAttachmentReconciliation periodically reconciles volume publications with volume attachments,
+// until the stop channel is closed.
+func (p *Plugin) runAttachmentReconciliation() {
+
+	ticker := time.NewTicker(AttachmentPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.attachmentStopChan:
+			return
+		case <-ticker.C:
+			p.reconcileAttachments()
+		}
+	}
+}
+
+// reconcileAttachments unpublishes each Trident volume from the nodes that have no volume attachment
+// for it, and deletes Trident volume attachments to nodes that are no longer in the cluster.
+func (p *Plugin) reconcileAttachments() {
+
+	// Volumes are listed before attachments, so a volume published during this pass was attached first
+	volumes, err := p.orchestrator.ListVolumes()
+	if err != nil {
+		log.WithField("error", err).Debug("K8S helper could not list volumes.")
+		return
+	}
+
+	attachments, err := p.kubeClient.StorageV1().VolumeAttachments().List(ctx(), metav1.ListOptions{})
+	if err != nil {
+		log.WithField("error", err).Debug("K8S helper could not list volume attachments.")
+		return
+	}
+
+	nodes, err := p.kubeClient.CoreV1().Nodes().List(ctx(), metav1.ListOptions{})
+	if err != nil {
+		log.WithField("error", err).Debug("K8S helper could not list nodes.")
+		return
+	}
+	nodeExists := make(map[string]bool, len(nodes.Items))
+	for _, node := range nodes.Items {
+		nodeExists[node.Name] = true
+	}
+
+	// Attachments being deleted still count, since the CSI attacher is about to unpublish them
+	attached := make(map[string]map[string]bool)
+	for _, attachment := range attachments.Items {
+		pvName := attachment.Spec.Source.PersistentVolumeName
+		if attachment.Spec.Attacher != csi.Provisioner || pvName == nil {
+			continue
+		}
+		if attached[*pvName] == nil {
+			attached[*pvName] = make(map[string]bool)
+		}
+		attached[*pvName][attachment.Spec.NodeName] = true
+
+		if !nodeExists[attachment.Spec.NodeName] && attachment.DeletionTimestamp == nil {
+			p.deleteStaleAttachment(attachment.Name, *pvName, attachment.Spec.NodeName)
+		}
+	}
+
+	for _, volume := range volumes {
+		// Inline ephemeral volumes are published by their node without an attachment
+		if volume.Config.EphemeralNode != "" {
+			if volume.State.IsDeleting() {
+				continue
+			}
+			if !nodeExists[volume.Config.EphemeralNode] || !p.ephemeralPodExists(volume) {
+				p.deleteStaleEphemeralVolume(volume)
+			}
+			continue
+		}
+		for _, nodeName := range volume.PublishedNodes {
+			if !attached[volume.Config.Name][nodeName] {
+				p.unpublishStaleAttachment(volume.Config.Name, nodeName)
+			}
+		}
+	}
+}
+
+// ephemeralPodExists reports whether the pod an inline ephemeral volume was created for still runs on
+// the volume's node.  A pod of the same name on another node is a new pod, which has its own volume.
+func (p *Plugin) ephemeralPodExists(volume *storage.VolumeExternal) bool {
+
+	// Volumes created before their pods were recorded are only deleted along with their nodes
+	if volume.Config.EphemeralPod == "" {
+		return true
+	}
+
+	pod, err := p.kubeClient.CoreV1().Pods(volume.Config.Namespace).Get(
+		ctx(), volume.Config.EphemeralPod, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return false
+	} else if err != nil {
+		log.WithFields(log.Fields{
+			"volume": volume.Config.Name,
+			"pod":    volume.Config.EphemeralPod,
+			"error":  err,
+		}).Debug("K8S helper could not get the pod of an ephemeral volume.")
+		return true
+	}
+	return pod.Spec.NodeName == volume.Config.EphemeralNode
+}
+
+// deleteStaleEphemeralVolume unpublishes and deletes an inline ephemeral volume whose pod or node is
+// gone.  The volume's node would have deleted it when the pod was done with it, but couldn't.
+func (p *Plugin) deleteStaleEphemeralVolume(volume *storage.VolumeExternal) {
+
+	volumeName := volume.Config.Name
+	logFields := log.Fields{
+		"volume":    volumeName,
+		"node":      volume.Config.EphemeralNode,
+		"pod":       volume.Config.EphemeralPod,
+		"namespace": volume.Config.Namespace,
+	}
+
+	var err error
+	for _, nodeName := range volume.PublishedNodes {
+		if err = p.orchestrator.UnpublishVolume(ctx(), volumeName, nodeName); err != nil {
+			break
+		}
+	}
+	if err == nil {
+		err = p.orchestrator.DeleteVolume(ctx(), volumeName)
+	}
+
+	logging.Audit(&logging.AuditEvent{
+		Source:    logging.AuditSourceCSI,
+		Operation: "DeleteStaleEphemeralVolume",
+		Resource:  logging.AuditResourceVolume,
+		Name:      volumeName,
+		Error:     logging.AuditError(err),
+	})
+
+	if err != nil && !utils.IsNotFoundError(err) {
+		log.WithFields(logFields).WithField("error", err).Error(
+			"K8S helper could not delete ephemeral volume whose pod is gone.")
+		return
+	}
+
+	log.WithFields(logFields).Warning("K8S helper deleted ephemeral volume whose pod is gone.")
+}
+
+// deleteStaleAttachment deletes a volume attachment to a node that is no longer in the cluster.
+func (p *Plugin) deleteStaleAttachment(attachmentName, volumeName, nodeName string) {
+
+	logFields := log.Fields{
+		"attachment": attachmentName,
+		"volume":     volumeName,
+		"node":       nodeName,
+	}
+
+	err := p.kubeClient.StorageV1().VolumeAttachments().Delete(ctx(), attachmentName, metav1.DeleteOptions{})
+	if apierrors.IsNotFound(err) {
+		return
+	}
+	if err != nil {
+		log.WithFields(logFields).WithField("error", err).Error(
+			"K8S helper could not delete volume attachment to missing node.")
+		return
+	}
+
+	log.WithFields(logFields).Warning("K8S helper deleted volume attachment to missing node.")
+	p.RecordVolumeEvent(volumeName, helpers.EventTypeWarning, staleAttachmentReason,
+		fmt.Sprintf("deleted attachment to node %s, which is no longer in the cluster", nodeName))
+}
+
+// unpublishStaleAttachment unpublishes a volume from a node that has no volume attachment for it.
+func (p *Plugin) unpublishStaleAttachment(volumeName, nodeName string) {
+
+	logFields := log.Fields{
+		"volume": volumeName,
+		"node":   nodeName,
+	}
+
+	err := p.orchestrator.UnpublishVolume(ctx(), volumeName, nodeName)
+
+	logging.Audit(&logging.AuditEvent{
+		Source:    logging.AuditSourceCSI,
+		Operation: "UnpublishStaleAttachment",
+		Resource:  logging.AuditResourceVolume,
+		Name:      volumeName,
+		Error:     logging.AuditError(err),
+	})
+
+	if err != nil {
+		log.WithFields(logFields).WithField("error", err).Error(
+			"K8S helper could not unpublish volume without attachment.")
+		return
+	}
+
+	log.WithFields(logFields).Warning("K8S helper unpublished volume without attachment.")
+	p.RecordVolumeEvent(volumeName, helpers.EventTypeWarning, staleAttachmentReason,
+		fmt.Sprintf("unpublished from node %s, which has no attachment for the volume", nodeName))
+}
@@ -0,0 +1,142 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package kubernetes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/core"
+	"github.com/netapp/trident/frontend/csi"
+	"github.com/netapp/trident/storage"
+	storageclass "github.com/netapp/trident/storage_class"
+	"github.com/netapp/trident/utils"
+)
+
+func TestReconcileAttachments(t *testing.T) {
+
+	orchestrator := core.NewMockOrchestrator()
+	orchestrator.AddMockONTAPNFSBackend("nas", "127.0.0.1")
+	_, _ = orchestrator.AddStorageClass(&storageclass.Config{Name: "gold"})
+	for _, name := range []string{"pv-attached", "pv-stale", "pv-ephemeral"} {
+		volumeConfig := &storage.VolumeConfig{Name: name, StorageClass: "gold", Protocol: config.File}
+		if name == "pv-ephemeral" {
+			volumeConfig.EphemeralNode = "node1"
+		}
+		_, err := orchestrator.AddVolume(context.Background(), volumeConfig)
+		assert.NoError(t, err)
+		assert.NoError(t, orchestrator.PublishVolume(context.Background(), name,
+			&utils.VolumePublishInfo{HostName: "node1"}))
+	}
+
+	newAttachment := func(name, attacher, nodeName, pvName string) *storagev1.VolumeAttachment {
+		return &storagev1.VolumeAttachment{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: storagev1.VolumeAttachmentSpec{
+				Attacher: attacher,
+				NodeName: nodeName,
+				Source:   storagev1.VolumeAttachmentSource{PersistentVolumeName: &pvName},
+			},
+		}
+	}
+
+	p := &Plugin{
+		orchestrator:  orchestrator,
+		eventRecorder: record.NewFakeRecorder(10),
+		pvIndexer:     cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{}),
+		pvcIndexer:    cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{uidIndex: MetaUIDKeyFunc}),
+		kubeClient: k8sfake.NewSimpleClientset(
+			&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1"}},
+			newAttachment("csi-1", csi.Provisioner, "node1", "pv-attached"),
+			newAttachment("csi-2", csi.Provisioner, "removed", "pv-attached"),
+			newAttachment("csi-3", "other.csi.driver", "removed", "pv-other"),
+		),
+	}
+
+	p.reconcileAttachments()
+
+	// A publication without an attachment is unpublished, unless the volume is an inline ephemeral one
+	volumes, err := orchestrator.ListVolumes()
+	assert.NoError(t, err)
+	published := make(map[string][]string)
+	for _, volume := range volumes {
+		published[volume.Config.Name] = volume.PublishedNodes
+	}
+	assert.Equal(t, []string{"node1"}, published["pv-attached"])
+	assert.Empty(t, published["pv-stale"])
+	assert.Equal(t, []string{"node1"}, published["pv-ephemeral"])
+
+	// Only Trident's attachments to nodes that are gone are deleted
+	attachments, err := p.kubeClient.StorageV1().VolumeAttachments().List(ctx(), metav1.ListOptions{})
+	assert.NoError(t, err)
+	remaining := make([]string, 0)
+	for _, attachment := range attachments.Items {
+		remaining = append(remaining, attachment.Name)
+	}
+	assert.ElementsMatch(t, []string{"csi-1", "csi-3"}, remaining)
+}
+
+func TestReconcileEphemeralVolumes(t *testing.T) {
+
+	orchestrator := core.NewMockOrchestrator()
+	orchestrator.AddMockONTAPNFSBackend("nas", "127.0.0.1")
+	_, _ = orchestrator.AddStorageClass(&storageclass.Config{Name: "gold"})
+	for name, pod := range map[string]string{
+		"eph-running": "running",
+		"eph-moved":   "moved",
+		"eph-deleted": "deleted",
+		"eph-unknown": "",
+	} {
+		volumeConfig := &storage.VolumeConfig{
+			Name:          name,
+			StorageClass:  "gold",
+			Protocol:      config.File,
+			Namespace:     "tenant1",
+			EphemeralNode: "node1",
+			EphemeralPod:  pod,
+		}
+		_, err := orchestrator.AddVolume(context.Background(), volumeConfig)
+		assert.NoError(t, err)
+		assert.NoError(t, orchestrator.PublishVolume(context.Background(), name,
+			&utils.VolumePublishInfo{HostName: "node1"}))
+	}
+
+	newPod := func(name, nodeName string) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "tenant1"},
+			Spec:       v1.PodSpec{NodeName: nodeName},
+		}
+	}
+
+	p := &Plugin{
+		orchestrator:  orchestrator,
+		eventRecorder: record.NewFakeRecorder(10),
+		pvIndexer:     cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{}),
+		pvcIndexer:    cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{uidIndex: MetaUIDKeyFunc}),
+		kubeClient: k8sfake.NewSimpleClientset(
+			&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1"}},
+			newPod("running", "node1"),
+			newPod("moved", "node2"),
+		),
+	}
+
+	p.reconcileAttachments()
+
+	// Volumes whose pods are gone, or were replaced on another node, are deleted
+	volumes, err := orchestrator.ListVolumes()
+	assert.NoError(t, err)
+	remaining := make([]string, 0)
+	for _, volume := range volumes {
+		remaining = append(remaining, volume.Config.Name)
+	}
+	assert.ElementsMatch(t, []string{"eph-running", "eph-unknown"}, remaining)
+}
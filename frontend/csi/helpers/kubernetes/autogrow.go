@@ -0,0 +1,173 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package kubernetes
+
+import (
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/netapp/trident/storage"
+)
+
+/////////////////////////////////////////////////////////////////////////////
+//
+// This file contains the controller that expands CSI Trident PVCs whose
+// filesystems reach the usage threshold of their storage class's autogrow
+// policy.  The PVC's requested size is increased, and Kubernetes resizes
+// the volume and filesystem as for any other expansion.
+//
+/////////////////////////////////////////////////////////////////////////////
+
+// runAutogrow periodically expands the volumes that need it, until the stop channel is closed.
+func (p *Plugin) runAutogrow() {
+
+	ticker := time.NewTicker(AutogrowPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.autogrowStopChan:
+			return
+		case <-ticker.C:
+			p.processAutogrow()
+		}
+	}
+}
+
+// processAutogrow checks the most recent usage of each volume against its storage class's autogrow
+// policy.
+func (p *Plugin) processAutogrow() {
+
+	usages, err := p.orchestrator.ListVolumeUsage()
+	if err != nil {
+		log.WithField("error", err).Debug("K8S helper could not list volume usage.")
+		return
+	}
+
+	for _, usage := range usages {
+		p.autogrowVolume(usage)
+	}
+}
+
+// autogrowVolume increases the requested size of a volume's PVC if its filesystem has reached the
+// autogrow threshold, and records an event on the PVC.
+func (p *Plugin) autogrowVolume(usage *storage.VolumeUsage) {
+
+	logFields := log.Fields{"volume": usage.VolumeName}
+
+	volume, err := p.orchestrator.GetVolume(usage.VolumeName)
+	if err != nil {
+		return
+	}
+	sc, err := p.orchestrator.GetStorageClass(volume.Config.StorageClass)
+	if err != nil || sc.Config == nil {
+		return
+	}
+	policy := sc.Config.Autogrow
+	if !policy.ShouldGrow(usage) {
+		return
+	}
+
+	// Usage reported before the last expansion doesn't reflect the new size
+	if resized, ok := p.autogrowResized[usage.VolumeName]; ok && !usage.UpdatedTime().After(resized) {
+		return
+	}
+
+	pv, err := p.getCachedPVByName(usage.VolumeName)
+	if err != nil || pv.Spec.ClaimRef == nil {
+		return
+	}
+	pvc, err := p.getCachedPVCByName(pv.Spec.ClaimRef.Name, pv.Spec.ClaimRef.Namespace)
+	if err != nil {
+		return
+	}
+	logFields["PVC"] = pvc.Name
+	logFields["namespace"] = pvc.Namespace
+
+	k8sSC, err := p.getCachedStorageClassByName(volume.Config.StorageClass)
+	if err != nil {
+		return
+	}
+	if k8sSC.AllowVolumeExpansion == nil || !*k8sSC.AllowVolumeExpansion {
+		p.warnAutogrow(usage.VolumeName, pvc, "can't expand a PVC whose storage class doesn't allow "+
+			"volume expansion.")
+		return
+	}
+
+	newSize, ok := getAutogrowSize(policy, pvc)
+	if !ok {
+		if !isPVCResizing(pvc) {
+			p.warnAutogrow(usage.VolumeName, pvc, fmt.Sprintf("filesystem is %d%% full, but the PVC has "+
+				"reached the autogrow maximum size.", usage.UsedPercent()))
+		}
+		return
+	}
+	currentSize := pvc.Status.Capacity[v1.ResourceStorage]
+
+	pvcClone := pvc.DeepCopy()
+	if pvcClone.Spec.Resources.Requests == nil {
+		pvcClone.Spec.Resources.Requests = make(v1.ResourceList)
+	}
+	pvcClone.Spec.Resources.Requests[v1.ResourceStorage] = newSize
+	updatedPVC, err := p.patchPVC(pvc, pvcClone)
+	if err != nil {
+		message := fmt.Sprintf("failed to expand the PVC: %v", err)
+		p.eventRecorder.Event(pvc, v1.EventTypeWarning, "AutogrowFailed", message)
+		log.WithFields(logFields).Errorf("K8S helper %s", message)
+		return
+	}
+
+	p.autogrowResized[usage.VolumeName] = time.Now()
+	delete(p.autogrowWarned, usage.VolumeName)
+
+	message := fmt.Sprintf("filesystem is %d%% full; expanding the PVC from %s to %s.",
+		usage.UsedPercent(), currentSize.String(), newSize.String())
+	p.eventRecorder.Event(updatedPVC, v1.EventTypeNormal, "AutogrowExpanding", message)
+	log.WithFields(logFields).Infof("K8S helper %s", message)
+}
+
+// warnAutogrow records a warning event for a volume that needs expanding but can't be, once
+// until the volume is expanded.
+func (p *Plugin) warnAutogrow(volumeName string, pvc *v1.PersistentVolumeClaim, message string) {
+	if p.autogrowWarned[volumeName] {
+		return
+	}
+	p.autogrowWarned[volumeName] = true
+	p.eventRecorder.Event(pvc, v1.EventTypeWarning, "AutogrowFailed", message)
+	log.WithFields(log.Fields{
+		"PVC":       pvc.Name,
+		"namespace": pvc.Namespace,
+	}).Warningf("K8S helper %s", message)
+}
+
+// getAutogrowSize returns the size to which an autogrow policy expands a bound PVC, or false if
+// the PVC is being resized already or has reached the policy's maximum size.
+func getAutogrowSize(policy *storage.AutogrowPolicy, pvc *v1.PersistentVolumeClaim) (resource.Quantity, bool) {
+
+	if pvc.Status.Phase != v1.ClaimBound || isPVCResizing(pvc) {
+		return resource.Quantity{}, false
+	}
+
+	currentSize := pvc.Status.Capacity[v1.ResourceStorage]
+	if currentSize.Sign() <= 0 {
+		return resource.Quantity{}, false
+	}
+	currentBytes := uint64(currentSize.Value())
+
+	newBytes := policy.NextSize(currentBytes)
+	if newBytes <= currentBytes {
+		return resource.Quantity{}, false
+	}
+	return *resource.NewQuantity(int64(newBytes), resource.BinarySI), true
+}
+
+// isPVCResizing reports whether a PVC requests more storage than it has.
+func isPVCResizing(pvc *v1.PersistentVolumeClaim) bool {
+	requestedSize := pvc.Spec.Resources.Requests[v1.ResourceStorage]
+	currentSize := pvc.Status.Capacity[v1.ResourceStorage]
+	return requestedSize.Cmp(currentSize) > 0
+}
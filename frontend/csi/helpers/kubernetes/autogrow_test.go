@@ -0,0 +1,49 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/netapp/trident/storage"
+)
+
+func TestGetAutogrowSize(t *testing.T) {
+
+	newPVC := func(phase v1.PersistentVolumeClaimPhase, requested, capacity string) *v1.PersistentVolumeClaim {
+		return &v1.PersistentVolumeClaim{
+			Spec: v1.PersistentVolumeClaimSpec{
+				Resources: v1.ResourceRequirements{
+					Requests: v1.ResourceList{v1.ResourceStorage: resource.MustParse(requested)},
+				},
+			},
+			Status: v1.PersistentVolumeClaimStatus{
+				Phase:    phase,
+				Capacity: v1.ResourceList{v1.ResourceStorage: resource.MustParse(capacity)},
+			},
+		}
+	}
+
+	policy := &storage.AutogrowPolicy{ThresholdPercent: 80, IncrementBytes: 1073741824, MaxSizeBytes: 3221225472}
+
+	size, ok := getAutogrowSize(policy, newPVC(v1.ClaimBound, "1Gi", "1Gi"))
+	assert.True(t, ok)
+	assert.Equal(t, "2Gi", size.String())
+
+	size, ok = getAutogrowSize(policy, newPVC(v1.ClaimBound, "2500Mi", "2500Mi"))
+	assert.True(t, ok)
+	assert.Equal(t, "3Gi", size.String(), "expansion should stop at the maximum size")
+
+	_, ok = getAutogrowSize(policy, newPVC(v1.ClaimBound, "3Gi", "3Gi"))
+	assert.False(t, ok, "PVC at the maximum size shouldn't be expanded")
+
+	_, ok = getAutogrowSize(policy, newPVC(v1.ClaimBound, "2Gi", "1Gi"))
+	assert.False(t, ok, "PVC being resized shouldn't be expanded again")
+
+	_, ok = getAutogrowSize(policy, newPVC(v1.ClaimPending, "1Gi", "1Gi"))
+	assert.False(t, ok, "unbound PVC shouldn't be expanded")
+}
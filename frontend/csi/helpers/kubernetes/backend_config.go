@@ -0,0 +1,338 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package kubernetes
+
+import (
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/netapp/trident/logging"
+	netappv1 "github.com/netapp/trident/persistent_store/crd/apis/netapp/v1"
+	"github.com/netapp/trident/storage"
+	tridentutils "github.com/netapp/trident/utils"
+)
+
+/////////////////////////////////////////////////////////////////////////////
+//
+// This file contains the controller that manages Trident backends defined by
+// TridentBackendConfig CRs in Trident's namespace.  A config may name a
+// secret whose keys, such as the backend's username and password, are added
+// to the config before it is applied.  The config is applied again whenever
+// the CR or the secret changes, so rotated credentials reach the backend
+// without an update from the user.  The result of the last validation is
+// recorded in the CR's status, and the backend is deleted with its CR.
+// Configs sent to the REST API may reference a secret in the same way, but
+// it is read only when the backend is created or updated.
+//
+/////////////////////////////////////////////////////////////////////////////
+
+const (
+	backendConfigReasonValidated = "BackendValidated"
+	backendConfigReasonFailed    = "ValidationFailed"
+)
+
+// runBackendConfigs periodically reconciles the TridentBackendConfig CRs, and again whenever a secret
+// in Trident's namespace changes, until the stop channel is closed.
+func (p *Plugin) runBackendConfigs() {
+
+	ticker := time.NewTicker(BackendConfigPeriod)
+	defer ticker.Stop()
+
+	// Backends are persisted, but apply any changes made while Trident was down without delay
+	p.processBackendConfigs()
+
+	for {
+		select {
+		case <-p.backendConfigStopChan:
+			return
+		case <-ticker.C:
+			p.processBackendConfigs()
+		case <-p.backendConfigTrigger:
+			p.processBackendConfigs()
+		}
+	}
+}
+
+// triggerBackendConfigs requests a reconciliation of the TridentBackendConfig CRs, unless one is
+// already pending.
+func (p *Plugin) triggerBackendConfigs() {
+	select {
+	case p.backendConfigTrigger <- struct{}{}:
+	default:
+	}
+}
+
+// addSecret reconciles the backend configs when a secret is created, since a config may be waiting
+// for its credentials.
+func (p *Plugin) addSecret(obj interface{}) {
+	if _, ok := obj.(*v1.Secret); ok {
+		p.triggerBackendConfigs()
+	}
+}
+
+// updateSecret reconciles the backend configs when a secret's contents change.
+func (p *Plugin) updateSecret(oldObj, newObj interface{}) {
+	oldSecret, ok := oldObj.(*v1.Secret)
+	if !ok {
+		return
+	}
+	newSecret, ok := newObj.(*v1.Secret)
+	if !ok {
+		return
+	}
+	// Periodic resyncs deliver unchanged secrets
+	if oldSecret.ResourceVersion != newSecret.ResourceVersion {
+		p.triggerBackendConfigs()
+	}
+}
+
+// processBackendConfigs reconciles the TridentBackendConfig CRs in Trident's namespace with Trident's backends.
+func (p *Plugin) processBackendConfigs() {
+
+	backendConfigs, err := p.crdClient.TridentV1().TridentBackendConfigs(p.namespace).List(ctx(), listOpts)
+	if err != nil {
+		log.WithField("error", err).Debug("K8S helper could not list backend configs.")
+		return
+	}
+
+	for _, backendConfig := range backendConfigs.Items {
+		p.processBackendConfig(backendConfig)
+	}
+}
+
+// processBackendConfig creates or updates the backend for a CR if the CR, its credentials secret, or
+// the backend changed since the config was last applied, and deletes the backend of a deleted CR.
+func (p *Plugin) processBackendConfig(backendConfig *netappv1.TridentBackendConfig) {
+
+	if !backendConfig.DeletionTimestamp.IsZero() {
+		p.deleteBackendConfig(backendConfig)
+		return
+	}
+
+	// The finalizer keeps the CR until its backend has been deleted
+	if !backendConfig.HasTridentFinalizers() {
+		backendConfigCopy := backendConfig.DeepCopy()
+		backendConfigCopy.AddTridentFinalizers()
+		updated, err := p.crdClient.TridentV1().TridentBackendConfigs(p.namespace).Update(
+			ctx(), backendConfigCopy, updateOpts)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"backendConfig": backendConfig.Name,
+				"error":         err,
+			}).Error("K8S helper could not add finalizer to backend config.")
+			return
+		}
+		backendConfig = updated
+	}
+
+	var secret *v1.Secret
+	secretName, err := backendConfig.GetSecretName()
+	if err == nil && secretName != "" {
+		secret, err = p.kubeClient.CoreV1().Secrets(p.namespace).Get(ctx(), secretName, getOpts)
+		if err != nil {
+			err = fmt.Errorf("could not read credentials secret %s; %v", secretName, err)
+		}
+	}
+	if err != nil {
+		p.updateBackendConfigStatus(backendConfig, nil, "", err)
+		return
+	}
+
+	var secretData map[string][]byte
+	var secretVersion string
+	if secret != nil {
+		secretData = secret.Data
+		secretVersion = secret.ResourceVersion
+	}
+
+	if !p.backendConfigChanged(backendConfig, secretVersion) {
+		return
+	}
+
+	backend, err := p.applyBackendConfig(backendConfig, secretData)
+	p.updateBackendConfigStatus(backendConfig, backend, secretVersion, err)
+}
+
+// backendConfigChanged returns true if a CR must be applied to its backend, either because it or its
+// secret changed since it was last applied, the last attempt failed, or the backend no longer exists.
+func (p *Plugin) backendConfigChanged(backendConfig *netappv1.TridentBackendConfig, secretVersion string) bool {
+
+	status := backendConfig.Status
+	if status.Phase != netappv1.BackendConfigPhaseBound ||
+		status.ObservedGeneration != backendConfig.Generation ||
+		status.SecretResourceVersion != secretVersion {
+		return true
+	}
+
+	_, err := p.orchestrator.GetBackendByBackendUUID(status.BackendUUID)
+	return tridentutils.IsNotFoundError(err)
+}
+
+// applyBackendConfig adds the credentials from a CR's secret to its config, and updates the CR's
+// backend with the result, or creates the backend if there isn't one.
+func (p *Plugin) applyBackendConfig(
+	backendConfig *netappv1.TridentBackendConfig, secretData map[string][]byte,
+) (*storage.BackendExternal, error) {
+
+	configJSON, err := backendConfig.GetConfigJSON(secretData)
+	if err != nil {
+		return nil, err
+	}
+
+	logFields := log.Fields{"backendConfig": backendConfig.Name}
+	operation := "UpdateBackend"
+
+	status := backendConfig.Status
+	var backend *storage.BackendExternal
+	if status.BackendUUID != "" {
+		backend, err = p.orchestrator.UpdateBackendByBackendUUID(status.BackendName, configJSON, status.BackendUUID)
+	}
+	if status.BackendUUID == "" || tridentutils.IsNotFoundError(err) {
+		operation = "AddBackend"
+		backend, err = p.orchestrator.AddBackend(configJSON)
+	}
+
+	auditName := status.BackendName
+	if backend != nil {
+		auditName = backend.Name
+	}
+	logging.Audit(&logging.AuditEvent{
+		Source:    logging.AuditSourceCSI,
+		Operation: operation,
+		Resource:  logging.AuditResourceBackend,
+		Name:      auditName,
+		Error:     logging.AuditError(err),
+	})
+
+	if err != nil {
+		log.WithFields(logFields).WithField("error", err).Error("K8S helper could not apply backend config.")
+		return backend, err
+	}
+
+	log.WithFields(logFields).WithFields(log.Fields{
+		"backend":     backend.Name,
+		"backendUUID": backend.BackendUUID,
+	}).Info("K8S helper applied backend config.")
+
+	return backend, nil
+}
+
+// ResolveBackendSecret replaces the reference to a credentials secret in a backend config with the
+// secret's keys, just as for TridentBackendConfig CRs, so configs created with tridentctl needn't
+// contain passwords.  The secret must be in Trident's namespace.
+func (p *Plugin) ResolveBackendSecret(configJSON string) (string, error) {
+
+	backendConfig := &netappv1.TridentBackendConfig{
+		Spec: netappv1.TridentBackendConfigSpec{
+			RawExtension: runtime.RawExtension{Raw: []byte(configJSON)},
+		},
+	}
+
+	secretName, err := backendConfig.GetSecretName()
+	if err != nil {
+		return "", err
+	}
+	if secretName == "" {
+		return configJSON, nil
+	}
+
+	secret, err := p.kubeClient.CoreV1().Secrets(p.namespace).Get(ctx(), secretName, getOpts)
+	if err != nil {
+		return "", fmt.Errorf("could not read credentials secret %s; %v", secretName, err)
+	}
+
+	log.WithField("secret", secretName).Debug("K8S helper resolved backend credentials secret.")
+
+	return backendConfig.GetConfigJSON(secret.Data)
+}
+
+// deleteBackendConfig deletes the backend of a deleted CR, and then removes the CR's finalizer.  A
+// backend with volumes is deleted once its volumes are.
+func (p *Plugin) deleteBackendConfig(backendConfig *netappv1.TridentBackendConfig) {
+
+	if !backendConfig.HasTridentFinalizers() {
+		return
+	}
+
+	logFields := log.Fields{
+		"backendConfig": backendConfig.Name,
+		"backend":       backendConfig.Status.BackendName,
+	}
+
+	if backendUUID := backendConfig.Status.BackendUUID; backendUUID != "" {
+		err := p.orchestrator.DeleteBackendByBackendUUID(backendConfig.Status.BackendName, backendUUID)
+
+		logging.Audit(&logging.AuditEvent{
+			Source:    logging.AuditSourceCSI,
+			Operation: "DeleteBackend",
+			Resource:  logging.AuditResourceBackend,
+			Name:      backendConfig.Status.BackendName,
+			Error:     logging.AuditError(err),
+		})
+
+		if err != nil && !tridentutils.IsNotFoundError(err) {
+			log.WithFields(logFields).WithField("error", err).Error("K8S helper could not delete backend.")
+			return
+		}
+		log.WithFields(logFields).Info("K8S helper deleted backend of deleted backend config.")
+	}
+
+	backendConfigCopy := backendConfig.DeepCopy()
+	backendConfigCopy.RemoveTridentFinalizers()
+	if _, err := p.crdClient.TridentV1().TridentBackendConfigs(p.namespace).Update(
+		ctx(), backendConfigCopy, updateOpts); err != nil {
+		log.WithFields(logFields).WithField("error", err).Error(
+			"K8S helper could not remove finalizer from backend config.")
+	}
+}
+
+// updateBackendConfigStatus records the result of applying a CR in its status.
+func (p *Plugin) updateBackendConfigStatus(
+	backendConfig *netappv1.TridentBackendConfig, backend *storage.BackendExternal, secretVersion string,
+	err error,
+) {
+
+	now := metav1.Now()
+	status := backendConfig.Status.DeepCopy()
+	status.LastValidationTime = now
+
+	if backend != nil {
+		status.BackendName = backend.Name
+		status.BackendUUID = backend.BackendUUID
+	}
+
+	condition := netappv1.TridentBackendConfigCondition{
+		Type:               netappv1.BackendConfigConditionValidated,
+		LastTransitionTime: now,
+	}
+	if err != nil {
+		status.Phase = netappv1.BackendConfigPhaseFailed
+		condition.Status = string(v1.ConditionFalse)
+		condition.Reason = backendConfigReasonFailed
+		condition.Message = err.Error()
+	} else {
+		status.Phase = netappv1.BackendConfigPhaseBound
+		status.ObservedGeneration = backendConfig.Generation
+		status.SecretResourceVersion = secretVersion
+		condition.Status = string(v1.ConditionTrue)
+		condition.Reason = backendConfigReasonValidated
+		condition.Message = fmt.Sprintf("backend %s validated", status.BackendName)
+	}
+	status.SetCondition(condition)
+
+	backendConfigCopy := backendConfig.DeepCopy()
+	backendConfigCopy.Status = *status
+
+	if _, err := p.crdClient.TridentV1().TridentBackendConfigs(p.namespace).UpdateStatus(
+		ctx(), backendConfigCopy, updateOpts); err != nil {
+		log.WithFields(log.Fields{
+			"backendConfig": backendConfig.Name,
+			"error":         err,
+		}).Error("K8S helper could not update backend config status.")
+	}
+}
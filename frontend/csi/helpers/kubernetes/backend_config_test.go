@@ -0,0 +1,144 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/core"
+	netappv1 "github.com/netapp/trident/persistent_store/crd/apis/netapp/v1"
+	"github.com/netapp/trident/persistent_store/crd/client/clientset/versioned/fake"
+	fakestorage "github.com/netapp/trident/storage/fake"
+	fakedriver "github.com/netapp/trident/storage_drivers/fake"
+)
+
+func newTestBackendConfig(t *testing.T, name, secretName string) *netappv1.TridentBackendConfig {
+	configJSON, err := fakedriver.NewFakeStorageDriverConfigJSON(
+		name, config.File, map[string]*fakestorage.StoragePool{}, []fakestorage.Volume{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	configJSON = configJSON[:len(configJSON)-1] + `,"credentials":{"name":"` + secretName + `"}}`
+
+	return &netappv1.TridentBackendConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "trident", Generation: 1},
+		Spec: netappv1.TridentBackendConfigSpec{
+			RawExtension: runtime.RawExtension{Raw: []byte(configJSON)},
+		},
+	}
+}
+
+func TestProcessBackendConfigs(t *testing.T) {
+	backendConfig := newTestBackendConfig(t, "backend", "creds")
+	missingSecretConfig := newTestBackendConfig(t, "nosecret", "missing")
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "creds", Namespace: "trident", ResourceVersion: "1"},
+		Data:       map[string][]byte{"username": []byte("admin"), "password": []byte("secret")},
+	}
+	orchestrator := core.NewMockOrchestrator()
+	p := &Plugin{
+		orchestrator: orchestrator,
+		namespace:    "trident",
+		crdClient:    fake.NewSimpleClientset(backendConfig, missingSecretConfig),
+		kubeClient:   k8sfake.NewSimpleClientset(secret),
+	}
+
+	p.processBackendConfigs()
+
+	updated, err := p.crdClient.TridentV1().TridentBackendConfigs("trident").Get(ctx(), "backend", getOpts)
+	if assert.NoError(t, err) {
+		assert.True(t, updated.HasTridentFinalizers())
+		assert.Equal(t, netappv1.BackendConfigPhaseBound, updated.Status.Phase)
+		assert.Equal(t, "1", updated.Status.SecretResourceVersion)
+		assert.NotEmpty(t, updated.Status.BackendUUID)
+		_, err = orchestrator.GetBackendByBackendUUID(updated.Status.BackendUUID)
+		assert.NoError(t, err)
+		if condition := updated.Status.GetCondition(netappv1.BackendConfigConditionValidated); assert.NotNil(t,
+			condition) {
+			assert.Equal(t, string(v1.ConditionTrue), condition.Status)
+		}
+	}
+	backendUUID := updated.Status.BackendUUID
+
+	failed, err := p.crdClient.TridentV1().TridentBackendConfigs("trident").Get(ctx(), "nosecret", getOpts)
+	if assert.NoError(t, err) {
+		assert.Equal(t, netappv1.BackendConfigPhaseFailed, failed.Status.Phase)
+		assert.Empty(t, failed.Status.BackendUUID)
+		if condition := failed.Status.GetCondition(netappv1.BackendConfigConditionValidated); assert.NotNil(t,
+			condition) {
+			assert.Equal(t, string(v1.ConditionFalse), condition.Status)
+			assert.Contains(t, condition.Message, "missing")
+		}
+	}
+
+	// Rotating the secret reapplies the config to the same backend
+	secret.ResourceVersion = "2"
+	secret.Data["password"] = []byte("rotated")
+	_, err = p.kubeClient.CoreV1().Secrets("trident").Update(ctx(), secret, updateOpts)
+	assert.NoError(t, err)
+
+	p.processBackendConfigs()
+
+	updated, err = p.crdClient.TridentV1().TridentBackendConfigs("trident").Get(ctx(), "backend", getOpts)
+	if assert.NoError(t, err) {
+		assert.Equal(t, netappv1.BackendConfigPhaseBound, updated.Status.Phase)
+		assert.Equal(t, "2", updated.Status.SecretResourceVersion)
+		assert.Equal(t, backendUUID, updated.Status.BackendUUID)
+	}
+
+	// Deleting the CR deletes its backend and releases the finalizer
+	now := metav1.Now()
+	updated.DeletionTimestamp = &now
+	p.processBackendConfig(updated)
+
+	deleted, err := p.crdClient.TridentV1().TridentBackendConfigs("trident").Get(ctx(), "backend", getOpts)
+	if assert.NoError(t, err) {
+		assert.False(t, deleted.HasTridentFinalizers())
+	}
+}
+
+func TestUpdateSecretTriggersBackendConfigs(t *testing.T) {
+	p := &Plugin{backendConfigTrigger: make(chan struct{}, 1)}
+
+	oldSecret := &v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "creds", ResourceVersion: "1"}}
+	p.updateSecret(oldSecret, oldSecret.DeepCopy())
+	assert.Len(t, p.backendConfigTrigger, 0, "resync should not trigger a pass")
+
+	newSecret := oldSecret.DeepCopy()
+	newSecret.ResourceVersion = "2"
+	p.updateSecret(oldSecret, newSecret)
+	p.updateSecret(oldSecret, newSecret)
+	assert.Len(t, p.backendConfigTrigger, 1)
+}
+
+func TestResolveBackendSecret(t *testing.T) {
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "creds", Namespace: "trident"},
+		Data:       map[string][]byte{"username": []byte("admin"), "password": []byte("secret")},
+	}
+	p := &Plugin{namespace: "trident", kubeClient: k8sfake.NewSimpleClientset(secret)}
+
+	configJSON, err := p.ResolveBackendSecret(`{"version":1,"credentials":{"name":"creds"}}`)
+	if assert.NoError(t, err) {
+		assert.JSONEq(t, `{"version":1,"username":"admin","password":"secret"}`, configJSON)
+	}
+
+	// Configs without a reference are unchanged
+	configJSON, err = p.ResolveBackendSecret(`{"version":1,"password":"inline"}`)
+	if assert.NoError(t, err) {
+		assert.Equal(t, `{"version":1,"password":"inline"}`, configJSON)
+	}
+
+	_, err = p.ResolveBackendSecret(`{"version":1,"credentials":{"name":"missing"}}`)
+	assert.Error(t, err)
+
+	_, err = p.ResolveBackendSecret(`{"version":1,"credentials":{"name":"creds"},"password":"inline"}`)
+	assert.Error(t, err, "keys in both the config and the secret should be rejected")
+}
@@ -0,0 +1,172 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package kubernetes
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+
+	netappv1 "github.com/netapp/trident/persistent_store/crd/apis/netapp/v1"
+	"github.com/netapp/trident/storage"
+	tridentutils "github.com/netapp/trident/utils"
+)
+
+/////////////////////////////////////////////////////////////////////////////
+//
+// This file contains the controller that keeps Trident's clone grants in
+// sync with TridentCloneGrant CRs.  Each CR's PVCs are resolved to their
+// Trident volumes, which may then be cloned into the CR's target namespaces.
+// Grants are also made from ReferenceGrants (see reference_grant.go).
+// Trident doesn't persist clone grants, so they are all added again when
+// the controller starts.
+//
+/////////////////////////////////////////////////////////////////////////////
+
+// runCloneGrants periodically reconciles the TridentCloneGrant CRs, until the stop channel is closed.
+func (p *Plugin) runCloneGrants() {
+
+	ticker := time.NewTicker(CloneGrantPeriod)
+	defer ticker.Stop()
+
+	// Clone grants only exist in memory, so restore them without waiting for the first tick
+	p.processCloneGrants()
+
+	for {
+		select {
+		case <-p.cloneGrantStopChan:
+			return
+		case <-ticker.C:
+			p.processCloneGrants()
+		}
+	}
+}
+
+// processCloneGrants reconciles the TridentCloneGrant and ReferenceGrant CRs in all namespaces with
+// Trident's clone grants.
+func (p *Plugin) processCloneGrants() {
+
+	cloneGrants, err := p.crdClient.TridentV1().TridentCloneGrants("").List(ctx(), listOpts)
+	if err != nil {
+		log.WithField("error", err).Debug("K8S helper could not list clone grants.")
+		return
+	}
+
+	managedGrants := make(map[string]bool)
+	for _, cloneGrant := range cloneGrants.Items {
+		managedGrants[storage.MakeCloneGrantID(cloneGrant.Namespace, cloneGrant.Name)] = true
+		p.processCloneGrant(cloneGrant)
+	}
+
+	referenceGrantsListed := p.processReferenceGrants(managedGrants)
+
+	// Delete the grants whose CRs no longer exist
+	tridentGrants, err := p.orchestrator.ListCloneGrants()
+	if err != nil {
+		log.WithField("error", err).Debug("K8S helper could not list Trident clone grants.")
+		return
+	}
+	for _, tridentGrant := range tridentGrants {
+		if managedGrants[tridentGrant.ID()] {
+			continue
+		}
+		if isReferenceGrantName(tridentGrant.Name) && !referenceGrantsListed {
+			continue
+		}
+		p.deleteTridentCloneGrant(tridentGrant.Namespace, tridentGrant.Name)
+	}
+}
+
+// processCloneGrant adds or replaces the Trident clone grant for a CR, and records the result in
+// the CR's status.
+func (p *Plugin) processCloneGrant(cloneGrant *netappv1.TridentCloneGrant) {
+
+	volumeNames, errMessages := p.getCloneGrantVolumes(cloneGrant)
+
+	grant := &storage.CloneGrant{
+		Name:             cloneGrant.Name,
+		Namespace:        cloneGrant.Namespace,
+		Volumes:          volumeNames,
+		TargetNamespaces: cloneGrant.Spec.TargetNamespaces,
+	}
+
+	if len(volumeNames) == 0 {
+		// Don't leave a stale grant in place if none of the PVCs can be cloned
+		p.deleteTridentCloneGrant(cloneGrant.Namespace, cloneGrant.Name)
+		errMessages = append(errMessages, "no PVCs are bound to Trident volumes")
+	} else if err := p.orchestrator.AddCloneGrant(grant); err != nil {
+		errMessages = append(errMessages, err.Error())
+		volumeNames = nil
+	}
+
+	status := netappv1.TridentCloneGrantStatus{
+		Volumes: volumeNames,
+		Message: strings.Join(errMessages, "; "),
+	}
+	p.updateCloneGrantStatus(cloneGrant, status)
+}
+
+// getCloneGrantVolumes returns the names of the Trident volumes bound to a CR's PVCs, or "*" if the CR
+// covers all PVCs in its namespace, along with a message for each PVC that isn't bound to a Trident volume.
+func (p *Plugin) getCloneGrantVolumes(cloneGrant *netappv1.TridentCloneGrant) ([]string, []string) {
+
+	errMessages := make([]string, 0)
+	if len(cloneGrant.Spec.PVCs) == 0 {
+		return []string{storage.CloneGrantAll}, errMessages
+	}
+
+	volumeNames := make([]string, 0, len(cloneGrant.Spec.PVCs))
+	for _, pvcName := range cloneGrant.Spec.PVCs {
+		pvc, err := p.getCachedPVCByName(pvcName, cloneGrant.Namespace)
+		if err != nil {
+			errMessages = append(errMessages, fmt.Sprintf("PVC %s not found", pvcName))
+			continue
+		}
+		if pvc.Status.Phase != v1.ClaimBound || pvc.Spec.VolumeName == "" {
+			errMessages = append(errMessages, fmt.Sprintf("PVC %s is not bound", pvcName))
+			continue
+		}
+		volumeNames = append(volumeNames, pvc.Spec.VolumeName)
+	}
+
+	return volumeNames, errMessages
+}
+
+// deleteTridentCloneGrant deletes a Trident clone grant.  Volumes already cloned under the grant are
+// not affected.
+func (p *Plugin) deleteTridentCloneGrant(namespace, name string) {
+	logFields := log.Fields{"cloneGrant": name, "namespace": namespace}
+	if err := p.orchestrator.DeleteCloneGrant(namespace, name); err != nil {
+		if !tridentutils.IsNotFoundError(err) {
+			log.WithFields(logFields).WithField("error", err).Error("K8S helper could not delete clone grant.")
+		}
+		return
+	}
+	log.WithFields(logFields).Info("K8S helper deleted clone grant.")
+}
+
+// updateCloneGrantStatus records the result of a reconciliation in a CR's status.
+func (p *Plugin) updateCloneGrantStatus(
+	cloneGrant *netappv1.TridentCloneGrant, status netappv1.TridentCloneGrantStatus,
+) {
+
+	if reflect.DeepEqual(cloneGrant.Status, status) {
+		return
+	}
+
+	cloneGrantCopy := cloneGrant.DeepCopy()
+	cloneGrantCopy.Status = status
+
+	if _, err := p.crdClient.TridentV1().TridentCloneGrants(cloneGrant.Namespace).UpdateStatus(
+		ctx(), cloneGrantCopy, updateOpts); err != nil {
+		log.WithFields(log.Fields{
+			"cloneGrant": cloneGrant.Name,
+			"namespace":  cloneGrant.Namespace,
+			"error":      err,
+		}).Error("K8S helper could not update clone grant status.")
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/netapp/trident/core"
+	netappv1 "github.com/netapp/trident/persistent_store/crd/apis/netapp/v1"
+	"github.com/netapp/trident/persistent_store/crd/client/clientset/versioned/fake"
+	"github.com/netapp/trident/storage"
+)
+
+func TestGetCloneGrantVolumes(t *testing.T) {
+	cloneGrant := &netappv1.TridentCloneGrant{
+		ObjectMeta: metav1.ObjectMeta{Name: "publish", Namespace: "datasets"},
+		Spec: netappv1.TridentCloneGrantSpec{
+			PVCs:             []string{"golden", "pending", "missing"},
+			TargetNamespaces: []string{"tenant1"},
+		},
+	}
+	p := &Plugin{
+		orchestrator:  core.NewMockOrchestrator(),
+		crdClient:     fake.NewSimpleClientset(cloneGrant),
+		dynamicClient: newReferenceGrantDynamicClient(),
+		pvcIndexer:    cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{}),
+	}
+	_ = p.pvcIndexer.Add(&v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "golden", Namespace: "datasets"},
+		Spec:       v1.PersistentVolumeClaimSpec{VolumeName: "pvc-1"},
+		Status:     v1.PersistentVolumeClaimStatus{Phase: v1.ClaimBound},
+	})
+	_ = p.pvcIndexer.Add(&v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "pending", Namespace: "datasets"},
+		Status:     v1.PersistentVolumeClaimStatus{Phase: v1.ClaimPending},
+	})
+
+	volumeNames, errMessages := p.getCloneGrantVolumes(cloneGrant)
+	assert.Equal(t, []string{"pvc-1"}, volumeNames)
+	assert.Equal(t, []string{"PVC pending is not bound", "PVC missing not found"}, errMessages)
+
+	p.processCloneGrants()
+
+	updated, err := p.crdClient.TridentV1().TridentCloneGrants("datasets").Get(ctx(), "publish", getOpts)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"pvc-1"}, updated.Status.Volumes)
+		assert.Contains(t, updated.Status.Message, "PVC missing not found")
+	}
+
+	// A grant without PVCs covers the whole namespace
+	cloneGrant.Spec.PVCs = nil
+	volumeNames, errMessages = p.getCloneGrantVolumes(cloneGrant)
+	assert.Equal(t, []string{storage.CloneGrantAll}, volumeNames)
+	assert.Empty(t, errMessages)
+}
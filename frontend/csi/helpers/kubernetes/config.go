@@ -21,6 +21,17 @@ const (
 	PVDeleteWaitPeriod      = 30 * time.Second
 	PodDeleteWaitPeriod     = 60 * time.Second
 	ImportPVCacheWaitPeriod = 180 * time.Second
+	SnapshotSchedulePeriod  = 1 * time.Minute
+	AutogrowPeriod          = 2 * time.Minute
+	VolumeGroupPeriod       = 1 * time.Minute
+	CloneGrantPeriod        = 1 * time.Minute
+	QuotaPeriod             = 1 * time.Minute
+	BackendConfigPeriod     = 1 * time.Minute
+	VolumeHealthPeriod      = 1 * time.Minute
+	StorageCapacityPeriod   = 1 * time.Minute
+	PopulatorPeriod         = 30 * time.Second
+	AttachmentPeriod        = 5 * time.Minute
+	BackendEventPeriod      = 5 * time.Minute
 
 	CacheBackoffInitialInterval     = 1 * time.Second
 	CacheBackoffRandomizationFactor = 0.1
@@ -28,7 +39,9 @@ const (
 	CacheBackoffMaxInterval         = 5 * time.Second
 
 	// Kubernetes-defined storage class parameters
-	K8sFsType = "fsType"
+	K8sFsType             = "fsType"
+	K8sCSIParameterPrefix = "csi.storage.k8s.io/"
+	K8sCSIFsType          = K8sCSIParameterPrefix + "fstype"
 
 	// Kubernetes-defined annotations
 	// (Based on kubernetes/pkg/controller/volume/persistentvolume/controller.go)
@@ -37,6 +50,9 @@ const (
 	AnnBindCompleted          = "pv.kubernetes.io/bind-completed"
 	AnnStorageProvisioner     = "volume.beta.kubernetes.io/storage-provisioner"
 
+	// Kubernetes-defined node labels that place a node in a topology segment, such as a zone
+	K8sTopologyLabelPrefix = "topology.kubernetes.io/"
+
 	// Kubernetes-defined finalizers
 	FinalizerPVProtection = "kubernetes.io/pv-protection"
 
@@ -51,10 +67,23 @@ const (
 	AnnBlockSize          = annPrefix + "/blockSize"
 	AnnFileSystem         = annPrefix + "/fileSystem"
 	AnnCloneFromPVC       = annPrefix + "/cloneFromPVC"
+	AnnCloneFromNamespace = annPrefix + "/cloneFromNamespace"
 	AnnSplitOnClone       = annPrefix + "/splitOnClone"
 	AnnNotManaged         = annPrefix + "/notManaged"
 	AnnImportOriginalName = annPrefix + "/importOriginalName"
 	AnnImportBackendUUID  = annPrefix + "/importBackendUUID"
+	AnnDeletionPolicy     = annPrefix + "/deletionPolicy"
+	AnnPool               = annPrefix + "/pool"
+
+	// Orchestrator-defined annotations on the staging PVCs of volume populators
+	AnnPopulateFor        = annPrefix + "/populateFor"
+	AnnPopulateDataSource = annPrefix + "/populateDataSource"
+	AnnPopulated          = annPrefix + "/populated"
+
+	// Orchestrator-defined labels
+	LabelSnapshotSchedule = annPrefix + "/snapshotSchedule"
+	LabelStorageCapacity  = annPrefix + "/storageCapacity"
+	LabelPopulatorTarget  = annPrefix + "/populatorTarget"
 )
 
 var features = map[helpers.Feature]*utils.Version{
@@ -0,0 +1,113 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package kubernetes
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/netapp/trident/frontend/csi"
+	"github.com/netapp/trident/logging"
+	tridentutils "github.com/netapp/trident/utils"
+)
+
+/////////////////////////////////////////////////////////////////////////////
+//
+// This file contains the handling of nodes that Kubernetes has marked out
+// of service after a non-graceful shutdown.  Trident revokes the node's
+// access to its volumes and deletes the node's volume attachments, so that
+// pods may fail over to other nodes without manual cleanup.
+//
+/////////////////////////////////////////////////////////////////////////////
+
+// outOfServiceTaintKey is the taint an administrator applies to a node that was shut down
+// without draining, to declare that its workloads may be moved elsewhere.
+const outOfServiceTaintKey = "node.kubernetes.io/out-of-service"
+
+// isNodeOutOfService reports whether a node carries the out-of-service taint.
+func isNodeOutOfService(node *v1.Node) bool {
+	for _, taint := range node.Spec.Taints {
+		if taint.Key == outOfServiceTaintKey && taint.Effect == v1.TaintEffectNoExecute {
+			return true
+		}
+	}
+	return false
+}
+
+// forceDetachNode revokes an out-of-service node's access to volumes on all backends and deletes
+// the node's Trident volume attachments.  A node that Trident no longer knows has already been
+// detached from the backends, but any attachments left behind are still deleted.
+func (p *Plugin) forceDetachNode(node *v1.Node) {
+
+	logFields := log.Fields{"node": node.Name}
+
+	// The node remains tainted until it is repaired, so only the first pass finds anything to detach
+	detached := true
+	err := p.orchestrator.ForceDetachNode(node.Name)
+	if err != nil && tridentutils.IsNotFoundError(err) {
+		detached, err = false, nil
+	}
+	if err == nil {
+		var deleted int
+		deleted, err = p.deleteNodeVolumeAttachments(node.Name)
+		detached = detached || deleted > 0
+	}
+	if err == nil && !detached {
+		return
+	}
+
+	logging.Audit(&logging.AuditEvent{
+		Source:    logging.AuditSourceCSI,
+		Operation: "ForceDetachNode",
+		Resource:  logging.AuditResourceNode,
+		Name:      node.Name,
+		Error:     logging.AuditError(err),
+	})
+
+	if err != nil {
+		message := fmt.Sprintf("failed to force-detach volumes from out-of-service node: %v", err)
+		p.eventRecorder.Event(node, v1.EventTypeWarning, "ForceDetachFailed", message)
+		log.WithFields(logFields).Errorf("K8S helper %s", message)
+		return
+	}
+
+	message := "force-detached volumes from out-of-service node."
+	p.eventRecorder.Event(node, v1.EventTypeWarning, "ForceDetached", message)
+	log.WithFields(logFields).Warningf("K8S helper %s", message)
+}
+
+// deleteNodeVolumeAttachments deletes the Trident volume attachments on a node, so that the
+// attach/detach controller can attach the volumes to other nodes.  It returns the number of
+// attachments deleted.
+func (p *Plugin) deleteNodeVolumeAttachments(nodeName string) (int, error) {
+
+	attachments, err := p.kubeClient.StorageV1().VolumeAttachments().List(ctx(), metav1.ListOptions{})
+	if err != nil {
+		return 0, fmt.Errorf("could not list volume attachments; %v", err)
+	}
+
+	deleted := 0
+	for _, attachment := range attachments.Items {
+		if attachment.Spec.Attacher != csi.Provisioner || attachment.Spec.NodeName != nodeName ||
+			attachment.DeletionTimestamp != nil {
+			continue
+		}
+		err := p.kubeClient.StorageV1().VolumeAttachments().Delete(ctx(), attachment.Name, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return deleted, fmt.Errorf("could not delete volume attachment %s; %v", attachment.Name, err)
+		}
+		deleted++
+	}
+
+	if deleted > 0 {
+		log.WithFields(log.Fields{
+			"node":        nodeName,
+			"attachments": deleted,
+		}).Warning("K8S helper deleted volume attachments of out-of-service node.")
+	}
+	return deleted, nil
+}
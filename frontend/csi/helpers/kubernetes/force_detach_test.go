@@ -0,0 +1,77 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/netapp/trident/core"
+	"github.com/netapp/trident/frontend/csi"
+	"github.com/netapp/trident/utils"
+)
+
+func TestIsNodeOutOfService(t *testing.T) {
+
+	node := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1"}}
+	assert.False(t, isNodeOutOfService(node))
+
+	node.Spec.Taints = []v1.Taint{{Key: outOfServiceTaintKey, Effect: v1.TaintEffectNoSchedule}}
+	assert.False(t, isNodeOutOfService(node), "only the NoExecute effect marks a node out of service")
+
+	node.Spec.Taints = append(node.Spec.Taints, v1.Taint{Key: outOfServiceTaintKey, Effect: v1.TaintEffectNoExecute})
+	assert.True(t, isNodeOutOfService(node))
+}
+
+func TestForceDetachNode(t *testing.T) {
+
+	newAttachment := func(name, attacher, nodeName string) *storagev1.VolumeAttachment {
+		return &storagev1.VolumeAttachment{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       storagev1.VolumeAttachmentSpec{Attacher: attacher, NodeName: nodeName},
+		}
+	}
+
+	orchestrator := core.NewMockOrchestrator()
+	assert.NoError(t, orchestrator.AddNode(&utils.Node{Name: "failed"}))
+
+	recorder := record.NewFakeRecorder(10)
+	p := &Plugin{
+		orchestrator:  orchestrator,
+		eventRecorder: recorder,
+		kubeClient: k8sfake.NewSimpleClientset(
+			newAttachment("csi-1", csi.Provisioner, "failed"),
+			newAttachment("csi-2", csi.Provisioner, "healthy"),
+			newAttachment("csi-3", "other.csi.driver", "failed"),
+		),
+	}
+	node := &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "failed"},
+		Spec:       v1.NodeSpec{Taints: []v1.Taint{{Key: outOfServiceTaintKey, Effect: v1.TaintEffectNoExecute}}},
+	}
+
+	p.processNode(node, eventUpdate)
+
+	_, err := orchestrator.GetNode("failed")
+	assert.True(t, utils.IsNotFoundError(err), "node should be deregistered")
+
+	attachments, err := p.kubeClient.StorageV1().VolumeAttachments().List(ctx(), metav1.ListOptions{})
+	assert.NoError(t, err)
+	remaining := make([]string, 0)
+	for _, attachment := range attachments.Items {
+		remaining = append(remaining, attachment.Name)
+	}
+	assert.ElementsMatch(t, []string{"csi-2", "csi-3"}, remaining)
+	assert.Len(t, recorder.Events, 1)
+	assert.Contains(t, <-recorder.Events, "ForceDetached")
+
+	// Later updates of the still-tainted node have nothing left to detach
+	p.processNode(node, eventUpdate)
+	assert.Len(t, recorder.Events, 0)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 	v1 "k8s.io/api/core/v1"
@@ -15,6 +16,7 @@ import (
 	frontendcommon "github.com/netapp/trident/frontend/common"
 	"github.com/netapp/trident/frontend/csi"
 	"github.com/netapp/trident/frontend/csi/helpers"
+	netappv1 "github.com/netapp/trident/persistent_store/crd/apis/netapp/v1"
 	"github.com/netapp/trident/storage"
 )
 
@@ -84,17 +86,54 @@ func (p *Plugin) GetVolumeConfig(
 	// Create the volume config
 	volumeConfig := getVolumeConfig(pvc.Spec.AccessModes, pvc.Spec.VolumeMode, pvName, pvcSize,
 		processPVCAnnotations(pvc, fsType), sc)
+	volumeConfig.Namespace = pvc.Namespace
+	volumeConfig.NamespaceLabels = p.getNamespaceLabels(pvc.Namespace)
 
 	// Check if we're cloning a PVC, and if so, do some further validation
-	if cloneSourcePVName, err := p.getCloneSourceInfo(pvc); err != nil {
+	if cloneSourcePVName, cloneSourceNamespace, err := p.getCloneSourceInfo(pvc); err != nil {
 		return nil, err
 	} else if cloneSourcePVName != "" {
 		volumeConfig.CloneSourceVolume = cloneSourcePVName
+		volumeConfig.CloneSourceNamespace = cloneSourceNamespace
 	}
 
 	return volumeConfig, nil
 }
 
+// getNamespaceLabels returns the labels of a namespace, which backends restricted by a namespace
+// selector are matched against.  If the namespace can't be read, no labels are returned, so such
+// backends won't hold the namespace's volumes.
+func (p *Plugin) getNamespaceLabels(namespace string) map[string]string {
+
+	ns, err := p.kubeClient.CoreV1().Namespaces().Get(ctx(), namespace, getOpts)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"namespace": namespace,
+			"error":     err,
+		}).Warning("Could not get namespace labels.")
+		return nil
+	}
+	return ns.Labels
+}
+
+// GetNodeTopologyLabels returns the topology labels, such as the zone and region, of a Kubernetes node.
+// Nodes register with the controller using these, so volumes are placed where the node can reach them.
+func (p *Plugin) GetNodeTopologyLabels(nodeName string) (map[string]string, error) {
+
+	node, err := p.kubeClient.CoreV1().Nodes().Get(ctx(), nodeName, getOpts)
+	if err != nil {
+		return nil, fmt.Errorf("could not get node %s; %v", nodeName, err)
+	}
+
+	topologyLabels := make(map[string]string)
+	for key, value := range node.Labels {
+		if strings.HasPrefix(key, K8sTopologyLabelPrefix) {
+			topologyLabels[key] = value
+		}
+	}
+	return topologyLabels, nil
+}
+
 // getPVCForCSIVolume accepts the name of a volume being requested by the CSI provisioner,
 // extracts the PVC name from the volume name, and returns the PVC object as read from the
 // Kubernetes API server.  The method waits for the object to appear in cache, resyncs the
@@ -175,27 +214,34 @@ func (p *Plugin) getStorageClass(name string) (*k8sstoragev1.StorageClass, error
 // getCloneSourceInfo accepts the PVC of a volume being provisioned by CSI and inspects it
 // for the annotations indicating a clone operation (of which CSI is unaware). If a clone is
 // being created, the method completes several checks on the source PVC/PV and returns the
-// name of the source PV as needed by Trident to clone a volume as well as an optional
-// snapshot name (also potentially unknown to CSI).  Note that these legacy clone annotations
-// will be overridden if the VolumeContentSource is set in the CSI CreateVolume request.
-func (p *Plugin) getCloneSourceInfo(clonePVC *v1.PersistentVolumeClaim) (string, error) {
+// name and namespace of the source PV as needed by Trident to clone a volume.  The source PVC
+// is in the clone's namespace unless another is named by the cloneFromNamespace annotation, in
+// which case Trident requires a clone grant in the source namespace.  Note that these legacy
+// clone annotations will be overridden if the VolumeContentSource is set in the CSI
+// CreateVolume request.
+func (p *Plugin) getCloneSourceInfo(clonePVC *v1.PersistentVolumeClaim) (string, string, error) {
 
 	// Check if this is a clone operation
 	annotations := processPVCAnnotations(clonePVC, "")
 	sourcePVCName := getAnnotation(annotations, AnnCloneFromPVC)
 	if sourcePVCName == "" {
-		return "", nil
+		return "", "", nil
+	}
+	sourceNamespace := getAnnotation(annotations, AnnCloneFromNamespace)
+	if sourceNamespace == "" {
+		sourceNamespace = clonePVC.Namespace
 	}
 
-	// Check that the source PVC is in the same namespace.
+	// Check that the source PVC exists.  Any clone grant is checked by Trident's core.
 	// NOTE: For VolumeContentSource this check is performed by CSI
-	sourcePVC, err := p.waitForCachedPVCByName(sourcePVCName, clonePVC.Namespace, PreSyncCacheWaitPeriod)
+	sourcePVC, err := p.waitForCachedPVCByName(sourcePVCName, sourceNamespace, PreSyncCacheWaitPeriod)
 	if err != nil {
 		log.WithFields(log.Fields{
 			"sourcePVCName": sourcePVCName,
-			"namespace":     clonePVC.Namespace,
+			"namespace":     sourceNamespace,
 		}).Errorf("Clone source PVC not found in local cache: %v", err)
-		return "", fmt.Errorf("cloning from a PVC requires both PVCs be in the same namespace: %v", err)
+		return "", "", fmt.Errorf("clone source PVC %s not found in namespace %s: %v", sourcePVCName,
+			sourceNamespace, err)
 	}
 
 	// Check that both source and clone PVCs have the same storage class
@@ -209,7 +255,7 @@ func (p *Plugin) getCloneSourceInfo(clonePVC *v1.PersistentVolumeClaim) (string,
 			"sourcePVCNamespace":    sourcePVC.Namespace,
 			"sourcePVCStorageClass": getStorageClassForPVC(sourcePVC),
 		}).Error("Cloning from a PVC requires both PVCs have the same storage class.")
-		return "", fmt.Errorf("cloning from a PVC requires both PVCs have the same storage class")
+		return "", "", fmt.Errorf("cloning from a PVC requires both PVCs have the same storage class")
 	}
 
 	// Check that the source PVC has an associated PV
@@ -219,24 +265,33 @@ func (p *Plugin) getCloneSourceInfo(clonePVC *v1.PersistentVolumeClaim) (string,
 			"sourcePVCName":      sourcePVC.Name,
 			"sourcePVCNamespace": sourcePVC.Namespace,
 		}).Error("Cloning from a PVC requires the source to be bound to a PV.")
-		return "", fmt.Errorf("cloning from a PVC requires the source to be bound to a PV")
+		return "", "", fmt.Errorf("cloning from a PVC requires the source to be bound to a PV")
 	}
 
-	return sourcePVName, nil
+	return sourcePVName, sourceNamespace, nil
 }
 
 // GetSnapshotConfig accepts the attributes of a snapshot being requested by the CSI
 // provisioner and returns a SnapshotConfig structure as needed by Trident to create a new snapshot.
-func (p *Plugin) GetSnapshotConfig(volumeName, snapshotName string) (*storage.SnapshotConfig, error) {
-	return &storage.SnapshotConfig{
+// The parameters are those of the snapshot's class.
+func (p *Plugin) GetSnapshotConfig(
+	volumeName, snapshotName string, parameters map[string]string,
+) (*storage.SnapshotConfig, error) {
+
+	snapshotConfig := &storage.SnapshotConfig{
 		Version:    config.OrchestratorAPIVersion,
 		Name:       snapshotName,
 		VolumeName: volumeName,
-	}, nil
+	}
+	if err := snapshotConfig.ApplyParameters(parameters); err != nil {
+		return nil, err
+	}
+	return snapshotConfig, nil
 }
 
 // RecordVolumeEvent accepts the name of a CSI volume (i.e. a PV name), finds the associated
-// PVC, and posts and event message on the PVC object with the K8S API server.
+// PVC, and posts and event message on the PVC object with the K8S API server.  If the PV
+// exists already, as when the volume is published or resized, the event is posted on the PV too.
 func (p *Plugin) RecordVolumeEvent(name, eventType, reason, message string) {
 
 	log.WithFields(log.Fields{
@@ -246,6 +301,16 @@ func (p *Plugin) RecordVolumeEvent(name, eventType, reason, message string) {
 		"message":   message,
 	}).Debug("Volume event.")
 
+	if pv, err := p.getCachedPVByName(name); err == nil {
+		p.eventRecorder.Event(pv, mapEventType(eventType), reason, message)
+		if pv.Spec.ClaimRef != nil {
+			if pvc, err := p.getCachedPVCByName(pv.Spec.ClaimRef.Name, pv.Spec.ClaimRef.Namespace); err == nil {
+				p.eventRecorder.Event(pvc, mapEventType(eventType), reason, message)
+			}
+		}
+		return
+	}
+
 	if pvc, err := p.getPVCForCSIVolume(name); err != nil {
 		log.WithField("error", err).Debug("Failed to find PVC for event.")
 	} else {
@@ -253,6 +318,81 @@ func (p *Plugin) RecordVolumeEvent(name, eventType, reason, message string) {
 	}
 }
 
+// RecordBackendEvent accepts the name of a Trident backend, finds the associated TridentBackend
+// CR, and posts an event message on it with the K8S API server.  An event with the same reason is
+// posted on a backend at most once per BackendEventPeriod, since failed requests may be retried
+// often.
+func (p *Plugin) RecordBackendEvent(backendName, eventType, reason, message string) {
+
+	log.WithFields(log.Fields{
+		"backend":   backendName,
+		"eventType": eventType,
+		"reason":    reason,
+		"message":   message,
+	}).Debug("Backend event.")
+
+	eventKey := backendName + "/" + reason
+	p.backendEventsLock.Lock()
+	if lastRecorded, ok := p.backendEventsRecorded[eventKey]; ok && time.Since(lastRecorded) < BackendEventPeriod {
+		p.backendEventsLock.Unlock()
+		log.WithField("backend", backendName).Debug("Backend event was posted recently, not posting it again.")
+		return
+	}
+	p.backendEventsRecorded[eventKey] = time.Now()
+	p.backendEventsLock.Unlock()
+
+	items, err := p.tbeIndexer.ByIndex(backendNameIndex, backendName)
+	if err != nil {
+		log.WithField("error", err).Debug("Failed to find TridentBackend for event.")
+		return
+	}
+	for _, item := range items {
+		backend, ok := item.(*netappv1.TridentBackend)
+		if !ok {
+			continue
+		}
+		// TridentBackend isn't registered with the client's scheme, so refer to it explicitly
+		ref := &v1.ObjectReference{
+			APIVersion:      netappv1.SchemeGroupVersion.String(),
+			Kind:            "TridentBackend",
+			Namespace:       backend.Namespace,
+			Name:            backend.Name,
+			UID:             backend.UID,
+			ResourceVersion: backend.ResourceVersion,
+		}
+		p.eventRecorder.Event(ref, mapEventType(eventType), reason, message)
+		return
+	}
+	log.WithField("backend", backendName).Debug("Failed to find TridentBackend for event.")
+}
+
+// GetVolumePublishSecrets returns the contents of a PV's node publish secret, which the CSI
+// provisioner sets from the csi.storage.k8s.io/node-publish-secret-name parameter of the PV's
+// storage class.  A PV without one has no secrets.
+func (p *Plugin) GetVolumePublishSecrets(volumeName string) (map[string]string, error) {
+
+	pv, err := p.kubeClient.CoreV1().PersistentVolumes().Get(ctx(), volumeName, getOpts)
+	if err != nil {
+		return nil, fmt.Errorf("could not get PV %s; %v", volumeName, err)
+	}
+	if pv.Spec.CSI == nil || pv.Spec.CSI.NodePublishSecretRef == nil {
+		return nil, nil
+	}
+
+	secretRef := pv.Spec.CSI.NodePublishSecretRef
+	secret, err := p.kubeClient.CoreV1().Secrets(secretRef.Namespace).Get(ctx(), secretRef.Name, getOpts)
+	if err != nil {
+		return nil, fmt.Errorf("could not get node publish secret %s/%s of PV %s; %v",
+			secretRef.Namespace, secretRef.Name, volumeName, err)
+	}
+
+	secrets := make(map[string]string, len(secret.Data))
+	for key, value := range secret.Data {
+		secrets[key] = string(value)
+	}
+	return secrets, nil
+}
+
 // mapEventType maps between K8S API event types and Trident CSI helper event types.  The
 // two sets of types may be identical, but the CSI helper interface should not be tightly
 // coupled to Kubernetes.
@@ -318,6 +458,8 @@ func getVolumeConfig(
 		ImportBackendUUID:  getAnnotation(annotations, AnnImportBackendUUID),
 		ImportNotManaged:   notManaged,
 		MountOptions:       strings.Join(storageClass.MountOptions, ","),
+		DeletionPolicy:     getAnnotation(annotations, AnnDeletionPolicy),
+		Pool:               getAnnotation(annotations, AnnPool),
 	}
 }
 
@@ -0,0 +1,110 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package kubernetes
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/netapp/trident/frontend/csi/helpers"
+	netappv1 "github.com/netapp/trident/persistent_store/crd/apis/netapp/v1"
+)
+
+func TestRecordVolumeEventOnPVAndPVC(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	p := &Plugin{
+		eventRecorder: recorder,
+		pvIndexer:     cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{}),
+		pvcIndexer:    cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{}),
+	}
+	_ = p.pvIndexer.Add(&v1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: "pvc-1"},
+		Spec: v1.PersistentVolumeSpec{
+			ClaimRef: &v1.ObjectReference{Name: "data", Namespace: "default"},
+		},
+	})
+	_ = p.pvcIndexer.Add(&v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "data", Namespace: "default"},
+	})
+
+	p.RecordVolumeEvent("pvc-1", helpers.EventTypeWarning, "PublishFailed", "could not publish")
+
+	assert.Len(t, recorder.Events, 2, "the event should be posted on the PV and the PVC")
+	assert.Equal(t, "Warning PublishFailed could not publish", <-recorder.Events)
+}
+
+func TestRecordBackendEvent(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	p := &Plugin{
+		eventRecorder: recorder,
+		tbeIndexer: cache.NewIndexer(cache.MetaNamespaceKeyFunc,
+			cache.Indexers{backendNameIndex: TridentBackendNameKeyFunc}),
+		backendEventsRecorded: make(map[string]time.Time),
+	}
+	_ = p.tbeIndexer.Add(&netappv1.TridentBackend{
+		ObjectMeta:  metav1.ObjectMeta{Name: "tbe-abcde", Namespace: "trident"},
+		BackendName: "ontapnas",
+	})
+
+	p.RecordBackendEvent("ontapnas", helpers.EventTypeWarning, "VolumeCreateFailed", "aggregate full")
+	p.RecordBackendEvent("missing", helpers.EventTypeWarning, "VolumeCreateFailed", "aggregate full")
+
+	assert.Len(t, recorder.Events, 1)
+	assert.Equal(t, "Warning VolumeCreateFailed aggregate full", <-recorder.Events)
+
+	// Repeated events are posted again only once the period has passed
+	p.RecordBackendEvent("ontapnas", helpers.EventTypeWarning, "VolumeCreateFailed", "aggregate full")
+	assert.Len(t, recorder.Events, 0)
+
+	p.backendEventsRecorded["ontapnas/VolumeCreateFailed"] = time.Now().Add(-BackendEventPeriod)
+	p.RecordBackendEvent("ontapnas", helpers.EventTypeWarning, "VolumeCreateFailed", "aggregate full")
+	assert.Len(t, recorder.Events, 1)
+}
+
+func TestGetVolumePublishSecrets(t *testing.T) {
+	p := &Plugin{
+		kubeClient: k8sfake.NewSimpleClientset(
+			&v1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{Name: "pvc-chap"},
+				Spec: v1.PersistentVolumeSpec{
+					PersistentVolumeSource: v1.PersistentVolumeSource{
+						CSI: &v1.CSIPersistentVolumeSource{
+							NodePublishSecretRef: &v1.SecretReference{Name: "tenant1-chap", Namespace: "tenant1"},
+						},
+					},
+				},
+			},
+			&v1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{Name: "pvc-plain"},
+				Spec: v1.PersistentVolumeSpec{
+					PersistentVolumeSource: v1.PersistentVolumeSource{CSI: &v1.CSIPersistentVolumeSource{}},
+				},
+			},
+			&v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "tenant1-chap", Namespace: "tenant1"},
+				Data: map[string][]byte{
+					"chapUsername":        []byte("tenant1"),
+					"chapInitiatorSecret": []byte("secret1234567"),
+				},
+			},
+		),
+	}
+
+	secrets, err := p.GetVolumePublishSecrets("pvc-chap")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"chapUsername": "tenant1", "chapInitiatorSecret": "secret1234567"}, secrets)
+
+	secrets, err = p.GetVolumePublishSecrets("pvc-plain")
+	assert.NoError(t, err)
+	assert.Nil(t, secrets)
+
+	_, err = p.GetVolumePublishSecrets("pvc-missing")
+	assert.Error(t, err)
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"strconv"
 
+	"github.com/google/uuid"
 	log "github.com/sirupsen/logrus"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -25,35 +26,11 @@ import (
 func (p *Plugin) ImportVolume(request *storage.ImportVolumeRequest) (*storage.VolumeExternal, error) {
 	log.WithField("request", request).Debug("ImportVolume")
 
-	// Get PVC from ImportVolumeRequest
-	jsonData, err := base64.StdEncoding.DecodeString(request.PVCData)
+	claim, err := p.getImportPVC(request)
 	if err != nil {
-		return nil, fmt.Errorf("the pvcData field does not contain valid base64-encoded data: %v", err)
-	}
-
-	claim := &v1.PersistentVolumeClaim{}
-	err = json.Unmarshal(jsonData, &claim)
-	if err != nil {
-		return nil, fmt.Errorf("could not parse JSON PVC: %v", err)
-	}
-
-	log.WithFields(log.Fields{
-		"PVC":               claim.Name,
-		"PVC_storageClass":  getStorageClassForPVC(claim),
-		"PVC_accessModes":   claim.Spec.AccessModes,
-		"PVC_annotations":   claim.Annotations,
-		"PVC_volume":        claim.Spec.VolumeName,
-		"PVC_requestedSize": claim.Spec.Resources.Requests[v1.ResourceStorage],
-	}).Debug("ImportVolume: received PVC data.")
-
-	if err = p.checkValidStorageClassReceived(claim); err != nil {
 		return nil, err
 	}
 
-	if len(claim.Namespace) == 0 {
-		return nil, fmt.Errorf("a valid PVC namespace is required for volume import")
-	}
-
 	// Lookup backend ID from given name
 	backend, err := p.orchestrator.GetBackend(request.Backend)
 	if err != nil {
@@ -110,6 +87,90 @@ func (p *Plugin) ImportVolume(request *storage.ImportVolumeRequest) (*storage.Vo
 	return volume, nil
 }
 
+// ImportVolumeDryRun runs all of the checks for importing a volume and reports the volume the import
+// would create, along with the changes it would make on the storage system, without creating a PVC
+// or changing the volume.
+func (p *Plugin) ImportVolumeDryRun(request *storage.ImportVolumeRequest) (*storage.ImportVolumeDryRunResult, error) {
+	log.WithField("request", request).Debug("ImportVolumeDryRun")
+
+	claim, err := p.getImportPVC(request)
+	if err != nil {
+		return nil, err
+	}
+
+	backend, err := p.orchestrator.GetBackend(request.Backend)
+	if err != nil {
+		return nil, fmt.Errorf("could not find backend %s; %v", request.Backend, err)
+	}
+
+	sc, err := p.getStorageClass(getStorageClassForPVC(claim))
+	if err != nil {
+		return nil, err
+	}
+
+	if claim.Annotations == nil {
+		claim.Annotations = map[string]string{}
+	}
+	claim.Annotations[AnnNotManaged] = strconv.FormatBool(request.NoManage)
+	claim.Annotations[AnnImportOriginalName] = request.InternalName
+	claim.Annotations[AnnImportBackendUUID] = backend.BackendUUID
+
+	// The PV is named for the UID of the PVC that a real import creates, so use a placeholder UID.  The
+	// volume's size is determined by the driver.
+	pvName := fmt.Sprintf("pvc-%s", uuid.New().String())
+	volumeConfig := getVolumeConfig(claim.Spec.AccessModes, claim.Spec.VolumeMode, pvName, resource.Quantity{},
+		processPVCAnnotations(claim, ""), sc)
+	volumeConfig.Namespace = claim.Namespace
+	volumeConfig.NamespaceLabels = p.getNamespaceLabels(claim.Namespace)
+
+	result, err := p.orchestrator.ImportVolumeDryRun(volumeConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	log.WithFields(log.Fields{
+		"volume":  request.InternalName,
+		"changes": result.Changes,
+	}).Debug("ImportVolumeDryRun: volume may be imported.")
+
+	return result, nil
+}
+
+// getImportPVC decodes and validates the PVC in a volume import request.
+func (p *Plugin) getImportPVC(request *storage.ImportVolumeRequest) (*v1.PersistentVolumeClaim, error) {
+
+	// Get PVC from ImportVolumeRequest
+	jsonData, err := base64.StdEncoding.DecodeString(request.PVCData)
+	if err != nil {
+		return nil, fmt.Errorf("the pvcData field does not contain valid base64-encoded data: %v", err)
+	}
+
+	claim := &v1.PersistentVolumeClaim{}
+	err = json.Unmarshal(jsonData, &claim)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse JSON PVC: %v", err)
+	}
+
+	log.WithFields(log.Fields{
+		"PVC":               claim.Name,
+		"PVC_storageClass":  getStorageClassForPVC(claim),
+		"PVC_accessModes":   claim.Spec.AccessModes,
+		"PVC_annotations":   claim.Annotations,
+		"PVC_volume":        claim.Spec.VolumeName,
+		"PVC_requestedSize": claim.Spec.Resources.Requests[v1.ResourceStorage],
+	}).Debug("ImportVolume: received PVC data.")
+
+	if err = p.checkValidStorageClassReceived(claim); err != nil {
+		return nil, err
+	}
+
+	if len(claim.Namespace) == 0 {
+		return nil, fmt.Errorf("a valid PVC namespace is required for volume import")
+	}
+
+	return claim, nil
+}
+
 func (p *Plugin) createImportPVC(claim *v1.PersistentVolumeClaim) (*v1.PersistentVolumeClaim, error) {
 
 	log.WithFields(log.Fields{
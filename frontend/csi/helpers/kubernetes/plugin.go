@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"io/ioutil"
 	"regexp"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
@@ -16,8 +18,10 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	k8sversion "k8s.io/apimachinery/pkg/version"
 	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
 	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
@@ -32,6 +36,8 @@ import (
 	"github.com/netapp/trident/frontend"
 	"github.com/netapp/trident/frontend/csi"
 	"github.com/netapp/trident/frontend/csi/helpers"
+	netappv1 "github.com/netapp/trident/persistent_store/crd/apis/netapp/v1"
+	"github.com/netapp/trident/persistent_store/crd/client/clientset/versioned"
 	"github.com/netapp/trident/storage"
 	storageattribute "github.com/netapp/trident/storage_attribute"
 	storageclass "github.com/netapp/trident/storage_class"
@@ -39,8 +45,9 @@ import (
 )
 
 const (
-	uidIndex  = "uid"
-	nameIndex = "name"
+	uidIndex         = "uid"
+	nameIndex        = "name"
+	backendNameIndex = "backendName"
 
 	eventAdd    = "add"
 	eventUpdate = "update"
@@ -56,12 +63,16 @@ var (
 type K8SHelperPlugin interface {
 	frontend.Plugin
 	UpgradeVolume(request *storage.UpgradeVolumeRequest) (*storage.VolumeExternal, error)
+	GetNodeTopologyLabels(nodeName string) (map[string]string, error)
+	ResolveBackendSecret(configJSON string) (string, error)
 }
 
 type Plugin struct {
 	orchestrator  core.Orchestrator
 	kubeConfig    rest.Config
 	kubeClient    kubernetes.Interface
+	crdClient     versioned.Interface
+	dynamicClient dynamic.Interface
 	kubeVersion   *k8sversion.Info
 	namespace     string
 	eventRecorder record.EventRecorder
@@ -85,6 +96,47 @@ type Plugin struct {
 	nodeController         cache.SharedIndexInformer
 	nodeControllerStopChan chan struct{}
 	nodeSource             cache.ListerWatcher
+
+	secretController         cache.SharedIndexInformer
+	secretControllerStopChan chan struct{}
+	secretSource             cache.ListerWatcher
+
+	tbeIndexer            cache.Indexer
+	tbeController         cache.SharedIndexInformer
+	tbeControllerStopChan chan struct{}
+	tbeSource             cache.ListerWatcher
+
+	// backendEventsRecorded records when each backend event was last posted, so repeats are limited
+	backendEventsRecorded map[string]time.Time
+	backendEventsLock     sync.Mutex
+
+	snapshotScheduleStopChan chan struct{}
+
+	autogrowStopChan chan struct{}
+	// autogrowResized records when each volume was last expanded, so stale usage isn't acted on
+	autogrowResized map[string]time.Time
+	// autogrowWarned records the volumes that can't be expanded, so they are reported once
+	autogrowWarned map[string]bool
+
+	volumeGroupStopChan chan struct{}
+	cloneGrantStopChan  chan struct{}
+	quotaStopChan       chan struct{}
+
+	backendConfigStopChan chan struct{}
+	// backendConfigTrigger requests a reconciliation of the backend configs, such as when a secret changes
+	backendConfigTrigger chan struct{}
+
+	volumeHealthStopChan chan struct{}
+	// volumeHealthReported records the problem last reported for each abnormal volume
+	volumeHealthReported map[string]string
+
+	storageCapacityStopChan chan struct{}
+	// storageCapacityResource is the CSIStorageCapacity API served by the cluster
+	storageCapacityResource *schema.GroupVersionResource
+
+	populatorStopChan chan struct{}
+
+	attachmentStopChan chan struct{}
 }
 
 // NewPlugin instantiates this plugin when running outside a pod.
@@ -138,6 +190,18 @@ func newKubernetesPlugin(orchestrator core.Orchestrator, kubeConfig *rest.Config
 		return nil, err
 	}
 
+	// Create the CRD client, used to read snapshot schedules
+	crdClient, err := versioned.NewForConfig(kubeConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create the dynamic client, used to manage VolumeSnapshots without depending on their API types
+	dynamicClient, err := dynamic.NewForConfig(kubeConfig)
+	if err != nil {
+		return nil, err
+	}
+
 	// Get the Kubernetes version
 	kubeVersion, err := kubeClient.Discovery().ServerVersion()
 	if err != nil {
@@ -145,15 +209,34 @@ func newKubernetesPlugin(orchestrator core.Orchestrator, kubeConfig *rest.Config
 	}
 
 	p := &Plugin{
-		orchestrator:           orchestrator,
-		kubeConfig:             *kubeConfig,
-		kubeClient:             kubeClient,
-		kubeVersion:            kubeVersion,
-		pvcControllerStopChan:  make(chan struct{}),
-		pvControllerStopChan:   make(chan struct{}),
-		scControllerStopChan:   make(chan struct{}),
-		nodeControllerStopChan: make(chan struct{}),
-		namespace:              namespace,
+		orchestrator:             orchestrator,
+		kubeConfig:               *kubeConfig,
+		kubeClient:               kubeClient,
+		crdClient:                crdClient,
+		dynamicClient:            dynamicClient,
+		kubeVersion:              kubeVersion,
+		pvcControllerStopChan:    make(chan struct{}),
+		pvControllerStopChan:     make(chan struct{}),
+		scControllerStopChan:     make(chan struct{}),
+		nodeControllerStopChan:   make(chan struct{}),
+		secretControllerStopChan: make(chan struct{}),
+		tbeControllerStopChan:    make(chan struct{}),
+		backendEventsRecorded:    make(map[string]time.Time),
+		snapshotScheduleStopChan: make(chan struct{}),
+		autogrowStopChan:         make(chan struct{}),
+		autogrowResized:          make(map[string]time.Time),
+		autogrowWarned:           make(map[string]bool),
+		volumeGroupStopChan:      make(chan struct{}),
+		cloneGrantStopChan:       make(chan struct{}),
+		quotaStopChan:            make(chan struct{}),
+		backendConfigStopChan:    make(chan struct{}),
+		backendConfigTrigger:     make(chan struct{}, 1),
+		volumeHealthStopChan:     make(chan struct{}),
+		volumeHealthReported:     make(map[string]string),
+		storageCapacityStopChan:  make(chan struct{}),
+		populatorStopChan:        make(chan struct{}),
+		attachmentStopChan:       make(chan struct{}),
+		namespace:                namespace,
 	}
 
 	log.WithFields(log.Fields{
@@ -298,6 +381,49 @@ func newKubernetesPlugin(orchestrator core.Orchestrator, kubeConfig *rest.Config
 		},
 	)
 
+	// Set up a watch for secrets in Trident's namespace, which may hold backend credentials
+	p.secretSource = &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			return kubeClient.CoreV1().Secrets(namespace).List(ctx(), options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			return kubeClient.CoreV1().Secrets(namespace).Watch(ctx(), options)
+		},
+	}
+
+	// Set up the secret controller, which reapplies backend configs when their credentials change
+	p.secretController = cache.NewSharedIndexInformer(
+		p.secretSource,
+		&v1.Secret{},
+		CacheSyncPeriod,
+		cache.Indexers{},
+	)
+	p.secretController.AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    p.addSecret,
+			UpdateFunc: p.updateSecret,
+		},
+	)
+
+	// Set up a watch for TridentBackends, which events about backends are posted on
+	p.tbeSource = &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			return crdClient.TridentV1().TridentBackends(namespace).List(ctx(), options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			return crdClient.TridentV1().TridentBackends(namespace).Watch(ctx(), options)
+		},
+	}
+
+	// Set up the TridentBackend indexing controller
+	p.tbeController = cache.NewSharedIndexInformer(
+		p.tbeSource,
+		&netappv1.TridentBackend{},
+		CacheSyncPeriod,
+		cache.Indexers{backendNameIndex: TridentBackendNameKeyFunc},
+	)
+	p.tbeIndexer = p.tbeController.GetIndexer()
+
 	return p, nil
 }
 
@@ -317,6 +443,19 @@ func MetaUIDKeyFunc(obj interface{}) ([]string, error) {
 	return []string{string(objectMeta.GetUID())}, nil
 }
 
+// TridentBackendNameKeyFunc is a KeyFunc which knows how to make keys for TridentBackends.  The key
+// is the name of the backend, which may differ from the name of the TridentBackend.
+func TridentBackendNameKeyFunc(obj interface{}) ([]string, error) {
+	if key, ok := obj.(string); ok {
+		return []string{key}, nil
+	}
+	backend, ok := obj.(*netappv1.TridentBackend)
+	if !ok {
+		return []string{""}, fmt.Errorf("object is not a TridentBackend: %v", obj)
+	}
+	return []string{backend.BackendName}, nil
+}
+
 // MetaNameKeyFunc is a KeyFunc which knows how to make keys for API objects
 // which implement meta.Interface.  The key is the object's name.
 func MetaNameKeyFunc(obj interface{}) ([]string, error) {
@@ -340,6 +479,18 @@ func (p *Plugin) Activate() error {
 	go p.pvController.Run(p.pvControllerStopChan)
 	go p.scController.Run(p.scControllerStopChan)
 	go p.nodeController.Run(p.nodeControllerStopChan)
+	go p.secretController.Run(p.secretControllerStopChan)
+	go p.tbeController.Run(p.tbeControllerStopChan)
+	go p.runSnapshotSchedules()
+	go p.runAutogrow()
+	go p.runVolumeGroups()
+	go p.runCloneGrants()
+	go p.runQuotas()
+	go p.runBackendConfigs()
+	go p.runVolumeHealth()
+	go p.runStorageCapacity()
+	go p.runPopulators()
+	go p.runAttachmentReconciliation()
 
 	// Configure telemetry
 	config.OrchestratorTelemetry.Platform = string(config.PlatformKubernetes)
@@ -359,6 +510,18 @@ func (p *Plugin) Deactivate() error {
 	close(p.pvControllerStopChan)
 	close(p.scControllerStopChan)
 	close(p.nodeControllerStopChan)
+	close(p.secretControllerStopChan)
+	close(p.tbeControllerStopChan)
+	close(p.snapshotScheduleStopChan)
+	close(p.autogrowStopChan)
+	close(p.volumeGroupStopChan)
+	close(p.cloneGrantStopChan)
+	close(p.quotaStopChan)
+	close(p.backendConfigStopChan)
+	close(p.volumeHealthStopChan)
+	close(p.storageCapacityStopChan)
+	close(p.populatorStopChan)
+	close(p.attachmentStopChan)
 	return nil
 }
 
@@ -693,6 +856,12 @@ func (p *Plugin) processAddedStorageClass(sc *k8sstoragev1.StorageClass) {
 
 	// Populate storage class config attributes and backend storage pools
 	for k, v := range sc.Parameters {
+
+		// Ignore parameters reserved by Kubernetes for the CSI sidecars
+		if strings.HasPrefix(k, K8sCSIParameterPrefix) {
+			continue
+		}
+
 		switch k {
 		case K8sFsType:
 			// Ignore Kubernetes-defined storage class parameters handled by CSI
@@ -736,6 +905,152 @@ func (p *Plugin) processAddedStorageClass(sc *k8sstoragev1.StorageClass) {
 			}
 			scConfig.Pools = pools
 
+		case storageattribute.SnapshotRetention:
+			// format:  snapshotRetention: "last=7,daily=7,weekly=4"
+			retention, err := storage.ParseSnapshotRetentionPolicy(v)
+			if err != nil {
+				log.WithFields(log.Fields{
+					"name":        sc.Name,
+					"provisioner": sc.Provisioner,
+					"parameters":  sc.Parameters,
+					"error":       err,
+				}).Errorf("K8S helper could not process the storage class parameter %s", k)
+			}
+			scConfig.SnapshotRetention = retention
+
+		case storageattribute.Autogrow:
+			// format:  autogrow: "threshold=80,increment=10Gi,maxSize=1Ti"
+			autogrow, err := storage.ParseAutogrowPolicy(v)
+			if err != nil {
+				log.WithFields(log.Fields{
+					"name":        sc.Name,
+					"provisioner": sc.Provisioner,
+					"parameters":  sc.Parameters,
+					"error":       err,
+				}).Errorf("K8S helper could not process the storage class parameter %s", k)
+			}
+			scConfig.Autogrow = autogrow
+
+		case storageattribute.MountOptions:
+			// format:  mountOptions: "nfsvers=4.1,hard"
+			if err := tridentutils.ValidateMountOptions(v); err != nil {
+				log.WithFields(log.Fields{
+					"name":        sc.Name,
+					"provisioner": sc.Provisioner,
+					"parameters":  sc.Parameters,
+					"error":       err,
+				}).Errorf("K8S helper could not process the storage class parameter %s", k)
+			}
+			scConfig.MountOptions = v
+
+		case storageattribute.FormatOptions:
+			// format:  formatOptions: "-E nodiscard -b 4096"
+			if err := tridentutils.ValidateFormatOptions(v); err != nil {
+				log.WithFields(log.Fields{
+					"name":        sc.Name,
+					"provisioner": sc.Provisioner,
+					"parameters":  sc.Parameters,
+					"error":       err,
+				}).Errorf("K8S helper could not process the storage class parameter %s", k)
+			}
+			scConfig.FormatOptions = v
+
+		case storageattribute.FsckPolicy:
+			// format:  fsckPolicy: "auto"
+			if err := tridentutils.ValidateFsckPolicy(v); err != nil {
+				log.WithFields(log.Fields{
+					"name":        sc.Name,
+					"provisioner": sc.Provisioner,
+					"parameters":  sc.Parameters,
+					"error":       err,
+				}).Errorf("K8S helper could not process the storage class parameter %s", k)
+			}
+			scConfig.FsckPolicy = v
+
+		case storageattribute.IOScheduler:
+			// format:  ioScheduler: "none"
+			if err := tridentutils.ValidateIOScheduler(v); err != nil {
+				log.WithFields(log.Fields{
+					"name":        sc.Name,
+					"provisioner": sc.Provisioner,
+					"parameters":  sc.Parameters,
+					"error":       err,
+				}).Errorf("K8S helper could not process the storage class parameter %s", k)
+			}
+			scConfig.IOScheduler = v
+
+		case storageattribute.ReadAheadKB:
+			// format:  readAheadKB: "128"
+			if err := tridentutils.ValidateReadAheadKB(v); err != nil {
+				log.WithFields(log.Fields{
+					"name":        sc.Name,
+					"provisioner": sc.Provisioner,
+					"parameters":  sc.Parameters,
+					"error":       err,
+				}).Errorf("K8S helper could not process the storage class parameter %s", k)
+			}
+			scConfig.ReadAheadKB = v
+
+		case storageattribute.NrRequests:
+			// format:  nrRequests: "256"
+			if err := tridentutils.ValidateNrRequests(v); err != nil {
+				log.WithFields(log.Fields{
+					"name":        sc.Name,
+					"provisioner": sc.Provisioner,
+					"parameters":  sc.Parameters,
+					"error":       err,
+				}).Errorf("K8S helper could not process the storage class parameter %s", k)
+			}
+			scConfig.NrRequests = v
+
+		case storageattribute.RootOwnership:
+			// format:  rootOwnership: "0:2000"
+			if _, _, err := tridentutils.ParseOwnership(v); err != nil {
+				log.WithFields(log.Fields{
+					"name":        sc.Name,
+					"provisioner": sc.Provisioner,
+					"parameters":  sc.Parameters,
+					"error":       err,
+				}).Errorf("K8S helper could not process the storage class parameter %s", k)
+			}
+			scConfig.RootOwnership = v
+
+		case storageattribute.DeletionPolicy:
+			// format:  deletionPolicy: "unmanage"
+			if err := storage.ValidateDeletionPolicy(v); err != nil {
+				log.WithFields(log.Fields{
+					"name":        sc.Name,
+					"provisioner": sc.Provisioner,
+					"parameters":  sc.Parameters,
+					"error":       err,
+				}).Errorf("K8S helper could not process the storage class parameter %s", k)
+			}
+			scConfig.DeletionPolicy = v
+
+		case storageattribute.ExportPolicy:
+			// format:  exportPolicy: "team-a"
+			if err := tridentutils.ValidateExportPolicyName(v); err != nil {
+				log.WithFields(log.Fields{
+					"name":        sc.Name,
+					"provisioner": sc.Provisioner,
+					"parameters":  sc.Parameters,
+					"error":       err,
+				}).Errorf("K8S helper could not process the storage class parameter %s", k)
+			}
+			scConfig.ExportPolicy = v
+
+		case storageattribute.IgroupName:
+			// format:  igroupName: "team-a"
+			if err := tridentutils.ValidateIgroupName(v); err != nil {
+				log.WithFields(log.Fields{
+					"name":        sc.Name,
+					"provisioner": sc.Provisioner,
+					"parameters":  sc.Parameters,
+					"error":       err,
+				}).Errorf("K8S helper could not process the storage class parameter %s", k)
+			}
+			scConfig.IgroupName = v
+
 		default:
 			// format:  attribute: "value"
 			req, err := storageattribute.CreateAttributeRequestFromAttributeValue(k, v)
@@ -752,6 +1067,9 @@ func (p *Plugin) processAddedStorageClass(sc *k8sstoragev1.StorageClass) {
 		}
 	}
 
+	// Volumes must be placed where the nodes allowed by the storage class can reach them
+	scConfig.AllowedTopologies = getAllowedTopologies(sc.AllowedTopologies)
+
 	// Add the storage class
 	if _, err := p.orchestrator.AddStorageClass(scConfig); err != nil {
 		log.WithFields(log.Fields{
@@ -879,8 +1197,14 @@ func (p *Plugin) processNode(node *v1.Node, eventType string) {
 	switch eventType {
 	case eventAdd:
 		log.WithFields(logFields).Debug("Node added to cache.")
+		if isNodeOutOfService(node) {
+			p.forceDetachNode(node)
+		}
 	case eventUpdate:
 		log.WithFields(logFields).Debug("Node updated in cache.")
+		if isNodeOutOfService(node) {
+			p.forceDetachNode(node)
+		}
 	case eventDelete:
 		err := p.orchestrator.DeleteNode(node.Name)
 		if err != nil {
@@ -0,0 +1,404 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package kubernetes
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	k8sstoragev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/netapp/trident/frontend/csi"
+	"github.com/netapp/trident/storage"
+	tridentutils "github.com/netapp/trident/utils"
+)
+
+/////////////////////////////////////////////////////////////////////////////
+//
+// This file contains the controller that provisions PVCs whose data source
+// is a custom resource handled by a volume populator.  Kubernetes leaves such
+// PVCs to the populator, so Trident creates the volume itself and binds it to
+// a staging PVC in the same namespace, which the populator mounts to write
+// the volume's contents.  Once the populator annotates the staging PVC as
+// populated and no pod uses it, the volume's PV is rebound to the original
+// PVC and the staging PVC is deleted, so the PVC is only bound to a volume
+// that is ready for use.
+//
+/////////////////////////////////////////////////////////////////////////////
+
+const (
+	// stagingPVCPrefix is followed by the UID of the PVC being populated
+	stagingPVCPrefix = "trident-populate-"
+
+	populationStagedReason   = "PopulationStaged"
+	populationCompleteReason = "PopulationComplete"
+)
+
+// runPopulators periodically provisions and binds the PVCs that are filled by volume populators, until
+// the stop channel is closed.
+func (p *Plugin) runPopulators() {
+
+	ticker := time.NewTicker(PopulatorPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.populatorStopChan:
+			return
+		case <-ticker.C:
+			p.processPopulators()
+		}
+	}
+}
+
+// processPopulators advances the population of each pending PVC with a custom data source, and cleans up
+// after the PVCs that were deleted before their population completed.
+func (p *Plugin) processPopulators() {
+
+	targets := make(map[string]bool)
+	stagingPVCs := make([]*v1.PersistentVolumeClaim, 0)
+
+	for _, obj := range p.pvcIndexer.List() {
+		pvc, ok := obj.(*v1.PersistentVolumeClaim)
+		if !ok {
+			continue
+		}
+		if _, ok := pvc.Labels[LabelPopulatorTarget]; ok {
+			stagingPVCs = append(stagingPVCs, pvc)
+			continue
+		}
+		if !p.isPopulatorPVC(pvc) {
+			continue
+		}
+		targets[string(pvc.UID)] = true
+		if err := p.populateVolume(pvc); err != nil {
+			log.WithFields(log.Fields{
+				"PVC":       pvc.Name,
+				"namespace": pvc.Namespace,
+				"error":     err,
+			}).Error("K8S helper could not populate volume.")
+		}
+	}
+
+	for _, stagingPVC := range stagingPVCs {
+		if !targets[stagingPVC.Labels[LabelPopulatorTarget]] {
+			p.cleanUpStagingPVC(stagingPVC)
+		}
+	}
+}
+
+// isPopulatorPVC returns true if a PVC is waiting for a Trident volume to be filled from a custom data
+// source.  Clusters that set dataSourceRef mirror it to dataSource, which is what Trident reads.
+func (p *Plugin) isPopulatorPVC(pvc *v1.PersistentVolumeClaim) bool {
+
+	if pvc.Status.Phase != v1.ClaimPending || pvc.Spec.VolumeName != "" || pvc.DeletionTimestamp != nil {
+		return false
+	}
+	if !isPopulatorDataSource(pvc.Spec.DataSource) {
+		return false
+	}
+	sc, err := p.getCachedStorageClassByName(getStorageClassForPVC(pvc))
+	if err != nil {
+		return false
+	}
+	return sc.Provisioner == csi.Provisioner
+}
+
+// isPopulatorDataSource returns true if a PVC data source is neither a PVC nor a volume snapshot, which
+// the CSI provisioner clones, and must therefore be handled by a volume populator.
+func isPopulatorDataSource(dataSource *v1.TypedLocalObjectReference) bool {
+	if dataSource == nil || dataSource.APIGroup == nil || *dataSource.APIGroup == "" {
+		return false
+	}
+	return !(*dataSource.APIGroup == volumeSnapshotResource.Group && dataSource.Kind == volumeSnapshotKind)
+}
+
+// populateVolume takes a PVC through one step of population: creating its volume, staging the volume for
+// the populator, and binding the volume to the PVC once it has been populated.  PVs and staging PVCs are
+// read from the API server, as the caches may not yet reflect the previous step.
+func (p *Plugin) populateVolume(pvc *v1.PersistentVolumeClaim) error {
+
+	pvName := "pvc-" + string(pvc.UID)
+	stagingPVCName := stagingPVCPrefix + string(pvc.UID)
+
+	sc, err := p.getCachedStorageClassByName(getStorageClassForPVC(pvc))
+	if err != nil {
+		return err
+	}
+
+	volume, err := p.orchestrator.GetVolume(pvName)
+	if tridentutils.IsNotFoundError(err) {
+		if volume, err = p.addPopulatorVolume(pvc, pvName, sc); err != nil {
+			return err
+		}
+	} else if err != nil {
+		return err
+	}
+
+	pv, err := p.kubeClient.CoreV1().PersistentVolumes().Get(ctx(), pvName, getOpts)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	pvExists := err == nil
+	if pvExists && pv.Spec.ClaimRef != nil && pv.Spec.ClaimRef.UID == pvc.UID {
+		// Population is complete, and Kubernetes will bind the PVC to the PV
+		return nil
+	}
+
+	stagingPVC, err := p.kubeClient.CoreV1().PersistentVolumeClaims(pvc.Namespace).Get(
+		ctx(), stagingPVCName, getOpts)
+	if apierrors.IsNotFound(err) {
+		if stagingPVC, err = p.createStagingPVC(pvc, stagingPVCName, pvName, sc); err != nil {
+			return err
+		}
+	} else if err != nil {
+		return err
+	}
+
+	if !pvExists {
+		if _, err = p.createPopulatorPV(pvc, stagingPVC, volume, sc); err != nil {
+			return err
+		}
+		p.eventRecorder.Event(pvc, v1.EventTypeNormal, populationStagedReason,
+			fmt.Sprintf("volume is bound to PVC %s for population", stagingPVCName))
+		return nil
+	}
+
+	if populated, _ := strconv.ParseBool(stagingPVC.Annotations[AnnPopulated]); !populated {
+		return nil
+	}
+	ownedPods, nakedPods, err := p.getPodsForPVC(stagingPVC)
+	if err != nil {
+		return err
+	} else if len(ownedPods)+len(nakedPods) > 0 {
+		log.WithFields(log.Fields{
+			"PVC":       stagingPVC.Name,
+			"namespace": stagingPVC.Namespace,
+		}).Debug("Waiting for the populator's pods to release the staging PVC.")
+		return nil
+	}
+
+	return p.completePopulation(pvc, stagingPVC, pv, sc)
+}
+
+// addPopulatorVolume creates the Trident volume for a PVC that will be populated.
+func (p *Plugin) addPopulatorVolume(
+	pvc *v1.PersistentVolumeClaim, pvName string, sc *k8sstoragev1.StorageClass,
+) (*storage.VolumeExternal, error) {
+
+	pvcSize, ok := pvc.Spec.Resources.Requests[v1.ResourceStorage]
+	if !ok {
+		return nil, fmt.Errorf("PVC %s does not have a valid size", pvc.Name)
+	}
+
+	fsType := sc.Parameters[K8sCSIFsType]
+	if fsType == "" {
+		fsType = sc.Parameters[K8sFsType]
+	}
+
+	volumeConfig := getVolumeConfig(pvc.Spec.AccessModes, pvc.Spec.VolumeMode, pvName, pvcSize,
+		processPVCAnnotations(pvc, fsType), sc)
+	volumeConfig.Namespace = pvc.Namespace
+	volumeConfig.NamespaceLabels = p.getNamespaceLabels(pvc.Namespace)
+
+	volume, err := p.orchestrator.AddVolume(ctx(), volumeConfig)
+	if err != nil {
+		p.eventRecorder.Event(pvc, v1.EventTypeWarning, "ProvisioningFailed", err.Error())
+		return nil, err
+	}
+	p.eventRecorder.Event(pvc, v1.EventTypeNormal, "ProvisioningSuccess", "provisioned a volume")
+
+	return volume, nil
+}
+
+// createStagingPVC creates the PVC that the populator mounts to fill a PVC's volume.  It is bound to the
+// volume's PV before the PV exists, so the CSI provisioner doesn't provision it.
+func (p *Plugin) createStagingPVC(
+	pvc *v1.PersistentVolumeClaim, stagingPVCName, pvName string, sc *k8sstoragev1.StorageClass,
+) (*v1.PersistentVolumeClaim, error) {
+
+	dataSource := pvc.Spec.DataSource
+	stagingPVC := &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      stagingPVCName,
+			Namespace: pvc.Namespace,
+			Labels:    map[string]string{LabelPopulatorTarget: string(pvc.UID)},
+			Annotations: map[string]string{
+				AnnPopulateFor:        pvc.Name,
+				AnnPopulateDataSource: fmt.Sprintf("%s/%s/%s", *dataSource.APIGroup, dataSource.Kind, dataSource.Name),
+			},
+		},
+		Spec: v1.PersistentVolumeClaimSpec{
+			AccessModes:      pvc.Spec.AccessModes,
+			Resources:        pvc.Spec.Resources,
+			StorageClassName: &sc.Name,
+			VolumeMode:       pvc.Spec.VolumeMode,
+			VolumeName:       pvName,
+		},
+	}
+
+	stagingPVC, err := p.kubeClient.CoreV1().PersistentVolumeClaims(pvc.Namespace).Create(
+		ctx(), stagingPVC, createOpts)
+	if err != nil {
+		return nil, fmt.Errorf("could not create staging PVC %s; %v", stagingPVCName, err)
+	}
+	log.WithFields(log.Fields{
+		"PVC":        pvc.Name,
+		"stagingPVC": stagingPVCName,
+		"namespace":  pvc.Namespace,
+	}).Info("K8S helper created staging PVC for volume population.")
+
+	return stagingPVC, nil
+}
+
+// createPopulatorPV creates the PV of a populated volume, bound to its staging PVC.  The PV is retained
+// while it is staged, so the volume outlives the staging PVC.
+func (p *Plugin) createPopulatorPV(
+	pvc, stagingPVC *v1.PersistentVolumeClaim, volume *storage.VolumeExternal, sc *k8sstoragev1.StorageClass,
+) (*v1.PersistentVolume, error) {
+
+	sizeBytes, err := strconv.ParseInt(volume.Config.Size, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse size of volume %s; %v", volume.Config.Name, err)
+	}
+
+	fsType := volume.Config.FileSystem
+	if pvc.Spec.VolumeMode != nil && *pvc.Spec.VolumeMode == v1.PersistentVolumeBlock {
+		fsType = ""
+	}
+
+	pv := &v1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        volume.Config.Name,
+			Annotations: map[string]string{AnnDynamicallyProvisioned: csi.Provisioner},
+		},
+		Spec: v1.PersistentVolumeSpec{
+			Capacity:                      v1.ResourceList{v1.ResourceStorage: *resource.NewQuantity(sizeBytes, resource.BinarySI)},
+			AccessModes:                   pvc.Spec.AccessModes,
+			PersistentVolumeReclaimPolicy: v1.PersistentVolumeReclaimRetain,
+			StorageClassName:              sc.Name,
+			MountOptions:                  sc.MountOptions,
+			VolumeMode:                    pvc.Spec.VolumeMode,
+			ClaimRef: &v1.ObjectReference{
+				Kind:       "PersistentVolumeClaim",
+				APIVersion: "v1",
+				Namespace:  stagingPVC.Namespace,
+				Name:       stagingPVC.Name,
+				UID:        stagingPVC.UID,
+			},
+			PersistentVolumeSource: v1.PersistentVolumeSource{
+				CSI: &v1.CSIPersistentVolumeSource{
+					Driver:       csi.Provisioner,
+					VolumeHandle: volume.Config.Name,
+					FSType:       fsType,
+					VolumeAttributes: map[string]string{
+						"backendUUID":  volume.BackendUUID,
+						"name":         volume.Config.Name,
+						"internalName": volume.Config.InternalName,
+						"protocol":     string(volume.Config.Protocol),
+					},
+				},
+			},
+		},
+	}
+
+	pv, err = p.kubeClient.CoreV1().PersistentVolumes().Create(ctx(), pv, createOpts)
+	if err != nil {
+		return nil, fmt.Errorf("could not create PV %s; %v", volume.Config.Name, err)
+	}
+	return pv, nil
+}
+
+// completePopulation rebinds a populated volume's PV to the PVC that requested it, restoring the storage
+// class reclaim policy, and deletes the staging PVC.
+func (p *Plugin) completePopulation(
+	pvc, stagingPVC *v1.PersistentVolumeClaim, pv *v1.PersistentVolume, sc *k8sstoragev1.StorageClass,
+) error {
+
+	reclaimPolicy := v1.PersistentVolumeReclaimDelete
+	if sc.ReclaimPolicy != nil {
+		reclaimPolicy = *sc.ReclaimPolicy
+	}
+
+	pvClone := pv.DeepCopy()
+	pvClone.Spec.PersistentVolumeReclaimPolicy = reclaimPolicy
+	pvClone.Spec.ClaimRef = &v1.ObjectReference{
+		Kind:       "PersistentVolumeClaim",
+		APIVersion: "v1",
+		Namespace:  pvc.Namespace,
+		Name:       pvc.Name,
+		UID:        pvc.UID,
+	}
+	if _, err := p.kubeClient.CoreV1().PersistentVolumes().Update(ctx(), pvClone, updateOpts); err != nil {
+		return fmt.Errorf("could not bind PV %s to PVC %s; %v", pv.Name, pvc.Name, err)
+	}
+
+	// The PV no longer refers to the staging PVC, so deleting it leaves the volume alone
+	p.deleteStagingPVC(stagingPVC)
+
+	p.eventRecorder.Event(pvc, v1.EventTypeNormal, populationCompleteReason, "volume has been populated")
+	log.WithFields(log.Fields{
+		"PVC":       pvc.Name,
+		"namespace": pvc.Namespace,
+		"PV":        pv.Name,
+	}).Info("K8S helper completed volume population.")
+
+	return nil
+}
+
+// cleanUpStagingPVC deletes a staging PVC whose PVC is no longer being populated.  If the PVC was deleted
+// before population completed, its volume and PV are deleted too.
+func (p *Plugin) cleanUpStagingPVC(stagingPVC *v1.PersistentVolumeClaim) {
+
+	targetUID := stagingPVC.Labels[LabelPopulatorTarget]
+	pvName := "pvc-" + targetUID
+
+	pv, err := p.kubeClient.CoreV1().PersistentVolumes().Get(ctx(), pvName, getOpts)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return
+	}
+	pvExists := err == nil
+	if pvExists && pv.Spec.ClaimRef != nil && string(pv.Spec.ClaimRef.UID) == targetUID {
+		// Population completed, but the staging PVC couldn't be deleted at the time
+		p.deleteStagingPVC(stagingPVC)
+		return
+	}
+
+	if targets, err := p.pvcIndexer.ByIndex(uidIndex, targetUID); err != nil || len(targets) > 0 {
+		return
+	}
+
+	logFields := log.Fields{"stagingPVC": stagingPVC.Name, "namespace": stagingPVC.Namespace, "PV": pvName}
+	log.WithFields(logFields).Info("K8S helper abandoning population of deleted PVC.")
+
+	p.deleteStagingPVC(stagingPVC)
+	if pvExists {
+		if err := p.kubeClient.CoreV1().PersistentVolumes().Delete(ctx(), pvName, deleteOpts); err != nil &&
+			!apierrors.IsNotFound(err) {
+			log.WithFields(logFields).WithField("error", err).Error("K8S helper could not delete PV.")
+			return
+		}
+	}
+	if err := p.orchestrator.DeleteVolume(ctx(), pvName); err != nil && !tridentutils.IsNotFoundError(err) {
+		log.WithFields(logFields).WithField("error", err).Error("K8S helper could not delete volume.")
+	}
+}
+
+// deleteStagingPVC deletes a staging PVC, logging any failure so it is retried on the next pass.
+func (p *Plugin) deleteStagingPVC(stagingPVC *v1.PersistentVolumeClaim) {
+	err := p.kubeClient.CoreV1().PersistentVolumeClaims(stagingPVC.Namespace).Delete(
+		ctx(), stagingPVC.Name, deleteOpts)
+	if err != nil && !apierrors.IsNotFound(err) {
+		log.WithFields(log.Fields{
+			"stagingPVC": stagingPVC.Name,
+			"namespace":  stagingPVC.Namespace,
+			"error":      err,
+		}).Error("K8S helper could not delete staging PVC.")
+	}
+}
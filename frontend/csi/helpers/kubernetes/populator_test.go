@@ -0,0 +1,143 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	k8sstoragev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/netapp/trident/core"
+	"github.com/netapp/trident/frontend/csi"
+	storageclass "github.com/netapp/trident/storage_class"
+	"github.com/netapp/trident/utils"
+)
+
+func TestIsPopulatorDataSource(t *testing.T) {
+
+	group := func(g string) *string { return &g }
+
+	assert.False(t, isPopulatorDataSource(nil))
+	assert.False(t, isPopulatorDataSource(&v1.TypedLocalObjectReference{Kind: "PersistentVolumeClaim", Name: "src"}))
+	assert.False(t, isPopulatorDataSource(&v1.TypedLocalObjectReference{
+		APIGroup: group("snapshot.storage.k8s.io"), Kind: "VolumeSnapshot", Name: "snap"}))
+	assert.True(t, isPopulatorDataSource(&v1.TypedLocalObjectReference{
+		APIGroup: group("populator.example.com"), Kind: "Dataset", Name: "images"}))
+}
+
+func TestProcessPopulators(t *testing.T) {
+
+	orchestrator := core.NewMockOrchestrator()
+	orchestrator.AddMockONTAPNFSBackend("nas", "127.0.0.1")
+	_, _ = orchestrator.AddStorageClass(&storageclass.Config{Name: "gold"})
+
+	recorder := record.NewFakeRecorder(10)
+	p := &Plugin{
+		orchestrator:  orchestrator,
+		eventRecorder: recorder,
+		kubeClient:    k8sfake.NewSimpleClientset(),
+		pvcIndexer:    cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{uidIndex: MetaUIDKeyFunc}),
+		scIndexer:     cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{}),
+	}
+	_ = p.scIndexer.Add(&k8sstoragev1.StorageClass{
+		ObjectMeta:  metav1.ObjectMeta{Name: "gold"},
+		Provisioner: csi.Provisioner,
+	})
+
+	apiGroup := "populator.example.com"
+	newPVC := func(name, uid string) *v1.PersistentVolumeClaim {
+		scName := "gold"
+		return &v1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   "apps",
+				UID:         types.UID(uid),
+				Annotations: map[string]string{AnnProtocol: "file"},
+			},
+			Spec: v1.PersistentVolumeClaimSpec{
+				AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteOnce},
+				Resources: v1.ResourceRequirements{
+					Requests: v1.ResourceList{v1.ResourceStorage: resource.MustParse("1Gi")},
+				},
+				StorageClassName: &scName,
+				DataSource:       &v1.TypedLocalObjectReference{APIGroup: &apiGroup, Kind: "Dataset", Name: "images"},
+			},
+			Status: v1.PersistentVolumeClaimStatus{Phase: v1.ClaimPending},
+		}
+	}
+	getPV := func(name string) *v1.PersistentVolume {
+		pv, err := p.kubeClient.CoreV1().PersistentVolumes().Get(ctx(), name, getOpts)
+		assert.NoError(t, err)
+		return pv
+	}
+
+	const uid = "11111111-2222-3333-4444-555555555555"
+	pvc := newPVC("data", uid)
+	_ = p.pvcIndexer.Add(pvc)
+
+	// The volume is created and bound to a staging PVC for the populator
+	p.processPopulators()
+	_, err := orchestrator.GetVolume("pvc-" + uid)
+	assert.NoError(t, err)
+	stagingPVC, err := p.kubeClient.CoreV1().PersistentVolumeClaims("apps").Get(
+		ctx(), stagingPVCPrefix+uid, getOpts)
+	assert.NoError(t, err)
+	assert.Equal(t, "pvc-"+uid, stagingPVC.Spec.VolumeName)
+	assert.Equal(t, uid, stagingPVC.Labels[LabelPopulatorTarget])
+	assert.Equal(t, "populator.example.com/Dataset/images", stagingPVC.Annotations[AnnPopulateDataSource])
+	pv := getPV("pvc-" + uid)
+	assert.Equal(t, stagingPVC.Name, pv.Spec.ClaimRef.Name)
+	assert.Equal(t, v1.PersistentVolumeReclaimRetain, pv.Spec.PersistentVolumeReclaimPolicy)
+	assert.Equal(t, csi.Provisioner, pv.Spec.CSI.Driver)
+	assert.Len(t, recorder.Events, 2)
+	assert.Contains(t, <-recorder.Events, "ProvisioningSuccess")
+	assert.Contains(t, <-recorder.Events, populationStagedReason)
+
+	// Nothing happens until the populator is done
+	p.processPopulators()
+	assert.Len(t, recorder.Events, 0)
+	assert.Equal(t, stagingPVC.Name, getPV("pvc-"+uid).Spec.ClaimRef.Name)
+
+	stagingPVC.Annotations[AnnPopulated] = "true"
+	_, err = p.kubeClient.CoreV1().PersistentVolumeClaims("apps").Update(ctx(), stagingPVC, updateOpts)
+	assert.NoError(t, err)
+
+	// The PV is handed to the original PVC, and the staging PVC is deleted
+	p.processPopulators()
+	pv = getPV("pvc-" + uid)
+	assert.Equal(t, "data", pv.Spec.ClaimRef.Name)
+	assert.Equal(t, types.UID(uid), pv.Spec.ClaimRef.UID)
+	assert.Equal(t, v1.PersistentVolumeReclaimDelete, pv.Spec.PersistentVolumeReclaimPolicy)
+	_, err = p.kubeClient.CoreV1().PersistentVolumeClaims("apps").Get(ctx(), stagingPVCPrefix+uid, getOpts)
+	assert.Error(t, err)
+	assert.Len(t, recorder.Events, 1)
+	assert.Contains(t, <-recorder.Events, populationCompleteReason)
+
+	// A PVC deleted before population completes has its volume and PV deleted
+	const abandonedUID = "66666666-7777-8888-9999-000000000000"
+	abandonedPVC := newPVC("abandoned", abandonedUID)
+	_ = p.pvcIndexer.Add(abandonedPVC)
+	p.processPopulators()
+	stagingPVC, err = p.kubeClient.CoreV1().PersistentVolumeClaims("apps").Get(
+		ctx(), stagingPVCPrefix+abandonedUID, getOpts)
+	assert.NoError(t, err)
+
+	_ = p.pvcIndexer.Delete(abandonedPVC)
+	stagingPVC.UID = "abcdefab-cdef-abcd-efab-cdefabcdefab"
+	_ = p.pvcIndexer.Add(stagingPVC)
+	p.processPopulators()
+	_, err = p.kubeClient.CoreV1().PersistentVolumes().Get(ctx(), "pvc-"+abandonedUID, getOpts)
+	assert.Error(t, err)
+	_, err = orchestrator.GetVolume("pvc-" + abandonedUID)
+	assert.True(t, utils.IsNotFoundError(err))
+	_, err = orchestrator.GetVolume("pvc-" + uid)
+	assert.NoError(t, err, "populated volume should be kept")
+}
@@ -0,0 +1,153 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package kubernetes
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	netappv1 "github.com/netapp/trident/persistent_store/crd/apis/netapp/v1"
+	"github.com/netapp/trident/storage"
+	tridentutils "github.com/netapp/trident/utils"
+)
+
+/////////////////////////////////////////////////////////////////////////////
+//
+// This file contains the controller that keeps Trident's quotas in sync
+// with TridentQuota CRs, and reports each quota's usage in its CR's status.
+// Trident doesn't persist quotas, so they are all added again when the
+// controller starts.
+//
+/////////////////////////////////////////////////////////////////////////////
+
+// runQuotas periodically reconciles the TridentQuota CRs, until the stop channel is closed.
+func (p *Plugin) runQuotas() {
+
+	ticker := time.NewTicker(QuotaPeriod)
+	defer ticker.Stop()
+
+	// Quotas only exist in memory, so restore them without waiting for the first tick
+	p.processQuotas()
+
+	for {
+		select {
+		case <-p.quotaStopChan:
+			return
+		case <-ticker.C:
+			p.processQuotas()
+		}
+	}
+}
+
+// processQuotas reconciles the TridentQuota CRs in all namespaces with Trident's quotas.
+func (p *Plugin) processQuotas() {
+
+	quotas, err := p.crdClient.TridentV1().TridentQuotas("").List(ctx(), listOpts)
+	if err != nil {
+		log.WithField("error", err).Debug("K8S helper could not list quotas.")
+		return
+	}
+
+	managedQuotas := make(map[string]bool)
+	for _, quota := range quotas.Items {
+		managedQuotas[storage.MakeQuotaID(quota.Namespace, quota.Name)] = true
+		p.processQuota(quota)
+	}
+
+	// Delete the quotas whose CRs no longer exist
+	tridentQuotas, err := p.orchestrator.ListQuotas()
+	if err != nil {
+		log.WithField("error", err).Debug("K8S helper could not list Trident quotas.")
+		return
+	}
+	for _, tridentQuota := range tridentQuotas {
+		if !managedQuotas[tridentQuota.ID()] {
+			p.deleteTridentQuota(tridentQuota.Namespace, tridentQuota.Name)
+		}
+	}
+}
+
+// processQuota adds or replaces the Trident quota for a CR, and records the quota's usage in the
+// CR's status.
+func (p *Plugin) processQuota(quota *netappv1.TridentQuota) {
+
+	tridentQuota, err := getTridentQuota(quota)
+	if err == nil {
+		err = p.orchestrator.AddQuota(tridentQuota)
+	}
+	if err != nil {
+		// Don't leave a stale quota in place if the CR is no longer valid
+		p.deleteTridentQuota(quota.Namespace, quota.Name)
+		p.updateQuotaStatus(quota, netappv1.TridentQuotaStatus{Message: err.Error()})
+		return
+	}
+
+	quotaExternal, err := p.orchestrator.GetQuota(quota.Namespace, quota.Name)
+	if err != nil {
+		p.updateQuotaStatus(quota, netappv1.TridentQuotaStatus{Message: err.Error()})
+		return
+	}
+
+	status := netappv1.TridentQuotaStatus{
+		UsedCapacity: resource.NewQuantity(quotaExternal.UsedBytes, resource.BinarySI).String(),
+		UsedVolumes:  quotaExternal.UsedVolumes,
+	}
+	p.updateQuotaStatus(quota, status)
+}
+
+// getTridentQuota converts a TridentQuota CR to a Trident quota.
+func getTridentQuota(quota *netappv1.TridentQuota) (*storage.Quota, error) {
+
+	tridentQuota := &storage.Quota{
+		Name:         quota.Name,
+		Namespace:    quota.Namespace,
+		StorageClass: quota.Spec.StorageClass,
+		MaxVolumes:   quota.Spec.MaxVolumes,
+	}
+
+	if quota.Spec.MaxCapacity != "" {
+		maxCapacity, err := resource.ParseQuantity(quota.Spec.MaxCapacity)
+		if err != nil {
+			return nil, fmt.Errorf("invalid maxCapacity %s; %v", quota.Spec.MaxCapacity, err)
+		}
+		tridentQuota.MaxBytes = maxCapacity.Value()
+	}
+
+	return tridentQuota, tridentQuota.Validate()
+}
+
+// deleteTridentQuota deletes a Trident quota.  Existing volumes are not affected.
+func (p *Plugin) deleteTridentQuota(namespace, name string) {
+	logFields := log.Fields{"quota": name, "namespace": namespace}
+	if err := p.orchestrator.DeleteQuota(namespace, name); err != nil {
+		if !tridentutils.IsNotFoundError(err) {
+			log.WithFields(logFields).WithField("error", err).Error("K8S helper could not delete quota.")
+		}
+		return
+	}
+	log.WithFields(logFields).Info("K8S helper deleted quota.")
+}
+
+// updateQuotaStatus records the result of a reconciliation in a CR's status.
+func (p *Plugin) updateQuotaStatus(quota *netappv1.TridentQuota, status netappv1.TridentQuotaStatus) {
+
+	if reflect.DeepEqual(quota.Status, status) {
+		return
+	}
+
+	quotaCopy := quota.DeepCopy()
+	quotaCopy.Status = status
+
+	if _, err := p.crdClient.TridentV1().TridentQuotas(quota.Namespace).UpdateStatus(
+		ctx(), quotaCopy, updateOpts); err != nil {
+		log.WithFields(log.Fields{
+			"quota":     quota.Name,
+			"namespace": quota.Namespace,
+			"error":     err,
+		}).Error("K8S helper could not update quota status.")
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/netapp/trident/core"
+	netappv1 "github.com/netapp/trident/persistent_store/crd/apis/netapp/v1"
+	"github.com/netapp/trident/persistent_store/crd/client/clientset/versioned/fake"
+	"github.com/netapp/trident/storage"
+)
+
+func TestProcessQuotas(t *testing.T) {
+	quota := &netappv1.TridentQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: "tenant", Namespace: "tenant1"},
+		Spec: netappv1.TridentQuotaSpec{
+			StorageClass: "gold",
+			MaxCapacity:  "10Gi",
+			MaxVolumes:   5,
+		},
+	}
+	invalidQuota := &netappv1.TridentQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: "invalid", Namespace: "tenant1"},
+		Spec:       netappv1.TridentQuotaSpec{MaxCapacity: "lots"},
+	}
+	orchestrator := core.NewMockOrchestrator()
+	p := &Plugin{
+		orchestrator: orchestrator,
+		crdClient:    fake.NewSimpleClientset(quota, invalidQuota),
+	}
+
+	// A quota without a CR is deleted
+	assert.NoError(t, orchestrator.AddQuota(&storage.Quota{Name: "orphan", Namespace: "tenant2", MaxVolumes: 1}))
+
+	p.processQuotas()
+
+	tridentQuota, err := orchestrator.GetQuota("tenant1", "tenant")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "gold", tridentQuota.StorageClass)
+		assert.Equal(t, int64(10*1024*1024*1024), tridentQuota.MaxBytes)
+		assert.Equal(t, 5, tridentQuota.MaxVolumes)
+	}
+	_, err = orchestrator.GetQuota("tenant1", "invalid")
+	assert.Error(t, err)
+	_, err = orchestrator.GetQuota("tenant2", "orphan")
+	assert.Error(t, err)
+
+	updated, err := p.crdClient.TridentV1().TridentQuotas("tenant1").Get(ctx(), "tenant", getOpts)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "0", updated.Status.UsedCapacity)
+		assert.Empty(t, updated.Status.Message)
+	}
+	updated, err = p.crdClient.TridentV1().TridentQuotas("tenant1").Get(ctx(), "invalid", getOpts)
+	if assert.NoError(t, err) {
+		assert.Contains(t, updated.Status.Message, "invalid maxCapacity")
+	}
+}
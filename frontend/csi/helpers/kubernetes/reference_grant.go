@@ -0,0 +1,178 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package kubernetes
+
+import (
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/netapp/trident/frontend/csi"
+	"github.com/netapp/trident/storage"
+)
+
+/////////////////////////////////////////////////////////////////////////////
+//
+// This file contains the methods that turn Gateway API ReferenceGrants into
+// Trident clone grants, so that a PVC may be restored from a VolumeSnapshot
+// in another namespace when the snapshot's namespace allows it.  Kubernetes
+// checks the ReferenceGrant before asking for the volume, and Trident's core
+// enforces the matching clone grant when the snapshot is restored.  The
+// grants are reconciled along with those from TridentCloneGrant CRs.
+//
+/////////////////////////////////////////////////////////////////////////////
+
+const (
+	// referenceGrantPrefix starts the names of clone grants made from ReferenceGrants.  The colon
+	// can't appear in a Kubernetes name, so they never clash with grants from TridentCloneGrants.
+	referenceGrantPrefix = "referencegrant:"
+
+	persistentVolumeClaimKind = "PersistentVolumeClaim"
+)
+
+var (
+	referenceGrantResource = schema.GroupVersionResource{
+		Group:    "gateway.networking.k8s.io",
+		Version:  "v1beta1",
+		Resource: "referencegrants",
+	}
+	volumeSnapshotContentResource = schema.GroupVersionResource{
+		Group:    volumeSnapshotResource.Group,
+		Version:  volumeSnapshotResource.Version,
+		Resource: "volumesnapshotcontents",
+	}
+)
+
+// processReferenceGrants adds or replaces the Trident clone grant for each ReferenceGrant that lets PVCs
+// refer to VolumeSnapshots, and marks the grants as managed.  It returns false if the ReferenceGrants
+// could not be listed, in which case the grants made from them earlier should be kept.
+func (p *Plugin) processReferenceGrants(managedGrants map[string]bool) bool {
+
+	referenceGrants, err := p.dynamicClient.Resource(referenceGrantResource).Namespace(v1.NamespaceAll).List(
+		ctx(), listOpts)
+	if err != nil {
+		// Without the Gateway API CRDs, there are no ReferenceGrants
+		if apierrors.IsNotFound(err) {
+			return true
+		}
+		log.WithField("error", err).Debug("K8S helper could not list reference grants.")
+		return false
+	}
+
+	for i := range referenceGrants.Items {
+		grant := p.getReferenceGrantCloneGrant(&referenceGrants.Items[i])
+		if grant == nil {
+			continue
+		}
+		if err := p.orchestrator.AddCloneGrant(grant); err != nil {
+			log.WithFields(log.Fields{
+				"referenceGrant": referenceGrants.Items[i].GetName(),
+				"namespace":      grant.Namespace,
+				"error":          err,
+			}).Error("K8S helper could not add clone grant for reference grant.")
+			continue
+		}
+		managedGrants[grant.ID()] = true
+	}
+
+	return true
+}
+
+// isReferenceGrantName returns true if a Trident clone grant was made from a ReferenceGrant.
+func isReferenceGrantName(name string) bool {
+	return strings.HasPrefix(name, referenceGrantPrefix)
+}
+
+// getReferenceGrantCloneGrant returns the clone grant equivalent to a ReferenceGrant, or nil if the
+// ReferenceGrant doesn't let PVCs refer to any Trident snapshots.
+func (p *Plugin) getReferenceGrantCloneGrant(referenceGrant *unstructured.Unstructured) *storage.CloneGrant {
+
+	namespace := referenceGrant.GetNamespace()
+
+	targetNamespaces := make([]string, 0)
+	from, _, _ := unstructured.NestedSlice(referenceGrant.Object, "spec", "from")
+	for _, entry := range from {
+		group, kind, name := getReferenceGrantEntry(entry, "namespace")
+		if group == "" && kind == persistentVolumeClaimKind && name != "" {
+			targetNamespaces = append(targetNamespaces, name)
+		}
+	}
+
+	snapshotIDs := make([]string, 0)
+	to, _, _ := unstructured.NestedSlice(referenceGrant.Object, "spec", "to")
+	for _, entry := range to {
+		group, kind, name := getReferenceGrantEntry(entry, "name")
+		if group != volumeSnapshotResource.Group || kind != volumeSnapshotKind {
+			continue
+		}
+		if name == "" {
+			snapshotIDs = append(snapshotIDs, storage.CloneGrantAll)
+			continue
+		}
+		snapshotID, err := p.getVolumeSnapshotID(namespace, name)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"referenceGrant": referenceGrant.GetName(),
+				"namespace":      namespace,
+				"snapshot":       name,
+			}).Debugf("K8S helper could not resolve snapshot in reference grant; %v", err)
+			continue
+		}
+		snapshotIDs = append(snapshotIDs, snapshotID)
+	}
+
+	if len(targetNamespaces) == 0 || len(snapshotIDs) == 0 {
+		return nil
+	}
+
+	return &storage.CloneGrant{
+		Name:             referenceGrantPrefix + referenceGrant.GetName(),
+		Namespace:        namespace,
+		Snapshots:        snapshotIDs,
+		TargetNamespaces: targetNamespaces,
+	}
+}
+
+// getReferenceGrantEntry returns the group, kind and the named field of an entry in a ReferenceGrant's
+// from or to list.
+func getReferenceGrantEntry(entry interface{}, field string) (string, string, string) {
+	fields, ok := entry.(map[string]interface{})
+	if !ok {
+		return "", "", ""
+	}
+	group, _, _ := unstructured.NestedString(fields, "group")
+	kind, _, _ := unstructured.NestedString(fields, "kind")
+	value, _, _ := unstructured.NestedString(fields, field)
+	return group, kind, value
+}
+
+// getVolumeSnapshotID returns the ID of the Trident snapshot bound to a VolumeSnapshot.
+func (p *Plugin) getVolumeSnapshotID(namespace, name string) (string, error) {
+
+	snapshot, err := p.dynamicClient.Resource(volumeSnapshotResource).Namespace(namespace).Get(ctx(), name, getOpts)
+	if err != nil {
+		return "", err
+	}
+	contentName, _, _ := unstructured.NestedString(snapshot.Object, "status", "boundVolumeSnapshotContentName")
+	if contentName == "" {
+		return "", fmt.Errorf("snapshot %s/%s is not bound", namespace, name)
+	}
+
+	content, err := p.dynamicClient.Resource(volumeSnapshotContentResource).Get(ctx(), contentName, getOpts)
+	if err != nil {
+		return "", err
+	}
+	if driver, _, _ := unstructured.NestedString(content.Object, "spec", "driver"); driver != csi.Provisioner {
+		return "", fmt.Errorf("snapshot %s/%s was not created by %s", namespace, name, csi.Provisioner)
+	}
+	snapshotID, _, _ := unstructured.NestedString(content.Object, "status", "snapshotHandle")
+	if _, _, err := storage.ParseSnapshotID(snapshotID); err != nil {
+		return "", err
+	}
+	return snapshotID, nil
+}
@@ -0,0 +1,99 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/netapp/trident/core"
+	"github.com/netapp/trident/frontend/csi"
+	"github.com/netapp/trident/storage"
+)
+
+// newReferenceGrantDynamicClient returns a fake dynamic client that can list ReferenceGrants.
+func newReferenceGrantDynamicClient(objects ...runtime.Object) *dynamicfake.FakeDynamicClient {
+	scheme := runtime.NewScheme()
+	scheme.AddKnownTypeWithName(schema.GroupVersionKind{
+		Group: referenceGrantResource.Group, Version: referenceGrantResource.Version, Kind: "ReferenceGrantList",
+	}, &unstructured.UnstructuredList{})
+	return dynamicfake.NewSimpleDynamicClient(scheme, objects...)
+}
+
+func TestGetReferenceGrantCloneGrant(t *testing.T) {
+
+	newObject := func(apiVersion, kind, namespace, name string, fields map[string]interface{}) *unstructured.Unstructured {
+		object := &unstructured.Unstructured{Object: fields}
+		object.SetAPIVersion(apiVersion)
+		object.SetKind(kind)
+		object.SetNamespace(namespace)
+		object.SetName(name)
+		return object
+	}
+
+	referenceGrant := newObject("gateway.networking.k8s.io/v1beta1", "ReferenceGrant", "datasets", "tenants",
+		map[string]interface{}{
+			"spec": map[string]interface{}{
+				"from": []interface{}{
+					map[string]interface{}{"group": "", "kind": "PersistentVolumeClaim", "namespace": "tenant1"},
+					map[string]interface{}{"group": "", "kind": "PersistentVolumeClaim", "namespace": "tenant2"},
+					map[string]interface{}{"group": "gateway.networking.k8s.io", "kind": "HTTPRoute",
+						"namespace": "web"},
+				},
+				"to": []interface{}{
+					map[string]interface{}{"group": "snapshot.storage.k8s.io", "kind": "VolumeSnapshot",
+						"name": "golden"},
+					map[string]interface{}{"group": "snapshot.storage.k8s.io", "kind": "VolumeSnapshot",
+						"name": "unbound"},
+					map[string]interface{}{"group": "", "kind": "Secret", "name": "credentials"},
+				},
+			},
+		})
+	objects := []runtime.Object{
+		referenceGrant,
+		newObject(volumeSnapshotAPIVersion, volumeSnapshotKind, "datasets", "golden", map[string]interface{}{
+			"status": map[string]interface{}{"boundVolumeSnapshotContentName": "snapcontent-1"},
+		}),
+		newObject(volumeSnapshotAPIVersion, volumeSnapshotKind, "datasets", "unbound", map[string]interface{}{}),
+		newObject(volumeSnapshotAPIVersion, "VolumeSnapshotContent", "", "snapcontent-1", map[string]interface{}{
+			"spec":   map[string]interface{}{"driver": csi.Provisioner},
+			"status": map[string]interface{}{"snapshotHandle": "pvc-1/snapshot-1"},
+		}),
+	}
+
+	p := &Plugin{
+		orchestrator:  core.NewMockOrchestrator(),
+		dynamicClient: newReferenceGrantDynamicClient(objects...),
+	}
+
+	grant := p.getReferenceGrantCloneGrant(referenceGrant)
+	if assert.NotNil(t, grant) {
+		assert.Equal(t, "datasets", grant.Namespace)
+		assert.Equal(t, "referencegrant:tenants", grant.Name)
+		assert.True(t, isReferenceGrantName(grant.Name))
+		assert.Equal(t, []string{"pvc-1/snapshot-1"}, grant.Snapshots)
+		assert.Empty(t, grant.Volumes)
+		assert.Equal(t, []string{"tenant1", "tenant2"}, grant.TargetNamespaces)
+	}
+
+	managedGrants := make(map[string]bool)
+	assert.True(t, p.processReferenceGrants(managedGrants))
+	assert.Equal(t, map[string]bool{"datasets/referencegrant:tenants": true}, managedGrants)
+
+	// A grant to all VolumeSnapshots in the namespace doesn't need them resolved
+	spec := referenceGrant.Object["spec"].(map[string]interface{})
+	spec["to"] = []interface{}{map[string]interface{}{"group": "snapshot.storage.k8s.io", "kind": "VolumeSnapshot"}}
+	grant = p.getReferenceGrantCloneGrant(referenceGrant)
+	if assert.NotNil(t, grant) {
+		assert.Equal(t, []string{storage.CloneGrantAll}, grant.Snapshots)
+	}
+
+	// Grants that don't let PVCs refer to snapshots are ignored
+	spec["to"] = []interface{}{map[string]interface{}{"group": "", "kind": "Secret"}}
+	assert.Nil(t, p.getReferenceGrantCloneGrant(referenceGrant))
+}
@@ -0,0 +1,314 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package kubernetes
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	netappv1 "github.com/netapp/trident/persistent_store/crd/apis/netapp/v1"
+	tridentutils "github.com/netapp/trident/utils"
+)
+
+const (
+	volumeSnapshotAPIVersion = "snapshot.storage.k8s.io/v1beta1"
+	volumeSnapshotKind       = "VolumeSnapshot"
+
+	// Kubernetes object names are limited to 253 characters
+	maxVolumeSnapshotNameLength = 253
+)
+
+var volumeSnapshotResource = schema.GroupVersionResource{
+	Group:    "snapshot.storage.k8s.io",
+	Version:  "v1beta1",
+	Resource: "volumesnapshots",
+}
+
+// runSnapshotSchedules periodically runs any TridentSnapshotSchedules that are due, until the
+// stop channel is closed.
+func (p *Plugin) runSnapshotSchedules() {
+
+	ticker := time.NewTicker(SnapshotSchedulePeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.snapshotScheduleStopChan:
+			return
+		case <-ticker.C:
+			p.processSnapshotSchedules(time.Now().UTC())
+		}
+	}
+}
+
+// processSnapshotSchedules runs each snapshot schedule in Trident's namespace that is due at the
+// specified time.
+func (p *Plugin) processSnapshotSchedules(now time.Time) {
+
+	schedules, err := p.crdClient.TridentV1().TridentSnapshotSchedules(p.namespace).List(ctx(), listOpts)
+	if err != nil {
+		log.WithField("error", err).Debug("K8S helper could not list snapshot schedules.")
+		return
+	}
+
+	for _, schedule := range schedules.Items {
+		p.processSnapshotSchedule(schedule, now)
+	}
+}
+
+// processSnapshotSchedule creates a VolumeSnapshot of each PVC matched by a snapshot schedule if the
+// schedule is due, prunes older scheduled snapshots beyond the retention count, and records the
+// result in the schedule's status.  Runs missed while Trident was down are collapsed into one.
+func (p *Plugin) processSnapshotSchedule(schedule *netappv1.TridentSnapshotSchedule, now time.Time) {
+
+	logFields := log.Fields{"schedule": schedule.Name}
+
+	if schedule.Spec.Suspend {
+		log.WithFields(logFields).Trace("Snapshot schedule is suspended.")
+		return
+	}
+
+	cron, err := tridentutils.ParseCronSchedule(schedule.Spec.Schedule)
+	if err != nil {
+		p.updateSnapshotScheduleStatus(schedule, schedule.Status.LastScheduleTime, 0,
+			fmt.Sprintf("invalid schedule; %v", err))
+		return
+	}
+
+	lastRun := schedule.CreationTimestamp.Time
+	if schedule.Status.LastScheduleTime != "" {
+		if lastRun, err = time.Parse(time.RFC3339, schedule.Status.LastScheduleTime); err != nil {
+			lastRun = schedule.CreationTimestamp.Time
+		}
+	}
+	if next := cron.Next(lastRun.UTC()); next.IsZero() || next.After(now) {
+		return
+	}
+
+	pvcs, err := p.getSnapshotSchedulePVCs(schedule)
+	if err != nil {
+		p.updateSnapshotScheduleStatus(schedule, now.Format(time.RFC3339), 0, err.Error())
+		return
+	}
+
+	log.WithFields(logFields).WithField("pvcs", len(pvcs)).Debug("Running snapshot schedule.")
+
+	created := 0
+	errMessages := make([]string, 0)
+	for _, pvc := range pvcs {
+		if err := p.createScheduledSnapshot(schedule, pvc, now); err != nil {
+			errMessages = append(errMessages, err.Error())
+			continue
+		}
+		created++
+		if err := p.pruneScheduledSnapshots(schedule, pvc); err != nil {
+			errMessages = append(errMessages, err.Error())
+		}
+	}
+
+	p.updateSnapshotScheduleStatus(schedule, now.Format(time.RFC3339), created, strings.Join(errMessages, "; "))
+}
+
+// getSnapshotSchedulePVCs returns the bound, Trident-managed PVCs matched by a snapshot schedule.
+func (p *Plugin) getSnapshotSchedulePVCs(
+	schedule *netappv1.TridentSnapshotSchedule,
+) ([]*v1.PersistentVolumeClaim, error) {
+
+	selector := labels.Everything()
+	if schedule.Spec.Selector != nil {
+		var err error
+		if selector, err = metav1.LabelSelectorAsSelector(schedule.Spec.Selector); err != nil {
+			return nil, fmt.Errorf("invalid selector; %v", err)
+		}
+	}
+
+	pvcs := make([]*v1.PersistentVolumeClaim, 0)
+	for _, obj := range p.pvcIndexer.List() {
+		pvc, ok := obj.(*v1.PersistentVolumeClaim)
+		if !ok || !snapshotScheduleMatchesPVC(&schedule.Spec, selector, pvc) {
+			continue
+		}
+		if _, err := p.orchestrator.GetVolume(pvc.Spec.VolumeName); err != nil {
+			continue
+		}
+		pvcs = append(pvcs, pvc)
+	}
+
+	sort.Slice(pvcs, func(i, j int) bool {
+		if pvcs[i].Namespace != pvcs[j].Namespace {
+			return pvcs[i].Namespace < pvcs[j].Namespace
+		}
+		return pvcs[i].Name < pvcs[j].Name
+	})
+
+	return pvcs, nil
+}
+
+// snapshotScheduleMatchesPVC reports whether a bound PVC is matched by the storage classes,
+// namespaces and label selector of a snapshot schedule.  Empty lists match everything.
+func snapshotScheduleMatchesPVC(
+	spec *netappv1.TridentSnapshotScheduleSpec, selector labels.Selector, pvc *v1.PersistentVolumeClaim,
+) bool {
+
+	if pvc.Status.Phase != v1.ClaimBound || pvc.Spec.VolumeName == "" || !pvc.DeletionTimestamp.IsZero() {
+		return false
+	}
+	if len(spec.Namespaces) > 0 && !tridentutils.SliceContainsString(spec.Namespaces, pvc.Namespace) {
+		return false
+	}
+	if len(spec.StorageClasses) > 0 &&
+		!tridentutils.SliceContainsString(spec.StorageClasses, getStorageClassForPVC(pvc)) {
+		return false
+	}
+	return selector.Matches(labels.Set(pvc.Labels))
+}
+
+// getScheduledSnapshotName returns a name for a scheduled snapshot that is unique per schedule,
+// PVC and run.
+func getScheduledSnapshotName(scheduleName, pvcName string, now time.Time) string {
+
+	suffix := "-" + now.UTC().Format("20060102150405")
+	name := scheduleName + "-" + pvcName
+	if len(name)+len(suffix) > maxVolumeSnapshotNameLength {
+		name = strings.TrimRight(name[:maxVolumeSnapshotNameLength-len(suffix)], "-.")
+	}
+	return name + suffix
+}
+
+// createScheduledSnapshot creates a VolumeSnapshot of a PVC, labeled with the snapshot schedule
+// so that it can be found later for pruning.
+func (p *Plugin) createScheduledSnapshot(
+	schedule *netappv1.TridentSnapshotSchedule, pvc *v1.PersistentVolumeClaim, now time.Time,
+) error {
+
+	spec := map[string]interface{}{
+		"source": map[string]interface{}{
+			"persistentVolumeClaimName": pvc.Name,
+		},
+	}
+	if schedule.Spec.VolumeSnapshotClassName != "" {
+		spec["volumeSnapshotClassName"] = schedule.Spec.VolumeSnapshotClassName
+	}
+
+	snapshot := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": volumeSnapshotAPIVersion,
+		"kind":       volumeSnapshotKind,
+		"metadata": map[string]interface{}{
+			"name":      getScheduledSnapshotName(schedule.Name, pvc.Name, now),
+			"namespace": pvc.Namespace,
+			"labels": map[string]interface{}{
+				LabelSnapshotSchedule: schedule.Name,
+			},
+		},
+		"spec": spec,
+	}}
+
+	created, err := p.dynamicClient.Resource(volumeSnapshotResource).Namespace(pvc.Namespace).Create(
+		ctx(), snapshot, createOpts)
+	if err != nil {
+		return fmt.Errorf("could not create snapshot of PVC %s/%s; %v", pvc.Namespace, pvc.Name, err)
+	}
+
+	log.WithFields(log.Fields{
+		"schedule":  schedule.Name,
+		"pvc":       pvc.Name,
+		"namespace": pvc.Namespace,
+		"snapshot":  created.GetName(),
+	}).Info("Created scheduled snapshot.")
+
+	return nil
+}
+
+// pruneScheduledSnapshots deletes the oldest snapshots of a PVC created by a snapshot schedule,
+// so that no more than the schedule's retention count remain.
+func (p *Plugin) pruneScheduledSnapshots(schedule *netappv1.TridentSnapshotSchedule, pvc *v1.PersistentVolumeClaim) error {
+
+	if schedule.Spec.Retain <= 0 {
+		return nil
+	}
+
+	client := p.dynamicClient.Resource(volumeSnapshotResource).Namespace(pvc.Namespace)
+
+	snapshots, err := client.List(ctx(), metav1.ListOptions{
+		LabelSelector: labels.Set{LabelSnapshotSchedule: schedule.Name}.String(),
+	})
+	if err != nil {
+		return fmt.Errorf("could not list snapshots of PVC %s/%s; %v", pvc.Namespace, pvc.Name, err)
+	}
+
+	for _, name := range getScheduledSnapshotsToPrune(snapshots.Items, pvc.Name, schedule.Spec.Retain) {
+		if err := client.Delete(ctx(), name, deleteOpts); err != nil {
+			return fmt.Errorf("could not delete snapshot %s/%s; %v", pvc.Namespace, name, err)
+		}
+		log.WithFields(log.Fields{
+			"schedule":  schedule.Name,
+			"pvc":       pvc.Name,
+			"namespace": pvc.Namespace,
+			"snapshot":  name,
+		}).Info("Pruned scheduled snapshot.")
+	}
+
+	return nil
+}
+
+// getScheduledSnapshotsToPrune returns the names of a PVC's snapshots beyond the newest retain
+// snapshots, ignoring any that are already being deleted.
+func getScheduledSnapshotsToPrune(snapshots []unstructured.Unstructured, pvcName string, retain int) []string {
+
+	pvcSnapshots := make([]unstructured.Unstructured, 0)
+	for _, snapshot := range snapshots {
+		source, _, _ := unstructured.NestedString(snapshot.Object, "spec", "source", "persistentVolumeClaimName")
+		if source == pvcName && snapshot.GetDeletionTimestamp() == nil {
+			pvcSnapshots = append(pvcSnapshots, snapshot)
+		}
+	}
+
+	sort.Slice(pvcSnapshots, func(i, j int) bool {
+		iCreated, jCreated := pvcSnapshots[i].GetCreationTimestamp(), pvcSnapshots[j].GetCreationTimestamp()
+		if !iCreated.Equal(&jCreated) {
+			return jCreated.Before(&iCreated)
+		}
+		return pvcSnapshots[i].GetName() > pvcSnapshots[j].GetName()
+	})
+
+	names := make([]string, 0)
+	for i := retain; i < len(pvcSnapshots); i++ {
+		names = append(names, pvcSnapshots[i].GetName())
+	}
+	return names
+}
+
+// updateSnapshotScheduleStatus records the result of a scheduled run.
+func (p *Plugin) updateSnapshotScheduleStatus(
+	schedule *netappv1.TridentSnapshotSchedule, lastScheduleTime string, created int, message string,
+) {
+
+	if schedule.Status.LastScheduleTime == lastScheduleTime && schedule.Status.Message == message &&
+		schedule.Status.LastSnapshotCount == created {
+		return
+	}
+
+	scheduleCopy := schedule.DeepCopy()
+	scheduleCopy.Status = netappv1.TridentSnapshotScheduleStatus{
+		LastScheduleTime:  lastScheduleTime,
+		LastSnapshotCount: created,
+		Message:           message,
+	}
+
+	if _, err := p.crdClient.TridentV1().TridentSnapshotSchedules(p.namespace).UpdateStatus(
+		ctx(), scheduleCopy, updateOpts); err != nil {
+		log.WithFields(log.Fields{
+			"schedule": schedule.Name,
+			"error":    err,
+		}).Error("K8S helper could not update snapshot schedule status.")
+	}
+}
@@ -0,0 +1,188 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package kubernetes
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/netapp/trident/core"
+	netappv1 "github.com/netapp/trident/persistent_store/crd/apis/netapp/v1"
+	"github.com/netapp/trident/persistent_store/crd/client/clientset/versioned/fake"
+)
+
+func newScheduledSnapshot(name, pvcName string, created time.Time, deleting bool) unstructured.Unstructured {
+	snapshot := unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": volumeSnapshotAPIVersion,
+		"kind":       volumeSnapshotKind,
+		"metadata": map[string]interface{}{
+			"name":      name,
+			"namespace": "default",
+			"labels":    map[string]interface{}{LabelSnapshotSchedule: "hourly"},
+		},
+		"spec": map[string]interface{}{
+			"source": map[string]interface{}{"persistentVolumeClaimName": pvcName},
+		},
+	}}
+	snapshot.SetCreationTimestamp(metav1.NewTime(created))
+	if deleting {
+		deleted := metav1.NewTime(created)
+		snapshot.SetDeletionTimestamp(&deleted)
+	}
+	return snapshot
+}
+
+func newSnapshotSchedulePlugin(schedules ...runtime.Object) *Plugin {
+	scheme := runtime.NewScheme()
+	scheme.AddKnownTypeWithName(schema.GroupVersionKind{
+		Group: volumeSnapshotResource.Group, Version: volumeSnapshotResource.Version, Kind: "VolumeSnapshotList",
+	}, &unstructured.UnstructuredList{})
+
+	return &Plugin{
+		orchestrator:  core.NewMockOrchestrator(),
+		namespace:     "trident",
+		crdClient:     fake.NewSimpleClientset(schedules...),
+		dynamicClient: dynamicfake.NewSimpleDynamicClient(scheme),
+		pvcIndexer:    cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{}),
+	}
+}
+
+func TestSnapshotScheduleMatchesPVC(t *testing.T) {
+
+	storageClass := "gold"
+	pvc := &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "pvc1", Namespace: "prod", Labels: map[string]string{"app": "db"}},
+		Spec:       v1.PersistentVolumeClaimSpec{StorageClassName: &storageClass, VolumeName: "pvc-1"},
+		Status:     v1.PersistentVolumeClaimStatus{Phase: v1.ClaimBound},
+	}
+	appSelector := labels.SelectorFromSet(labels.Set{"app": "db"})
+
+	tests := []struct {
+		spec     netappv1.TridentSnapshotScheduleSpec
+		selector labels.Selector
+		expected bool
+	}{
+		{netappv1.TridentSnapshotScheduleSpec{}, labels.Everything(), true},
+		{netappv1.TridentSnapshotScheduleSpec{Namespaces: []string{"dev", "prod"}}, labels.Everything(), true},
+		{netappv1.TridentSnapshotScheduleSpec{Namespaces: []string{"dev"}}, labels.Everything(), false},
+		{netappv1.TridentSnapshotScheduleSpec{StorageClasses: []string{"gold"}}, labels.Everything(), true},
+		{netappv1.TridentSnapshotScheduleSpec{StorageClasses: []string{"silver"}}, labels.Everything(), false},
+		{netappv1.TridentSnapshotScheduleSpec{}, appSelector, true},
+		{netappv1.TridentSnapshotScheduleSpec{}, labels.SelectorFromSet(labels.Set{"app": "web"}), false},
+	}
+
+	for i, test := range tests {
+		assert.Equal(t, test.expected, snapshotScheduleMatchesPVC(&test.spec, test.selector, pvc), "test %d", i)
+	}
+
+	// Unbound PVCs never match
+	pvc.Status.Phase = v1.ClaimPending
+	assert.False(t, snapshotScheduleMatchesPVC(&netappv1.TridentSnapshotScheduleSpec{}, labels.Everything(), pvc))
+}
+
+func TestGetScheduledSnapshotName(t *testing.T) {
+
+	now := time.Date(2020, 10, 15, 10, 17, 0, 0, time.UTC)
+
+	assert.Equal(t, "hourly-pvc1-20201015101700", getScheduledSnapshotName("hourly", "pvc1", now))
+
+	name := getScheduledSnapshotName("hourly", strings.Repeat("a", 253), now)
+	assert.Len(t, name, maxVolumeSnapshotNameLength)
+	assert.True(t, strings.HasSuffix(name, "-20201015101700"))
+}
+
+func TestGetScheduledSnapshotsToPrune(t *testing.T) {
+
+	now := time.Now().Truncate(time.Second)
+	snapshots := []unstructured.Unstructured{
+		newScheduledSnapshot("snap1", "pvc1", now.Add(-3*time.Hour), false),
+		newScheduledSnapshot("snap2", "pvc1", now.Add(-1*time.Hour), false),
+		newScheduledSnapshot("snap3", "pvc1", now, false),
+		newScheduledSnapshot("snap4", "pvc1", now.Add(-2*time.Hour), false),
+		newScheduledSnapshot("snap5", "pvc1", now.Add(-4*time.Hour), true),
+		newScheduledSnapshot("other", "pvc2", now.Add(-5*time.Hour), false),
+	}
+
+	assert.Equal(t, []string{"snap4", "snap1"}, getScheduledSnapshotsToPrune(snapshots, "pvc1", 2))
+	assert.Empty(t, getScheduledSnapshotsToPrune(snapshots, "pvc1", 4))
+	assert.Empty(t, getScheduledSnapshotsToPrune(snapshots, "pvc2", 1))
+}
+
+func TestCreateAndPruneScheduledSnapshots(t *testing.T) {
+
+	schedule := &netappv1.TridentSnapshotSchedule{
+		ObjectMeta: metav1.ObjectMeta{Name: "hourly", Namespace: "trident"},
+		Spec:       netappv1.TridentSnapshotScheduleSpec{Schedule: "@hourly", Retain: 2, VolumeSnapshotClassName: "csi"},
+	}
+	pvc := &v1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "pvc1", Namespace: "default"}}
+	plugin := newSnapshotSchedulePlugin()
+	client := plugin.dynamicClient.Resource(volumeSnapshotResource).Namespace("default")
+
+	start := time.Date(2020, 10, 15, 10, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, plugin.createScheduledSnapshot(schedule, pvc, start.Add(time.Duration(i)*time.Hour)))
+	}
+
+	snapshot, err := client.Get(ctx(), "hourly-pvc1-20201015100000", getOpts)
+	assert.NoError(t, err)
+	class, _, _ := unstructured.NestedString(snapshot.Object, "spec", "volumeSnapshotClassName")
+	assert.Equal(t, "csi", class)
+	assert.Equal(t, "hourly", snapshot.GetLabels()[LabelSnapshotSchedule])
+
+	// The fake client doesn't set creation times, so the oldest is pruned by name
+	assert.NoError(t, plugin.pruneScheduledSnapshots(schedule, pvc))
+	snapshots, err := client.List(ctx(), listOpts)
+	assert.NoError(t, err)
+	assert.Len(t, snapshots.Items, 2)
+	_, err = client.Get(ctx(), "hourly-pvc1-20201015100000", getOpts)
+	assert.Error(t, err)
+}
+
+func TestProcessSnapshotSchedule(t *testing.T) {
+
+	now := time.Date(2020, 10, 15, 10, 30, 0, 0, time.UTC)
+	newSchedule := func(name, cron string) *netappv1.TridentSnapshotSchedule {
+		return &netappv1.TridentSnapshotSchedule{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name, Namespace: "trident", CreationTimestamp: metav1.NewTime(now.Add(-2 * time.Hour)),
+			},
+			Spec: netappv1.TridentSnapshotScheduleSpec{Schedule: cron},
+		}
+	}
+
+	due := newSchedule("due", "@hourly")
+	notDue := newSchedule("notdue", "@daily")
+	notDue.Status.LastScheduleTime = "2020-10-15T00:00:00Z"
+	suspended := newSchedule("suspended", "@hourly")
+	suspended.Spec.Suspend = true
+	invalid := newSchedule("invalid", "every hour")
+
+	plugin := newSnapshotSchedulePlugin(due, notDue, suspended, invalid)
+	plugin.processSnapshotSchedules(now)
+
+	get := func(name string) *netappv1.TridentSnapshotSchedule {
+		schedule, err := plugin.crdClient.TridentV1().TridentSnapshotSchedules("trident").Get(ctx(), name, getOpts)
+		if err != nil {
+			t.Fatalf("Could not get schedule %s; %v", name, err)
+		}
+		return schedule
+	}
+
+	assert.Equal(t, "2020-10-15T10:30:00Z", get("due").Status.LastScheduleTime)
+	assert.Empty(t, get("due").Status.Message)
+	assert.Equal(t, "2020-10-15T00:00:00Z", get("notdue").Status.LastScheduleTime)
+	assert.Empty(t, get("suspended").Status.LastScheduleTime)
+	assert.Empty(t, get("invalid").Status.LastScheduleTime)
+	assert.Contains(t, get("invalid").Status.Message, "invalid schedule")
+}
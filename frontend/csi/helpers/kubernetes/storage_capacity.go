@@ -0,0 +1,217 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package kubernetes
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/netapp/trident/storage"
+)
+
+/////////////////////////////////////////////////////////////////////////////
+//
+// This file contains the controller that publishes CSIStorageCapacity
+// objects, which let the Kubernetes scheduler avoid placing pods with
+// late-bound PVCs on nodes whose storage is full.  One object is kept per
+// storage class and topology segment, recording the space Trident reports
+// for the class's pools.  The scheduler only consults these objects if the
+// CSIDriver enables storageCapacity.
+//
+/////////////////////////////////////////////////////////////////////////////
+
+const (
+	storageCapacityKind     = "CSIStorageCapacity"
+	storageCapacityResource = "csistoragecapacities"
+	storageCapacityGroup    = "storage.k8s.io"
+)
+
+// storageCapacityVersions lists the API versions that serve CSIStorageCapacity, most preferred first.
+var storageCapacityVersions = []string{"v1", "v1beta1", "v1alpha1"}
+
+// runStorageCapacity periodically publishes the capacity of each storage class, until the stop channel
+// is closed.  It does nothing if the cluster doesn't serve CSIStorageCapacity objects.
+func (p *Plugin) runStorageCapacity() {
+
+	gvr, err := p.discoverStorageCapacityResource()
+	if err != nil {
+		log.WithField("error", err).Info("K8S helper will not publish storage capacity.")
+		return
+	}
+	p.storageCapacityResource = gvr
+
+	ticker := time.NewTicker(StorageCapacityPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.storageCapacityStopChan:
+			return
+		case <-ticker.C:
+			p.processStorageCapacity()
+		}
+	}
+}
+
+// discoverStorageCapacityResource returns the most preferred CSIStorageCapacity API served by the cluster.
+func (p *Plugin) discoverStorageCapacityResource() (*schema.GroupVersionResource, error) {
+
+	for _, version := range storageCapacityVersions {
+		groupVersion := schema.GroupVersion{Group: storageCapacityGroup, Version: version}
+		resources, err := p.kubeClient.Discovery().ServerResourcesForGroupVersion(groupVersion.String())
+		if err != nil {
+			continue
+		}
+		for _, apiResource := range resources.APIResources {
+			if apiResource.Name == storageCapacityResource {
+				gvr := groupVersion.WithResource(storageCapacityResource)
+				return &gvr, nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("the cluster does not serve %s objects", storageCapacityKind)
+}
+
+// processStorageCapacity makes the CSIStorageCapacity objects in Trident's namespace match the capacity
+// reported for each storage class, creating, updating and deleting objects as needed.
+func (p *Plugin) processStorageCapacity() {
+
+	capacities, err := p.orchestrator.ListStorageClassCapacities()
+	if err != nil {
+		log.WithField("error", err).Debug("K8S helper could not list storage capacity.")
+		return
+	}
+
+	desired := make(map[string]*unstructured.Unstructured, len(capacities))
+	for _, capacity := range capacities {
+		object := p.newStorageCapacityObject(capacity)
+		desired[object.GetName()] = object
+	}
+
+	client := p.dynamicClient.Resource(*p.storageCapacityResource).Namespace(p.namespace)
+	existingList, err := client.List(ctx(), metav1.ListOptions{
+		LabelSelector: labels.Set{LabelStorageCapacity: "true"}.String(),
+	})
+	if err != nil {
+		log.WithField("error", err).Warning("K8S helper could not list storage capacity objects.")
+		return
+	}
+
+	existing := make(map[string]bool, len(existingList.Items))
+	for i := range existingList.Items {
+		object := &existingList.Items[i]
+		existing[object.GetName()] = true
+
+		want, ok := desired[object.GetName()]
+		if !ok {
+			if err = client.Delete(ctx(), object.GetName(), deleteOpts); err != nil {
+				log.WithFields(log.Fields{
+					"name":  object.GetName(),
+					"error": err,
+				}).Warning("K8S helper could not delete storage capacity object.")
+			}
+			continue
+		}
+
+		if storageCapacityEqual(object, want) {
+			continue
+		}
+		for _, field := range []string{"capacity", "maximumVolumeSize"} {
+			object.Object[field] = want.Object[field]
+		}
+		if _, err = client.Update(ctx(), object, updateOpts); err != nil {
+			log.WithFields(log.Fields{
+				"name":  object.GetName(),
+				"error": err,
+			}).Warning("K8S helper could not update storage capacity object.")
+		}
+	}
+
+	for name, object := range desired {
+		if existing[name] {
+			continue
+		}
+		if _, err = client.Create(ctx(), object, createOpts); err != nil {
+			log.WithFields(log.Fields{
+				"name":         name,
+				"storageClass": object.Object["storageClassName"],
+				"error":        err,
+			}).Warning("K8S helper could not create storage capacity object.")
+		}
+	}
+}
+
+// newStorageCapacityObject builds the CSIStorageCapacity object for a storage class and topology segment.
+func (p *Plugin) newStorageCapacityObject(capacity *storage.StorageClassCapacity) *unstructured.Unstructured {
+
+	// An empty selector matches every node
+	matchLabels := make(map[string]interface{}, len(capacity.Topology))
+	for key, value := range capacity.Topology {
+		matchLabels[key] = value
+	}
+
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": p.storageCapacityResource.GroupVersion().String(),
+		"kind":       storageCapacityKind,
+		"metadata": map[string]interface{}{
+			"name":      getStorageCapacityName(capacity),
+			"namespace": p.namespace,
+			"labels":    map[string]interface{}{LabelStorageCapacity: "true"},
+		},
+		"storageClassName":  capacity.StorageClass,
+		"nodeTopology":      map[string]interface{}{"matchLabels": matchLabels},
+		"capacity":          strconv.FormatInt(capacity.CapacityBytes, 10),
+		"maximumVolumeSize": strconv.FormatInt(capacity.MaximumVolumeSizeBytes, 10),
+	}}
+}
+
+// getStorageCapacityName returns a stable object name for a storage class and topology segment.  The
+// node topology of a CSIStorageCapacity can't be changed, so each segment gets its own object.
+func getStorageCapacityName(capacity *storage.StorageClassCapacity) string {
+
+	keys := make([]string, 0, len(capacity.Topology))
+	for key := range capacity.Topology {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	segment := make([]string, 0, len(keys))
+	for _, key := range keys {
+		segment = append(segment, key+"="+capacity.Topology[key])
+	}
+
+	hash := sha256.Sum256([]byte(capacity.StorageClass + "/" + strings.Join(segment, ",")))
+	return "trident-" + hex.EncodeToString(hash[:])[:16]
+}
+
+// storageCapacityEqual reports whether an object already records the capacity of another.  Kubernetes
+// rewrites quantities in canonical form, so they are compared by value.
+func storageCapacityEqual(object, want *unstructured.Unstructured) bool {
+
+	if object.Object["storageClassName"] != want.Object["storageClassName"] {
+		return false
+	}
+	for _, field := range []string{"capacity", "maximumVolumeSize"} {
+		have, err := resource.ParseQuantity(fmt.Sprint(object.Object[field]))
+		if err != nil {
+			return false
+		}
+		if have.Cmp(resource.MustParse(fmt.Sprint(want.Object[field]))) != 0 {
+			return false
+		}
+	}
+	return true
+}
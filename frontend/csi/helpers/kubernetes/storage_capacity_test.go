@@ -0,0 +1,93 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/netapp/trident/core"
+	"github.com/netapp/trident/storage"
+)
+
+func TestProcessStorageCapacity(t *testing.T) {
+
+	gvr := schema.GroupVersionResource{Group: storageCapacityGroup, Version: "v1beta1", Resource: storageCapacityResource}
+	scheme := runtime.NewScheme()
+	scheme.AddKnownTypeWithName(gvr.GroupVersion().WithKind(storageCapacityKind+"List"), &unstructured.UnstructuredList{})
+
+	orchestrator := core.NewMockOrchestrator()
+	p := &Plugin{
+		orchestrator:            orchestrator,
+		namespace:               "trident",
+		dynamicClient:           dynamicfake.NewSimpleDynamicClient(scheme),
+		storageCapacityResource: &gvr,
+	}
+	client := p.dynamicClient.Resource(gvr).Namespace("trident")
+
+	getCapacities := func() map[string]map[string]interface{} {
+		list, err := client.List(ctx(), metav1.ListOptions{})
+		assert.NoError(t, err)
+		objects := make(map[string]map[string]interface{})
+		for _, item := range list.Items {
+			objects[item.Object["storageClassName"].(string)] = item.Object
+		}
+		return objects
+	}
+
+	gold := &storage.StorageClassCapacity{StorageClass: "gold", CapacityBytes: 3000, MaximumVolumeSizeBytes: 2000}
+	silver := &storage.StorageClassCapacity{StorageClass: "silver", CapacityBytes: 100, MaximumVolumeSizeBytes: 100}
+
+	// An object is created for each storage class
+	orchestrator.SetStorageClassCapacities([]*storage.StorageClassCapacity{gold, silver})
+	p.processStorageCapacity()
+	objects := getCapacities()
+	assert.Len(t, objects, 2)
+	assert.Equal(t, "3000", objects["gold"]["capacity"])
+	assert.Equal(t, "2000", objects["gold"]["maximumVolumeSize"])
+	assert.Equal(t, map[string]interface{}{"matchLabels": map[string]interface{}{}}, objects["gold"]["nodeTopology"])
+
+	// Objects are updated as capacity changes and deleted with their storage class
+	gold.CapacityBytes = 1000
+	orchestrator.SetStorageClassCapacities([]*storage.StorageClassCapacity{gold})
+	p.processStorageCapacity()
+	objects = getCapacities()
+	assert.Len(t, objects, 1)
+	assert.Equal(t, "1000", objects["gold"]["capacity"])
+}
+
+func TestStorageCapacityEqual(t *testing.T) {
+
+	newObject := func(capacity string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"storageClassName":  "gold",
+			"capacity":          capacity,
+			"maximumVolumeSize": "1000",
+		}}
+	}
+
+	// Kubernetes stores quantities in canonical form
+	assert.True(t, storageCapacityEqual(newObject("2G"), newObject("2000000000")))
+	assert.True(t, storageCapacityEqual(newObject("1k"), newObject("1000")))
+	assert.False(t, storageCapacityEqual(newObject("1Ki"), newObject("1000")))
+}
+
+func TestGetStorageCapacityName(t *testing.T) {
+
+	all := &storage.StorageClassCapacity{StorageClass: "gold"}
+	zoneA := &storage.StorageClassCapacity{StorageClass: "gold", Topology: map[string]string{"zone": "a"}}
+	zoneB := &storage.StorageClassCapacity{StorageClass: "gold", Topology: map[string]string{"zone": "b"}}
+
+	assert.Equal(t, getStorageCapacityName(all), getStorageCapacityName(&storage.StorageClassCapacity{
+		StorageClass: "gold",
+	}))
+	assert.NotEqual(t, getStorageCapacityName(all), getStorageCapacityName(zoneA))
+	assert.NotEqual(t, getStorageCapacityName(zoneA), getStorageCapacityName(zoneB))
+	assert.Len(t, getStorageCapacityName(all), len("trident-")+16)
+}
@@ -0,0 +1,123 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package kubernetes
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	k8sstoragev1 "k8s.io/api/storage/v1"
+
+	"github.com/netapp/trident/frontend/csi"
+	"github.com/netapp/trident/storage"
+	storageattribute "github.com/netapp/trident/storage_attribute"
+	drivers "github.com/netapp/trident/storage_drivers"
+	tridentutils "github.com/netapp/trident/utils"
+)
+
+// storageAttributeValues lists the values accepted by string attributes that have a fixed set of values.
+var storageAttributeValues = map[string][]string{
+	storageattribute.Media:            {storageattribute.HDD, storageattribute.SSD, storageattribute.Hybrid},
+	storageattribute.ProvisioningType: {storageattribute.Thick, storageattribute.Thin},
+}
+
+// ValidateStorageClass checks the parameters of a Trident storage class the way the K8S helper will
+// interpret them, so that mistakes may be rejected when the class is applied rather than when the first
+// volume is provisioned.  Storage classes of other provisioners are not checked.
+func ValidateStorageClass(sc *k8sstoragev1.StorageClass) error {
+
+	if sc.Provisioner != csi.Provisioner {
+		return nil
+	}
+
+	keys := make([]string, 0, len(sc.Parameters))
+	for key := range sc.Parameters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	problems := make([]string, 0)
+	for _, key := range keys {
+		if err := validateStorageClassParameter(key, sc.Parameters[key]); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", key, err))
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid parameters in storage class %s; %s", sc.Name, strings.Join(problems, "; "))
+	}
+
+	return nil
+}
+
+// validateStorageClassParameter checks a single storage class parameter.
+func validateStorageClassParameter(key, value string) error {
+
+	switch key {
+	case K8sFsType, K8sCSIFsType:
+		// An empty file system type selects the driver's default
+		if value == "" {
+			return nil
+		}
+		_, err := drivers.CheckSupportedFilesystem(value, "")
+		return err
+
+	case storageattribute.RequiredStorage, storageattribute.AdditionalStoragePools,
+		storageattribute.ExcludeStoragePools, storageattribute.StoragePools:
+		_, err := storageattribute.CreateBackendStoragePoolsMapFromEncodedString(value)
+		return err
+
+	case storageattribute.SnapshotRetention:
+		_, err := storage.ParseSnapshotRetentionPolicy(value)
+		return err
+
+	case storageattribute.Autogrow:
+		_, err := storage.ParseAutogrowPolicy(value)
+		return err
+
+	case storageattribute.MountOptions:
+		return tridentutils.ValidateMountOptions(value)
+
+	case storageattribute.FormatOptions:
+		return tridentutils.ValidateFormatOptions(value)
+
+	case storageattribute.FsckPolicy:
+		return tridentutils.ValidateFsckPolicy(value)
+
+	case storageattribute.IOScheduler:
+		return tridentutils.ValidateIOScheduler(value)
+
+	case storageattribute.ReadAheadKB:
+		return tridentutils.ValidateReadAheadKB(value)
+
+	case storageattribute.NrRequests:
+		return tridentutils.ValidateNrRequests(value)
+
+	case storageattribute.RootOwnership:
+		_, _, err := tridentutils.ParseOwnership(value)
+		return err
+
+	case storageattribute.DeletionPolicy:
+		return storage.ValidateDeletionPolicy(value)
+
+	case storageattribute.ExportPolicy:
+		return tridentutils.ValidateExportPolicyName(value)
+
+	case storageattribute.IgroupName:
+		return tridentutils.ValidateIgroupName(value)
+	}
+
+	// Other parameters reserved by Kubernetes are consumed by the CSI sidecars
+	if strings.HasPrefix(key, K8sCSIParameterPrefix) {
+		return nil
+	}
+
+	if _, err := storageattribute.CreateAttributeRequestFromAttributeValue(key, value); err != nil {
+		return err
+	}
+	if allowed, ok := storageAttributeValues[key]; ok && !tridentutils.StringInSlice(value, allowed) {
+		return fmt.Errorf("value %s is not one of %s", value, strings.Join(allowed, ", "))
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	k8sstoragev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/netapp/trident/frontend/csi"
+)
+
+func TestValidateStorageClass(t *testing.T) {
+
+	newStorageClass := func(provisioner string, parameters map[string]string) *k8sstoragev1.StorageClass {
+		return &k8sstoragev1.StorageClass{
+			ObjectMeta:  metav1.ObjectMeta{Name: "gold"},
+			Provisioner: provisioner,
+			Parameters:  parameters,
+		}
+	}
+
+	validParameters := []map[string]string{
+		nil,
+		{"backendType": "ontap-nas", "media": "ssd", "provisioningType": "thin", "IOPS": "1000"},
+		{"snapshots": "true", "selector": "performance=gold"},
+		{"fsType": "ext4", "csi.storage.k8s.io/fstype": "xfs"},
+		{"csi.storage.k8s.io/node-stage-secret-name": "chap", "fsType": ""},
+		{"storagePools": "nas:aggr1,aggr2", "excludeStoragePools": "san:.*"},
+		{"mountOptions": "nfsvers=4.1,hard", "rootOwnership": "0:2000"},
+		{"deletionPolicy": "unmanage"},
+		{"exportPolicy": "team-a", "igroupName": "team-a"},
+		{"formatOptions": "-E nodiscard -b 4096"},
+		{"fsckPolicy": "auto"},
+		{"ioScheduler": "none", "readAheadKB": "128", "nrRequests": "256"},
+	}
+	for _, parameters := range validParameters {
+		assert.NoError(t, ValidateStorageClass(newStorageClass(csi.Provisioner, parameters)), parameters)
+	}
+
+	invalidParameters := []map[string]string{
+		{"tieringPolicy": "none"},
+		{"media": "tape"},
+		{"provisioningType": "thinnish"},
+		{"IOPS": "many"},
+		{"snapshots": "sometimes"},
+		{"fsType": "zfs"},
+		{"csi.storage.k8s.io/fstype": "btrfs"},
+		{"storagePools": "nas"},
+		{"snapshotRetention": "forever"},
+		{"rootOwnership": "root"},
+		{"deletionPolicy": "retain"},
+		{"exportPolicy": "team a"},
+		{"igroupName": "team/a"},
+		{"formatOptions": "nodiscard"},
+		{"fsckPolicy": "always"},
+		{"ioScheduler": "fastest"},
+		{"readAheadKB": "128k"},
+		{"nrRequests": "0"},
+	}
+	for _, parameters := range invalidParameters {
+		assert.Error(t, ValidateStorageClass(newStorageClass(csi.Provisioner, parameters)), parameters)
+	}
+
+	// Storage classes of other provisioners aren't Trident's to judge
+	assert.NoError(t, ValidateStorageClass(newStorageClass("kubernetes.io/no-provisioner",
+		map[string]string{"media": "tape"})))
+
+	// Every problem is reported at once
+	err := ValidateStorageClass(newStorageClass(csi.Provisioner, map[string]string{"media": "tape", "fsType": "zfs"}))
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "media")
+		assert.Contains(t, err.Error(), "fsType")
+	}
+}
@@ -186,13 +186,49 @@ func isNotManaged(annotations map[string]string, name string, kind string) bool
 func convertStorageClassV1BetaToV1(class *k8sstoragev1beta.StorageClass) *k8sstoragev1.StorageClass {
 	// For now we just copy the fields used by Trident.
 	v1Class := &k8sstoragev1.StorageClass{
-		Provisioner: class.Provisioner,
-		Parameters:  class.Parameters,
+		Provisioner:       class.Provisioner,
+		Parameters:        class.Parameters,
+		AllowedTopologies: class.AllowedTopologies,
 	}
 	v1Class.Name = class.Name
 	return v1Class
 }
 
+// getAllowedTopologies returns the topology segments a storage class's allowed topologies select.  Each
+// term selects every combination of the values of its label expressions, so a term with zones a and b in
+// region r1 selects {region: r1, zone: a} and {region: r1, zone: b}.
+func getAllowedTopologies(terms []v1.TopologySelectorTerm) []map[string]string {
+
+	segments := make([]map[string]string, 0)
+	for _, term := range terms {
+		termSegments := []map[string]string{{}}
+		for _, expression := range term.MatchLabelExpressions {
+			expanded := make([]map[string]string, 0, len(termSegments)*len(expression.Values))
+			for _, segment := range termSegments {
+				for _, value := range expression.Values {
+					newSegment := make(map[string]string, len(segment)+1)
+					for k, v := range segment {
+						newSegment[k] = v
+					}
+					newSegment[expression.Key] = value
+					expanded = append(expanded, newSegment)
+				}
+			}
+			termSegments = expanded
+		}
+		for _, segment := range termSegments {
+			if len(segment) > 0 {
+				segments = append(segments, segment)
+			}
+		}
+	}
+
+	if len(segments) == 0 {
+		return nil
+	}
+	return segments
+}
+
 // getStorageClassForPVC returns StorageClassName from a PVC. If no storage class was requested, it returns "".
 func getStorageClassForPVC(pvc *v1.PersistentVolumeClaim) string {
 	// Use beta annotation first
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/version"
 
 	"github.com/netapp/trident/frontend/csi"
@@ -33,3 +34,29 @@ func TestSupportsFeature(t *testing.T) {
 		}
 	}
 }
+
+func TestGetAllowedTopologies(t *testing.T) {
+
+	assert.Nil(t, getAllowedTopologies(nil))
+
+	terms := []v1.TopologySelectorTerm{
+		{
+			MatchLabelExpressions: []v1.TopologySelectorLabelRequirement{
+				{Key: "topology.kubernetes.io/region", Values: []string{"r1"}},
+				{Key: "topology.kubernetes.io/zone", Values: []string{"a", "b"}},
+			},
+		},
+		{
+			MatchLabelExpressions: []v1.TopologySelectorLabelRequirement{
+				{Key: "topology.kubernetes.io/region", Values: []string{"r2"}},
+			},
+		},
+		{},
+	}
+	expected := []map[string]string{
+		{"topology.kubernetes.io/region": "r1", "topology.kubernetes.io/zone": "a"},
+		{"topology.kubernetes.io/region": "r1", "topology.kubernetes.io/zone": "b"},
+		{"topology.kubernetes.io/region": "r2"},
+	}
+	assert.Equal(t, expected, getAllowedTopologies(terms))
+}
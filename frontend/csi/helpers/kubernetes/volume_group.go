@@ -0,0 +1,196 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package kubernetes
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+
+	netappv1 "github.com/netapp/trident/persistent_store/crd/apis/netapp/v1"
+	"github.com/netapp/trident/storage"
+	tridentutils "github.com/netapp/trident/utils"
+)
+
+/////////////////////////////////////////////////////////////////////////////
+//
+// This file contains the controller that keeps Trident's volume groups in
+// sync with TridentVolumeGroup CRs.  Each CR's PVCs are resolved to their
+// Trident volumes, which become the members of a Trident volume group named
+// after the CR.  Groups whose CRs were deleted are deleted from Trident.
+//
+/////////////////////////////////////////////////////////////////////////////
+
+// volumeGroupPrefix distinguishes the Trident volume groups managed by TridentVolumeGroup CRs
+// from those created with the REST API.
+const volumeGroupPrefix = "k8s."
+
+// runVolumeGroups periodically reconciles the TridentVolumeGroup CRs, until the stop channel is closed.
+func (p *Plugin) runVolumeGroups() {
+
+	ticker := time.NewTicker(VolumeGroupPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.volumeGroupStopChan:
+			return
+		case <-ticker.C:
+			p.processVolumeGroups()
+		}
+	}
+}
+
+// processVolumeGroups reconciles the TridentVolumeGroup CRs in all namespaces with Trident's
+// volume groups.
+func (p *Plugin) processVolumeGroups() {
+
+	volumeGroups, err := p.crdClient.TridentV1().TridentVolumeGroups("").List(ctx(), listOpts)
+	if err != nil {
+		log.WithField("error", err).Debug("K8S helper could not list volume groups.")
+		return
+	}
+
+	managedGroups := make(map[string]bool)
+	for _, volumeGroup := range volumeGroups.Items {
+		managedGroups[getVolumeGroupName(volumeGroup)] = true
+		p.processVolumeGroup(volumeGroup)
+	}
+
+	// Delete the groups whose CRs no longer exist
+	tridentGroups, err := p.orchestrator.ListVolumeGroups()
+	if err != nil {
+		log.WithField("error", err).Debug("K8S helper could not list Trident volume groups.")
+		return
+	}
+	for _, tridentGroup := range tridentGroups {
+		if strings.HasPrefix(tridentGroup.Name, volumeGroupPrefix) && !managedGroups[tridentGroup.Name] {
+			p.deleteTridentVolumeGroup(tridentGroup.Name)
+		}
+	}
+}
+
+// processVolumeGroup makes the Trident volumes of a CR's PVCs the members of the CR's Trident
+// volume group, and records the result in the CR's status.
+func (p *Plugin) processVolumeGroup(volumeGroup *netappv1.TridentVolumeGroup) {
+
+	groupName := getVolumeGroupName(volumeGroup)
+	logFields := log.Fields{
+		"volumeGroup": volumeGroup.Name,
+		"namespace":   volumeGroup.Namespace,
+	}
+
+	volumeNames, errMessages := p.getVolumeGroupVolumes(volumeGroup)
+
+	var tridentGroup *storage.VolumeGroupExternal
+	existingGroup, err := p.orchestrator.GetVolumeGroup(groupName)
+	if err != nil && !tridentutils.IsNotFoundError(err) {
+		log.WithFields(logFields).WithField("error", err).Debug("K8S helper could not get volume group.")
+		return
+	}
+
+	groupConfig := &storage.VolumeGroupConfig{Name: groupName, Volumes: volumeNames}
+	switch {
+	case len(volumeNames) == 0:
+		if existingGroup != nil {
+			p.deleteTridentVolumeGroup(groupName)
+		}
+		errMessages = append(errMessages, "no PVCs are bound to Trident volumes")
+	case existingGroup == nil:
+		if tridentGroup, err = p.orchestrator.AddVolumeGroup(groupConfig); err != nil {
+			errMessages = append(errMessages, err.Error())
+		} else {
+			log.WithFields(logFields).Info("K8S helper created volume group.")
+		}
+	case !reflect.DeepEqual(existingGroup.Volumes, volumeNames):
+		if tridentGroup, err = p.orchestrator.UpdateVolumeGroup(groupConfig); err != nil {
+			errMessages = append(errMessages, err.Error())
+			tridentGroup = existingGroup
+		} else {
+			log.WithFields(logFields).Info("K8S helper updated volume group.")
+		}
+	default:
+		tridentGroup = existingGroup
+	}
+
+	status := netappv1.TridentVolumeGroupStatus{Message: strings.Join(errMessages, "; ")}
+	if tridentGroup != nil {
+		status.VolumeGroup = tridentGroup.Name
+		status.Volumes = tridentGroup.Volumes
+		status.Snapshots = tridentGroup.Snapshots
+	}
+	p.updateVolumeGroupStatus(volumeGroup, status)
+}
+
+// getVolumeGroupVolumes returns the sorted names of the Trident volumes bound to a CR's PVCs, along
+// with a message for each PVC that isn't bound to a Trident volume.
+func (p *Plugin) getVolumeGroupVolumes(volumeGroup *netappv1.TridentVolumeGroup) ([]string, []string) {
+
+	volumeNames := make([]string, 0, len(volumeGroup.Spec.PVCs))
+	errMessages := make([]string, 0)
+
+	for _, pvcName := range volumeGroup.Spec.PVCs {
+		pvc, err := p.getCachedPVCByName(pvcName, volumeGroup.Namespace)
+		if err != nil {
+			errMessages = append(errMessages, fmt.Sprintf("PVC %s not found", pvcName))
+			continue
+		}
+		if pvc.Status.Phase != v1.ClaimBound || pvc.Spec.VolumeName == "" {
+			errMessages = append(errMessages, fmt.Sprintf("PVC %s is not bound", pvcName))
+			continue
+		}
+		if _, err := p.orchestrator.GetVolume(pvc.Spec.VolumeName); err != nil {
+			errMessages = append(errMessages, fmt.Sprintf("PVC %s is not a Trident volume", pvcName))
+			continue
+		}
+		volumeNames = append(volumeNames, pvc.Spec.VolumeName)
+	}
+
+	sort.Strings(volumeNames)
+	return volumeNames, errMessages
+}
+
+// deleteTridentVolumeGroup deletes a Trident volume group, leaving its volumes and snapshots in place.
+func (p *Plugin) deleteTridentVolumeGroup(groupName string) {
+	if err := p.orchestrator.DeleteVolumeGroup(groupName); err != nil && !tridentutils.IsNotFoundError(err) {
+		log.WithFields(log.Fields{
+			"volumeGroup": groupName,
+			"error":       err,
+		}).Error("K8S helper could not delete volume group.")
+		return
+	}
+	log.WithField("volumeGroup", groupName).Info("K8S helper deleted volume group.")
+}
+
+// updateVolumeGroupStatus records the result of a reconciliation in a CR's status.
+func (p *Plugin) updateVolumeGroupStatus(
+	volumeGroup *netappv1.TridentVolumeGroup, status netappv1.TridentVolumeGroupStatus,
+) {
+
+	if reflect.DeepEqual(volumeGroup.Status, status) {
+		return
+	}
+
+	volumeGroupCopy := volumeGroup.DeepCopy()
+	volumeGroupCopy.Status = status
+
+	if _, err := p.crdClient.TridentV1().TridentVolumeGroups(volumeGroup.Namespace).UpdateStatus(
+		ctx(), volumeGroupCopy, updateOpts); err != nil {
+		log.WithFields(log.Fields{
+			"volumeGroup": volumeGroup.Name,
+			"namespace":   volumeGroup.Namespace,
+			"error":       err,
+		}).Error("K8S helper could not update volume group status.")
+	}
+}
+
+// getVolumeGroupName returns the name of the Trident volume group managed by a CR.  Namespace names
+// can't contain dots, so the name is unique.
+func getVolumeGroupName(volumeGroup *netappv1.TridentVolumeGroup) string {
+	return volumeGroupPrefix + volumeGroup.Namespace + "." + volumeGroup.Name
+}
@@ -0,0 +1,52 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/netapp/trident/core"
+	netappv1 "github.com/netapp/trident/persistent_store/crd/apis/netapp/v1"
+	"github.com/netapp/trident/persistent_store/crd/client/clientset/versioned/fake"
+)
+
+func TestGetVolumeGroupName(t *testing.T) {
+	volumeGroup := &netappv1.TridentVolumeGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "db.primary", Namespace: "prod"},
+	}
+	assert.Equal(t, "k8s.prod.db.primary", getVolumeGroupName(volumeGroup))
+}
+
+func TestProcessVolumeGroupUnboundPVCs(t *testing.T) {
+	volumeGroup := &netappv1.TridentVolumeGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+		Spec:       netappv1.TridentVolumeGroupSpec{PVCs: []string{"data", "logs"}},
+	}
+	p := &Plugin{
+		orchestrator: core.NewMockOrchestrator(),
+		crdClient:    fake.NewSimpleClientset(volumeGroup),
+		pvcIndexer:   cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{}),
+	}
+	_ = p.pvcIndexer.Add(&v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "data", Namespace: "default"},
+		Status:     v1.PersistentVolumeClaimStatus{Phase: v1.ClaimPending},
+	})
+
+	volumeNames, errMessages := p.getVolumeGroupVolumes(volumeGroup)
+	assert.Empty(t, volumeNames)
+	assert.Equal(t, []string{"PVC data is not bound", "PVC logs not found"}, errMessages)
+
+	p.processVolumeGroups()
+
+	updated, err := p.crdClient.TridentV1().TridentVolumeGroups("default").Get(ctx(), "app", getOpts)
+	if assert.NoError(t, err) {
+		assert.Empty(t, updated.Status.VolumeGroup)
+		assert.Contains(t, updated.Status.Message, "PVC logs not found")
+		assert.Contains(t, updated.Status.Message, "no PVCs are bound to Trident volumes")
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package kubernetes
+
+import (
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/netapp/trident/storage"
+)
+
+/////////////////////////////////////////////////////////////////////////////
+//
+// This file contains the controller that surfaces the condition of CSI
+// Trident volumes as events on their PVCs.  Problems detected by the storage
+// drivers, such as an offline volume or an unmapped LUN, and by the nodes,
+// such as a broken mount path, are recorded as VolumeConditionAbnormal
+// events, and a VolumeConditionNormal event is recorded when they clear.
+//
+/////////////////////////////////////////////////////////////////////////////
+
+const (
+	volumeConditionAbnormalReason = "VolumeConditionAbnormal"
+	volumeConditionNormalReason   = "VolumeConditionNormal"
+)
+
+// runVolumeHealth periodically reports changes in volume condition, until the stop channel is closed.
+func (p *Plugin) runVolumeHealth() {
+
+	ticker := time.NewTicker(VolumeHealthPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.volumeHealthStopChan:
+			return
+		case <-ticker.C:
+			p.processVolumeHealth()
+		}
+	}
+}
+
+// processVolumeHealth records an event on the PVC of each volume whose condition has changed since
+// the last pass.
+func (p *Plugin) processVolumeHealth() {
+
+	conditions, err := p.orchestrator.ListVolumeConditions()
+	if err != nil {
+		log.WithField("error", err).Debug("K8S helper could not list volume conditions.")
+		return
+	}
+
+	listed := make(map[string]bool, len(conditions))
+	for _, condition := range conditions {
+		listed[condition.VolumeName] = true
+		p.reportVolumeCondition(condition)
+	}
+
+	// Forget the volumes that have been deleted
+	for volumeName := range p.volumeHealthReported {
+		if !listed[volumeName] {
+			delete(p.volumeHealthReported, volumeName)
+		}
+	}
+}
+
+// reportVolumeCondition records an event on a volume's PVC when it becomes abnormal, when the problem
+// changes, and when it returns to normal.
+func (p *Plugin) reportVolumeCondition(condition *storage.VolumeCondition) {
+
+	reported, wasAbnormal := p.volumeHealthReported[condition.VolumeName]
+	if condition.Abnormal == wasAbnormal && (!condition.Abnormal || condition.Message == reported) {
+		return
+	}
+
+	pv, err := p.getCachedPVByName(condition.VolumeName)
+	if err != nil || pv.Spec.ClaimRef == nil {
+		return
+	}
+	pvc, err := p.getCachedPVCByName(pv.Spec.ClaimRef.Name, pv.Spec.ClaimRef.Namespace)
+	if err != nil {
+		return
+	}
+	logFields := log.Fields{
+		"volume":    condition.VolumeName,
+		"PVC":       pvc.Name,
+		"namespace": pvc.Namespace,
+	}
+
+	if condition.Abnormal {
+		p.volumeHealthReported[condition.VolumeName] = condition.Message
+		p.eventRecorder.Event(pvc, v1.EventTypeWarning, volumeConditionAbnormalReason, condition.Message)
+		log.WithFields(logFields).Warningf("K8S helper found abnormal volume condition: %s.", condition.Message)
+		return
+	}
+
+	delete(p.volumeHealthReported, condition.VolumeName)
+	p.eventRecorder.Event(pvc, v1.EventTypeNormal, volumeConditionNormalReason, "volume is healthy")
+	log.WithFields(logFields).Info("K8S helper found volume condition returned to normal.")
+}
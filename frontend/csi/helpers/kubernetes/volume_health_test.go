@@ -0,0 +1,70 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/netapp/trident/core"
+	"github.com/netapp/trident/storage"
+)
+
+func TestProcessVolumeHealth(t *testing.T) {
+
+	orchestrator := core.NewMockOrchestrator()
+	recorder := record.NewFakeRecorder(10)
+	p := &Plugin{
+		orchestrator:         orchestrator,
+		eventRecorder:        recorder,
+		pvIndexer:            cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{}),
+		pvcIndexer:           cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{}),
+		volumeHealthReported: make(map[string]string),
+	}
+	_ = p.pvIndexer.Add(&v1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: "pvc-1"},
+		Spec: v1.PersistentVolumeSpec{
+			ClaimRef: &v1.ObjectReference{Name: "data", Namespace: "apps"},
+		},
+	})
+	_ = p.pvcIndexer.Add(&v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "data", Namespace: "apps"},
+		Spec:       v1.PersistentVolumeClaimSpec{VolumeName: "pvc-1"},
+	})
+
+	setCondition := func(abnormal bool, message string) {
+		assert.NoError(t, orchestrator.UpdateVolumeCondition(&storage.VolumeCondition{
+			VolumeName: "pvc-1", Abnormal: abnormal, Message: message,
+		}))
+	}
+
+	// Healthy volumes aren't reported
+	setCondition(false, "")
+	p.processVolumeHealth()
+	assert.Len(t, recorder.Events, 0)
+
+	setCondition(true, "volume is full")
+	p.processVolumeHealth()
+	assert.Len(t, recorder.Events, 1)
+	assert.Equal(t, "Warning VolumeConditionAbnormal volume is full", <-recorder.Events)
+
+	// An unchanged problem is reported once
+	p.processVolumeHealth()
+	assert.Len(t, recorder.Events, 0)
+
+	setCondition(true, "volume pvc-1 is offline")
+	p.processVolumeHealth()
+	assert.Len(t, recorder.Events, 1)
+	assert.Equal(t, "Warning VolumeConditionAbnormal volume pvc-1 is offline", <-recorder.Events)
+
+	setCondition(false, "")
+	p.processVolumeHealth()
+	assert.Len(t, recorder.Events, 1)
+	assert.Equal(t, "Normal VolumeConditionNormal volume is healthy", <-recorder.Events)
+	assert.Empty(t, p.volumeHealthReported)
+}
@@ -84,12 +84,20 @@ func (p *Plugin) GetVolumeConfig(
 
 // GetSnapshotConfig accepts the attributes of a snapshot being requested by the CSI
 // provisioner and returns a SnapshotConfig structure as needed by Trident to create a new snapshot.
-func (p *Plugin) GetSnapshotConfig(volumeName, snapshotName string) (*storage.SnapshotConfig, error) {
-	return &storage.SnapshotConfig{
+// The parameters are those of the snapshot's class.
+func (p *Plugin) GetSnapshotConfig(
+	volumeName, snapshotName string, parameters map[string]string,
+) (*storage.SnapshotConfig, error) {
+
+	snapshotConfig := &storage.SnapshotConfig{
 		Version:    config.OrchestratorAPIVersion,
 		Name:       snapshotName,
 		VolumeName: volumeName,
-	}, nil
+	}
+	if err := snapshotConfig.ApplyParameters(parameters); err != nil {
+		return nil, err
+	}
+	return snapshotConfig, nil
 }
 
 // RecordVolumeEvent accepts the name of a CSI volume and writes the specified
@@ -103,6 +111,23 @@ func (p *Plugin) RecordVolumeEvent(name, eventType, reason, message string) {
 	}).Debug("Volume event.")
 }
 
+// RecordBackendEvent accepts the name of a Trident backend and writes the specified
+// event message to the debug log.
+func (p *Plugin) RecordBackendEvent(backendName, eventType, reason, message string) {
+	log.WithFields(log.Fields{
+		"backend":   backendName,
+		"eventType": eventType,
+		"reason":    reason,
+		"message":   message,
+	}).Debug("Backend event.")
+}
+
+// GetVolumePublishSecrets returns nil, since plain CSI volumes have no record of their
+// node publish secrets.
+func (p *Plugin) GetVolumePublishSecrets(volumeName string) (map[string]string, error) {
+	return nil, nil
+}
+
 // SupportsFeature accepts a CSI feature and returns true if the
 // feature exists and is supported.
 func (p *Plugin) SupportsFeature(feature helpers.Feature) bool {
@@ -32,13 +32,22 @@ type HybridPlugin interface {
 
 	// GetSnapshotConfig accepts the attributes of a snapshot being requested byt the CSI
 	// provisioner, adds in any CO-specific details about the new volume, and returns
-	// a SnapshotConfig structure as needed by Trident to create a new snapshot.
-	GetSnapshotConfig(volumeName, snapshotName string) (*storage.SnapshotConfig, error)
+	// a SnapshotConfig structure as needed by Trident to create a new snapshot.  The parameters
+	// are those of the snapshot's class.
+	GetSnapshotConfig(volumeName, snapshotName string, parameters map[string]string) (*storage.SnapshotConfig, error)
 
 	// RecordVolumeEvent accepts the name of a CSI volume and writes the specified
 	// event message in a manner appropriate to the container orchestrator.
 	RecordVolumeEvent(name, eventType, reason, message string)
 
+	// RecordBackendEvent accepts the name of a Trident backend and writes the specified
+	// event message in a manner appropriate to the container orchestrator.
+	RecordBackendEvent(backendName, eventType, reason, message string)
+
+	// GetVolumePublishSecrets returns the contents of the secret the container orchestrator hands
+	// to the node when it publishes the named volume, or nil if the volume has no such secret.
+	GetVolumePublishSecrets(volumeName string) (map[string]string, error)
+
 	//SupportsFeature accepts a CSI feature and returns true if the feature is supported.
 	SupportsFeature(feature Feature) bool
 
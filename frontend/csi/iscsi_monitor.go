@@ -0,0 +1,279 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package csi
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	log "github.com/sirupsen/logrus"
+
+	tridentconfig "github.com/netapp/trident/config"
+	"github.com/netapp/trident/utils"
+)
+
+const (
+	// iscsiSessionLoggedIn is the sysfs state of a healthy iSCSI session
+	iscsiSessionLoggedIn = "LOGGED_IN"
+
+	// iscsiPathDegradedChecks is how many consecutive checks a path must be down before it is reported
+	iscsiPathDegradedChecks = 3
+)
+
+var (
+	iscsiDegradedPathsGauge = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: tridentconfig.OrchestratorName,
+			Subsystem: "node",
+			Name:      "iscsi_degraded_path_count",
+			Help:      "The number of paths to staged iSCSI targets that have stayed down",
+		},
+	)
+	iscsiReloginsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: tridentconfig.OrchestratorName,
+			Subsystem: "node",
+			Name:      "iscsi_relogin_total",
+			Help:      "The total number of logins to portals of staged iSCSI targets that had no session",
+		},
+		[]string{"result"},
+	)
+	iscsiRescansTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: tridentconfig.OrchestratorName,
+			Subsystem: "node",
+			Name:      "iscsi_rescan_total",
+			Help:      "The total number of rescans of iSCSI sessions that came back up",
+		},
+	)
+)
+
+// iscsiMonitoredTarget is an iSCSI target that volumes staged on this node were published from.
+type iscsiMonitoredTarget struct {
+	// publishInfo is the publish info of one of the target's volumes, for logging in to its portals
+	publishInfo *utils.VolumePublishInfo
+	portals     []string
+	lunIDs      []int
+	volumes     []string
+}
+
+// iscsiPathState is what the session monitor remembers about a path to a target between checks.
+type iscsiPathState struct {
+	// downChecks is how many consecutive checks the path has been down
+	downChecks int
+}
+
+// SetISCSISessionMonitorPeriod sets how often the node checks the iSCSI sessions to the targets of its
+// staged volumes.  A period of zero turns the monitor off.
+func (p *Plugin) SetISCSISessionMonitorPeriod(period time.Duration) {
+	p.iscsiSessionMonitorPeriod = period
+}
+
+// iscsiSessionMonitor periodically checks the iSCSI sessions to the targets of the volumes staged on
+// this node, so that paths lost to a network or LIF outage are restored without restarting pods.
+func (p *Plugin) iscsiSessionMonitor() {
+
+	if p.iscsiSessionMonitorPeriod <= 0 {
+		log.Debug("iSCSI session monitor is disabled.")
+		return
+	}
+
+	log.WithField("period", p.iscsiSessionMonitorPeriod).Info("Starting iSCSI session monitor.")
+
+	ticker := time.NewTicker(p.iscsiSessionMonitorPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			p.checkISCSISessions()
+		case <-p.stopNodeHeartbeat:
+			return
+		}
+	}
+}
+
+// checkISCSISessions logs in again to the portals of staged targets that have no session, rescans
+// sessions that came back up, and reports the volumes on paths that have stayed down.
+func (p *Plugin) checkISCSISessions() {
+
+	// Hold the node lock, so sessions aren't restored while a volume is being unstaged
+	lockContext := "ISCSISessionMonitor"
+	utils.Lock(lockContext, lockID)
+	defer utils.Unlock(lockContext, lockID)
+
+	targets := p.getStagedISCSITargets()
+	if len(targets) == 0 {
+		p.iscsiPaths = make(map[string]*iscsiPathState)
+		iscsiDegradedPathsGauge.Set(0)
+		return
+	}
+
+	sessions, err := utils.GetISCSISessionStates()
+	if err != nil {
+		log.WithError(err).Warning("Could not read iSCSI sessions.")
+		return
+	}
+
+	paths := make(map[string]*iscsiPathState)
+	degraded := 0
+
+	for targetIQN, target := range targets {
+		for _, portal := range target.portals {
+
+			portalIP := utils.ISCSIPortalIP(portal)
+			key := targetIQN + "@" + portalIP
+			state, ok := p.iscsiPaths[key]
+			if !ok {
+				state = &iscsiPathState{}
+			}
+			paths[key] = state
+
+			if p.checkISCSIPath(target, portal, findISCSISession(sessions, targetIQN, portalIP), state) {
+				continue
+			}
+
+			if state.downChecks >= iscsiPathDegradedChecks {
+				degraded++
+				message := fmt.Sprintf("iSCSI path to target %s through portal %s has been down for %d checks",
+					targetIQN, portalIP, state.downChecks)
+				if state.downChecks == iscsiPathDegradedChecks {
+					log.WithFields(log.Fields{
+						"target":  targetIQN,
+						"portal":  portalIP,
+						"volumes": target.volumes,
+					}).Warning("iSCSI path is degraded.")
+				}
+				for _, volume := range target.volumes {
+					go p.reportVolumeCondition(volume, message)
+				}
+			}
+		}
+	}
+
+	// Paths to targets that are no longer staged are forgotten
+	p.iscsiPaths = paths
+	iscsiDegradedPathsGauge.Set(float64(degraded))
+}
+
+// checkISCSIPath restores one path to a target, and returns whether it is up.
+func (p *Plugin) checkISCSIPath(
+	target *iscsiMonitoredTarget, portal string, session *utils.ISCSISessionState, state *iscsiPathState,
+) bool {
+
+	targetIQN := target.publishInfo.IscsiTargetIQN
+	logFields := log.Fields{"target": targetIQN, "portal": portal}
+
+	if session == nil {
+		if err := utils.LoginISCSIPortal(target.publishInfo, portal); err != nil {
+			iscsiReloginsTotal.WithLabelValues("failure").Inc()
+			log.WithFields(logFields).WithError(err).Debug("Could not log in to iSCSI portal.")
+			state.downChecks++
+			return false
+		}
+		iscsiReloginsTotal.WithLabelValues("success").Inc()
+		log.WithFields(logFields).Info("Logged in again to iSCSI portal.")
+
+		sessions, err := utils.GetISCSISessionStates()
+		if err != nil {
+			state.downChecks++
+			return false
+		}
+		if session = findISCSISession(sessions, targetIQN, utils.ISCSIPortalIP(portal)); session == nil {
+			state.downChecks++
+			return false
+		}
+		// Counting the path as down gets the new session rescanned for its LUNs below
+		state.downChecks++
+	}
+
+	if session.State != iscsiSessionLoggedIn {
+		log.WithFields(logFields).WithField("state", session.State).Debug("iSCSI session is not logged in.")
+		state.downChecks++
+		return false
+	}
+
+	// A session that comes back up, such as after a LIF fails back, must be rescanned for its LUNs
+	if state.downChecks > 0 {
+		if err := utils.ISCSIScanSessionLUNs(targetIQN, session.SID, target.lunIDs); err != nil {
+			log.WithFields(logFields).WithError(err).Warning("Could not rescan iSCSI session.")
+			return false
+		}
+		iscsiRescansTotal.Inc()
+		log.WithFields(logFields).WithField("downChecks", state.downChecks).Info("iSCSI path is back up.")
+		state.downChecks = 0
+	}
+
+	return true
+}
+
+// findISCSISession returns the session to a target through a portal, if there is one.
+func findISCSISession(sessions []utils.ISCSISessionState, targetIQN, portalIP string) *utils.ISCSISessionState {
+	for i := range sessions {
+		if sessions[i].TargetName == targetIQN && sessions[i].PortalIP == portalIP {
+			return &sessions[i]
+		}
+	}
+	return nil
+}
+
+// getStagedISCSITargets returns the iSCSI targets of the volumes staged on this node, by target IQN.
+func (p *Plugin) getStagedISCSITargets() map[string]*iscsiMonitoredTarget {
+
+	targets := make(map[string]*iscsiMonitoredTarget)
+
+	files, err := ioutil.ReadDir(tridentDeviceInfoPath)
+	if err != nil {
+		log.WithError(err).Debug("Could not list tracking files.")
+		return targets
+	}
+
+	for _, file := range files {
+		if strings.HasPrefix(file.Name(), ephemeralTrackingFilePrefix) || !strings.HasSuffix(file.Name(), ".json") {
+			continue
+		}
+		volumeId := strings.TrimSuffix(file.Name(), ".json")
+
+		stagingTargetPath, err := p.readStagedTrackingFile(volumeId)
+		if err != nil {
+			continue
+		}
+		publishInfo, err := p.readStagedDeviceInfo(stagingTargetPath)
+		if err != nil || publishInfo.IscsiTargetIQN == "" {
+			continue
+		}
+
+		target, ok := targets[publishInfo.IscsiTargetIQN]
+		if !ok {
+			target = &iscsiMonitoredTarget{publishInfo: publishInfo}
+			targets[publishInfo.IscsiTargetIQN] = target
+		}
+		target.volumes = append(target.volumes, volumeId)
+		target.lunIDs = appendUniqueInt(target.lunIDs, int(publishInfo.IscsiLunNumber))
+		portals := append([]string{publishInfo.IscsiTargetPortal}, publishInfo.IscsiPortals...)
+		for _, portal := range portals {
+			if portal != "" && !utils.SliceContainsString(target.portals, portal) {
+				target.portals = append(target.portals, portal)
+			}
+		}
+	}
+
+	for _, target := range targets {
+		sort.Strings(target.volumes)
+	}
+	return targets
+}
+
+func appendUniqueInt(values []int, value int) []int {
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
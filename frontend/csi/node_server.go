@@ -20,14 +20,20 @@ import (
 	"google.golang.org/grpc/status"
 
 	tridentconfig "github.com/netapp/trident/config"
+	"github.com/netapp/trident/storage"
 	"github.com/netapp/trident/utils"
 )
 
+// tridentDeviceInfoPath is where the node plugin keeps its tracking files
+var tridentDeviceInfoPath = "/var/lib/trident/tracking"
+
 const (
-	tridentDeviceInfoPath     = "/var/lib/trident/tracking"
 	fsRaw                     = "raw"
 	lockID                    = "csi_node_server"
 	volumePublishInfoFilename = "volumePublishInfo.json"
+
+	// nodeHeartbeatPeriod must be well below the controller's node TTL
+	nodeHeartbeatPeriod = 5 * time.Minute
 )
 
 func (p *Plugin) NodeStageVolume(
@@ -42,8 +48,15 @@ func (p *Plugin) NodeStageVolume(
 	log.WithFields(fields).Debug(">>>> NodeStageVolume")
 	defer log.WithFields(fields).Debug("<<<< NodeStageVolume")
 
+	if p.csiProxy != nil {
+		return p.nodeStageWindowsVolume(ctx, req)
+	}
+
 	switch req.PublishContext["protocol"] {
 	case string(tridentconfig.File):
+		if req.PublishContext["smbPath"] != "" {
+			return nil, status.Error(codes.InvalidArgument, "SMB volumes require a Windows node")
+		}
 		return p.nodeStageNFSVolume(ctx, req)
 	case string(tridentconfig.Block):
 		return p.nodeStageISCSIVolume(ctx, req)
@@ -74,6 +87,11 @@ func (p *Plugin) NodeUnstageVolume(
 	if err != nil {
 		return nil, status.Errorf(codes.FailedPrecondition, "unable to read the staging target %s; %s", stagingTargetPath, err)
 	}
+
+	if p.csiProxy != nil {
+		return p.nodeUnstageWindowsVolume(ctx, req, publishInfo)
+	}
+
 	protocol, err := p.getVolumeProtocolFromPublishInfo(publishInfo)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "unable to read protocol info from publish info; %s", err)
@@ -101,6 +119,14 @@ func (p *Plugin) NodePublishVolume(
 	log.WithFields(fields).Debug(">>>> NodePublishVolume")
 	defer log.WithFields(fields).Debug("<<<< NodePublishVolume")
 
+	if isEphemeralVolumeRequest(req) {
+		return p.nodePublishEphemeralVolume(ctx, req)
+	}
+
+	if p.csiProxy != nil {
+		return p.nodePublishWindowsVolume(ctx, req)
+	}
+
 	switch req.PublishContext["protocol"] {
 	case string(tridentconfig.File):
 		return p.nodePublishNFSVolume(ctx, req)
@@ -133,6 +159,17 @@ func (p *Plugin) NodeUnpublishVolume(
 		return nil, status.Error(codes.InvalidArgument, "no target path provided")
 	}
 
+	// Ephemeral volumes are deleted as soon as their pod is done with them
+	if info, err := p.readEphemeralVolumeInfo(req.GetVolumeId()); err == nil {
+		return p.nodeUnpublishEphemeralVolume(ctx, req, info)
+	} else if !utils.IsNotFoundError(err) {
+		return nil, status.Errorf(codes.Internal, "unable to read ephemeral volume tracking file; %s", err)
+	}
+
+	if p.csiProxy != nil {
+		return p.nodeUnpublishWindowsVolume(ctx, req)
+	}
+
 	isDir, err := utils.IsLikelyDir(targetPath)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -189,59 +226,149 @@ func (p *Plugin) NodeGetVolumeStats(
 	ctx context.Context, req *csi.NodeGetVolumeStatsRequest,
 ) (*csi.NodeGetVolumeStatsResponse, error) {
 
-        if req.GetVolumeId() == "" {
-                return nil, status.Error(codes.InvalidArgument, "empty volume id provided")
-        }
-
-        if req.GetVolumePath() == "" {
-                return nil, status.Error(codes.InvalidArgument, "empty volume path provided")
-        }
-
-        // Ensure volume is published at path
-        _, err := os.Stat(req.GetVolumePath())
-        if err != nil {
-                return nil, status.Error(codes.NotFound,
-                        fmt.Sprintf("could not find volume mount at path: %s; %v ", req.GetVolumePath(), err))
-        }
-
-        // If raw block volume, dont return usage
-        isRawBlock := false
-        if req.StagingTargetPath != "" {
-                publishInfo, err := p.readStagedDeviceInfo(req.StagingTargetPath)
-                if err != nil {
-                        return nil, status.Error(codes.Internal, err.Error())
-                }
-
-                isRawBlock = publishInfo.FilesystemType == fsRaw
-        }
-        if isRawBlock {
-                // Return no capacity info for raw block volumes, we cannot reliably determine the capacity
-                return &csi.NodeGetVolumeStatsResponse{}, nil
-        } else {
-                // If filesystem, return usage reported by FS
-                available, capacity, usage, inodes, inodesFree, inodesUsed, err := utils.GetFilesystemStats(req.GetVolumePath())
-                if err != nil {
-                        log.Errorf("unable to get filesystem stats at path: %s; %v", req.GetVolumePath(), err)
-                        return nil, status.Error(codes.Unknown, "Failed to get filesystem stats")
-                }
-                return &csi.NodeGetVolumeStatsResponse{
-                        Usage: []*csi.VolumeUsage{
-                                &csi.VolumeUsage{
-                                        Unit:      csi.VolumeUsage_BYTES,
-                                        Available: available,
-                                        Total:     capacity,
-                                        Used:      usage,
-                                },
-                                &csi.VolumeUsage{
-                                        Unit:      csi.VolumeUsage_INODES,
-                                        Available: inodesFree,
-                                        Total:     inodes,
-                                        Used:      inodesUsed,
-                                },
-                        },
-                }, nil
-        }
+	if req.GetVolumeId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "empty volume id provided")
+	}
+
+	if req.GetVolumePath() == "" {
+		return nil, status.Error(codes.InvalidArgument, "empty volume path provided")
+	}
+
+	if p.csiProxy != nil {
+		return p.nodeGetWindowsVolumeStats(ctx, req)
+	}
 
+	// Ensure volume is published at path
+	fileInfo, err := os.Stat(req.GetVolumePath())
+	if err != nil {
+		if !os.IsNotExist(err) {
+			go p.reportVolumeCondition(req.GetVolumeId(),
+				fmt.Sprintf("volume path %s is broken: %v", req.GetVolumePath(), err))
+		}
+		return nil, status.Error(codes.NotFound,
+			fmt.Sprintf("could not find volume mount at path: %s; %v ", req.GetVolumePath(), err))
+	}
+
+	// A raw-block volume is published as a device file, whether or not the CO supplied the staging path
+	isRawBlock := fileInfo.Mode()&os.ModeDevice != 0
+	if !isRawBlock && req.StagingTargetPath != "" {
+		publishInfo, err := p.readStagedDeviceInfo(req.StagingTargetPath)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+
+		isRawBlock = publishInfo.FilesystemType == fsRaw
+	}
+
+	if isRawBlock {
+		return p.nodeGetBlockVolumeStats(req)
+	}
+
+	// If filesystem, return usage reported by FS
+	available, capacity, usage, inodes, inodesFree, inodesUsed, err := utils.GetFilesystemStats(req.GetVolumePath())
+	if err != nil {
+		log.Errorf("unable to get filesystem stats at path: %s; %v", req.GetVolumePath(), err)
+		go p.reportVolumeCondition(req.GetVolumeId(),
+			fmt.Sprintf("could not read filesystem at volume path %s: %v", req.GetVolumePath(), err))
+		return nil, status.Error(codes.Unknown, "Failed to get filesystem stats")
+	}
+	go p.reportVolumeUsage(&storage.VolumeUsage{
+		VolumeName:     req.GetVolumeId(),
+		TotalBytes:     uint64(capacity),
+		UsedBytes:      uint64(usage),
+		AvailableBytes: uint64(available),
+		TotalInodes:    uint64(inodes),
+		UsedInodes:     uint64(inodesUsed),
+	})
+
+	volumeUsage := []*csi.VolumeUsage{
+		{
+			Unit:      csi.VolumeUsage_BYTES,
+			Available: available,
+			Total:     capacity,
+			Used:      usage,
+		},
+	}
+
+	// Some filesystems, such as NFS exports of filesystems with dynamic inodes, report no inode counts
+	if inodes > 0 {
+		volumeUsage = append(volumeUsage, &csi.VolumeUsage{
+			Unit:      csi.VolumeUsage_INODES,
+			Available: inodesFree,
+			Total:     inodes,
+			Used:      inodesUsed,
+		})
+	}
+
+	return &csi.NodeGetVolumeStatsResponse{Usage: volumeUsage}, nil
+}
+
+// nodeGetBlockVolumeStats returns the capacity of a raw-block volume.  Trident cannot tell which blocks
+// an application uses, so only the size of the device is reported, and the storage driver remains the
+// source of the volume's usage.
+func (p *Plugin) nodeGetBlockVolumeStats(req *csi.NodeGetVolumeStatsRequest) (*csi.NodeGetVolumeStatsResponse, error) {
+
+	capacity, err := utils.GetBlockDeviceSize(req.GetVolumePath())
+	if err != nil {
+		log.Errorf("unable to get block device size at path: %s; %v", req.GetVolumePath(), err)
+		go p.reportVolumeCondition(req.GetVolumeId(),
+			fmt.Sprintf("could not read block device at volume path %s: %v", req.GetVolumePath(), err))
+		return nil, status.Error(codes.Unknown, "Failed to get block device size")
+	}
+
+	return &csi.NodeGetVolumeStatsResponse{
+		Usage: []*csi.VolumeUsage{
+			{
+				Unit:  csi.VolumeUsage_BYTES,
+				Total: capacity,
+			},
+		},
+	}, nil
+}
+
+// reportVolumeUsage sends the space consumed by a volume mounted on this node to the controller, so
+// that it can be reported along with the usage collected from the storage drivers.
+func (p *Plugin) reportVolumeUsage(usage *storage.VolumeUsage) {
+
+	if p.restClient == nil {
+		return
+	}
+
+	usage.Source = storage.VolumeUsageSourceNode
+	usage.Node = p.nodeName
+	usage.Updated = time.Now().UTC().Format(time.RFC3339)
+
+	if err := p.restClient.UpdateVolumeUsage(usage); err != nil {
+		log.WithFields(log.Fields{
+			"volume": usage.VolumeName,
+			"error":  err,
+		}).Debug("Could not report volume usage.")
+	}
+}
+
+// reportVolumeCondition sends a problem with a volume mounted on this node to the controller, so that it
+// can be surfaced along with the problems the storage drivers detect.  The problem is cleared when the
+// node next reports the volume's usage.
+func (p *Plugin) reportVolumeCondition(volumeName, message string) {
+
+	if p.restClient == nil {
+		return
+	}
+
+	condition := &storage.VolumeCondition{
+		VolumeName: volumeName,
+		Abnormal:   true,
+		Message:    message,
+		Source:     storage.VolumeUsageSourceNode,
+		Node:       p.nodeName,
+		Updated:    time.Now().UTC().Format(time.RFC3339),
+	}
+	if err := p.restClient.UpdateVolumeCondition(condition); err != nil {
+		log.WithFields(log.Fields{
+			"volume": volumeName,
+			"error":  err,
+		}).Debug("Could not report volume condition.")
+	}
 }
 
 // The CO only calls NodeExpandVolume for the Block protocol as the filesystem has to be mounted to perform
@@ -311,6 +438,10 @@ func (p *Plugin) NodeExpandVolume(
 		return nil, status.Errorf(codes.Internal, err.Error())
 	}
 
+	if p.csiProxy != nil {
+		return p.nodeExpandWindowsVolume(ctx, publishInfo)
+	}
+
 	lunID := int(publishInfo.IscsiLunNumber)
 
 	log.WithFields(log.Fields{
@@ -324,7 +455,7 @@ func (p *Plugin) NodeExpandVolume(
 	// Make sure device is ready
 	if utils.IsAlreadyAttached(lunID, publishInfo.IscsiTargetIQN) {
 
-		// Rescan device to detect increased size
+		// Rescan all paths to detect increased size, then grow the multipath and LUKS devices on them
 		if err = utils.ISCSIRescanDevices(publishInfo.IscsiTargetIQN, publishInfo.IscsiLunNumber, requiredBytes); err != nil {
 			log.WithFields(log.Fields{
 				"device": publishInfo.DevicePath,
@@ -333,8 +464,9 @@ func (p *Plugin) NodeExpandVolume(
 			return nil, status.Error(codes.Internal, err.Error())
 		}
 
-		// Expand filesystem
-		if publishInfo.FilesystemType != fsRaw {
+		// Expand filesystem, unless the volume is a raw block device
+		isRawBlock := publishInfo.FilesystemType == fsRaw || req.GetVolumeCapability().GetBlock() != nil
+		if !isRawBlock {
 			filesystemSize, err := utils.ExpandISCSIFilesystem(publishInfo, stagingTargetPath)
 			if err != nil {
 				log.WithFields(log.Fields{
@@ -382,13 +514,19 @@ func (p *Plugin) NodeGetInfo(
 	log.WithFields(fields).Debug(">>>> NodeGetInfo")
 	defer log.WithFields(fields).Debug("<<<< NodeGetInfo")
 
-	return &csi.NodeGetInfoResponse{NodeId: p.nodeName}, nil
+	response := &csi.NodeGetInfoResponse{NodeId: p.nodeName}
+	if len(p.topologyLabels) > 0 {
+		response.AccessibleTopology = &csi.Topology{Segments: p.topologyLabels}
+	}
+	return response, nil
 }
 
 func (p *Plugin) nodeGetInfo() *utils.Node {
 	iscsiWWN := ""
 	iscsiWWNs, err := utils.GetInitiatorIqns()
-	if err != nil {
+	if p.csiProxy != nil {
+		iscsiWWN = windowsNodeIQN(p.nodeName)
+	} else if err != nil {
 		log.WithField("error", err).Warn("Problem getting iSCSI initiator name.")
 	} else if iscsiWWNs == nil || len(iscsiWWNs) == 0 {
 		log.Warn("Could not find iSCSI initiator name.")
@@ -406,22 +544,99 @@ func (p *Plugin) nodeGetInfo() *utils.Node {
 	}
 
 	node := &utils.Node{
-		Name: p.nodeName,
-		IQN:  iscsiWWN,
-		IPs:  ips,
+		Name:           p.nodeName,
+		IQN:            iscsiWWN,
+		IPs:            ips,
+		TopologyLabels: p.topologyLabels,
+	}
+	if p.csiProxy == nil {
+		node.NQN = utils.GetHostNQN()
+		node.Health = utils.GetNodeHealth()
+		node.Multipath = p.multipathStatus
 	}
 	return node
 }
 
+// nodeValidateMultipath checks the node's multipath configuration, remediating it if enabled, so
+// that the result is reported when the node registers and with each heartbeat.
+func (p *Plugin) nodeValidateMultipath() {
+
+	if p.csiProxy != nil {
+		return
+	}
+
+	p.multipathStatus = utils.ValidateMultipath(p.multipathRemediation)
+
+	fields := log.Fields{
+		"node":       p.nodeName,
+		"remediated": p.multipathStatus.Remediated,
+	}
+	if p.multipathStatus.Compatible {
+		log.WithFields(fields).Info("Multipath configuration is compatible with ONTAP LUNs.")
+	} else {
+		fields["problems"] = strings.Join(p.multipathStatus.Problems, "; ")
+		log.WithFields(fields).Warning("Multipath configuration is not compatible with ONTAP LUNs.")
+	}
+}
+
+// nodeEnsureISCSIInterface creates the iSCSI interface for the node's iSCSI network interface, if it has
+// one, so that a missing network interface is reported when the node starts rather than when a volume
+// is staged.
+func (p *Plugin) nodeEnsureISCSIInterface() {
+
+	if p.csiProxy != nil || p.iscsiNetworkInterface == "" {
+		return
+	}
+
+	fields := log.Fields{"node": p.nodeName, "netInterface": p.iscsiNetworkInterface}
+	if iface, err := utils.EnsureISCSIInterface(p.iscsiNetworkInterface); err != nil {
+		log.WithFields(fields).WithError(err).Warning("Could not create iSCSI interface.")
+	} else {
+		log.WithFields(fields).WithField("iface", iface).Info("iSCSI logins are bound to network interface.")
+	}
+}
+
+// bindISCSIInterface binds a volume's iSCSI logins to the network interface set by its backend or, if
+// its backend sets none, to the node's.  Volumes whose backend names its own iSCSI interface keep it.
+func (p *Plugin) bindISCSIInterface(publishInfo *utils.VolumePublishInfo) error {
+
+	if publishInfo.IscsiInterface != "" && publishInfo.IscsiInterface != "default" {
+		return nil
+	}
+
+	netInterface := publishInfo.IscsiNetworkInterface
+	if netInterface == "" {
+		netInterface = p.iscsiNetworkInterface
+	}
+	if netInterface == "" {
+		return nil
+	}
+
+	iface, err := utils.EnsureISCSIInterface(netInterface)
+	if err != nil {
+		return err
+	}
+	publishInfo.IscsiInterface = iface
+	publishInfo.IscsiNetworkInterface = netInterface
+	return nil
+}
+
 func (p *Plugin) nodeRegisterWithController() {
 
+	p.nodeValidateMultipath()
+	p.nodeEnsureISCSIInterface()
+
 	// Assemble the node details that we will register with the controller
 	node := p.nodeGetInfo()
 
 	// The controller may not be fully initialized by the time the node is ready to register,
 	// so retry until it is responding on the back channel and we have registered the node.
 	registerNode := func() error {
-		return p.restClient.CreateNode(node)
+		topologyLabels, err := p.restClient.CreateNode(node)
+		if err == nil {
+			p.topologyLabels = topologyLabels
+		}
+		return err
 	}
 
 	registerNodeNotify := func(err error, duration time.Duration) {
@@ -444,6 +659,30 @@ func (p *Plugin) nodeRegisterWithController() {
 	log.WithField("node", p.nodeName).Debug("Communication with controller established, node registered.")
 }
 
+// nodeHeartbeat registers the node again periodically, so that the controller knows it is still
+// alive and doesn't deregister it as stale.  It also retries deleting ephemeral volumes the controller
+// couldn't delete when their pods finished.  It runs until the plugin is deactivated.
+func (p *Plugin) nodeHeartbeat() {
+
+	ticker := time.NewTicker(nodeHeartbeatPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if _, err := p.restClient.CreateNode(p.nodeGetInfo()); err != nil {
+				log.WithFields(log.Fields{
+					"node":  p.nodeName,
+					"error": err,
+				}).Warning("Could not send node heartbeat to controller.")
+			}
+			p.deleteDetachedEphemeralVolumes()
+		case <-p.stopNodeHeartbeat:
+			return
+		}
+	}
+}
+
 func (p *Plugin) nodeDeregisterWithController() error {
 	return p.restClient.DeleteNode(p.nodeName)
 }
@@ -520,6 +759,7 @@ func (p *Plugin) nodePublishNFSVolume(
 		mountOptions = append(mountOptions, "ro")
 		publishInfo.MountOptions = strings.Join(mountOptions, ",")
 	}
+	publishInfo.MountOptions = addSELinuxContext(publishInfo.MountOptions, req.GetVolumeCapability())
 
 	err = utils.AttachNFSVolume(req.VolumeContext["internalName"], req.TargetPath, publishInfo)
 	if err != nil {
@@ -617,12 +857,18 @@ func (p *Plugin) nodeStageISCSIVolume(
 	publishInfo.IscsiTargetIQN = req.PublishContext["iscsiTargetIqn"]
 	publishInfo.IscsiLunNumber = int32(lunID)
 	publishInfo.IscsiInterface = req.PublishContext["iscsiInterface"]
+	publishInfo.IscsiNetworkInterface = req.PublishContext["iscsiNetworkInterface"]
+	publishInfo.FormatOptions = req.PublishContext["formatOptions"]
 	publishInfo.IscsiIgroup = req.PublishContext["iscsiIgroup"]
 	publishInfo.IscsiUsername = req.PublishContext["iscsiUsername"]
 	publishInfo.IscsiInitiatorSecret = req.PublishContext["iscsiInitiatorSecret"]
 	publishInfo.IscsiTargetUsername = req.PublishContext["iscsiTargetUsername"]
 	publishInfo.IscsiTargetSecret = req.PublishContext["iscsiTargetSecret"]
 
+	if err = p.bindISCSIInterface(publishInfo); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
 	// Perform the login/rescan/discovery/(optionally)format, mount & get the device back in the publish info
 	if err := utils.AttachISCSIVolume(req.VolumeContext["internalName"], "", publishInfo); err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
@@ -633,20 +879,134 @@ func (p *Plugin) nodeStageISCSIVolume(
 		return nil, err
 	}
 
+	if fstype != fsRaw {
+		if err = p.nodeCheckFilesystem(volumeId, publishInfo, req.PublishContext["fsckPolicy"]); err != nil {
+			return nil, err
+		}
+	}
+
+	// A device that can't be tuned still works, so the volume is staged and reported instead
+	tuning := utils.BlockDeviceTuning{
+		Scheduler:   req.PublishContext["ioScheduler"],
+		ReadAheadKB: req.PublishContext["readAheadKB"],
+		NrRequests:  req.PublishContext["nrRequests"],
+	}
+	if err = utils.TuneBlockDevice(volumeId, publishInfo.DevicePath, tuning); err != nil {
+		log.WithFields(log.Fields{
+			"volumeId": volumeId,
+			"error":    err,
+		}).Warning("Could not tune block device.")
+		go p.reportVolumeCondition(volumeId, err.Error())
+	}
+
 	// Save the device info to the staging path for use in the publish & unstage calls
 	if err := p.writeStagedDeviceInfo(stagingTargetPath, publishInfo, volumeId); err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
+	// A volume expanded before it was first staged, such as a snapshot restored into a larger PVC, still has
+	// the filesystem of its source.  Volumes published by older controllers don't say, so they aren't grown.
+	if growFilesystem, _ := strconv.ParseBool(req.PublishContext["growFilesystem"]); growFilesystem && fstype != fsRaw {
+		filesystemSize, err := utils.ExpandISCSIFilesystem(publishInfo, stagingTargetPath)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"device":         publishInfo.DevicePath,
+				"filesystemType": publishInfo.FilesystemType,
+				"error":          err,
+			}).Error("Unable to grow filesystem.")
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		log.WithFields(log.Fields{
+			"volumeId":       volumeId,
+			"filesystemSize": filesystemSize,
+		}).Debug("Filesystem size after staging.")
+	}
+
 	return &csi.NodeStageVolumeResponse{}, nil
 }
 
+// nodeCheckFilesystem checks the filesystem of a volume being staged as its fsck policy requires, so that
+// a corrupt filesystem fails the stage rather than being mounted.  A filesystem that is already mounted,
+// such as by an earlier stage of the volume, isn't checked.
+func (p *Plugin) nodeCheckFilesystem(volumeId string, publishInfo *utils.VolumePublishInfo, policy string) error {
+
+	if policy == "" || policy == utils.FsckPolicyNever {
+		return nil
+	}
+
+	if mounted, err := utils.IsMounted(publishInfo.DevicePath, ""); err != nil {
+		return status.Error(codes.Internal, err.Error())
+	} else if mounted {
+		log.WithFields(log.Fields{
+			"volumeId": volumeId,
+			"device":   publishInfo.DevicePath,
+		}).Debug("Filesystem is mounted, skipping filesystem check.")
+		return nil
+	}
+
+	err := utils.CheckFilesystem(publishInfo.DevicePath, publishInfo.FilesystemType, policy)
+	if utils.IsFilesystemCorruptError(err) {
+		go p.reportVolumeCondition(volumeId, err.Error())
+		return status.Error(codes.FailedPrecondition, err.Error())
+	} else if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	return nil
+}
+
 func (p *Plugin) nodeUnstageISCSIVolume(
 	ctx context.Context, req *csi.NodeUnstageVolumeRequest, publishInfo *utils.VolumePublishInfo,
 ) (*csi.NodeUnstageVolumeResponse, error) {
 
+	volumeId, stagingTargetPath, err := p.getVolumeIdAndStagingPath(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// The staged device info is kept until the device is gone, so a failed removal is retried on the
+	// next unstage, rather than leaving a stale device behind to block a later attach of the same LUN
+	if err := p.detachISCSIDevice(publishInfo); err != nil {
+		log.WithFields(log.Fields{
+			"volume": volumeId,
+			"error":  err,
+		}).Error("Could not remove iSCSI device.")
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	if err := utils.RemoveBlockDeviceTuning(volumeId); err != nil {
+		log.WithFields(log.Fields{
+			"volume": volumeId,
+			"error":  err,
+		}).Warning("Could not remove udev rule for block device tuning.")
+	}
+
+	// Delete the device info we saved to the staging path so unstage can succeed
+	if err := p.clearStagedDeviceInfo(stagingTargetPath, volumeId); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	// Ensure that the temporary mount point created during a filesystem expand operation is removed.
+	if err := utils.UmountAndRemoveTemporaryMountPoint(stagingTargetPath); err != nil {
+		log.WithField("stagingTargetPath", stagingTargetPath).Errorf(
+			"Failed to remove directory in staging target path; %s", err)
+		return nil, fmt.Errorf("failed to remove temporary directory in staging target path %s; %s",
+			stagingTargetPath, err)
+	}
+
+	return &csi.NodeUnstageVolumeResponse{}, nil
+}
+
+// detachISCSIDevice removes a LUN's device from the host, and logs out of its target if nothing else uses it.
+// The target is not logged out of if the device could not be removed, so that the removal may be retried.
+func (p *Plugin) detachISCSIDevice(publishInfo *utils.VolumePublishInfo) error {
+
 	// Delete the device from the host
-	utils.PrepareDeviceForRemoval(int(publishInfo.IscsiLunNumber), publishInfo.IscsiTargetIQN)
+	err := utils.RemoveISCSIDevice(int(publishInfo.IscsiLunNumber), publishInfo.IscsiTargetIQN,
+		publishInfo.DevicePath)
+	if err != nil {
+		return fmt.Errorf("could not remove device for LUN %d of target %s; %v", publishInfo.IscsiLunNumber,
+			publishInfo.IscsiTargetIQN, err)
+	}
 
 	// Get map of hosts and sessions for given Target IQN
 	hostSessionMap := utils.GetISCSIHostSessionMapForTarget(publishInfo.IscsiTargetIQN)
@@ -678,25 +1038,7 @@ func (p *Plugin) nodeUnstageISCSIVolume(
 		}
 	}
 
-	volumeId, stagingTargetPath, err := p.getVolumeIdAndStagingPath(req)
-	if err != nil {
-		return nil, err
-	}
-
-	// Delete the device info we saved to the staging path so unstage can succeed
-	if err := p.clearStagedDeviceInfo(stagingTargetPath, volumeId); err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
-	}
-
-	// Ensure that the temporary mount point created during a filesystem expand operation is removed.
-	if err := utils.UmountAndRemoveTemporaryMountPoint(stagingTargetPath); err != nil {
-		log.WithField("stagingTargetPath", stagingTargetPath).Errorf(
-			"Failed to remove directory in staging target path; %s", err)
-		return nil, fmt.Errorf("failed to remove temporary directory in staging target path %s; %s",
-			stagingTargetPath, err)
-	}
-
-	return &csi.NodeUnstageVolumeResponse{}, nil
+	return nil
 }
 
 func (p *Plugin) nodePublishISCSIVolume(
@@ -735,6 +1077,7 @@ func (p *Plugin) nodePublishISCSIVolume(
 		}
 	} else {
 		// Mount the device
+		publishInfo.MountOptions = addSELinuxContext(publishInfo.MountOptions, req.GetVolumeCapability())
 		err = utils.MountDevice(publishInfo.DevicePath, req.TargetPath, publishInfo.MountOptions, false)
 		if err != nil {
 			return nil, status.Errorf(codes.Internal, "unable to mount device; %s", err)
@@ -892,6 +1235,8 @@ func (p *Plugin) clearStagedTrackingFile(volumeId string) error {
 func (p *Plugin) getVolumeProtocolFromPublishInfo(publishInfo *utils.VolumePublishInfo) (tridentconfig.Protocol, error) {
 	if publishInfo.VolumeAccessInfo.NfsServerIP != "" && publishInfo.VolumeAccessInfo.IscsiTargetIQN == "" {
 		return tridentconfig.File, nil
+	} else if publishInfo.VolumeAccessInfo.SMBPath != "" && publishInfo.VolumeAccessInfo.IscsiTargetIQN == "" {
+		return tridentconfig.File, nil
 	} else if publishInfo.VolumeAccessInfo.IscsiTargetIQN != "" && publishInfo.VolumeAccessInfo.NfsServerIP == "" {
 		return tridentconfig.Block, nil
 	} else {
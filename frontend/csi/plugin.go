@@ -4,7 +4,9 @@ package csi
 
 import (
 	"os"
+	"runtime"
 	"strings"
+	"time"
 
 	"github.com/container-storage-interface/spec/lib/go/csi"
 	"github.com/netapp/trident/utils"
@@ -15,6 +17,7 @@ import (
 	tridentconfig "github.com/netapp/trident/config"
 	"github.com/netapp/trident/core"
 	"github.com/netapp/trident/frontend/csi/helpers"
+	"github.com/netapp/trident/utils/csiproxy"
 )
 
 const (
@@ -42,6 +45,25 @@ type Plugin struct {
 	vCap  []*csi.VolumeCapability_AccessMode
 
 	opCache map[string]bool
+
+	stopNodeHeartbeat chan struct{}
+	// topologyLabels are this node's topology segments, as read by the controller when the node registered
+	topologyLabels map[string]string
+
+	// csiProxy performs the node's host operations on Windows, and is nil on other platforms
+	csiProxy csiproxy.Interface
+
+	// multipathRemediation drops in Trident's multipath configuration if the node's isn't compatible
+	multipathRemediation bool
+	// multipathStatus is the state of the node's multipath configuration, as checked when it registered
+	multipathStatus *utils.MultipathStatus
+
+	// iscsiSessionMonitorPeriod is how often the iSCSI sessions of staged volumes are checked, or zero if never
+	iscsiSessionMonitorPeriod time.Duration
+	// iscsiPaths is the state of each path to a staged iSCSI target, by target IQN and portal
+	iscsiPaths map[string]*iscsiPathState
+	// iscsiNetworkInterface is the network interface the node binds iSCSI logins to, for backends that set none
+	iscsiNetworkInterface string
 }
 
 func NewControllerPlugin(
@@ -68,6 +90,9 @@ func NewControllerPlugin(
 		csi.ControllerServiceCapability_RPC_LIST_SNAPSHOTS,
 		csi.ControllerServiceCapability_RPC_EXPAND_VOLUME,
 		csi.ControllerServiceCapability_RPC_CLONE_VOLUME,
+		csi.ControllerServiceCapability_RPC_GET_VOLUME,
+		csi.ControllerServiceCapability_RPC_LIST_VOLUMES_PUBLISHED_NODES,
+		csi.ControllerServiceCapability_RPC_VOLUME_CONDITION,
 	})
 
 	// Define volume capabilities
@@ -83,7 +108,8 @@ func NewControllerPlugin(
 }
 
 func NewNodePlugin(
-	nodeName, endpoint, caCert, clientCert, clientKey string, orchestrator core.Orchestrator,
+	nodeName, endpoint, caCert, clientCert, clientKey string, tlsOptions utils.TLSOptions,
+	orchestrator core.Orchestrator,
 ) (*Plugin, error) {
 
 	p := &Plugin{
@@ -114,11 +140,15 @@ func NewNodePlugin(
 
 	restURL := "https://" + hostname + ":" + port
 	var err error
-	p.restClient, err = CreateTLSRestClient(restURL, caCert, clientCert, clientKey)
+	p.restClient, err = CreateTLSRestClient(restURL, caCert, clientCert, clientKey, tlsOptions)
 	if err != nil {
 		return nil, err
 	}
 
+	if err = p.connectCSIProxy(); err != nil {
+		return nil, err
+	}
+
 	// Define volume capabilities
 	p.addVolumeCapabilityAccessModes([]csi.VolumeCapability_AccessMode_Mode{
 		csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER,
@@ -135,7 +165,7 @@ func NewNodePlugin(
 // CSI Sanity expects a single process to respond to controller, node, and
 // identity interfaces.
 func NewAllInOnePlugin(
-	nodeName, endpoint, caCert, clientCert, clientKey string,
+	nodeName, endpoint, caCert, clientCert, clientKey string, tlsOptions utils.TLSOptions,
 	orchestrator core.Orchestrator, helper *helpers.HybridPlugin,
 ) (*Plugin, error) {
 
@@ -159,6 +189,9 @@ func NewAllInOnePlugin(
 		csi.ControllerServiceCapability_RPC_LIST_SNAPSHOTS,
 		csi.ControllerServiceCapability_RPC_EXPAND_VOLUME,
 		csi.ControllerServiceCapability_RPC_CLONE_VOLUME,
+		csi.ControllerServiceCapability_RPC_GET_VOLUME,
+		csi.ControllerServiceCapability_RPC_LIST_VOLUMES_PUBLISHED_NODES,
+		csi.ControllerServiceCapability_RPC_VOLUME_CONDITION,
 	})
 
 	p.addNodeServiceCapabilities([]csi.NodeServiceCapability_RPC_Type{
@@ -176,11 +209,15 @@ func NewAllInOnePlugin(
 	}
 	restURL := "https://" + tridentconfig.ServerCertName + ":" + port
 	var err error
-	p.restClient, err = CreateTLSRestClient(restURL, caCert, clientCert, clientKey)
+	p.restClient, err = CreateTLSRestClient(restURL, caCert, clientCert, clientKey, tlsOptions)
 	if err != nil {
 		return nil, err
 	}
 
+	if err = p.connectCSIProxy(); err != nil {
+		return nil, err
+	}
+
 	// Define volume capabilities
 	p.addVolumeCapabilityAccessModes([]csi.VolumeCapability_AccessMode_Mode{
 		csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER,
@@ -193,13 +230,50 @@ func NewAllInOnePlugin(
 	return p, nil
 }
 
+// connectCSIProxy connects a node plugin running on Windows to CSI Proxy, through which it stages and
+// publishes volumes.
+func (p *Plugin) connectCSIProxy() error {
+
+	if runtime.GOOS != "windows" {
+		return nil
+	}
+
+	client, err := csiproxy.NewClient()
+	if err != nil {
+		return err
+	}
+	p.csiProxy = client
+	log.Info("Connected to CSI Proxy.")
+	return nil
+}
+
+// SetMultipathRemediation determines whether a node whose multipath configuration isn't compatible
+// with ONTAP LUNs gets Trident's multipath configuration when it registers.
+func (p *Plugin) SetMultipathRemediation(remediate bool) {
+	p.multipathRemediation = remediate
+}
+
+// SetISCSINetworkInterface binds the node's iSCSI logins to a network interface, through an iSCSI
+// interface the node creates for it, for volumes whose backend doesn't set a network interface.
+func (p *Plugin) SetISCSINetworkInterface(netInterface string) {
+	p.iscsiNetworkInterface = netInterface
+}
+
 func (p *Plugin) Activate() error {
+	p.stopNodeHeartbeat = make(chan struct{})
 	go func() {
 		log.Info("Activating CSI frontend.")
+		// The node registers before serving CSI calls, so it knows its topology when the CO asks for it
+		if p.role == CSINode || p.role == CSIAllInOne {
+			p.nodeRegisterWithController()
+		}
 		p.grpc = NewNonBlockingGRPCServer()
 		p.grpc.Start(p.endpoint, p, p, p)
 		if p.role == CSINode || p.role == CSIAllInOne {
-			go p.nodeRegisterWithController()
+			go p.nodeHeartbeat()
+			if p.csiProxy == nil {
+				go p.iscsiSessionMonitor()
+			}
 		}
 	}()
 	return nil
@@ -209,6 +283,11 @@ func (p *Plugin) Deactivate() error {
 	log.Info("Deactivating CSI frontend.")
 	p.grpc.GracefulStop()
 	if p.role == CSINode || p.role == CSIAllInOne {
+		close(p.stopNodeHeartbeat)
+		if p.csiProxy != nil {
+			p.csiProxy.Close()
+		}
+		defer p.restClient.Close()
 		err := p.nodeDeregisterWithController()
 		if err != nil {
 			log.Errorf("Error deregistering node %s with controller; %v", p.nodeName, err)
@@ -269,6 +348,8 @@ func (p *Plugin) getCSIErrorForOrchestratorError(err error) error {
 		return status.Error(codes.FailedPrecondition, err.Error())
 	} else if utils.IsNotFoundError(err) {
 		return status.Error(codes.NotFound, err.Error())
+	} else if utils.IsQuotaExceededError(err) {
+		return status.Error(codes.ResourceExhausted, err.Error())
 	} else {
 		return status.Error(codes.Unknown, err.Error())
 	}
@@ -4,53 +4,46 @@ package csi
 
 import (
 	"bytes"
-	"crypto/tls"
-	"crypto/x509"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 
 	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/storage"
 	"github.com/netapp/trident/utils"
 )
 
 type RestClient struct {
-	url        string
-	httpClient http.Client
+	url          string
+	httpClient   http.Client
+	certReloader *utils.CertReloader
 }
 
-func CreateTLSRestClient(url, caFile, certFile, keyFile string) (*RestClient, error) {
-	tlsConfig := &tls.Config{}
-	if "" != caFile {
-		caCert, err := ioutil.ReadFile(caFile)
-		if err != nil {
-			return nil, err
-		}
-		caCertPool := x509.NewCertPool()
-		caCertPool.AppendCertsFromPEM(caCert)
-		tlsConfig.RootCAs = caCertPool
-		tlsConfig.ServerName = config.ServerCertName
-	} else {
-		tlsConfig.InsecureSkipVerify = true
-	}
-	if "" != certFile && "" != keyFile {
-		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
-		if err != nil {
-			return nil, err
-		}
-		tlsConfig.Certificates = []tls.Certificate{cert}
+// CreateTLSRestClient returns a client of the controller's HTTPS REST frontend.  Its certificates
+// are reread as they are rotated, until the client is closed.
+func CreateTLSRestClient(url, caFile, certFile, keyFile string, options utils.TLSOptions) (*RestClient, error) {
+	certReloader, err := utils.NewCertReloader(caFile, certFile, keyFile)
+	if err != nil {
+		return nil, err
 	}
+	certReloader.Start(options.ReloadInterval)
 	return &RestClient{
 		url: url,
 		httpClient: http.Client{
 			Transport: &http.Transport{
-				TLSClientConfig: tlsConfig,
+				TLSClientConfig: certReloader.ClientTLSConfig(config.ServerCertName, options),
 			},
 		},
+		certReloader: certReloader,
 	}, nil
 }
 
+// Close stops rereading the client's certificates.
+func (c *RestClient) Close() {
+	c.certReloader.Stop()
+}
+
 // InvokeAPI makes a REST call to the CSI Controller REST endpoint. The body must be a marshaled JSON byte array (
 // or nil). The method is the HTTP verb (i.e. GET, POST, ...).  The resource path is appended to the base URL to
 // identify the desired server resource; it should start with '/'.
@@ -106,21 +99,33 @@ func (c *RestClient) InvokeAPI(requestBody []byte, method string, resourcePath s
 	return response, responseBody, err
 }
 
-// CreateNode registers the node with the CSI controller server
-func (c *RestClient) CreateNode(node *utils.Node) error {
+type CreateNodeResponse struct {
+	TopologyLabels map[string]string `json:"topologyLabels"`
+	Error          string            `json:"error,omitempty"`
+}
+
+// CreateNode registers the node with the CSI controller server, and returns the node's topology labels
+func (c *RestClient) CreateNode(node *utils.Node) (map[string]string, error) {
 	nodeData, err := json.Marshal(node)
 	if err != nil {
-		return fmt.Errorf("error parsing create node request; %v", err)
+		return nil, fmt.Errorf("error parsing create node request; %v", err)
 	}
-	resp, _, err := c.InvokeAPI(nodeData, "PUT", config.NodeURL+"/"+node.Name)
+	resp, respBody, err := c.InvokeAPI(nodeData, "PUT", config.NodeURL+"/"+node.Name)
 	if err != nil {
-		return fmt.Errorf("could not log into the Trident CSI Controller: %v", err)
+		return nil, fmt.Errorf("could not log into the Trident CSI Controller: %v", err)
 	}
 
 	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("could not add CSI node")
+		return nil, fmt.Errorf("could not add CSI node")
 	}
-	return nil
+
+	// Parse JSON data
+	respData := CreateNodeResponse{}
+	if err := json.Unmarshal(respBody, &respData); err != nil {
+		return nil, fmt.Errorf("could not parse node response: %s; %v", string(respBody), err)
+	}
+
+	return respData.TopologyLabels, nil
 }
 
 type ListNodesResponse struct {
@@ -167,3 +172,87 @@ func (c *RestClient) DeleteNode(name string) error {
 	}
 	return nil
 }
+
+// UpdateVolumeCondition reports a problem with a volume mounted on this node to the controller
+func (c *RestClient) UpdateVolumeCondition(condition *storage.VolumeCondition) error {
+	conditionData, err := json.Marshal(condition)
+	if err != nil {
+		return fmt.Errorf("error parsing volume condition request; %v", err)
+	}
+	resp, _, err := c.InvokeAPI(conditionData, "PUT", config.ConditionURL+"/"+condition.VolumeName)
+	if err != nil {
+		return fmt.Errorf("could not log into the Trident CSI Controller: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("could not update the condition of volume %s", condition.VolumeName)
+	}
+	return nil
+}
+
+// UpdateVolumeUsage reports the space consumed by a volume mounted on this node to the controller
+func (c *RestClient) UpdateVolumeUsage(usage *storage.VolumeUsage) error {
+	usageData, err := json.Marshal(usage)
+	if err != nil {
+		return fmt.Errorf("error parsing volume usage request; %v", err)
+	}
+	resp, _, err := c.InvokeAPI(usageData, "PUT", config.UsageURL+"/"+usage.VolumeName)
+	if err != nil {
+		return fmt.Errorf("could not log into the Trident CSI Controller: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("could not update the usage of volume %s", usage.VolumeName)
+	}
+	return nil
+}
+
+type CreateEphemeralVolumeResponse struct {
+	Volume *storage.EphemeralVolume `json:"ephemeralVolume,omitempty"`
+	Error  string                   `json:"error,omitempty"`
+}
+
+// CreateEphemeralVolume asks the controller to create a CSI inline ephemeral volume and publish it to this node
+func (c *RestClient) CreateEphemeralVolume(request *storage.EphemeralVolumeRequest) (*storage.EphemeralVolume, error) {
+	requestData, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing ephemeral volume request; %v", err)
+	}
+	resp, respBody, err := c.InvokeAPI(requestData, "PUT", config.EphemeralURL+"/"+request.Name)
+	if err != nil {
+		return nil, fmt.Errorf("could not log into the Trident CSI Controller: %v", err)
+	}
+
+	respData := CreateEphemeralVolumeResponse{}
+	if err := json.Unmarshal(respBody, &respData); err != nil {
+		return nil, fmt.Errorf("could not parse ephemeral volume response: %s; %v", string(respBody), err)
+	}
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not create ephemeral volume %s; %s", request.Name, respData.Error)
+	}
+	if respData.Volume == nil || respData.Volume.PublishInfo == nil {
+		return nil, fmt.Errorf("controller returned no publish info for ephemeral volume %s", request.Name)
+	}
+
+	return respData.Volume, nil
+}
+
+// DeleteEphemeralVolume asks the controller to delete a CSI inline ephemeral volume this node no longer uses
+func (c *RestClient) DeleteEphemeralVolume(name string) error {
+	resp, _, err := c.InvokeAPI(nil, "DELETE", config.EphemeralURL+"/"+name)
+	if err != nil {
+		return fmt.Errorf("could not log into the Trident CSI Controller: %v", err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNoContent:
+	case http.StatusNotFound:
+	case http.StatusGone:
+		break
+	default:
+		return fmt.Errorf("could not delete ephemeral volume %s", name)
+	}
+	return nil
+}
@@ -12,6 +12,8 @@ import (
 	"github.com/container-storage-interface/spec/lib/go/csi"
 	log "github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
+
+	"github.com/netapp/trident/utils"
 )
 
 func ParseEndpoint(ep string) (string, string, error) {
@@ -59,3 +61,56 @@ func logGRPC(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, h
 	}
 	return resp, err
 }
+
+// addSELinuxContext adds the SELinux context that kubelet passes in a volume's mount flags, when the
+// CSIDriver enables seLinuxMount, to the options a filesystem is mounted with.  Mounting with the pod's
+// context spares kubelet from recursively relabeling every file on the volume.
+func addSELinuxContext(mountOptions string, capability *csi.VolumeCapability) string {
+
+	// Any context set explicitly, such as by a storage class, takes precedence
+	if strings.Contains(","+mountOptions, ",context=") {
+		return mountOptions
+	}
+
+	for _, flag := range capability.GetMount().GetMountFlags() {
+		if strings.HasPrefix(flag, "context=") {
+			if mountOptions == "" {
+				return flag
+			}
+			return mountOptions + "," + flag
+		}
+	}
+	return mountOptions
+}
+
+// setVolumeCHAPCredentials adds the CHAP credentials in a volume's node publish secret, if it has any,
+// to the volume's publish info, so the backend grants the node access with them instead of with its
+// own.  The secret's keys are named like the CHAP fields of a backend config, and a username and
+// initiator secret are required.  A target username and secret enable bidirectional CHAP.
+func setVolumeCHAPCredentials(publishInfo *utils.VolumePublishInfo, secrets map[string]string) error {
+
+	username := secrets[chapUsernameKey]
+	initiatorSecret := secrets[chapInitiatorSecretKey]
+	targetUsername := secrets[chapTargetUsernameKey]
+	targetSecret := secrets[chapTargetInitiatorSecretKey]
+
+	// A node publish secret may be meant for something else
+	if username == "" && initiatorSecret == "" && targetUsername == "" && targetSecret == "" {
+		return nil
+	}
+	if username == "" || initiatorSecret == "" {
+		return fmt.Errorf("node publish secret must set both %s and %s", chapUsernameKey, chapInitiatorSecretKey)
+	}
+	if (targetUsername == "") != (targetSecret == "") {
+		return fmt.Errorf("node publish secret must set both or neither of %s and %s",
+			chapTargetUsernameKey, chapTargetInitiatorSecretKey)
+	}
+
+	publishInfo.UseCHAP = true
+	publishInfo.VolumeCHAP = true
+	publishInfo.IscsiUsername = username
+	publishInfo.IscsiInitiatorSecret = initiatorSecret
+	publishInfo.IscsiTargetUsername = targetUsername
+	publishInfo.IscsiTargetSecret = targetSecret
+	return nil
+}
@@ -0,0 +1,526 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package csi
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	tridentconfig "github.com/netapp/trident/config"
+	"github.com/netapp/trident/storage"
+	"github.com/netapp/trident/utils"
+	"github.com/netapp/trident/utils/csiproxy"
+)
+
+// Windows nodes can't mount volumes from inside the node plugin's container, so every host operation is
+// delegated to CSI Proxy.  SMB shares are mapped when staged and linked to each target path when published.
+// iSCSI LUNs are logged in to, partitioned and formatted with NTFS when staged, and the resulting volume is
+// mounted at each target path when published.  The staging directory holds only the publish info, as on Linux.
+
+const (
+	fsNTFS = "ntfs"
+	fsSMB  = "smb"
+
+	// windowsIQNPrefix is the prefix of the default initiator name of the Microsoft iSCSI initiator
+	windowsIQNPrefix = "iqn.1991-05.com.microsoft:"
+
+	defaultISCSIPort       = 3260
+	windowsDiskWaitTimeout = 60 * time.Second
+)
+
+// windowsNodeIQN returns the default initiator name of a Windows host, which the Microsoft iSCSI initiator
+// derives from the host's name.
+func windowsNodeIQN(nodeName string) string {
+	return windowsIQNPrefix + strings.ToLower(nodeName)
+}
+
+// normalizeWindowsPath converts a path received from the CO to the form CSI Proxy expects, with backslash
+// separators and a drive letter.
+func normalizeWindowsPath(path string) string {
+	normalizedPath := strings.Replace(path, "/", `\`, -1)
+	if strings.HasPrefix(normalizedPath, `\`) && !strings.HasPrefix(normalizedPath, `\\`) {
+		normalizedPath = "c:" + normalizedPath
+	}
+	return normalizedPath
+}
+
+// parseTargetPortal splits an iSCSI portal of the form address[:port] for CSI Proxy.
+func parseTargetPortal(portal string) (*csiproxy.TargetPortal, error) {
+
+	host, port, err := net.SplitHostPort(portal)
+	if err != nil {
+		// No port, so the address may be a bare IPv4 or bracketed IPv6 address
+		return &csiproxy.TargetPortal{
+			TargetAddress: strings.Trim(portal, "[]"),
+			TargetPort:    defaultISCSIPort,
+		}, nil
+	}
+
+	portNumber, err := strconv.ParseUint(port, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid port in iSCSI portal %s", portal)
+	}
+	return &csiproxy.TargetPortal{TargetAddress: host, TargetPort: uint32(portNumber)}, nil
+}
+
+// targetPortals returns the CSI Proxy form of all of a LUN's iSCSI portals.
+func targetPortals(publishInfo *utils.VolumePublishInfo) ([]*csiproxy.TargetPortal, error) {
+
+	portals := make([]*csiproxy.TargetPortal, 0, len(publishInfo.IscsiPortals)+1)
+	for _, portal := range append([]string{publishInfo.IscsiTargetPortal}, publishInfo.IscsiPortals...) {
+		targetPortal, err := parseTargetPortal(portal)
+		if err != nil {
+			return nil, err
+		}
+		portals = append(portals, targetPortal)
+	}
+	return portals, nil
+}
+
+func (p *Plugin) nodeStageWindowsVolume(
+	ctx context.Context, req *csi.NodeStageVolumeRequest,
+) (*csi.NodeStageVolumeResponse, error) {
+
+	switch req.PublishContext["protocol"] {
+	case string(tridentconfig.File):
+		if req.PublishContext["smbPath"] == "" {
+			return nil, status.Error(codes.InvalidArgument, "Windows nodes only support SMB file volumes")
+		}
+		return p.nodeStageSMBVolume(ctx, req)
+	case string(tridentconfig.Block):
+		return p.nodeStageWindowsISCSIVolume(ctx, req)
+	default:
+		return nil, status.Error(codes.InvalidArgument, "unknown protocol")
+	}
+}
+
+func (p *Plugin) nodeUnstageWindowsVolume(
+	ctx context.Context, req *csi.NodeUnstageVolumeRequest, publishInfo *utils.VolumePublishInfo,
+) (*csi.NodeUnstageVolumeResponse, error) {
+
+	if publishInfo.SMBPath != "" {
+		if err := p.csiProxy.RemoveSMBGlobalMapping(ctx, publishInfo.SMBPath); err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	} else {
+		p.detachWindowsISCSIDevice(ctx, publishInfo)
+	}
+
+	volumeId, stagingTargetPath, err := p.getVolumeIdAndStagingPath(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Delete the device info we saved to the staging path so unstage can succeed
+	if err := p.clearStagedDeviceInfo(stagingTargetPath, volumeId); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &csi.NodeUnstageVolumeResponse{}, nil
+}
+
+func (p *Plugin) nodePublishWindowsVolume(
+	ctx context.Context, req *csi.NodePublishVolumeRequest,
+) (*csi.NodePublishVolumeResponse, error) {
+
+	publishInfo, err := p.readStagedDeviceInfo(req.StagingTargetPath)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	targetPath := normalizeWindowsPath(req.GetTargetPath())
+
+	if publishInfo.SMBPath != "" {
+
+		// CSI Proxy links the target path to the share, so the directory the CO created must go first
+		exists, err := p.csiProxy.PathExists(ctx, targetPath)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		if exists {
+			if err = p.csiProxy.Rmdir(ctx, targetPath, false); err != nil {
+				return nil, status.Error(codes.Internal, err.Error())
+			}
+		}
+		if err = p.csiProxy.Mkdir(ctx, targetPath[:strings.LastIndex(targetPath, `\`)]); err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+
+		// The share was mapped with its credentials when it was staged, so the existing mapping is reused
+		if err = p.csiProxy.NewSMBGlobalMapping(ctx, publishInfo.SMBPath, targetPath, "", ""); err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		return &csi.NodePublishVolumeResponse{}, nil
+	}
+
+	if err = p.csiProxy.Mkdir(ctx, targetPath); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if err = p.csiProxy.MountVolume(ctx, publishInfo.DevicePath, targetPath); err != nil {
+		return nil, status.Errorf(codes.Internal, "unable to mount volume; %s", err)
+	}
+
+	return &csi.NodePublishVolumeResponse{}, nil
+}
+
+func (p *Plugin) nodeUnpublishWindowsVolume(
+	ctx context.Context, req *csi.NodeUnpublishVolumeRequest,
+) (*csi.NodeUnpublishVolumeResponse, error) {
+
+	targetPath := normalizeWindowsPath(req.GetTargetPath())
+
+	exists, err := p.csiProxy.PathExists(ctx, targetPath)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if !exists {
+		return nil, status.Error(codes.NotFound, "target path not found")
+	}
+
+	// An iSCSI volume is mounted at the target path, while an SMB share is only linked to it
+	if volumeID, err := p.csiProxy.GetVolumeIDFromTargetPath(ctx, targetPath); err == nil && volumeID != "" {
+		if err = p.csiProxy.UnmountVolume(ctx, volumeID, targetPath); err != nil {
+			log.WithFields(log.Fields{"path": targetPath, "error": err}).Error("unable to unmount volume.")
+			return nil, status.Errorf(codes.Internal, "unable to unmount volume; %s", err)
+		}
+	}
+
+	if err = p.csiProxy.Rmdir(ctx, targetPath, false); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &csi.NodeUnpublishVolumeResponse{}, nil
+}
+
+func (p *Plugin) nodeStageSMBVolume(
+	ctx context.Context, req *csi.NodeStageVolumeRequest,
+) (*csi.NodeStageVolumeResponse, error) {
+
+	publishInfo := &utils.VolumePublishInfo{
+		Localhost:      true,
+		FilesystemType: fsSMB,
+	}
+	publishInfo.SMBPath = req.PublishContext["smbPath"]
+
+	volumeId, stagingTargetPath, err := p.getVolumeIdAndStagingPath(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// SMB shares are secured by Active Directory, so the credentials come from the node stage secret
+	secrets := req.GetSecrets()
+	username, password := secrets["username"], secrets["password"]
+	if username == "" || password == "" {
+		return nil, status.Error(codes.InvalidArgument,
+			"SMB volumes require a node stage secret with a username and password")
+	}
+
+	if err = p.csiProxy.NewSMBGlobalMapping(ctx, publishInfo.SMBPath, "", username, password); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	// Save the device info to the staging path for use in the publish & unstage calls
+	if err := p.writeStagedDeviceInfo(stagingTargetPath, publishInfo, volumeId); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &csi.NodeStageVolumeResponse{}, nil
+}
+
+func (p *Plugin) nodeStageWindowsISCSIVolume(
+	ctx context.Context, req *csi.NodeStageVolumeRequest,
+) (*csi.NodeStageVolumeResponse, error) {
+
+	if req.GetVolumeCapability().GetBlock() != nil {
+		return nil, status.Error(codes.InvalidArgument, "Windows nodes do not support raw block volumes")
+	}
+
+	fstype := req.GetVolumeCapability().GetMount().GetFsType()
+	if fstype == "" {
+		fstype = req.PublishContext["filesystemType"]
+	}
+	if fstype != fsNTFS {
+		return nil, status.Errorf(codes.InvalidArgument, "Windows nodes only support %s, not %s", fsNTFS, fstype)
+	}
+
+	useCHAP, err := strconv.ParseBool(req.PublishContext["useCHAP"])
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	sharedTarget, err := strconv.ParseBool(req.PublishContext["sharedTarget"])
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	lunID, err := strconv.Atoi(req.PublishContext["iscsiLunNumber"])
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	publishInfo := &utils.VolumePublishInfo{
+		Localhost:      true,
+		FilesystemType: fstype,
+		UseCHAP:        useCHAP,
+		SharedTarget:   sharedTarget,
+	}
+
+	if err = unstashIscsiTargetPortals(publishInfo, req.PublishContext); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	publishInfo.IscsiTargetIQN = req.PublishContext["iscsiTargetIqn"]
+	publishInfo.IscsiLunNumber = int32(lunID)
+	publishInfo.IscsiIgroup = req.PublishContext["iscsiIgroup"]
+	publishInfo.IscsiUsername = req.PublishContext["iscsiUsername"]
+	publishInfo.IscsiInitiatorSecret = req.PublishContext["iscsiInitiatorSecret"]
+	publishInfo.IscsiTargetUsername = req.PublishContext["iscsiTargetUsername"]
+	publishInfo.IscsiTargetSecret = req.PublishContext["iscsiTargetSecret"]
+
+	// Log in, then partition and format the LUN's disk, and get its volume back in the publish info
+	if publishInfo.DevicePath, err = p.attachWindowsISCSIVolume(ctx, publishInfo); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	volumeId, stagingTargetPath, err := p.getVolumeIdAndStagingPath(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Save the device info to the staging path for use in the publish & unstage calls
+	if err := p.writeStagedDeviceInfo(stagingTargetPath, publishInfo, volumeId); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &csi.NodeStageVolumeResponse{}, nil
+}
+
+// attachWindowsISCSIVolume logs in to a LUN's target, prepares the LUN's disk and returns the ID of the
+// NTFS volume on it.
+func (p *Plugin) attachWindowsISCSIVolume(ctx context.Context, publishInfo *utils.VolumePublishInfo) (string, error) {
+
+	fields := log.Fields{
+		"targetIQN": publishInfo.IscsiTargetIQN,
+		"lunID":     publishInfo.IscsiLunNumber,
+	}
+	log.WithFields(fields).Debug(">>>> attachWindowsISCSIVolume")
+	defer log.WithFields(fields).Debug("<<<< attachWindowsISCSIVolume")
+
+	portals, err := targetPortals(publishInfo)
+	if err != nil {
+		return "", err
+	}
+
+	// A shared target may already be logged in for another LUN
+	if disks, err := p.csiProxy.GetTargetDisks(ctx, portals[0], publishInfo.IscsiTargetIQN); err != nil || len(disks) == 0 {
+		if publishInfo.UseCHAP && publishInfo.IscsiTargetSecret != "" {
+			if err = p.csiProxy.SetMutualCHAPSecret(ctx, publishInfo.IscsiTargetSecret); err != nil {
+				return "", err
+			}
+		}
+
+		for _, portal := range portals {
+			if err = p.csiProxy.AddTargetPortal(ctx, portal); err != nil {
+				return "", err
+			}
+
+			request := &csiproxy.ConnectTargetRequest{
+				TargetPortal: portal,
+				IQN:          publishInfo.IscsiTargetIQN,
+				AuthType:     csiproxy.AuthenticationNone,
+			}
+			if publishInfo.UseCHAP {
+				request.CHAPUsername = publishInfo.IscsiUsername
+				request.CHAPSecret = publishInfo.IscsiInitiatorSecret
+				request.AuthType = csiproxy.AuthenticationOneWayCHAP
+				if publishInfo.IscsiTargetSecret != "" {
+					request.AuthType = csiproxy.AuthenticationMutualCHAP
+				}
+			}
+			if err = p.csiProxy.ConnectTarget(ctx, request); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	diskNumber, err := p.waitForWindowsISCSIDisk(ctx, portals[0], publishInfo)
+	if err != nil {
+		return "", err
+	}
+
+	if err = p.csiProxy.SetDiskOnline(ctx, diskNumber); err != nil {
+		return "", err
+	}
+	if err = p.csiProxy.PartitionDisk(ctx, diskNumber); err != nil {
+		return "", err
+	}
+
+	volumeIDs, err := p.csiProxy.ListVolumesOnDisk(ctx, diskNumber)
+	if err != nil {
+		return "", err
+	}
+	if len(volumeIDs) == 0 {
+		return "", fmt.Errorf("no volume found on disk %d", diskNumber)
+	}
+	volumeID := volumeIDs[0]
+
+	formatted, err := p.csiProxy.IsVolumeFormatted(ctx, volumeID)
+	if err != nil {
+		return "", err
+	}
+	if !formatted {
+		log.WithField("volumeID", volumeID).Debug("Formatting LUN.")
+		if err = p.csiProxy.FormatVolume(ctx, volumeID); err != nil {
+			return "", err
+		}
+	}
+
+	return volumeID, nil
+}
+
+// waitForWindowsISCSIDisk returns the number of the host disk backed by a LUN, rescanning until it appears.
+func (p *Plugin) waitForWindowsISCSIDisk(
+	ctx context.Context, portal *csiproxy.TargetPortal, publishInfo *utils.VolumePublishInfo,
+) (uint32, error) {
+
+	lunID := strconv.Itoa(int(publishInfo.IscsiLunNumber))
+	var diskNumber uint32
+
+	findDisk := func() error {
+		disks, err := p.csiProxy.GetTargetDisks(ctx, portal, publishInfo.IscsiTargetIQN)
+		if err != nil {
+			return err
+		}
+		locations, err := p.csiProxy.ListDiskLocations(ctx)
+		if err != nil {
+			return err
+		}
+		for _, disk := range disks {
+			if location, ok := locations[disk]; ok && location.LUNID == lunID {
+				diskNumber = disk
+				return nil
+			}
+		}
+		if err = p.csiProxy.Rescan(ctx); err != nil {
+			log.WithField("error", err).Warning("Could not rescan disks.")
+		}
+		return fmt.Errorf("no disk found for LUN %s of target %s", lunID, publishInfo.IscsiTargetIQN)
+	}
+
+	findDiskNotify := func(err error, duration time.Duration) {
+		log.WithFields(log.Fields{
+			"increment": duration,
+			"error":     err,
+		}).Debug("Disk not yet present, waiting.")
+	}
+
+	findDiskBackoff := backoff.NewExponentialBackOff()
+	findDiskBackoff.InitialInterval = 1 * time.Second
+	findDiskBackoff.Multiplier = 1.414 // approx sqrt(2)
+	findDiskBackoff.RandomizationFactor = 0.1
+	findDiskBackoff.MaxElapsedTime = windowsDiskWaitTimeout
+
+	if err := backoff.RetryNotify(findDisk, findDiskBackoff, findDiskNotify); err != nil {
+		return 0, err
+	}
+	return diskNumber, nil
+}
+
+// detachWindowsISCSIDevice logs out of a LUN's target, unless other LUNs may share it.
+func (p *Plugin) detachWindowsISCSIDevice(ctx context.Context, publishInfo *utils.VolumePublishInfo) {
+
+	if publishInfo.SharedTarget {
+		return
+	}
+
+	portals, err := targetPortals(publishInfo)
+	if err != nil {
+		log.WithField("error", err).Warning("Could not parse iSCSI portals.")
+		return
+	}
+	for _, portal := range portals {
+		if err = p.csiProxy.DisconnectTarget(ctx, portal, publishInfo.IscsiTargetIQN); err != nil {
+			log.WithFields(log.Fields{
+				"portal": portal.TargetAddress,
+				"error":  err,
+			}).Warning("Could not log out of iSCSI target.")
+		}
+	}
+}
+
+func (p *Plugin) nodeExpandWindowsVolume(
+	ctx context.Context, publishInfo *utils.VolumePublishInfo,
+) (*csi.NodeExpandVolumeResponse, error) {
+
+	if publishInfo.SMBPath != "" {
+		return &csi.NodeExpandVolumeResponse{}, nil
+	}
+
+	// Let the host see the larger LUN, then grow the partition and NTFS volume to fill it
+	if err := p.csiProxy.Rescan(ctx); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if err := p.csiProxy.ResizeVolume(ctx, publishInfo.DevicePath); err != nil {
+		log.WithFields(log.Fields{
+			"volumeID": publishInfo.DevicePath,
+			"error":    err,
+		}).Error("Unable to expand volume.")
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &csi.NodeExpandVolumeResponse{}, nil
+}
+
+func (p *Plugin) nodeGetWindowsVolumeStats(
+	ctx context.Context, req *csi.NodeGetVolumeStatsRequest,
+) (*csi.NodeGetVolumeStatsResponse, error) {
+
+	volumePath := normalizeWindowsPath(req.GetVolumePath())
+
+	exists, err := p.csiProxy.PathExists(ctx, volumePath)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if !exists {
+		return nil, status.Errorf(codes.NotFound, "could not find volume mount at path: %s", volumePath)
+	}
+
+	// SMB shares have no local volume to report on
+	volumeID, err := p.csiProxy.GetVolumeIDFromTargetPath(ctx, volumePath)
+	if err != nil || volumeID == "" {
+		return &csi.NodeGetVolumeStatsResponse{}, nil
+	}
+
+	total, used, err := p.csiProxy.GetVolumeStats(ctx, volumeID)
+	if err != nil {
+		go p.reportVolumeCondition(req.GetVolumeId(),
+			fmt.Sprintf("could not read volume at path %s: %v", volumePath, err))
+		return nil, status.Error(codes.Unknown, "Failed to get volume stats")
+	}
+
+	go p.reportVolumeUsage(&storage.VolumeUsage{
+		VolumeName:     req.GetVolumeId(),
+		TotalBytes:     uint64(total),
+		UsedBytes:      uint64(used),
+		AvailableBytes: uint64(total - used),
+	})
+	return &csi.NodeGetVolumeStatsResponse{
+		Usage: []*csi.VolumeUsage{
+			{
+				Unit:      csi.VolumeUsage_BYTES,
+				Available: total - used,
+				Total:     total,
+				Used:      used,
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,527 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package csi
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	tridentconfig "github.com/netapp/trident/config"
+	"github.com/netapp/trident/utils/csiproxy"
+)
+
+// fakeCSIProxy keeps just enough host state to follow the node plugin through staging and publishing.
+// A target's LUNs show up as disks once it is connected, and each disk holds a single volume.
+type fakeCSIProxy struct {
+	paths       map[string]bool
+	smbMappings map[string]string
+	portals     map[string]bool
+	connected   map[string]*csiproxy.ConnectTargetRequest
+	chapSecret  string
+	targetLUNs  map[string][]string
+	disks       map[uint32]*csiproxy.DiskLocation
+	diskTargets map[uint32]string
+	online      map[uint32]bool
+	partitioned map[uint32]bool
+	formatted   map[string]bool
+	mounts      map[string]string
+	calls       []string
+}
+
+func newFakeCSIProxy() *fakeCSIProxy {
+	return &fakeCSIProxy{
+		paths:       make(map[string]bool),
+		smbMappings: make(map[string]string),
+		portals:     make(map[string]bool),
+		connected:   make(map[string]*csiproxy.ConnectTargetRequest),
+		targetLUNs:  make(map[string][]string),
+		disks:       make(map[uint32]*csiproxy.DiskLocation),
+		diskTargets: make(map[uint32]string),
+		online:      make(map[uint32]bool),
+		partitioned: make(map[uint32]bool),
+		formatted:   make(map[string]bool),
+		mounts:      make(map[string]string),
+	}
+}
+
+func (f *fakeCSIProxy) record(call string) {
+	f.calls = append(f.calls, call)
+}
+
+func diskVolumeID(diskNumber uint32) string {
+	return fmt.Sprintf(`\\?\Volume{%d}\`, diskNumber)
+}
+
+func (f *fakeCSIProxy) Close() {}
+
+func (f *fakeCSIProxy) PathExists(_ context.Context, path string) (bool, error) {
+	return f.paths[path], nil
+}
+
+func (f *fakeCSIProxy) Mkdir(_ context.Context, path string) error {
+	f.record("Mkdir")
+	f.paths[path] = true
+	return nil
+}
+
+func (f *fakeCSIProxy) Rmdir(_ context.Context, path string, _ bool) error {
+	f.record("Rmdir")
+	delete(f.paths, path)
+	return nil
+}
+
+func (f *fakeCSIProxy) NewSMBGlobalMapping(_ context.Context, remotePath, localPath, username, password string) error {
+	f.record("NewSMBGlobalMapping")
+	if localPath == "" {
+		if username == "" || password == "" {
+			return fmt.Errorf("access denied to %s", remotePath)
+		}
+		f.smbMappings[remotePath] = username
+		return nil
+	}
+	if _, ok := f.smbMappings[remotePath]; !ok {
+		return fmt.Errorf("no global mapping for %s", remotePath)
+	}
+	f.paths[localPath] = true
+	return nil
+}
+
+func (f *fakeCSIProxy) RemoveSMBGlobalMapping(_ context.Context, remotePath string) error {
+	f.record("RemoveSMBGlobalMapping")
+	delete(f.smbMappings, remotePath)
+	return nil
+}
+
+func (f *fakeCSIProxy) AddTargetPortal(_ context.Context, portal *csiproxy.TargetPortal) error {
+	f.record("AddTargetPortal")
+	f.portals[fmt.Sprintf("%s:%d", portal.TargetAddress, portal.TargetPort)] = true
+	return nil
+}
+
+func (f *fakeCSIProxy) ConnectTarget(_ context.Context, request *csiproxy.ConnectTargetRequest) error {
+	f.record("ConnectTarget")
+	portal := fmt.Sprintf("%s:%d", request.TargetPortal.TargetAddress, request.TargetPortal.TargetPort)
+	if !f.portals[portal] {
+		return fmt.Errorf("portal %s not added", portal)
+	}
+	if _, ok := f.connected[request.IQN]; ok {
+		return nil
+	}
+	f.connected[request.IQN] = request
+
+	// Each of the target's LUNs appears as a disk on the first login
+	for _, lunID := range f.targetLUNs[request.IQN] {
+		diskNumber := uint32(len(f.disks) + 1)
+		f.disks[diskNumber] = &csiproxy.DiskLocation{LUNID: lunID}
+		f.diskTargets[diskNumber] = request.IQN
+	}
+	return nil
+}
+
+func (f *fakeCSIProxy) DisconnectTarget(_ context.Context, _ *csiproxy.TargetPortal, iqn string) error {
+	f.record("DisconnectTarget")
+	delete(f.connected, iqn)
+	return nil
+}
+
+func (f *fakeCSIProxy) GetTargetDisks(_ context.Context, _ *csiproxy.TargetPortal, iqn string) ([]uint32, error) {
+	if _, ok := f.connected[iqn]; !ok {
+		return nil, fmt.Errorf("target %s not connected", iqn)
+	}
+	disks := make([]uint32, 0)
+	for diskNumber, target := range f.diskTargets {
+		if target == iqn {
+			disks = append(disks, diskNumber)
+		}
+	}
+	return disks, nil
+}
+
+func (f *fakeCSIProxy) SetMutualCHAPSecret(_ context.Context, secret string) error {
+	f.record("SetMutualCHAPSecret")
+	f.chapSecret = secret
+	return nil
+}
+
+func (f *fakeCSIProxy) ListDiskLocations(_ context.Context) (map[uint32]*csiproxy.DiskLocation, error) {
+	return f.disks, nil
+}
+
+func (f *fakeCSIProxy) Rescan(_ context.Context) error {
+	f.record("Rescan")
+	return nil
+}
+
+func (f *fakeCSIProxy) PartitionDisk(_ context.Context, diskNumber uint32) error {
+	f.record("PartitionDisk")
+	if !f.online[diskNumber] {
+		return fmt.Errorf("disk %d is offline", diskNumber)
+	}
+	f.partitioned[diskNumber] = true
+	return nil
+}
+
+func (f *fakeCSIProxy) SetDiskOnline(_ context.Context, diskNumber uint32) error {
+	f.record("SetDiskOnline")
+	f.online[diskNumber] = true
+	return nil
+}
+
+func (f *fakeCSIProxy) ListVolumesOnDisk(_ context.Context, diskNumber uint32) ([]string, error) {
+	if !f.partitioned[diskNumber] {
+		return []string{}, nil
+	}
+	return []string{diskVolumeID(diskNumber)}, nil
+}
+
+func (f *fakeCSIProxy) IsVolumeFormatted(_ context.Context, volumeID string) (bool, error) {
+	return f.formatted[volumeID], nil
+}
+
+func (f *fakeCSIProxy) FormatVolume(_ context.Context, volumeID string) error {
+	f.record("FormatVolume")
+	f.formatted[volumeID] = true
+	return nil
+}
+
+func (f *fakeCSIProxy) MountVolume(_ context.Context, volumeID, targetPath string) error {
+	f.record("MountVolume")
+	if !f.formatted[volumeID] {
+		return fmt.Errorf("volume %s is not formatted", volumeID)
+	}
+	f.mounts[targetPath] = volumeID
+	return nil
+}
+
+func (f *fakeCSIProxy) UnmountVolume(_ context.Context, volumeID, targetPath string) error {
+	f.record("UnmountVolume")
+	if f.mounts[targetPath] != volumeID {
+		return fmt.Errorf("volume %s is not mounted at %s", volumeID, targetPath)
+	}
+	delete(f.mounts, targetPath)
+	return nil
+}
+
+func (f *fakeCSIProxy) ResizeVolume(_ context.Context, _ string) error {
+	f.record("ResizeVolume")
+	return nil
+}
+
+func (f *fakeCSIProxy) GetVolumeStats(_ context.Context, _ string) (int64, int64, error) {
+	return 1073741824, 1048576, nil
+}
+
+func (f *fakeCSIProxy) GetVolumeIDFromTargetPath(_ context.Context, targetPath string) (string, error) {
+	return f.mounts[targetPath], nil
+}
+
+// newWindowsTestPlugin returns a node plugin backed by a fake CSI Proxy, with its tracking files and
+// staging directory in a temporary directory.
+func newWindowsTestPlugin(t *testing.T) (*Plugin, *fakeCSIProxy, string) {
+
+	tempDir, err := ioutil.TempDir("", "windows_node_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+
+	savedDeviceInfoPath := tridentDeviceInfoPath
+	tridentDeviceInfoPath = path.Join(tempDir, "tracking")
+	t.Cleanup(func() { tridentDeviceInfoPath = savedDeviceInfoPath })
+
+	stagingPath := path.Join(tempDir, "staging")
+	for _, dir := range []string{tridentDeviceInfoPath, stagingPath} {
+		if err = os.Mkdir(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	proxy := newFakeCSIProxy()
+	return &Plugin{csiProxy: proxy}, proxy, stagingPath
+}
+
+const (
+	windowsTestVolumeID   = "pvc-1"
+	windowsTestTargetPath = "/var/lib/kubelet/pods/pod1/volumes/kubernetes.io~csi/pvc-1/mount"
+	windowsTestHostPath   = `c:\var\lib\kubelet\pods\pod1\volumes\kubernetes.io~csi\pvc-1\mount`
+	windowsTestTargetIQN  = "iqn.1992-08.com.netapp:sn.1234:vs.2"
+	windowsTestSMBPath    = `\\10.0.0.1\pvc-1`
+)
+
+func smbStageRequest(stagingPath string, secrets map[string]string) *csi.NodeStageVolumeRequest {
+	return &csi.NodeStageVolumeRequest{
+		VolumeId:          windowsTestVolumeID,
+		StagingTargetPath: stagingPath,
+		PublishContext: map[string]string{
+			"protocol": string(tridentconfig.File),
+			"smbPath":  windowsTestSMBPath,
+		},
+		VolumeCapability: &csi.VolumeCapability{
+			AccessType: &csi.VolumeCapability_Mount{Mount: &csi.VolumeCapability_MountVolume{}},
+		},
+		Secrets: secrets,
+	}
+}
+
+func iscsiStageRequest(stagingPath, fstype string, useCHAP bool) *csi.NodeStageVolumeRequest {
+	return &csi.NodeStageVolumeRequest{
+		VolumeId:          windowsTestVolumeID,
+		StagingTargetPath: stagingPath,
+		PublishContext: map[string]string{
+			"protocol":               string(tridentconfig.Block),
+			"filesystemType":         fstype,
+			"useCHAP":                fmt.Sprintf("%t", useCHAP),
+			"sharedTarget":           "false",
+			"iscsiLunNumber":         "3",
+			"iscsiTargetPortalCount": "2",
+			"p1":                     "10.0.0.1",
+			"p2":                     "10.0.0.2:3261",
+			"iscsiTargetIqn":         windowsTestTargetIQN,
+			"iscsiUsername":          "initiator",
+			"iscsiInitiatorSecret":   "initiatorSecret",
+			"iscsiTargetUsername":    "target",
+			"iscsiTargetSecret":      "targetSecret",
+		},
+		VolumeCapability: &csi.VolumeCapability{
+			AccessType: &csi.VolumeCapability_Mount{Mount: &csi.VolumeCapability_MountVolume{}},
+		},
+	}
+}
+
+func assertStatusCode(t *testing.T, expected codes.Code, err error) {
+	t.Helper()
+	if assert.Error(t, err) {
+		assert.Equal(t, expected, status.Code(err), "unexpected error: %v", err)
+	}
+}
+
+func TestNormalizeWindowsPath(t *testing.T) {
+	assert.Equal(t, windowsTestHostPath, normalizeWindowsPath(windowsTestTargetPath))
+	assert.Equal(t, `c:\var\lib`, normalizeWindowsPath(`c:\var\lib`))
+	assert.Equal(t, windowsTestSMBPath, normalizeWindowsPath(windowsTestSMBPath))
+}
+
+func TestParseTargetPortal(t *testing.T) {
+
+	tests := []struct {
+		portal  string
+		address string
+		port    uint32
+	}{
+		{"10.0.0.1", "10.0.0.1", defaultISCSIPort},
+		{"10.0.0.1:3261", "10.0.0.1", 3261},
+		{"[fd00::1]", "fd00::1", defaultISCSIPort},
+		{"[fd00::1]:3261", "fd00::1", 3261},
+	}
+	for _, test := range tests {
+		portal, err := parseTargetPortal(test.portal)
+		if assert.NoError(t, err, test.portal) {
+			assert.Equal(t, test.address, portal.TargetAddress, test.portal)
+			assert.Equal(t, test.port, portal.TargetPort, test.portal)
+		}
+	}
+
+	_, err := parseTargetPortal("10.0.0.1:port")
+	assert.Error(t, err)
+}
+
+func TestWindowsSMBVolumeLifecycle(t *testing.T) {
+
+	ctx := context.Background()
+	plugin, proxy, stagingPath := newWindowsTestPlugin(t)
+	secrets := map[string]string{"username": "EXAMPLE\\trident", "password": "secret"}
+
+	_, err := plugin.NodeStageVolume(ctx, smbStageRequest(stagingPath, secrets))
+	assert.NoError(t, err)
+	assert.Equal(t, "EXAMPLE\\trident", proxy.smbMappings[windowsTestSMBPath], "share should be mapped")
+
+	publishInfo, err := plugin.readStagedDeviceInfo(stagingPath)
+	if assert.NoError(t, err) {
+		assert.Equal(t, windowsTestSMBPath, publishInfo.SMBPath)
+		assert.Equal(t, fsSMB, publishInfo.FilesystemType)
+	}
+	trackedStagingPath, err := plugin.readStagedTrackingFile(windowsTestVolumeID)
+	assert.NoError(t, err)
+	assert.Equal(t, stagingPath, trackedStagingPath)
+
+	// The CO creates the target directory, which must be replaced by the link to the share
+	proxy.paths[windowsTestHostPath] = true
+	proxy.calls = nil
+	_, err = plugin.NodePublishVolume(ctx, &csi.NodePublishVolumeRequest{
+		VolumeId:          windowsTestVolumeID,
+		StagingTargetPath: stagingPath,
+		TargetPath:        windowsTestTargetPath,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Rmdir", "Mkdir", "NewSMBGlobalMapping"}, proxy.calls)
+	assert.True(t, proxy.paths[`c:\var\lib\kubelet\pods\pod1\volumes\kubernetes.io~csi\pvc-1`])
+	assert.True(t, proxy.paths[windowsTestHostPath])
+
+	proxy.calls = nil
+	_, err = plugin.NodeUnpublishVolume(ctx, &csi.NodeUnpublishVolumeRequest{
+		VolumeId:   windowsTestVolumeID,
+		TargetPath: windowsTestTargetPath,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Rmdir"}, proxy.calls, "a share should only be unlinked")
+	assert.False(t, proxy.paths[windowsTestHostPath])
+
+	_, err = plugin.NodeUnpublishVolume(ctx, &csi.NodeUnpublishVolumeRequest{
+		VolumeId:   windowsTestVolumeID,
+		TargetPath: windowsTestTargetPath,
+	})
+	assertStatusCode(t, codes.NotFound, err)
+
+	_, err = plugin.NodeUnstageVolume(ctx, &csi.NodeUnstageVolumeRequest{
+		VolumeId:          windowsTestVolumeID,
+		StagingTargetPath: stagingPath,
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, proxy.smbMappings, "share should be unmapped")
+	_, err = os.Stat(path.Join(stagingPath, volumePublishInfoFilename))
+	assert.True(t, os.IsNotExist(err), "publish info should be removed")
+	_, err = plugin.readStagedTrackingFile(windowsTestVolumeID)
+	assert.Error(t, err, "tracking file should be removed")
+}
+
+func TestWindowsSMBVolumeStageRequiresSecrets(t *testing.T) {
+
+	ctx := context.Background()
+	plugin, proxy, stagingPath := newWindowsTestPlugin(t)
+
+	for _, secrets := range []map[string]string{nil, {"username": "trident"}, {"password": "secret"}} {
+		_, err := plugin.NodeStageVolume(ctx, smbStageRequest(stagingPath, secrets))
+		assertStatusCode(t, codes.InvalidArgument, err)
+	}
+	assert.Empty(t, proxy.calls, "nothing should be mapped")
+
+	// Windows nodes can't mount NFS
+	request := smbStageRequest(stagingPath, nil)
+	delete(request.PublishContext, "smbPath")
+	_, err := plugin.NodeStageVolume(ctx, request)
+	assertStatusCode(t, codes.InvalidArgument, err)
+}
+
+func TestWindowsISCSIVolumeLifecycle(t *testing.T) {
+
+	ctx := context.Background()
+	plugin, proxy, stagingPath := newWindowsTestPlugin(t)
+	proxy.targetLUNs[windowsTestTargetIQN] = []string{"1", "3"}
+
+	_, err := plugin.NodeStageVolume(ctx, iscsiStageRequest(stagingPath, fsNTFS, true))
+	assert.NoError(t, err)
+
+	// Both portals are logged in to with mutual CHAP
+	assert.True(t, proxy.portals["10.0.0.1:3260"])
+	assert.True(t, proxy.portals["10.0.0.2:3261"])
+	assert.Equal(t, "targetSecret", proxy.chapSecret)
+	if request, ok := proxy.connected[windowsTestTargetIQN]; assert.True(t, ok, "target should be connected") {
+		assert.Equal(t, csiproxy.AuthenticationMutualCHAP, request.AuthType)
+		assert.Equal(t, "initiator", request.CHAPUsername)
+		assert.Equal(t, "initiatorSecret", request.CHAPSecret)
+	}
+
+	// The disk for LUN 3 is prepared, and its volume is saved in the publish info
+	volumeID := diskVolumeID(2)
+	assert.True(t, proxy.online[2])
+	assert.True(t, proxy.partitioned[2])
+	assert.True(t, proxy.formatted[volumeID])
+	assert.False(t, proxy.partitioned[1], "other LUNs should not be touched")
+
+	publishInfo, err := plugin.readStagedDeviceInfo(stagingPath)
+	if assert.NoError(t, err) {
+		assert.Equal(t, volumeID, publishInfo.DevicePath)
+		assert.Equal(t, fsNTFS, publishInfo.FilesystemType)
+		assert.Equal(t, int32(3), publishInfo.IscsiLunNumber)
+		assert.Equal(t, []string{"10.0.0.2:3261"}, publishInfo.IscsiPortals)
+	}
+
+	// Staging again reuses the session and doesn't reformat the volume
+	proxy.calls = nil
+	_, err = plugin.NodeStageVolume(ctx, iscsiStageRequest(stagingPath, fsNTFS, true))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"SetDiskOnline", "PartitionDisk"}, proxy.calls)
+
+	_, err = plugin.NodePublishVolume(ctx, &csi.NodePublishVolumeRequest{
+		VolumeId:          windowsTestVolumeID,
+		StagingTargetPath: stagingPath,
+		TargetPath:        windowsTestTargetPath,
+	})
+	assert.NoError(t, err)
+	assert.True(t, proxy.paths[windowsTestHostPath])
+	assert.Equal(t, volumeID, proxy.mounts[windowsTestHostPath])
+
+	stats, err := plugin.nodeGetWindowsVolumeStats(ctx, &csi.NodeGetVolumeStatsRequest{
+		VolumeId:   windowsTestVolumeID,
+		VolumePath: windowsTestTargetPath,
+	})
+	if assert.NoError(t, err) && assert.Len(t, stats.Usage, 1) {
+		assert.Equal(t, int64(1073741824), stats.Usage[0].Total)
+		assert.Equal(t, int64(1048576), stats.Usage[0].Used)
+	}
+
+	_, err = plugin.NodeUnpublishVolume(ctx, &csi.NodeUnpublishVolumeRequest{
+		VolumeId:   windowsTestVolumeID,
+		TargetPath: windowsTestTargetPath,
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, proxy.mounts, "volume should be unmounted")
+	assert.False(t, proxy.paths[windowsTestHostPath])
+
+	_, err = plugin.NodeUnstageVolume(ctx, &csi.NodeUnstageVolumeRequest{
+		VolumeId:          windowsTestVolumeID,
+		StagingTargetPath: stagingPath,
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, proxy.connected, "target should be logged out")
+	_, err = os.Stat(path.Join(stagingPath, volumePublishInfoFilename))
+	assert.True(t, os.IsNotExist(err), "publish info should be removed")
+}
+
+func TestWindowsISCSIVolumeStageWithoutCHAP(t *testing.T) {
+
+	ctx := context.Background()
+	plugin, proxy, stagingPath := newWindowsTestPlugin(t)
+	proxy.targetLUNs[windowsTestTargetIQN] = []string{"3"}
+
+	_, err := plugin.NodeStageVolume(ctx, iscsiStageRequest(stagingPath, fsNTFS, false))
+	assert.NoError(t, err)
+	assert.NotContains(t, proxy.calls, "SetMutualCHAPSecret")
+	if request, ok := proxy.connected[windowsTestTargetIQN]; assert.True(t, ok, "target should be connected") {
+		assert.Equal(t, csiproxy.AuthenticationNone, request.AuthType)
+		assert.Empty(t, request.CHAPSecret)
+	}
+}
+
+func TestWindowsISCSIVolumeStageInvalidArguments(t *testing.T) {
+
+	ctx := context.Background()
+	plugin, proxy, stagingPath := newWindowsTestPlugin(t)
+
+	// Only NTFS is supported
+	_, err := plugin.NodeStageVolume(ctx, iscsiStageRequest(stagingPath, "ext4", true))
+	assertStatusCode(t, codes.InvalidArgument, err)
+
+	// The volume capability's filesystem takes precedence over the publish context's
+	request := iscsiStageRequest(stagingPath, fsNTFS, true)
+	request.VolumeCapability.GetMount().FsType = "xfs"
+	_, err = plugin.NodeStageVolume(ctx, request)
+	assertStatusCode(t, codes.InvalidArgument, err)
+
+	// Raw block volumes aren't supported
+	request = iscsiStageRequest(stagingPath, fsNTFS, true)
+	request.VolumeCapability.AccessType = &csi.VolumeCapability_Block{Block: &csi.VolumeCapability_BlockVolume{}}
+	_, err = plugin.NodeStageVolume(ctx, request)
+	assertStatusCode(t, codes.InvalidArgument, err)
+
+	assert.Empty(t, proxy.calls, "nothing should be logged in to")
+}
@@ -3,6 +3,7 @@
 package docker
 
 import (
+	"context"
 	"crypto/tls"
 	"encoding/json"
 	"fmt"
@@ -20,6 +21,7 @@ import (
 	"github.com/netapp/trident/config"
 	"github.com/netapp/trident/core"
 	frontendcommon "github.com/netapp/trident/frontend/common"
+	"github.com/netapp/trident/logging"
 	"github.com/netapp/trident/storage"
 	"github.com/netapp/trident/utils"
 )
@@ -196,11 +198,14 @@ func (p *Plugin) Create(request *volume.CreateRequest) error {
 	}
 
 	// Invoke the orchestrator to create or clone the new volume
+	operation := "CreateVolume"
 	if volConfig.CloneSourceVolume != "" {
-		_, err = p.orchestrator.CloneVolume(volConfig)
+		operation = "CloneVolume"
+		_, err = p.orchestrator.CloneVolume(context.Background(), volConfig)
 	} else {
-		_, err = p.orchestrator.AddVolume(volConfig)
+		_, err = p.orchestrator.AddVolume(context.Background(), volConfig)
 	}
+	auditDockerOperation(operation, request.Name, err)
 	return p.dockerError(err)
 }
 
@@ -285,7 +290,8 @@ func (p *Plugin) Remove(request *volume.RemoveRequest) error {
 		"name":   request.Name,
 	}).Debug("Docker frontend method is invoked.")
 
-	err := p.orchestrator.DeleteVolume(request.Name)
+	err := p.orchestrator.DeleteVolume(context.Background(), request.Name)
+	auditDockerOperation("DeleteVolume", request.Name, err)
 	if err != nil {
 		log.WithFields(log.Fields{
 			"volume": request.Name,
@@ -332,7 +338,7 @@ func (p *Plugin) Mount(request *volume.MountRequest) (*volume.MountResponse, err
 
 	// First call PublishVolume to make the volume available to the node
 	publishInfo := &utils.VolumePublishInfo{Localhost: true}
-	if err = p.orchestrator.PublishVolume(request.Name, publishInfo); err != nil {
+	if err = p.orchestrator.PublishVolume(context.Background(), request.Name, publishInfo); err != nil {
 		err = fmt.Errorf("error publishing volume %s: %v", request.Name, err)
 		log.Error(err)
 		return &volume.MountResponse{}, p.dockerError(err)
@@ -455,3 +461,14 @@ func (p *Plugin) reloadVolumes() error {
 
 	return backoff.RetryNotify(reloadVolumesFunc, reloadBackoff, reloadNotify)
 }
+
+// auditDockerOperation records an audit event for a volume change requested by Docker.
+func auditDockerOperation(operation, volumeName string, err error) {
+	logging.Audit(&logging.AuditEvent{
+		Source:    logging.AuditSourceDocker,
+		Operation: operation,
+		Resource:  logging.AuditResourceVolume,
+		Name:      volumeName,
+		Error:     logging.AuditError(err),
+	})
+}
@@ -0,0 +1,152 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package grpc
+
+import (
+	"context"
+
+	grpclib "google.golang.org/grpc"
+)
+
+// TridentAdminClient is a client of Trident's gRPC admin service, for controllers and other
+// programs written in Go.
+type TridentAdminClient struct {
+	conn *grpclib.ClientConn
+}
+
+func NewTridentAdminClient(conn *grpclib.ClientConn) *TridentAdminClient {
+	return &TridentAdminClient{conn: conn}
+}
+
+func (c *TridentAdminClient) invoke(ctx context.Context, method string, request, response interface{}) error {
+	return c.conn.Invoke(ctx, "/"+ServiceName+"/"+method, request, response,
+		grpclib.CallContentSubtype(codecName))
+}
+
+func (c *TridentAdminClient) GetVersion(ctx context.Context) (*VersionResponse, error) {
+	response := &VersionResponse{}
+	return response, c.invoke(ctx, "GetVersion", &Empty{}, response)
+}
+
+func (c *TridentAdminClient) ListBackends(ctx context.Context) (*ListBackendsResponse, error) {
+	response := &ListBackendsResponse{}
+	return response, c.invoke(ctx, "ListBackends", &Empty{}, response)
+}
+
+func (c *TridentAdminClient) GetBackend(ctx context.Context, request *NameRequest) (*BackendResponse, error) {
+	response := &BackendResponse{}
+	return response, c.invoke(ctx, "GetBackend", request, response)
+}
+
+func (c *TridentAdminClient) AddBackend(ctx context.Context, request *AddBackendRequest) (*BackendResponse, error) {
+	response := &BackendResponse{}
+	return response, c.invoke(ctx, "AddBackend", request, response)
+}
+
+func (c *TridentAdminClient) UpdateBackend(
+	ctx context.Context, request *UpdateBackendRequest,
+) (*BackendResponse, error) {
+	response := &BackendResponse{}
+	return response, c.invoke(ctx, "UpdateBackend", request, response)
+}
+
+func (c *TridentAdminClient) DeleteBackend(ctx context.Context, request *NameRequest) error {
+	return c.invoke(ctx, "DeleteBackend", request, &Empty{})
+}
+
+func (c *TridentAdminClient) ListVolumes(ctx context.Context) (*ListVolumesResponse, error) {
+	response := &ListVolumesResponse{}
+	return response, c.invoke(ctx, "ListVolumes", &Empty{}, response)
+}
+
+func (c *TridentAdminClient) GetVolume(ctx context.Context, request *NameRequest) (*VolumeResponse, error) {
+	response := &VolumeResponse{}
+	return response, c.invoke(ctx, "GetVolume", request, response)
+}
+
+func (c *TridentAdminClient) AddVolume(ctx context.Context, request *VolumeRequest) (*VolumeResponse, error) {
+	response := &VolumeResponse{}
+	return response, c.invoke(ctx, "AddVolume", request, response)
+}
+
+func (c *TridentAdminClient) CloneVolume(ctx context.Context, request *VolumeRequest) (*VolumeResponse, error) {
+	response := &VolumeResponse{}
+	return response, c.invoke(ctx, "CloneVolume", request, response)
+}
+
+func (c *TridentAdminClient) ResizeVolume(ctx context.Context, request *ResizeVolumeRequest) error {
+	return c.invoke(ctx, "ResizeVolume", request, &Empty{})
+}
+
+func (c *TridentAdminClient) DeleteVolume(ctx context.Context, request *NameRequest) error {
+	return c.invoke(ctx, "DeleteVolume", request, &Empty{})
+}
+
+func (c *TridentAdminClient) ListSnapshots(
+	ctx context.Context, request *ListSnapshotsRequest,
+) (*ListSnapshotsResponse, error) {
+	response := &ListSnapshotsResponse{}
+	return response, c.invoke(ctx, "ListSnapshots", request, response)
+}
+
+func (c *TridentAdminClient) GetSnapshot(ctx context.Context, request *SnapshotRequest) (*SnapshotResponse, error) {
+	response := &SnapshotResponse{}
+	return response, c.invoke(ctx, "GetSnapshot", request, response)
+}
+
+func (c *TridentAdminClient) CreateSnapshot(
+	ctx context.Context, request *CreateSnapshotRequest,
+) (*SnapshotResponse, error) {
+	response := &SnapshotResponse{}
+	return response, c.invoke(ctx, "CreateSnapshot", request, response)
+}
+
+func (c *TridentAdminClient) DeleteSnapshot(ctx context.Context, request *SnapshotRequest) error {
+	return c.invoke(ctx, "DeleteSnapshot", request, &Empty{})
+}
+
+func (c *TridentAdminClient) ListNodes(ctx context.Context) (*ListNodesResponse, error) {
+	response := &ListNodesResponse{}
+	return response, c.invoke(ctx, "ListNodes", &Empty{}, response)
+}
+
+func (c *TridentAdminClient) GetNode(ctx context.Context, request *NameRequest) (*NodeResponse, error) {
+	response := &NodeResponse{}
+	return response, c.invoke(ctx, "GetNode", request, response)
+}
+
+func (c *TridentAdminClient) DeleteNode(ctx context.Context, request *NameRequest) error {
+	return c.invoke(ctx, "DeleteNode", request, &Empty{})
+}
+
+// TridentAdminWatchClient receives the events of a watch stream.
+type TridentAdminWatchClient struct {
+	stream grpclib.ClientStream
+}
+
+// Recv waits for the next event of the watch.
+func (w *TridentAdminWatchClient) Recv() (*WatchEvent, error) {
+	event := &WatchEvent{}
+	if err := w.stream.RecvMsg(event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// Watch starts streaming changes to Trident's objects.  The watch ends when the context is
+// cancelled.
+func (c *TridentAdminClient) Watch(ctx context.Context, request *WatchRequest) (*TridentAdminWatchClient, error) {
+
+	stream, err := c.conn.NewStream(ctx, &tridentAdminServiceDesc.Streams[0], "/"+ServiceName+"/Watch",
+		grpclib.CallContentSubtype(codecName))
+	if err != nil {
+		return nil, err
+	}
+	if err = stream.SendMsg(request); err != nil {
+		return nil, err
+	}
+	if err = stream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return &TridentAdminWatchClient{stream: stream}, nil
+}
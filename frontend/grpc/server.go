@@ -0,0 +1,352 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/core"
+	"github.com/netapp/trident/logging"
+	"github.com/netapp/trident/storage"
+	"github.com/netapp/trident/utils"
+)
+
+// APIServerGRPC is the frontend that serves Trident's gRPC admin API.
+type APIServerGRPC struct {
+	server  *grpclib.Server
+	address string
+	admin   *adminServer
+}
+
+func NewGRPCServer(p core.Orchestrator, address, port string) *APIServerGRPC {
+
+	admin := &adminServer{orchestrator: p, stopChan: make(chan struct{})}
+
+	apiServer := &APIServerGRPC{
+		server:  grpclib.NewServer(grpclib.UnaryInterceptor(logGRPC)),
+		address: fmt.Sprintf("%s:%s", address, port),
+		admin:   admin,
+	}
+	RegisterTridentAdminServer(apiServer.server, admin)
+
+	log.WithField("address", apiServer.address).Info("Initializing gRPC frontend.")
+
+	return apiServer
+}
+
+func (s *APIServerGRPC) Activate() error {
+
+	listener, err := net.Listen("tcp", s.address)
+	if err != nil {
+		return fmt.Errorf("could not listen on %s; %v", s.address, err)
+	}
+
+	go func() {
+		log.WithField("address", s.address).Info("Activating gRPC frontend.")
+		if err := s.server.Serve(listener); err != nil {
+			log.Fatal(err)
+		}
+	}()
+	return nil
+}
+
+func (s *APIServerGRPC) Deactivate() error {
+
+	log.WithField("address", s.address).Info("Deactivating gRPC frontend.")
+
+	// End the watches, which would otherwise keep the server from stopping gracefully
+	close(s.admin.stopChan)
+
+	stopped := make(chan struct{})
+	go func() {
+		s.server.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(config.HTTPTimeout):
+		s.server.Stop()
+	}
+	return nil
+}
+
+func (s *APIServerGRPC) GetName() string {
+	return "gRPC"
+}
+
+func (s *APIServerGRPC) Version() string {
+	return config.OrchestratorAPIVersion
+}
+
+// logGRPC logs each call without its request, which may contain backend credentials.
+func logGRPC(
+	ctx context.Context, req interface{}, info *grpclib.UnaryServerInfo, handler grpclib.UnaryHandler,
+) (interface{}, error) {
+
+	log.WithField("method", info.FullMethod).Debug("gRPC call received.")
+	resp, err := handler(ctx, req)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"method": info.FullMethod,
+			"error":  err,
+		}).Debug("gRPC call failed.")
+	}
+	return resp, err
+}
+
+// adminServer implements the admin service with the orchestrator.
+type adminServer struct {
+	orchestrator core.Orchestrator
+	stopChan     chan struct{}
+
+	// watches is the number of watches running
+	watches   int
+	watchLock sync.Mutex
+}
+
+// getGRPCError returns the gRPC status corresponding to an orchestrator error.
+func getGRPCError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case utils.IsNotFoundError(err):
+		return status.Error(codes.NotFound, err.Error())
+	case utils.IsFoundError(err):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case utils.IsNotReadyError(err):
+		return status.Error(codes.Unavailable, err.Error())
+	case utils.IsBootstrapError(err):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case utils.IsUnsupportedError(err):
+		return status.Error(codes.Unimplemented, err.Error())
+	default:
+		return status.Error(codes.Unknown, err.Error())
+	}
+}
+
+// audit records an audit event for a change requested through the admin API.
+func audit(ctx context.Context, operation, resource, name string, err error) {
+
+	event := &logging.AuditEvent{
+		Source:    logging.AuditSourceGRPC,
+		Operation: operation,
+		Resource:  resource,
+		Name:      name,
+		Error:     logging.AuditError(err),
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		event.Identity = p.Addr.String()
+	}
+	logging.Audit(event)
+}
+
+func (s *adminServer) GetVersion(ctx context.Context, _ *Empty) (*VersionResponse, error) {
+	version, err := s.orchestrator.GetVersion()
+	if err != nil {
+		return nil, getGRPCError(err)
+	}
+	return &VersionResponse{Version: version}, nil
+}
+
+func (s *adminServer) ListBackends(ctx context.Context, _ *Empty) (*ListBackendsResponse, error) {
+	backends, err := s.orchestrator.ListBackends()
+	if err != nil {
+		return nil, getGRPCError(err)
+	}
+	return &ListBackendsResponse{Backends: backends}, nil
+}
+
+func (s *adminServer) GetBackend(ctx context.Context, request *NameRequest) (*BackendResponse, error) {
+	backend, err := s.orchestrator.GetBackend(request.Name)
+	if err != nil {
+		return nil, getGRPCError(err)
+	}
+	return &BackendResponse{Backend: backend}, nil
+}
+
+func (s *adminServer) AddBackend(ctx context.Context, request *AddBackendRequest) (*BackendResponse, error) {
+	backend, err := s.orchestrator.AddBackend(request.Config)
+	name := ""
+	if backend != nil {
+		name = backend.Name
+	}
+	audit(ctx, "AddBackend", logging.AuditResourceBackend, name, err)
+	if err != nil {
+		return nil, getGRPCError(err)
+	}
+	return &BackendResponse{Backend: backend}, nil
+}
+
+func (s *adminServer) UpdateBackend(ctx context.Context, request *UpdateBackendRequest) (*BackendResponse, error) {
+	backend, err := s.orchestrator.UpdateBackend(request.Name, request.Config)
+	audit(ctx, "UpdateBackend", logging.AuditResourceBackend, request.Name, err)
+	if err != nil {
+		return nil, getGRPCError(err)
+	}
+	return &BackendResponse{Backend: backend}, nil
+}
+
+func (s *adminServer) DeleteBackend(ctx context.Context, request *NameRequest) (*Empty, error) {
+	err := s.orchestrator.DeleteBackend(request.Name)
+	audit(ctx, "DeleteBackend", logging.AuditResourceBackend, request.Name, err)
+	if err != nil {
+		return nil, getGRPCError(err)
+	}
+	return &Empty{}, nil
+}
+
+func (s *adminServer) ListVolumes(ctx context.Context, _ *Empty) (*ListVolumesResponse, error) {
+	volumes, err := s.orchestrator.ListVolumes()
+	if err != nil {
+		return nil, getGRPCError(err)
+	}
+	return &ListVolumesResponse{Volumes: volumes}, nil
+}
+
+func (s *adminServer) GetVolume(ctx context.Context, request *NameRequest) (*VolumeResponse, error) {
+	volume, err := s.orchestrator.GetVolume(request.Name)
+	if err != nil {
+		return nil, getGRPCError(err)
+	}
+	return &VolumeResponse{Volume: volume}, nil
+}
+
+func (s *adminServer) AddVolume(ctx context.Context, request *VolumeRequest) (*VolumeResponse, error) {
+	if request.Config == nil {
+		return nil, status.Error(codes.InvalidArgument, "volume config missing in request")
+	}
+	if err := request.Config.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	volume, err := s.orchestrator.AddVolume(ctx, request.Config)
+	audit(ctx, "AddVolume", logging.AuditResourceVolume, request.Config.Name, err)
+	if err != nil {
+		return nil, getGRPCError(err)
+	}
+	return &VolumeResponse{Volume: volume}, nil
+}
+
+func (s *adminServer) CloneVolume(ctx context.Context, request *VolumeRequest) (*VolumeResponse, error) {
+	if request.Config == nil {
+		return nil, status.Error(codes.InvalidArgument, "volume config missing in request")
+	}
+	if request.Config.CloneSourceVolume == "" {
+		return nil, status.Error(codes.InvalidArgument, "clone source volume missing in request")
+	}
+	if err := request.Config.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	volume, err := s.orchestrator.CloneVolume(ctx, request.Config)
+	audit(ctx, "CloneVolume", logging.AuditResourceVolume, request.Config.Name, err)
+	if err != nil {
+		return nil, getGRPCError(err)
+	}
+	return &VolumeResponse{Volume: volume}, nil
+}
+
+func (s *adminServer) ResizeVolume(ctx context.Context, request *ResizeVolumeRequest) (*Empty, error) {
+	err := s.orchestrator.ResizeVolume(ctx, request.Name, request.Size)
+	audit(ctx, "ResizeVolume", logging.AuditResourceVolume, request.Name, err)
+	if err != nil {
+		return nil, getGRPCError(err)
+	}
+	return &Empty{}, nil
+}
+
+func (s *adminServer) DeleteVolume(ctx context.Context, request *NameRequest) (*Empty, error) {
+	err := s.orchestrator.DeleteVolume(ctx, request.Name)
+	audit(ctx, "DeleteVolume", logging.AuditResourceVolume, request.Name, err)
+	if err != nil {
+		return nil, getGRPCError(err)
+	}
+	return &Empty{}, nil
+}
+
+func (s *adminServer) ListSnapshots(
+	ctx context.Context, request *ListSnapshotsRequest,
+) (*ListSnapshotsResponse, error) {
+
+	var snapshots []*storage.SnapshotExternal
+	var err error
+	if request.Volume != "" {
+		snapshots, err = s.orchestrator.ListSnapshotsForVolume(request.Volume)
+	} else {
+		snapshots, err = s.orchestrator.ListSnapshots()
+	}
+	if err != nil {
+		return nil, getGRPCError(err)
+	}
+	return &ListSnapshotsResponse{Snapshots: snapshots}, nil
+}
+
+func (s *adminServer) GetSnapshot(ctx context.Context, request *SnapshotRequest) (*SnapshotResponse, error) {
+	snapshot, err := s.orchestrator.GetSnapshot(request.Volume, request.Name)
+	if err != nil {
+		return nil, getGRPCError(err)
+	}
+	return &SnapshotResponse{Snapshot: snapshot}, nil
+}
+
+func (s *adminServer) CreateSnapshot(
+	ctx context.Context, request *CreateSnapshotRequest,
+) (*SnapshotResponse, error) {
+
+	if request.Config == nil {
+		return nil, status.Error(codes.InvalidArgument, "snapshot config missing in request")
+	}
+	if err := request.Config.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	snapshot, err := s.orchestrator.CreateSnapshot(ctx, request.Config)
+	audit(ctx, "CreateSnapshot", logging.AuditResourceSnapshot, request.Config.ID(), err)
+	if err != nil {
+		return nil, getGRPCError(err)
+	}
+	return &SnapshotResponse{Snapshot: snapshot}, nil
+}
+
+func (s *adminServer) DeleteSnapshot(ctx context.Context, request *SnapshotRequest) (*Empty, error) {
+	err := s.orchestrator.DeleteSnapshot(ctx, request.Volume, request.Name)
+	audit(ctx, "DeleteSnapshot", logging.AuditResourceSnapshot,
+		storage.MakeSnapshotID(request.Volume, request.Name), err)
+	if err != nil {
+		return nil, getGRPCError(err)
+	}
+	return &Empty{}, nil
+}
+
+func (s *adminServer) ListNodes(ctx context.Context, _ *Empty) (*ListNodesResponse, error) {
+	nodes, err := s.orchestrator.ListNodes()
+	if err != nil {
+		return nil, getGRPCError(err)
+	}
+	return &ListNodesResponse{Nodes: nodes}, nil
+}
+
+func (s *adminServer) GetNode(ctx context.Context, request *NameRequest) (*NodeResponse, error) {
+	node, err := s.orchestrator.GetNode(request.Name)
+	if err != nil {
+		return nil, getGRPCError(err)
+	}
+	return &NodeResponse{Node: node}, nil
+}
+
+func (s *adminServer) DeleteNode(ctx context.Context, request *NameRequest) (*Empty, error) {
+	err := s.orchestrator.DeleteNode(request.Name)
+	audit(ctx, "DeleteNode", logging.AuditResourceNode, request.Name, err)
+	if err != nil {
+		return nil, getGRPCError(err)
+	}
+	return &Empty{}, nil
+}
@@ -0,0 +1,173 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package grpc
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/core"
+	"github.com/netapp/trident/utils"
+)
+
+// newTestClient serves the admin API over an in-memory connection, and returns a client of it.
+func newTestClient(t *testing.T, orchestrator core.Orchestrator) (*TridentAdminClient, func()) {
+
+	listener := bufconn.Listen(1024 * 1024)
+	admin := &adminServer{orchestrator: orchestrator, stopChan: make(chan struct{})}
+	server := grpclib.NewServer(grpclib.UnaryInterceptor(logGRPC))
+	RegisterTridentAdminServer(server, admin)
+	go server.Serve(listener)
+
+	conn, err := grpclib.Dial("bufnet", grpclib.WithInsecure(),
+		grpclib.WithDialer(func(string, time.Duration) (net.Conn, error) {
+			return listener.Dial()
+		}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return NewTridentAdminClient(conn), func() {
+		conn.Close()
+		close(admin.stopChan)
+		server.Stop()
+	}
+}
+
+func TestAdminServer(t *testing.T) {
+
+	orchestrator := core.NewMockOrchestrator()
+	client, cleanup := newTestClient(t, orchestrator)
+	defer cleanup()
+	ctx := context.Background()
+
+	version, err := client.GetVersion(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, config.OrchestratorVersion.String(), version.Version)
+
+	_ = orchestrator.AddNode(&utils.Node{Name: "node1", IQN: "iqn.1"})
+
+	nodes, err := client.ListNodes(ctx)
+	assert.NoError(t, err)
+	if assert.Len(t, nodes.Nodes, 1) {
+		assert.Equal(t, "iqn.1", nodes.Nodes[0].IQN)
+	}
+
+	node, err := client.GetNode(ctx, &NameRequest{Name: "node1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "node1", node.Node.Name)
+
+	err = client.DeleteNode(ctx, &NameRequest{Name: "node1"})
+	assert.NoError(t, err)
+
+	_, err = client.GetNode(ctx, &NameRequest{Name: "node1"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = client.AddVolume(ctx, &VolumeRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestAdminServerWatch(t *testing.T) {
+
+	defer func(period time.Duration) { watchPeriod = period }(watchPeriod)
+	watchPeriod = 10 * time.Millisecond
+
+	orchestrator := core.NewMockOrchestrator()
+	client, cleanup := newTestClient(t, orchestrator)
+	defer cleanup()
+
+	_ = orchestrator.AddNode(&utils.Node{Name: "node1"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Only valid kinds may be watched
+	badWatch, err := client.Watch(ctx, &WatchRequest{Kinds: []string{"pod"}})
+	assert.NoError(t, err)
+	_, err = badWatch.Recv()
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	watch, err := client.Watch(ctx, &WatchRequest{Kinds: []string{WatchKindNode}})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	recv := func() *WatchEvent {
+		event, err := watch.Recv()
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		return event
+	}
+
+	event := recv()
+	assert.Equal(t, WatchEventAdded, event.Type)
+	assert.Equal(t, WatchKindNode, event.Kind)
+	assert.Equal(t, "node1", event.Name)
+	assert.Equal(t, WatchEventSynced, recv().Type)
+
+	_ = orchestrator.AddNode(&utils.Node{Name: "node1", IQN: "iqn.1"})
+	event = recv()
+	assert.Equal(t, WatchEventModified, event.Type)
+	assert.Contains(t, string(event.Object), "iqn.1")
+
+	_ = orchestrator.AddNode(&utils.Node{Name: "node2"})
+	event = recv()
+	assert.Equal(t, WatchEventAdded, event.Type)
+	assert.Equal(t, "node2", event.Name)
+
+	_ = orchestrator.DeleteNode("node1")
+	event = recv()
+	assert.Equal(t, WatchEventDeleted, event.Type)
+	assert.Equal(t, "node1", event.Name)
+	assert.Empty(t, event.Object)
+}
+
+func TestAdminServerWatchLimit(t *testing.T) {
+
+	defer func(watches int) { maxWatches = watches }(maxWatches)
+	maxWatches = 1
+
+	orchestrator := core.NewMockOrchestrator()
+	client, cleanup := newTestClient(t, orchestrator)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	watchCtx, cancelWatch := context.WithCancel(ctx)
+	defer cancelWatch()
+	watch, err := client.Watch(watchCtx, &WatchRequest{})
+	if !assert.NoError(t, err) {
+		return
+	}
+	event, err := watch.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, WatchEventSynced, event.Type)
+
+	// Watches beyond the maximum are refused
+	extraWatch, err := client.Watch(ctx, &WatchRequest{})
+	assert.NoError(t, err)
+	_, err = extraWatch.Recv()
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	// and allowed again once a watch ends
+	cancelWatch()
+	assert.Eventually(t, func() bool {
+		nextWatch, err := client.Watch(ctx, &WatchRequest{})
+		if err != nil {
+			return false
+		}
+		_, err = nextWatch.Recv()
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+}
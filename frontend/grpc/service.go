@@ -0,0 +1,197 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package grpc
+
+import (
+	"context"
+
+	grpclib "google.golang.org/grpc"
+)
+
+// ServiceName is the full name of Trident's gRPC admin service.
+const ServiceName = "trident.v1.TridentAdmin"
+
+// TridentAdminServer is the server API of Trident's gRPC admin service.
+type TridentAdminServer interface {
+	GetVersion(context.Context, *Empty) (*VersionResponse, error)
+
+	ListBackends(context.Context, *Empty) (*ListBackendsResponse, error)
+	GetBackend(context.Context, *NameRequest) (*BackendResponse, error)
+	AddBackend(context.Context, *AddBackendRequest) (*BackendResponse, error)
+	UpdateBackend(context.Context, *UpdateBackendRequest) (*BackendResponse, error)
+	DeleteBackend(context.Context, *NameRequest) (*Empty, error)
+
+	ListVolumes(context.Context, *Empty) (*ListVolumesResponse, error)
+	GetVolume(context.Context, *NameRequest) (*VolumeResponse, error)
+	AddVolume(context.Context, *VolumeRequest) (*VolumeResponse, error)
+	CloneVolume(context.Context, *VolumeRequest) (*VolumeResponse, error)
+	ResizeVolume(context.Context, *ResizeVolumeRequest) (*Empty, error)
+	DeleteVolume(context.Context, *NameRequest) (*Empty, error)
+
+	ListSnapshots(context.Context, *ListSnapshotsRequest) (*ListSnapshotsResponse, error)
+	GetSnapshot(context.Context, *SnapshotRequest) (*SnapshotResponse, error)
+	CreateSnapshot(context.Context, *CreateSnapshotRequest) (*SnapshotResponse, error)
+	DeleteSnapshot(context.Context, *SnapshotRequest) (*Empty, error)
+
+	ListNodes(context.Context, *Empty) (*ListNodesResponse, error)
+	GetNode(context.Context, *NameRequest) (*NodeResponse, error)
+	DeleteNode(context.Context, *NameRequest) (*Empty, error)
+
+	// Watch streams changes to Trident's objects until the client cancels it
+	Watch(*WatchRequest, TridentAdminWatchServer) error
+}
+
+// TridentAdminWatchServer is the server side of a watch stream.
+type TridentAdminWatchServer interface {
+	Send(*WatchEvent) error
+	grpclib.ServerStream
+}
+
+type tridentAdminWatchServer struct {
+	grpclib.ServerStream
+}
+
+func (s *tridentAdminWatchServer) Send(event *WatchEvent) error {
+	return s.ServerStream.SendMsg(event)
+}
+
+// RegisterTridentAdminServer registers an implementation of the admin service with a gRPC server.
+func RegisterTridentAdminServer(s *grpclib.Server, srv TridentAdminServer) {
+	s.RegisterService(&tridentAdminServiceDesc, srv)
+}
+
+// unaryMethod describes a unary method of the admin service.  The newRequest function allocates
+// the method's request, and call invokes the method with it.
+func unaryMethod(
+	name string, newRequest func() interface{},
+	call func(TridentAdminServer, context.Context, interface{}) (interface{}, error),
+) grpclib.MethodDesc {
+
+	return grpclib.MethodDesc{
+		MethodName: name,
+		Handler: func(
+			srv interface{}, ctx context.Context, dec func(interface{}) error,
+			interceptor grpclib.UnaryServerInterceptor,
+		) (interface{}, error) {
+
+			request := newRequest()
+			if err := dec(request); err != nil {
+				return nil, err
+			}
+			handler := func(ctx context.Context, request interface{}) (interface{}, error) {
+				return call(srv.(TridentAdminServer), ctx, request)
+			}
+			if interceptor == nil {
+				return handler(ctx, request)
+			}
+			info := &grpclib.UnaryServerInfo{Server: srv, FullMethod: "/" + ServiceName + "/" + name}
+			return interceptor(ctx, request, info, handler)
+		},
+	}
+}
+
+func watchHandler(srv interface{}, stream grpclib.ServerStream) error {
+	request := &WatchRequest{}
+	if err := stream.RecvMsg(request); err != nil {
+		return err
+	}
+	return srv.(TridentAdminServer).Watch(request, &tridentAdminWatchServer{stream})
+}
+
+func newEmpty() interface{}           { return &Empty{} }
+func newNameRequest() interface{}     { return &NameRequest{} }
+func newSnapshotRequest() interface{} { return &SnapshotRequest{} }
+
+var tridentAdminServiceDesc = grpclib.ServiceDesc{
+	ServiceName: ServiceName,
+	HandlerType: (*TridentAdminServer)(nil),
+	Methods: []grpclib.MethodDesc{
+		unaryMethod("GetVersion", newEmpty,
+			func(s TridentAdminServer, ctx context.Context, r interface{}) (interface{}, error) {
+				return s.GetVersion(ctx, r.(*Empty))
+			}),
+
+		unaryMethod("ListBackends", newEmpty,
+			func(s TridentAdminServer, ctx context.Context, r interface{}) (interface{}, error) {
+				return s.ListBackends(ctx, r.(*Empty))
+			}),
+		unaryMethod("GetBackend", newNameRequest,
+			func(s TridentAdminServer, ctx context.Context, r interface{}) (interface{}, error) {
+				return s.GetBackend(ctx, r.(*NameRequest))
+			}),
+		unaryMethod("AddBackend", func() interface{} { return &AddBackendRequest{} },
+			func(s TridentAdminServer, ctx context.Context, r interface{}) (interface{}, error) {
+				return s.AddBackend(ctx, r.(*AddBackendRequest))
+			}),
+		unaryMethod("UpdateBackend", func() interface{} { return &UpdateBackendRequest{} },
+			func(s TridentAdminServer, ctx context.Context, r interface{}) (interface{}, error) {
+				return s.UpdateBackend(ctx, r.(*UpdateBackendRequest))
+			}),
+		unaryMethod("DeleteBackend", newNameRequest,
+			func(s TridentAdminServer, ctx context.Context, r interface{}) (interface{}, error) {
+				return s.DeleteBackend(ctx, r.(*NameRequest))
+			}),
+
+		unaryMethod("ListVolumes", newEmpty,
+			func(s TridentAdminServer, ctx context.Context, r interface{}) (interface{}, error) {
+				return s.ListVolumes(ctx, r.(*Empty))
+			}),
+		unaryMethod("GetVolume", newNameRequest,
+			func(s TridentAdminServer, ctx context.Context, r interface{}) (interface{}, error) {
+				return s.GetVolume(ctx, r.(*NameRequest))
+			}),
+		unaryMethod("AddVolume", func() interface{} { return &VolumeRequest{} },
+			func(s TridentAdminServer, ctx context.Context, r interface{}) (interface{}, error) {
+				return s.AddVolume(ctx, r.(*VolumeRequest))
+			}),
+		unaryMethod("CloneVolume", func() interface{} { return &VolumeRequest{} },
+			func(s TridentAdminServer, ctx context.Context, r interface{}) (interface{}, error) {
+				return s.CloneVolume(ctx, r.(*VolumeRequest))
+			}),
+		unaryMethod("ResizeVolume", func() interface{} { return &ResizeVolumeRequest{} },
+			func(s TridentAdminServer, ctx context.Context, r interface{}) (interface{}, error) {
+				return s.ResizeVolume(ctx, r.(*ResizeVolumeRequest))
+			}),
+		unaryMethod("DeleteVolume", newNameRequest,
+			func(s TridentAdminServer, ctx context.Context, r interface{}) (interface{}, error) {
+				return s.DeleteVolume(ctx, r.(*NameRequest))
+			}),
+
+		unaryMethod("ListSnapshots", func() interface{} { return &ListSnapshotsRequest{} },
+			func(s TridentAdminServer, ctx context.Context, r interface{}) (interface{}, error) {
+				return s.ListSnapshots(ctx, r.(*ListSnapshotsRequest))
+			}),
+		unaryMethod("GetSnapshot", newSnapshotRequest,
+			func(s TridentAdminServer, ctx context.Context, r interface{}) (interface{}, error) {
+				return s.GetSnapshot(ctx, r.(*SnapshotRequest))
+			}),
+		unaryMethod("CreateSnapshot", func() interface{} { return &CreateSnapshotRequest{} },
+			func(s TridentAdminServer, ctx context.Context, r interface{}) (interface{}, error) {
+				return s.CreateSnapshot(ctx, r.(*CreateSnapshotRequest))
+			}),
+		unaryMethod("DeleteSnapshot", newSnapshotRequest,
+			func(s TridentAdminServer, ctx context.Context, r interface{}) (interface{}, error) {
+				return s.DeleteSnapshot(ctx, r.(*SnapshotRequest))
+			}),
+
+		unaryMethod("ListNodes", newEmpty,
+			func(s TridentAdminServer, ctx context.Context, r interface{}) (interface{}, error) {
+				return s.ListNodes(ctx, r.(*Empty))
+			}),
+		unaryMethod("GetNode", newNameRequest,
+			func(s TridentAdminServer, ctx context.Context, r interface{}) (interface{}, error) {
+				return s.GetNode(ctx, r.(*NameRequest))
+			}),
+		unaryMethod("DeleteNode", newNameRequest,
+			func(s TridentAdminServer, ctx context.Context, r interface{}) (interface{}, error) {
+				return s.DeleteNode(ctx, r.(*NameRequest))
+			}),
+	},
+	Streams: []grpclib.StreamDesc{
+		{
+			StreamName:    "Watch",
+			Handler:       watchHandler,
+			ServerStreams: true,
+		},
+	},
+}
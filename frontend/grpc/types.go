@@ -0,0 +1,118 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package grpc
+
+import (
+	"encoding/json"
+
+	"github.com/netapp/trident/storage"
+	"github.com/netapp/trident/utils"
+)
+
+const (
+	WatchKindBackend  = "backend"
+	WatchKindVolume   = "volume"
+	WatchKindSnapshot = "snapshot"
+	WatchKindNode     = "node"
+
+	// WatchEventAdded is sent for each object that exists when a watch starts, and for each
+	// object created afterward
+	WatchEventAdded    = "ADDED"
+	WatchEventModified = "MODIFIED"
+	WatchEventDeleted  = "DELETED"
+	// WatchEventSynced is sent once the objects that existed when a watch started were sent
+	WatchEventSynced = "SYNCED"
+)
+
+// Empty is the request or response of a method that takes or returns nothing.
+type Empty struct{}
+
+type VersionResponse struct {
+	Version string `json:"version"`
+}
+
+// NameRequest identifies a backend, volume or node by name.
+type NameRequest struct {
+	Name string `json:"name"`
+}
+
+type AddBackendRequest struct {
+	// Config is the backend's JSON configuration, as accepted by tridentctl
+	Config string `json:"config"`
+}
+
+type UpdateBackendRequest struct {
+	Name   string `json:"name"`
+	Config string `json:"config"`
+}
+
+type BackendResponse struct {
+	Backend *storage.BackendExternal `json:"backend"`
+}
+
+type ListBackendsResponse struct {
+	Backends []*storage.BackendExternal `json:"backends"`
+}
+
+// VolumeRequest creates or clones a volume.
+type VolumeRequest struct {
+	Config *storage.VolumeConfig `json:"config"`
+}
+
+type ResizeVolumeRequest struct {
+	Name string `json:"name"`
+	Size string `json:"size"`
+}
+
+type VolumeResponse struct {
+	Volume *storage.VolumeExternal `json:"volume"`
+}
+
+type ListVolumesResponse struct {
+	Volumes []*storage.VolumeExternal `json:"volumes"`
+}
+
+// SnapshotRequest identifies a snapshot by its volume and name.
+type SnapshotRequest struct {
+	Volume string `json:"volume"`
+	Name   string `json:"name"`
+}
+
+type CreateSnapshotRequest struct {
+	Config *storage.SnapshotConfig `json:"config"`
+}
+
+type ListSnapshotsRequest struct {
+	// Volume limits the list to the snapshots of a volume, if set
+	Volume string `json:"volume,omitempty"`
+}
+
+type SnapshotResponse struct {
+	Snapshot *storage.SnapshotExternal `json:"snapshot"`
+}
+
+type ListSnapshotsResponse struct {
+	Snapshots []*storage.SnapshotExternal `json:"snapshots"`
+}
+
+type NodeResponse struct {
+	Node *utils.Node `json:"node"`
+}
+
+type ListNodesResponse struct {
+	Nodes []*utils.Node `json:"nodes"`
+}
+
+type WatchRequest struct {
+	// Kinds limits the watch to backends, volumes, snapshots or nodes, or watches all of them if empty
+	Kinds []string `json:"kinds,omitempty"`
+}
+
+// WatchEvent reports a change to an object.  Object holds the object's JSON, in the form returned
+// by the corresponding Get method, except for deletions and the synced event.
+type WatchEvent struct {
+	Type   string          `json:"type"`
+	Kind   string          `json:"kind,omitempty"`
+	Name   string          `json:"name,omitempty"`
+	Object json.RawMessage `json:"object,omitempty"`
+}
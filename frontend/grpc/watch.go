@@ -0,0 +1,221 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package grpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/netapp/trident/utils"
+)
+
+// watchPeriod is how often a watch checks Trident's objects for changes.
+var watchPeriod = 2 * time.Second
+
+// maxWatches is how many watches may run at once, since each one lists Trident's objects every
+// watchPeriod.
+var maxWatches = 10
+
+// watchObject is the JSON of an object, as last sent to a watch.
+type watchObject struct {
+	kind   string
+	name   string
+	object []byte
+}
+
+// Watch sends an ADDED event for each existing object of the requested kinds, then a SYNCED
+// event, and then an event for each object created, modified or deleted afterward.
+func (s *adminServer) Watch(request *WatchRequest, stream TridentAdminWatchServer) error {
+
+	kinds, err := getWatchKinds(request.Kinds)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if !s.startWatch() {
+		return status.Errorf(codes.ResourceExhausted, "no more than %d watches may run at once", maxWatches)
+	}
+	defer s.endWatch()
+
+	ticker := time.NewTicker(watchPeriod)
+	defer ticker.Stop()
+
+	var known map[string]*watchObject
+	for {
+		current, err := s.listWatchObjects(kinds)
+		if err != nil && !utils.IsNotReadyError(err) {
+			return getGRPCError(err)
+		}
+
+		// Until Trident bootstraps, the objects aren't known yet
+		if err == nil {
+			for _, event := range diffWatchObjects(known, current) {
+				if err := stream.Send(event); err != nil {
+					return err
+				}
+			}
+			if known == nil {
+				if err := stream.Send(&WatchEvent{Type: WatchEventSynced}); err != nil {
+					return err
+				}
+			}
+			known = current
+		}
+
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-s.stopChan:
+			return status.Error(codes.Unavailable, "Trident is shutting down")
+		case <-ticker.C:
+		}
+	}
+}
+
+// startWatch counts a new watch, unless the maximum number of watches are already running.
+func (s *adminServer) startWatch() bool {
+	s.watchLock.Lock()
+	defer s.watchLock.Unlock()
+
+	if s.watches >= maxWatches {
+		return false
+	}
+	s.watches++
+	return true
+}
+
+// endWatch stops counting a watch that has ended.
+func (s *adminServer) endWatch() {
+	s.watchLock.Lock()
+	defer s.watchLock.Unlock()
+
+	s.watches--
+}
+
+// getWatchKinds validates the kinds of objects requested by a watch.
+func getWatchKinds(requested []string) (map[string]bool, error) {
+
+	allKinds := []string{WatchKindBackend, WatchKindVolume, WatchKindSnapshot, WatchKindNode}
+
+	kinds := make(map[string]bool)
+	if len(requested) == 0 {
+		requested = allKinds
+	}
+	for _, kind := range requested {
+		if !utils.SliceContainsString(allKinds, kind) {
+			return nil, fmt.Errorf("cannot watch '%s'; valid kinds are %v", kind, allKinds)
+		}
+		kinds[kind] = true
+	}
+	return kinds, nil
+}
+
+// listWatchObjects returns the JSON of the objects of the specified kinds, keyed by kind and name.
+func (s *adminServer) listWatchObjects(kinds map[string]bool) (map[string]*watchObject, error) {
+
+	objects := make(map[string]*watchObject)
+	add := func(kind, name string, object interface{}) error {
+		objectBytes, err := json.Marshal(object)
+		if err != nil {
+			return err
+		}
+		objects[kind+"/"+name] = &watchObject{kind: kind, name: name, object: objectBytes}
+		return nil
+	}
+
+	if kinds[WatchKindBackend] {
+		backends, err := s.orchestrator.ListBackends()
+		if err != nil {
+			return nil, err
+		}
+		for _, backend := range backends {
+			if err = add(WatchKindBackend, backend.Name, backend); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if kinds[WatchKindVolume] {
+		volumes, err := s.orchestrator.ListVolumes()
+		if err != nil {
+			return nil, err
+		}
+		for _, volume := range volumes {
+			if err = add(WatchKindVolume, volume.Config.Name, volume); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if kinds[WatchKindSnapshot] {
+		snapshots, err := s.orchestrator.ListSnapshots()
+		if err != nil {
+			return nil, err
+		}
+		for _, snapshot := range snapshots {
+			if err = add(WatchKindSnapshot, snapshot.ID(), snapshot); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if kinds[WatchKindNode] {
+		nodes, err := s.orchestrator.ListNodes()
+		if err != nil {
+			return nil, err
+		}
+		for _, node := range nodes {
+			if err = add(WatchKindNode, node.Name, node); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return objects, nil
+}
+
+// diffWatchObjects returns the events that turn the known objects into the current ones, ordered
+// by kind and name.
+func diffWatchObjects(known, current map[string]*watchObject) []*WatchEvent {
+
+	keys := make([]string, 0, len(current))
+	for key := range current {
+		keys = append(keys, key)
+	}
+	for key := range known {
+		if _, ok := current[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	events := make([]*WatchEvent, 0)
+	for _, key := range keys {
+		knownObject, wasKnown := known[key]
+		currentObject, isCurrent := current[key]
+
+		switch {
+		case !wasKnown:
+			events = append(events, &WatchEvent{Type: WatchEventAdded, Kind: currentObject.kind,
+				Name: currentObject.name, Object: currentObject.object})
+		case !isCurrent:
+			events = append(events, &WatchEvent{Type: WatchEventDeleted, Kind: knownObject.kind,
+				Name: knownObject.name})
+		case !bytes.Equal(knownObject.object, currentObject.object):
+			events = append(events, &WatchEvent{Type: WatchEventModified, Kind: currentObject.kind,
+				Name: currentObject.name, Object: currentObject.object})
+		}
+	}
+
+	if len(events) > 0 {
+		log.WithField("events", len(events)).Debug("gRPC watch found changes.")
+	}
+	return events
+}
@@ -3,6 +3,7 @@
 package kubernetes
 
 import (
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
@@ -13,6 +14,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/google/uuid"
 	log "github.com/sirupsen/logrus"
 	v1 "k8s.io/api/core/v1"
 	k8sstoragev1 "k8s.io/api/storage/v1"
@@ -20,6 +22,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	k8sversion "k8s.io/apimachinery/pkg/version"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes"
@@ -47,6 +50,7 @@ import (
 type KubernetesPlugin interface {
 	frontend.Plugin
 	ImportVolume(request *storage.ImportVolumeRequest) (*storage.VolumeExternal, error)
+	ImportVolumeDryRun(request *storage.ImportVolumeRequest) (*storage.ImportVolumeDryRunResult, error)
 }
 
 // StorageClassSummary captures relevant fields in the storage class that are needed during PV creation or PV resize.
@@ -641,40 +645,11 @@ func (p *Plugin) ImportVolume(request *storage.ImportVolumeRequest) (*storage.Vo
 
 	log.WithField("request", request).Debug("ImportVolume")
 
-	// Get PVC from ImportVolumeRequest
-	jsonData, err := base64.StdEncoding.DecodeString(request.PVCData)
-	if err != nil {
-		return nil, fmt.Errorf("the pvcData field does not contain valid base64-encoded data: %v", err)
-	}
-
-	claim := &v1.PersistentVolumeClaim{}
-	err = json.Unmarshal(jsonData, &claim)
+	claim, err := p.getImportPVC(request)
 	if err != nil {
-		return nil, fmt.Errorf("could not parse JSON PVC: %v", err)
-	}
-
-	log.WithFields(log.Fields{
-		"PVC":               claim.Name,
-		"PVC_storageClass":  GetPersistentVolumeClaimClass(claim),
-		"PVC_accessModes":   claim.Spec.AccessModes,
-		"PVC_annotations":   claim.Annotations,
-		"PVC_volume":        claim.Spec.VolumeName,
-		"PVC_requestedSize": claim.Spec.Resources.Requests[v1.ResourceStorage],
-	}).Debug("ImportVolume: received PVC data.")
-
-	if err = p.validStorageClassReceived(claim); err != nil {
-		return nil, err
-	}
-
-	provisioner := p.getClaimOrClassProvisioner(claim)
-	if err = p.validClaimReceived(claim, provisioner); err != nil {
 		return nil, err
 	}
 
-	if len(claim.Namespace) == 0 {
-		return nil, fmt.Errorf("a valid PVC namespace is required for volume import")
-	}
-
 	// Set required annotations
 	if claim.Annotations == nil {
 		claim.Annotations = map[string]string{}
@@ -774,7 +749,8 @@ func (p *Plugin) ImportVolume(request *storage.ImportVolumeRequest) (*storage.Vo
 		return nil
 	}
 
-	volumeExternal, err := p.orchestrator.LegacyImportVolume(volConfig, request.Backend, request.NoManage, createPVandPVC)
+	volumeExternal, err := p.orchestrator.LegacyImportVolume(context.Background(), volConfig, request.Backend,
+		request.NoManage, createPVandPVC)
 	if err != nil {
 		log.WithFields(log.Fields{
 			"name":     volConfig.Name,
@@ -789,6 +765,84 @@ func (p *Plugin) ImportVolume(request *storage.ImportVolumeRequest) (*storage.Vo
 	return volumeExternal, nil
 }
 
+// ImportVolumeDryRun runs all of the checks for importing a volume and reports the volume the import
+// would create, along with the changes it would make on the storage system, without creating a PVC
+// or changing the volume.
+func (p *Plugin) ImportVolumeDryRun(request *storage.ImportVolumeRequest) (*storage.ImportVolumeDryRunResult, error) {
+
+	log.WithField("request", request).Debug("ImportVolumeDryRun")
+
+	claim, err := p.getImportPVC(request)
+	if err != nil {
+		return nil, err
+	}
+
+	backend, err := p.orchestrator.GetBackend(request.Backend)
+	if err != nil {
+		return nil, fmt.Errorf("could not find backend %s; %v", request.Backend, err)
+	}
+
+	if claim.Annotations == nil {
+		claim.Annotations = map[string]string{}
+	}
+	claim.Annotations[AnnNotManaged] = strconv.FormatBool(request.NoManage)
+	claim.Annotations[AnnStorageProvisioner] = AnnOrchestrator
+
+	// The volume is named for the UID of the PVC that a real import creates, so use a placeholder UID.
+	// The volume's size is determined by the driver.
+	claim.UID = types.UID(uuid.New().String())
+	storageClass := GetPersistentVolumeClaimClass(claim)
+	annotations := p.processStorageClassAnnotations(claim, storageClass)
+	volConfig := getVolumeConfig(claim.Spec.AccessModes, claim.Spec.VolumeMode, getUniqueClaimName(claim),
+		resource.Quantity{}, annotations)
+	volConfig.ImportOriginalName = request.InternalName
+	volConfig.ImportBackendUUID = backend.BackendUUID
+	volConfig.ImportNotManaged = request.NoManage
+	volConfig.FileSystem = ""
+
+	return p.orchestrator.ImportVolumeDryRun(volConfig)
+}
+
+// getImportPVC decodes and validates the PVC in a volume import request.
+func (p *Plugin) getImportPVC(request *storage.ImportVolumeRequest) (*v1.PersistentVolumeClaim, error) {
+
+	// Get PVC from ImportVolumeRequest
+	jsonData, err := base64.StdEncoding.DecodeString(request.PVCData)
+	if err != nil {
+		return nil, fmt.Errorf("the pvcData field does not contain valid base64-encoded data: %v", err)
+	}
+
+	claim := &v1.PersistentVolumeClaim{}
+	err = json.Unmarshal(jsonData, &claim)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse JSON PVC: %v", err)
+	}
+
+	log.WithFields(log.Fields{
+		"PVC":               claim.Name,
+		"PVC_storageClass":  GetPersistentVolumeClaimClass(claim),
+		"PVC_accessModes":   claim.Spec.AccessModes,
+		"PVC_annotations":   claim.Annotations,
+		"PVC_volume":        claim.Spec.VolumeName,
+		"PVC_requestedSize": claim.Spec.Resources.Requests[v1.ResourceStorage],
+	}).Debug("ImportVolume: received PVC data.")
+
+	if err = p.validStorageClassReceived(claim); err != nil {
+		return nil, err
+	}
+
+	provisioner := p.getClaimOrClassProvisioner(claim)
+	if err = p.validClaimReceived(claim, provisioner); err != nil {
+		return nil, err
+	}
+
+	if len(claim.Namespace) == 0 {
+		return nil, fmt.Errorf("a valid PVC namespace is required for volume import")
+	}
+
+	return claim, nil
+}
+
 func (p *Plugin) deletePV(pvName string) {
 	gracePeriod := int64(0)
 	do := metav1.DeleteOptions{GracePeriodSeconds: &gracePeriod}
@@ -838,7 +892,7 @@ func (p *Plugin) createVolumeFromConfig(
 	}
 
 	if volConfig.CloneSourceVolume == "" {
-		vol, err = p.orchestrator.AddVolume(volConfig)
+		vol, err = p.orchestrator.AddVolume(context.Background(), volConfig)
 		if err != nil {
 			return nil, err
 		}
@@ -897,7 +951,7 @@ func (p *Plugin) createVolumeFromConfig(
 		volConfig.CloneSourceVolume = getUniqueClaimName(pvc)
 
 		// 5) Clone the existing volume
-		vol, err = p.orchestrator.CloneVolume(volConfig)
+		vol, err = p.orchestrator.CloneVolume(context.Background(), volConfig)
 		if err != nil {
 			return nil, err
 		}
@@ -1060,7 +1114,7 @@ func (p *Plugin) createVolumeAndPV(
 		if volExternal != nil && err != nil {
 			err1 := err
 			// Delete the volume on the backend
-			err = p.orchestrator.DeleteVolume(volExternal.Config.Name)
+			err = p.orchestrator.DeleteVolume(context.Background(), volExternal.Config.Name)
 			if err != nil {
 				err2 := "Kubernetes frontend couldn't delete the volume after failed creation: " + err.Error()
 				log.WithFields(log.Fields{
@@ -1141,7 +1195,7 @@ func (p *Plugin) getPVMountOptions(scSummary *StorageClassSummary, volume *stora
 }
 
 func (p *Plugin) deleteVolumeAndPV(pv *v1.PersistentVolume) error {
-	err := p.orchestrator.DeleteVolume(pv.GetName())
+	err := p.orchestrator.DeleteVolume(context.Background(), pv.GetName())
 	if err != nil && !utils.IsNotFoundError(err) {
 		message := fmt.Sprintf("failed to delete the volume for PV %s: %s. Volume and PV may "+
 			"need to be manually deleted.", pv.GetName(), err.Error())
@@ -1257,7 +1311,7 @@ func (p *Plugin) processDeletedVolume(pv *v1.PersistentVolume) {
 	if vol, _ := p.orchestrator.GetVolume(pv.Name); vol == nil {
 		return
 	}
-	err = p.orchestrator.DeleteVolume(pv.Name)
+	err = p.orchestrator.DeleteVolume(context.Background(), pv.Name)
 	if err != nil {
 		message := "failed to delete the provisioned volume for the lost PVC."
 		p.updatePVCWithEvent(claim, v1.EventTypeWarning, "FailedVolumeDelete", message)
@@ -1317,7 +1371,7 @@ func (p *Plugin) processUpdatedVolume(pv *v1.PersistentVolume) {
 		if pv.Spec.PersistentVolumeReclaimPolicy != v1.PersistentVolumeReclaimDelete {
 			return
 		}
-		err := p.orchestrator.DeleteVolume(pv.Name)
+		err := p.orchestrator.DeleteVolume(context.Background(), pv.Name)
 		if err != nil && !utils.IsNotFoundError(err) {
 			// Updating the PV's phase to "VolumeFailed", so that a storage admin can take action.
 			message := fmt.Sprintf("failed to delete the volume for PV %s: %s. Will eventually retry, "+
@@ -1855,7 +1909,7 @@ func (p *Plugin) resizeVolumeAndPV(pv *v1.PersistentVolume, newSize resource.Qua
 	pvSize := pv.Spec.Capacity[v1.ResourceStorage]
 	if pvSize.Cmp(newSize) < 0 {
 		// Calling the orchestrator to resize the volume on the storage backend.
-		if err := p.orchestrator.ResizeVolume(pv.Name,
+		if err := p.orchestrator.ResizeVolume(context.Background(), pv.Name,
 			fmt.Sprintf("%d", newSize.Value())); err != nil {
 			return pv, err
 		}
@@ -4,6 +4,7 @@ package metrics
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 
@@ -11,22 +12,33 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/core"
+	"github.com/netapp/trident/storage"
 )
 
+// HealthPath is served alongside the metrics so that a liveness probe, which can't use the REST
+// interface's client certificates, can check Trident's health.
+const HealthPath = "/health"
+
 type Server struct {
-	server *http.Server
+	server       *http.Server
+	orchestrator core.Orchestrator
 }
 
 // NewMetricsServer see also: https://godoc.org/github.com/prometheus/client_golang/prometheus/promauto
-func NewMetricsServer(address, port string) *Server {
-
-	metricsServer := &Server{
-		server: &http.Server{
-			Addr:         fmt.Sprintf("%s:%s", address, port),
-			Handler:      promhttp.Handler(),
-			ReadTimeout:  config.HTTPTimeout,
-			WriteTimeout: config.HTTPTimeout,
-		},
+func NewMetricsServer(address, port string, orchestrator core.Orchestrator) *Server {
+
+	metricsServer := &Server{orchestrator: orchestrator}
+
+	mux := http.NewServeMux()
+	mux.Handle("/", promhttp.Handler())
+	mux.HandleFunc(HealthPath, metricsServer.getHealth)
+
+	metricsServer.server = &http.Server{
+		Addr:         fmt.Sprintf("%s:%s", address, port),
+		Handler:      mux,
+		ReadTimeout:  config.HTTPTimeout,
+		WriteTimeout: config.HTTPTimeout,
 	}
 
 	log.WithField("address", metricsServer.server.Addr).Info("Initializing metrics frontend.")
@@ -45,6 +57,26 @@ func (s *Server) Activate() error {
 	return nil
 }
 
+// getHealth writes Trident's health report.  The response is only an error if Trident failed, so a
+// backend that can't be reached doesn't cause Trident to be restarted.
+func (s *Server) getHealth(w http.ResponseWriter, r *http.Request) {
+
+	statusCode := http.StatusOK
+	report, err := s.orchestrator.GetHealth()
+	if err != nil {
+		statusCode = http.StatusServiceUnavailable
+		report = &storage.HealthReport{Status: storage.HealthStatusFailed, Error: err.Error()}
+	} else if report.Status == storage.HealthStatusFailed {
+		statusCode = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		log.WithField("error", err).Error("Could not write health report.")
+	}
+}
+
 func (s *Server) Deactivate() error {
 	log.WithField("address", s.server.Addr).Info("Deactivating metrics frontend.")
 	ctx, cancel := context.WithTimeout(context.Background(), config.HTTPTimeout)
@@ -19,14 +19,14 @@ type APIServerHTTP struct {
 	server *http.Server
 }
 
-func NewHTTPServer(p core.Orchestrator, address, port string) *APIServerHTTP {
+func NewHTTPServer(p core.Orchestrator, address, port string, limits RequestLimits) *APIServerHTTP {
 
 	orchestrator = p
 
 	apiServer := &APIServerHTTP{
 		server: &http.Server{
 			Addr:         fmt.Sprintf("%s:%s", address, port),
-			Handler:      NewRouter(),
+			Handler:      NewRouter(limits),
 			ReadTimeout:  config.HTTPTimeout,
 			WriteTimeout: config.HTTPTimeout,
 		},
@@ -4,64 +4,61 @@ package rest
 
 import (
 	"context"
-	"crypto/tls"
-	"crypto/x509"
 	"fmt"
-	"io/ioutil"
 	"net/http"
 
 	log "github.com/sirupsen/logrus"
 
 	"github.com/netapp/trident/config"
 	"github.com/netapp/trident/core"
+	"github.com/netapp/trident/utils"
 )
 
 type APIServerHTTPS struct {
-	server         *http.Server
-	caCertFile     string
-	serverCertFile string
-	serverKeyFile  string
+	server       *http.Server
+	certReloader *utils.CertReloader
+	options      utils.TLSOptions
 }
 
 func NewHTTPSServer(
-	p core.Orchestrator, address, port, caCertFile, serverCertFile, serverKeyFile string,
+	p core.Orchestrator, address, port, caCertFile, serverCertFile, serverKeyFile string, options utils.TLSOptions,
+	limits RequestLimits,
 ) (*APIServerHTTPS, error) {
 
 	orchestrator = p
 
+	certReloader, err := utils.NewCertReloader(caCertFile, serverCertFile, serverKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("could not load HTTPS certificates: %v", err)
+	}
+
 	apiServer := &APIServerHTTPS{
 		server: &http.Server{
 			Addr:         fmt.Sprintf("%s:%s", address, port),
-			Handler:      &tlsAuthHandler{handler: NewRouter()},
-			TLSConfig:    &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert},
+			Handler:      &tlsAuthHandler{handler: NewRouter(limits), requireClientCert: options.RequireClientCert},
+			TLSConfig:    certReloader.ServerTLSConfig(options),
 			ReadTimeout:  config.HTTPTimeout,
 			WriteTimeout: config.HTTPTimeout,
 		},
-		caCertFile:     caCertFile,
-		serverCertFile: serverCertFile,
-		serverKeyFile:  serverKeyFile,
-	}
-
-	if caCertFile != "" {
-		caCert, err := ioutil.ReadFile(caCertFile)
-		if err != nil {
-			return nil, fmt.Errorf("could not read CA certificate file: %v", err)
-		}
-		caCertPool := x509.NewCertPool()
-		caCertPool.AppendCertsFromPEM(caCert)
-		apiServer.server.TLSConfig.ClientCAs = caCertPool
+		certReloader: certReloader,
+		options:      options,
 	}
 
-	log.WithField("address", apiServer.server.Addr).Info("Initializing HTTPS REST frontend.")
+	log.WithFields(log.Fields{
+		"address":           apiServer.server.Addr,
+		"requireClientCert": options.RequireClientCert,
+	}).Info("Initializing HTTPS REST frontend.")
 
 	return apiServer, nil
 }
 
 func (s *APIServerHTTPS) Activate() error {
+	s.certReloader.Start(s.options.ReloadInterval)
 	go func() {
 		log.WithField("address", s.server.Addr).Infof("Activating HTTPS REST frontend.")
-		err := s.server.ListenAndServeTLS(s.serverCertFile, s.serverKeyFile)
-		if err != nil {
+		// The certificates come from the server's TLS config, so they may be rotated while it runs
+		err := s.server.ListenAndServeTLS("", "")
+		if err != nil && err != http.ErrServerClosed {
 			log.Fatal(err)
 		}
 	}()
@@ -70,6 +67,7 @@ func (s *APIServerHTTPS) Activate() error {
 
 func (s *APIServerHTTPS) Deactivate() error {
 	log.WithField("address", s.server.Addr).Infof("Deactivating HTTPS REST frontend.")
+	s.certReloader.Stop()
 	ctx, cancel := context.WithTimeout(context.Background(), config.HTTPTimeout)
 	defer cancel()
 	return s.server.Shutdown(ctx)
@@ -85,14 +83,19 @@ func (s *APIServerHTTPS) Version() string {
 
 type tlsAuthHandler struct {
 	handler http.Handler
+	// requireClientCert rejects requests without a client certificate, rather than only those whose
+	// certificate isn't a Trident node's
+	requireClientCert bool
 }
 
 func (h *tlsAuthHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	// Service requests from Trident nodes with a valid client certificate
-	if len(r.TLS.PeerCertificates) > 0 && r.TLS.PeerCertificates[0].Subject.CommonName == config.ClientCertName {
+	if isTridentNode(r) {
 		log.WithField("peerCert", config.ClientCertName).Debug("Authenticated by HTTPS REST frontend.")
 		h.handler.ServeHTTP(w, r)
+	} else if len(r.TLS.PeerCertificates) == 0 && !h.requireClientCert {
+		h.handler.ServeHTTP(w, r)
 	} else {
 		w.Header().Set("WWW-Authenticate", fmt.Sprintf("Basic realm=\"%s\"", config.OrchestratorName))
 		w.WriteHeader(http.StatusUnauthorized)
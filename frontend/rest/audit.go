@@ -0,0 +1,156 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package rest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"github.com/netapp/trident/logging"
+)
+
+type auditContextKey struct{}
+
+// auditedRoute describes the resource changed by a REST route, and the route variables that name it.
+type auditedRoute struct {
+	resource string
+	vars     []string
+}
+
+// auditedRoutes lists the routes that change Trident's state.  Routes that only preview changes,
+// or that nodes call periodically to report on themselves, aren't audited.
+var auditedRoutes = map[string]auditedRoute{
+	"AddBackend":             {logging.AuditResourceBackend, nil},
+	"UpdateBackend":          {logging.AuditResourceBackend, []string{"backend"}},
+	"UpdateBackendState":     {logging.AuditResourceBackend, []string{"backend"}},
+	"UpdateBackendTracing":   {logging.AuditResourceBackend, []string{"backend"}},
+	"DeleteBackend":          {logging.AuditResourceBackend, []string{"backend"}},
+	"ReencryptSecrets":       {logging.AuditResourceBackend, nil},
+	"AddVolume":              {logging.AuditResourceVolume, nil},
+	"DeleteVolume":           {logging.AuditResourceVolume, []string{"volume"}},
+	"ImportVolume":           {logging.AuditResourceVolume, nil},
+	"UpgradeVolume":          {logging.AuditResourceVolume, []string{"volume"}},
+	"ResizeVolume":           {logging.AuditResourceVolume, []string{"volume"}},
+	"UnmanageVolume":         {logging.AuditResourceVolume, []string{"volume"}},
+	"CloneVolume":            {logging.AuditResourceVolume, nil},
+	"AddMigration":           {logging.AuditResourceVolume, nil},
+	"AddStorageClass":        {logging.AuditResourceStorageClass, nil},
+	"DeleteStorageClass":     {logging.AuditResourceStorageClass, []string{"storageClass"}},
+	"AddOrUpdateNode":        {logging.AuditResourceNode, []string{"node"}},
+	"DeleteNode":             {logging.AuditResourceNode, []string{"node"}},
+	"AddSnapshot":            {logging.AuditResourceSnapshot, nil},
+	"DeleteSnapshot":         {logging.AuditResourceSnapshot, []string{"volume", "snapshot"}},
+	"RestoreSnapshot":        {logging.AuditResourceSnapshot, []string{"volume", "snapshot"}},
+	"AddVolumeGroup":         {logging.AuditResourceVolumeGroup, nil},
+	"UpdateVolumeGroup":      {logging.AuditResourceVolumeGroup, []string{"group"}},
+	"DeleteVolumeGroup":      {logging.AuditResourceVolumeGroup, []string{"group"}},
+	"AddVolumeGroupSnapshot": {logging.AuditResourceVolumeGroup, []string{"group"}},
+	"CloneVolumeGroup":       {logging.AuditResourceVolumeGroup, []string{"group"}},
+	"AddCloneGrant":          {logging.AuditResourceCloneGrant, nil},
+	"DeleteCloneGrant":       {logging.AuditResourceCloneGrant, []string{"namespace", "grant"}},
+	"AddQuota":               {logging.AuditResourceQuota, nil},
+	"DeleteQuota":            {logging.AuditResourceQuota, []string{"namespace", "quota"}},
+	"ImportState":            {logging.AuditResourceState, nil},
+	"CollectGarbage":         {logging.AuditResourceState, nil},
+	"VerifyState":            {logging.AuditResourceState, nil},
+	"SetLogLevel":            {logging.AuditResourceLogLevel, nil},
+	"AddEphemeralVolume":     {logging.AuditResourceVolume, []string{"volume"}},
+	"DeleteEphemeralVolume":  {logging.AuditResourceVolume, []string{"volume"}},
+}
+
+// auditResponseWriter captures the status code and body of a response, so the outcome of an
+// audited request can be recorded.
+type auditResponseWriter struct {
+	http.ResponseWriter
+	statusCode int
+	body       bytes.Buffer
+}
+
+func (arw *auditResponseWriter) WriteHeader(code int) {
+	arw.statusCode = code
+	arw.ResponseWriter.WriteHeader(code)
+}
+
+func (arw *auditResponseWriter) Write(b []byte) (int, error) {
+	arw.body.Write(b)
+	return arw.ResponseWriter.Write(b)
+}
+
+// Auditor records an audit event for each request to a route that changes Trident's state.
+func Auditor(inner http.Handler, routeName string) http.Handler {
+
+	route, ok := auditedRoutes[routeName]
+	if !ok {
+		return inner
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		if !logging.AuditEnabled() {
+			inner.ServeHTTP(w, r)
+			return
+		}
+
+		event := &logging.AuditEvent{
+			Source:    logging.AuditSourceREST,
+			Identity:  getRequestIdentity(r),
+			UserAgent: r.UserAgent(),
+			Operation: routeName,
+			Resource:  route.resource,
+		}
+
+		vars := mux.Vars(r)
+		names := make([]string, 0, len(route.vars))
+		for _, varName := range route.vars {
+			names = append(names, vars[varName])
+		}
+		event.Name = strings.Join(names, "/")
+
+		arw := &auditResponseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+		inner.ServeHTTP(arw, r.WithContext(context.WithValue(r.Context(), auditContextKey{}, event)))
+
+		if arw.statusCode >= http.StatusBadRequest {
+			event.Result = logging.AuditResultFailure
+			event.Error = getResponseError(arw.body.Bytes())
+			if event.Error == "" {
+				event.Error = http.StatusText(arw.statusCode)
+			}
+		} else {
+			event.Result = logging.AuditResultSuccess
+		}
+		logging.Audit(event)
+	})
+}
+
+// setAuditName records the name of the resource changed by an audited request, for requests
+// whose URLs don't name the resource.
+func setAuditName(r *http.Request, name string) {
+	if event, ok := r.Context().Value(auditContextKey{}).(*logging.AuditEvent); ok && name != "" {
+		event.Name = name
+	}
+}
+
+// getRequestIdentity returns the common name of the client's certificate if the client
+// authenticated with one, or else the client's address.
+func getRequestIdentity(r *http.Request) string {
+	if r.TLS != nil && len(r.TLS.PeerCertificates) > 0 {
+		return "CN=" + r.TLS.PeerCertificates[0].Subject.CommonName
+	}
+	return r.RemoteAddr
+}
+
+// getResponseError returns the error reported in a JSON response body, if any.
+func getResponseError(body []byte) string {
+	response := struct {
+		Error string `json:"error"`
+	}{}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return ""
+	}
+	return response.Error
+}
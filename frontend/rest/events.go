@@ -0,0 +1,242 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package rest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/utils"
+)
+
+const (
+	EventKindBackend  = "backend"
+	EventKindVolume   = "volume"
+	EventKindSnapshot = "snapshot"
+
+	EventTypeAdded    = "ADDED"
+	EventTypeModified = "MODIFIED"
+	EventTypeDeleted  = "DELETED"
+	// EventTypeSynced follows the ADDED events for the objects that existed when the stream began
+	EventTypeSynced = "SYNCED"
+
+	eventKindParam = "kind"
+)
+
+var (
+	// eventPeriod is how often an event stream checks Trident's objects for changes.
+	eventPeriod = 2 * time.Second
+	// eventStreamDuration is how long an event stream runs.  Streams end before the REST server's
+	// write timeout would cut them off, and clients reconnect to continue.
+	eventStreamDuration = config.HTTPTimeout - 10*time.Second
+	// eventRetry is how long clients wait to reconnect after a stream ends.
+	eventRetry = time.Second
+)
+
+// Event describes an object of Trident's that was created, modified or deleted.
+type Event struct {
+	Type string `json:"type"`
+	Kind string `json:"kind,omitempty"`
+	Name string `json:"name,omitempty"`
+	// State is the object's state, and PreviousState its state before a modification that changed it
+	State         string          `json:"state,omitempty"`
+	PreviousState string          `json:"previousState,omitempty"`
+	Object        json.RawMessage `json:"object,omitempty"`
+}
+
+// eventObject is the JSON and state of an object, as last sent to an event stream.
+type eventObject struct {
+	kind   string
+	name   string
+	state  string
+	object []byte
+}
+
+// StreamEvents sends server-sent events for Trident's backends, volumes and snapshots, or for the
+// kinds of objects in the request's kind parameters.  It sends an ADDED event for each existing
+// object, then a SYNCED event, and then an event for each object created, modified or deleted
+// afterward, until the client disconnects or the stream's time is up.
+func StreamEvents(w http.ResponseWriter, r *http.Request) {
+
+	kinds, err := getEventKinds(r.URL.Query()[eventKindParam])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "retry: %d\n\n", eventRetry.Milliseconds())
+	flusher.Flush()
+
+	ticker := time.NewTicker(eventPeriod)
+	defer ticker.Stop()
+	timer := time.NewTimer(eventStreamDuration)
+	defer timer.Stop()
+
+	var known map[string]*eventObject
+	for {
+		current, err := listEventObjects(kinds)
+		if err != nil && !utils.IsNotReadyError(err) {
+			log.WithField("error", err).Error("Could not list objects for event stream.")
+			return
+		}
+
+		// Until Trident bootstraps, the objects aren't known yet
+		if err == nil {
+			events := diffEventObjects(known, current)
+			if known == nil {
+				events = append(events, &Event{Type: EventTypeSynced})
+			}
+			for _, event := range events {
+				if err := writeEvent(w, event); err != nil {
+					log.WithField("error", err).Debug("Could not write to event stream.")
+					return
+				}
+			}
+			flusher.Flush()
+			known = current
+		}
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-timer.C:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// writeEvent writes an event in the server-sent events format.
+func writeEvent(w http.ResponseWriter, event *Event) error {
+	eventBytes, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, eventBytes)
+	return err
+}
+
+// getEventKinds validates the kinds of objects requested by an event stream.
+func getEventKinds(requested []string) (map[string]bool, error) {
+
+	allKinds := []string{EventKindBackend, EventKindVolume, EventKindSnapshot}
+
+	kinds := make(map[string]bool)
+	if len(requested) == 0 {
+		requested = allKinds
+	}
+	for _, kind := range requested {
+		if !utils.SliceContainsString(allKinds, kind) {
+			return nil, fmt.Errorf("cannot stream events for '%s'; valid kinds are %v", kind, allKinds)
+		}
+		kinds[kind] = true
+	}
+	return kinds, nil
+}
+
+// listEventObjects returns the objects of the specified kinds, keyed by kind and name.
+func listEventObjects(kinds map[string]bool) (map[string]*eventObject, error) {
+
+	objects := make(map[string]*eventObject)
+	add := func(kind, name, state string, object interface{}) error {
+		objectBytes, err := json.Marshal(object)
+		if err != nil {
+			return err
+		}
+		objects[kind+"/"+name] = &eventObject{kind: kind, name: name, state: state, object: objectBytes}
+		return nil
+	}
+
+	if kinds[EventKindBackend] {
+		backends, err := orchestrator.ListBackends()
+		if err != nil {
+			return nil, err
+		}
+		for _, backend := range backends {
+			if err = add(EventKindBackend, backend.Name, string(backend.State), backend); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if kinds[EventKindVolume] {
+		volumes, err := orchestrator.ListVolumes()
+		if err != nil {
+			return nil, err
+		}
+		for _, volume := range volumes {
+			if err = add(EventKindVolume, volume.Config.Name, string(volume.State), volume); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if kinds[EventKindSnapshot] {
+		snapshots, err := orchestrator.ListSnapshots()
+		if err != nil {
+			return nil, err
+		}
+		for _, snapshot := range snapshots {
+			if err = add(EventKindSnapshot, snapshot.ID(), string(snapshot.State), snapshot); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return objects, nil
+}
+
+// diffEventObjects returns the events that turn the known objects into the current ones, ordered
+// by kind and name.
+func diffEventObjects(known, current map[string]*eventObject) []*Event {
+
+	keys := make([]string, 0, len(current))
+	for key := range current {
+		keys = append(keys, key)
+	}
+	for key := range known {
+		if _, ok := current[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	events := make([]*Event, 0)
+	for _, key := range keys {
+		knownObject, wasKnown := known[key]
+		currentObject, isCurrent := current[key]
+
+		switch {
+		case !wasKnown:
+			events = append(events, &Event{Type: EventTypeAdded, Kind: currentObject.kind,
+				Name: currentObject.name, State: currentObject.state, Object: currentObject.object})
+		case !isCurrent:
+			events = append(events, &Event{Type: EventTypeDeleted, Kind: knownObject.kind,
+				Name: knownObject.name, State: knownObject.state})
+		case !bytes.Equal(knownObject.object, currentObject.object):
+			event := &Event{Type: EventTypeModified, Kind: currentObject.kind, Name: currentObject.name,
+				State: currentObject.state, Object: currentObject.object}
+			if knownObject.state != currentObject.state {
+				event.PreviousState = knownObject.state
+			}
+			events = append(events, event)
+		}
+	}
+	return events
+}
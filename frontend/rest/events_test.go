@@ -0,0 +1,74 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netapp/trident/core"
+)
+
+func TestDiffEventObjects(t *testing.T) {
+
+	known := map[string]*eventObject{
+		"backend/b1": {kind: EventKindBackend, name: "b1", state: "online", object: []byte(`{"state":"online"}`)},
+		"volume/v1":  {kind: EventKindVolume, name: "v1", state: "online", object: []byte(`{"size":"1"}`)},
+		"volume/v2":  {kind: EventKindVolume, name: "v2", state: "online", object: []byte(`{}`)},
+	}
+	current := map[string]*eventObject{
+		"backend/b1": {kind: EventKindBackend, name: "b1", state: "offline", object: []byte(`{"state":"offline"}`)},
+		"volume/v1":  {kind: EventKindVolume, name: "v1", state: "online", object: []byte(`{"size":"2"}`)},
+		"volume/v3":  {kind: EventKindVolume, name: "v3", state: "online", object: []byte(`{}`)},
+	}
+
+	events := diffEventObjects(known, current)
+	assert.Equal(t, []*Event{
+		{Type: EventTypeModified, Kind: EventKindBackend, Name: "b1", State: "offline", PreviousState: "online",
+			Object: []byte(`{"state":"offline"}`)},
+		{Type: EventTypeModified, Kind: EventKindVolume, Name: "v1", State: "online", Object: []byte(`{"size":"2"}`)},
+		{Type: EventTypeDeleted, Kind: EventKindVolume, Name: "v2", State: "online"},
+		{Type: EventTypeAdded, Kind: EventKindVolume, Name: "v3", State: "online", Object: []byte(`{}`)},
+	}, events)
+
+	assert.Empty(t, diffEventObjects(current, current))
+}
+
+func TestGetEventKinds(t *testing.T) {
+
+	kinds, err := getEventKinds(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]bool{EventKindBackend: true, EventKindVolume: true, EventKindSnapshot: true}, kinds)
+
+	kinds, err = getEventKinds([]string{EventKindVolume})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]bool{EventKindVolume: true}, kinds)
+
+	_, err = getEventKinds([]string{"storageclass"})
+	assert.Error(t, err)
+}
+
+func TestStreamEvents(t *testing.T) {
+
+	savedOrchestrator, savedPeriod, savedDuration := orchestrator, eventPeriod, eventStreamDuration
+	defer func() {
+		orchestrator, eventPeriod, eventStreamDuration = savedOrchestrator, savedPeriod, savedDuration
+	}()
+	orchestrator = core.NewMockOrchestrator()
+	eventPeriod = 10 * time.Millisecond
+	eventStreamDuration = 50 * time.Millisecond
+
+	w := httptest.NewRecorder()
+	StreamEvents(w, httptest.NewRequest("GET", "/trident/v1/events?kind=volume", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+	assert.Equal(t, "retry: 1000\n\nevent: SYNCED\ndata: {\"type\":\"SYNCED\"}\n\n", w.Body.String())
+
+	w = httptest.NewRecorder()
+	StreamEvents(w, httptest.NewRequest("GET", "/trident/v1/events?kind=storageclass", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -5,9 +5,9 @@ package rest
 import (
 	"encoding/json"
 	"fmt"
-	"io"
 	"io/ioutil"
 	"net/http"
+	"strconv"
 
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
@@ -17,6 +17,9 @@ import (
 	"github.com/netapp/trident/frontend/csi/helpers"
 	k8shelper "github.com/netapp/trident/frontend/csi/helpers/kubernetes"
 	"github.com/netapp/trident/frontend/kubernetes"
+	"github.com/netapp/trident/logging"
+	persistentstore "github.com/netapp/trident/persistent_store"
+	netappv1 "github.com/netapp/trident/persistent_store/crd/apis/netapp/v1"
 	"github.com/netapp/trident/storage"
 	storageclass "github.com/netapp/trident/storage_class"
 	"github.com/netapp/trident/utils"
@@ -189,7 +192,8 @@ func AddGeneric(
 		writeHTTPResponse(w, response, httpStatusCode)
 	}()
 
-	body, err := ioutil.ReadAll(io.LimitReader(r.Body, config.MaxRESTRequestSize))
+	// The body's size is limited by the router
+	body, err := ioutil.ReadAll(r.Body)
 	if err != nil {
 		response.setError(err)
 		httpStatusCode = httpStatusCodeForAdd(err)
@@ -226,7 +230,8 @@ func UpdateGeneric(
 
 	vars := mux.Vars(r)
 	target := vars[varName]
-	body, err := ioutil.ReadAll(io.LimitReader(r.Body, config.MaxRESTRequestSize))
+	// The body's size is limited by the router
+	body, err := ioutil.ReadAll(r.Body)
 	if err != nil {
 		response.setError(err)
 		httpStatusCode = httpStatusCodeForGetUpdateList(err)
@@ -337,16 +342,47 @@ func GetVersion(w http.ResponseWriter, r *http.Request) {
 	)
 }
 
+// resolveBackendSecret returns a backend config with the keys of the credentials secret it references,
+// if any, in place of the reference.  Only the K8S helper can read secrets, so configs that reference
+// one are rejected elsewhere.
+func resolveBackendSecret(configJSON string) (string, error) {
+
+	// Configs that can't be parsed are left for the orchestrator to reject
+	var config map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(configJSON), &config); err != nil {
+		return configJSON, nil
+	}
+	if _, ok := config[netappv1.BackendConfigCredentialsKey]; !ok {
+		return configJSON, nil
+	}
+
+	k8sHelperFrontend, err := orchestrator.GetFrontend(helpers.KubernetesHelper)
+	if err != nil {
+		return "", utils.UnsupportedError("credentials secrets are only supported with Kubernetes")
+	}
+	k8sHelper, ok := k8sHelperFrontend.(k8shelper.K8SHelperPlugin)
+	if !ok {
+		return "", fmt.Errorf("unable to obtain K8S helper frontend")
+	}
+	return k8sHelper.ResolveBackendSecret(configJSON)
+}
+
 func AddBackend(w http.ResponseWriter, r *http.Request) {
 	response := &AddBackendResponse{}
 	AddGeneric(w, r, response,
 		func(body []byte) int {
-			backend, err := orchestrator.AddBackend(string(body))
+			configJSON, err := resolveBackendSecret(string(body))
+			if err != nil {
+				response.setError(err)
+				return httpStatusCodeForAdd(err)
+			}
+			backend, err := orchestrator.AddBackend(configJSON)
 			if err != nil {
 				response.setError(err)
 			}
 			if backend != nil {
 				response.BackendID = backend.Name
+				setAuditName(r, backend.Name)
 			}
 			return httpStatusCodeForAdd(err)
 		},
@@ -384,7 +420,12 @@ func UpdateBackend(w http.ResponseWriter, r *http.Request) {
 	response := &UpdateBackendResponse{}
 	UpdateGeneric(w, r, "backend", response,
 		func(backendName string, body []byte) int {
-			backend, err := orchestrator.UpdateBackend(backendName, string(body))
+			configJSON, err := resolveBackendSecret(string(body))
+			if err != nil {
+				response.setError(err)
+				return httpStatusCodeForGetUpdateList(err)
+			}
+			backend, err := orchestrator.UpdateBackend(backendName, configJSON)
 			if err != nil {
 				response.Error = err.Error()
 			}
@@ -396,6 +437,52 @@ func UpdateBackend(w http.ResponseWriter, r *http.Request) {
 	)
 }
 
+type PreviewBackendUpdateResponse struct {
+	Preview *storage.BackendUpdatePreview `json:"preview,omitempty"`
+	Error   string                        `json:"error,omitempty"`
+}
+
+func (r *PreviewBackendUpdateResponse) setError(err error) {
+	r.Error = err.Error()
+}
+
+func (r *PreviewBackendUpdateResponse) isError() bool {
+	return r.Error != ""
+}
+
+func (r *PreviewBackendUpdateResponse) logSuccess() {
+	log.WithFields(log.Fields{
+		"backend": r.Preview.Backend,
+		"allowed": r.Preview.Allowed,
+		"handler": "PreviewBackendUpdate",
+	}).Info("Previewed a backend update.")
+}
+
+func (r *PreviewBackendUpdateResponse) logFailure() {
+	log.WithFields(log.Fields{
+		"handler": "PreviewBackendUpdate",
+	}).Error(r.Error)
+}
+
+func PreviewBackendUpdate(w http.ResponseWriter, r *http.Request) {
+	response := &PreviewBackendUpdateResponse{}
+	UpdateGeneric(w, r, "backend", response,
+		func(backendName string, body []byte) int {
+			configJSON, err := resolveBackendSecret(string(body))
+			if err != nil {
+				response.setError(err)
+				return httpStatusCodeForGetUpdateList(err)
+			}
+			preview, err := orchestrator.PreviewBackendUpdate(backendName, configJSON)
+			if err != nil {
+				response.setError(err)
+			}
+			response.Preview = preview
+			return httpStatusCodeForGetUpdateList(err)
+		},
+	)
+}
+
 func UpdateBackendState(w http.ResponseWriter, r *http.Request) {
 	response := &UpdateBackendResponse{}
 	UpdateGeneric(w, r, "backend", response,
@@ -418,8 +505,71 @@ func UpdateBackendState(w http.ResponseWriter, r *http.Request) {
 	)
 }
 
+func UpdateBackendTracing(w http.ResponseWriter, r *http.Request) {
+	response := &UpdateBackendResponse{}
+	UpdateGeneric(w, r, "backend", response,
+		func(backendName string, body []byte) int {
+			request := new(storage.UpdateBackendTracingRequest)
+			err := json.Unmarshal(body, request)
+			if err != nil {
+				response.setError(fmt.Errorf("invalid JSON: %s", err.Error()))
+				return httpStatusCodeForGetUpdateList(err)
+			}
+			backend, err := orchestrator.UpdateBackendTracing(backendName, request.DebugTraceFlags)
+			if err != nil {
+				response.Error = err.Error()
+			}
+			if backend != nil {
+				response.BackendID = backend.Name
+			}
+			return httpStatusCodeForGetUpdateList(err)
+		},
+	)
+}
+
+type ReencryptSecretsResponse struct {
+	Backends []string `json:"backends"`
+	Error    string   `json:"error,omitempty"`
+}
+
+func (r *ReencryptSecretsResponse) setError(err error) {
+	r.Error = err.Error()
+}
+
+func (r *ReencryptSecretsResponse) isError() bool {
+	return r.Error != ""
+}
+
+func (r *ReencryptSecretsResponse) logSuccess() {
+	log.WithFields(log.Fields{
+		"handler":  "ReencryptSecrets",
+		"backends": len(r.Backends),
+	}).Info("Re-encrypted backend secrets.")
+}
+
+func (r *ReencryptSecretsResponse) logFailure() {
+	log.WithFields(log.Fields{
+		"handler": "ReencryptSecrets",
+	}).Error(r.Error)
+}
+
+func ReencryptSecrets(w http.ResponseWriter, r *http.Request) {
+	response := &ReencryptSecretsResponse{}
+	UpdateGeneric(w, r, "", response,
+		func(_ string, _ []byte) int {
+			backendNames, err := orchestrator.ReencryptBackendSecrets()
+			if err != nil {
+				response.setError(err)
+			}
+			response.Backends = backendNames
+			return httpStatusCodeForGetUpdateList(err)
+		},
+	)
+}
+
 type ListBackendsResponse struct {
 	Backends []string `json:"backends"`
+	Continue string   `json:"continue,omitempty"`
 	Error    string   `json:"error,omitempty"`
 }
 
@@ -427,23 +577,39 @@ func (l *ListBackendsResponse) setList(payload []string) {
 	l.Backends = payload
 }
 
+var backendListFields = []string{"state", "protocol", "online"}
+
 func ListBackends(w http.ResponseWriter, r *http.Request) {
 	response := &ListBackendsResponse{}
 	ListGeneric(w, r, response,
 		func() int {
-			backendNames := make([]string, 0)
+			response.setList(make([]string, 0))
+			options, err := parseListOptions(r, backendListFields...)
+			if err != nil {
+				response.Error = err.Error()
+				return http.StatusBadRequest
+			}
 			backends, err := orchestrator.ListBackends()
 			if err != nil {
 				log.Errorf("ListBackends: %v", err)
 				response.Error = err.Error()
-			} else if backends != nil && len(backends) > 0 {
-				backendNames = make([]string, 0, len(backends))
-				for _, backend := range backends {
-					backendNames = append(backendNames, backend.Name)
-				}
+				return httpStatusCodeForGetUpdateList(err)
 			}
+			items := make([]listItem, 0, len(backends))
+			for _, backend := range backends {
+				items = append(items, listItem{
+					name: backend.Name,
+					fields: map[string]string{
+						"state":    string(backend.State),
+						"protocol": string(backend.Protocol),
+						"online":   strconv.FormatBool(backend.Online),
+					},
+				})
+			}
+			backendNames, continueToken := options.apply(items)
 			response.setList(backendNames)
-			return httpStatusCodeForGetUpdateList(err)
+			response.Continue = continueToken
+			return http.StatusOK
 		},
 	)
 }
@@ -496,10 +662,70 @@ func GetBackendByBackendUUID(w http.ResponseWriter, r *http.Request) {
 	)
 }
 
+type GetBackendCapacityResponse struct {
+	Capacity *storage.BackendCapacity `json:"capacity"`
+	Error    string                   `json:"error,omitempty"`
+}
+
+// GetBackendCapacity returns the space and volumes in each of a backend's pools, so capacity can be
+// monitored without access to the storage system.
+func GetBackendCapacity(w http.ResponseWriter, r *http.Request) {
+	response := &GetBackendCapacityResponse{}
+	GetGeneric(w, r, "backend", response,
+		func(backend string) int {
+			if IsValidUUID(backend) {
+				result, err := orchestrator.GetBackendByBackendUUID(backend)
+				if err != nil {
+					response.Error = err.Error()
+					return httpStatusCodeForGetUpdateList(err)
+				}
+				backend = result.Name
+			}
+
+			capacity, err := orchestrator.GetBackendCapacity(backend)
+			if err != nil {
+				response.Error = err.Error()
+			} else {
+				response.Capacity = capacity
+			}
+			return httpStatusCodeForGetUpdateList(err)
+		},
+	)
+}
+
 // DeleteBackend calls OfflineBackend in the orchestrator, as we currently do
 // not allow for full deletion of backends due to the potential for race
 // conditions and the additional bookkeeping that would be required.
+// deleteForceParam is the query parameter that removes the custom resource of a backend or volume
+// that Trident no longer manages, even if Trident's finalizers are keeping it
+const deleteForceParam = "force"
+
+// isForceDelete reports whether a delete request sets the force query parameter.
+func isForceDelete(r *http.Request) (bool, error) {
+	value := r.URL.Query().Get(deleteForceParam)
+	if value == "" {
+		return false, nil
+	}
+	force, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s parameter %s", deleteForceParam, value)
+	}
+	return force, nil
+}
+
+// DeleteBackend deletes a backend.  The force query parameter instead removes the custom resource
+// of a backend that Trident no longer manages.
 func DeleteBackend(w http.ResponseWriter, r *http.Request) {
+	force, err := isForceDelete(r)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+		writeHTTPResponse(w, DeleteResponse{Error: err.Error()}, http.StatusBadRequest)
+		return
+	}
+	if force {
+		DeleteGeneric(w, r, orchestrator.ForceDeleteBackend, "backend")
+		return
+	}
 	DeleteGeneric(w, r, orchestrator.DeleteBackend, "backend")
 }
 
@@ -538,11 +764,12 @@ func AddVolume(w http.ResponseWriter, r *http.Request) {
 				response.setError(fmt.Errorf("invalid JSON: %s", err.Error()))
 				return httpStatusCodeForAdd(err)
 			}
+			setAuditName(r, volumeConfig.Name)
 			if err = volumeConfig.Validate(); err != nil {
 				response.setError(err)
 				return httpStatusCodeForAdd(err)
 			}
-			volume, err := orchestrator.AddVolume(volumeConfig)
+			volume, err := orchestrator.AddVolume(r.Context(), volumeConfig)
 			if err != nil {
 				response.setError(err)
 			}
@@ -555,30 +782,58 @@ func AddVolume(w http.ResponseWriter, r *http.Request) {
 }
 
 type ListVolumesResponse struct {
-	Volumes []string `json:"volumes"`
-	Error   string   `json:"error,omitempty"`
+	Volumes  []string `json:"volumes"`
+	Continue string   `json:"continue,omitempty"`
+	Error    string   `json:"error,omitempty"`
 }
 
 func (l *ListVolumesResponse) setList(payload []string) {
 	l.Volumes = payload
 }
 
+var volumeListFields = []string{"backend", "storageClass", "state", "protocol", "size"}
+
 func ListVolumes(w http.ResponseWriter, r *http.Request) {
 	response := &ListVolumesResponse{}
 	ListGeneric(w, r, response,
 		func() int {
-			volumeNames := make([]string, 0)
+			response.setList(make([]string, 0))
+			options, err := parseListOptions(r, volumeListFields...)
+			if err != nil {
+				response.Error = err.Error()
+				return http.StatusBadRequest
+			}
 			volumes, err := orchestrator.ListVolumes()
 			if err != nil {
 				response.Error = err.Error()
-			} else if volumes != nil && len(volumes) > 0 {
-				volumeNames = make([]string, 0, len(volumes))
-				for _, volume := range volumes {
-					volumeNames = append(volumeNames, volume.Config.Name)
-				}
+				return httpStatusCodeForGetUpdateList(err)
+			}
+			backends, err := orchestrator.ListBackends()
+			if err != nil {
+				response.Error = err.Error()
+				return httpStatusCodeForGetUpdateList(err)
 			}
+			backendNames := make(map[string]string, len(backends))
+			for _, backend := range backends {
+				backendNames[backend.BackendUUID] = backend.Name
+			}
+			items := make([]listItem, 0, len(volumes))
+			for _, volume := range volumes {
+				items = append(items, listItem{
+					name: volume.Config.Name,
+					fields: map[string]string{
+						"backend":      backendNames[volume.BackendUUID],
+						"storageClass": volume.Config.StorageClass,
+						"state":        string(volume.State),
+						"protocol":     string(volume.Config.Protocol),
+						"size":         volume.Config.Size,
+					},
+				})
+			}
+			volumeNames, continueToken := options.apply(items)
 			response.setList(volumeNames)
-			return httpStatusCodeForGetUpdateList(err)
+			response.Continue = continueToken
+			return http.StatusOK
 		},
 	)
 }
@@ -603,13 +858,30 @@ func GetVolume(w http.ResponseWriter, r *http.Request) {
 	)
 }
 
+// DeleteVolume deletes a volume.  The force query parameter instead removes the custom resource of
+// a volume that Trident no longer manages.
 func DeleteVolume(w http.ResponseWriter, r *http.Request) {
-	DeleteGeneric(w, r, orchestrator.DeleteVolume, "volume")
+	force, err := isForceDelete(r)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+		writeHTTPResponse(w, DeleteResponse{Error: err.Error()}, http.StatusBadRequest)
+		return
+	}
+	if force {
+		DeleteGeneric(w, r, orchestrator.ForceDeleteVolume, "volume")
+		return
+	}
+	DeleteGeneric(w, r, func(volumeName string) error {
+		return orchestrator.DeleteVolume(r.Context(), volumeName)
+	}, "volume")
 }
 
 type ImportVolumeResponse struct {
 	Volume *storage.VolumeExternal `json:"volume"`
-	Error  string                  `json:"error,omitempty"`
+	// DryRun is set if the volume was only checked, in which case Changes lists what importing it would change
+	DryRun  bool     `json:"dryRun,omitempty"`
+	Changes []string `json:"changes,omitempty"`
+	Error   string   `json:"error,omitempty"`
 }
 
 func (i *ImportVolumeResponse) setError(err error) {
@@ -621,7 +893,12 @@ func (i *ImportVolumeResponse) isError() bool {
 }
 
 func (i *ImportVolumeResponse) logSuccess() {
-	if i.Volume != nil {
+	if i.DryRun {
+		log.WithFields(log.Fields{
+			"handler": "ImportVolume",
+			"changes": i.Changes,
+		}).Info("Checked an existing volume for import.")
+	} else if i.Volume != nil {
 		log.WithFields(log.Fields{
 			"handler":     "ImportVolume",
 			"backendUUID": i.Volume.BackendUUID,
@@ -649,6 +926,7 @@ func ImportVolume(w http.ResponseWriter, r *http.Request) {
 				response.setError(fmt.Errorf("invalid JSON: %s", err.Error()))
 				return httpStatusCodeForAdd(err)
 			}
+			setAuditName(r, importVolumeRequest.InternalName)
 			if err = importVolumeRequest.Validate(); err != nil {
 				response.setError(err)
 				return httpStatusCodeForAdd(err)
@@ -667,12 +945,24 @@ func ImportVolume(w http.ResponseWriter, r *http.Request) {
 				response.setError(err)
 				return httpStatusCodeForAdd(err)
 			}
+			if importVolumeRequest.DryRun {
+				response.DryRun = true
+				result, err := k8s.ImportVolumeDryRun(importVolumeRequest)
+				if err != nil {
+					response.setError(err)
+				} else {
+					response.Volume = result.Volume
+					response.Changes = result.Changes
+				}
+				return httpStatusCodeForAdd(err)
+			}
 			volume, err := k8s.ImportVolume(importVolumeRequest)
 			if err != nil {
 				response.setError(err)
 			}
 			if volume != nil {
 				response.Volume = volume
+				setAuditName(r, volume.Config.Name)
 			}
 			return httpStatusCodeForAdd(err)
 		},
@@ -742,6 +1032,192 @@ func UpgradeVolume(w http.ResponseWriter, r *http.Request) {
 	)
 }
 
+type ResizeVolumeResponse struct {
+	Volume *storage.VolumeExternal `json:"volume"`
+	// RescanNodes are the nodes that must rescan the volume's LUN and grow its filesystem to see the new size
+	RescanNodes []string `json:"rescanNodes,omitempty"`
+	Error       string   `json:"error,omitempty"`
+}
+
+func (i *ResizeVolumeResponse) setError(err error) {
+	i.Error = err.Error()
+}
+
+func (i *ResizeVolumeResponse) isError() bool {
+	return i.Error != ""
+}
+
+func (i *ResizeVolumeResponse) logSuccess() {
+	log.WithFields(log.Fields{
+		"handler": "ResizeVolume",
+		"volume":  i.Volume.Config.Name,
+		"size":    i.Volume.Config.Size,
+	}).Info("Resized a volume.")
+}
+func (i *ResizeVolumeResponse) logFailure() {
+	log.WithFields(log.Fields{
+		"handler": "ResizeVolume",
+	}).Error(i.Error)
+}
+
+func ResizeVolume(w http.ResponseWriter, r *http.Request) {
+	response := &ResizeVolumeResponse{}
+	UpdateGeneric(w, r, "volume", response,
+		func(volumeName string, body []byte) int {
+			resizeVolumeRequest := new(storage.ResizeVolumeRequest)
+			err := json.Unmarshal(body, resizeVolumeRequest)
+			if err != nil {
+				response.setError(fmt.Errorf("invalid JSON: %s", err.Error()))
+				return httpStatusCodeForGetUpdateList(err)
+			}
+			if err = resizeVolumeRequest.Validate(); err != nil {
+				response.setError(err)
+				return httpStatusCodeForGetUpdateList(err)
+			}
+
+			if err = orchestrator.ResizeVolume(r.Context(), volumeName, resizeVolumeRequest.Size); err != nil {
+				response.setError(err)
+				return httpStatusCodeForGetUpdateList(err)
+			}
+
+			volume, err := orchestrator.GetVolume(volumeName)
+			if err != nil {
+				response.setError(err)
+				return httpStatusCodeForGetUpdateList(err)
+			}
+			response.Volume = volume
+
+			// Hosts see a larger NFS export right away, but must rescan a LUN to see its new size
+			if volume.Config.Protocol == config.Block {
+				response.RescanNodes = volume.PublishedNodes
+			}
+			return httpStatusCodeForGetUpdateList(nil)
+		},
+	)
+}
+
+type UnmanageVolumeResponse struct {
+	Volume string `json:"volume"`
+	// StorageName is the name of the volume on its storage system, which Trident no longer manages
+	StorageName string `json:"storageName,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+func (i *UnmanageVolumeResponse) setError(err error) {
+	i.Error = err.Error()
+}
+
+func (i *UnmanageVolumeResponse) isError() bool {
+	return i.Error != ""
+}
+
+func (i *UnmanageVolumeResponse) logSuccess() {
+	log.WithFields(log.Fields{
+		"handler":     "UnmanageVolume",
+		"volume":      i.Volume,
+		"storageName": i.StorageName,
+	}).Info("Unmanaged a volume.")
+}
+func (i *UnmanageVolumeResponse) logFailure() {
+	log.WithFields(log.Fields{
+		"handler": "UnmanageVolume",
+		"volume":  i.Volume,
+	}).Error(i.Error)
+}
+
+// UnmanageVolume removes a volume from Trident without deleting its storage.
+func UnmanageVolume(w http.ResponseWriter, r *http.Request) {
+	response := &UnmanageVolumeResponse{}
+	UpdateGeneric(w, r, "volume", response,
+		func(volumeName string, body []byte) int {
+			response.Volume = volumeName
+			unmanageVolumeRequest := new(storage.UnmanageVolumeRequest)
+			if len(body) > 0 {
+				if err := json.Unmarshal(body, unmanageVolumeRequest); err != nil {
+					response.setError(fmt.Errorf("invalid JSON: %s", err.Error()))
+					return httpStatusCodeForGetUpdateList(err)
+				}
+			}
+
+			storageName, err := orchestrator.UnmanageVolume(volumeName, unmanageVolumeRequest.StorageName)
+			if err != nil {
+				response.setError(err)
+			}
+			response.StorageName = storageName
+			return httpStatusCodeForGetUpdateList(err)
+		},
+	)
+}
+
+type CloneVolumeResponse struct {
+	Volume *storage.VolumeExternal `json:"volume,omitempty"`
+	Error  string                  `json:"error,omitempty"`
+}
+
+func (c *CloneVolumeResponse) setError(err error) {
+	c.Error = err.Error()
+}
+
+func (c *CloneVolumeResponse) isError() bool {
+	return c.Error != ""
+}
+
+func (c *CloneVolumeResponse) logSuccess() {
+	log.WithFields(log.Fields{
+		"handler":        "CloneVolume",
+		"volume":         c.Volume.Config.Name,
+		"sourceVolume":   c.Volume.Config.CloneSourceVolume,
+		"sourceSnapshot": c.Volume.Config.CloneSourceSnapshot,
+	}).Info("Cloned a volume.")
+}
+func (c *CloneVolumeResponse) logFailure() {
+	log.WithFields(log.Fields{
+		"handler": "CloneVolume",
+	}).Error(c.Error)
+}
+
+// CloneVolume clones a volume, or one of its snapshots, into a new volume alongside it.
+func CloneVolume(w http.ResponseWriter, r *http.Request) {
+	response := &CloneVolumeResponse{}
+	UpdateGeneric(w, r, "volume", response,
+		func(sourceName string, body []byte) int {
+			request := new(storage.VolumeCloneRequest)
+			if err := json.Unmarshal(body, request); err != nil {
+				response.setError(fmt.Errorf("invalid JSON: %s", err.Error()))
+				return httpStatusCodeForAdd(err)
+			}
+			setAuditName(r, request.Name)
+			if err := request.Validate(); err != nil {
+				response.setError(err)
+				return httpStatusCodeForAdd(err)
+			}
+
+			// The clone has its source's volume mode and storage class
+			sourceVolume, err := orchestrator.GetVolume(sourceName)
+			if err != nil {
+				response.setError(err)
+				return httpStatusCodeForGetUpdateList(err)
+			}
+			cloneConfig := &storage.VolumeConfig{
+				Name:                request.Name,
+				StorageClass:        sourceVolume.Config.StorageClass,
+				VolumeMode:          sourceVolume.Config.VolumeMode,
+				CloneSourceVolume:   sourceName,
+				CloneSourceSnapshot: request.Snapshot,
+				SplitOnClone:        request.SplitOnClone,
+				Pool:                request.Pool,
+			}
+
+			volume, err := orchestrator.CloneVolume(r.Context(), cloneConfig)
+			if err != nil {
+				response.setError(err)
+			}
+			response.Volume = volume
+			return httpStatusCodeForAdd(err)
+		},
+	)
+}
+
 type AddStorageClassResponse struct {
 	StorageClassID string `json:"storageClass"`
 	Error          string `json:"error,omitempty"`
@@ -781,6 +1257,7 @@ func AddStorageClass(w http.ResponseWriter, r *http.Request) {
 				response.setError(fmt.Errorf("invalid JSON: %s", err.Error()))
 				return httpStatusCodeForAdd(err)
 			}
+			setAuditName(r, scConfig.Name)
 			sc, err := orchestrator.AddStorageClass(scConfig)
 			if err != nil {
 				response.setError(err)
@@ -795,6 +1272,7 @@ func AddStorageClass(w http.ResponseWriter, r *http.Request) {
 
 type ListStorageClassesResponse struct {
 	StorageClasses []string `json:"storageClasses"`
+	Continue       string   `json:"continue,omitempty"`
 	Error          string   `json:"error,omitempty"`
 }
 
@@ -806,18 +1284,25 @@ func ListStorageClasses(w http.ResponseWriter, r *http.Request) {
 	response := &ListStorageClassesResponse{}
 	ListGeneric(w, r, response,
 		func() int {
-			storageClassNames := make([]string, 0)
+			response.setList(make([]string, 0))
+			options, err := parseListOptions(r)
+			if err != nil {
+				response.Error = err.Error()
+				return http.StatusBadRequest
+			}
 			storageClasses, err := orchestrator.ListStorageClasses()
 			if err != nil {
 				response.Error = err.Error()
-			} else if storageClasses != nil && len(storageClasses) > 0 {
-				storageClassNames = make([]string, 0, len(storageClasses))
-				for _, sc := range storageClasses {
-					storageClassNames = append(storageClassNames, sc.GetName())
-				}
+				return httpStatusCodeForGetUpdateList(err)
+			}
+			items := make([]listItem, 0, len(storageClasses))
+			for _, sc := range storageClasses {
+				items = append(items, listItem{name: sc.GetName()})
 			}
+			storageClassNames, continueToken := options.apply(items)
 			response.setList(storageClassNames)
-			return httpStatusCodeForGetUpdateList(err)
+			response.Continue = continueToken
+			return http.StatusOK
 		},
 	)
 }
@@ -847,8 +1332,9 @@ func DeleteStorageClass(w http.ResponseWriter, r *http.Request) {
 }
 
 type AddNodeResponse struct {
-	Name  string `json:"name"`
-	Error string `json:"error,omitempty"`
+	Name           string            `json:"name"`
+	TopologyLabels map[string]string `json:"topologyLabels,omitempty"`
+	Error          string            `json:"error,omitempty"`
 }
 
 func (a *AddNodeResponse) setError(err error) {
@@ -886,16 +1372,42 @@ func AddNode(w http.ResponseWriter, r *http.Request) {
 				response.setError(fmt.Errorf("invalid JSON: %s", err.Error()))
 				return httpStatusCodeForAdd(err)
 			}
+			setNodeTopologyLabels(node)
 			err = orchestrator.AddNode(node)
 			if err != nil {
 				response.setError(err)
 			}
 			response.Name = node.Name
+			response.TopologyLabels = node.TopologyLabels
 			return httpStatusCodeForAdd(err)
 		},
 	)
 }
 
+// setNodeTopologyLabels sets a node's topology labels from the container orchestrator, which is the only
+// one that knows them.  If they can't be read, the node keeps the labels it registered with.
+func setNodeTopologyLabels(node *utils.Node) {
+
+	k8sHelperFrontend, err := orchestrator.GetFrontend(helpers.KubernetesHelper)
+	if err != nil {
+		return
+	}
+	k8sHelper, ok := k8sHelperFrontend.(k8shelper.K8SHelperPlugin)
+	if !ok {
+		return
+	}
+
+	topologyLabels, err := k8sHelper.GetNodeTopologyLabels(node.Name)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"node":  node.Name,
+			"error": err,
+		}).Warning("Could not get node topology labels.")
+		return
+	}
+	node.TopologyLabels = topologyLabels
+}
+
 type GetNodeResponse struct {
 	Node  *utils.Node `json:"node"`
 	Error string      `json:"error,omitempty"`
@@ -917,8 +1429,9 @@ func GetNode(w http.ResponseWriter, r *http.Request) {
 }
 
 type ListNodesResponse struct {
-	Nodes []string `json:"nodes"`
-	Error string   `json:"error,omitempty"`
+	Nodes    []string `json:"nodes"`
+	Continue string   `json:"continue,omitempty"`
+	Error    string   `json:"error,omitempty"`
 }
 
 func (l *ListNodesResponse) setList(payload []string) {
@@ -929,18 +1442,25 @@ func ListNodes(w http.ResponseWriter, r *http.Request) {
 	response := &ListNodesResponse{}
 	ListGeneric(w, r, response,
 		func() int {
-			nodeNames := make([]string, 0)
+			response.setList(make([]string, 0))
+			options, err := parseListOptions(r)
+			if err != nil {
+				response.Error = err.Error()
+				return http.StatusBadRequest
+			}
 			nodes, err := orchestrator.ListNodes()
 			if err != nil {
 				response.Error = err.Error()
-			} else if nodes != nil && len(nodes) > 0 {
-				nodeNames = make([]string, 0, len(nodes))
-				for _, node := range nodes {
-					nodeNames = append(nodeNames, node.Name)
-				}
+				return httpStatusCodeForGetUpdateList(err)
+			}
+			items := make([]listItem, 0, len(nodes))
+			for _, node := range nodes {
+				items = append(items, listItem{name: node.Name})
 			}
+			nodeNames, continueToken := options.apply(items)
 			response.setList(nodeNames)
-			return httpStatusCodeForGetUpdateList(err)
+			response.Continue = continueToken
+			return http.StatusOK
 		},
 	)
 }
@@ -971,9 +1491,28 @@ func GetSnapshot(w http.ResponseWriter, r *http.Request) {
 
 type ListSnapshotsResponse struct {
 	Snapshots []string `json:"snapshots"`
+	Continue  string   `json:"continue,omitempty"`
 	Error     string   `json:"error,omitempty"`
 }
 
+var snapshotListFields = []string{"volume", "state", "created", "size"}
+
+func getSnapshotListItems(snapshots []*storage.SnapshotExternal) []listItem {
+	items := make([]listItem, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		items = append(items, listItem{
+			name: snapshot.ID(),
+			fields: map[string]string{
+				"volume":  snapshot.Config.VolumeName,
+				"state":   string(snapshot.State),
+				"created": snapshot.Created,
+				"size":    strconv.FormatInt(snapshot.SizeBytes, 10),
+			},
+		})
+	}
+	return items
+}
+
 func (l *ListSnapshotsResponse) setList(payload []string) {
 	l.Snapshots = payload
 }
@@ -982,18 +1521,21 @@ func ListSnapshots(w http.ResponseWriter, r *http.Request) {
 	response := &ListSnapshotsResponse{}
 	ListGeneric(w, r, response,
 		func() int {
-			snapshotIDs := make([]string, 0)
+			response.setList(make([]string, 0))
+			options, err := parseListOptions(r, snapshotListFields...)
+			if err != nil {
+				response.Error = err.Error()
+				return http.StatusBadRequest
+			}
 			snapshots, err := orchestrator.ListSnapshots()
 			if err != nil {
 				response.Error = err.Error()
-			} else if snapshots != nil && len(snapshots) > 0 {
-				snapshotIDs = make([]string, 0, len(snapshots))
-				for _, snapshot := range snapshots {
-					snapshotIDs = append(snapshotIDs, snapshot.ID())
-				}
+				return httpStatusCodeForGetUpdateList(err)
 			}
+			snapshotIDs, continueToken := options.apply(getSnapshotListItems(snapshots))
 			response.setList(snapshotIDs)
-			return httpStatusCodeForGetUpdateList(err)
+			response.Continue = continueToken
+			return http.StatusOK
 		},
 	)
 }
@@ -1002,18 +1544,21 @@ func ListSnapshotsForVolume(w http.ResponseWriter, r *http.Request) {
 	response := &ListSnapshotsResponse{}
 	ListGenericOneArg(w, r, "volume", response,
 		func(volumeName string) int {
-			snapshotIDs := make([]string, 0)
+			response.setList(make([]string, 0))
+			options, err := parseListOptions(r, snapshotListFields...)
+			if err != nil {
+				response.Error = err.Error()
+				return http.StatusBadRequest
+			}
 			snapshots, err := orchestrator.ListSnapshotsForVolume(volumeName)
 			if err != nil {
 				response.Error = err.Error()
-			} else if snapshots != nil && len(snapshots) > 0 {
-				snapshotIDs = make([]string, 0, len(snapshots))
-				for _, snapshot := range snapshots {
-					snapshotIDs = append(snapshotIDs, snapshot.ID())
-				}
+				return httpStatusCodeForGetUpdateList(err)
 			}
+			snapshotIDs, continueToken := options.apply(getSnapshotListItems(snapshots))
 			response.setList(snapshotIDs)
-			return httpStatusCodeForGetUpdateList(err)
+			response.Continue = continueToken
+			return http.StatusOK
 		},
 	)
 }
@@ -1054,11 +1599,14 @@ func AddSnapshot(w http.ResponseWriter, r *http.Request) {
 				response.setError(fmt.Errorf("invalid JSON: %s", err.Error()))
 				return httpStatusCodeForAdd(err)
 			}
+			setAuditName(r, snapshotConfig.ID())
+			// Snapshots created through Trident's own API have no other owner to track them
+			snapshotConfig.TridentOwned = true
 			if err := snapshotConfig.Validate(); err != nil {
 				response.setError(err)
 				return httpStatusCodeForAdd(err)
 			}
-			snapshot, err := orchestrator.CreateSnapshot(snapshotConfig)
+			snapshot, err := orchestrator.CreateSnapshot(r.Context(), snapshotConfig)
 			if err != nil {
 				response.setError(err)
 			}
@@ -1071,5 +1619,1326 @@ func AddSnapshot(w http.ResponseWriter, r *http.Request) {
 }
 
 func DeleteSnapshot(w http.ResponseWriter, r *http.Request) {
-	DeleteGenericTwoArg(w, r, orchestrator.DeleteSnapshot, "volume", "snapshot")
+	DeleteGenericTwoArg(w, r, func(volumeName, snapshotName string) error {
+		return orchestrator.DeleteSnapshot(r.Context(), volumeName, snapshotName)
+	}, "volume", "snapshot")
+}
+
+// restoreForceParam is the query parameter that forces a snapshot to be restored
+const restoreForceParam = "force"
+
+type RestoreSnapshotResponse struct {
+	Error string `json:"error,omitempty"`
+}
+
+// RestoreSnapshot reverts a volume to one of its snapshots.  The request has no body, as the volume
+// and snapshot are named by the route.  The force query parameter restores a volume that is still
+// published to nodes, for when those nodes are known to be gone.
+func RestoreSnapshot(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	response := RestoreSnapshotResponse{}
+
+	vars := mux.Vars(r)
+	volumeName := vars["volume"]
+	snapshotName := vars["snapshot"]
+
+	force := false
+	if value := r.URL.Query().Get(restoreForceParam); value != "" {
+		var err error
+		if force, err = strconv.ParseBool(value); err != nil {
+			response.Error = fmt.Sprintf("invalid %s parameter %s", restoreForceParam, value)
+			writeHTTPResponse(w, response, http.StatusBadRequest)
+			return
+		}
+	}
+
+	err := orchestrator.RestoreSnapshot(r.Context(), volumeName, snapshotName, force)
+	if err != nil {
+		response.Error = err.Error()
+		log.WithFields(log.Fields{
+			"volume":   volumeName,
+			"snapshot": snapshotName,
+			"handler":  "RestoreSnapshot",
+		}).Error(response.Error)
+	} else {
+		log.WithFields(log.Fields{
+			"volume":   volumeName,
+			"snapshot": snapshotName,
+			"force":    force,
+			"handler":  "RestoreSnapshot",
+		}).Info("Restored a volume from a snapshot.")
+	}
+
+	writeHTTPResponse(w, response, httpStatusCodeForGetUpdateList(err))
+}
+
+type SnapshotRestorePreflightResponse struct {
+	Report *storage.PreflightReport `json:"report,omitempty"`
+	Error  string                   `json:"error,omitempty"`
+}
+
+// CheckSnapshotRestore reports whether a volume may be reverted to one of its snapshots, and which
+// newer snapshots the restore could discard, without restoring it.
+func CheckSnapshotRestore(w http.ResponseWriter, r *http.Request) {
+	response := &SnapshotRestorePreflightResponse{}
+	GetGenericTwoArg(w, r, "volume", "snapshot", response,
+		func(volumeName, snapshotName string) int {
+			report, err := orchestrator.CheckSnapshotRestore(volumeName, snapshotName)
+			if err != nil {
+				response.Error = err.Error()
+			} else {
+				response.Report = report
+			}
+			return httpStatusCodeForGetUpdateList(err)
+		},
+	)
+}
+
+type AddMigrationResponse struct {
+	Migration *storage.VolumeMigrationExternal `json:"migration,omitempty"`
+	Error     string                           `json:"error,omitempty"`
+}
+
+func (r *AddMigrationResponse) setError(err error) {
+	r.Error = err.Error()
+}
+
+func (r *AddMigrationResponse) isError() bool {
+	return r.Error != ""
+}
+
+func (r *AddMigrationResponse) logSuccess() {
+	log.WithFields(log.Fields{
+		"volume":  r.Migration.VolumeName,
+		"backend": r.Migration.DestinationBackend,
+		"handler": "AddMigration",
+	}).Info("Started a volume migration.")
+}
+
+func (r *AddMigrationResponse) logFailure() {
+	log.WithFields(log.Fields{
+		"handler": "AddMigration",
+	}).Error(r.Error)
+}
+
+func AddMigration(w http.ResponseWriter, r *http.Request) {
+	response := &AddMigrationResponse{}
+	AddGeneric(w, r, response,
+		func(body []byte) int {
+			request := new(storage.VolumeMigrationRequest)
+			if err := json.Unmarshal(body, request); err != nil {
+				response.setError(fmt.Errorf("invalid JSON: %s", err.Error()))
+				return httpStatusCodeForAdd(err)
+			}
+			setAuditName(r, request.Volume)
+			if err := request.Validate(); err != nil {
+				response.setError(err)
+				return httpStatusCodeForAdd(err)
+			}
+			migration, err := orchestrator.MigrateVolume(request)
+			if err != nil {
+				response.setError(err)
+			}
+			response.Migration = migration
+			return httpStatusCodeForAdd(err)
+		},
+	)
+}
+
+const (
+	// planBackendParam and planPoolParam are the query parameters naming the destination of a planned migration
+	planBackendParam = "backend"
+	planPoolParam    = "pool"
+)
+
+type MigrationPlanResponse struct {
+	Plan  *storage.VolumeMigrationPlan `json:"plan,omitempty"`
+	Error string                       `json:"error,omitempty"`
+}
+
+// PlanMigration reports how a volume would be migrated to the backend and storage pool named by the query
+// parameters, and whether the migration may proceed, without migrating it.
+func PlanMigration(w http.ResponseWriter, r *http.Request) {
+	response := &MigrationPlanResponse{}
+	GetGeneric(w, r, "volume", response,
+		func(volumeName string) int {
+			plan, err := orchestrator.PlanVolumeMigration(&storage.VolumeMigrationRequest{
+				Volume:      volumeName,
+				Backend:     r.URL.Query().Get(planBackendParam),
+				StoragePool: r.URL.Query().Get(planPoolParam),
+			})
+			if err != nil {
+				response.Error = err.Error()
+			} else {
+				response.Plan = plan
+			}
+			return httpStatusCodeForGetUpdateList(err)
+		},
+	)
+}
+
+type GetMigrationResponse struct {
+	Migration *storage.VolumeMigrationExternal `json:"migration"`
+	Error     string                           `json:"error,omitempty"`
+}
+
+func GetMigration(w http.ResponseWriter, r *http.Request) {
+	response := &GetMigrationResponse{}
+	GetGeneric(w, r, "volume", response,
+		func(volumeName string) int {
+			migration, err := orchestrator.GetVolumeMigration(volumeName)
+			if err != nil {
+				response.Error = err.Error()
+			} else {
+				response.Migration = migration
+			}
+			return httpStatusCodeForGetUpdateList(err)
+		},
+	)
+}
+
+type ListMigrationsResponse struct {
+	Migrations []string `json:"migrations"`
+	Error      string   `json:"error,omitempty"`
+}
+
+func (l *ListMigrationsResponse) setList(payload []string) {
+	l.Migrations = payload
+}
+
+func ListMigrations(w http.ResponseWriter, r *http.Request) {
+	response := &ListMigrationsResponse{}
+	ListGeneric(w, r, response,
+		func() int {
+			volumeNames := make([]string, 0)
+			migrations, err := orchestrator.ListVolumeMigrations()
+			if err != nil {
+				response.Error = err.Error()
+			} else {
+				for _, migration := range migrations {
+					volumeNames = append(volumeNames, migration.VolumeName)
+				}
+			}
+			response.setList(volumeNames)
+			return httpStatusCodeForGetUpdateList(err)
+		},
+	)
+}
+
+type UpdateVolumeUsageResponse struct {
+	Volume string `json:"volume"`
+	Error  string `json:"error,omitempty"`
+}
+
+func (r *UpdateVolumeUsageResponse) setError(err error) {
+	r.Error = err.Error()
+}
+
+func (r *UpdateVolumeUsageResponse) isError() bool {
+	return r.Error != ""
+}
+
+func (r *UpdateVolumeUsageResponse) logSuccess() {
+	log.WithFields(log.Fields{
+		"volume":  r.Volume,
+		"handler": "UpdateVolumeUsage",
+	}).Debug("Updated volume usage.")
+}
+
+func (r *UpdateVolumeUsageResponse) logFailure() {
+	log.WithFields(log.Fields{
+		"volume":  r.Volume,
+		"handler": "UpdateVolumeUsage",
+	}).Error(r.Error)
+}
+
+func UpdateVolumeUsage(w http.ResponseWriter, r *http.Request) {
+	response := &UpdateVolumeUsageResponse{}
+	UpdateGeneric(w, r, "volume", response,
+		func(volumeName string, body []byte) int {
+			response.Volume = volumeName
+			usage := new(storage.VolumeUsage)
+			if err := json.Unmarshal(body, usage); err != nil {
+				response.setError(fmt.Errorf("invalid JSON: %s", err.Error()))
+				return httpStatusCodeForGetUpdateList(err)
+			}
+			usage.VolumeName = volumeName
+			err := orchestrator.UpdateVolumeUsage(usage)
+			if err != nil {
+				response.setError(err)
+			}
+			return httpStatusCodeForGetUpdateList(err)
+		},
+	)
+}
+
+type GetVolumeUsageResponse struct {
+	Usage *storage.VolumeUsage `json:"usage"`
+	Error string               `json:"error,omitempty"`
+}
+
+func GetVolumeUsage(w http.ResponseWriter, r *http.Request) {
+	response := &GetVolumeUsageResponse{}
+	GetGeneric(w, r, "volume", response,
+		func(volumeName string) int {
+			usage, err := orchestrator.GetVolumeUsage(volumeName)
+			if err != nil {
+				response.Error = err.Error()
+			} else {
+				response.Usage = usage
+			}
+			return httpStatusCodeForGetUpdateList(err)
+		},
+	)
+}
+
+type UpdateVolumeConditionResponse struct {
+	Volume string `json:"volume"`
+	Error  string `json:"error,omitempty"`
+}
+
+func (r *UpdateVolumeConditionResponse) setError(err error) {
+	r.Error = err.Error()
+}
+
+func (r *UpdateVolumeConditionResponse) isError() bool {
+	return r.Error != ""
+}
+
+func (r *UpdateVolumeConditionResponse) logSuccess() {
+	log.WithFields(log.Fields{
+		"volume":  r.Volume,
+		"handler": "UpdateVolumeCondition",
+	}).Debug("Updated volume condition.")
+}
+
+func (r *UpdateVolumeConditionResponse) logFailure() {
+	log.WithFields(log.Fields{
+		"volume":  r.Volume,
+		"handler": "UpdateVolumeCondition",
+	}).Error(r.Error)
+}
+
+func UpdateVolumeCondition(w http.ResponseWriter, r *http.Request) {
+	response := &UpdateVolumeConditionResponse{}
+	UpdateGeneric(w, r, "volume", response,
+		func(volumeName string, body []byte) int {
+			response.Volume = volumeName
+			condition := new(storage.VolumeCondition)
+			if err := json.Unmarshal(body, condition); err != nil {
+				response.setError(fmt.Errorf("invalid JSON: %s", err.Error()))
+				return httpStatusCodeForGetUpdateList(err)
+			}
+			condition.VolumeName = volumeName
+			err := orchestrator.UpdateVolumeCondition(condition)
+			if err != nil {
+				response.setError(err)
+			}
+			return httpStatusCodeForGetUpdateList(err)
+		},
+	)
+}
+
+type GetVolumeConditionResponse struct {
+	Condition *storage.VolumeCondition `json:"condition"`
+	Error     string                   `json:"error,omitempty"`
+}
+
+func GetVolumeCondition(w http.ResponseWriter, r *http.Request) {
+	response := &GetVolumeConditionResponse{}
+	GetGeneric(w, r, "volume", response,
+		func(volumeName string) int {
+			condition, err := orchestrator.GetVolumeCondition(volumeName)
+			if err != nil {
+				response.Error = err.Error()
+			} else {
+				response.Condition = condition
+			}
+			return httpStatusCodeForGetUpdateList(err)
+		},
+	)
+}
+
+// ListVolumeConditionsResponse lists the volumes whose condition is abnormal.
+type ListVolumeConditionsResponse struct {
+	Volumes []string `json:"volumes"`
+	Error   string   `json:"error,omitempty"`
+}
+
+func (l *ListVolumeConditionsResponse) setList(payload []string) {
+	l.Volumes = payload
+}
+
+func ListVolumeConditions(w http.ResponseWriter, r *http.Request) {
+	response := &ListVolumeConditionsResponse{}
+	ListGeneric(w, r, response,
+		func() int {
+			volumeNames := make([]string, 0)
+			conditions, err := orchestrator.ListVolumeConditions()
+			if err != nil {
+				response.Error = err.Error()
+			} else {
+				for _, condition := range conditions {
+					if condition.Abnormal {
+						volumeNames = append(volumeNames, condition.VolumeName)
+					}
+				}
+			}
+			response.setList(volumeNames)
+			return httpStatusCodeForGetUpdateList(err)
+		},
+	)
+}
+
+type ListVolumeUsageResponse struct {
+	Volumes []string `json:"volumes"`
+	Error   string   `json:"error,omitempty"`
+}
+
+func (l *ListVolumeUsageResponse) setList(payload []string) {
+	l.Volumes = payload
+}
+
+func ListVolumeUsage(w http.ResponseWriter, r *http.Request) {
+	response := &ListVolumeUsageResponse{}
+	ListGeneric(w, r, response,
+		func() int {
+			volumeNames := make([]string, 0)
+			usage, err := orchestrator.ListVolumeUsage()
+			if err != nil {
+				response.Error = err.Error()
+			} else {
+				for _, volumeUsage := range usage {
+					volumeNames = append(volumeNames, volumeUsage.VolumeName)
+				}
+			}
+			response.setList(volumeNames)
+			return httpStatusCodeForGetUpdateList(err)
+		},
+	)
+}
+
+type DriftReportResponse struct {
+	Report *storage.DriftReport `json:"report"`
+	Error  string               `json:"error,omitempty"`
+}
+
+func (r *DriftReportResponse) setError(err error) {
+	r.Error = err.Error()
+}
+
+func (r *DriftReportResponse) isError() bool {
+	return r.Error != ""
+}
+
+func (r *DriftReportResponse) logSuccess() {
+	log.WithFields(log.Fields{
+		"drifts":  len(r.Report.Drifts),
+		"handler": "DetectDrift",
+	}).Info("Detected drift.")
+}
+
+func (r *DriftReportResponse) logFailure() {
+	log.WithFields(log.Fields{
+		"handler": "DetectDrift",
+	}).Error(r.Error)
+}
+
+func DetectDrift(w http.ResponseWriter, r *http.Request) {
+	response := &DriftReportResponse{}
+	AddGeneric(w, r, response,
+		func(body []byte) int {
+			report, err := orchestrator.DetectDrift()
+			if err != nil {
+				response.setError(err)
+			}
+			response.Report = report
+			return httpStatusCodeForAdd(err)
+		},
+	)
+}
+
+func GetDriftReport(w http.ResponseWriter, r *http.Request) {
+	response := &DriftReportResponse{}
+	GetGenericNoArg(w, r, response,
+		func() int {
+			report, err := orchestrator.GetDriftReport()
+			if err != nil {
+				response.Error = err.Error()
+			} else {
+				response.Report = report
+			}
+			return httpStatusCodeForGetUpdateList(err)
+		},
+	)
+}
+
+type GarbageReportResponse struct {
+	Report *storage.GarbageReport `json:"report"`
+	Error  string                 `json:"error,omitempty"`
+}
+
+func (r *GarbageReportResponse) setError(err error) {
+	r.Error = err.Error()
+}
+
+func (r *GarbageReportResponse) isError() bool {
+	return r.Error != ""
+}
+
+func (r *GarbageReportResponse) logSuccess() {
+	log.WithFields(log.Fields{
+		"garbage": len(r.Report.Garbage),
+		"handler": "CollectGarbage",
+	}).Info("Collected garbage.")
+}
+
+func (r *GarbageReportResponse) logFailure() {
+	log.WithFields(log.Fields{
+		"handler": "CollectGarbage",
+	}).Error(r.Error)
+}
+
+func CollectGarbage(w http.ResponseWriter, r *http.Request) {
+	response := &GarbageReportResponse{}
+	AddGeneric(w, r, response,
+		func(body []byte) int {
+			report, err := orchestrator.CollectGarbage()
+			if err != nil {
+				response.setError(err)
+			}
+			response.Report = report
+			return httpStatusCodeForAdd(err)
+		},
+	)
+}
+
+func GetGarbageReport(w http.ResponseWriter, r *http.Request) {
+	response := &GarbageReportResponse{}
+	GetGenericNoArg(w, r, response,
+		func() int {
+			report, err := orchestrator.GetGarbageReport()
+			if err != nil {
+				response.Error = err.Error()
+			} else {
+				response.Report = report
+			}
+			return httpStatusCodeForGetUpdateList(err)
+		},
+	)
+}
+
+type GetDeletionResponse struct {
+	Deletion *storage.VolumeDeletion `json:"deletion"`
+	Error    string                  `json:"error,omitempty"`
+}
+
+func GetDeletion(w http.ResponseWriter, r *http.Request) {
+	response := &GetDeletionResponse{}
+	GetGeneric(w, r, "volume", response,
+		func(volumeName string) int {
+			deletion, err := orchestrator.GetVolumeDeletion(volumeName)
+			if err != nil {
+				response.Error = err.Error()
+			} else {
+				response.Deletion = deletion
+			}
+			return httpStatusCodeForGetUpdateList(err)
+		},
+	)
+}
+
+type ListDeletionsResponse struct {
+	Deletions []string `json:"deletions"`
+	Error     string   `json:"error,omitempty"`
+}
+
+func (l *ListDeletionsResponse) setList(payload []string) {
+	l.Deletions = payload
+}
+
+func ListDeletions(w http.ResponseWriter, r *http.Request) {
+	response := &ListDeletionsResponse{}
+	ListGeneric(w, r, response,
+		func() int {
+			volumeNames := make([]string, 0)
+			deletions, err := orchestrator.ListVolumeDeletions()
+			if err != nil {
+				response.Error = err.Error()
+			} else {
+				for _, deletion := range deletions {
+					volumeNames = append(volumeNames, deletion.VolumeName)
+				}
+			}
+			response.setList(volumeNames)
+			return httpStatusCodeForGetUpdateList(err)
+		},
+	)
+}
+
+type VolumeGroupResponse struct {
+	VolumeGroup *storage.VolumeGroupExternal `json:"volumeGroup,omitempty"`
+	Error       string                       `json:"error,omitempty"`
+	handler     string
+}
+
+func (r *VolumeGroupResponse) setError(err error) {
+	r.Error = err.Error()
+}
+
+func (r *VolumeGroupResponse) isError() bool {
+	return r.Error != ""
+}
+
+func (r *VolumeGroupResponse) logSuccess() {
+	log.WithFields(log.Fields{
+		"volumeGroup": r.VolumeGroup.Name,
+		"volumes":     r.VolumeGroup.Volumes,
+		"handler":     r.handler,
+	}).Info("Updated a volume group.")
+}
+
+func (r *VolumeGroupResponse) logFailure() {
+	log.WithFields(log.Fields{
+		"handler": r.handler,
+	}).Error(r.Error)
+}
+
+func AddVolumeGroup(w http.ResponseWriter, r *http.Request) {
+	response := &VolumeGroupResponse{handler: "AddVolumeGroup"}
+	AddGeneric(w, r, response,
+		func(body []byte) int {
+			groupConfig := new(storage.VolumeGroupConfig)
+			if err := json.Unmarshal(body, groupConfig); err != nil {
+				response.setError(fmt.Errorf("invalid JSON: %s", err.Error()))
+				return httpStatusCodeForAdd(err)
+			}
+			setAuditName(r, groupConfig.Name)
+			volumeGroup, err := orchestrator.AddVolumeGroup(groupConfig)
+			if err != nil {
+				response.setError(err)
+			}
+			response.VolumeGroup = volumeGroup
+			return httpStatusCodeForAdd(err)
+		},
+	)
+}
+
+func UpdateVolumeGroup(w http.ResponseWriter, r *http.Request) {
+	response := &VolumeGroupResponse{handler: "UpdateVolumeGroup"}
+	UpdateGeneric(w, r, "group", response,
+		func(groupName string, body []byte) int {
+			groupConfig := new(storage.VolumeGroupConfig)
+			if err := json.Unmarshal(body, groupConfig); err != nil {
+				response.setError(fmt.Errorf("invalid JSON: %s", err.Error()))
+				return httpStatusCodeForGetUpdateList(err)
+			}
+			groupConfig.Name = groupName
+			volumeGroup, err := orchestrator.UpdateVolumeGroup(groupConfig)
+			if err != nil {
+				response.setError(err)
+			}
+			response.VolumeGroup = volumeGroup
+			return httpStatusCodeForGetUpdateList(err)
+		},
+	)
+}
+
+func CloneVolumeGroup(w http.ResponseWriter, r *http.Request) {
+	response := &VolumeGroupResponse{handler: "CloneVolumeGroup"}
+	UpdateGeneric(w, r, "group", response,
+		func(groupName string, body []byte) int {
+			request := new(storage.VolumeGroupCloneRequest)
+			if err := json.Unmarshal(body, request); err != nil {
+				response.setError(fmt.Errorf("invalid JSON: %s", err.Error()))
+				return httpStatusCodeForAdd(err)
+			}
+			volumeGroup, err := orchestrator.CloneVolumeGroup(r.Context(), groupName, request)
+			if err != nil {
+				response.setError(err)
+			}
+			response.VolumeGroup = volumeGroup
+			return httpStatusCodeForAdd(err)
+		},
+	)
+}
+
+type GetVolumeGroupResponse struct {
+	VolumeGroup *storage.VolumeGroupExternal `json:"volumeGroup"`
+	Error       string                       `json:"error,omitempty"`
+}
+
+func GetVolumeGroup(w http.ResponseWriter, r *http.Request) {
+	response := &GetVolumeGroupResponse{}
+	GetGeneric(w, r, "group", response,
+		func(groupName string) int {
+			volumeGroup, err := orchestrator.GetVolumeGroup(groupName)
+			if err != nil {
+				response.Error = err.Error()
+			} else {
+				response.VolumeGroup = volumeGroup
+			}
+			return httpStatusCodeForGetUpdateList(err)
+		},
+	)
+}
+
+type ListVolumeGroupsResponse struct {
+	VolumeGroups []string `json:"volumeGroups"`
+	Error        string   `json:"error,omitempty"`
+}
+
+func (l *ListVolumeGroupsResponse) setList(payload []string) {
+	l.VolumeGroups = payload
+}
+
+func ListVolumeGroups(w http.ResponseWriter, r *http.Request) {
+	response := &ListVolumeGroupsResponse{}
+	ListGeneric(w, r, response,
+		func() int {
+			groupNames := make([]string, 0)
+			volumeGroups, err := orchestrator.ListVolumeGroups()
+			if err != nil {
+				response.Error = err.Error()
+			} else {
+				for _, volumeGroup := range volumeGroups {
+					groupNames = append(groupNames, volumeGroup.Name)
+				}
+			}
+			response.setList(groupNames)
+			return httpStatusCodeForGetUpdateList(err)
+		},
+	)
+}
+
+func DeleteVolumeGroup(w http.ResponseWriter, r *http.Request) {
+	DeleteGeneric(w, r, orchestrator.DeleteVolumeGroup, "group")
+}
+
+type AddVolumeGroupSnapshotResponse struct {
+	Snapshot *storage.VolumeGroupSnapshot `json:"snapshot,omitempty"`
+	Error    string                       `json:"error,omitempty"`
+}
+
+func (r *AddVolumeGroupSnapshotResponse) setError(err error) {
+	r.Error = err.Error()
+}
+
+func (r *AddVolumeGroupSnapshotResponse) isError() bool {
+	return r.Error != ""
+}
+
+func (r *AddVolumeGroupSnapshotResponse) logSuccess() {
+	log.WithFields(log.Fields{
+		"volumeGroup": r.Snapshot.VolumeGroup,
+		"snapshot":    r.Snapshot.Name,
+		"handler":     "AddVolumeGroupSnapshot",
+	}).Info("Created a volume group snapshot.")
+}
+
+func (r *AddVolumeGroupSnapshotResponse) logFailure() {
+	log.WithFields(log.Fields{
+		"handler": "AddVolumeGroupSnapshot",
+	}).Error(r.Error)
+}
+
+func AddVolumeGroupSnapshot(w http.ResponseWriter, r *http.Request) {
+	response := &AddVolumeGroupSnapshotResponse{}
+	UpdateGeneric(w, r, "group", response,
+		func(groupName string, body []byte) int {
+			request := new(storage.VolumeGroupSnapshotRequest)
+			if err := json.Unmarshal(body, request); err != nil {
+				response.setError(fmt.Errorf("invalid JSON: %s", err.Error()))
+				return httpStatusCodeForAdd(err)
+			}
+			request.TridentOwned = true
+			snapshot, err := orchestrator.CreateVolumeGroupSnapshot(r.Context(), groupName, request)
+			if err != nil {
+				response.setError(err)
+			}
+			response.Snapshot = snapshot
+			return httpStatusCodeForAdd(err)
+		},
+	)
+}
+
+type AddCloneGrantResponse struct {
+	CloneGrant *storage.CloneGrant `json:"cloneGrant,omitempty"`
+	Error      string              `json:"error,omitempty"`
+}
+
+func (r *AddCloneGrantResponse) setError(err error) {
+	r.Error = err.Error()
+}
+
+func (r *AddCloneGrantResponse) isError() bool {
+	return r.Error != ""
+}
+
+func (r *AddCloneGrantResponse) logSuccess() {
+	log.WithFields(log.Fields{
+		"cloneGrant":       r.CloneGrant.ID(),
+		"volumes":          r.CloneGrant.Volumes,
+		"snapshots":        r.CloneGrant.Snapshots,
+		"targetNamespaces": r.CloneGrant.TargetNamespaces,
+		"handler":          "AddCloneGrant",
+	}).Info("Added a clone grant.")
+}
+
+func (r *AddCloneGrantResponse) logFailure() {
+	log.WithFields(log.Fields{
+		"handler": "AddCloneGrant",
+	}).Error(r.Error)
+}
+
+func AddCloneGrant(w http.ResponseWriter, r *http.Request) {
+	response := &AddCloneGrantResponse{}
+	AddGeneric(w, r, response,
+		func(body []byte) int {
+			grant := new(storage.CloneGrant)
+			if err := json.Unmarshal(body, grant); err != nil {
+				response.setError(fmt.Errorf("invalid JSON: %s", err.Error()))
+				return httpStatusCodeForAdd(err)
+			}
+			setAuditName(r, grant.ID())
+			err := orchestrator.AddCloneGrant(grant)
+			if err != nil {
+				response.setError(err)
+			} else {
+				response.CloneGrant = grant
+			}
+			return httpStatusCodeForAdd(err)
+		},
+	)
+}
+
+type GetCloneGrantResponse struct {
+	CloneGrant *storage.CloneGrant `json:"cloneGrant"`
+	Error      string              `json:"error,omitempty"`
+}
+
+func GetCloneGrant(w http.ResponseWriter, r *http.Request) {
+	response := &GetCloneGrantResponse{}
+	GetGenericTwoArg(w, r, "namespace", "grant", response,
+		func(namespace, grantName string) int {
+			grant, err := orchestrator.GetCloneGrant(namespace, grantName)
+			if err != nil {
+				response.Error = err.Error()
+			} else {
+				response.CloneGrant = grant
+			}
+			return httpStatusCodeForGetUpdateList(err)
+		},
+	)
+}
+
+type ListCloneGrantsResponse struct {
+	CloneGrants []string `json:"cloneGrants"`
+	Error       string   `json:"error,omitempty"`
+}
+
+func (l *ListCloneGrantsResponse) setList(payload []string) {
+	l.CloneGrants = payload
+}
+
+func ListCloneGrants(w http.ResponseWriter, r *http.Request) {
+	response := &ListCloneGrantsResponse{}
+	ListGeneric(w, r, response,
+		func() int {
+			grantIDs := make([]string, 0)
+			grants, err := orchestrator.ListCloneGrants()
+			if err != nil {
+				response.Error = err.Error()
+			} else {
+				for _, grant := range grants {
+					grantIDs = append(grantIDs, grant.ID())
+				}
+			}
+			response.setList(grantIDs)
+			return httpStatusCodeForGetUpdateList(err)
+		},
+	)
+}
+
+func DeleteCloneGrant(w http.ResponseWriter, r *http.Request) {
+	DeleteGenericTwoArg(w, r, orchestrator.DeleteCloneGrant, "namespace", "grant")
+}
+
+type AddQuotaResponse struct {
+	Quota *storage.Quota `json:"quota,omitempty"`
+	Error string         `json:"error,omitempty"`
+}
+
+func (r *AddQuotaResponse) setError(err error) {
+	r.Error = err.Error()
+}
+
+func (r *AddQuotaResponse) isError() bool {
+	return r.Error != ""
+}
+
+func (r *AddQuotaResponse) logSuccess() {
+	log.WithFields(log.Fields{
+		"quota":        r.Quota.ID(),
+		"storageClass": r.Quota.StorageClass,
+		"maxBytes":     r.Quota.MaxBytes,
+		"maxVolumes":   r.Quota.MaxVolumes,
+		"handler":      "AddQuota",
+	}).Info("Added a quota.")
+}
+
+func (r *AddQuotaResponse) logFailure() {
+	log.WithFields(log.Fields{
+		"handler": "AddQuota",
+	}).Error(r.Error)
+}
+
+func AddQuota(w http.ResponseWriter, r *http.Request) {
+	response := &AddQuotaResponse{}
+	AddGeneric(w, r, response,
+		func(body []byte) int {
+			quota := new(storage.Quota)
+			if err := json.Unmarshal(body, quota); err != nil {
+				response.setError(fmt.Errorf("invalid JSON: %s", err.Error()))
+				return httpStatusCodeForAdd(err)
+			}
+			setAuditName(r, quota.ID())
+			err := orchestrator.AddQuota(quota)
+			if err != nil {
+				response.setError(err)
+			} else {
+				response.Quota = quota
+			}
+			return httpStatusCodeForAdd(err)
+		},
+	)
+}
+
+type GetQuotaResponse struct {
+	Quota *storage.QuotaExternal `json:"quota"`
+	Error string                 `json:"error,omitempty"`
+}
+
+func GetQuota(w http.ResponseWriter, r *http.Request) {
+	response := &GetQuotaResponse{}
+	GetGenericTwoArg(w, r, "namespace", "quota", response,
+		func(namespace, quotaName string) int {
+			quota, err := orchestrator.GetQuota(namespace, quotaName)
+			if err != nil {
+				response.Error = err.Error()
+			} else {
+				response.Quota = quota
+			}
+			return httpStatusCodeForGetUpdateList(err)
+		},
+	)
+}
+
+type ListQuotasResponse struct {
+	Quotas []string `json:"quotas"`
+	Error  string   `json:"error,omitempty"`
+}
+
+func (l *ListQuotasResponse) setList(payload []string) {
+	l.Quotas = payload
+}
+
+func ListQuotas(w http.ResponseWriter, r *http.Request) {
+	response := &ListQuotasResponse{}
+	ListGeneric(w, r, response,
+		func() int {
+			quotaIDs := make([]string, 0)
+			quotas, err := orchestrator.ListQuotas()
+			if err != nil {
+				response.Error = err.Error()
+			} else {
+				for _, quota := range quotas {
+					quotaIDs = append(quotaIDs, quota.ID())
+				}
+			}
+			response.setList(quotaIDs)
+			return httpStatusCodeForGetUpdateList(err)
+		},
+	)
+}
+
+func DeleteQuota(w http.ResponseWriter, r *http.Request) {
+	DeleteGenericTwoArg(w, r, orchestrator.DeleteQuota, "namespace", "quota")
+}
+
+type ExportStateResponse struct {
+	State *persistentstore.StateBundle `json:"state"`
+	Error string                       `json:"error,omitempty"`
+}
+
+func ExportState(w http.ResponseWriter, r *http.Request) {
+	response := &ExportStateResponse{}
+	GetGenericNoArg(w, r, response,
+		func() int {
+			bundle, err := orchestrator.ExportState()
+			if err != nil {
+				response.Error = err.Error()
+			} else {
+				response.State = bundle
+			}
+			return httpStatusCodeForGetUpdateList(err)
+		},
+	)
+}
+
+type ImportStateResponse struct {
+	Result *persistentstore.StateImportResult `json:"result,omitempty"`
+	Error  string                             `json:"error,omitempty"`
+}
+
+func (r *ImportStateResponse) setError(err error) {
+	r.Error = err.Error()
+}
+
+func (r *ImportStateResponse) isError() bool {
+	return r.Error != ""
+}
+
+func (r *ImportStateResponse) logSuccess() {
+	log.WithFields(log.Fields{
+		"addedBackends":   len(r.Result.AddedBackends),
+		"reboundBackends": len(r.Result.ReboundBackends),
+		"volumes":         len(r.Result.Volumes),
+		"snapshots":       len(r.Result.Snapshots),
+		"handler":         "ImportState",
+	}).Info("Imported state.")
+}
+
+func (r *ImportStateResponse) logFailure() {
+	log.WithFields(log.Fields{
+		"handler": "ImportState",
+	}).Error(r.Error)
+}
+
+func ImportState(w http.ResponseWriter, r *http.Request) {
+	response := &ImportStateResponse{}
+	AddGeneric(w, r, response,
+		func(body []byte) int {
+			bundle := new(persistentstore.StateBundle)
+			if err := json.Unmarshal(body, bundle); err != nil {
+				response.setError(fmt.Errorf("invalid JSON: %s", err.Error()))
+				return httpStatusCodeForAdd(err)
+			}
+			result, err := orchestrator.ImportState(bundle)
+			if err != nil {
+				response.setError(err)
+			}
+			response.Result = result
+			return httpStatusCodeForAdd(err)
+		},
+	)
+}
+
+type StateVerificationResponse struct {
+	Verification *storage.StateVerification `json:"verification"`
+	Error        string                     `json:"error,omitempty"`
+}
+
+func (r *StateVerificationResponse) setError(err error) {
+	r.Error = err.Error()
+}
+
+func (r *StateVerificationResponse) isError() bool {
+	return r.Error != ""
+}
+
+func (r *StateVerificationResponse) logSuccess() {
+	log.WithFields(log.Fields{
+		"issues":  len(r.Verification.Issues),
+		"errors":  len(r.Verification.Errors),
+		"handler": "VerifyState",
+	}).Info("Verified state.")
+}
+
+func (r *StateVerificationResponse) logFailure() {
+	log.WithFields(log.Fields{
+		"handler": "VerifyState",
+	}).Error(r.Error)
+}
+
+func VerifyState(w http.ResponseWriter, r *http.Request) {
+	response := &StateVerificationResponse{}
+	AddGeneric(w, r, response,
+		func(body []byte) int {
+			request := new(storage.StateVerifyRequest)
+			if len(body) > 0 {
+				if err := json.Unmarshal(body, request); err != nil {
+					response.setError(fmt.Errorf("invalid JSON: %s", err.Error()))
+					return http.StatusBadRequest
+				}
+			}
+			repair, err := storage.ParseStateRepair(string(request.Repair))
+			if err != nil {
+				response.setError(err)
+				return http.StatusBadRequest
+			}
+			verification, err := orchestrator.VerifyState(repair)
+			if err != nil {
+				response.setError(err)
+			}
+			response.Verification = verification
+			return httpStatusCodeForAdd(err)
+		},
+	)
+}
+
+type UpgradePreflightResponse struct {
+	Report *storage.PreflightReport `json:"report"`
+	Error  string                   `json:"error,omitempty"`
+}
+
+func GetUpgradePreflight(w http.ResponseWriter, r *http.Request) {
+	response := &UpgradePreflightResponse{}
+	GetGenericNoArg(w, r, response,
+		func() int {
+			report, err := orchestrator.CheckUpgradeReadiness()
+			if err != nil {
+				response.Error = err.Error()
+			} else {
+				response.Report = report
+			}
+			return httpStatusCodeForGetUpdateList(err)
+		},
+	)
+}
+
+type GetHealthResponse struct {
+	Report *storage.HealthReport `json:"report"`
+	Error  string                `json:"error,omitempty"`
+}
+
+// GetHealth reports the health of Trident and its backends.  A Trident that is running but can't
+// reach a backend is degraded, not failed, so the response is only an error if Trident failed.
+func GetHealth(w http.ResponseWriter, r *http.Request) {
+	response := &GetHealthResponse{}
+	GetGenericNoArg(w, r, response,
+		func() int {
+			report, err := orchestrator.GetHealth()
+			if err != nil {
+				response.Error = err.Error()
+				return httpStatusCodeForGetUpdateList(err)
+			}
+			response.Report = report
+			if report.Status == storage.HealthStatusFailed {
+				response.Error = report.Error
+				return http.StatusServiceUnavailable
+			}
+			return http.StatusOK
+		},
+	)
+}
+
+type LogLevelRequest struct {
+	LogLevel string `json:"logLevel"`
+}
+
+type LogLevelResponse struct {
+	LogLevel string `json:"logLevel"`
+	Error    string `json:"error,omitempty"`
+}
+
+func (r *LogLevelResponse) setError(err error) {
+	r.Error = err.Error()
+}
+
+func (r *LogLevelResponse) isError() bool {
+	return r.Error != ""
+}
+
+func (r *LogLevelResponse) logSuccess() {
+	log.WithFields(log.Fields{
+		"handler":  "SetLogLevel",
+		"logLevel": r.LogLevel,
+	}).Info("Set log level.")
+}
+
+func (r *LogLevelResponse) logFailure() {
+	log.WithFields(log.Fields{
+		"handler": "SetLogLevel",
+	}).Error(r.Error)
+}
+
+func GetLogLevel(w http.ResponseWriter, r *http.Request) {
+	response := &LogLevelResponse{}
+	GetGenericNoArg(w, r, response,
+		func() int {
+			response.LogLevel = log.GetLevel().String()
+			return http.StatusOK
+		},
+	)
+}
+
+func SetLogLevel(w http.ResponseWriter, r *http.Request) {
+	response := &LogLevelResponse{}
+	UpdateGeneric(w, r, "", response,
+		func(_ string, body []byte) int {
+			request := new(LogLevelRequest)
+			if err := json.Unmarshal(body, request); err != nil {
+				response.setError(fmt.Errorf("invalid JSON: %s", err.Error()))
+				return http.StatusBadRequest
+			}
+			if err := logging.InitLogLevel(false, request.LogLevel); err != nil {
+				response.setError(err)
+				return http.StatusBadRequest
+			}
+			response.LogLevel = log.GetLevel().String()
+			return http.StatusOK
+		},
+	)
+}
+
+type AddEphemeralVolumeResponse struct {
+	Volume *storage.EphemeralVolume `json:"ephemeralVolume,omitempty"`
+	Error  string                   `json:"error,omitempty"`
+}
+
+func (r *AddEphemeralVolumeResponse) setError(err error) {
+	r.Error = err.Error()
+}
+
+func (r *AddEphemeralVolumeResponse) isError() bool {
+	return r.Error != ""
+}
+
+func (r *AddEphemeralVolumeResponse) logSuccess() {
+	log.WithFields(log.Fields{
+		"handler": "AddEphemeralVolume",
+		"volume":  r.Volume.Volume.Config.Name,
+		"node":    r.Volume.Volume.Config.EphemeralNode,
+	}).Info("Added an ephemeral volume.")
+}
+
+func (r *AddEphemeralVolumeResponse) logFailure() {
+	log.WithFields(log.Fields{
+		"handler": "AddEphemeralVolume",
+	}).Error(r.Error)
+}
+
+// AddEphemeralVolume creates a CSI inline ephemeral volume for a node and publishes it to that node, so the
+// node can attach it without waiting for an external provisioner.  Repeating the request for a volume that
+// already exists on the same node publishes it again.
+func AddEphemeralVolume(w http.ResponseWriter, r *http.Request) {
+	response := &AddEphemeralVolumeResponse{}
+	UpdateGeneric(w, r, "volume", response,
+		func(volumeName string, body []byte) int {
+			request := new(storage.EphemeralVolumeRequest)
+			if err := json.Unmarshal(body, request); err != nil {
+				response.setError(fmt.Errorf("invalid JSON: %s", err.Error()))
+				return httpStatusCodeForAdd(err)
+			}
+			request.Name = volumeName
+			if err := request.Validate(); err != nil {
+				response.setError(err)
+				return http.StatusBadRequest
+			}
+			ephemeralVolume, err := addEphemeralVolume(r, request)
+			if err != nil {
+				response.setError(err)
+			} else {
+				response.Volume = ephemeralVolume
+			}
+			return httpStatusCodeForAdd(err)
+		},
+	)
+}
+
+func addEphemeralVolume(r *http.Request, request *storage.EphemeralVolumeRequest) (*storage.EphemeralVolume, error) {
+
+	node, err := orchestrator.GetNode(request.Node)
+	if err != nil {
+		return nil, err
+	}
+
+	volume, err := orchestrator.GetVolume(request.Name)
+	if err != nil && !utils.IsNotFoundError(err) {
+		return nil, err
+	}
+	created := false
+	if volume == nil {
+		volConfig, err := request.VolumeConfig()
+		if err != nil {
+			return nil, err
+		}
+		if volume, err = orchestrator.AddVolume(r.Context(), volConfig); err != nil {
+			return nil, err
+		}
+		created = true
+	} else if volume.Config.EphemeralNode != request.Node {
+		return nil, fmt.Errorf("volume %s is not an ephemeral volume of node %s", request.Name, request.Node)
+	}
+
+	publishInfo := &utils.VolumePublishInfo{
+		HostIQN:  []string{node.IQN},
+		HostIP:   node.IPs,
+		HostName: node.Name,
+	}
+	if err = orchestrator.PublishVolume(r.Context(), volume.Config.Name, publishInfo); err != nil {
+		// A volume nobody can use would only leak, so remove one created for this request
+		if created {
+			if deleteErr := orchestrator.DeleteVolume(r.Context(), volume.Config.Name); deleteErr != nil {
+				log.WithFields(log.Fields{
+					"volume": volume.Config.Name,
+					"error":  deleteErr,
+				}).Error("Could not delete ephemeral volume that failed to publish.")
+			}
+		}
+		return nil, err
+	}
+
+	return &storage.EphemeralVolume{
+		Volume:      volume,
+		PublishInfo: getEphemeralNodePublishInfo(volume, publishInfo, request.MountOptions),
+	}, nil
+}
+
+// getEphemeralNodePublishInfo assembles what a node needs to attach a volume, as the CSI controller would
+// send it in a publish context.
+func getEphemeralNodePublishInfo(
+	volume *storage.VolumeExternal, publishInfo *utils.VolumePublishInfo, mountOptions string,
+) *utils.VolumePublishInfo {
+
+	nodePublishInfo := &utils.VolumePublishInfo{
+		Localhost:        true,
+		VolumeAccessInfo: volume.Config.AccessInfo,
+	}
+	nodePublishInfo.MountOptions = publishInfo.MountOptions
+	if mountOptions != "" {
+		nodePublishInfo.MountOptions = mountOptions
+	}
+
+	switch volume.Config.Protocol {
+	case config.File:
+		nodePublishInfo.FilesystemType = "nfs"
+	case config.Block:
+		nodePublishInfo.FilesystemType = publishInfo.FilesystemType
+		nodePublishInfo.UseCHAP = publishInfo.UseCHAP
+		nodePublishInfo.SharedTarget = publishInfo.SharedTarget
+		nodePublishInfo.IscsiTargetPortal = publishInfo.IscsiTargetPortal
+		nodePublishInfo.IscsiPortals = publishInfo.IscsiPortals
+		nodePublishInfo.IscsiUsername = publishInfo.IscsiUsername
+		nodePublishInfo.IscsiInitiatorSecret = publishInfo.IscsiInitiatorSecret
+		nodePublishInfo.IscsiTargetUsername = publishInfo.IscsiTargetUsername
+		nodePublishInfo.IscsiTargetSecret = publishInfo.IscsiTargetSecret
+	}
+	return nodePublishInfo
+}
+
+// DeleteEphemeralVolume deletes a CSI inline ephemeral volume once its node has detached it.  Only
+// ephemeral volumes may be deleted this way, so a node can't delete persistent volumes.
+func DeleteEphemeralVolume(w http.ResponseWriter, r *http.Request) {
+	DeleteGeneric(w, r, func(volumeName string) error {
+		volume, err := orchestrator.GetVolume(volumeName)
+		if err != nil {
+			return err
+		}
+		if volume.Config.EphemeralNode == "" {
+			return fmt.Errorf("volume %s is not an ephemeral volume", volumeName)
+		}
+		return orchestrator.DeleteVolume(r.Context(), volumeName)
+	}, "volume")
 }
@@ -0,0 +1,231 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netapp/trident/config"
+)
+
+// RequestLimits protect Trident from REST clients, such as misbehaving automation, that send too many
+// requests or requests that are too large.  Requests from Trident's own nodes, which authenticate with
+// Trident's client certificate, are exempt from the rate and concurrency limits, so that CSI operations
+// aren't starved by other clients.
+type RequestLimits struct {
+	// RequestsPerSecond is the sustained rate of requests accepted from each client address, or zero
+	// for no limit
+	RequestsPerSecond float64
+	// Burst is the number of requests a client may send at once before its rate is limited
+	Burst int
+	// MaxConcurrentRequests caps the requests handled at once across all clients, or zero for no cap
+	MaxConcurrentRequests int
+	// MaxRequestSize is the largest request body accepted, except by routes that accept larger documents
+	MaxRequestSize int64
+}
+
+// DefaultRequestLimits returns limits that only cap the size of requests.
+func DefaultRequestLimits() RequestLimits {
+	return RequestLimits{MaxRequestSize: config.MaxRESTRequestSize}
+}
+
+// Validate returns an error if the limits are inconsistent.
+func (l RequestLimits) Validate() error {
+	if l.RequestsPerSecond < 0 {
+		return errors.New("the request rate may not be negative")
+	}
+	if l.RequestsPerSecond > 0 && l.Burst < 1 {
+		return errors.New("the request burst must be at least 1 when the request rate is limited")
+	}
+	if l.MaxConcurrentRequests < 0 {
+		return errors.New("the concurrent request cap may not be negative")
+	}
+	if l.MaxRequestSize < 1 {
+		return errors.New("the maximum request size must be positive")
+	}
+	return nil
+}
+
+// routeMaxRequestSizes lists the routes that accept documents larger than other requests.
+var routeMaxRequestSizes = map[string]int64{
+	"ImportState": config.MaxStateBundleSize,
+}
+
+// longLivedRoutes lists the routes whose requests stay open indefinitely, so they don't count against
+// the concurrency cap.
+var longLivedRoutes = map[string]bool{
+	"StreamEvents": true,
+}
+
+// clientIdleSweepInterval is how often clients whose rate limits have fully recovered are forgotten.
+const clientIdleSweepInterval = time.Minute
+
+// tokenBucket tracks the requests a client may still send, refilled at the limited rate.
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// requestLimiter enforces the request limits of one REST server.
+type requestLimiter struct {
+	limits     RequestLimits
+	concurrent chan struct{}
+
+	mutex     sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func newRequestLimiter(limits RequestLimits) *requestLimiter {
+	l := &requestLimiter{
+		limits:  limits,
+		buckets: make(map[string]*tokenBucket),
+		now:     time.Now,
+	}
+	if limits.MaxConcurrentRequests > 0 {
+		l.concurrent = make(chan struct{}, limits.MaxConcurrentRequests)
+	}
+	l.lastSweep = l.now()
+	return l
+}
+
+// allow takes a token from a client's bucket.  If the bucket is empty, it returns false and how long
+// the client should wait before retrying.
+func (l *requestLimiter) allow(client string) (bool, time.Duration) {
+
+	if l.limits.RequestsPerSecond <= 0 {
+		return true, 0
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := l.now()
+	burst := float64(l.limits.Burst)
+
+	if now.Sub(l.lastSweep) >= clientIdleSweepInterval {
+		for name, bucket := range l.buckets {
+			if bucket.tokens+now.Sub(bucket.last).Seconds()*l.limits.RequestsPerSecond >= burst {
+				delete(l.buckets, name)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	bucket, ok := l.buckets[client]
+	if !ok {
+		bucket = &tokenBucket{tokens: burst, last: now}
+		l.buckets[client] = bucket
+	}
+	bucket.tokens = math.Min(burst, bucket.tokens+now.Sub(bucket.last).Seconds()*l.limits.RequestsPerSecond)
+	bucket.last = now
+
+	if bucket.tokens < 1 {
+		wait := time.Duration((1 - bucket.tokens) / l.limits.RequestsPerSecond * float64(time.Second))
+		return false, wait
+	}
+	bucket.tokens--
+	return true, 0
+}
+
+// acquire reserves one of the concurrent request slots, and returns false if none is free.
+func (l *requestLimiter) acquire() bool {
+	if l.concurrent == nil {
+		return true
+	}
+	select {
+	case l.concurrent <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+func (l *requestLimiter) release() {
+	if l.concurrent != nil {
+		<-l.concurrent
+	}
+}
+
+// LimitErrorResponse is the body of a response to a request rejected by the server's request limits.
+type LimitErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// limitRequests rejects requests to a route that exceed the server's request limits.
+func limitRequests(inner http.Handler, routeName string, limiter *requestLimiter) http.Handler {
+
+	maxRequestSize := limiter.limits.MaxRequestSize
+	if routeSize, ok := routeMaxRequestSizes[routeName]; ok && routeSize > maxRequestSize {
+		maxRequestSize = routeSize
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		reject := func(statusCode int, retryAfter time.Duration, message string) {
+			log.WithFields(log.Fields{
+				"route":  routeName,
+				"client": r.RemoteAddr,
+			}).Debugf("Rejected REST request; %s", message)
+			if retryAfter > 0 {
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			}
+			w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+			writeHTTPResponse(w, &LimitErrorResponse{Error: message}, statusCode)
+		}
+
+		if r.ContentLength > maxRequestSize {
+			reject(http.StatusRequestEntityTooLarge, 0, fmt.Sprintf("request body exceeds %d bytes",
+				maxRequestSize))
+			return
+		}
+		if r.Body != nil {
+			r.Body = http.MaxBytesReader(w, r.Body, maxRequestSize)
+		}
+
+		if isTridentNode(r) {
+			inner.ServeHTTP(w, r)
+			return
+		}
+
+		if ok, retryAfter := limiter.allow(clientAddress(r)); !ok {
+			reject(http.StatusTooManyRequests, retryAfter, "too many requests from this client")
+			return
+		}
+
+		if !longLivedRoutes[routeName] {
+			if !limiter.acquire() {
+				reject(http.StatusServiceUnavailable, time.Second, "too many concurrent requests")
+				return
+			}
+			defer limiter.release()
+		}
+
+		inner.ServeHTTP(w, r)
+	})
+}
+
+// isTridentNode returns true if a request came from a Trident node, which authenticates with Trident's
+// client certificate.
+func isTridentNode(r *http.Request) bool {
+	return r.TLS != nil && len(r.TLS.PeerCertificates) > 0 &&
+		r.TLS.PeerCertificates[0].Subject.CommonName == config.ClientCertName
+}
+
+// clientAddress returns the IP address of a request's client, without its port.
+func clientAddress(r *http.Request) string {
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
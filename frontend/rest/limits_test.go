@@ -0,0 +1,111 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package rest
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netapp/trident/config"
+)
+
+func TestRequestLimiterRate(t *testing.T) {
+
+	now := time.Now()
+	limiter := newRequestLimiter(RequestLimits{RequestsPerSecond: 2, Burst: 3, MaxRequestSize: 1})
+	limiter.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		ok, _ := limiter.allow("client1")
+		assert.True(t, ok, "requests within the burst should be allowed")
+	}
+	ok, retryAfter := limiter.allow("client1")
+	assert.False(t, ok)
+	assert.Equal(t, 500*time.Millisecond, retryAfter)
+
+	ok, _ = limiter.allow("client2")
+	assert.True(t, ok, "each client should have its own limit")
+
+	now = now.Add(500 * time.Millisecond)
+	ok, _ = limiter.allow("client1")
+	assert.True(t, ok, "the limit should recover over time")
+
+	// Clients whose limits have recovered are forgotten
+	now = now.Add(clientIdleSweepInterval)
+	limiter.allow("client3")
+	assert.Len(t, limiter.buckets, 1)
+}
+
+func TestLimitRequests(t *testing.T) {
+
+	release := make(chan struct{})
+	inner := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := ioutil.ReadAll(r.Body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if r.URL.Query().Get("block") != "" {
+			<-release
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	limiter := newRequestLimiter(RequestLimits{
+		RequestsPerSecond: 0.001, Burst: 2, MaxConcurrentRequests: 1, MaxRequestSize: 10,
+	})
+	handler := limitRequests(inner, "AddVolume", limiter)
+
+	serve := func(request *http.Request) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, request)
+		return w
+	}
+
+	w := serve(httptest.NewRequest("POST", "/trident/v1/volume", strings.NewReader("more than ten bytes")))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+
+	// Requests that exceed the concurrency cap are rejected while another is handled
+	done := make(chan int)
+	go func() {
+		done <- serve(httptest.NewRequest("POST", "/trident/v1/volume?block=true", strings.NewReader("{}"))).Code
+	}()
+	assert.Eventually(t, func() bool { return len(limiter.concurrent) == 1 }, time.Second, time.Millisecond)
+	w = serve(httptest.NewRequest("POST", "/trident/v1/volume", strings.NewReader("{}")))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	close(release)
+	assert.Equal(t, http.StatusOK, <-done)
+
+	// The burst is spent, so the client is rate limited
+	w = serve(httptest.NewRequest("POST", "/trident/v1/volume", strings.NewReader("{}")))
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+
+	// Trident nodes aren't limited
+	request := httptest.NewRequest("POST", "/trident/v1/volume", strings.NewReader("{}"))
+	request.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{
+		{Subject: pkix.Name{CommonName: config.ClientCertName}},
+	}}
+	assert.Equal(t, http.StatusOK, serve(request).Code)
+
+	// Some routes accept larger documents
+	largeHandler := limitRequests(inner, "ImportState", newRequestLimiter(DefaultRequestLimits()))
+	w = httptest.NewRecorder()
+	largeHandler.ServeHTTP(w, httptest.NewRequest("POST", "/trident/v1/state",
+		strings.NewReader(strings.Repeat("x", config.MaxRESTRequestSize+1))))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestRequestLimitsValidate(t *testing.T) {
+	assert.NoError(t, DefaultRequestLimits().Validate())
+	assert.Error(t, RequestLimits{RequestsPerSecond: 1, MaxRequestSize: 1}.Validate(), "a rate limit needs a burst")
+	assert.Error(t, RequestLimits{}.Validate(), "the request size must be limited")
+}
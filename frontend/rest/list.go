@@ -0,0 +1,238 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package rest
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	listLimitParam         = "limit"
+	listContinueParam      = "continue"
+	listSortParam          = "sort"
+	listFieldSelectorParam = "fieldSelector"
+
+	listNameField = "name"
+)
+
+// listItem is the name of a listed object, along with the values of the fields by which the list
+// may be filtered and sorted.
+type listItem struct {
+	name   string
+	fields map[string]string
+}
+
+func (i *listItem) value(field string) string {
+	if field == listNameField {
+		return i.name
+	}
+	return i.fields[field]
+}
+
+// listSelector matches the objects whose field has, or with notEqual doesn't have, a value.
+type listSelector struct {
+	field    string
+	value    string
+	notEqual bool
+}
+
+// listOptions controls which objects a list request returns, and in what order.
+type listOptions struct {
+	limit      int
+	sortField  string
+	descending bool
+	selectors  []listSelector
+	// continueFrom is the last object returned by the previous page, if any
+	continueFrom *listContinueToken
+}
+
+// listContinueToken records where a page of a list ended, so the next page can start after it
+// even if objects were added or removed in the meantime.
+type listContinueToken struct {
+	Sort  string `json:"sort"`
+	Value string `json:"value,omitempty"`
+	Name  string `json:"name"`
+}
+
+// parseListOptions reads the limit, continue, sort and fieldSelector query parameters of a list
+// request.  Objects may be filtered and sorted by name and by any of the specified fields.
+// Sorting is by name unless a field is specified, and is descending if the field is prefixed by
+// '-'.  Field selectors are comma-separated 'field=value' or 'field!=value' terms.
+func parseListOptions(r *http.Request, fields ...string) (*listOptions, error) {
+
+	query := r.URL.Query()
+	options := &listOptions{sortField: listNameField}
+
+	isField := func(field string) bool {
+		if field == listNameField {
+			return true
+		}
+		for _, f := range fields {
+			if f == field {
+				return true
+			}
+		}
+		return false
+	}
+	validFields := strings.Join(append([]string{listNameField}, fields...), ", ")
+
+	if limit := query.Get(listLimitParam); limit != "" {
+		value, err := strconv.Atoi(limit)
+		if err != nil || value < 0 {
+			return nil, fmt.Errorf("invalid limit '%s'; must be a non-negative integer", limit)
+		}
+		options.limit = value
+	}
+
+	if sortBy := query.Get(listSortParam); sortBy != "" {
+		options.descending = strings.HasPrefix(sortBy, "-")
+		options.sortField = strings.TrimPrefix(sortBy, "-")
+		if !isField(options.sortField) {
+			return nil, fmt.Errorf("cannot sort by '%s'; valid fields are %s", options.sortField, validFields)
+		}
+	}
+
+	if selector := query.Get(listFieldSelectorParam); selector != "" {
+		for _, term := range strings.Split(selector, ",") {
+			var s listSelector
+			if parts := strings.SplitN(term, "!=", 2); len(parts) == 2 {
+				s = listSelector{field: parts[0], value: parts[1], notEqual: true}
+			} else if parts := strings.SplitN(term, "=", 2); len(parts) == 2 {
+				s = listSelector{field: parts[0], value: strings.TrimPrefix(parts[1], "=")}
+			} else {
+				return nil, fmt.Errorf("invalid field selector '%s'; must be field=value or field!=value", term)
+			}
+			s.field = strings.TrimSpace(s.field)
+			s.value = strings.TrimSpace(s.value)
+			if !isField(s.field) {
+				return nil, fmt.Errorf("cannot select by '%s'; valid fields are %s", s.field, validFields)
+			}
+			options.selectors = append(options.selectors, s)
+		}
+	}
+
+	if continueToken := query.Get(listContinueParam); continueToken != "" {
+		token, err := decodeListContinueToken(continueToken)
+		if err != nil {
+			return nil, err
+		}
+		if token.Sort != options.sortString() {
+			return nil, fmt.Errorf("continue token was issued for a list sorted by '%s'", token.Sort)
+		}
+		options.continueFrom = token
+	}
+
+	return options, nil
+}
+
+func (o *listOptions) sortString() string {
+	if o.descending {
+		return "-" + o.sortField
+	}
+	return o.sortField
+}
+
+// matches reports whether an object satisfies all of the field selectors.
+func (o *listOptions) matches(item *listItem) bool {
+	for _, s := range o.selectors {
+		if (item.value(s.field) == s.value) == s.notEqual {
+			return false
+		}
+	}
+	return true
+}
+
+// less orders objects by the sort field, and then by name so the order is stable between pages.
+func (o *listOptions) less(value1, name1, value2, name2 string) bool {
+	result := compareListValues(value1, value2)
+	if result == 0 {
+		result = strings.Compare(name1, name2)
+	}
+	if o.descending {
+		return result > 0
+	}
+	return result < 0
+}
+
+// apply filters, sorts and pages a list, returning the names of the objects in the page and the
+// continue token for the next page, if there is one.
+func (o *listOptions) apply(items []listItem) ([]string, string) {
+
+	selected := make([]listItem, 0, len(items))
+	for i := range items {
+		if o.matches(&items[i]) {
+			selected = append(selected, items[i])
+		}
+	}
+
+	sort.Slice(selected, func(i, j int) bool {
+		return o.less(selected[i].value(o.sortField), selected[i].name,
+			selected[j].value(o.sortField), selected[j].name)
+	})
+
+	if o.continueFrom != nil {
+		start := sort.Search(len(selected), func(i int) bool {
+			return o.less(o.continueFrom.Value, o.continueFrom.Name,
+				selected[i].value(o.sortField), selected[i].name)
+		})
+		selected = selected[start:]
+	}
+
+	continueToken := ""
+	if o.limit > 0 && len(selected) > o.limit {
+		selected = selected[:o.limit]
+		last := selected[len(selected)-1]
+		continueToken = encodeListContinueToken(&listContinueToken{
+			Sort:  o.sortString(),
+			Value: last.value(o.sortField),
+			Name:  last.name,
+		})
+	}
+
+	names := make([]string, 0, len(selected))
+	for _, item := range selected {
+		names = append(names, item.name)
+	}
+	return names, continueToken
+}
+
+// compareListValues compares two field values numerically if both are integers, and otherwise
+// as strings.
+func compareListValues(value1, value2 string) int {
+	if int1, err := strconv.ParseInt(value1, 10, 64); err == nil {
+		if int2, err := strconv.ParseInt(value2, 10, 64); err == nil {
+			switch {
+			case int1 < int2:
+				return -1
+			case int1 > int2:
+				return 1
+			default:
+				return 0
+			}
+		}
+	}
+	return strings.Compare(value1, value2)
+}
+
+func encodeListContinueToken(token *listContinueToken) string {
+	tokenBytes, _ := json.Marshal(token)
+	return base64.RawURLEncoding.EncodeToString(tokenBytes)
+}
+
+func decodeListContinueToken(value string) (*listContinueToken, error) {
+	tokenBytes, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid continue token")
+	}
+	token := &listContinueToken{}
+	if err = json.Unmarshal(tokenBytes, token); err != nil {
+		return nil, fmt.Errorf("invalid continue token")
+	}
+	return token, nil
+}
@@ -0,0 +1,117 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package rest
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func getTestListItems() []listItem {
+	return []listItem{
+		{name: "vol3", fields: map[string]string{"backend": "b1", "size": "1000"}},
+		{name: "vol1", fields: map[string]string{"backend": "b2", "size": "20000"}},
+		{name: "vol4", fields: map[string]string{"backend": "b1", "size": "300"}},
+		{name: "vol2", fields: map[string]string{"backend": "b1", "size": "1000"}},
+	}
+}
+
+func TestParseListOptions(t *testing.T) {
+
+	tests := []struct {
+		query string
+		valid bool
+	}{
+		{"", true},
+		{"?limit=10&sort=-size&fieldSelector=backend=b1,name!=vol2", true},
+		{"?fieldSelector=backend==b1", true},
+		{"?limit=-1", false},
+		{"?limit=ten", false},
+		{"?sort=color", false},
+		{"?fieldSelector=color=blue", false},
+		{"?fieldSelector=backend", false},
+		{"?continue=invalid!", false},
+	}
+	for _, test := range tests {
+		r := httptest.NewRequest("GET", "/trident/v1/volume"+test.query, nil)
+		_, err := parseListOptions(r, "backend", "size")
+		assert.Equal(t, test.valid, err == nil, "query '%s'", test.query)
+	}
+}
+
+func TestListOptionsApply(t *testing.T) {
+
+	tests := []struct {
+		query    string
+		expected []string
+	}{
+		{"", []string{"vol1", "vol2", "vol3", "vol4"}},
+		{"?sort=-name", []string{"vol4", "vol3", "vol2", "vol1"}},
+		{"?sort=size", []string{"vol4", "vol2", "vol3", "vol1"}},
+		{"?sort=-size", []string{"vol1", "vol3", "vol2", "vol4"}},
+		{"?fieldSelector=backend=b1", []string{"vol2", "vol3", "vol4"}},
+		{"?fieldSelector=backend=b1,size!=1000", []string{"vol4"}},
+		{"?fieldSelector=backend=b3", []string{}},
+	}
+	for _, test := range tests {
+		r := httptest.NewRequest("GET", "/trident/v1/volume"+test.query, nil)
+		options, err := parseListOptions(r, "backend", "size")
+		if !assert.NoError(t, err) {
+			continue
+		}
+		names, continueToken := options.apply(getTestListItems())
+		assert.Equal(t, test.expected, names, "query '%s'", test.query)
+		assert.Empty(t, continueToken)
+	}
+}
+
+func TestListOptionsPaging(t *testing.T) {
+
+	for _, sortBy := range []string{"name", "-size"} {
+
+		r := httptest.NewRequest("GET", "/trident/v1/volume?sort="+sortBy, nil)
+		options, _ := parseListOptions(r, "backend", "size")
+		expected, _ := options.apply(getTestListItems())
+
+		names := make([]string, 0)
+		continueToken := ""
+		for pages := 0; pages < 10; pages++ {
+			r = httptest.NewRequest("GET", "/trident/v1/volume?limit=3&sort="+sortBy+"&continue="+continueToken, nil)
+			options, err := parseListOptions(r, "backend", "size")
+			if !assert.NoError(t, err) {
+				return
+			}
+			var page []string
+			page, continueToken = options.apply(getTestListItems())
+			assert.True(t, len(page) <= 3)
+			names = append(names, page...)
+			if continueToken == "" {
+				break
+			}
+		}
+		assert.Equal(t, expected, names, "sort '%s'", sortBy)
+	}
+
+	// A page continues after the last object returned even if it was deleted
+	r := httptest.NewRequest("GET", "/trident/v1/volume?limit=2", nil)
+	options, _ := parseListOptions(r)
+	page, continueToken := options.apply(getTestListItems())
+	assert.Equal(t, []string{"vol1", "vol2"}, page)
+
+	items := getTestListItems()[:3]
+	r = httptest.NewRequest("GET", "/trident/v1/volume?limit=2&continue="+continueToken, nil)
+	options, _ = parseListOptions(r)
+	page, continueToken = options.apply(items)
+	assert.Equal(t, []string{"vol3", "vol4"}, page)
+	assert.Empty(t, continueToken)
+
+	// A continue token can't be used with a different sort order
+	r = httptest.NewRequest("GET", "/trident/v1/volume?limit=2", nil)
+	options, _ = parseListOptions(r)
+	_, continueToken = options.apply(getTestListItems())
+	r = httptest.NewRequest("GET", "/trident/v1/volume?sort=-name&continue="+continueToken, nil)
+	_, err := parseListOptions(r)
+	assert.Error(t, err)
+}
@@ -0,0 +1,378 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"path"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/netapp/trident/config"
+	persistentstore "github.com/netapp/trident/persistent_store"
+	"github.com/netapp/trident/storage"
+	storageclass "github.com/netapp/trident/storage_class"
+	"github.com/netapp/trident/utils"
+)
+
+const openAPIVersion = "3.0.3"
+
+var openAPIPathParamRegex = regexp.MustCompile(`{([^}]+)}`)
+
+// routeSchema describes the bodies of a route's requests and responses by sample values of the
+// types they unmarshal to, along with what the route's paths don't say about it.
+type routeSchema struct {
+	request  interface{}
+	response interface{}
+	// created is set for routes that respond with 201 Created when they succeed
+	created bool
+	// listFields are the fields by which a list route may be filtered and sorted
+	listFields []string
+	// queryParams are the route's other query parameters
+	queryParams []string
+	// contentType is the type of the route's responses, if they aren't JSON
+	contentType string
+}
+
+// freeForm stands for request bodies that are arbitrary JSON objects, such as backend configurations.
+var freeForm = map[string]interface{}{}
+
+// routeSchemas describes the bodies of each route for the OpenAPI document.  Every route served
+// under the versioned API must have an entry.
+var routeSchemas = map[string]routeSchema{
+	"GetVersion":          {response: GetVersionResponse{}},
+	"GetHealth":           {response: GetHealthResponse{}},
+	"GetOpenAPISpec":      {response: freeForm},
+	"StreamEvents":        {response: Event{}, queryParams: []string{eventKindParam}, contentType: "text/event-stream"},
+	"GetLogLevel":         {response: LogLevelResponse{}},
+	"SetLogLevel":         {request: LogLevelRequest{}, response: LogLevelResponse{}},
+	"GetUpgradePreflight": {response: UpgradePreflightResponse{}},
+	"ExportState":         {response: ExportStateResponse{}},
+	"ImportState":         {request: persistentstore.StateBundle{}, response: ImportStateResponse{}},
+	"VerifyState": {
+		request: storage.StateVerifyRequest{}, response: StateVerificationResponse{}, created: true,
+	},
+
+	"AddBackend":           {request: freeForm, response: AddBackendResponse{}, created: true},
+	"UpdateBackend":        {request: freeForm, response: UpdateBackendResponse{}},
+	"PreviewBackendUpdate": {request: freeForm, response: PreviewBackendUpdateResponse{}},
+	"UpdateBackendState":   {request: storage.UpdateBackendStateRequest{}, response: UpdateBackendResponse{}},
+	"UpdateBackendTracing": {request: storage.UpdateBackendTracingRequest{}, response: UpdateBackendResponse{}},
+	"ReencryptSecrets":     {response: ReencryptSecretsResponse{}},
+	"GetBackend":           {response: GetBackendResponse{}},
+	"GetBackendCapacity":   {response: GetBackendCapacityResponse{}},
+	"ListBackends":         {response: ListBackendsResponse{}, listFields: backendListFields},
+	"DeleteBackend":        {response: DeleteResponse{}, queryParams: []string{deleteForceParam}},
+	"DetectDrift":          {response: DriftReportResponse{}, created: true},
+	"GetDriftReport":       {response: DriftReportResponse{}},
+	"CollectGarbage":       {response: GarbageReportResponse{}, created: true},
+	"GetGarbageReport":     {response: GarbageReportResponse{}},
+
+	"AddVolume":             {request: storage.VolumeConfig{}, response: AddVolumeResponse{}, created: true},
+	"GetVolume":             {response: GetVolumeResponse{}},
+	"ListVolumes":           {response: ListVolumesResponse{}, listFields: volumeListFields},
+	"DeleteVolume":          {response: DeleteResponse{}, queryParams: []string{deleteForceParam}},
+	"ImportVolume":          {request: storage.ImportVolumeRequest{}, response: ImportVolumeResponse{}, created: true},
+	"UpgradeVolume":         {request: storage.UpgradeVolumeRequest{}, response: UpgradeVolumeResponse{}},
+	"ResizeVolume":          {request: storage.ResizeVolumeRequest{}, response: ResizeVolumeResponse{}},
+	"UnmanageVolume":        {request: storage.UnmanageVolumeRequest{}, response: UnmanageVolumeResponse{}},
+	"CloneVolume":           {request: storage.VolumeCloneRequest{}, response: CloneVolumeResponse{}, created: true},
+	"AddEphemeralVolume":    {request: storage.EphemeralVolumeRequest{}, response: AddEphemeralVolumeResponse{}},
+	"DeleteEphemeralVolume": {response: DeleteResponse{}},
+
+	"AddMigration":   {request: storage.VolumeMigrationRequest{}, response: AddMigrationResponse{}, created: true},
+	"GetMigration":   {response: GetMigrationResponse{}},
+	"ListMigrations": {response: ListMigrationsResponse{}},
+	"PlanMigration":  {response: MigrationPlanResponse{}, queryParams: []string{planBackendParam, planPoolParam}},
+	"GetDeletion":    {response: GetDeletionResponse{}},
+	"ListDeletions":  {response: ListDeletionsResponse{}},
+
+	"UpdateVolumeUsage":     {request: storage.VolumeUsage{}, response: UpdateVolumeUsageResponse{}},
+	"GetVolumeUsage":        {response: GetVolumeUsageResponse{}},
+	"ListVolumeUsage":       {response: ListVolumeUsageResponse{}},
+	"UpdateVolumeCondition": {request: storage.VolumeCondition{}, response: UpdateVolumeConditionResponse{}},
+	"GetVolumeCondition":    {response: GetVolumeConditionResponse{}},
+	"ListVolumeConditions":  {response: ListVolumeConditionsResponse{}},
+
+	"AddStorageClass":    {request: storageclass.Config{}, response: AddStorageClassResponse{}, created: true},
+	"GetStorageClass":    {response: GetStorageClassResponse{}},
+	"ListStorageClasses": {response: ListStorageClassesResponse{}, listFields: []string{}},
+	"DeleteStorageClass": {response: DeleteResponse{}},
+	"AddQuota":           {request: storage.Quota{}, response: AddQuotaResponse{}, created: true},
+	"GetQuota":           {response: GetQuotaResponse{}},
+	"ListQuotas":         {response: ListQuotasResponse{}},
+	"DeleteQuota":        {response: DeleteResponse{}},
+
+	"AddOrUpdateNode": {request: utils.Node{}, response: AddNodeResponse{}},
+	"GetNode":         {response: GetNodeResponse{}},
+	"ListNodes":       {response: ListNodesResponse{}, listFields: []string{}},
+	"DeleteNode":      {response: DeleteResponse{}},
+
+	"ListSnapshots":          {response: ListSnapshotsResponse{}, listFields: snapshotListFields},
+	"ListSnapshotsForVolume": {response: ListSnapshotsResponse{}, listFields: snapshotListFields},
+	"GetSnapshot":            {response: GetSnapshotResponse{}},
+	"AddSnapshot":            {request: storage.SnapshotConfig{}, response: AddSnapshotResponse{}, created: true},
+	"DeleteSnapshot":         {response: DeleteResponse{}},
+	"RestoreSnapshot":        {response: RestoreSnapshotResponse{}, queryParams: []string{restoreForceParam}},
+	"CheckSnapshotRestore":   {response: SnapshotRestorePreflightResponse{}},
+
+	"AddVolumeGroup":    {request: storage.VolumeGroupConfig{}, response: VolumeGroupResponse{}, created: true},
+	"UpdateVolumeGroup": {request: storage.VolumeGroupConfig{}, response: VolumeGroupResponse{}},
+	"GetVolumeGroup":    {response: GetVolumeGroupResponse{}},
+	"ListVolumeGroups":  {response: ListVolumeGroupsResponse{}},
+	"DeleteVolumeGroup": {response: DeleteResponse{}},
+	"CloneVolumeGroup":  {request: storage.VolumeGroupCloneRequest{}, response: VolumeGroupResponse{}},
+	"AddVolumeGroupSnapshot": {request: storage.VolumeGroupSnapshotRequest{},
+		response: AddVolumeGroupSnapshotResponse{}},
+	"AddCloneGrant":    {request: storage.CloneGrant{}, response: AddCloneGrantResponse{}, created: true},
+	"GetCloneGrant":    {response: GetCloneGrantResponse{}},
+	"ListCloneGrants":  {response: ListCloneGrantsResponse{}},
+	"DeleteCloneGrant": {response: DeleteResponse{}},
+}
+
+// openAPIRoutes are the routes the OpenAPI document describes.  They're set by init, since the
+// routes include the one that serves the document.
+var openAPIRoutes Routes
+
+func init() {
+	openAPIRoutes = routes
+}
+
+// GetOpenAPISpec serves an OpenAPI document describing the versioned REST API.
+func GetOpenAPISpec(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(buildOpenAPISpec(openAPIRoutes)); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// buildOpenAPISpec generates an OpenAPI document from the routes served under the versioned API.
+func buildOpenAPISpec(routes Routes) map[string]interface{} {
+
+	generator := &openAPIGenerator{
+		schemas: make(map[string]interface{}),
+		types:   make(map[string]reflect.Type),
+	}
+
+	paths := make(map[string]map[string]interface{})
+	for _, route := range routes {
+		if !strings.HasPrefix(route.Pattern, config.BaseURL+"/") {
+			continue
+		}
+		if paths[route.Pattern] == nil {
+			paths[route.Pattern] = make(map[string]interface{})
+		}
+		paths[route.Pattern][strings.ToLower(route.Method)] = generator.operation(route)
+	}
+
+	return map[string]interface{}{
+		"openapi": openAPIVersion,
+		"info": map[string]interface{}{
+			"title":   "Trident REST API",
+			"version": config.OrchestratorVersion.String(),
+		},
+		"servers": []interface{}{
+			map[string]interface{}{"url": "/"},
+		},
+		"paths": paths,
+		"components": map[string]interface{}{
+			"schemas": generator.schemas,
+		},
+	}
+}
+
+type openAPIGenerator struct {
+	// schemas are the components of the document, keyed by the package and name of their types
+	schemas map[string]interface{}
+	// types are the types of the schemas, so that types of the same name in packages of the same
+	// name, such as the Kubernetes API's v1 packages, don't share a schema
+	types map[string]reflect.Type
+}
+
+// operation describes a route's parameters, request body and response.
+func (g *openAPIGenerator) operation(route Route) map[string]interface{} {
+
+	schema := routeSchemas[route.Name]
+
+	parameters := make([]interface{}, 0)
+	for _, match := range openAPIPathParamRegex.FindAllStringSubmatch(route.Pattern, -1) {
+		parameters = append(parameters, map[string]interface{}{
+			"name":     match[1],
+			"in":       "path",
+			"required": true,
+			"schema":   map[string]interface{}{"type": "string"},
+		})
+	}
+	queryParams := schema.queryParams
+	if schema.listFields != nil {
+		queryParams = append(queryParams, listLimitParam, listContinueParam, listSortParam, listFieldSelectorParam)
+	}
+	for _, param := range queryParams {
+		parameters = append(parameters, map[string]interface{}{
+			"name":   param,
+			"in":     "query",
+			"schema": map[string]interface{}{"type": "string"},
+		})
+	}
+
+	operation := map[string]interface{}{
+		"operationId": route.Name,
+		"tags":        []string{routeTag(route.Pattern)},
+	}
+	if len(parameters) > 0 {
+		operation["parameters"] = parameters
+	}
+	if schema.listFields != nil {
+		operation["description"] = "Besides name, lists may be filtered and sorted by: " +
+			strings.Join(schema.listFields, ", ")
+	}
+
+	if schema.request != nil {
+		operation["requestBody"] = map[string]interface{}{
+			"required": true,
+			"content": map[string]interface{}{
+				"application/json": map[string]interface{}{
+					"schema": g.schemaFor(reflect.TypeOf(schema.request)),
+				},
+			},
+		}
+	}
+
+	status := "200"
+	if schema.created {
+		status = "201"
+	}
+	contentType := schema.contentType
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	response := map[string]interface{}{"description": http.StatusText(http.StatusOK)}
+	if schema.created {
+		response["description"] = http.StatusText(http.StatusCreated)
+	}
+	if schema.response != nil {
+		response["content"] = map[string]interface{}{
+			contentType: map[string]interface{}{
+				"schema": g.schemaFor(reflect.TypeOf(schema.response)),
+			},
+		}
+	}
+	operation["responses"] = map[string]interface{}{
+		status:    response,
+		"default": map[string]interface{}{"description": "Error"},
+	}
+
+	return operation
+}
+
+// routeTag groups routes by the first element of their paths under the versioned API, such as volume.
+func routeTag(pattern string) string {
+	return strings.SplitN(strings.TrimPrefix(pattern, config.BaseURL+"/"), "/", 2)[0]
+}
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	timeType          = reflect.TypeOf(time.Time{})
+)
+
+// schemaFor returns the schema of the JSON that a type marshals to.  Structs are added to the
+// document's components and referenced from there, so that recursive types are described.
+func (g *openAPIGenerator) schemaFor(t reflect.Type) map[string]interface{} {
+
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case t.Implements(jsonMarshalerType) || reflect.PtrTo(t).Implements(jsonMarshalerType):
+		// Types that marshal themselves may produce any JSON
+		return map[string]interface{}{}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
+		return map[string]interface{}{"type": "integer", "format": "int32"}
+	case reflect.Int64:
+		return map[string]interface{}{"type": "integer", "format": "int64"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer", "minimum": 0}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]interface{}{"type": "string", "format": "byte"}
+		}
+		return map[string]interface{}{"type": "array", "items": g.schemaFor(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": g.schemaFor(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		name := path.Base(t.PkgPath()) + "." + t.Name()
+		if knownType, ok := g.types[name]; ok && knownType != t {
+			name = strings.ReplaceAll(t.PkgPath(), "/", ".") + "." + t.Name()
+		}
+		if _, ok := g.types[name]; !ok {
+			// Reserve the name before describing the fields, in case they refer back to the type
+			g.types[name] = t
+			g.schemas[name] = g.structSchema(t)
+		}
+		return map[string]interface{}{"$ref": "#/components/schemas/" + name}
+	default:
+		// Interfaces may hold any JSON
+		return map[string]interface{}{}
+	}
+}
+
+// structSchema describes a struct's JSON fields, including those of the structs it embeds.
+func (g *openAPIGenerator) structSchema(t reflect.Type) map[string]interface{} {
+
+	properties := make(map[string]interface{})
+	g.addStructProperties(t, properties)
+	return map[string]interface{}{"type": "object", "properties": properties}
+}
+
+func (g *openAPIGenerator) addStructProperties(t reflect.Type, properties map[string]interface{}) {
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		tag := strings.Split(field.Tag.Get("json"), ",")
+		name := tag[0]
+		if name == "-" {
+			continue
+		}
+
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && name == "" && fieldType.Kind() == reflect.Struct {
+			g.addStructProperties(fieldType, properties)
+			continue
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		if utils.SliceContainsString(tag[1:], "string") {
+			properties[name] = map[string]interface{}{"type": "string"}
+		} else {
+			properties[name] = g.schemaFor(field.Type)
+		}
+	}
+}
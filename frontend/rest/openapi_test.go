@@ -0,0 +1,66 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netapp/trident/config"
+)
+
+func TestRouteSchemas(t *testing.T) {
+
+	routeNames := make(map[string]bool)
+	for _, route := range routes {
+		routeNames[route.Name] = true
+		if strings.HasPrefix(route.Pattern, config.BaseURL+"/") {
+			_, ok := routeSchemas[route.Name]
+			assert.True(t, ok, "route %s is missing from the OpenAPI route schemas", route.Name)
+		}
+	}
+	for name := range routeSchemas {
+		assert.True(t, routeNames[name], "OpenAPI route schema %s has no route", name)
+	}
+}
+
+func TestGetOpenAPISpec(t *testing.T) {
+
+	w := httptest.NewRecorder()
+	GetOpenAPISpec(w, httptest.NewRequest("GET", config.OpenAPIURL, nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var spec struct {
+		OpenAPI    string                                       `json:"openapi"`
+		Paths      map[string]map[string]map[string]interface{} `json:"paths"`
+		Components struct {
+			Schemas map[string]map[string]interface{} `json:"schemas"`
+		} `json:"components"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &spec))
+	assert.Equal(t, openAPIVersion, spec.OpenAPI)
+
+	volumePath := spec.Paths[config.VolumeURL+"/{volume}"]
+	assert.Equal(t, "GetVolume", volumePath["get"]["operationId"])
+	assert.Equal(t, "DeleteVolume", volumePath["delete"]["operationId"])
+	assert.Contains(t, spec.Paths[config.VolumeURL]["post"]["responses"], "201")
+	assert.NotContains(t, spec.Paths, "/trident/version", "unversioned routes aren't described")
+
+	volumeResponse := spec.Components.Schemas["rest.GetVolumeResponse"]
+	assert.Equal(t, map[string]interface{}{"$ref": "#/components/schemas/storage.VolumeExternal"},
+		volumeResponse["properties"].(map[string]interface{})["volume"])
+	assert.Contains(t, spec.Components.Schemas["storage.VolumeExternal"]["properties"], "Config")
+
+	// Every reference resolves to a schema of the document
+	refs := regexp.MustCompile(`"#/components/schemas/([^"]+)"`).FindAllStringSubmatch(w.Body.String(), -1)
+	assert.NotEmpty(t, refs)
+	for _, ref := range refs {
+		assert.Contains(t, spec.Components.Schemas, ref[1])
+	}
+}
@@ -8,13 +8,17 @@ import (
 	"github.com/gorilla/mux"
 )
 
-func NewRouter() *mux.Router {
+func NewRouter(limits RequestLimits) *mux.Router {
+
+	limiter := newRequestLimiter(limits)
 
 	router := mux.NewRouter().StrictSlash(true)
 	for _, route := range routes {
 		var handler http.Handler
 
 		handler = route.HandlerFunc
+		handler = Auditor(handler, route.Name)
+		handler = limitRequests(handler, route.Name, limiter)
 		handler = Logger(handler, route.Name)
 
 		router.
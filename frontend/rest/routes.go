@@ -43,18 +43,42 @@ var routes = Routes{
 		config.BackendURL + "/{backend}",
 		UpdateBackend,
 	},
+	Route{
+		"PreviewBackendUpdate",
+		"POST",
+		config.BackendURL + "/{backend}" + "/preview",
+		PreviewBackendUpdate,
+	},
 	Route{
 		"UpdateBackendState",
 		"POST",
 		config.BackendURL + "/{backend}" + "/state",
 		UpdateBackendState,
 	},
+	Route{
+		"UpdateBackendTracing",
+		"POST",
+		config.BackendURL + "/{backend}" + "/tracing",
+		UpdateBackendTracing,
+	},
+	Route{
+		"ReencryptSecrets",
+		"POST",
+		config.BackendURL + "/secrets" + "/reencrypt",
+		ReencryptSecrets,
+	},
 	Route{
 		"GetBackend",
 		"GET",
 		config.BackendURL + "/{backend}",
 		GetBackend,
 	},
+	Route{
+		"GetBackendCapacity",
+		"GET",
+		config.BackendURL + "/{backend}" + "/capacity",
+		GetBackendCapacity,
+	},
 	Route{
 		"ListBackends",
 		"GET",
@@ -103,6 +127,24 @@ var routes = Routes{
 		config.VolumeURL + "/{volume}/upgrade",
 		UpgradeVolume,
 	},
+	Route{
+		"ResizeVolume",
+		"POST",
+		config.VolumeURL + "/{volume}/resize",
+		ResizeVolume,
+	},
+	Route{
+		"UnmanageVolume",
+		"POST",
+		config.VolumeURL + "/{volume}/unmanage",
+		UnmanageVolume,
+	},
+	Route{
+		"CloneVolume",
+		"POST",
+		config.VolumeURL + "/{volume}/clone",
+		CloneVolume,
+	},
 	Route{
 		"AddStorageClass",
 		"POST",
@@ -181,4 +223,268 @@ var routes = Routes{
 		config.SnapshotURL + "/{volume}/{snapshot}",
 		DeleteSnapshot,
 	},
+	Route{
+		"RestoreSnapshot",
+		"POST",
+		config.SnapshotURL + "/{volume}/{snapshot}/restore",
+		RestoreSnapshot,
+	},
+	Route{
+		"CheckSnapshotRestore",
+		"GET",
+		config.SnapshotURL + "/{volume}/{snapshot}/restore",
+		CheckSnapshotRestore,
+	},
+	Route{
+		"AddMigration",
+		"POST",
+		config.MigrationURL,
+		AddMigration,
+	},
+	Route{
+		"GetMigration",
+		"GET",
+		config.MigrationURL + "/{volume}",
+		GetMigration,
+	},
+	Route{
+		"PlanMigration",
+		"GET",
+		config.MigrationURL + "/{volume}/plan",
+		PlanMigration,
+	},
+	Route{
+		"ListMigrations",
+		"GET",
+		config.MigrationURL,
+		ListMigrations,
+	},
+	Route{
+		"UpdateVolumeUsage",
+		"PUT",
+		config.UsageURL + "/{volume}",
+		UpdateVolumeUsage,
+	},
+	Route{
+		"GetVolumeUsage",
+		"GET",
+		config.UsageURL + "/{volume}",
+		GetVolumeUsage,
+	},
+	Route{
+		"ListVolumeUsage",
+		"GET",
+		config.UsageURL,
+		ListVolumeUsage,
+	},
+	Route{
+		"UpdateVolumeCondition",
+		"PUT",
+		config.ConditionURL + "/{volume}",
+		UpdateVolumeCondition,
+	},
+	Route{
+		"GetVolumeCondition",
+		"GET",
+		config.ConditionURL + "/{volume}",
+		GetVolumeCondition,
+	},
+	Route{
+		"ListVolumeConditions",
+		"GET",
+		config.ConditionURL,
+		ListVolumeConditions,
+	},
+	Route{
+		"DetectDrift",
+		"POST",
+		config.DriftURL,
+		DetectDrift,
+	},
+	Route{
+		"GetDriftReport",
+		"GET",
+		config.DriftURL,
+		GetDriftReport,
+	},
+	Route{
+		"CollectGarbage",
+		"POST",
+		config.GarbageURL,
+		CollectGarbage,
+	},
+	Route{
+		"GetGarbageReport",
+		"GET",
+		config.GarbageURL,
+		GetGarbageReport,
+	},
+	Route{
+		"GetDeletion",
+		"GET",
+		config.DeletionURL + "/{volume}",
+		GetDeletion,
+	},
+	Route{
+		"ListDeletions",
+		"GET",
+		config.DeletionURL,
+		ListDeletions,
+	},
+	Route{
+		"AddVolumeGroup",
+		"POST",
+		config.VolumeGroupURL,
+		AddVolumeGroup,
+	},
+	Route{
+		"UpdateVolumeGroup",
+		"PUT",
+		config.VolumeGroupURL + "/{group}",
+		UpdateVolumeGroup,
+	},
+	Route{
+		"GetVolumeGroup",
+		"GET",
+		config.VolumeGroupURL + "/{group}",
+		GetVolumeGroup,
+	},
+	Route{
+		"ListVolumeGroups",
+		"GET",
+		config.VolumeGroupURL,
+		ListVolumeGroups,
+	},
+	Route{
+		"DeleteVolumeGroup",
+		"DELETE",
+		config.VolumeGroupURL + "/{group}",
+		DeleteVolumeGroup,
+	},
+	Route{
+		"AddVolumeGroupSnapshot",
+		"POST",
+		config.VolumeGroupURL + "/{group}/snapshot",
+		AddVolumeGroupSnapshot,
+	},
+	Route{
+		"CloneVolumeGroup",
+		"POST",
+		config.VolumeGroupURL + "/{group}/clone",
+		CloneVolumeGroup,
+	},
+	Route{
+		"AddCloneGrant",
+		"POST",
+		config.CloneGrantURL,
+		AddCloneGrant,
+	},
+	Route{
+		"GetCloneGrant",
+		"GET",
+		config.CloneGrantURL + "/{namespace}/{grant}",
+		GetCloneGrant,
+	},
+	Route{
+		"ListCloneGrants",
+		"GET",
+		config.CloneGrantURL,
+		ListCloneGrants,
+	},
+	Route{
+		"DeleteCloneGrant",
+		"DELETE",
+		config.CloneGrantURL + "/{namespace}/{grant}",
+		DeleteCloneGrant,
+	},
+	Route{
+		"AddQuota",
+		"POST",
+		config.QuotaURL,
+		AddQuota,
+	},
+	Route{
+		"GetQuota",
+		"GET",
+		config.QuotaURL + "/{namespace}/{quota}",
+		GetQuota,
+	},
+	Route{
+		"ListQuotas",
+		"GET",
+		config.QuotaURL,
+		ListQuotas,
+	},
+	Route{
+		"DeleteQuota",
+		"DELETE",
+		config.QuotaURL + "/{namespace}/{quota}",
+		DeleteQuota,
+	},
+	Route{
+		"ExportState",
+		"GET",
+		config.StateURL,
+		ExportState,
+	},
+	Route{
+		"ImportState",
+		"POST",
+		config.StateURL,
+		ImportState,
+	},
+	Route{
+		"VerifyState",
+		"POST",
+		config.StateURL + "/verify",
+		VerifyState,
+	},
+	Route{
+		"GetUpgradePreflight",
+		"GET",
+		config.PreflightURL,
+		GetUpgradePreflight,
+	},
+	Route{
+		"GetHealth",
+		"GET",
+		config.HealthURL,
+		GetHealth,
+	},
+	Route{
+		"StreamEvents",
+		"GET",
+		config.EventsURL,
+		StreamEvents,
+	},
+	Route{
+		"GetOpenAPISpec",
+		"GET",
+		config.OpenAPIURL,
+		GetOpenAPISpec,
+	},
+	Route{
+		"GetLogLevel",
+		"GET",
+		config.LogLevelURL,
+		GetLogLevel,
+	},
+	Route{
+		"SetLogLevel",
+		"POST",
+		config.LogLevelURL,
+		SetLogLevel,
+	},
+	Route{
+		"AddEphemeralVolume",
+		"PUT",
+		config.EphemeralURL + "/{volume}",
+		AddEphemeralVolume,
+	},
+	Route{
+		"DeleteEphemeralVolume",
+		"DELETE",
+		config.EphemeralURL + "/{volume}",
+		DeleteEphemeralVolume,
+	},
 }
@@ -0,0 +1,174 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	netappv1 "github.com/netapp/trident/persistent_store/crd/apis/netapp/v1"
+)
+
+/////////////////////////////////////////////////////////////////////////////
+//
+// This file contains the conversion webhook for Trident's custom resources.
+// Each kind is converted between API versions through the hub version, so
+// when a field changes, the CRD gains a new version along with a conversion
+// between it and the hub, and the API server converts stored objects as they
+// are read instead of Trident rewriting them in place.  Every Trident CRD is
+// only served as v1 today, so there are no conversions yet.
+//
+/////////////////////////////////////////////////////////////////////////////
+
+const (
+	// CRDConversionPath is the path at which Trident custom resources are converted
+	CRDConversionPath = "/convert/crds"
+
+	// maxConversionRequestBytes limits the size of conversion reviews, which carry every object in a list
+	maxConversionRequestBytes = 64 * 1024 * 1024
+)
+
+// hubVersion is the version that each kind is converted through.
+var hubVersion = netappv1.SchemeGroupVersion.Version
+
+// crdConversion converts the fields of a custom resource, held as unstructured JSON, between the hub
+// version and another version of its kind.  It need not set the object's apiVersion.
+type crdConversion func(object map[string]interface{}) error
+
+type conversionKey struct {
+	kind        string
+	fromVersion string
+	toVersion   string
+}
+
+// crdConversions holds the conversions to and from the hub version for each kind and version.
+var crdConversions = map[conversionKey]crdConversion{}
+
+// handleCRDConversionReview answers a conversion review of Trident custom resources.  The v1 and
+// v1beta1 ConversionReview APIs share a schema, so the response echoes the version of the request.
+func handleCRDConversionReview(w http.ResponseWriter, r *http.Request) {
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "conversion reviews must be posted", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxConversionRequestBytes))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	review := &apiextensionsv1.ConversionReview{}
+	if err = json.Unmarshal(body, review); err != nil || review.Request == nil {
+		http.Error(w, "invalid conversion review", http.StatusBadRequest)
+		return
+	}
+
+	review.Response = convertCustomResources(review.Request)
+	review.Request = nil
+
+	w.Header().Set("Content-Type", "application/json")
+	if err = json.NewEncoder(w).Encode(review); err != nil {
+		log.WithField("error", err).Error("Could not write conversion review response.")
+	}
+}
+
+// convertCustomResources converts each object in a conversion request.  If any object can't be
+// converted, the whole request fails, as the API server requires.
+func convertCustomResources(request *apiextensionsv1.ConversionRequest) *apiextensionsv1.ConversionResponse {
+
+	response := &apiextensionsv1.ConversionResponse{
+		UID:              request.UID,
+		ConvertedObjects: make([]runtime.RawExtension, 0, len(request.Objects)),
+		Result:           metav1.Status{Status: metav1.StatusSuccess},
+	}
+
+	for _, object := range request.Objects {
+		converted, err := convertCustomResource(object.Raw, request.DesiredAPIVersion)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"desiredAPIVersion": request.DesiredAPIVersion,
+				"error":             err,
+			}).Error("Conversion webhook could not convert a custom resource.")
+
+			response.ConvertedObjects = nil
+			response.Result = metav1.Status{
+				Status:  metav1.StatusFailure,
+				Message: err.Error(),
+			}
+			return response
+		}
+		response.ConvertedObjects = append(response.ConvertedObjects, runtime.RawExtension{Raw: converted})
+	}
+
+	return response
+}
+
+// convertCustomResource converts a custom resource to another version of its kind.
+func convertCustomResource(raw []byte, desiredAPIVersion string) ([]byte, error) {
+
+	object := make(map[string]interface{})
+	if err := json.Unmarshal(raw, &object); err != nil {
+		return nil, fmt.Errorf("could not decode custom resource; %v", err)
+	}
+
+	kind, _ := object["kind"].(string)
+	apiVersion, _ := object["apiVersion"].(string)
+
+	fromVersion, err := tridentAPIVersion(apiVersion)
+	if err != nil {
+		return nil, err
+	}
+	toVersion, err := tridentAPIVersion(desiredAPIVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	if fromVersion != toVersion {
+		if fromVersion != hubVersion {
+			if err = applyCRDConversion(object, kind, fromVersion, hubVersion); err != nil {
+				return nil, err
+			}
+		}
+		if toVersion != hubVersion {
+			if err = applyCRDConversion(object, kind, hubVersion, toVersion); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	object["apiVersion"] = desiredAPIVersion
+
+	return json.Marshal(object)
+}
+
+func applyCRDConversion(object map[string]interface{}, kind, fromVersion, toVersion string) error {
+
+	conversion, ok := crdConversions[conversionKey{kind: kind, fromVersion: fromVersion, toVersion: toVersion}]
+	if !ok {
+		return fmt.Errorf("%s cannot be converted from %s to %s", kind, fromVersion, toVersion)
+	}
+	if err := conversion(object); err != nil {
+		return fmt.Errorf("could not convert %s from %s to %s; %v", kind, fromVersion, toVersion, err)
+	}
+	return nil
+}
+
+// tridentAPIVersion returns the version part of an apiVersion in Trident's API group.
+func tridentAPIVersion(apiVersion string) (string, error) {
+
+	parts := strings.Split(apiVersion, "/")
+	if len(parts) != 2 || parts[0] != netappv1.GroupName || parts[1] == "" {
+		return "", fmt.Errorf("%s is not a Trident API version", apiVersion)
+	}
+	return parts[1], nil
+}
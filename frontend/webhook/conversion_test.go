@@ -0,0 +1,166 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func postConversionReview(
+	t *testing.T, desiredAPIVersion string, objects ...map[string]interface{},
+) *apiextensionsv1.ConversionResponse {
+
+	request := &apiextensionsv1.ConversionRequest{UID: types.UID("1234"), DesiredAPIVersion: desiredAPIVersion}
+	for _, object := range objects {
+		raw, err := json.Marshal(object)
+		if err != nil {
+			t.Fatal(err)
+		}
+		request.Objects = append(request.Objects, runtime.RawExtension{Raw: raw})
+	}
+
+	body, err := json.Marshal(&apiextensionsv1.ConversionReview{
+		TypeMeta: metav1.TypeMeta{APIVersion: "apiextensions.k8s.io/v1", Kind: "ConversionReview"},
+		Request:  request,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	recorder := httptest.NewRecorder()
+	handleCRDConversionReview(recorder, httptest.NewRequest(http.MethodPost, CRDConversionPath,
+		bytes.NewReader(body)))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	review := &apiextensionsv1.ConversionReview{}
+	if err = json.Unmarshal(recorder.Body.Bytes(), review); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "apiextensions.k8s.io/v1", review.APIVersion)
+	assert.Nil(t, review.Request)
+	if !assert.NotNil(t, review.Response) {
+		t.FailNow()
+	}
+	assert.Equal(t, types.UID("1234"), review.Response.UID)
+	return review.Response
+}
+
+func tridentVolumeObject(apiVersion string, fields map[string]interface{}) map[string]interface{} {
+	object := map[string]interface{}{
+		"apiVersion": apiVersion,
+		"kind":       "TridentVolume",
+		"metadata":   map[string]interface{}{"name": "pvc-1234", "namespace": "trident"},
+	}
+	for key, value := range fields {
+		object[key] = value
+	}
+	return object
+}
+
+func TestConvertCRDsSameVersion(t *testing.T) {
+	volume := tridentVolumeObject("trident.netapp.io/v1", map[string]interface{}{"pool": "aggr1"})
+
+	response := postConversionReview(t, "trident.netapp.io/v1", volume)
+
+	assert.Equal(t, metav1.StatusSuccess, response.Result.Status)
+	if assert.Len(t, response.ConvertedObjects, 1) {
+		converted := make(map[string]interface{})
+		assert.NoError(t, json.Unmarshal(response.ConvertedObjects[0].Raw, &converted))
+		assert.Equal(t, volume, converted)
+	}
+}
+
+func TestConvertCRDsThroughHub(t *testing.T) {
+	// Register a hypothetical v2 that renames a field, and a v3 that can't be converted to
+	key := conversionKey{kind: "TridentVolume", fromVersion: "v2", toVersion: hubVersion}
+	reverseKey := conversionKey{kind: "TridentVolume", fromVersion: hubVersion, toVersion: "v2"}
+	failingKey := conversionKey{kind: "TridentVolume", fromVersion: hubVersion, toVersion: "v3"}
+	crdConversions[key] = func(object map[string]interface{}) error {
+		object["pool"] = object["storagePool"]
+		delete(object, "storagePool")
+		return nil
+	}
+	crdConversions[reverseKey] = func(object map[string]interface{}) error {
+		object["storagePool"] = object["pool"]
+		delete(object, "pool")
+		return nil
+	}
+	crdConversions[failingKey] = func(object map[string]interface{}) error {
+		return fmt.Errorf("pool is required")
+	}
+	defer func() {
+		delete(crdConversions, key)
+		delete(crdConversions, reverseKey)
+		delete(crdConversions, failingKey)
+	}()
+
+	v1Volume := tridentVolumeObject("trident.netapp.io/v1", map[string]interface{}{"pool": "aggr1"})
+	v2Volume := tridentVolumeObject("trident.netapp.io/v2", map[string]interface{}{"storagePool": "aggr1"})
+
+	response := postConversionReview(t, "trident.netapp.io/v1", v2Volume, v1Volume)
+	assert.Equal(t, metav1.StatusSuccess, response.Result.Status)
+	if assert.Len(t, response.ConvertedObjects, 2) {
+		for _, raw := range response.ConvertedObjects {
+			converted := make(map[string]interface{})
+			assert.NoError(t, json.Unmarshal(raw.Raw, &converted))
+			assert.Equal(t, v1Volume, converted)
+		}
+	}
+
+	response = postConversionReview(t, "trident.netapp.io/v2", v1Volume)
+	assert.Equal(t, metav1.StatusSuccess, response.Result.Status)
+	if assert.Len(t, response.ConvertedObjects, 1) {
+		converted := make(map[string]interface{})
+		assert.NoError(t, json.Unmarshal(response.ConvertedObjects[0].Raw, &converted))
+		assert.Equal(t, v2Volume, converted)
+	}
+
+	// A failed conversion fails the whole review
+	response = postConversionReview(t, "trident.netapp.io/v3", v1Volume)
+	assert.Equal(t, metav1.StatusFailure, response.Result.Status)
+	assert.Contains(t, response.Result.Message, "pool is required")
+	assert.Empty(t, response.ConvertedObjects)
+}
+
+func TestConvertCRDsUnsupported(t *testing.T) {
+	volume := tridentVolumeObject("trident.netapp.io/v1", nil)
+
+	tests := []struct {
+		name              string
+		desiredAPIVersion string
+		object            map[string]interface{}
+	}{
+		{"unknown version", "trident.netapp.io/v9", volume},
+		{"other group", "example.com/v1", volume},
+		{"other group object", "trident.netapp.io/v1", tridentVolumeObject("example.com/v1", nil)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			response := postConversionReview(t, test.desiredAPIVersion, test.object)
+			assert.Equal(t, metav1.StatusFailure, response.Result.Status)
+			assert.Empty(t, response.ConvertedObjects)
+		})
+	}
+}
+
+func TestConvertCRDsInvalidRequest(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	handleCRDConversionReview(recorder, httptest.NewRequest(http.MethodGet, CRDConversionPath, nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	handleCRDConversionReview(recorder, httptest.NewRequest(http.MethodPost, CRDConversionPath,
+		bytes.NewReader([]byte("{}"))))
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
@@ -0,0 +1,166 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	log "github.com/sirupsen/logrus"
+	admissionv1 "k8s.io/api/admission/v1"
+	k8sstoragev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/netapp/trident/config"
+	k8shelper "github.com/netapp/trident/frontend/csi/helpers/kubernetes"
+	"github.com/netapp/trident/utils"
+)
+
+const (
+	// StorageClassPath is the path at which storage classes are validated
+	StorageClassPath = "/validate/storageclasses"
+
+	// maxRequestBytes limits the size of admission reviews, which carry at most a storage class
+	maxRequestBytes = 1024 * 1024
+)
+
+// Server is an admission webhook that Kubernetes calls before persisting storage classes, so that
+// classes with parameters Trident can't use are rejected when they are applied.  It also serves the
+// conversion webhook for Trident's CRDs.  The API server only connects over TLS, and it authenticates
+// Trident by the CA bundle in the webhook configuration.
+type Server struct {
+	server       *http.Server
+	certReloader *utils.CertReloader
+	options      utils.TLSOptions
+}
+
+func NewServer(address, port, serverCertFile, serverKeyFile string, options utils.TLSOptions) (*Server, error) {
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(StorageClassPath, handleStorageClassReview)
+	mux.HandleFunc(CRDConversionPath, handleCRDConversionReview)
+
+	// The API server authenticates Trident, not the reverse, so client certificates aren't checked
+	certReloader, err := utils.NewCertReloader("", serverCertFile, serverKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("could not load webhook certificates: %v", err)
+	}
+	options.RequireClientCert = false
+
+	server := &Server{
+		server: &http.Server{
+			Addr:         fmt.Sprintf("%s:%s", address, port),
+			Handler:      mux,
+			TLSConfig:    certReloader.ServerTLSConfig(options),
+			ReadTimeout:  config.HTTPTimeout,
+			WriteTimeout: config.HTTPTimeout,
+		},
+		certReloader: certReloader,
+		options:      options,
+	}
+
+	log.WithField("address", server.server.Addr).Info("Initializing admission webhook frontend.")
+
+	return server, nil
+}
+
+func (s *Server) Activate() error {
+	s.certReloader.Start(s.options.ReloadInterval)
+	go func() {
+		log.WithField("address", s.server.Addr).Infof("Activating admission webhook frontend.")
+		err := s.server.ListenAndServeTLS("", "")
+		if err != nil && err != http.ErrServerClosed {
+			log.Fatal(err)
+		}
+	}()
+	return nil
+}
+
+func (s *Server) Deactivate() error {
+	log.WithField("address", s.server.Addr).Infof("Deactivating admission webhook frontend.")
+	s.certReloader.Stop()
+	ctx, cancel := context.WithTimeout(context.Background(), config.HTTPTimeout)
+	defer cancel()
+	return s.server.Shutdown(ctx)
+}
+
+func (s *Server) GetName() string {
+	return "admission webhook"
+}
+
+func (s *Server) Version() string {
+	return config.OrchestratorAPIVersion
+}
+
+// handleStorageClassReview answers an admission review of a storage class.  The v1 and v1beta1
+// AdmissionReview APIs share a schema, so the response echoes the version of the request.
+func handleStorageClassReview(w http.ResponseWriter, r *http.Request) {
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "admission reviews must be posted", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestBytes))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	review := &admissionv1.AdmissionReview{}
+	if err = json.Unmarshal(body, review); err != nil || review.Request == nil {
+		http.Error(w, "invalid admission review", http.StatusBadRequest)
+		return
+	}
+
+	review.Response = reviewStorageClass(review.Request)
+	review.Request = nil
+
+	w.Header().Set("Content-Type", "application/json")
+	if err = json.NewEncoder(w).Encode(review); err != nil {
+		log.WithField("error", err).Error("Could not write admission review response.")
+	}
+}
+
+// reviewStorageClass decides whether a storage class may be admitted.
+func reviewStorageClass(request *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+
+	response := &admissionv1.AdmissionResponse{UID: request.UID, Allowed: true}
+
+	if request.Operation != admissionv1.Create {
+		return response
+	}
+
+	sc := &k8sstoragev1.StorageClass{}
+	if err := json.Unmarshal(request.Object.Raw, sc); err != nil {
+		response.Allowed = false
+		response.Result = &metav1.Status{
+			Status:  metav1.StatusFailure,
+			Message: fmt.Sprintf("could not decode storage class; %v", err),
+			Reason:  metav1.StatusReasonBadRequest,
+			Code:    http.StatusBadRequest,
+		}
+		return response
+	}
+
+	if err := k8shelper.ValidateStorageClass(sc); err != nil {
+		log.WithFields(log.Fields{
+			"name":       sc.Name,
+			"parameters": sc.Parameters,
+			"error":      err,
+		}).Info("Admission webhook rejected a storage class.")
+
+		response.Allowed = false
+		response.Result = &metav1.Status{
+			Status:  metav1.StatusFailure,
+			Message: err.Error(),
+			Reason:  metav1.StatusReasonInvalid,
+			Code:    http.StatusUnprocessableEntity,
+		}
+	}
+
+	return response
+}
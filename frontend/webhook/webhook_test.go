@@ -0,0 +1,93 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	k8sstoragev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/netapp/trident/frontend/csi"
+)
+
+func postStorageClassReview(
+	t *testing.T, apiVersion string, operation admissionv1.Operation, parameters map[string]string,
+) *admissionv1.AdmissionReview {
+
+	sc, err := json.Marshal(&k8sstoragev1.StorageClass{
+		TypeMeta:    metav1.TypeMeta{APIVersion: "storage.k8s.io/v1", Kind: "StorageClass"},
+		ObjectMeta:  metav1.ObjectMeta{Name: "gold"},
+		Provisioner: csi.Provisioner,
+		Parameters:  parameters,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body, err := json.Marshal(&admissionv1.AdmissionReview{
+		TypeMeta: metav1.TypeMeta{APIVersion: apiVersion, Kind: "AdmissionReview"},
+		Request: &admissionv1.AdmissionRequest{
+			UID:       types.UID("1234"),
+			Operation: operation,
+			Object:    runtime.RawExtension{Raw: sc},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	recorder := httptest.NewRecorder()
+	handleStorageClassReview(recorder, httptest.NewRequest(http.MethodPost, StorageClassPath, bytes.NewReader(body)))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	review := &admissionv1.AdmissionReview{}
+	if err = json.Unmarshal(recorder.Body.Bytes(), review); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, apiVersion, review.APIVersion)
+	assert.Nil(t, review.Request)
+	if assert.NotNil(t, review.Response) {
+		assert.Equal(t, types.UID("1234"), review.Response.UID)
+	}
+	return review
+}
+
+func TestHandleStorageClassReview(t *testing.T) {
+
+	for _, apiVersion := range []string{"admission.k8s.io/v1", "admission.k8s.io/v1beta1"} {
+		review := postStorageClassReview(t, apiVersion, admissionv1.Create, map[string]string{"media": "ssd"})
+		assert.True(t, review.Response.Allowed, apiVersion)
+
+		review = postStorageClassReview(t, apiVersion, admissionv1.Create, map[string]string{"media": "tape"})
+		assert.False(t, review.Response.Allowed, apiVersion)
+		if assert.NotNil(t, review.Response.Result) {
+			assert.Contains(t, review.Response.Result.Message, "media")
+			assert.Equal(t, int32(http.StatusUnprocessableEntity), review.Response.Result.Code)
+		}
+	}
+
+	// Existing storage classes aren't rejected by later reviews
+	review := postStorageClassReview(t, "admission.k8s.io/v1", admissionv1.Update, map[string]string{"media": "tape"})
+	assert.True(t, review.Response.Allowed)
+}
+
+func TestHandleStorageClassReviewInvalidRequest(t *testing.T) {
+
+	recorder := httptest.NewRecorder()
+	handleStorageClassReview(recorder, httptest.NewRequest(http.MethodGet, StorageClassPath, nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	handleStorageClassReview(recorder, httptest.NewRequest(http.MethodPost, StorageClassPath,
+		bytes.NewReader([]byte(`{"kind": "AdmissionReview"}`))))
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
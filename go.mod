@@ -9,10 +9,10 @@ require (
 	github.com/Azure/go-autorest/autorest/date v0.2.0
 	github.com/Azure/go-autorest/autorest/to v0.3.0 // indirect
 	github.com/Azure/go-autorest/autorest/validation v0.2.0 // indirect
-	github.com/Microsoft/go-winio v0.4.14 // indirect
+	github.com/Microsoft/go-winio v0.4.14
 	github.com/RoaringBitmap/roaring v0.4.23
 	github.com/cenkalti/backoff/v4 v4.0.2
-	github.com/container-storage-interface/spec v1.2.0
+	github.com/container-storage-interface/spec v1.3.0
 	github.com/coreos/etcd v3.3.20+incompatible
 	github.com/docker/go-connections v0.4.0 // indirect
 	github.com/docker/go-plugins-helpers v0.0.0-20200102110956-c9a8a2d92ccc
@@ -24,6 +24,7 @@ require (
         github.com/google/go-cmp v0.4.0
         github.com/google/uuid v1.1.1
         github.com/gorilla/mux v1.7.4
+        github.com/lib/pq v1.10.9
         github.com/mitchellh/copystructure v1.0.0
         github.com/mitchellh/hashstructure v1.0.0
         github.com/olekukonko/tablewriter v0.0.4
@@ -0,0 +1,201 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+
+	"github.com/netapp/trident/config"
+)
+
+const serviceAccountNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
+// leaderElection elects one of several Trident controller replicas, using a Kubernetes lease, to be the
+// only one that bootstraps and serves requests.  The other replicas wait on standby, and one of them takes
+// over within a lease duration if the leader stops renewing the lease, such as when its node fails.
+type leaderElection struct {
+	kubeClient   kubernetes.Interface
+	namespace    string
+	identity     string
+	podName      string
+	podNamespace string
+	elector      *leaderelection.LeaderElector
+	elected      chan struct{}
+	ctx          context.Context
+	cancel       context.CancelFunc
+}
+
+// newLeaderElection prepares a leader election in the specified namespace, which defaults to the
+// namespace of this pod.
+func newLeaderElection(
+	apiServerIP, kubeConfigPath, namespace string, leaseDuration, renewDeadline, retryPeriod time.Duration,
+) (*leaderElection, error) {
+
+	kubeConfig, err := clientcmd.BuildConfigFromFlags(apiServerIP, kubeConfigPath)
+	if err != nil {
+		return nil, fmt.Errorf("could not create Kubernetes client configuration; %v", err)
+	}
+	kubeClient, err := kubernetes.NewForConfig(kubeConfig)
+	if err != nil {
+		return nil, fmt.Errorf("could not create Kubernetes client; %v", err)
+	}
+
+	if namespace == "" {
+		namespace = getPodNamespace()
+	}
+	if namespace == "" {
+		return nil, fmt.Errorf("could not determine the namespace for leader election")
+	}
+
+	podName := os.Getenv("POD_NAME")
+	identity := podName
+	if identity == "" {
+		if identity, err = os.Hostname(); err != nil {
+			return nil, fmt.Errorf("could not determine the identity for leader election; %v", err)
+		}
+	}
+
+	e := &leaderElection{
+		kubeClient:   kubeClient,
+		namespace:    namespace,
+		identity:     identity,
+		podName:      podName,
+		podNamespace: getPodNamespace(),
+		elected:      make(chan struct{}),
+	}
+	e.ctx, e.cancel = context.WithCancel(context.Background())
+
+	lock := &resourcelock.LeaseLock{
+		LeaseMeta: metav1.ObjectMeta{
+			Name:      config.LeaderElectionLeaseName,
+			Namespace: namespace,
+		},
+		Client:     kubeClient.CoordinationV1(),
+		LockConfig: resourcelock.ResourceLockConfig{Identity: identity},
+	}
+
+	e.elector, err = leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock:            lock,
+		LeaseDuration:   leaseDuration,
+		RenewDeadline:   renewDeadline,
+		RetryPeriod:     retryPeriod,
+		ReleaseOnCancel: true,
+		Name:            config.LeaderElectionLeaseName,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: e.onStartedLeading,
+			OnStoppedLeading: e.onStoppedLeading,
+			OnNewLeader:      e.onNewLeader,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not create leader elector; %v", err)
+	}
+
+	return e, nil
+}
+
+// getPodNamespace returns the namespace of this pod, or an empty string if not running in a pod.
+func getPodNamespace() string {
+	if namespace := os.Getenv("POD_NAMESPACE"); namespace != "" {
+		return namespace
+	}
+	if namespaceBytes, err := ioutil.ReadFile(serviceAccountNamespaceFile); err == nil {
+		return strings.TrimSpace(string(namespaceBytes))
+	}
+	return ""
+}
+
+// Run starts campaigning for leadership.  A replica that was the leader before it restarted may still
+// carry the leader label, so the label is removed until this replica is elected.
+func (e *leaderElection) Run() {
+
+	if err := e.labelPod(false); err != nil {
+		log.WithField("pod", e.podName).Warningf("Could not remove leader label from pod. %v", err)
+	}
+
+	log.WithFields(log.Fields{
+		"lease":     config.LeaderElectionLeaseName,
+		"namespace": e.namespace,
+		"identity":  e.identity,
+	}).Info("Campaigning for leadership of the Trident controller.")
+
+	go e.elector.Run(e.ctx)
+}
+
+// Elected returns a channel that is closed when this replica becomes the leader.
+func (e *leaderElection) Elected() <-chan struct{} {
+	return e.elected
+}
+
+// Stop releases the lease, if held, so that a standby replica may take over without waiting for it
+// to expire.
+func (e *leaderElection) Stop() {
+	e.cancel()
+}
+
+func (e *leaderElection) onStartedLeading(context.Context) {
+
+	log.WithField("identity", e.identity).Info("Elected leader of the Trident controller.")
+
+	if err := e.labelPod(true); err != nil {
+		log.WithField("pod", e.podName).Errorf("Could not add leader label to pod. %v", err)
+	}
+	close(e.elected)
+}
+
+// onStoppedLeading is called when the lease is lost or released.  A replica that loses the lease while
+// running cannot safely continue, so it exits and is restarted as a standby.
+func (e *leaderElection) onStoppedLeading() {
+
+	if e.ctx.Err() != nil {
+		log.WithField("identity", e.identity).Info("Released leadership of the Trident controller.")
+		return
+	}
+	log.WithField("identity", e.identity).Fatal("Lost leadership of the Trident controller.")
+}
+
+func (e *leaderElection) onNewLeader(identity string) {
+	if identity != e.identity {
+		log.WithField("leader", identity).Info("Trident controller is on standby.")
+	}
+}
+
+// labelPod adds or removes the leader label on this pod.  The Trident service selects only the labeled
+// pod, so that node plugins and clients reach the leader.
+func (e *leaderElection) labelPod(leader bool) error {
+
+	if e.podName == "" || e.podNamespace == "" {
+		return nil
+	}
+
+	var labelValue interface{}
+	if leader {
+		labelValue = config.LeaderLabelValue
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": map[string]interface{}{config.LeaderLabelKey: labelValue},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = e.kubeClient.CoreV1().Pods(e.podNamespace).Patch(e.ctx, e.podName, types.MergePatchType, patch,
+		metav1.PatchOptions{})
+	return err
+}
@@ -0,0 +1,154 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	AuditSourceREST   = "REST"
+	AuditSourceCSI    = "CSI"
+	AuditSourceDocker = "Docker"
+	AuditSourceGRPC   = "gRPC"
+
+	AuditResourceBackend      = "backend"
+	AuditResourceVolume       = "volume"
+	AuditResourceSnapshot     = "snapshot"
+	AuditResourceStorageClass = "storageClass"
+	AuditResourceNode         = "node"
+	AuditResourceVolumeGroup  = "volumeGroup"
+	AuditResourceCloneGrant   = "cloneGrant"
+	AuditResourceQuota        = "quota"
+	AuditResourceState        = "state"
+	AuditResourceLogLevel     = "logLevel"
+
+	AuditResultSuccess = "success"
+	AuditResultFailure = "failure"
+)
+
+// AuditEvent records who changed Trident's state, what was changed, and when.
+type AuditEvent struct {
+	Time time.Time `json:"time"`
+	// Source is the frontend through which the change was requested
+	Source string `json:"source"`
+	// Identity identifies the client, such as the common name of its certificate or its address
+	Identity  string `json:"identity,omitempty"`
+	UserAgent string `json:"userAgent,omitempty"`
+	// Namespace is the Kubernetes namespace on whose behalf the change was made, if any
+	Namespace string `json:"namespace,omitempty"`
+	Operation string `json:"operation"`
+	Resource  string `json:"resource"`
+	Name      string `json:"name,omitempty"`
+	Result    string `json:"result"`
+	Error     string `json:"error,omitempty"`
+}
+
+// AuditLog appends audit events to a file, one JSON object per line.  The file is never
+// truncated or rotated by Trident.
+type AuditLog struct {
+	file  *os.File
+	mutex *sync.Mutex
+}
+
+var auditLog *AuditLog
+
+// NewAuditLog opens an audit log file for appending, creating it and its directory if needed.
+func NewAuditLog(path string) (*AuditLog, error) {
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("could not create audit log directory; %v", err)
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("could not open audit log file %s; %v", path, err)
+	}
+
+	return &AuditLog{file: file, mutex: &sync.Mutex{}}, nil
+}
+
+// Record appends an event to the audit log, setting its time if not already set.
+func (a *AuditLog) Record(event *AuditEvent) error {
+
+	if event.Time.IsZero() {
+		event.Time = time.Now().UTC()
+	}
+	eventBytes, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if _, err = a.file.Write(append(eventBytes, '\n')); err != nil {
+		return err
+	}
+	return a.file.Sync()
+}
+
+// Close closes the audit log file.
+func (a *AuditLog) Close() error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	return a.file.Close()
+}
+
+// InitAuditLog starts recording audit events to the specified file.
+func InitAuditLog(path string) error {
+
+	newAuditLog, err := NewAuditLog(path)
+	if err != nil {
+		return err
+	}
+	auditLog = newAuditLog
+
+	log.WithField("auditLogLocation", path).Info("Initialized audit log.")
+	return nil
+}
+
+// AuditEnabled reports whether audit events are being recorded.
+func AuditEnabled() bool {
+	return auditLog != nil
+}
+
+// Audit records an event in the audit log, if one was initialized.  Failures are logged, since
+// they must not fail the audited operation.
+func Audit(event *AuditEvent) {
+
+	if auditLog == nil {
+		return
+	}
+
+	if event.Result == "" {
+		if event.Error == "" {
+			event.Result = AuditResultSuccess
+		} else {
+			event.Result = AuditResultFailure
+		}
+	}
+
+	if err := auditLog.Record(event); err != nil {
+		log.WithFields(log.Fields{
+			"operation": event.Operation,
+			"resource":  event.Resource,
+			"name":      event.Name,
+			"error":     err,
+		}).Error("Could not record audit event.")
+	}
+}
+
+// AuditError returns the message of an error for an audit event, or an empty string.
+func AuditError(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
@@ -0,0 +1,84 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package logging
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditLog(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "audit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "audit", "trident-audit.log")
+
+	// Nothing is recorded until the audit log is initialized
+	Audit(&AuditEvent{Operation: "AddBackend"})
+	assert.False(t, AuditEnabled())
+
+	err = InitAuditLog(path)
+	assert.NoError(t, err)
+	defer func() {
+		auditLog.Close()
+		auditLog = nil
+	}()
+	assert.True(t, AuditEnabled())
+
+	Audit(&AuditEvent{
+		Source:    AuditSourceREST,
+		Identity:  "127.0.0.1:1234",
+		Operation: "AddBackend",
+		Resource:  AuditResourceBackend,
+		Name:      "backend1",
+	})
+	Audit(&AuditEvent{
+		Source:    AuditSourceCSI,
+		Namespace: "ns1",
+		Operation: "DeleteVolume",
+		Resource:  AuditResourceVolume,
+		Name:      "pvc-1",
+		Error:     AuditError(fmt.Errorf("volume is busy")),
+	})
+
+	// Reopening the log appends to it
+	auditLog.Close()
+	err = InitAuditLog(path)
+	assert.NoError(t, err)
+	Audit(&AuditEvent{Operation: "DeleteBackend", Resource: AuditResourceBackend, Name: "backend1"})
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	events := make([]AuditEvent, 0)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var event AuditEvent
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+		events = append(events, event)
+	}
+
+	if assert.Len(t, events, 3) {
+		assert.Equal(t, "AddBackend", events[0].Operation)
+		assert.Equal(t, "127.0.0.1:1234", events[0].Identity)
+		assert.Equal(t, AuditResultSuccess, events[0].Result)
+		assert.False(t, events[0].Time.IsZero())
+		assert.Equal(t, "ns1", events[1].Namespace)
+		assert.Equal(t, AuditResultFailure, events[1].Result)
+		assert.Equal(t, "volume is busy", events[1].Error)
+		assert.Equal(t, "DeleteBackend", events[2].Operation)
+	}
+}
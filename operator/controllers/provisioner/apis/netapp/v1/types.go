@@ -3,6 +3,7 @@
 package v1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -39,6 +40,32 @@ type TridentProvisionerSpec struct {
 	KubeletDir       string   `json:"kubeletDir,omitempty"`
 	Wipeout          []string `json:"wipeout,omitempty"`
 	ImagePullSecrets []string `json:"imagePullSecrets,omitempty"`
+	// ControllerReplicas is the number of Trident controller replicas, of which one is elected leader
+	ControllerReplicas int `json:"controllerReplicas,omitempty"`
+	// FSGroupPolicy is the CSI driver's fsGroupPolicy, which controls whether kubelet applies a pod's fsGroup
+	FSGroupPolicy string `json:"fsGroupPolicy,omitempty"`
+	// EnableStorageCapacity has the scheduler consult the storage capacity Trident publishes
+	EnableStorageCapacity bool `json:"enableStorageCapacity,omitempty"`
+	// EnableSELinuxMount has kubelet mount volumes with each pod's SELinux context instead of relabeling them
+	EnableSELinuxMount bool `json:"enableSELinuxMount,omitempty"`
+	// Windows also installs the node plugin on Windows nodes, which must be running CSI Proxy
+	Windows bool `json:"windows,omitempty"`
+	// ControllerPluginNodeSelector adds node selector labels to the Trident controller pods
+	ControllerPluginNodeSelector map[string]string `json:"controllerPluginNodeSelector,omitempty"`
+	// ControllerPluginTolerations are the tolerations of the Trident controller pods
+	ControllerPluginTolerations []corev1.Toleration `json:"controllerPluginTolerations,omitempty"`
+	// ControllerPluginAffinity replaces the anti-affinity that spreads Trident controller pods across nodes
+	ControllerPluginAffinity *corev1.Affinity `json:"controllerPluginAffinity,omitempty"`
+	// ControllerPluginResources are the resource requests and limits of the Trident controller container
+	ControllerPluginResources *corev1.ResourceRequirements `json:"controllerPluginResources,omitempty"`
+	// NodePluginNodeSelector adds node selector labels to the Trident node pods
+	NodePluginNodeSelector map[string]string `json:"nodePluginNodeSelector,omitempty"`
+	// NodePluginTolerations replaces the default tolerations, which let Trident node pods run on every node
+	NodePluginTolerations []corev1.Toleration `json:"nodePluginTolerations,omitempty"`
+	// NodePluginAffinity is the affinity of the Trident node pods
+	NodePluginAffinity *corev1.Affinity `json:"nodePluginAffinity,omitempty"`
+	// NodePluginResources are the resource requests and limits of the Trident node container
+	NodePluginResources *corev1.ResourceRequirements `json:"nodePluginResources,omitempty"`
 }
 
 // TridentProvisionerStatus defines the observed state of TridentProvisioner
@@ -56,4 +83,14 @@ type TridentProvisionerSpecValues struct {
 	TridentImage  string `json:"tridentImage"`
 	ImageRegistry string `json:"imageRegistry"`
 	KubeletDir    string `json:"kubeletDir"`
+	// ControllerReplicas is the number of Trident controller replicas
+	ControllerReplicas string `json:"controllerReplicas"`
+	// FSGroupPolicy is the CSI driver's fsGroupPolicy
+	FSGroupPolicy string `json:"fsGroupPolicy"`
+	// EnableStorageCapacity is whether the scheduler consults Trident's storage capacity
+	EnableStorageCapacity string `json:"enableStorageCapacity"`
+	// EnableSELinuxMount is whether kubelet mounts volumes with each pod's SELinux context
+	EnableSELinuxMount string `json:"enableSELinuxMount"`
+	// Windows is whether the node plugin is installed on Windows nodes
+	Windows string `json:"windows"`
 }
@@ -7,6 +7,7 @@
 package v1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -84,6 +85,54 @@ func (in *TridentProvisionerSpec) DeepCopyInto(out *TridentProvisionerSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.ControllerPluginNodeSelector != nil {
+		in, out := &in.ControllerPluginNodeSelector, &out.ControllerPluginNodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ControllerPluginTolerations != nil {
+		in, out := &in.ControllerPluginTolerations, &out.ControllerPluginTolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ControllerPluginAffinity != nil {
+		in, out := &in.ControllerPluginAffinity, &out.ControllerPluginAffinity
+		*out = new(corev1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ControllerPluginResources != nil {
+		in, out := &in.ControllerPluginResources, &out.ControllerPluginResources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodePluginNodeSelector != nil {
+		in, out := &in.NodePluginNodeSelector, &out.NodePluginNodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.NodePluginTolerations != nil {
+		in, out := &in.NodePluginTolerations, &out.NodePluginTolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NodePluginAffinity != nil {
+		in, out := &in.NodePluginAffinity, &out.NodePluginAffinity
+		*out = new(corev1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodePluginResources != nil {
+		in, out := &in.NodePluginResources, &out.NodePluginResources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -36,6 +36,12 @@ const (
 	TridentNodeLabelValue = "node.csi.trident.netapp.io"
 	TridentNodeLabel      = TridentNodeLabelKey + "=" + TridentNodeLabelValue
 
+	TridentWindowsNodeLabelKey   = TridentAppLabelKey
+	TridentWindowsNodeLabelValue = "windows-node.csi.trident.netapp.io"
+	TridentWindowsNodeLabel      = TridentWindowsNodeLabelKey + "=" + TridentWindowsNodeLabelValue
+
+	TridentWindowsDaemonSetName = "trident-csi-windows"
+
 	TridentVersionPodLabelKey   = TridentAppLabelKey
 	TridentVersionPodLabelValue = "pod.version.trident.netapp.io"
 	TridentVersionPodLabel      = TridentVersionPodLabelKey + "=" + TridentVersionPodLabelValue
@@ -58,6 +64,9 @@ const (
 	// DefaultKubeletDir is the host location of kubelet's internal state
 	DefaultKubeletDir = "/var/lib/kubelet"
 
+	// webhookName is the name of the storage class admission webhook configuration
+	webhookName = "trident-csi"
+
 	CRAPIVersionKey = "apiVersion"
 	CRController    = "controller"
 	CRKind          = "kind"
@@ -3,6 +3,7 @@
 package installer
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -31,13 +32,18 @@ import (
 
 const (
 	// CRD names
-	BackendCRDName      = "tridentbackends.trident.netapp.io"
-	NodeCRDName         = "tridentnodes.trident.netapp.io"
-	StorageClassCRDName = "tridentstorageclasses.trident.netapp.io"
-	TransactionCRDName  = "tridenttransactions.trident.netapp.io"
-	VersionCRDName      = "tridentversions.trident.netapp.io"
-	VolumeCRDName       = "tridentvolumes.trident.netapp.io"
-	SnapshotCRDName     = "tridentsnapshots.trident.netapp.io"
+	BackendCRDName       = "tridentbackends.trident.netapp.io"
+	NodeCRDName          = "tridentnodes.trident.netapp.io"
+	StorageClassCRDName  = "tridentstorageclasses.trident.netapp.io"
+	TransactionCRDName   = "tridenttransactions.trident.netapp.io"
+	VersionCRDName       = "tridentversions.trident.netapp.io"
+	VolumeCRDName        = "tridentvolumes.trident.netapp.io"
+	SnapshotCRDName      = "tridentsnapshots.trident.netapp.io"
+	ScheduleCRDName      = "tridentsnapshotschedules.trident.netapp.io"
+	VolumeGroupCRDName   = "tridentvolumegroups.trident.netapp.io"
+	CloneGrantCRDName    = "tridentclonegrants.trident.netapp.io"
+	QuotaCRDName         = "tridentquotas.trident.netapp.io"
+	BackendConfigCRDName = "tridentbackendconfigs.trident.netapp.io"
 
 	VolumeSnapshotCRDName        = "volumesnapshots.snapshot.storage.k8s.io"
 	VolumeSnapshotClassCRDName   = "volumesnapshotclasses.snapshot.storage.k8s.io"
@@ -57,6 +63,20 @@ var (
 
 	imagePullSecrets []string
 
+	controllerReplicas int
+
+	// Placement and resources of the controller and node plugin pods
+	controllerScheduling *k8sclient.PodScheduling
+	nodeScheduling       *k8sclient.PodScheduling
+
+	fsGroupPolicy string
+
+	enableStorageCapacity bool
+
+	enableSELinuxMount bool
+
+	windows bool
+
 	k8sTimeout time.Duration
 
 	appLabel      string
@@ -71,6 +91,11 @@ var (
 		VersionCRDName,
 		VolumeCRDName,
 		SnapshotCRDName,
+		ScheduleCRDName,
+		VolumeGroupCRDName,
+		CloneGrantCRDName,
+		QuotaCRDName,
+		BackendConfigCRDName,
 	}
 
 	AlphaCRDNames = []string{
@@ -266,6 +291,11 @@ func (i *Installer) InstallOrPatchTrident(cr netappv1.TridentProvisioner,
 	kubeletDir = DefaultKubeletDir
 
 	imagePullSecrets = []string{}
+	controllerReplicas = 1
+	fsGroupPolicy = ""
+	enableStorageCapacity = false
+	enableSELinuxMount = false
+	windows = false
 
 	// Get values from CR
 	csi = true
@@ -280,6 +310,36 @@ func (i *Installer) InstallOrPatchTrident(cr netappv1.TridentProvisioner,
 	if len(cr.Spec.ImagePullSecrets) != 0 {
 		imagePullSecrets = cr.Spec.ImagePullSecrets
 	}
+	if cr.Spec.ControllerReplicas > 1 {
+		controllerReplicas = cr.Spec.ControllerReplicas
+	}
+	controllerScheduling = &k8sclient.PodScheduling{
+		NodeSelector: cr.Spec.ControllerPluginNodeSelector,
+		Tolerations:  cr.Spec.ControllerPluginTolerations,
+		Affinity:     cr.Spec.ControllerPluginAffinity,
+		Resources:    cr.Spec.ControllerPluginResources,
+	}
+	nodeScheduling = &k8sclient.PodScheduling{
+		NodeSelector: cr.Spec.NodePluginNodeSelector,
+		Tolerations:  cr.Spec.NodePluginTolerations,
+		Affinity:     cr.Spec.NodePluginAffinity,
+		Resources:    cr.Spec.NodePluginResources,
+	}
+	if cr.Spec.FSGroupPolicy != "" {
+		if !k8sclient.IsValidFSGroupPolicy(cr.Spec.FSGroupPolicy) {
+			return nil, "", fmt.Errorf("'%s' is not a valid fsGroup policy", cr.Spec.FSGroupPolicy)
+		}
+		fsGroupPolicy = cr.Spec.FSGroupPolicy
+	}
+	if cr.Spec.EnableStorageCapacity {
+		enableStorageCapacity = true
+	}
+	if cr.Spec.EnableSELinuxMount {
+		enableSELinuxMount = true
+	}
+	if cr.Spec.Windows {
+		windows = true
+	}
 	if cr.Spec.ImageRegistry != "" {
 		imageRegistry = cr.Spec.ImageRegistry
 	}
@@ -412,6 +472,13 @@ func (i *Installer) InstallOrPatchTrident(cr netappv1.TridentProvisioner,
 			return nil, "", returnError
 		}
 
+		// Create or update the storage class admission webhook
+		returnError = i.createOrPatchTridentWebhook(controllingCRDetails, labels)
+		if returnError != nil {
+			returnError = fmt.Errorf("could not create the Trident admission webhook; %v", returnError)
+			return nil, "", returnError
+		}
+
 		// Create or update the Trident CSI deployment
 		returnError = i.createOrPatchTridentDeployment(controllingCRDetails, labels, shouldUpdate)
 		if returnError != nil {
@@ -425,6 +492,13 @@ func (i *Installer) InstallOrPatchTrident(cr netappv1.TridentProvisioner,
 			returnError = fmt.Errorf("could not create the Trident DaemonSet; %v", returnError)
 			return nil, "", returnError
 		}
+
+		// Create, update or remove the Trident CSI daemonset for Windows nodes
+		returnError = i.createOrPatchTridentWindowsDaemonSet(controllingCRDetails, labels, shouldUpdate)
+		if returnError != nil {
+			returnError = fmt.Errorf("could not create the Trident Windows DaemonSet; %v", returnError)
+			return nil, "", returnError
+		}
 	}
 
 	// Wait for Trident pod to be running
@@ -449,6 +523,11 @@ func (i *Installer) InstallOrPatchTrident(cr netappv1.TridentProvisioner,
 		ImageRegistry: imageRegistry,
 		IPv6: strconv.FormatBool(useIPv6),
 		KubeletDir: kubeletDir,
+		ControllerReplicas: strconv.Itoa(controllerReplicas),
+		FSGroupPolicy: fsGroupPolicy,
+		EnableStorageCapacity: strconv.FormatBool(enableStorageCapacity),
+		EnableSELinuxMount: strconv.FormatBool(enableSELinuxMount),
+		Windows: strconv.FormatBool(windows),
 	}
 
 	log.WithFields(log.Fields{
@@ -581,6 +660,21 @@ func (i *Installer) createCRDs() error {
 	if err = i.createCRD(SnapshotCRDName, k8sclient.GetSnapshotCRDYAML(useCRDv1)); err != nil {
 		return err
 	}
+	if err = i.createCRD(ScheduleCRDName, k8sclient.GetSnapshotScheduleCRDYAML(useCRDv1)); err != nil {
+		return err
+	}
+	if err = i.createCRD(VolumeGroupCRDName, k8sclient.GetVolumeGroupCRDYAML(useCRDv1)); err != nil {
+		return err
+	}
+	if err = i.createCRD(CloneGrantCRDName, k8sclient.GetCloneGrantCRDYAML(useCRDv1)); err != nil {
+		return err
+	}
+	if err = i.createCRD(QuotaCRDName, k8sclient.GetQuotaCRDYAML(useCRDv1)); err != nil {
+		return err
+	}
+	if err = i.createCRD(BackendConfigCRDName, k8sclient.GetBackendConfigCRDYAML(useCRDv1)); err != nil {
+		return err
+	}
 
 	return err
 }
@@ -720,7 +814,30 @@ func (i *Installer) createOrPatchK8sCSIDriver(controllingCRDetails, labels map[s
 		return err
 	}
 
-	newK8sCSIDriverYAML := k8sclient.GetCSIDriverCRYAML(CSIDriverName, labels, controllingCRDetails)
+	// The fsGroupPolicy field is only known to Kubernetes 1.19+
+	policy := fsGroupPolicy
+	if policy != "" && i.client.ServerVersion().MinorVersion() < 19 {
+		log.WithField("fsGroupPolicy", policy).Warning(
+			"The CSI driver fsGroupPolicy requires Kubernetes 1.19 or later and will be ignored.")
+		policy = ""
+	}
+
+	// CSIStorageCapacity objects are only served by default in Kubernetes 1.21+
+	storageCapacity := enableStorageCapacity
+	if storageCapacity && i.client.ServerVersion().MinorVersion() < 21 {
+		log.Warning("Storage capacity tracking requires Kubernetes 1.21 or later and will not be enabled.")
+		storageCapacity = false
+	}
+
+	// The CSIDriver seLinuxMount field is only known to Kubernetes 1.25+
+	seLinuxMount := enableSELinuxMount
+	if seLinuxMount && i.client.ServerVersion().MinorVersion() < 25 {
+		log.Warning("SELinux mounts require Kubernetes 1.25 or later and will not be enabled.")
+		seLinuxMount = false
+	}
+
+	newK8sCSIDriverYAML := k8sclient.GetCSIDriverCRYAML(CSIDriverName, policy, storageCapacity, seLinuxMount,
+		labels, controllingCRDetails)
 
 	if createCSIDriver {
 		err = i.client.CreateObjectByYAML(newK8sCSIDriverYAML)
@@ -1231,6 +1348,7 @@ func (i *Installer) createOrPatchTridentSecret(controllingCRDetails, labels map[
 	shouldUpdate bool) error {
 	createSecret := true
 	secretName := "trident-csi"
+	serviceName := "trident-csi"
 	//var currentSecret *v1.Secret
 	var unwantedSecrets []v1.Secret
 	secretMap := make(map[string]string)
@@ -1271,8 +1389,14 @@ func (i *Installer) createOrPatchTridentSecret(controllingCRDetails, labels map[
 	}
 
 	if createSecret {
-		// Create the certificates for the CSI controller's HTTPS REST interface
-		certInfo, err := utils.MakeHTTPCertInfo(commonconfig.CACertName, commonconfig.ServerCertName, commonconfig.ClientCertName)
+		// Create the certificates for the CSI controller's HTTPS REST interface and admission webhook
+		serviceDNSNames := []string{
+			serviceName,
+			serviceName + "." + i.namespace,
+			serviceName + "." + i.namespace + ".svc",
+		}
+		certInfo, err := utils.MakeHTTPCertInfo(commonconfig.CACertName, commonconfig.ServerCertName,
+			commonconfig.ClientCertName, serviceDNSNames)
 		if err != nil {
 			return fmt.Errorf("could not create Trident X509 certificates; %v", err)
 		}
@@ -1310,6 +1434,48 @@ func (i *Installer) createOrPatchTridentSecret(controllingCRDetails, labels map[
 	return nil
 }
 
+// createOrPatchTridentWebhook registers the storage class admission and CRD conversion webhooks served by
+// the CSI controller, trusting the CA in the Trident secret.
+func (i *Installer) createOrPatchTridentWebhook(controllingCRDetails, labels map[string]string) error {
+
+	// The API server only calls webhooks through a service on Kubernetes 1.14+
+	if i.client.ServerVersion().MajorVersion() != 1 || i.client.ServerVersion().MinorVersion() < 14 {
+		return nil
+	}
+
+	secret, err := i.client.GetSecret("trident-csi")
+	if err != nil {
+		return fmt.Errorf("could not read Trident secret; %v", err)
+	}
+	caBundle := base64.StdEncoding.EncodeToString(secret.Data[commonconfig.CACertFile])
+
+	newWebhookYAML := k8sclient.GetValidatingWebhookConfigurationYAML(webhookName, "trident-csi", i.namespace,
+		caBundle, i.client.ServerVersion(), labels, controllingCRDetails)
+
+	// Replace any existing configuration, which may hold the CA bundle of an earlier secret
+	if err = i.client.DeleteObjectByYAML(newWebhookYAML, true); err != nil {
+		return fmt.Errorf("could not delete validating webhook configuration; %v", err)
+	}
+	if err = i.client.CreateObjectByYAML(newWebhookYAML); err != nil {
+		return fmt.Errorf("could not create validating webhook configuration; %v", err)
+	}
+	log.WithField("webhook", webhookName).Info("Created validating webhook configuration.")
+
+	// Have the API server convert Trident's custom resources by calling the CSI controller.  The API
+	// server only calls the webhook once a CRD serves more than one version.
+	if useCRDv1 {
+		patch := k8sclient.GetCRDConversionPatch("trident-csi", i.namespace, caBundle)
+		for _, crdName := range CRDnames {
+			if err = i.client.PatchCRD(crdName, []byte(patch)); err != nil {
+				return fmt.Errorf("could not register the conversion webhook for CRD %s; %v", crdName, err)
+			}
+		}
+		log.Info("Registered the CRD conversion webhook.")
+	}
+
+	return nil
+}
+
 func (i *Installer) createOrPatchTridentDeployment(controllingCRDetails, labels map[string]string,
 	shouldUpdate bool) error {
 	var deploymentName string
@@ -1337,7 +1503,8 @@ func (i *Installer) createOrPatchTridentDeployment(controllingCRDetails, labels
 	var newDeploymentYAML string
 	if csi {
 		newDeploymentYAML = k8sclient.GetCSIDeploymentYAML(deploymentName, tridentImage, imageRegistry,
-			logFormat, imagePullSecrets, labels, controllingCRDetails, debug, useIPv6, i.client.ServerVersion())
+			logFormat, controllerReplicas, imagePullSecrets, labels, controllingCRDetails, controllerScheduling,
+			debug, useIPv6, i.client.ServerVersion())
 	} else {
 		newDeploymentYAML = k8sclient.GetDeploymentYAML(deploymentName, tridentImage, logFormat, imagePullSecrets, labels,
 			controllingCRDetails, debug)
@@ -1433,7 +1600,7 @@ func (i *Installer) createOrPatchTridentDaemonSet(controllingCRDetails, labels m
 	labels[appLabelKey] = TridentNodeLabelValue
 
 	newDaemonSetYAML := k8sclient.GetCSIDaemonSetYAML(daemonsetName, tridentImage, imageRegistry, kubeletDir,
-		logFormat, imagePullSecrets, labels, controllingCRDetails, debug, i.client.ServerVersion())
+		logFormat, imagePullSecrets, labels, controllingCRDetails, nodeScheduling, debug, i.client.ServerVersion())
 
 	if createDaemonset {
 		// Create the daemonset
@@ -1447,7 +1614,57 @@ func (i *Installer) createOrPatchTridentDaemonSet(controllingCRDetails, labels m
 			"daemontset": currentDaemonset.Name,
 			"namespace":  currentDaemonset.Namespace,
 		}).Debug("Patching Trident daemonset.")
-		err = i.patchTridentDaemonSet(currentDaemonset, []byte(newDaemonSetYAML))
+		err = i.patchTridentDaemonSet(currentDaemonset, []byte(newDaemonSetYAML), TridentNodeLabel)
+	}
+
+	return err
+}
+
+// createOrPatchTridentWindowsDaemonSet creates or updates the node plugin daemonset for Windows nodes if the
+// CR asks for one, and removes it otherwise.
+func (i *Installer) createOrPatchTridentWindowsDaemonSet(controllingCRDetails, labels map[string]string,
+	shouldUpdate bool) error {
+
+	currentDaemonset, unwantedDaemonsets, createDaemonset, err := i.TridentWindowsDaemonSetInformation()
+	if err != nil {
+		return err
+	}
+
+	if currentDaemonset != nil && (shouldUpdate || !windows) {
+		unwantedDaemonsets = append(unwantedDaemonsets, *currentDaemonset)
+		createDaemonset = true
+	}
+
+	if err = i.RemoveMultipleDaemonSets(unwantedDaemonsets); err != nil {
+		return err
+	}
+
+	if !windows {
+		return nil
+	}
+
+	windowsLabels := make(map[string]string)
+	for key, value := range labels {
+		windowsLabels[key] = value
+	}
+	windowsLabels[appLabelKey] = TridentWindowsNodeLabelValue
+
+	newDaemonSetYAML := k8sclient.GetCSIWindowsDaemonSetYAML(TridentWindowsDaemonSetName, tridentImage,
+		imageRegistry, logFormat, imagePullSecrets, windowsLabels, controllingCRDetails, nodeScheduling, debug)
+
+	if createDaemonset {
+		// Create the daemonset
+		err = i.client.CreateObjectByYAML(newDaemonSetYAML)
+		if err != nil {
+			return fmt.Errorf("could not create Trident Windows daemonset; %v", err)
+		}
+		log.Info("Created Trident Windows daemonset.")
+	} else {
+		log.WithFields(log.Fields{
+			"daemonset": currentDaemonset.Name,
+			"namespace": currentDaemonset.Namespace,
+		}).Debug("Patching Trident Windows daemonset.")
+		err = i.patchTridentDaemonSet(currentDaemonset, []byte(newDaemonSetYAML), TridentWindowsNodeLabel)
 	}
 
 	return err
@@ -1459,14 +1676,27 @@ func (i *Installer) createOrPatchTridentDaemonSet(controllingCRDetails, labels m
 func (i *Installer) TridentDaemonSetInformation() (*appsv1.DaemonSet,
 	[]appsv1.DaemonSet, bool, error) {
 
+	return i.daemonSetInformation("trident-csi", TridentNodeLabel)
+}
+
+// TridentWindowsDaemonSetInformation identifies the Operator based Trident CSI daemonset for Windows nodes and
+// unwanted Windows daemonsets.
+func (i *Installer) TridentWindowsDaemonSetInformation() (*appsv1.DaemonSet,
+	[]appsv1.DaemonSet, bool, error) {
+
+	return i.daemonSetInformation(TridentWindowsDaemonSetName, TridentWindowsNodeLabel)
+}
+
+func (i *Installer) daemonSetInformation(daemonsetName, label string) (*appsv1.DaemonSet,
+	[]appsv1.DaemonSet, bool, error) {
+
 	createDaemonset := true
-	daemonsetName := "trident-csi"
 	var currentDaemonset *appsv1.DaemonSet
 	var unwantedDaemonsets []appsv1.DaemonSet
 
-	if daemonsets, err := i.client.GetDaemonSetsByLabel(TridentNodeLabel, true); err != nil {
+	if daemonsets, err := i.client.GetDaemonSetsByLabel(label, true); err != nil {
 
-		log.Errorf("Unable to get list of daemonset by label %v", TridentNodeLabel)
+		log.Errorf("Unable to get list of daemonset by label %v", label)
 		return nil, nil, createDaemonset, fmt.Errorf("unable to get list of daemonset")
 
 	} else if len(daemonsets) == 0 {
@@ -1509,8 +1739,11 @@ func (i *Installer) waitForTridentPod() (*v1.Pod, error) {
 	time.Sleep(waitTime)
 
 	checkPodRunning := func() error {
-		var podError error
-		pod, podError = i.client.GetPodByLabel(appLabel, false)
+		// Of several controller replicas, wait for the elected leader
+		pods, podError := i.client.GetPodsByLabel(appLabel, false)
+		if podError == nil {
+			pod, podError = k8sclient.SelectLeaderPod(pods, appLabel)
+		}
 		if podError != nil || pod.Status.Phase != v1.PodRunning {
 			return errors.New("pod not running")
 		}
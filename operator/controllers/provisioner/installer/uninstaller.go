@@ -108,6 +108,10 @@ func (i *Installer) UninstallTrident() error {
 		return err
 	}
 
+	if err := i.deleteTridentWindowsDaemonSet(); err != nil {
+		return err
+	}
+
 	if err := i.deleteTridentService(); err != nil {
 		return err
 	}
@@ -120,6 +124,10 @@ func (i *Installer) UninstallTrident() error {
 		return err
 	}
 
+	if err := i.deleteTridentWebhook(); err != nil {
+		return err
+	}
+
 	if err := i.removeRBACObjects(log.InfoLevel); err != nil {
 		return err
 	}
@@ -337,6 +345,22 @@ func (i *Installer) deleteTridentDaemonSet() error {
 	return nil
 }
 
+// deleteTridentWindowsDaemonSet deletes the node plugin daemonsets for Windows nodes, which are only present
+// if Trident was installed for Windows.
+func (i *Installer) deleteTridentWindowsDaemonSet() error {
+
+	daemonsets, err := i.client.GetDaemonSetsByLabel(TridentWindowsNodeLabel, true)
+	if err != nil {
+		log.Errorf("Unable to get list of daemonset by label %v", TridentWindowsNodeLabel)
+		return fmt.Errorf("unable to get list of Windows daemonset")
+	} else if len(daemonsets) == 0 {
+		log.WithField("label", TridentWindowsNodeLabel).Debug("Trident Windows daemonset not found.")
+		return nil
+	}
+
+	return i.RemoveMultipleDaemonSets(daemonsets)
+}
+
 func (i *Installer) RemoveMultipleDaemonSets(unwantedDaemonsets []appsv1.DaemonSet) error {
 	var err error
 	var anyError bool
@@ -577,6 +601,38 @@ func (i *Installer) deleteTridentCSIDriverCR() error {
 	return nil
 }
 
+func (i *Installer) deleteTridentWebhook() error {
+
+	// The webhook configuration is only created on Kubernetes 1.14+
+	if i.client.ServerVersion().MajorVersion() != 1 || i.client.ServerVersion().MinorVersion() < 14 {
+		return nil
+	}
+
+	webhookYAML := k8sclient.GetValidatingWebhookConfigurationYAML(webhookName, "trident-csi", i.namespace, "",
+		i.client.ServerVersion(), nil, nil)
+	if err := i.client.DeleteObjectByYAML(webhookYAML, true); err != nil {
+		log.WithField("error", err).Warning("Could not delete validating webhook configuration.")
+		return fmt.Errorf("unable to delete validating webhook configuration %s", webhookName)
+	}
+	log.WithField("webhook", webhookName).Info("Deleted validating webhook configuration.")
+
+	// The CRDs may be kept, so make sure the API server no longer expects Trident to convert their resources
+	for _, crdName := range CRDnames {
+		if exists, err := i.client.CheckCRDExists(crdName); err != nil || !exists {
+			continue
+		}
+		if err := i.client.PatchCRD(crdName, []byte(k8sclient.CRDConversionRemovalPatch)); err != nil {
+			log.WithFields(log.Fields{
+				"CRD":   crdName,
+				"error": err,
+			}).Warning("Could not unregister the CRD conversion webhook.")
+			return fmt.Errorf("unable to unregister the conversion webhook for CRD %s", crdName)
+		}
+	}
+
+	return nil
+}
+
 func (i *Installer) RemoveMultipleCSIDriverCRs(unwantedCSIDriverCRs []v1beta12.CSIDriver) error {
 	var err error
 	var anyError bool
@@ -193,7 +193,9 @@ func (i *Installer) patchTridentDeployment(currentDeployment *appsv1.Deployment,
 	return nil
 }
 
-func (i *Installer) patchTridentDaemonSet(currentDaemonSet *appsv1.DaemonSet, newDaemonSetYAML []byte) error {
+func (i *Installer) patchTridentDaemonSet(
+	currentDaemonSet *appsv1.DaemonSet, newDaemonSetYAML []byte, label string,
+) error {
 
 	// Identify the deltas
 	patchBytes, err := i.genericPatch(currentDaemonSet, newDaemonSetYAML, &appsv1.DaemonSet{})
@@ -203,7 +205,7 @@ func (i *Installer) patchTridentDaemonSet(currentDaemonSet *appsv1.DaemonSet, ne
 	}
 
 	// Apply the patch to the current DaemonSet
-	err = i.client.PatchDaemonSetByLabel(TridentNodeLabel, patchBytes)
+	err = i.client.PatchDaemonSetByLabel(label, patchBytes)
 	if err != nil {
 		return fmt.Errorf("could not patch Trident DaemonSet; %v", err)
 	}
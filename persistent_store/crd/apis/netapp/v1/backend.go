@@ -4,6 +4,8 @@ package v1
 
 import (
 	"encoding/json"
+	"fmt"
+	"reflect"
 
 	"github.com/google/uuid"
 	log "github.com/sirupsen/logrus"
@@ -93,6 +95,66 @@ func (in *TridentBackend) Persistent() (*storage.BackendPersistent, error) {
 	return persistent, json.Unmarshal(in.Config.Raw, &persistent.Config)
 }
 
+// RefreshStatus sets the backend's status from its state, and reports whether the status changed.
+// The health recorded by ApplyHealth is kept.
+func (in *TridentBackend) RefreshStatus() bool {
+
+	status := in.Status.DeepCopy()
+	status.ObservedGeneration = in.Generation
+	status.State = in.State
+
+	ready := TridentCondition{
+		Type:               ConditionReady,
+		Status:             conditionStatus(in.CurrentState().AcceptsNewVolumes()),
+		ObservedGeneration: in.Generation,
+		Reason:             stateReason(in.State),
+		Message:            fmt.Sprintf("backend is %s", in.CurrentState()),
+	}
+	status.Conditions = setCondition(status.Conditions, ready)
+
+	changed := !reflect.DeepEqual(in.Status, *status)
+	in.Status = *status
+	return changed
+}
+
+// ApplyHealth records whether Trident can reach the backend's storage system, and the last error
+// from it, in the backend's status, and reports whether the status changed.
+func (in *TridentBackend) ApplyHealth(health *storage.BackendHealth) bool {
+
+	status := in.Status.DeepCopy()
+	if health.LastError != "" {
+		status.LastError = health.LastError
+		status.LastErrorTime = health.LastFailure
+	}
+
+	reachable := TridentCondition{
+		Type:               ConditionReachable,
+		ObservedGeneration: in.Generation,
+	}
+	switch {
+	case health.AuthStatus == storage.AuthStatusFailed:
+		reachable.Status = conditionStatus(false)
+		reachable.Reason = "AuthenticationFailed"
+		reachable.Message = health.LastError
+	case !health.Reachable:
+		reachable.Status = conditionStatus(false)
+		reachable.Reason = "Unreachable"
+		reachable.Message = health.LastError
+		if reachable.Message == "" {
+			reachable.Message = "storage system is not reachable"
+		}
+	default:
+		reachable.Status = conditionStatus(true)
+		reachable.Reason = "Reachable"
+		reachable.Message = "storage system is reachable"
+	}
+	status.Conditions = setCondition(status.Conditions, reachable)
+
+	changed := !reflect.DeepEqual(in.Status, *status)
+	in.Status = *status
+	return changed
+}
+
 func (in *TridentBackend) CurrentState() storage.BackendState {
 	return storage.BackendState(in.State)
 }
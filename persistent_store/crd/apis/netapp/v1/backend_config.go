@@ -0,0 +1,147 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/netapp/trident/utils"
+)
+
+const (
+	// BackendConfigPhaseBound is the phase of a config whose backend has been created
+	BackendConfigPhaseBound = "Bound"
+	// BackendConfigPhaseFailed is the phase of a config that could not be applied to its backend
+	BackendConfigPhaseFailed = "Failed"
+
+	// BackendConfigConditionValidated reports whether the config was last applied successfully
+	BackendConfigConditionValidated = "Validated"
+
+	// BackendConfigCredentialsKey is the config field that names the credentials secret
+	BackendConfigCredentialsKey = "credentials"
+)
+
+func (in *TridentBackendConfig) GetObjectMeta() metav1.ObjectMeta {
+	return in.ObjectMeta
+}
+
+func (in *TridentBackendConfig) GetFinalizers() []string {
+	if in.ObjectMeta.Finalizers != nil {
+		return in.ObjectMeta.Finalizers
+	}
+	return []string{}
+}
+
+func (in *TridentBackendConfig) HasTridentFinalizers() bool {
+	for _, finalizerName := range GetTridentFinalizers() {
+		if utils.SliceContainsString(in.ObjectMeta.Finalizers, finalizerName) {
+			return true
+		}
+	}
+	return false
+}
+
+func (in *TridentBackendConfig) AddTridentFinalizers() {
+	for _, finalizerName := range GetTridentFinalizers() {
+		if !utils.SliceContainsString(in.ObjectMeta.Finalizers, finalizerName) {
+			in.ObjectMeta.Finalizers = append(in.ObjectMeta.Finalizers, finalizerName)
+		}
+	}
+}
+
+func (in *TridentBackendConfig) RemoveTridentFinalizers() {
+	for _, finalizerName := range GetTridentFinalizers() {
+		in.ObjectMeta.Finalizers = utils.RemoveStringFromSlice(in.ObjectMeta.Finalizers, finalizerName)
+	}
+}
+
+// getConfig returns the backend config as a map.
+func (in *TridentBackendConfig) getConfig() (map[string]interface{}, error) {
+
+	if len(in.Spec.Raw) == 0 {
+		return nil, errors.New("backend config is empty")
+	}
+
+	var config map[string]interface{}
+	if err := json.Unmarshal(in.Spec.Raw, &config); err != nil {
+		return nil, fmt.Errorf("could not parse backend config; %v", err)
+	}
+	return config, nil
+}
+
+// GetSecretName returns the name of the secret holding the backend's credentials, or an empty
+// string if the credentials are in the config itself.
+func (in *TridentBackendConfig) GetSecretName() (string, error) {
+
+	config, err := in.getConfig()
+	if err != nil {
+		return "", err
+	}
+
+	credentials, ok := config[BackendConfigCredentialsKey]
+	if !ok {
+		return "", nil
+	}
+	credentialsMap, ok := credentials.(map[string]interface{})
+	if !ok {
+		return "", fmt.Errorf("%s must be an object with the name of a secret", BackendConfigCredentialsKey)
+	}
+	name, ok := credentialsMap["name"].(string)
+	if !ok || name == "" {
+		return "", fmt.Errorf("%s must have the name of a secret", BackendConfigCredentialsKey)
+	}
+	return name, nil
+}
+
+// GetConfigJSON returns the backend config to pass to Trident, with the keys of the credentials
+// secret in place of the reference to it.
+func (in *TridentBackendConfig) GetConfigJSON(secretData map[string][]byte) (string, error) {
+
+	config, err := in.getConfig()
+	if err != nil {
+		return "", err
+	}
+
+	delete(config, BackendConfigCredentialsKey)
+	for key, value := range secretData {
+		if _, ok := config[key]; ok {
+			return "", fmt.Errorf("config field %s is also set in the credentials secret", key)
+		}
+		config[key] = string(value)
+	}
+
+	configJSON, err := json.Marshal(config)
+	if err != nil {
+		return "", err
+	}
+	return string(configJSON), nil
+}
+
+// GetCondition returns the condition of the specified type, or nil if the config doesn't have one.
+func (in *TridentBackendConfigStatus) GetCondition(conditionType string) *TridentBackendConfigCondition {
+	for i := range in.Conditions {
+		if in.Conditions[i].Type == conditionType {
+			return &in.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// SetCondition adds or replaces the condition of the same type.  The transition time is kept unless
+// the condition's status changes.
+func (in *TridentBackendConfigStatus) SetCondition(condition TridentBackendConfigCondition) {
+
+	existing := in.GetCondition(condition.Type)
+	if existing == nil {
+		in.Conditions = append(in.Conditions, condition)
+		return
+	}
+	if existing.Status == condition.Status {
+		condition.LastTransitionTime = existing.LastTransitionTime
+	}
+	*existing = condition
+}
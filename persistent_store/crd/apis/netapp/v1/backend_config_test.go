@@ -0,0 +1,104 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package v1
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func newTestBackendConfig(config string) *TridentBackendConfig {
+	return &TridentBackendConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "nas", Namespace: "trident"},
+		Spec:       TridentBackendConfigSpec{RawExtension: runtime.RawExtension{Raw: []byte(config)}},
+	}
+}
+
+func TestBackendConfigGetConfigJSON(t *testing.T) {
+
+	backendConfig := newTestBackendConfig(
+		`{"version": 1, "storageDriverName": "ontap-nas", "credentials": {"name": "ontap-creds"}}`)
+
+	secretName, err := backendConfig.GetSecretName()
+	assert.NoError(t, err)
+	assert.Equal(t, "ontap-creds", secretName)
+
+	configJSON, err := backendConfig.GetConfigJSON(map[string][]byte{
+		"username": []byte("admin"),
+		"password": []byte("secret"),
+	})
+	assert.NoError(t, err)
+
+	var config map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(configJSON), &config))
+	assert.Equal(t, "ontap-nas", config["storageDriverName"])
+	assert.Equal(t, "admin", config["username"])
+	assert.Equal(t, "secret", config["password"])
+	assert.NotContains(t, config, BackendConfigCredentialsKey)
+
+	// A field may not be set in both the config and the secret
+	_, err = backendConfig.GetConfigJSON(map[string][]byte{"storageDriverName": []byte("ontap-san")})
+	assert.Error(t, err)
+
+	// Credentials may be in the config itself
+	secretName, err = newTestBackendConfig(`{"version": 1, "username": "admin"}`).GetSecretName()
+	assert.NoError(t, err)
+	assert.Equal(t, "", secretName)
+
+	for _, config := range []string{``, `[]`, `{"credentials": "ontap-creds"}`, `{"credentials": {}}`} {
+		_, err = newTestBackendConfig(config).GetSecretName()
+		assert.Error(t, err, config)
+	}
+}
+
+func TestBackendConfigFinalizers(t *testing.T) {
+
+	backendConfig := newTestBackendConfig(`{}`)
+	assert.False(t, backendConfig.HasTridentFinalizers())
+
+	backendConfig.AddTridentFinalizers()
+	backendConfig.AddTridentFinalizers()
+	assert.True(t, backendConfig.HasTridentFinalizers())
+	assert.Len(t, backendConfig.GetFinalizers(), 1)
+
+	backendConfig.RemoveTridentFinalizers()
+	assert.False(t, backendConfig.HasTridentFinalizers())
+}
+
+func TestBackendConfigSetCondition(t *testing.T) {
+
+	status := &TridentBackendConfigStatus{}
+	assert.Nil(t, status.GetCondition(BackendConfigConditionValidated))
+
+	firstTime := metav1.NewTime(time.Now().Add(-time.Hour))
+	status.SetCondition(TridentBackendConfigCondition{
+		Type:               BackendConfigConditionValidated,
+		Status:             "True",
+		LastTransitionTime: firstTime,
+	})
+
+	// The transition time is kept while the status is unchanged
+	status.SetCondition(TridentBackendConfigCondition{
+		Type:               BackendConfigConditionValidated,
+		Status:             "True",
+		Message:            "again",
+		LastTransitionTime: metav1.Now(),
+	})
+	condition := status.GetCondition(BackendConfigConditionValidated)
+	assert.Len(t, status.Conditions, 1)
+	assert.Equal(t, "again", condition.Message)
+	assert.Equal(t, firstTime, condition.LastTransitionTime)
+
+	now := metav1.Now()
+	status.SetCondition(TridentBackendConfigCondition{
+		Type:               BackendConfigConditionValidated,
+		Status:             "False",
+		LastTransitionTime: now,
+	})
+	assert.Equal(t, now, status.GetCondition(BackendConfigConditionValidated).LastTransitionTime)
+}
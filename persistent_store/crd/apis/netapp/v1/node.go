@@ -39,6 +39,17 @@ func (in *TridentNode) Apply(persistent *utils.Node) error {
 	in.Name = persistent.Name
 	in.IQN = persistent.IQN
 	in.IPs = persistent.IPs
+	in.LastSeen = persistent.LastSeen
+	in.Multipath = nil
+	if persistent.Multipath != nil {
+		in.Multipath = &TridentNodeMultipath{
+			DaemonRunning:  persistent.Multipath.DaemonRunning,
+			FindMultipaths: persistent.Multipath.FindMultipaths,
+			Compatible:     persistent.Multipath.Compatible,
+			Remediated:     persistent.Multipath.Remediated,
+			Problems:       persistent.Multipath.Problems,
+		}
+	}
 
 	return nil
 }
@@ -47,9 +58,19 @@ func (in *TridentNode) Apply(persistent *utils.Node) error {
 // utils.TridentNode equivalent.
 func (in *TridentNode) Persistent() (*utils.Node, error) {
 	persistent := &utils.Node{
-		Name: in.Name,
-		IQN:  in.IQN,
-		IPs:  in.IPs,
+		Name:     in.Name,
+		IQN:      in.IQN,
+		IPs:      in.IPs,
+		LastSeen: in.LastSeen,
+	}
+	if in.Multipath != nil {
+		persistent.Multipath = &utils.MultipathStatus{
+			DaemonRunning:  in.Multipath.DaemonRunning,
+			FindMultipaths: in.Multipath.FindMultipaths,
+			Compatible:     in.Multipath.Compatible,
+			Remediated:     in.Multipath.Remediated,
+			Problems:       in.Multipath.Problems,
+		}
 	}
 
 	return persistent, nil
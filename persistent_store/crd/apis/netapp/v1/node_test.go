@@ -8,6 +8,7 @@ import (
 	"github.com/netapp/trident/utils"
 
 	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
 )
 
 func init() {
@@ -25,6 +26,7 @@ func TestNewNode(t *testing.T) {
 		IPs: []string{
 			"192.168.0.1",
 		},
+		LastSeen: "2020-06-01T12:00:00Z",
 	}
 
 	// Convert to Kubernetes Object using the NewTridentBackend method
@@ -41,6 +43,10 @@ func TestNewNode(t *testing.T) {
 		t.Fatalf("%v differs:  '%v' != '%v'", "IQN", node.IQN, utilsNode.IQN)
 	}
 
+	if node.LastSeen != utilsNode.LastSeen {
+		t.Fatalf("%v differs:  '%v' != '%v'", "LastSeen", node.LastSeen, utilsNode.LastSeen)
+	}
+
 	if len(node.IPs) != len(utilsNode.IPs) {
 		t.Fatalf("%v differs:  '%v' != '%v'", "IPs", node.IPs, utilsNode.IPs)
 	}
@@ -55,3 +61,37 @@ func TestNewNode(t *testing.T) {
 		t.Fatal("Unable to construct TridentNode CRD")
 	}
 }
+
+func TestNodeMultipath(t *testing.T) {
+	utilsNode := &utils.Node{
+		Name: "test",
+		Multipath: &utils.MultipathStatus{
+			DaemonRunning:  true,
+			FindMultipaths: "yes",
+			Problems:       []string{"find_multipaths is \"yes\""},
+		},
+	}
+
+	node, err := NewTridentNode(utilsNode)
+	if err != nil {
+		t.Fatal("Unable to construct TridentNode CRD: ", err)
+	}
+	if assert.NotNil(t, node.Multipath) {
+		assert.True(t, node.Multipath.DaemonRunning)
+		assert.False(t, node.Multipath.Compatible)
+		assert.Equal(t, "yes", node.Multipath.FindMultipaths)
+	}
+
+	copied := node.DeepCopy()
+	copied.Multipath.Problems[0] = "changed"
+	assert.Equal(t, utilsNode.Multipath.Problems, node.Multipath.Problems)
+
+	persistent, err := node.Persistent()
+	assert.NoError(t, err)
+	assert.Equal(t, utilsNode.Multipath, persistent.Multipath)
+
+	// A node that no longer reports its multipath configuration clears it
+	utilsNode.Multipath = nil
+	assert.NoError(t, node.Apply(utilsNode))
+	assert.Nil(t, node.Multipath)
+}
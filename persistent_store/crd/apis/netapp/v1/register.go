@@ -46,6 +46,16 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&TridentVersionList{},
 		&TridentSnapshot{},
 		&TridentSnapshotList{},
+		&TridentSnapshotSchedule{},
+		&TridentSnapshotScheduleList{},
+		&TridentVolumeGroup{},
+		&TridentVolumeGroupList{},
+		&TridentCloneGrant{},
+		&TridentCloneGrantList{},
+		&TridentQuota{},
+		&TridentQuotaList{},
+		&TridentBackendConfig{},
+		&TridentBackendConfigList{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
@@ -0,0 +1,79 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package v1
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ConditionReady reports whether a backend accepts new volumes, or whether a volume is usable
+	ConditionReady = "Ready"
+	// ConditionReachable reports whether Trident can reach a backend's storage system
+	ConditionReachable = "Reachable"
+	// ConditionOrphaned reports whether a volume no longer exists on its backend
+	ConditionOrphaned = "Orphaned"
+)
+
+// GetCondition returns the condition of the specified type, or nil if the backend doesn't have one.
+func (in *TridentBackendStatus) GetCondition(conditionType string) *TridentCondition {
+	return getCondition(in.Conditions, conditionType)
+}
+
+// GetCondition returns the condition of the specified type, or nil if the volume doesn't have one.
+func (in *TridentVolumeStatus) GetCondition(conditionType string) *TridentCondition {
+	return getCondition(in.Conditions, conditionType)
+}
+
+// getCondition returns the condition of the specified type, or nil if there isn't one.
+func getCondition(conditions []TridentCondition, conditionType string) *TridentCondition {
+	for i := range conditions {
+		if conditions[i].Type == conditionType {
+			return &conditions[i]
+		}
+	}
+	return nil
+}
+
+// setCondition adds or replaces the condition of the same type.  The transition time is kept unless
+// the condition's status changes.
+func setCondition(conditions []TridentCondition, condition TridentCondition) []TridentCondition {
+
+	existing := getCondition(conditions, condition.Type)
+	if existing == nil {
+		condition.LastTransitionTime = metav1.Now()
+		return append(conditions, condition)
+	}
+	if existing.Status == condition.Status {
+		condition.LastTransitionTime = existing.LastTransitionTime
+	} else {
+		condition.LastTransitionTime = metav1.Now()
+	}
+	*existing = condition
+	return conditions
+}
+
+// conditionStatus converts a boolean to a condition status.
+func conditionStatus(value bool) string {
+	if value {
+		return string(corev1.ConditionTrue)
+	}
+	return string(corev1.ConditionFalse)
+}
+
+// stateReason converts a Trident state, such as missing_backend, to a CamelCase condition reason.
+func stateReason(state string) string {
+	if state == "" {
+		return "Unknown"
+	}
+	reason := ""
+	for _, word := range strings.Split(state, "_") {
+		if word != "" {
+			reason += strings.ToUpper(word[:1]) + word[1:]
+		}
+	}
+	return reason
+}
@@ -0,0 +1,110 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package v1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/netapp/trident/storage"
+)
+
+func TestBackendRefreshStatus(t *testing.T) {
+
+	backend := &TridentBackend{
+		ObjectMeta: metav1.ObjectMeta{Name: "tbe-abcde", Generation: 2},
+		State:      string(storage.Online),
+	}
+
+	assert.True(t, backend.RefreshStatus())
+	assert.Equal(t, int64(2), backend.Status.ObservedGeneration)
+	assert.Equal(t, "online", backend.Status.State)
+	ready := backend.Status.GetCondition(ConditionReady)
+	if assert.NotNil(t, ready) {
+		assert.Equal(t, "True", ready.Status)
+		assert.Equal(t, "Online", ready.Reason)
+		assert.Equal(t, int64(2), ready.ObservedGeneration)
+	}
+
+	// An unchanged status needn't be written again
+	transitionTime := ready.LastTransitionTime
+	assert.False(t, backend.RefreshStatus())
+
+	// A backend that doesn't accept new volumes isn't ready
+	backend.Generation = 3
+	backend.State = string(storage.Failed)
+	assert.True(t, backend.RefreshStatus())
+	ready = backend.Status.GetCondition(ConditionReady)
+	assert.Equal(t, "False", ready.Status)
+	assert.Equal(t, "Failed", ready.Reason)
+	assert.Equal(t, int64(3), backend.Status.ObservedGeneration)
+	assert.False(t, transitionTime.After(ready.LastTransitionTime.Time))
+}
+
+func TestBackendApplyHealth(t *testing.T) {
+
+	backend := &TridentBackend{State: string(storage.Online)}
+	backend.RefreshStatus()
+
+	health := &storage.BackendHealth{Reachable: true, AuthStatus: storage.AuthStatusOK}
+	assert.True(t, backend.ApplyHealth(health))
+	assert.Equal(t, "True", backend.Status.GetCondition(ConditionReachable).Status)
+	assert.False(t, backend.ApplyHealth(health))
+
+	// The last error is kept once the storage system is reachable again
+	health = &storage.BackendHealth{
+		Reachable:   false,
+		AuthStatus:  storage.AuthStatusOK,
+		LastFailure: "2020-01-02T03:04:05Z",
+		LastError:   "connection refused",
+	}
+	assert.True(t, backend.ApplyHealth(health))
+	reachable := backend.Status.GetCondition(ConditionReachable)
+	assert.Equal(t, "False", reachable.Status)
+	assert.Equal(t, "Unreachable", reachable.Reason)
+	assert.Equal(t, "connection refused", reachable.Message)
+	assert.Equal(t, "connection refused", backend.Status.LastError)
+	assert.Equal(t, "2020-01-02T03:04:05Z", backend.Status.LastErrorTime)
+
+	health.Reachable = true
+	assert.True(t, backend.ApplyHealth(health))
+	assert.Equal(t, "True", backend.Status.GetCondition(ConditionReachable).Status)
+	assert.Equal(t, "connection refused", backend.Status.LastError)
+
+	health.AuthStatus = storage.AuthStatusFailed
+	assert.True(t, backend.ApplyHealth(health))
+	assert.Equal(t, "AuthenticationFailed", backend.Status.GetCondition(ConditionReachable).Reason)
+
+	// Refreshing the status from the backend's state keeps its health
+	assert.False(t, backend.RefreshStatus())
+	assert.Len(t, backend.Status.Conditions, 2)
+}
+
+func TestVolumeRefreshStatus(t *testing.T) {
+
+	volume := &TridentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: "pvc-1", Generation: 1},
+		State:      string(storage.VolumeStateOnline),
+	}
+
+	assert.True(t, volume.RefreshStatus())
+	assert.Equal(t, "True", volume.Status.GetCondition(ConditionReady).Status)
+	assert.Equal(t, "False", volume.Status.GetCondition(ConditionOrphaned).Status)
+	assert.False(t, volume.RefreshStatus())
+
+	volume.Generation = 2
+	volume.Orphaned = true
+	assert.True(t, volume.RefreshStatus())
+	ready := volume.Status.GetCondition(ConditionReady)
+	assert.Equal(t, "False", ready.Status)
+	assert.Equal(t, ConditionOrphaned, ready.Reason)
+	assert.Equal(t, "True", volume.Status.GetCondition(ConditionOrphaned).Status)
+	assert.Equal(t, int64(2), volume.Status.ObservedGeneration)
+
+	volume.Orphaned = false
+	volume.State = string(storage.VolumeStateMissingBackend)
+	assert.True(t, volume.RefreshStatus())
+	assert.Equal(t, "MissingBackend", volume.Status.GetCondition(ConditionReady).Reason)
+}
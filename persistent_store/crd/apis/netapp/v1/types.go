@@ -41,10 +41,26 @@ type TridentBackend struct {
 	BackendUUID string `json:"backendUUID"`
 	// Version is the version of the backend
 	Version string `json:"version"`
-	// Online defines if the backend is online
+	// Online defines if the backend is online, and is kept for earlier Trident versions (see Status)
 	Online bool `json:"online"`
-	// State records the TridentBackend's state
+	// State records the TridentBackend's state, and is kept for earlier Trident versions (see Status)
 	State string `json:"state"`
+	// Most recently observed runtime state of the backend
+	Status TridentBackendStatus `json:"status,omitempty"`
+}
+
+// TridentBackendStatus records the runtime state of a backend.
+type TridentBackendStatus struct {
+	// ObservedGeneration is the generation of the backend resource the status describes
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// State is the backend's state, such as online or failed
+	State string `json:"state,omitempty"`
+	// LastError is the error from the last call to the backend's storage system that failed
+	LastError string `json:"lastError,omitempty"`
+	// LastErrorTime is when the last call to the backend's storage system failed
+	LastErrorTime string `json:"lastErrorTime,omitempty"`
+	// Conditions describe whether the backend is ready for new volumes and whether it is reachable
+	Conditions []TridentCondition `json:"conditions,omitempty"`
 }
 
 // TridentBackendList is a list of TridentBackend objects.
@@ -73,8 +89,36 @@ type TridentVolume struct {
 	Pool string `json:"pool"`
 	// Orphaned defines if the backend is orphaned
 	Orphaned bool `json:"orphaned"`
-	// State records the TridentVolume's state
+	// State records the TridentVolume's state, and is kept for earlier Trident versions (see Status)
 	State string `json:"state"`
+	// Most recently observed runtime state of the volume
+	Status TridentVolumeStatus `json:"status,omitempty"`
+}
+
+// TridentVolumeStatus records the runtime state of a volume.
+type TridentVolumeStatus struct {
+	// ObservedGeneration is the generation of the volume resource the status describes
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// State is the volume's state, such as online or deleting
+	State string `json:"state,omitempty"`
+	// Conditions describe whether the volume is ready and whether it is orphaned
+	Conditions []TridentCondition `json:"conditions,omitempty"`
+}
+
+// TridentCondition describes one aspect of the runtime state of a Trident object.
+type TridentCondition struct {
+	// Type of the condition, such as Ready
+	Type string `json:"type"`
+	// Status of the condition, one of True, False or Unknown
+	Status string `json:"status"`
+	// ObservedGeneration is the generation of the object the condition was set for
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// LastTransitionTime is when the condition's status last changed
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+	// Reason is a CamelCase reason for the condition's status
+	Reason string `json:"reason,omitempty"`
+	// Message describes the condition's status
+	Message string `json:"message,omitempty"`
 }
 
 // TridentVolumeList is a list of TridentVolume objects.
@@ -145,6 +189,25 @@ type TridentNode struct {
 	IQN string `json:"iqn,omitempty"`
 	// IPs is a list of IP addresses for the TridentNode
 	IPs []string `json:"ips,omitempty"`
+	// LastSeen is the time the node last registered with the controller
+	LastSeen string `json:"lastSeen,omitempty"`
+	// Multipath is the state of the node's multipath configuration, as checked when it registered
+	Multipath *TridentNodeMultipath `json:"multipath,omitempty"`
+}
+
+// TridentNodeMultipath records whether a node's multipath daemon is running and its configuration
+// lets multipath claim ONTAP LUNs.
+type TridentNodeMultipath struct {
+	// DaemonRunning is whether multipathd is running
+	DaemonRunning bool `json:"daemonRunning"`
+	// FindMultipaths is the find_multipaths setting
+	FindMultipaths string `json:"findMultipaths,omitempty"`
+	// Compatible is whether multipath claims ONTAP LUNs
+	Compatible bool `json:"compatible"`
+	// Remediated is whether Trident dropped in its multipath configuration
+	Remediated bool `json:"remediated,omitempty"`
+	// Problems describes why the configuration isn't compatible
+	Problems []string `json:"problems,omitempty"`
 }
 
 // TridentNodeList is a list of TridentNode objects.
@@ -210,3 +273,246 @@ type TridentSnapshotList struct {
 	// List of TridentSnapshot objects
 	Items []*TridentSnapshot `json:"items"`
 }
+
+// TridentSnapshotSchedule defines a schedule for taking periodic snapshots of Trident volumes.
+// +genclient
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type TridentSnapshotSchedule struct {
+	metav1.TypeMeta `json:",inline"`
+	// +k8s:openapi-gen=false
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// Specification of the snapshot schedule
+	Spec TridentSnapshotScheduleSpec `json:"spec"`
+	// Most recently observed status of the snapshot schedule
+	Status TridentSnapshotScheduleStatus `json:"status,omitempty"`
+}
+
+// TridentSnapshotScheduleSpec defines when snapshots are taken, which volumes they are taken of,
+// and how many of them are kept.
+type TridentSnapshotScheduleSpec struct {
+	// Schedule is a five-field cron expression, evaluated in UTC
+	Schedule string `json:"schedule"`
+	// StorageClasses limits the schedule to PVCs using one of these storage classes
+	StorageClasses []string `json:"storageClasses,omitempty"`
+	// Namespaces limits the schedule to PVCs in one of these namespaces
+	Namespaces []string `json:"namespaces,omitempty"`
+	// Selector limits the schedule to PVCs with matching labels
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+	// VolumeSnapshotClassName is the snapshot class used for the snapshots, or the default if unset
+	VolumeSnapshotClassName string `json:"volumeSnapshotClassName,omitempty"`
+	// Retain is the number of scheduled snapshots kept for each PVC, or all of them if zero
+	Retain int `json:"retain,omitempty"`
+	// Suspend stops new snapshots from being taken while true
+	Suspend bool `json:"suspend,omitempty"`
+}
+
+// TridentSnapshotScheduleStatus records the result of the most recent scheduled run.
+type TridentSnapshotScheduleStatus struct {
+	// The UTC time that the schedule last ran, in RFC3339 format
+	LastScheduleTime string `json:"lastScheduleTime,omitempty"`
+	// The number of snapshots created by the last run
+	LastSnapshotCount int `json:"lastSnapshotCount,omitempty"`
+	// Message describes any errors from the last run
+	Message string `json:"message,omitempty"`
+}
+
+// TridentSnapshotScheduleList is a list of TridentSnapshotSchedule objects.
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type TridentSnapshotScheduleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	// List of TridentSnapshotSchedule objects
+	Items []*TridentSnapshotSchedule `json:"items"`
+}
+
+// TridentVolumeGroup groups PVCs in one namespace, such as those used by one application, so that
+// they can be snapshotted and cloned together.
+// +genclient
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type TridentVolumeGroup struct {
+	metav1.TypeMeta `json:",inline"`
+	// +k8s:openapi-gen=false
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// Specification of the volume group
+	Spec TridentVolumeGroupSpec `json:"spec"`
+	// Most recently observed status of the volume group
+	Status TridentVolumeGroupStatus `json:"status,omitempty"`
+}
+
+// TridentVolumeGroupSpec lists the PVCs in a volume group.
+type TridentVolumeGroupSpec struct {
+	// PVCs are the names of the group's PVCs, which must be in the volume group's namespace
+	PVCs []string `json:"pvcs"`
+}
+
+// TridentVolumeGroupStatus records the Trident volume group that the PVCs were added to.
+type TridentVolumeGroupStatus struct {
+	// The name of the volume group in Trident
+	VolumeGroup string `json:"volumeGroup,omitempty"`
+	// The Trident volumes in the group
+	Volumes []string `json:"volumes,omitempty"`
+	// The group snapshots that can be cloned
+	Snapshots []string `json:"snapshots,omitempty"`
+	// Message describes any errors from the last reconciliation
+	Message string `json:"message,omitempty"`
+}
+
+// TridentVolumeGroupList is a list of TridentVolumeGroup objects.
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type TridentVolumeGroupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	// List of TridentVolumeGroup objects
+	Items []*TridentVolumeGroup `json:"items"`
+}
+
+// TridentCloneGrant allows PVCs in its namespace to be cloned into other namespaces, such as when
+// a platform team publishes golden datasets to tenants.
+// +genclient
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type TridentCloneGrant struct {
+	metav1.TypeMeta `json:",inline"`
+	// +k8s:openapi-gen=false
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// Specification of the clone grant
+	Spec TridentCloneGrantSpec `json:"spec"`
+	// Most recently observed status of the clone grant
+	Status TridentCloneGrantStatus `json:"status,omitempty"`
+}
+
+// TridentCloneGrantSpec lists the PVCs that may be cloned and the namespaces they may be cloned into.
+type TridentCloneGrantSpec struct {
+	// PVCs are the names of the PVCs in the grant's namespace that may be cloned, or empty for all PVCs
+	PVCs []string `json:"pvcs,omitempty"`
+	// TargetNamespaces are the namespaces the PVCs may be cloned into, or "*" for any namespace
+	TargetNamespaces []string `json:"targetNamespaces"`
+}
+
+// TridentCloneGrantStatus records the Trident volumes covered by the grant.
+type TridentCloneGrantStatus struct {
+	// The Trident volumes that may be cloned, or "*" for all volumes in the namespace
+	Volumes []string `json:"volumes,omitempty"`
+	// Message describes any errors from the last reconciliation
+	Message string `json:"message,omitempty"`
+}
+
+// TridentCloneGrantList is a list of TridentCloneGrant objects.
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type TridentCloneGrantList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	// List of TridentCloneGrant objects
+	Items []*TridentCloneGrant `json:"items"`
+}
+
+// TridentQuota limits the space and number of volumes Trident provisions for PVCs in its namespace.
+// +genclient
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type TridentQuota struct {
+	metav1.TypeMeta `json:",inline"`
+	// +k8s:openapi-gen=false
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// Specification of the quota
+	Spec TridentQuotaSpec `json:"spec"`
+	// Most recently observed status of the quota
+	Status TridentQuotaStatus `json:"status,omitempty"`
+}
+
+// TridentQuotaSpec defines the limits of the quota.  At least one limit must be set.
+type TridentQuotaSpec struct {
+	// StorageClass limits the quota to PVCs of one storage class, or is empty for all PVCs
+	StorageClass string `json:"storageClass,omitempty"`
+	// MaxCapacity is the total size the volumes may have, such as "500Gi"
+	MaxCapacity string `json:"maxCapacity,omitempty"`
+	// MaxVolumes is the number of volumes there may be
+	MaxVolumes int `json:"maxVolumes,omitempty"`
+}
+
+// TridentQuotaStatus records the usage counted by the quota.
+type TridentQuotaStatus struct {
+	// UsedCapacity is the total size of the volumes counted by the quota
+	UsedCapacity string `json:"usedCapacity,omitempty"`
+	// UsedVolumes is the number of volumes counted by the quota
+	UsedVolumes int `json:"usedVolumes"`
+	// Message describes any errors from the last reconciliation
+	Message string `json:"message,omitempty"`
+}
+
+// TridentQuotaList is a list of TridentQuota objects.
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type TridentQuotaList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	// List of TridentQuota objects
+	Items []*TridentQuota `json:"items"`
+}
+
+// TridentBackendConfig defines a Trident backend whose credentials may be kept in a Kubernetes secret.
+// +genclient
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type TridentBackendConfig struct {
+	metav1.TypeMeta `json:",inline"`
+	// +k8s:openapi-gen=false
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// Specification of the backend
+	Spec TridentBackendConfigSpec `json:"spec"`
+	// Most recently observed status of the backend
+	Status TridentBackendConfigStatus `json:"status,omitempty"`
+}
+
+// TridentBackendConfigSpec is a backend config, as accepted by tridentctl, with an optional
+// "credentials" field naming a secret whose keys are added to the config.
+type TridentBackendConfigSpec struct {
+	runtime.RawExtension `json:",inline"`
+}
+
+// TridentBackendConfigStatus records the backend created for the config and its last validation.
+type TridentBackendConfigStatus struct {
+	// BackendName is the name of the backend created for the config
+	BackendName string `json:"backendName,omitempty"`
+	// BackendUUID is the UUID of the backend created for the config
+	BackendUUID string `json:"backendUUID,omitempty"`
+	// Phase is Bound once the backend has been created, or Failed if the config could not be applied
+	Phase string `json:"phase,omitempty"`
+	// ObservedGeneration is the generation of the spec last applied to the backend
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// SecretResourceVersion is the version of the credentials secret last applied to the backend
+	SecretResourceVersion string `json:"secretResourceVersion,omitempty"`
+	// LastValidationTime is when the config was last applied to the backend
+	LastValidationTime metav1.Time `json:"lastValidationTime,omitempty"`
+	// Conditions describe the result of the last validation
+	Conditions []TridentBackendConfigCondition `json:"conditions,omitempty"`
+}
+
+// TridentBackendConfigCondition describes one aspect of a backend config's state.
+type TridentBackendConfigCondition struct {
+	// Type of the condition, such as Validated
+	Type string `json:"type"`
+	// Status of the condition, one of True, False or Unknown
+	Status string `json:"status"`
+	// LastTransitionTime is when the condition's status last changed
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+	// Reason is a CamelCase reason for the condition's status
+	Reason string `json:"reason,omitempty"`
+	// Message describes the condition's status
+	Message string `json:"message,omitempty"`
+}
+
+// TridentBackendConfigList is a list of TridentBackendConfig objects.
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type TridentBackendConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	// List of TridentBackendConfig objects
+	Items []*TridentBackendConfig `json:"items"`
+}
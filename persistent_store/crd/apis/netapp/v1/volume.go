@@ -4,6 +4,8 @@ package v1
 
 import (
 	"encoding/json"
+	"fmt"
+	"reflect"
 
 	"github.com/netapp/trident/storage"
 	"github.com/netapp/trident/utils"
@@ -84,6 +86,45 @@ func (in *TridentVolume) Persistent() (*storage.VolumeExternal, error) {
 	return persistent, json.Unmarshal(in.Config.Raw, persistent.Config)
 }
 
+// RefreshStatus sets the volume's status from its state, and reports whether the status changed.
+func (in *TridentVolume) RefreshStatus() bool {
+
+	status := in.Status.DeepCopy()
+	status.ObservedGeneration = in.Generation
+	status.State = in.State
+
+	state := storage.VolumeState(in.State)
+	ready := TridentCondition{
+		Type:               ConditionReady,
+		Status:             conditionStatus(state.IsOnline() && !in.Orphaned),
+		ObservedGeneration: in.Generation,
+		Reason:             stateReason(in.State),
+		Message:            fmt.Sprintf("volume is %s", state),
+	}
+	if in.Orphaned {
+		ready.Reason = ConditionOrphaned
+		ready.Message = "volume was not found on its backend"
+	}
+	status.Conditions = setCondition(status.Conditions, ready)
+
+	orphaned := TridentCondition{
+		Type:               ConditionOrphaned,
+		Status:             conditionStatus(in.Orphaned),
+		ObservedGeneration: in.Generation,
+		Reason:             "Found",
+		Message:            "volume exists on its backend",
+	}
+	if in.Orphaned {
+		orphaned.Reason = "NotFound"
+		orphaned.Message = "volume was not found on its backend"
+	}
+	status.Conditions = setCondition(status.Conditions, orphaned)
+
+	changed := !reflect.DeepEqual(in.Status, *status)
+	in.Status = *status
+	return changed
+}
+
 func (in *TridentVolume) GetObjectMeta() metav1.ObjectMeta {
 	return in.ObjectMeta
 }
@@ -18,14 +18,30 @@ func (c *FakeTridentV1) TridentBackends(namespace string) v1.TridentBackendInter
 	return &FakeTridentBackends{c, namespace}
 }
 
+func (c *FakeTridentV1) TridentBackendConfigs(namespace string) v1.TridentBackendConfigInterface {
+	return &FakeTridentBackendConfigs{c, namespace}
+}
+
+func (c *FakeTridentV1) TridentCloneGrants(namespace string) v1.TridentCloneGrantInterface {
+	return &FakeTridentCloneGrants{c, namespace}
+}
+
 func (c *FakeTridentV1) TridentNodes(namespace string) v1.TridentNodeInterface {
 	return &FakeTridentNodes{c, namespace}
 }
 
+func (c *FakeTridentV1) TridentQuotas(namespace string) v1.TridentQuotaInterface {
+	return &FakeTridentQuotas{c, namespace}
+}
+
 func (c *FakeTridentV1) TridentSnapshots(namespace string) v1.TridentSnapshotInterface {
 	return &FakeTridentSnapshots{c, namespace}
 }
 
+func (c *FakeTridentV1) TridentSnapshotSchedules(namespace string) v1.TridentSnapshotScheduleInterface {
+	return &FakeTridentSnapshotSchedules{c, namespace}
+}
+
 func (c *FakeTridentV1) TridentStorageClasses(namespace string) v1.TridentStorageClassInterface {
 	return &FakeTridentStorageClasses{c, namespace}
 }
@@ -42,6 +58,10 @@ func (c *FakeTridentV1) TridentVolumes(namespace string) v1.TridentVolumeInterfa
 	return &FakeTridentVolumes{c, namespace}
 }
 
+func (c *FakeTridentV1) TridentVolumeGroups(namespace string) v1.TridentVolumeGroupInterface {
+	return &FakeTridentVolumeGroups{c, namespace}
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakeTridentV1) RESTClient() rest.Interface {
@@ -88,6 +88,18 @@ func (c *FakeTridentBackends) Update(ctx context.Context, tridentBackend *netapp
 	return obj.(*netappv1.TridentBackend), err
 }
 
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeTridentBackends) UpdateStatus(ctx context.Context, tridentBackend *netappv1.TridentBackend, opts v1.UpdateOptions) (*netappv1.TridentBackend, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateSubresourceAction(tridentbackendsResource, "status", c.ns, tridentBackend), &netappv1.TridentBackend{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*netappv1.TridentBackend), err
+}
+
 // Delete takes name of the tridentBackend and deletes it. Returns an error if one occurs.
 func (c *FakeTridentBackends) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
 	_, err := c.Fake.
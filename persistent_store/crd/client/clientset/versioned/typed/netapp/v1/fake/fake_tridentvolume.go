@@ -88,6 +88,18 @@ func (c *FakeTridentVolumes) Update(ctx context.Context, tridentVolume *netappv1
 	return obj.(*netappv1.TridentVolume), err
 }
 
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeTridentVolumes) UpdateStatus(ctx context.Context, tridentVolume *netappv1.TridentVolume, opts v1.UpdateOptions) (*netappv1.TridentVolume, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateSubresourceAction(tridentvolumesResource, "status", c.ns, tridentVolume), &netappv1.TridentVolume{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*netappv1.TridentVolume), err
+}
+
 // Delete takes name of the tridentVolume and deletes it. Returns an error if one occurs.
 func (c *FakeTridentVolumes) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
 	_, err := c.Fake.
@@ -6,10 +6,18 @@ package v1
 
 type TridentBackendExpansion interface{}
 
+type TridentBackendConfigExpansion interface{}
+
+type TridentCloneGrantExpansion interface{}
+
 type TridentNodeExpansion interface{}
 
+type TridentQuotaExpansion interface{}
+
 type TridentSnapshotExpansion interface{}
 
+type TridentSnapshotScheduleExpansion interface{}
+
 type TridentStorageClassExpansion interface{}
 
 type TridentTransactionExpansion interface{}
@@ -17,3 +25,5 @@ type TridentTransactionExpansion interface{}
 type TridentVersionExpansion interface{}
 
 type TridentVolumeExpansion interface{}
+
+type TridentVolumeGroupExpansion interface{}
@@ -13,12 +13,17 @@ import (
 type TridentV1Interface interface {
 	RESTClient() rest.Interface
 	TridentBackendsGetter
+	TridentBackendConfigsGetter
+	TridentCloneGrantsGetter
 	TridentNodesGetter
+	TridentQuotasGetter
 	TridentSnapshotsGetter
+	TridentSnapshotSchedulesGetter
 	TridentStorageClassesGetter
 	TridentTransactionsGetter
 	TridentVersionsGetter
 	TridentVolumesGetter
+	TridentVolumeGroupsGetter
 }
 
 // TridentV1Client is used to interact with features provided by the trident.netapp.io group.
@@ -30,14 +35,30 @@ func (c *TridentV1Client) TridentBackends(namespace string) TridentBackendInterf
 	return newTridentBackends(c, namespace)
 }
 
+func (c *TridentV1Client) TridentBackendConfigs(namespace string) TridentBackendConfigInterface {
+	return newTridentBackendConfigs(c, namespace)
+}
+
+func (c *TridentV1Client) TridentCloneGrants(namespace string) TridentCloneGrantInterface {
+	return newTridentCloneGrants(c, namespace)
+}
+
 func (c *TridentV1Client) TridentNodes(namespace string) TridentNodeInterface {
 	return newTridentNodes(c, namespace)
 }
 
+func (c *TridentV1Client) TridentQuotas(namespace string) TridentQuotaInterface {
+	return newTridentQuotas(c, namespace)
+}
+
 func (c *TridentV1Client) TridentSnapshots(namespace string) TridentSnapshotInterface {
 	return newTridentSnapshots(c, namespace)
 }
 
+func (c *TridentV1Client) TridentSnapshotSchedules(namespace string) TridentSnapshotScheduleInterface {
+	return newTridentSnapshotSchedules(c, namespace)
+}
+
 func (c *TridentV1Client) TridentStorageClasses(namespace string) TridentStorageClassInterface {
 	return newTridentStorageClasses(c, namespace)
 }
@@ -54,6 +75,10 @@ func (c *TridentV1Client) TridentVolumes(namespace string) TridentVolumeInterfac
 	return newTridentVolumes(c, namespace)
 }
 
+func (c *TridentV1Client) TridentVolumeGroups(namespace string) TridentVolumeGroupInterface {
+	return newTridentVolumeGroups(c, namespace)
+}
+
 // NewForConfig creates a new TridentV1Client for the given config.
 func NewForConfig(c *rest.Config) (*TridentV1Client, error) {
 	config := *c
@@ -26,6 +26,7 @@ type TridentBackendsGetter interface {
 type TridentBackendInterface interface {
 	Create(ctx context.Context, tridentBackend *v1.TridentBackend, opts metav1.CreateOptions) (*v1.TridentBackend, error)
 	Update(ctx context.Context, tridentBackend *v1.TridentBackend, opts metav1.UpdateOptions) (*v1.TridentBackend, error)
+	UpdateStatus(ctx context.Context, tridentBackend *v1.TridentBackend, opts metav1.UpdateOptions) (*v1.TridentBackend, error)
 	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
 	DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error
 	Get(ctx context.Context, name string, opts metav1.GetOptions) (*v1.TridentBackend, error)
@@ -121,6 +122,22 @@ func (c *tridentBackends) Update(ctx context.Context, tridentBackend *v1.Trident
 	return
 }
 
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *tridentBackends) UpdateStatus(ctx context.Context, tridentBackend *v1.TridentBackend, opts metav1.UpdateOptions) (result *v1.TridentBackend, err error) {
+	result = &v1.TridentBackend{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("tridentbackends").
+		Name(tridentBackend.Name).
+		SubResource("status").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(tridentBackend).
+		Do(ctx).
+		Into(result)
+	return
+}
+
 // Delete takes name of the tridentBackend and deletes it. Returns an error if one occurs.
 func (c *tridentBackends) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
 	return c.client.Delete().
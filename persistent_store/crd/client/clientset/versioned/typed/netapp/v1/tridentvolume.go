@@ -26,6 +26,7 @@ type TridentVolumesGetter interface {
 type TridentVolumeInterface interface {
 	Create(ctx context.Context, tridentVolume *v1.TridentVolume, opts metav1.CreateOptions) (*v1.TridentVolume, error)
 	Update(ctx context.Context, tridentVolume *v1.TridentVolume, opts metav1.UpdateOptions) (*v1.TridentVolume, error)
+	UpdateStatus(ctx context.Context, tridentVolume *v1.TridentVolume, opts metav1.UpdateOptions) (*v1.TridentVolume, error)
 	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
 	DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error
 	Get(ctx context.Context, name string, opts metav1.GetOptions) (*v1.TridentVolume, error)
@@ -121,6 +122,22 @@ func (c *tridentVolumes) Update(ctx context.Context, tridentVolume *v1.TridentVo
 	return
 }
 
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *tridentVolumes) UpdateStatus(ctx context.Context, tridentVolume *v1.TridentVolume, opts metav1.UpdateOptions) (result *v1.TridentVolume, err error) {
+	result = &v1.TridentVolume{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("tridentvolumes").
+		Name(tridentVolume.Name).
+		SubResource("status").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(tridentVolume).
+		Do(ctx).
+		Into(result)
+	return
+}
+
 // Delete takes name of the tridentVolume and deletes it. Returns an error if one occurs.
 func (c *tridentVolumes) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
 	return c.client.Delete().
@@ -41,10 +41,18 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 	// Group=trident.netapp.io, Version=v1
 	case v1.SchemeGroupVersion.WithResource("tridentbackends"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Trident().V1().TridentBackends().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("tridentbackendconfigs"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Trident().V1().TridentBackendConfigs().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("tridentclonegrants"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Trident().V1().TridentCloneGrants().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("tridentnodes"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Trident().V1().TridentNodes().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("tridentquotas"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Trident().V1().TridentQuotas().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("tridentsnapshots"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Trident().V1().TridentSnapshots().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("tridentsnapshotschedules"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Trident().V1().TridentSnapshotSchedules().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("tridentstorageclasses"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Trident().V1().TridentStorageClasses().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("tridenttransactions"):
@@ -53,6 +61,8 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Trident().V1().TridentVersions().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("tridentvolumes"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Trident().V1().TridentVolumes().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("tridentvolumegroups"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Trident().V1().TridentVolumeGroups().Informer()}, nil
 
 	}
 
@@ -12,10 +12,18 @@ import (
 type Interface interface {
 	// TridentBackends returns a TridentBackendInformer.
 	TridentBackends() TridentBackendInformer
+	// TridentBackendConfigs returns a TridentBackendConfigInformer.
+	TridentBackendConfigs() TridentBackendConfigInformer
+	// TridentCloneGrants returns a TridentCloneGrantInformer.
+	TridentCloneGrants() TridentCloneGrantInformer
 	// TridentNodes returns a TridentNodeInformer.
 	TridentNodes() TridentNodeInformer
+	// TridentQuotas returns a TridentQuotaInformer.
+	TridentQuotas() TridentQuotaInformer
 	// TridentSnapshots returns a TridentSnapshotInformer.
 	TridentSnapshots() TridentSnapshotInformer
+	// TridentSnapshotSchedules returns a TridentSnapshotScheduleInformer.
+	TridentSnapshotSchedules() TridentSnapshotScheduleInformer
 	// TridentStorageClasses returns a TridentStorageClassInformer.
 	TridentStorageClasses() TridentStorageClassInformer
 	// TridentTransactions returns a TridentTransactionInformer.
@@ -24,6 +32,8 @@ type Interface interface {
 	TridentVersions() TridentVersionInformer
 	// TridentVolumes returns a TridentVolumeInformer.
 	TridentVolumes() TridentVolumeInformer
+	// TridentVolumeGroups returns a TridentVolumeGroupInformer.
+	TridentVolumeGroups() TridentVolumeGroupInformer
 }
 
 type version struct {
@@ -42,16 +52,36 @@ func (v *version) TridentBackends() TridentBackendInformer {
 	return &tridentBackendInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// TridentBackendConfigs returns a TridentBackendConfigInformer.
+func (v *version) TridentBackendConfigs() TridentBackendConfigInformer {
+	return &tridentBackendConfigInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// TridentCloneGrants returns a TridentCloneGrantInformer.
+func (v *version) TridentCloneGrants() TridentCloneGrantInformer {
+	return &tridentCloneGrantInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // TridentNodes returns a TridentNodeInformer.
 func (v *version) TridentNodes() TridentNodeInformer {
 	return &tridentNodeInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// TridentQuotas returns a TridentQuotaInformer.
+func (v *version) TridentQuotas() TridentQuotaInformer {
+	return &tridentQuotaInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // TridentSnapshots returns a TridentSnapshotInformer.
 func (v *version) TridentSnapshots() TridentSnapshotInformer {
 	return &tridentSnapshotInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// TridentSnapshotSchedules returns a TridentSnapshotScheduleInformer.
+func (v *version) TridentSnapshotSchedules() TridentSnapshotScheduleInformer {
+	return &tridentSnapshotScheduleInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // TridentStorageClasses returns a TridentStorageClassInformer.
 func (v *version) TridentStorageClasses() TridentStorageClassInformer {
 	return &tridentStorageClassInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
@@ -71,3 +101,8 @@ func (v *version) TridentVersions() TridentVersionInformer {
 func (v *version) TridentVolumes() TridentVolumeInformer {
 	return &tridentVolumeInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
+
+// TridentVolumeGroups returns a TridentVolumeGroupInformer.
+func (v *version) TridentVolumeGroups() TridentVolumeGroupInformer {
+	return &tridentVolumeGroupInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
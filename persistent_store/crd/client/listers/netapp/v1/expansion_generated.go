@@ -12,6 +12,22 @@ type TridentBackendListerExpansion interface{}
 // TridentBackendNamespaceLister.
 type TridentBackendNamespaceListerExpansion interface{}
 
+// TridentBackendConfigListerExpansion allows custom methods to be added to
+// TridentBackendConfigLister.
+type TridentBackendConfigListerExpansion interface{}
+
+// TridentBackendConfigNamespaceListerExpansion allows custom methods to be added to
+// TridentBackendConfigNamespaceLister.
+type TridentBackendConfigNamespaceListerExpansion interface{}
+
+// TridentCloneGrantListerExpansion allows custom methods to be added to
+// TridentCloneGrantLister.
+type TridentCloneGrantListerExpansion interface{}
+
+// TridentCloneGrantNamespaceListerExpansion allows custom methods to be added to
+// TridentCloneGrantNamespaceLister.
+type TridentCloneGrantNamespaceListerExpansion interface{}
+
 // TridentNodeListerExpansion allows custom methods to be added to
 // TridentNodeLister.
 type TridentNodeListerExpansion interface{}
@@ -20,6 +36,14 @@ type TridentNodeListerExpansion interface{}
 // TridentNodeNamespaceLister.
 type TridentNodeNamespaceListerExpansion interface{}
 
+// TridentQuotaListerExpansion allows custom methods to be added to
+// TridentQuotaLister.
+type TridentQuotaListerExpansion interface{}
+
+// TridentQuotaNamespaceListerExpansion allows custom methods to be added to
+// TridentQuotaNamespaceLister.
+type TridentQuotaNamespaceListerExpansion interface{}
+
 // TridentSnapshotListerExpansion allows custom methods to be added to
 // TridentSnapshotLister.
 type TridentSnapshotListerExpansion interface{}
@@ -28,6 +52,14 @@ type TridentSnapshotListerExpansion interface{}
 // TridentSnapshotNamespaceLister.
 type TridentSnapshotNamespaceListerExpansion interface{}
 
+// TridentSnapshotScheduleListerExpansion allows custom methods to be added to
+// TridentSnapshotScheduleLister.
+type TridentSnapshotScheduleListerExpansion interface{}
+
+// TridentSnapshotScheduleNamespaceListerExpansion allows custom methods to be added to
+// TridentSnapshotScheduleNamespaceLister.
+type TridentSnapshotScheduleNamespaceListerExpansion interface{}
+
 // TridentStorageClassListerExpansion allows custom methods to be added to
 // TridentStorageClassLister.
 type TridentStorageClassListerExpansion interface{}
@@ -59,3 +91,11 @@ type TridentVolumeListerExpansion interface{}
 // TridentVolumeNamespaceListerExpansion allows custom methods to be added to
 // TridentVolumeNamespaceLister.
 type TridentVolumeNamespaceListerExpansion interface{}
+
+// TridentVolumeGroupListerExpansion allows custom methods to be added to
+// TridentVolumeGroupLister.
+type TridentVolumeGroupListerExpansion interface{}
+
+// TridentVolumeGroupNamespaceListerExpansion allows custom methods to be added to
+// TridentVolumeGroupNamespaceLister.
+type TridentVolumeGroupNamespaceListerExpansion interface{}
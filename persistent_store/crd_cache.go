@@ -0,0 +1,298 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package persistentstore
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
+
+	v1 "github.com/netapp/trident/persistent_store/crd/apis/netapp/v1"
+	"github.com/netapp/trident/persistent_store/crd/client/clientset/versioned"
+	informers "github.com/netapp/trident/persistent_store/crd/client/informers/externalversions"
+	listers "github.com/netapp/trident/persistent_store/crd/client/listers/netapp/v1"
+)
+
+// crdCache serves the CRD store's reads of backends, volumes, storage classes, nodes and snapshots
+// from shared informers, so that listing them doesn't reach the API server.  Objects written by the
+// CRD store are stored in the cache as soon as the API server accepts them, so the store reads its
+// own writes without waiting for the informers' watches.  Transactions are always read from the API
+// server, as are the objects that the store reads in order to update them.
+type crdCache struct {
+	stopChan chan struct{}
+
+	backendInformer      cache.SharedIndexInformer
+	volumeInformer       cache.SharedIndexInformer
+	storageClassInformer cache.SharedIndexInformer
+	nodeInformer         cache.SharedIndexInformer
+	snapshotInformer     cache.SharedIndexInformer
+
+	backends       listers.TridentBackendNamespaceLister
+	volumes        listers.TridentVolumeNamespaceLister
+	storageClasses listers.TridentStorageClassNamespaceLister
+	nodes          listers.TridentNodeNamespaceLister
+	snapshots      listers.TridentSnapshotNamespaceLister
+}
+
+// newCRDCache starts informers for the Trident CRs in a namespace and waits up to syncTimeout
+// for their caches to be filled.  The informers have no event handlers, so they are never resynced.
+func newCRDCache(crdClient versioned.Interface, namespace string, syncTimeout time.Duration) (*crdCache, error) {
+
+	factory := informers.NewSharedInformerFactoryWithOptions(crdClient, 0, informers.WithNamespace(namespace))
+	trident := factory.Trident().V1()
+
+	c := &crdCache{
+		stopChan:             make(chan struct{}),
+		backendInformer:      trident.TridentBackends().Informer(),
+		volumeInformer:       trident.TridentVolumes().Informer(),
+		storageClassInformer: trident.TridentStorageClasses().Informer(),
+		nodeInformer:         trident.TridentNodes().Informer(),
+		snapshotInformer:     trident.TridentSnapshots().Informer(),
+		backends:             trident.TridentBackends().Lister().TridentBackends(namespace),
+		volumes:              trident.TridentVolumes().Lister().TridentVolumes(namespace),
+		storageClasses:       trident.TridentStorageClasses().Lister().TridentStorageClasses(namespace),
+		nodes:                trident.TridentNodes().Lister().TridentNodes(namespace),
+		snapshots:            trident.TridentSnapshots().Lister().TridentSnapshots(namespace),
+	}
+
+	factory.Start(c.stopChan)
+
+	// Give up waiting once the timeout expires, leaving the informers running until the cache is stopped
+	timeoutChan := make(chan struct{})
+	timer := time.AfterFunc(syncTimeout, func() { close(timeoutChan) })
+	defer timer.Stop()
+
+	if !cache.WaitForCacheSync(timeoutChan,
+		c.backendInformer.HasSynced,
+		c.volumeInformer.HasSynced,
+		c.storageClassInformer.HasSynced,
+		c.nodeInformer.HasSynced,
+		c.snapshotInformer.HasSynced) {
+		c.stop()
+		return nil, fmt.Errorf("informer caches did not sync within %v", syncTimeout)
+	}
+
+	log.WithField("namespace", namespace).Debug("CRD informer caches synced.")
+
+	return c, nil
+}
+
+// stop stops the cache's informers.
+func (c *crdCache) stop() {
+	if c != nil {
+		close(c.stopChan)
+	}
+}
+
+// informerFor returns the informer caching objects of the specified object's type, or nil if
+// objects of that type aren't cached.
+func (c *crdCache) informerFor(obj runtime.Object) cache.SharedIndexInformer {
+	switch obj.(type) {
+	case *v1.TridentBackend:
+		return c.backendInformer
+	case *v1.TridentVolume:
+		return c.volumeInformer
+	case *v1.TridentStorageClass:
+		return c.storageClassInformer
+	case *v1.TridentNode:
+		return c.nodeInformer
+	case *v1.TridentSnapshot:
+		return c.snapshotInformer
+	default:
+		return nil
+	}
+}
+
+// store writes an object returned by the API server through to the cache, unless the cache already
+// holds a later version of it.  Calling store on a nil cache is a no-op.
+func (c *crdCache) store(obj runtime.Object) {
+
+	if c == nil || obj == nil {
+		return
+	}
+	informer := c.informerFor(obj)
+	if informer == nil {
+		return
+	}
+	indexer := informer.GetIndexer()
+
+	if cached, exists, err := indexer.Get(obj); err == nil && exists {
+		if cachedObj, ok := cached.(runtime.Object); ok && isNewerResourceVersion(cachedObj, obj) {
+			return
+		}
+	}
+
+	if err := indexer.Update(obj); err != nil {
+		log.WithError(err).Warning("Could not write object through to the CRD informer cache.")
+	}
+}
+
+// remove deletes an object from the cache.  Calling remove on a nil cache is a no-op.
+func (c *crdCache) remove(obj runtime.Object) {
+
+	if c == nil || obj == nil {
+		return
+	}
+	informer := c.informerFor(obj)
+	if informer == nil {
+		return
+	}
+
+	if err := informer.GetIndexer().Delete(obj); err != nil {
+		log.WithError(err).Warning("Could not remove object from the CRD informer cache.")
+	}
+}
+
+// isNewerResourceVersion returns true if the first object has a later resource version than the
+// second.  Resource versions are compared only if both are integers, as they are with etcd-backed
+// API servers; otherwise the second object is assumed to be the later one.
+func isNewerResourceVersion(first, second runtime.Object) bool {
+
+	firstMeta, err := meta.Accessor(first)
+	if err != nil {
+		return false
+	}
+	secondMeta, err := meta.Accessor(second)
+	if err != nil {
+		return false
+	}
+
+	firstVersion, err := strconv.ParseUint(firstMeta.GetResourceVersion(), 10, 64)
+	if err != nil {
+		return false
+	}
+	secondVersion, err := strconv.ParseUint(secondMeta.GetResourceVersion(), 10, 64)
+	if err != nil {
+		return false
+	}
+
+	return firstVersion > secondVersion
+}
+
+// EnableInformerCache starts shared informers for the Trident CRs and serves subsequent reads of
+// backends, volumes, storage classes, nodes and snapshots from their caches.  If the caches don't
+// sync within syncTimeout, the informers are stopped and reads continue to go to the API server.
+func (k *CRDClientV1) EnableInformerCache(syncTimeout time.Duration) error {
+
+	if k.cache != nil {
+		return nil
+	}
+
+	c, err := newCRDCache(k.crdClient, k.namespace, syncTimeout)
+	if err != nil {
+		return err
+	}
+	k.cache = c
+
+	return nil
+}
+
+// objectMeta returns the metadata identifying a named object in the store's namespace, for
+// removing objects that were deleted by name from the cache.
+func (k *CRDClientV1) objectMeta(name string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{Name: name, Namespace: k.namespace}
+}
+
+// listBackendCRDs returns the backend CRs, from the cache if it's enabled.  The objects returned
+// from the cache are shared and must not be modified.
+func (k *CRDClientV1) listBackendCRDs() ([]*v1.TridentBackend, error) {
+	if k.cache != nil {
+		return k.cache.backends.List(labels.Everything())
+	}
+	list, err := k.crdClient.TridentV1().TridentBackends(k.namespace).List(ctx(), listOpts)
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// listVolumeCRDs returns the volume CRs, from the cache if it's enabled.  The objects returned
+// from the cache are shared and must not be modified.
+func (k *CRDClientV1) listVolumeCRDs() ([]*v1.TridentVolume, error) {
+	if k.cache != nil {
+		return k.cache.volumes.List(labels.Everything())
+	}
+	list, err := k.crdClient.TridentV1().TridentVolumes(k.namespace).List(ctx(), listOpts)
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// getVolumeCRD returns a volume CR, from the cache if it's enabled.
+func (k *CRDClientV1) getVolumeCRD(name string) (*v1.TridentVolume, error) {
+	if k.cache != nil {
+		return k.cache.volumes.Get(name)
+	}
+	return k.crdClient.TridentV1().TridentVolumes(k.namespace).Get(ctx(), name, getOpts)
+}
+
+// listStorageClassCRDs returns the storage class CRs, from the cache if it's enabled.  The
+// objects returned from the cache are shared and must not be modified.
+func (k *CRDClientV1) listStorageClassCRDs() ([]*v1.TridentStorageClass, error) {
+	if k.cache != nil {
+		return k.cache.storageClasses.List(labels.Everything())
+	}
+	list, err := k.crdClient.TridentV1().TridentStorageClasses(k.namespace).List(ctx(), listOpts)
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// getStorageClassCRD returns a storage class CR, from the cache if it's enabled.
+func (k *CRDClientV1) getStorageClassCRD(name string) (*v1.TridentStorageClass, error) {
+	if k.cache != nil {
+		return k.cache.storageClasses.Get(name)
+	}
+	return k.crdClient.TridentV1().TridentStorageClasses(k.namespace).Get(ctx(), name, getOpts)
+}
+
+// listNodeCRDs returns the node CRs, from the cache if it's enabled.  The objects returned from
+// the cache are shared and must not be modified.
+func (k *CRDClientV1) listNodeCRDs() ([]*v1.TridentNode, error) {
+	if k.cache != nil {
+		return k.cache.nodes.List(labels.Everything())
+	}
+	list, err := k.crdClient.TridentV1().TridentNodes(k.namespace).List(ctx(), listOpts)
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// getNodeCRD returns a node CR, from the cache if it's enabled.
+func (k *CRDClientV1) getNodeCRD(name string) (*v1.TridentNode, error) {
+	if k.cache != nil {
+		return k.cache.nodes.Get(name)
+	}
+	return k.crdClient.TridentV1().TridentNodes(k.namespace).Get(ctx(), name, getOpts)
+}
+
+// listSnapshotCRDs returns the snapshot CRs, from the cache if it's enabled.  The objects
+// returned from the cache are shared and must not be modified.
+func (k *CRDClientV1) listSnapshotCRDs() ([]*v1.TridentSnapshot, error) {
+	if k.cache != nil {
+		return k.cache.snapshots.List(labels.Everything())
+	}
+	list, err := k.crdClient.TridentV1().TridentSnapshots(k.namespace).List(ctx(), listOpts)
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// getSnapshotCRD returns a snapshot CR, from the cache if it's enabled.
+func (k *CRDClientV1) getSnapshotCRD(name string) (*v1.TridentSnapshot, error) {
+	if k.cache != nil {
+		return k.cache.snapshots.Get(name)
+	}
+	return k.crdClient.TridentV1().TridentSnapshots(k.namespace).Get(ctx(), name, getOpts)
+}
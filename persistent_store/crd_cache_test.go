@@ -0,0 +1,151 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package persistentstore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "github.com/netapp/trident/persistent_store/crd/apis/netapp/v1"
+	"github.com/netapp/trident/storage"
+	storageclass "github.com/netapp/trident/storage_class"
+	drivers "github.com/netapp/trident/storage_drivers"
+	"github.com/netapp/trident/storage_drivers/ontap"
+)
+
+func TestKubernetesInformerCache(t *testing.T) {
+	p, _ := GetTestKubernetesClient()
+	defer p.Stop()
+
+	// Volumes persisted before the cache is enabled are listed by the informers
+	volume := storage.NewVolume(&storage.VolumeConfig{Name: "vol1", Size: "1GB"}, "uuid1", "pool1", false)
+	if err := p.AddVolume(volume); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if err := p.EnableInformerCache(10 * time.Second); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if volumes, err := p.GetVolumes(); err != nil {
+		t.Fatal(err.Error())
+	} else if len(volumes) != 1 || volumes[0].Config.Name != "vol1" {
+		t.Errorf("Cache should hold the volume persisted before it was enabled; got %v", volumes)
+	}
+
+	// Objects written by the store are readable from the cache immediately
+	backend := &storage.Backend{
+		Driver: &ontap.NASStorageDriver{
+			Config: drivers.OntapStorageDriverConfig{
+				CommonStorageDriverConfig: &drivers.CommonStorageDriverConfig{
+					StorageDriverName: drivers.OntapNASStorageDriverName,
+				},
+				ManagementLIF: "10.0.0.4",
+				SVM:           "svm1",
+				Username:      "admin",
+				Password:      "netapp",
+			},
+		},
+		Name:        "nfs-server-1",
+		BackendUUID: uuid.New().String(),
+		State:       storage.Online,
+	}
+	if err := p.AddBackend(backend); err != nil {
+		t.Fatal(err.Error())
+	}
+	if hasBackends, err := p.HasBackends(); err != nil || !hasBackends {
+		t.Errorf("Cache should hold the added backend; %v", err)
+	}
+	if recovered, err := p.GetBackend(backend.Name); err != nil {
+		t.Error(err.Error())
+	} else if recovered.BackendUUID != backend.BackendUUID {
+		t.Errorf("Incorrect backend UUID; expected %s, got %s", backend.BackendUUID, recovered.BackendUUID)
+	}
+
+	if err := p.DeleteVolume(volume); err != nil {
+		t.Fatal(err.Error())
+	}
+	if hasVolumes, err := p.HasVolumes(); err != nil || hasVolumes {
+		t.Errorf("Cache should not hold the deleted volume; %v", err)
+	}
+
+	// Objects written by others are read from the cache once the informers see them
+	sc, err := v1.NewTridentStorageClass(storageclass.New(&storageclass.Config{Name: "gold"}).ConstructPersistent())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if _, err = p.crdClient.TridentV1().TridentStorageClasses(p.namespace).Create(ctx(), sc, createOpts); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		if storageClasses, err := p.GetStorageClasses(); err != nil {
+			t.Fatal(err.Error())
+		} else if len(storageClasses) == 1 {
+			break
+		} else if time.Now().After(deadline) {
+			t.Fatal("Cache did not see the storage class created by another client.")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestKubernetesInformerCacheWriteThrough(t *testing.T) {
+	p, _ := GetTestKubernetesClient()
+	p.namespace = "trident"
+	defer p.Stop()
+
+	if err := p.EnableInformerCache(10 * time.Second); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	node := &v1.TridentNode{ObjectMeta: p.objectMeta("node1"), IQN: "iqn.new"}
+	node.ResourceVersion = "5"
+	p.cache.store(node)
+
+	// An earlier version of an object doesn't replace the cached one
+	staleNode := node.DeepCopy()
+	staleNode.ResourceVersion = "4"
+	staleNode.IQN = "iqn.old"
+	p.cache.store(staleNode)
+
+	if cached, err := p.cache.nodes.Get("node1"); err != nil {
+		t.Fatal(err.Error())
+	} else if cached.IQN != "iqn.new" {
+		t.Errorf("Cache should keep the later version of the node; got IQN %s", cached.IQN)
+	}
+
+	p.cache.remove(&v1.TridentNode{ObjectMeta: p.objectMeta("node1")})
+	if _, err := p.cache.nodes.Get("node1"); !errors.IsNotFound(err) {
+		t.Errorf("Cache should not hold the removed node; got error %v", err)
+	}
+}
+
+func TestIsNewerResourceVersion(t *testing.T) {
+
+	tests := []struct {
+		first, second string
+		expected      bool
+	}{
+		{"5", "4", true},
+		{"4", "5", false},
+		{"5", "5", false},
+		{"", "5", false},
+		{"5", "", false},
+		{"abc", "1", false},
+	}
+
+	for _, test := range tests {
+		first := &v1.TridentNode{ObjectMeta: metav1.ObjectMeta{ResourceVersion: test.first}}
+		second := &v1.TridentNode{ObjectMeta: metav1.ObjectMeta{ResourceVersion: test.second}}
+		if actual := isNewerResourceVersion(first, second); actual != test.expected {
+			t.Errorf("isNewerResourceVersion(%q, %q) = %v, expected %v", test.first, test.second, actual,
+				test.expected)
+		}
+	}
+}
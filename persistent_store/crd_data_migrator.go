@@ -5,6 +5,7 @@ package persistentstore
 import (
 	"errors"
 	"fmt"
+	"reflect"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
@@ -18,6 +19,13 @@ import (
 const crRegistrationTimeout = 3 * time.Minute
 const failureInjectionBackendName = "failure-aa0c8eda-e992-470a-80be-a052fcb93fd9"
 
+// crdMigrationChunkSize is the number of objects copied between progress reports
+const crdMigrationChunkSize = 100
+
+// crdMigrationProgressURL is the etcd prefix of the markers recording each object copied to a custom
+// resource, which let an interrupted migration resume where it stopped
+var crdMigrationProgressURL = config.BaseURL + "/crdmigration"
+
 type CRDDataMigrator struct {
 	etcdClient    EtcdClient
 	crdClient     CRDClient
@@ -26,6 +34,42 @@ type CRDDataMigrator struct {
 	totalCount    int
 	migratedCount int
 	startTime     time.Time
+	resuming      bool
+	report        *CRDMigrationReport
+}
+
+// CRDMigrationReport summarizes a migration from etcd to custom resources.  Objects copied by an
+// earlier, interrupted run are counted as resumed rather than migrated.
+type CRDMigrationReport struct {
+	Total    int                    `json:"total"`
+	Migrated int                    `json:"migrated"`
+	Resumed  int                    `json:"resumed"`
+	Verified int                    `json:"verified"`
+	Failures []*CRDMigrationFailure `json:"failures,omitempty"`
+}
+
+// CRDMigrationFailure identifies an object that could not be copied to a custom resource, or
+// whose custom resource didn't match the object in etcd.
+type CRDMigrationFailure struct {
+	Kind   string `json:"kind"`
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+// crdMigrationObject is an object to be copied from etcd to a custom resource.  The get function
+// returns the object's custom resource, or nil if it doesn't exist.
+type crdMigrationObject struct {
+	kind   string
+	name   string
+	source interface{}
+	add    func() error
+	update func() error
+	get    func() (interface{}, error)
+}
+
+// progressKey returns the etcd key of the object's progress marker.
+func (o *crdMigrationObject) progressKey() string {
+	return crdMigrationProgressURL + "/" + o.kind + "/" + o.name
 }
 
 func NewCRDDataMigrator(etcdClient EtcdClient, crdClient CRDClient, dryRun bool, t *EtcdDataTransformer) *CRDDataMigrator {
@@ -36,11 +80,25 @@ func NewCRDDataMigrator(etcdClient EtcdClient, crdClient CRDClient, dryRun bool,
 		transformer:   t,
 		totalCount:    0,
 		migratedCount: 0,
+		report:        &CRDMigrationReport{Failures: make([]*CRDMigrationFailure, 0)},
 	}
 }
 
+// Report returns the outcome of the last call to Run.
+func (m *CRDDataMigrator) Report() *CRDMigrationReport {
+	return m.report
+}
+
 func (m *CRDDataMigrator) RunPrechecks() error {
 
+	// Progress markers are left by a migration that was interrupted, which this one resumes
+	if _, err := m.etcdClient.ReadKeys(crdMigrationProgressURL); err == nil {
+		log.Info("Found the progress of an interrupted migration, resuming it.")
+		m.resuming = true
+	} else if !MatchKeyNotFoundErr(err) {
+		return fmt.Errorf("could not check for the progress of an earlier migration; %v", err)
+	}
+
 	// Ensure we have valid etcd V3 data present
 	etcdVersion, err := m.etcdClient.GetVersion()
 	if err != nil {
@@ -56,13 +114,17 @@ func (m *CRDDataMigrator) RunPrechecks() error {
 			"OrchestratorAPIVersion": etcdVersion.OrchestratorAPIVersion,
 		}).Debug("Found etcdv3 persistent state version.")
 
-		if etcdVersion.PersistentStoreVersion != string(EtcdV3Store) {
+		// An interrupted migration may have already transformed the etcd data
+		resumable := m.resuming && etcdVersion.PersistentStoreVersion == string(EtcdV3bStore)
+
+		if etcdVersion.PersistentStoreVersion != string(EtcdV3Store) && !resumable {
 			return fmt.Errorf("etcd persistent state version is %s, not %s",
 				etcdVersion.PersistentStoreVersion, EtcdV3Store)
 		}
 	}
 
-	// Ensure there are no Trident objects already stored in custom resources
+	// Ensure there are no Trident objects already stored in custom resources, other than
+	// those copied by an interrupted migration
 	if err := m.ensureNoExistingCRs(); err != nil {
 		return err
 	}
@@ -78,40 +140,10 @@ func (m *CRDDataMigrator) ensureNoExistingCRs() error {
 
 	checkCRs := func() error {
 
-		// Ensure there are no CRD-based backends
-		if hasBackends, err := m.crdClient.HasBackends(); err != nil {
-			return fmt.Errorf("could not check for CRD-based backends; %v", err)
-		} else if hasBackends {
-			return backoff.Permanent(errors.New("CRD-based backends are already present, aborting migration"))
-		} else {
-			log.Debug("No CRD-based backends found.")
-		}
-
-		// Ensure there are no CRD-based storage classes
-		if hasStorageClasses, err := m.crdClient.HasStorageClasses(); err != nil {
-			return fmt.Errorf("could not check for CRD-based storage classes; %v", err)
-		} else if hasStorageClasses {
-			return backoff.Permanent(errors.New("CRD-based storage classes are already present, aborting migration"))
-		} else {
-			log.Debug("No CRD-based storage classes found.")
-		}
-
-		// Ensure there are no CRD-based volumes
-		if hasVolumes, err := m.crdClient.HasVolumes(); err != nil {
-			return fmt.Errorf("could not check for CRD-based volumes; %v", err)
-		} else if hasVolumes {
-			return backoff.Permanent(errors.New("CRD-based volumes are already present, aborting migration"))
-		} else {
-			log.Debug("No CRD-based volumes found.")
-		}
-
-		// Ensure there are no CRD-based volume transactions
-		if hasVolumeTransactions, err := m.crdClient.HasVolumeTransactions(); err != nil {
-			return fmt.Errorf("could not check for CRD-based transactions; %v", err)
-		} else if hasVolumeTransactions {
-			return backoff.Permanent(errors.New("CRD-based transactions are already present, aborting migration"))
-		} else {
-			log.Debug("No CRD-based transactions found.")
+		if !m.resuming {
+			if err := m.ensureNoExistingObjectCRs(); err != nil {
+				return err
+			}
 		}
 
 		// Ensure there is no CRD-based version
@@ -151,6 +183,54 @@ func (m *CRDDataMigrator) ensureNoExistingCRs() error {
 	return nil
 }
 
+// ensureNoExistingObjectCRs returns a permanent error if any backends, storage classes, volumes,
+// or transactions are already stored in custom resources.
+func (m *CRDDataMigrator) ensureNoExistingObjectCRs() error {
+
+	// Ensure there are no CRD-based backends
+	if hasBackends, err := m.crdClient.HasBackends(); err != nil {
+		return fmt.Errorf("could not check for CRD-based backends; %v", err)
+	} else if hasBackends {
+		return backoff.Permanent(errors.New("CRD-based backends are already present, aborting migration"))
+	} else {
+		log.Debug("No CRD-based backends found.")
+	}
+
+	// Ensure there are no CRD-based storage classes
+	if hasStorageClasses, err := m.crdClient.HasStorageClasses(); err != nil {
+		return fmt.Errorf("could not check for CRD-based storage classes; %v", err)
+	} else if hasStorageClasses {
+		return backoff.Permanent(errors.New("CRD-based storage classes are already present, aborting migration"))
+	} else {
+		log.Debug("No CRD-based storage classes found.")
+	}
+
+	// Ensure there are no CRD-based volumes
+	if hasVolumes, err := m.crdClient.HasVolumes(); err != nil {
+		return fmt.Errorf("could not check for CRD-based volumes; %v", err)
+	} else if hasVolumes {
+		return backoff.Permanent(errors.New("CRD-based volumes are already present, aborting migration"))
+	} else {
+		log.Debug("No CRD-based volumes found.")
+	}
+
+	// Ensure there are no CRD-based volume transactions
+	if hasVolumeTransactions, err := m.crdClient.HasVolumeTransactions(); err != nil {
+		return fmt.Errorf("could not check for CRD-based transactions; %v", err)
+	} else if hasVolumeTransactions {
+		return backoff.Permanent(errors.New("CRD-based transactions are already present, aborting migration"))
+	} else {
+		log.Debug("No CRD-based transactions found.")
+	}
+
+	return nil
+}
+
+// Run copies Trident's objects from etcd to custom resources.  Objects are copied in chunks, and a
+// progress marker is stored in etcd for each object once its custom resource is written, so that
+// an interrupted migration resumes where it stopped instead of starting over.  Objects that can't
+// be copied are reported rather than ending the migration, and every copied object is read back
+// and compared with the original before the CRD-based version is written.
 func (m *CRDDataMigrator) Run() error {
 
 	var (
@@ -182,32 +262,79 @@ func (m *CRDDataMigrator) Run() error {
 		return fmt.Errorf("could not read transactions from etcd; %v", err)
 	}
 
+	objects := make([]*crdMigrationObject, 0)
+	objects = append(objects, m.backendObjects(backends)...)
+	objects = append(objects, m.storageClassObjects(storageClasses)...)
+	objects = append(objects, m.volumeObjects(volumes)...)
+	objects = append(objects, m.transactionObjects(transactions)...)
+
 	// Determine number of objects to migrate
-	m.totalCount = len(backends) + len(storageClasses) + len(volumes) + len(transactions)
+	m.totalCount = len(objects)
+	m.migratedCount = 0
+	m.report = &CRDMigrationReport{Total: m.totalCount, Failures: make([]*CRDMigrationFailure, 0)}
+
+	if m.dryRun {
+		log.WithFields(log.Fields{
+			"backends":       len(backends),
+			"storageClasses": len(storageClasses),
+			"volumes":        len(volumes),
+			"transactions":   len(transactions),
+		}).Info("Dry run: read all objects.")
+		log.Info("Migration dry run completed, no problems found.")
+		return nil
+	}
 
 	// Save start time
 	m.startTime = time.Now()
 
-	log.Infof("Migrating %d objects. Please do not interrupt this operation!", m.totalCount)
+	log.Infof("Migrating %d objects. If this operation is interrupted, run it again to resume it.", m.totalCount)
 
-	// Migrate backends
-	if err := m.migrateBackends(backends); err != nil {
+	progress, err := m.readProgress()
+	if err != nil {
 		return err
 	}
 
-	// Migrate storage classes
-	if err := m.migrateStorageClasses(storageClasses); err != nil {
-		return err
+	// Copy the objects in chunks, skipping those copied by an interrupted migration
+	for start := 0; start < len(objects); start += crdMigrationChunkSize {
+		end := start + crdMigrationChunkSize
+		if end > len(objects) {
+			end = len(objects)
+		}
+		for _, object := range objects[start:end] {
+			if progress[object.progressKey()] {
+				m.report.Resumed++
+			} else if err := m.migrateObject(object); err != nil {
+				m.addFailure(object, err)
+				continue
+			} else {
+				m.report.Migrated++
+			}
+			m.migratedCount++
+		}
+		m.logTimeRemainingEstimate()
 	}
 
-	// Migrate volumes
-	if err := m.migrateVolumes(volumes); err != nil {
-		return err
-	}
+	// Compare each copied object with the original
+	m.verifyObjects(objects)
 
-	// Migrate transactions
-	if err := m.migrateTransactions(transactions); err != nil {
-		return err
+	log.WithFields(log.Fields{
+		"total":    m.report.Total,
+		"migrated": m.report.Migrated,
+		"resumed":  m.report.Resumed,
+		"verified": m.report.Verified,
+		"failed":   len(m.report.Failures),
+	}).Info("Migration report.")
+
+	if len(m.report.Failures) > 0 {
+		for _, failure := range m.report.Failures {
+			log.WithFields(log.Fields{
+				"kind":   failure.Kind,
+				"name":   failure.Name,
+				"reason": failure.Reason,
+			}).Error("Object could not be migrated.")
+		}
+		return fmt.Errorf("%d of %d objects could not be migrated, running the migration again will retry them",
+			len(m.report.Failures), m.report.Total)
 	}
 
 	// Write schema version to prevent future migrations
@@ -215,136 +342,222 @@ func (m *CRDDataMigrator) Run() error {
 		return err
 	}
 
-	if m.dryRun {
-		log.Info("Migration dry run completed, no problems found.")
-	} else {
-		log.WithField("count", m.migratedCount).Info("Migration succeeded.")
+	// The progress markers aren't needed once the version is written
+	if err := m.etcdClient.DeleteKeys(crdMigrationProgressURL); err != nil && !MatchKeyNotFoundErr(err) {
+		log.WithField("error", err).Warning("Could not delete migration progress markers.")
 	}
 
+	log.WithField("count", m.report.Migrated+m.report.Resumed).Info("Migration succeeded.")
+
 	return nil
 }
 
-func (m *CRDDataMigrator) migrateBackends(backends []*storage.BackendPersistent) error {
+// readProgress returns the progress markers of the objects copied by an interrupted migration.
+func (m *CRDDataMigrator) readProgress() (map[string]bool, error) {
 
-	if backends == nil || len(backends) == 0 {
-		if m.dryRun {
-			log.Info("Dry run: no backends found.")
-		} else {
-			log.Info("No backends found, none will be migrated.")
+	progress := make(map[string]bool)
+
+	keys, err := m.etcdClient.ReadKeys(crdMigrationProgressURL)
+	if err != nil {
+		if MatchKeyNotFoundErr(err) {
+			return progress, nil
 		}
-		return nil
+		return nil, fmt.Errorf("could not read migration progress from etcd; %v", err)
+	}
+	for _, key := range keys {
+		progress[key] = true
 	}
 
-	if m.dryRun {
-		log.WithField("count", len(backends)).Info("Dry run: read all backends.")
-		return nil
+	if len(progress) > 0 {
+		log.WithField("count", len(progress)).Info("Resuming migration, skipping objects already copied.")
 	}
 
-	for _, backend := range backends {
-		if backend.Name == failureInjectionBackendName {
-			return fmt.Errorf("failure injection for backend %v", failureInjectionBackendName)
+	return progress, nil
+}
+
+// migrateObject copies an object to a custom resource and records its progress.  A custom resource
+// that already exists was written by an interrupted migration before it recorded its progress, so
+// it is replaced if it doesn't match the object in etcd.
+func (m *CRDDataMigrator) migrateObject(object *crdMigrationObject) error {
+
+	existing, err := object.get()
+	if err != nil {
+		return fmt.Errorf("could not check for %s resource; %v", object.kind, err)
+	}
+
+	if existing == nil {
+		if err := object.add(); err != nil {
+			return fmt.Errorf("could not write %s resource; %v", object.kind, err)
 		}
-		if err := m.crdClient.AddBackendPersistent(backend); err != nil {
-			return fmt.Errorf("could not write backend resource; %v", err)
+	} else if !reflect.DeepEqual(existing, object.source) {
+		if err := object.update(); err != nil {
+			return fmt.Errorf("could not replace %s resource; %v", object.kind, err)
 		}
-		log.WithField("backend", backend.Name).Debug("Copied backend.")
+	}
 
-		m.migratedCount++
-		m.logTimeRemainingEstimate()
+	if err := m.etcdClient.Set(object.progressKey(), time.Now().UTC().Format(time.RFC3339)); err != nil {
+		return fmt.Errorf("could not record migration progress; %v", err)
 	}
-	log.WithField("count", len(backends)).Info("Copied all backends to CRD resources.")
+
+	log.WithFields(log.Fields{
+		"kind": object.kind,
+		"name": object.name,
+	}).Debug("Copied object.")
 
 	return nil
 }
 
-func (m *CRDDataMigrator) migrateStorageClasses(storageClasses []*storageclass.Persistent) error {
+// verifyObjects reads back each copied object's custom resource and compares it with the object in
+// etcd.  The progress markers of mismatched objects are removed, so that they are copied again by
+// the next migration.
+func (m *CRDDataMigrator) verifyObjects(objects []*crdMigrationObject) {
 
-	if len(storageClasses) == 0 {
-		if m.dryRun {
-			log.Info("Dry run: no storage classes found.")
-		} else {
-			log.Info("No storage classes found, none will be migrated.")
+	failed := make(map[*crdMigrationObject]bool)
+	for _, failure := range m.report.Failures {
+		for _, object := range objects {
+			if object.kind == failure.Kind && object.name == failure.Name {
+				failed[object] = true
+			}
 		}
-		return nil
 	}
 
-	if m.dryRun {
-		log.WithField("count", len(storageClasses)).Info("Dry run: read all storage classes.")
-		return nil
-	}
+	for _, object := range objects {
+		if failed[object] {
+			continue
+		}
 
-	for _, sc := range storageClasses {
-		if err := m.crdClient.AddStorageClassPersistent(sc); err != nil {
-			return fmt.Errorf("could not write storage class resource; %v", err)
+		existing, err := object.get()
+		if err != nil {
+			m.addFailure(object, fmt.Errorf("could not read back %s resource; %v", object.kind, err))
+			continue
+		} else if existing == nil {
+			m.addFailure(object, fmt.Errorf("%s resource not found", object.kind))
+		} else if !reflect.DeepEqual(existing, object.source) {
+			m.addFailure(object, fmt.Errorf("%s resource does not match etcd", object.kind))
+		} else {
+			m.report.Verified++
+			continue
 		}
-		log.WithField("sc", sc.GetName()).Debug("Copied storage class.")
 
-		m.migratedCount++
-		m.logTimeRemainingEstimate()
+		if err := m.etcdClient.Delete(object.progressKey()); err != nil && !MatchKeyNotFoundErr(err) {
+			log.WithFields(log.Fields{
+				"kind":  object.kind,
+				"name":  object.name,
+				"error": err,
+			}).Warning("Could not delete migration progress marker.")
+		}
 	}
-	log.WithField("count", len(storageClasses)).Info("Copied all storage classes to CRD resources.")
 
-	return nil
+	log.WithField("count", m.report.Verified).Info("Verified migrated objects.")
 }
 
-func (m *CRDDataMigrator) migrateVolumes(volumes []*storage.VolumeExternal) error {
-
-	if volumes == nil || len(volumes) == 0 {
-		if m.dryRun {
-			log.Info("Dry run: no volumes found.")
-		} else {
-			log.Info("No volumes found, none will be migrated.")
-		}
-		return nil
-	}
+func (m *CRDDataMigrator) addFailure(object *crdMigrationObject, err error) {
+	m.report.Failures = append(m.report.Failures, &CRDMigrationFailure{
+		Kind:   object.kind,
+		Name:   object.name,
+		Reason: err.Error(),
+	})
+}
 
-	if m.dryRun {
-		log.WithField("count", len(volumes)).Info("Dry run: read all volumes.")
-		return nil
-	}
+func (m *CRDDataMigrator) backendObjects(backends []*storage.BackendPersistent) []*crdMigrationObject {
 
-	for _, volume := range volumes {
-		if err := m.crdClient.AddVolumePersistent(volume); err != nil {
-			return fmt.Errorf("could not write volume resource; %v", err)
-		}
-		log.WithField("volume", volume.Config.Name).Debug("Copied volume.")
+	objects := make([]*crdMigrationObject, 0, len(backends))
+	for _, backend := range backends {
+		backend := backend
+		objects = append(objects, &crdMigrationObject{
+			kind:   "backend",
+			name:   backend.Name,
+			source: backend,
+			add: func() error {
+				if backend.Name == failureInjectionBackendName {
+					return fmt.Errorf("failure injection for backend %v", failureInjectionBackendName)
+				}
+				return m.crdClient.AddBackendPersistent(backend)
+			},
+			update: func() error { return m.crdClient.UpdateBackendPersistent(backend) },
+			get: func() (interface{}, error) {
+				return notFoundAsNil(m.crdClient.GetBackend(backend.Name))
+			},
+		})
+	}
+	return objects
+}
 
-		m.migratedCount++
-		m.logTimeRemainingEstimate()
-	}
-	log.WithField("count", len(volumes)).Info("Copied all volumes to CRD resources.")
+func (m *CRDDataMigrator) storageClassObjects(storageClasses []*storageclass.Persistent) []*crdMigrationObject {
 
-	return nil
+	objects := make([]*crdMigrationObject, 0, len(storageClasses))
+	for _, sc := range storageClasses {
+		sc := sc
+		objects = append(objects, &crdMigrationObject{
+			kind:   "storageClass",
+			name:   sc.GetName(),
+			source: sc,
+			add:    func() error { return m.crdClient.AddStorageClassPersistent(sc) },
+			update: func() error {
+				if err := m.crdClient.DeleteStorageClass(storageclass.NewFromPersistent(sc)); err != nil {
+					return err
+				}
+				return m.crdClient.AddStorageClassPersistent(sc)
+			},
+			get: func() (interface{}, error) {
+				return notFoundAsNil(m.crdClient.GetStorageClass(sc.GetName()))
+			},
+		})
+	}
+	return objects
 }
 
-func (m *CRDDataMigrator) migrateTransactions(transactions []*storage.VolumeTransaction) error {
+func (m *CRDDataMigrator) volumeObjects(volumes []*storage.VolumeExternal) []*crdMigrationObject {
 
-	if len(transactions) == 0 {
-		if m.dryRun {
-			log.Info("Dry run: no transactions found.")
-		} else {
-			log.Info("No transactions found, none will be migrated.")
-		}
-		return nil
-	}
+	objects := make([]*crdMigrationObject, 0, len(volumes))
+	for _, volume := range volumes {
+		volume := volume
+		objects = append(objects, &crdMigrationObject{
+			kind:   "volume",
+			name:   volume.Config.Name,
+			source: volume,
+			add:    func() error { return m.crdClient.AddVolumePersistent(volume) },
+			update: func() error { return m.crdClient.UpdateVolumePersistent(volume) },
+			get: func() (interface{}, error) {
+				return notFoundAsNil(m.crdClient.GetVolume(volume.Config.Name))
+			},
+		})
+	}
+	return objects
+}
 
-	if m.dryRun {
-		log.WithField("count", len(transactions)).Info("Dry run: read all transactions.")
-		return nil
-	}
+func (m *CRDDataMigrator) transactionObjects(transactions []*storage.VolumeTransaction) []*crdMigrationObject {
 
+	objects := make([]*crdMigrationObject, 0, len(transactions))
 	for _, txn := range transactions {
-		if err := m.crdClient.AddVolumeTransaction(txn); err != nil {
-			return fmt.Errorf("could not write transaction resource; %v", err)
-		}
-		log.WithField("volume", txn.Config.Name).Debug("Copied transaction.")
+		txn := txn
+		objects = append(objects, &crdMigrationObject{
+			kind:   "transaction",
+			name:   txn.Name(),
+			source: txn,
+			add:    func() error { return m.crdClient.AddVolumeTransaction(txn) },
+			update: func() error { return m.crdClient.UpdateVolumeTransaction(txn) },
+			get: func() (interface{}, error) {
+				return notFoundAsNil(m.crdClient.GetExistingVolumeTransaction(txn))
+			},
+		})
+	}
+	return objects
+}
 
-		m.migratedCount++
-		m.logTimeRemainingEstimate()
+// notFoundAsNil converts the result of reading a custom resource to a nil object if the resource
+// doesn't exist.  It also converts typed nil pointers to an untyped nil.
+func notFoundAsNil(object interface{}, err error) (interface{}, error) {
+	if err != nil {
+		if MatchKeyNotFoundErr(err) || IsStatusNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, err
 	}
-	log.WithField("count", len(transactions)).Info("Copied all transactions to CRD resources.")
-
-	return nil
+	if value := reflect.ValueOf(object); !value.IsValid() || (value.Kind() == reflect.Ptr && value.IsNil()) {
+		return nil, nil
+	}
+	return object, nil
 }
 
 func (m *CRDDataMigrator) writeCRDSchemaVersion() error {
@@ -393,8 +606,8 @@ func (m *CRDDataMigrator) writeEtcdSchemaVersion() error {
 
 func (m *CRDDataMigrator) logTimeRemainingEstimate() {
 
-	// Ensure we have migrated something, and log the time remaining every 100 objects
-	if (m.migratedCount%100) != 0 || m.migratedCount <= 0 {
+	// Ensure we have migrated something
+	if m.migratedCount <= 0 {
 		return
 	}
 
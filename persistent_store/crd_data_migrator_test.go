@@ -5,6 +5,7 @@ package persistentstore
 import (
 	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/google/uuid"
@@ -603,3 +604,214 @@ func TestEtcdV3ToCRDV1MigrationNoData(t *testing.T) {
 		t.Fatalf("Incorrect version after migration: %v", version.PersistentStoreVersion)
 	}
 }
+
+// fakeEtcdClient is an in-memory etcd client, which lets migrations be tested without an etcd server.
+type fakeEtcdClient struct {
+	*InMemoryClient
+	version *config.PersistentStateVersion
+	keys    map[string]string
+}
+
+func newFakeEtcdClient() *fakeEtcdClient {
+	return &fakeEtcdClient{InMemoryClient: NewInMemoryClient(), keys: make(map[string]string)}
+}
+
+func (c *fakeEtcdClient) GetVersion() (*config.PersistentStateVersion, error) {
+	if c.version == nil {
+		return nil, NewPersistentStoreError(KeyNotFoundErr, config.StoreURL)
+	}
+	return c.version, nil
+}
+
+func (c *fakeEtcdClient) SetVersion(version *config.PersistentStateVersion) error {
+	c.version = version
+	return nil
+}
+
+func (c *fakeEtcdClient) Create(key, value string) error {
+	if _, ok := c.keys[key]; ok {
+		return NewPersistentStoreError(KeyExistsErr, key)
+	}
+	return c.Set(key, value)
+}
+
+func (c *fakeEtcdClient) Read(key string) (string, error) {
+	value, ok := c.keys[key]
+	if !ok {
+		return "", NewPersistentStoreError(KeyNotFoundErr, key)
+	}
+	return value, nil
+}
+
+func (c *fakeEtcdClient) ReadKeys(keyPrefix string) ([]string, error) {
+	keys := make([]string, 0)
+	for key := range c.keys {
+		if strings.HasPrefix(key, keyPrefix) {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return keys, NewPersistentStoreError(KeyNotFoundErr, keyPrefix)
+	}
+	return keys, nil
+}
+
+func (c *fakeEtcdClient) Update(key, value string) error {
+	if _, err := c.Read(key); err != nil {
+		return err
+	}
+	return c.Set(key, value)
+}
+
+func (c *fakeEtcdClient) Set(key, value string) error {
+	c.keys[key] = value
+	return nil
+}
+
+func (c *fakeEtcdClient) Delete(key string) error {
+	if _, err := c.Read(key); err != nil {
+		return err
+	}
+	delete(c.keys, key)
+	return nil
+}
+
+func (c *fakeEtcdClient) DeleteKeys(keyPrefix string) error {
+	keys, err := c.ReadKeys(keyPrefix)
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		delete(c.keys, key)
+	}
+	return nil
+}
+
+// faultyCRDClient fails to write one volume, and misreports another, to test the handling of
+// objects that can't be migrated.
+type faultyCRDClient struct {
+	CRDClient
+	failVolume  string
+	alterVolume string
+}
+
+func (c *faultyCRDClient) AddVolumePersistent(volume *storage.VolumeExternal) error {
+	if volume.Config.Name == c.failVolume {
+		return fmt.Errorf("injected failure for volume %s", volume.Config.Name)
+	}
+	return c.CRDClient.AddVolumePersistent(volume)
+}
+
+func (c *faultyCRDClient) GetVolume(volumeName string) (*storage.VolumeExternal, error) {
+	volume, err := c.CRDClient.GetVolume(volumeName)
+	if err == nil && volumeName == c.alterVolume {
+		altered := *volume
+		altered.Backend = "altered"
+		return &altered, nil
+	}
+	return volume, err
+}
+
+func TestCRDDataMigrationResume(t *testing.T) {
+
+	etcdClient := newFakeEtcdClient()
+	if err := populateEtcdCluster(etcdClient); err != nil {
+		t.Fatalf("Populating the source etcd cluster failed: %v", err)
+	}
+	crdClient, _ := GetTestKubernetesClient()
+
+	// Interrupt the migration by failing to copy a volume
+	faultyClient := &faultyCRDClient{CRDClient: crdClient, failVolume: "fake_volume_2"}
+	transformer := NewEtcdDataTransformer(etcdClient, false, true)
+	dataMigrator := NewCRDDataMigrator(etcdClient, faultyClient, false, transformer)
+	if err := dataMigrator.RunPrechecks(); err != nil {
+		t.Fatalf("CRD data migration prechecks failed: %v", err)
+	}
+	if err := dataMigrator.Run(); err == nil {
+		t.Fatal("CRD data migration should have failed!")
+	}
+
+	report := dataMigrator.Report()
+	if report.Total != 13 || report.Migrated != 12 || report.Resumed != 0 || report.Verified != 12 {
+		t.Fatalf("Unexpected migration report: %+v", report)
+	}
+	if len(report.Failures) != 1 || report.Failures[0].Kind != "volume" ||
+		report.Failures[0].Name != "fake_volume_2" {
+		t.Fatalf("Unexpected migration failures: %+v", report.Failures)
+	}
+	if _, err := crdClient.GetVersion(); !MatchKeyNotFoundErr(err) {
+		t.Fatalf("CRD version should not be written after a failed migration: %v", err)
+	}
+
+	// Resume the migration, which copies only the volume that failed
+	transformer = NewEtcdDataTransformer(etcdClient, false, true)
+	dataMigrator = NewCRDDataMigrator(etcdClient, crdClient, false, transformer)
+	if err := dataMigrator.RunPrechecks(); err != nil {
+		t.Fatalf("CRD data migration prechecks failed when resuming: %v", err)
+	}
+	if err := dataMigrator.Run(); err != nil {
+		t.Fatalf("CRD data migration failed when resuming: %v", err)
+	}
+
+	report = dataMigrator.Report()
+	if report.Migrated != 1 || report.Resumed != 12 || report.Verified != 13 || len(report.Failures) != 0 {
+		t.Fatalf("Unexpected migration report: %+v", report)
+	}
+	if _, err := crdClient.GetVolume("fake_volume_2"); err != nil {
+		t.Fatalf("Volume not found in CRD: %v", err)
+	}
+	if version, err := crdClient.GetVersion(); err != nil || version.PersistentStoreVersion != string(CRDV1Store) {
+		t.Fatalf("Incorrect version after migration: %v, %v", version, err)
+	}
+	if _, err := etcdClient.ReadKeys(crdMigrationProgressURL); !MatchKeyNotFoundErr(err) {
+		t.Fatalf("Migration progress should be deleted after migration: %v", err)
+	}
+
+	// A completed migration isn't run again
+	transformer = NewEtcdDataTransformer(etcdClient, false, true)
+	dataMigrator = NewCRDDataMigrator(etcdClient, crdClient, false, transformer)
+	if err := dataMigrator.RunPrechecks(); err == nil {
+		t.Fatal("CRD data migration prechecks should have failed!")
+	}
+}
+
+func TestCRDDataMigrationVerification(t *testing.T) {
+
+	etcdClient := newFakeEtcdClient()
+	if err := populateEtcdCluster(etcdClient); err != nil {
+		t.Fatalf("Populating the source etcd cluster failed: %v", err)
+	}
+	crdClient, _ := GetTestKubernetesClient()
+
+	// A volume that doesn't read back as written fails verification
+	faultyClient := &faultyCRDClient{CRDClient: crdClient, alterVolume: "fake_volume_3"}
+	transformer := NewEtcdDataTransformer(etcdClient, false, true)
+	dataMigrator := NewCRDDataMigrator(etcdClient, faultyClient, false, transformer)
+	if err := dataMigrator.RunPrechecks(); err != nil {
+		t.Fatalf("CRD data migration prechecks failed: %v", err)
+	}
+	if err := dataMigrator.Run(); err == nil {
+		t.Fatal("CRD data migration should have failed!")
+	}
+
+	report := dataMigrator.Report()
+	if report.Migrated != 13 || report.Verified != 12 || len(report.Failures) != 1 ||
+		report.Failures[0].Name != "fake_volume_3" {
+		t.Fatalf("Unexpected migration report: %+v", report)
+	}
+
+	// The volume that failed verification is copied again when the migration is resumed
+	transformer = NewEtcdDataTransformer(etcdClient, false, true)
+	dataMigrator = NewCRDDataMigrator(etcdClient, crdClient, false, transformer)
+	if err := dataMigrator.RunPrechecks(); err != nil {
+		t.Fatalf("CRD data migration prechecks failed when resuming: %v", err)
+	}
+	if err := dataMigrator.Run(); err != nil {
+		t.Fatalf("CRD data migration failed when resuming: %v", err)
+	}
+
+	report = dataMigrator.Report()
+	if report.Migrated != 1 || report.Resumed != 12 || report.Verified != 13 {
+		t.Fatalf("Unexpected migration report: %+v", report)
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package persistentstore
+
+import (
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	v1 "github.com/netapp/trident/persistent_store/crd/apis/netapp/v1"
+)
+
+// GetBackendUUID returns the UUID recorded in a backend's custom resource.  Unlike GetBackend, it
+// doesn't read the backend's secret, and it finds resources that are being deleted.
+func (k *CRDClientV1) GetBackendUUID(backendName string) (string, error) {
+
+	backend, err := k.getBackendCRD(backendName)
+	if err != nil {
+		return "", err
+	}
+
+	return backend.BackendUUID, nil
+}
+
+// ForceDeleteBackend removes Trident's finalizers from a backend's custom resource, deletes the
+// resource if it isn't already being deleted, and deletes the backend's secret.  The resource is
+// removed even if Trident's CRD controller isn't running to release it, so the caller must first
+// verify that Trident no longer manages the backend.
+func (k *CRDClientV1) ForceDeleteBackend(backendName string) error {
+
+	backend, err := k.getBackendCRD(backendName)
+	if err != nil {
+		return err
+	}
+
+	if backend.HasTridentFinalizers() {
+		backendCopy := backend.DeepCopy()
+		backendCopy.RemoveTridentFinalizers()
+		if backend, err = k.crdClient.TridentV1().TridentBackends(k.namespace).Update(
+			ctx(), backendCopy, updateOpts); err != nil {
+			return err
+		}
+		log.WithField("backend", backendName).Debug("Removed finalizers from backend resource.")
+	}
+
+	if backend.DeletionTimestamp.IsZero() {
+		err = k.crdClient.TridentV1().TridentBackends(k.namespace).Delete(ctx(), backend.Name, k.deleteOpts())
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	k.cache.remove(backend)
+
+	secretName := k.backendSecretName(backend.BackendUUID)
+	if err = k.k8sClient.DeleteSecretDefault(secretName); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+
+	log.WithFields(log.Fields{
+		"backend":  backendName,
+		"resource": backend.Name,
+	}).Info("Force deleted backend resource.")
+
+	return nil
+}
+
+// ForceDeleteVolume removes Trident's finalizers from a volume's custom resource, and deletes the
+// resource if it isn't already being deleted.  The resource is removed even if Trident's CRD
+// controller isn't running to release it, so the caller must first verify that the volume no
+// longer exists.
+func (k *CRDClientV1) ForceDeleteVolume(volumeName string) error {
+
+	name := v1.NameFix(volumeName)
+	volume, err := k.crdClient.TridentV1().TridentVolumes(k.namespace).Get(ctx(), name, getOpts)
+	if err != nil {
+		return err
+	}
+
+	if volume.HasTridentFinalizers() {
+		volumeCopy := volume.DeepCopy()
+		volumeCopy.RemoveTridentFinalizers()
+		if volume, err = k.crdClient.TridentV1().TridentVolumes(k.namespace).Update(
+			ctx(), volumeCopy, updateOpts); err != nil {
+			return err
+		}
+		log.WithField("volume", volumeName).Debug("Removed finalizers from volume resource.")
+	}
+
+	if volume.DeletionTimestamp.IsZero() {
+		err = k.crdClient.TridentV1().TridentVolumes(k.namespace).Delete(ctx(), name, k.deleteOpts())
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	k.cache.remove(&v1.TridentVolume{ObjectMeta: k.objectMeta(name)})
+
+	log.WithField("volume", volumeName).Info("Force deleted volume resource.")
+
+	return nil
+}
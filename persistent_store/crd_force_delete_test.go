@@ -0,0 +1,81 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package persistentstore
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	v1 "github.com/netapp/trident/persistent_store/crd/apis/netapp/v1"
+	"github.com/netapp/trident/storage"
+	drivers "github.com/netapp/trident/storage_drivers"
+	"github.com/netapp/trident/storage_drivers/ontap"
+)
+
+func TestKubernetesForceDeleteBackend(t *testing.T) {
+	p, _ := GetTestKubernetesClient()
+
+	backend := &storage.Backend{
+		Driver: &ontap.NASStorageDriver{
+			Config: drivers.OntapStorageDriverConfig{
+				CommonStorageDriverConfig: &drivers.CommonStorageDriverConfig{
+					StorageDriverName: drivers.OntapNASStorageDriverName,
+				},
+				ManagementLIF: "10.0.0.4",
+				SVM:           "svm1",
+				Username:      "admin",
+				Password:      "netapp",
+			},
+		},
+		Name:        "nfs-server-1",
+		BackendUUID: uuid.New().String(),
+		State:       storage.Online,
+	}
+	if err := p.AddBackend(backend); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	backendUUID, err := p.GetBackendUUID(backend.Name)
+	assert.NoError(t, err)
+	assert.Equal(t, backend.BackendUUID, backendUUID)
+
+	if err = p.ForceDeleteBackend(backend.Name); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	_, err = p.getBackendCRD(backend.Name)
+	assert.True(t, MatchKeyNotFoundErr(err), "expected the backend resource to be deleted")
+	_, err = p.k8sClient.GetSecret(p.backendSecretName(backend.BackendUUID))
+	assert.True(t, errors.IsNotFound(err), "expected the backend secret to be deleted")
+
+	err = p.ForceDeleteBackend(backend.Name)
+	assert.True(t, MatchKeyNotFoundErr(err), "expected a missing backend resource to be reported")
+}
+
+func TestKubernetesForceDeleteVolume(t *testing.T) {
+	p, _ := GetTestKubernetesClient()
+
+	volume := storage.NewVolume(&storage.VolumeConfig{Name: "vol1", Size: "1GB"}, "uuid1", "pool1", false)
+	if err := p.AddVolume(volume); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	volumeCRD, err := p.crdClient.TridentV1().TridentVolumes(p.namespace).Get(ctx(), v1.NameFix("vol1"), getOpts)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	assert.True(t, volumeCRD.HasTridentFinalizers())
+
+	if err = p.ForceDeleteVolume("vol1"); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	_, err = p.GetVolume("vol1")
+	assert.True(t, IsStatusNotFoundError(err), "expected the volume resource to be deleted")
+
+	err = p.ForceDeleteVolume("vol1")
+	assert.True(t, IsStatusNotFoundError(err), "expected a missing volume resource to be reported")
+}
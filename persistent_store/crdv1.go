@@ -48,6 +48,8 @@ type CRDClientV1 struct {
 	k8sClient k8sclient.Interface
 	version   *config.PersistentStateVersion
 	namespace string
+	encrypter *SecretEncrypter
+	cache     *crdCache
 }
 
 func NewCRDClientV1(apiServerIP, kubeConfigPath string) (*CRDClientV1, error) {
@@ -118,6 +120,13 @@ func newCRDClientV1(kubeConfig *rest.Config, k8sClient k8sclient.Interface) (*CR
 	}, err
 }
 
+// SetSecretEncrypter configures the client to encrypt the backend secrets it writes.  Secrets
+// written before encryption was configured are still read, and are encrypted the next time their
+// backends are updated.
+func (k *CRDClientV1) SetSecretEncrypter(encrypter *SecretEncrypter) {
+	k.encrypter = encrypter
+}
+
 func (k *CRDClientV1) GetVersion() (*config.PersistentStateVersion, error) {
 
 	versionList, err := k.crdClient.TridentV1().TridentVersions(k.namespace).List(ctx(), listOpts)
@@ -195,6 +204,8 @@ func (k *CRDClientV1) GetType() StoreType {
 }
 
 func (k *CRDClientV1) Stop() error {
+	k.cache.stop()
+	k.cache = nil
 	return nil
 }
 
@@ -252,6 +263,9 @@ func (k *CRDClientV1) addBackendPersistent(backendPersistent *storage.BackendPer
 	if err != nil {
 		return err
 	}
+	if secretMap, err = sealSecret(k.encrypter, secretName, secretMap); err != nil {
+		return err
+	}
 
 	// Create the backend resource struct
 	tridentBackend, err := v1.NewTridentBackend(redactedBackendPersistent)
@@ -281,6 +295,7 @@ func (k *CRDClientV1) addBackendPersistent(backendPersistent *storage.BackendPer
 		if deleteErr != nil {
 			log.WithField("backend", crd.Name).Error("Could not delete backend resource after secret create failure.")
 		} else {
+			k.cache.remove(crd)
 			log.WithField("backend", crd.Name).Warning("Deleted backend resource after secret create failure.")
 		}
 
@@ -288,6 +303,8 @@ func (k *CRDClientV1) addBackendPersistent(backendPersistent *storage.BackendPer
 	}
 	log.WithField("secret", secretName).Debug("Created backend secret.")
 
+	k.updateBackendStatus(crd)
+
 	return nil
 }
 
@@ -318,18 +335,48 @@ func (k *CRDClientV1) makeBackendSecret(
 	}
 }
 
+// backendSecretData decodes a backend secret's data into a map.  The fake client returns only
+// StringData while the real API returns only Data, so we must use both here to support the unit tests.
+func backendSecretData(secret *corev1.Secret) map[string]string {
+	secretMap := make(map[string]string)
+	for key, value := range secret.Data {
+		secretMap[key] = string(value)
+	}
+	for key, value := range secret.StringData {
+		secretMap[key] = value
+	}
+	return secretMap
+}
+
+// setBackendSecretData replaces the data of an existing backend secret.  The secret's Data is cleared,
+// so that keys from an earlier form of the secret, such as before it was encrypted, aren't kept.
+func setBackendSecretData(secret *corev1.Secret, secretMap map[string]string) {
+	secret.Data = nil
+	secret.StringData = secretMap
+}
+
 // addBackendCRD accepts a backend resource structure and creates it in Kubernetes.
 func (k *CRDClientV1) addBackendCRD(backend *v1.TridentBackend) (*v1.TridentBackend, error) {
 
 	log.WithField("backendName", backend.Name).Debug("addBackendCRD")
 
-	return k.crdClient.TridentV1().TridentBackends(k.namespace).Create(ctx(), backend, createOpts)
+	crd, err := k.crdClient.TridentV1().TridentBackends(k.namespace).Create(ctx(), backend, createOpts)
+	if err != nil {
+		return nil, err
+	}
+	k.cache.store(crd)
+	return crd, nil
 }
 
 // HasBackends returns true if any backend objects have been persisted as custom
 // resources in the current namespace.
 func (k *CRDClientV1) HasBackends() (bool, error) {
 
+	if k.cache != nil {
+		backends, err := k.listBackendCRDs()
+		return len(backends) > 0, err
+	}
+
 	listOneOpts := metav1.ListOptions{Limit: 1}
 	backendList, err := k.crdClient.TridentV1().TridentBackends(k.namespace).List(ctx(), listOneOpts)
 	if err != nil {
@@ -347,17 +394,17 @@ func (k *CRDClientV1) GetBackend(backendName string) (*storage.BackendPersistent
 	log.WithField("backendName", backendName).Debug("GetBackend")
 
 	// Get all backend resources
-	list, err := k.crdClient.TridentV1().TridentBackends(k.namespace).List(ctx(), listOpts)
+	backends, err := k.listBackendCRDs()
 	if err != nil {
 		return nil, err
-	} else if list == nil || list.Items == nil || len(list.Items) == 0 {
+	} else if len(backends) == 0 {
 		return nil, NewPersistentStoreError(KeyNotFoundErr, backendName)
 	}
 
 	var backendPersistent *storage.BackendPersistent
 
 	// Find the backend with the name we want
-	for _, backend := range list.Items {
+	for _, backend := range backends {
 
 		log.WithFields(log.Fields{
 			"name":        backend.Name,
@@ -381,6 +428,57 @@ func (k *CRDClientV1) GetBackend(backendName string) (*storage.BackendPersistent
 	return k.addSecretToBackend(backendPersistent)
 }
 
+// ReencryptBackendSecrets rewrites every backend secret encrypted with the key provider's active
+// key, so that older keys may be retired after a key rotation.  Unencrypted secrets are encrypted.
+// It returns the names of the backends whose secrets were rewritten, up to any failure.
+func (k *CRDClientV1) ReencryptBackendSecrets() ([]string, error) {
+
+	if k.encrypter == nil {
+		return nil, utils.UnsupportedError("backend secret encryption is not configured")
+	}
+
+	list, err := k.crdClient.TridentV1().TridentBackends(k.namespace).List(ctx(), listOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	reencrypted := make([]string, 0)
+	for _, backend := range list.Items {
+
+		secretName := k.backendSecretName(backend.BackendUUID)
+		logFields := log.Fields{"backend": backend.BackendName, "secret": secretName}
+
+		if exists, err := k.k8sClient.CheckSecretExists(secretName); err != nil {
+			return reencrypted, err
+		} else if !exists {
+			log.WithFields(logFields).Warning("Backend secret does not exist, skipping re-encryption.")
+			continue
+		}
+
+		secret, err := k.k8sClient.GetSecret(secretName)
+		if err != nil {
+			return reencrypted, err
+		}
+		secretMap, err := k.encrypter.Open(secretName, backendSecretData(secret))
+		if err != nil {
+			return reencrypted, err
+		}
+		if secretMap, err = k.encrypter.Seal(secretName, secretMap); err != nil {
+			return reencrypted, err
+		}
+		setBackendSecretData(secret, secretMap)
+
+		if _, err = k.k8sClient.UpdateSecret(secret); err != nil {
+			return reencrypted, fmt.Errorf("could not update secret %s; %v", secretName, err)
+		}
+		log.WithFields(logFields).Debug("Re-encrypted backend secret.")
+
+		reencrypted = append(reencrypted, backend.BackendName)
+	}
+
+	return reencrypted, nil
+}
+
 // getBackendCRD retrieves the list of backends persisted as custom resources, finds the
 // one with the specified backend name, and returns the CRD form of the object.  This is
 // an internal method that does not fetch any Secret data.
@@ -409,6 +507,24 @@ func (k *CRDClientV1) getBackendCRD(backendName string) (*v1.TridentBackend, err
 	return nil, NewPersistentStoreError(KeyNotFoundErr, backendName)
 }
 
+// findBackendCRD finds the backend resource with the specified backend name, from the cache if it's
+// enabled.  The object returned from the cache is shared and must not be modified.  Callers that
+// update the backend's spec should use getBackendCRD, which always reads from the API server.
+func (k *CRDClientV1) findBackendCRD(backendName string) (*v1.TridentBackend, error) {
+
+	backends, err := k.listBackendCRDs()
+	if err != nil {
+		return nil, err
+	}
+	for _, backend := range backends {
+		if backend.BackendName == backendName {
+			return backend, nil
+		}
+	}
+
+	return nil, NewPersistentStoreError(KeyNotFoundErr, backendName)
+}
+
 // addSecretToBackend accepts a BackendPersistent object, retrieves the corresponding secret
 // containing any sensitive data for that backend, and returns the same object with all of its
 // sensitive fields filled in.
@@ -444,14 +560,11 @@ func (k *CRDClientV1) addSecretToBackend(
 		return nil, err
 	}
 
-	// Decode secret data into map.  The fake client returns only StringData while the real
-	// API returns only Data, so we must use both here to support the unit tests.
-	secretMap := make(map[string]string)
-	for key, value := range secret.Data {
-		secretMap[key] = string(value)
-	}
-	for key, value := range secret.StringData {
-		secretMap[key] = value
+	// Decrypt the secret if it was encrypted
+	secretMap, err := k.encrypter.Open(secretName, backendSecretData(secret))
+	if err != nil {
+		log.WithFields(logFields).Errorf("Could not decrypt backend secret; %v", err)
+		return nil, err
 	}
 	// Set all sensitive fields on the backend
 	if err := backendPersistent.InjectBackendSecrets(secretMap); err != nil {
@@ -507,6 +620,9 @@ func (k *CRDClientV1) updateBackendPersistent(backendPersistent *storage.Backend
 	if err != nil {
 		return err
 	}
+	if secretMap, err = sealSecret(k.encrypter, secretName, secretMap); err != nil {
+		return err
+	}
 
 	// Update the backend resource struct
 	if err = crd.Apply(redactedBackendPersistent); err != nil {
@@ -514,9 +630,11 @@ func (k *CRDClientV1) updateBackendPersistent(backendPersistent *storage.Backend
 	}
 
 	// Update the backend resource in Kubernetes
-	if _, err = k.crdClient.TridentV1().TridentBackends(k.namespace).Update(ctx(), crd, updateOpts); err != nil {
+	updatedCRD, err := k.crdClient.TridentV1().TridentBackends(k.namespace).Update(ctx(), crd, updateOpts)
+	if err != nil {
 		return err
 	}
+	k.cache.store(updatedCRD)
 
 	log.WithFields(log.Fields{
 		"backendName": crd.BackendName,
@@ -561,7 +679,7 @@ func (k *CRDClientV1) updateBackendPersistent(backendPersistent *storage.Backend
 		} else {
 
 			// Copy the backend's sensitive info into the secret
-			secret.StringData = secretMap
+			setBackendSecretData(secret, secretMap)
 
 			// Update the secret
 			if _, secretError = k.k8sClient.UpdateSecret(secret); secretError != nil {
@@ -578,10 +696,12 @@ func (k *CRDClientV1) updateBackendPersistent(backendPersistent *storage.Backend
 	if secretError != nil {
 
 		// If the secret update failed, unroll the backend update
-		_, updateErr := k.crdClient.TridentV1().TridentBackends(k.namespace).Update(ctx(), origCRDCopy, updateOpts)
+		backends := k.crdClient.TridentV1().TridentBackends(k.namespace)
+		restoredCRD, updateErr := backends.Update(ctx(), origCRDCopy, updateOpts)
 		if updateErr != nil {
 			log.WithField("backend", crd.Name).Error("Could not restore backend after secret update failure.")
 		} else {
+			k.cache.store(restoredCRD)
 			log.WithField("backend", crd.Name).Warning("Restored backend after secret update failure.")
 		}
 
@@ -590,6 +710,46 @@ func (k *CRDClientV1) updateBackendPersistent(backendPersistent *storage.Backend
 
 	log.WithField("secret", secretName).Debug("Updated backend secret.")
 
+	k.updateBackendStatus(updatedCRD)
+
+	return nil
+}
+
+// updateBackendStatus caches a backend resource that was just written and publishes its state in its
+// status.  The status only informs cluster tooling, so a failure to write it is logged rather than returned.
+func (k *CRDClientV1) updateBackendStatus(crd *v1.TridentBackend) {
+
+	k.cache.store(crd)
+
+	if crd = crd.DeepCopy(); !crd.RefreshStatus() {
+		return
+	}
+	updatedCRD, err := k.crdClient.TridentV1().TridentBackends(k.namespace).UpdateStatus(ctx(), crd, updateOpts)
+	if err == nil {
+		k.cache.store(updatedCRD)
+	}
+	logStatusUpdateError(log.WithField("backend", crd.BackendName), err)
+}
+
+// PublishBackendHealth records whether Trident can reach a backend's storage system, and the last
+// error from it, in the status of the backend's resource.  The status is only written if it changed.
+func (k *CRDClientV1) PublishBackendHealth(health *storage.BackendHealth) error {
+
+	crd, err := k.findBackendCRD(health.Name)
+	if err != nil {
+		return err
+	}
+	if crd = crd.DeepCopy(); !crd.ApplyHealth(health) {
+		return nil
+	}
+	updatedCRD, err := k.crdClient.TridentV1().TridentBackends(k.namespace).UpdateStatus(ctx(), crd, updateOpts)
+	if IsStatusNotFoundError(err) {
+		logStatusUpdateError(log.WithField("backend", crd.BackendName), err)
+		return nil
+	} else if err != nil {
+		return err
+	}
+	k.cache.store(updatedCRD)
 	return nil
 }
 
@@ -612,6 +772,7 @@ func (k *CRDClientV1) DeleteBackend(b *storage.Backend) error {
 	if err := k.crdClient.TridentV1().TridentBackends(k.namespace).Delete(ctx(), backend.Name, k.deleteOpts()); err != nil {
 		return err
 	}
+	k.cache.remove(backend)
 	log.WithFields(log.Fields{
 		"backend":  backend.BackendName,
 		"resource": backend.Name,
@@ -633,14 +794,14 @@ func (k *CRDClientV1) DeleteBackend(b *storage.Backend) error {
 func (k *CRDClientV1) GetBackends() ([]*storage.BackendPersistent, error) {
 
 	// Get the backend resources
-	backendList, err := k.crdClient.TridentV1().TridentBackends(k.namespace).List(ctx(), listOpts)
+	backends, err := k.listBackendCRDs()
 	if err != nil {
 		return nil, err
 	}
 
 	results := make([]*storage.BackendPersistent, 0)
 
-	for _, backend := range backendList.Items {
+	for _, backend := range backends {
 
 		// Convert backend resource into BackendPersistent object
 		backendPersistent, err := backend.Persistent()
@@ -679,6 +840,7 @@ func (k *CRDClientV1) DeleteBackends() error {
 			log.WithField("error", err).Error("Could not delete backend.")
 			return err
 		}
+		k.cache.remove(backend)
 		log.WithFields(log.Fields{
 			"backend":  backend.BackendName,
 			"resource": backend.Name,
@@ -748,7 +910,10 @@ func (k *CRDClientV1) ReplaceBackendAndUpdateVolumes(origBackend, newBackend *st
 	if err != nil {
 		return err
 	}
-	secret.StringData = secretMap
+	if secretMap, err = sealSecret(k.encrypter, secretName, secretMap); err != nil {
+		return err
+	}
+	setBackendSecretData(secret, secretMap)
 
 	// Update the backend resource struct
 	if err = origCRD.Apply(redactedNewBackendPersistent); err != nil {
@@ -761,6 +926,7 @@ func (k *CRDClientV1) ReplaceBackendAndUpdateVolumes(origBackend, newBackend *st
 		log.WithField("error", err).Error("Could not update backend.")
 		return err
 	}
+	k.cache.store(newCRD)
 
 	log.WithFields(log.Fields{
 		"backendName": newCRD.BackendName,
@@ -774,10 +940,12 @@ func (k *CRDClientV1) ReplaceBackendAndUpdateVolumes(origBackend, newBackend *st
 		log.WithField("secret", secretName).Error("Could not update backend secret, will unroll backend update.")
 
 		// If the secret update failed, unroll the backend update
-		_, updateErr := k.crdClient.TridentV1().TridentBackends(k.namespace).Update(ctx(), origCRDCopy, updateOpts)
+		backends := k.crdClient.TridentV1().TridentBackends(k.namespace)
+		restoredCRD, updateErr := backends.Update(ctx(), origCRDCopy, updateOpts)
 		if updateErr != nil {
 			log.WithField("backend", origCRD.Name).Error("Could not restore backend after secret update failure.")
 		} else {
+			k.cache.store(restoredCRD)
 			log.WithField("backend", origCRD.Name).Warning("Restored backend after secret update failure.")
 		}
 
@@ -785,6 +953,8 @@ func (k *CRDClientV1) ReplaceBackendAndUpdateVolumes(origBackend, newBackend *st
 	}
 	log.WithField("secret", secretName).Debug("Updated backend secret.")
 
+	k.updateBackendStatus(newCRD)
+
 	return nil
 }
 
@@ -802,11 +972,13 @@ func (k *CRDClientV1) AddVolume(volume *storage.Volume) error {
 		"persistentVolume.BackendUUID": persistentVolume.BackendUUID,
 	}).Debug("AddVolume")
 
-	_, err = k.crdClient.TridentV1().TridentVolumes(k.namespace).Create(ctx(), persistentVolume, createOpts)
+	crd, err := k.crdClient.TridentV1().TridentVolumes(k.namespace).Create(ctx(), persistentVolume, createOpts)
 	if err != nil {
 		return err
 	}
 
+	k.updateVolumeStatus(crd)
+
 	return nil
 }
 
@@ -818,16 +990,23 @@ func (k *CRDClientV1) AddVolumePersistent(volume *storage.VolumeExternal) error
 		return err
 	}
 
-	_, err = k.crdClient.TridentV1().TridentVolumes(k.namespace).Create(ctx(), persistentVolume, createOpts)
+	crd, err := k.crdClient.TridentV1().TridentVolumes(k.namespace).Create(ctx(), persistentVolume, createOpts)
 	if err != nil {
 		return err
 	}
 
+	k.updateVolumeStatus(crd)
+
 	return nil
 }
 
 func (k *CRDClientV1) HasVolumes() (bool, error) {
 
+	if k.cache != nil {
+		volumes, err := k.listVolumeCRDs()
+		return len(volumes) > 0, err
+	}
+
 	listOneOpts := metav1.ListOptions{Limit: 1}
 	volumeList, err := k.crdClient.TridentV1().TridentVolumes(k.namespace).List(ctx(), listOneOpts)
 	if err != nil {
@@ -839,7 +1018,7 @@ func (k *CRDClientV1) HasVolumes() (bool, error) {
 
 func (k *CRDClientV1) GetVolume(volName string) (*storage.VolumeExternal, error) {
 
-	volume, err := k.crdClient.TridentV1().TridentVolumes(k.namespace).Get(ctx(), v1.NameFix(volName), getOpts)
+	volume, err := k.getVolumeCRD(v1.NameFix(volName))
 
 	if err != nil {
 		return nil, err
@@ -864,11 +1043,13 @@ func (k *CRDClientV1) UpdateVolume(update *storage.Volume) error {
 		return err
 	}
 
-	_, err = k.crdClient.TridentV1().TridentVolumes(k.namespace).Update(ctx(), volume, updateOpts)
+	crd, err := k.crdClient.TridentV1().TridentVolumes(k.namespace).Update(ctx(), volume, updateOpts)
 	if err != nil {
 		return err
 	}
 
+	k.updateVolumeStatus(crd)
+
 	return nil
 }
 
@@ -883,21 +1064,59 @@ func (k *CRDClientV1) UpdateVolumePersistent(update *storage.VolumeExternal) err
 		return err
 	}
 
-	_, err = k.crdClient.TridentV1().TridentVolumes(k.namespace).Update(ctx(), volume, updateOpts)
+	crd, err := k.crdClient.TridentV1().TridentVolumes(k.namespace).Update(ctx(), volume, updateOpts)
 	if err != nil {
 		return err
 	}
 
+	k.updateVolumeStatus(crd)
+
 	return nil
 }
 
+// updateVolumeStatus caches a volume resource that was just written and publishes its state in its
+// status.  The status only informs cluster tooling, so a failure to write it is logged rather than returned.
+func (k *CRDClientV1) updateVolumeStatus(crd *v1.TridentVolume) {
+
+	k.cache.store(crd)
+
+	if crd = crd.DeepCopy(); !crd.RefreshStatus() {
+		return
+	}
+	updatedCRD, err := k.crdClient.TridentV1().TridentVolumes(k.namespace).UpdateStatus(ctx(), crd, updateOpts)
+	if err == nil {
+		k.cache.store(updatedCRD)
+	}
+	logStatusUpdateError(log.WithField("volume", crd.Name), err)
+}
+
+// logStatusUpdateError logs a failure to write the status of a resource.  A CRD created by a Trident
+// release without status subresources doesn't serve them, which isn't worth a warning each time.
+func logStatusUpdateError(logEntry *log.Entry, err error) {
+	if err == nil {
+		return
+	}
+	if IsStatusNotFoundError(err) {
+		logEntry.WithField("error", err).Debug("Could not update status, the CRD may not have a status subresource.")
+		return
+	}
+	logEntry.WithField("error", err).Warning("Could not update status.")
+}
+
 func (k *CRDClientV1) DeleteVolume(volume *storage.Volume) error {
-	return k.crdClient.TridentV1().TridentVolumes(k.namespace).Delete(ctx(), v1.NameFix(volume.Config.Name), k.deleteOpts())
+
+	name := v1.NameFix(volume.Config.Name)
+	err := k.crdClient.TridentV1().TridentVolumes(k.namespace).Delete(ctx(), name, k.deleteOpts())
+	if err == nil || errors.IsNotFound(err) {
+		k.cache.remove(&v1.TridentVolume{ObjectMeta: k.objectMeta(name)})
+	}
+
+	return err
 }
 
 func (k *CRDClientV1) DeleteVolumeIgnoreNotFound(volume *storage.Volume) error {
 
-	err := k.crdClient.TridentV1().TridentVolumes(k.namespace).Delete(ctx(), v1.NameFix(volume.Config.Name), k.deleteOpts())
+	err := k.DeleteVolume(volume)
 
 	if errors.IsNotFound(err) {
 		return nil
@@ -908,14 +1127,14 @@ func (k *CRDClientV1) DeleteVolumeIgnoreNotFound(volume *storage.Volume) error {
 
 func (k *CRDClientV1) GetVolumes() ([]*storage.VolumeExternal, error) {
 
-	volumeList, err := k.crdClient.TridentV1().TridentVolumes(k.namespace).List(ctx(), listOpts)
+	volumes, err := k.listVolumeCRDs()
 	if err != nil {
 		return nil, err
 	}
 
 	results := make([]*storage.VolumeExternal, 0)
 
-	for _, item := range volumeList.Items {
+	for _, item := range volumes {
 		if !item.ObjectMeta.DeletionTimestamp.IsZero() {
 			log.WithFields(log.Fields{
 				"Name":              item.Name,
@@ -948,6 +1167,7 @@ func (k *CRDClientV1) DeleteVolumes() error {
 		if err != nil {
 			return err
 		}
+		k.cache.remove(item)
 	}
 
 	return nil
@@ -1065,10 +1285,11 @@ func (k *CRDClientV1) AddStorageClass(sc *storageclass.StorageClass) error {
 		return err
 	}
 
-	_, err = k.crdClient.TridentV1().TridentStorageClasses(k.namespace).Create(ctx(), persistentSC, createOpts)
+	crd, err := k.crdClient.TridentV1().TridentStorageClasses(k.namespace).Create(ctx(), persistentSC, createOpts)
 	if err != nil {
 		return err
 	}
+	k.cache.store(crd)
 
 	return nil
 }
@@ -1080,16 +1301,22 @@ func (k *CRDClientV1) AddStorageClassPersistent(scp *storageclass.Persistent) er
 		return err
 	}
 
-	_, err = k.crdClient.TridentV1().TridentStorageClasses(k.namespace).Create(ctx(), persistentSC, createOpts)
+	crd, err := k.crdClient.TridentV1().TridentStorageClasses(k.namespace).Create(ctx(), persistentSC, createOpts)
 	if err != nil {
 		return err
 	}
+	k.cache.store(crd)
 
 	return nil
 }
 
 func (k *CRDClientV1) HasStorageClasses() (bool, error) {
 
+	if k.cache != nil {
+		storageClasses, err := k.listStorageClassCRDs()
+		return len(storageClasses) > 0, err
+	}
+
 	listOneOpts := metav1.ListOptions{Limit: 1}
 	scList, err := k.crdClient.TridentV1().TridentStorageClasses(k.namespace).List(ctx(), listOneOpts)
 	if err != nil {
@@ -1101,7 +1328,7 @@ func (k *CRDClientV1) HasStorageClasses() (bool, error) {
 
 func (k *CRDClientV1) GetStorageClass(scName string) (*storageclass.Persistent, error) {
 
-	sc, err := k.crdClient.TridentV1().TridentStorageClasses(k.namespace).Get(ctx(), v1.NameFix(scName), getOpts)
+	sc, err := k.getStorageClassCRD(v1.NameFix(scName))
 	if err != nil {
 		return nil, err
 	}
@@ -1116,14 +1343,14 @@ func (k *CRDClientV1) GetStorageClass(scName string) (*storageclass.Persistent,
 
 func (k *CRDClientV1) GetStorageClasses() ([]*storageclass.Persistent, error) {
 
-	scList, err := k.crdClient.TridentV1().TridentStorageClasses(k.namespace).List(ctx(), listOpts)
+	storageClasses, err := k.listStorageClassCRDs()
 	if err != nil {
 		return nil, err
 	}
 
 	results := make([]*storageclass.Persistent, 0)
 
-	for _, item := range scList.Items {
+	for _, item := range storageClasses {
 		if !item.ObjectMeta.DeletionTimestamp.IsZero() {
 			log.WithFields(log.Fields{
 				"Name":              item.Name,
@@ -1144,7 +1371,14 @@ func (k *CRDClientV1) GetStorageClasses() ([]*storageclass.Persistent, error) {
 }
 
 func (k *CRDClientV1) DeleteStorageClass(sc *storageclass.StorageClass) error {
-	return k.crdClient.TridentV1().TridentStorageClasses(k.namespace).Delete(ctx(), v1.NameFix(sc.GetName()), k.deleteOpts())
+
+	name := v1.NameFix(sc.GetName())
+	err := k.crdClient.TridentV1().TridentStorageClasses(k.namespace).Delete(ctx(), name, k.deleteOpts())
+	if err == nil || errors.IsNotFound(err) {
+		k.cache.remove(&v1.TridentStorageClass{ObjectMeta: k.objectMeta(name)})
+	}
+
+	return err
 }
 
 func (k *CRDClientV1) AddOrUpdateNode(node *utils.Node) error {
@@ -1163,10 +1397,11 @@ func (k *CRDClientV1) AddOrUpdateNode(node *utils.Node) error {
 		if err = existingNode.Apply(node); err != nil {
 			return err
 		}
-		_, err = k.crdClient.TridentV1().TridentNodes(k.namespace).Update(ctx(), existingNode, updateOpts)
+		updatedNode, err := k.crdClient.TridentV1().TridentNodes(k.namespace).Update(ctx(), existingNode, updateOpts)
 		if err != nil {
 			return err
 		}
+		k.cache.store(updatedNode)
 		return nil
 	}
 
@@ -1176,17 +1411,18 @@ func (k *CRDClientV1) AddOrUpdateNode(node *utils.Node) error {
 		return err
 	}
 
-	_, err = k.crdClient.TridentV1().TridentNodes(k.namespace).Create(ctx(), newNode, createOpts)
+	crd, err := k.crdClient.TridentV1().TridentNodes(k.namespace).Create(ctx(), newNode, createOpts)
 	if err != nil {
 		return err
 	}
+	k.cache.store(crd)
 
 	return nil
 }
 
 func (k *CRDClientV1) GetNode(nName string) (*utils.Node, error) {
 
-	node, err := k.crdClient.TridentV1().TridentNodes(k.namespace).Get(ctx(), v1.NameFix(nName), getOpts)
+	node, err := k.getNodeCRD(v1.NameFix(nName))
 	if err != nil {
 		return nil, err
 	}
@@ -1201,14 +1437,14 @@ func (k *CRDClientV1) GetNode(nName string) (*utils.Node, error) {
 
 func (k *CRDClientV1) GetNodes() ([]*utils.Node, error) {
 
-	nodeList, err := k.crdClient.TridentV1().TridentNodes(k.namespace).List(ctx(), listOpts)
+	nodes, err := k.listNodeCRDs()
 	if err != nil {
 		return nil, err
 	}
 
 	results := make([]*utils.Node, 0)
 
-	for _, item := range nodeList.Items {
+	for _, item := range nodes {
 		if !item.ObjectMeta.DeletionTimestamp.IsZero() {
 			log.WithFields(log.Fields{
 				"Name":              item.Name,
@@ -1229,7 +1465,14 @@ func (k *CRDClientV1) GetNodes() ([]*utils.Node, error) {
 }
 
 func (k *CRDClientV1) DeleteNode(n *utils.Node) error {
-	return k.crdClient.TridentV1().TridentNodes(k.namespace).Delete(ctx(), v1.NameFix(n.Name), k.deleteOpts())
+
+	name := v1.NameFix(n.Name)
+	err := k.crdClient.TridentV1().TridentNodes(k.namespace).Delete(ctx(), name, k.deleteOpts())
+	if err == nil || errors.IsNotFound(err) {
+		k.cache.remove(&v1.TridentNode{ObjectMeta: k.objectMeta(name)})
+	}
+
+	return err
 }
 
 // deleteOpts returns a DeleteOptions struct suitable for most DELETE calls to the K8S REST API.
@@ -1248,10 +1491,11 @@ func (k *CRDClientV1) AddSnapshot(snapshot *storage.Snapshot) error {
 		return err
 	}
 
-	_, err = k.crdClient.TridentV1().TridentSnapshots(k.namespace).Create(ctx(), persistentSnapshot, createOpts)
+	crd, err := k.crdClient.TridentV1().TridentSnapshots(k.namespace).Create(ctx(), persistentSnapshot, createOpts)
 	if err != nil {
 		return err
 	}
+	k.cache.store(crd)
 
 	return nil
 }
@@ -1259,7 +1503,7 @@ func (k *CRDClientV1) AddSnapshot(snapshot *storage.Snapshot) error {
 func (k *CRDClientV1) GetSnapshot(volumeName, snapshotName string) (*storage.SnapshotPersistent, error) {
 
 	snapshotID := storage.MakeSnapshotID(volumeName, snapshotName)
-	snapshot, err := k.crdClient.TridentV1().TridentSnapshots(k.namespace).Get(ctx(), v1.NameFix(snapshotID), getOpts)
+	snapshot, err := k.getSnapshotCRD(v1.NameFix(snapshotID))
 	if err != nil {
 		return nil, err
 	}
@@ -1274,14 +1518,14 @@ func (k *CRDClientV1) GetSnapshot(volumeName, snapshotName string) (*storage.Sna
 
 func (k *CRDClientV1) GetSnapshots() ([]*storage.SnapshotPersistent, error) {
 
-	snapshotList, err := k.crdClient.TridentV1().TridentSnapshots(k.namespace).List(ctx(), listOpts)
+	snapshots, err := k.listSnapshotCRDs()
 	if err != nil {
 		return nil, err
 	}
 
 	results := make([]*storage.SnapshotPersistent, 0)
 
-	for _, item := range snapshotList.Items {
+	for _, item := range snapshots {
 		if !item.ObjectMeta.DeletionTimestamp.IsZero() {
 			log.WithFields(log.Fields{
 				"Name":              item.Name,
@@ -1302,12 +1546,19 @@ func (k *CRDClientV1) GetSnapshots() ([]*storage.SnapshotPersistent, error) {
 }
 
 func (k *CRDClientV1) DeleteSnapshot(snapshot *storage.Snapshot) error {
-	return k.crdClient.TridentV1().TridentSnapshots(k.namespace).Delete(ctx(), v1.NameFix(snapshot.ID()), k.deleteOpts())
+
+	name := v1.NameFix(snapshot.ID())
+	err := k.crdClient.TridentV1().TridentSnapshots(k.namespace).Delete(ctx(), name, k.deleteOpts())
+	if err == nil || errors.IsNotFound(err) {
+		k.cache.remove(&v1.TridentSnapshot{ObjectMeta: k.objectMeta(name)})
+	}
+
+	return err
 }
 
 func (k *CRDClientV1) DeleteSnapshotIgnoreNotFound(snapshot *storage.Snapshot) error {
 
-	err := k.crdClient.TridentV1().TridentSnapshots(k.namespace).Delete(ctx(), v1.NameFix(snapshot.ID()), k.deleteOpts())
+	err := k.DeleteSnapshot(snapshot)
 
 	if errors.IsNotFound(err) {
 		return nil
@@ -1328,6 +1579,7 @@ func (k *CRDClientV1) DeleteSnapshots() error {
 		if err != nil {
 			return err
 		}
+		k.cache.remove(item)
 	}
 
 	return nil
@@ -397,6 +397,87 @@ func TestKubernetesDuplicateBackend(t *testing.T) {
 	}
 }
 
+func TestKubernetesBackendStatus(t *testing.T) {
+	p, _ := GetTestKubernetesClient()
+
+	NFSServerConfig := drivers.OntapStorageDriverConfig{
+		CommonStorageDriverConfig: &drivers.CommonStorageDriverConfig{
+			StorageDriverName: drivers.OntapNASStorageDriverName,
+		},
+		ManagementLIF: "10.0.0.4",
+		DataLIF:       "10.0.0.100",
+		SVM:           "svm1",
+		Username:      "admin",
+		Password:      "netapp",
+	}
+	NFSServer := &storage.Backend{
+		Driver: &ontap.NASStorageDriver{
+			Config: NFSServerConfig,
+		},
+		Name:  "nfs-server-1-" + NFSServerConfig.ManagementLIF,
+		State: storage.Online,
+	}
+	NFSServer.BackendUUID = uuid.New().String()
+
+	if err := p.AddBackend(NFSServer); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	backendCRD, err := p.getBackendCRD(NFSServer.Name)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if backendCRD.Status.State != string(storage.Online) {
+		t.Errorf("Incorrect backend status state; expected %s, got %s", storage.Online, backendCRD.Status.State)
+	}
+	if ready := backendCRD.Status.GetCondition(v1.ConditionReady); ready == nil || ready.Status != "True" {
+		t.Errorf("Backend status should have a true Ready condition; got %v", ready)
+	}
+
+	health := &storage.BackendHealth{
+		Name:        NFSServer.Name,
+		Reachable:   false,
+		AuthStatus:  storage.AuthStatusOK,
+		LastFailure: "2020-01-02T03:04:05Z",
+		LastError:   "connection refused",
+	}
+	if err = p.PublishBackendHealth(health); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	backendCRD, err = p.getBackendCRD(NFSServer.Name)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if backendCRD.Status.LastError != health.LastError {
+		t.Errorf("Incorrect backend last error; expected %s, got %s", health.LastError, backendCRD.Status.LastError)
+	}
+	if reachable := backendCRD.Status.GetCondition(v1.ConditionReachable); reachable == nil ||
+		reachable.Status != "False" {
+		t.Errorf("Backend status should have a false Reachable condition; got %v", reachable)
+	}
+
+	// Updating the backend keeps its health
+	NFSServer.State = storage.Offline
+	if err = p.UpdateBackend(NFSServer); err != nil {
+		t.Fatal(err.Error())
+	}
+	backendCRD, err = p.getBackendCRD(NFSServer.Name)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if ready := backendCRD.Status.GetCondition(v1.ConditionReady); ready == nil || ready.Status != "False" {
+		t.Errorf("Backend status should have a false Ready condition; got %v", ready)
+	}
+	if backendCRD.Status.LastError != health.LastError {
+		t.Errorf("Backend update should keep the last error; got %s", backendCRD.Status.LastError)
+	}
+
+	if err = p.DeleteBackend(NFSServer); err != nil {
+		t.Error(err.Error())
+	}
+}
+
 func TestKubernetesVolume(t *testing.T) {
 	p, _ := GetTestKubernetesClient()
 
@@ -0,0 +1,264 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package persistentstore
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+/////////////////////////////////////////////////////////////////////////////
+//
+// This file contains the envelope encryption of backend secrets.  Each
+// secret's values are encrypted with a random data key, and the data key is
+// wrapped by a key provider, either a KMS plugin or a key kept in a sealed
+// Kubernetes secret mounted into the Trident pod.  The wrapped data key is
+// stored with the values it encrypts, so a secret may be decrypted after the
+// provider's active key changes, for as long as the provider still has the
+// key that wrapped it.
+//
+/////////////////////////////////////////////////////////////////////////////
+
+const (
+	envelopeKeyPrefix   = "envelope."
+	envelopeProviderKey = envelopeKeyPrefix + "provider"
+	envelopeKeyIDKey    = envelopeKeyPrefix + "keyID"
+	envelopeDataKeyKey  = envelopeKeyPrefix + "dataKey"
+
+	dataKeySize          = 32
+	keyEncryptionKeySize = 32
+)
+
+// KeyProvider wraps the data keys that encrypt backend secrets with a key encryption key that
+// never leaves the provider.
+type KeyProvider interface {
+	// Name identifies the provider, so that a secret is never given to another provider to decrypt
+	Name() string
+	// WrapKey encrypts a data key with the provider's active key, and returns the ID of that key
+	WrapKey(dataKey []byte) (keyID string, wrappedKey []byte, err error)
+	// UnwrapKey decrypts a data key wrapped by the key with the specified ID
+	UnwrapKey(keyID string, wrappedKey []byte) ([]byte, error)
+}
+
+// SecretEncrypter encrypts the values of backend secrets before they are written to Kubernetes,
+// and decrypts them when they are read.
+type SecretEncrypter struct {
+	provider KeyProvider
+}
+
+func NewSecretEncrypter(provider KeyProvider) *SecretEncrypter {
+	return &SecretEncrypter{provider: provider}
+}
+
+// IsEncrypted returns true if a secret's data was encrypted by a SecretEncrypter.
+func IsEncrypted(data map[string]string) bool {
+	_, ok := data[envelopeDataKeyKey]
+	return ok
+}
+
+// Seal encrypts the values of a secret.  The secret's name is bound to each value, so a value
+// can't be decrypted if it is copied to another secret or another key of the same secret.
+func (e *SecretEncrypter) Seal(secretName string, data map[string]string) (map[string]string, error) {
+
+	dataKey := make([]byte, dataKeySize)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return nil, fmt.Errorf("could not create data key; %v", err)
+	}
+
+	keyID, wrappedKey, err := e.provider.WrapKey(dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("could not wrap data key with %s key provider; %v", e.provider.Name(), err)
+	}
+
+	sealed := map[string]string{
+		envelopeProviderKey: e.provider.Name(),
+		envelopeKeyIDKey:    keyID,
+		envelopeDataKeyKey:  base64.StdEncoding.EncodeToString(wrappedKey),
+	}
+	for key, value := range data {
+		if strings.HasPrefix(key, envelopeKeyPrefix) {
+			return nil, fmt.Errorf("secret key %s is reserved", key)
+		}
+		ciphertext, err := encryptAESGCM(dataKey, []byte(value), []byte(secretName+"/"+key))
+		if err != nil {
+			return nil, err
+		}
+		sealed[key] = base64.StdEncoding.EncodeToString(ciphertext)
+	}
+
+	return sealed, nil
+}
+
+// Open decrypts the values of a secret sealed by Seal.  Secrets written before encryption was
+// enabled are returned unchanged, so they remain readable until they are re-encrypted.  Open may be
+// called on a nil SecretEncrypter, which fails only if the secret is encrypted.
+func (e *SecretEncrypter) Open(secretName string, data map[string]string) (map[string]string, error) {
+
+	if !IsEncrypted(data) {
+		return data, nil
+	}
+	if e == nil {
+		return nil, fmt.Errorf("secret %s is encrypted, but no key provider is configured", secretName)
+	}
+	if providerName := data[envelopeProviderKey]; providerName != e.provider.Name() {
+		return nil, fmt.Errorf("secret %s was encrypted by the %s key provider, not the %s key provider",
+			secretName, providerName, e.provider.Name())
+	}
+
+	wrappedKey, err := base64.StdEncoding.DecodeString(data[envelopeDataKeyKey])
+	if err != nil {
+		return nil, fmt.Errorf("could not decode data key of secret %s; %v", secretName, err)
+	}
+	dataKey, err := e.provider.UnwrapKey(data[envelopeKeyIDKey], wrappedKey)
+	if err != nil {
+		return nil, fmt.Errorf("could not unwrap data key of secret %s with key %s; %v", secretName,
+			data[envelopeKeyIDKey], err)
+	}
+
+	opened := make(map[string]string)
+	for key, value := range data {
+		if strings.HasPrefix(key, envelopeKeyPrefix) {
+			continue
+		}
+		ciphertext, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return nil, fmt.Errorf("could not decode key %s of secret %s; %v", key, secretName, err)
+		}
+		plaintext, err := decryptAESGCM(dataKey, ciphertext, []byte(secretName+"/"+key))
+		if err != nil {
+			return nil, fmt.Errorf("could not decrypt key %s of secret %s; %v", key, secretName, err)
+		}
+		opened[key] = string(plaintext)
+	}
+
+	return opened, nil
+}
+
+// sealSecret encrypts a secret's data if an encrypter is configured.
+func sealSecret(e *SecretEncrypter, secretName string, data map[string]string) (map[string]string, error) {
+	if e == nil {
+		return data, nil
+	}
+	return e.Seal(secretName, data)
+}
+
+// encryptAESGCM encrypts plaintext with AES-GCM, returning the nonce followed by the ciphertext.
+func encryptAESGCM(key, plaintext, additionalData []byte) ([]byte, error) {
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	return gcm.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+// decryptAESGCM decrypts the output of encryptAESGCM.
+func decryptAESGCM(key, ciphertext, additionalData []byte) ([]byte, error) {
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ciphertext) < gcm.NonceSize() {
+		return nil, errors.New("ciphertext is too short")
+	}
+	nonce, ciphertext := ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():]
+
+	return gcm.Open(nil, nonce, ciphertext, additionalData)
+}
+
+// SealedKeyProvider wraps data keys with AES-256 keys read from a directory, normally a Kubernetes
+// secret mounted into the Trident pod.  Each file in the directory holds one base64-encoded key and
+// is named by the key's ID.  The files are read on every use, so a key may be rotated by adding a
+// new key to the secret and making it the active key, while older keys still unwrap the data keys
+// of secrets that haven't been re-encrypted.
+type SealedKeyProvider struct {
+	keyDir      string
+	activeKeyID string
+}
+
+const SealedKeyProviderName = "secret"
+
+func NewSealedKeyProvider(keyDir, activeKeyID string) (*SealedKeyProvider, error) {
+
+	p := &SealedKeyProvider{keyDir: keyDir, activeKeyID: activeKeyID}
+	if activeKeyID == "" {
+		return nil, errors.New("the active encryption key is not specified")
+	}
+	if _, err := p.readKey(activeKeyID); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *SealedKeyProvider) Name() string {
+	return SealedKeyProviderName
+}
+
+func (p *SealedKeyProvider) WrapKey(dataKey []byte) (string, []byte, error) {
+
+	key, err := p.readKey(p.activeKeyID)
+	if err != nil {
+		return "", nil, err
+	}
+	wrappedKey, err := encryptAESGCM(key, dataKey, []byte(p.activeKeyID))
+	if err != nil {
+		return "", nil, err
+	}
+	return p.activeKeyID, wrappedKey, nil
+}
+
+func (p *SealedKeyProvider) UnwrapKey(keyID string, wrappedKey []byte) ([]byte, error) {
+
+	key, err := p.readKey(keyID)
+	if err != nil {
+		return nil, err
+	}
+	return decryptAESGCM(key, wrappedKey, []byte(keyID))
+}
+
+// readKey reads and decodes the key with the specified ID.
+func (p *SealedKeyProvider) readKey(keyID string) ([]byte, error) {
+
+	// Key IDs come from the secrets being decrypted, so they mustn't name files outside the directory
+	if keyID == "" || strings.ContainsAny(keyID, `/\`) || strings.HasPrefix(keyID, ".") {
+		return nil, fmt.Errorf("invalid encryption key ID '%s'", keyID)
+	}
+
+	encodedKey, err := ioutil.ReadFile(filepath.Join(p.keyDir, keyID))
+	if err != nil {
+		return nil, fmt.Errorf("could not read encryption key %s; %v", keyID, err)
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(encodedKey)))
+	if err != nil {
+		return nil, fmt.Errorf("could not decode encryption key %s; %v", keyID, err)
+	}
+	if len(key) != keyEncryptionKeySize {
+		return nil, fmt.Errorf("encryption key %s must be %d bytes, not %d", keyID, keyEncryptionKeySize,
+			len(key))
+	}
+	return key, nil
+}
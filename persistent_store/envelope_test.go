@@ -0,0 +1,187 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package persistentstore
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netapp/trident/storage"
+	drivers "github.com/netapp/trident/storage_drivers"
+	"github.com/netapp/trident/storage_drivers/ontap"
+)
+
+// writeTestKey writes a key encryption key, derived from its ID, to a directory.
+func writeTestKey(t *testing.T, keyDir, keyID string) {
+	key := make([]byte, keyEncryptionKeySize)
+	for i := range key {
+		key[i] = byte(len(keyID) + i)
+	}
+	encodedKey := base64.StdEncoding.EncodeToString(key)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(keyDir, keyID), []byte(encodedKey+"\n"), 0600))
+}
+
+func newTestSealedKeyProvider(t *testing.T, activeKeyID string) (*SealedKeyProvider, string) {
+	keyDir, err := ioutil.TempDir("", "trident-keys")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(keyDir) })
+
+	writeTestKey(t, keyDir, activeKeyID)
+	provider, err := NewSealedKeyProvider(keyDir, activeKeyID)
+	require.NoError(t, err)
+	return provider, keyDir
+}
+
+func TestSecretEncrypterRoundTrip(t *testing.T) {
+
+	provider, _ := newTestSealedKeyProvider(t, "key1")
+	encrypter := NewSecretEncrypter(provider)
+	data := map[string]string{"Username": "admin", "Password": "netapp"}
+
+	sealed, err := encrypter.Seal("tbe-1", data)
+	require.NoError(t, err)
+	assert.True(t, IsEncrypted(sealed))
+	assert.Equal(t, "key1", sealed[envelopeKeyIDKey])
+	assert.NotEqual(t, "netapp", sealed["Password"])
+
+	opened, err := encrypter.Open("tbe-1", sealed)
+	require.NoError(t, err)
+	assert.Equal(t, data, opened)
+
+	// Values are bound to their secret and key
+	_, err = encrypter.Open("tbe-2", sealed)
+	assert.Error(t, err)
+	sealed["Username"], sealed["Password"] = sealed["Password"], sealed["Username"]
+	_, err = encrypter.Open("tbe-1", sealed)
+	assert.Error(t, err)
+
+	// Reserved keys can't be sealed
+	_, err = encrypter.Seal("tbe-1", map[string]string{envelopeKeyIDKey: "x"})
+	assert.Error(t, err)
+}
+
+func TestSecretEncrypterPlaintext(t *testing.T) {
+
+	data := map[string]string{"Username": "admin", "Password": "netapp"}
+
+	// Secrets written before encryption was enabled are read unchanged
+	provider, _ := newTestSealedKeyProvider(t, "key1")
+	opened, err := NewSecretEncrypter(provider).Open("tbe-1", data)
+	require.NoError(t, err)
+	assert.Equal(t, data, opened)
+
+	var encrypter *SecretEncrypter
+	opened, err = encrypter.Open("tbe-1", data)
+	require.NoError(t, err)
+	assert.Equal(t, data, opened)
+
+	// Encrypted secrets can't be read once encryption is disabled
+	sealed, err := NewSecretEncrypter(provider).Seal("tbe-1", data)
+	require.NoError(t, err)
+	_, err = encrypter.Open("tbe-1", sealed)
+	assert.Error(t, err)
+}
+
+func TestSealedKeyProviderRotation(t *testing.T) {
+
+	provider, keyDir := newTestSealedKeyProvider(t, "key1")
+	data := map[string]string{"Password": "netapp"}
+	sealed, err := NewSecretEncrypter(provider).Seal("tbe-1", data)
+	require.NoError(t, err)
+
+	// After rotation, secrets sealed with the old key are still read
+	writeTestKey(t, keyDir, "key2")
+	rotatedProvider, err := NewSealedKeyProvider(keyDir, "key2")
+	require.NoError(t, err)
+	rotatedEncrypter := NewSecretEncrypter(rotatedProvider)
+
+	opened, err := rotatedEncrypter.Open("tbe-1", sealed)
+	require.NoError(t, err)
+	assert.Equal(t, data, opened)
+
+	resealed, err := rotatedEncrypter.Seal("tbe-1", opened)
+	require.NoError(t, err)
+	assert.Equal(t, "key2", resealed[envelopeKeyIDKey])
+
+	// Once the old key is retired, only re-encrypted secrets can be read
+	require.NoError(t, os.Remove(filepath.Join(keyDir, "key1")))
+	_, err = rotatedEncrypter.Open("tbe-1", sealed)
+	assert.Error(t, err)
+	_, err = rotatedEncrypter.Open("tbe-1", resealed)
+	assert.NoError(t, err)
+
+	// Key IDs may not name files outside the key directory
+	_, err = rotatedProvider.UnwrapKey("../key2", nil)
+	assert.Error(t, err)
+
+	_, err = NewSealedKeyProvider(keyDir, "missing")
+	assert.Error(t, err)
+}
+
+func TestKubernetesBackendSecretEncryption(t *testing.T) {
+
+	p, k8sClient := GetTestKubernetesClient()
+
+	// Add a backend before encryption is enabled
+	backend := &storage.Backend{
+		Driver: &ontap.NASStorageDriver{
+			Config: drivers.OntapStorageDriverConfig{
+				CommonStorageDriverConfig: &drivers.CommonStorageDriverConfig{
+					StorageDriverName: drivers.OntapNASStorageDriverName,
+				},
+				ManagementLIF: "10.0.0.4",
+				SVM:           "svm1",
+				Username:      "admin",
+				Password:      "netapp",
+			},
+		},
+		Name:        "nfs",
+		BackendUUID: uuid.New().String(),
+	}
+	require.NoError(t, p.AddBackend(backend))
+	secretName := p.backendSecretName(backend.BackendUUID)
+
+	checkPassword := func() {
+		recoveredBackend, err := p.GetBackend(backend.Name)
+		require.NoError(t, err)
+		configJSON, err := recoveredBackend.MarshalConfig()
+		require.NoError(t, err)
+		var ontapConfig drivers.OntapStorageDriverConfig
+		require.NoError(t, json.Unmarshal([]byte(configJSON), &ontapConfig))
+		assert.Equal(t, "netapp", ontapConfig.Password)
+	}
+
+	_, err := p.ReencryptBackendSecrets()
+	assert.Error(t, err, "re-encryption should fail without a key provider")
+
+	// Existing secrets are still read after encryption is enabled
+	provider, _ := newTestSealedKeyProvider(t, "key1")
+	p.SetSecretEncrypter(NewSecretEncrypter(provider))
+	checkPassword()
+
+	backendNames, err := p.ReencryptBackendSecrets()
+	require.NoError(t, err)
+	assert.Equal(t, []string{backend.Name}, backendNames)
+
+	secret, err := k8sClient.GetSecret(secretName)
+	require.NoError(t, err)
+	secretMap := backendSecretData(secret)
+	assert.True(t, IsEncrypted(secretMap))
+	assert.NotEqual(t, "netapp", secretMap["Password"])
+	checkPassword()
+
+	// Updated backends are encrypted too
+	require.NoError(t, p.UpdateBackend(backend))
+	secret, err = k8sClient.GetSecret(secretName)
+	require.NoError(t, err)
+	assert.True(t, IsEncrypted(backendSecretData(secret)))
+	checkPassword()
+}
@@ -0,0 +1,158 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package persistentstore
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+)
+
+const (
+	KMSKeyProviderName = "kms"
+
+	kmsAPIVersion = "v1beta1"
+	kmsService    = "/v1beta1.KeyManagementService/"
+	kmsTimeout    = 10 * time.Second
+)
+
+// KMSKeyProvider wraps data keys with a KMS plugin that implements the Kubernetes KMS v1beta1 API, the
+// same plugins that Kubernetes uses to encrypt secrets at rest.  The plugin doesn't identify the key
+// it used, so the plugin's name is recorded as the key ID, and the plugin is expected to decrypt
+// anything encrypted by its earlier keys.
+type KMSKeyProvider struct {
+	name string
+	conn *grpc.ClientConn
+}
+
+// NewKMSKeyProvider connects to a KMS plugin listening on a unix socket, and checks that it
+// implements a supported version of the KMS API.
+func NewKMSKeyProvider(name, socketPath string) (*KMSKeyProvider, error) {
+
+	dial := func(ctx context.Context, address string) (net.Conn, error) {
+		return (&net.Dialer{}).DialContext(ctx, "unix", address)
+	}
+	conn, err := grpc.Dial(socketPath, grpc.WithInsecure(), grpc.WithContextDialer(dial))
+	if err != nil {
+		return nil, fmt.Errorf("could not connect to KMS plugin at %s; %v", socketPath, err)
+	}
+
+	p := &KMSKeyProvider{name: name, conn: conn}
+
+	response := &kmsVersionResponse{}
+	if err = p.invoke("Version", &kmsVersionRequest{Version: kmsAPIVersion}, response); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	if response.Version != kmsAPIVersion {
+		_ = conn.Close()
+		return nil, fmt.Errorf("KMS plugin at %s implements API version %s, not %s", socketPath,
+			response.Version, kmsAPIVersion)
+	}
+
+	log.WithFields(log.Fields{
+		"socket":         socketPath,
+		"runtimeName":    response.RuntimeName,
+		"runtimeVersion": response.RuntimeVersion,
+	}).Info("Connected to KMS plugin.")
+
+	return p, nil
+}
+
+func (p *KMSKeyProvider) Name() string {
+	return KMSKeyProviderName
+}
+
+func (p *KMSKeyProvider) WrapKey(dataKey []byte) (string, []byte, error) {
+
+	response := &kmsEncryptResponse{}
+	if err := p.invoke("Encrypt", &kmsEncryptRequest{Version: kmsAPIVersion, Plain: dataKey}, response); err != nil {
+		return "", nil, err
+	}
+	return p.name, response.Cipher, nil
+}
+
+func (p *KMSKeyProvider) UnwrapKey(keyID string, wrappedKey []byte) ([]byte, error) {
+
+	if keyID != p.name {
+		return nil, fmt.Errorf("key %s does not belong to KMS plugin %s", keyID, p.name)
+	}
+
+	response := &kmsDecryptResponse{}
+	if err := p.invoke("Decrypt", &kmsDecryptRequest{Version: kmsAPIVersion, Cipher: wrappedKey},
+		response); err != nil {
+		return nil, err
+	}
+	return response.Plain, nil
+}
+
+func (p *KMSKeyProvider) invoke(method string, request, response proto.Message) error {
+
+	ctx, cancel := context.WithTimeout(context.Background(), kmsTimeout)
+	defer cancel()
+
+	if err := p.conn.Invoke(ctx, kmsService+method, request, response); err != nil {
+		return fmt.Errorf("KMS plugin %s failed; %v", method, err)
+	}
+	return nil
+}
+
+// The KMS v1beta1 request and response messages.  They match the field numbers of the Kubernetes
+// KMS API definitions, so they may be sent with the standard protobuf codec.
+
+type kmsVersionRequest struct {
+	Version string `protobuf:"bytes,1,opt,name=version,proto3"`
+}
+
+type kmsVersionResponse struct {
+	Version        string `protobuf:"bytes,1,opt,name=version,proto3"`
+	RuntimeName    string `protobuf:"bytes,2,opt,name=runtime_name,json=runtimeName,proto3"`
+	RuntimeVersion string `protobuf:"bytes,3,opt,name=runtime_version,json=runtimeVersion,proto3"`
+}
+
+type kmsEncryptRequest struct {
+	Version string `protobuf:"bytes,1,opt,name=version,proto3"`
+	Plain   []byte `protobuf:"bytes,2,opt,name=plain,proto3"`
+}
+
+type kmsEncryptResponse struct {
+	Cipher []byte `protobuf:"bytes,1,opt,name=cipher,proto3"`
+}
+
+type kmsDecryptRequest struct {
+	Version string `protobuf:"bytes,1,opt,name=version,proto3"`
+	Cipher  []byte `protobuf:"bytes,2,opt,name=cipher,proto3"`
+}
+
+type kmsDecryptResponse struct {
+	Plain []byte `protobuf:"bytes,1,opt,name=plain,proto3"`
+}
+
+func (m *kmsVersionRequest) Reset()         { *m = kmsVersionRequest{} }
+func (m *kmsVersionRequest) String() string { return proto.CompactTextString(m) }
+func (*kmsVersionRequest) ProtoMessage()    {}
+
+func (m *kmsVersionResponse) Reset()         { *m = kmsVersionResponse{} }
+func (m *kmsVersionResponse) String() string { return proto.CompactTextString(m) }
+func (*kmsVersionResponse) ProtoMessage()    {}
+
+func (m *kmsEncryptRequest) Reset()         { *m = kmsEncryptRequest{} }
+func (m *kmsEncryptRequest) String() string { return proto.CompactTextString(m) }
+func (*kmsEncryptRequest) ProtoMessage()    {}
+
+func (m *kmsEncryptResponse) Reset()         { *m = kmsEncryptResponse{} }
+func (m *kmsEncryptResponse) String() string { return proto.CompactTextString(m) }
+func (*kmsEncryptResponse) ProtoMessage()    {}
+
+func (m *kmsDecryptRequest) Reset()         { *m = kmsDecryptRequest{} }
+func (m *kmsDecryptRequest) String() string { return proto.CompactTextString(m) }
+func (*kmsDecryptRequest) ProtoMessage()    {}
+
+func (m *kmsDecryptResponse) Reset()         { *m = kmsDecryptResponse{} }
+func (m *kmsDecryptResponse) String() string { return proto.CompactTextString(m) }
+func (*kmsDecryptResponse) ProtoMessage()    {}
@@ -556,7 +556,7 @@ func TestPassthroughClient_GetVolumes(t *testing.T) {
 		InternalName: "fake_volume_1",
 		Size:         "1000000000",
 	}
-	err := fakeBackend.Driver.Create(volConfig, fakeBackend.Storage["pool-0"], make(map[string]sa.Request))
+	err := fakeBackend.Driver.Create(ctx(), volConfig, fakeBackend.Storage["pool-0"], make(map[string]sa.Request))
 	if err != nil {
 		t.Error(err)
 	}
@@ -566,7 +566,7 @@ func TestPassthroughClient_GetVolumes(t *testing.T) {
 		InternalName: "fake_volume_2",
 		Size:         "2000000000",
 	}
-	err = fakeBackend.Driver.Create(volConfig, fakeBackend.Storage["pool-0"], make(map[string]sa.Request))
+	err = fakeBackend.Driver.Create(ctx(), volConfig, fakeBackend.Storage["pool-0"], make(map[string]sa.Request))
 	if err != nil {
 		t.Error(err)
 	}
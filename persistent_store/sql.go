@@ -0,0 +1,764 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package persistentstore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	_ "github.com/lib/pq" // registers the "postgres" database/sql driver
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/storage"
+	storageclass "github.com/netapp/trident/storage_class"
+	"github.com/netapp/trident/utils"
+)
+
+/////////////////////////////////////////////////////////////////////////////
+//
+// This file contains a persistent store client that keeps Trident's state
+// in a PostgreSQL database, for Docker and other deployments outside of
+// Kubernetes that would otherwise need an etcd cluster.  Each kind of
+// object has its own table of JSON documents keyed like the etcd stores,
+// and writes that change several objects are made in one transaction.  The
+// database/sql driver is chosen by name; Trident is built with lib/pq, which
+// registers itself as the default "postgres" driver.
+//
+/////////////////////////////////////////////////////////////////////////////
+
+// Compile time check to ensure SQLClient implements TransactionalClient
+var _ TransactionalClient = &SQLClient{}
+
+const DefaultSQLDriver = "postgres"
+
+// sqlTable is a table of JSON documents, each holding one object keyed by name.
+type sqlTable string
+
+const (
+	sqlVersionTable      sqlTable = "trident_version"
+	sqlBackendTable      sqlTable = "trident_backends"
+	sqlVolumeTable       sqlTable = "trident_volumes"
+	sqlTransactionTable  sqlTable = "trident_volume_transactions"
+	sqlStorageClassTable sqlTable = "trident_storage_classes"
+	sqlNodeTable         sqlTable = "trident_nodes"
+	sqlSnapshotTable     sqlTable = "trident_snapshots"
+)
+
+const (
+	// sqlSchemaVersionTable records the schema migrations applied to the database
+	sqlSchemaVersionTable = "trident_schema_migrations"
+
+	// sqlVersionKey is the key of the only row in the version table
+	sqlVersionKey = "version"
+)
+
+// sqlMigration is one change to the database schema.  Migrations are applied in order of their
+// versions, together in one transaction, and are never changed once released; the schema is changed
+// by adding migrations.
+type sqlMigration struct {
+	version    int
+	statements []string
+}
+
+var sqlMigrations = []sqlMigration{
+	{
+		version: 1,
+		statements: []string{
+			sqlCreateTable(sqlVersionTable),
+			sqlCreateTable(sqlBackendTable),
+			sqlCreateTable(sqlVolumeTable),
+			sqlCreateTable(sqlTransactionTable),
+			sqlCreateTable(sqlStorageClassTable),
+			sqlCreateTable(sqlNodeTable),
+			sqlCreateTable(sqlSnapshotTable),
+		},
+	},
+}
+
+func sqlCreateTable(table sqlTable) string {
+	return fmt.Sprintf("CREATE TABLE %s (name TEXT PRIMARY KEY, document JSONB NOT NULL, "+
+		"updated TIMESTAMPTZ NOT NULL DEFAULT now())", table)
+}
+
+// sqlQuerier is satisfied by both a database and a transaction, so objects may be written either
+// on their own or together with others.
+type sqlQuerier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// SQLClient stores persistent state in a PostgreSQL database.  A client made by Atomically writes
+// in that call's transaction instead.
+type SQLClient struct {
+	db *sql.DB
+	tx sqlQuerier
+}
+
+// NewSQLClient connects to a database and brings its schema up to date.
+func NewSQLClient(driverName, dataSourceName string) (*SQLClient, error) {
+
+	log.WithField("driver", driverName).Debug("Creating SQL persistent store client.")
+
+	db, err := sql.Open(driverName, dataSourceName)
+	if err != nil {
+		return nil, fmt.Errorf("could not open %s database; %v", driverName, err)
+	}
+
+	p := &SQLClient{db: db}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.PersistentStoreBootstrapTimeout)
+	defer cancel()
+
+	if err = db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("could not connect to %s database; %v", driverName, err)
+	}
+	if err = p.migrateSchema(ctx); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// migrateSchema applies the schema migrations the database hasn't had yet.  The migration table is
+// locked while migrating, so that Trident instances started together don't both migrate the schema.
+func (p *SQLClient) migrateSchema(ctx context.Context) error {
+
+	createVersionTable := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (version INTEGER PRIMARY KEY, "+
+		"applied TIMESTAMPTZ NOT NULL DEFAULT now())", sqlSchemaVersionTable)
+	if _, err := p.db.ExecContext(ctx, createVersionTable); err != nil {
+		return fmt.Errorf("could not create schema migration table; %v", err)
+	}
+
+	return p.transact(ctx, func(tx sqlQuerier) error {
+
+		lock := fmt.Sprintf("LOCK TABLE %s IN EXCLUSIVE MODE", sqlSchemaVersionTable)
+		if _, err := tx.ExecContext(ctx, lock); err != nil {
+			return fmt.Errorf("could not lock schema migration table; %v", err)
+		}
+
+		var schemaVersion int
+		query := fmt.Sprintf("SELECT COALESCE(MAX(version), 0) FROM %s", sqlSchemaVersionTable)
+		if err := tx.QueryRowContext(ctx, query).Scan(&schemaVersion); err != nil {
+			return fmt.Errorf("could not read schema version; %v", err)
+		}
+
+		latestVersion := sqlMigrations[len(sqlMigrations)-1].version
+		if schemaVersion > latestVersion {
+			return fmt.Errorf("database schema version %d is newer than this version of %s supports (%d)",
+				schemaVersion, config.OrchestratorName, latestVersion)
+		}
+
+		for _, migration := range pendingSQLMigrations(schemaVersion) {
+			for _, statement := range migration.statements {
+				if _, err := tx.ExecContext(ctx, statement); err != nil {
+					return fmt.Errorf("could not migrate schema to version %d; %v", migration.version, err)
+				}
+			}
+			insert := fmt.Sprintf("INSERT INTO %s (version) VALUES ($1)", sqlSchemaVersionTable)
+			if _, err := tx.ExecContext(ctx, insert, migration.version); err != nil {
+				return fmt.Errorf("could not record schema version %d; %v", migration.version, err)
+			}
+			log.WithField("version", migration.version).Info("Migrated database schema.")
+		}
+
+		return nil
+	})
+}
+
+// pendingSQLMigrations returns the migrations newer than a schema version, in order.
+func pendingSQLMigrations(schemaVersion int) []sqlMigration {
+	pending := make([]sqlMigration, 0)
+	for _, migration := range sqlMigrations {
+		if migration.version > schemaVersion {
+			pending = append(pending, migration)
+		}
+	}
+	return pending
+}
+
+// querier returns the transaction the client writes in, if any, or else the database.
+func (p *SQLClient) querier() sqlQuerier {
+	if p.tx != nil {
+		return p.tx
+	}
+	return p.db
+}
+
+// transact runs a function in a transaction, which is committed if the function succeeds and rolled
+// back otherwise.  A client that is already in a transaction runs the function in it.
+func (p *SQLClient) transact(ctx context.Context, f func(q sqlQuerier) error) error {
+
+	if p.tx != nil {
+		return f(p.tx)
+	}
+
+	tx, err := p.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+	if err != nil {
+		return err
+	}
+	if err = f(tx); err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			log.WithField("error", rollbackErr).Error("Could not roll back database transaction.")
+		}
+		return err
+	}
+	return tx.Commit()
+}
+
+// insert adds a document, failing if one with the same key exists.
+func (p *SQLClient) insert(ctx context.Context, q sqlQuerier, table sqlTable, key string, object interface{}) error {
+
+	document, err := json.Marshal(object)
+	if err != nil {
+		return err
+	}
+	statement := fmt.Sprintf("INSERT INTO %s (name, document) VALUES ($1, $2) ON CONFLICT (name) DO NOTHING",
+		table)
+	result, err := q.ExecContext(ctx, statement, key, string(document))
+	return checkRowAffected(result, err, KeyExistsErr, key)
+}
+
+// update replaces a document, failing if none with the key exists.
+func (p *SQLClient) update(ctx context.Context, q sqlQuerier, table sqlTable, key string, object interface{}) error {
+
+	document, err := json.Marshal(object)
+	if err != nil {
+		return err
+	}
+	statement := fmt.Sprintf("UPDATE %s SET document = $2, updated = now() WHERE name = $1", table)
+	result, err := q.ExecContext(ctx, statement, key, string(document))
+	return checkRowAffected(result, err, KeyNotFoundErr, key)
+}
+
+// set adds or replaces a document.
+func (p *SQLClient) set(ctx context.Context, q sqlQuerier, table sqlTable, key string, object interface{}) error {
+
+	document, err := json.Marshal(object)
+	if err != nil {
+		return err
+	}
+	statement := fmt.Sprintf("INSERT INTO %s (name, document) VALUES ($1, $2) "+
+		"ON CONFLICT (name) DO UPDATE SET document = EXCLUDED.document, updated = now()", table)
+	_, err = q.ExecContext(ctx, statement, key, string(document))
+	return err
+}
+
+// get reads a document into an object.
+func (p *SQLClient) get(ctx context.Context, q sqlQuerier, table sqlTable, key string, object interface{}) error {
+
+	var document []byte
+	statement := fmt.Sprintf("SELECT document FROM %s WHERE name = $1", table)
+	if err := q.QueryRowContext(ctx, statement, key).Scan(&document); err != nil {
+		if err == sql.ErrNoRows {
+			return NewPersistentStoreError(KeyNotFoundErr, key)
+		}
+		return err
+	}
+	return json.Unmarshal(document, object)
+}
+
+// list reads every document of a table, in order of their keys, calling a function with each.
+func (p *SQLClient) list(ctx context.Context, q sqlQuerier, table sqlTable, f func(document []byte) error) error {
+
+	rows, err := q.QueryContext(ctx, fmt.Sprintf("SELECT document FROM %s ORDER BY name", table))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var document []byte
+		if err = rows.Scan(&document); err != nil {
+			return err
+		}
+		if err = f(document); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// delete removes a document, failing if none with the key exists.
+func (p *SQLClient) delete(ctx context.Context, q sqlQuerier, table sqlTable, key string) error {
+	result, err := q.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s WHERE name = $1", table), key)
+	return checkRowAffected(result, err, KeyNotFoundErr, key)
+}
+
+// deleteAll removes every document of a table.
+func (p *SQLClient) deleteAll(ctx context.Context, q sqlQuerier, table sqlTable) error {
+	_, err := q.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s", table))
+	return err
+}
+
+// checkRowAffected returns a persistent store error if a statement changed no rows.
+func checkRowAffected(result sql.Result, err error, message, key string) error {
+	if err != nil {
+		return err
+	}
+	if rows, err := result.RowsAffected(); err != nil {
+		return err
+	} else if rows == 0 {
+		return NewPersistentStoreError(message, key)
+	}
+	return nil
+}
+
+func (p *SQLClient) context() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), config.PersistentStoreTimeout)
+}
+
+// GetType returns the persistent store type
+func (p *SQLClient) GetType() StoreType {
+	return SQLStore
+}
+
+// Stop closes the database connections
+func (p *SQLClient) Stop() error {
+	if p.tx != nil {
+		return errors.New("the SQL client of a transaction can't be stopped")
+	}
+	return p.db.Close()
+}
+
+// Atomically calls a function with a client that writes in one database transaction, which is
+// committed if the function succeeds and rolled back otherwise.
+func (p *SQLClient) Atomically(f func(client Client) error) error {
+	ctx, cancel := p.context()
+	defer cancel()
+
+	return p.transact(ctx, func(tx sqlQuerier) error {
+		return f(&SQLClient{db: p.db, tx: tx})
+	})
+}
+
+// GetConfig returns the configuration for the SQL client
+func (p *SQLClient) GetConfig() *ClientConfig {
+	return &ClientConfig{}
+}
+
+// GetVersion returns the version of the persistent data
+func (p *SQLClient) GetVersion() (*config.PersistentStateVersion, error) {
+	ctx, cancel := p.context()
+	defer cancel()
+
+	version := &config.PersistentStateVersion{}
+	if err := p.get(ctx, p.querier(), sqlVersionTable, sqlVersionKey, version); err != nil {
+		if MatchKeyNotFoundErr(err) {
+			return nil, NewPersistentStoreError(KeyNotFoundErr, config.StoreURL)
+		}
+		return nil, err
+	}
+	return version, nil
+}
+
+// SetVersion sets the version of the persistent data
+func (p *SQLClient) SetVersion(version *config.PersistentStateVersion) error {
+	ctx, cancel := p.context()
+	defer cancel()
+
+	return p.set(ctx, p.querier(), sqlVersionTable, sqlVersionKey, version)
+}
+
+// AddBackend saves the minimally required backend state to the persistent store
+func (p *SQLClient) AddBackend(b *storage.Backend) error {
+	return p.AddBackendPersistent(b.ConstructPersistent())
+}
+
+func (p *SQLClient) AddBackendPersistent(backend *storage.BackendPersistent) error {
+	ctx, cancel := p.context()
+	defer cancel()
+
+	return p.insert(ctx, p.querier(), sqlBackendTable, backend.Name, backend)
+}
+
+// GetBackend retrieves a backend from the persistent store
+func (p *SQLClient) GetBackend(backendName string) (*storage.BackendPersistent, error) {
+	ctx, cancel := p.context()
+	defer cancel()
+
+	backend := &storage.BackendPersistent{}
+	if err := p.get(ctx, p.querier(), sqlBackendTable, backendName, backend); err != nil {
+		return nil, err
+	}
+	return backend, nil
+}
+
+// UpdateBackend updates the backend state on the persistent store
+func (p *SQLClient) UpdateBackend(b *storage.Backend) error {
+	return p.UpdateBackendPersistent(b.ConstructPersistent())
+}
+
+// UpdateBackendPersistent updates a backend's persistent state
+func (p *SQLClient) UpdateBackendPersistent(backend *storage.BackendPersistent) error {
+	ctx, cancel := p.context()
+	defer cancel()
+
+	return p.update(ctx, p.querier(), sqlBackendTable, backend.Name, backend)
+}
+
+// DeleteBackend deletes the backend state on the persistent store
+func (p *SQLClient) DeleteBackend(backend *storage.Backend) error {
+	ctx, cancel := p.context()
+	defer cancel()
+
+	return p.delete(ctx, p.querier(), sqlBackendTable, backend.Name)
+}
+
+// GetBackends retrieves all backends
+func (p *SQLClient) GetBackends() ([]*storage.BackendPersistent, error) {
+	ctx, cancel := p.context()
+	defer cancel()
+
+	backendList := make([]*storage.BackendPersistent, 0)
+	err := p.list(ctx, p.querier(), sqlBackendTable, func(document []byte) error {
+		backend := &storage.BackendPersistent{}
+		if err := json.Unmarshal(document, backend); err != nil {
+			return err
+		}
+		if !backend.Online {
+			// handle upgrade logic for "Offline" (aka Deleting) backends
+			backend.State = storage.Deleting
+		}
+		backendList = append(backendList, backend)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return backendList, nil
+}
+
+// DeleteBackends deletes all backends
+func (p *SQLClient) DeleteBackends() error {
+	ctx, cancel := p.context()
+	defer cancel()
+
+	return p.deleteAll(ctx, p.querier(), sqlBackendTable)
+}
+
+// ReplaceBackendAndUpdateVolumes replaces a backend and updates all volumes to
+// reflect the new backend.
+func (p *SQLClient) ReplaceBackendAndUpdateVolumes(origBackend, newBackend *storage.Backend) error {
+	ctx, cancel := p.context()
+	defer cancel()
+
+	// Volumes refer to their backend by UUID, which the new backend keeps, so only the backend changes
+	newBackend.BackendUUID = origBackend.BackendUUID
+
+	return p.transact(ctx, func(tx sqlQuerier) error {
+		if err := p.delete(ctx, tx, sqlBackendTable, origBackend.Name); err != nil {
+			return err
+		}
+		return p.insert(ctx, tx, sqlBackendTable, newBackend.Name, newBackend.ConstructPersistent())
+	})
+}
+
+// AddVolume saves a volume's state to the persistent store
+func (p *SQLClient) AddVolume(vol *storage.Volume) error {
+	return p.AddVolumePersistent(vol.ConstructExternal())
+}
+
+// AddVolumePersistent saves a volume's persistent state to the persistent store
+func (p *SQLClient) AddVolumePersistent(volExternal *storage.VolumeExternal) error {
+	ctx, cancel := p.context()
+	defer cancel()
+
+	return p.insert(ctx, p.querier(), sqlVolumeTable, volExternal.Config.Name, volExternal)
+}
+
+// GetVolume retrieves a volume's state from the persistent store
+func (p *SQLClient) GetVolume(volName string) (*storage.VolumeExternal, error) {
+	ctx, cancel := p.context()
+	defer cancel()
+
+	volExternal := &storage.VolumeExternal{}
+	if err := p.get(ctx, p.querier(), sqlVolumeTable, volName, volExternal); err != nil {
+		return nil, err
+	}
+	return volExternal, nil
+}
+
+// UpdateVolume updates a volume's state on the persistent store
+func (p *SQLClient) UpdateVolume(vol *storage.Volume) error {
+	return p.UpdateVolumePersistent(vol.ConstructExternal())
+}
+
+// UpdateVolumePersistent updates a volume's persistent state
+func (p *SQLClient) UpdateVolumePersistent(volExternal *storage.VolumeExternal) error {
+	ctx, cancel := p.context()
+	defer cancel()
+
+	return p.update(ctx, p.querier(), sqlVolumeTable, volExternal.Config.Name, volExternal)
+}
+
+// DeleteVolume deletes a volume's state from the persistent store
+func (p *SQLClient) DeleteVolume(vol *storage.Volume) error {
+	ctx, cancel := p.context()
+	defer cancel()
+
+	return p.delete(ctx, p.querier(), sqlVolumeTable, vol.Config.Name)
+}
+
+func (p *SQLClient) DeleteVolumeIgnoreNotFound(vol *storage.Volume) error {
+	err := p.DeleteVolume(vol)
+	if err != nil && MatchKeyNotFoundErr(err) {
+		return nil
+	}
+	return err
+}
+
+// GetVolumes retrieves all volumes
+func (p *SQLClient) GetVolumes() ([]*storage.VolumeExternal, error) {
+	ctx, cancel := p.context()
+	defer cancel()
+
+	volumeList := make([]*storage.VolumeExternal, 0)
+	err := p.list(ctx, p.querier(), sqlVolumeTable, func(document []byte) error {
+		volExternal := &storage.VolumeExternal{}
+		if err := json.Unmarshal(document, volExternal); err != nil {
+			return err
+		}
+		volumeList = append(volumeList, volExternal)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return volumeList, nil
+}
+
+// DeleteVolumes deletes all volumes
+func (p *SQLClient) DeleteVolumes() error {
+	ctx, cancel := p.context()
+	defer cancel()
+
+	return p.deleteAll(ctx, p.querier(), sqlVolumeTable)
+}
+
+// AddVolumeTransaction logs an AddVolume operation
+func (p *SQLClient) AddVolumeTransaction(volTxn *storage.VolumeTransaction) error {
+	ctx, cancel := p.context()
+	defer cancel()
+
+	return p.set(ctx, p.querier(), sqlTransactionTable, volTxn.Name(), volTxn)
+}
+
+// GetVolumeTransactions retrieves AddVolume logs
+func (p *SQLClient) GetVolumeTransactions() ([]*storage.VolumeTransaction, error) {
+	ctx, cancel := p.context()
+	defer cancel()
+
+	volTxnList := make([]*storage.VolumeTransaction, 0)
+	err := p.list(ctx, p.querier(), sqlTransactionTable, func(document []byte) error {
+		volTxn := &storage.VolumeTransaction{}
+		if err := json.Unmarshal(document, volTxn); err != nil {
+			return err
+		}
+		volTxnList = append(volTxnList, volTxn)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return volTxnList, nil
+}
+
+func (p *SQLClient) UpdateVolumeTransaction(volTxn *storage.VolumeTransaction) error {
+	ctx, cancel := p.context()
+	defer cancel()
+
+	return p.update(ctx, p.querier(), sqlTransactionTable, volTxn.Name(), volTxn)
+}
+
+// GetExistingVolumeTransaction returns an existing version of the current
+// volume transaction, if it exists.  If no volume transaction with the same
+// key exists, it returns nil.
+func (p *SQLClient) GetExistingVolumeTransaction(
+	volTxn *storage.VolumeTransaction,
+) (*storage.VolumeTransaction, error) {
+	ctx, cancel := p.context()
+	defer cancel()
+
+	existing := &storage.VolumeTransaction{}
+	if err := p.get(ctx, p.querier(), sqlTransactionTable, volTxn.Name(), existing); err != nil {
+		if MatchKeyNotFoundErr(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("unable to read volume transaction %s from database: %v", volTxn.Name(), err)
+	}
+	return existing, nil
+}
+
+// DeleteVolumeTransaction deletes an AddVolume log
+func (p *SQLClient) DeleteVolumeTransaction(volTxn *storage.VolumeTransaction) error {
+	ctx, cancel := p.context()
+	defer cancel()
+
+	return p.delete(ctx, p.querier(), sqlTransactionTable, volTxn.Name())
+}
+
+func (p *SQLClient) AddStorageClass(sc *storageclass.StorageClass) error {
+	ctx, cancel := p.context()
+	defer cancel()
+
+	sClass := sc.ConstructPersistent()
+	return p.insert(ctx, p.querier(), sqlStorageClassTable, sClass.GetName(), sClass)
+}
+
+func (p *SQLClient) GetStorageClass(scName string) (*storageclass.Persistent, error) {
+	ctx, cancel := p.context()
+	defer cancel()
+
+	persistent := &storageclass.Persistent{}
+	if err := p.get(ctx, p.querier(), sqlStorageClassTable, scName, persistent); err != nil {
+		return nil, err
+	}
+	return persistent, nil
+}
+
+func (p *SQLClient) GetStorageClasses() ([]*storageclass.Persistent, error) {
+	ctx, cancel := p.context()
+	defer cancel()
+
+	storageClassList := make([]*storageclass.Persistent, 0)
+	err := p.list(ctx, p.querier(), sqlStorageClassTable, func(document []byte) error {
+		persistent := &storageclass.Persistent{}
+		if err := json.Unmarshal(document, persistent); err != nil {
+			return err
+		}
+		storageClassList = append(storageClassList, persistent)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return storageClassList, nil
+}
+
+// DeleteStorageClass deletes a storage class's state from the persistent store
+func (p *SQLClient) DeleteStorageClass(sc *storageclass.StorageClass) error {
+	ctx, cancel := p.context()
+	defer cancel()
+
+	return p.delete(ctx, p.querier(), sqlStorageClassTable, sc.GetName())
+}
+
+// AddOrUpdateNode adds/updates a CSI node object to the persistent store
+func (p *SQLClient) AddOrUpdateNode(n *utils.Node) error {
+	ctx, cancel := p.context()
+	defer cancel()
+
+	return p.set(ctx, p.querier(), sqlNodeTable, n.Name, n)
+}
+
+func (p *SQLClient) GetNode(nName string) (*utils.Node, error) {
+	ctx, cancel := p.context()
+	defer cancel()
+
+	node := &utils.Node{}
+	if err := p.get(ctx, p.querier(), sqlNodeTable, nName, node); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+func (p *SQLClient) GetNodes() ([]*utils.Node, error) {
+	ctx, cancel := p.context()
+	defer cancel()
+
+	nodeList := make([]*utils.Node, 0)
+	err := p.list(ctx, p.querier(), sqlNodeTable, func(document []byte) error {
+		node := &utils.Node{}
+		if err := json.Unmarshal(document, node); err != nil {
+			return err
+		}
+		nodeList = append(nodeList, node)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return nodeList, nil
+}
+
+// DeleteNode deletes a node from the persistent store
+func (p *SQLClient) DeleteNode(n *utils.Node) error {
+	ctx, cancel := p.context()
+	defer cancel()
+
+	return p.delete(ctx, p.querier(), sqlNodeTable, n.Name)
+}
+
+// AddSnapshot adds a snapshot's state to the persistent store
+func (p *SQLClient) AddSnapshot(snapshot *storage.Snapshot) error {
+	ctx, cancel := p.context()
+	defer cancel()
+
+	return p.insert(ctx, p.querier(), sqlSnapshotTable, snapshot.ID(), snapshot.ConstructPersistent())
+}
+
+// GetSnapshot fetches a snapshot's state from the persistent store
+func (p *SQLClient) GetSnapshot(volumeName, snapshotName string) (*storage.SnapshotPersistent, error) {
+	ctx, cancel := p.context()
+	defer cancel()
+
+	snapPersistent := &storage.SnapshotPersistent{}
+	snapshotID := storage.MakeSnapshotID(volumeName, snapshotName)
+	if err := p.get(ctx, p.querier(), sqlSnapshotTable, snapshotID, snapPersistent); err != nil {
+		return nil, err
+	}
+	return snapPersistent, nil
+}
+
+// GetSnapshots retrieves all snapshots
+func (p *SQLClient) GetSnapshots() ([]*storage.SnapshotPersistent, error) {
+	ctx, cancel := p.context()
+	defer cancel()
+
+	snapshotList := make([]*storage.SnapshotPersistent, 0)
+	err := p.list(ctx, p.querier(), sqlSnapshotTable, func(document []byte) error {
+		snapPersistent := &storage.SnapshotPersistent{}
+		if err := json.Unmarshal(document, snapPersistent); err != nil {
+			return err
+		}
+		snapshotList = append(snapshotList, snapPersistent)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return snapshotList, nil
+}
+
+// DeleteSnapshot deletes a snapshot from the persistent store
+func (p *SQLClient) DeleteSnapshot(snapshot *storage.Snapshot) error {
+	ctx, cancel := p.context()
+	defer cancel()
+
+	return p.delete(ctx, p.querier(), sqlSnapshotTable, snapshot.ID())
+}
+
+// DeleteSnapshotIgnoreNotFound deletes a snapshot from the persistent store,
+// returning no error if the record does not exist.
+func (p *SQLClient) DeleteSnapshotIgnoreNotFound(snapshot *storage.Snapshot) error {
+	err := p.DeleteSnapshot(snapshot)
+	if err != nil && MatchKeyNotFoundErr(err) {
+		return nil
+	}
+	return err
+}
+
+// DeleteSnapshots deletes all snapshots
+func (p *SQLClient) DeleteSnapshots() error {
+	ctx, cancel := p.context()
+	defer cancel()
+
+	return p.deleteAll(ctx, p.querier(), sqlSnapshotTable)
+}
@@ -0,0 +1,187 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package persistentstore
+
+import (
+	"database/sql"
+	"flag"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/storage"
+	drivers "github.com/netapp/trident/storage_drivers"
+	"github.com/netapp/trident/storage_drivers/ontap"
+	"github.com/netapp/trident/utils"
+)
+
+var (
+	sqlDataSource = flag.String("sql", "", "PostgreSQL database for the SQL store tests, which are skipped "+
+		"if none is given")
+	sqlDriver = flag.String("sql_driver", DefaultSQLDriver, "database/sql driver for the SQL store tests")
+)
+
+func TestSQLDefaultDriverRegistered(t *testing.T) {
+	assert.Contains(t, sql.Drivers(), DefaultSQLDriver)
+}
+
+func TestSQLMigrations(t *testing.T) {
+
+	// Versions must increase, so that every database applies the migrations in the same order
+	for i, migration := range sqlMigrations {
+		assert.Equal(t, i+1, migration.version, "schema versions must be consecutive")
+		assert.NotEmpty(t, migration.statements)
+	}
+
+	assert.Len(t, pendingSQLMigrations(0), len(sqlMigrations))
+	assert.Empty(t, pendingSQLMigrations(sqlMigrations[len(sqlMigrations)-1].version))
+}
+
+// getTestSQLClient returns a client for an empty test database.
+func getTestSQLClient(t *testing.T) *SQLClient {
+	if *sqlDataSource == "" {
+		t.Skip("no SQL database specified")
+	}
+
+	p, err := NewSQLClient(*sqlDriver, *sqlDataSource)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = p.Stop() })
+
+	require.NoError(t, p.DeleteBackends())
+	require.NoError(t, p.DeleteVolumes())
+	require.NoError(t, p.DeleteSnapshots())
+	return p
+}
+
+func TestSQLBackendsAndVolumes(t *testing.T) {
+
+	p := getTestSQLClient(t)
+
+	// The schema is already up to date, so connecting again changes nothing
+	p2, err := NewSQLClient(*sqlDriver, *sqlDataSource)
+	require.NoError(t, err)
+	require.NoError(t, p2.Stop())
+
+	version := &config.PersistentStateVersion{
+		PersistentStoreVersion: string(SQLStore),
+		OrchestratorAPIVersion: config.OrchestratorAPIVersion,
+	}
+	require.NoError(t, p.SetVersion(version))
+	storedVersion, err := p.GetVersion()
+	require.NoError(t, err)
+	assert.Equal(t, version, storedVersion)
+
+	backend := &storage.Backend{
+		Driver: &ontap.NASStorageDriver{
+			Config: drivers.OntapStorageDriverConfig{
+				CommonStorageDriverConfig: &drivers.CommonStorageDriverConfig{
+					StorageDriverName: drivers.OntapNASStorageDriverName,
+				},
+				ManagementLIF: "10.0.0.4",
+				SVM:           "svm1",
+			},
+		},
+		Name:        "nfs",
+		BackendUUID: uuid.New().String(),
+		Online:      true,
+		State:       storage.Online,
+	}
+	require.NoError(t, p.AddBackend(backend))
+	assert.True(t, MatchKeyNotFoundErr(p.UpdateBackendPersistent(&storage.BackendPersistent{Name: "missing"})))
+	assert.Error(t, p.AddBackend(backend), "duplicate backends should be rejected")
+
+	volume := &storage.Volume{
+		Config:      &storage.VolumeConfig{Name: "vol1", Size: "1GB"},
+		BackendUUID: backend.BackendUUID,
+		State:       storage.VolumeStateOnline,
+	}
+	require.NoError(t, p.AddVolume(volume))
+	volumes, err := p.GetVolumes()
+	require.NoError(t, err)
+	require.Len(t, volumes, 1)
+	assert.Equal(t, backend.BackendUUID, volumes[0].BackendUUID)
+
+	// Replacing a backend renames it and keeps its UUID, all or nothing
+	newBackend := &storage.Backend{Driver: backend.Driver, Name: "nfs2", Online: true, State: storage.Online}
+	require.NoError(t, p.ReplaceBackendAndUpdateVolumes(backend, newBackend))
+	backends, err := p.GetBackends()
+	require.NoError(t, err)
+	require.Len(t, backends, 1)
+	assert.Equal(t, "nfs2", backends[0].Name)
+	assert.Equal(t, backend.BackendUUID, backends[0].BackendUUID)
+
+	assert.Error(t, p.ReplaceBackendAndUpdateVolumes(backend, newBackend), "the original backend is gone")
+	backends, err = p.GetBackends()
+	require.NoError(t, err)
+	assert.Len(t, backends, 1, "a failed replacement should change nothing")
+
+	require.NoError(t, p.DeleteVolume(volume))
+	assert.True(t, MatchKeyNotFoundErr(p.DeleteVolume(volume)))
+	assert.NoError(t, p.DeleteVolumeIgnoreNotFound(volume))
+}
+
+func TestSQLTransactionsAndNodes(t *testing.T) {
+
+	p := getTestSQLClient(t)
+
+	volTxn := &storage.VolumeTransaction{
+		Config: &storage.VolumeConfig{Name: "vol1"},
+		Op:     storage.AddVolume,
+	}
+	existing, err := p.GetExistingVolumeTransaction(volTxn)
+	require.NoError(t, err)
+	assert.Nil(t, existing)
+
+	require.NoError(t, p.AddVolumeTransaction(volTxn))
+	existing, err = p.GetExistingVolumeTransaction(volTxn)
+	require.NoError(t, err)
+	assert.Equal(t, volTxn.Name(), existing.Name())
+	require.NoError(t, p.DeleteVolumeTransaction(volTxn))
+
+	node := &utils.Node{Name: "node1", IQN: "iqn.1993-08.org.debian:01:1"}
+	require.NoError(t, p.AddOrUpdateNode(node))
+	node.IQN = "iqn.1993-08.org.debian:01:2"
+	require.NoError(t, p.AddOrUpdateNode(node))
+	storedNode, err := p.GetNode(node.Name)
+	require.NoError(t, err)
+	assert.Equal(t, node.IQN, storedNode.IQN)
+	require.NoError(t, p.DeleteNode(node))
+}
+
+func TestSQLAtomically(t *testing.T) {
+
+	p := getTestSQLClient(t)
+
+	snapshots := []*storage.Snapshot{
+		storage.NewSnapshot(&storage.SnapshotConfig{Name: "snap1", VolumeName: "vol1"}, "", 0),
+		storage.NewSnapshot(&storage.SnapshotConfig{Name: "snap1", VolumeName: "vol2"}, "", 0),
+	}
+
+	// Nothing is kept if a later write fails
+	err := p.Atomically(func(client Client) error {
+		require.NoError(t, client.AddSnapshot(snapshots[0]))
+		return client.AddSnapshot(snapshots[0])
+	})
+	assert.Error(t, err, "duplicate snapshots should be rejected")
+	storedSnapshots, err := p.GetSnapshots()
+	require.NoError(t, err)
+	assert.Empty(t, storedSnapshots, "a failed transaction should change nothing")
+
+	// Everything is kept if all writes succeed
+	err = p.Atomically(func(client Client) error {
+		assert.Error(t, client.Stop(), "the client of a transaction can't be stopped")
+		for _, snapshot := range snapshots {
+			if err := client.AddSnapshot(snapshot); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	storedSnapshots, err = p.GetSnapshots()
+	require.NoError(t, err)
+	assert.Len(t, storedSnapshots, 2)
+}
@@ -0,0 +1,108 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package persistentstore
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/storage"
+	storageclass "github.com/netapp/trident/storage_class"
+	"github.com/netapp/trident/utils"
+)
+
+// StateBundleVersion is the version of the StateBundle schema.
+const StateBundleVersion = "1"
+
+// StateBundle is a portable copy of Trident's persistent state, which may be exported from one Trident
+// instance and imported into another, such as when recovering from the loss of a cluster.  Backend
+// configurations include their credentials, so bundles must be stored securely.
+type StateBundle struct {
+	Version             string                        `json:"version"`
+	OrchestratorVersion string                        `json:"orchestratorVersion"`
+	Created             string                        `json:"created"`
+	Backends            []*storage.BackendPersistent  `json:"backends"`
+	StorageClasses      []*storageclass.Persistent    `json:"storageClasses"`
+	Volumes             []*storage.VolumeExternal     `json:"volumes"`
+	Snapshots           []*storage.SnapshotPersistent `json:"snapshots"`
+	Nodes               []*utils.Node                 `json:"nodes"`
+	Transactions        []*storage.VolumeTransaction  `json:"transactions"`
+}
+
+// StateImportResult describes what was done with each object in an imported StateBundle.
+type StateImportResult struct {
+	// AddedBackends are the backends created from the bundle
+	AddedBackends []string `json:"addedBackends"`
+	// ReboundBackends are the bundle's backends that were already defined, whose volumes now use them
+	ReboundBackends []string `json:"reboundBackends"`
+	StorageClasses  []string `json:"storageClasses"`
+	Volumes         []string `json:"volumes"`
+	Snapshots       []string `json:"snapshots"`
+	// Skipped describes each object that wasn't imported, and why
+	Skipped []string `json:"skipped"`
+}
+
+// ExportState reads all of the state in a persistent store.
+func ExportState(client Client) (*StateBundle, error) {
+
+	bundle := &StateBundle{
+		Version:             StateBundleVersion,
+		OrchestratorVersion: config.OrchestratorVersion.String(),
+		Created:             time.Now().UTC().Format(time.RFC3339),
+	}
+
+	var err error
+	if bundle.Backends, err = client.GetBackends(); err != nil && !MatchKeyNotFoundErr(err) {
+		return nil, fmt.Errorf("could not read backends; %v", err)
+	}
+	if bundle.StorageClasses, err = client.GetStorageClasses(); err != nil && !MatchKeyNotFoundErr(err) {
+		return nil, fmt.Errorf("could not read storage classes; %v", err)
+	}
+	if bundle.Volumes, err = client.GetVolumes(); err != nil && !MatchKeyNotFoundErr(err) {
+		return nil, fmt.Errorf("could not read volumes; %v", err)
+	}
+	if bundle.Snapshots, err = client.GetSnapshots(); err != nil && !MatchKeyNotFoundErr(err) {
+		return nil, fmt.Errorf("could not read snapshots; %v", err)
+	}
+	if bundle.Nodes, err = client.GetNodes(); err != nil && !MatchKeyNotFoundErr(err) {
+		return nil, fmt.Errorf("could not read nodes; %v", err)
+	}
+	if bundle.Transactions, err = client.GetVolumeTransactions(); err != nil && !MatchKeyNotFoundErr(err) {
+		return nil, fmt.Errorf("could not read transactions; %v", err)
+	}
+
+	return bundle, nil
+}
+
+// Validate checks that a bundle may be imported.
+func (b *StateBundle) Validate() error {
+
+	if b.Version != StateBundleVersion {
+		return fmt.Errorf("unsupported state bundle version %s, expected %s", b.Version, StateBundleVersion)
+	}
+
+	backendUUIDs := make(map[string]bool)
+	for _, backend := range b.Backends {
+		if backend == nil || backend.Name == "" || backend.BackendUUID == "" {
+			return fmt.Errorf("state bundle contains a backend without a name or UUID")
+		}
+		backendUUIDs[backend.BackendUUID] = true
+	}
+	for _, volume := range b.Volumes {
+		if volume == nil || volume.Config == nil || volume.Config.Name == "" {
+			return fmt.Errorf("state bundle contains a volume without a name")
+		}
+		if !backendUUIDs[volume.BackendUUID] {
+			return fmt.Errorf("volume %s uses backend %s, which is not in the state bundle", volume.Config.Name,
+				volume.BackendUUID)
+		}
+	}
+	for _, snapshot := range b.Snapshots {
+		if snapshot == nil || snapshot.Config == nil || snapshot.Config.Name == "" {
+			return fmt.Errorf("state bundle contains a snapshot without a name")
+		}
+	}
+
+	return nil
+}
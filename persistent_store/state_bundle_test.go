@@ -0,0 +1,52 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package persistentstore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netapp/trident/storage"
+)
+
+func TestExportStateEmptyStore(t *testing.T) {
+	bundle, err := ExportState(NewInMemoryClient())
+	if assert.NoError(t, err) {
+		assert.Equal(t, StateBundleVersion, bundle.Version)
+		assert.Empty(t, bundle.Backends)
+		assert.Empty(t, bundle.Volumes)
+		assert.NoError(t, bundle.Validate())
+	}
+}
+
+func TestStateBundleValidate(t *testing.T) {
+	backend := &storage.BackendPersistent{Name: "backend", BackendUUID: "uuid1"}
+	volume := &storage.VolumeExternal{Config: &storage.VolumeConfig{Name: "vol"}, BackendUUID: "uuid1"}
+
+	tests := []struct {
+		name    string
+		bundle  *StateBundle
+		isValid bool
+	}{
+		{"valid", &StateBundle{Version: StateBundleVersion, Backends: []*storage.BackendPersistent{backend},
+			Volumes: []*storage.VolumeExternal{volume}}, true},
+		{"wrong version", &StateBundle{Version: "0"}, false},
+		{"unnamed backend", &StateBundle{Version: StateBundleVersion,
+			Backends: []*storage.BackendPersistent{{BackendUUID: "uuid1"}}}, false},
+		{"missing backend", &StateBundle{Version: StateBundleVersion,
+			Volumes: []*storage.VolumeExternal{volume}}, false},
+		{"unnamed snapshot", &StateBundle{Version: StateBundleVersion,
+			Snapshots: []*storage.SnapshotPersistent{{}}}, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.bundle.Validate()
+			if test.isValid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
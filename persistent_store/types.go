@@ -20,6 +20,7 @@ const (
 	EtcdV3bStore     StoreType = "etcdv3b"
 	PassthroughStore StoreType = "passthrough"
 	CRDV1Store       StoreType = "crdv1"
+	SQLStore         StoreType = "sql"
 )
 
 type ClientConfig struct {
@@ -78,6 +79,25 @@ type Client interface {
 	DeleteSnapshots() error
 }
 
+// TransactionalClient is implemented by stores that can write several objects at once, so that a
+// change to several objects is persisted either completely or not at all.
+type TransactionalClient interface {
+	Client
+	// Atomically calls a function with a client whose writes are all kept if the function succeeds,
+	// and all discarded if it fails.
+	Atomically(f func(client Client) error) error
+}
+
+// Atomically calls a function that changes several objects, with a client that makes the changes
+// atomically if the store supports it.  Other stores make each change as the function makes it, so
+// the function should still undo its earlier changes when a later one fails.
+func Atomically(client Client, f func(client Client) error) error {
+	if txnClient, ok := client.(TransactionalClient); ok {
+		return txnClient.Atomically(f)
+	}
+	return f(client)
+}
+
 type EtcdClient interface {
 	Client
 	Create(key, value string) error
@@ -96,4 +116,14 @@ type CRDClient interface {
 	HasStorageClasses() (bool, error)
 	HasVolumes() (bool, error)
 	HasVolumeTransactions() (bool, error)
+	ReencryptBackendSecrets() ([]string, error)
+	GetBackendUUID(backendName string) (string, error)
+	ForceDeleteBackend(backendName string) error
+	ForceDeleteVolume(volumeName string) error
+}
+
+// StatusPublisher is implemented by stores that publish the runtime state of Trident's objects where
+// cluster tooling can watch it, such as in the status of custom resources.
+type StatusPublisher interface {
+	PublishBackendHealth(health *storage.BackendHealth) error
 }
@@ -0,0 +1,67 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package persistentstore
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netapp/trident/storage"
+)
+
+// transactionalTestClient discards the writes of a failed Atomically call, like a store with transactions.
+type transactionalTestClient struct {
+	*InMemoryClient
+	calls int
+}
+
+func (c *transactionalTestClient) Atomically(f func(client Client) error) error {
+	c.calls++
+	txnClient := NewInMemoryClient()
+	if err := f(txnClient); err != nil {
+		return err
+	}
+	snapshots, err := txnClient.GetSnapshots()
+	if err != nil {
+		return err
+	}
+	for _, snapshot := range snapshots {
+		if err = c.AddSnapshot(storage.NewSnapshot(snapshot.Config, snapshot.Created, snapshot.SizeBytes)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestAtomically(t *testing.T) {
+
+	snapshot := storage.NewSnapshot(&storage.SnapshotConfig{Name: "snap1", VolumeName: "vol1"}, "", 0)
+	failure := errors.New("failed")
+	addThenFail := func(client Client) error {
+		require.NoError(t, client.AddSnapshot(snapshot))
+		return failure
+	}
+
+	// A store without transactions keeps the writes made before the failure
+	client := NewInMemoryClient()
+	assert.Equal(t, failure, Atomically(client, addThenFail))
+	snapshots, err := client.GetSnapshots()
+	require.NoError(t, err)
+	assert.Len(t, snapshots, 1)
+
+	// A store with transactions keeps all of the writes or none of them
+	txnClient := &transactionalTestClient{InMemoryClient: NewInMemoryClient()}
+	assert.Equal(t, failure, Atomically(txnClient, addThenFail))
+	snapshots, err = txnClient.GetSnapshots()
+	require.NoError(t, err)
+	assert.Empty(t, snapshots)
+
+	assert.NoError(t, Atomically(txnClient, func(client Client) error { return client.AddSnapshot(snapshot) }))
+	snapshots, err = txnClient.GetSnapshots()
+	require.NoError(t, err)
+	assert.Len(t, snapshots, 1)
+	assert.Equal(t, 2, txnClient.calls)
+}
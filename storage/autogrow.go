@@ -0,0 +1,153 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package storage
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/netapp/trident/utils"
+)
+
+const (
+	AutogrowNone      = "none"
+	AutogrowThreshold = "threshold"
+	AutogrowIncrement = "increment"
+	AutogrowMaxSize   = "maxSize"
+
+	defaultAutogrowIncrementPercent = 10
+)
+
+// AutogrowPolicy determines when and by how much a volume is expanded as its filesystem fills up.
+type AutogrowPolicy struct {
+	// ThresholdPercent is the filesystem usage at or above which the volume is expanded
+	ThresholdPercent int `json:"thresholdPercent"`
+	// IncrementBytes is the amount added to the volume at each expansion
+	IncrementBytes uint64 `json:"incrementBytes,omitempty"`
+	// IncrementPercent is the percentage of the volume's size added at each expansion, if
+	// IncrementBytes isn't set
+	IncrementPercent int `json:"incrementPercent,omitempty"`
+	// MaxSizeBytes is the size the volume is never expanded beyond, or zero for no limit
+	MaxSizeBytes uint64 `json:"maxSizeBytes,omitempty"`
+}
+
+// ParseAutogrowPolicy parses an autogrow policy of the form "threshold=80,increment=10Gi,maxSize=1Ti".
+// The increment may be a size or a percentage of the volume's size such as "20%", and defaults
+// to 10%.  The maximum size may be omitted, and "none" disables autogrow.
+func ParseAutogrowPolicy(value string) (*AutogrowPolicy, error) {
+
+	policy := &AutogrowPolicy{}
+
+	value = strings.TrimSpace(value)
+	if value == "" || strings.EqualFold(value, AutogrowNone) {
+		return policy, nil
+	}
+
+	for _, rule := range strings.Split(value, ",") {
+		parts := strings.SplitN(strings.TrimSpace(rule), "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid autogrow rule '%s'", rule)
+		}
+		setting := strings.TrimSpace(parts[1])
+
+		switch strings.ToLower(strings.TrimSpace(parts[0])) {
+		case AutogrowThreshold:
+			threshold, err := strconv.Atoi(strings.TrimSuffix(setting, "%"))
+			if err != nil || threshold < 1 || threshold > 99 {
+				return nil, fmt.Errorf("invalid autogrow threshold '%s'; must be a percentage from 1 to 99",
+					setting)
+			}
+			policy.ThresholdPercent = threshold
+		case AutogrowIncrement:
+			if strings.HasSuffix(setting, "%") {
+				percent, err := strconv.Atoi(strings.TrimSuffix(setting, "%"))
+				if err != nil || percent < 1 {
+					return nil, fmt.Errorf("invalid autogrow increment '%s'", setting)
+				}
+				policy.IncrementPercent = percent
+				policy.IncrementBytes = 0
+			} else {
+				increment, err := parseAutogrowSize(setting)
+				if err != nil || increment == 0 {
+					return nil, fmt.Errorf("invalid autogrow increment '%s'", setting)
+				}
+				policy.IncrementBytes = increment
+				policy.IncrementPercent = 0
+			}
+		case strings.ToLower(AutogrowMaxSize):
+			maxSize, err := parseAutogrowSize(setting)
+			if err != nil {
+				return nil, fmt.Errorf("invalid autogrow maximum size '%s'", setting)
+			}
+			policy.MaxSizeBytes = maxSize
+		default:
+			return nil, fmt.Errorf("unknown autogrow rule '%s'; must be threshold, increment or maxSize", parts[0])
+		}
+	}
+
+	if policy.ThresholdPercent == 0 {
+		return nil, fmt.Errorf("autogrow policy '%s' must set a threshold", value)
+	}
+	if policy.IncrementBytes == 0 && policy.IncrementPercent == 0 {
+		policy.IncrementPercent = defaultAutogrowIncrementPercent
+	}
+
+	return policy, nil
+}
+
+func parseAutogrowSize(value string) (uint64, error) {
+	sizeBytes, err := utils.ConvertSizeToBytes(value)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(sizeBytes, 10, 64)
+}
+
+// IsEnabled reports whether the policy expands volumes.
+func (p *AutogrowPolicy) IsEnabled() bool {
+	return p != nil && p.ThresholdPercent > 0
+}
+
+func (p *AutogrowPolicy) String() string {
+	if !p.IsEnabled() {
+		return AutogrowNone
+	}
+	rules := []string{fmt.Sprintf("%s=%d", AutogrowThreshold, p.ThresholdPercent)}
+	if p.IncrementBytes > 0 {
+		rules = append(rules, fmt.Sprintf("%s=%d", AutogrowIncrement, p.IncrementBytes))
+	} else {
+		rules = append(rules, fmt.Sprintf("%s=%d%%", AutogrowIncrement, p.IncrementPercent))
+	}
+	if p.MaxSizeBytes > 0 {
+		rules = append(rules, fmt.Sprintf("%s=%d", AutogrowMaxSize, p.MaxSizeBytes))
+	}
+	return strings.Join(rules, ",")
+}
+
+// ShouldGrow reports whether a volume with the specified usage has reached the policy's threshold.
+func (p *AutogrowPolicy) ShouldGrow(usage *VolumeUsage) bool {
+	return p.IsEnabled() && usage.TotalBytes > 0 && usage.UsedPercent() >= p.ThresholdPercent
+}
+
+// NextSize returns the size to which a volume of the specified size should be expanded, limited
+// to the policy's maximum size.  If the volume can't be expanded further, its size is returned.
+func (p *AutogrowPolicy) NextSize(sizeBytes uint64) uint64 {
+
+	increment := p.IncrementBytes
+	if increment == 0 {
+		increment = sizeBytes * uint64(p.IncrementPercent) / 100
+		if increment == 0 {
+			increment = 1
+		}
+	}
+
+	newSize := sizeBytes + increment
+	if p.MaxSizeBytes > 0 && newSize > p.MaxSizeBytes {
+		newSize = p.MaxSizeBytes
+	}
+	if newSize < sizeBytes {
+		return sizeBytes
+	}
+	return newSize
+}
@@ -0,0 +1,63 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAutogrowPolicy(t *testing.T) {
+
+	policy, err := ParseAutogrowPolicy("threshold=80, increment=10Gi,MAXSIZE=1Ti")
+	assert.NoError(t, err)
+	assert.Equal(t, &AutogrowPolicy{ThresholdPercent: 80, IncrementBytes: 10737418240,
+		MaxSizeBytes: 1099511627776}, policy)
+	assert.Equal(t, "threshold=80,increment=10737418240,maxSize=1099511627776", policy.String())
+
+	policy, err = ParseAutogrowPolicy("threshold=90%,increment=25%")
+	assert.NoError(t, err)
+	assert.Equal(t, &AutogrowPolicy{ThresholdPercent: 90, IncrementPercent: 25}, policy)
+
+	policy, err = ParseAutogrowPolicy("threshold=75")
+	assert.NoError(t, err)
+	assert.Equal(t, &AutogrowPolicy{ThresholdPercent: 75, IncrementPercent: 10}, policy)
+
+	for _, value := range []string{"", "none"} {
+		policy, err = ParseAutogrowPolicy(value)
+		assert.NoError(t, err)
+		assert.False(t, policy.IsEnabled(), "expected '%s' to disable autogrow", value)
+		assert.Equal(t, AutogrowNone, policy.String())
+	}
+
+	for _, value := range []string{"threshold", "threshold=0", "threshold=100", "increment=10Gi",
+		"threshold=80,increment=0", "threshold=80,increment=x%", "threshold=80,maxSize=big", "minSize=1Gi"} {
+		_, err = ParseAutogrowPolicy(value)
+		assert.Error(t, err, "expected an error parsing '%s'", value)
+	}
+}
+
+func TestAutogrowPolicyShouldGrow(t *testing.T) {
+
+	policy := &AutogrowPolicy{ThresholdPercent: 80, IncrementPercent: 10}
+
+	assert.True(t, policy.ShouldGrow(&VolumeUsage{TotalBytes: 100, UsedBytes: 80}))
+	assert.False(t, policy.ShouldGrow(&VolumeUsage{TotalBytes: 100, UsedBytes: 79}))
+	assert.False(t, policy.ShouldGrow(&VolumeUsage{}))
+
+	var disabled *AutogrowPolicy
+	assert.False(t, disabled.ShouldGrow(&VolumeUsage{TotalBytes: 100, UsedBytes: 100}))
+}
+
+func TestAutogrowPolicyNextSize(t *testing.T) {
+
+	percent := &AutogrowPolicy{ThresholdPercent: 80, IncrementPercent: 10, MaxSizeBytes: 1150}
+	assert.Equal(t, uint64(1100), percent.NextSize(1000))
+	assert.Equal(t, uint64(1150), percent.NextSize(1100), "expansion should stop at the maximum size")
+	assert.Equal(t, uint64(1150), percent.NextSize(1150))
+	assert.Equal(t, uint64(2000), percent.NextSize(2000), "volumes beyond the maximum size shouldn't shrink")
+
+	bytes := &AutogrowPolicy{ThresholdPercent: 80, IncrementBytes: 500}
+	assert.Equal(t, uint64(1500), bytes.NextSize(1000))
+}
@@ -3,6 +3,7 @@
 package storage
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -13,6 +14,7 @@ import (
 	"github.com/cenkalti/backoff/v4"
 	"github.com/mitchellh/copystructure"
 	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/labels"
 
 	tridentconfig "github.com/netapp/trident/config"
 	sa "github.com/netapp/trident/storage_attribute"
@@ -27,7 +29,7 @@ type Driver interface {
 	Initialized() bool
 	// Terminate tells the driver to clean up, as it won't be called again.
 	Terminate(backendUUID string)
-	Create(volConfig *VolumeConfig, storagePool *Pool, volAttributes map[string]sa.Request) error
+	Create(ctx context.Context, volConfig *VolumeConfig, storagePool *Pool, volAttributes map[string]sa.Request) error
 	CreatePrepare(volConfig *VolumeConfig)
 	// CreateFollowup adds necessary information for accessing the volume to VolumeConfig.
 	CreateFollowup(volConfig *VolumeConfig) error
@@ -35,22 +37,24 @@ type Driver interface {
 	// constraints present on the backend and that will be unique to Trident.
 	// The latter requirement should generally be done by prepending the
 	// value of CommonStorageDriver.SnapshotPrefix to the name.
-	CreateClone(volConfig *VolumeConfig, storagePool *Pool) error
-	Import(volConfig *VolumeConfig, originalName string) error
-	Destroy(name string) error
+	CreateClone(ctx context.Context, volConfig *VolumeConfig, storagePool *Pool) error
+	Import(ctx context.Context, volConfig *VolumeConfig, originalName string) error
+	Destroy(ctx context.Context, name string) error
 	Rename(name string, newName string) error
-	Resize(volConfig *VolumeConfig, sizeBytes uint64) error
+	Resize(ctx context.Context, volConfig *VolumeConfig, sizeBytes uint64) error
+	// Get returns nil if the volume exists, and a NotFoundError if the storage system reports that
+	// it doesn't.
 	Get(name string) error
 	GetInternalVolumeName(name string) string
 	GetStorageBackendSpecs(backend *Backend) error
 	GetStorageBackendPhysicalPoolNames() []string
 	GetProtocol() tridentconfig.Protocol
-	Publish(volConfig *VolumeConfig, publishInfo *utils.VolumePublishInfo) error
+	Publish(ctx context.Context, volConfig *VolumeConfig, publishInfo *utils.VolumePublishInfo) error
 	GetSnapshot(snapConfig *SnapshotConfig) (*Snapshot, error)
 	GetSnapshots(volConfig *VolumeConfig) ([]*Snapshot, error)
-	CreateSnapshot(snapConfig *SnapshotConfig) (*Snapshot, error)
-	RestoreSnapshot(snapConfig *SnapshotConfig) error
-	DeleteSnapshot(snapConfig *SnapshotConfig) error
+	CreateSnapshot(ctx context.Context, snapConfig *SnapshotConfig) (*Snapshot, error)
+	RestoreSnapshot(ctx context.Context, snapConfig *SnapshotConfig) error
+	DeleteSnapshot(ctx context.Context, snapConfig *SnapshotConfig) error
 	StoreConfig(b *PersistentStorageBackendConfig)
 	// GetExternalConfig returns a version of the driver configuration that
 	// lacks confidential information, such as usernames and passwords.
@@ -66,6 +70,107 @@ type Driver interface {
 	ReconcileNodeAccess(nodes []*utils.Node, backendUUID string) error
 }
 
+// BackupDriver is implemented by drivers that can create backups stored outside of the backend,
+// such as AWS-native backups of FSx for NetApp ONTAP volumes.  Backups are snapshots of type
+// SnapshotTypeBackup, and like snapshots a nil result from GetBackup means the backup doesn't exist.
+type BackupDriver interface {
+	SupportsBackups() bool
+	GetBackup(snapConfig *SnapshotConfig) (*Snapshot, error)
+	CreateBackup(snapConfig *SnapshotConfig) (*Snapshot, error)
+	DeleteBackup(snapConfig *SnapshotConfig) error
+}
+
+// ReplicationDriver is implemented by drivers that can populate a new volume from a volume on another
+// backend using storage-native replication, such as SnapMirror between ONTAP backends.  A replica
+// is read-only until it is promoted, after which it is an independent volume on this driver's backend.
+type ReplicationDriver interface {
+	// CanReplicateFrom reports whether volumes managed by the source driver can be replicated to this driver.
+	CanReplicateFrom(source Driver) bool
+	CreateReplica(volConfig, sourceVolConfig *VolumeConfig, storagePool *Pool, source Driver) error
+	// GetReplicaProgress returns the number of bytes transferred to the replica and whether
+	// the baseline transfer is complete.
+	GetReplicaProgress(volConfig *VolumeConfig) (uint64, bool, error)
+	// PromoteReplica performs a final transfer from the source volume, ends the replication
+	// relationship, and makes the replica writable.
+	PromoteReplica(volConfig, sourceVolConfig *VolumeConfig, source Driver) error
+	DeleteReplica(volConfig, sourceVolConfig *VolumeConfig, source Driver) error
+}
+
+// VolumeMoveDriver is implemented by drivers that can move a volume between their storage pools while
+// the volume stays in use, such as ONTAP volume move between aggregates.  The storage system copies the
+// volume and cuts its clients over on its own, so a move that fails leaves the volume where it was.
+type VolumeMoveDriver interface {
+	MoveVolume(volConfig *VolumeConfig, storagePool *Pool) error
+	// GetVolumeMoveProgress returns the number of bytes moved, the number of bytes to move, and
+	// whether the move is complete.
+	GetVolumeMoveProgress(volConfig *VolumeConfig) (uint64, uint64, bool, error)
+}
+
+// GroupSnapshotDriver is implemented by drivers that can snapshot several of their volumes at the
+// same point in time, such as with ONTAP consistency group snapshots.  All snapshots in a group
+// have the same name.
+type GroupSnapshotDriver interface {
+	CreateGroupSnapshot(snapConfigs []*SnapshotConfig) ([]*Snapshot, error)
+}
+
+// CloneDependencyDriver is implemented by drivers whose clones may share storage with their source
+// volumes, such as ONTAP FlexClones that aren't split from their parents.  A source volume can't be
+// destroyed while such clones exist.  Drivers ask the storage, since a clone may have been split
+// after it was created.
+type CloneDependencyDriver interface {
+	CloneDependsOnSource(volConfig *VolumeConfig) (bool, error)
+}
+
+// VolumeUsageDriver is implemented by drivers that can report the space consumed by their volumes.
+type VolumeUsageDriver interface {
+	GetVolumeUsage(volConfig *VolumeConfig) (*VolumeUsage, error)
+}
+
+// VolumeConditionDriver is implemented by drivers that can detect problems with their volumes on the
+// storage system, such as an offline volume.
+type VolumeConditionDriver interface {
+	GetVolumeCondition(volConfig *VolumeConfig) (*VolumeCondition, error)
+}
+
+// StorageCapacityDriver is implemented by drivers that can report the free space behind their pools.
+type StorageCapacityDriver interface {
+	GetStorageCapacity() (*StorageCapacity, error)
+}
+
+// NodeAccessRevokingDriver is implemented by drivers that grant nodes access to volumes at publish
+// time, such as by adding a node's initiator to an igroup, and so can revoke the access of a node
+// that was shut down without unpublishing its volumes.
+type NodeAccessRevokingDriver interface {
+	RevokeNodeAccess(node *utils.Node, backendUUID string) error
+}
+
+// UnpublishDriver is implemented by drivers that grant each node access to a volume separately, such as
+// by mapping a LUN to the node's own igroup, and so must revoke that access when the volume is unpublished.
+type UnpublishDriver interface {
+	Unpublish(ctx context.Context, volConfig *VolumeConfig, publishInfo *utils.VolumePublishInfo) error
+}
+
+// ImportDryRunDriver is implemented by drivers that can check whether a volume may be imported, and
+// describe how importing it would change it, without changing anything on the storage system.
+type ImportDryRunDriver interface {
+	ImportCheck(volConfig *VolumeConfig, originalName string) ([]string, error)
+}
+
+// PoolCreatePrepareDriver is implemented by drivers whose internal volume names depend on the storage
+// pool a volume is created in, such as when a virtual pool sets its own storage prefix.
+type PoolCreatePrepareDriver interface {
+	CreatePrepareInPool(volConfig *VolumeConfig, pool *Pool)
+}
+
+// JournalingDriver is implemented by drivers that record the steps of multi-step operations, such as
+// creating a FlexVol and then a LUN within it, in the journal carried by the operation's context, so
+// that an operation interrupted partway can be undone step by step.
+type JournalingDriver interface {
+	// RollbackJournal undoes the journal's steps, latest first, and must tolerate steps that didn't
+	// take effect.
+	RollbackJournal(ctx context.Context, volConfig *VolumeConfig, journal *OperationJournal) error
+}
+
 type Backend struct {
 	Driver      Driver
 	Name        string
@@ -74,12 +179,21 @@ type Backend struct {
 	State       BackendState
 	Storage     map[string]*Pool
 	Volumes     map[string]*Volume
+	// AllowedNamespaces and NamespaceSelector restrict the namespaces the backend holds new volumes for
+	AllowedNamespaces []string
+	NamespaceSelector string
+	// Generation counts the updates to the backend, each of which replaces it with a new instance
+	Generation int64
 }
 
 type UpdateBackendStateRequest struct {
 	State string `json:"state"`
 }
 
+type UpdateBackendTracingRequest struct {
+	DebugTraceFlags map[string]bool `json:"debugTraceFlags"`
+}
+
 type NotManagedError struct {
 	volumeName string
 }
@@ -96,11 +210,15 @@ const (
 	Offline  = BackendState("offline")
 	Deleting = BackendState("deleting")
 	Failed   = BackendState("failed")
+	// Cordoned backends serve their existing volumes but don't accept new ones
+	Cordoned = BackendState("cordoned")
+	// Draining backends are cordoned while Trident migrates their volumes to other backends
+	Draining = BackendState("draining")
 )
 
 func (s BackendState) String() string {
 	switch s {
-	case Unknown, Online, Offline, Deleting, Failed:
+	case Unknown, Online, Offline, Deleting, Failed, Cordoned, Draining:
 		return string(s)
 	default:
 		return "unknown"
@@ -109,7 +227,7 @@ func (s BackendState) String() string {
 
 func (s BackendState) IsUnknown() bool {
 	switch s {
-	case Online, Offline, Deleting, Failed:
+	case Online, Offline, Deleting, Failed, Cordoned, Draining:
 		return false
 	case Unknown:
 		return true
@@ -134,6 +252,24 @@ func (s BackendState) IsFailed() bool {
 	return s == Failed
 }
 
+func (s BackendState) IsCordoned() bool {
+	return s == Cordoned
+}
+
+func (s BackendState) IsDraining() bool {
+	return s == Draining
+}
+
+// IsServing returns true if a backend in this state can serve its existing volumes.
+func (s BackendState) IsServing() bool {
+	return s == Online || s == Cordoned || s == Draining
+}
+
+// AcceptsNewVolumes returns true if volumes may be provisioned on a backend in this state.
+func (s BackendState) AcceptsNewVolumes() bool {
+	return s == Online
+}
+
 func NewStorageBackend(driver Driver) (*Backend, error) {
 	backend := Backend{
 		Driver:  driver,
@@ -184,7 +320,21 @@ func (b *Backend) GetProtocol() tridentconfig.Protocol {
 }
 
 func (b *Backend) AddVolume(
-	volConfig *VolumeConfig, storagePool *Pool, volAttributes map[string]sa.Request, retry bool,
+	ctx context.Context, volConfig *VolumeConfig, storagePool *Pool, volAttributes map[string]sa.Request, retry bool,
+) (*Volume, error) {
+
+	vol, err := b.CreateVolume(ctx, volConfig, storagePool, volAttributes, retry)
+	if err != nil {
+		return nil, err
+	}
+	b.AddCachedVolume(vol)
+	return vol, nil
+}
+
+// CreateVolume creates a volume on the backend without adding it to the backend's volumes, so
+// it may run concurrently with other operations that read them.
+func (b *Backend) CreateVolume(
+	ctx context.Context, volConfig *VolumeConfig, storagePool *Pool, volAttributes map[string]sa.Request, retry bool,
 ) (*Volume, error) {
 
 	var err error
@@ -200,7 +350,12 @@ func (b *Backend) AddVolume(
 	}).Debug("Attempting volume create.")
 
 	// Ensure backend is ready
-	if err := b.ensureOnline(); err != nil {
+	if err := b.ensureAcceptsNewVolumes(); err != nil {
+		return nil, err
+	}
+
+	// Ensure backend may hold volumes for the namespace
+	if err := b.ensureServesNamespace(volConfig); err != nil {
 		return nil, err
 	}
 
@@ -211,7 +366,7 @@ func (b *Backend) AddVolume(
 
 	// Add volume to the backend
 	volumeExists := false
-	if err = b.Driver.Create(volConfig, storagePool, volAttributes); err != nil {
+	if err = b.Driver.Create(ctx, volConfig, storagePool, volAttributes); err != nil {
 
 		if drivers.IsVolumeExistsError(err) {
 
@@ -239,7 +394,8 @@ func (b *Backend) AddVolume(
 			"retry":        retry,
 		}).Errorf("CreateFollowup failed; %v", err)
 
-		// If follow-up fails and we just created the volume, clean up by deleting it
+		// If follow-up fails and we just created the volume, clean up by deleting it.  The cleanup
+		// doesn't use the caller's context, which may be why the follow-up failed.
 		if !volumeExists || retry {
 
 			log.WithFields(log.Fields{
@@ -247,7 +403,7 @@ func (b *Backend) AddVolume(
 				"volume":  volConfig.InternalName,
 			}).Errorf("CreateFollowup failed for newly created volume, deleting the volume.")
 
-			errDestroy := b.Driver.Destroy(volConfig.InternalName)
+			errDestroy := b.Driver.Destroy(context.Background(), volConfig.InternalName)
 			if errDestroy != nil {
 				log.WithFields(log.Fields{
 					"backend": b.Name,
@@ -263,12 +419,12 @@ func (b *Backend) AddVolume(
 		return nil, err
 	}
 
-	vol := NewVolume(volConfig, b.BackendUUID, storagePool.Name, false)
-	b.Volumes[vol.Config.Name] = vol
-	return vol, nil
+	return NewVolume(volConfig, b.BackendUUID, storagePool.Name, false), nil
 }
 
-func (b *Backend) CloneVolume(volConfig *VolumeConfig, storagePool *Pool, retry bool) (*Volume, error) {
+func (b *Backend) CloneVolume(
+	ctx context.Context, volConfig *VolumeConfig, storagePool *Pool, retry bool,
+) (*Volume, error) {
 
 	log.WithFields(log.Fields{
 		"backend":                volConfig.Name,
@@ -287,7 +443,12 @@ func (b *Backend) CloneVolume(volConfig *VolumeConfig, storagePool *Pool, retry
 	}
 
 	// Ensure backend is ready
-	if err := b.ensureOnline(); err != nil {
+	if err := b.ensureAcceptsNewVolumes(); err != nil {
+		return nil, err
+	}
+
+	// Ensure backend may hold volumes for the namespace
+	if err := b.ensureServesNamespace(volConfig); err != nil {
 		return nil, err
 	}
 
@@ -301,7 +462,7 @@ func (b *Backend) CloneVolume(volConfig *VolumeConfig, storagePool *Pool, retry
 
 	// Clone volume on the backend
 	volumeExists := false
-	if err := b.Driver.CreateClone(volConfig, storagePool); err != nil {
+	if err := b.Driver.CreateClone(ctx, volConfig, storagePool); err != nil {
 
 		if drivers.IsVolumeExistsError(err) {
 
@@ -333,7 +494,8 @@ func (b *Backend) CloneVolume(volConfig *VolumeConfig, storagePool *Pool, retry
 	cloneBackoff.MaxElapsedTime = 90 * time.Second
 
 	// Run the clone check using an exponential backoff
-	if err := backoff.RetryNotify(checkCloneExists, cloneBackoff, cloneExistsNotify); err != nil {
+	err := backoff.RetryNotify(checkCloneExists, backoff.WithContext(cloneBackoff, ctx), cloneExistsNotify)
+	if err != nil {
 		log.WithField("clone_volume", volConfig.Name).Warnf("Could not find clone after %3.2f seconds.",
 			float64(cloneBackoff.MaxElapsedTime))
 	} else {
@@ -342,9 +504,10 @@ func (b *Backend) CloneVolume(volConfig *VolumeConfig, storagePool *Pool, retry
 
 	if err := b.Driver.CreateFollowup(volConfig); err != nil {
 
-		// If follow-up fails and we just created the volume, clean up by deleting it
+		// If follow-up fails and we just created the volume, clean up by deleting it.  The cleanup
+		// doesn't use the caller's context, which may be why the follow-up failed.
 		if !volumeExists || retry {
-			errDestroy := b.Driver.Destroy(volConfig.InternalName)
+			errDestroy := b.Driver.Destroy(context.Background(), volConfig.InternalName)
 			if errDestroy != nil {
 				log.WithFields(log.Fields{
 					"backend": b.Name,
@@ -374,7 +537,9 @@ func (b *Backend) CloneVolume(volConfig *VolumeConfig, storagePool *Pool, retry
 	return vol, nil
 }
 
-func (b *Backend) PublishVolume(volConfig *VolumeConfig, publishInfo *utils.VolumePublishInfo) error {
+func (b *Backend) PublishVolume(
+	ctx context.Context, volConfig *VolumeConfig, publishInfo *utils.VolumePublishInfo,
+) error {
 
 	log.WithFields(log.Fields{
 		"backend":        b.Name,
@@ -388,7 +553,294 @@ func (b *Backend) PublishVolume(volConfig *VolumeConfig, publishInfo *utils.Volu
 		return err
 	}
 
-	return b.Driver.Publish(volConfig, publishInfo)
+	return b.Driver.Publish(ctx, volConfig, publishInfo)
+}
+
+// UnpublishVolume revokes a node's access to a volume, if the backend's driver grants access per node.
+func (b *Backend) UnpublishVolume(
+	ctx context.Context, volConfig *VolumeConfig, publishInfo *utils.VolumePublishInfo,
+) error {
+
+	log.WithFields(log.Fields{
+		"backend":        b.Name,
+		"backendUUID":    b.BackendUUID,
+		"volume":         volConfig.Name,
+		"volumeInternal": volConfig.InternalName,
+		"node":           publishInfo.HostName,
+	}).Debug("Attempting volume unpublish.")
+
+	unpublishDriver, ok := b.Driver.(UnpublishDriver)
+	if !ok {
+		return nil
+	}
+
+	// Ensure backend is ready
+	if err := b.ensureOnlineOrDeleting(); err != nil {
+		return err
+	}
+
+	return unpublishDriver.Unpublish(ctx, volConfig, publishInfo)
+}
+
+// getReplicationDriver returns the backend's driver as a ReplicationDriver, if it can replicate
+// volumes from the source backend.
+func (b *Backend) getReplicationDriver(source *Backend) (ReplicationDriver, error) {
+	if replicationDriver, ok := b.Driver.(ReplicationDriver); ok && replicationDriver.CanReplicateFrom(source.Driver) {
+		return replicationDriver, nil
+	}
+	return nil, fmt.Errorf("backend %s cannot replicate volumes from backend %s", b.Name, source.Name)
+}
+
+// CanReplicateFrom reports whether volumes on the source backend can be replicated to this backend.
+func (b *Backend) CanReplicateFrom(source *Backend) bool {
+	_, err := b.getReplicationDriver(source)
+	return err == nil
+}
+
+// CreateReplica creates a volume on this backend and starts replicating the source volume to it.
+// The replica is not added to the backend's volumes until it is promoted.
+func (b *Backend) CreateReplica(
+	volConfig, sourceVolConfig *VolumeConfig, storagePool *Pool, source *Backend,
+) error {
+
+	log.WithFields(log.Fields{
+		"backend":               b.Name,
+		"volume":                volConfig.Name,
+		"volumeInternal":        volConfig.InternalName,
+		"storage_pool":          storagePool.Name,
+		"source_backend":        source.Name,
+		"source_volumeInternal": sourceVolConfig.InternalName,
+	}).Debug("Attempting replica create.")
+
+	// Ensure backend is ready
+	if err := b.ensureAcceptsNewVolumes(); err != nil {
+		return err
+	}
+
+	// Ensure backend may hold volumes for the namespace
+	if err := b.ensureServesNamespace(volConfig); err != nil {
+		return err
+	}
+
+	// Ensure the internal name exists
+	if volConfig.InternalName == "" {
+		return errors.New("internal name not set")
+	}
+
+	replicationDriver, err := b.getReplicationDriver(source)
+	if err != nil {
+		return err
+	}
+
+	return replicationDriver.CreateReplica(volConfig, sourceVolConfig, storagePool, source.Driver)
+}
+
+// GetReplicaProgress returns the number of bytes replicated to a replica on this backend and
+// whether the baseline transfer is complete.
+func (b *Backend) GetReplicaProgress(volConfig *VolumeConfig, source *Backend) (uint64, bool, error) {
+
+	// Ensure backend is ready
+	if err := b.ensureOnline(); err != nil {
+		return 0, false, err
+	}
+
+	replicationDriver, err := b.getReplicationDriver(source)
+	if err != nil {
+		return 0, false, err
+	}
+
+	return replicationDriver.GetReplicaProgress(volConfig)
+}
+
+// PromoteReplica makes a replica on this backend writable and independent of its source volume,
+// and it completes the replica's volume config with the information needed to access it.
+func (b *Backend) PromoteReplica(volConfig, sourceVolConfig *VolumeConfig, source *Backend) error {
+
+	log.WithFields(log.Fields{
+		"backend":        b.Name,
+		"volume":         volConfig.Name,
+		"volumeInternal": volConfig.InternalName,
+		"source_backend": source.Name,
+	}).Debug("Attempting replica promotion.")
+
+	// Ensure backend is ready
+	if err := b.ensureOnline(); err != nil {
+		return err
+	}
+
+	replicationDriver, err := b.getReplicationDriver(source)
+	if err != nil {
+		return err
+	}
+
+	if err = replicationDriver.PromoteReplica(volConfig, sourceVolConfig, source.Driver); err != nil {
+		return err
+	}
+
+	return b.Driver.CreateFollowup(volConfig)
+}
+
+// DeleteReplica ends replication to a replica on this backend and destroys the replica.
+func (b *Backend) DeleteReplica(volConfig, sourceVolConfig *VolumeConfig, source *Backend) error {
+
+	log.WithFields(log.Fields{
+		"backend":        b.Name,
+		"volume":         volConfig.Name,
+		"volumeInternal": volConfig.InternalName,
+		"source_backend": source.Name,
+	}).Debug("Attempting replica delete.")
+
+	// Ensure backend is ready
+	if err := b.ensureOnlineOrDeleting(); err != nil {
+		return err
+	}
+
+	replicationDriver, err := b.getReplicationDriver(source)
+	if err != nil {
+		return err
+	}
+
+	return replicationDriver.DeleteReplica(volConfig, sourceVolConfig, source.Driver)
+}
+
+// CanMoveVolumes reports whether the backend can move its volumes between its storage pools.
+func (b *Backend) CanMoveVolumes() bool {
+	_, ok := b.Driver.(VolumeMoveDriver)
+	return ok
+}
+
+// MoveVolume starts moving a volume on this backend to another of the backend's storage pools.
+func (b *Backend) MoveVolume(volConfig *VolumeConfig, storagePool *Pool) error {
+
+	log.WithFields(log.Fields{
+		"backend":        b.Name,
+		"volume":         volConfig.Name,
+		"volumeInternal": volConfig.InternalName,
+		"storage_pool":   storagePool.Name,
+	}).Debug("Attempting volume move.")
+
+	// Ensure backend is ready
+	if err := b.ensureOnline(); err != nil {
+		return err
+	}
+
+	moveDriver, ok := b.Driver.(VolumeMoveDriver)
+	if !ok {
+		return fmt.Errorf("backend %s cannot move volumes between its storage pools", b.Name)
+	}
+
+	return moveDriver.MoveVolume(volConfig, storagePool)
+}
+
+// GetVolumeMoveProgress returns the number of bytes of a volume moved within this backend, the number
+// of bytes to move, and whether the move is complete.
+func (b *Backend) GetVolumeMoveProgress(volConfig *VolumeConfig) (uint64, uint64, bool, error) {
+
+	// Ensure backend is ready
+	if err := b.ensureOnline(); err != nil {
+		return 0, 0, false, err
+	}
+
+	moveDriver, ok := b.Driver.(VolumeMoveDriver)
+	if !ok {
+		return 0, 0, false, fmt.Errorf("backend %s cannot move volumes between its storage pools", b.Name)
+	}
+
+	return moveDriver.GetVolumeMoveProgress(volConfig)
+}
+
+// CloneDependsOnSource reports whether a clone on this backend shares storage with its source volume,
+// so the source must outlive it.
+func (b *Backend) CloneDependsOnSource(volume *Volume) (bool, error) {
+	if volume.Config.CloneSourceVolume == "" {
+		return false, nil
+	}
+	dependencyDriver, ok := b.Driver.(CloneDependencyDriver)
+	if !ok {
+		return false, nil
+	}
+	return dependencyDriver.CloneDependsOnSource(volume.Config)
+}
+
+// CanReportVolumeUsage reports whether the backend's driver can report the space consumed by its volumes.
+func (b *Backend) CanReportVolumeUsage() bool {
+	_, ok := b.Driver.(VolumeUsageDriver)
+	return ok
+}
+
+// GetVolumeUsage returns the space consumed by a volume on this backend, as reported by the storage system.
+func (b *Backend) GetVolumeUsage(volConfig *VolumeConfig) (*VolumeUsage, error) {
+
+	// Ensure backend is ready
+	if err := b.ensureOnline(); err != nil {
+		return nil, err
+	}
+
+	usageDriver, ok := b.Driver.(VolumeUsageDriver)
+	if !ok {
+		return nil, fmt.Errorf("backend %s cannot report volume usage", b.Name)
+	}
+
+	usage, err := usageDriver.GetVolumeUsage(volConfig)
+	if err != nil {
+		return nil, err
+	}
+	usage.VolumeName = volConfig.Name
+	usage.Source = VolumeUsageSourceDriver
+	usage.Updated = time.Now().UTC().Format(time.RFC3339)
+	return usage, nil
+}
+
+// CanReportVolumeCondition reports whether the backend's driver can detect problems with its volumes.
+func (b *Backend) CanReportVolumeCondition() bool {
+	_, ok := b.Driver.(VolumeConditionDriver)
+	return ok
+}
+
+// GetVolumeCondition returns the condition of a volume on this backend, as reported by the storage system.
+func (b *Backend) GetVolumeCondition(volConfig *VolumeConfig) (*VolumeCondition, error) {
+
+	// Ensure backend is ready
+	if err := b.ensureOnline(); err != nil {
+		return nil, err
+	}
+
+	conditionDriver, ok := b.Driver.(VolumeConditionDriver)
+	if !ok {
+		return nil, fmt.Errorf("backend %s cannot report volume condition", b.Name)
+	}
+
+	condition, err := conditionDriver.GetVolumeCondition(volConfig)
+	if err != nil {
+		return nil, err
+	}
+	condition.VolumeName = volConfig.Name
+	condition.Source = VolumeUsageSourceDriver
+	condition.Node = ""
+	condition.Updated = time.Now().UTC().Format(time.RFC3339)
+	return condition, nil
+}
+
+// CanReportStorageCapacity reports whether the backend's driver can report the free space behind its pools.
+func (b *Backend) CanReportStorageCapacity() bool {
+	_, ok := b.Driver.(StorageCapacityDriver)
+	return ok
+}
+
+// GetStorageCapacity returns the free space behind this backend's pools, as reported by the storage system.
+func (b *Backend) GetStorageCapacity() (*StorageCapacity, error) {
+
+	// Ensure backend is ready
+	if err := b.ensureOnline(); err != nil {
+		return nil, err
+	}
+
+	capacityDriver, ok := b.Driver.(StorageCapacityDriver)
+	if !ok {
+		return nil, fmt.Errorf("backend %s cannot report storage capacity", b.Name)
+	}
+
+	return capacityDriver.GetStorageCapacity()
 }
 
 func (b *Backend) GetVolumeExternal(volumeName string) (*VolumeExternal, error) {
@@ -411,7 +863,19 @@ func (b *Backend) GetVolumeExternal(volumeName string) (*VolumeExternal, error)
 	return volExternal, nil
 }
 
-func (b *Backend) ImportVolume(volConfig *VolumeConfig) (*Volume, error) {
+// CreatePrepare sets the internal name of a volume about to be created in a pool of this backend.
+// The pool may be nil if the volume isn't created in a particular pool.
+func (b *Backend) CreatePrepare(volConfig *VolumeConfig, pool *Pool) {
+	if poolDriver, ok := b.Driver.(PoolCreatePrepareDriver); ok && pool != nil {
+		poolDriver.CreatePrepareInPool(volConfig, pool)
+		return
+	}
+	b.Driver.CreatePrepare(volConfig)
+}
+
+// ImportVolume brings a volume under Trident's management.  A managed volume is renamed as if it
+// were created in the given pool, which may be nil.
+func (b *Backend) ImportVolume(ctx context.Context, volConfig *VolumeConfig, pool *Pool) (*Volume, error) {
 
 	log.WithFields(log.Fields{
 		"backend":    b.Name,
@@ -424,15 +888,20 @@ func (b *Backend) ImportVolume(volConfig *VolumeConfig) (*Volume, error) {
 		return nil, err
 	}
 
+	// Ensure backend may hold volumes for the namespace
+	if err := b.ensureServesNamespace(volConfig); err != nil {
+		return nil, err
+	}
+
 	if volConfig.ImportNotManaged {
 		// The volume is not managed and will not be renamed during import.
 		volConfig.InternalName = volConfig.ImportOriginalName
 	} else {
 		// Sanitize the volume name
-		b.Driver.CreatePrepare(volConfig)
+		b.CreatePrepare(volConfig, pool)
 	}
 
-	err := b.Driver.Import(volConfig, volConfig.ImportOriginalName)
+	err := b.Driver.Import(ctx, volConfig, volConfig.ImportOriginalName)
 	if err != nil {
 		return nil, fmt.Errorf("driver import volume failed: %v", err)
 	}
@@ -447,7 +916,89 @@ func (b *Backend) ImportVolume(volConfig *VolumeConfig) (*Volume, error) {
 	return volume, nil
 }
 
-func (b *Backend) ResizeVolume(volConfig *VolumeConfig, newSize string) error {
+// ImportVolumeDryRun runs the driver's checks for importing a volume and returns the changes the
+// import would make to it, without renaming or otherwise changing the volume.  A managed volume
+// would be renamed as if it were created in the given pool, which may be nil.
+func (b *Backend) ImportVolumeDryRun(volConfig *VolumeConfig, pool *Pool) ([]string, error) {
+
+	log.WithFields(log.Fields{
+		"backend":    b.Name,
+		"volume":     volConfig.ImportOriginalName,
+		"NotManaged": volConfig.ImportNotManaged,
+	}).Debug("Backend#ImportVolumeDryRun")
+
+	// Ensure backend is ready
+	if err := b.ensureOnline(); err != nil {
+		return nil, err
+	}
+
+	// Ensure backend may hold volumes for the namespace
+	if err := b.ensureServesNamespace(volConfig); err != nil {
+		return nil, err
+	}
+
+	dryRunDriver, ok := b.Driver.(ImportDryRunDriver)
+	if !ok {
+		return nil, fmt.Errorf("backend %s does not support volume import dry runs", b.Name)
+	}
+
+	if volConfig.ImportNotManaged {
+		// The volume is not managed and will not be renamed during import.
+		volConfig.InternalName = volConfig.ImportOriginalName
+	} else {
+		// Sanitize the volume name
+		b.CreatePrepare(volConfig, pool)
+	}
+
+	changes, err := dryRunDriver.ImportCheck(volConfig, volConfig.ImportOriginalName)
+	if err != nil {
+		return nil, fmt.Errorf("driver import volume check failed: %v", err)
+	}
+
+	return changes, nil
+}
+
+// UnmanageVolume renames a volume's storage as Trident gives it up, and returns the name the storage
+// is left with.  Unless storageName is set, an imported volume is renamed back to its original name
+// and other volumes keep their internal names.  The volume is removed from the backend's volumes.
+func (b *Backend) UnmanageVolume(volConfig *VolumeConfig, storageName string) (string, error) {
+
+	log.WithFields(log.Fields{
+		"backend":        b.Name,
+		"volume":         volConfig.Name,
+		"volumeInternal": volConfig.InternalName,
+		"storageName":    storageName,
+	}).Debug("Backend#UnmanageVolume")
+
+	if storageName == "" {
+		storageName = volConfig.InternalName
+		if volConfig.ImportOriginalName != "" {
+			storageName = volConfig.ImportOriginalName
+		}
+	}
+
+	if storageName != volConfig.InternalName {
+		// Ensure volume is managed
+		if volConfig.ImportNotManaged {
+			return "", &NotManagedError{volConfig.InternalName}
+		}
+
+		// Ensure backend is ready
+		if err := b.ensureOnline(); err != nil {
+			return "", err
+		}
+
+		if err := b.Driver.Rename(volConfig.InternalName, storageName); err != nil {
+			return "", fmt.Errorf("could not rename volume %s to %s; %v", volConfig.InternalName,
+				storageName, err)
+		}
+	}
+
+	b.RemoveCachedVolume(volConfig.Name)
+	return storageName, nil
+}
+
+func (b *Backend) ResizeVolume(ctx context.Context, volConfig *VolumeConfig, newSize string) error {
 
 	// Ensure volume is managed
 	if volConfig.ImportNotManaged {
@@ -474,7 +1025,7 @@ func (b *Backend) ResizeVolume(volConfig *VolumeConfig, newSize string) error {
 		"volume":      volConfig.InternalName,
 		"volume_size": newSizeBytes,
 	}).Debug("Attempting volume resize.")
-	return b.Driver.Resize(volConfig, newSizeBytes)
+	return b.Driver.Resize(ctx, volConfig, newSizeBytes)
 }
 
 func (b *Backend) RenameVolume(volConfig *VolumeConfig, newName string) error {
@@ -486,12 +1037,8 @@ func (b *Backend) RenameVolume(volConfig *VolumeConfig, newName string) error {
 		return &NotManagedError{oldName}
 	}
 
-	if b.State != Online {
-		log.WithFields(log.Fields{
-			"state":         b.State,
-			"expectedState": string(Online),
-		}).Error("Invalid backend state.")
-		return fmt.Errorf("backend %s is not Online", b.Name)
+	if err := b.ensureOnline(); err != nil {
+		return err
 	}
 
 	if err := b.Driver.Get(oldName); err != nil {
@@ -503,7 +1050,18 @@ func (b *Backend) RenameVolume(volConfig *VolumeConfig, newName string) error {
 	return nil
 }
 
-func (b *Backend) RemoveVolume(volConfig *VolumeConfig) error {
+func (b *Backend) RemoveVolume(ctx context.Context, volConfig *VolumeConfig) error {
+
+	if err := b.DestroyVolume(ctx, volConfig); err != nil {
+		return err
+	}
+	b.RemoveCachedVolume(volConfig.Name)
+	return nil
+}
+
+// DestroyVolume destroys a volume on the backend without removing it from the backend's volumes,
+// so it may run concurrently with other operations that read them.
+func (b *Backend) DestroyVolume(ctx context.Context, volConfig *VolumeConfig) error {
 
 	log.WithFields(log.Fields{
 		"backend":        b.Name,
@@ -511,8 +1069,8 @@ func (b *Backend) RemoveVolume(volConfig *VolumeConfig) error {
 		"volumeInternal": volConfig.InternalName,
 	}).Debug("Backend#RemoveVolume")
 
-	// Ensure volume is managed
-	if volConfig.ImportNotManaged {
+	// Ensure volume is managed, and that its storage should be destroyed
+	if volConfig.ImportNotManaged || volConfig.DeletionPolicy == DeletionPolicyUnmanage {
 		return &NotManagedError{volConfig.InternalName}
 	}
 
@@ -521,15 +1079,40 @@ func (b *Backend) RemoveVolume(volConfig *VolumeConfig) error {
 		return err
 	}
 
-	if err := b.Driver.Destroy(volConfig.InternalName); err != nil {
-		// TODO:  Check the error being returned once the nDVP throws errors
-		// for volumes that aren't found.
+	// TODO:  Check the error being returned once the nDVP throws errors
+	// for volumes that aren't found.
+	return b.Driver.Destroy(ctx, volConfig.InternalName)
+}
+
+// RollbackVolume undoes the journaled steps of an interrupted volume create.  Drivers that don't
+// journal their steps destroy the volume instead, which is likewise safe if it doesn't exist.
+func (b *Backend) RollbackVolume(ctx context.Context, volConfig *VolumeConfig, journal *OperationJournal) error {
+
+	log.WithFields(log.Fields{
+		"backend":        b.Name,
+		"volume":         volConfig.Name,
+		"volumeInternal": volConfig.InternalName,
+	}).Debug("Backend#RollbackVolume")
+
+	journalingDriver, ok := b.Driver.(JournalingDriver)
+	if !ok || journal == nil {
+		return b.RemoveVolume(ctx, volConfig)
+	}
+
+	if err := b.ensureOnlineOrDeleting(); err != nil {
+		return err
+	}
+	if err := journalingDriver.RollbackJournal(ctx, volConfig, journal); err != nil {
 		return err
 	}
 	b.RemoveCachedVolume(volConfig.Name)
 	return nil
 }
 
+func (b *Backend) AddCachedVolume(volume *Volume) {
+	b.Volumes[volume.Config.Name] = volume
+}
+
 func (b *Backend) RemoveCachedVolume(volumeName string) {
 
 	if _, ok := b.Volumes[volumeName]; ok {
@@ -551,7 +1134,7 @@ func (b *Backend) GetSnapshot(snapConfig *SnapshotConfig) (*Snapshot, error) {
 		return nil, err
 	}
 
-	if snapshot, err := b.Driver.GetSnapshot(snapConfig); err != nil {
+	if snapshot, err := b.getDriverSnapshot(snapConfig); err != nil {
 		// An error here means we couldn't check for the snapshot.  It does not mean the snapshot doesn't exist.
 		return nil, err
 	} else if snapshot == nil {
@@ -578,7 +1161,9 @@ func (b *Backend) GetSnapshots(volConfig *VolumeConfig) ([]*Snapshot, error) {
 	return b.Driver.GetSnapshots(volConfig)
 }
 
-func (b *Backend) CreateSnapshot(snapConfig *SnapshotConfig, volConfig *VolumeConfig) (*Snapshot, error) {
+func (b *Backend) CreateSnapshot(
+	ctx context.Context, snapConfig *SnapshotConfig, volConfig *VolumeConfig,
+) (*Snapshot, error) {
 
 	log.WithFields(log.Fields{
 		"backend":        b.Name,
@@ -602,7 +1187,7 @@ func (b *Backend) CreateSnapshot(snapConfig *SnapshotConfig, volConfig *VolumeCo
 	snapConfig.InternalName = snapConfig.Name
 
 	// Implement idempotency by checking for the snapshot first
-	if existingSnapshot, err := b.Driver.GetSnapshot(snapConfig); err != nil {
+	if existingSnapshot, err := b.getDriverSnapshot(snapConfig); err != nil {
 
 		// An error here means we couldn't check for the snapshot.  It does not mean the snapshot doesn't exist.
 		return nil, err
@@ -619,11 +1204,97 @@ func (b *Backend) CreateSnapshot(snapConfig *SnapshotConfig, volConfig *VolumeCo
 		return existingSnapshot, nil
 	}
 
+	// Create backup
+	if snapConfig.IsBackup() {
+		backupDriver, err := b.getBackupDriver()
+		if err != nil {
+			return nil, err
+		}
+		return backupDriver.CreateBackup(snapConfig)
+	}
+
 	// Create snapshot
-	return b.Driver.CreateSnapshot(snapConfig)
+	return b.Driver.CreateSnapshot(ctx, snapConfig)
+}
+
+// CanCreateGroupSnapshot reports whether the backend's driver can snapshot several volumes at the same
+// point in time.
+func (b *Backend) CanCreateGroupSnapshot() bool {
+	_, ok := b.Driver.(GroupSnapshotDriver)
+	return ok
+}
+
+// CreateGroupSnapshot snapshots several volumes on this backend at the same point in time.  The
+// snapshot configs and volume configs are matched by index, and all snapshots must have the same name.
+func (b *Backend) CreateGroupSnapshot(snapConfigs []*SnapshotConfig, volConfigs []*VolumeConfig) ([]*Snapshot, error) {
+
+	log.WithFields(log.Fields{
+		"backend": b.Name,
+		"volumes": len(volConfigs),
+	}).Debug("Attempting group snapshot create.")
+
+	if len(snapConfigs) == 0 || len(snapConfigs) != len(volConfigs) {
+		return nil, fmt.Errorf("a group snapshot requires one snapshot config per volume")
+	}
+
+	// Ensure volumes are managed
+	for _, volConfig := range volConfigs {
+		if volConfig.ImportNotManaged {
+			return nil, &NotManagedError{volConfig.InternalName}
+		}
+	}
+
+	// Ensure backend is ready
+	if err := b.ensureOnline(); err != nil {
+		return nil, err
+	}
+
+	groupDriver, ok := b.Driver.(GroupSnapshotDriver)
+	if !ok {
+		return nil, fmt.Errorf("backend %s does not support group snapshots", b.Name)
+	}
+
+	for _, snapConfig := range snapConfigs {
+		if snapConfig.Name != snapConfigs[0].Name {
+			return nil, fmt.Errorf("snapshots in a group must have the same name")
+		}
+		if snapConfig.IsBackup() {
+			return nil, fmt.Errorf("backups cannot be created as a group")
+		}
+		snapConfig.InternalName = snapConfig.Name
+
+		// A group snapshot can't be partially present, so refuse to overwrite any existing snapshot
+		if existingSnapshot, err := b.Driver.GetSnapshot(snapConfig); err != nil {
+			return nil, err
+		} else if existingSnapshot != nil {
+			return nil, fmt.Errorf("snapshot %s already exists on volume %s", snapConfig.Name, snapConfig.VolumeName)
+		}
+	}
+
+	return groupDriver.CreateGroupSnapshot(snapConfigs)
+}
+
+// getBackupDriver returns the backend's driver as a BackupDriver, if it supports backups.
+func (b *Backend) getBackupDriver() (BackupDriver, error) {
+	if backupDriver, ok := b.Driver.(BackupDriver); ok && backupDriver.SupportsBackups() {
+		return backupDriver, nil
+	}
+	return nil, fmt.Errorf("backend %s does not support backups", b.Name)
+}
+
+// getDriverSnapshot reads a snapshot or backup from the driver, depending on the snapshot type.
+func (b *Backend) getDriverSnapshot(snapConfig *SnapshotConfig) (*Snapshot, error) {
+	if snapConfig.IsBackup() {
+		backupDriver, err := b.getBackupDriver()
+		if err != nil {
+			return nil, err
+		}
+		return backupDriver.GetBackup(snapConfig)
+	}
+	return b.Driver.GetSnapshot(snapConfig)
 }
 
-func (b *Backend) RestoreSnapshot(snapConfig *SnapshotConfig, volConfig *VolumeConfig) error {
+func (b *Backend) RestoreSnapshot(ctx context.Context, snapConfig *SnapshotConfig, volConfig *VolumeConfig) error {
 
 	log.WithFields(log.Fields{
 		"backend":        b.Name,
@@ -642,11 +1313,16 @@ func (b *Backend) RestoreSnapshot(snapConfig *SnapshotConfig, volConfig *VolumeC
 		return err
 	}
 
+	// Backups live outside the backend, so they cannot be restored in place
+	if snapConfig.IsBackup() {
+		return fmt.Errorf("backup %s cannot be restored in place", snapConfig.Name)
+	}
+
 	// Restore snapshot
-	return b.Driver.RestoreSnapshot(snapConfig)
+	return b.Driver.RestoreSnapshot(ctx, snapConfig)
 }
 
-func (b *Backend) DeleteSnapshot(snapConfig *SnapshotConfig, volConfig *VolumeConfig) error {
+func (b *Backend) DeleteSnapshot(ctx context.Context, snapConfig *SnapshotConfig, volConfig *VolumeConfig) error {
 
 	log.WithFields(log.Fields{
 		"backend":        b.Name,
@@ -666,7 +1342,7 @@ func (b *Backend) DeleteSnapshot(snapConfig *SnapshotConfig, volConfig *VolumeCo
 	}
 
 	// Implement idempotency by checking for the snapshot first
-	if existingSnapshot, err := b.Driver.GetSnapshot(snapConfig); err != nil {
+	if existingSnapshot, err := b.getDriverSnapshot(snapConfig); err != nil {
 
 		// An error here means we couldn't check for the snapshot.  It does not mean the snapshot doesn't exist.
 		return err
@@ -683,8 +1359,17 @@ func (b *Backend) DeleteSnapshot(snapConfig *SnapshotConfig, volConfig *VolumeCo
 		return nil
 	}
 
+	// Delete backup
+	if snapConfig.IsBackup() {
+		backupDriver, err := b.getBackupDriver()
+		if err != nil {
+			return err
+		}
+		return backupDriver.DeleteBackup(snapConfig)
+	}
+
 	// Delete snapshot
-	return b.Driver.DeleteSnapshot(snapConfig)
+	return b.Driver.DeleteSnapshot(ctx, snapConfig)
 }
 
 const (
@@ -693,6 +1378,7 @@ const (
 	InvalidUpdate
 	UsernameChange
 	PasswordChange
+	PrefixChange
 )
 
 func (b *Backend) GetUpdateType(origBackend *Backend) *roaring.Bitmap {
@@ -733,14 +1419,28 @@ func (b *Backend) Terminate() {
 // to its volumes from active nodes in the k8s cluster. This is usually
 // handled via export policies or initiators
 func (b *Backend) ReconcileNodeAccess(nodes []*utils.Node) error {
-	if b.State == Online || b.State == Deleting {
+	if b.State.IsServing() || b.State.IsDeleting() {
 		return b.Driver.ReconcileNodeAccess(nodes, b.BackendUUID)
 	}
 	return nil
 }
 
+// RevokeNodeAccess removes a node's access to all volumes on this backend, if its driver grants
+// access at publish time.
+func (b *Backend) RevokeNodeAccess(node *utils.Node) error {
+	if !b.State.IsServing() && !b.State.IsDeleting() {
+		return nil
+	}
+	if revokingDriver, ok := b.Driver.(NodeAccessRevokingDriver); ok {
+		return revokingDriver.RevokeNodeAccess(node, b.BackendUUID)
+	}
+	return nil
+}
+
+// ensureOnline returns an error unless the backend can serve its volumes.  Cordoned and draining
+// backends are online for this purpose.
 func (b *Backend) ensureOnline() error {
-	if b.State != Online {
+	if !b.State.IsServing() {
 		log.WithFields(log.Fields{
 			"state":         b.State,
 			"expectedState": string(Online),
@@ -750,8 +1450,64 @@ func (b *Backend) ensureOnline() error {
 	return nil
 }
 
+// ensureAcceptsNewVolumes returns an error unless volumes may be provisioned on the backend.
+func (b *Backend) ensureAcceptsNewVolumes() error {
+	if err := b.ensureOnline(); err != nil {
+		return err
+	}
+	if !b.State.AcceptsNewVolumes() {
+		log.WithFields(log.Fields{
+			"state":         b.State,
+			"expectedState": string(Online),
+		}).Error("Backend does not accept new volumes.")
+		return fmt.Errorf("backend %s is %s and does not accept new volumes", b.Name, b.State)
+	}
+	return nil
+}
+
+// ServesNamespace reports whether the backend may hold new volumes for a namespace with the given
+// labels.  A backend without namespace restrictions serves all volumes, including those without a
+// namespace, while a restricted backend only serves the namespaces it lists or whose labels match
+// its selector.
+func (b *Backend) ServesNamespace(namespace string, namespaceLabels map[string]string) bool {
+
+	if len(b.AllowedNamespaces) == 0 && b.NamespaceSelector == "" {
+		return true
+	}
+	if namespace == "" {
+		return false
+	}
+	if utils.StringInSlice(namespace, b.AllowedNamespaces) {
+		return true
+	}
+	if b.NamespaceSelector != "" {
+		selector, err := labels.Parse(b.NamespaceSelector)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"backend":           b.Name,
+				"namespaceSelector": b.NamespaceSelector,
+				"error":             err,
+			}).Error("Invalid backend namespace selector.")
+			return false
+		}
+		return selector.Matches(labels.Set(namespaceLabels))
+	}
+	return false
+}
+
+// ensureServesNamespace returns an error unless the backend may hold new volumes for a volume's namespace.
+func (b *Backend) ensureServesNamespace(volConfig *VolumeConfig) error {
+	if b.ServesNamespace(volConfig.Namespace, volConfig.NamespaceLabels) {
+		return nil
+	}
+	if volConfig.Namespace == "" {
+		return fmt.Errorf("backend %s only serves volumes in specific namespaces", b.Name)
+	}
+	return fmt.Errorf("backend %s does not serve namespace %s", b.Name, volConfig.Namespace)
+}
+
 func (b *Backend) ensureOnlineOrDeleting() error {
-	if b.State != Online && b.State != Deleting {
+	if !b.State.IsServing() && b.State != Deleting {
 		log.WithFields(log.Fields{
 			"state":         b.State,
 			"expectedState": string(Online) + "/" + string(Deleting),
@@ -770,6 +1526,7 @@ type BackendExternal struct {
 	State       BackendState           `json:"state"`
 	Online      bool                   `json:"online"`
 	Volumes     []string               `json:"volumes"`
+	Generation  int64                  `json:"generation"`
 }
 
 func (b *Backend) ConstructExternal() *BackendExternal {
@@ -782,6 +1539,7 @@ func (b *Backend) ConstructExternal() *BackendExternal {
 		Online:      b.Online,
 		State:       b.State,
 		Volumes:     make([]string, 0),
+		Generation:  b.Generation,
 	}
 
 	for name, pool := range b.Storage {
@@ -814,6 +1572,7 @@ type BackendPersistent struct {
 	BackendUUID string                         `json:"backendUUID"`
 	Online      bool                           `json:"online"`
 	State       BackendState                   `json:"state"`
+	Generation  int64                          `json:"generation,omitempty"`
 }
 
 func (b *Backend) ConstructPersistent() *BackendPersistent {
@@ -824,6 +1583,7 @@ func (b *Backend) ConstructPersistent() *BackendPersistent {
 		Online:      b.Online,
 		State:       b.State,
 		BackendUUID: b.BackendUUID,
+		Generation:  b.Generation,
 	}
 	b.Driver.StoreConfig(&persistentBackend.Config)
 	return persistentBackend
@@ -896,6 +1656,13 @@ func (p *BackendPersistent) ExtractBackendSecrets(secretName string) (*BackendPe
 			backend.Config.OntapConfig.ChapTargetUsername = secretName
 			backend.Config.OntapConfig.ChapTargetInitiatorSecret = secretName
 		}
+		// FSx for NetApp ONTAP settings
+		if p.Config.OntapConfig.AWSConfig != nil {
+			secretMap["AWSAPIKey"] = backend.Config.OntapConfig.AWSConfig.APIKey
+			secretMap["AWSSecretKey"] = backend.Config.OntapConfig.AWSConfig.SecretKey
+			backend.Config.OntapConfig.AWSConfig.APIKey = secretName
+			backend.Config.OntapConfig.AWSConfig.SecretKey = secretName
+		}
 	case p.Config.SolidfireConfig != nil:
 		secretMap["EndPoint"] = backend.Config.SolidfireConfig.EndPoint
 		backend.Config.SolidfireConfig.EndPoint = secretName
@@ -961,6 +1728,15 @@ func (p *BackendPersistent) InjectBackendSecrets(secretMap map[string]string) er
 				return makeError("ChapTargetInitiatorSecret")
 			}
 		}
+		// FSx for NetApp ONTAP settings
+		if p.Config.OntapConfig.AWSConfig != nil {
+			if p.Config.OntapConfig.AWSConfig.APIKey, ok = secretMap["AWSAPIKey"]; !ok {
+				return makeError("AWSAPIKey")
+			}
+			if p.Config.OntapConfig.AWSConfig.SecretKey, ok = secretMap["AWSSecretKey"]; !ok {
+				return makeError("AWSSecretKey")
+			}
+		}
 	case p.Config.SolidfireConfig != nil:
 		if p.Config.SolidfireConfig.EndPoint, ok = secretMap["EndPoint"]; !ok {
 			return makeError("EndPoint")
@@ -57,6 +57,20 @@ func TestBackendState(t *testing.T) {
 				return input.IsFailed()
 			},
 		},
+		"Cordoned state": {
+			input:  Cordoned,
+			output: "cordoned",
+			predicate: func(input BackendState) bool {
+				return input.IsCordoned() && input.IsServing() && !input.AcceptsNewVolumes()
+			},
+		},
+		"Draining state": {
+			input:  Draining,
+			output: "draining",
+			predicate: func(input BackendState) bool {
+				return input.IsDraining() && input.IsServing() && !input.AcceptsNewVolumes()
+			},
+		},
 	}
 	for testName, test := range tests {
 		t.Logf("Running test case '%s'", testName)
@@ -65,3 +79,34 @@ func TestBackendState(t *testing.T) {
 		assert.True(t, test.predicate(test.input), "Predicate failed")
 	}
 }
+
+func TestBackendStateServing(t *testing.T) {
+
+	assert.True(t, Online.IsServing())
+	assert.True(t, Online.AcceptsNewVolumes())
+
+	for _, state := range []BackendState{Unknown, Offline, Deleting, Failed} {
+		assert.False(t, state.IsServing(), "%s backends don't serve volumes", state)
+		assert.False(t, state.AcceptsNewVolumes(), "%s backends don't accept new volumes", state)
+	}
+}
+
+func TestBackendServesNamespace(t *testing.T) {
+
+	backend := &Backend{Name: "shared"}
+	assert.True(t, backend.ServesNamespace("tenant1", nil))
+	assert.True(t, backend.ServesNamespace("", nil), "unrestricted backends serve volumes without a namespace")
+
+	backend.AllowedNamespaces = []string{"tenant1"}
+	assert.True(t, backend.ServesNamespace("tenant1", nil))
+	assert.False(t, backend.ServesNamespace("tenant2", nil))
+	assert.False(t, backend.ServesNamespace("", nil))
+
+	backend.NamespaceSelector = "tenant in (a, b)"
+	assert.True(t, backend.ServesNamespace("tenant1", nil))
+	assert.True(t, backend.ServesNamespace("tenant2", map[string]string{"tenant": "b"}))
+	assert.False(t, backend.ServesNamespace("tenant3", map[string]string{"tenant": "c"}))
+
+	err := backend.ensureServesNamespace(&VolumeConfig{Name: "vol", Namespace: "tenant3"})
+	assert.EqualError(t, err, "backend shared does not serve namespace tenant3")
+}
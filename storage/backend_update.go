@@ -0,0 +1,146 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package storage
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+
+	"github.com/RoaringBitmap/roaring"
+)
+
+// Names of the backend update types, as reported in update previews
+const (
+	UpdateTypeBackendRename          = "backendRename"
+	UpdateTypeVolumeAccessInfoChange = "volumeAccessInfoChange"
+	UpdateTypeInvalidUpdate          = "invalidUpdate"
+	UpdateTypeUsernameChange         = "usernameChange"
+	UpdateTypePasswordChange         = "passwordChange"
+	UpdateTypePrefixChange           = "prefixChange"
+)
+
+// Effects of a backend update on an existing volume
+const (
+	VolumeImpactBackendRename  = "backend renamed"
+	VolumeImpactOrphaned       = "not found on updated backend"
+	VolumeImpactNoLongerOrphan = "found on updated backend"
+)
+
+var updateTypeNames = map[uint32]string{
+	BackendRename:          UpdateTypeBackendRename,
+	VolumeAccessInfoChange: UpdateTypeVolumeAccessInfoChange,
+	InvalidUpdate:          UpdateTypeInvalidUpdate,
+	UsernameChange:         UpdateTypeUsernameChange,
+	PasswordChange:         UpdateTypePasswordChange,
+	PrefixChange:           UpdateTypePrefixChange,
+}
+
+// UpdateTypeNames returns the names of the update types in a bitmap returned by GetUpdateType.
+func UpdateTypeNames(updateCode *roaring.Bitmap) []string {
+	names := make([]string, 0, updateCode.GetCardinality())
+	for _, updateType := range updateCode.ToArray() {
+		if name, ok := updateTypeNames[updateType]; ok {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// BackendUpdatePreview describes what updating a backend's config would change, without changing it.
+type BackendUpdatePreview struct {
+	Backend     string `json:"backend"`
+	BackendUUID string `json:"backendUUID"`
+	// Allowed reports whether the update would be accepted; if not, Error explains why
+	Allowed bool   `json:"allowed"`
+	Error   string `json:"error,omitempty"`
+	// Changes are the config fields that differ, excluding credentials
+	Changes []BackendConfigChange `json:"changes"`
+	// UpdateTypes are the categories of change detected by the driver
+	UpdateTypes []string `json:"updateTypes"`
+	// AffectedVolumes are the existing volumes whose records would change
+	AffectedVolumes []BackendUpdateVolumeImpact `json:"affectedVolumes"`
+}
+
+// BackendConfigChange is a config field changed by a backend update.  Nested fields are named by
+// their dotted path, such as "defaults.spaceReserve".
+type BackendConfigChange struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old,omitempty"`
+	New   interface{} `json:"new,omitempty"`
+}
+
+// BackendUpdateVolumeImpact is the effect of a backend update on one volume.
+type BackendUpdateVolumeImpact struct {
+	Volume string `json:"volume"`
+	Impact string `json:"impact"`
+}
+
+// DiffBackendConfigs returns the fields that differ between two external driver configs, sorted by name.
+func DiffBackendConfigs(oldConfig, newConfig interface{}) ([]BackendConfigChange, error) {
+
+	oldFields, err := flattenBackendConfig(oldConfig)
+	if err != nil {
+		return nil, err
+	}
+	newFields, err := flattenBackendConfig(newConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]BackendConfigChange, 0)
+	for field, oldValue := range oldFields {
+		if newValue, ok := newFields[field]; !ok || !reflect.DeepEqual(oldValue, newValue) {
+			changes = append(changes, BackendConfigChange{Field: field, Old: oldValue, New: newFields[field]})
+		}
+	}
+	for field, newValue := range newFields {
+		if _, ok := oldFields[field]; !ok {
+			changes = append(changes, BackendConfigChange{Field: field, New: newValue})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	return changes, nil
+}
+
+// flattenBackendConfig converts a config to a map of dotted field paths to values, omitting empty values.
+func flattenBackendConfig(config interface{}) (map[string]interface{}, error) {
+
+	configJSON, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	var configMap map[string]interface{}
+	if err = json.Unmarshal(configJSON, &configMap); err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]interface{})
+	flattenBackendConfigMap("", configMap, fields)
+	return fields, nil
+}
+
+func flattenBackendConfigMap(prefix string, configMap map[string]interface{}, fields map[string]interface{}) {
+	for key, value := range configMap {
+		field := key
+		if prefix != "" {
+			field = prefix + "." + key
+		}
+		switch v := value.(type) {
+		case map[string]interface{}:
+			flattenBackendConfigMap(field, v, fields)
+		case nil:
+		case string:
+			if v != "" {
+				fields[field] = v
+			}
+		case []interface{}:
+			if len(v) > 0 {
+				fields[field] = v
+			}
+		default:
+			fields[field] = v
+		}
+	}
+}
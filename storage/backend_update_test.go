@@ -0,0 +1,44 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package storage
+
+import (
+	"testing"
+
+	"github.com/RoaringBitmap/roaring"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffBackendConfigs(t *testing.T) {
+	oldConfig := map[string]interface{}{
+		"backendName": "ontap",
+		"dataLIF":     "10.0.0.1",
+		"defaults":    map[string]interface{}{"spaceReserve": "none", "snapshotReserve": "10"},
+		"labels":      []string{},
+	}
+	newConfig := map[string]interface{}{
+		"backendName":     "ontap",
+		"dataLIF":         "10.0.0.2",
+		"defaults":        map[string]interface{}{"spaceReserve": "volume"},
+		"nfsMountOptions": "nfsvers=4",
+	}
+
+	changes, err := DiffBackendConfigs(oldConfig, newConfig)
+	assert.NoError(t, err)
+	assert.Equal(t, []BackendConfigChange{
+		{Field: "dataLIF", Old: "10.0.0.1", New: "10.0.0.2"},
+		{Field: "defaults.snapshotReserve", Old: "10"},
+		{Field: "defaults.spaceReserve", Old: "none", New: "volume"},
+		{Field: "nfsMountOptions", New: "nfsvers=4"},
+	}, changes)
+
+	changes, err = DiffBackendConfigs(oldConfig, oldConfig)
+	assert.NoError(t, err)
+	assert.Empty(t, changes)
+}
+
+func TestUpdateTypeNames(t *testing.T) {
+	assert.Empty(t, UpdateTypeNames(roaring.New()))
+	assert.Equal(t, []string{UpdateTypeVolumeAccessInfoChange, UpdateTypePasswordChange, UpdateTypePrefixChange},
+		UpdateTypeNames(roaring.BitmapOf(PrefixChange, VolumeAccessInfoChange, PasswordChange)))
+}
@@ -0,0 +1,73 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package storage
+
+import (
+	"fmt"
+)
+
+// CloneGrantAll matches every volume or namespace in a clone grant.
+const CloneGrantAll = "*"
+
+// CloneGrant allows volumes owned by one namespace to be cloned into other namespaces, such as
+// when a platform team publishes golden datasets to tenants.  Without a grant, a volume may only
+// be cloned within its own namespace.
+type CloneGrant struct {
+	Name string `json:"name"`
+	// Namespace owns the source volumes
+	Namespace string `json:"namespace"`
+	// Volumes are the source volumes that may be cloned, or "*" for all volumes in the namespace.
+	// New volumes may also be restored from any snapshot of these volumes.
+	Volumes []string `json:"volumes"`
+	// Snapshots are the IDs of further snapshots that new volumes may be restored from, or "*" for
+	// all snapshots of volumes in the namespace
+	Snapshots []string `json:"snapshots,omitempty"`
+	// TargetNamespaces are the namespaces the volumes may be cloned into, or "*" for any namespace
+	TargetNamespaces []string `json:"targetNamespaces"`
+}
+
+func (g *CloneGrant) Validate() error {
+	if g.Name == "" || g.Namespace == "" {
+		return fmt.Errorf("the following fields for \"CloneGrant\" are mandatory: name and namespace")
+	}
+	if len(g.TargetNamespaces) == 0 {
+		return fmt.Errorf("clone grant %s must list at least one target namespace", g.ID())
+	}
+	return nil
+}
+
+func (g *CloneGrant) ID() string {
+	return MakeCloneGrantID(g.Namespace, g.Name)
+}
+
+func MakeCloneGrantID(namespace, name string) string {
+	return fmt.Sprintf("%s/%s", namespace, name)
+}
+
+// Allows reports whether the grant permits a volume in a namespace to be cloned into the target namespace.
+func (g *CloneGrant) Allows(sourceNamespace, sourceVolume, targetNamespace string) bool {
+	return g.Namespace == sourceNamespace && cloneGrantMatches(g.Volumes, sourceVolume) &&
+		cloneGrantMatches(g.TargetNamespaces, targetNamespace)
+}
+
+// AllowsSnapshot reports whether the grant permits a snapshot of a volume in a namespace to be restored
+// into the target namespace.
+func (g *CloneGrant) AllowsSnapshot(sourceNamespace, snapshotID, targetNamespace string) bool {
+	if g.Namespace != sourceNamespace || !cloneGrantMatches(g.TargetNamespaces, targetNamespace) {
+		return false
+	}
+	if cloneGrantMatches(g.Snapshots, snapshotID) {
+		return true
+	}
+	volumeName, _, err := ParseSnapshotID(snapshotID)
+	return err == nil && cloneGrantMatches(g.Volumes, volumeName)
+}
+
+func cloneGrantMatches(values []string, value string) bool {
+	for _, v := range values {
+		if v == CloneGrantAll || v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCloneGrantAllows(t *testing.T) {
+	grant := &CloneGrant{
+		Name:             "publish",
+		Namespace:        "datasets",
+		Volumes:          []string{"pvc-1", "pvc-2"},
+		TargetNamespaces: []string{"tenant1", "tenant2"},
+	}
+	assert.NoError(t, grant.Validate())
+	assert.Equal(t, "datasets/publish", grant.ID())
+
+	assert.True(t, grant.Allows("datasets", "pvc-1", "tenant1"))
+	assert.True(t, grant.Allows("datasets", "pvc-2", "tenant2"))
+	assert.False(t, grant.Allows("datasets", "pvc-3", "tenant1"))
+	assert.False(t, grant.Allows("datasets", "pvc-1", "tenant3"))
+	assert.False(t, grant.Allows("other", "pvc-1", "tenant1"))
+
+	grant.Volumes = []string{CloneGrantAll}
+	grant.TargetNamespaces = []string{CloneGrantAll}
+	assert.True(t, grant.Allows("datasets", "pvc-3", "tenant3"))
+	assert.False(t, grant.Allows("other", "pvc-3", "tenant3"))
+
+	grant.TargetNamespaces = nil
+	assert.Error(t, grant.Validate())
+	grant.Namespace = ""
+	assert.Error(t, grant.Validate())
+}
+
+func TestCloneGrantAllowsSnapshot(t *testing.T) {
+	grant := &CloneGrant{
+		Name:             "publish",
+		Namespace:        "datasets",
+		Volumes:          []string{"pvc-1"},
+		Snapshots:        []string{"pvc-2/snap-1"},
+		TargetNamespaces: []string{"tenant1"},
+	}
+
+	// Snapshots of granted volumes are granted too
+	assert.True(t, grant.AllowsSnapshot("datasets", "pvc-1/snap-1", "tenant1"))
+	assert.True(t, grant.AllowsSnapshot("datasets", "pvc-2/snap-1", "tenant1"))
+	assert.False(t, grant.AllowsSnapshot("datasets", "pvc-2/snap-2", "tenant1"))
+	assert.False(t, grant.AllowsSnapshot("datasets", "pvc-2/snap-1", "tenant2"))
+	assert.False(t, grant.AllowsSnapshot("other", "pvc-2/snap-1", "tenant1"))
+	assert.False(t, grant.Allows("datasets", "pvc-2", "tenant1"), "a snapshot grant doesn't cover its volume")
+
+	grant.Volumes = nil
+	grant.Snapshots = []string{CloneGrantAll}
+	assert.True(t, grant.AllowsSnapshot("datasets", "pvc-3/snap-9", "tenant1"))
+	assert.False(t, grant.Allows("datasets", "pvc-3", "tenant1"))
+}
@@ -0,0 +1,38 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package storage
+
+type DriftType string
+
+const (
+	// DriftMissingVolume is a volume known to Trident that its backend doesn't report
+	DriftMissingVolume = DriftType("missingVolume")
+	// DriftSizeMismatch is a volume whose size on its backend differs from the size Trident recorded
+	DriftSizeMismatch = DriftType("sizeMismatch")
+	// DriftMissingSnapshot is a snapshot known to Trident that its backend doesn't report
+	DriftMissingSnapshot = DriftType("missingSnapshot")
+	// DriftOrphanedVolume is a volume owned by a backend's driver that Trident doesn't know about
+	DriftOrphanedVolume = DriftType("orphanedVolume")
+)
+
+// Drift is a discrepancy between Trident's state and what a backend reports.
+type Drift struct {
+	Type         DriftType `json:"type"`
+	Backend      string    `json:"backend"`
+	Volume       string    `json:"volume,omitempty"`
+	Snapshot     string    `json:"snapshot,omitempty"`
+	InternalName string    `json:"internalName"`
+	Expected     string    `json:"expected,omitempty"`
+	Actual       string    `json:"actual,omitempty"`
+	Message      string    `json:"message"`
+	Repaired     bool      `json:"repaired"`
+}
+
+// DriftReport is the result of comparing Trident's state with the backends.
+type DriftReport struct {
+	Started  string   `json:"started"`
+	Finished string   `json:"finished"`
+	Drifts   []*Drift `json:"drifts"`
+	// Errors describes the backends that could not be checked
+	Errors []string `json:"errors,omitempty"`
+}
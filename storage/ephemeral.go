@@ -0,0 +1,79 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package storage
+
+import (
+	"fmt"
+
+	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/utils"
+)
+
+const (
+	// DefaultEphemeralVolumeSize is the size of a CSI inline ephemeral volume whose pod doesn't ask for one
+	DefaultEphemeralVolumeSize = "1Gi"
+)
+
+// EphemeralVolumeRequest asks the controller to create a CSI inline ephemeral volume and publish it to
+// the node running the pod that uses it.  Such volumes have no PVC, so the node sends what a storage
+// class and PVC would otherwise provide.
+type EphemeralVolumeRequest struct {
+	Name         string          `json:"name"`
+	Node         string          `json:"node"`
+	StorageClass string          `json:"storageClass"`
+	Size         string          `json:"size,omitempty"`
+	Protocol     config.Protocol `json:"protocol,omitempty"`
+	FileSystem   string          `json:"fileSystem,omitempty"`
+	MountOptions string          `json:"mountOptions,omitempty"`
+	Pod          string          `json:"pod,omitempty"`
+	Namespace    string          `json:"namespace,omitempty"`
+}
+
+// EphemeralVolume is a CSI inline ephemeral volume created for a node, along with everything the node
+// needs to attach it.
+type EphemeralVolume struct {
+	Volume      *VolumeExternal          `json:"volume"`
+	PublishInfo *utils.VolumePublishInfo `json:"publishInfo"`
+}
+
+func (r *EphemeralVolumeRequest) Validate() error {
+	if r.Name == "" || r.Node == "" || r.StorageClass == "" {
+		return fmt.Errorf("the following fields for \"EphemeralVolume\" are mandatory: name, node and " +
+			"storageClass")
+	}
+	if r.Protocol != "" && !config.IsValidProtocol(r.Protocol) {
+		return fmt.Errorf("%v is an unsupported protocol", r.Protocol)
+	}
+	return nil
+}
+
+// VolumeConfig returns the config of the volume to create for the request.
+func (r *EphemeralVolumeRequest) VolumeConfig() (*VolumeConfig, error) {
+
+	size := r.Size
+	if size == "" {
+		size = DefaultEphemeralVolumeSize
+	}
+	sizeBytes, err := utils.ConvertSizeToBytes(size)
+	if err != nil {
+		return nil, fmt.Errorf("invalid size for ephemeral volume %s; %v", r.Name, err)
+	}
+	protocol := r.Protocol
+	if protocol == "" {
+		protocol = config.ProtocolAny
+	}
+
+	return &VolumeConfig{
+		Name:          r.Name,
+		Size:          sizeBytes,
+		Protocol:      protocol,
+		StorageClass:  r.StorageClass,
+		AccessMode:    config.ReadWriteOnce,
+		VolumeMode:    config.Filesystem,
+		FileSystem:    r.FileSystem,
+		MountOptions:  r.MountOptions,
+		Namespace:     r.Namespace,
+		EphemeralNode: r.Node,
+		EphemeralPod:  r.Pod,
+	}, nil
+}
@@ -0,0 +1,49 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netapp/trident/config"
+)
+
+func TestEphemeralVolumeRequest(t *testing.T) {
+	request := &EphemeralVolumeRequest{
+		Name:         "csi-0123456789abcdef",
+		Node:         "node1",
+		StorageClass: "gold",
+		Pod:          "app-0",
+		Namespace:    "tenant1",
+	}
+	assert.NoError(t, request.Validate())
+
+	// Unspecified size and protocol fall back to defaults
+	volConfig, err := request.VolumeConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, "1073741824", volConfig.Size)
+	assert.Equal(t, config.ProtocolAny, volConfig.Protocol)
+	assert.Equal(t, "gold", volConfig.StorageClass)
+	assert.Equal(t, "tenant1", volConfig.Namespace)
+	assert.Equal(t, "node1", volConfig.EphemeralNode)
+	assert.Equal(t, "app-0", volConfig.EphemeralPod)
+
+	request.Size = "10Mi"
+	request.Protocol = config.File
+	volConfig, err = request.VolumeConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, "10485760", volConfig.Size)
+	assert.Equal(t, config.File, volConfig.Protocol)
+
+	request.Size = "lots"
+	_, err = request.VolumeConfig()
+	assert.Error(t, err)
+
+	request.Protocol = "fibre"
+	assert.Error(t, request.Validate())
+	request.Protocol = ""
+	request.StorageClass = ""
+	assert.Error(t, request.Validate())
+}
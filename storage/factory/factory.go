@@ -105,6 +105,8 @@ func NewStorageBackendForConfig(configJSON string) (sb *storage.Backend, err err
 	}
 
 	sb.State = storage.Online
+	sb.AllowedNamespaces = commonConfig.AllowedNamespaces
+	sb.NamespaceSelector = commonConfig.NamespaceSelector
 
 	return sb, err
 }
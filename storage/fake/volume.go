@@ -5,6 +5,8 @@ type Volume struct {
 	RequestedPool string `json:"requestedPool"`
 	PhysicalPool  string
 	SizeBytes     uint64 `json:"size"`
+	// CloneParent is the volume a clone shares storage with, until it is split from it
+	CloneParent string `json:"cloneParent,omitempty"`
 }
 
 type CreatingVolume struct {
@@ -0,0 +1,37 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package storage
+
+type GarbageType string
+
+const (
+	// GarbageOrphanedTransaction is a transaction for a synchronous operation that is no longer running
+	GarbageOrphanedTransaction = GarbageType("orphanedTransaction")
+	// GarbageStaleSnapshot is a snapshot record whose volume is no longer persisted
+	GarbageStaleSnapshot = GarbageType("staleSnapshot")
+	// GarbageStaleAttachment is a record of a volume being published to a node that is no longer registered
+	GarbageStaleAttachment = GarbageType("staleAttachment")
+	// GarbageStaleEphemeralVolume is a CSI inline ephemeral volume whose node is no longer registered
+	GarbageStaleEphemeralVolume = GarbageType("staleEphemeralVolume")
+)
+
+// Garbage is an object in Trident's persistent store that nothing refers to or will finish.
+type Garbage struct {
+	Type      GarbageType     `json:"type"`
+	Name      string          `json:"name"`
+	Operation VolumeOperation `json:"operation,omitempty"`
+	Volume    string          `json:"volume,omitempty"`
+	Snapshot  string          `json:"snapshot,omitempty"`
+	Node      string          `json:"node,omitempty"`
+	Message   string          `json:"message"`
+	Cleaned   bool            `json:"cleaned"`
+}
+
+// GarbageReport is the result of searching Trident's persistent store for garbage.
+type GarbageReport struct {
+	Started  string     `json:"started"`
+	Finished string     `json:"finished"`
+	Garbage  []*Garbage `json:"garbage"`
+	// Errors describes the objects that could not be read or cleaned up
+	Errors []string `json:"errors,omitempty"`
+}
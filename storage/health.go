@@ -0,0 +1,99 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package storage
+
+import (
+	"strings"
+	"time"
+)
+
+type HealthStatus string
+
+const (
+	// HealthStatusHealthy means Trident, its persistent store, and all of its backends are reachable
+	HealthStatusHealthy = HealthStatus("healthy")
+	// HealthStatusDegraded means Trident is running, but its persistent store or a backend isn't reachable
+	HealthStatusDegraded = HealthStatus("degraded")
+	// HealthStatusFailed means Trident failed to bootstrap and can't serve requests
+	HealthStatusFailed = HealthStatus("failed")
+	// HealthStatusStarting means Trident hasn't finished bootstrapping, or is a standby replica
+	HealthStatusStarting = HealthStatus("starting")
+)
+
+type AuthStatus string
+
+const (
+	// AuthStatusUnknown means no call to the storage system has succeeded or failed authentication yet
+	AuthStatusUnknown = AuthStatus("unknown")
+	AuthStatusOK      = AuthStatus("ok")
+	// AuthStatusFailed means the storage system rejected the backend's credentials
+	AuthStatusFailed = AuthStatus("failed")
+)
+
+// BackendHealth describes whether Trident can reach a backend's storage system, as determined by
+// the outcome of the calls made to it.
+type BackendHealth struct {
+	Name        string       `json:"name"`
+	BackendUUID string       `json:"backendUUID"`
+	State       BackendState `json:"state"`
+	Reachable   bool         `json:"reachable"`
+	AuthStatus  AuthStatus   `json:"authStatus"`
+	// LastSuccess is when a call to the storage system last succeeded
+	LastSuccess string `json:"lastSuccess,omitempty"`
+	// LastFailure and LastError describe the last call to the storage system that failed
+	LastFailure string `json:"lastFailure,omitempty"`
+	LastError   string `json:"lastError,omitempty"`
+}
+
+// RecordSuccess records a successful call to the backend's storage system.
+func (h *BackendHealth) RecordSuccess(when time.Time) {
+	h.Reachable = true
+	h.AuthStatus = AuthStatusOK
+	h.LastSuccess = when.UTC().Format(time.RFC3339)
+}
+
+// RecordFailure records a failed call to the backend's storage system.  Not every failure means the
+// storage system is unreachable, as a request may be rejected on its merits, so only failures of the
+// calls Trident makes to check the storage system's health mark it unreachable.
+func (h *BackendHealth) RecordFailure(when time.Time, err error, unreachable bool) {
+	h.LastFailure = when.UTC().Format(time.RFC3339)
+	h.LastError = err.Error()
+	if IsAuthenticationError(err) {
+		h.AuthStatus = AuthStatusFailed
+		h.Reachable = false
+	} else if unreachable {
+		h.Reachable = false
+	}
+}
+
+// StoreHealth describes whether Trident can reach its persistent store.
+type StoreHealth struct {
+	Type      string `json:"type"`
+	Reachable bool   `json:"reachable"`
+	Error     string `json:"error,omitempty"`
+}
+
+// HealthReport describes the health of Trident and of the storage systems it manages, so that
+// monitoring can tell a failed Trident from a running one that can't reach a backend.
+type HealthReport struct {
+	Status   HealthStatus     `json:"status"`
+	Version  string           `json:"version"`
+	Error    string           `json:"error,omitempty"`
+	Store    *StoreHealth     `json:"store,omitempty"`
+	Backends []*BackendHealth `json:"backends"`
+}
+
+// IsAuthenticationError reports whether an error from a storage system's API means it rejected the
+// credentials it was called with.
+func IsAuthenticationError(err error) bool {
+	if err == nil {
+		return false
+	}
+	message := strings.ToLower(err.Error())
+	for _, indicator := range []string{"unauthorized", "authentication", "not authorized"} {
+		if strings.Contains(message, indicator) {
+			return true
+		}
+	}
+	return false
+}
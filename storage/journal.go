@@ -0,0 +1,125 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package storage
+
+import (
+	"context"
+	"time"
+)
+
+// JournalStep is one step of a multi-step storage operation, such as creating the FlexVol or the LUN
+// of a new ONTAP SAN volume.  Resource names the object the step creates, so that it can be removed
+// if the operation is rolled back.
+type JournalStep struct {
+	Name     string `json:"name"`
+	Resource string `json:"resource,omitempty"`
+	Started  string `json:"started"`
+	Done     bool   `json:"done"`
+}
+
+// OperationJournal records the steps of a storage operation in the operation's volume transaction,
+// so that Trident can finish or undo an operation that was interrupted by a restart.  Each step is
+// persisted before it is carried out, so a step that isn't done may or may not have taken effect,
+// and undoing it must tolerate the resource not existing.  A nil journal records nothing, so
+// drivers may use the journal from their context without checking for one.
+type OperationJournal struct {
+	BackendUUID string         `json:"backendUUID"`
+	Pool        string         `json:"pool,omitempty"`
+	Steps       []*JournalStep `json:"steps"`
+
+	// Committed is set once the operation has succeeded on the backend, after which it is finished
+	// rather than undone
+	Committed bool `json:"committed"`
+
+	persist func() error
+}
+
+// NewOperationJournal returns a journal for an operation on a backend's pool.  The persist function
+// saves the journal, normally by updating the volume transaction that holds it.
+func NewOperationJournal(backendUUID, pool string, persist func() error) *OperationJournal {
+	return &OperationJournal{
+		BackendUUID: backendUUID,
+		Pool:        pool,
+		Steps:       make([]*JournalStep, 0),
+		persist:     persist,
+	}
+}
+
+func (j *OperationJournal) save() error {
+	if j.persist == nil {
+		return nil
+	}
+	return j.persist()
+}
+
+// Begin records that a step is about to start, and saves the journal before returning, so the
+// caller must not carry out the step if it fails.  Beginning a step again, such as when a create
+// is retried in another physical pool, restarts it.
+func (j *OperationJournal) Begin(name, resource string) error {
+	if j == nil {
+		return nil
+	}
+	step := j.Step(name, resource)
+	if step == nil {
+		step = &JournalStep{Name: name, Resource: resource}
+		j.Steps = append(j.Steps, step)
+	}
+	step.Started = time.Now().UTC().Format(time.RFC3339)
+	step.Done = false
+	return j.save()
+}
+
+// Complete records that a step finished.  The journal is saved with the next step, as a step that
+// isn't done is undone the same way.
+func (j *OperationJournal) Complete(name, resource string) {
+	if j == nil {
+		return
+	}
+	if step := j.Step(name, resource); step != nil {
+		step.Done = true
+	}
+}
+
+// Step returns the journal's record of a step, or nil if the step hasn't begun.
+func (j *OperationJournal) Step(name, resource string) *JournalStep {
+	if j == nil {
+		return nil
+	}
+	for _, step := range j.Steps {
+		if step.Name == name && step.Resource == resource {
+			return step
+		}
+	}
+	return nil
+}
+
+// Reset forgets the journal's steps once they have been undone, and saves the journal.
+func (j *OperationJournal) Reset() error {
+	if j == nil {
+		return nil
+	}
+	j.Steps = make([]*JournalStep, 0)
+	return j.save()
+}
+
+// Commit records that the operation succeeded, and saves the journal.
+func (j *OperationJournal) Commit() error {
+	if j == nil {
+		return nil
+	}
+	j.Committed = true
+	return j.save()
+}
+
+type journalContextKey struct{}
+
+// ContextWithJournal returns a context that carries a journal to the driver performing an operation.
+func ContextWithJournal(ctx context.Context, journal *OperationJournal) context.Context {
+	return context.WithValue(ctx, journalContextKey{}, journal)
+}
+
+// JournalFromContext returns the journal carried by a context, or nil if there is none.
+func JournalFromContext(ctx context.Context) *OperationJournal {
+	journal, _ := ctx.Value(journalContextKey{}).(*OperationJournal)
+	return journal
+}
@@ -0,0 +1,61 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperationJournal(t *testing.T) {
+
+	saves := 0
+	journal := NewOperationJournal("uuid", "pool", func() error {
+		saves++
+		return nil
+	})
+
+	// Steps are saved before they start, and restarting a step doesn't repeat it
+	require.NoError(t, journal.Begin("createFlexvol", "vol1"))
+	journal.Complete("createFlexvol", "vol1")
+	require.NoError(t, journal.Begin("createLUN", "/vol/vol1/lun0"))
+	require.NoError(t, journal.Begin("createLUN", "/vol/vol1/lun0"))
+	assert.Equal(t, 3, saves)
+	require.Len(t, journal.Steps, 2)
+	assert.True(t, journal.Step("createFlexvol", "vol1").Done)
+	assert.False(t, journal.Step("createLUN", "/vol/vol1/lun0").Done)
+	assert.Nil(t, journal.Step("createLUN", "/vol/vol2/lun0"))
+
+	// Journals are saved with their transactions
+	txn := &VolumeTransaction{Config: &VolumeConfig{Name: "vol1"}, Op: AddVolume, Journal: journal}
+	txnJSON, err := json.Marshal(txn)
+	require.NoError(t, err)
+	storedTxn := &VolumeTransaction{}
+	require.NoError(t, json.Unmarshal(txnJSON, storedTxn))
+	assert.Equal(t, "uuid", storedTxn.Journal.BackendUUID)
+	assert.Len(t, storedTxn.Journal.Steps, 2)
+	assert.NoError(t, storedTxn.Journal.Commit(), "a journal read from the store has nothing to save it")
+
+	require.NoError(t, journal.Reset())
+	assert.Empty(t, journal.Steps)
+	require.NoError(t, journal.Commit())
+	assert.True(t, journal.Committed)
+	assert.Equal(t, 5, saves)
+}
+
+func TestOperationJournalContext(t *testing.T) {
+
+	// Drivers may use the journal of a context without one
+	journal := JournalFromContext(context.Background())
+	assert.Nil(t, journal)
+	assert.NoError(t, journal.Begin("createFlexvol", "vol1"))
+	journal.Complete("createFlexvol", "vol1")
+	assert.NoError(t, journal.Commit())
+
+	journal = NewOperationJournal("uuid", "pool", nil)
+	assert.Equal(t, journal, JournalFromContext(ContextWithJournal(context.Background(), journal)))
+}
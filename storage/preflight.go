@@ -0,0 +1,51 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package storage
+
+type PreflightStatus string
+
+const (
+	// PreflightPassed is a check that found nothing to do before the operation
+	PreflightPassed = PreflightStatus("passed")
+	// PreflightWarning is a check that found something worth resolving, but that doesn't block the operation
+	PreflightWarning = PreflightStatus("warning")
+	// PreflightFailed is a check that found something that must be resolved before the operation
+	PreflightFailed = PreflightStatus("failed")
+)
+
+// PreflightCheck is the result of one check made before an operation such as upgrading Trident.
+type PreflightCheck struct {
+	Name   string          `json:"name"`
+	Status PreflightStatus `json:"status"`
+	// Findings describes each problem the check found
+	Findings []string `json:"findings,omitempty"`
+	// Remediation describes how to resolve the findings
+	Remediation string `json:"remediation,omitempty"`
+}
+
+// PreflightReport is the result of checking whether an operation, such as upgrading Trident or
+// restoring a snapshot, may proceed.
+type PreflightReport struct {
+	Ready  bool              `json:"ready"`
+	Checks []*PreflightCheck `json:"checks"`
+}
+
+// NewPreflightReport returns a report that is ready until a failed check is added.
+func NewPreflightReport() *PreflightReport {
+	return &PreflightReport{Ready: true, Checks: make([]*PreflightCheck, 0)}
+}
+
+// AddCheck adds a check to the report.  A check with findings but no status is a failure.
+func (r *PreflightReport) AddCheck(check *PreflightCheck) {
+	if check.Status == "" {
+		if len(check.Findings) == 0 {
+			check.Status = PreflightPassed
+		} else {
+			check.Status = PreflightFailed
+		}
+	}
+	if check.Status == PreflightFailed {
+		r.Ready = false
+	}
+	r.Checks = append(r.Checks, check)
+}
@@ -0,0 +1,74 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package storage
+
+import (
+	"fmt"
+)
+
+// Quota limits the space and number of volumes Trident provisions for a namespace, either for all
+// of the namespace's volumes or only for those of one storage class.
+type Quota struct {
+	Name string `json:"name"`
+	// Namespace is the namespace whose volumes are limited
+	Namespace string `json:"namespace"`
+	// StorageClass limits the quota to volumes of one storage class, or is empty for all volumes
+	StorageClass string `json:"storageClass,omitempty"`
+	// MaxBytes is the total size the volumes may have, or zero for no limit
+	MaxBytes int64 `json:"maxBytes,omitempty"`
+	// MaxVolumes is the number of volumes there may be, or zero for no limit
+	MaxVolumes int `json:"maxVolumes,omitempty"`
+}
+
+// QuotaExternal is a quota along with the usage it currently counts.
+type QuotaExternal struct {
+	Quota
+	UsedBytes   int64 `json:"usedBytes"`
+	UsedVolumes int   `json:"usedVolumes"`
+}
+
+func (q *Quota) Validate() error {
+	if q.Name == "" || q.Namespace == "" {
+		return fmt.Errorf("the following fields for \"Quota\" are mandatory: name and namespace")
+	}
+	if q.MaxBytes < 0 || q.MaxVolumes < 0 {
+		return fmt.Errorf("quota %s may not have negative limits", q.ID())
+	}
+	if q.MaxBytes == 0 && q.MaxVolumes == 0 {
+		return fmt.Errorf("quota %s must limit the size or the number of volumes", q.ID())
+	}
+	return nil
+}
+
+func (q *Quota) ID() string {
+	return MakeQuotaID(q.Namespace, q.Name)
+}
+
+func MakeQuotaID(namespace, name string) string {
+	return fmt.Sprintf("%s/%s", namespace, name)
+}
+
+// Counts reports whether a volume in a namespace and storage class counts against the quota.
+func (q *Quota) Counts(namespace, storageClass string) bool {
+	return q.Namespace == namespace && (q.StorageClass == "" || q.StorageClass == storageClass)
+}
+
+// Check returns an error if the usage counted by the quota would exceed its limits after adding the
+// given number of bytes and volumes.
+func (q *Quota) Check(usedBytes int64, usedVolumes int, addBytes int64, addVolumes int) error {
+
+	scope := "namespace " + q.Namespace
+	if q.StorageClass != "" {
+		scope += " and storage class " + q.StorageClass
+	}
+
+	if q.MaxVolumes > 0 && addVolumes > 0 && usedVolumes+addVolumes > q.MaxVolumes {
+		return fmt.Errorf("quota %s allows %d volumes in %s, which has %d", q.ID(), q.MaxVolumes, scope,
+			usedVolumes)
+	}
+	if q.MaxBytes > 0 && addBytes > 0 && usedBytes+addBytes > q.MaxBytes {
+		return fmt.Errorf("quota %s allows %d bytes in %s, which has %d bytes and needs %d more", q.ID(),
+			q.MaxBytes, scope, usedBytes, addBytes)
+	}
+	return nil
+}
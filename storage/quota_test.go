@@ -0,0 +1,44 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuotaCheck(t *testing.T) {
+	quota := &Quota{
+		Name:       "tenant",
+		Namespace:  "tenant1",
+		MaxBytes:   100,
+		MaxVolumes: 2,
+	}
+	assert.NoError(t, quota.Validate())
+	assert.Equal(t, "tenant1/tenant", quota.ID())
+
+	assert.True(t, quota.Counts("tenant1", "gold"))
+	assert.False(t, quota.Counts("tenant2", "gold"))
+	quota.StorageClass = "gold"
+	assert.True(t, quota.Counts("tenant1", "gold"))
+	assert.False(t, quota.Counts("tenant1", "silver"))
+
+	assert.NoError(t, quota.Check(0, 0, 100, 1))
+	assert.NoError(t, quota.Check(50, 1, 50, 1))
+	assert.Error(t, quota.Check(50, 1, 51, 1))
+	assert.Error(t, quota.Check(50, 2, 10, 1))
+
+	// Usage already over the limits doesn't prevent shrinking or growth that isn't counted
+	assert.NoError(t, quota.Check(200, 3, 0, 0))
+
+	quota.MaxVolumes = 0
+	assert.NoError(t, quota.Check(0, 10, 10, 1))
+	quota.MaxBytes = 0
+	assert.Error(t, quota.Validate())
+	quota.MaxBytes = -1
+	assert.Error(t, quota.Validate())
+	quota.MaxBytes = 100
+	quota.Namespace = ""
+	assert.Error(t, quota.Validate())
+}
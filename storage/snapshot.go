@@ -5,6 +5,9 @@ package storage
 import (
 	"fmt"
 	"regexp"
+	"time"
+
+	log "github.com/sirupsen/logrus"
 )
 
 const SnapshotTimestampFormat = "2006-01-02T15:04:05Z"
@@ -13,11 +16,50 @@ const SnapshotNameFormat = "20060102T150405Z"
 var snapshotIDRegex = regexp.MustCompile(`^(?P<volume>[^\s/]+)/(?P<snapshot>[^\s/]+)$`)
 
 type SnapshotConfig struct {
-	Version            string `json:"version,omitempty"`
-	Name               string `json:"name,omitempty"`
-	InternalName       string `json:"internalName,omitempty"`
-	VolumeName         string `json:"volumeName,omitempty"`
-	VolumeInternalName string `json:"volumeInternalName,omitempty"`
+	Version            string       `json:"version,omitempty"`
+	Name               string       `json:"name,omitempty"`
+	InternalName       string       `json:"internalName,omitempty"`
+	VolumeName         string       `json:"volumeName,omitempty"`
+	VolumeInternalName string       `json:"volumeInternalName,omitempty"`
+	Type               SnapshotType `json:"type,omitempty"`
+	// SnapmirrorLabel is the label replication and backup policies use to select the snapshot
+	SnapmirrorLabel string `json:"snapmirrorLabel,omitempty"`
+	// Retention is a hint to backup tooling of how long the snapshot should be kept
+	Retention string `json:"retention,omitempty"`
+	// ConsistencyType tells backup tooling whether the snapshot is crash or application consistent
+	ConsistencyType string `json:"consistencyType,omitempty"`
+	// TridentOwned is set on snapshots Trident created for itself rather than for an object such
+	// as a Kubernetes VolumeSnapshot.  Only these are deleted by snapshot retention policies.
+	TridentOwned bool `json:"tridentOwned,omitempty"`
+}
+
+// SnapshotType distinguishes snapshots stored on the backend from backups stored elsewhere,
+// such as AWS-native backups of FSx for NetApp ONTAP volumes.  An empty type means a snapshot.
+type SnapshotType string
+
+const (
+	SnapshotTypeSnapshot = SnapshotType("snapshot")
+	SnapshotTypeBackup   = SnapshotType("backup")
+)
+
+const (
+	// SnapshotParameterSnapmirrorLabel, SnapshotParameterRetention, and SnapshotParameterConsistencyType
+	// are the snapshot class parameters Trident passes through to the storage drivers
+	SnapshotParameterSnapmirrorLabel = "snapmirrorLabel"
+	SnapshotParameterRetention       = "retention"
+	SnapshotParameterConsistencyType = "consistencyType"
+
+	// SnapshotConsistencyCrash snapshots capture a volume as it would be after a power failure
+	SnapshotConsistencyCrash = "crash"
+	// SnapshotConsistencyApplication snapshots are taken while the application is quiesced, which
+	// is up to the application or its backup tooling rather than Trident
+	SnapshotConsistencyApplication = "application"
+)
+
+var snapmirrorLabelRegex = regexp.MustCompile(`^[a-zA-Z0-9_.-]{1,31}$`)
+
+func (c *SnapshotConfig) IsBackup() bool {
+	return c.Type == SnapshotTypeBackup
 }
 
 func (c *SnapshotConfig) ID() string {
@@ -28,9 +70,53 @@ func (c *SnapshotConfig) Validate() error {
 	if c.Name == "" || c.VolumeName == "" {
 		return fmt.Errorf("the following fields for \"Snapshot\" are mandatory: name and volumeName")
 	}
+	switch c.Type {
+	case "", SnapshotTypeSnapshot, SnapshotTypeBackup:
+	default:
+		return fmt.Errorf("invalid snapshot type %s; must be %s or %s", c.Type, SnapshotTypeSnapshot, SnapshotTypeBackup)
+	}
+	if c.SnapmirrorLabel != "" && !snapmirrorLabelRegex.MatchString(c.SnapmirrorLabel) {
+		return fmt.Errorf("invalid snapmirror label %s; must be up to 31 letters, digits, underscores, "+
+			"periods, and hyphens", c.SnapmirrorLabel)
+	}
+	if c.Retention != "" {
+		if retention, err := time.ParseDuration(c.Retention); err != nil || retention <= 0 {
+			return fmt.Errorf("invalid snapshot retention %s; must be a positive duration such as 168h",
+				c.Retention)
+		}
+	}
+	switch c.ConsistencyType {
+	case "", SnapshotConsistencyCrash, SnapshotConsistencyApplication:
+	default:
+		return fmt.Errorf("invalid snapshot consistency type %s; must be %s or %s", c.ConsistencyType,
+			SnapshotConsistencyCrash, SnapshotConsistencyApplication)
+	}
 	return nil
 }
 
+// ApplyParameters sets the fields of a snapshot config from the parameters of a snapshot class, such
+// as a Kubernetes VolumeSnapshotClass, and validates them.  Unknown parameters are ignored.
+func (c *SnapshotConfig) ApplyParameters(parameters map[string]string) error {
+
+	for key, value := range parameters {
+		switch key {
+		case SnapshotParameterSnapmirrorLabel:
+			c.SnapmirrorLabel = value
+		case SnapshotParameterRetention:
+			c.Retention = value
+		case SnapshotParameterConsistencyType:
+			c.ConsistencyType = value
+		default:
+			log.WithFields(log.Fields{
+				"snapshot":  c.ID(),
+				"parameter": key,
+			}).Warning("Ignoring unknown snapshot class parameter.")
+		}
+	}
+
+	return c.Validate()
+}
+
 type Snapshot struct {
 	Config    *SnapshotConfig
 	Created   string `json:"dateCreated"` // The UTC time that the snapshot was created, in RFC3339 format
@@ -41,15 +127,25 @@ type Snapshot struct {
 type SnapshotState string
 
 const (
+	SnapshotStateCreating       = SnapshotState("creating")
 	SnapshotStateOnline         = SnapshotState("online")
+	SnapshotStateFailed         = SnapshotState("failed")
 	SnapshotStateMissingBackend = SnapshotState("missing_backend")
 	SnapshotStateMissingVolume  = SnapshotState("missing_volume")
 )
 
+func (s SnapshotState) IsCreating() bool {
+	return s == SnapshotStateCreating
+}
+
 func (s SnapshotState) IsOnline() bool {
 	return s == SnapshotStateOnline
 }
 
+func (s SnapshotState) IsFailed() bool {
+	return s == SnapshotStateFailed
+}
+
 func (s SnapshotState) IsMissingBackend() bool {
 	return s == SnapshotStateMissingBackend
 }
@@ -97,6 +193,10 @@ func (s *Snapshot) ConstructClone() *Snapshot {
 			InternalName:       s.Config.InternalName,
 			VolumeName:         s.Config.VolumeName,
 			VolumeInternalName: s.Config.VolumeInternalName,
+			Type:               s.Config.Type,
+			SnapmirrorLabel:    s.Config.SnapmirrorLabel,
+			Retention:          s.Config.Retention,
+			ConsistencyType:    s.Config.ConsistencyType,
 		},
 		Created:   s.Created,
 		SizeBytes: s.SizeBytes,
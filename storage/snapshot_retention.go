@@ -0,0 +1,150 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package storage
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	SnapshotRetentionNone   = "none"
+	SnapshotRetentionLast   = "last"
+	SnapshotRetentionDaily  = "daily"
+	SnapshotRetentionWeekly = "weekly"
+)
+
+// SnapshotRetentionPolicy determines which of a volume's snapshots are kept.  A snapshot is kept
+// if any rule keeps it, and a policy with no rules keeps every snapshot.
+type SnapshotRetentionPolicy struct {
+	// KeepLast keeps the newest snapshots
+	KeepLast int `json:"keepLast,omitempty"`
+	// KeepDaily keeps the newest snapshot of each of the most recent days that have snapshots
+	KeepDaily int `json:"keepDaily,omitempty"`
+	// KeepWeekly keeps the newest snapshot of each of the most recent ISO weeks that have snapshots
+	KeepWeekly int `json:"keepWeekly,omitempty"`
+}
+
+// ParseSnapshotRetentionPolicy parses a retention policy of the form "last=7,daily=7,weekly=4".
+// Any rule may be omitted, and "none" disables retention.
+func ParseSnapshotRetentionPolicy(value string) (*SnapshotRetentionPolicy, error) {
+
+	policy := &SnapshotRetentionPolicy{}
+
+	value = strings.TrimSpace(value)
+	if value == "" || strings.EqualFold(value, SnapshotRetentionNone) {
+		return policy, nil
+	}
+
+	for _, rule := range strings.Split(value, ",") {
+		parts := strings.SplitN(strings.TrimSpace(rule), "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid snapshot retention rule '%s'", rule)
+		}
+		count, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+		if err != nil || count < 0 {
+			return nil, fmt.Errorf("invalid count in snapshot retention rule '%s'", rule)
+		}
+		switch strings.ToLower(strings.TrimSpace(parts[0])) {
+		case SnapshotRetentionLast:
+			policy.KeepLast = count
+		case SnapshotRetentionDaily:
+			policy.KeepDaily = count
+		case SnapshotRetentionWeekly:
+			policy.KeepWeekly = count
+		default:
+			return nil, fmt.Errorf("unknown snapshot retention rule '%s'; must be %s, %s or %s", parts[0],
+				SnapshotRetentionLast, SnapshotRetentionDaily, SnapshotRetentionWeekly)
+		}
+	}
+
+	return policy, nil
+}
+
+// IsEnabled reports whether the policy has any rules, and therefore expires snapshots.
+func (p *SnapshotRetentionPolicy) IsEnabled() bool {
+	return p != nil && (p.KeepLast > 0 || p.KeepDaily > 0 || p.KeepWeekly > 0)
+}
+
+func (p *SnapshotRetentionPolicy) String() string {
+	if !p.IsEnabled() {
+		return SnapshotRetentionNone
+	}
+	rules := make([]string, 0)
+	if p.KeepLast > 0 {
+		rules = append(rules, fmt.Sprintf("%s=%d", SnapshotRetentionLast, p.KeepLast))
+	}
+	if p.KeepDaily > 0 {
+		rules = append(rules, fmt.Sprintf("%s=%d", SnapshotRetentionDaily, p.KeepDaily))
+	}
+	if p.KeepWeekly > 0 {
+		rules = append(rules, fmt.Sprintf("%s=%d", SnapshotRetentionWeekly, p.KeepWeekly))
+	}
+	return strings.Join(rules, ",")
+}
+
+// ExpiredSnapshots returns the snapshots of a single volume that the policy doesn't keep, oldest
+// first.  Days and weeks are evaluated in UTC.  Snapshots without a valid creation time are never
+// expired.
+func (p *SnapshotRetentionPolicy) ExpiredSnapshots(snapshots []*Snapshot) []*Snapshot {
+
+	if !p.IsEnabled() {
+		return nil
+	}
+
+	type datedSnapshot struct {
+		snapshot *Snapshot
+		created  time.Time
+	}
+
+	dated := make([]datedSnapshot, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		created, err := time.Parse(time.RFC3339, snapshot.Created)
+		if err != nil {
+			continue
+		}
+		dated = append(dated, datedSnapshot{snapshot, created.UTC()})
+	}
+
+	// Newest first, with names breaking ties so the result is stable
+	sort.Slice(dated, func(i, j int) bool {
+		if !dated[i].created.Equal(dated[j].created) {
+			return dated[i].created.After(dated[j].created)
+		}
+		return dated[i].snapshot.Config.Name > dated[j].snapshot.Config.Name
+	})
+
+	days := make(map[string]bool)
+	weeks := make(map[string]bool)
+	expired := make([]*Snapshot, 0)
+
+	for i, d := range dated {
+		keep := i < p.KeepLast
+
+		day := d.created.Format("2006-01-02")
+		if !days[day] && len(days) < p.KeepDaily {
+			days[day] = true
+			keep = true
+		}
+
+		year, week := d.created.ISOWeek()
+		weekKey := fmt.Sprintf("%d-%d", year, week)
+		if !weeks[weekKey] && len(weeks) < p.KeepWeekly {
+			weeks[weekKey] = true
+			keep = true
+		}
+
+		if !keep {
+			expired = append(expired, d.snapshot)
+		}
+	}
+
+	// Return oldest first, so interrupted pruning removes the oldest snapshots
+	for i, j := 0, len(expired)-1; i < j; i, j = i+1, j-1 {
+		expired[i], expired[j] = expired[j], expired[i]
+	}
+	return expired
+}
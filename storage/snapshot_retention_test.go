@@ -0,0 +1,69 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSnapshotRetentionPolicy(t *testing.T) {
+
+	policy, err := ParseSnapshotRetentionPolicy("last=7, daily=7,WEEKLY=4")
+	assert.NoError(t, err)
+	assert.Equal(t, &SnapshotRetentionPolicy{KeepLast: 7, KeepDaily: 7, KeepWeekly: 4}, policy)
+	assert.Equal(t, "last=7,daily=7,weekly=4", policy.String())
+
+	policy, err = ParseSnapshotRetentionPolicy("daily=3")
+	assert.NoError(t, err)
+	assert.Equal(t, &SnapshotRetentionPolicy{KeepDaily: 3}, policy)
+
+	for _, value := range []string{"", "none", "last=0"} {
+		policy, err = ParseSnapshotRetentionPolicy(value)
+		assert.NoError(t, err)
+		assert.False(t, policy.IsEnabled(), "expected '%s' to disable retention", value)
+		assert.Equal(t, SnapshotRetentionNone, policy.String())
+	}
+
+	for _, value := range []string{"last", "last=x", "last=-1", "monthly=1"} {
+		_, err = ParseSnapshotRetentionPolicy(value)
+		assert.Error(t, err, "expected an error parsing '%s'", value)
+	}
+}
+
+func TestSnapshotRetentionPolicyExpiredSnapshots(t *testing.T) {
+
+	newSnapshot := func(name, created string) *Snapshot {
+		return NewSnapshot(&SnapshotConfig{Name: name, VolumeName: "vol"}, created, 0)
+	}
+
+	s1 := newSnapshot("s1", "2020-10-01T10:00:00Z") // Thursday, week 40
+	s2 := newSnapshot("s2", "2020-10-05T10:00:00Z") // Monday, week 41
+	s3 := newSnapshot("s3", "2020-10-12T09:00:00Z") // Monday, week 42
+	s4 := newSnapshot("s4", "2020-10-12T10:00:00Z")
+	s5 := newSnapshot("s5", "2020-10-13T10:00:00Z")
+	s6 := newSnapshot("s6", "2020-10-14T10:00:00Z")
+	unknown := newSnapshot("unknown", "")
+	snapshots := []*Snapshot{s4, s1, unknown, s6, s3, s5, s2}
+
+	policy := &SnapshotRetentionPolicy{KeepLast: 3}
+	assert.Equal(t, []*Snapshot{s1, s2, s3}, policy.ExpiredSnapshots(snapshots))
+
+	// Only the newest snapshot of each day is kept by the daily rule
+	policy = &SnapshotRetentionPolicy{KeepDaily: 3}
+	assert.Equal(t, []*Snapshot{s1, s2, s3}, policy.ExpiredSnapshots(snapshots))
+
+	policy = &SnapshotRetentionPolicy{KeepWeekly: 2}
+	assert.Equal(t, []*Snapshot{s1, s3, s4, s5}, policy.ExpiredSnapshots(snapshots))
+
+	// Rules are combined
+	policy = &SnapshotRetentionPolicy{KeepLast: 2, KeepDaily: 3, KeepWeekly: 2}
+	assert.Equal(t, []*Snapshot{s1, s3}, policy.ExpiredSnapshots(snapshots))
+
+	policy = &SnapshotRetentionPolicy{KeepLast: 10}
+	assert.Empty(t, policy.ExpiredSnapshots(snapshots))
+
+	policy = &SnapshotRetentionPolicy{}
+	assert.Empty(t, policy.ExpiredSnapshots(snapshots))
+}
@@ -0,0 +1,67 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshotConfigValidate(t *testing.T) {
+
+	tests := map[string]struct {
+		config *SnapshotConfig
+		valid  bool
+	}{
+		"Default type":  {&SnapshotConfig{Name: "snap", VolumeName: "vol"}, true},
+		"Snapshot type": {&SnapshotConfig{Name: "snap", VolumeName: "vol", Type: SnapshotTypeSnapshot}, true},
+		"Backup type":   {&SnapshotConfig{Name: "snap", VolumeName: "vol", Type: SnapshotTypeBackup}, true},
+		"Unknown type":  {&SnapshotConfig{Name: "snap", VolumeName: "vol", Type: "mirror"}, false},
+		"Missing name":  {&SnapshotConfig{VolumeName: "vol"}, false},
+		"Labeled": {&SnapshotConfig{Name: "snap", VolumeName: "vol", SnapmirrorLabel: "daily",
+			Retention: "168h", ConsistencyType: SnapshotConsistencyApplication}, true},
+		"Invalid label":       {&SnapshotConfig{Name: "snap", VolumeName: "vol", SnapmirrorLabel: "a b"}, false},
+		"Invalid retention":   {&SnapshotConfig{Name: "snap", VolumeName: "vol", Retention: "7 days"}, false},
+		"Negative retention":  {&SnapshotConfig{Name: "snap", VolumeName: "vol", Retention: "-1h"}, false},
+		"Invalid consistency": {&SnapshotConfig{Name: "snap", VolumeName: "vol", ConsistencyType: "frozen"}, false},
+	}
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			err := test.config.Validate()
+			if test.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestSnapshotConfigApplyParameters(t *testing.T) {
+
+	config := &SnapshotConfig{Name: "snap", VolumeName: "vol"}
+	err := config.ApplyParameters(map[string]string{
+		SnapshotParameterSnapmirrorLabel: "weekly",
+		SnapshotParameterRetention:       "720h",
+		SnapshotParameterConsistencyType: SnapshotConsistencyCrash,
+		"unknown":                        "ignored",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "weekly", config.SnapmirrorLabel)
+	assert.Equal(t, "720h", config.Retention)
+	assert.Equal(t, SnapshotConsistencyCrash, config.ConsistencyType)
+
+	config = &SnapshotConfig{Name: "snap", VolumeName: "vol"}
+	assert.Error(t, config.ApplyParameters(map[string]string{SnapshotParameterRetention: "forever"}))
+}
+
+func TestSnapshotConstructCloneKeepsType(t *testing.T) {
+
+	snapshot := NewSnapshot(&SnapshotConfig{Name: "snap", VolumeName: "vol", Type: SnapshotTypeBackup}, "", 0)
+
+	clone := snapshot.ConstructClone()
+
+	assert.True(t, clone.Config.IsBackup())
+	assert.True(t, snapshot.ConstructExternal().Config.IsBackup())
+}
@@ -0,0 +1,138 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package storage
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+type StateIssueType string
+
+const (
+	// StateIssueMissingBackend is a persisted volume whose backend is not persisted
+	StateIssueMissingBackend = StateIssueType("missingBackend")
+	// StateIssueMissingVolume is a persisted snapshot whose volume is not persisted
+	StateIssueMissingVolume = StateIssueType("missingVolume")
+	// StateIssueMissingNode is a persisted volume published to a node that is not registered
+	StateIssueMissingNode = StateIssueType("missingNode")
+	// StateIssueChecksumMismatch is a persisted object whose contents differ from Trident's record of it
+	StateIssueChecksumMismatch = StateIssueType("checksumMismatch")
+	// StateIssueNotPersisted is an object known to Trident that is not persisted
+	StateIssueNotPersisted = StateIssueType("notPersisted")
+	// StateIssueNotLoaded is a persisted volume that Trident doesn't know about
+	StateIssueNotLoaded = StateIssueType("notLoaded")
+)
+
+type StateRepair string
+
+const (
+	// StateRepairReadopt makes Trident's record of an object authoritative again, persisting it,
+	// loading it, or binding a volume to the backend that reports it
+	StateRepairReadopt = StateRepair("readopt")
+	// StateRepairPrune deletes a persisted object, or a reference, that can't be repaired
+	StateRepairPrune = StateRepair("prune")
+	// StateRepairRebuild persists an object as its backend reports it
+	StateRepairRebuild = StateRepair("rebuild")
+)
+
+// StateIssue is an inconsistency found in Trident's persistent state.
+type StateIssue struct {
+	Type StateIssueType `json:"type"`
+	// Kind is the kind of the object with the issue, such as backend, volume or snapshot
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	// Reference is the missing object that Name refers to
+	Reference string `json:"reference,omitempty"`
+	// Expected and Actual are the checksums of Trident's record and the persisted object
+	Expected string `json:"expected,omitempty"`
+	Actual   string `json:"actual,omitempty"`
+	Message  string `json:"message"`
+	// Repairs are the repairs that may be applied to the issue
+	Repairs  []StateRepair `json:"repairs"`
+	Repaired StateRepair   `json:"repaired,omitempty"`
+}
+
+// StateVerification is the result of verifying Trident's persistent state.
+type StateVerification struct {
+	Started   string        `json:"started"`
+	Finished  string        `json:"finished"`
+	Backends  int           `json:"backends"`
+	Volumes   int           `json:"volumes"`
+	Snapshots int           `json:"snapshots"`
+	Nodes     int           `json:"nodes"`
+	Issues    []*StateIssue `json:"issues"`
+	// Errors describes the issues that could not be repaired
+	Errors []string `json:"errors,omitempty"`
+}
+
+// StateVerifyRequest asks for Trident's persistent state to be verified, applying a repair to each
+// issue it applies to.
+type StateVerifyRequest struct {
+	Repair StateRepair `json:"repair,omitempty"`
+}
+
+// HasRepair reports whether a repair may be applied to an issue.
+func (i *StateIssue) HasRepair(repair StateRepair) bool {
+	for _, r := range i.Repairs {
+		if r == repair {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseStateRepair checks that a repair is known.  The empty repair only reports the issues.
+func ParseStateRepair(repair string) (StateRepair, error) {
+	switch r := StateRepair(repair); r {
+	case "", StateRepairReadopt, StateRepairPrune, StateRepairRebuild:
+		return r, nil
+	default:
+		return "", fmt.Errorf("unknown repair %s; expected %s, %s or %s", repair, StateRepairReadopt,
+			StateRepairPrune, StateRepairRebuild)
+	}
+}
+
+// checksum returns the SHA-256 checksum of an object's JSON.
+func checksum(obj interface{}) string {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Checksum returns the checksum of the fields of a persisted backend that Trident owns.  The state
+// and generation change without Trident's record of the backend being rewritten, so they're excluded.
+func (p *BackendPersistent) Checksum() string {
+	return checksum(struct {
+		Name        string
+		BackendUUID string
+		Config      PersistentStorageBackendConfig
+	}{p.Name, p.BackendUUID, p.Config})
+}
+
+// Checksum returns the checksum of the fields of a persisted volume that Trident owns.  The state is
+// excluded, since Trident sets it when loading the volume.
+func (v *VolumeExternal) Checksum() string {
+	return checksum(struct {
+		Config         *VolumeConfig
+		BackendUUID    string
+		Pool           string
+		Orphaned       bool
+		PublishedNodes []string
+	}{v.Config, v.BackendUUID, v.Pool, v.Orphaned, v.PublishedNodes})
+}
+
+// Checksum returns the checksum of the fields of a persisted snapshot that Trident owns.  The state
+// is excluded, since Trident sets it when loading the snapshot.
+func (s *SnapshotPersistent) Checksum() string {
+	return checksum(struct {
+		Config    *SnapshotConfig
+		Created   string
+		SizeBytes int64
+	}{s.Config, s.Created, s.SizeBytes})
+}
@@ -0,0 +1,43 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseStateRepair(t *testing.T) {
+	for _, repair := range []string{"", "readopt", "prune", "rebuild"} {
+		parsed, err := ParseStateRepair(repair)
+		assert.NoError(t, err, repair)
+		assert.Equal(t, StateRepair(repair), parsed)
+	}
+
+	_, err := ParseStateRepair("rescue")
+	assert.Error(t, err)
+}
+
+func TestVolumeChecksum(t *testing.T) {
+	volume := NewVolume(&VolumeConfig{Name: "vol", Size: "1073741824"}, "uuid", "pool", false)
+	expected := volume.ConstructExternal().Checksum()
+
+	// The state is set when a volume is loaded, so it doesn't change the checksum
+	volume.State = VolumeStateMissingBackend
+	assert.Equal(t, expected, volume.ConstructExternal().Checksum())
+
+	volume.AddPublishedNode("node")
+	assert.NotEqual(t, expected, volume.ConstructExternal().Checksum())
+}
+
+func TestSnapshotChecksum(t *testing.T) {
+	snapshot := NewSnapshot(&SnapshotConfig{Name: "snap", VolumeName: "vol"}, "2020-01-01T00:00:00Z", 100)
+	expected := snapshot.ConstructPersistent().Checksum()
+
+	snapshot.State = SnapshotStateMissingVolume
+	assert.Equal(t, expected, snapshot.ConstructPersistent().Checksum())
+
+	snapshot.SizeBytes = 200
+	assert.NotEqual(t, expected, snapshot.ConstructPersistent().Checksum())
+}
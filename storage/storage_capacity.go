@@ -0,0 +1,48 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package storage
+
+// StorageCapacity is the free space a backend's pools can provision from.  Pools may share the
+// underlying storage, as virtual pools share the ONTAP aggregates behind them, so free space is
+// reported per source and each pool lists the sources it draws on.
+type StorageCapacity struct {
+	// SourceFreeBytes maps each source of storage, such as an ONTAP aggregate, to its free bytes
+	SourceFreeBytes map[string]int64
+	// PoolSources maps each pool to the sources it provisions from
+	PoolSources map[string][]string
+	// SourceTotalBytes maps each source to its size, if the storage system reports it
+	SourceTotalBytes map[string]int64
+}
+
+// BackendCapacity is the space and volumes in each of a backend's pools.
+type BackendCapacity struct {
+	Backend string `json:"backend"`
+	// CapacityReported is set if the backend's driver reports the space behind its pools, without
+	// which only the pools' volumes are known
+	CapacityReported bool           `json:"capacityReported"`
+	Pools            []PoolCapacity `json:"pools"`
+}
+
+// PoolCapacity is the space and volumes in a backend's pool.  Pools that draw on the same sources
+// of storage, such as virtual pools on the same aggregates, report the same space.
+type PoolCapacity struct {
+	Name    string   `json:"name"`
+	Sources []string `json:"sources,omitempty"`
+	// TotalBytes is zero if the storage system doesn't report the size of the sources.  UsedBytes is
+	// the rest of that size, including any space withheld from new volumes by the backend's limits.
+	TotalBytes     int64 `json:"totalBytes"`
+	UsedBytes      int64 `json:"usedBytes"`
+	AvailableBytes int64 `json:"availableBytes"`
+	Volumes        int   `json:"volumes"`
+	// ProvisionedBytes is the combined size of the pool's volumes
+	ProvisionedBytes int64 `json:"provisionedBytes"`
+}
+
+// StorageClassCapacity is the space available to new volumes of a storage class within a topology segment.
+type StorageClassCapacity struct {
+	StorageClass string `json:"storageClass"`
+	// Topology is the segment of the cluster the capacity is reachable from, or empty for every node
+	Topology               map[string]string `json:"topology,omitempty"`
+	CapacityBytes          int64             `json:"capacityBytes"`
+	MaximumVolumeSizeBytes int64             `json:"maximumVolumeSizeBytes"`
+}
@@ -0,0 +1,105 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package storage
+
+// SupportsTopologies reports whether a pool can provision a volume that is accessible from at least one
+// of the requisite topology segments.  Volumes without requisite segments may be placed anywhere, and
+// pools without supported segments are accessible from every node.
+func (pool *Pool) SupportsTopologies(requisiteTopologies []map[string]string) bool {
+	if len(requisiteTopologies) == 0 {
+		return true
+	}
+	return pool.matchesTopologies(requisiteTopologies)
+}
+
+// PrefersTopologies reports whether a pool's volumes are accessible from any of the preferred topology
+// segments, so that the pool should be tried before others.
+func (pool *Pool) PrefersTopologies(preferredTopologies []map[string]string) bool {
+	if len(preferredTopologies) == 0 {
+		return false
+	}
+	return pool.matchesTopologies(preferredTopologies)
+}
+
+// AccessibleTopologies returns the topology segments a volume created in the pool will be accessible
+// from, limited to those matching the requisite segments.  It returns nil if the pool's volumes are
+// accessible from every node.
+func (pool *Pool) AccessibleTopologies(requisiteTopologies []map[string]string) []map[string]string {
+	if len(pool.SupportedTopologies) == 0 {
+		return nil
+	}
+	if len(requisiteTopologies) == 0 {
+		return pool.SupportedTopologies
+	}
+
+	accessible := make([]map[string]string, 0)
+	for _, supported := range pool.SupportedTopologies {
+		for _, requisite := range requisiteTopologies {
+			if topologyMatches(supported, requisite) {
+				accessible = append(accessible, supported)
+				break
+			}
+		}
+	}
+	return accessible
+}
+
+// WithinTopologies reports whether a pool's volumes are accessible from any of the allowed topology
+// segments, such as those a storage class is restricted to.  Allowed segments often name fewer keys than
+// a pool's, so a pool's segment is within an allowed one if they share a key and agree on every key they
+// share.  A pool of {region: r1, zone: a} is within {zone: a}, but not within {region: r2}.  Pools
+// without supported segments are accessible from every node.
+func (pool *Pool) WithinTopologies(allowedTopologies []map[string]string) bool {
+	if len(allowedTopologies) == 0 || len(pool.SupportedTopologies) == 0 {
+		return true
+	}
+	for _, supported := range pool.SupportedTopologies {
+		for _, allowed := range allowedTopologies {
+			if topologyOverlaps(supported, allowed) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (pool *Pool) matchesTopologies(topologies []map[string]string) bool {
+	if len(pool.SupportedTopologies) == 0 {
+		return true
+	}
+	for _, supported := range pool.SupportedTopologies {
+		for _, topology := range topologies {
+			if topologyMatches(supported, topology) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// topologyMatches reports whether a node topology segment, such as one requested by the container
+// orchestrator, lies within a supported segment, i.e. it has the same value for every supported key.
+// A supported segment of {zone: a} matches a requested one of {region: r1, zone: a}.
+func topologyMatches(supported, requested map[string]string) bool {
+	for key, value := range supported {
+		if requestedValue, ok := requested[key]; !ok || requestedValue != value {
+			return false
+		}
+	}
+	return true
+}
+
+// topologyOverlaps reports whether two topology segments have at least one key in common and the same
+// value for each of their common keys.
+func topologyOverlaps(a, b map[string]string) bool {
+	shared := false
+	for key, value := range a {
+		if otherValue, ok := b[key]; ok {
+			if otherValue != value {
+				return false
+			}
+			shared = true
+		}
+	}
+	return shared
+}
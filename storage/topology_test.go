@@ -0,0 +1,62 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPoolTopologies(t *testing.T) {
+	zoneA := map[string]string{"region": "r1", "zone": "a"}
+	zoneB := map[string]string{"region": "r1", "zone": "b"}
+	region2 := map[string]string{"region": "r2", "zone": "a"}
+
+	// A pool without topologies is accessible from every node
+	pool := &Pool{Name: "anywhere"}
+	assert.True(t, pool.SupportsTopologies(nil))
+	assert.True(t, pool.SupportsTopologies([]map[string]string{zoneA}))
+	assert.True(t, pool.PrefersTopologies([]map[string]string{zoneA}))
+	assert.False(t, pool.PrefersTopologies(nil))
+	assert.Nil(t, pool.AccessibleTopologies([]map[string]string{zoneA}))
+
+	// A pool's segment only needs to match the keys it specifies
+	pool = &Pool{
+		Name:                "zoneA",
+		SupportedTopologies: []map[string]string{{"region": "r1", "zone": "a"}, {"region": "r3"}},
+	}
+	assert.True(t, pool.SupportsTopologies(nil))
+	assert.True(t, pool.SupportsTopologies([]map[string]string{zoneA}))
+	assert.True(t, pool.SupportsTopologies([]map[string]string{zoneB, zoneA}))
+	assert.False(t, pool.SupportsTopologies([]map[string]string{zoneB}))
+	assert.False(t, pool.SupportsTopologies([]map[string]string{region2}))
+	assert.True(t, pool.PrefersTopologies([]map[string]string{zoneA}))
+	assert.False(t, pool.PrefersTopologies([]map[string]string{zoneB}))
+
+	assert.Equal(t, pool.SupportedTopologies, pool.AccessibleTopologies(nil))
+	assert.Equal(t, []map[string]string{{"region": "r1", "zone": "a"}},
+		pool.AccessibleTopologies([]map[string]string{zoneA, zoneB}))
+	assert.Equal(t, []map[string]string{{"region": "r3"}},
+		pool.AccessibleTopologies([]map[string]string{{"region": "r3", "zone": "c"}}))
+	assert.Empty(t, pool.AccessibleTopologies([]map[string]string{zoneB}))
+}
+
+func TestPoolWithinTopologies(t *testing.T) {
+	pool := &Pool{Name: "anywhere"}
+	assert.True(t, pool.WithinTopologies(nil))
+	assert.True(t, pool.WithinTopologies([]map[string]string{{"zone": "a"}}))
+
+	pool = &Pool{
+		Name:                "zoneA",
+		SupportedTopologies: []map[string]string{{"region": "r1", "zone": "a"}},
+	}
+	assert.True(t, pool.WithinTopologies(nil))
+	assert.True(t, pool.WithinTopologies([]map[string]string{{"zone": "a"}}))
+	assert.True(t, pool.WithinTopologies([]map[string]string{{"region": "r1"}}))
+	assert.True(t, pool.WithinTopologies([]map[string]string{{"zone": "b"}, {"region": "r1", "zone": "a"}}))
+	assert.False(t, pool.WithinTopologies([]map[string]string{{"zone": "b"}}))
+	assert.False(t, pool.WithinTopologies([]map[string]string{{"region": "r2"}}))
+	assert.False(t, pool.WithinTopologies([]map[string]string{{"rack": "1"}}),
+		"a pool should not be within a segment it shares no keys with")
+}
@@ -5,6 +5,7 @@ package storage
 import (
 	"encoding/base64"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -46,6 +47,72 @@ type VolumeConfig struct {
 	ImportBackendUUID         string                 `json:"importBackendUUID,omitempty"`
 	ImportNotManaged          bool                   `json:"importNotManaged,omitempty"`
 	MountOptions              string                 `json:"mountOptions,omitempty"`
+	VolumeGroup               string                 `json:"volumeGroup,omitempty"`
+	Namespace                 string                 `json:"namespace,omitempty"`
+	NamespaceLabels           map[string]string      `json:"namespaceLabels,omitempty"`
+	CloneSourceNamespace      string                 `json:"cloneSourceNamespace,omitempty"`
+	RootOwnership             string                 `json:"rootOwnership,omitempty"`
+	FormatOptions             string                 `json:"formatOptions,omitempty"`
+	FsckPolicy                string                 `json:"fsckPolicy,omitempty"`
+	IOScheduler               string                 `json:"ioScheduler,omitempty"`
+	ReadAheadKB               string                 `json:"readAheadKB,omitempty"`
+	NrRequests                string                 `json:"nrRequests,omitempty"`
+	// IgroupName is the igroup a SAN volume is mapped to, in place of its backend's igroup
+	IgroupName string `json:"igroupName,omitempty"`
+	// DeletionPolicy determines whether deleting the volume destroys its storage
+	DeletionPolicy string `json:"deletionPolicy,omitempty"`
+	// RequisiteTopologies and PreferredTopologies are the topology segments, such as zones, the volume
+	// must be, and should preferably be, accessible from
+	RequisiteTopologies []map[string]string `json:"requisiteTopologies,omitempty"`
+	PreferredTopologies []map[string]string `json:"preferredTopologies,omitempty"`
+	// AllowedTopologies are the topology segments the volume is accessible from, or empty for every node
+	AllowedTopologies []map[string]string `json:"allowedTopologies,omitempty"`
+	// Pool pins the volume to one of its storage class's pools, or to a physical pool such as an ONTAP
+	// aggregate behind them
+	Pool string `json:"pool,omitempty"`
+	// EphemeralNode is the node whose pod uses the volume as a CSI inline ephemeral volume, or empty
+	// for a persistent volume
+	EphemeralNode string `json:"ephemeralNode,omitempty"`
+	// EphemeralPod is the pod, in the volume's namespace, that uses the volume as a CSI inline ephemeral volume
+	EphemeralPod string `json:"ephemeralPod,omitempty"`
+	// CopySourceVolume is the volume whose files are copied into this volume when it is created, as for a
+	// clone whose storage class uses another protocol than its source's backend
+	CopySourceVolume string `json:"copySourceVolume,omitempty"`
+	// CloneTargetSize is the size a clone is expanded to once it is created, if it was requested larger
+	// than its source
+	CloneTargetSize string `json:"cloneTargetSize,omitempty"`
+	// GrowFilesystem is set if the volume was expanded before its filesystem was first staged, so the
+	// filesystem must be grown to fill the volume when it is staged
+	GrowFilesystem bool `json:"growFilesystem,omitempty"`
+}
+
+const (
+	// DeletionPolicyDelete destroys a volume's storage when the volume is deleted, which is the default
+	DeletionPolicyDelete = "delete"
+	// DeletionPolicyUnmanage removes a deleted volume from Trident but leaves its storage intact
+	DeletionPolicyUnmanage = "unmanage"
+)
+
+// ValidateDeletionPolicy checks that a deletion policy is known.  An empty policy is the default.
+func ValidateDeletionPolicy(policy string) error {
+	switch policy {
+	case "", DeletionPolicyDelete, DeletionPolicyUnmanage:
+		return nil
+	default:
+		return fmt.Errorf("invalid deletion policy '%s'; must be %s or %s", policy, DeletionPolicyDelete,
+			DeletionPolicyUnmanage)
+	}
+}
+
+// AllowsMultiNodePublish reports whether a volume may be published to more than one node at a time.
+// Nodes mounting the filesystem on a LUN together would corrupt it, so block volumes may only be shared
+// in raw-block mode, by clustered applications that coordinate their own access to the device.
+func (c *VolumeConfig) AllowsMultiNodePublish() bool {
+	if c.Protocol != config.Block {
+		return true
+	}
+	return c.VolumeMode == config.RawBlock &&
+		(c.AccessMode == config.ReadWriteMany || c.AccessMode == config.ReadOnlyMany)
 }
 
 type VolumeCreatingConfig struct {
@@ -89,6 +156,8 @@ type Volume struct {
 	Pool        string // Name of the pool on which this volume was first provisioned
 	Orphaned    bool   // An Orphaned volume isn't currently tracked by the storage backend
 	State       VolumeState
+	// PublishedNodes are the nodes the volume is currently published to
+	PublishedNodes []string
 }
 
 type VolumeState string
@@ -151,6 +220,8 @@ type VolumeExternal struct {
 	Pool        string      `json:"pool"`
 	Orphaned    bool        `json:"orphaned"`
 	State       VolumeState `json:"state"`
+	// PublishedNodes are the nodes the volume is currently published to
+	PublishedNodes []string `json:"publishedNodes,omitempty"`
 }
 
 func (v *VolumeExternal) GetCHAPSecretName() string {
@@ -163,12 +234,44 @@ func (v *VolumeExternal) GetCHAPSecretName() string {
 
 func (v *Volume) ConstructExternal() *VolumeExternal {
 	return &VolumeExternal{
-		Config:      v.Config,
-		BackendUUID: v.BackendUUID,
-		Pool:        v.Pool,
-		Orphaned:    v.Orphaned,
-		State:       v.State,
+		Config:         v.Config,
+		BackendUUID:    v.BackendUUID,
+		Pool:           v.Pool,
+		Orphaned:       v.Orphaned,
+		State:          v.State,
+		PublishedNodes: v.PublishedNodes,
+	}
+}
+
+// IsPublishedToNode reports whether the volume is published to a node.
+func (v *Volume) IsPublishedToNode(nodeName string) bool {
+	for _, node := range v.PublishedNodes {
+		if node == nodeName {
+			return true
+		}
+	}
+	return false
+}
+
+// AddPublishedNode records that the volume is published to a node, returning false if it already was.
+func (v *Volume) AddPublishedNode(nodeName string) bool {
+	if v.IsPublishedToNode(nodeName) {
+		return false
+	}
+	v.PublishedNodes = append(v.PublishedNodes, nodeName)
+	return true
+}
+
+// RemovePublishedNode records that the volume is no longer published to a node, returning false if it
+// wasn't published there.
+func (v *Volume) RemovePublishedNode(nodeName string) bool {
+	for i, node := range v.PublishedNodes {
+		if node == nodeName {
+			v.PublishedNodes = append(v.PublishedNodes[:i], v.PublishedNodes[i+1:]...)
+			return true
+		}
 	}
+	return false
 }
 
 // VolumeExternalWrapper is used to return volumes and errors via channels between goroutines
@@ -182,6 +285,14 @@ type ImportVolumeRequest struct {
 	InternalName string `json:"internalName"`
 	NoManage     bool   `json:"noManage"`
 	PVCData      string `json:"pvcData"` // Opaque, base64-encoded
+	DryRun       bool   `json:"dryRun,omitempty"`
+}
+
+// ImportVolumeDryRunResult describes the volume that an import would create, along with the changes
+// the import would make to the volume on the storage system.
+type ImportVolumeDryRunResult struct {
+	Volume  *VolumeExternal `json:"volume"`
+	Changes []string        `json:"changes"`
 }
 
 func (r *ImportVolumeRequest) Validate() error {
@@ -209,6 +320,52 @@ func (r *UpgradeVolumeRequest) Validate() error {
 	return nil
 }
 
+type ResizeVolumeRequest struct {
+	Size string `json:"size"`
+}
+
+func (r *ResizeVolumeRequest) Validate() error {
+	if r.Size == "" {
+		return fmt.Errorf("the following field is mandatory: size")
+	}
+	sizeBytes, err := utils.ConvertSizeToBytes(r.Size)
+	if err != nil {
+		return fmt.Errorf("invalid size %s: %v", r.Size, err)
+	}
+	if sizeBytes == "0" {
+		return fmt.Errorf("size must be greater than zero")
+	}
+	return nil
+}
+
+// UnmanageVolumeRequest removes a volume from Trident without deleting its storage.  StorageName is
+// the name to give the storage, by default the original name of an imported volume.
+type UnmanageVolumeRequest struct {
+	StorageName string `json:"storageName,omitempty"`
+}
+
+// VolumeCloneRequest is the body of a request to clone a volume, optionally from one of its snapshots.
+// SplitOnClone overrides whether the clone is split from its source, and Pool places the clone in
+// another of its source backend's pools instead of its source's pool.
+type VolumeCloneRequest struct {
+	Name         string `json:"name"`
+	Snapshot     string `json:"snapshot,omitempty"`
+	SplitOnClone string `json:"splitOnClone,omitempty"`
+	Pool         string `json:"pool,omitempty"`
+}
+
+func (r *VolumeCloneRequest) Validate() error {
+	if r.Name == "" {
+		return fmt.Errorf("a clone requires a name")
+	}
+	if r.SplitOnClone != "" {
+		if _, err := strconv.ParseBool(r.SplitOnClone); err != nil {
+			return fmt.Errorf("invalid value for splitOnClone: %s", r.SplitOnClone)
+		}
+	}
+	return nil
+}
+
 type ByVolumeExternalName []*VolumeExternal
 
 func (a ByVolumeExternalName) Len() int           { return len(a) }
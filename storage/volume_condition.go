@@ -0,0 +1,39 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package storage
+
+import (
+	"fmt"
+	"time"
+)
+
+// VolumeCondition records whether a volume is healthy, as last reported by its driver or by a node.
+// An abnormal condition carries a message describing the problem.
+type VolumeCondition struct {
+	VolumeName string            `json:"volume"`
+	Abnormal   bool              `json:"abnormal"`
+	Message    string            `json:"message,omitempty"`
+	Source     VolumeUsageSource `json:"source,omitempty"`
+	Node       string            `json:"node,omitempty"`
+	Updated    string            `json:"updated"`
+}
+
+// Validate checks a condition report received from a node.
+func (c *VolumeCondition) Validate() error {
+	if c.VolumeName == "" {
+		return fmt.Errorf("the volume name is mandatory")
+	}
+	if c.Abnormal && c.Message == "" {
+		return fmt.Errorf("an abnormal condition must have a message")
+	}
+	return nil
+}
+
+// UpdatedTime returns the time of the report, or the zero time if it is unknown.
+func (c *VolumeCondition) UpdatedTime() time.Time {
+	updated, err := time.Parse(time.RFC3339, c.Updated)
+	if err != nil {
+		return time.Time{}
+	}
+	return updated
+}
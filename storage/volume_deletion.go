@@ -0,0 +1,62 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package storage
+
+import (
+	"time"
+)
+
+type DeletionState string
+
+const (
+	// DeletionStatePending is a deletion that will be retried automatically
+	DeletionStatePending = DeletionState("pending")
+	// DeletionStateFailed is a deletion that reached the maximum number of attempts
+	DeletionStateFailed = DeletionState("failed")
+)
+
+// VolumeDeletion records a volume deletion that failed on the volume's backend and is retried
+// later.  It is persisted with the volume's delete transaction, so retries survive a restart.
+type VolumeDeletion struct {
+	VolumeName   string        `json:"volume"`
+	Backend      string        `json:"backend"`
+	BackendUUID  string        `json:"backendUUID"`
+	State        DeletionState `json:"state"`
+	Attempts     int           `json:"attempts"`
+	LastError    string        `json:"lastError,omitempty"`
+	FirstAttempt string        `json:"firstAttempt"`
+	LastAttempt  string        `json:"lastAttempt"`
+	NextAttempt  string        `json:"nextAttempt,omitempty"`
+}
+
+// RecordFailure records a failed attempt.  The deletion is retried after the backoff, unless
+// maxAttempts have been made.
+func (d *VolumeDeletion) RecordFailure(err error, backoff time.Duration, maxAttempts int) {
+	now := time.Now().UTC()
+	if d.FirstAttempt == "" {
+		d.FirstAttempt = now.Format(time.RFC3339)
+	}
+	d.Attempts++
+	d.LastAttempt = now.Format(time.RFC3339)
+	d.LastError = err.Error()
+
+	if d.Attempts >= maxAttempts {
+		d.State = DeletionStateFailed
+		d.NextAttempt = ""
+	} else {
+		d.State = DeletionStatePending
+		d.NextAttempt = now.Add(backoff).Format(time.RFC3339)
+	}
+}
+
+// IsDue returns true if the deletion should be retried now.
+func (d *VolumeDeletion) IsDue() bool {
+	if d.State != DeletionStatePending {
+		return false
+	}
+	next, err := time.Parse(time.RFC3339, d.NextAttempt)
+	if err != nil {
+		return true
+	}
+	return !time.Now().Before(next)
+}
@@ -0,0 +1,73 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package storage
+
+import (
+	"fmt"
+	"regexp"
+)
+
+var volumeGroupNameRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// VolumeGroupConfig is the body of a request to create a volume group or change its members.  A
+// volume may belong to only one group.
+type VolumeGroupConfig struct {
+	Name    string   `json:"name"`
+	Volumes []string `json:"volumes"`
+}
+
+func (c *VolumeGroupConfig) Validate() error {
+	if !volumeGroupNameRegex.MatchString(c.Name) {
+		return fmt.Errorf("invalid volume group name '%s'", c.Name)
+	}
+	if len(c.Volumes) == 0 {
+		return fmt.Errorf("volume group %s must contain at least one volume", c.Name)
+	}
+	members := make(map[string]bool, len(c.Volumes))
+	for _, volumeName := range c.Volumes {
+		if members[volumeName] {
+			return fmt.Errorf("volume %s is listed more than once in volume group %s", volumeName, c.Name)
+		}
+		members[volumeName] = true
+	}
+	return nil
+}
+
+// VolumeGroupExternal describes a volume group.  Membership is recorded in each member volume's
+// config, so a group exists as long as any volume belongs to it.  Snapshots lists the names of
+// the snapshots present on every member, which are the group snapshots that can be cloned.
+type VolumeGroupExternal struct {
+	Name      string   `json:"name"`
+	Volumes   []string `json:"volumes"`
+	Backends  []string `json:"backends"`
+	Snapshots []string `json:"snapshots"`
+}
+
+// VolumeGroupSnapshotRequest is the body of a request to snapshot the members of a volume group.
+type VolumeGroupSnapshotRequest struct {
+	Name string `json:"name"`
+	// TridentOwned marks the member snapshots as Trident's own, so retention policies may delete them
+	TridentOwned bool `json:"-"`
+}
+
+// VolumeGroupSnapshot is the result of snapshotting a volume group.  The member snapshots were all
+// taken at the same point in time by the storage system, such as with an ONTAP consistency group
+// snapshot.
+type VolumeGroupSnapshot struct {
+	Name        string              `json:"name"`
+	VolumeGroup string              `json:"volumeGroup"`
+	Snapshots   []*SnapshotExternal `json:"snapshots"`
+}
+
+// VolumeGroupCloneRequest is the body of a request to clone the members of a volume group from one
+// of its group snapshots.  Each clone is named after its source volume, prefixed by the new group's
+// name, and the clones form the new group.
+type VolumeGroupCloneRequest struct {
+	Snapshot    string `json:"snapshot"`
+	VolumeGroup string `json:"volumeGroup"`
+}
+
+// GroupCloneVolumeName returns the name of the clone of a group member in a cloned group.
+func GroupCloneVolumeName(groupName, volumeName string) string {
+	return groupName + "-" + volumeName
+}
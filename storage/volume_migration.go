@@ -0,0 +1,145 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package storage
+
+import (
+	"fmt"
+	"time"
+)
+
+type MigrationMethod string
+
+const (
+	// MigrationMethodReplication copies a volume using storage-native replication, such as SnapMirror
+	MigrationMethodReplication = MigrationMethod("replication")
+	// MigrationMethodHostCopy copies a volume's files after mounting both volumes on the Trident host
+	MigrationMethodHostCopy = MigrationMethod("hostCopy")
+	// MigrationMethodVolumeMove moves a volume between the pools of its backend without disrupting its
+	// clients, such as with ONTAP volume move between aggregates
+	MigrationMethodVolumeMove = MigrationMethod("volumeMove")
+)
+
+type MigrationState string
+
+const (
+	MigrationStatePending     = MigrationState("pending")
+	MigrationStateCopying     = MigrationState("copying")
+	MigrationStateCuttingOver = MigrationState("cuttingOver")
+	MigrationStateCompleted   = MigrationState("completed")
+	MigrationStateFailed      = MigrationState("failed")
+)
+
+// IsDone returns true if a migration in this state is no longer running.
+func (s MigrationState) IsDone() bool {
+	return s == MigrationStateCompleted || s == MigrationStateFailed
+}
+
+// VolumeMigrationRequest is the body of a request to migrate a volume to another backend, or to move
+// it to another storage pool of its own backend.
+type VolumeMigrationRequest struct {
+	Volume string `json:"volume"`
+	// Backend defaults to the volume's own backend, in which case a storage pool must be named
+	Backend     string `json:"backend,omitempty"`
+	StoragePool string `json:"storagePool,omitempty"`
+}
+
+func (r *VolumeMigrationRequest) Validate() error {
+	if r.Volume == "" || (r.Backend == "" && r.StoragePool == "") {
+		return fmt.Errorf("the following fields for \"VolumeMigration\" are mandatory: volume, and backend " +
+			"or storagePool")
+	}
+	return nil
+}
+
+// VolumeMigration records the progress of moving a volume from one backend to another.
+type VolumeMigration struct {
+	VolumeName              string          `json:"volume"`
+	SourceBackend           string          `json:"sourceBackend"`
+	SourceBackendUUID       string          `json:"sourceBackendUUID"`
+	SourceInternalName      string          `json:"sourceInternalName"`
+	DestinationBackend      string          `json:"destinationBackend"`
+	DestinationBackendUUID  string          `json:"destinationBackendUUID"`
+	DestinationPool         string          `json:"destinationPool"`
+	DestinationInternalName string          `json:"destinationInternalName"`
+	Method                  MigrationMethod `json:"method"`
+	State                   MigrationState  `json:"state"`
+	BytesTransferred        uint64          `json:"bytesTransferred"`
+	BytesTotal              uint64          `json:"bytesTotal"`
+	Message                 string          `json:"message,omitempty"`
+	StartTime               string          `json:"startTime"`
+	EndTime                 string          `json:"endTime,omitempty"`
+}
+
+// Progress returns the percentage of the volume's data that has been copied.
+func (m *VolumeMigration) Progress() int {
+	if m.State == MigrationStateCompleted {
+		return 100
+	}
+	if m.BytesTotal == 0 {
+		return 0
+	}
+	progress := int(m.BytesTransferred * 100 / m.BytesTotal)
+	if progress > 99 {
+		// Only a completed migration is 100% done
+		progress = 99
+	}
+	return progress
+}
+
+// Finish records the end of a migration, which failed if err is not nil.
+func (m *VolumeMigration) Finish(err error) {
+	m.EndTime = time.Now().UTC().Format(time.RFC3339)
+	if err != nil {
+		m.State = MigrationStateFailed
+		m.Message = err.Error()
+	} else {
+		m.State = MigrationStateCompleted
+		m.Message = ""
+	}
+}
+
+// ConstructExternal returns a copy of the migration suitable for returning from the REST API.
+func (m *VolumeMigration) ConstructExternal() *VolumeMigrationExternal {
+	return &VolumeMigrationExternal{
+		VolumeName:         m.VolumeName,
+		SourceBackend:      m.SourceBackend,
+		DestinationBackend: m.DestinationBackend,
+		DestinationPool:    m.DestinationPool,
+		Method:             m.Method,
+		State:              m.State,
+		Progress:           m.Progress(),
+		BytesTransferred:   m.BytesTransferred,
+		BytesTotal:         m.BytesTotal,
+		Message:            m.Message,
+		StartTime:          m.StartTime,
+		EndTime:            m.EndTime,
+	}
+}
+
+type VolumeMigrationExternal struct {
+	VolumeName         string          `json:"volume"`
+	SourceBackend      string          `json:"sourceBackend"`
+	DestinationBackend string          `json:"destinationBackend"`
+	DestinationPool    string          `json:"destinationPool"`
+	Method             MigrationMethod `json:"method"`
+	State              MigrationState  `json:"state"`
+	Progress           int             `json:"progress"`
+	BytesTransferred   uint64          `json:"bytesTransferred"`
+	BytesTotal         uint64          `json:"bytesTotal"`
+	Message            string          `json:"message,omitempty"`
+	StartTime          string          `json:"startTime"`
+	EndTime            string          `json:"endTime,omitempty"`
+}
+
+// VolumeMigrationPlan describes how a volume would be migrated, such as from one driver to another on
+// the same storage system, and whether the migration may proceed.
+type VolumeMigrationPlan struct {
+	VolumeName         string           `json:"volume"`
+	SourceBackend      string           `json:"sourceBackend"`
+	SourceDriver       string           `json:"sourceDriver"`
+	DestinationBackend string           `json:"destinationBackend"`
+	DestinationDriver  string           `json:"destinationDriver,omitempty"`
+	DestinationPool    string           `json:"destinationPool,omitempty"`
+	Method             MigrationMethod  `json:"method,omitempty"`
+	Preflight          *PreflightReport `json:"preflight"`
+}
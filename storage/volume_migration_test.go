@@ -0,0 +1,35 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package storage
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVolumeMigrationProgress(t *testing.T) {
+
+	migration := &VolumeMigration{State: MigrationStateCopying}
+	assert.Equal(t, 0, migration.Progress(), "progress is unknown without a total")
+
+	migration.BytesTotal = 200
+	migration.BytesTransferred = 50
+	assert.Equal(t, 25, migration.Progress())
+
+	// Replication may transfer more than the volume's logical size
+	migration.BytesTransferred = 400
+	assert.Equal(t, 99, migration.Progress())
+
+	migration.Finish(nil)
+	assert.Equal(t, MigrationStateCompleted, migration.State)
+	assert.Equal(t, 100, migration.ConstructExternal().Progress)
+	assert.NotEmpty(t, migration.EndTime)
+
+	migration = &VolumeMigration{State: MigrationStateCopying}
+	migration.Finish(errors.New("failed"))
+	assert.Equal(t, MigrationStateFailed, migration.State)
+	assert.Equal(t, "failed", migration.Message)
+	assert.True(t, migration.State.IsDone())
+}
@@ -51,3 +51,13 @@ func TestVolumeState(t *testing.T) {
 		assert.True(t, test.predicate(test.input), "Predicate failed")
 	}
 }
+
+func TestResizeVolumeRequestValidate(t *testing.T) {
+
+	assert.NoError(t, (&ResizeVolumeRequest{Size: "200Gi"}).Validate())
+	assert.NoError(t, (&ResizeVolumeRequest{Size: "1073741824"}).Validate())
+
+	assert.Error(t, (&ResizeVolumeRequest{}).Validate(), "size is mandatory")
+	assert.Error(t, (&ResizeVolumeRequest{Size: "lots"}).Validate())
+	assert.Error(t, (&ResizeVolumeRequest{Size: "0"}).Validate())
+}
@@ -20,6 +20,7 @@ const (
 
 	// Transactions for long-running operations
 	VolumeCreating VolumeOperation = "volumeCreating"
+	MigrateVolume  VolumeOperation = "migrateVolume"
 )
 
 type VolumeTransaction struct {
@@ -27,6 +28,9 @@ type VolumeTransaction struct {
 	VolumeCreatingConfig *VolumeCreatingConfig
 	SnapshotConfig       *SnapshotConfig
 	PVUpgradeConfig      *PVUpgradeConfig
+	VolumeMigration      *VolumeMigration
+	VolumeDeletion       *VolumeDeletion
+	Journal              *OperationJournal
 	Op                   VolumeOperation
 }
 
@@ -0,0 +1,63 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package storage
+
+import (
+	"fmt"
+	"time"
+)
+
+type VolumeUsageSource string
+
+const (
+	// VolumeUsageSourceDriver is space reported by the storage system
+	VolumeUsageSourceDriver = VolumeUsageSource("driver")
+	// VolumeUsageSourceNode is space reported by the filesystem on a node where the volume is mounted
+	VolumeUsageSourceNode = VolumeUsageSource("node")
+)
+
+// VolumeUsage records the space consumed by a volume, as last reported by its driver or by a node.
+type VolumeUsage struct {
+	VolumeName     string            `json:"volume"`
+	TotalBytes     uint64            `json:"totalBytes"`
+	UsedBytes      uint64            `json:"usedBytes"`
+	AvailableBytes uint64            `json:"availableBytes"`
+	SnapshotBytes  uint64            `json:"snapshotBytes,omitempty"`
+	SavedBytes     uint64            `json:"savedBytes,omitempty"` // saved by deduplication and compression
+	TotalInodes    uint64            `json:"totalInodes,omitempty"`
+	UsedInodes     uint64            `json:"usedInodes,omitempty"`
+	Source         VolumeUsageSource `json:"source"`
+	Node           string            `json:"node,omitempty"`
+	Updated        string            `json:"updated"`
+}
+
+// Validate checks a usage report received from a node.
+func (u *VolumeUsage) Validate() error {
+	if u.VolumeName == "" {
+		return fmt.Errorf("the volume name is mandatory")
+	}
+	if u.UsedBytes > u.TotalBytes {
+		return fmt.Errorf("used bytes (%d) exceed total bytes (%d)", u.UsedBytes, u.TotalBytes)
+	}
+	if u.UsedInodes > u.TotalInodes {
+		return fmt.Errorf("used inodes (%d) exceed total inodes (%d)", u.UsedInodes, u.TotalInodes)
+	}
+	return nil
+}
+
+// UsedPercent returns the percentage of the volume's space that is used.
+func (u *VolumeUsage) UsedPercent() int {
+	if u.TotalBytes == 0 {
+		return 0
+	}
+	return int(u.UsedBytes * 100 / u.TotalBytes)
+}
+
+// UpdatedTime returns the time of the report, or the zero time if it is unknown.
+func (u *VolumeUsage) UpdatedTime() time.Time {
+	updated, err := time.Parse(time.RFC3339, u.Updated)
+	if err != nil {
+		return time.Time{}
+	}
+	return updated
+}
@@ -41,6 +41,18 @@ const (
 	StoragePools           = "storagePools"
 	AdditionalStoragePools = "additionalStoragePools"
 	ExcludeStoragePools    = "excludeStoragePools"
+	SnapshotRetention      = "snapshotRetention"
+	Autogrow               = "autogrow"
+	MountOptions           = "mountOptions"
+	FormatOptions          = "formatOptions"
+	FsckPolicy             = "fsckPolicy"
+	IOScheduler            = "ioScheduler"
+	ReadAheadKB            = "readAheadKB"
+	NrRequests             = "nrRequests"
+	RootOwnership          = "rootOwnership"
+	DeletionPolicy         = "deletionPolicy"
+	ExportPolicy           = "exportPolicy"
+	IgroupName             = "igroupName"
 )
 
 var attrTypes = map[string]Type{
@@ -5,19 +5,33 @@ package storageclass
 import (
 	"encoding/json"
 
+	"github.com/netapp/trident/storage"
 	storageattribute "github.com/netapp/trident/storage_attribute"
 )
 
 // UnmarshalJSON parses a JSON-formatted byte array into a storage class config struct.
 func (c *Config) UnmarshalJSON(data []byte) error {
 	var tmp struct {
-		Version         string              `json:"version"`
-		Name            string              `json:"name"`
-		Attributes      json.RawMessage     `json:"attributes,omitempty"`
-		Pools           map[string][]string `json:"storagePools,omitempty"`
-		RequiredStorage map[string][]string `json:"requiredStorage,omitempty"`
-		AdditionalPools map[string][]string `json:"additionalStoragePools,omitempty"`
-		ExcludePools    map[string][]string `json:"excludeStoragePools,omitempty"`
+		Version           string                           `json:"version"`
+		Name              string                           `json:"name"`
+		Attributes        json.RawMessage                  `json:"attributes,omitempty"`
+		Pools             map[string][]string              `json:"storagePools,omitempty"`
+		RequiredStorage   map[string][]string              `json:"requiredStorage,omitempty"`
+		AdditionalPools   map[string][]string              `json:"additionalStoragePools,omitempty"`
+		ExcludePools      map[string][]string              `json:"excludeStoragePools,omitempty"`
+		SnapshotRetention *storage.SnapshotRetentionPolicy `json:"snapshotRetention,omitempty"`
+		Autogrow          *storage.AutogrowPolicy          `json:"autogrow,omitempty"`
+		MountOptions      string                           `json:"mountOptions,omitempty"`
+		FormatOptions     string                           `json:"formatOptions,omitempty"`
+		FsckPolicy        string                           `json:"fsckPolicy,omitempty"`
+		IOScheduler       string                           `json:"ioScheduler,omitempty"`
+		ReadAheadKB       string                           `json:"readAheadKB,omitempty"`
+		NrRequests        string                           `json:"nrRequests,omitempty"`
+		RootOwnership     string                           `json:"rootOwnership,omitempty"`
+		DeletionPolicy    string                           `json:"deletionPolicy,omitempty"`
+		ExportPolicy      string                           `json:"exportPolicy,omitempty"`
+		IgroupName        string                           `json:"igroupName,omitempty"`
+		AllowedTopologies []map[string]string              `json:"allowedTopologies,omitempty"`
 	}
 	err := json.Unmarshal(data, &tmp)
 	if err != nil {
@@ -36,6 +50,19 @@ func (c *Config) UnmarshalJSON(data []byte) error {
 	}
 
 	c.ExcludePools = tmp.ExcludePools
+	c.SnapshotRetention = tmp.SnapshotRetention
+	c.Autogrow = tmp.Autogrow
+	c.MountOptions = tmp.MountOptions
+	c.FormatOptions = tmp.FormatOptions
+	c.FsckPolicy = tmp.FsckPolicy
+	c.IOScheduler = tmp.IOScheduler
+	c.ReadAheadKB = tmp.ReadAheadKB
+	c.NrRequests = tmp.NrRequests
+	c.RootOwnership = tmp.RootOwnership
+	c.DeletionPolicy = tmp.DeletionPolicy
+	c.ExportPolicy = tmp.ExportPolicy
+	c.IgroupName = tmp.IgroupName
+	c.AllowedTopologies = tmp.AllowedTopologies
 
 	return err
 }
@@ -43,18 +70,44 @@ func (c *Config) UnmarshalJSON(data []byte) error {
 // MarshalJSON emits a storage class config struct as a JSON-formatted byte array.
 func (c *Config) MarshalJSON() ([]byte, error) {
 	var tmp struct {
-		Version         string              `json:"version"`
-		Name            string              `json:"name"`
-		Attributes      json.RawMessage     `json:"attributes,omitempty"`
-		Pools           map[string][]string `json:"storagePools,omitempty"`
-		AdditionalPools map[string][]string `json:"additionalStoragePools,omitempty"`
-		ExcludePools    map[string][]string `json:"excludeStoragePools,omitempty"`
+		Version           string                           `json:"version"`
+		Name              string                           `json:"name"`
+		Attributes        json.RawMessage                  `json:"attributes,omitempty"`
+		Pools             map[string][]string              `json:"storagePools,omitempty"`
+		AdditionalPools   map[string][]string              `json:"additionalStoragePools,omitempty"`
+		ExcludePools      map[string][]string              `json:"excludeStoragePools,omitempty"`
+		SnapshotRetention *storage.SnapshotRetentionPolicy `json:"snapshotRetention,omitempty"`
+		Autogrow          *storage.AutogrowPolicy          `json:"autogrow,omitempty"`
+		MountOptions      string                           `json:"mountOptions,omitempty"`
+		FormatOptions     string                           `json:"formatOptions,omitempty"`
+		FsckPolicy        string                           `json:"fsckPolicy,omitempty"`
+		IOScheduler       string                           `json:"ioScheduler,omitempty"`
+		ReadAheadKB       string                           `json:"readAheadKB,omitempty"`
+		NrRequests        string                           `json:"nrRequests,omitempty"`
+		RootOwnership     string                           `json:"rootOwnership,omitempty"`
+		DeletionPolicy    string                           `json:"deletionPolicy,omitempty"`
+		ExportPolicy      string                           `json:"exportPolicy,omitempty"`
+		IgroupName        string                           `json:"igroupName,omitempty"`
+		AllowedTopologies []map[string]string              `json:"allowedTopologies,omitempty"`
 	}
 	tmp.Version = c.Version
 	tmp.Name = c.Name
 	tmp.Pools = c.Pools
 	tmp.AdditionalPools = c.AdditionalPools
 	tmp.ExcludePools = c.ExcludePools
+	tmp.SnapshotRetention = c.SnapshotRetention
+	tmp.Autogrow = c.Autogrow
+	tmp.MountOptions = c.MountOptions
+	tmp.FormatOptions = c.FormatOptions
+	tmp.FsckPolicy = c.FsckPolicy
+	tmp.IOScheduler = c.IOScheduler
+	tmp.ReadAheadKB = c.ReadAheadKB
+	tmp.NrRequests = c.NrRequests
+	tmp.RootOwnership = c.RootOwnership
+	tmp.DeletionPolicy = c.DeletionPolicy
+	tmp.ExportPolicy = c.ExportPolicy
+	tmp.IgroupName = c.IgroupName
+	tmp.AllowedTopologies = c.AllowedTopologies
 	attrs, err := storageattribute.MarshalRequestMap(c.Attributes)
 	if err != nil {
 		return nil, err
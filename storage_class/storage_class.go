@@ -227,7 +227,7 @@ func (s *StorageClass) CheckAndAddBackend(b *storage.Backend) int {
 		"storageClass": s.GetName(),
 	}).Debug("Checking backend for storage class")
 
-	if !b.State.IsOnline() {
+	if !b.State.IsServing() {
 		log.WithField("backend", b.Name).Warn("Backend not online.")
 		return 0
 	}
@@ -282,6 +282,84 @@ func (s *StorageClass) GetStoragePools() map[string][]string {
 	return s.config.Pools
 }
 
+// GetSnapshotRetentionPolicy returns the storage class's snapshot retention policy, or nil if it
+// uses the orchestrator's default policy.
+func (s *StorageClass) GetSnapshotRetentionPolicy() *storage.SnapshotRetentionPolicy {
+	return s.config.SnapshotRetention
+}
+
+// GetAutogrowPolicy returns the storage class's autogrow policy, or nil if its volumes aren't
+// expanded automatically.
+func (s *StorageClass) GetAutogrowPolicy() *storage.AutogrowPolicy {
+	return s.config.Autogrow
+}
+
+// GetMountOptions returns the mount options for the storage class's volumes, or an empty string if
+// the backend's mount options should be used.
+func (s *StorageClass) GetMountOptions() string {
+	return s.config.MountOptions
+}
+
+// GetFormatOptions returns the mkfs options for the storage class's new block volumes, or an empty
+// string if the default options should be used.
+func (s *StorageClass) GetFormatOptions() string {
+	return s.config.FormatOptions
+}
+
+// GetFsckPolicy returns the policy for checking the filesystems of the storage class's block volumes
+// when they are staged, or an empty string if they aren't checked.
+func (s *StorageClass) GetFsckPolicy() string {
+	return s.config.FsckPolicy
+}
+
+// GetIOScheduler returns the I/O scheduler for the block devices of the storage class's volumes, or
+// an empty string if the node's default scheduler should be used.
+func (s *StorageClass) GetIOScheduler() string {
+	return s.config.IOScheduler
+}
+
+// GetReadAheadKB returns the read-ahead, in KiB, for the block devices of the storage class's volumes,
+// or an empty string if the node's default should be used.
+func (s *StorageClass) GetReadAheadKB() string {
+	return s.config.ReadAheadKB
+}
+
+// GetNrRequests returns the request queue depth for the block devices of the storage class's volumes,
+// or an empty string if the node's default should be used.
+func (s *StorageClass) GetNrRequests() string {
+	return s.config.NrRequests
+}
+
+// GetRootOwnership returns the uid:gid that owns the root directory of the storage class's new
+// volumes, or an empty string if the backend's default ownership should be used.
+func (s *StorageClass) GetRootOwnership() string {
+	return s.config.RootOwnership
+}
+
+// GetDeletionPolicy returns the deletion policy of the storage class's volumes, or an empty string
+// if their storage should be destroyed when they are deleted.
+func (s *StorageClass) GetDeletionPolicy() string {
+	return s.config.DeletionPolicy
+}
+
+// GetExportPolicy returns the export policy of the storage class's NAS volumes, or an empty string if
+// the backend's export policy should be used.
+func (s *StorageClass) GetExportPolicy() string {
+	return s.config.ExportPolicy
+}
+
+// GetIgroupName returns the igroup the storage class's SAN volumes are mapped to, or an empty string
+// if the backend's igroup should be used.
+func (s *StorageClass) GetIgroupName() string {
+	return s.config.IgroupName
+}
+
+// GetAllowedTopologies returns the topology segments the storage class's volumes must be accessible
+// from, or nil if they may be placed in any pool.
+func (s *StorageClass) GetAllowedTopologies() []map[string]string {
+	return s.config.AllowedTopologies
+}
+
 func (s *StorageClass) GetAdditionalStoragePools() map[string][]string {
 	return s.config.AdditionalPools
 }
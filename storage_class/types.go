@@ -21,6 +21,32 @@ type Config struct {
 	Pools           map[string][]string                 `json:"storagePools,omitempty"`
 	AdditionalPools map[string][]string                 `json:"additionalStoragePools,omitempty"`
 	ExcludePools    map[string][]string                 `json:"excludeStoragePools,omitempty"`
+	// SnapshotRetention overrides the orchestrator's default snapshot retention policy
+	SnapshotRetention *storage.SnapshotRetentionPolicy `json:"snapshotRetention,omitempty"`
+	// Autogrow expands the storage class's volumes as their filesystems fill up
+	Autogrow *storage.AutogrowPolicy `json:"autogrow,omitempty"`
+	// MountOptions are used for the storage class's volumes unless the volume specifies its own
+	MountOptions string `json:"mountOptions,omitempty"`
+	// FormatOptions are the mkfs options used when the storage class's new block volumes are formatted
+	FormatOptions string `json:"formatOptions,omitempty"`
+	// FsckPolicy determines whether the filesystems of the storage class's block volumes are checked when
+	// they are staged
+	FsckPolicy string `json:"fsckPolicy,omitempty"`
+	// IOScheduler, ReadAheadKB and NrRequests tune the block devices of the storage class's volumes on
+	// the nodes they are staged on
+	IOScheduler string `json:"ioScheduler,omitempty"`
+	ReadAheadKB string `json:"readAheadKB,omitempty"`
+	NrRequests  string `json:"nrRequests,omitempty"`
+	// RootOwnership is the uid:gid that owns the root directory of the storage class's new volumes
+	RootOwnership string `json:"rootOwnership,omitempty"`
+	// DeletionPolicy determines whether deleting the storage class's volumes destroys their storage
+	DeletionPolicy string `json:"deletionPolicy,omitempty"`
+	// ExportPolicy is the export policy of the storage class's NAS volumes, in place of the backend's
+	ExportPolicy string `json:"exportPolicy,omitempty"`
+	// IgroupName is the igroup the storage class's SAN volumes are mapped to, in place of the backend's
+	IgroupName string `json:"igroupName,omitempty"`
+	// AllowedTopologies are the topology segments the storage class's volumes must be accessible from
+	AllowedTopologies []map[string]string `json:"allowedTopologies,omitempty"`
 }
 
 type External struct {
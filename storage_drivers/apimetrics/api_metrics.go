@@ -0,0 +1,121 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+// Package apimetrics records the latency, outcome, and retry counts of calls made by the storage
+// drivers' API clients, labeled by backend so that slow or failing storage systems stand out.
+package apimetrics
+
+import (
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/netapp/trident/config"
+)
+
+const (
+	ResultSuccess        = "success"
+	ResultTransportError = "transport_error"
+)
+
+// staticPathSegmentRegex matches the URL path segments that name REST resources, as opposed to
+// segments holding IDs, which would give the operation label an unbounded number of values.
+var staticPathSegmentRegex = regexp.MustCompile(`^([A-Za-z_-]+|v[0-9]+)$`)
+
+var (
+	apiOperationsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: config.OrchestratorName,
+			Subsystem: "storage_api",
+			Name:      "ops_total",
+			Help:      "The total number of storage API calls by backend, operation, and result",
+		},
+		[]string{"backend", "type", "op", "result"},
+	)
+	apiOperationDurationHistogram = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: config.OrchestratorName,
+			Subsystem: "storage_api",
+			Name:      "operation_duration_seconds",
+			Help:      "The duration of storage API calls by backend and operation",
+			Buckets:   prometheus.ExponentialBuckets(0.005, 2, 14),
+		},
+		[]string{"backend", "type", "op"},
+	)
+	apiRetriesTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: config.OrchestratorName,
+			Subsystem: "storage_api",
+			Name:      "retries_total",
+			Help:      "The total number of retried storage API calls by backend and operation",
+		},
+		[]string{"backend", "type", "op"},
+	)
+)
+
+// Recorder records metrics for the API calls made on behalf of one backend.  A nil Recorder
+// is valid and records nothing, so clients created outside of a driver need not supply one.
+type Recorder struct {
+	Backend    string
+	DriverType string
+}
+
+// NewRecorder returns a Recorder for the named backend and driver type.
+func NewRecorder(backend, driverType string) *Recorder {
+	return &Recorder{Backend: backend, DriverType: driverType}
+}
+
+// Start begins timing an API call and returns a function that must be called with the call's result,
+// which is ResultSuccess, ResultTransportError, or a storage-specific error code.
+func (r *Recorder) Start(operation string) func(result string) {
+	if r == nil {
+		return func(string) {}
+	}
+	startTime := time.Now()
+	return func(result string) {
+		apiOperationDurationHistogram.WithLabelValues(r.Backend, r.DriverType, operation).
+			Observe(time.Since(startTime).Seconds())
+		apiOperationsTotal.WithLabelValues(r.Backend, r.DriverType, operation, result).Inc()
+	}
+}
+
+// RecordRetry counts one retry of an API call.  The cloud clients retry calls that fail transiently,
+// while the ONTAP, SolidFire and E-Series clients don't retry failed calls, so for them this counts each
+// repeated poll of an operation that hasn't finished yet, and each re-read that works around a failed call.
+func (r *Recorder) RecordRetry(operation string) {
+	if r == nil {
+		return
+	}
+	apiRetriesTotal.WithLabelValues(r.Backend, r.DriverType, operation).Inc()
+}
+
+// ResultForHTTPStatus converts an HTTP status code to a result label.
+func ResultForHTTPStatus(statusCode int) string {
+	if statusCode >= 200 && statusCode < 300 {
+		return ResultSuccess
+	}
+	return "http_" + strconv.Itoa(statusCode)
+}
+
+// OperationForREST returns the operation label for a REST call, which is the HTTP method followed by
+// the request path with any resource IDs replaced by a placeholder, i.e. "GET /v1/Volumes/{id}".
+func OperationForREST(method, requestURL string) string {
+
+	path := requestURL
+	if parsedURL, err := url.Parse(requestURL); err == nil {
+		path = parsedURL.Path
+	}
+
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if segment != "" && !staticPathSegmentRegex.MatchString(segment) {
+			segments[i] = "{id}"
+		}
+	}
+
+	return method + " " + strings.Join(segments, "/")
+}
@@ -0,0 +1,50 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package apimetrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecorder(t *testing.T) {
+
+	recorder := NewRecorder("backend1", "ontap-nas")
+
+	recorder.Start("volume-create")(ResultSuccess)
+	recorder.Start("volume-create")("13001")
+	recorder.RecordRetry("volume-create")
+
+	assert.Equal(t, float64(1),
+		testutil.ToFloat64(apiOperationsTotal.WithLabelValues("backend1", "ontap-nas", "volume-create", ResultSuccess)))
+	assert.Equal(t, float64(1),
+		testutil.ToFloat64(apiOperationsTotal.WithLabelValues("backend1", "ontap-nas", "volume-create", "13001")))
+	assert.Equal(t, float64(1),
+		testutil.ToFloat64(apiRetriesTotal.WithLabelValues("backend1", "ontap-nas", "volume-create")))
+
+	// A nil recorder is a no-op
+	var nilRecorder *Recorder
+	nilRecorder.Start("volume-create")(ResultSuccess)
+	nilRecorder.RecordRetry("volume-create")
+}
+
+func TestResultForHTTPStatus(t *testing.T) {
+	assert.Equal(t, ResultSuccess, ResultForHTTPStatus(200))
+	assert.Equal(t, ResultSuccess, ResultForHTTPStatus(204))
+	assert.Equal(t, "http_404", ResultForHTTPStatus(404))
+}
+
+func TestOperationForREST(t *testing.T) {
+
+	tests := map[string]string{
+		"https://cv.example.com:8080/v1/FileSystems":                                      "GET /v1/FileSystems",
+		"https://cv.example.com:8080/v1/FileSystems/c3f5a4e2-12cd-4c1e-9f7e-0a1b2c3d4e5f": "GET /v1/FileSystems/{id}",
+		"https://api.example.com/v2/projects/123456/locations/us-east4/Volumes":           "GET /v2/projects/{id}/locations/{id}/Volumes",
+		"/volumes/02000000600A098000A4B9D100000F7D5C3E6C31/expand":                        "GET /volumes/{id}/expand",
+	}
+	for requestURL, expected := range tests {
+		assert.Equal(t, expected, OperationForREST("GET", requestURL), requestURL)
+	}
+}
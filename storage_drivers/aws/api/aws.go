@@ -5,6 +5,7 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"encoding/json"
 	"errors"
@@ -18,6 +19,7 @@ import (
 	"github.com/cenkalti/backoff/v4"
 	log "github.com/sirupsen/logrus"
 
+	"github.com/netapp/trident/storage_drivers/apimetrics"
 	"github.com/netapp/trident/utils"
 )
 
@@ -40,24 +42,46 @@ type ClientConfig struct {
 
 	// Options
 	DebugTraceFlags map[string]bool
+
+	// Identifies the backend in API metrics
+	BackendName       string
+	StorageDriverName string
 }
 
 type Client struct {
-	config *ClientConfig
-	m      *sync.Mutex
+	config  *ClientConfig
+	m       *sync.Mutex
+	metrics *apimetrics.Recorder
+
+	ctx context.Context
 }
 
 // NewDriver is a factory method for creating a new instance.
 func NewDriver(config ClientConfig) *Client {
 
 	d := &Client{
-		config: &config,
-		m:      &sync.Mutex{},
+		config:  &config,
+		m:       &sync.Mutex{},
+		metrics: apimetrics.NewRecorder(config.BackendName, config.StorageDriverName),
 	}
 
 	return d
 }
 
+// WithContext returns a copy of this client whose requests are cancelled when the context is done.
+func (d *Client) WithContext(ctx context.Context) *Client {
+	clone := *d
+	clone.ctx = ctx
+	return &clone
+}
+
+func (d *Client) requestContext() context.Context {
+	if d.ctx == nil {
+		return context.Background()
+	}
+	return d.ctx
+}
+
 func (d *Client) makeURL(resourcePath string) string {
 	return fmt.Sprintf("%s%s", d.config.APIURL, resourcePath)
 }
@@ -71,9 +95,9 @@ func (d *Client) InvokeAPI(requestBody []byte, method string, awsURL string) (*h
 	var err error
 
 	if requestBody == nil {
-		request, err = http.NewRequest(method, awsURL, nil)
+		request, err = http.NewRequestWithContext(d.requestContext(), method, awsURL, nil)
 	} else {
-		request, err = http.NewRequest(method, awsURL, bytes.NewBuffer(requestBody))
+		request, err = http.NewRequestWithContext(d.requestContext(), method, awsURL, bytes.NewBuffer(requestBody))
 	}
 	if err != nil {
 		return nil, nil, err
@@ -116,12 +140,16 @@ func (d *Client) InvokeAPI(requestBody []byte, method string, awsURL string) (*h
 		Transport: tr,
 		Timeout:   httpTimeoutSeconds * time.Second,
 	}
+	operation := apimetrics.OperationForREST(method, awsURL)
+	recordResult := d.metrics.Start(operation)
 	response, err = d.invokeAPINoRetry(client, request)
 
 	if response == nil && err != nil {
+		recordResult(apimetrics.ResultTransportError)
 		log.Warnf("Error communicating with AWS REST interface. %v", err)
 		return nil, nil, err
 	} else if response != nil {
+		recordResult(apimetrics.ResultForHTTPStatus(response.StatusCode))
 		defer func() { _ = response.Body.Close() }()
 	}
 
@@ -142,7 +170,9 @@ func (d *Client) invokeAPINoRetry(client *http.Client, request *http.Request) (*
 	return client.Do(request)
 }
 
-func (d *Client) invokeAPIWithRetry(client *http.Client, request *http.Request) (*http.Response, error) {
+func (d *Client) invokeAPIWithRetry(
+	client *http.Client, request *http.Request, operation string,
+) (*http.Response, error) {
 
 	var response *http.Response
 	var err error
@@ -166,6 +196,7 @@ func (d *Client) invokeAPIWithRetry(client *http.Client, request *http.Request)
 		return nil
 	}
 	invokeNotify := func(err error, duration time.Duration) {
+		d.metrics.RecordRetry(operation)
 		log.WithFields(log.Fields{
 			"increment": duration,
 			"message":   err.Error(),
@@ -3,6 +3,7 @@
 package aws
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -418,11 +419,13 @@ func (d *NFSStorageDriver) initializeAWSAPIClient(
 	}
 
 	client := api.NewDriver(api.ClientConfig{
-		APIURL:          config.APIURL,
-		APIKey:          config.APIKey,
-		SecretKey:       config.SecretKey,
-		ProxyURL:        config.ProxyURL,
-		DebugTraceFlags: config.DebugTraceFlags,
+		APIURL:            config.APIURL,
+		APIKey:            config.APIKey,
+		SecretKey:         config.SecretKey,
+		ProxyURL:          config.ProxyURL,
+		DebugTraceFlags:   config.DebugTraceFlags,
+		BackendName:       config.BackendName,
+		StorageDriverName: config.StorageDriverName,
 	})
 
 	return client, nil
@@ -537,9 +540,11 @@ func (d *NFSStorageDriver) validate() error {
 
 // Create a volume with the specified options
 func (d *NFSStorageDriver) Create(
-	volConfig *storage.VolumeConfig, storagePool *storage.Pool, volAttributes map[string]sa.Request,
+	ctx context.Context, volConfig *storage.VolumeConfig, storagePool *storage.Pool, volAttributes map[string]sa.Request,
 ) error {
 
+	client := d.API.WithContext(ctx)
+
 	name := volConfig.InternalName
 
 	if d.Config.DebugTraceFlags["method"] {
@@ -568,7 +573,7 @@ func (d *NFSStorageDriver) Create(
 	}
 
 	// If the volume already exists, bail out
-	volumeExists, extantVolume, err := d.API.VolumeExistsByCreationToken(name)
+	volumeExists, extantVolume, err := client.VolumeExistsByCreationToken(name)
 	if err != nil {
 		return fmt.Errorf("error checking for existing volume: %v", err)
 	}
@@ -705,7 +710,7 @@ func (d *NFSStorageDriver) Create(
 	}
 
 	// Create the volume
-	volume, err := d.API.CreateVolume(createRequest)
+	volume, err := client.CreateVolume(createRequest)
 	if err != nil {
 		return err
 	}
@@ -715,7 +720,9 @@ func (d *NFSStorageDriver) Create(
 }
 
 // CreateClone clones an existing volume.  If a snapshot is not specified, one is created.
-func (d *NFSStorageDriver) CreateClone(volConfig *storage.VolumeConfig, _ *storage.Pool) error {
+func (d *NFSStorageDriver) CreateClone(ctx context.Context, volConfig *storage.VolumeConfig, _ *storage.Pool) error {
+
+	client := d.API.WithContext(ctx)
 
 	name := volConfig.InternalName
 	source := volConfig.CloneSourceVolumeInternal
@@ -745,7 +752,7 @@ func (d *NFSStorageDriver) CreateClone(volConfig *storage.VolumeConfig, _ *stora
 	}
 
 	// If the volume already exists, bail out
-	volumeExists, extantVolume, err := d.API.VolumeExistsByCreationToken(name)
+	volumeExists, extantVolume, err := client.VolumeExistsByCreationToken(name)
 	if err != nil {
 		return fmt.Errorf("error checking for existing volume: %v", err)
 	}
@@ -759,7 +766,7 @@ func (d *NFSStorageDriver) CreateClone(volConfig *storage.VolumeConfig, _ *stora
 	}
 
 	// Get the source volume by creation token (efficient query) to get its ID
-	sourceVolume, err := d.API.GetVolumeByCreationToken(source)
+	sourceVolume, err := client.GetVolumeByCreationToken(source)
 	if err != nil {
 		return fmt.Errorf("could not find source volume: %v", err)
 	}
@@ -769,7 +776,7 @@ func (d *NFSStorageDriver) CreateClone(volConfig *storage.VolumeConfig, _ *stora
 	}
 
 	// Get the source volume by ID to get all details
-	sourceVolume, err = d.API.GetVolumeByID(sourceVolume.FileSystemID)
+	sourceVolume, err = client.GetVolumeByID(sourceVolume.FileSystemID)
 	if err != nil {
 		return fmt.Errorf("could not get source volume details: %v", err)
 	}
@@ -779,7 +786,7 @@ func (d *NFSStorageDriver) CreateClone(volConfig *storage.VolumeConfig, _ *stora
 	if snapshot != "" {
 
 		// Get the source snapshot
-		sourceSnapshot, err = d.API.GetSnapshotForVolume(sourceVolume, snapshot)
+		sourceSnapshot, err = client.GetSnapshotForVolume(sourceVolume, snapshot)
 		if err != nil {
 			return fmt.Errorf("could not find source snapshot: %v", err)
 		}
@@ -799,13 +806,13 @@ func (d *NFSStorageDriver) CreateClone(volConfig *storage.VolumeConfig, _ *stora
 			Region:       sourceVolume.Region,
 		}
 
-		sourceSnapshot, err = d.API.CreateSnapshot(snapshotCreateRequest)
+		sourceSnapshot, err = client.CreateSnapshot(snapshotCreateRequest)
 		if err != nil {
 			return fmt.Errorf("could not create source snapshot: %v", err)
 		}
 
 		// Wait for snapshot creation to complete
-		err = d.API.WaitForSnapshotState(sourceSnapshot, api.StateAvailable, []string{api.StateError}, d.defaultTimeout())
+		err = client.WaitForSnapshotState(sourceSnapshot, api.StateAvailable, []string{api.StateError}, d.defaultTimeout())
 		if err != nil {
 			return err
 		}
@@ -832,7 +839,7 @@ func (d *NFSStorageDriver) CreateClone(volConfig *storage.VolumeConfig, _ *stora
 	}
 
 	// Clone the volume
-	clone, err := d.API.CreateVolume(createRequest)
+	clone, err := client.CreateVolume(createRequest)
 	if err != nil {
 		return err
 	}
@@ -841,7 +848,7 @@ func (d *NFSStorageDriver) CreateClone(volConfig *storage.VolumeConfig, _ *stora
 	return d.waitForVolumeCreate(clone, name)
 }
 
-func (d *NFSStorageDriver) Import(volConfig *storage.VolumeConfig, originalName string) error {
+func (d *NFSStorageDriver) Import(ctx context.Context, volConfig *storage.VolumeConfig, originalName string) error {
 
 	if d.Config.DebugTraceFlags["method"] {
 		fields := log.Fields{
@@ -854,10 +861,12 @@ func (d *NFSStorageDriver) Import(volConfig *storage.VolumeConfig, originalName
 		defer log.WithFields(fields).Debug("<<<< Import")
 	}
 
+	client := d.API.WithContext(ctx)
+
 	// Get the volume
 	creationToken := originalName
 
-	volume, err := d.API.GetVolumeByCreationToken(creationToken)
+	volume, err := client.GetVolumeByCreationToken(creationToken)
 	if err != nil {
 		return fmt.Errorf("could not find volume %s: %v", creationToken, err)
 	}
@@ -867,11 +876,11 @@ func (d *NFSStorageDriver) Import(volConfig *storage.VolumeConfig, originalName
 
 	// Update the volume labels if Trident will manage its lifecycle
 	if !volConfig.ImportNotManaged {
-		if _, err := d.API.RelabelVolume(volume, d.updateTelemetryLabels(volume)); err != nil {
+		if _, err := client.RelabelVolume(volume, d.updateTelemetryLabels(volume)); err != nil {
 			log.WithField("originalName", originalName).Errorf("Could not import volume, relabel failed: %v", err)
 			return fmt.Errorf("could not import volume %s, relabel failed: %v", originalName, err)
 		}
-		_, err = d.API.WaitForVolumeState(volume, api.StateAvailable, []string{api.StateError}, d.defaultTimeout())
+		_, err = client.WaitForVolumeState(volume, api.StateAvailable, []string{api.StateError}, d.defaultTimeout())
 		if err != nil {
 			return fmt.Errorf("could not import volume %s: %v", originalName, err)
 		}
@@ -985,7 +994,7 @@ func (d *NFSStorageDriver) waitForVolumeCreate(volume *api.FileSystem, volumeNam
 }
 
 // Destroy deletes a volume.
-func (d *NFSStorageDriver) Destroy(name string) error {
+func (d *NFSStorageDriver) Destroy(ctx context.Context, name string) error {
 
 	if d.Config.DebugTraceFlags["method"] {
 		fields := log.Fields{
@@ -997,8 +1006,10 @@ func (d *NFSStorageDriver) Destroy(name string) error {
 		defer log.WithFields(fields).Debug("<<<< Destroy")
 	}
 
+	client := d.API.WithContext(ctx)
+
 	// If volume doesn't exist, return success
-	volumeExists, extantVolume, err := d.API.VolumeExistsByCreationToken(name)
+	volumeExists, extantVolume, err := client.VolumeExistsByCreationToken(name)
 	if err != nil {
 		return err
 	}
@@ -1007,30 +1018,34 @@ func (d *NFSStorageDriver) Destroy(name string) error {
 		return nil
 	} else if extantVolume.LifeCycleState == api.StateDeleting {
 		// This is a retry, so give it more time before giving up again.
-		_, err = d.API.WaitForVolumeState(extantVolume, api.StateDeleted, []string{api.StateError}, d.volumeCreateTimeout)
+		_, err = client.WaitForVolumeState(extantVolume, api.StateDeleted, []string{api.StateError}, d.volumeCreateTimeout)
 		return err
 	}
 
 	// Get the volume
-	volume, err := d.API.GetVolumeByCreationToken(name)
+	volume, err := client.GetVolumeByCreationToken(name)
 	if err != nil {
 		return err
 	}
 
 	// Delete the volume
-	if err = d.API.DeleteVolume(volume); err != nil {
+	if err = client.DeleteVolume(volume); err != nil {
 		return err
 	}
 
 	// Wait for deletion to complete
-	_, err = d.API.WaitForVolumeState(volume, api.StateDeleted, []string{api.StateError}, d.defaultTimeout())
+	_, err = client.WaitForVolumeState(volume, api.StateDeleted, []string{api.StateError}, d.defaultTimeout())
 	return err
 }
 
 // Publish the volume to the host specified in publishInfo.  This method may or may not be running on the host
 // where the volume will be mounted, so it should limit itself to updating access rules, initiator groups, etc.
 // that require some host identity (but not locality) as well as storage controller API access.
-func (d *NFSStorageDriver) Publish(volConfig *storage.VolumeConfig, publishInfo *utils.VolumePublishInfo) error {
+func (d *NFSStorageDriver) Publish(
+	ctx context.Context, volConfig *storage.VolumeConfig, publishInfo *utils.VolumePublishInfo,
+) error {
+
+	client := d.API.WithContext(ctx)
 
 	name := volConfig.InternalName
 
@@ -1047,12 +1062,12 @@ func (d *NFSStorageDriver) Publish(volConfig *storage.VolumeConfig, publishInfo
 	// Get the volume
 	creationToken := name
 
-	volume, err := d.API.GetVolumeByCreationToken(creationToken)
+	volume, err := client.GetVolumeByCreationToken(creationToken)
 	if err != nil {
 		return fmt.Errorf("could not find volume %s: %v", creationToken, err)
 	}
 
-	mountTargets, err := d.API.GetMountTargetsForVolume(volume)
+	mountTargets, err := client.GetMountTargetsForVolume(volume)
 	if err != nil {
 		return fmt.Errorf("could not read mount targets for volume %s: %v", name, err)
 	}
@@ -1187,7 +1202,11 @@ func (d *NFSStorageDriver) GetSnapshots(volConfig *storage.VolumeConfig) ([]*sto
 }
 
 // CreateSnapshot creates a snapshot for the given volume
-func (d *NFSStorageDriver) CreateSnapshot(snapConfig *storage.SnapshotConfig) (*storage.Snapshot, error) {
+func (d *NFSStorageDriver) CreateSnapshot(
+	ctx context.Context, snapConfig *storage.SnapshotConfig,
+) (*storage.Snapshot, error) {
+
+	client := d.API.WithContext(ctx)
 
 	internalSnapName := snapConfig.InternalName
 	internalVolName := snapConfig.VolumeInternalName
@@ -1204,7 +1223,7 @@ func (d *NFSStorageDriver) CreateSnapshot(snapConfig *storage.SnapshotConfig) (*
 	}
 
 	// Check if volume exists
-	volumeExists, sourceVolume, err := d.API.VolumeExistsByCreationToken(internalVolName)
+	volumeExists, sourceVolume, err := client.VolumeExistsByCreationToken(internalVolName)
 	if err != nil {
 		return nil, fmt.Errorf("error checking for existing volume: %v", err)
 	}
@@ -1213,7 +1232,7 @@ func (d *NFSStorageDriver) CreateSnapshot(snapConfig *storage.SnapshotConfig) (*
 	}
 
 	// Get the source volume by ID to get all details
-	sourceVolume, err = d.API.GetVolumeByID(sourceVolume.FileSystemID)
+	sourceVolume, err = client.GetVolumeByID(sourceVolume.FileSystemID)
 	if err != nil {
 		return nil, fmt.Errorf("could not get source volume details: %v", err)
 	}
@@ -1225,13 +1244,13 @@ func (d *NFSStorageDriver) CreateSnapshot(snapConfig *storage.SnapshotConfig) (*
 		Region:       sourceVolume.Region,
 	}
 
-	snapshot, err := d.API.CreateSnapshot(snapshotCreateRequest)
+	snapshot, err := client.CreateSnapshot(snapshotCreateRequest)
 	if err != nil {
 		return nil, fmt.Errorf("could not create snapshot: %v", err)
 	}
 
 	// Wait for snapshot creation to complete
-	err = d.API.WaitForSnapshotState(snapshot, api.StateAvailable, []string{api.StateError}, d.defaultTimeout())
+	err = client.WaitForSnapshotState(snapshot, api.StateAvailable, []string{api.StateError}, d.defaultTimeout())
 	if err != nil {
 		return nil, err
 	}
@@ -1244,7 +1263,9 @@ func (d *NFSStorageDriver) CreateSnapshot(snapConfig *storage.SnapshotConfig) (*
 }
 
 // RestoreSnapshot restores a volume (in place) from a snapshot.
-func (d *NFSStorageDriver) RestoreSnapshot(snapConfig *storage.SnapshotConfig) error {
+func (d *NFSStorageDriver) RestoreSnapshot(ctx context.Context, snapConfig *storage.SnapshotConfig) error {
+
+	client := d.API.WithContext(ctx)
 
 	internalSnapName := snapConfig.InternalName
 	internalVolName := snapConfig.VolumeInternalName
@@ -1263,21 +1284,23 @@ func (d *NFSStorageDriver) RestoreSnapshot(snapConfig *storage.SnapshotConfig) e
 	// Get the volume
 	creationToken := internalVolName
 
-	volume, err := d.API.GetVolumeByCreationToken(creationToken)
+	volume, err := client.GetVolumeByCreationToken(creationToken)
 	if err != nil {
 		return fmt.Errorf("could not find volume %s: %v", creationToken, err)
 	}
 
-	snapshot, err := d.API.GetSnapshotForVolume(volume, internalSnapName)
+	snapshot, err := client.GetSnapshotForVolume(volume, internalSnapName)
 	if err != nil {
 		return fmt.Errorf("unable to find snapshot %s: %v", internalSnapName, err)
 	}
 
-	return d.API.RestoreSnapshot(volume, snapshot)
+	return client.RestoreSnapshot(volume, snapshot)
 }
 
 // DeleteSnapshot creates a snapshot of a volume.
-func (d *NFSStorageDriver) DeleteSnapshot(snapConfig *storage.SnapshotConfig) error {
+func (d *NFSStorageDriver) DeleteSnapshot(ctx context.Context, snapConfig *storage.SnapshotConfig) error {
+
+	client := d.API.WithContext(ctx)
 
 	internalSnapName := snapConfig.InternalName
 	internalVolName := snapConfig.VolumeInternalName
@@ -1296,22 +1319,22 @@ func (d *NFSStorageDriver) DeleteSnapshot(snapConfig *storage.SnapshotConfig) er
 	// Get the volume
 	creationToken := internalVolName
 
-	volume, err := d.API.GetVolumeByCreationToken(creationToken)
+	volume, err := client.GetVolumeByCreationToken(creationToken)
 	if err != nil {
 		return fmt.Errorf("could not find volume %s: %v", creationToken, err)
 	}
 
-	snapshot, err := d.API.GetSnapshotForVolume(volume, internalSnapName)
+	snapshot, err := client.GetSnapshotForVolume(volume, internalSnapName)
 	if err != nil {
 		return fmt.Errorf("unable to find snapshot %s: %v", internalSnapName, err)
 	}
 
-	if err := d.API.DeleteSnapshot(volume, snapshot); err != nil {
+	if err := client.DeleteSnapshot(volume, snapshot); err != nil {
 		return err
 	}
 
 	// Wait for snapshot deletion to complete
-	return d.API.WaitForSnapshotState(snapshot, api.StateDeleted, []string{api.StateError}, d.defaultTimeout())
+	return client.WaitForSnapshotState(snapshot, api.StateDeleted, []string{api.StateError}, d.defaultTimeout())
 }
 
 // Return the list of volumes associated with this tenant
@@ -1359,12 +1382,18 @@ func (d *NFSStorageDriver) Get(name string) error {
 		defer log.WithFields(fields).Debug("<<<< Get")
 	}
 
-	_, err := d.API.GetVolumeByCreationToken(name)
-
-	return err
+	exists, _, err := d.API.VolumeExistsByCreationToken(name)
+	if err != nil {
+		return fmt.Errorf("could not check for volume %s; %v", name, err)
+	} else if !exists {
+		return utils.NotFoundError(fmt.Sprintf("volume %s not found", name))
+	}
+	return nil
 }
 
-func (d *NFSStorageDriver) Resize(volConfig *storage.VolumeConfig, sizeBytes uint64) error {
+func (d *NFSStorageDriver) Resize(ctx context.Context, volConfig *storage.VolumeConfig, sizeBytes uint64) error {
+
+	client := d.API.WithContext(ctx)
 
 	name := volConfig.InternalName
 	if d.Config.DebugTraceFlags["method"] {
@@ -1381,7 +1410,7 @@ func (d *NFSStorageDriver) Resize(volConfig *storage.VolumeConfig, sizeBytes uin
 	// Get the volume
 	creationToken := name
 
-	volume, err := d.API.GetVolumeByCreationToken(creationToken)
+	volume, err := client.GetVolumeByCreationToken(creationToken)
 	if err != nil {
 		return fmt.Errorf("could not find volume %s: %v", creationToken, err)
 	}
@@ -1409,7 +1438,7 @@ func (d *NFSStorageDriver) Resize(volConfig *storage.VolumeConfig, sizeBytes uin
 	}
 
 	// Resize the volume
-	_, err = d.API.ResizeVolume(volume, int64(sizeBytes))
+	_, err = client.ResizeVolume(volume, int64(sizeBytes))
 	if err != nil {
 		return err
 	}
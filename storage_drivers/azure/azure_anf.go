@@ -3,6 +3,7 @@
 package azure
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -251,11 +252,16 @@ func (d *NFSStorageDriver) populateConfigurationDefaults(config *drivers.AzureNF
 	if config.ExportRule == "" {
 		config.ExportRule = defaultExportRule
 	}
+
+	if config.NASType == "" {
+		config.NASType = drivers.NASTypeNFS
+	}
 	log.WithFields(log.Fields{
 		"StoragePrefix":   *config.StoragePrefix,
 		"Size":            config.Size,
 		"ServiceLevel":    config.ServiceLevel,
 		"NfsMountOptions": config.NfsMountOptions,
+		"NASType":         config.NASType,
 		"LimitVolumeSize": config.LimitVolumeSize,
 		"ExportRule":      config.ExportRule,
 	}).Debugf("Configuration defaults")
@@ -443,6 +449,13 @@ func (d *NFSStorageDriver) validate() error {
 		return fmt.Errorf("storage prefix may only contain letters and hyphens")
 	}
 
+	switch d.Config.NASType {
+	case drivers.NASTypeNFS, drivers.NASTypeSMB:
+		break
+	default:
+		return fmt.Errorf("invalid value for nasType: %s", d.Config.NASType)
+	}
+
 	// Validate pool-level attributes
 	for poolName, pool := range d.pools {
 
@@ -475,9 +488,11 @@ func (d *NFSStorageDriver) validate() error {
 
 // Create a volume with the specified options
 func (d *NFSStorageDriver) Create(
-	volConfig *storage.VolumeConfig, storagePool *storage.Pool, volAttributes map[string]sa.Request,
+	ctx context.Context, volConfig *storage.VolumeConfig, storagePool *storage.Pool, volAttributes map[string]sa.Request,
 ) error {
 
+	client := d.SDK.WithContext(ctx)
+
 	name := volConfig.InternalName
 
 	if d.Config.DebugTraceFlags["method"] {
@@ -506,7 +521,7 @@ func (d *NFSStorageDriver) Create(
 	}
 
 	// If the volume already exists, bail out
-	volumeExists, extantVolume, err := d.SDK.VolumeExistsByCreationToken(name)
+	volumeExists, extantVolume, err := client.VolumeExistsByCreationToken(name)
 	if err != nil {
 		return fmt.Errorf("error checking for existing volume: %v", err)
 	}
@@ -571,23 +586,28 @@ func (d *NFSStorageDriver) Create(
 		mountOptions = volConfig.MountOptions
 	}
 
-	// Determine protocol from mount options
+	// Determine protocol from NAS type and mount options
 	var protocolTypes []string
 	var cifsAccess, nfsV3Access, nfsV41Access bool
 
-	nfsVersion, err := utils.GetNFSVersionFromMountOptions(mountOptions, nfsVersion3, supportedNFSVersions)
-	if err != nil {
-		return err
-	}
-	switch nfsVersion {
-	case nfsVersion3:
-		nfsV3Access = true
-		protocolTypes = []string{sdk.ProtocolTypeNFSv3}
-	case nfsVersion4:
-		fallthrough
-	case nfsVersion41:
-		nfsV41Access = true
-		protocolTypes = []string{sdk.ProtocolTypeNFSv41}
+	if d.Config.NASType == drivers.NASTypeSMB {
+		cifsAccess = true
+		protocolTypes = []string{sdk.ProtocolTypeCIFS}
+	} else {
+		nfsVersion, err := utils.GetNFSVersionFromMountOptions(mountOptions, nfsVersion3, supportedNFSVersions)
+		if err != nil {
+			return err
+		}
+		switch nfsVersion {
+		case nfsVersion3:
+			nfsV3Access = true
+			protocolTypes = []string{sdk.ProtocolTypeNFSv3}
+		case nfsVersion4:
+			fallthrough
+		case nfsVersion41:
+			nfsV41Access = true
+			protocolTypes = []string{sdk.ProtocolTypeNFSv41}
+		}
 	}
 
 	apiExportRule := sdk.ExportRule{
@@ -602,6 +622,10 @@ func (d *NFSStorageDriver) Create(
 	exportPolicy := sdk.ExportPolicy{
 		Rules: []sdk.ExportRule{apiExportRule},
 	}
+	if cifsAccess {
+		// SMB shares are secured by Active Directory rather than by export rules
+		exportPolicy.Rules = []sdk.ExportRule{}
+	}
 
 	log.WithFields(log.Fields{
 		"creationToken": name,
@@ -626,7 +650,7 @@ func (d *NFSStorageDriver) Create(
 	}
 
 	// Create the volume
-	volume, err := d.SDK.CreateVolume(createRequest)
+	volume, err := client.CreateVolume(createRequest)
 	if err != nil {
 		return err
 	}
@@ -636,7 +660,9 @@ func (d *NFSStorageDriver) Create(
 }
 
 // CreateClone clones an existing volume.  If a snapshot is not specified, one is created.
-func (d *NFSStorageDriver) CreateClone(volConfig *storage.VolumeConfig, _ *storage.Pool) error {
+func (d *NFSStorageDriver) CreateClone(ctx context.Context, volConfig *storage.VolumeConfig, _ *storage.Pool) error {
+
+	client := d.SDK.WithContext(ctx)
 
 	name := volConfig.InternalName
 	source := volConfig.CloneSourceVolumeInternal
@@ -666,7 +692,7 @@ func (d *NFSStorageDriver) CreateClone(volConfig *storage.VolumeConfig, _ *stora
 	}
 
 	// If the volume already exists, bail out
-	volumeExists, extantVolume, err := d.SDK.VolumeExistsByCreationToken(name)
+	volumeExists, extantVolume, err := client.VolumeExistsByCreationToken(name)
 	if err != nil {
 		return fmt.Errorf("error checking for existing volume: %v", err)
 	}
@@ -680,7 +706,7 @@ func (d *NFSStorageDriver) CreateClone(volConfig *storage.VolumeConfig, _ *stora
 	}
 
 	// Get the source volume
-	sourceVolume, err := d.SDK.GetVolumeByCreationToken(source)
+	sourceVolume, err := client.GetVolumeByCreationToken(source)
 	if err != nil {
 		return fmt.Errorf("could not find source volume: %v", err)
 	}
@@ -690,7 +716,7 @@ func (d *NFSStorageDriver) CreateClone(volConfig *storage.VolumeConfig, _ *stora
 	if snapshot != "" {
 
 		// Get the source snapshot
-		sourceSnapshot, err = d.SDK.GetSnapshotForVolume(sourceVolume, snapshot)
+		sourceSnapshot, err = client.GetSnapshotForVolume(sourceVolume, snapshot)
 		if err != nil {
 			return fmt.Errorf("could not find source snapshot: %v", err)
 		}
@@ -722,20 +748,20 @@ func (d *NFSStorageDriver) CreateClone(volConfig *storage.VolumeConfig, _ *stora
 			"source":   sourceVolume.Name,
 		}).Debug("Creating source snapshot.")
 
-		sourceSnapshot, err = d.SDK.CreateSnapshot(snapshotCreateRequest)
+		sourceSnapshot, err = client.CreateSnapshot(snapshotCreateRequest)
 		if err != nil {
 			return fmt.Errorf("could not create source snapshot: %v", err)
 		}
 
 		// Wait for snapshot creation to complete
-		err = d.SDK.WaitForSnapshotState(
+		err = client.WaitForSnapshotState(
 			sourceSnapshot, sourceVolume, sdk.StateAvailable, []string{sdk.StateError}, sdk.SnapshotTimeout)
 		if err != nil {
 			return err
 		}
 
 		// Re-fetch the snapshot to populate the properties after create has completed
-		sourceSnapshot, err = d.SDK.GetSnapshotForVolume(sourceVolume, snapName)
+		sourceSnapshot, err = client.GetSnapshotForVolume(sourceVolume, snapName)
 		if err != nil {
 			return fmt.Errorf("could not retrieve newly-created snapshot")
 		}
@@ -752,7 +778,7 @@ func (d *NFSStorageDriver) CreateClone(volConfig *storage.VolumeConfig, _ *stora
 		"sourceSnapshot": sourceSnapshot.Name,
 	}).Debug("Cloning volume.")
 
-	cookie, err := d.SDK.GetCookieByCapacityPoolName(sourceVolume.CapacityPoolName)
+	cookie, err := client.GetCookieByCapacityPoolName(sourceVolume.CapacityPoolName)
 	if err != nil {
 		return fmt.Errorf("couldn't find cookie for volume %v", name)
 	}
@@ -773,7 +799,7 @@ func (d *NFSStorageDriver) CreateClone(volConfig *storage.VolumeConfig, _ *stora
 	}
 
 	// Clone the volume
-	clone, err := d.SDK.CreateVolume(createRequest)
+	clone, err := client.CreateVolume(createRequest)
 	if err != nil {
 		return err
 	}
@@ -782,7 +808,7 @@ func (d *NFSStorageDriver) CreateClone(volConfig *storage.VolumeConfig, _ *stora
 	return d.waitForVolumeCreate(clone, name)
 }
 
-func (d *NFSStorageDriver) Import(volConfig *storage.VolumeConfig, originalName string) error {
+func (d *NFSStorageDriver) Import(ctx context.Context, volConfig *storage.VolumeConfig, originalName string) error {
 
 	if d.Config.DebugTraceFlags["method"] {
 		fields := log.Fields{
@@ -795,10 +821,12 @@ func (d *NFSStorageDriver) Import(volConfig *storage.VolumeConfig, originalName
 		defer log.WithFields(fields).Debug("<<<< Import")
 	}
 
+	client := d.SDK.WithContext(ctx)
+
 	// Get the volume
 	creationToken := originalName
 
-	volume, err := d.SDK.GetVolumeByCreationToken(creationToken)
+	volume, err := client.GetVolumeByCreationToken(creationToken)
 	if err != nil {
 		return fmt.Errorf("could not find volume %s: %v", creationToken, err)
 	}
@@ -816,11 +844,11 @@ func (d *NFSStorageDriver) Import(volConfig *storage.VolumeConfig, originalName
 
 	// Update the volume labels if Trident will manage its lifecycle
 	if !volConfig.ImportNotManaged {
-		if _, err := d.SDK.RelabelVolume(volume, d.updateTelemetryLabels(volume)); err != nil {
+		if _, err := client.RelabelVolume(volume, d.updateTelemetryLabels(volume)); err != nil {
 			log.WithField("originalName", originalName).Errorf("Could not import volume, relabel failed: %v", err)
 			return fmt.Errorf("could not import volume %s, relabel failed: %v", originalName, err)
 		}
-		_, err := d.SDK.WaitForVolumeState(volume, sdk.StateAvailable, []string{sdk.StateError}, d.defaultTimeout())
+		_, err := client.WaitForVolumeState(volume, sdk.StateAvailable, []string{sdk.StateError}, d.defaultTimeout())
 		if err != nil {
 			return fmt.Errorf("could not import volume %s: %v", originalName, err)
 		}
@@ -931,7 +959,7 @@ func (d *NFSStorageDriver) waitForVolumeCreate(volume *sdk.FileSystem, volumeNam
 }
 
 // Destroy deletes a volume.
-func (d *NFSStorageDriver) Destroy(name string) error {
+func (d *NFSStorageDriver) Destroy(ctx context.Context, name string) error {
 
 	if d.Config.DebugTraceFlags["method"] {
 		fields := log.Fields{
@@ -943,9 +971,11 @@ func (d *NFSStorageDriver) Destroy(name string) error {
 		defer log.WithFields(fields).Debug("<<<< Destroy")
 	}
 
+	client := d.SDK.WithContext(ctx)
+
 	// If volume doesn't exist, return success
 	// 'name' is in fact 'creationToken' here.
-	volumeExists, extantVolume, err := d.SDK.VolumeExistsByCreationToken(name)
+	volumeExists, extantVolume, err := client.VolumeExistsByCreationToken(name)
 
 	if err != nil {
 		return err
@@ -955,24 +985,28 @@ func (d *NFSStorageDriver) Destroy(name string) error {
 		return nil
 	} else if extantVolume.ProvisioningState == sdk.StateDeleting {
 		// This is a retry, so give it more time before giving up again.
-		_, err = d.SDK.WaitForVolumeState(extantVolume, sdk.StateDeleted, []string{sdk.StateError}, d.volumeCreateTimeout)
+		_, err = client.WaitForVolumeState(extantVolume, sdk.StateDeleted, []string{sdk.StateError}, d.volumeCreateTimeout)
 		return err
 	}
 
 	// Delete the volume
-	if err = d.SDK.DeleteVolume(extantVolume); err != nil {
+	if err = client.DeleteVolume(extantVolume); err != nil {
 		return err
 	}
 
 	// Wait for deletion to complete
-	_, err = d.SDK.WaitForVolumeState(extantVolume, sdk.StateDeleted, []string{sdk.StateError}, d.defaultTimeout())
+	_, err = client.WaitForVolumeState(extantVolume, sdk.StateDeleted, []string{sdk.StateError}, d.defaultTimeout())
 	return err
 }
 
 // Publish the volume to the host specified in publishInfo.  This method may or may not be running on the host
 // where the volume will be mounted, so it should limit itself to updating access rules, initiator groups, etc.
 // that require some host identity (but not locality) as well as storage controller API access.
-func (d *NFSStorageDriver) Publish(volConfig *storage.VolumeConfig, publishInfo *utils.VolumePublishInfo) error {
+func (d *NFSStorageDriver) Publish(
+	ctx context.Context, volConfig *storage.VolumeConfig, publishInfo *utils.VolumePublishInfo,
+) error {
+
+	client := d.SDK.WithContext(ctx)
 
 	name := volConfig.InternalName
 
@@ -989,12 +1023,12 @@ func (d *NFSStorageDriver) Publish(volConfig *storage.VolumeConfig, publishInfo
 	// Get the volume
 	creationToken := name
 
-	volume, err := d.SDK.GetVolumeByCreationToken(creationToken)
+	volume, err := client.GetVolumeByCreationToken(creationToken)
 	if err != nil {
 		return fmt.Errorf("could not find volume %s: %v", creationToken, err)
 	}
 
-	mountTargets, err := d.SDK.GetMountTargetsForVolume(volume)
+	mountTargets, err := client.GetMountTargetsForVolume(volume)
 	if err != nil {
 		return fmt.Errorf("could not read mount targets for volume %s: %v", name, err)
 	}
@@ -1009,6 +1043,11 @@ func (d *NFSStorageDriver) Publish(volConfig *storage.VolumeConfig, publishInfo
 	}
 
 	// Add fields needed by Attach
+	if d.Config.NASType == drivers.NASTypeSMB {
+		publishInfo.SMBPath = getSMBPath((*mountTargets)[0], volume.CreationToken)
+		publishInfo.FilesystemType = drivers.NASTypeSMB
+		return nil
+	}
 	publishInfo.NfsPath = "/" + volume.CreationToken
 	publishInfo.NfsServerIP = (*mountTargets)[0].IPAddress
 	publishInfo.FilesystemType = "nfs"
@@ -1128,7 +1167,11 @@ func (d *NFSStorageDriver) GetSnapshots(volConfig *storage.VolumeConfig) ([]*sto
 }
 
 // CreateSnapshot creates a snapshot for the given volume
-func (d *NFSStorageDriver) CreateSnapshot(snapConfig *storage.SnapshotConfig) (*storage.Snapshot, error) {
+func (d *NFSStorageDriver) CreateSnapshot(
+	ctx context.Context, snapConfig *storage.SnapshotConfig,
+) (*storage.Snapshot, error) {
+
+	client := d.SDK.WithContext(ctx)
 
 	internalSnapName := snapConfig.InternalName
 	internalVolName := snapConfig.VolumeInternalName
@@ -1145,7 +1188,7 @@ func (d *NFSStorageDriver) CreateSnapshot(snapConfig *storage.SnapshotConfig) (*
 	}
 
 	// Check if volume exists
-	volumeExists, sourceVolume, err := d.SDK.VolumeExistsByCreationToken(internalVolName)
+	volumeExists, sourceVolume, err := client.VolumeExistsByCreationToken(internalVolName)
 	if err != nil {
 		return nil, fmt.Errorf("error checking for existing volume: %v", err)
 	}
@@ -1161,13 +1204,13 @@ func (d *NFSStorageDriver) CreateSnapshot(snapConfig *storage.SnapshotConfig) (*
 		Location:     sourceVolume.Location,
 	}
 
-	snapshot, err := d.SDK.CreateSnapshot(snapshotCreateRequest)
+	snapshot, err := client.CreateSnapshot(snapshotCreateRequest)
 	if err != nil {
 		return nil, fmt.Errorf("could not create snapshot: %v", err)
 	}
 
 	// Wait for snapshot creation to complete
-	err = d.SDK.WaitForSnapshotState(
+	err = client.WaitForSnapshotState(
 		snapshot, sourceVolume, sdk.StateAvailable, []string{sdk.StateError}, sdk.SnapshotTimeout)
 	if err != nil {
 		return nil, err
@@ -1181,7 +1224,9 @@ func (d *NFSStorageDriver) CreateSnapshot(snapConfig *storage.SnapshotConfig) (*
 }
 
 // RestoreSnapshot restores a volume (in place) from a snapshot.
-func (d *NFSStorageDriver) RestoreSnapshot(snapConfig *storage.SnapshotConfig) error {
+func (d *NFSStorageDriver) RestoreSnapshot(ctx context.Context, snapConfig *storage.SnapshotConfig) error {
+
+	client := d.SDK.WithContext(ctx)
 
 	internalSnapName := snapConfig.InternalName
 	internalVolName := snapConfig.VolumeInternalName
@@ -1200,21 +1245,23 @@ func (d *NFSStorageDriver) RestoreSnapshot(snapConfig *storage.SnapshotConfig) e
 	// Get the volume
 	creationToken := internalVolName
 
-	volume, err := d.SDK.GetVolumeByCreationToken(creationToken)
+	volume, err := client.GetVolumeByCreationToken(creationToken)
 	if err != nil {
 		return fmt.Errorf("could not find volume %s: %v", creationToken, err)
 	}
 
-	snapshot, err := d.SDK.GetSnapshotForVolume(volume, internalSnapName)
+	snapshot, err := client.GetSnapshotForVolume(volume, internalSnapName)
 	if err != nil {
 		return fmt.Errorf("unable to find snapshot %s: %v", internalSnapName, err)
 	}
 
-	return d.SDK.RestoreSnapshot(volume, snapshot)
+	return client.RestoreSnapshot(volume, snapshot)
 }
 
 // DeleteSnapshot creates a snapshot of a volume.
-func (d *NFSStorageDriver) DeleteSnapshot(snapConfig *storage.SnapshotConfig) error {
+func (d *NFSStorageDriver) DeleteSnapshot(ctx context.Context, snapConfig *storage.SnapshotConfig) error {
+
+	client := d.SDK.WithContext(ctx)
 
 	internalSnapName := snapConfig.InternalName
 	internalVolName := snapConfig.VolumeInternalName
@@ -1233,22 +1280,22 @@ func (d *NFSStorageDriver) DeleteSnapshot(snapConfig *storage.SnapshotConfig) er
 	// Get the volume
 	creationToken := internalVolName
 
-	volume, err := d.SDK.GetVolumeByCreationToken(creationToken)
+	volume, err := client.GetVolumeByCreationToken(creationToken)
 	if err != nil {
 		return fmt.Errorf("could not find volume %s: %v", creationToken, err)
 	}
 
-	snapshot, err := d.SDK.GetSnapshotForVolume(volume, internalSnapName)
+	snapshot, err := client.GetSnapshotForVolume(volume, internalSnapName)
 	if err != nil {
 		return fmt.Errorf("unable to find snapshot %s: %v", internalSnapName, err)
 	}
 
-	if err = d.SDK.DeleteSnapshot(volume, snapshot); err != nil {
+	if err = client.DeleteSnapshot(volume, snapshot); err != nil {
 		return err
 	}
 
 	// Wait for snapshot deletion to complete
-	if err := d.SDK.WaitForSnapshotState(snapshot, volume, sdk.StateDeleted, []string{sdk.StateError},
+	if err := client.WaitForSnapshotState(snapshot, volume, sdk.StateDeleted, []string{sdk.StateError},
 		sdk.SnapshotTimeout); err != nil {
 		return err
 	}
@@ -1307,7 +1354,9 @@ func (d *NFSStorageDriver) Get(name string) error {
 }
 
 // Resize increases a volume's quota
-func (d *NFSStorageDriver) Resize(volConfig *storage.VolumeConfig, sizeBytes uint64) error {
+func (d *NFSStorageDriver) Resize(ctx context.Context, volConfig *storage.VolumeConfig, sizeBytes uint64) error {
+
+	client := d.SDK.WithContext(ctx)
 
 	name := volConfig.InternalName
 	if d.Config.DebugTraceFlags["method"] {
@@ -1324,7 +1373,7 @@ func (d *NFSStorageDriver) Resize(volConfig *storage.VolumeConfig, sizeBytes uin
 	// Get the volume
 	creationToken := name
 
-	volume, err := d.SDK.GetVolumeByCreationToken(creationToken)
+	volume, err := client.GetVolumeByCreationToken(creationToken)
 	if err != nil {
 		return fmt.Errorf("could not find volume %s: %v", creationToken, err)
 	}
@@ -1352,7 +1401,7 @@ func (d *NFSStorageDriver) Resize(volConfig *storage.VolumeConfig, sizeBytes uin
 	}
 
 	// Resize the volume
-	_, err = d.SDK.ResizeVolume(volume, int64(sizeBytes))
+	_, err = client.ResizeVolume(volume, int64(sizeBytes))
 	if err != nil {
 		return err
 	}
@@ -1430,13 +1479,26 @@ func (d *NFSStorageDriver) CreateFollowup(volConfig *storage.VolumeConfig) error
 	}
 
 	// Just use the first mount target found
-	volConfig.AccessInfo.NfsServerIP = (*mountTargets)[0].IPAddress
-	volConfig.AccessInfo.NfsPath = "/" + volume.CreationToken
+	if d.Config.NASType == drivers.NASTypeSMB {
+		volConfig.AccessInfo.SMBPath = getSMBPath((*mountTargets)[0], volume.CreationToken)
+	} else {
+		volConfig.AccessInfo.NfsServerIP = (*mountTargets)[0].IPAddress
+		volConfig.AccessInfo.NfsPath = "/" + volume.CreationToken
+	}
 	volConfig.FileSystem = ""
 
 	return nil
 }
 
+// getSMBPath returns the UNC path of a volume's SMB share, which is named after the volume's creation token.
+func getSMBPath(mountTarget sdk.MountTarget, creationToken string) string {
+	server := mountTarget.SMBServerFQDN
+	if server == "" {
+		server = mountTarget.IPAddress
+	}
+	return `\\` + server + `\` + creationToken
+}
+
 func (d *NFSStorageDriver) GetProtocol() tridentconfig.Protocol {
 	return tridentconfig.File
 }
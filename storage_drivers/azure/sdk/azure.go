@@ -21,6 +21,7 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	"github.com/netapp/trident/storage"
+	"github.com/netapp/trident/utils"
 )
 
 const (
@@ -113,6 +114,15 @@ func NewDriver(config ClientConfig) *Client {
 	return d
 }
 
+// WithContext returns a copy of this client whose SDK calls are cancelled when the context is done.
+func (d *Client) WithContext(ctx context.Context) *Client {
+	sdkClient := *d.SDKClient
+	sdkClient.Ctx = ctx
+	clone := *d
+	clone.SDKClient = &sdkClient
+	return &clone
+}
+
 // Init runs startup logic after allocating the driver resources
 func (d *Client) Init(pools map[string]*storage.Pool) (err error) {
 
@@ -386,7 +396,7 @@ func (d *Client) GetVolumeByCreationToken(creationToken string) (*FileSystem, er
 		}
 	}
 
-	return nil, fmt.Errorf("filesystem with token '%s' not found", creationToken)
+	return nil, utils.NotFoundError(fmt.Sprintf("filesystem with token '%s' not found", creationToken))
 }
 
 // VolumeExistsByCreationToken checks whether a volume exists using its token as a key
@@ -848,6 +858,9 @@ func (d *Client) GetMountTargetsForVolume(filesystem *FileSystem) (*[]MountTarge
 		if mt.MountTargetProperties.Subnet != nil {
 			m.Subnet = *mt.MountTargetProperties.Subnet
 		}
+		if mt.MountTargetProperties.SmbServerFqdn != nil {
+			m.SMBServerFQDN = *mt.MountTargetProperties.SmbServerFqdn
+		}
 		mounts = append(mounts, m)
 	}
 
@@ -126,6 +126,7 @@ type MountTarget struct {
 	Netmask           string    `json:"netmask"`
 	StartIP           string    `json:"startIP"`
 	Subnet            string    `json:"subnet"`
+	SMBServerFQDN     string    `json:"smbServerFqdn"`
 }
 
 type Snapshot struct {
@@ -33,9 +33,16 @@ const (
 	FsXfs  = "xfs"
 	FsExt3 = "ext3"
 	FsExt4 = "ext4"
+	FsNTFS = "ntfs"
 	FsRaw  = "raw"
 )
 
+// NAS protocols that volumes may be shared with
+const (
+	NASTypeNFS = "nfs"
+	NASTypeSMB = "smb"
+)
+
 // Default Filesystem value
 const DefaultFileSystemType = FsExt4
 
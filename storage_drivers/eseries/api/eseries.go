@@ -5,6 +5,7 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"encoding/json"
 	"errors"
@@ -21,6 +22,7 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	tridentconfig "github.com/netapp/trident/config"
+	"github.com/netapp/trident/storage_drivers/apimetrics"
 	"github.com/netapp/trident/utils"
 )
 
@@ -64,19 +66,26 @@ type ClientConfig struct {
 	DriverName    string
 	Telemetry     map[string]string
 	ConfigVersion int
+
+	// Identifies the backend in API metrics
+	BackendName string
 }
 
 // Client is the object to use for interacting with the E-series API.
 type Client struct {
-	config *ClientConfig
-	m      *sync.Mutex
+	config  *ClientConfig
+	m       *sync.Mutex
+	metrics *apimetrics.Recorder
+
+	ctx context.Context
 }
 
 // NewAPIClient is a factory method for creating a new instance.
 func NewAPIClient(config ClientConfig) *Client {
 	c := &Client{
-		config: &config,
-		m:      &sync.Mutex{},
+		config:  &config,
+		m:       &sync.Mutex{},
+		metrics: apimetrics.NewRecorder(config.BackendName, config.DriverName),
 	}
 
 	// Initialize internal config variables
@@ -96,6 +105,20 @@ func NewAPIClient(config ClientConfig) *Client {
 	return c
 }
 
+// WithContext returns a copy of this client whose requests are cancelled when the context is done.
+func (d Client) WithContext(ctx context.Context) *Client {
+	clone := d
+	clone.ctx = ctx
+	return &clone
+}
+
+func (d Client) requestContext() context.Context {
+	if d.ctx == nil {
+		return context.Background()
+	}
+	return d.ctx
+}
+
 func (d Client) makeVolumeTags() []VolumeTag {
 
 	return []VolumeTag{
@@ -136,9 +159,9 @@ func (d Client) InvokeAPI(requestBody []byte, method string, resourcePath string
 
 	// Create the request
 	if requestBody == nil {
-		request, err = http.NewRequest(method, url, nil)
+		request, err = http.NewRequestWithContext(d.requestContext(), method, url, nil)
 	} else {
-		request, err = http.NewRequest(method, url, bytes.NewBuffer(requestBody))
+		request, err = http.NewRequestWithContext(d.requestContext(), method, url, bytes.NewBuffer(requestBody))
 	}
 	if err != nil {
 		return nil, nil, err
@@ -168,11 +191,14 @@ func (d Client) InvokeAPI(requestBody []byte, method string, resourcePath string
 		Transport: tr,
 		Timeout:   time.Duration(tridentconfig.StorageAPITimeoutSeconds * time.Second),
 	}
+	recordResult := d.metrics.Start(apimetrics.OperationForREST(method, resourcePath))
 	response, err := client.Do(request)
 	if err != nil {
+		recordResult(apimetrics.ResultTransportError)
 		log.Warnf("Error communicating with Web Services Proxy. %v", err)
 		return nil, nil, err
 	}
+	recordResult(apimetrics.ResultForHTTPStatus(response.StatusCode))
 	defer response.Body.Close()
 
 	responseBody := []byte{}
@@ -223,7 +249,7 @@ func (d Client) AboutInfo() (*AboutResponse, error) {
 	var prettyResponseBuffer bytes.Buffer
 
 	// Create the request
-	request, err = http.NewRequest("GET", url, nil)
+	request, err = http.NewRequestWithContext(d.requestContext(), "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -790,6 +816,7 @@ func (d Client) CreateVolume(
 	if response.StatusCode == http.StatusUnprocessableEntity {
 
 		log.Debug("Volume created failed with 422 response, attempting to re-read volume.")
+		d.metrics.RecordRetry(apimetrics.OperationForREST("POST", "/volumes"))
 
 		retryVolume, retryError := d.GetVolume(name)
 		if retryError != nil {
@@ -1454,6 +1481,7 @@ func (d Client) MapVolume(volume VolumeEx, host HostEx) (LUNMapping, error) {
 		if apiError, ok := err.(Error); ok && apiError.Code == http.StatusUnprocessableEntity {
 
 			log.Debug("Volume map failed with 422 response, attempting to re-read volume/map.")
+			d.metrics.RecordRetry(apimetrics.OperationForREST("POST", "/volume-mappings"))
 
 			retryVolume, retryError := d.GetVolumeByRef(volume.VolumeRef)
 			if retryError != nil {
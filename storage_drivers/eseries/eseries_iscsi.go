@@ -3,6 +3,7 @@
 package eseries
 
 import (
+	"context"
 	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
@@ -137,6 +138,7 @@ func (d *SANStorageDriver) Initialize(
 		Telemetry:             telemetry,
 		ConfigVersion:         config.CommonStorageDriverConfig.Version,
 		DebugTraceFlags:       config.CommonStorageDriverConfig.DebugTraceFlags,
+		BackendName:           config.CommonStorageDriverConfig.BackendName,
 	})
 
 	// Connect to web services proxy
@@ -430,9 +432,11 @@ func (d *SANStorageDriver) validate() error {
 // and disk media type may be provided in the opts map. If more than one pool on the storage controller can satisfy the request, the
 // one with the most free space is selected.
 func (d *SANStorageDriver) Create(
-	volConfig *storage.VolumeConfig, storagePool *storage.Pool, volAttributes map[string]sa.Request,
+	ctx context.Context, volConfig *storage.VolumeConfig, storagePool *storage.Pool, volAttributes map[string]sa.Request,
 ) error {
 
+	client := d.API.WithContext(ctx)
+
 	name := volConfig.InternalName
 
 	var fstype string
@@ -449,11 +453,11 @@ func (d *SANStorageDriver) Create(
 	}
 
 	// If the volume already exists, bail out
-	extantVolume, err := d.API.GetVolume(name)
+	extantVolume, err := client.GetVolume(name)
 	if err != nil {
 		return fmt.Errorf("error checking for existing volume: %v", err)
 	}
-	if d.API.IsRefValid(extantVolume.VolumeRef) {
+	if client.IsRefValid(extantVolume.VolumeRef) {
 		return drivers.NewVolumeExistsError(name)
 	}
 
@@ -507,7 +511,7 @@ func (d *SANStorageDriver) Create(
 		physicalPoolNames = append(physicalPoolNames, poolName)
 
 		// expect pool of size 1
-		pools, err := d.API.GetVolumePools(mediaType, sizeBytes, poolName)
+		pools, err := client.GetVolumePools(mediaType, sizeBytes, poolName)
 		if err != nil {
 			errMessage := fmt.Sprintf("E-series pool %s not found", poolName)
 			log.Error(errMessage)
@@ -519,7 +523,7 @@ func (d *SANStorageDriver) Create(
 		pool := pools[0]
 
 		// Create the volume
-		vol, err := d.API.CreateVolume(name, pool.VolumeGroupRef, sizeBytes, mediaType, fstype)
+		vol, err := client.CreateVolume(name, pool.VolumeGroupRef, sizeBytes, mediaType, fstype)
 		if err != nil {
 			errMessage := fmt.Sprintf("E-series pool %s could not create volume %s: %v", poolName, name, err)
 			log.Error(errMessage)
@@ -589,7 +593,7 @@ func (d *SANStorageDriver) getPoolsForCreate(
 }
 
 // Destroy is called by Docker to delete a container volume.
-func (d *SANStorageDriver) Destroy(name string) error {
+func (d *SANStorageDriver) Destroy(ctx context.Context, name string) error {
 
 	if d.Config.DebugTraceFlags["method"] {
 		fields := log.Fields{
@@ -601,13 +605,15 @@ func (d *SANStorageDriver) Destroy(name string) error {
 		defer log.WithFields(fields).Debug("<<<< Destroy")
 	}
 
+	client := d.API.WithContext(ctx)
+
 	var (
 		err           error
 		iSCSINodeName string
 		lunID         int
 	)
 
-	vol, err := d.API.GetVolume(name)
+	vol, err := client.GetVolume(name)
 	if err != nil {
 		return fmt.Errorf("could not find volume %s: %v", name, err)
 	}
@@ -632,10 +638,10 @@ func (d *SANStorageDriver) Destroy(name string) error {
 		}
 	}
 
-	if d.API.IsRefValid(vol.VolumeRef) {
+	if client.IsRefValid(vol.VolumeRef) {
 
 		// Destroy volume on storage array
-		err = d.API.DeleteVolume(vol)
+		err = client.DeleteVolume(vol)
 		if err != nil {
 			return fmt.Errorf("could not destroy volume %s: %v", name, err)
 		}
@@ -652,7 +658,11 @@ func (d *SANStorageDriver) Destroy(name string) error {
 // Publish the volume to the host specified in publishInfo.  This method may or may not be running on the host
 // where the volume will be mounted, so it should limit itself to updating access rules, initiator groups, etc.
 // that require some host identity (but not locality) as well as storage controller API access.
-func (d *SANStorageDriver) Publish(volConfig *storage.VolumeConfig, publishInfo *utils.VolumePublishInfo) error {
+func (d *SANStorageDriver) Publish(
+	ctx context.Context, volConfig *storage.VolumeConfig, publishInfo *utils.VolumePublishInfo,
+) error {
+
+	client := d.API.WithContext(ctx)
 
 	name := volConfig.InternalName
 
@@ -667,16 +677,16 @@ func (d *SANStorageDriver) Publish(volConfig *storage.VolumeConfig, publishInfo
 	}
 
 	// Get the volume
-	vol, err := d.API.GetVolume(name)
+	vol, err := client.GetVolume(name)
 	if err != nil {
 		return fmt.Errorf("could not find volume %s: %v", name, err)
 	}
-	if !d.API.IsRefValid(vol.VolumeRef) {
+	if !client.IsRefValid(vol.VolumeRef) {
 		return fmt.Errorf("could not find volume %s", name)
 	}
 
 	// Get the Target IQN
-	targetIQN, err := d.API.GetTargetIQN()
+	targetIQN, err := client.GetTargetIQN()
 	if err != nil {
 		return fmt.Errorf("could not get target IQN from array: %v", err)
 	}
@@ -726,20 +736,20 @@ func (d *SANStorageDriver) Publish(volConfig *storage.VolumeConfig, publishInfo
 		hostname = publishInfo.HostName
 
 		// Get the host group
-		hostGroup, err := d.API.EnsureHostGroup()
+		hostGroup, err := client.EnsureHostGroup()
 		if err != nil {
 			return fmt.Errorf("could not get host group: %v", err)
 		}
 
 		// See if there is already a host for the specified IQN
-		host, err := d.API.GetHostForIQN(iqn)
+		host, err := client.GetHostForIQN(iqn)
 		if err != nil {
 			return fmt.Errorf("could not get host for IQN %s: %v", iqn, err)
 		}
 
 		// Create the host if necessary
 		if host.HostRef == "" {
-			host, err = d.API.CreateHost(hostname, iqn, d.Config.HostType, hostGroup)
+			host, err = client.CreateHost(hostname, iqn, d.Config.HostType, hostGroup)
 			if err != nil {
 				return fmt.Errorf("could not create host for IQN %s: %v", iqn, err)
 			}
@@ -755,7 +765,7 @@ func (d *SANStorageDriver) Publish(volConfig *storage.VolumeConfig, publishInfo
 			HostRef:    api.NullRef,
 			ClusterRef: hostGroup.ClusterRef,
 		}
-		mapping, err = d.API.MapVolume(vol, mapHost)
+		mapping, err = client.MapVolume(vol, mapHost)
 		if err != nil {
 			return fmt.Errorf("could not map volume %s to Host Group %s: %v", name, hostGroup.Label, err)
 		}
@@ -882,7 +892,9 @@ func (d *SANStorageDriver) GetSnapshots(volConfig *storage.VolumeConfig) ([]*sto
 
 // CreateSnapshot creates a snapshot for the given volume. The E-series volume plugin
 // does not support cloning or snapshots, so this method always returns an error.
-func (d *SANStorageDriver) CreateSnapshot(snapConfig *storage.SnapshotConfig) (*storage.Snapshot, error) {
+func (d *SANStorageDriver) CreateSnapshot(
+	ctx context.Context, snapConfig *storage.SnapshotConfig,
+) (*storage.Snapshot, error) {
 
 	if d.Config.DebugTraceFlags["method"] {
 		fields := log.Fields{
@@ -899,7 +911,7 @@ func (d *SANStorageDriver) CreateSnapshot(snapConfig *storage.SnapshotConfig) (*
 }
 
 // RestoreSnapshot restores a volume (in place) from a snapshot.
-func (d *SANStorageDriver) RestoreSnapshot(snapConfig *storage.SnapshotConfig) error {
+func (d *SANStorageDriver) RestoreSnapshot(ctx context.Context, snapConfig *storage.SnapshotConfig) error {
 
 	if d.Config.DebugTraceFlags["method"] {
 		fields := log.Fields{
@@ -916,7 +928,7 @@ func (d *SANStorageDriver) RestoreSnapshot(snapConfig *storage.SnapshotConfig) e
 }
 
 // DeleteSnapshot deletes a volume snapshot.
-func (d *SANStorageDriver) DeleteSnapshot(snapConfig *storage.SnapshotConfig) error {
+func (d *SANStorageDriver) DeleteSnapshot(ctx context.Context, snapConfig *storage.SnapshotConfig) error {
 
 	if d.Config.DebugTraceFlags["method"] {
 		fields := log.Fields{
@@ -934,7 +946,9 @@ func (d *SANStorageDriver) DeleteSnapshot(snapConfig *storage.SnapshotConfig) er
 
 // CreateClone creates a new volume from the named volume, either by direct clone or from the named snapshot. The E-series volume plugin
 // does not support cloning or snapshots, so this method always returns an error.
-func (d *SANStorageDriver) CreateClone(volConfig *storage.VolumeConfig, storagePool *storage.Pool) error {
+func (d *SANStorageDriver) CreateClone(
+	ctx context.Context, volConfig *storage.VolumeConfig, storagePool *storage.Pool,
+) error {
 
 	name := volConfig.InternalName
 	source := volConfig.CloneSourceVolumeInternal
@@ -955,7 +969,7 @@ func (d *SANStorageDriver) CreateClone(volConfig *storage.VolumeConfig, storageP
 	return fmt.Errorf("cloning is not supported by backend type %s", d.Name())
 }
 
-func (d *SANStorageDriver) Import(volConfig *storage.VolumeConfig, originalName string) error {
+func (d *SANStorageDriver) Import(ctx context.Context, volConfig *storage.VolumeConfig, originalName string) error {
 	return errors.New("import is not implemented")
 }
 
@@ -990,7 +1004,7 @@ func (d *SANStorageDriver) getVolume(name string) (api.VolumeEx, error) {
 	if err != nil {
 		return vol, fmt.Errorf("could not find volume %s: %v", name, err)
 	} else if !d.API.IsRefValid(vol.VolumeRef) {
-		return vol, fmt.Errorf("could not find volume %s", name)
+		return vol, utils.NotFoundError(fmt.Sprintf("could not find volume %s", name))
 	}
 	log.WithField("volume", vol).Debug("Found volume.")
 
@@ -1347,12 +1361,17 @@ func (d *SANStorageDriver) GetUpdateType(driverOrig storage.Driver) *roaring.Bit
 		bitmap.Add(storage.UsernameChange)
 	}
 
+	if drivers.StoragePrefixChanged(d.Config.CommonStorageDriverConfig, dOrig.Config.CommonStorageDriverConfig) {
+		bitmap.Add(storage.PrefixChange)
+	}
 	return bitmap
 }
 
 // Resize expands the volume size. This method relies on the desired state model of Kubernetes
 // and will not work with Docker.
-func (d *SANStorageDriver) Resize(volConfig *storage.VolumeConfig, sizeBytes uint64) error {
+func (d *SANStorageDriver) Resize(ctx context.Context, volConfig *storage.VolumeConfig, sizeBytes uint64) error {
+
+	client := d.API.WithContext(ctx)
 
 	name := volConfig.InternalName
 	vol, err := d.getVolume(name)
@@ -1363,7 +1382,7 @@ func (d *SANStorageDriver) Resize(volConfig *storage.VolumeConfig, sizeBytes uin
 	// Check to see if a volume expand operation is already being processed.
 	// If true then return the error, to K8S, which indicates that the volume resize is in progress.
 	// If no errors exist continue to attempt to resize the volume.
-	isResizing, err := d.API.ResizingVolume(vol)
+	isResizing, err := client.ResizingVolume(vol)
 	if isResizing || (err != nil) {
 		return err
 	}
@@ -1398,12 +1417,12 @@ func (d *SANStorageDriver) Resize(volConfig *storage.VolumeConfig, sizeBytes uin
 		return checkVolumeSizeLimitsError
 	}
 
-	d.API.ResizeVolume(vol, sizeBytes)
+	client.ResizeVolume(vol, sizeBytes)
 
 	// Check to see if a volume expand operation is still being processed.
 	// If true then return the error, to K8S, which indicates that the volume resize is in progress.
 	// If no errors exist then return nil as resize succeeded.
-	isResizing, err = d.API.ResizingVolume(vol)
+	isResizing, err = client.ResizingVolume(vol)
 	if isResizing || (err != nil) {
 		return err
 	}
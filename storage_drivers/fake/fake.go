@@ -4,6 +4,7 @@ package fake
 
 import (
 	"bytes"
+	"context"
 	"encoding/gob"
 	"encoding/json"
 	"errors"
@@ -35,6 +36,9 @@ const (
 	Region = "region"
 	Zone   = "zone"
 
+	// Journal steps of a volume create
+	journalStepCreateVolume = "createVolume"
+
 	// Constants for special use cases
 	PVC_creating_01       = "creating-c44b-40f9-a0a2-a09172f1a1f6"
 	PVC_creating_02       = "creating-686e-4960-9135-b040c2d54332"
@@ -217,7 +221,7 @@ func (d *StorageDriver) Initialize(
 			InternalName: volume.Name,
 			Size:         strconv.FormatUint(volume.SizeBytes, 10),
 		}
-		if err = d.Create(volConfig, requestedPool, make(map[string]sa.Request)); err != nil {
+		if err = d.Create(context.Background(), volConfig, requestedPool, make(map[string]sa.Request)); err != nil {
 			return fmt.Errorf("error creating volume %s; %v", volume.Name, err)
 		}
 
@@ -412,9 +416,14 @@ func (d *StorageDriver) validate() error {
 }
 
 func (d *StorageDriver) Create(
-	volConfig *storage.VolumeConfig, storagePool *storage.Pool, volAttributes map[string]sa.Request,
+	ctx context.Context, volConfig *storage.VolumeConfig, storagePool *storage.Pool, volAttributes map[string]sa.Request,
 ) error {
 
+	// A canceled operation fails like a canceled storage API call would
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("could not create volume %s; %v", volConfig.InternalName, err)
+	}
+
 	name := volConfig.InternalName
 	if _, ok := d.Volumes[name]; ok {
 		return drivers.NewVolumeExistsError(name)
@@ -476,6 +485,11 @@ func (d *StorageDriver) Create(
 			return err
 		}
 
+		journal := storage.JournalFromContext(ctx)
+		if err = journal.Begin(journalStepCreateVolume, name); err != nil {
+			return err
+		}
+
 		d.Volumes[name] = fake.Volume{
 			Name:          name,
 			RequestedPool: storagePool.Name,
@@ -485,6 +499,7 @@ func (d *StorageDriver) Create(
 		d.Snapshots[name] = make(map[string]*storage.Snapshot)
 		d.DestroyedVolumes[name] = false
 		fakePool.Bytes -= sizeBytes
+		journal.Complete(journalStepCreateVolume, name)
 
 		log.WithFields(log.Fields{
 			"backend":       d.Config.InstanceName,
@@ -570,14 +585,14 @@ func (d *StorageDriver) BootstrapVolume(volume *storage.Volume) {
 		"sizeBytes":     volume.Config.Size,
 	}
 
-	if err := d.Create(volume.Config, pool, volAttrs); err != nil {
+	if err := d.Create(context.Background(), volume.Config, pool, volAttrs); err != nil {
 		log.WithFields(logFields).Error("Failed to bootstrap fake volume.")
 	} else {
 		log.WithFields(logFields).Debug("Bootstrapped fake volume.")
 	}
 }
 
-func (d *StorageDriver) CreateClone(volConfig *storage.VolumeConfig, _ *storage.Pool) error {
+func (d *StorageDriver) CreateClone(ctx context.Context, volConfig *storage.VolumeConfig, _ *storage.Pool) error {
 
 	name := volConfig.InternalName
 	source := volConfig.CloneSourceVolumeInternal
@@ -608,6 +623,19 @@ func (d *StorageDriver) CreateClone(volConfig *storage.VolumeConfig, _ *storage.
 			sizeBytes, fakePool.Bytes, physicalPool)
 	}
 
+	// Like ONTAP FlexClones, fake clones share storage with their source if splitOnClone is false,
+	// but they are independent by default
+	cloneParent := ""
+	if volConfig.SplitOnClone != "" {
+		split, err := strconv.ParseBool(volConfig.SplitOnClone)
+		if err != nil {
+			return fmt.Errorf("invalid boolean value for splitOnClone: %v", err)
+		}
+		if !split {
+			cloneParent = source
+		}
+	}
+
 	if err := d.handleVolumeCreatingTransaction(name); err != nil {
 		return err
 	}
@@ -617,6 +645,7 @@ func (d *StorageDriver) CreateClone(volConfig *storage.VolumeConfig, _ *storage.
 		RequestedPool: sourceVolume.RequestedPool,
 		PhysicalPool:  physicalPool,
 		SizeBytes:     sizeBytes,
+		CloneParent:   cloneParent,
 	}
 	d.Snapshots[name] = make(map[string]*storage.Snapshot)
 	d.DestroyedVolumes[name] = false
@@ -635,7 +664,13 @@ func (d *StorageDriver) CreateClone(volConfig *storage.VolumeConfig, _ *storage.
 	return nil
 }
 
-func (d *StorageDriver) Import(volConfig *storage.VolumeConfig, originalName string) error {
+// CloneDependsOnSource reports whether a clone shares storage with its source volume.
+func (d *StorageDriver) CloneDependsOnSource(volConfig *storage.VolumeConfig) (bool, error) {
+	volume, ok := d.Volumes[volConfig.InternalName]
+	return ok && volume.CloneParent != "", nil
+}
+
+func (d *StorageDriver) Import(ctx context.Context, volConfig *storage.VolumeConfig, originalName string) error {
 
 	log.WithFields(log.Fields{
 		"volumeConfig": volConfig,
@@ -657,6 +692,34 @@ func (d *StorageDriver) Import(volConfig *storage.VolumeConfig, originalName str
 	return nil
 }
 
+// ImportCheck runs the checks for importing a volume and returns the changes the import would make
+// to the volume, without making them.
+func (d *StorageDriver) ImportCheck(volConfig *storage.VolumeConfig, originalName string) ([]string, error) {
+
+	log.WithFields(log.Fields{
+		"volumeConfig": volConfig,
+		"originalName": originalName,
+	}).Debug("ImportCheck")
+
+	importVolume, ok := d.Volumes[originalName]
+	if !ok {
+		return nil, fmt.Errorf("import volume %s not found", originalName)
+	}
+
+	volConfig.Size = strconv.FormatUint(importVolume.SizeBytes, 10)
+
+	changes := make([]string, 0)
+	if !volConfig.ImportNotManaged {
+		if _, ok := d.Volumes[volConfig.InternalName]; ok {
+			return nil, fmt.Errorf("volume %s cannot be renamed to %s, which already exists", originalName,
+				volConfig.InternalName)
+		}
+		changes = append(changes, fmt.Sprintf("rename volume %s to %s", originalName, volConfig.InternalName))
+	}
+
+	return changes, nil
+}
+
 func (d *StorageDriver) Rename(name string, newName string) error {
 
 	log.WithFields(log.Fields{
@@ -675,7 +738,7 @@ func (d *StorageDriver) Rename(name string, newName string) error {
 	return nil
 }
 
-func (d *StorageDriver) Destroy(name string) error {
+func (d *StorageDriver) Destroy(ctx context.Context, name string) error {
 
 	d.DestroyedVolumes[name] = true
 
@@ -705,8 +768,39 @@ func (d *StorageDriver) Destroy(name string) error {
 	return nil
 }
 
-func (d *StorageDriver) Publish(_ *storage.VolumeConfig, _ *utils.VolumePublishInfo) error {
-	return errors.New("fake driver does not support Publish")
+// RollbackJournal undoes the journaled steps of an interrupted volume create.
+func (d *StorageDriver) RollbackJournal(
+	ctx context.Context, volConfig *storage.VolumeConfig, journal *storage.OperationJournal,
+) error {
+	for i := len(journal.Steps) - 1; i >= 0; i-- {
+		step := journal.Steps[i]
+		switch step.Name {
+		case journalStepCreateVolume:
+			if err := d.Destroy(ctx, step.Resource); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unknown journal step %s", step.Name)
+		}
+	}
+	return nil
+}
+
+// Publish accepts any node, since fake volumes are never attached.
+func (d *StorageDriver) Publish(
+	ctx context.Context, volConfig *storage.VolumeConfig, publishInfo *utils.VolumePublishInfo,
+) error {
+	if _, ok := d.Volumes[volConfig.InternalName]; !ok {
+		return utils.NotFoundError(fmt.Sprintf("volume %s not found", volConfig.InternalName))
+	}
+
+	log.WithFields(log.Fields{
+		"backend": d.Config.InstanceName,
+		"Name":    volConfig.InternalName,
+		"node":    publishInfo.HostName,
+	}).Debug("Published fake volume.")
+
+	return nil
 }
 
 // GetSnapshot gets a snapshot.  To distinguish between an API error reading the snapshot
@@ -753,7 +847,9 @@ func (d *StorageDriver) GetSnapshots(volConfig *storage.VolumeConfig) ([]*storag
 }
 
 // CreateSnapshot creates a snapshot for the given volume
-func (d *StorageDriver) CreateSnapshot(snapConfig *storage.SnapshotConfig) (*storage.Snapshot, error) {
+func (d *StorageDriver) CreateSnapshot(
+	ctx context.Context, snapConfig *storage.SnapshotConfig,
+) (*storage.Snapshot, error) {
 
 	internalSnapName := snapConfig.InternalName
 	internalVolName := snapConfig.VolumeInternalName
@@ -802,6 +898,41 @@ func (d *StorageDriver) CreateSnapshot(snapConfig *storage.SnapshotConfig) (*sto
 	return snapshot, nil
 }
 
+// CreateGroupSnapshot creates snapshots of several volumes, all of which must exist, with the same timestamp.
+func (d *StorageDriver) CreateGroupSnapshot(snapConfigs []*storage.SnapshotConfig) ([]*storage.Snapshot, error) {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":       "CreateGroupSnapshot",
+			"Type":         "StorageDriver",
+			"snapshotName": snapConfigs[0].InternalName,
+			"volumes":      len(snapConfigs),
+		}
+		log.WithFields(fields).Debug(">>>> CreateGroupSnapshot")
+		defer log.WithFields(fields).Debug("<<<< CreateGroupSnapshot")
+	}
+
+	// Ensure all source volumes exist before creating any snapshot
+	for _, snapConfig := range snapConfigs {
+		if _, ok := d.Volumes[snapConfig.VolumeInternalName]; !ok {
+			return nil, fmt.Errorf("source volume %s not found", snapConfig.VolumeInternalName)
+		}
+	}
+
+	created := time.Now().UTC().Format(storage.SnapshotTimestampFormat)
+	snapshots := make([]*storage.Snapshot, 0, len(snapConfigs))
+	for _, snapConfig := range snapConfigs {
+		snapshot, err := d.CreateSnapshot(context.Background(), snapConfig)
+		if err != nil {
+			return nil, err
+		}
+		snapshot.Created = created
+		snapshots = append(snapshots, snapshot)
+	}
+
+	return snapshots, nil
+}
+
 func (d *StorageDriver) BootstrapSnapshot(snapshot *storage.Snapshot) {
 
 	logFields := log.Fields{
@@ -810,7 +941,7 @@ func (d *StorageDriver) BootstrapSnapshot(snapshot *storage.Snapshot) {
 		"sourceVolume": snapshot.Config.VolumeInternalName,
 	}
 
-	if newSnapshot, err := d.CreateSnapshot(snapshot.Config); err != nil {
+	if newSnapshot, err := d.CreateSnapshot(context.Background(), snapshot.Config); err != nil {
 		log.WithFields(logFields).Error("Failed to bootstrap fake snapshot.")
 	} else {
 		newSnapshot.Created = snapshot.Created
@@ -819,7 +950,7 @@ func (d *StorageDriver) BootstrapSnapshot(snapshot *storage.Snapshot) {
 }
 
 // RestoreSnapshot restores a volume (in place) from a snapshot.
-func (d *StorageDriver) RestoreSnapshot(snapConfig *storage.SnapshotConfig) error {
+func (d *StorageDriver) RestoreSnapshot(ctx context.Context, snapConfig *storage.SnapshotConfig) error {
 
 	internalSnapName := snapConfig.InternalName
 	internalVolName := snapConfig.VolumeInternalName
@@ -841,7 +972,7 @@ func (d *StorageDriver) RestoreSnapshot(snapConfig *storage.SnapshotConfig) erro
 }
 
 // DeleteSnapshot creates a snapshot of a volume.
-func (d *StorageDriver) DeleteSnapshot(snapConfig *storage.SnapshotConfig) error {
+func (d *StorageDriver) DeleteSnapshot(ctx context.Context, snapConfig *storage.SnapshotConfig) error {
 
 	internalSnapName := snapConfig.InternalName
 	internalVolName := snapConfig.VolumeInternalName
@@ -864,14 +995,121 @@ func (d *StorageDriver) Get(name string) error {
 
 	_, ok := d.Volumes[name]
 	if !ok {
-		return fmt.Errorf("could not find volume %s", name)
+		return utils.NotFoundError(fmt.Sprintf("could not find volume %s", name))
 	}
 
 	return nil
 }
 
+// MoveVolume moves a volume to a physical pool that satisfies the storage pool, unless it is already
+// on one.  Fake moves complete at once.
+func (d *StorageDriver) MoveVolume(volConfig *storage.VolumeConfig, storagePool *storage.Pool) error {
+
+	vol, ok := d.Volumes[volConfig.InternalName]
+	if !ok {
+		return fmt.Errorf("could not find volume %s", volConfig.InternalName)
+	}
+
+	physicalPools, err := d.getPoolsForCreate(volConfig, storagePool, make(map[string]sa.Request))
+	if err != nil {
+		return err
+	}
+
+	destination := physicalPools[0].Name
+	for _, physicalPool := range physicalPools {
+		if physicalPool.Name == vol.PhysicalPool {
+			destination = vol.PhysicalPool
+		}
+	}
+	if destination != vol.PhysicalPool {
+		fakePool, ok := d.fakePools[destination]
+		if !ok {
+			return fmt.Errorf("fake pool %s not found", destination)
+		}
+		if vol.SizeBytes > fakePool.Bytes {
+			return fmt.Errorf("volume is too large, requested %d bytes, have %d available in pool %s",
+				vol.SizeBytes, fakePool.Bytes, destination)
+		}
+		fakePool.Bytes -= vol.SizeBytes
+		if sourcePool, ok := d.fakePools[vol.PhysicalPool]; ok {
+			sourcePool.Bytes += vol.SizeBytes
+		}
+	}
+
+	vol.RequestedPool = storagePool.Name
+	vol.PhysicalPool = destination
+	d.Volumes[volConfig.InternalName] = vol
+
+	return nil
+}
+
+// GetVolumeMoveProgress reports that a volume's move is complete, as fake moves complete at once.
+func (d *StorageDriver) GetVolumeMoveProgress(volConfig *storage.VolumeConfig) (uint64, uint64, bool, error) {
+
+	vol, ok := d.Volumes[volConfig.InternalName]
+	if !ok {
+		return 0, 0, false, fmt.Errorf("could not find volume %s", volConfig.InternalName)
+	}
+
+	return vol.SizeBytes, vol.SizeBytes, true, nil
+}
+
+// GetVolumeUsage returns the space consumed by a volume.  Fake volumes hold no data, so all
+// of their space is available.
+func (d *StorageDriver) GetVolumeUsage(volConfig *storage.VolumeConfig) (*storage.VolumeUsage, error) {
+
+	vol, ok := d.Volumes[volConfig.InternalName]
+	if !ok {
+		return nil, fmt.Errorf("could not find volume %s", volConfig.InternalName)
+	}
+
+	return &storage.VolumeUsage{TotalBytes: vol.SizeBytes, AvailableBytes: vol.SizeBytes}, nil
+}
+
+// GetVolumeCondition reports a volume that is missing from the driver as abnormal.
+func (d *StorageDriver) GetVolumeCondition(volConfig *storage.VolumeConfig) (*storage.VolumeCondition, error) {
+
+	if _, ok := d.Volumes[volConfig.InternalName]; !ok {
+		return &storage.VolumeCondition{
+			Abnormal: true,
+			Message:  fmt.Sprintf("volume %s not found", volConfig.InternalName),
+		}, nil
+	}
+
+	return &storage.VolumeCondition{}, nil
+}
+
+// GetStorageCapacity reports the free bytes in each fake pool, whose size is its free bytes plus
+// those of its volumes.  Virtual pools draw on every fake pool.
+func (d *StorageDriver) GetStorageCapacity() (*storage.StorageCapacity, error) {
+
+	capacity := &storage.StorageCapacity{
+		SourceFreeBytes:  make(map[string]int64),
+		PoolSources:      make(map[string][]string),
+		SourceTotalBytes: make(map[string]int64),
+	}
+
+	allSources := make([]string, 0, len(d.fakePools))
+	for name, fakePool := range d.fakePools {
+		capacity.SourceFreeBytes[name] = int64(fakePool.Bytes)
+		capacity.SourceTotalBytes[name] = int64(fakePool.Bytes)
+		capacity.PoolSources[name] = []string{name}
+		allSources = append(allSources, name)
+	}
+	for _, volume := range d.Volumes {
+		if _, ok := capacity.SourceTotalBytes[volume.PhysicalPool]; ok {
+			capacity.SourceTotalBytes[volume.PhysicalPool] += int64(volume.SizeBytes)
+		}
+	}
+	for name := range d.virtualPools {
+		capacity.PoolSources[name] = allSources
+	}
+
+	return capacity, nil
+}
+
 // Resize expands the volume size.
-func (d *StorageDriver) Resize(volConfig *storage.VolumeConfig, sizeBytes uint64) error {
+func (d *StorageDriver) Resize(ctx context.Context, volConfig *storage.VolumeConfig, sizeBytes uint64) error {
 
 	name := volConfig.InternalName
 	vol := d.Volumes[name]
@@ -5,6 +5,7 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"encoding/json"
 	"errors"
@@ -21,6 +22,7 @@ import (
 	"golang.org/x/oauth2/google"
 
 	drivers "github.com/netapp/trident/storage_drivers"
+	"github.com/netapp/trident/storage_drivers/apimetrics"
 	"github.com/netapp/trident/utils"
 )
 
@@ -53,6 +55,10 @@ type ClientConfig struct {
 
 	// Options
 	DebugTraceFlags map[string]bool
+
+	// Identifies the backend in API metrics
+	BackendName       string
+	StorageDriverName string
 }
 
 type Client struct {
@@ -60,19 +66,37 @@ type Client struct {
 	tokenSource *oauth2.TokenSource
 	token       *oauth2.Token
 	m           *sync.Mutex
+	metrics     *apimetrics.Recorder
+
+	ctx context.Context
 }
 
 // NewDriver is a factory method for creating a new instance.
 func NewDriver(config ClientConfig) *Client {
 
 	d := &Client{
-		config: &config,
-		m:      &sync.Mutex{},
+		config:  &config,
+		m:       &sync.Mutex{},
+		metrics: apimetrics.NewRecorder(config.BackendName, config.StorageDriverName),
 	}
 
 	return d
 }
 
+// WithContext returns a copy of this client whose requests are cancelled when the context is done.
+func (d *Client) WithContext(ctx context.Context) *Client {
+	clone := *d
+	clone.ctx = ctx
+	return &clone
+}
+
+func (d *Client) requestContext() context.Context {
+	if d.ctx == nil {
+		return context.Background()
+	}
+	return d.ctx
+}
+
 func (d *Client) makeURL(resourcePath string) string {
 	return fmt.Sprintf("%s/v2/projects/%s/locations/%s%s", apiServer, d.config.ProjectNumber, d.config.APIRegion, resourcePath)
 }
@@ -146,9 +170,9 @@ func (d *Client) InvokeAPI(requestBody []byte, method string, gcpURL string) (*h
 	}
 
 	if requestBody == nil {
-		request, err = http.NewRequest(method, gcpURL, nil)
+		request, err = http.NewRequestWithContext(d.requestContext(), method, gcpURL, nil)
 	} else {
-		request, err = http.NewRequest(method, gcpURL, bytes.NewBuffer(requestBody))
+		request, err = http.NewRequestWithContext(d.requestContext(), method, gcpURL, bytes.NewBuffer(requestBody))
 	}
 	if err != nil {
 		return nil, nil, err
@@ -190,12 +214,16 @@ func (d *Client) InvokeAPI(requestBody []byte, method string, gcpURL string) (*h
 		Transport: tr,
 		Timeout:   httpTimeoutSeconds * time.Second,
 	}
-	response, err = d.invokeAPIWithRetry(client, request)
+	operation := apimetrics.OperationForREST(method, gcpURL)
+	recordResult := d.metrics.Start(operation)
+	response, err = d.invokeAPIWithRetry(client, request, operation)
 
 	if response == nil && err != nil {
+		recordResult(apimetrics.ResultTransportError)
 		log.Warnf("Error communicating with GCP REST interface. %v", err)
 		return nil, nil, err
 	} else if response != nil {
+		recordResult(apimetrics.ResultForHTTPStatus(response.StatusCode))
 		defer func() { _ = response.Body.Close() }()
 	}
 
@@ -216,7 +244,9 @@ func (d *Client) invokeAPINoRetry(client *http.Client, request *http.Request) (*
 	return client.Do(request)
 }
 
-func (d *Client) invokeAPIWithRetry(client *http.Client, request *http.Request) (*http.Response, error) {
+func (d *Client) invokeAPIWithRetry(
+	client *http.Client, request *http.Request, operation string,
+) (*http.Response, error) {
 
 	var response *http.Response
 	var err error
@@ -240,6 +270,7 @@ func (d *Client) invokeAPIWithRetry(client *http.Client, request *http.Request)
 		return nil
 	}
 	invokeNotify := func(err error, duration time.Duration) {
+		d.metrics.RecordRetry(operation)
 		log.WithFields(log.Fields{
 			"increment": duration,
 			"message":   err.Error(),
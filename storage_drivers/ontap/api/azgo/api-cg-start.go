@@ -0,0 +1,187 @@
+package azgo
+
+import (
+	"encoding/xml"
+	"reflect"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// CgStartRequest is a structure to represent a cg-start Request ZAPI object
+type CgStartRequest struct {
+	XMLName        xml.Name               `xml:"cg-start"`
+	SnapshotPtr    *string                `xml:"snapshot"`
+	TimeoutPtr     *string                `xml:"timeout"`
+	UserTimeoutPtr *int                   `xml:"user-timeout"`
+	VolumesPtr     *CgStartRequestVolumes `xml:"volumes"`
+}
+
+// CgStartResponse is a structure to represent a cg-start Response ZAPI object
+type CgStartResponse struct {
+	XMLName         xml.Name              `xml:"netapp"`
+	ResponseVersion string                `xml:"version,attr"`
+	ResponseXmlns   string                `xml:"xmlns,attr"`
+	Result          CgStartResponseResult `xml:"results"`
+}
+
+// NewCgStartResponse is a factory method for creating new instances of CgStartResponse objects
+func NewCgStartResponse() *CgStartResponse {
+	return &CgStartResponse{}
+}
+
+// String returns a string representation of this object's fields and implements the Stringer interface
+func (o CgStartResponse) String() string {
+	return ToString(reflect.ValueOf(o))
+}
+
+// ToXML converts this object into an xml string representation
+func (o *CgStartResponse) ToXML() (string, error) {
+	output, err := xml.MarshalIndent(o, " ", "    ")
+	if err != nil {
+		log.Errorf("error: %v", err)
+	}
+	return string(output), err
+}
+
+// CgStartResponseResult is a structure to represent a cg-start Response Result ZAPI object
+type CgStartResponseResult struct {
+	XMLName          xml.Name `xml:"results"`
+	ResultStatusAttr string   `xml:"status,attr"`
+	ResultReasonAttr string   `xml:"reason,attr"`
+	ResultErrnoAttr  string   `xml:"errno,attr"`
+	CgIdPtr          *int     `xml:"cg-id"`
+}
+
+// NewCgStartRequest is a factory method for creating new instances of CgStartRequest objects
+func NewCgStartRequest() *CgStartRequest {
+	return &CgStartRequest{}
+}
+
+// NewCgStartResponseResult is a factory method for creating new instances of CgStartResponseResult objects
+func NewCgStartResponseResult() *CgStartResponseResult {
+	return &CgStartResponseResult{}
+}
+
+// ToXML converts this object into an xml string representation
+func (o *CgStartRequest) ToXML() (string, error) {
+	output, err := xml.MarshalIndent(o, " ", "    ")
+	if err != nil {
+		log.Errorf("error: %v", err)
+	}
+	return string(output), err
+}
+
+// ToXML converts this object into an xml string representation
+func (o *CgStartResponseResult) ToXML() (string, error) {
+	output, err := xml.MarshalIndent(o, " ", "    ")
+	if err != nil {
+		log.Errorf("error: %v", err)
+	}
+	return string(output), err
+}
+
+// String returns a string representation of this object's fields and implements the Stringer interface
+func (o CgStartRequest) String() string {
+	return ToString(reflect.ValueOf(o))
+}
+
+// String returns a string representation of this object's fields and implements the Stringer interface
+func (o CgStartResponseResult) String() string {
+	return ToString(reflect.ValueOf(o))
+}
+
+// ExecuteUsing converts this object to a ZAPI XML representation and uses the supplied ZapiRunner to send to a filer
+
+func (o *CgStartRequest) ExecuteUsing(zr *ZapiRunner) (*CgStartResponse, error) {
+	return o.executeWithoutIteration(zr)
+}
+
+// executeWithoutIteration converts this object to a ZAPI XML representation and uses the supplied ZapiRunner to send to a filer
+
+func (o *CgStartRequest) executeWithoutIteration(zr *ZapiRunner) (*CgStartResponse, error) {
+	result, err := zr.ExecuteUsing(o, "CgStartRequest", NewCgStartResponse())
+	if result == nil {
+		return nil, err
+	}
+	return result.(*CgStartResponse), err
+}
+
+// Snapshot is a 'getter' method
+func (o *CgStartRequest) Snapshot() string {
+	r := *o.SnapshotPtr
+	return r
+}
+
+// SetSnapshot is a fluent style 'setter' method that can be chained
+func (o *CgStartRequest) SetSnapshot(newValue string) *CgStartRequest {
+	o.SnapshotPtr = &newValue
+	return o
+}
+
+// Timeout is a 'getter' method
+func (o *CgStartRequest) Timeout() string {
+	r := *o.TimeoutPtr
+	return r
+}
+
+// SetTimeout is a fluent style 'setter' method that can be chained
+func (o *CgStartRequest) SetTimeout(newValue string) *CgStartRequest {
+	o.TimeoutPtr = &newValue
+	return o
+}
+
+// UserTimeout is a 'getter' method
+func (o *CgStartRequest) UserTimeout() int {
+	r := *o.UserTimeoutPtr
+	return r
+}
+
+// SetUserTimeout is a fluent style 'setter' method that can be chained
+func (o *CgStartRequest) SetUserTimeout(newValue int) *CgStartRequest {
+	o.UserTimeoutPtr = &newValue
+	return o
+}
+
+// CgStartRequestVolumes is a wrapper
+type CgStartRequestVolumes struct {
+	XMLName       xml.Name `xml:"volumes"`
+	VolumeNamePtr []string `xml:"volume-name"`
+}
+
+// VolumeName is a 'getter' method
+func (o *CgStartRequestVolumes) VolumeName() []string {
+	r := o.VolumeNamePtr
+	return r
+}
+
+// SetVolumeName is a fluent style 'setter' method that can be chained
+func (o *CgStartRequestVolumes) SetVolumeName(newValue []string) *CgStartRequestVolumes {
+	newSlice := make([]string, len(newValue))
+	copy(newSlice, newValue)
+	o.VolumeNamePtr = newSlice
+	return o
+}
+
+// Volumes is a 'getter' method
+func (o *CgStartRequest) Volumes() CgStartRequestVolumes {
+	r := *o.VolumesPtr
+	return r
+}
+
+// SetVolumes is a fluent style 'setter' method that can be chained
+func (o *CgStartRequest) SetVolumes(newValue CgStartRequestVolumes) *CgStartRequest {
+	o.VolumesPtr = &newValue
+	return o
+}
+
+// CgId is a 'getter' method
+func (o *CgStartResponseResult) CgId() int {
+	r := *o.CgIdPtr
+	return r
+}
+
+// SetCgId is a fluent style 'setter' method that can be chained
+func (o *CgStartResponseResult) SetCgId(newValue int) *CgStartResponseResult {
+	o.CgIdPtr = &newValue
+	return o
+}
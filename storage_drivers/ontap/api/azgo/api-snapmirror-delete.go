@@ -0,0 +1,115 @@
+package azgo
+
+import (
+	"encoding/xml"
+	"reflect"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// SnapmirrorDeleteRequest is a structure to represent a snapmirror-delete Request ZAPI object
+type SnapmirrorDeleteRequest struct {
+	XMLName                xml.Name `xml:"snapmirror-delete"`
+	DestinationLocationPtr *string  `xml:"destination-location"`
+}
+
+// SnapmirrorDeleteResponse is a structure to represent a snapmirror-delete Response ZAPI object
+type SnapmirrorDeleteResponse struct {
+	XMLName         xml.Name                       `xml:"netapp"`
+	ResponseVersion string                         `xml:"version,attr"`
+	ResponseXmlns   string                         `xml:"xmlns,attr"`
+	Result          SnapmirrorDeleteResponseResult `xml:"results"`
+}
+
+// NewSnapmirrorDeleteResponse is a factory method for creating new instances of SnapmirrorDeleteResponse objects
+func NewSnapmirrorDeleteResponse() *SnapmirrorDeleteResponse {
+	return &SnapmirrorDeleteResponse{}
+}
+
+// String returns a string representation of this object's fields and implements the Stringer interface
+func (o SnapmirrorDeleteResponse) String() string {
+	return ToString(reflect.ValueOf(o))
+}
+
+// ToXML converts this object into an xml string representation
+func (o *SnapmirrorDeleteResponse) ToXML() (string, error) {
+	output, err := xml.MarshalIndent(o, " ", "    ")
+	if err != nil {
+		log.Errorf("error: %v", err)
+	}
+	return string(output), err
+}
+
+// SnapmirrorDeleteResponseResult is a structure to represent a snapmirror-delete Response Result ZAPI object
+type SnapmirrorDeleteResponseResult struct {
+	XMLName          xml.Name `xml:"results"`
+	ResultStatusAttr string   `xml:"status,attr"`
+	ResultReasonAttr string   `xml:"reason,attr"`
+	ResultErrnoAttr  string   `xml:"errno,attr"`
+}
+
+// NewSnapmirrorDeleteRequest is a factory method for creating new instances of SnapmirrorDeleteRequest objects
+func NewSnapmirrorDeleteRequest() *SnapmirrorDeleteRequest {
+	return &SnapmirrorDeleteRequest{}
+}
+
+// NewSnapmirrorDeleteResponseResult is a factory method for creating new instances of SnapmirrorDeleteResponseResult objects
+func NewSnapmirrorDeleteResponseResult() *SnapmirrorDeleteResponseResult {
+	return &SnapmirrorDeleteResponseResult{}
+}
+
+// ToXML converts this object into an xml string representation
+func (o *SnapmirrorDeleteRequest) ToXML() (string, error) {
+	output, err := xml.MarshalIndent(o, " ", "    ")
+	if err != nil {
+		log.Errorf("error: %v", err)
+	}
+	return string(output), err
+}
+
+// ToXML converts this object into an xml string representation
+func (o *SnapmirrorDeleteResponseResult) ToXML() (string, error) {
+	output, err := xml.MarshalIndent(o, " ", "    ")
+	if err != nil {
+		log.Errorf("error: %v", err)
+	}
+	return string(output), err
+}
+
+// String returns a string representation of this object's fields and implements the Stringer interface
+func (o SnapmirrorDeleteRequest) String() string {
+	return ToString(reflect.ValueOf(o))
+}
+
+// String returns a string representation of this object's fields and implements the Stringer interface
+func (o SnapmirrorDeleteResponseResult) String() string {
+	return ToString(reflect.ValueOf(o))
+}
+
+// ExecuteUsing converts this object to a ZAPI XML representation and uses the supplied ZapiRunner to send to a filer
+
+func (o *SnapmirrorDeleteRequest) ExecuteUsing(zr *ZapiRunner) (*SnapmirrorDeleteResponse, error) {
+	return o.executeWithoutIteration(zr)
+}
+
+// executeWithoutIteration converts this object to a ZAPI XML representation and uses the supplied ZapiRunner to send to a filer
+
+func (o *SnapmirrorDeleteRequest) executeWithoutIteration(zr *ZapiRunner) (*SnapmirrorDeleteResponse, error) {
+	result, err := zr.ExecuteUsing(o, "SnapmirrorDeleteRequest", NewSnapmirrorDeleteResponse())
+	if result == nil {
+		return nil, err
+	}
+	return result.(*SnapmirrorDeleteResponse), err
+}
+
+// DestinationLocation is a 'getter' method
+func (o *SnapmirrorDeleteRequest) DestinationLocation() string {
+	r := *o.DestinationLocationPtr
+	return r
+}
+
+// SetDestinationLocation is a fluent style 'setter' method that can be chained
+func (o *SnapmirrorDeleteRequest) SetDestinationLocation(newValue string) *SnapmirrorDeleteRequest {
+	o.DestinationLocationPtr = &newValue
+	return o
+}
@@ -0,0 +1,151 @@
+package azgo
+
+import (
+	"encoding/xml"
+	"reflect"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// SnapmirrorGetRequest is a structure to represent a snapmirror-get Request ZAPI object
+type SnapmirrorGetRequest struct {
+	XMLName                xml.Name `xml:"snapmirror-get"`
+	DestinationLocationPtr *string  `xml:"destination-location"`
+}
+
+// SnapmirrorGetResponse is a structure to represent a snapmirror-get Response ZAPI object
+type SnapmirrorGetResponse struct {
+	XMLName         xml.Name                    `xml:"netapp"`
+	ResponseVersion string                      `xml:"version,attr"`
+	ResponseXmlns   string                      `xml:"xmlns,attr"`
+	Result          SnapmirrorGetResponseResult `xml:"results"`
+}
+
+// NewSnapmirrorGetResponse is a factory method for creating new instances of SnapmirrorGetResponse objects
+func NewSnapmirrorGetResponse() *SnapmirrorGetResponse {
+	return &SnapmirrorGetResponse{}
+}
+
+// String returns a string representation of this object's fields and implements the Stringer interface
+func (o SnapmirrorGetResponse) String() string {
+	return ToString(reflect.ValueOf(o))
+}
+
+// ToXML converts this object into an xml string representation
+func (o *SnapmirrorGetResponse) ToXML() (string, error) {
+	output, err := xml.MarshalIndent(o, " ", "    ")
+	if err != nil {
+		log.Errorf("error: %v", err)
+	}
+	return string(output), err
+}
+
+// SnapmirrorGetResponseResult is a structure to represent a snapmirror-get Response Result ZAPI object
+type SnapmirrorGetResponseResult struct {
+	XMLName          xml.Name                               `xml:"results"`
+	ResultStatusAttr string                                 `xml:"status,attr"`
+	ResultReasonAttr string                                 `xml:"reason,attr"`
+	ResultErrnoAttr  string                                 `xml:"errno,attr"`
+	AttributesPtr    *SnapmirrorGetResponseResultAttributes `xml:"attributes"`
+}
+
+// NewSnapmirrorGetRequest is a factory method for creating new instances of SnapmirrorGetRequest objects
+func NewSnapmirrorGetRequest() *SnapmirrorGetRequest {
+	return &SnapmirrorGetRequest{}
+}
+
+// NewSnapmirrorGetResponseResult is a factory method for creating new instances of SnapmirrorGetResponseResult objects
+func NewSnapmirrorGetResponseResult() *SnapmirrorGetResponseResult {
+	return &SnapmirrorGetResponseResult{}
+}
+
+// ToXML converts this object into an xml string representation
+func (o *SnapmirrorGetRequest) ToXML() (string, error) {
+	output, err := xml.MarshalIndent(o, " ", "    ")
+	if err != nil {
+		log.Errorf("error: %v", err)
+	}
+	return string(output), err
+}
+
+// ToXML converts this object into an xml string representation
+func (o *SnapmirrorGetResponseResult) ToXML() (string, error) {
+	output, err := xml.MarshalIndent(o, " ", "    ")
+	if err != nil {
+		log.Errorf("error: %v", err)
+	}
+	return string(output), err
+}
+
+// String returns a string representation of this object's fields and implements the Stringer interface
+func (o SnapmirrorGetRequest) String() string {
+	return ToString(reflect.ValueOf(o))
+}
+
+// String returns a string representation of this object's fields and implements the Stringer interface
+func (o SnapmirrorGetResponseResult) String() string {
+	return ToString(reflect.ValueOf(o))
+}
+
+// ExecuteUsing converts this object to a ZAPI XML representation and uses the supplied ZapiRunner to send to a filer
+
+func (o *SnapmirrorGetRequest) ExecuteUsing(zr *ZapiRunner) (*SnapmirrorGetResponse, error) {
+	return o.executeWithoutIteration(zr)
+}
+
+// executeWithoutIteration converts this object to a ZAPI XML representation and uses the supplied ZapiRunner to send to a filer
+
+func (o *SnapmirrorGetRequest) executeWithoutIteration(zr *ZapiRunner) (*SnapmirrorGetResponse, error) {
+	result, err := zr.ExecuteUsing(o, "SnapmirrorGetRequest", NewSnapmirrorGetResponse())
+	if result == nil {
+		return nil, err
+	}
+	return result.(*SnapmirrorGetResponse), err
+}
+
+// DestinationLocation is a 'getter' method
+func (o *SnapmirrorGetRequest) DestinationLocation() string {
+	r := *o.DestinationLocationPtr
+	return r
+}
+
+// SetDestinationLocation is a fluent style 'setter' method that can be chained
+func (o *SnapmirrorGetRequest) SetDestinationLocation(newValue string) *SnapmirrorGetRequest {
+	o.DestinationLocationPtr = &newValue
+	return o
+}
+
+// SnapmirrorGetResponseResultAttributes is a wrapper
+type SnapmirrorGetResponseResultAttributes struct {
+	XMLName           xml.Name            `xml:"attributes"`
+	SnapmirrorInfoPtr *SnapmirrorInfoType `xml:"snapmirror-info"`
+}
+
+// String returns a string representation of this object's fields and implements the Stringer interface
+func (o SnapmirrorGetResponseResultAttributes) String() string {
+	return ToString(reflect.ValueOf(o))
+}
+
+// SnapmirrorInfo is a 'getter' method
+func (o *SnapmirrorGetResponseResultAttributes) SnapmirrorInfo() SnapmirrorInfoType {
+	r := *o.SnapmirrorInfoPtr
+	return r
+}
+
+// SetSnapmirrorInfo is a fluent style 'setter' method that can be chained
+func (o *SnapmirrorGetResponseResultAttributes) SetSnapmirrorInfo(newValue SnapmirrorInfoType) *SnapmirrorGetResponseResultAttributes {
+	o.SnapmirrorInfoPtr = &newValue
+	return o
+}
+
+// Attributes is a 'getter' method
+func (o *SnapmirrorGetResponseResult) Attributes() SnapmirrorGetResponseResultAttributes {
+	r := *o.AttributesPtr
+	return r
+}
+
+// SetAttributes is a fluent style 'setter' method that can be chained
+func (o *SnapmirrorGetResponseResult) SetAttributes(newValue SnapmirrorGetResponseResultAttributes) *SnapmirrorGetResponseResult {
+	o.AttributesPtr = &newValue
+	return o
+}
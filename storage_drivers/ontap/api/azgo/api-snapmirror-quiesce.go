@@ -0,0 +1,115 @@
+package azgo
+
+import (
+	"encoding/xml"
+	"reflect"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// SnapmirrorQuiesceRequest is a structure to represent a snapmirror-quiesce Request ZAPI object
+type SnapmirrorQuiesceRequest struct {
+	XMLName                xml.Name `xml:"snapmirror-quiesce"`
+	DestinationLocationPtr *string  `xml:"destination-location"`
+}
+
+// SnapmirrorQuiesceResponse is a structure to represent a snapmirror-quiesce Response ZAPI object
+type SnapmirrorQuiesceResponse struct {
+	XMLName         xml.Name                        `xml:"netapp"`
+	ResponseVersion string                          `xml:"version,attr"`
+	ResponseXmlns   string                          `xml:"xmlns,attr"`
+	Result          SnapmirrorQuiesceResponseResult `xml:"results"`
+}
+
+// NewSnapmirrorQuiesceResponse is a factory method for creating new instances of SnapmirrorQuiesceResponse objects
+func NewSnapmirrorQuiesceResponse() *SnapmirrorQuiesceResponse {
+	return &SnapmirrorQuiesceResponse{}
+}
+
+// String returns a string representation of this object's fields and implements the Stringer interface
+func (o SnapmirrorQuiesceResponse) String() string {
+	return ToString(reflect.ValueOf(o))
+}
+
+// ToXML converts this object into an xml string representation
+func (o *SnapmirrorQuiesceResponse) ToXML() (string, error) {
+	output, err := xml.MarshalIndent(o, " ", "    ")
+	if err != nil {
+		log.Errorf("error: %v", err)
+	}
+	return string(output), err
+}
+
+// SnapmirrorQuiesceResponseResult is a structure to represent a snapmirror-quiesce Response Result ZAPI object
+type SnapmirrorQuiesceResponseResult struct {
+	XMLName          xml.Name `xml:"results"`
+	ResultStatusAttr string   `xml:"status,attr"`
+	ResultReasonAttr string   `xml:"reason,attr"`
+	ResultErrnoAttr  string   `xml:"errno,attr"`
+}
+
+// NewSnapmirrorQuiesceRequest is a factory method for creating new instances of SnapmirrorQuiesceRequest objects
+func NewSnapmirrorQuiesceRequest() *SnapmirrorQuiesceRequest {
+	return &SnapmirrorQuiesceRequest{}
+}
+
+// NewSnapmirrorQuiesceResponseResult is a factory method for creating new instances of SnapmirrorQuiesceResponseResult objects
+func NewSnapmirrorQuiesceResponseResult() *SnapmirrorQuiesceResponseResult {
+	return &SnapmirrorQuiesceResponseResult{}
+}
+
+// ToXML converts this object into an xml string representation
+func (o *SnapmirrorQuiesceRequest) ToXML() (string, error) {
+	output, err := xml.MarshalIndent(o, " ", "    ")
+	if err != nil {
+		log.Errorf("error: %v", err)
+	}
+	return string(output), err
+}
+
+// ToXML converts this object into an xml string representation
+func (o *SnapmirrorQuiesceResponseResult) ToXML() (string, error) {
+	output, err := xml.MarshalIndent(o, " ", "    ")
+	if err != nil {
+		log.Errorf("error: %v", err)
+	}
+	return string(output), err
+}
+
+// String returns a string representation of this object's fields and implements the Stringer interface
+func (o SnapmirrorQuiesceRequest) String() string {
+	return ToString(reflect.ValueOf(o))
+}
+
+// String returns a string representation of this object's fields and implements the Stringer interface
+func (o SnapmirrorQuiesceResponseResult) String() string {
+	return ToString(reflect.ValueOf(o))
+}
+
+// ExecuteUsing converts this object to a ZAPI XML representation and uses the supplied ZapiRunner to send to a filer
+
+func (o *SnapmirrorQuiesceRequest) ExecuteUsing(zr *ZapiRunner) (*SnapmirrorQuiesceResponse, error) {
+	return o.executeWithoutIteration(zr)
+}
+
+// executeWithoutIteration converts this object to a ZAPI XML representation and uses the supplied ZapiRunner to send to a filer
+
+func (o *SnapmirrorQuiesceRequest) executeWithoutIteration(zr *ZapiRunner) (*SnapmirrorQuiesceResponse, error) {
+	result, err := zr.ExecuteUsing(o, "SnapmirrorQuiesceRequest", NewSnapmirrorQuiesceResponse())
+	if result == nil {
+		return nil, err
+	}
+	return result.(*SnapmirrorQuiesceResponse), err
+}
+
+// DestinationLocation is a 'getter' method
+func (o *SnapmirrorQuiesceRequest) DestinationLocation() string {
+	r := *o.DestinationLocationPtr
+	return r
+}
+
+// SetDestinationLocation is a fluent style 'setter' method that can be chained
+func (o *SnapmirrorQuiesceRequest) SetDestinationLocation(newValue string) *SnapmirrorQuiesceRequest {
+	o.DestinationLocationPtr = &newValue
+	return o
+}
@@ -0,0 +1,115 @@
+package azgo
+
+import (
+	"encoding/xml"
+	"reflect"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// SnapmirrorUpdateRequest is a structure to represent a snapmirror-update Request ZAPI object
+type SnapmirrorUpdateRequest struct {
+	XMLName                xml.Name `xml:"snapmirror-update"`
+	DestinationLocationPtr *string  `xml:"destination-location"`
+}
+
+// SnapmirrorUpdateResponse is a structure to represent a snapmirror-update Response ZAPI object
+type SnapmirrorUpdateResponse struct {
+	XMLName         xml.Name                       `xml:"netapp"`
+	ResponseVersion string                         `xml:"version,attr"`
+	ResponseXmlns   string                         `xml:"xmlns,attr"`
+	Result          SnapmirrorUpdateResponseResult `xml:"results"`
+}
+
+// NewSnapmirrorUpdateResponse is a factory method for creating new instances of SnapmirrorUpdateResponse objects
+func NewSnapmirrorUpdateResponse() *SnapmirrorUpdateResponse {
+	return &SnapmirrorUpdateResponse{}
+}
+
+// String returns a string representation of this object's fields and implements the Stringer interface
+func (o SnapmirrorUpdateResponse) String() string {
+	return ToString(reflect.ValueOf(o))
+}
+
+// ToXML converts this object into an xml string representation
+func (o *SnapmirrorUpdateResponse) ToXML() (string, error) {
+	output, err := xml.MarshalIndent(o, " ", "    ")
+	if err != nil {
+		log.Errorf("error: %v", err)
+	}
+	return string(output), err
+}
+
+// SnapmirrorUpdateResponseResult is a structure to represent a snapmirror-update Response Result ZAPI object
+type SnapmirrorUpdateResponseResult struct {
+	XMLName          xml.Name `xml:"results"`
+	ResultStatusAttr string   `xml:"status,attr"`
+	ResultReasonAttr string   `xml:"reason,attr"`
+	ResultErrnoAttr  string   `xml:"errno,attr"`
+}
+
+// NewSnapmirrorUpdateRequest is a factory method for creating new instances of SnapmirrorUpdateRequest objects
+func NewSnapmirrorUpdateRequest() *SnapmirrorUpdateRequest {
+	return &SnapmirrorUpdateRequest{}
+}
+
+// NewSnapmirrorUpdateResponseResult is a factory method for creating new instances of SnapmirrorUpdateResponseResult objects
+func NewSnapmirrorUpdateResponseResult() *SnapmirrorUpdateResponseResult {
+	return &SnapmirrorUpdateResponseResult{}
+}
+
+// ToXML converts this object into an xml string representation
+func (o *SnapmirrorUpdateRequest) ToXML() (string, error) {
+	output, err := xml.MarshalIndent(o, " ", "    ")
+	if err != nil {
+		log.Errorf("error: %v", err)
+	}
+	return string(output), err
+}
+
+// ToXML converts this object into an xml string representation
+func (o *SnapmirrorUpdateResponseResult) ToXML() (string, error) {
+	output, err := xml.MarshalIndent(o, " ", "    ")
+	if err != nil {
+		log.Errorf("error: %v", err)
+	}
+	return string(output), err
+}
+
+// String returns a string representation of this object's fields and implements the Stringer interface
+func (o SnapmirrorUpdateRequest) String() string {
+	return ToString(reflect.ValueOf(o))
+}
+
+// String returns a string representation of this object's fields and implements the Stringer interface
+func (o SnapmirrorUpdateResponseResult) String() string {
+	return ToString(reflect.ValueOf(o))
+}
+
+// ExecuteUsing converts this object to a ZAPI XML representation and uses the supplied ZapiRunner to send to a filer
+
+func (o *SnapmirrorUpdateRequest) ExecuteUsing(zr *ZapiRunner) (*SnapmirrorUpdateResponse, error) {
+	return o.executeWithoutIteration(zr)
+}
+
+// executeWithoutIteration converts this object to a ZAPI XML representation and uses the supplied ZapiRunner to send to a filer
+
+func (o *SnapmirrorUpdateRequest) executeWithoutIteration(zr *ZapiRunner) (*SnapmirrorUpdateResponse, error) {
+	result, err := zr.ExecuteUsing(o, "SnapmirrorUpdateRequest", NewSnapmirrorUpdateResponse())
+	if result == nil {
+		return nil, err
+	}
+	return result.(*SnapmirrorUpdateResponse), err
+}
+
+// DestinationLocation is a 'getter' method
+func (o *SnapmirrorUpdateRequest) DestinationLocation() string {
+	r := *o.DestinationLocationPtr
+	return r
+}
+
+// SetDestinationLocation is a fluent style 'setter' method that can be chained
+func (o *SnapmirrorUpdateRequest) SetDestinationLocation(newValue string) *SnapmirrorUpdateRequest {
+	o.DestinationLocationPtr = &newValue
+	return o
+}
@@ -0,0 +1,347 @@
+package azgo
+
+import (
+	"encoding/xml"
+	"reflect"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// VolumeMoveGetIterRequest is a structure to represent a volume-move-get-iter Request ZAPI object
+type VolumeMoveGetIterRequest struct {
+	XMLName              xml.Name                                   `xml:"volume-move-get-iter"`
+	DesiredAttributesPtr *VolumeMoveGetIterRequestDesiredAttributes `xml:"desired-attributes"`
+	ExpandPtr            *bool                                      `xml:"expand"`
+	MaxRecordsPtr        *int                                       `xml:"max-records"`
+	QueryPtr             *VolumeMoveGetIterRequestQuery             `xml:"query"`
+	TagPtr               *string                                    `xml:"tag"`
+}
+
+// VolumeMoveGetIterResponse is a structure to represent a volume-move-get-iter Response ZAPI object
+type VolumeMoveGetIterResponse struct {
+	XMLName         xml.Name                        `xml:"netapp"`
+	ResponseVersion string                          `xml:"version,attr"`
+	ResponseXmlns   string                          `xml:"xmlns,attr"`
+	Result          VolumeMoveGetIterResponseResult `xml:"results"`
+}
+
+// NewVolumeMoveGetIterResponse is a factory method for creating new instances of VolumeMoveGetIterResponse objects
+func NewVolumeMoveGetIterResponse() *VolumeMoveGetIterResponse {
+	return &VolumeMoveGetIterResponse{}
+}
+
+// String returns a string representation of this object's fields and implements the Stringer interface
+func (o VolumeMoveGetIterResponse) String() string {
+	return ToString(reflect.ValueOf(o))
+}
+
+// ToXML converts this object into an xml string representation
+func (o *VolumeMoveGetIterResponse) ToXML() (string, error) {
+	output, err := xml.MarshalIndent(o, " ", "    ")
+	if err != nil {
+		log.Errorf("error: %v", err)
+	}
+	return string(output), err
+}
+
+// VolumeMoveGetIterResponseResult is a structure to represent a volume-move-get-iter Response Result ZAPI object
+type VolumeMoveGetIterResponseResult struct {
+	XMLName           xml.Name                                       `xml:"results"`
+	ResultStatusAttr  string                                         `xml:"status,attr"`
+	ResultReasonAttr  string                                         `xml:"reason,attr"`
+	ResultErrnoAttr   string                                         `xml:"errno,attr"`
+	AttributesListPtr *VolumeMoveGetIterResponseResultAttributesList `xml:"attributes-list"`
+	NextTagPtr        *string                                        `xml:"next-tag"`
+	NumRecordsPtr     *int                                           `xml:"num-records"`
+}
+
+// NewVolumeMoveGetIterRequest is a factory method for creating new instances of VolumeMoveGetIterRequest objects
+func NewVolumeMoveGetIterRequest() *VolumeMoveGetIterRequest {
+	return &VolumeMoveGetIterRequest{}
+}
+
+// NewVolumeMoveGetIterResponseResult is a factory method for creating new instances of VolumeMoveGetIterResponseResult objects
+func NewVolumeMoveGetIterResponseResult() *VolumeMoveGetIterResponseResult {
+	return &VolumeMoveGetIterResponseResult{}
+}
+
+// ToXML converts this object into an xml string representation
+func (o *VolumeMoveGetIterRequest) ToXML() (string, error) {
+	output, err := xml.MarshalIndent(o, " ", "    ")
+	if err != nil {
+		log.Errorf("error: %v", err)
+	}
+	return string(output), err
+}
+
+// ToXML converts this object into an xml string representation
+func (o *VolumeMoveGetIterResponseResult) ToXML() (string, error) {
+	output, err := xml.MarshalIndent(o, " ", "    ")
+	if err != nil {
+		log.Errorf("error: %v", err)
+	}
+	return string(output), err
+}
+
+// String returns a string representation of this object's fields and implements the Stringer interface
+func (o VolumeMoveGetIterRequest) String() string {
+	return ToString(reflect.ValueOf(o))
+}
+
+// String returns a string representation of this object's fields and implements the Stringer interface
+func (o VolumeMoveGetIterResponseResult) String() string {
+	return ToString(reflect.ValueOf(o))
+}
+
+// ExecuteUsing converts this object to a ZAPI XML representation and uses the supplied ZapiRunner to send to a filer
+
+func (o *VolumeMoveGetIterRequest) ExecuteUsing(zr *ZapiRunner) (*VolumeMoveGetIterResponse, error) {
+	return o.executeWithIteration(zr)
+}
+
+// executeWithoutIteration converts this object to a ZAPI XML representation and uses the supplied ZapiRunner to send to a filer
+
+func (o *VolumeMoveGetIterRequest) executeWithoutIteration(zr *ZapiRunner) (*VolumeMoveGetIterResponse, error) {
+	result, err := zr.ExecuteUsing(o, "VolumeMoveGetIterRequest", NewVolumeMoveGetIterResponse())
+	if result == nil {
+		return nil, err
+	}
+	return result.(*VolumeMoveGetIterResponse), err
+}
+
+// executeWithIteration converts this object to a ZAPI XML representation and uses the supplied ZapiRunner to send to a filer
+func (o *VolumeMoveGetIterRequest) executeWithIteration(zr *ZapiRunner) (*VolumeMoveGetIterResponse, error) {
+	combined := NewVolumeMoveGetIterResponse()
+	combined.Result.SetAttributesList(VolumeMoveGetIterResponseResultAttributesList{})
+	var nextTagPtr *string
+	done := false
+	for done != true {
+		n, err := o.executeWithoutIteration(zr)
+
+		if err != nil {
+			return nil, err
+		}
+		nextTagPtr = n.Result.NextTagPtr
+		if nextTagPtr == nil {
+			done = true
+		} else {
+			o.SetTag(*nextTagPtr)
+		}
+
+		if n.Result.NumRecordsPtr == nil {
+			done = true
+		} else {
+			recordsRead := n.Result.NumRecords()
+			if recordsRead == 0 {
+				done = true
+			}
+		}
+
+		if n.Result.AttributesListPtr != nil {
+			if combined.Result.AttributesListPtr == nil {
+				combined.Result.SetAttributesList(VolumeMoveGetIterResponseResultAttributesList{})
+			}
+			combinedAttributesList := combined.Result.AttributesList()
+			combinedAttributes := combinedAttributesList.values()
+
+			resultAttributesList := n.Result.AttributesList()
+			resultAttributes := resultAttributesList.values()
+
+			combined.Result.AttributesListPtr.setValues(append(combinedAttributes, resultAttributes...))
+		}
+
+		if done == true {
+
+			combined.Result.ResultErrnoAttr = n.Result.ResultErrnoAttr
+			combined.Result.ResultReasonAttr = n.Result.ResultReasonAttr
+			combined.Result.ResultStatusAttr = n.Result.ResultStatusAttr
+
+			combinedAttributesList := combined.Result.AttributesList()
+			combinedAttributes := combinedAttributesList.values()
+			combined.Result.SetNumRecords(len(combinedAttributes))
+
+		}
+	}
+	return combined, nil
+}
+
+// VolumeMoveGetIterRequestDesiredAttributes is a wrapper
+type VolumeMoveGetIterRequestDesiredAttributes struct {
+	XMLName           xml.Name            `xml:"desired-attributes"`
+	VolumeMoveInfoPtr *VolumeMoveInfoType `xml:"volume-move-info"`
+}
+
+// String returns a string representation of this object's fields and implements the Stringer interface
+func (o VolumeMoveGetIterRequestDesiredAttributes) String() string {
+	return ToString(reflect.ValueOf(o))
+}
+
+// VolumeMoveInfo is a 'getter' method
+func (o *VolumeMoveGetIterRequestDesiredAttributes) VolumeMoveInfo() VolumeMoveInfoType {
+	r := *o.VolumeMoveInfoPtr
+	return r
+}
+
+// SetVolumeMoveInfo is a fluent style 'setter' method that can be chained
+func (o *VolumeMoveGetIterRequestDesiredAttributes) SetVolumeMoveInfo(newValue VolumeMoveInfoType) *VolumeMoveGetIterRequestDesiredAttributes {
+	o.VolumeMoveInfoPtr = &newValue
+	return o
+}
+
+// DesiredAttributes is a 'getter' method
+func (o *VolumeMoveGetIterRequest) DesiredAttributes() VolumeMoveGetIterRequestDesiredAttributes {
+	r := *o.DesiredAttributesPtr
+	return r
+}
+
+// SetDesiredAttributes is a fluent style 'setter' method that can be chained
+func (o *VolumeMoveGetIterRequest) SetDesiredAttributes(newValue VolumeMoveGetIterRequestDesiredAttributes) *VolumeMoveGetIterRequest {
+	o.DesiredAttributesPtr = &newValue
+	return o
+}
+
+// Expand is a 'getter' method
+func (o *VolumeMoveGetIterRequest) Expand() bool {
+	r := *o.ExpandPtr
+	return r
+}
+
+// SetExpand is a fluent style 'setter' method that can be chained
+func (o *VolumeMoveGetIterRequest) SetExpand(newValue bool) *VolumeMoveGetIterRequest {
+	o.ExpandPtr = &newValue
+	return o
+}
+
+// MaxRecords is a 'getter' method
+func (o *VolumeMoveGetIterRequest) MaxRecords() int {
+	r := *o.MaxRecordsPtr
+	return r
+}
+
+// SetMaxRecords is a fluent style 'setter' method that can be chained
+func (o *VolumeMoveGetIterRequest) SetMaxRecords(newValue int) *VolumeMoveGetIterRequest {
+	o.MaxRecordsPtr = &newValue
+	return o
+}
+
+// VolumeMoveGetIterRequestQuery is a wrapper
+type VolumeMoveGetIterRequestQuery struct {
+	XMLName           xml.Name            `xml:"query"`
+	VolumeMoveInfoPtr *VolumeMoveInfoType `xml:"volume-move-info"`
+}
+
+// String returns a string representation of this object's fields and implements the Stringer interface
+func (o VolumeMoveGetIterRequestQuery) String() string {
+	return ToString(reflect.ValueOf(o))
+}
+
+// VolumeMoveInfo is a 'getter' method
+func (o *VolumeMoveGetIterRequestQuery) VolumeMoveInfo() VolumeMoveInfoType {
+	r := *o.VolumeMoveInfoPtr
+	return r
+}
+
+// SetVolumeMoveInfo is a fluent style 'setter' method that can be chained
+func (o *VolumeMoveGetIterRequestQuery) SetVolumeMoveInfo(newValue VolumeMoveInfoType) *VolumeMoveGetIterRequestQuery {
+	o.VolumeMoveInfoPtr = &newValue
+	return o
+}
+
+// Query is a 'getter' method
+func (o *VolumeMoveGetIterRequest) Query() VolumeMoveGetIterRequestQuery {
+	r := *o.QueryPtr
+	return r
+}
+
+// SetQuery is a fluent style 'setter' method that can be chained
+func (o *VolumeMoveGetIterRequest) SetQuery(newValue VolumeMoveGetIterRequestQuery) *VolumeMoveGetIterRequest {
+	o.QueryPtr = &newValue
+	return o
+}
+
+// Tag is a 'getter' method
+func (o *VolumeMoveGetIterRequest) Tag() string {
+	r := *o.TagPtr
+	return r
+}
+
+// SetTag is a fluent style 'setter' method that can be chained
+func (o *VolumeMoveGetIterRequest) SetTag(newValue string) *VolumeMoveGetIterRequest {
+	o.TagPtr = &newValue
+	return o
+}
+
+// VolumeMoveGetIterResponseResultAttributesList is a wrapper
+type VolumeMoveGetIterResponseResultAttributesList struct {
+	XMLName           xml.Name             `xml:"attributes-list"`
+	VolumeMoveInfoPtr []VolumeMoveInfoType `xml:"volume-move-info"`
+}
+
+// String returns a string representation of this object's fields and implements the Stringer interface
+func (o VolumeMoveGetIterResponseResultAttributesList) String() string {
+	return ToString(reflect.ValueOf(o))
+}
+
+// VolumeMoveInfo is a 'getter' method
+func (o *VolumeMoveGetIterResponseResultAttributesList) VolumeMoveInfo() []VolumeMoveInfoType {
+	r := o.VolumeMoveInfoPtr
+	return r
+}
+
+// SetVolumeMoveInfo is a fluent style 'setter' method that can be chained
+func (o *VolumeMoveGetIterResponseResultAttributesList) SetVolumeMoveInfo(newValue []VolumeMoveInfoType) *VolumeMoveGetIterResponseResultAttributesList {
+	newSlice := make([]VolumeMoveInfoType, len(newValue))
+	copy(newSlice, newValue)
+	o.VolumeMoveInfoPtr = newSlice
+	return o
+}
+
+// values is a 'getter' method
+func (o *VolumeMoveGetIterResponseResultAttributesList) values() []VolumeMoveInfoType {
+	r := o.VolumeMoveInfoPtr
+	return r
+}
+
+// setValues is a fluent style 'setter' method that can be chained
+func (o *VolumeMoveGetIterResponseResultAttributesList) setValues(newValue []VolumeMoveInfoType) *VolumeMoveGetIterResponseResultAttributesList {
+	newSlice := make([]VolumeMoveInfoType, len(newValue))
+	copy(newSlice, newValue)
+	o.VolumeMoveInfoPtr = newSlice
+	return o
+}
+
+// AttributesList is a 'getter' method
+func (o *VolumeMoveGetIterResponseResult) AttributesList() VolumeMoveGetIterResponseResultAttributesList {
+	r := *o.AttributesListPtr
+	return r
+}
+
+// SetAttributesList is a fluent style 'setter' method that can be chained
+func (o *VolumeMoveGetIterResponseResult) SetAttributesList(newValue VolumeMoveGetIterResponseResultAttributesList) *VolumeMoveGetIterResponseResult {
+	o.AttributesListPtr = &newValue
+	return o
+}
+
+// NextTag is a 'getter' method
+func (o *VolumeMoveGetIterResponseResult) NextTag() string {
+	r := *o.NextTagPtr
+	return r
+}
+
+// SetNextTag is a fluent style 'setter' method that can be chained
+func (o *VolumeMoveGetIterResponseResult) SetNextTag(newValue string) *VolumeMoveGetIterResponseResult {
+	o.NextTagPtr = &newValue
+	return o
+}
+
+// NumRecords is a 'getter' method
+func (o *VolumeMoveGetIterResponseResult) NumRecords() int {
+	r := *o.NumRecordsPtr
+	return r
+}
+
+// SetNumRecords is a fluent style 'setter' method that can be chained
+func (o *VolumeMoveGetIterResponseResult) SetNumRecords(newValue int) *VolumeMoveGetIterResponseResult {
+	o.NumRecordsPtr = &newValue
+	return o
+}
@@ -0,0 +1,141 @@
+package azgo
+
+import (
+	"encoding/xml"
+	"reflect"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// VolumeMoveStartRequest is a structure to represent a volume-move-start Request ZAPI object
+type VolumeMoveStartRequest struct {
+	XMLName         xml.Name `xml:"volume-move-start"`
+	DestAggrPtr     *string  `xml:"dest-aggr"`
+	SourceVolumePtr *string  `xml:"source-volume"`
+	VserverPtr      *string  `xml:"vserver"`
+}
+
+// VolumeMoveStartResponse is a structure to represent a volume-move-start Response ZAPI object
+type VolumeMoveStartResponse struct {
+	XMLName         xml.Name                      `xml:"netapp"`
+	ResponseVersion string                        `xml:"version,attr"`
+	ResponseXmlns   string                        `xml:"xmlns,attr"`
+	Result          VolumeMoveStartResponseResult `xml:"results"`
+}
+
+// NewVolumeMoveStartResponse is a factory method for creating new instances of VolumeMoveStartResponse objects
+func NewVolumeMoveStartResponse() *VolumeMoveStartResponse {
+	return &VolumeMoveStartResponse{}
+}
+
+// String returns a string representation of this object's fields and implements the Stringer interface
+func (o VolumeMoveStartResponse) String() string {
+	return ToString(reflect.ValueOf(o))
+}
+
+// ToXML converts this object into an xml string representation
+func (o *VolumeMoveStartResponse) ToXML() (string, error) {
+	output, err := xml.MarshalIndent(o, " ", "    ")
+	if err != nil {
+		log.Errorf("error: %v", err)
+	}
+	return string(output), err
+}
+
+// VolumeMoveStartResponseResult is a structure to represent a volume-move-start Response Result ZAPI object
+type VolumeMoveStartResponseResult struct {
+	XMLName          xml.Name `xml:"results"`
+	ResultStatusAttr string   `xml:"status,attr"`
+	ResultReasonAttr string   `xml:"reason,attr"`
+	ResultErrnoAttr  string   `xml:"errno,attr"`
+}
+
+// NewVolumeMoveStartRequest is a factory method for creating new instances of VolumeMoveStartRequest objects
+func NewVolumeMoveStartRequest() *VolumeMoveStartRequest {
+	return &VolumeMoveStartRequest{}
+}
+
+// NewVolumeMoveStartResponseResult is a factory method for creating new instances of VolumeMoveStartResponseResult objects
+func NewVolumeMoveStartResponseResult() *VolumeMoveStartResponseResult {
+	return &VolumeMoveStartResponseResult{}
+}
+
+// ToXML converts this object into an xml string representation
+func (o *VolumeMoveStartRequest) ToXML() (string, error) {
+	output, err := xml.MarshalIndent(o, " ", "    ")
+	if err != nil {
+		log.Errorf("error: %v", err)
+	}
+	return string(output), err
+}
+
+// ToXML converts this object into an xml string representation
+func (o *VolumeMoveStartResponseResult) ToXML() (string, error) {
+	output, err := xml.MarshalIndent(o, " ", "    ")
+	if err != nil {
+		log.Errorf("error: %v", err)
+	}
+	return string(output), err
+}
+
+// String returns a string representation of this object's fields and implements the Stringer interface
+func (o VolumeMoveStartRequest) String() string {
+	return ToString(reflect.ValueOf(o))
+}
+
+// String returns a string representation of this object's fields and implements the Stringer interface
+func (o VolumeMoveStartResponseResult) String() string {
+	return ToString(reflect.ValueOf(o))
+}
+
+// ExecuteUsing converts this object to a ZAPI XML representation and uses the supplied ZapiRunner to send to a filer
+
+func (o *VolumeMoveStartRequest) ExecuteUsing(zr *ZapiRunner) (*VolumeMoveStartResponse, error) {
+	return o.executeWithoutIteration(zr)
+}
+
+// executeWithoutIteration converts this object to a ZAPI XML representation and uses the supplied ZapiRunner to send to a filer
+
+func (o *VolumeMoveStartRequest) executeWithoutIteration(zr *ZapiRunner) (*VolumeMoveStartResponse, error) {
+	result, err := zr.ExecuteUsing(o, "VolumeMoveStartRequest", NewVolumeMoveStartResponse())
+	if result == nil {
+		return nil, err
+	}
+	return result.(*VolumeMoveStartResponse), err
+}
+
+// DestAggr is a 'getter' method
+func (o *VolumeMoveStartRequest) DestAggr() string {
+	r := *o.DestAggrPtr
+	return r
+}
+
+// SetDestAggr is a fluent style 'setter' method that can be chained
+func (o *VolumeMoveStartRequest) SetDestAggr(newValue string) *VolumeMoveStartRequest {
+	o.DestAggrPtr = &newValue
+	return o
+}
+
+// SourceVolume is a 'getter' method
+func (o *VolumeMoveStartRequest) SourceVolume() string {
+	r := *o.SourceVolumePtr
+	return r
+}
+
+// SetSourceVolume is a fluent style 'setter' method that can be chained
+func (o *VolumeMoveStartRequest) SetSourceVolume(newValue string) *VolumeMoveStartRequest {
+	o.SourceVolumePtr = &newValue
+	return o
+}
+
+// Vserver is a 'getter' method
+func (o *VolumeMoveStartRequest) Vserver() string {
+	r := *o.VserverPtr
+	return r
+}
+
+// SetVserver is a fluent style 'setter' method that can be chained
+func (o *VolumeMoveStartRequest) SetVserver(newValue string) *VolumeMoveStartRequest {
+	o.VserverPtr = &newValue
+	return o
+}
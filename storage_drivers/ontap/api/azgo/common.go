@@ -4,6 +4,7 @@ package azgo
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"encoding/xml"
 	"errors"
@@ -14,8 +15,10 @@ import (
 	"strings"
 	"time"
 
-	tridentconfig "github.com/netapp/trident/config"
 	log "github.com/sirupsen/logrus"
+
+	tridentconfig "github.com/netapp/trident/config"
+	"github.com/netapp/trident/storage_drivers/apimetrics"
 )
 
 type ZAPIRequest interface {
@@ -34,6 +37,9 @@ type ZapiRunner struct {
 	Secure          bool
 	OntapiVersion   string
 	DebugTraceFlags map[string]bool // Example: {"api":false, "method":true}
+	Metrics         *apimetrics.Recorder
+	// Context, if set, cancels the requests sent by this runner when it is done
+	Context context.Context
 }
 
 // GetZAPIName returns the name of the ZAPI request; it must parse the XML because ZAPIRequest is an interface
@@ -58,6 +64,39 @@ func GetZAPIName(zr ZAPIRequest) (string, error) {
 	return "", fmt.Errorf("could not find start tag for ZAPI: %v", zapiXML)
 }
 
+// GetZAPIResult returns the metrics result label for a ZAPI response body, which is the
+// ONTAP errno if the API failed.
+func GetZAPIResult(body []byte) string {
+
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return "invalid_response"
+		}
+		startElement, ok := token.(xml.StartElement)
+		if !ok || startElement.Name.Local != "results" {
+			continue
+		}
+
+		status, errno := "", ""
+		for _, attr := range startElement.Attr {
+			switch attr.Name.Local {
+			case "status":
+				status = attr.Value
+			case "errno":
+				errno = attr.Value
+			}
+		}
+		if status == "passed" {
+			return apimetrics.ResultSuccess
+		} else if errno != "" {
+			return errno
+		}
+		return "failed"
+	}
+}
+
 // SendZapi sends the provided ZAPIRequest to the Ontap system
 func (o *ZapiRunner) SendZapi(r ZAPIRequest) (*http.Response, error) {
 
@@ -107,8 +146,16 @@ func (o *ZapiRunner) SendZapi(r ZAPIRequest) (*http.Response, error) {
 		log.Debugf("URL:> %s", url)
 	}
 
+	ctx := o.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
 	b := []byte(s)
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(b))
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(b))
+	if err != nil {
+		return nil, err
+	}
 	req.Header.Set("Content-Type", "application/xml")
 	req.SetBasicAuth(o.Username, o.Password)
 
@@ -149,17 +196,27 @@ func (o *ZapiRunner) ExecuteWithoutIteration(z ZAPIRequest, requestType string,
 		defer log.WithFields(fields).Debug("<<<< ExecuteUsing")
 	}
 
+	recordResult := func(string) {}
+	if o.Metrics != nil {
+		if zapiName, zapiNameErr := GetZAPIName(z); zapiNameErr == nil {
+			recordResult = o.Metrics.Start(zapiName)
+		}
+	}
+
 	resp, err := o.SendZapi(z)
 	if err != nil {
+		recordResult(apimetrics.ResultTransportError)
 		log.Errorf("API invocation failed. %v", err.Error())
 		return nil, err
 	}
 	defer resp.Body.Close()
 	body, readErr := ioutil.ReadAll(resp.Body)
 	if readErr != nil {
+		recordResult(apimetrics.ResultTransportError)
 		log.Errorf("Error reading response body. %v", readErr.Error())
 		return nil, readErr
 	}
+	recordResult(GetZAPIResult(body))
 	if o.DebugTraceFlags["api"] {
 		log.Debugf("response Body:\n%s", string(body))
 	}
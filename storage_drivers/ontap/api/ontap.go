@@ -4,6 +4,7 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"reflect"
@@ -16,6 +17,7 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	tridentconfig "github.com/netapp/trident/config"
+	"github.com/netapp/trident/storage_drivers/apimetrics"
 	"github.com/netapp/trident/storage_drivers/ontap/api/azgo"
 	"github.com/netapp/trident/utils"
 )
@@ -36,6 +38,10 @@ type ClientConfig struct {
 	DriverContext           tridentconfig.DriverContext
 	ContextBasedZapiRecords int
 	DebugTraceFlags         map[string]bool
+
+	// Identifies the backend in API metrics; the SVM is used if the backend is unnamed
+	BackendName       string
+	StorageDriverName string
 }
 
 // Client is the object to use for interacting with ONTAP controllers
@@ -55,6 +61,11 @@ func NewClient(config ClientConfig) *Client {
 		config.ContextBasedZapiRecords = maxZapiRecords
 	}
 
+	backendName := config.BackendName
+	if backendName == "" {
+		backendName = config.SVM
+	}
+
 	d := &Client{
 		config: config,
 		zr: &azgo.ZapiRunner{
@@ -64,12 +75,21 @@ func NewClient(config ClientConfig) *Client {
 			Password:        config.Password,
 			Secure:          true,
 			DebugTraceFlags: config.DebugTraceFlags,
+			Metrics:         apimetrics.NewRecorder(backendName, config.StorageDriverName),
 		},
 		m: &sync.Mutex{},
 	}
 	return d
 }
 
+// WithContext returns a copy of this client whose ZAPI calls are cancelled when the context is done.
+func (d Client) WithContext(ctx context.Context) *Client {
+	clone := d
+	clone.zr = d.GetClonedZapiRunner()
+	clone.zr.Context = ctx
+	return &clone
+}
+
 // GetClonedZapiRunner returns a clone of the ZapiRunner configured on this driver.
 func (d Client) GetClonedZapiRunner() *azgo.ZapiRunner {
 	clone := new(azgo.ZapiRunner)
@@ -448,6 +468,19 @@ func (d Client) LunMapAutoID(initiatorGroupName, lunPath string) (*azgo.LunMapRe
 }
 
 func (d Client) LunMapIfNotMapped(initiatorGroupName, lunPath string, importNotManaged bool) (int, error) {
+	return d.lunMapIfNotMapped(initiatorGroupName, lunPath, importNotManaged, false)
+}
+
+// LunMapSharedIfNotMapped maps a LUN to an igroup unless it already is, leaving the LUN's maps to other
+// igroups in place so that the initiators of several igroups may share the LUN.  It returns the LUN's ID
+// in the igroup.
+func (d Client) LunMapSharedIfNotMapped(initiatorGroupName, lunPath string) (int, error) {
+	return d.lunMapIfNotMapped(initiatorGroupName, lunPath, false, true)
+}
+
+func (d Client) lunMapIfNotMapped(
+	initiatorGroupName, lunPath string, importNotManaged, keepOtherMaps bool,
+) (int, error) {
 
 	// Read LUN maps to see if the LUN is already mapped to the igroup
 	lunMapListResponse, err := d.LunMapListInfo(lunPath)
@@ -461,7 +494,7 @@ func (d Client) LunMapIfNotMapped(initiatorGroupName, lunPath string, importNotM
 	alreadyMapped := false
 	if lunMapListResponse.Result.InitiatorGroupsPtr != nil {
 		for _, igroup := range lunMapListResponse.Result.InitiatorGroupsPtr.InitiatorGroupInfoPtr {
-			if igroup.InitiatorGroupName() != initiatorGroupName && !importNotManaged {
+			if igroup.InitiatorGroupName() != initiatorGroupName && !importNotManaged && !keepOtherMaps {
 				log.Debugf("deleting existing LUN mapping")
 				lunUnmapResponse, err := d.LunUnmap(igroup.InitiatorGroupName(), lunPath)
 				if err != nil {
@@ -994,6 +1027,7 @@ func (d *Client) checkForJobCompletion(jobId int, maxWaitTime time.Duration) err
 	}
 
 	jobCompletedNotify := func(err error, duration time.Duration) {
+		d.zr.Metrics.RecordRetry("job-get-iter")
 		log.WithField("duration", duration).
 			Debug("Job not yet completed, waiting.")
 	}
@@ -1091,6 +1125,19 @@ func (d Client) VolumeCreate(
 	return response, err
 }
 
+// VolumeCreateDP creates a data protection volume to serve as the destination of a SnapMirror relationship
+// equivalent to filer::> volume create -vserver iscsi_vs -volume v -aggregate aggr1 -size 1g -type DP
+func (d Client) VolumeCreateDP(name, aggregateName, size string) (*azgo.VolumeCreateResponse, error) {
+	response, err := azgo.NewVolumeCreateRequest().
+		SetVolume(name).
+		SetContainingAggrName(aggregateName).
+		SetSize(size).
+		SetSpaceReserve("none").
+		SetVolumeType("dp").
+		ExecuteUsing(d.zr)
+	return response, err
+}
+
 func (d Client) VolumeModifyExportPolicy(volumeName, exportPolicyName string) (*azgo.VolumeModifyIterResponse, error) {
 	volAttr := &azgo.VolumeModifyIterRequestAttributes{}
 	exportAttributes := azgo.NewVolumeExportAttributesType().SetPolicy(exportPolicyName)
@@ -1109,6 +1156,27 @@ func (d Client) VolumeModifyExportPolicy(volumeName, exportPolicyName string) (*
 	return response, err
 }
 
+// VolumeModifyUnixOwner sets the UNIX user and group that own a volume's root directory
+// equivalent to filer::> volume modify -vserver iscsi_vs -volume v -user 0 -group 2000
+func (d Client) VolumeModifyUnixOwner(volumeName string, uid, gid int) (*azgo.VolumeModifyIterResponse, error) {
+	volAttr := &azgo.VolumeModifyIterRequestAttributes{}
+	unixAttributes := azgo.NewVolumeSecurityUnixAttributesType().SetUserId(uid).SetGroupId(gid)
+	securityAttributes := azgo.NewVolumeSecurityAttributesType().SetVolumeSecurityUnixAttributes(*unixAttributes)
+	volSecurityAttrs := azgo.NewVolumeAttributesType().SetVolumeSecurityAttributes(*securityAttributes)
+	volAttr.SetVolumeAttributes(*volSecurityAttrs)
+
+	queryAttr := &azgo.VolumeModifyIterRequestQuery{}
+	volIDAttr := azgo.NewVolumeIdAttributesType().SetName(volumeName)
+	volIDAttrs := azgo.NewVolumeAttributesType().SetVolumeIdAttributes(*volIDAttr)
+	queryAttr.SetVolumeAttributes(*volIDAttrs)
+
+	response, err := azgo.NewVolumeModifyIterRequest().
+		SetQuery(*queryAttr).
+		SetAttributes(*volAttr).
+		ExecuteUsing(d.zr)
+	return response, err
+}
+
 // VolumeCloneCreate clones a volume from a snapshot
 func (d Client) VolumeCloneCreate(name, source, snapshot string) (*azgo.VolumeCloneCreateResponse, error) {
 	response, err := azgo.NewVolumeCloneCreateRequest().
@@ -1266,6 +1334,60 @@ func (d Client) volumeGetIterCommon(name string,
 	return &azgo.VolumeAttributesType{}, fmt.Errorf("flexvol %s not found", name)
 }
 
+// VolumeGetState returns the state of a Flexvol, such as online, offline or restricted.  Unlike VolumeGet,
+// it finds Flexvols that aren't online.
+// equivalent to filer::> volume show -fields state
+func (d Client) VolumeGetState(name string) (string, error) {
+	return d.volumeGetStateCommon(name, "flexvol")
+}
+
+// FlexGroupGetState returns the state of a FlexGroup, such as online, offline or restricted.  Unlike
+// FlexGroupGet, it finds FlexGroups that aren't online.
+func (d Client) FlexGroupGetState(name string) (string, error) {
+	return d.volumeGetStateCommon(name, "flexgroup")
+}
+
+func (d Client) volumeGetStateCommon(name, style string) (string, error) {
+
+	// Limit the volumes to the one matching the name, in any state
+	queryVolIDAttrs := azgo.NewVolumeIdAttributesType().
+		SetName(azgo.VolumeNameType(name)).
+		SetStyleExtended(style)
+	query := &azgo.VolumeGetIterRequestQuery{}
+	volAttrs := azgo.NewVolumeAttributesType().
+		SetVolumeIdAttributes(*queryVolIDAttrs)
+	query.SetVolumeAttributes(*volAttrs)
+
+	// Limit the returned data to the state
+	desiredVolStateAttrs := azgo.NewVolumeStateAttributesType().
+		SetState("")
+	desiredAttributes := &azgo.VolumeGetIterRequestDesiredAttributes{}
+	desiredVolumeAttributes := azgo.NewVolumeAttributesType().
+		SetVolumeStateAttributes(*desiredVolStateAttrs)
+	desiredAttributes.SetVolumeAttributes(*desiredVolumeAttributes)
+
+	response, err := azgo.NewVolumeGetIterRequest().
+		SetMaxRecords(d.config.ContextBasedZapiRecords).
+		SetQuery(*query).
+		SetDesiredAttributes(*desiredAttributes).
+		ExecuteUsing(d.zr)
+
+	if err != nil {
+		return "", err
+	} else if response.Result.NumRecords() == 0 || response.Result.AttributesListPtr == nil ||
+		len(response.Result.AttributesListPtr.VolumeAttributesPtr) == 0 {
+		return "", fmt.Errorf("%s %s not found", style, name)
+	} else if response.Result.NumRecords() > 1 {
+		return "", fmt.Errorf("more than one %s %s found", style, name)
+	}
+
+	volStateAttrs := response.Result.AttributesListPtr.VolumeAttributesPtr[0].VolumeStateAttributesPtr
+	if volStateAttrs == nil || volStateAttrs.StatePtr == nil {
+		return "", fmt.Errorf("state of %s %s not found", style, name)
+	}
+	return volStateAttrs.State(), nil
+}
+
 // VolumeGetAll returns all relevant details for all FlexVols whose names match the supplied prefix
 // equivalent to filer::> volume show
 func (d Client) VolumeGetAll(prefix string) (response *azgo.VolumeGetIterResponse, err error) {
@@ -1444,6 +1566,39 @@ func (d Client) VolumeRename(volumeName, newVolumeName string) (*azgo.VolumeRena
 	return response, err
 }
 
+// VolumeMoveStart starts moving a FlexVol to another aggregate without disrupting its clients
+// equivalent to filer::> volume move start -vserver iscsi_vs -volume v -destination-aggregate aggr2
+func (d Client) VolumeMoveStart(name, aggregateName string) (*azgo.VolumeMoveStartResponse, error) {
+	response, err := azgo.NewVolumeMoveStartRequest().
+		SetVserver(d.config.SVM).
+		SetSourceVolume(name).
+		SetDestAggr(aggregateName).
+		ExecuteUsing(d.zr)
+	return response, err
+}
+
+// VolumeMoveGet returns the most recent move of a FlexVol in this SVM
+// equivalent to filer::> volume move show -vserver iscsi_vs -volume v
+func (d Client) VolumeMoveGet(name string) (*azgo.VolumeMoveInfoType, error) {
+	query := &azgo.VolumeMoveGetIterRequestQuery{}
+	query.SetVolumeMoveInfo(*azgo.NewVolumeMoveInfoType().SetVserver(d.config.SVM).SetVolume(name))
+
+	response, err := azgo.NewVolumeMoveGetIterRequest().
+		SetMaxRecords(defaultZapiRecords).
+		SetQuery(*query).
+		ExecuteUsing(d.zr)
+	if err = GetError(response, err); err != nil {
+		return nil, err
+	}
+
+	if response.Result.AttributesListPtr == nil || len(response.Result.AttributesListPtr.VolumeMoveInfoPtr) == 0 {
+		return nil, fmt.Errorf("move of volume %s not found", name)
+	}
+
+	moves := response.Result.AttributesListPtr.VolumeMoveInfoPtr
+	return &moves[len(moves)-1], nil
+}
+
 // VOLUME operations END
 /////////////////////////////////////////////////////////////////////////////
 
@@ -1852,6 +2007,29 @@ func (d Client) ExportRuleDestroy(policy string, ruleIndex int) (*azgo.ExportRul
 // EXPORT POLICY operations END
 /////////////////////////////////////////////////////////////////////////////
 
+/////////////////////////////////////////////////////////////////////////////
+// CIFS operations BEGIN
+
+// CifsShareCreate shares a junction path over SMB
+// equivalent to filer::> vserver cifs share create -share-name <name> -path <path>
+func (d Client) CifsShareCreate(shareName, path string) (*azgo.CifsShareCreateResponse, error) {
+	return azgo.NewCifsShareCreateRequest().
+		SetShareName(shareName).
+		SetPath(path).
+		ExecuteUsing(d.zr)
+}
+
+// CifsShareDelete stops sharing a junction path over SMB
+// equivalent to filer::> vserver cifs share delete -share-name <name>
+func (d Client) CifsShareDelete(shareName string) (*azgo.CifsShareDeleteResponse, error) {
+	return azgo.NewCifsShareDeleteRequest().
+		SetShareName(shareName).
+		ExecuteUsing(d.zr)
+}
+
+// CIFS operations END
+/////////////////////////////////////////////////////////////////////////////
+
 /////////////////////////////////////////////////////////////////////////////
 // SNAPSHOT operations BEGIN
 
@@ -1864,6 +2042,24 @@ func (d Client) SnapshotCreate(snapshotName, volumeName string) (*azgo.SnapshotC
 	return response, err
 }
 
+// SnapshotCreateWithLabel creates a snapshot of a volume with a SnapMirror label and a comment, either of
+// which may be empty
+func (d Client) SnapshotCreateWithLabel(
+	snapshotName, volumeName, snapmirrorLabel, comment string,
+) (*azgo.SnapshotCreateResponse, error) {
+	request := azgo.NewSnapshotCreateRequest().
+		SetSnapshot(snapshotName).
+		SetVolume(volumeName)
+	if snapmirrorLabel != "" {
+		request.SetSnapmirrorLabel(snapmirrorLabel)
+	}
+	if comment != "" {
+		request.SetComment(comment)
+	}
+	response, err := request.ExecuteUsing(d.zr)
+	return response, err
+}
+
 // SnapshotList returns the list of snapshots associated with a volume
 func (d Client) SnapshotList(volumeName string) (*azgo.SnapshotGetIterResponse, error) {
 	query := &azgo.SnapshotGetIterRequestQuery{}
@@ -1897,6 +2093,34 @@ func (d Client) SnapshotDelete(snapshotName, volumeName string) (*azgo.SnapshotD
 	return response, err
 }
 
+// ConsistencyGroupSnapshot creates a snapshot of a set of volumes at the same point in time.  Write
+// operations to the volumes are fenced between the start and commit of the consistency group.
+func (d Client) ConsistencyGroupSnapshot(snapshotName string, volumeNames []string) error {
+
+	volumes := azgo.CgStartRequestVolumes{}
+	volumes.SetVolumeName(volumeNames)
+
+	startResponse, err := azgo.NewCgStartRequest().
+		SetSnapshot(snapshotName).
+		SetTimeout("relaxed").
+		SetVolumes(volumes).
+		ExecuteUsing(d.zr)
+	if err = GetError(startResponse, err); err != nil {
+		return fmt.Errorf("error starting consistency group snapshot: %v", err)
+	}
+	if startResponse.Result.CgIdPtr == nil {
+		return fmt.Errorf("consistency group snapshot start did not return an ID")
+	}
+
+	commitResponse, err := azgo.NewCgCommitRequest().
+		SetCgId(startResponse.Result.CgId()).
+		ExecuteUsing(d.zr)
+	if err = GetError(commitResponse, err); err != nil {
+		return fmt.Errorf("error committing consistency group snapshot: %v", err)
+	}
+	return nil
+}
+
 // SNAPSHOT operations END
 /////////////////////////////////////////////////////////////////////////////
 
@@ -2210,6 +2434,105 @@ func (d Client) SnapmirrorGetDestinationIterRequest(relGroupType string) (*azgo.
 	return response, err
 }
 
+// SnapmirrorLocation returns the location of a volume as used by the SnapMirror APIs
+func SnapmirrorLocation(svmName, volumeName string) string {
+	return fmt.Sprintf("%s:%s", svmName, volumeName)
+}
+
+// SnapmirrorCreate creates a SnapMirror relationship to a volume in this SVM
+// equivalent to filer::> snapmirror create -source-path svm1:vol1 -destination-path svm2:vol2 -type XDP -policy MirrorAllSnapshots
+func (d Client) SnapmirrorCreate(
+	sourceSVMName, sourceVolumeName, destinationVolumeName, policy string,
+) (*azgo.SnapmirrorCreateResponse, error) {
+	request := azgo.NewSnapmirrorCreateRequest().
+		SetSourceLocation(SnapmirrorLocation(sourceSVMName, sourceVolumeName)).
+		SetDestinationLocation(SnapmirrorLocation(d.config.SVM, destinationVolumeName)).
+		SetRelationshipType("extended_data_protection")
+
+	if policy != "" {
+		request.SetPolicy(policy)
+	}
+
+	response, err := request.ExecuteUsing(d.zr)
+	return response, err
+}
+
+// SnapmirrorInitialize starts the baseline transfer of a SnapMirror relationship to a volume in this SVM
+// equivalent to filer::> snapmirror initialize -destination-path svm2:vol2
+func (d Client) SnapmirrorInitialize(
+	sourceSVMName, sourceVolumeName, destinationVolumeName string,
+) (*azgo.SnapmirrorInitializeResponse, error) {
+	response, err := azgo.NewSnapmirrorInitializeRequest().
+		SetSourceLocation(SnapmirrorLocation(sourceSVMName, sourceVolumeName)).
+		SetDestinationLocation(SnapmirrorLocation(d.config.SVM, destinationVolumeName)).
+		ExecuteUsing(d.zr)
+	return response, err
+}
+
+// SnapmirrorUpdate starts an incremental transfer of a SnapMirror relationship to a volume in this SVM
+// equivalent to filer::> snapmirror update -destination-path svm2:vol2
+func (d Client) SnapmirrorUpdate(destinationVolumeName string) (*azgo.SnapmirrorUpdateResponse, error) {
+	response, err := azgo.NewSnapmirrorUpdateRequest().
+		SetDestinationLocation(SnapmirrorLocation(d.config.SVM, destinationVolumeName)).
+		ExecuteUsing(d.zr)
+	return response, err
+}
+
+// SnapmirrorGet returns the SnapMirror relationship to a volume in this SVM
+// equivalent to filer::> snapmirror show -destination-path svm2:vol2
+func (d Client) SnapmirrorGet(destinationVolumeName string) (*azgo.SnapmirrorInfoType, error) {
+	response, err := azgo.NewSnapmirrorGetRequest().
+		SetDestinationLocation(SnapmirrorLocation(d.config.SVM, destinationVolumeName)).
+		ExecuteUsing(d.zr)
+	if err = GetError(response, err); err != nil {
+		return nil, err
+	}
+
+	if response.Result.AttributesPtr == nil || response.Result.AttributesPtr.SnapmirrorInfoPtr == nil {
+		return nil, fmt.Errorf("SnapMirror relationship to volume %s not found", destinationVolumeName)
+	}
+
+	return response.Result.AttributesPtr.SnapmirrorInfoPtr, nil
+}
+
+// SnapmirrorQuiesce disables future transfers of a SnapMirror relationship to a volume in this SVM
+// equivalent to filer::> snapmirror quiesce -destination-path svm2:vol2
+func (d Client) SnapmirrorQuiesce(destinationVolumeName string) (*azgo.SnapmirrorQuiesceResponse, error) {
+	response, err := azgo.NewSnapmirrorQuiesceRequest().
+		SetDestinationLocation(SnapmirrorLocation(d.config.SVM, destinationVolumeName)).
+		ExecuteUsing(d.zr)
+	return response, err
+}
+
+// SnapmirrorBreak makes the destination volume of a SnapMirror relationship in this SVM writable
+// equivalent to filer::> snapmirror break -destination-path svm2:vol2
+func (d Client) SnapmirrorBreak(destinationVolumeName string) (*azgo.SnapmirrorBreakResponse, error) {
+	response, err := azgo.NewSnapmirrorBreakRequest().
+		SetDestinationLocation(SnapmirrorLocation(d.config.SVM, destinationVolumeName)).
+		ExecuteUsing(d.zr)
+	return response, err
+}
+
+// SnapmirrorDelete removes a SnapMirror relationship to a volume in this SVM
+// equivalent to filer::> snapmirror delete -destination-path svm2:vol2
+func (d Client) SnapmirrorDelete(destinationVolumeName string) (*azgo.SnapmirrorDeleteResponse, error) {
+	response, err := azgo.NewSnapmirrorDeleteRequest().
+		SetDestinationLocation(SnapmirrorLocation(d.config.SVM, destinationVolumeName)).
+		ExecuteUsing(d.zr)
+	return response, err
+}
+
+// SnapmirrorRelease removes the source information of a SnapMirror relationship from a volume in this SVM
+// equivalent to filer::> snapmirror release -destination-path svm2:vol2
+func (d Client) SnapmirrorRelease(
+	destinationSVMName, destinationVolumeName string,
+) (*azgo.SnapmirrorReleaseResponse, error) {
+	response, err := azgo.NewSnapmirrorReleaseRequest().
+		SetDestinationLocation(SnapmirrorLocation(destinationSVMName, destinationVolumeName)).
+		ExecuteUsing(d.zr)
+	return response, err
+}
+
 // IsVserverDRDestination identifies if the Vserver is a destination vserver of Snapmirror relationship (SVM-DR) or not
 func (d Client) IsVserverDRDestination() (bool, error) {
 
@@ -3,10 +3,17 @@
 package api
 
 import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
-	"github.com/netapp/trident/storage_drivers/ontap/api/azgo"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
+
+	"github.com/netapp/trident/storage_drivers/ontap/api/azgo"
 )
 
 func TestGetError(t *testing.T) {
@@ -18,3 +25,68 @@ func TestGetError(t *testing.T) {
 
 	assert.Equal(t, "unexpected nil ZAPI result", e.(ZapiError).Reason(), "Strings not equal")
 }
+
+func TestGetZAPIResult(t *testing.T) {
+
+	passed := `<netapp version="1.21" xmlns="http://www.netapp.com/filer/admin">
+		<results status="passed"><num-records>0</num-records></results></netapp>`
+	assert.Equal(t, "success", azgo.GetZAPIResult([]byte(passed)))
+
+	failed := `<netapp version="1.21" xmlns="http://www.netapp.com/filer/admin">
+		<results status="failed" errno="15661" reason="entry doesn't exist"></results></netapp>`
+	assert.Equal(t, "15661", azgo.GetZAPIResult([]byte(failed)))
+
+	assert.Equal(t, "invalid_response", azgo.GetZAPIResult([]byte("<html>")))
+}
+
+// retriesRecorded returns the number of API retries recorded for a backend and operation.
+func retriesRecorded(t *testing.T, backend, operation string) float64 {
+
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, family := range families {
+		if family.GetName() != "trident_storage_api_retries_total" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := make(map[string]string)
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			if labels["backend"] == backend && labels["op"] == operation {
+				return metric.GetCounter().GetValue()
+			}
+		}
+	}
+	return 0
+}
+
+func TestCheckForJobCompletionRecordsRetries(t *testing.T) {
+
+	// The job is running when first polled, and done when polled again
+	polls := 0
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		state := "running"
+		if polls > 1 {
+			state = "success"
+		}
+		_, _ = fmt.Fprintf(w, `<netapp version="1.21" xmlns="http://www.netapp.com/filer/admin">
+			<results status="passed"><attributes-list><job-info><job-id>7</job-id>
+			<job-state>%s</job-state></job-info></attributes-list><num-records>1</num-records></results></netapp>`,
+			state)
+	}))
+	defer server.Close()
+
+	client := NewClient(ClientConfig{
+		ManagementLIF:     strings.TrimPrefix(server.URL, "https://"),
+		BackendName:       "jobRetryBackend",
+		StorageDriverName: "ontap-nas",
+	})
+
+	assert.NoError(t, client.checkForJobCompletion(7, 10*time.Second))
+	assert.Equal(t, 2, polls)
+	assert.Equal(t, float64(1), retriesRecorded(t, "jobRetryBackend", "job-get-iter"))
+}
@@ -0,0 +1,185 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+// This package provides a minimal interface to the AWS APIs needed to run the ONTAP drivers
+// against Amazon FSx for NetApp ONTAP (FSxN), namely the FSx and Secrets Manager services.
+package awsapi
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netapp/trident/utils"
+)
+
+const (
+	httpTimeoutSeconds = 30
+
+	ServiceFSx            = "fsx"
+	ServiceSecretsManager = "secretsmanager"
+
+	fsxTargetPrefix            = "AWSSimbaAPIService_v20180301."
+	secretsManagerTargetPrefix = "secretsmanager."
+
+	envAccessKeyID     = "AWS_ACCESS_KEY_ID"
+	envSecretAccessKey = "AWS_SECRET_ACCESS_KEY"
+	envSessionToken    = "AWS_SESSION_TOKEN"
+	envRegion          = "AWS_REGION"
+	envDefaultRegion   = "AWS_DEFAULT_REGION"
+)
+
+// ClientConfig holds configuration data for the API client object.
+type ClientConfig struct {
+
+	// AWS API authentication parameters.  If the keys are not specified, the static credentials in the
+	// AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN environment variables are used.
+	// Web identity tokens and instance metadata credentials are not supported.
+	APIRegion    string
+	APIKey       string
+	SecretKey    string
+	SessionToken string
+
+	// EndpointURL overrides the regional service endpoint, and is mainly useful for testing
+	EndpointURL string
+
+	// Options
+	DebugTraceFlags map[string]bool
+}
+
+type Client struct {
+	config *ClientConfig
+	now    func() time.Time
+}
+
+// NewClient is a factory method for creating a new instance.
+func NewClient(config ClientConfig) (*Client, error) {
+
+	if config.APIRegion == "" {
+		config.APIRegion = os.Getenv(envRegion)
+	}
+	if config.APIRegion == "" {
+		config.APIRegion = os.Getenv(envDefaultRegion)
+	}
+	if config.APIRegion == "" {
+		return nil, errors.New("AWS API region must be specified")
+	}
+
+	if config.APIKey == "" && config.SecretKey == "" {
+		config.APIKey = os.Getenv(envAccessKeyID)
+		config.SecretKey = os.Getenv(envSecretAccessKey)
+		config.SessionToken = os.Getenv(envSessionToken)
+	}
+	if config.APIKey == "" || config.SecretKey == "" {
+		return nil, errors.New("AWS API credentials must be specified in the config or the environment")
+	}
+
+	return &Client{
+		config: &config,
+		now:    time.Now,
+	}, nil
+}
+
+// Error is returned when an AWS API call completes with a non-success HTTP status.
+type Error struct {
+	StatusCode int
+	Type       string `json:"__type"`
+	Message    string `json:"message"`
+}
+
+func (e Error) Error() string {
+	errorType := e.Type
+	if i := strings.LastIndex(errorType, "#"); i >= 0 {
+		errorType = errorType[i+1:]
+	}
+	return fmt.Sprintf("AWS API failed (%d). Type: %s. Message: %s", e.StatusCode, errorType, e.Message)
+}
+
+// IsNotFoundError returns true if the error indicates the requested AWS resource does not exist.
+func IsNotFoundError(err error) bool {
+	if awsErr, ok := err.(Error); ok {
+		return strings.HasSuffix(awsErr.Type, "NotFound") || strings.HasSuffix(awsErr.Type, "NotFoundException")
+	}
+	return false
+}
+
+func (c *Client) endpointURL(service string) string {
+	if c.config.EndpointURL != "" {
+		return c.config.EndpointURL
+	}
+	return fmt.Sprintf("https://%s.%s.amazonaws.com/", service, c.config.APIRegion)
+}
+
+// invokeAPI makes a signed call to an AWS service that uses the JSON 1.1 protocol, which includes
+// both FSx and Secrets Manager.  The request is marshaled to JSON and the response unmarshaled into result.
+func (c *Client) invokeAPI(service, target string, request, result interface{}) error {
+
+	requestBody, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("could not marshal %s request; %v", target, err)
+	}
+
+	httpRequest, err := http.NewRequest("POST", c.endpointURL(service), bytes.NewBuffer(requestBody))
+	if err != nil {
+		return err
+	}
+
+	httpRequest.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	httpRequest.Header.Set("X-Amz-Target", target)
+
+	credentials := utils.AWSCredentials{
+		AccessKeyID:     c.config.APIKey,
+		SecretAccessKey: c.config.SecretKey,
+		SessionToken:    c.config.SessionToken,
+	}
+	utils.SignAWSRequest(httpRequest, requestBody, credentials, c.config.APIRegion, service, c.now())
+
+	if c.config.DebugTraceFlags["api"] {
+		utils.LogHTTPRequest(httpRequest, requestBody)
+	}
+
+	client := &http.Client{
+		Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: false}},
+		Timeout:   httpTimeoutSeconds * time.Second,
+	}
+
+	response, err := client.Do(httpRequest)
+	if err != nil {
+		log.Warnf("Error communicating with AWS %s API. %v", service, err)
+		return err
+	}
+	defer func() { _ = response.Body.Close() }()
+
+	responseBody, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+
+	if c.config.DebugTraceFlags["api"] && service != ServiceSecretsManager {
+		utils.LogHTTPResponse(response, responseBody)
+	}
+
+	if response.StatusCode >= 300 {
+		apiError := Error{StatusCode: response.StatusCode}
+		if jsonErr := json.Unmarshal(responseBody, &apiError); jsonErr != nil {
+			apiError.Message = string(responseBody)
+		}
+		return apiError
+	}
+
+	if result != nil {
+		if err = json.Unmarshal(responseBody, result); err != nil {
+			return fmt.Errorf("could not parse %s response; %v", target, err)
+		}
+	}
+
+	return nil
+}
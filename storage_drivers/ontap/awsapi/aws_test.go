@@ -0,0 +1,103 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package awsapi
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netapp/trident/utils"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) (*Client, *httptest.Server) {
+
+	server := httptest.NewServer(handler)
+
+	client, err := NewClient(ClientConfig{
+		APIRegion:   "us-east-1",
+		APIKey:      "key",
+		SecretKey:   "secret",
+		EndpointURL: server.URL + "/",
+	})
+	if err != nil {
+		t.Fatalf("Could not create client; %v", err)
+	}
+
+	return client, server
+}
+
+func TestGetSVMDiscovery(t *testing.T) {
+
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.True(t, strings.HasPrefix(r.Header.Get("Authorization"), utils.SigV4Algorithm))
+		assert.Equal(t, "AWSSimbaAPIService_v20180301.DescribeStorageVirtualMachines", r.Header.Get("X-Amz-Target"))
+
+		body, _ := ioutil.ReadAll(r.Body)
+		request := describeStorageVirtualMachinesRequest{}
+		_ = json.Unmarshal(body, &request)
+
+		response := describeStorageVirtualMachinesResponse{}
+		if request.NextToken == "" {
+			response.StorageVirtualMachines = []StorageVirtualMachine{{Name: "svm1"}}
+			response.NextToken = "page2"
+		} else {
+			response.StorageVirtualMachines = []StorageVirtualMachine{{
+				Name: "svm2",
+				Endpoints: &SVMEndpoints{
+					Management: &Endpoint{IPAddresses: []string{"10.0.0.5"}},
+				},
+			}}
+		}
+		responseBody, _ := json.Marshal(response)
+		_, _ = w.Write(responseBody)
+	})
+	defer server.Close()
+
+	svms, err := client.GetSVMs("fs-1234")
+	assert.NoError(t, err)
+	assert.Len(t, svms, 2)
+
+	svm, err := client.GetSVM("fs-1234", "svm2")
+	assert.NoError(t, err)
+	assert.Equal(t, "10.0.0.5", svm.Endpoints.Management.IPAddresses[0])
+
+	_, err = client.GetSVM("fs-1234", "")
+	assert.Error(t, err, "expected an error when the SVM cannot be derived")
+
+	_, err = client.GetSVM("fs-1234", "missing")
+	assert.Error(t, err)
+}
+
+func TestGetSecret(t *testing.T) {
+
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secretsmanager.GetSecretValue", r.Header.Get("X-Amz-Target"))
+		_, _ = w.Write([]byte(`{"ARN": "arn", "SecretString": "{\"username\":\"vsadmin\",\"password\":\"pw\"}"}`))
+	})
+	defer server.Close()
+
+	secret, err := client.GetSecret("arn")
+	assert.NoError(t, err)
+	assert.Equal(t, "vsadmin", secret["username"])
+	assert.Equal(t, "pw", secret["password"])
+}
+
+func TestAPIError(t *testing.T) {
+
+	client, server := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"__type": "com.amazonaws#FileSystemNotFound", "message": "not found"}`))
+	})
+	defer server.Close()
+
+	_, err := client.GetFileSystem("fs-1234")
+	assert.Error(t, err)
+	assert.True(t, IsNotFoundError(err))
+	assert.Contains(t, err.Error(), "FileSystemNotFound")
+}
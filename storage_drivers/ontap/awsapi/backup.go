@@ -0,0 +1,198 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package awsapi
+
+import (
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	BackupLifecycleAvailable    = "AVAILABLE"
+	BackupLifecycleCreating     = "CREATING"
+	BackupLifecycleTransferring = "TRANSFERRING"
+	BackupLifecyclePending      = "PENDING"
+	BackupLifecycleDeleted      = "DELETED"
+	BackupLifecycleFailed       = "FAILED"
+)
+
+type Tag struct {
+	Key   string `json:"Key"`
+	Value string `json:"Value"`
+}
+
+type OntapVolumeConfiguration struct {
+	JunctionPath            string `json:"JunctionPath"`
+	SizeInMegabytes         int64  `json:"SizeInMegabytes"`
+	StorageVirtualMachineID string `json:"StorageVirtualMachineId"`
+	UUID                    string `json:"UUID"`
+}
+
+// Volume is the subset of an FSx volume description needed by Trident.
+type Volume struct {
+	VolumeID           string                    `json:"VolumeId"`
+	FileSystemID       string                    `json:"FileSystemId"`
+	Name               string                    `json:"Name"`
+	Lifecycle          string                    `json:"Lifecycle"`
+	OntapConfiguration *OntapVolumeConfiguration `json:"OntapConfiguration,omitempty"`
+}
+
+type BackupFailureDetails struct {
+	Message string `json:"Message"`
+}
+
+// Backup is the subset of an FSx backup description needed by Trident.
+type Backup struct {
+	BackupID       string                `json:"BackupId"`
+	Lifecycle      string                `json:"Lifecycle"`
+	Type           string                `json:"Type"`
+	CreationTime   float64               `json:"CreationTime"`
+	Tags           []Tag                 `json:"Tags"`
+	FailureDetails *BackupFailureDetails `json:"FailureDetails,omitempty"`
+}
+
+// Created returns the backup's creation time.
+func (b *Backup) Created() time.Time {
+	return time.Unix(int64(b.CreationTime), 0).UTC()
+}
+
+// Tag returns the value of the named tag, or an empty string if the backup does not have the tag.
+func (b *Backup) Tag(key string) string {
+	for _, tag := range b.Tags {
+		if tag.Key == key {
+			return tag.Value
+		}
+	}
+	return ""
+}
+
+type describeVolumesRequest struct {
+	Filters   []filter `json:"Filters,omitempty"`
+	NextToken string   `json:"NextToken,omitempty"`
+}
+
+type describeVolumesResponse struct {
+	Volumes   []Volume `json:"Volumes"`
+	NextToken string   `json:"NextToken,omitempty"`
+}
+
+type createBackupRequest struct {
+	VolumeID           string `json:"VolumeId"`
+	ClientRequestToken string `json:"ClientRequestToken,omitempty"`
+	Tags               []Tag  `json:"Tags,omitempty"`
+}
+
+type createBackupResponse struct {
+	Backup Backup `json:"Backup"`
+}
+
+type describeBackupsRequest struct {
+	Filters   []filter `json:"Filters,omitempty"`
+	NextToken string   `json:"NextToken,omitempty"`
+}
+
+type describeBackupsResponse struct {
+	Backups   []Backup `json:"Backups"`
+	NextToken string   `json:"NextToken,omitempty"`
+}
+
+type deleteBackupRequest struct {
+	BackupID string `json:"BackupId"`
+}
+
+// GetVolume returns the FSx volume in the specified SVM whose name matches the ONTAP volume name.
+func (c *Client) GetVolume(fileSystemID, svmID, volumeName string) (*Volume, error) {
+
+	request := describeVolumesRequest{
+		Filters: []filter{
+			{Name: "file-system-id", Values: []string{fileSystemID}},
+			{Name: "storage-virtual-machine-id", Values: []string{svmID}},
+		},
+	}
+
+	for {
+		response := describeVolumesResponse{}
+		if err := c.invokeAPI(ServiceFSx, fsxTargetPrefix+"DescribeVolumes", request, &response); err != nil {
+			return nil, err
+		}
+
+		for i := range response.Volumes {
+			if response.Volumes[i].Name == volumeName {
+				return &response.Volumes[i], nil
+			}
+		}
+
+		if response.NextToken == "" {
+			break
+		}
+		request.NextToken = response.NextToken
+	}
+
+	return nil, fmt.Errorf("volume %s not found in FSx file system %s", volumeName, fileSystemID)
+}
+
+// CreateBackup starts an AWS-native backup of the specified FSx volume.  The clientRequestToken
+// makes the call idempotent, so retrying with the same token will not create another backup.
+func (c *Client) CreateBackup(volumeID, clientRequestToken string, tags map[string]string) (*Backup, error) {
+
+	request := createBackupRequest{
+		VolumeID:           volumeID,
+		ClientRequestToken: clientRequestToken,
+	}
+	for key, value := range tags {
+		request.Tags = append(request.Tags, Tag{Key: key, Value: value})
+	}
+	response := createBackupResponse{}
+
+	if err := c.invokeAPI(ServiceFSx, fsxTargetPrefix+"CreateBackup", request, &response); err != nil {
+		return nil, err
+	}
+
+	log.WithFields(log.Fields{
+		"volumeID":  volumeID,
+		"backupID":  response.Backup.BackupID,
+		"lifecycle": response.Backup.Lifecycle,
+	}).Debug("Created FSx backup.")
+
+	return &response.Backup, nil
+}
+
+// GetBackups returns all backups of the specified FSx volume.
+func (c *Client) GetBackups(volumeID string) ([]Backup, error) {
+
+	backups := make([]Backup, 0)
+	request := describeBackupsRequest{
+		Filters: []filter{{Name: "volume-id", Values: []string{volumeID}}},
+	}
+
+	for {
+		response := describeBackupsResponse{}
+		if err := c.invokeAPI(ServiceFSx, fsxTargetPrefix+"DescribeBackups", request, &response); err != nil {
+			return nil, err
+		}
+
+		backups = append(backups, response.Backups...)
+
+		if response.NextToken == "" {
+			break
+		}
+		request.NextToken = response.NextToken
+	}
+
+	return backups, nil
+}
+
+// DeleteBackup deletes the specified FSx backup.
+func (c *Client) DeleteBackup(backupID string) error {
+
+	request := deleteBackupRequest{BackupID: backupID}
+
+	if err := c.invokeAPI(ServiceFSx, fsxTargetPrefix+"DeleteBackup", request, nil); err != nil {
+		return err
+	}
+
+	log.WithField("backupID", backupID).Debug("Deleted FSx backup.")
+	return nil
+}
@@ -0,0 +1,163 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package awsapi
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	FileSystemTypeONTAP = "ONTAP"
+	LifecycleAvailable  = "AVAILABLE"
+	LifecycleCreated    = "CREATED"
+)
+
+// Endpoint describes a DNS name and set of IP addresses at which an FSx service is reachable.
+type Endpoint struct {
+	DNSName     string   `json:"DNSName"`
+	IPAddresses []string `json:"IpAddresses"`
+}
+
+type FileSystemEndpoints struct {
+	Intercluster *Endpoint `json:"Intercluster,omitempty"`
+	Management   *Endpoint `json:"Management,omitempty"`
+}
+
+type OntapFileSystemConfiguration struct {
+	DeploymentType string               `json:"DeploymentType"`
+	Endpoints      *FileSystemEndpoints `json:"Endpoints,omitempty"`
+}
+
+// FileSystem is the subset of an FSx file system description needed by Trident.
+type FileSystem struct {
+	FileSystemID       string                        `json:"FileSystemId"`
+	FileSystemType     string                        `json:"FileSystemType"`
+	Lifecycle          string                        `json:"Lifecycle"`
+	StorageCapacity    int64                         `json:"StorageCapacity"`
+	VpcID              string                        `json:"VpcId"`
+	OntapConfiguration *OntapFileSystemConfiguration `json:"OntapConfiguration,omitempty"`
+}
+
+type SVMEndpoints struct {
+	Iscsi      *Endpoint `json:"Iscsi,omitempty"`
+	Management *Endpoint `json:"Management,omitempty"`
+	Nfs        *Endpoint `json:"Nfs,omitempty"`
+	Smb        *Endpoint `json:"Smb,omitempty"`
+}
+
+// StorageVirtualMachine is the subset of an FSx SVM description needed by Trident.
+type StorageVirtualMachine struct {
+	StorageVirtualMachineID string        `json:"StorageVirtualMachineId"`
+	FileSystemID            string        `json:"FileSystemId"`
+	Name                    string        `json:"Name"`
+	Lifecycle               string        `json:"Lifecycle"`
+	UUID                    string        `json:"UUID"`
+	Endpoints               *SVMEndpoints `json:"Endpoints,omitempty"`
+}
+
+type filter struct {
+	Name   string   `json:"Name"`
+	Values []string `json:"Values"`
+}
+
+type describeFileSystemsRequest struct {
+	FileSystemIds []string `json:"FileSystemIds,omitempty"`
+	NextToken     string   `json:"NextToken,omitempty"`
+}
+
+type describeFileSystemsResponse struct {
+	FileSystems []FileSystem `json:"FileSystems"`
+	NextToken   string       `json:"NextToken,omitempty"`
+}
+
+type describeStorageVirtualMachinesRequest struct {
+	Filters   []filter `json:"Filters,omitempty"`
+	NextToken string   `json:"NextToken,omitempty"`
+}
+
+type describeStorageVirtualMachinesResponse struct {
+	StorageVirtualMachines []StorageVirtualMachine `json:"StorageVirtualMachines"`
+	NextToken              string                  `json:"NextToken,omitempty"`
+}
+
+// GetFileSystem returns the FSx file system with the specified ID, which must be an ONTAP file system.
+func (c *Client) GetFileSystem(fileSystemID string) (*FileSystem, error) {
+
+	request := describeFileSystemsRequest{FileSystemIds: []string{fileSystemID}}
+	response := describeFileSystemsResponse{}
+
+	if err := c.invokeAPI(ServiceFSx, fsxTargetPrefix+"DescribeFileSystems", request, &response); err != nil {
+		return nil, err
+	}
+
+	if len(response.FileSystems) == 0 {
+		return nil, fmt.Errorf("FSx file system %s not found", fileSystemID)
+	}
+
+	fileSystem := response.FileSystems[0]
+	if fileSystem.FileSystemType != FileSystemTypeONTAP {
+		return nil, fmt.Errorf("FSx file system %s is of type %s, not %s",
+			fileSystemID, fileSystem.FileSystemType, FileSystemTypeONTAP)
+	}
+
+	log.WithFields(log.Fields{
+		"fileSystemID": fileSystem.FileSystemID,
+		"lifecycle":    fileSystem.Lifecycle,
+	}).Debug("Found FSx file system.")
+
+	return &fileSystem, nil
+}
+
+// GetSVMs returns all SVMs in the specified FSx file system.
+func (c *Client) GetSVMs(fileSystemID string) ([]StorageVirtualMachine, error) {
+
+	svms := make([]StorageVirtualMachine, 0)
+	request := describeStorageVirtualMachinesRequest{
+		Filters: []filter{{Name: "file-system-id", Values: []string{fileSystemID}}},
+	}
+
+	for {
+		response := describeStorageVirtualMachinesResponse{}
+		err := c.invokeAPI(ServiceFSx, fsxTargetPrefix+"DescribeStorageVirtualMachines", request, &response)
+		if err != nil {
+			return nil, err
+		}
+
+		svms = append(svms, response.StorageVirtualMachines...)
+
+		if response.NextToken == "" {
+			break
+		}
+		request.NextToken = response.NextToken
+	}
+
+	return svms, nil
+}
+
+// GetSVM returns the named SVM in the specified FSx file system.  If no name is supplied and
+// the file system contains exactly one SVM, that SVM is returned.
+func (c *Client) GetSVM(fileSystemID, svmName string) (*StorageVirtualMachine, error) {
+
+	svms, err := c.GetSVMs(fileSystemID)
+	if err != nil {
+		return nil, err
+	}
+
+	if svmName == "" {
+		if len(svms) != 1 {
+			return nil, fmt.Errorf("cannot derive SVM to use; FSx file system %s has %d SVMs",
+				fileSystemID, len(svms))
+		}
+		return &svms[0], nil
+	}
+
+	for i := range svms {
+		if svms[i].Name == svmName {
+			return &svms[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("SVM %s not found in FSx file system %s", svmName, fileSystemID)
+}
@@ -0,0 +1,38 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package awsapi
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type getSecretValueRequest struct {
+	SecretID string `json:"SecretId"`
+}
+
+type getSecretValueResponse struct {
+	ARN          string `json:"ARN"`
+	Name         string `json:"Name"`
+	SecretString string `json:"SecretString"`
+}
+
+// GetSecret reads a Secrets Manager secret whose value is a flat JSON object of strings,
+// such as {"username": "vsadmin", "password": "secret"}.
+func (c *Client) GetSecret(secretARN string) (map[string]string, error) {
+
+	request := getSecretValueRequest{SecretID: secretARN}
+	response := getSecretValueResponse{}
+
+	err := c.invokeAPI(ServiceSecretsManager, secretsManagerTargetPrefix+"GetSecretValue", request, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	secretMap := make(map[string]string)
+	if err = json.Unmarshal([]byte(response.SecretString), &secretMap); err != nil {
+		return nil, fmt.Errorf("secret %s is not a JSON object of strings", secretARN)
+	}
+
+	return secretMap, nil
+}
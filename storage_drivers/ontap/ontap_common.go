@@ -11,6 +11,7 @@ import (
 	"math/rand"
 	"net"
 	"os"
+	"regexp"
 	"runtime/debug"
 	"sort"
 	"strconv"
@@ -55,8 +56,13 @@ const (
 	ProvisioningType = "provisioningType"
 	SplitOnClone     = "splitOnClone"
 	TieringPolicy    = "tieringPolicy"
+	MountOptions     = "mountOptions"
+	StoragePrefix    = "storagePrefix"
 )
 
+// storagePrefixRegex matches the storage prefixes virtual pools may set, which must form valid ONTAP volume names
+var storagePrefixRegex = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_]*$`)
+
 //For legacy reasons, these strings mustn't change
 const (
 	artifactPrefixDocker     = "ndvp"
@@ -286,6 +292,36 @@ func getExportPolicyName(backendUUID string) string {
 	return fmt.Sprintf("trident-%s", backendUUID)
 }
 
+// getSMBPath returns the UNC path of a volume's SMB share, which is named after the volume.
+func getSMBPath(dataLIF, shareName string) string {
+	return `\\` + dataLIF + `\` + shareName
+}
+
+// createCIFSShare shares a junction path over SMB.  It is not an error if the share already exists.
+func createCIFSShare(clientAPI *api.Client, shareName, path string) error {
+	shareResponse, err := clientAPI.CifsShareCreate(shareName, path)
+	if err = api.GetError(shareResponse, err); err != nil {
+		if zerr, ok := err.(api.ZapiError); ok && zerr.Code() == azgo.EDUPLICATEENTRY {
+			log.WithField("share", shareName).Debug("CIFS share already exists.")
+			return nil
+		}
+		return fmt.Errorf("error creating CIFS share %s for path %s: %v", shareName, path, err)
+	}
+	return nil
+}
+
+// deleteCIFSShare removes a volume's SMB share, logging rather than returning any error since the share
+// is deleted along with its volume anyway.
+func deleteCIFSShare(clientAPI *api.Client, shareName string) {
+	shareResponse, err := clientAPI.CifsShareDelete(shareName)
+	if err = api.GetError(shareResponse, err); err != nil {
+		log.WithFields(log.Fields{
+			"share": shareName,
+			"error": err,
+		}).Warning("Could not delete CIFS share.")
+	}
+}
+
 // ensureNodeAccess check to see if the export policy exists and if not it will create it and force a reconcile.
 // This should be used during publish to make sure access is available if the policy has somehow been deleted.
 // Otherwise we should not need to reconcile, which could be expensive.
@@ -454,7 +490,7 @@ func PopulateOntapLunMapping(
 	volConfig.AccessInfo.IscsiPortals = filteredIPs[1:]
 	volConfig.AccessInfo.IscsiTargetIQN = targetIQN
 	volConfig.AccessInfo.IscsiLunNumber = int32(lunID)
-	volConfig.AccessInfo.IscsiIgroup = config.IgroupName
+	volConfig.AccessInfo.IscsiIgroup = igroupName
 	log.WithFields(log.Fields{
 		"volume":          volConfig.Name,
 		"volume_internal": volConfig.InternalName,
@@ -472,9 +508,11 @@ func PopulateOntapLunMapping(
 // some host identity (but not locality) as well as storage controller API access.
 // This function assumes that the list of data LIF IP addresses does not change between driver initialization
 // and publish
+// PublishLUN makes a LUN accessible to a host through an igroup.  A shared LUN is mapped to the igroup
+// alongside any other igroups it is mapped to; otherwise the igroup becomes the LUN's only map.
 func PublishLUN(
 	clientAPI *api.Client, config *drivers.OntapStorageDriverConfig, ips []string,
-	publishInfo *utils.VolumePublishInfo, lunPath, igroupName string, iSCSINodeName string,
+	publishInfo *utils.VolumePublishInfo, lunPath, igroupName string, iSCSINodeName string, shared bool,
 ) error {
 
 	if config.DebugTraceFlags["method"] {
@@ -523,6 +561,13 @@ func PublishLUN(
 		log.WithFields(log.Fields{"LUN": lunPath, "fstype": fstype}).Debug("Found LUN attribute fstype.")
 	}
 
+	// A volume's own CHAP credentials must be configured for the host's initiator before it logs in
+	if publishInfo.VolumeCHAP {
+		if err = ensureInitiatorCHAP(clientAPI, iqn, publishInfo); err != nil {
+			return err
+		}
+	}
+
 	if !publishInfo.Unmanaged {
 		// Add IQN to igroup
 		igroupAddResponse, err := clientAPI.IgroupAdd(igroupName, iqn)
@@ -539,7 +584,12 @@ func PublishLUN(
 	}
 
 	// Map LUN (it may already be mapped)
-	lunID, err := clientAPI.LunMapIfNotMapped(igroupName, lunPath, publishInfo.Unmanaged)
+	var lunID int
+	if shared {
+		lunID, err = clientAPI.LunMapSharedIfNotMapped(igroupName, lunPath)
+	} else {
+		lunID, err = clientAPI.LunMapIfNotMapped(igroupName, lunPath, publishInfo.Unmanaged)
+	}
 	if err != nil {
 		return err
 	}
@@ -561,20 +611,118 @@ func PublishLUN(
 	publishInfo.IscsiTargetIQN = iSCSINodeName
 	publishInfo.IscsiIgroup = igroupName
 	publishInfo.FilesystemType = fstype
-	publishInfo.UseCHAP = config.UseCHAP
 
+	if !publishInfo.VolumeCHAP {
+		publishInfo.UseCHAP = config.UseCHAP
+		if publishInfo.UseCHAP {
+			publishInfo.IscsiUsername = config.ChapUsername
+			publishInfo.IscsiInitiatorSecret = config.ChapInitiatorSecret
+			publishInfo.IscsiTargetUsername = config.ChapTargetUsername
+			publishInfo.IscsiTargetSecret = config.ChapTargetInitiatorSecret
+		}
+	}
 	if publishInfo.UseCHAP {
-		publishInfo.IscsiUsername = config.ChapUsername
-		publishInfo.IscsiInitiatorSecret = config.ChapInitiatorSecret
-		publishInfo.IscsiTargetUsername = config.ChapTargetUsername
-		publishInfo.IscsiTargetSecret = config.ChapTargetInitiatorSecret
 		publishInfo.IscsiInterface = "default"
 	}
+	publishInfo.IscsiNetworkInterface = config.IscsiNetworkInterface
 	publishInfo.SharedTarget = true
 
 	return nil
 }
 
+// ensureInitiatorCHAP configures an initiator to authenticate with the CHAP credentials of a volume.
+// ONTAP holds one set of credentials per initiator in an SVM, so an initiator already using another
+// CHAP user can't be given a volume with different credentials.  Credentials for the same user are
+// updated, so a rotated secret takes effect on the volume's next publication.
+func ensureInitiatorCHAP(clientAPI *api.Client, initiator string, publishInfo *utils.VolumePublishInfo) error {
+
+	logFields := log.Fields{
+		"initiator": initiator,
+		"username":  publishInfo.IscsiUsername,
+	}
+
+	authResponse, err := clientAPI.IscsiInitiatorGetAuth(initiator)
+	if err = api.GetError(authResponse, err); err == nil && authResponse.Result.AuthTypePtr != nil {
+		authType := authResponse.Result.AuthType()
+		if authType == "CHAP" {
+			if authResponse.Result.UserNamePtr != nil &&
+				authResponse.Result.UserName() != publishInfo.IscsiUsername {
+				return fmt.Errorf("initiator %s already authenticates as CHAP user %s, not %s",
+					initiator, authResponse.Result.UserName(), publishInfo.IscsiUsername)
+			}
+
+			modifyResponse, err := clientAPI.IscsiInitiatorModifyCHAPParams(initiator,
+				publishInfo.IscsiUsername, publishInfo.IscsiInitiatorSecret,
+				publishInfo.IscsiTargetUsername, publishInfo.IscsiTargetSecret)
+			if err = api.GetError(modifyResponse, err); err != nil {
+				return fmt.Errorf("error updating CHAP credentials of initiator %s: %v", initiator, err)
+			}
+			log.WithFields(logFields).Debug("Updated initiator CHAP credentials.")
+			return nil
+		}
+
+		// Replace the initiator's other auth type, such as none, with CHAP
+		deleteResponse, err := clientAPI.IscsiInitiatorDeleteAuth(initiator)
+		if err = api.GetError(deleteResponse, err); err != nil {
+			return fmt.Errorf("error removing %s auth of initiator %s: %v", authType, initiator, err)
+		}
+	}
+
+	addResponse, err := clientAPI.IscsiInitiatorAddAuth(initiator, "CHAP",
+		publishInfo.IscsiUsername, publishInfo.IscsiInitiatorSecret,
+		publishInfo.IscsiTargetUsername, publishInfo.IscsiTargetSecret)
+	if err = api.GetError(addResponse, err); err != nil {
+		return fmt.Errorf("error setting CHAP credentials of initiator %s: %v", initiator, err)
+	}
+	log.WithFields(logFields).Debug("Set initiator CHAP credentials.")
+
+	return nil
+}
+
+// getNodeIgroupName returns the name of the igroup that holds only one node's initiator.
+func getNodeIgroupName(igroupName, nodeName string) string {
+	return igroupName + "-" + nodeName
+}
+
+// ensureIgroupExists creates an iSCSI igroup, unless it already exists.
+func ensureIgroupExists(clientAPI *api.Client, igroupName string) error {
+
+	igroupResponse, err := clientAPI.IgroupCreate(igroupName, "iscsi", "linux")
+	err = api.GetError(igroupResponse, err)
+	zerr, zerrOK := err.(api.ZapiError)
+	if err == nil || (zerrOK && zerr.Code() == azgo.EVDISK_ERROR_INITGROUP_EXISTS) {
+		return nil
+	}
+	return fmt.Errorf("error creating igroup %v: %v", igroupName, err)
+}
+
+// unmapLUNFromIgroup removes a LUN's map to an igroup, if the LUN is mapped to it.
+func unmapLUNFromIgroup(clientAPI *api.Client, lunPath, igroupName string) error {
+
+	lunMapListResponse, err := clientAPI.LunMapListInfo(lunPath)
+	if err = api.GetError(lunMapListResponse, err); err != nil {
+		return fmt.Errorf("problem reading maps for LUN %s: %v", lunPath, err)
+	}
+	if lunMapListResponse.Result.InitiatorGroupsPtr == nil {
+		return nil
+	}
+
+	for _, igroup := range lunMapListResponse.Result.InitiatorGroupsPtr.InitiatorGroupInfoPtr {
+		if igroup.InitiatorGroupName() != igroupName {
+			continue
+		}
+		lunUnmapResponse, err := clientAPI.LunUnmap(igroupName, lunPath)
+		if err = api.GetError(lunUnmapResponse, err); err != nil {
+			return fmt.Errorf("problem unmapping LUN %s from igroup %s: %v", lunPath, igroupName, err)
+		}
+		log.WithFields(log.Fields{
+			"lun":    lunPath,
+			"igroup": igroupName,
+		}).Debug("LUN unmapped.")
+	}
+	return nil
+}
+
 // getISCSIDataLIFsForReportingNodes finds the data LIFs for the reporting nodes for the LUN.
 func getISCSIDataLIFsForReportingNodes(clientAPI *api.Client, ips []string, lunPath string, igroupName string,
 ) ([]string, error) {
@@ -609,6 +757,29 @@ func getISCSIDataLIFsForReportingNodes(clientAPI *api.Client, ips []string, lunP
 	return reportedDataLIFs, nil
 }
 
+// removeNodeFromIgroup removes a node's initiator from an igroup, so that the node can no longer
+// access the LUNs mapped to the igroup.  A node that isn't in the igroup is not an error.
+func removeNodeFromIgroup(node *utils.Node, igroupName string, clientAPI *api.Client) error {
+
+	if node.IQN == "" {
+		return nil
+	}
+
+	igroupRemoveResponse, err := clientAPI.IgroupRemove(igroupName, node.IQN, true)
+	err = api.GetError(igroupRemoveResponse, err)
+	zerr, zerrOK := err.(api.ZapiError)
+	if err == nil || (zerrOK && (zerr.Code() == azgo.EVDISK_ERROR_NODE_NOT_IN_INITGROUP ||
+		zerr.Code() == azgo.EVDISK_ERROR_NO_SUCH_INITGROUP)) {
+		log.WithFields(log.Fields{
+			"node":   node.Name,
+			"IQN":    node.IQN,
+			"igroup": igroupName,
+		}).Debug("Host IQN not in igroup.")
+		return nil
+	}
+	return fmt.Errorf("error removing IQN %v from igroup %v: %v", node.IQN, igroupName, err)
+}
+
 // randomString returns a string of the specified length.
 func randomChapString(strSize int) (string, error) {
 	b := make([]byte, strSize)
@@ -866,6 +1037,11 @@ func InitializeOntapDriver(config *drivers.OntapStorageDriverConfig) (*api.Clien
 		defer log.WithFields(fields).Debug("<<<< InitializeOntapDriver")
 	}
 
+	// Discover endpoints and credentials if this backend is an FSx for NetApp ONTAP file system
+	if err := InitializeFSxConfig(config); err != nil {
+		return nil, fmt.Errorf("could not initialize FSx for NetApp ONTAP configuration: %v", err)
+	}
+
 	// Splitting config.ManagementLIF with colon allows to provide managementLIF value as address:port format
 	mgmtLIF := ""
 	if utils.IPv6Check(config.ManagementLIF) {
@@ -934,12 +1110,14 @@ func InitializeOntapAPI(config *drivers.OntapStorageDriverConfig) (*api.Client,
 	}
 
 	client := api.NewClient(api.ClientConfig{
-		ManagementLIF:   config.ManagementLIF,
-		SVM:             config.SVM,
-		Username:        config.Username,
-		Password:        config.Password,
-		DriverContext:   config.DriverContext,
-		DebugTraceFlags: config.DebugTraceFlags,
+		ManagementLIF:     config.ManagementLIF,
+		SVM:               config.SVM,
+		Username:          config.Username,
+		Password:          config.Password,
+		DriverContext:     config.DriverContext,
+		DebugTraceFlags:   config.DebugTraceFlags,
+		BackendName:       config.BackendName,
+		StorageDriverName: config.StorageDriverName,
 	})
 
 	if config.SVM != "" {
@@ -970,12 +1148,14 @@ func InitializeOntapAPI(config *drivers.OntapStorageDriverConfig) (*api.Client,
 	svmUUID := string(vserverResponse.Result.AttributesListPtr.VserverInfoPtr[0].Uuid())
 
 	client = api.NewClient(api.ClientConfig{
-		ManagementLIF:   config.ManagementLIF,
-		SVM:             config.SVM,
-		Username:        config.Username,
-		Password:        config.Password,
-		DriverContext:   config.DriverContext,
-		DebugTraceFlags: config.DebugTraceFlags,
+		ManagementLIF:     config.ManagementLIF,
+		SVM:               config.SVM,
+		Username:          config.Username,
+		Password:          config.Password,
+		DriverContext:     config.DriverContext,
+		DebugTraceFlags:   config.DebugTraceFlags,
+		BackendName:       config.BackendName,
+		StorageDriverName: config.StorageDriverName,
 	})
 	client.SVMUUID = svmUUID
 
@@ -1017,8 +1197,15 @@ func ValidateSANDriver(api *api.Client, config *drivers.OntapStorageDriverConfig
 	}
 
 	if config.DriverContext == tridentconfig.ContextDocker {
-		// Make sure this host is logged into the ONTAP iSCSI target
-		err := utils.EnsureISCSISessions(ips)
+		// Make sure this host is logged into the ONTAP iSCSI target, through the backend's network interface
+		iface := ""
+		if config.IscsiNetworkInterface != "" {
+			var err error
+			if iface, err = utils.EnsureISCSIInterface(config.IscsiNetworkInterface); err != nil {
+				return fmt.Errorf("error creating iSCSI interface: %v", err)
+			}
+		}
+		err := utils.EnsureISCSISessions(ips, iface)
 		if err != nil {
 			return fmt.Errorf("error establishing iSCSI session: %v", err)
 		}
@@ -1036,7 +1223,24 @@ func ValidateNASDriver(api *api.Client, config *drivers.OntapStorageDriverConfig
 		defer log.WithFields(fields).Debug("<<<< ValidateNASDriver")
 	}
 
-	dataLIFs, err := api.NetInterfaceGetDataLIFs("nfs")
+	switch config.NASType {
+	case drivers.NASTypeNFS:
+		break
+	case drivers.NASTypeSMB:
+		if config.StorageDriverName != drivers.OntapNASStorageDriverName {
+			return fmt.Errorf("SMB volumes are only supported by the %s driver", drivers.OntapNASStorageDriverName)
+		}
+	default:
+		return fmt.Errorf("invalid value for nasType: %s", config.NASType)
+	}
+
+	// SMB shares are served by CIFS LIFs
+	lifProtocol := "nfs"
+	if config.NASType == drivers.NASTypeSMB {
+		lifProtocol = "cifs"
+	}
+
+	dataLIFs, err := api.NetInterfaceGetDataLIFs(lifProtocol)
 	if err != nil {
 		return err
 	}
@@ -1112,6 +1316,7 @@ const DefaultUnixPermissions = "---rwxrwxrwx"
 const DefaultSnapshotDir = "false"
 const DefaultExportPolicy = "default"
 const DefaultSecurityStyle = "unix"
+const DefaultSMBSecurityStyle = "ntfs"
 const DefaultNfsMountOptionsDocker = "-o nfsvers=3"
 const DefaultNfsMountOptionsKubernetes = ""
 const DefaultSplitOnClone = "false"
@@ -1178,8 +1383,16 @@ func PopulateConfigurationDefaults(config *drivers.OntapStorageDriverConfig) err
 		config.ExportPolicy = DefaultExportPolicy
 	}
 
+	if config.NASType == "" {
+		config.NASType = drivers.NASTypeNFS
+	}
+
 	if config.SecurityStyle == "" {
-		config.SecurityStyle = DefaultSecurityStyle
+		if config.NASType == drivers.NASTypeSMB {
+			config.SecurityStyle = DefaultSMBSecurityStyle
+		} else {
+			config.SecurityStyle = DefaultSecurityStyle
+		}
 	}
 
 	if config.NfsMountOptions == "" {
@@ -1235,6 +1448,7 @@ func PopulateConfigurationDefaults(config *drivers.OntapStorageDriverConfig) err
 		"ExportPolicy":        config.ExportPolicy,
 		"SecurityStyle":       config.SecurityStyle,
 		"NfsMountOptions":     config.NfsMountOptions,
+		"NASType":             config.NASType,
 		"SplitOnClone":        config.SplitOnClone,
 		"FileSystemType":      config.FileSystemType,
 		"Encryption":          config.Encryption,
@@ -1469,6 +1683,36 @@ func probeForVolume(name string, client *api.Client) error {
 	}
 }
 
+// cloneDependsOnSource asks ONTAP whether a clone is still a FlexClone sharing storage with its
+// parent volume.  A clone that was split from its parent, or that no longer exists, doesn't depend
+// on it.
+func cloneDependsOnSource(volConfig *storage.VolumeConfig, client *api.Client) (bool, error) {
+
+	if volConfig.CloneSourceVolume == "" {
+		return false, nil
+	}
+
+	volume, err := client.VolumeGet(volConfig.InternalName)
+	if err != nil {
+		if exists, existsErr := client.VolumeExists(volConfig.InternalName); existsErr == nil && !exists {
+			return false, nil
+		}
+		return false, fmt.Errorf("could not get volume %s; %v", volConfig.InternalName, err)
+	}
+	return isFlexClone(volume), nil
+}
+
+// isFlexClone reports whether a volume has a FlexClone parent, which ONTAP reports until the clone is
+// split from it.
+func isFlexClone(volume *azgo.VolumeAttributesType) bool {
+	cloneAttrs := volume.VolumeCloneAttributesPtr
+	if cloneAttrs == nil || cloneAttrs.VolumeCloneParentAttributesPtr == nil {
+		return false
+	}
+	parentAttrs := cloneAttrs.VolumeCloneParentAttributesPtr
+	return parentAttrs.NamePtr != nil && *parentAttrs.NamePtr != ""
+}
+
 // Create a volume clone
 func CreateOntapClone(
 	name, source, snapshot string, split bool, config *drivers.OntapStorageDriverConfig, client *api.Client,
@@ -1695,7 +1939,8 @@ func CreateSnapshot(
 		return nil, fmt.Errorf("error reading volume size: %v", err)
 	}
 
-	snapResponse, err := client.SnapshotCreate(internalSnapName, internalVolName)
+	snapResponse, err := client.SnapshotCreateWithLabel(internalSnapName, internalVolName,
+		snapConfig.SnapmirrorLabel, getSnapshotComment(snapConfig))
 	if err = api.GetError(snapResponse, err); err != nil {
 		return nil, fmt.Errorf("could not create snapshot: %v", err)
 	}
@@ -1719,6 +1964,63 @@ func CreateSnapshot(
 	return nil, fmt.Errorf("could not find snapshot %s for souce volume %s", internalSnapName, internalVolName)
 }
 
+// getSnapshotComment returns the comment recorded on a new ONTAP snapshot, which carries the hints from
+// its snapshot class that ONTAP has no attribute for, so that backup tooling can read them.
+func getSnapshotComment(snapConfig *storage.SnapshotConfig) string {
+
+	hints := make([]string, 0, 2)
+	if snapConfig.Retention != "" {
+		hints = append(hints, storage.SnapshotParameterRetention+"="+snapConfig.Retention)
+	}
+	if snapConfig.ConsistencyType != "" {
+		hints = append(hints, storage.SnapshotParameterConsistencyType+"="+snapConfig.ConsistencyType)
+	}
+	return strings.Join(hints, ",")
+}
+
+// CreateGroupSnapshot creates snapshots of several volumes at the same point in time using an ONTAP
+// consistency group snapshot.  All snapshots must have the same name.
+func CreateGroupSnapshot(
+	snapConfigs []*storage.SnapshotConfig, config *drivers.OntapStorageDriverConfig, client *api.Client,
+	sizeGetter func(string) (int, error),
+) ([]*storage.Snapshot, error) {
+
+	internalSnapName := snapConfigs[0].InternalName
+	internalVolNames := make([]string, 0, len(snapConfigs))
+	for _, snapConfig := range snapConfigs {
+		internalVolNames = append(internalVolNames, snapConfig.VolumeInternalName)
+	}
+
+	if config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":       "CreateGroupSnapshot",
+			"Type":         "ontap_common",
+			"snapshotName": internalSnapName,
+			"volumeNames":  internalVolNames,
+		}
+		log.WithFields(fields).Debug(">>>> CreateGroupSnapshot")
+		defer log.WithFields(fields).Debug("<<<< CreateGroupSnapshot")
+	}
+
+	if err := client.ConsistencyGroupSnapshot(internalSnapName, internalVolNames); err != nil {
+		return nil, fmt.Errorf("could not create group snapshot: %v", err)
+	}
+
+	snapshots := make([]*storage.Snapshot, 0, len(snapConfigs))
+	for _, snapConfig := range snapConfigs {
+		snapshot, err := GetSnapshot(snapConfig, config, client, sizeGetter)
+		if err != nil {
+			return nil, err
+		}
+		if snapshot == nil {
+			return nil, fmt.Errorf("could not find snapshot %s for source volume %s", internalSnapName,
+				snapConfig.VolumeInternalName)
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots, nil
+}
+
 // Restore a volume (in place) from a snapshot.
 func RestoreSnapshot(
 	snapConfig *storage.SnapshotConfig, config *drivers.OntapStorageDriverConfig, client *api.Client,
@@ -1858,7 +2160,7 @@ func GetVolume(name string, client *api.Client, config *drivers.OntapStorageDriv
 	}
 	if !volExists {
 		log.WithField("flexvol", name).Debug("Flexvol not found.")
-		return fmt.Errorf("volume %s does not exist", name)
+		return utils.NotFoundError(fmt.Sprintf("volume %s does not exist", name))
 	}
 
 	return nil
@@ -2026,7 +2328,9 @@ func InitializeStoragePoolsCommon(d StorageDriver, poolAttributes map[string]sa.
 	// Update physical pool attributes map with aggregate info (i.e. MediaType)
 	aggrErr := getVserverAggrAttributes(d, &physicalStoragePoolAttributes)
 
-	if zerr, ok := aggrErr.(api.ZapiError); ok && zerr.IsScopeError() {
+	if isFSx(config) {
+		applyFSxAggrAttributes(physicalStoragePoolAttributes)
+	} else if zerr, ok := aggrErr.(api.ZapiError); ok && zerr.IsScopeError() {
 		log.WithFields(log.Fields{
 			"username": config.Username,
 		}).Warn("User has insufficient privileges to obtain aggregate info. " +
@@ -2081,6 +2385,8 @@ func InitializeStoragePoolsCommon(d StorageDriver, poolAttributes map[string]sa.
 		pool.InternalAttributes[ExportPolicy] = config.ExportPolicy
 		pool.InternalAttributes[SecurityStyle] = config.SecurityStyle
 		pool.InternalAttributes[TieringPolicy] = config.TieringPolicy
+		pool.InternalAttributes[MountOptions] = config.MountOptions
+		pool.SupportedTopologies = config.SupportedTopologies
 
 		if d.Name() == drivers.OntapSANStorageDriverName || d.Name() == drivers.OntapSANEconomyStorageDriverName {
 			pool.InternalAttributes[SpaceAllocation] = config.SpaceAllocation
@@ -2090,6 +2396,11 @@ func InitializeStoragePoolsCommon(d StorageDriver, poolAttributes map[string]sa.
 		physicalPools[pool.Name] = pool
 	}
 
+	// Only virtual pools may name their volumes differently than the backend
+	if config.OntapStorageDriverConfigDefaults.StoragePrefix != "" {
+		return nil, nil, errors.New("storagePrefix may only be set in the defaults of a virtual pool")
+	}
+
 	// Define virtual pools
 	for index, vpool := range config.Storage {
 
@@ -2168,6 +2479,16 @@ func InitializeStoragePoolsCommon(d StorageDriver, poolAttributes map[string]sa.
 			tieringPolicy = vpool.TieringPolicy
 		}
 
+		mountOptions := config.MountOptions
+		if vpool.MountOptions != "" {
+			mountOptions = vpool.MountOptions
+		}
+
+		supportedTopologies := config.SupportedTopologies
+		if len(vpool.SupportedTopologies) > 0 {
+			supportedTopologies = vpool.SupportedTopologies
+		}
+
 		pool := storage.NewStoragePool(nil, poolName(fmt.Sprintf("pool_%d", index), backendName))
 
 		// Update pool with attributes set by default for this backend
@@ -2212,6 +2533,12 @@ func InitializeStoragePoolsCommon(d StorageDriver, poolAttributes map[string]sa.
 		pool.InternalAttributes[ExportPolicy] = exportPolicy
 		pool.InternalAttributes[SecurityStyle] = securityStyle
 		pool.InternalAttributes[TieringPolicy] = tieringPolicy
+		pool.InternalAttributes[MountOptions] = mountOptions
+		pool.SupportedTopologies = supportedTopologies
+
+		if vpool.StoragePrefix != "" {
+			pool.InternalAttributes[StoragePrefix] = vpool.StoragePrefix
+		}
 
 		if d.Name() == drivers.OntapSANStorageDriverName || d.Name() == drivers.OntapSANEconomyStorageDriverName {
 			pool.InternalAttributes[SpaceAllocation] = spaceAllocation
@@ -2224,6 +2551,58 @@ func InitializeStoragePoolsCommon(d StorageDriver, poolAttributes map[string]sa.
 	return physicalPools, virtualPools, nil
 }
 
+// setRootOwnership sets the owner of a new volume's root directory if the volume requested one, so
+// that kubelet needn't recursively change the ownership of a large volume to match a pod's fsGroup.
+func setRootOwnership(volConfig *storage.VolumeConfig, name string, clientAPI *api.Client) error {
+
+	if volConfig.RootOwnership == "" {
+		return nil
+	}
+
+	uid, gid, err := utils.ParseOwnership(volConfig.RootOwnership)
+	if err != nil {
+		return err
+	}
+
+	modifyResponse, err := clientAPI.VolumeModifyUnixOwner(name, uid, gid)
+	if err = api.GetError(modifyResponse, err); err != nil {
+		return fmt.Errorf("error setting ownership of volume %s to %s: %v", name, volConfig.RootOwnership, err)
+	}
+
+	log.WithFields(log.Fields{
+		"volume":    name,
+		"ownership": volConfig.RootOwnership,
+	}).Debug("Set volume root ownership.")
+
+	return nil
+}
+
+// checkImportRename ensures that an imported volume can be renamed to the name Trident gives it.
+func checkImportRename(originalName, newName string, clientAPI *api.Client) error {
+
+	if originalName == newName {
+		return nil
+	}
+
+	exists, err := clientAPI.VolumeExists(newName)
+	if err != nil {
+		return fmt.Errorf("error checking for existing volume %s: %v", newName, err)
+	}
+	if exists {
+		return fmt.Errorf("volume %s cannot be renamed to %s, which already exists", originalName, newName)
+	}
+
+	return nil
+}
+
+// applyPoolMountOptions sets a volume's mount options from the storage pool it is created in,
+// unless the volume already has mount options of its own.
+func applyPoolMountOptions(volConfig *storage.VolumeConfig, pool *storage.Pool) {
+	if volConfig.MountOptions == "" && pool != nil {
+		volConfig.MountOptions = pool.InternalAttributes[MountOptions]
+	}
+}
+
 // ValidateStoragePools makes sure that values are set for the fields, if value(s) were not specified
 // for a field then a default should have been set in for that field in the intialize storage pools
 func ValidateStoragePools(physicalPools, virtualPools map[string]*storage.Pool, driverType string) error {
@@ -2300,6 +2679,23 @@ func ValidateStoragePools(physicalPools, virtualPools map[string]*storage.Pool,
 				poolName)
 		}
 
+		// Validate MountOptions
+		if err := utils.ValidateMountOptions(pool.InternalAttributes[MountOptions]); err != nil {
+			return fmt.Errorf("invalid mountOptions in pool %s: %v", poolName, err)
+		}
+
+		// Validate StoragePrefix, which the economy drivers can't honor, since they share Flexvols among pools
+		if prefix, ok := pool.InternalAttributes[StoragePrefix]; ok {
+			if driverType == drivers.OntapNASQtreeStorageDriverName ||
+				driverType == drivers.OntapSANEconomyStorageDriverName {
+				return fmt.Errorf("storagePrefix may not be set in pool %s of a %s backend", poolName, driverType)
+			}
+			if !storagePrefixRegex.MatchString(prefix) {
+				return fmt.Errorf("invalid storagePrefix %s in pool %s; it must start with a letter and "+
+					"contain only letters, digits, and underscores", prefix, poolName)
+			}
+		}
+
 		// Validate media type
 		if pool.InternalAttributes[Media] != "" {
 			for _, mediaType := range strings.Split(pool.InternalAttributes[Media], ",") {
@@ -2388,6 +2784,96 @@ func getStorageBackendSpecsCommon(backend *storage.Backend, physicalPools,
 	return nil
 }
 
+// getStorageCapacityCommon reports the free space in each of the backend's aggregates.  A physical pool
+// draws on its own aggregate, while a virtual pool may place volumes on any of them.
+func getStorageCapacityCommon(
+	d StorageDriver, physicalPools, virtualPools map[string]*storage.Pool,
+) (*storage.StorageCapacity, error) {
+
+	aggrFree, aggrSize, err := getAggregateSpace(d)
+	if err != nil {
+		return nil, err
+	}
+
+	capacity := &storage.StorageCapacity{
+		SourceFreeBytes:  make(map[string]int64),
+		PoolSources:      make(map[string][]string),
+		SourceTotalBytes: make(map[string]int64),
+	}
+
+	aggregates := make([]string, 0, len(physicalPools))
+	for aggrName := range physicalPools {
+		capacity.SourceFreeBytes[aggrName] = aggrFree[aggrName]
+		capacity.PoolSources[aggrName] = []string{aggrName}
+		if size, ok := aggrSize[aggrName]; ok {
+			capacity.SourceTotalBytes[aggrName] = size
+		}
+		aggregates = append(aggregates, aggrName)
+	}
+	for poolName := range virtualPools {
+		capacity.PoolSources[poolName] = aggregates
+	}
+
+	return capacity, nil
+}
+
+// getAggregateSpace returns the bytes available in each aggregate assigned to the SVM, reduced to
+// stay within limitAggregateUsage if it is set, along with the size of each aggregate.  Reading
+// aggregate sizes requires cluster credentials, so no sizes are returned without them.
+func getAggregateSpace(d StorageDriver) (map[string]int64, map[string]int64, error) {
+
+	result, err := d.GetAPI().VserverShowAggrGetIterRequest()
+	if err = api.GetError(result, err); err != nil {
+		return nil, nil, fmt.Errorf("could not get SVM aggregates; %v", err)
+	}
+
+	aggrFree := make(map[string]int64)
+	if result.Result.AttributesListPtr != nil {
+		for _, aggr := range result.Result.AttributesListPtr.ShowAggregatesPtr {
+			if aggr.AggregateNamePtr == nil || aggr.AvailableSizePtr == nil {
+				continue
+			}
+			aggrFree[string(aggr.AggregateName())] = int64(aggr.AvailableSize())
+		}
+	}
+
+	percentLimit := 0.0
+	if limitAggregateUsage := d.GetConfig().LimitAggregateUsage; limitAggregateUsage != "" {
+		if percentLimit, err = strconv.ParseFloat(limitAggregateUsage, 64); err != nil {
+			return nil, nil, fmt.Errorf("invalid value for limitAggregateUsage; %v", err)
+		}
+	}
+
+	aggrSize := make(map[string]int64)
+	spaceResponse, err := d.GetAPI().AggrSpaceGetIterRequest("")
+	if err = api.GetError(spaceResponse, err); err != nil {
+		log.WithField("error", err).Debug("Could not read aggregate sizes or apply limitAggregateUsage.")
+		return aggrFree, aggrSize, nil
+	}
+	if spaceResponse.Result.AttributesListPtr != nil {
+		for _, aggrSpace := range spaceResponse.Result.AttributesListPtr.SpaceInformationPtr {
+			free, ok := aggrFree[aggrSpace.Aggregate()]
+			if !ok {
+				continue
+			}
+			aggrSize[aggrSpace.Aggregate()] = int64(aggrSpace.AggregateSize())
+			if percentLimit == 0 {
+				continue
+			}
+			allowed := int64(float64(aggrSpace.AggregateSize())*percentLimit/100.0) -
+				int64(aggrSpace.UsedIncludingSnapshotReserve())
+			if allowed < 0 {
+				allowed = 0
+			}
+			if allowed < free {
+				aggrFree[aggrSpace.Aggregate()] = allowed
+			}
+		}
+	}
+
+	return aggrFree, aggrSize, nil
+}
+
 func getStorageBackendPhysicalPoolNamesCommon(physicalPools map[string]*storage.Pool) []string {
 	physicalPoolNames := make([]string, 0)
 	for poolName := range physicalPools {
@@ -2495,10 +2981,13 @@ func getPoolsForCreate(
 	delete(attributesCopy, sa.Selector)
 	storageClass := sc.NewFromAttributes(attributesCopy)
 
-	// Find matching pools
+	// Find matching pools, limited to the one the volume is pinned to if any
 	candidatePools := make([]*storage.Pool, 0)
 
 	for _, pool := range physicalPools {
+		if volConfig.Pool != "" && pool.Name != volConfig.Pool {
+			continue
+		}
 		if storageClass.Matches(pool) {
 			candidatePools = append(candidatePools, pool)
 		}
@@ -2536,6 +3025,84 @@ func createPrepareCommon(d storage.Driver, volConfig *storage.VolumeConfig) {
 	volConfig.InternalName = d.GetInternalVolumeName(volConfig.Name)
 }
 
+// getPoolInternalVolumeName returns the internal name of a volume created in a storage pool, which
+// uses the pool's storage prefix, if it has one, in place of the backend's.
+func getPoolInternalVolumeName(
+	commonConfig *drivers.CommonStorageDriverConfig, pool *storage.Pool, name string,
+) string {
+
+	if pool != nil {
+		if prefix, ok := pool.InternalAttributes[StoragePrefix]; ok {
+			poolConfig := *commonConfig
+			poolConfig.StoragePrefix = &prefix
+			return getInternalVolumeNameCommon(&poolConfig, name)
+		}
+	}
+	return getInternalVolumeNameCommon(commonConfig, name)
+}
+
+// getStoragePrefixes returns the distinct storage prefixes of a backend and its virtual pools, longest
+// first, so that a volume name is matched with the most specific prefix it starts with.
+func getStoragePrefixes(commonConfig *drivers.CommonStorageDriverConfig, virtualPools map[string]*storage.Pool) []string {
+
+	prefixes := []string{*commonConfig.StoragePrefix}
+	for _, pool := range virtualPools {
+		if prefix, ok := pool.InternalAttributes[StoragePrefix]; ok && !utils.StringInSlice(prefix, prefixes) {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+
+	sort.Slice(prefixes, func(i, j int) bool {
+		if len(prefixes[i]) != len(prefixes[j]) {
+			return len(prefixes[i]) > len(prefixes[j])
+		}
+		return prefixes[i] < prefixes[j]
+	})
+	return prefixes
+}
+
+// getStoragePrefixesToList returns the storage prefixes whose volumes must be listed to discover all of
+// a backend's volumes.  A prefix starting with another prefix is left out, since listing the shorter
+// prefix finds its volumes too.
+func getStoragePrefixesToList(prefixes []string) []string {
+
+	toList := make([]string, 0, len(prefixes))
+	for _, prefix := range prefixes {
+		covered := false
+		for _, other := range prefixes {
+			if other != prefix && strings.HasPrefix(prefix, other) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			toList = append(toList, prefix)
+		}
+	}
+	return toList
+}
+
+// trimStoragePrefix returns a volume's name without the first of the storage prefixes it starts with.
+func trimStoragePrefix(internalName string, prefixes []string) string {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(internalName, prefix) {
+			return internalName[len(prefix):]
+		}
+	}
+	return internalName
+}
+
+// storagePrefixesRemoved returns true if a backend update removes any storage prefix, leaving the
+// volumes named with it undiscoverable.
+func storagePrefixesRemoved(prefixes, origPrefixes []string) bool {
+	for _, prefix := range origPrefixes {
+		if !utils.StringInSlice(prefix, prefixes) {
+			return true
+		}
+	}
+	return false
+}
+
 func getExternalConfig(config drivers.OntapStorageDriverConfig) interface{} {
 
 	// Clone the config so we don't risk altering the original
@@ -2545,6 +3112,10 @@ func getExternalConfig(config drivers.OntapStorageDriverConfig) interface{} {
 	drivers.SanitizeCommonStorageDriverConfig(cloneConfig.CommonStorageDriverConfig)
 	cloneConfig.Username = "" // redact the username
 	cloneConfig.Password = "" // redact the password
+	if cloneConfig.AWSConfig != nil {
+		cloneConfig.AWSConfig.APIKey = ""    // redact the AWS API key
+		cloneConfig.AWSConfig.SecretKey = "" // redact the AWS secret key
+	}
 	return cloneConfig
 }
 
@@ -2614,3 +3185,106 @@ func UnmountAndOfflineVolume(API *api.Client, name string) (bool, error) {
 
 	return true, nil
 }
+
+// getOntapVolumeUsage returns the space and inodes consumed by a Flexvol.
+// getOntapVolumeCondition reports a Flexvol that isn't online as abnormal.
+func getOntapVolumeCondition(name string, client *api.Client) (*storage.VolumeCondition, error) {
+
+	state, err := client.VolumeGetState(name)
+	if err != nil {
+		return nil, fmt.Errorf("error reading volume %s; %v", name, err)
+	}
+	if state != "online" {
+		return &storage.VolumeCondition{
+			Abnormal: true,
+			Message:  fmt.Sprintf("volume %s is %s", name, state),
+		}, nil
+	}
+	return &storage.VolumeCondition{}, nil
+}
+
+// getOntapJunctionCondition reports a NAS volume that isn't mounted in its SVM's namespace as abnormal,
+// since clients can't reach a volume without a junction path.
+func getOntapJunctionCondition(name string, volAttrs *azgo.VolumeAttributesType) *storage.VolumeCondition {
+
+	if volAttrs.VolumeIdAttributesPtr == nil || volAttrs.VolumeIdAttributesPtr.JunctionPathPtr == nil ||
+		volAttrs.VolumeIdAttributesPtr.JunctionPath() == "" {
+		return &storage.VolumeCondition{
+			Abnormal: true,
+			Message:  fmt.Sprintf("volume %s has no junction path", name),
+		}
+	}
+	return &storage.VolumeCondition{}
+}
+
+// getOntapLUNCondition reports a Flexvol that isn't online, an offline LUN, or a LUN that should be
+// mapped but isn't, as abnormal.
+func getOntapLUNCondition(
+	volConfig *storage.VolumeConfig, flexvol, lunPath string, client *api.Client,
+) (*storage.VolumeCondition, error) {
+
+	condition, err := getOntapVolumeCondition(flexvol, client)
+	if err != nil || condition.Abnormal {
+		return condition, err
+	}
+
+	lunInfo, err := client.LunGet(lunPath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading LUN %s; %v", lunPath, err)
+	}
+	if lunInfo.OnlinePtr != nil && !lunInfo.Online() {
+		return &storage.VolumeCondition{
+			Abnormal: true,
+			Message:  fmt.Sprintf("LUN %s is offline", lunPath),
+		}, nil
+	}
+	// LUNs are mapped when created, except for Docker, which maps them when they are attached
+	if volConfig.AccessInfo.IscsiIgroup != "" && lunInfo.MappedPtr != nil && !lunInfo.Mapped() {
+		return &storage.VolumeCondition{
+			Abnormal: true,
+			Message:  fmt.Sprintf("LUN %s is not mapped to igroup %s", lunPath, volConfig.AccessInfo.IscsiIgroup),
+		}, nil
+	}
+	return &storage.VolumeCondition{}, nil
+}
+
+func getOntapVolumeUsage(name string, client *api.Client) (*storage.VolumeUsage, error) {
+
+	volAttrs, err := client.VolumeGet(name)
+	if err != nil {
+		return nil, fmt.Errorf("error reading volume %s; %v", name, err)
+	}
+	if volAttrs.VolumeSpaceAttributesPtr == nil {
+		return nil, fmt.Errorf("space attributes of volume %s not found", name)
+	}
+
+	usage := &storage.VolumeUsage{}
+
+	spaceAttrs := volAttrs.VolumeSpaceAttributesPtr
+	if spaceAttrs.SizeTotalPtr != nil {
+		usage.TotalBytes = uint64(spaceAttrs.SizeTotal())
+	}
+	if spaceAttrs.SizeUsedPtr != nil {
+		usage.UsedBytes = uint64(spaceAttrs.SizeUsed())
+	}
+	if spaceAttrs.SizeAvailablePtr != nil {
+		usage.AvailableBytes = uint64(spaceAttrs.SizeAvailable())
+	}
+	if spaceAttrs.SizeUsedBySnapshotsPtr != nil {
+		usage.SnapshotBytes = uint64(spaceAttrs.SizeUsedBySnapshots())
+	}
+	if sisAttrs := volAttrs.VolumeSisAttributesPtr; sisAttrs != nil && sisAttrs.TotalSpaceSavedPtr != nil {
+		usage.SavedBytes = uint64(sisAttrs.TotalSpaceSaved())
+	}
+
+	if inodeAttrs := volAttrs.VolumeInodeAttributesPtr; inodeAttrs != nil {
+		if inodeAttrs.FilesTotalPtr != nil {
+			usage.TotalInodes = uint64(inodeAttrs.FilesTotal())
+		}
+		if inodeAttrs.FilesUsedPtr != nil {
+			usage.UsedInodes = uint64(inodeAttrs.FilesUsed())
+		}
+	}
+
+	return usage, nil
+}
@@ -5,6 +5,8 @@ package ontap
 import (
 	"testing"
 
+	"github.com/netapp/trident/storage"
+	sa "github.com/netapp/trident/storage_attribute"
 	drivers "github.com/netapp/trident/storage_drivers"
 	"github.com/netapp/trident/storage_drivers/ontap/api/azgo"
 	"github.com/stretchr/testify/assert"
@@ -181,4 +183,128 @@ func Test_randomChapString16(t *testing.T) {
 		// make sure nothing invalid is left in the trimmed string
 		assert.Equal(t, 0, len(trimmed))
 	}
-}
\ No newline at end of file
+}
+func TestIsFlexClone(t *testing.T) {
+	volume := azgo.NewVolumeAttributesType()
+	assert.False(t, isFlexClone(volume), "a volume without clone attributes")
+
+	volume.SetVolumeCloneAttributes(*azgo.NewVolumeCloneAttributesType())
+	assert.False(t, isFlexClone(volume), "a volume split from its parent")
+
+	parentAttrs := azgo.NewVolumeCloneParentAttributesType().SetName("source")
+	volume.SetVolumeCloneAttributes(*azgo.NewVolumeCloneAttributesType().SetVolumeCloneParentAttributes(*parentAttrs))
+	assert.True(t, isFlexClone(volume), "a FlexClone")
+}
+
+func TestGetSMBPath(t *testing.T) {
+	assert.Equal(t, `\\10.0.0.1\trident_pvc_1`, getSMBPath("10.0.0.1", "trident_pvc_1"))
+}
+
+func TestGetPoolsForCreatePinnedAggregate(t *testing.T) {
+
+	backend := &storage.Backend{Name: "ontapnas"}
+	physicalPools := map[string]*storage.Pool{
+		"aggr1": storage.NewStoragePool(backend, "aggr1"),
+		"aggr2": storage.NewStoragePool(backend, "aggr2"),
+	}
+	virtualPool := storage.NewStoragePool(backend, "ontapnas_pool_0")
+	virtualPools := map[string]*storage.Pool{virtualPool.Name: virtualPool}
+
+	volConfig := &storage.VolumeConfig{InternalName: "vol1"}
+	pools, err := getPoolsForCreate(volConfig, virtualPool, map[string]sa.Request{}, physicalPools, virtualPools)
+	assert.NoError(t, err)
+	assert.Len(t, pools, 2)
+
+	// A virtual pool's volume is only placed in the aggregate it is pinned to
+	volConfig.Pool = "aggr2"
+	pools, err = getPoolsForCreate(volConfig, virtualPool, map[string]sa.Request{}, physicalPools, virtualPools)
+	if assert.NoError(t, err) && assert.Len(t, pools, 1) {
+		assert.Equal(t, "aggr2", pools[0].Name)
+	}
+
+	volConfig.Pool = "aggr3"
+	_, err = getPoolsForCreate(volConfig, virtualPool, map[string]sa.Request{}, physicalPools, virtualPools)
+	assert.Error(t, err)
+}
+
+func TestGetSnapshotComment(t *testing.T) {
+
+	assert.Equal(t, "", getSnapshotComment(&storage.SnapshotConfig{SnapmirrorLabel: "daily"}))
+	assert.Equal(t, "retention=168h,consistencyType=application", getSnapshotComment(&storage.SnapshotConfig{
+		Retention:       "168h",
+		ConsistencyType: storage.SnapshotConsistencyApplication,
+	}))
+}
+
+func TestGetOntapJunctionCondition(t *testing.T) {
+
+	volAttrs := &azgo.VolumeAttributesType{}
+	condition := getOntapJunctionCondition("vol1", volAttrs)
+	assert.True(t, condition.Abnormal)
+	assert.Equal(t, "volume vol1 has no junction path", condition.Message)
+
+	volAttrs.SetVolumeIdAttributes(*azgo.NewVolumeIdAttributesType().SetJunctionPath(""))
+	assert.True(t, getOntapJunctionCondition("vol1", volAttrs).Abnormal)
+
+	volAttrs.SetVolumeIdAttributes(*azgo.NewVolumeIdAttributesType().SetJunctionPath("/vol1"))
+	assert.False(t, getOntapJunctionCondition("vol1", volAttrs).Abnormal)
+}
+
+func TestPoolStoragePrefixes(t *testing.T) {
+
+	config := newTestOntapSANConfig()
+	goldPool := storage.NewStoragePool(nil, "ontapsan_pool_0")
+	goldPool.InternalAttributes[StoragePrefix] = "test_gold_"
+	bronzePool := storage.NewStoragePool(nil, "ontapsan_pool_1")
+	bronzePool.InternalAttributes[StoragePrefix] = "bronze_"
+	defaultPool := storage.NewStoragePool(nil, "ontapsan_pool_2")
+	virtualPools := map[string]*storage.Pool{
+		goldPool.Name:    goldPool,
+		bronzePool.Name:  bronzePool,
+		defaultPool.Name: defaultPool,
+	}
+
+	assert.Equal(t, "test_gold_pvc_1", getPoolInternalVolumeName(config.CommonStorageDriverConfig, goldPool, "pvc-1"))
+	assert.Equal(t, "test_pvc_1", getPoolInternalVolumeName(config.CommonStorageDriverConfig, defaultPool, "pvc-1"))
+	assert.Equal(t, "test_pvc_1", getPoolInternalVolumeName(config.CommonStorageDriverConfig, nil, "pvc-1"))
+
+	prefixes := getStoragePrefixes(config.CommonStorageDriverConfig, virtualPools)
+	assert.Equal(t, []string{"test_gold_", "bronze_", "test_"}, prefixes)
+
+	// The gold pool's volumes are found by listing the backend's prefix
+	assert.ElementsMatch(t, []string{"bronze_", "test_"}, getStoragePrefixesToList(prefixes))
+
+	assert.Equal(t, "pvc_1", trimStoragePrefix("test_gold_pvc_1", prefixes))
+	assert.Equal(t, "pvc_1", trimStoragePrefix("bronze_pvc_1", prefixes))
+	assert.Equal(t, "pvc_1", trimStoragePrefix("test_pvc_1", prefixes))
+	assert.Equal(t, "other_vol", trimStoragePrefix("other_vol", prefixes))
+
+	// Adding a pool prefix is harmless, but removing one hides its volumes
+	assert.False(t, storagePrefixesRemoved(prefixes, []string{"bronze_", "test_"}))
+	assert.True(t, storagePrefixesRemoved([]string{"test_"}, prefixes))
+}
+
+func TestValidateStoragePoolsStoragePrefix(t *testing.T) {
+
+	newPool := func(prefix string) map[string]*storage.Pool {
+		pool := storage.NewStoragePool(nil, "pool_0")
+		pool.InternalAttributes = map[string]string{
+			SpaceReserve:    "none",
+			SnapshotPolicy:  "none",
+			Encryption:      "false",
+			SnapshotDir:     "false",
+			SecurityStyle:   "unix",
+			ExportPolicy:    "default",
+			UnixPermissions: "777",
+			SplitOnClone:    "false",
+			Size:            "1G",
+			StoragePrefix:   prefix,
+		}
+		return map[string]*storage.Pool{pool.Name: pool}
+	}
+
+	assert.NoError(t, ValidateStoragePools(nil, newPool("gold_"), drivers.OntapNASStorageDriverName))
+	assert.Error(t, ValidateStoragePools(nil, newPool("gold-"), drivers.OntapNASStorageDriverName))
+	assert.Error(t, ValidateStoragePools(nil, newPool("1gold"), drivers.OntapNASStorageDriverName))
+	assert.Error(t, ValidateStoragePools(nil, newPool("gold_"), drivers.OntapNASQtreeStorageDriverName))
+}
@@ -0,0 +1,374 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package ontap
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netapp/trident/storage"
+	sa "github.com/netapp/trident/storage_attribute"
+	drivers "github.com/netapp/trident/storage_drivers"
+	"github.com/netapp/trident/storage_drivers/ontap/awsapi"
+)
+
+const (
+	// FSxAdminUsername is the cluster-scoped administrator of an FSx for NetApp ONTAP file system.
+	// All other users, including vsadmin, are scoped to an SVM.
+	FSxAdminUsername = "fsxadmin"
+
+	fsxSecretUsernameKey = "username"
+	fsxSecretPasswordKey = "password"
+
+	// Tags applied to FSx backups so they may be matched to Trident snapshots
+	fsxBackupSnapshotTag = "trident.netapp.io/snapshot"
+	fsxBackupVolumeTag   = "trident.netapp.io/volume"
+)
+
+// isFSx returns true if the backend config refers to an Amazon FSx for NetApp ONTAP file system.
+func isFSx(config *drivers.OntapStorageDriverConfig) bool {
+	return config.AWSConfig != nil && config.AWSConfig.FSxFilesystemID != ""
+}
+
+// isFSxAdmin returns true if the backend uses the cluster-scoped FSx administrator account.
+func isFSxAdmin(config *drivers.OntapStorageDriverConfig) bool {
+	return config.Username == FSxAdminUsername
+}
+
+// InitializeFSxConfig fills in the parts of an ONTAP backend config that may be discovered from AWS when the
+// backend refers to an FSx for NetApp ONTAP file system.  Credentials are read from Secrets Manager if a
+// secret ARN is configured, and the SVM and management/data endpoints are read from the FSx API unless they
+// were set explicitly.  It also rejects settings that cannot work given FSx's restricted administrator roles.
+func InitializeFSxConfig(config *drivers.OntapStorageDriverConfig) error {
+
+	if config.DebugTraceFlags["method"] {
+		fields := log.Fields{"Method": "InitializeFSxConfig", "Type": "ontap_fsx"}
+		log.WithFields(fields).Debug(">>>> InitializeFSxConfig")
+		defer log.WithFields(fields).Debug("<<<< InitializeFSxConfig")
+	}
+
+	if !isFSx(config) {
+		if config.AWSConfig != nil {
+			return errors.New("fsxFilesystemID must be specified in the aws section of the config")
+		}
+		return nil
+	}
+
+	client, err := newFSxClient(config)
+	if err != nil {
+		return err
+	}
+
+	return initializeFSxConfig(config, client)
+}
+
+// newFSxClient returns an AWS API client using the credentials in the backend config.
+func newFSxClient(config *drivers.OntapStorageDriverConfig) (*awsapi.Client, error) {
+
+	client, err := awsapi.NewClient(awsapi.ClientConfig{
+		APIRegion:       config.AWSConfig.APIRegion,
+		APIKey:          config.AWSConfig.APIKey,
+		SecretKey:       config.AWSConfig.SecretKey,
+		DebugTraceFlags: config.DebugTraceFlags,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not create AWS API client; %v", err)
+	}
+	return client, nil
+}
+
+func initializeFSxConfig(config *drivers.OntapStorageDriverConfig, client *awsapi.Client) error {
+
+	fsxID := config.AWSConfig.FSxFilesystemID
+
+	// Read the ONTAP credentials from Secrets Manager
+	if config.AWSConfig.CredentialsSecretARN != "" {
+		secret, err := client.GetSecret(config.AWSConfig.CredentialsSecretARN)
+		if err != nil {
+			return fmt.Errorf("could not read ONTAP credentials from AWS Secrets Manager; %v", err)
+		}
+		username, password := secret[fsxSecretUsernameKey], secret[fsxSecretPasswordKey]
+		if username == "" || password == "" {
+			return fmt.Errorf("secret %s must contain the keys '%s' and '%s'",
+				config.AWSConfig.CredentialsSecretARN, fsxSecretUsernameKey, fsxSecretPasswordKey)
+		}
+		config.Username = username
+		config.Password = password
+	}
+
+	fileSystem, err := client.GetFileSystem(fsxID)
+	if err != nil {
+		return fmt.Errorf("could not read FSx file system %s; %v", fsxID, err)
+	}
+	if fileSystem.Lifecycle != awsapi.LifecycleAvailable {
+		return fmt.Errorf("FSx file system %s is not available; lifecycle is %s", fsxID, fileSystem.Lifecycle)
+	}
+
+	svm, err := client.GetSVM(fsxID, config.SVM)
+	if err != nil {
+		return err
+	}
+	if svm.Lifecycle != "" && svm.Lifecycle != awsapi.LifecycleCreated {
+		return fmt.Errorf("FSx SVM %s is not ready; lifecycle is %s", svm.Name, svm.Lifecycle)
+	}
+	config.SVM = svm.Name
+
+	// The fsxadmin user must connect to the file system management endpoint, while SVM-scoped
+	// users such as vsadmin must connect to the SVM management endpoint.
+	if config.ManagementLIF == "" {
+		var endpoint *awsapi.Endpoint
+		if isFSxAdmin(config) {
+			if fileSystem.OntapConfiguration != nil && fileSystem.OntapConfiguration.Endpoints != nil {
+				endpoint = fileSystem.OntapConfiguration.Endpoints.Management
+			}
+		} else if svm.Endpoints != nil {
+			endpoint = svm.Endpoints.Management
+		}
+		if endpoint == nil || len(endpoint.IPAddresses) == 0 {
+			return fmt.Errorf("could not discover a management endpoint for FSx file system %s", fsxID)
+		}
+		config.ManagementLIF = endpoint.IPAddresses[0]
+	}
+
+	switch config.StorageDriverName {
+	case drivers.OntapNASStorageDriverName, drivers.OntapNASQtreeStorageDriverName,
+		drivers.OntapNASFlexGroupStorageDriverName:
+		if config.DataLIF == "" && svm.Endpoints != nil && svm.Endpoints.Nfs != nil &&
+			len(svm.Endpoints.Nfs.IPAddresses) > 0 {
+			config.DataLIF = svm.Endpoints.Nfs.IPAddresses[0]
+		}
+	case drivers.OntapSANStorageDriverName, drivers.OntapSANEconomyStorageDriverName:
+		if svm.Endpoints == nil || svm.Endpoints.Iscsi == nil || len(svm.Endpoints.Iscsi.IPAddresses) == 0 {
+			return fmt.Errorf("FSx SVM %s has no iSCSI endpoints", svm.Name)
+		}
+		log.WithField("iscsiEndpoints", svm.Endpoints.Iscsi.IPAddresses).Debug("Discovered FSx iSCSI endpoints.")
+	}
+
+	// Aggregate space can only be read with cluster scope, which only fsxadmin has on FSx
+	if config.LimitAggregateUsage != "" && !isFSxAdmin(config) {
+		return fmt.Errorf("limitAggregateUsage requires the %s user on FSx for NetApp ONTAP", FSxAdminUsername)
+	}
+
+	log.WithFields(log.Fields{
+		"fsxFilesystemID": fsxID,
+		"svm":             config.SVM,
+		"managementLIF":   config.ManagementLIF,
+		"dataLIF":         config.DataLIF,
+		"username":        config.Username,
+	}).Info("Discovered FSx for NetApp ONTAP configuration.")
+
+	return nil
+}
+
+// applyFSxAggrAttributes sets the media type of any aggregate whose attributes could not be read.
+// SVM-scoped users on FSx cannot read aggregate details, but FSx always uses SSDs for its primary tier.
+func applyFSxAggrAttributes(poolsAttributeMap map[string]map[string]sa.Offer) {
+	for _, attrs := range poolsAttributeMap {
+		if _, ok := attrs[sa.Media]; !ok {
+			for attrName, attr := range ontapPerformanceClasses[ontapSSD] {
+				attrs[attrName] = attr
+			}
+		}
+	}
+}
+
+// GetFSxBackup returns the FSx backup matching a Trident snapshot of type backup, or nil if it doesn't exist.
+func GetFSxBackup(
+	snapConfig *storage.SnapshotConfig, config *drivers.OntapStorageDriverConfig, sizeGetter func(string) (int, error),
+) (*storage.Snapshot, error) {
+
+	if config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":       "GetFSxBackup",
+			"Type":         "ontap_fsx",
+			"snapshotName": snapConfig.Name,
+			"volumeName":   snapConfig.VolumeInternalName,
+		}
+		log.WithFields(fields).Debug(">>>> GetFSxBackup")
+		defer log.WithFields(fields).Debug("<<<< GetFSxBackup")
+	}
+
+	client, err := newFSxClient(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return getFSxBackup(snapConfig, config, client, sizeGetter)
+}
+
+func getFSxBackup(
+	snapConfig *storage.SnapshotConfig, config *drivers.OntapStorageDriverConfig, client *awsapi.Client,
+	sizeGetter func(string) (int, error),
+) (*storage.Snapshot, error) {
+
+	volumeID, err := getFSxVolumeID(snapConfig.VolumeInternalName, config, client)
+	if err != nil {
+		return nil, err
+	}
+
+	backups, err := client.GetBackups(volumeID)
+	if err != nil {
+		return nil, fmt.Errorf("error enumerating FSx backups: %v", err)
+	}
+
+	for _, backup := range backups {
+		if backup.Tag(fsxBackupSnapshotTag) != snapConfig.Name || backup.Lifecycle == awsapi.BackupLifecycleDeleted {
+			continue
+		}
+
+		log.WithFields(log.Fields{
+			"snapshotName": snapConfig.Name,
+			"volumeName":   snapConfig.VolumeInternalName,
+			"backupID":     backup.BackupID,
+			"lifecycle":    backup.Lifecycle,
+		}).Debug("Found FSx backup.")
+
+		if backup.Lifecycle == awsapi.BackupLifecycleFailed && backup.FailureDetails != nil {
+			log.WithField("backupID", backup.BackupID).Warningf("FSx backup failed: %s",
+				backup.FailureDetails.Message)
+		}
+
+		return fsxBackupToSnapshot(&backup, snapConfig, sizeGetter)
+	}
+
+	log.WithFields(log.Fields{
+		"snapshotName": snapConfig.Name,
+		"volumeName":   snapConfig.VolumeInternalName,
+	}).Warning("FSx backup not found.")
+
+	return nil, nil
+}
+
+// CreateFSxBackup starts an AWS-native backup of the volume underlying a Trident snapshot of type backup.
+func CreateFSxBackup(
+	snapConfig *storage.SnapshotConfig, config *drivers.OntapStorageDriverConfig, sizeGetter func(string) (int, error),
+) (*storage.Snapshot, error) {
+
+	if config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":       "CreateFSxBackup",
+			"Type":         "ontap_fsx",
+			"snapshotName": snapConfig.Name,
+			"volumeName":   snapConfig.VolumeInternalName,
+		}
+		log.WithFields(fields).Debug(">>>> CreateFSxBackup")
+		defer log.WithFields(fields).Debug("<<<< CreateFSxBackup")
+	}
+
+	client, err := newFSxClient(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return createFSxBackup(snapConfig, config, client, sizeGetter)
+}
+
+func createFSxBackup(
+	snapConfig *storage.SnapshotConfig, config *drivers.OntapStorageDriverConfig, client *awsapi.Client,
+	sizeGetter func(string) (int, error),
+) (*storage.Snapshot, error) {
+
+	volumeID, err := getFSxVolumeID(snapConfig.VolumeInternalName, config, client)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := map[string]string{
+		fsxBackupSnapshotTag: snapConfig.Name,
+		fsxBackupVolumeTag:   snapConfig.VolumeName,
+	}
+
+	// Derive the request token from the volume and snapshot so that retries don't create duplicate backups
+	token := sha256.Sum256([]byte(volumeID + "/" + snapConfig.Name))
+
+	backup, err := client.CreateBackup(volumeID, hex.EncodeToString(token[:])[:32], tags)
+	if err != nil {
+		return nil, fmt.Errorf("could not create FSx backup: %v", err)
+	}
+
+	return fsxBackupToSnapshot(backup, snapConfig, sizeGetter)
+}
+
+// DeleteFSxBackup deletes the FSx backup matching a Trident snapshot of type backup.
+func DeleteFSxBackup(snapConfig *storage.SnapshotConfig, config *drivers.OntapStorageDriverConfig) error {
+
+	if config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":       "DeleteFSxBackup",
+			"Type":         "ontap_fsx",
+			"snapshotName": snapConfig.Name,
+			"backupID":     snapConfig.InternalName,
+		}
+		log.WithFields(fields).Debug(">>>> DeleteFSxBackup")
+		defer log.WithFields(fields).Debug("<<<< DeleteFSxBackup")
+	}
+
+	client, err := newFSxClient(config)
+	if err != nil {
+		return err
+	}
+
+	if err = client.DeleteBackup(snapConfig.InternalName); err != nil && !awsapi.IsNotFoundError(err) {
+		return fmt.Errorf("error deleting FSx backup: %v", err)
+	}
+
+	log.WithField("backupID", snapConfig.InternalName).Debug("Deleted FSx backup.")
+	return nil
+}
+
+// getFSxVolumeID returns the FSx ID of the named ONTAP volume in the backend's SVM.
+func getFSxVolumeID(
+	internalVolName string, config *drivers.OntapStorageDriverConfig, client *awsapi.Client,
+) (string, error) {
+
+	svm, err := client.GetSVM(config.AWSConfig.FSxFilesystemID, config.SVM)
+	if err != nil {
+		return "", err
+	}
+
+	volume, err := client.GetVolume(config.AWSConfig.FSxFilesystemID, svm.StorageVirtualMachineID, internalVolName)
+	if err != nil {
+		return "", err
+	}
+
+	return volume.VolumeID, nil
+}
+
+// fsxBackupToSnapshot converts an FSx backup to a Trident snapshot.  The backup ID is recorded as
+// the snapshot's internal name, so later operations may find the backup directly.  A backup is only
+// online once AWS reports it available.
+func fsxBackupToSnapshot(
+	backup *awsapi.Backup, snapConfig *storage.SnapshotConfig, sizeGetter func(string) (int, error),
+) (*storage.Snapshot, error) {
+
+	size, err := sizeGetter(snapConfig.VolumeInternalName)
+	if err != nil {
+		return nil, fmt.Errorf("error reading volume size: %v", err)
+	}
+
+	snapConfig.InternalName = backup.BackupID
+
+	return &storage.Snapshot{
+		Config:    snapConfig,
+		Created:   backup.Created().Format(storage.SnapshotTimestampFormat),
+		SizeBytes: int64(size),
+		State:     fsxBackupState(backup),
+	}, nil
+}
+
+// fsxBackupState returns the Trident snapshot state matching the lifecycle of an FSx backup.
+func fsxBackupState(backup *awsapi.Backup) storage.SnapshotState {
+	switch backup.Lifecycle {
+	case awsapi.BackupLifecycleAvailable:
+		return storage.SnapshotStateOnline
+	case awsapi.BackupLifecycleFailed:
+		return storage.SnapshotStateFailed
+	default:
+		// CREATING, TRANSFERRING and PENDING
+		return storage.SnapshotStateCreating
+	}
+}
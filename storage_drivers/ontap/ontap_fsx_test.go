@@ -0,0 +1,198 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package ontap
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netapp/trident/storage"
+	sa "github.com/netapp/trident/storage_attribute"
+	drivers "github.com/netapp/trident/storage_drivers"
+	"github.com/netapp/trident/storage_drivers/ontap/awsapi"
+)
+
+func newFSxTestServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Header.Get("X-Amz-Target") {
+		case "secretsmanager.GetSecretValue":
+			_, _ = w.Write([]byte(`{"SecretString": "{\"username\":\"vsadmin\",\"password\":\"pw\"}"}`))
+		case "AWSSimbaAPIService_v20180301.DescribeFileSystems":
+			_, _ = w.Write([]byte(`{"FileSystems": [{"FileSystemId": "fs-1", "FileSystemType": "ONTAP",
+				"Lifecycle": "AVAILABLE", "OntapConfiguration": {"Endpoints": {
+				"Management": {"IpAddresses": ["10.0.0.1"]}}}}]}`))
+		case "AWSSimbaAPIService_v20180301.DescribeStorageVirtualMachines":
+			_, _ = w.Write([]byte(`{"StorageVirtualMachines": [{"Name": "svm1", "Lifecycle": "CREATED",
+				"StorageVirtualMachineId": "svm-1",
+				"Endpoints": {"Management": {"IpAddresses": ["10.0.0.2"]}, "Nfs": {"IpAddresses": ["10.0.0.3"]},
+				"Iscsi": {"IpAddresses": ["10.0.0.4", "10.0.0.5"]}}}]}`))
+		case "AWSSimbaAPIService_v20180301.DescribeVolumes":
+			_, _ = w.Write([]byte(`{"Volumes": [{"VolumeId": "fsvol-1", "Name": "trident_pvc_1"}]}`))
+		case "AWSSimbaAPIService_v20180301.DescribeBackups":
+			_, _ = w.Write([]byte(`{"Backups": [
+				{"BackupId": "backup-0", "Lifecycle": "DELETED", "CreationTime": 1600000000,
+				"Tags": [{"Key": "trident.netapp.io/snapshot", "Value": "snap1"}]},
+				{"BackupId": "backup-1", "Lifecycle": "AVAILABLE", "CreationTime": 1600000000,
+				"Tags": [{"Key": "trident.netapp.io/snapshot", "Value": "snap1"}]},
+				{"BackupId": "backup-2", "Lifecycle": "FAILED", "CreationTime": 1600000000,
+				"Tags": [{"Key": "trident.netapp.io/snapshot", "Value": "snap2"}]}]}`))
+		case "AWSSimbaAPIService_v20180301.CreateBackup":
+			_, _ = w.Write([]byte(`{"Backup": {"BackupId": "backup-3", "Lifecycle": "CREATING",
+				"CreationTime": 1600000000}}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+}
+
+func newFSxTestConfig(driverName string) *drivers.OntapStorageDriverConfig {
+	return &drivers.OntapStorageDriverConfig{
+		CommonStorageDriverConfig: &drivers.CommonStorageDriverConfig{StorageDriverName: driverName},
+		AWSConfig: &drivers.AWSConfig{
+			FSxFilesystemID:      "fs-1",
+			CredentialsSecretARN: "arn:aws:secretsmanager:us-east-1:0:secret:trident",
+		},
+	}
+}
+
+func newFSxTestClient(t *testing.T, server *httptest.Server) *awsapi.Client {
+	client, err := awsapi.NewClient(awsapi.ClientConfig{
+		APIRegion: "us-east-1", APIKey: "key", SecretKey: "secret", EndpointURL: server.URL + "/",
+	})
+	if err != nil {
+		t.Fatalf("Could not create AWS client; %v", err)
+	}
+	return client
+}
+
+func TestInitializeFSxConfig(t *testing.T) {
+
+	server := newFSxTestServer()
+	defer server.Close()
+	client := newFSxTestClient(t, server)
+
+	// NAS with an SVM-scoped user discovers the SVM endpoints
+	config := newFSxTestConfig(drivers.OntapNASStorageDriverName)
+	assert.NoError(t, initializeFSxConfig(config, client))
+	assert.Equal(t, "vsadmin", config.Username)
+	assert.Equal(t, "pw", config.Password)
+	assert.Equal(t, "svm1", config.SVM)
+	assert.Equal(t, "10.0.0.2", config.ManagementLIF)
+	assert.Equal(t, "10.0.0.3", config.DataLIF)
+
+	// fsxadmin uses the file system management endpoint
+	config = newFSxTestConfig(drivers.OntapSANStorageDriverName)
+	config.AWSConfig.CredentialsSecretARN = ""
+	config.Username = FSxAdminUsername
+	config.LimitAggregateUsage = "80%"
+	assert.NoError(t, initializeFSxConfig(config, client))
+	assert.Equal(t, "10.0.0.1", config.ManagementLIF)
+	assert.Equal(t, "", config.DataLIF)
+
+	// Explicit settings are not overwritten
+	config = newFSxTestConfig(drivers.OntapNASStorageDriverName)
+	config.ManagementLIF = "1.1.1.1"
+	config.DataLIF = "2.2.2.2"
+	assert.NoError(t, initializeFSxConfig(config, client))
+	assert.Equal(t, "1.1.1.1", config.ManagementLIF)
+	assert.Equal(t, "2.2.2.2", config.DataLIF)
+
+	// Aggregate limits need cluster scope
+	config = newFSxTestConfig(drivers.OntapNASStorageDriverName)
+	config.LimitAggregateUsage = "80%"
+	assert.Error(t, initializeFSxConfig(config, client))
+
+	// Unknown SVM
+	config = newFSxTestConfig(drivers.OntapNASStorageDriverName)
+	config.SVM = "svm2"
+	assert.Error(t, initializeFSxConfig(config, client))
+}
+
+func TestInitializeFSxConfigNotFSx(t *testing.T) {
+
+	config := &drivers.OntapStorageDriverConfig{CommonStorageDriverConfig: &drivers.CommonStorageDriverConfig{}}
+	assert.NoError(t, InitializeFSxConfig(config))
+
+	config.AWSConfig = &drivers.AWSConfig{}
+	assert.Error(t, InitializeFSxConfig(config), "expected an error when the file system ID is missing")
+}
+
+func TestApplyFSxAggrAttributes(t *testing.T) {
+
+	attrs := map[string]map[string]sa.Offer{
+		"aggr1": {},
+		"aggr2": {sa.Media: sa.NewStringOffer(sa.HDD)},
+	}
+
+	applyFSxAggrAttributes(attrs)
+
+	assert.Equal(t, sa.NewStringOffer(sa.SSD), attrs["aggr1"][sa.Media])
+	assert.Equal(t, sa.NewStringOffer(sa.HDD), attrs["aggr2"][sa.Media])
+}
+
+func TestFSxBackups(t *testing.T) {
+
+	server := newFSxTestServer()
+	defer server.Close()
+	client := newFSxTestClient(t, server)
+
+	config := newFSxTestConfig(drivers.OntapNASStorageDriverName)
+	config.SVM = "svm1"
+	sizeGetter := func(string) (int, error) { return 1073741824, nil }
+
+	newSnapConfig := func(name string) *storage.SnapshotConfig {
+		return &storage.SnapshotConfig{
+			Name:               name,
+			InternalName:       name,
+			VolumeName:         "pvc-1",
+			VolumeInternalName: "trident_pvc_1",
+			Type:               storage.SnapshotTypeBackup,
+		}
+	}
+
+	// An available backup is found by its tag, skipping deleted backups
+	snapConfig := newSnapConfig("snap1")
+	snapshot, err := getFSxBackup(snapConfig, config, client, sizeGetter)
+	assert.NoError(t, err)
+	assert.NotNil(t, snapshot)
+	assert.Equal(t, "backup-1", snapshot.Config.InternalName)
+	assert.Equal(t, "2020-09-13T12:26:40Z", snapshot.Created)
+	assert.Equal(t, int64(1073741824), snapshot.SizeBytes)
+	assert.Equal(t, storage.SnapshotStateOnline, snapshot.State)
+
+	// A failed backup is reported as a failed snapshot
+	snapshot, err = getFSxBackup(newSnapConfig("snap2"), config, client, sizeGetter)
+	assert.NoError(t, err)
+	assert.NotNil(t, snapshot)
+	assert.Equal(t, storage.SnapshotStateFailed, snapshot.State)
+
+	// A missing backup is not an error
+	snapshot, err = getFSxBackup(newSnapConfig("snap3"), config, client, sizeGetter)
+	assert.NoError(t, err)
+	assert.Nil(t, snapshot)
+
+	// A new backup records the backup ID
+	snapConfig = newSnapConfig("snap3")
+	snapshot, err = createFSxBackup(snapConfig, config, client, sizeGetter)
+	assert.NoError(t, err)
+	assert.Equal(t, "backup-3", snapshot.Config.InternalName)
+	assert.Equal(t, storage.SnapshotStateCreating, snapshot.State)
+}
+
+func TestFSxBackupState(t *testing.T) {
+
+	states := map[string]storage.SnapshotState{
+		awsapi.BackupLifecycleCreating:     storage.SnapshotStateCreating,
+		awsapi.BackupLifecycleTransferring: storage.SnapshotStateCreating,
+		awsapi.BackupLifecyclePending:      storage.SnapshotStateCreating,
+		awsapi.BackupLifecycleAvailable:    storage.SnapshotStateOnline,
+		awsapi.BackupLifecycleFailed:       storage.SnapshotStateFailed,
+	}
+
+	for lifecycle, state := range states {
+		assert.Equal(t, state, fsxBackupState(&awsapi.Backup{Lifecycle: lifecycle}), lifecycle)
+	}
+}
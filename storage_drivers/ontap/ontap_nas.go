@@ -3,6 +3,7 @@
 package ontap
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"strings"
@@ -148,9 +149,11 @@ func (d *NASStorageDriver) validate() error {
 
 // Create a volume with the specified options
 func (d *NASStorageDriver) Create(
-	volConfig *storage.VolumeConfig, storagePool *storage.Pool, volAttributes map[string]sa.Request,
+	ctx context.Context, volConfig *storage.VolumeConfig, storagePool *storage.Pool, volAttributes map[string]sa.Request,
 ) error {
 
+	client := d.API.WithContext(ctx)
+
 	name := volConfig.InternalName
 
 	if d.Config.DebugTraceFlags["method"] {
@@ -165,7 +168,7 @@ func (d *NASStorageDriver) Create(
 	}
 
 	// If the volume already exists, bail out
-	volExists, err := d.API.VolumeExists(name)
+	volExists, err := client.VolumeExists(name)
 	if err != nil {
 		return fmt.Errorf("error checking for existing volume: %v", err)
 	}
@@ -179,6 +182,9 @@ func (d *NASStorageDriver) Create(
 		return err
 	}
 
+	// Use the pool's mount options unless the volume or its storage class specified some
+	applyPoolMountOptions(volConfig, storagePool)
+
 	// Determine volume size in bytes
 	requestedSize, err := utils.ConvertSizeToBytes(volConfig.Size)
 	if err != nil {
@@ -232,7 +238,7 @@ func (d *NASStorageDriver) Create(
 	}
 
 	if tieringPolicy == "" {
-		tieringPolicy = d.API.TieringPolicyValue()
+		tieringPolicy = client.TieringPolicyValue()
 	}
 
 	if d.Config.AutoExportPolicy {
@@ -268,7 +274,7 @@ func (d *NASStorageDriver) Create(
 		}
 
 		// Create the volume
-		volCreateResponse, err := d.API.VolumeCreate(
+		volCreateResponse, err := client.VolumeCreate(
 			name, aggregate, size, spaceReserve, snapshotPolicy, unixPermissions,
 			exportPolicy, securityStyle, tieringPolicy, enableEncryption, snapshotReserveInt)
 
@@ -289,14 +295,19 @@ func (d *NASStorageDriver) Create(
 
 		// Disable '.snapshot' to allow official mysql container's chmod-in-init to work
 		if !enableSnapshotDir {
-			snapDirResponse, err := d.API.VolumeDisableSnapshotDirectoryAccess(name)
+			snapDirResponse, err := client.VolumeDisableSnapshotDirectoryAccess(name)
 			if err = api.GetError(snapDirResponse, err); err != nil {
 				return fmt.Errorf("error disabling snapshot directory access: %v", err)
 			}
 		}
 
+		// Set the ownership of the volume's root directory at provision time
+		if err = setRootOwnership(volConfig, name, client); err != nil {
+			return err
+		}
+
 		// Mount the volume at the specified junction
-		mountResponse, err := d.API.VolumeMount(name, "/"+name)
+		mountResponse, err := client.VolumeMount(name, "/"+name)
 		if err = api.GetError(mountResponse, err); err != nil {
 			return fmt.Errorf("error mounting volume to junction: %v", err)
 		}
@@ -309,7 +320,11 @@ func (d *NASStorageDriver) Create(
 }
 
 // Create a volume clone
-func (d *NASStorageDriver) CreateClone(volConfig *storage.VolumeConfig, storagePool *storage.Pool) error {
+func (d *NASStorageDriver) CreateClone(
+	ctx context.Context, volConfig *storage.VolumeConfig, storagePool *storage.Pool,
+) error {
+
+	client := d.API.WithContext(ctx)
 
 	name := volConfig.InternalName
 	source := volConfig.CloneSourceVolumeInternal
@@ -356,11 +371,16 @@ func (d *NASStorageDriver) CreateClone(volConfig *storage.VolumeConfig, storageP
 	}
 
 	log.WithField("splitOnClone", split).Debug("Creating volume clone.")
-	return CreateOntapClone(name, source, snapshot, split, &d.Config, d.API)
+	return CreateOntapClone(name, source, snapshot, split, &d.Config, client)
+}
+
+// CloneDependsOnSource reports whether a clone is a FlexClone that still shares storage with its source volume.
+func (d *NASStorageDriver) CloneDependsOnSource(volConfig *storage.VolumeConfig) (bool, error) {
+	return cloneDependsOnSource(volConfig, d.API)
 }
 
 // Destroy the volume
-func (d *NASStorageDriver) Destroy(name string) error {
+func (d *NASStorageDriver) Destroy(ctx context.Context, name string) error {
 
 	if d.Config.DebugTraceFlags["method"] {
 		fields := log.Fields{
@@ -372,6 +392,8 @@ func (d *NASStorageDriver) Destroy(name string) error {
 		defer log.WithFields(fields).Debug("<<<< Destroy")
 	}
 
+	client := d.API.WithContext(ctx)
+
 	// TODO: If this is the parent of one or more clones, those clones have to split from this
 	// volume before it can be deleted, which means separate copies of those volumes.
 	// If there are a lot of clones on this volume, that could seriously balloon the amount of
@@ -379,7 +401,11 @@ func (d *NASStorageDriver) Destroy(name string) error {
 	// user to keep the volume around until all of the clones are gone? If we do that, need a
 	// way to list the clones. Maybe volume inspect.
 
-	volDestroyResponse, err := d.API.VolumeDestroy(name, true)
+	if d.Config.NASType == drivers.NASTypeSMB {
+		deleteCIFSShare(client, name)
+	}
+
+	volDestroyResponse, err := client.VolumeDestroy(name, true)
 	if err != nil {
 		return fmt.Errorf("error destroying volume %v: %v", name, err)
 	}
@@ -396,7 +422,7 @@ func (d *NASStorageDriver) Destroy(name string) error {
 	return nil
 }
 
-func (d *NASStorageDriver) Import(volConfig *storage.VolumeConfig, originalName string) error {
+func (d *NASStorageDriver) Import(ctx context.Context, volConfig *storage.VolumeConfig, originalName string) error {
 
 	if d.Config.DebugTraceFlags["method"] {
 		fields := log.Fields{
@@ -410,6 +436,58 @@ func (d *NASStorageDriver) Import(volConfig *storage.VolumeConfig, originalName
 		defer log.WithFields(fields).Debug("<<<< Import")
 	}
 
+	client := d.API.WithContext(ctx)
+
+	if err := d.validateImport(volConfig, originalName); err != nil {
+		return err
+	}
+
+	// Rename the volume if Trident will manage its lifecycle
+	if !volConfig.ImportNotManaged {
+		renameResponse, err := client.VolumeRename(originalName, volConfig.InternalName)
+		if err = api.GetError(renameResponse, err); err != nil {
+			log.WithField("originalName", originalName).Errorf("Could not import volume, rename failed: %v", err)
+			return fmt.Errorf("volume %s rename failed: %v", originalName, err)
+		}
+	}
+
+	return nil
+}
+
+// ImportCheck runs the checks for importing a volume and returns the changes the import would make
+// to the volume, without making them.
+func (d *NASStorageDriver) ImportCheck(volConfig *storage.VolumeConfig, originalName string) ([]string, error) {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":       "ImportCheck",
+			"Type":         "NASStorageDriver",
+			"originalName": originalName,
+			"newName":      volConfig.InternalName,
+			"notManaged":   volConfig.ImportNotManaged,
+		}
+		log.WithFields(fields).Debug(">>>> ImportCheck")
+		defer log.WithFields(fields).Debug("<<<< ImportCheck")
+	}
+
+	if err := d.validateImport(volConfig, originalName); err != nil {
+		return nil, err
+	}
+
+	changes := make([]string, 0)
+	if !volConfig.ImportNotManaged {
+		if err := checkImportRename(originalName, volConfig.InternalName, d.API); err != nil {
+			return nil, err
+		}
+		changes = append(changes, fmt.Sprintf("rename volume %s to %s", originalName, volConfig.InternalName))
+	}
+
+	return changes, nil
+}
+
+// validateImport ensures a volume may be imported and sets the volume config's size from it.
+func (d *NASStorageDriver) validateImport(volConfig *storage.VolumeConfig, originalName string) error {
+
 	// Ensure the volume exists
 	flexvol, err := d.API.VolumeGet(originalName)
 	if err != nil {
@@ -434,15 +512,6 @@ func (d *NASStorageDriver) Import(volConfig *storage.VolumeConfig, originalName
 	}
 	volConfig.Size = strconv.FormatInt(int64(flexvol.VolumeSpaceAttributesPtr.Size()), 10)
 
-	// Rename the volume if Trident will manage its lifecycle
-	if !volConfig.ImportNotManaged {
-		renameResponse, err := d.API.VolumeRename(originalName, volConfig.InternalName)
-		if err = api.GetError(renameResponse, err); err != nil {
-			log.WithField("originalName", originalName).Errorf("Could not import volume, rename failed: %v", err)
-			return fmt.Errorf("volume %s rename failed: %v", originalName, err)
-		}
-	}
-
 	// Make sure we're not importing a volume without a junction path when not managed
 	if volConfig.ImportNotManaged {
 		if flexvol.VolumeIdAttributesPtr == nil {
@@ -481,7 +550,11 @@ func (d *NASStorageDriver) Rename(name string, newName string) error {
 // Publish the volume to the host specified in publishInfo.  This method may or may not be running on the host
 // where the volume will be mounted, so it should limit itself to updating access rules, initiator groups, etc.
 // that require some host identity (but not locality) as well as storage controller API access.
-func (d *NASStorageDriver) Publish(volConfig *storage.VolumeConfig, publishInfo *utils.VolumePublishInfo) error {
+func (d *NASStorageDriver) Publish(
+	ctx context.Context, volConfig *storage.VolumeConfig, publishInfo *utils.VolumePublishInfo,
+) error {
+
+	client := d.API.WithContext(ctx)
 
 	name := volConfig.InternalName
 
@@ -502,13 +575,18 @@ func (d *NASStorageDriver) Publish(volConfig *storage.VolumeConfig, publishInfo
 		mountOptions = volConfig.MountOptions
 	}
 
-	// Add fields needed by Attach
+	// Add fields needed by Attach.  SMB shares are secured by Active Directory rather than export policies.
+	if d.Config.NASType == drivers.NASTypeSMB {
+		publishInfo.SMBPath = getSMBPath(d.Config.DataLIF, name)
+		publishInfo.FilesystemType = drivers.NASTypeSMB
+		return nil
+	}
 	publishInfo.NfsPath = fmt.Sprintf("/%s", name)
 	publishInfo.NfsServerIP = d.Config.DataLIF
 	publishInfo.FilesystemType = "nfs"
 	publishInfo.MountOptions = mountOptions
 
-	return publishFlexVolShare(d.API, &d.Config, publishInfo, name)
+	return publishFlexVolShare(client, &d.Config, publishInfo, name)
 }
 
 // GetSnapshot gets a snapshot.  To distinguish between an API error reading the snapshot
@@ -546,7 +624,11 @@ func (d *NASStorageDriver) GetSnapshots(volConfig *storage.VolumeConfig) ([]*sto
 }
 
 // CreateSnapshot creates a snapshot for the given volume
-func (d *NASStorageDriver) CreateSnapshot(snapConfig *storage.SnapshotConfig) (*storage.Snapshot, error) {
+func (d *NASStorageDriver) CreateSnapshot(
+	ctx context.Context, snapConfig *storage.SnapshotConfig,
+) (*storage.Snapshot, error) {
+
+	client := d.API.WithContext(ctx)
 
 	internalSnapName := snapConfig.InternalName
 	internalVolName := snapConfig.VolumeInternalName
@@ -562,11 +644,28 @@ func (d *NASStorageDriver) CreateSnapshot(snapConfig *storage.SnapshotConfig) (*
 		defer log.WithFields(fields).Debug("<<<< CreateSnapshot")
 	}
 
-	return CreateSnapshot(snapConfig, &d.Config, d.API, d.API.VolumeSize)
+	return CreateSnapshot(snapConfig, &d.Config, client, client.VolumeSize)
+}
+
+// CreateGroupSnapshot creates snapshots of several volumes at the same point in time
+func (d *NASStorageDriver) CreateGroupSnapshot(snapConfigs []*storage.SnapshotConfig) ([]*storage.Snapshot, error) {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":       "CreateGroupSnapshot",
+			"Type":         "NASStorageDriver",
+			"snapshotName": snapConfigs[0].InternalName,
+			"volumes":      len(snapConfigs),
+		}
+		log.WithFields(fields).Debug(">>>> CreateGroupSnapshot")
+		defer log.WithFields(fields).Debug("<<<< CreateGroupSnapshot")
+	}
+
+	return CreateGroupSnapshot(snapConfigs, &d.Config, d.API, d.API.VolumeSize)
 }
 
 // RestoreSnapshot restores a volume (in place) from a snapshot.
-func (d *NASStorageDriver) RestoreSnapshot(snapConfig *storage.SnapshotConfig) error {
+func (d *NASStorageDriver) RestoreSnapshot(ctx context.Context, snapConfig *storage.SnapshotConfig) error {
 
 	if d.Config.DebugTraceFlags["method"] {
 		fields := log.Fields{
@@ -579,11 +678,13 @@ func (d *NASStorageDriver) RestoreSnapshot(snapConfig *storage.SnapshotConfig) e
 		defer log.WithFields(fields).Debug("<<<< RestoreSnapshot")
 	}
 
-	return RestoreSnapshot(snapConfig, &d.Config, d.API)
+	client := d.API.WithContext(ctx)
+
+	return RestoreSnapshot(snapConfig, &d.Config, client)
 }
 
 // DeleteSnapshot creates a snapshot of a volume.
-func (d *NASStorageDriver) DeleteSnapshot(snapConfig *storage.SnapshotConfig) error {
+func (d *NASStorageDriver) DeleteSnapshot(ctx context.Context, snapConfig *storage.SnapshotConfig) error {
 
 	if d.Config.DebugTraceFlags["method"] {
 		fields := log.Fields{
@@ -596,7 +697,193 @@ func (d *NASStorageDriver) DeleteSnapshot(snapConfig *storage.SnapshotConfig) er
 		defer log.WithFields(fields).Debug("<<<< DeleteSnapshot")
 	}
 
-	return DeleteSnapshot(snapConfig, &d.Config, d.API)
+	client := d.API.WithContext(ctx)
+
+	return DeleteSnapshot(snapConfig, &d.Config, client)
+}
+
+// SupportsBackups returns true if this backend is an FSx for NetApp ONTAP file system.
+func (d *NASStorageDriver) SupportsBackups() bool {
+	return isFSx(&d.Config)
+}
+
+// GetBackup returns an AWS-native backup of the given volume, or nil if it doesn't exist.
+func (d *NASStorageDriver) GetBackup(snapConfig *storage.SnapshotConfig) (*storage.Snapshot, error) {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":       "GetBackup",
+			"Type":         "NASStorageDriver",
+			"snapshotName": snapConfig.Name,
+			"volumeName":   snapConfig.VolumeInternalName,
+		}
+		log.WithFields(fields).Debug(">>>> GetBackup")
+		defer log.WithFields(fields).Debug("<<<< GetBackup")
+	}
+
+	return GetFSxBackup(snapConfig, &d.Config, d.API.VolumeSize)
+}
+
+// CreateBackup starts an AWS-native backup of the given volume.
+func (d *NASStorageDriver) CreateBackup(snapConfig *storage.SnapshotConfig) (*storage.Snapshot, error) {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":       "CreateBackup",
+			"Type":         "NASStorageDriver",
+			"snapshotName": snapConfig.Name,
+			"sourceVolume": snapConfig.VolumeInternalName,
+		}
+		log.WithFields(fields).Debug(">>>> CreateBackup")
+		defer log.WithFields(fields).Debug("<<<< CreateBackup")
+	}
+
+	return CreateFSxBackup(snapConfig, &d.Config, d.API.VolumeSize)
+}
+
+// DeleteBackup deletes an AWS-native backup.
+func (d *NASStorageDriver) DeleteBackup(snapConfig *storage.SnapshotConfig) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":       "DeleteBackup",
+			"Type":         "NASStorageDriver",
+			"snapshotName": snapConfig.Name,
+			"volumeName":   snapConfig.VolumeInternalName,
+		}
+		log.WithFields(fields).Debug(">>>> DeleteBackup")
+		defer log.WithFields(fields).Debug("<<<< DeleteBackup")
+	}
+
+	return DeleteFSxBackup(snapConfig, &d.Config)
+}
+
+// GetVolumeUsage returns the space consumed by a volume, as reported by the Flexvol.
+func (d *NASStorageDriver) GetVolumeUsage(volConfig *storage.VolumeConfig) (*storage.VolumeUsage, error) {
+	return getOntapVolumeUsage(volConfig.InternalName, d.API)
+}
+
+// GetVolumeCondition reports a volume whose Flexvol isn't online, or has no junction path, as abnormal.
+func (d *NASStorageDriver) GetVolumeCondition(volConfig *storage.VolumeConfig) (*storage.VolumeCondition, error) {
+
+	condition, err := getOntapVolumeCondition(volConfig.InternalName, d.API)
+	if err != nil || condition.Abnormal {
+		return condition, err
+	}
+
+	flexvol, err := d.API.VolumeGet(volConfig.InternalName)
+	if err != nil {
+		return nil, fmt.Errorf("error reading volume %s; %v", volConfig.InternalName, err)
+	}
+	return getOntapJunctionCondition(volConfig.InternalName, flexvol), nil
+}
+
+// CanReplicateFrom returns true if volumes on the source backend can be SnapMirrored to this backend.
+func (d *NASStorageDriver) CanReplicateFrom(source storage.Driver) bool {
+	return canReplicateOntapVolume(d, source)
+}
+
+// CreateReplica creates a data protection volume and starts replicating the source volume to it.
+func (d *NASStorageDriver) CreateReplica(
+	volConfig, sourceVolConfig *storage.VolumeConfig, storagePool *storage.Pool, source storage.Driver,
+) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":       "CreateReplica",
+			"Type":         "NASStorageDriver",
+			"name":         volConfig.InternalName,
+			"sourceVolume": sourceVolConfig.InternalName,
+		}
+		log.WithFields(fields).Debug(">>>> CreateReplica")
+		defer log.WithFields(fields).Debug("<<<< CreateReplica")
+	}
+
+	return createOntapReplica(volConfig, sourceVolConfig, storagePool, d.physicalPools, d.virtualPools,
+		d, source.(StorageDriver))
+}
+
+// GetReplicaProgress returns the number of bytes replicated and whether the baseline transfer is complete.
+func (d *NASStorageDriver) GetReplicaProgress(volConfig *storage.VolumeConfig) (uint64, bool, error) {
+	return getOntapReplicaProgress(volConfig, d.API)
+}
+
+// PromoteReplica breaks the SnapMirror relationship to a replica, making it writable.
+func (d *NASStorageDriver) PromoteReplica(
+	volConfig, sourceVolConfig *storage.VolumeConfig, source storage.Driver,
+) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":       "PromoteReplica",
+			"Type":         "NASStorageDriver",
+			"name":         volConfig.InternalName,
+			"sourceVolume": sourceVolConfig.InternalName,
+		}
+		log.WithFields(fields).Debug(">>>> PromoteReplica")
+		defer log.WithFields(fields).Debug("<<<< PromoteReplica")
+	}
+
+	sourceDriver := source.(StorageDriver)
+	if err := promoteOntapReplica(volConfig, d, sourceDriver,
+		func() error { return suspendOntapNASReplicaSource(sourceVolConfig, sourceDriver) },
+		func() error { return resumeOntapNASReplicaSource(sourceVolConfig, sourceDriver) }); err != nil {
+		return err
+	}
+
+	// Data protection volumes are created with the default export policy, and the source volume's
+	// policy may not exist in this SVM, so use the policy configured for this backend
+	volConfig.ExportPolicy = d.Config.ExportPolicy
+	policyResponse, err := d.API.VolumeModifyExportPolicy(volConfig.InternalName, volConfig.ExportPolicy)
+	if err = api.GetError(policyResponse, err); err != nil {
+		return fmt.Errorf("error setting export policy on volume %s; %v", volConfig.InternalName, err)
+	}
+
+	return nil
+}
+
+// DeleteReplica stops replicating to a replica and destroys it.
+func (d *NASStorageDriver) DeleteReplica(
+	volConfig, sourceVolConfig *storage.VolumeConfig, source storage.Driver,
+) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":       "DeleteReplica",
+			"Type":         "NASStorageDriver",
+			"name":         volConfig.InternalName,
+			"sourceVolume": sourceVolConfig.InternalName,
+		}
+		log.WithFields(fields).Debug(">>>> DeleteReplica")
+		defer log.WithFields(fields).Debug("<<<< DeleteReplica")
+	}
+
+	sourceDriver := source.(StorageDriver)
+	return deleteOntapReplica(volConfig, d, sourceDriver, func() error {
+		return resumeOntapNASReplicaSource(sourceVolConfig, sourceDriver)
+	})
+}
+
+// MoveVolume starts moving a volume's Flexvol to an aggregate of another storage pool.
+func (d *NASStorageDriver) MoveVolume(volConfig *storage.VolumeConfig, storagePool *storage.Pool) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":      "MoveVolume",
+			"Type":        "NASStorageDriver",
+			"name":        volConfig.InternalName,
+			"storagePool": storagePool.Name,
+		}
+		log.WithFields(fields).Debug(">>>> MoveVolume")
+		defer log.WithFields(fields).Debug("<<<< MoveVolume")
+	}
+
+	return moveOntapVolume(volConfig, storagePool, d.physicalPools, d.virtualPools, d.API)
+}
+
+// GetVolumeMoveProgress returns the number of bytes moved and to move, and whether the move is complete.
+func (d *NASStorageDriver) GetVolumeMoveProgress(volConfig *storage.VolumeConfig) (uint64, uint64, bool, error) {
+	return getOntapVolumeMoveProgress(volConfig, d.API)
 }
 
 // Test for the existence of a volume
@@ -616,6 +903,11 @@ func (d *NASStorageDriver) GetStorageBackendSpecs(backend *storage.Backend) erro
 	return getStorageBackendSpecsCommon(backend, d.physicalPools, d.virtualPools, d.backendName())
 }
 
+// GetStorageCapacity reports the free space in the aggregates behind the backend's pools
+func (d *NASStorageDriver) GetStorageCapacity() (*storage.StorageCapacity, error) {
+	return getStorageCapacityCommon(d, d.physicalPools, d.virtualPools)
+}
+
 // Retrieve storage backend physical pools
 func (d *NASStorageDriver) GetStorageBackendPhysicalPoolNames() []string {
 	return getStorageBackendPhysicalPoolNamesCommon(d.physicalPools)
@@ -647,6 +939,12 @@ func (d *NASStorageDriver) CreatePrepare(volConfig *storage.VolumeConfig) {
 	createPrepareCommon(d, volConfig)
 }
 
+// CreatePrepareInPool sets the internal name of a volume created in a pool, using the pool's storage
+// prefix if it has one.
+func (d *NASStorageDriver) CreatePrepareInPool(volConfig *storage.VolumeConfig, pool *storage.Pool) {
+	volConfig.InternalName = getPoolInternalVolumeName(d.Config.CommonStorageDriverConfig, pool, volConfig.Name)
+}
+
 func (d *NASStorageDriver) CreateFollowup(volConfig *storage.VolumeConfig) error {
 
 	volConfig.AccessInfo.NfsServerIP = d.Config.DataLIF
@@ -674,6 +972,17 @@ func (d *NASStorageDriver) CreateFollowup(volConfig *storage.VolumeConfig) error
 	} else {
 		volConfig.AccessInfo.NfsPath = flexvol.VolumeIdAttributesPtr.JunctionPath()
 	}
+
+	// Share the junction over SMB, whether the volume was created, cloned or imported
+	if d.Config.NASType == drivers.NASTypeSMB {
+		if err = createCIFSShare(d.API, volConfig.InternalName, volConfig.AccessInfo.NfsPath); err != nil {
+			return err
+		}
+		volConfig.AccessInfo.SMBPath = getSMBPath(d.Config.DataLIF, volConfig.InternalName)
+		volConfig.AccessInfo.NfsServerIP = ""
+		volConfig.AccessInfo.NfsPath = ""
+		volConfig.AccessInfo.MountOptions = ""
+	}
 	return nil
 }
 
@@ -715,17 +1024,20 @@ func (d *NASStorageDriver) GetVolumeExternalWrappers(
 	// Let the caller know we're done by closing the channel
 	defer close(channel)
 
-	// Get all volumes matching the storage prefix
-	volumesResponse, err := d.API.VolumeGetAll(*d.Config.StoragePrefix)
-	if err = api.GetError(volumesResponse, err); err != nil {
-		channel <- &storage.VolumeExternalWrapper{Volume: nil, Error: err}
-		return
-	}
+	// Get all volumes matching the storage prefixes of the backend and its virtual pools
+	prefixes := getStoragePrefixes(d.Config.CommonStorageDriverConfig, d.virtualPools)
+	for _, prefix := range getStoragePrefixesToList(prefixes) {
+		volumesResponse, err := d.API.VolumeGetAll(prefix)
+		if err = api.GetError(volumesResponse, err); err != nil {
+			channel <- &storage.VolumeExternalWrapper{Volume: nil, Error: err}
+			return
+		}
 
-	// Convert all volumes to VolumeExternal and write them to the channel
-	if volumesResponse.Result.AttributesListPtr != nil {
-		for _, volume := range volumesResponse.Result.AttributesListPtr.VolumeAttributesPtr {
-			channel <- &storage.VolumeExternalWrapper{Volume: d.getVolumeExternal(&volume), Error: nil}
+		// Convert all volumes to VolumeExternal and write them to the channel
+		if volumesResponse.Result.AttributesListPtr != nil {
+			for _, volume := range volumesResponse.Result.AttributesListPtr.VolumeAttributesPtr {
+				channel <- &storage.VolumeExternalWrapper{Volume: d.getVolumeExternal(&volume), Error: nil}
+			}
 		}
 	}
 }
@@ -744,10 +1056,7 @@ func (d *NASStorageDriver) getVolumeExternal(
 	volumeSnapshotAttrs := volumeAttrs.VolumeSnapshotAttributesPtr
 
 	internalName := string(volumeIDAttrs.Name())
-	name := internalName
-	if strings.HasPrefix(internalName, *d.Config.StoragePrefix) {
-		name = internalName[len(*d.Config.StoragePrefix):]
-	}
+	name := trimStoragePrefix(internalName, getStoragePrefixes(d.Config.CommonStorageDriverConfig, d.virtualPools))
 
 	volumeConfig := &storage.VolumeConfig{
 		Version:         tridentconfig.OrchestratorAPIVersion,
@@ -802,11 +1111,18 @@ func (d *NASStorageDriver) GetUpdateType(driverOrig storage.Driver) *roaring.Bit
 		bitmap.Add(storage.UsernameChange)
 	}
 
+	if drivers.StoragePrefixChanged(d.Config.CommonStorageDriverConfig, dOrig.Config.CommonStorageDriverConfig) ||
+		storagePrefixesRemoved(getStoragePrefixes(d.Config.CommonStorageDriverConfig, d.virtualPools),
+			getStoragePrefixes(dOrig.Config.CommonStorageDriverConfig, dOrig.virtualPools)) {
+		bitmap.Add(storage.PrefixChange)
+	}
 	return bitmap
 }
 
 // Resize expands the volume size.
-func (d *NASStorageDriver) Resize(volConfig *storage.VolumeConfig, sizeBytes uint64) error {
+func (d *NASStorageDriver) Resize(ctx context.Context, volConfig *storage.VolumeConfig, sizeBytes uint64) error {
+	client := d.API.WithContext(ctx)
+
 	name := volConfig.InternalName
 	if d.Config.DebugTraceFlags["method"] {
 		fields := log.Fields{
@@ -819,7 +1135,7 @@ func (d *NASStorageDriver) Resize(volConfig *storage.VolumeConfig, sizeBytes uin
 		defer log.WithFields(fields).Debug("<<<< Resize")
 	}
 
-	flexvolSize, err := resizeValidation(name, sizeBytes, d.API.VolumeExists, d.API.VolumeSize)
+	flexvolSize, err := resizeValidation(name, sizeBytes, client.VolumeExists, client.VolumeSize)
 	if err != nil {
 		return err
 	}
@@ -837,7 +1153,7 @@ func (d *NASStorageDriver) Resize(volConfig *storage.VolumeConfig, sizeBytes uin
 		return checkVolumeSizeLimitsError
 	}
 
-	response, err := d.API.VolumeSetSize(name, strconv.FormatUint(sizeBytes, 10))
+	response, err := client.VolumeSetSize(name, strconv.FormatUint(sizeBytes, 10))
 	if err = api.GetError(response.Result, err); err != nil {
 		log.WithField("error", err).Error("Volume resize failed.")
 		return fmt.Errorf("volume resize failed")
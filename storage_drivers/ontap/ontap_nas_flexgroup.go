@@ -2,6 +2,7 @@
 package ontap
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"math"
@@ -183,11 +184,17 @@ func (d *NASFlexGroupStorageDriver) initializeStoragePools() error {
 	pool.InternalAttributes[ExportPolicy] = config.ExportPolicy
 	pool.InternalAttributes[SecurityStyle] = config.SecurityStyle
 	pool.InternalAttributes[TieringPolicy] = config.TieringPolicy
+	pool.InternalAttributes[MountOptions] = config.MountOptions
 
 	d.physicalPool = pool
 
 	d.virtualPools = make(map[string]*storage.Pool)
 
+	// Only virtual pools may name their volumes differently than the backend
+	if config.OntapStorageDriverConfigDefaults.StoragePrefix != "" {
+		return errors.New("storagePrefix may only be set in the defaults of a virtual pool")
+	}
+
 	if len(d.Config.Storage) != 0 {
 		log.Debug("Defining Virtual Pools based on Virtual Pools definition in the backend file.")
 
@@ -252,6 +259,11 @@ func (d *NASFlexGroupStorageDriver) initializeStoragePools() error {
 				tieringPolicy = vpool.TieringPolicy
 			}
 
+			mountOptions := config.MountOptions
+			if vpool.MountOptions != "" {
+				mountOptions = vpool.MountOptions
+			}
+
 			pool := storage.NewStoragePool(nil, poolName(fmt.Sprintf("pool_%d", index), d.backendName()))
 
 			// Update pool with attributes set by default for this backend
@@ -295,6 +307,11 @@ func (d *NASFlexGroupStorageDriver) initializeStoragePools() error {
 			pool.InternalAttributes[ExportPolicy] = exportPolicy
 			pool.InternalAttributes[SecurityStyle] = securityStyle
 			pool.InternalAttributes[TieringPolicy] = tieringPolicy
+			pool.InternalAttributes[MountOptions] = mountOptions
+
+			if vpool.StoragePrefix != "" {
+				pool.InternalAttributes[StoragePrefix] = vpool.StoragePrefix
+			}
 
 			d.virtualPools[pool.Name] = pool
 		}
@@ -397,9 +414,11 @@ func (d *NASFlexGroupStorageDriver) validate() error {
 
 // Create a volume with the specified options
 func (d *NASFlexGroupStorageDriver) Create(
-	volConfig *storage.VolumeConfig, storagePool *storage.Pool, volAttributes map[string]sa.Request,
+	ctx context.Context, volConfig *storage.VolumeConfig, storagePool *storage.Pool, volAttributes map[string]sa.Request,
 ) error {
 
+	client := d.API.WithContext(ctx)
+
 	name := volConfig.InternalName
 
 	if d.Config.DebugTraceFlags["method"] {
@@ -414,7 +433,7 @@ func (d *NASFlexGroupStorageDriver) Create(
 	}
 
 	// If the volume already exists, bail out
-	volExists, err := d.API.FlexGroupExists(name)
+	volExists, err := client.FlexGroupExists(name)
 	if err != nil {
 		return fmt.Errorf("error checking for existing FlexGroup: %v", err)
 	}
@@ -422,6 +441,9 @@ func (d *NASFlexGroupStorageDriver) Create(
 		return drivers.NewVolumeExistsError(name)
 	}
 
+	// Use the pool's mount options unless the volume or its storage class specified some
+	applyPoolMountOptions(volConfig, storagePool)
+
 	// Determine volume size in bytes
 	requestedSize, err := utils.ConvertSizeToBytes(volConfig.Size)
 	if err != nil {
@@ -441,7 +463,7 @@ func (d *NASFlexGroupStorageDriver) Create(
 	size := int(sizeBytes)
 
 	// Get the aggregates assigned to the SVM.  There must be at least one!
-	vserverAggrs, err := d.API.VserverGetAggregateNames()
+	vserverAggrs, err := client.VserverGetAggregateNames()
 	if err != nil {
 		return err
 	}
@@ -523,7 +545,7 @@ func (d *NASFlexGroupStorageDriver) Create(
 
 	// Create the FlexGroup
 	checkVolumeCreated := func() error {
-		_, err = d.API.FlexGroupCreate(
+		_, err = client.FlexGroupCreate(
 			name, size, vserverAggrNames, spaceReserve, snapshotPolicy, unixPermissions,
 			exportPolicy, securityStyle, tieringPolicy, enableEncryption, snapshotReserveInt)
 
@@ -551,15 +573,21 @@ func (d *NASFlexGroupStorageDriver) Create(
 
 	// Disable '.snapshot' to allow official mysql container's chmod-in-init to work
 	if !enableSnapshotDir {
-		_, err := d.API.FlexGroupVolumeDisableSnapshotDirectoryAccess(name)
+		_, err := client.FlexGroupVolumeDisableSnapshotDirectoryAccess(name)
 		if err != nil {
 			createErrors = append(createErrors, fmt.Errorf("ONTAP-NAS-FLEXGROUP pool %s; error disabling snapshot directory access for volume %v: %v", storagePool.Name, name, err))
 			return drivers.NewBackendIneligibleError(name, createErrors, physicalPoolNames)
 		}
 	}
 
+	// Set the ownership of the volume's root directory at provision time
+	if err = setRootOwnership(volConfig, name, client); err != nil {
+		createErrors = append(createErrors, fmt.Errorf("ONTAP-NAS-FLEXGROUP pool %s; %v", storagePool.Name, err))
+		return drivers.NewBackendIneligibleError(name, createErrors, physicalPoolNames)
+	}
+
 	// Mount the volume at the specified junction
-	mountResponse, err := d.API.VolumeMount(name, "/"+name)
+	mountResponse, err := client.VolumeMount(name, "/"+name)
 	if err = api.GetError(mountResponse, err); err != nil {
 		createErrors = append(createErrors, fmt.Errorf("ONTAP-NAS-FLEXGROUP pool %s; error mounting volume %s to junction: %v", storagePool.Name, name, err))
 		return drivers.NewBackendIneligibleError(name, createErrors, physicalPoolNames)
@@ -569,12 +597,16 @@ func (d *NASFlexGroupStorageDriver) Create(
 }
 
 // CreateClone creates a volume clone
-func (d *NASFlexGroupStorageDriver) CreateClone(volConfig *storage.VolumeConfig, storagePool *storage.Pool) error {
+func (d *NASFlexGroupStorageDriver) CreateClone(
+	ctx context.Context, volConfig *storage.VolumeConfig, storagePool *storage.Pool,
+) error {
 	return errors.New("clones are not supported for FlexGroups")
 }
 
 // Import brings an existing volume under trident's control
-func (d *NASFlexGroupStorageDriver) Import(volConfig *storage.VolumeConfig, originalName string) error {
+func (d *NASFlexGroupStorageDriver) Import(
+	ctx context.Context, volConfig *storage.VolumeConfig, originalName string,
+) error {
 
 	if d.Config.DebugTraceFlags["method"] {
 		fields := log.Fields{
@@ -587,6 +619,33 @@ func (d *NASFlexGroupStorageDriver) Import(volConfig *storage.VolumeConfig, orig
 		defer log.WithFields(fields).Debug("<<<< Import")
 	}
 
+	return d.validateImport(volConfig, originalName)
+}
+
+// ImportCheck runs the checks for importing a volume and returns the changes the import would make
+// to the volume.  FlexGroups are imported without being renamed, so there are none.
+func (d *NASFlexGroupStorageDriver) ImportCheck(volConfig *storage.VolumeConfig, originalName string) ([]string, error) {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":       "ImportCheck",
+			"Type":         "NASFlexGroupStorageDriver",
+			"originalName": originalName,
+			"notManaged":   volConfig.ImportNotManaged,
+		}
+		log.WithFields(fields).Debug(">>>> ImportCheck")
+		defer log.WithFields(fields).Debug("<<<< ImportCheck")
+	}
+
+	if err := d.validateImport(volConfig, originalName); err != nil {
+		return nil, err
+	}
+	return []string{}, nil
+}
+
+// validateImport ensures a FlexGroup may be imported and sets the volume config's size and name from it.
+func (d *NASFlexGroupStorageDriver) validateImport(volConfig *storage.VolumeConfig, originalName string) error {
+
 	// Ensure the volume exists
 	flexgroup, err := d.API.FlexGroupGet(originalName)
 	if err != nil {
@@ -634,7 +693,7 @@ func (d *NASFlexGroupStorageDriver) Rename(name string, newName string) error {
 }
 
 // Destroy the volume
-func (d *NASFlexGroupStorageDriver) Destroy(name string) error {
+func (d *NASFlexGroupStorageDriver) Destroy(ctx context.Context, name string) error {
 
 	if d.Config.DebugTraceFlags["method"] {
 		fields := log.Fields{
@@ -646,6 +705,8 @@ func (d *NASFlexGroupStorageDriver) Destroy(name string) error {
 		defer log.WithFields(fields).Debug("<<<< Destroy")
 	}
 
+	client := d.API.WithContext(ctx)
+
 	// Needed once FlexGroups support clones
 	// TODO: If this is the parent of one or more clones, those clones have to split from this
 	// volume before it can be deleted, which means separate copies of those volumes.
@@ -663,7 +724,7 @@ func (d *NASFlexGroupStorageDriver) Destroy(name string) error {
 	// This call is async, but we will receive an immediate error back for anything but very rare volume deletion
 	// failures. Failures in this category are almost certainly likely to be beyond our capability to fix or even
 	// diagnose, so we defer to the ONTAP cluster admin
-	if _, err := d.API.FlexGroupDestroy(name, true); err != nil {
+	if _, err := client.FlexGroupDestroy(name, true); err != nil {
 		return fmt.Errorf("error destroying FlexGroup %v: %v", name, err)
 	}
 
@@ -673,7 +734,11 @@ func (d *NASFlexGroupStorageDriver) Destroy(name string) error {
 // Publish the volume to the host specified in publishInfo.  This method may or may not be running on the host
 // where the volume will be mounted, so it should limit itself to updating access rules, initiator groups, etc.
 // that require some host identity (but not locality) as well as storage controller API access.
-func (d *NASFlexGroupStorageDriver) Publish(volConfig *storage.VolumeConfig, publishInfo *utils.VolumePublishInfo) error {
+func (d *NASFlexGroupStorageDriver) Publish(
+	ctx context.Context, volConfig *storage.VolumeConfig, publishInfo *utils.VolumePublishInfo,
+) error {
+
+	client := d.API.WithContext(ctx)
 
 	name := volConfig.InternalName
 
@@ -699,7 +764,7 @@ func (d *NASFlexGroupStorageDriver) Publish(volConfig *storage.VolumeConfig, pub
 	publishInfo.FilesystemType = "nfs"
 	publishInfo.MountOptions = mountOptions
 
-	return publishFlexVolShare(d.API, &d.Config, publishInfo, name)
+	return publishFlexVolShare(client, &d.Config, publishInfo, name)
 }
 
 // GetSnapshot gets a snapshot.  To distinguish between an API error reading the snapshot
@@ -739,7 +804,9 @@ func (d *NASFlexGroupStorageDriver) GetSnapshots(volConfig *storage.VolumeConfig
 }
 
 // CreateSnapshot creates a snapshot for the given volume
-func (d *NASFlexGroupStorageDriver) CreateSnapshot(snapConfig *storage.SnapshotConfig) (*storage.Snapshot, error) {
+func (d *NASFlexGroupStorageDriver) CreateSnapshot(
+	ctx context.Context, snapConfig *storage.SnapshotConfig,
+) (*storage.Snapshot, error) {
 
 	internalSnapName := snapConfig.InternalName
 	internalVolName := snapConfig.VolumeInternalName
@@ -760,7 +827,7 @@ func (d *NASFlexGroupStorageDriver) CreateSnapshot(snapConfig *storage.SnapshotC
 }
 
 // RestoreSnapshot restores a volume (in place) from a snapshot.
-func (d *NASFlexGroupStorageDriver) RestoreSnapshot(snapConfig *storage.SnapshotConfig) error {
+func (d *NASFlexGroupStorageDriver) RestoreSnapshot(ctx context.Context, snapConfig *storage.SnapshotConfig) error {
 
 	if d.Config.DebugTraceFlags["method"] {
 		fields := log.Fields{
@@ -778,7 +845,7 @@ func (d *NASFlexGroupStorageDriver) RestoreSnapshot(snapConfig *storage.Snapshot
 }
 
 // DeleteSnapshot creates a snapshot of a volume.
-func (d *NASFlexGroupStorageDriver) DeleteSnapshot(snapConfig *storage.SnapshotConfig) error {
+func (d *NASFlexGroupStorageDriver) DeleteSnapshot(ctx context.Context, snapConfig *storage.SnapshotConfig) error {
 
 	if d.Config.DebugTraceFlags["method"] {
 		fields := log.Fields{
@@ -811,12 +878,36 @@ func (d *NASFlexGroupStorageDriver) Get(name string) error {
 	}
 	if !volExists {
 		log.WithField("FlexGroup", name).Debug("FlexGroup not found.")
-		return fmt.Errorf("volume %s does not exist", name)
+		return utils.NotFoundError(fmt.Sprintf("volume %s does not exist", name))
 	}
 
 	return nil
 }
 
+// GetVolumeCondition reports a volume whose FlexGroup isn't online, or has no junction path, as abnormal.
+func (d *NASFlexGroupStorageDriver) GetVolumeCondition(
+	volConfig *storage.VolumeConfig,
+) (*storage.VolumeCondition, error) {
+
+	name := volConfig.InternalName
+	state, err := d.API.FlexGroupGetState(name)
+	if err != nil {
+		return nil, fmt.Errorf("error reading volume %s; %v", name, err)
+	}
+	if state != "online" {
+		return &storage.VolumeCondition{
+			Abnormal: true,
+			Message:  fmt.Sprintf("volume %s is %s", name, state),
+		}, nil
+	}
+
+	flexgroup, err := d.API.FlexGroupGet(name)
+	if err != nil {
+		return nil, fmt.Errorf("error reading volume %s; %v", name, err)
+	}
+	return getOntapJunctionCondition(name, flexgroup), nil
+}
+
 // getStorageBackendSpecsCommon updates the specified Backend object with StoragePools.
 func (d *NASFlexGroupStorageDriver) GetStorageBackendSpecs(backend *storage.Backend) error {
 	backend.Name = d.backendName()
@@ -838,6 +929,38 @@ func (d *NASFlexGroupStorageDriver) GetStorageBackendSpecs(backend *storage.Back
 	return nil
 }
 
+// GetStorageCapacity reports the free space in the SVM.  A FlexGroup spans the SVM's aggregates, so the
+// single physical pool and every virtual pool draw on their combined space.
+func (d *NASFlexGroupStorageDriver) GetStorageCapacity() (*storage.StorageCapacity, error) {
+
+	aggrFree, aggrSize, err := getAggregateSpace(d)
+	if err != nil {
+		return nil, err
+	}
+
+	var svmFree, svmSize int64
+	for aggrName, free := range aggrFree {
+		svmFree += free
+		svmSize += aggrSize[aggrName]
+	}
+
+	source := d.physicalPool.Name
+	capacity := &storage.StorageCapacity{
+		SourceFreeBytes:  map[string]int64{source: svmFree},
+		PoolSources:      map[string][]string{source: {source}},
+		SourceTotalBytes: make(map[string]int64),
+	}
+	// The SVM's size is only known if every aggregate's size is
+	if len(aggrSize) == len(aggrFree) {
+		capacity.SourceTotalBytes[source] = svmSize
+	}
+	for poolName := range d.virtualPools {
+		capacity.PoolSources[poolName] = []string{source}
+	}
+
+	return capacity, nil
+}
+
 // Retrieve storage backend physical pools
 func (d *NASFlexGroupStorageDriver) GetStorageBackendPhysicalPoolNames() []string {
 	physicalPoolNames := make([]string, 0)
@@ -886,6 +1009,12 @@ func (d *NASFlexGroupStorageDriver) CreatePrepare(volConfig *storage.VolumeConfi
 	createPrepareCommon(d, volConfig)
 }
 
+// CreatePrepareInPool sets the internal name of a volume created in a pool, using the pool's storage
+// prefix if it has one.
+func (d *NASFlexGroupStorageDriver) CreatePrepareInPool(volConfig *storage.VolumeConfig, pool *storage.Pool) {
+	volConfig.InternalName = getPoolInternalVolumeName(d.Config.CommonStorageDriverConfig, pool, volConfig.Name)
+}
+
 func (d *NASFlexGroupStorageDriver) CreateFollowup(volConfig *storage.VolumeConfig) error {
 
 	volConfig.AccessInfo.NfsServerIP = d.Config.DataLIF
@@ -955,17 +1084,20 @@ func (d *NASFlexGroupStorageDriver) GetVolumeExternalWrappers(
 	// Let the caller know we're done by closing the channel
 	defer close(channel)
 
-	// Get all volumes matching the storage prefix
-	volumesResponse, err := d.API.FlexGroupGetAll(*d.Config.StoragePrefix)
-	if err = api.GetError(volumesResponse, err); err != nil {
-		channel <- &storage.VolumeExternalWrapper{Volume: nil, Error: err}
-		return
-	}
+	// Get all volumes matching the storage prefixes of the backend and its virtual pools
+	prefixes := getStoragePrefixes(d.Config.CommonStorageDriverConfig, d.virtualPools)
+	for _, prefix := range getStoragePrefixesToList(prefixes) {
+		volumesResponse, err := d.API.FlexGroupGetAll(prefix)
+		if err = api.GetError(volumesResponse, err); err != nil {
+			channel <- &storage.VolumeExternalWrapper{Volume: nil, Error: err}
+			return
+		}
 
-	// Convert all volumes to VolumeExternal and write them to the channel
-	if volumesResponse.Result.AttributesListPtr != nil {
-		for _, volume := range volumesResponse.Result.AttributesListPtr.VolumeAttributesPtr {
-			channel <- &storage.VolumeExternalWrapper{Volume: d.getVolumeExternal(&volume), Error: nil}
+		// Convert all volumes to VolumeExternal and write them to the channel
+		if volumesResponse.Result.AttributesListPtr != nil {
+			for _, volume := range volumesResponse.Result.AttributesListPtr.VolumeAttributesPtr {
+				channel <- &storage.VolumeExternalWrapper{Volume: d.getVolumeExternal(&volume), Error: nil}
+			}
 		}
 	}
 }
@@ -984,10 +1116,7 @@ func (d *NASFlexGroupStorageDriver) getVolumeExternal(
 	volumeSnapshotAttrs := volumeAttrs.VolumeSnapshotAttributesPtr
 
 	internalName := string(volumeIDAttrs.Name())
-	name := internalName
-	if strings.HasPrefix(internalName, *d.Config.StoragePrefix) {
-		name = internalName[len(*d.Config.StoragePrefix):]
-	}
+	name := trimStoragePrefix(internalName, getStoragePrefixes(d.Config.CommonStorageDriverConfig, d.virtualPools))
 
 	volumeConfig := &storage.VolumeConfig{
 		Version:         tridentconfig.OrchestratorAPIVersion,
@@ -1033,11 +1162,20 @@ func (d *NASFlexGroupStorageDriver) GetUpdateType(driverOrig storage.Driver) *ro
 		bitmap.Add(storage.UsernameChange)
 	}
 
+	if drivers.StoragePrefixChanged(d.Config.CommonStorageDriverConfig, dOrig.Config.CommonStorageDriverConfig) ||
+		storagePrefixesRemoved(getStoragePrefixes(d.Config.CommonStorageDriverConfig, d.virtualPools),
+			getStoragePrefixes(dOrig.Config.CommonStorageDriverConfig, dOrig.virtualPools)) {
+		bitmap.Add(storage.PrefixChange)
+	}
 	return bitmap
 }
 
 // Resize expands the FlexGroup size.
-func (d *NASFlexGroupStorageDriver) Resize(volConfig *storage.VolumeConfig, sizeBytes uint64) error {
+func (d *NASFlexGroupStorageDriver) Resize(
+	ctx context.Context, volConfig *storage.VolumeConfig, sizeBytes uint64,
+) error {
+
+	client := d.API.WithContext(ctx)
 
 	name := volConfig.InternalName
 	if d.Config.DebugTraceFlags["method"] {
@@ -1051,7 +1189,7 @@ func (d *NASFlexGroupStorageDriver) Resize(volConfig *storage.VolumeConfig, size
 		defer log.WithFields(fields).Debug("<<<< Resize")
 	}
 
-	flexvolSize, err := resizeValidation(name, sizeBytes, d.API.FlexGroupExists, d.API.FlexGroupSize)
+	flexvolSize, err := resizeValidation(name, sizeBytes, client.FlexGroupExists, client.FlexGroupSize)
 	if err != nil {
 		return err
 	}
@@ -1061,7 +1199,7 @@ func (d *NASFlexGroupStorageDriver) Resize(volConfig *storage.VolumeConfig, size
 		return nil
 	}
 
-	_, err = d.API.FlexGroupSetSize(name, strconv.FormatUint(sizeBytes, 10))
+	_, err = client.FlexGroupSetSize(name, strconv.FormatUint(sizeBytes, 10))
 	if err != nil {
 		log.WithField("error", err).Error("FlexGroup resize failed.")
 		return fmt.Errorf("flexgroup resize failed")
@@ -3,6 +3,7 @@
 package ontap
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"math/rand"
@@ -240,9 +241,11 @@ func (d *NASQtreeStorageDriver) validate() error {
 
 // Create a qtree-backed volume with the specified options
 func (d *NASQtreeStorageDriver) Create(
-	volConfig *storage.VolumeConfig, storagePool *storage.Pool, volAttributes map[string]sa.Request,
+	ctx context.Context, volConfig *storage.VolumeConfig, storagePool *storage.Pool, volAttributes map[string]sa.Request,
 ) error {
 
+	client := d.API.WithContext(ctx)
+
 	name := volConfig.InternalName
 
 	if d.Config.DebugTraceFlags["method"] {
@@ -264,7 +267,7 @@ func (d *NASQtreeStorageDriver) Create(
 	createError := errors.New("volume creation failed")
 
 	// Ensure volume doesn't already exist
-	exists, existsInFlexvol, err := d.API.QtreeExists(name, d.FlexvolNamePrefix())
+	exists, existsInFlexvol, err := client.QtreeExists(name, d.FlexvolNamePrefix())
 	if err != nil {
 		log.Errorf("Error checking for existing volume: %v.", err)
 		return createError
@@ -280,6 +283,17 @@ func (d *NASQtreeStorageDriver) Create(
 		return err
 	}
 
+	// Use the pool's mount options unless the volume or its storage class specified some
+	applyPoolMountOptions(volConfig, storagePool)
+
+	// ONTAP can't set the owner of a qtree as it creates it
+	if volConfig.RootOwnership != "" {
+		log.WithFields(log.Fields{
+			"qtree":     name,
+			"ownership": volConfig.RootOwnership,
+		}).Warning("The ontap-nas-economy driver does not support setting root ownership, ignoring.")
+	}
+
 	// Determine volume size in bytes
 	requestedSize, err := utils.ConvertSizeToBytes(volConfig.Size)
 	if err != nil {
@@ -330,7 +344,7 @@ func (d *NASQtreeStorageDriver) Create(
 	}
 
 	if tieringPolicy == "" {
-		tieringPolicy = d.API.TieringPolicyValue()
+		tieringPolicy = client.TieringPolicyValue()
 	}
 
 	if d.Config.AutoExportPolicy {
@@ -374,7 +388,7 @@ func (d *NASQtreeStorageDriver) Create(
 		}
 
 		// Create the qtree
-		qtreeResponse, err := d.API.QtreeCreate(name, flexvol, unixPermissions, exportPolicy, securityStyle)
+		qtreeResponse, err := client.QtreeCreate(name, flexvol, unixPermissions, exportPolicy, securityStyle)
 		if err = api.GetError(qtreeResponse, err); err != nil {
 
 			errMessage := fmt.Sprintf("ONTAP-NAS-QTREE pool %s/%s; Qtree creation failed %s/%s: %v", storagePool.Name,
@@ -400,7 +414,9 @@ func (d *NASQtreeStorageDriver) Create(
 }
 
 // Create a volume clone
-func (d *NASQtreeStorageDriver) CreateClone(volConfig *storage.VolumeConfig, storagePool *storage.Pool) error {
+func (d *NASQtreeStorageDriver) CreateClone(
+	ctx context.Context, volConfig *storage.VolumeConfig, storagePool *storage.Pool,
+) error {
 
 	name := volConfig.InternalName
 	source := volConfig.CloneSourceVolumeInternal
@@ -421,7 +437,9 @@ func (d *NASQtreeStorageDriver) CreateClone(volConfig *storage.VolumeConfig, sto
 	return fmt.Errorf("cloning is not supported by backend type %s", d.Name())
 }
 
-func (d *NASQtreeStorageDriver) Import(volConfig *storage.VolumeConfig, originalName string) error {
+func (d *NASQtreeStorageDriver) Import(
+	ctx context.Context, volConfig *storage.VolumeConfig, originalName string,
+) error {
 	return errors.New("import is not implemented")
 }
 
@@ -430,7 +448,7 @@ func (d *NASQtreeStorageDriver) Rename(name string, newName string) error {
 }
 
 // Destroy the volume
-func (d *NASQtreeStorageDriver) Destroy(name string) error {
+func (d *NASQtreeStorageDriver) Destroy(ctx context.Context, name string) error {
 
 	if d.Config.DebugTraceFlags["method"] {
 		fields := log.Fields{
@@ -442,6 +460,8 @@ func (d *NASQtreeStorageDriver) Destroy(name string) error {
 		defer log.WithFields(fields).Debug("<<<< Destroy")
 	}
 
+	client := d.API.WithContext(ctx)
+
 	// Ensure the deleted qtree reaping job doesn't interfere with this workflow
 	utils.Lock("destroy", d.sharedLockID)
 	defer utils.Unlock("destroy", d.sharedLockID)
@@ -449,7 +469,7 @@ func (d *NASQtreeStorageDriver) Destroy(name string) error {
 	// Generic user-facing message
 	deleteError := errors.New("volume deletion failed")
 
-	exists, flexvol, err := d.API.QtreeExists(name, d.FlexvolNamePrefix())
+	exists, flexvol, err := client.QtreeExists(name, d.FlexvolNamePrefix())
 	if err != nil {
 		log.Errorf("Error checking for existing qtree. %v", err)
 		return deleteError
@@ -469,17 +489,17 @@ func (d *NASQtreeStorageDriver) Destroy(name string) error {
 	}
 	deletedPath := fmt.Sprintf("/vol/%s/%s", flexvol, deletedName)
 
-	renameResponse, err := d.API.QtreeRename(path, deletedPath)
+	renameResponse, err := client.QtreeRename(path, deletedPath)
 	if err = api.GetError(renameResponse, err); err != nil {
 		log.Errorf("Qtree rename failed. %v", err)
 		return deleteError
 	}
 
 	// Destroy the qtree in the background.  If this fails, try to restore the original qtree name.
-	destroyResponse, err := d.API.QtreeDestroyAsync(deletedPath, true)
+	destroyResponse, err := client.QtreeDestroyAsync(deletedPath, true)
 	if err = api.GetError(destroyResponse, err); err != nil {
 		log.Errorf("Qtree async delete failed. %v", err)
-		defer d.API.QtreeRename(deletedPath, path)
+		defer client.QtreeRename(deletedPath, path)
 		return deleteError
 	}
 
@@ -489,7 +509,11 @@ func (d *NASQtreeStorageDriver) Destroy(name string) error {
 // Publish the volume to the host specified in publishInfo.  This method may or may not be running on the host
 // where the volume will be mounted, so it should limit itself to updating access rules, initiator groups, etc.
 // that require some host identity (but not locality) as well as storage controller API access.
-func (d *NASQtreeStorageDriver) Publish(volConfig *storage.VolumeConfig, publishInfo *utils.VolumePublishInfo) error {
+func (d *NASQtreeStorageDriver) Publish(
+	ctx context.Context, volConfig *storage.VolumeConfig, publishInfo *utils.VolumePublishInfo,
+) error {
+
+	client := d.API.WithContext(ctx)
 
 	name := volConfig.InternalName
 
@@ -504,7 +528,7 @@ func (d *NASQtreeStorageDriver) Publish(volConfig *storage.VolumeConfig, publish
 	}
 
 	// Check if qtree exists, and find its Flexvol so we can build the export location
-	exists, flexvol, err := d.API.QtreeExists(name, d.FlexvolNamePrefix())
+	exists, flexvol, err := client.QtreeExists(name, d.FlexvolNamePrefix())
 	if err != nil {
 		log.Errorf("Error checking for existing qtree. %v", err)
 		return errors.New("volume mount failed")
@@ -601,7 +625,9 @@ func (d *NASQtreeStorageDriver) GetSnapshots(volConfig *storage.VolumeConfig) ([
 }
 
 // CreateSnapshot creates a snapshot for the given volume
-func (d *NASQtreeStorageDriver) CreateSnapshot(snapConfig *storage.SnapshotConfig) (*storage.Snapshot, error) {
+func (d *NASQtreeStorageDriver) CreateSnapshot(
+	ctx context.Context, snapConfig *storage.SnapshotConfig,
+) (*storage.Snapshot, error) {
 
 	if d.Config.DebugTraceFlags["method"] {
 		fields := log.Fields{
@@ -618,7 +644,7 @@ func (d *NASQtreeStorageDriver) CreateSnapshot(snapConfig *storage.SnapshotConfi
 }
 
 // RestoreSnapshot restores a volume (in place) from a snapshot.
-func (d *NASQtreeStorageDriver) RestoreSnapshot(snapConfig *storage.SnapshotConfig) error {
+func (d *NASQtreeStorageDriver) RestoreSnapshot(ctx context.Context, snapConfig *storage.SnapshotConfig) error {
 
 	if d.Config.DebugTraceFlags["method"] {
 		fields := log.Fields{
@@ -635,7 +661,7 @@ func (d *NASQtreeStorageDriver) RestoreSnapshot(snapConfig *storage.SnapshotConf
 }
 
 // DeleteSnapshot creates a snapshot of a volume.
-func (d *NASQtreeStorageDriver) DeleteSnapshot(snapConfig *storage.SnapshotConfig) error {
+func (d *NASQtreeStorageDriver) DeleteSnapshot(ctx context.Context, snapConfig *storage.SnapshotConfig) error {
 
 	if d.Config.DebugTraceFlags["method"] {
 		fields := log.Fields{
@@ -670,7 +696,7 @@ func (d *NASQtreeStorageDriver) Get(name string) error {
 	}
 	if !exists {
 		log.WithField("qtree", name).Debug("Qtree not found.")
-		return getError
+		return utils.NotFoundError(getError.Error())
 	}
 
 	log.WithFields(log.Fields{"qtree": name, "flexvol": flexvol}).Debug("Qtree found.")
@@ -678,6 +704,22 @@ func (d *NASQtreeStorageDriver) Get(name string) error {
 	return nil
 }
 
+// GetVolumeCondition reports a volume whose qtree is missing, or whose Flexvol isn't online, as abnormal.
+func (d *NASQtreeStorageDriver) GetVolumeCondition(volConfig *storage.VolumeConfig) (*storage.VolumeCondition, error) {
+
+	exists, flexvol, err := d.API.QtreeExists(volConfig.InternalName, d.FlexvolNamePrefix())
+	if err != nil {
+		return nil, fmt.Errorf("could not determine if qtree %s exists: %v", volConfig.InternalName, err)
+	}
+	if !exists {
+		return &storage.VolumeCondition{
+			Abnormal: true,
+			Message:  fmt.Sprintf("qtree %s not found", volConfig.InternalName),
+		}, nil
+	}
+	return getOntapVolumeCondition(flexvol, d.API)
+}
+
 // ensureFlexvolForQtree accepts a set of Flexvol characteristics and either finds one to contain a new
 // qtree or it creates a new Flexvol with the needed attributes.
 func (d *NASQtreeStorageDriver) ensureFlexvolForQtree(
@@ -1265,6 +1307,11 @@ func (d *NASQtreeStorageDriver) GetStorageBackendSpecs(backend *storage.Backend)
 	return getStorageBackendSpecsCommon(backend, d.physicalPools, d.virtualPools, d.backendName())
 }
 
+// GetStorageCapacity reports the free space in the aggregates behind the backend's pools
+func (d *NASQtreeStorageDriver) GetStorageCapacity() (*storage.StorageCapacity, error) {
+	return getStorageCapacityCommon(d, d.physicalPools, d.virtualPools)
+}
+
 // Retrieve storage backend physical pools
 func (d *NASQtreeStorageDriver) GetStorageBackendPhysicalPoolNames() []string {
 	return getStorageBackendPhysicalPoolNamesCommon(d.physicalPools)
@@ -1547,6 +1594,9 @@ func (d *NASQtreeStorageDriver) GetUpdateType(driverOrig storage.Driver) *roarin
 		bitmap.Add(storage.UsernameChange)
 	}
 
+	if drivers.StoragePrefixChanged(d.Config.CommonStorageDriverConfig, dOrig.Config.CommonStorageDriverConfig) {
+		bitmap.Add(storage.PrefixChange)
+	}
 	return bitmap
 }
 
@@ -1676,7 +1726,9 @@ func NewResizeTask(d *NASQtreeStorageDriver, tasks []func()) *HousekeepingTask {
 }
 
 // Resize expands the Flexvol containing the Qtree and updates the Qtree quota.
-func (d *NASQtreeStorageDriver) Resize(volConfig *storage.VolumeConfig, sizeBytes uint64) error {
+func (d *NASQtreeStorageDriver) Resize(ctx context.Context, volConfig *storage.VolumeConfig, sizeBytes uint64) error {
+
+	client := d.API.WithContext(ctx)
 
 	name := volConfig.InternalName
 	if d.Config.DebugTraceFlags["method"] {
@@ -1698,7 +1750,7 @@ func (d *NASQtreeStorageDriver) Resize(volConfig *storage.VolumeConfig, sizeByte
 	resizeError := errors.New("storage driver failed to resize the volume")
 
 	// Check that volume exists
-	exists, flexvol, err := d.API.QtreeExists(name, d.FlexvolNamePrefix())
+	exists, flexvol, err := client.QtreeExists(name, d.FlexvolNamePrefix())
 	if err != nil {
 		log.WithField("error", err).Error("Error checking for existing volume.")
 		return resizeError
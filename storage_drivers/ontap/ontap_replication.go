@@ -0,0 +1,344 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package ontap
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netapp/trident/storage"
+	sa "github.com/netapp/trident/storage_attribute"
+	"github.com/netapp/trident/storage_drivers/ontap/api"
+	"github.com/netapp/trident/storage_drivers/ontap/api/azgo"
+)
+
+const (
+	SnapmirrorPolicyMirrorAllSnapshots = "MirrorAllSnapshots"
+
+	SnapmirrorMirrorStateSnapmirrored = "snapmirrored"
+	SnapmirrorMirrorStateBrokenOff    = "broken-off"
+	SnapmirrorStatusIdle              = "idle"
+	SnapmirrorStatusQuiesced          = "quiesced"
+
+	snapmirrorTransferTimeout = 10 * time.Minute
+)
+
+// canReplicateOntapVolume reports whether volumes managed by the source driver can be SnapMirrored
+// to the destination driver.  Both drivers must be the same ONTAP driver type, so that the replica
+// has the layout the destination driver expects.  The SVMs must already be peered if they differ.
+func canReplicateOntapVolume(destination StorageDriver, source storage.Driver) bool {
+	sourceDriver, ok := source.(StorageDriver)
+	return ok && sourceDriver.Name() == destination.Name()
+}
+
+// createOntapReplica creates a data protection Flexvol on one of the physical pools that can satisfy
+// the storage pool, and it starts the baseline SnapMirror transfer from the source Flexvol.
+func createOntapReplica(
+	volConfig, sourceVolConfig *storage.VolumeConfig, storagePool *storage.Pool,
+	physicalPools, virtualPools map[string]*storage.Pool, destination, source StorageDriver,
+) error {
+
+	client := destination.GetAPI()
+	sourceConfig := source.GetConfig()
+	name := volConfig.InternalName
+
+	if destination.GetConfig().DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":       "createOntapReplica",
+			"Type":         "ontap_replication",
+			"name":         name,
+			"sourceSVM":    sourceConfig.SVM,
+			"sourceVolume": sourceVolConfig.InternalName,
+		}
+		log.WithFields(fields).Debug(">>>> createOntapReplica")
+		defer log.WithFields(fields).Debug("<<<< createOntapReplica")
+	}
+
+	// The destination must be at least as large as the source
+	sourceSize, err := source.GetAPI().VolumeSize(sourceVolConfig.InternalName)
+	if err != nil {
+		return fmt.Errorf("could not get size of source volume %s; %v", sourceVolConfig.InternalName, err)
+	}
+	size := strconv.Itoa(sourceSize)
+
+	physicalPoolsForCreate, err := getPoolsForCreate(volConfig, storagePool, map[string]sa.Request{},
+		physicalPools, virtualPools)
+	if err != nil {
+		return err
+	}
+
+	created := false
+	for _, physicalPool := range physicalPoolsForCreate {
+		createResponse, err := client.VolumeCreateDP(name, physicalPool.Name, size)
+		if err = api.GetError(createResponse, err); err != nil {
+			log.WithFields(log.Fields{
+				"volume":    name,
+				"aggregate": physicalPool.Name,
+				"error":     err,
+			}).Warn("Could not create replica volume.")
+			continue
+		}
+		created = true
+		break
+	}
+	if !created {
+		return fmt.Errorf("could not create replica volume %s on any pool", name)
+	}
+
+	createResponse, err := client.SnapmirrorCreate(sourceConfig.SVM, sourceVolConfig.InternalName, name,
+		SnapmirrorPolicyMirrorAllSnapshots)
+	if err = api.GetError(createResponse, err); err != nil {
+		return fmt.Errorf("error creating SnapMirror relationship to volume %s; %v", name, err)
+	}
+
+	initResponse, err := client.SnapmirrorInitialize(sourceConfig.SVM, sourceVolConfig.InternalName, name)
+	if err = api.GetError(initResponse, err); err != nil {
+		return fmt.Errorf("error initializing SnapMirror relationship to volume %s; %v", name, err)
+	}
+
+	log.WithFields(log.Fields{
+		"volume":       name,
+		"sourceSVM":    sourceConfig.SVM,
+		"sourceVolume": sourceVolConfig.InternalName,
+	}).Debug("Started SnapMirror baseline transfer.")
+
+	return nil
+}
+
+// getOntapReplicaProgress returns the number of bytes transferred to a replica and whether the
+// baseline transfer is complete.
+func getOntapReplicaProgress(volConfig *storage.VolumeConfig, client *api.Client) (uint64, bool, error) {
+
+	info, err := client.SnapmirrorGet(volConfig.InternalName)
+	if err != nil {
+		return 0, false, err
+	}
+
+	if info.MirrorStatePtr != nil && info.MirrorState() == SnapmirrorMirrorStateSnapmirrored &&
+		info.RelationshipStatusPtr != nil && info.RelationshipStatus() == SnapmirrorStatusIdle {
+		var transferred uint64
+		if info.TotalTransferBytesPtr != nil {
+			transferred = info.TotalTransferBytes()
+		}
+		return transferred, true, nil
+	}
+
+	if info.LastTransferErrorPtr != nil && info.LastTransferError() != "" &&
+		info.RelationshipStatusPtr != nil && info.RelationshipStatus() == SnapmirrorStatusIdle {
+		return 0, false, fmt.Errorf("SnapMirror transfer to volume %s failed; %s",
+			volConfig.InternalName, info.LastTransferError())
+	}
+
+	var transferred uint64
+	if info.SnapshotProgressPtr != nil {
+		transferred = info.SnapshotProgress()
+	}
+	return transferred, false, nil
+}
+
+// waitForSnapmirrorStatus polls a SnapMirror relationship until it reaches the specified status.
+func waitForSnapmirrorStatus(volumeName, status string, client *api.Client) error {
+
+	checkStatus := func() error {
+		info, err := client.SnapmirrorGet(volumeName)
+		if err != nil {
+			return err
+		}
+		if info.RelationshipStatusPtr == nil || info.RelationshipStatus() != status {
+			return fmt.Errorf("SnapMirror relationship to volume %s is not %s", volumeName, status)
+		}
+		return nil
+	}
+	statusNotify := func(err error, duration time.Duration) {
+		log.WithFields(log.Fields{
+			"volume":    volumeName,
+			"status":    status,
+			"increment": duration,
+		}).Debug("SnapMirror relationship not yet in desired status, waiting.")
+	}
+	statusBackoff := backoff.NewExponentialBackOff()
+	statusBackoff.InitialInterval = 1 * time.Second
+	statusBackoff.MaxInterval = 30 * time.Second
+	statusBackoff.MaxElapsedTime = snapmirrorTransferTimeout
+
+	return backoff.RetryNotify(checkStatus, statusBackoff, statusNotify)
+}
+
+// promoteOntapReplica suspends client access to the source volume, performs a final SnapMirror
+// transfer, breaks and deletes the relationship, and releases the source volume from it, leaving the
+// replica as an independent writable volume.  Suspending the source ensures no write is made after the
+// final transfer; if the promotion fails, access to the source is restored.
+func promoteOntapReplica(
+	volConfig *storage.VolumeConfig, destination, source StorageDriver, suspendSource, resumeSource func() error,
+) (err error) {
+
+	client := destination.GetAPI()
+	name := volConfig.InternalName
+
+	if err = suspendSource(); err != nil {
+		return fmt.Errorf("could not suspend access to the source of volume %s; %v", name, err)
+	}
+	defer func() {
+		if err != nil {
+			if resumeErr := resumeSource(); resumeErr != nil {
+				log.WithField("volume", name).Errorf("Could not restore access to the source volume; %v",
+					resumeErr)
+			}
+		}
+	}()
+
+	updateResponse, err := client.SnapmirrorUpdate(name)
+	if err = api.GetError(updateResponse, err); err != nil {
+		return fmt.Errorf("error updating SnapMirror relationship to volume %s; %v", name, err)
+	}
+	if err = waitForSnapmirrorStatus(name, SnapmirrorStatusIdle, client); err != nil {
+		return fmt.Errorf("final SnapMirror transfer to volume %s did not complete; %v", name, err)
+	}
+
+	quiesceResponse, err := client.SnapmirrorQuiesce(name)
+	if err = api.GetError(quiesceResponse, err); err != nil {
+		return fmt.Errorf("error quiescing SnapMirror relationship to volume %s; %v", name, err)
+	}
+	if err = waitForSnapmirrorStatus(name, SnapmirrorStatusQuiesced, client); err != nil {
+		return fmt.Errorf("SnapMirror relationship to volume %s did not quiesce; %v", name, err)
+	}
+
+	breakResponse, err := client.SnapmirrorBreak(name)
+	if err = api.GetError(breakResponse, err); err != nil {
+		return fmt.Errorf("error breaking SnapMirror relationship to volume %s; %v", name, err)
+	}
+
+	deleteOntapSnapmirrorRelationship(name, destination, source)
+
+	log.WithField("volume", name).Debug("Promoted SnapMirror replica.")
+	return nil
+}
+
+// deleteOntapSnapmirrorRelationship deletes a SnapMirror relationship at both ends.  Failures are
+// logged but not returned, since a stale relationship doesn't prevent using either volume.
+func deleteOntapSnapmirrorRelationship(name string, destination, source StorageDriver) {
+
+	deleteResponse, err := destination.GetAPI().SnapmirrorDelete(name)
+	if err = api.GetError(deleteResponse, err); err != nil {
+		log.WithFields(log.Fields{
+			"volume": name,
+			"error":  err,
+		}).Warn("Could not delete SnapMirror relationship.")
+	}
+
+	releaseResponse, err := source.GetAPI().SnapmirrorRelease(destination.GetConfig().SVM, name)
+	if err = api.GetError(releaseResponse, err); err != nil {
+		log.WithFields(log.Fields{
+			"volume": name,
+			"error":  err,
+		}).Warn("Could not release SnapMirror relationship at the source.")
+	}
+}
+
+// deleteOntapReplica stops replication to a replica and destroys it.  Access to the source volume is
+// restored, in case it was suspended to promote the replica.
+func deleteOntapReplica(
+	volConfig *storage.VolumeConfig, destination, source StorageDriver, resumeSource func() error,
+) error {
+
+	client := destination.GetAPI()
+	name := volConfig.InternalName
+
+	if err := resumeSource(); err != nil {
+		log.WithField("volume", name).Errorf("Could not restore access to the source volume; %v", err)
+	}
+
+	if _, err := client.SnapmirrorGet(name); err == nil {
+		quiesceResponse, err := client.SnapmirrorQuiesce(name)
+		if err = api.GetError(quiesceResponse, err); err != nil {
+			log.WithField("volume", name).Warnf("Could not quiesce SnapMirror relationship; %v", err)
+		}
+		breakResponse, err := client.SnapmirrorBreak(name)
+		if err = api.GetError(breakResponse, err); err != nil {
+			log.WithField("volume", name).Warnf("Could not break SnapMirror relationship; %v", err)
+		}
+		deleteOntapSnapmirrorRelationship(name, destination, source)
+	}
+
+	destroyResponse, err := client.VolumeDestroy(name, true)
+	if err != nil {
+		return fmt.Errorf("error destroying replica volume %s; %v", name, err)
+	}
+	if zerr := api.NewZapiError(destroyResponse); !zerr.IsPassed() && zerr.Code() != azgo.EVOLUMEDOESNOTEXIST {
+		return fmt.Errorf("error destroying replica volume %s; %v", name, zerr)
+	}
+
+	return nil
+}
+
+// suspendOntapNASReplicaSource unmounts the source volume of a replica from its junction, so NFS
+// clients can't write to it while the final SnapMirror transfer runs.
+func suspendOntapNASReplicaSource(sourceVolConfig *storage.VolumeConfig, source StorageDriver) error {
+
+	unmountResponse, err := source.GetAPI().VolumeUnmount(sourceVolConfig.InternalName, true)
+	if err = api.GetError(unmountResponse, err); err != nil {
+		return fmt.Errorf("error unmounting volume %s; %v", sourceVolConfig.InternalName, err)
+	}
+	return nil
+}
+
+// resumeOntapNASReplicaSource mounts the source volume of a replica at its junction again, if it
+// isn't mounted.
+func resumeOntapNASReplicaSource(sourceVolConfig *storage.VolumeConfig, source StorageDriver) error {
+
+	name := sourceVolConfig.InternalName
+	flexvol, err := source.GetAPI().VolumeGet(name)
+	if err != nil {
+		return fmt.Errorf("error reading volume %s; %v", name, err)
+	}
+	if flexvol.VolumeIdAttributesPtr != nil && flexvol.VolumeIdAttributesPtr.JunctionPathPtr != nil &&
+		flexvol.VolumeIdAttributesPtr.JunctionPath() != "" {
+		return nil
+	}
+
+	junctionPath := sourceVolConfig.AccessInfo.NfsPath
+	if junctionPath == "" {
+		junctionPath = "/" + name
+	}
+	mountResponse, err := source.GetAPI().VolumeMount(name, junctionPath)
+	if err = api.GetError(mountResponse, err); err != nil {
+		return fmt.Errorf("error mounting volume %s to junction %s; %v", name, junctionPath, err)
+	}
+	return nil
+}
+
+// suspendOntapSANReplicaSource takes the LUN of a replica's source volume offline, so initiators
+// can't write to it while the final SnapMirror transfer runs.
+func suspendOntapSANReplicaSource(sourceVolConfig *storage.VolumeConfig, source StorageDriver) error {
+
+	path := lunPath(sourceVolConfig.InternalName)
+	offlineResponse, err := source.GetAPI().LunOffline(path)
+	if err = api.GetError(offlineResponse, err); err != nil {
+		return fmt.Errorf("error taking LUN %s offline; %v", path, err)
+	}
+	return nil
+}
+
+// resumeOntapSANReplicaSource brings the LUN of a replica's source volume online again, if it is
+// offline.
+func resumeOntapSANReplicaSource(sourceVolConfig *storage.VolumeConfig, source StorageDriver) error {
+
+	path := lunPath(sourceVolConfig.InternalName)
+	lun, err := source.GetAPI().LunGet(path)
+	if err != nil {
+		return fmt.Errorf("error reading LUN %s; %v", path, err)
+	}
+	if lun.OnlinePtr == nil || lun.Online() {
+		return nil
+	}
+
+	onlineResponse, err := source.GetAPI().LunOnline(path)
+	if err = api.GetError(onlineResponse, err); err != nil {
+		return fmt.Errorf("error bringing LUN %s online; %v", path, err)
+	}
+	return nil
+}
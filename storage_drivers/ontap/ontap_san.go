@@ -3,6 +3,7 @@
 package ontap
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"strings"
@@ -19,6 +20,12 @@ import (
 	"github.com/netapp/trident/utils"
 )
 
+// Journal steps of a volume create
+const (
+	sanJournalStepCreateFlexvol = "createFlexvol"
+	sanJournalStepCreateLUN     = "createLUN"
+)
+
 func lunPath(name string) string {
 	return fmt.Sprintf("/vol/%v/lun0", name)
 }
@@ -156,9 +163,11 @@ func (d *SANStorageDriver) validate() error {
 
 // Create a volume+LUN with the specified options
 func (d *SANStorageDriver) Create(
-	volConfig *storage.VolumeConfig, storagePool *storage.Pool, volAttributes map[string]sa.Request,
+	ctx context.Context, volConfig *storage.VolumeConfig, storagePool *storage.Pool, volAttributes map[string]sa.Request,
 ) error {
 
+	client := d.API.WithContext(ctx)
+
 	name := volConfig.InternalName
 
 	var fstype string
@@ -175,7 +184,7 @@ func (d *SANStorageDriver) Create(
 	}
 
 	// If the volume already exists, bail out
-	volExists, err := d.API.VolumeExists(name)
+	volExists, err := client.VolumeExists(name)
 	if err != nil {
 		return fmt.Errorf("error checking for existing volume: %v", err)
 	}
@@ -189,6 +198,9 @@ func (d *SANStorageDriver) Create(
 		return err
 	}
 
+	// Use the pool's mount options unless the volume or its storage class specified some
+	applyPoolMountOptions(volConfig, storagePool)
+
 	// Determine volume size in bytes
 	requestedSize, err := utils.ConvertSizeToBytes(volConfig.Size)
 	if err != nil {
@@ -244,7 +256,7 @@ func (d *SANStorageDriver) Create(
 	}
 
 	if tieringPolicy == "" {
-		tieringPolicy = d.API.TieringPolicyValue()
+		tieringPolicy = client.TieringPolicyValue()
 	}
 
 	log.WithFields(log.Fields{
@@ -264,6 +276,13 @@ func (d *SANStorageDriver) Create(
 	createErrors := make([]error, 0)
 	physicalPoolNames := make([]string, 0)
 
+	// Each step is journaled, so that a create that fails or is interrupted partway can be undone.  Without
+	// a journal from the orchestrator, the steps are only tracked here.
+	journal := storage.JournalFromContext(ctx)
+	if journal == nil {
+		journal = storage.NewOperationJournal("", storagePool.Name, nil)
+	}
+
 	for _, physicalPool := range physicalPools {
 		aggregate := physicalPool.Name
 		physicalPoolNames = append(physicalPoolNames, aggregate)
@@ -276,7 +295,10 @@ func (d *SANStorageDriver) Create(
 		}
 
 		// Create the volume
-		volCreateResponse, err := d.API.VolumeCreate(
+		if err = journal.Begin(sanJournalStepCreateFlexvol, name); err != nil {
+			return err
+		}
+		volCreateResponse, err := client.VolumeCreate(
 			name, aggregate, size, spaceReserve, snapshotPolicy, unixPermissions,
 			exportPolicy, securityStyle, tieringPolicy, enableEncryption, snapshotReserveInt)
 
@@ -294,31 +316,43 @@ func (d *SANStorageDriver) Create(
 				aggregate, name, err)
 			log.Error(errMessage)
 			createErrors = append(createErrors, fmt.Errorf(errMessage))
+			if err = d.rollbackCreate(client, journal); err != nil {
+				return err
+			}
 			continue
 		}
+		journal.Complete(sanJournalStepCreateFlexvol, name)
 
 		lunPath := lunPath(name)
 		osType := "linux"
 
 		// Create the LUN
-		lunCreateResponse, err := d.API.LunCreate(lunPath, int(sizeBytes), osType, false, spaceAllocation)
+		if err = journal.Begin(sanJournalStepCreateLUN, lunPath); err != nil {
+			_ = d.rollbackCreate(client, journal)
+			return err
+		}
+		lunCreateResponse, err := client.LunCreate(lunPath, int(sizeBytes), osType, false, spaceAllocation)
 		if err = api.GetError(lunCreateResponse, err); err != nil {
 			errMessage := fmt.Sprintf("ONTAP-SAN pool %s/%s; error creating LUN %s: %v", storagePool.Name,
 				aggregate, name, err)
 			log.Error(errMessage)
 			createErrors = append(createErrors, fmt.Errorf(errMessage))
+			if err = d.rollbackCreate(client, journal); err != nil {
+				return err
+			}
 			continue
 		}
+		journal.Complete(sanJournalStepCreateLUN, lunPath)
 
 		// Save the fstype in a LUN attribute so we know what to do in Attach
-		attrResponse, err := d.API.LunSetAttribute(lunPath, LUNAttributeFSType, fstype)
+		attrResponse, err := client.LunSetAttribute(lunPath, LUNAttributeFSType, fstype)
 		if err = api.GetError(attrResponse, err); err != nil {
-			defer d.API.LunDestroy(lunPath)
+			_ = d.rollbackCreate(client, journal)
 			return fmt.Errorf("ONTAP-SAN pool %s/%s; error saving file system type for LUN %s: %v", storagePool.Name,
 				aggregate, name, err)
 		}
 		// Save the context
-		attrResponse, err = d.API.LunSetAttribute(lunPath, "context", string(d.Config.DriverContext))
+		attrResponse, err = client.LunSetAttribute(lunPath, "context", string(d.Config.DriverContext))
 		if err = api.GetError(attrResponse, err); err != nil {
 			log.WithField("name", name).Warning("Failed to save the driver context attribute for new volume.")
 		}
@@ -326,10 +360,10 @@ func (d *SANStorageDriver) Create(
 		// Resize FlexVol to be the same size or bigger than LUN because ONTAP creates
 		// larger LUNs sometimes based on internal geometry
 		lunSize := uint64(lunCreateResponse.Result.ActualSize())
-		if initialVolumeSize, err := d.API.VolumeSize(name); err != nil {
+		if initialVolumeSize, err := client.VolumeSize(name); err != nil {
 			log.WithField("name", name).Warning("Failed to get volume size.")
 		} else if lunSize != uint64(initialVolumeSize) {
-			volumeSizeResponse, err := d.API.VolumeSetSize(name, strconv.FormatUint(lunSize, 10))
+			volumeSizeResponse, err := client.VolumeSetSize(name, strconv.FormatUint(lunSize, 10))
 			if err = api.GetError(volumeSizeResponse, err); err != nil {
 				volConfig.Size = strconv.FormatUint(uint64(initialVolumeSize), 10)
 				log.WithFields(log.Fields{
@@ -337,7 +371,7 @@ func (d *SANStorageDriver) Create(
 					"initialVolumeSize": initialVolumeSize,
 					"lunSize":           lunSize}).Warning("Failed to resize new volume to LUN size.")
 			} else {
-				if adjustedVolumeSize, err := d.API.VolumeSize(name); err != nil {
+				if adjustedVolumeSize, err := client.VolumeSize(name); err != nil {
 					log.WithField("name", name).Warning("Failed to get volume size after the second resize operation.")
 				} else {
 					volConfig.Size = strconv.FormatUint(uint64(adjustedVolumeSize), 10)
@@ -356,7 +390,11 @@ func (d *SANStorageDriver) Create(
 }
 
 // Create a volume clone
-func (d *SANStorageDriver) CreateClone(volConfig *storage.VolumeConfig, storagePool *storage.Pool) error {
+func (d *SANStorageDriver) CreateClone(
+	ctx context.Context, volConfig *storage.VolumeConfig, storagePool *storage.Pool,
+) error {
+
+	client := d.API.WithContext(ctx)
 
 	name := volConfig.InternalName
 	source := volConfig.CloneSourceVolumeInternal
@@ -403,10 +441,10 @@ func (d *SANStorageDriver) CreateClone(volConfig *storage.VolumeConfig, storageP
 	}
 
 	log.WithField("splitOnClone", split).Debug("Creating volume clone.")
-	return CreateOntapClone(name, source, snapshot, split, &d.Config, d.API)
+	return CreateOntapClone(name, source, snapshot, split, &d.Config, client)
 }
 
-func (d *SANStorageDriver) Import(volConfig *storage.VolumeConfig, originalName string) error {
+func (d *SANStorageDriver) Import(ctx context.Context, volConfig *storage.VolumeConfig, originalName string) error {
 	if d.Config.DebugTraceFlags["method"] {
 		fields := log.Fields{
 			"Method":       "Import",
@@ -419,18 +457,87 @@ func (d *SANStorageDriver) Import(volConfig *storage.VolumeConfig, originalName
 		defer log.WithFields(fields).Debug("<<<< Import")
 	}
 
+	client := d.API.WithContext(ctx)
+
+	lunInfo, err := d.validateImport(volConfig, originalName)
+	if err != nil {
+		return err
+	}
+	targetPath := "/vol/" + originalName + "/lun0"
+
+	// Rename the volume or LUN if Trident will manage its lifecycle
+	if !volConfig.ImportNotManaged {
+		if lunInfo.Path() != targetPath {
+			renameResponse, err := client.LunRename(lunInfo.Path(), targetPath)
+			if err = api.GetError(renameResponse, err); err != nil {
+				log.WithField("path", lunInfo.Path()).Errorf("Could not import volume, rename LUN failed: %v", err)
+				return fmt.Errorf("LUN path %s rename failed: %v", lunInfo.Path(), err)
+			}
+		}
+
+		renameResponse, err := client.VolumeRename(originalName, volConfig.InternalName)
+		if err = api.GetError(renameResponse, err); err != nil {
+			log.WithField("originalName", originalName).Errorf("Could not import volume, rename volume failed: %v", err)
+			return fmt.Errorf("volume %s rename failed: %v", originalName, err)
+		}
+	}
+
+	return nil
+}
+
+// ImportCheck runs the checks for importing a volume and returns the changes the import would make
+// to the volume, without making them.
+func (d *SANStorageDriver) ImportCheck(volConfig *storage.VolumeConfig, originalName string) ([]string, error) {
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":       "ImportCheck",
+			"Type":         "SANStorageDriver",
+			"originalName": originalName,
+			"newName":      volConfig.InternalName,
+			"notManaged":   volConfig.ImportNotManaged,
+		}
+		log.WithFields(fields).Debug(">>>> ImportCheck")
+		defer log.WithFields(fields).Debug("<<<< ImportCheck")
+	}
+
+	lunInfo, err := d.validateImport(volConfig, originalName)
+	if err != nil {
+		return nil, err
+	}
+	targetPath := "/vol/" + originalName + "/lun0"
+
+	changes := make([]string, 0)
+	if !volConfig.ImportNotManaged {
+		if err := checkImportRename(originalName, volConfig.InternalName, d.API); err != nil {
+			return nil, err
+		}
+		if lunInfo.Path() != targetPath {
+			changes = append(changes, fmt.Sprintf("rename LUN %s to %s", lunInfo.Path(), targetPath))
+		}
+		changes = append(changes, fmt.Sprintf("rename volume %s to %s", originalName, volConfig.InternalName))
+	}
+
+	return changes, nil
+}
+
+// validateImport ensures a volume may be imported, sets the volume config's size from its LUN, and
+// returns the LUN.
+func (d *SANStorageDriver) validateImport(
+	volConfig *storage.VolumeConfig, originalName string,
+) (*azgo.LunInfoType, error) {
+
 	// Ensure the volume exists
 	flexvol, err := d.API.VolumeGet(originalName)
 	if err != nil {
-		return err
+		return nil, err
 	} else if flexvol == nil {
-		return fmt.Errorf("volume %s not found", originalName)
+		return nil, fmt.Errorf("volume %s not found", originalName)
 	}
 
 	// Ensure the volume has only one LUN
 	lunInfo, err := d.API.LunGet("/vol/" + originalName + "/*")
 	if err != nil {
-		return err
+		return nil, err
 	}
 	targetPath := "/vol/" + originalName + "/lun0"
 
@@ -439,55 +546,40 @@ func (d *SANStorageDriver) Import(volConfig *storage.VolumeConfig, originalName
 		volumeIdAttrs := flexvol.VolumeIdAttributes()
 		if volumeIdAttrs.TypePtr != nil && volumeIdAttrs.Type() != "rw" {
 			log.WithField("originalName", originalName).Error("Could not import volume, type is not rw.")
-			return fmt.Errorf("volume %s type is %s, not rw", originalName, volumeIdAttrs.Type())
+			return nil, fmt.Errorf("volume %s type is %s, not rw", originalName, volumeIdAttrs.Type())
 		}
 	}
 
 	// The LUN should be online
 	if lunInfo.OnlinePtr != nil {
 		if !lunInfo.Online() {
-			return fmt.Errorf("LUN %s is not online", lunInfo.Path())
+			return nil, fmt.Errorf("LUN %s is not online", lunInfo.Path())
 		}
 	}
 
 	// Use the LUN size
 	if lunInfo.SizePtr == nil {
 		log.WithField("originalName", originalName).Errorf("Could not import volume, size not available")
-		return fmt.Errorf("volume %s size not available", originalName)
+		return nil, fmt.Errorf("volume %s size not available", originalName)
 	}
 	volConfig.Size = strconv.FormatInt(int64(lunInfo.Size()), 10)
 
-	// Rename the volume or LUN if Trident will manage its lifecycle
-	if !volConfig.ImportNotManaged {
-		if lunInfo.Path() != targetPath {
-			renameResponse, err := d.API.LunRename(lunInfo.Path(), targetPath)
-			if err = api.GetError(renameResponse, err); err != nil {
-				log.WithField("path", lunInfo.Path()).Errorf("Could not import volume, rename LUN failed: %v", err)
-				return fmt.Errorf("LUN path %s rename failed: %v", lunInfo.Path(), err)
-			}
-		}
-
-		renameResponse, err := d.API.VolumeRename(originalName, volConfig.InternalName)
-		if err = api.GetError(renameResponse, err); err != nil {
-			log.WithField("originalName", originalName).Errorf("Could not import volume, rename volume failed: %v", err)
-			return fmt.Errorf("volume %s rename failed: %v", originalName, err)
-		}
-	} else {
+	if volConfig.ImportNotManaged {
 		// Volume import is not managed by Trident
 		if flexvol.VolumeIdAttributesPtr == nil {
-			return fmt.Errorf("unable to read volume id attributes of volume %s", originalName)
+			return nil, fmt.Errorf("unable to read volume id attributes of volume %s", originalName)
 		}
 		if lunInfo.Path() != targetPath {
-			return fmt.Errorf("Could not import volume, LUN is nammed incorrectly: %s", lunInfo.Path())
+			return nil, fmt.Errorf("Could not import volume, LUN is nammed incorrectly: %s", lunInfo.Path())
 		}
 		if lunInfo.MappedPtr != nil {
 			if !lunInfo.Mapped() {
-				return fmt.Errorf("Could not import volume, LUN is not mapped: %s", lunInfo.Path())
+				return nil, fmt.Errorf("Could not import volume, LUN is not mapped: %s", lunInfo.Path())
 			}
 		}
 	}
 
-	return nil
+	return lunInfo, nil
 }
 
 func (d *SANStorageDriver) Rename(name string, newName string) error {
@@ -511,8 +603,13 @@ func (d *SANStorageDriver) Rename(name string, newName string) error {
 	return nil
 }
 
+// CloneDependsOnSource reports whether a clone is a FlexClone that still shares storage with its source volume.
+func (d *SANStorageDriver) CloneDependsOnSource(volConfig *storage.VolumeConfig) (bool, error) {
+	return cloneDependsOnSource(volConfig, d.API)
+}
+
 // Destroy the requested (volume,lun) storage tuple
-func (d *SANStorageDriver) Destroy(name string) error {
+func (d *SANStorageDriver) Destroy(ctx context.Context, name string) error {
 
 	if d.Config.DebugTraceFlags["method"] {
 		fields := log.Fields{
@@ -524,6 +621,8 @@ func (d *SANStorageDriver) Destroy(name string) error {
 		defer log.WithFields(fields).Debug("<<<< Destroy")
 	}
 
+	client := d.API.WithContext(ctx)
+
 	var (
 		err           error
 		iSCSINodeName string
@@ -531,7 +630,7 @@ func (d *SANStorageDriver) Destroy(name string) error {
 	)
 
 	// Validate Flexvol exists before trying to destroy
-	volExists, err := d.API.VolumeExists(name)
+	volExists, err := client.VolumeExists(name)
 	if err != nil {
 		return fmt.Errorf("error checking for existing volume: %v", err)
 	}
@@ -543,7 +642,7 @@ func (d *SANStorageDriver) Destroy(name string) error {
 	if d.Config.DriverContext == tridentconfig.ContextDocker {
 
 		// Get target info
-		iSCSINodeName, _, err = GetISCSITargetInfo(d.API, &d.Config)
+		iSCSINodeName, _, err = GetISCSITargetInfo(client, &d.Config)
 		if err != nil {
 			log.WithField("error", err).Error("Could not get target info.")
 			return err
@@ -551,7 +650,7 @@ func (d *SANStorageDriver) Destroy(name string) error {
 
 		// Get the LUN ID
 		lunPath := fmt.Sprintf("/vol/%s/lun0", name)
-		lunMapResponse, err := d.API.LunMapListInfo(lunPath)
+		lunMapResponse, err := client.LunMapListInfo(lunPath)
 		if err != nil {
 			return fmt.Errorf("error reading LUN maps for volume %s: %v", name, err)
 		}
@@ -570,7 +669,7 @@ func (d *SANStorageDriver) Destroy(name string) error {
 	}
 
 	// Delete the Flexvol & LUN
-	volDestroyResponse, err := d.API.VolumeDestroy(name, true)
+	volDestroyResponse, err := client.VolumeDestroy(name, true)
 	if err != nil {
 		return fmt.Errorf("error destroying volume %v: %v", name, err)
 	}
@@ -586,10 +685,76 @@ func (d *SANStorageDriver) Destroy(name string) error {
 	return nil
 }
 
+// rollbackCreate undoes the journaled steps of a failed create, so that nothing is left behind if the
+// create is tried in another physical pool, and then forgets them.
+func (d *SANStorageDriver) rollbackCreate(client *api.Client, journal *storage.OperationJournal) error {
+	if err := d.undoJournalSteps(client, journal); err != nil {
+		log.WithField("error", err).Error("Could not roll back volume create.")
+		return err
+	}
+	return journal.Reset()
+}
+
+// RollbackJournal undoes the journaled steps of an interrupted volume create.
+func (d *SANStorageDriver) RollbackJournal(
+	ctx context.Context, _ *storage.VolumeConfig, journal *storage.OperationJournal,
+) error {
+	return d.undoJournalSteps(d.API.WithContext(ctx), journal)
+}
+
+// undoJournalSteps destroys the LUN and FlexVol created by the journaled steps of a create, latest
+// first.  Either may not exist if its step didn't finish.
+func (d *SANStorageDriver) undoJournalSteps(client *api.Client, journal *storage.OperationJournal) error {
+
+	for i := len(journal.Steps) - 1; i >= 0; i-- {
+		step := journal.Steps[i]
+
+		log.WithFields(log.Fields{
+			"step":     step.Name,
+			"resource": step.Resource,
+			"done":     step.Done,
+		}).Debug("Undoing volume create step.")
+
+		switch step.Name {
+		case sanJournalStepCreateLUN:
+			lunDestroyResponse, err := client.LunDestroy(step.Resource)
+			if err != nil {
+				return fmt.Errorf("error destroying LUN %s: %v", step.Resource, err)
+			}
+			if zerr := api.NewZapiError(lunDestroyResponse); !zerr.IsPassed() &&
+				zerr.Code() != azgo.EOBJECTNOTFOUND && zerr.Code() != azgo.EVOLUMEDOESNOTEXIST {
+				return fmt.Errorf("error destroying LUN %s: %v", step.Resource, zerr)
+			}
+
+		case sanJournalStepCreateFlexvol:
+			volExists, err := client.VolumeExists(step.Resource)
+			if err != nil {
+				return fmt.Errorf("error checking for existing volume: %v", err)
+			}
+			if !volExists {
+				continue
+			}
+			volDestroyResponse, err := client.VolumeDestroy(step.Resource, true)
+			if err = api.GetError(volDestroyResponse, err); err != nil {
+				return fmt.Errorf("error destroying volume %s: %v", step.Resource, err)
+			}
+
+		default:
+			return fmt.Errorf("unknown journal step %s", step.Name)
+		}
+	}
+
+	return nil
+}
+
 // Publish the volume to the host specified in publishInfo.  This method may or may not be running on the host
 // where the volume will be mounted, so it should limit itself to updating access rules, initiator groups, etc.
 // that require some host identity (but not locality) as well as storage controller API access.
-func (d *SANStorageDriver) Publish(volConfig *storage.VolumeConfig, publishInfo *utils.VolumePublishInfo) error {
+func (d *SANStorageDriver) Publish(
+	ctx context.Context, volConfig *storage.VolumeConfig, publishInfo *utils.VolumePublishInfo,
+) error {
+
+	client := d.API.WithContext(ctx)
 
 	name := volConfig.InternalName
 
@@ -604,22 +769,98 @@ func (d *SANStorageDriver) Publish(volConfig *storage.VolumeConfig, publishInfo
 	}
 
 	lunPath := lunPath(name)
-	igroupName := d.Config.IgroupName
+	volumeIgroupName := d.getVolumeIgroupName(volConfig)
+	igroupName := d.getPublishIgroupName(volConfig, publishInfo.HostName)
+	shared := igroupName != volumeIgroupName
 
 	// Get target info
-	iSCSINodeName, _, err := GetISCSITargetInfo(d.API, &d.Config)
+	iSCSINodeName, _, err := GetISCSITargetInfo(client, &d.Config)
 	if err != nil {
 		return err
 	}
 
-	err = PublishLUN(d.API, &d.Config, d.ips, publishInfo, lunPath, igroupName, iSCSINodeName)
+	// The backend igroup is created when the driver is initialized, but others are created on demand
+	if igroupName != d.Config.IgroupName {
+		if err = ensureIgroupExists(client, igroupName); err != nil {
+			return fmt.Errorf("error publishing %s driver: %v", d.Name(), err)
+		}
+	}
+
+	err = PublishLUN(client, &d.Config, d.ips, publishInfo, lunPath, igroupName, iSCSINodeName, shared)
 	if err != nil {
 		return fmt.Errorf("error publishing %s driver: %v", d.Name(), err)
 	}
 
+	// A shared LUN must only be reachable through the igroups of the nodes it is published to
+	if shared {
+		if err = unmapLUNFromIgroup(client, lunPath, volumeIgroupName); err != nil {
+			return fmt.Errorf("error publishing %s driver: %v", d.Name(), err)
+		}
+	}
+
+	if volConfig.MountOptions != "" {
+		publishInfo.MountOptions = volConfig.MountOptions
+	}
+
+	// Raw-block volumes are never formatted, even if their LUN, like an imported one, lacks the fstype attribute
+	if volConfig.VolumeMode == tridentconfig.RawBlock {
+		publishInfo.FilesystemType = drivers.FsRaw
+	}
+
 	return nil
 }
 
+// Unpublish removes a node's access to a LUN that is shared by several nodes, by unmapping the LUN from
+// the node's igroup.  Other LUNs are mapped to the backend igroup that all nodes share, so there is
+// nothing to do for them.
+func (d *SANStorageDriver) Unpublish(
+	ctx context.Context, volConfig *storage.VolumeConfig, publishInfo *utils.VolumePublishInfo,
+) error {
+
+	name := volConfig.InternalName
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method": "Unpublish",
+			"Type":   "SANStorageDriver",
+			"name":   name,
+			"node":   publishInfo.HostName,
+		}
+		log.WithFields(fields).Debug(">>>> Unpublish")
+		defer log.WithFields(fields).Debug("<<<< Unpublish")
+	}
+
+	igroupName := d.getPublishIgroupName(volConfig, publishInfo.HostName)
+	if igroupName == d.getVolumeIgroupName(volConfig) {
+		return nil
+	}
+
+	if err := unmapLUNFromIgroup(d.API.WithContext(ctx), lunPath(name), igroupName); err != nil {
+		return fmt.Errorf("error unpublishing %s driver: %v", d.Name(), err)
+	}
+	return nil
+}
+
+// getPublishIgroupName returns the igroup through which a node accesses a volume.  Raw-block volumes that
+// several nodes may use at once are mapped to an igroup of each node, so that one node's access can be
+// revoked without affecting the others.  All other volumes use the volume's igroup.
+func (d *SANStorageDriver) getPublishIgroupName(volConfig *storage.VolumeConfig, nodeName string) string {
+	igroupName := d.getVolumeIgroupName(volConfig)
+	if nodeName == "" || volConfig.ImportNotManaged || !volConfig.AllowsMultiNodePublish() {
+		return igroupName
+	}
+	return getNodeIgroupName(igroupName, nodeName)
+}
+
+// getVolumeIgroupName returns the igroup a volume is mapped to, which is the one its storage class
+// names, if any, or else the backend igroup.
+func (d *SANStorageDriver) getVolumeIgroupName(volConfig *storage.VolumeConfig) string {
+	if volConfig.IgroupName != "" {
+		return volConfig.IgroupName
+	}
+	return d.Config.IgroupName
+}
+
 // GetSnapshot gets a snapshot.  To distinguish between an API error reading the snapshot
 // and a non-existent snapshot, this method may return (nil, nil).
 func (d *SANStorageDriver) GetSnapshot(snapConfig *storage.SnapshotConfig) (*storage.Snapshot, error) {
@@ -655,7 +896,11 @@ func (d *SANStorageDriver) GetSnapshots(volConfig *storage.VolumeConfig) ([]*sto
 }
 
 // CreateSnapshot creates a snapshot for the given volume
-func (d *SANStorageDriver) CreateSnapshot(snapConfig *storage.SnapshotConfig) (*storage.Snapshot, error) {
+func (d *SANStorageDriver) CreateSnapshot(
+	ctx context.Context, snapConfig *storage.SnapshotConfig,
+) (*storage.Snapshot, error) {
+
+	client := d.API.WithContext(ctx)
 
 	internalSnapName := snapConfig.InternalName
 	internalVolName := snapConfig.VolumeInternalName
@@ -671,11 +916,28 @@ func (d *SANStorageDriver) CreateSnapshot(snapConfig *storage.SnapshotConfig) (*
 		defer log.WithFields(fields).Debug("<<<< CreateSnapshot")
 	}
 
-	return CreateSnapshot(snapConfig, &d.Config, d.API, d.API.VolumeSize)
+	return CreateSnapshot(snapConfig, &d.Config, client, client.VolumeSize)
+}
+
+// CreateGroupSnapshot creates snapshots of several volumes at the same point in time
+func (d *SANStorageDriver) CreateGroupSnapshot(snapConfigs []*storage.SnapshotConfig) ([]*storage.Snapshot, error) {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":       "CreateGroupSnapshot",
+			"Type":         "SANStorageDriver",
+			"snapshotName": snapConfigs[0].InternalName,
+			"volumes":      len(snapConfigs),
+		}
+		log.WithFields(fields).Debug(">>>> CreateGroupSnapshot")
+		defer log.WithFields(fields).Debug("<<<< CreateGroupSnapshot")
+	}
+
+	return CreateGroupSnapshot(snapConfigs, &d.Config, d.API, d.API.VolumeSize)
 }
 
 // RestoreSnapshot restores a volume (in place) from a snapshot.
-func (d *SANStorageDriver) RestoreSnapshot(snapConfig *storage.SnapshotConfig) error {
+func (d *SANStorageDriver) RestoreSnapshot(ctx context.Context, snapConfig *storage.SnapshotConfig) error {
 
 	if d.Config.DebugTraceFlags["method"] {
 		fields := log.Fields{
@@ -688,11 +950,13 @@ func (d *SANStorageDriver) RestoreSnapshot(snapConfig *storage.SnapshotConfig) e
 		defer log.WithFields(fields).Debug("<<<< RestoreSnapshot")
 	}
 
-	return RestoreSnapshot(snapConfig, &d.Config, d.API)
+	client := d.API.WithContext(ctx)
+
+	return RestoreSnapshot(snapConfig, &d.Config, client)
 }
 
 // DeleteSnapshot creates a snapshot of a volume.
-func (d *SANStorageDriver) DeleteSnapshot(snapConfig *storage.SnapshotConfig) error {
+func (d *SANStorageDriver) DeleteSnapshot(ctx context.Context, snapConfig *storage.SnapshotConfig) error {
 
 	if d.Config.DebugTraceFlags["method"] {
 		fields := log.Fields{
@@ -705,7 +969,173 @@ func (d *SANStorageDriver) DeleteSnapshot(snapConfig *storage.SnapshotConfig) er
 		defer log.WithFields(fields).Debug("<<<< DeleteSnapshot")
 	}
 
-	return DeleteSnapshot(snapConfig, &d.Config, d.API)
+	client := d.API.WithContext(ctx)
+
+	return DeleteSnapshot(snapConfig, &d.Config, client)
+}
+
+// SupportsBackups returns true if this backend is an FSx for NetApp ONTAP file system.
+func (d *SANStorageDriver) SupportsBackups() bool {
+	return isFSx(&d.Config)
+}
+
+// GetBackup returns an AWS-native backup of the given volume, or nil if it doesn't exist.
+func (d *SANStorageDriver) GetBackup(snapConfig *storage.SnapshotConfig) (*storage.Snapshot, error) {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":       "GetBackup",
+			"Type":         "SANStorageDriver",
+			"snapshotName": snapConfig.Name,
+			"volumeName":   snapConfig.VolumeInternalName,
+		}
+		log.WithFields(fields).Debug(">>>> GetBackup")
+		defer log.WithFields(fields).Debug("<<<< GetBackup")
+	}
+
+	return GetFSxBackup(snapConfig, &d.Config, d.API.VolumeSize)
+}
+
+// CreateBackup starts an AWS-native backup of the given volume.
+func (d *SANStorageDriver) CreateBackup(snapConfig *storage.SnapshotConfig) (*storage.Snapshot, error) {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":       "CreateBackup",
+			"Type":         "SANStorageDriver",
+			"snapshotName": snapConfig.Name,
+			"sourceVolume": snapConfig.VolumeInternalName,
+		}
+		log.WithFields(fields).Debug(">>>> CreateBackup")
+		defer log.WithFields(fields).Debug("<<<< CreateBackup")
+	}
+
+	return CreateFSxBackup(snapConfig, &d.Config, d.API.VolumeSize)
+}
+
+// DeleteBackup deletes an AWS-native backup.
+func (d *SANStorageDriver) DeleteBackup(snapConfig *storage.SnapshotConfig) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":       "DeleteBackup",
+			"Type":         "SANStorageDriver",
+			"snapshotName": snapConfig.Name,
+			"volumeName":   snapConfig.VolumeInternalName,
+		}
+		log.WithFields(fields).Debug(">>>> DeleteBackup")
+		defer log.WithFields(fields).Debug("<<<< DeleteBackup")
+	}
+
+	return DeleteFSxBackup(snapConfig, &d.Config)
+}
+
+// GetVolumeUsage returns the space consumed by a volume, as reported by the Flexvol.
+func (d *SANStorageDriver) GetVolumeUsage(volConfig *storage.VolumeConfig) (*storage.VolumeUsage, error) {
+	return getOntapVolumeUsage(volConfig.InternalName, d.API)
+}
+
+// GetVolumeCondition reports a volume whose Flexvol or LUN isn't online, or whose LUN isn't mapped,
+// as abnormal.
+func (d *SANStorageDriver) GetVolumeCondition(volConfig *storage.VolumeConfig) (*storage.VolumeCondition, error) {
+	lunPath := fmt.Sprintf("/vol/%v/lun0", volConfig.InternalName)
+	return getOntapLUNCondition(volConfig, volConfig.InternalName, lunPath, d.API)
+}
+
+// CanReplicateFrom returns true if volumes on the source backend can be SnapMirrored to this backend.
+func (d *SANStorageDriver) CanReplicateFrom(source storage.Driver) bool {
+	return canReplicateOntapVolume(d, source)
+}
+
+// CreateReplica creates a data protection volume and starts replicating the source volume to it.
+func (d *SANStorageDriver) CreateReplica(
+	volConfig, sourceVolConfig *storage.VolumeConfig, storagePool *storage.Pool, source storage.Driver,
+) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":       "CreateReplica",
+			"Type":         "SANStorageDriver",
+			"name":         volConfig.InternalName,
+			"sourceVolume": sourceVolConfig.InternalName,
+		}
+		log.WithFields(fields).Debug(">>>> CreateReplica")
+		defer log.WithFields(fields).Debug("<<<< CreateReplica")
+	}
+
+	return createOntapReplica(volConfig, sourceVolConfig, storagePool, d.physicalPools, d.virtualPools,
+		d, source.(StorageDriver))
+}
+
+// GetReplicaProgress returns the number of bytes replicated and whether the baseline transfer is complete.
+func (d *SANStorageDriver) GetReplicaProgress(volConfig *storage.VolumeConfig) (uint64, bool, error) {
+	return getOntapReplicaProgress(volConfig, d.API)
+}
+
+// PromoteReplica breaks the SnapMirror relationship to a replica, making it writable.
+func (d *SANStorageDriver) PromoteReplica(
+	volConfig, sourceVolConfig *storage.VolumeConfig, source storage.Driver,
+) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":       "PromoteReplica",
+			"Type":         "SANStorageDriver",
+			"name":         volConfig.InternalName,
+			"sourceVolume": sourceVolConfig.InternalName,
+		}
+		log.WithFields(fields).Debug(">>>> PromoteReplica")
+		defer log.WithFields(fields).Debug("<<<< PromoteReplica")
+	}
+
+	sourceDriver := source.(StorageDriver)
+	return promoteOntapReplica(volConfig, d, sourceDriver,
+		func() error { return suspendOntapSANReplicaSource(sourceVolConfig, sourceDriver) },
+		func() error { return resumeOntapSANReplicaSource(sourceVolConfig, sourceDriver) })
+}
+
+// DeleteReplica stops replicating to a replica and destroys it.
+func (d *SANStorageDriver) DeleteReplica(
+	volConfig, sourceVolConfig *storage.VolumeConfig, source storage.Driver,
+) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":       "DeleteReplica",
+			"Type":         "SANStorageDriver",
+			"name":         volConfig.InternalName,
+			"sourceVolume": sourceVolConfig.InternalName,
+		}
+		log.WithFields(fields).Debug(">>>> DeleteReplica")
+		defer log.WithFields(fields).Debug("<<<< DeleteReplica")
+	}
+
+	sourceDriver := source.(StorageDriver)
+	return deleteOntapReplica(volConfig, d, sourceDriver, func() error {
+		return resumeOntapSANReplicaSource(sourceVolConfig, sourceDriver)
+	})
+}
+
+// MoveVolume starts moving a volume's Flexvol to an aggregate of another storage pool.
+func (d *SANStorageDriver) MoveVolume(volConfig *storage.VolumeConfig, storagePool *storage.Pool) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":      "MoveVolume",
+			"Type":        "SANStorageDriver",
+			"name":        volConfig.InternalName,
+			"storagePool": storagePool.Name,
+		}
+		log.WithFields(fields).Debug(">>>> MoveVolume")
+		defer log.WithFields(fields).Debug("<<<< MoveVolume")
+	}
+
+	return moveOntapVolume(volConfig, storagePool, d.physicalPools, d.virtualPools, d.API)
+}
+
+// GetVolumeMoveProgress returns the number of bytes moved and to move, and whether the move is complete.
+func (d *SANStorageDriver) GetVolumeMoveProgress(volConfig *storage.VolumeConfig) (uint64, uint64, bool, error) {
+	return getOntapVolumeMoveProgress(volConfig, d.API)
 }
 
 // Test for the existence of a volume
@@ -725,6 +1155,11 @@ func (d *SANStorageDriver) GetStorageBackendSpecs(backend *storage.Backend) erro
 	return getStorageBackendSpecsCommon(backend, d.physicalPools, d.virtualPools, d.backendName())
 }
 
+// GetStorageCapacity reports the free space in the aggregates behind the backend's pools
+func (d *SANStorageDriver) GetStorageCapacity() (*storage.StorageCapacity, error) {
+	return getStorageCapacityCommon(d, d.physicalPools, d.virtualPools)
+}
+
 // Retrieve storage backend physical pools
 func (d *SANStorageDriver) GetStorageBackendPhysicalPoolNames() []string {
 	return getStorageBackendPhysicalPoolNamesCommon(d.physicalPools)
@@ -756,6 +1191,12 @@ func (d *SANStorageDriver) CreatePrepare(volConfig *storage.VolumeConfig) {
 	createPrepareCommon(d, volConfig)
 }
 
+// CreatePrepareInPool sets the internal name of a volume created in a pool, using the pool's storage
+// prefix if it has one.
+func (d *SANStorageDriver) CreatePrepareInPool(volConfig *storage.VolumeConfig, pool *storage.Pool) {
+	volConfig.InternalName = getPoolInternalVolumeName(d.Config.CommonStorageDriverConfig, pool, volConfig.Name)
+}
+
 func (d *SANStorageDriver) CreateFollowup(volConfig *storage.VolumeConfig) error {
 
 	if d.Config.DebugTraceFlags["method"] {
@@ -781,12 +1222,18 @@ func (d *SANStorageDriver) mapOntapSANLun(volConfig *storage.VolumeConfig) error
 
 	// get the lunPath and lunID
 	lunPath := fmt.Sprintf("/vol/%v/lun0", volConfig.InternalName)
-	lunID, err := d.API.LunMapIfNotMapped(d.Config.IgroupName, lunPath, volConfig.ImportNotManaged)
+	igroupName := d.getVolumeIgroupName(volConfig)
+	if igroupName != d.Config.IgroupName {
+		if err := ensureIgroupExists(d.API, igroupName); err != nil {
+			return err
+		}
+	}
+	lunID, err := d.API.LunMapIfNotMapped(igroupName, lunPath, volConfig.ImportNotManaged)
 	if err != nil {
 		return err
 	}
 
-	err = PopulateOntapLunMapping(d.API, &d.Config, d.ips, volConfig, lunID, lunPath, d.Config.IgroupName)
+	err = PopulateOntapLunMapping(d.API, &d.Config, d.ips, volConfig, lunID, lunPath, igroupName)
 	if err != nil {
 		return fmt.Errorf("error mapping LUN for %s driver: %v", d.Name(), err)
 	}
@@ -838,19 +1285,33 @@ func (d *SANStorageDriver) GetVolumeExternalWrappers(
 	// Let the caller know we're done by closing the channel
 	defer close(channel)
 
+	// Get all volumes matching the storage prefixes of the backend and its virtual pools
+	prefixes := getStoragePrefixes(d.Config.CommonStorageDriverConfig, d.virtualPools)
+	for _, prefix := range getStoragePrefixesToList(prefixes) {
+		if err := d.getVolumeExternalWrappersForPrefix(prefix, channel); err != nil {
+			channel <- &storage.VolumeExternalWrapper{Volume: nil, Error: err}
+			return
+		}
+	}
+}
+
+// getVolumeExternalWrappersForPrefix writes a VolumeExternal representation of each volume whose
+// name starts with a storage prefix to the supplied channel.
+func (d *SANStorageDriver) getVolumeExternalWrappersForPrefix(
+	prefix string, channel chan *storage.VolumeExternalWrapper,
+) error {
+
 	// Get all volumes matching the storage prefix
-	volumesResponse, err := d.API.VolumeGetAll(*d.Config.StoragePrefix)
+	volumesResponse, err := d.API.VolumeGetAll(prefix)
 	if err = api.GetError(volumesResponse, err); err != nil {
-		channel <- &storage.VolumeExternalWrapper{Volume: nil, Error: err}
-		return
+		return err
 	}
 
 	// Get all LUNs named 'lun0' in volumes matching the storage prefix
-	lunPathPattern := fmt.Sprintf("/vol/%v/lun0", *d.Config.StoragePrefix+"*")
+	lunPathPattern := fmt.Sprintf("/vol/%v/lun0", prefix+"*")
 	lunsResponse, err := d.API.LunGetAll(lunPathPattern)
 	if err = api.GetError(lunsResponse, err); err != nil {
-		channel <- &storage.VolumeExternalWrapper{Volume: nil, Error: err}
-		return
+		return err
 	}
 
 	// Make a map of volumes for faster correlation with LUNs
@@ -875,6 +1336,8 @@ func (d *SANStorageDriver) GetVolumeExternalWrappers(
 			channel <- &storage.VolumeExternalWrapper{Volume: d.getVolumeExternal(&lun, &volume), Error: nil}
 		}
 	}
+
+	return nil
 }
 
 // getVolumeExternal is a private method that accepts info about a volume
@@ -888,10 +1351,7 @@ func (d *SANStorageDriver) getVolumeExternal(
 	volumeSnapshotAttrs := volumeAttrs.VolumeSnapshotAttributesPtr
 
 	internalName := volumeIDAttrs.Name()
-	name := internalName
-	if strings.HasPrefix(internalName, *d.Config.StoragePrefix) {
-		name = internalName[len(*d.Config.StoragePrefix):]
-	}
+	name := trimStoragePrefix(internalName, getStoragePrefixes(d.Config.CommonStorageDriverConfig, d.virtualPools))
 
 	volumeConfig := &storage.VolumeConfig{
 		Version:         tridentconfig.OrchestratorAPIVersion,
@@ -937,11 +1397,18 @@ func (d *SANStorageDriver) GetUpdateType(driverOrig storage.Driver) *roaring.Bit
 		bitmap.Add(storage.UsernameChange)
 	}
 
+	if drivers.StoragePrefixChanged(d.Config.CommonStorageDriverConfig, dOrig.Config.CommonStorageDriverConfig) ||
+		storagePrefixesRemoved(getStoragePrefixes(d.Config.CommonStorageDriverConfig, d.virtualPools),
+			getStoragePrefixes(dOrig.Config.CommonStorageDriverConfig, dOrig.virtualPools)) {
+		bitmap.Add(storage.PrefixChange)
+	}
 	return bitmap
 }
 
 // Resize expands the volume size.
-func (d *SANStorageDriver) Resize(volConfig *storage.VolumeConfig, sizeBytes uint64) error {
+func (d *SANStorageDriver) Resize(ctx context.Context, volConfig *storage.VolumeConfig, sizeBytes uint64) error {
+
+	client := d.API.WithContext(ctx)
 
 	name := volConfig.InternalName
 	if d.Config.DebugTraceFlags["method"] {
@@ -956,7 +1423,7 @@ func (d *SANStorageDriver) Resize(volConfig *storage.VolumeConfig, sizeBytes uin
 	}
 
 	// Validation checks
-	volExists, err := d.API.VolumeExists(name)
+	volExists, err := client.VolumeExists(name)
 	if err != nil {
 		log.WithFields(log.Fields{
 			"error": err,
@@ -968,7 +1435,7 @@ func (d *SANStorageDriver) Resize(volConfig *storage.VolumeConfig, sizeBytes uin
 		return fmt.Errorf("volume %s does not exist", name)
 	}
 
-	volSize, err := d.API.VolumeSize(name)
+	volSize, err := client.VolumeSize(name)
 	if err != nil {
 		log.WithFields(log.Fields{
 			"error": err,
@@ -1008,9 +1475,9 @@ func (d *SANStorageDriver) Resize(volConfig *storage.VolumeConfig, sizeBytes uin
 
 	// Resize operations
 	lunPath := fmt.Sprintf("/vol/%v/lun0", name)
-	if !d.API.SupportsFeature(api.LunGeometrySkip) {
+	if !client.SupportsFeature(api.LunGeometrySkip) {
 		// Check LUN geometry and verify LUN max size.
-		lunGeometry, err := d.API.LunGetGeometry(lunPath)
+		lunGeometry, err := client.LunGetGeometry(lunPath)
 		if err != nil {
 			log.WithField("error", err).Error("LUN resize failed.")
 			return fmt.Errorf("volume resize failed")
@@ -1029,14 +1496,14 @@ func (d *SANStorageDriver) Resize(volConfig *storage.VolumeConfig, sizeBytes uin
 	}
 
 	// Resize FlexVol
-	response, err := d.API.VolumeSetSize(name, strconv.FormatUint(sizeBytes, 10))
+	response, err := client.VolumeSetSize(name, strconv.FormatUint(sizeBytes, 10))
 	if err = api.GetError(response.Result, err); err != nil {
 		log.WithField("error", err).Error("Volume resize failed.")
 		return fmt.Errorf("volume resize failed")
 	}
 
 	// Resize LUN0
-	returnSize, err := d.API.LunResize(lunPath, int(sizeBytes))
+	returnSize, err := client.LunResize(lunPath, int(sizeBytes))
 	if err != nil {
 		log.WithField("error", err).Error("LUN resize failed.")
 		return fmt.Errorf("volume resize failed")
@@ -1044,10 +1511,10 @@ func (d *SANStorageDriver) Resize(volConfig *storage.VolumeConfig, sizeBytes uin
 
 	// Resize FlexVol to be the same size or bigger than LUN because ONTAP creates
 	// larger LUNs sometimes based on internal geometry
-	if initialVolumeSize, err := d.API.VolumeSize(name); err != nil {
+	if initialVolumeSize, err := client.VolumeSize(name); err != nil {
 		log.WithField("name", name).Warning("Failed to get volume size.")
 	} else if returnSize != uint64(initialVolumeSize) {
-		volumeSizeResponse, err := d.API.VolumeSetSize(name, strconv.FormatUint(returnSize, 10))
+		volumeSizeResponse, err := client.VolumeSetSize(name, strconv.FormatUint(returnSize, 10))
 		if err = api.GetError(volumeSizeResponse, err); err != nil {
 			volConfig.Size = strconv.FormatUint(uint64(initialVolumeSize), 10)
 			log.WithFields(log.Fields{
@@ -1055,7 +1522,7 @@ func (d *SANStorageDriver) Resize(volConfig *storage.VolumeConfig, sizeBytes uin
 				"initialVolumeSize":  initialVolumeSize,
 				"adjustedVolumeSize": returnSize}).Warning("Failed to resize volume to match LUN size.")
 		} else {
-			if adjustedVolumeSize, err := d.API.VolumeSize(name); err != nil {
+			if adjustedVolumeSize, err := client.VolumeSize(name); err != nil {
 				log.WithField("name", name).
 					Warning("Failed to get volume size after the second resize operation.")
 			} else {
@@ -1071,6 +1538,26 @@ func (d *SANStorageDriver) Resize(volConfig *storage.VolumeConfig, sizeBytes uin
 	return nil
 }
 
+// RevokeNodeAccess removes a node's initiator from the backend igroup, so that a node that was shut
+// down without unpublishing its volumes can no longer access their LUNs.
+func (d *SANStorageDriver) RevokeNodeAccess(node *utils.Node, backendUUID string) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method": "RevokeNodeAccess",
+			"Type":   "SANStorageDriver",
+			"Node":   node.Name,
+		}
+		log.WithFields(fields).Debug(">>>> RevokeNodeAccess")
+		defer log.WithFields(fields).Debug("<<<< RevokeNodeAccess")
+	}
+
+	if err := removeNodeFromIgroup(node, d.Config.IgroupName, d.API); err != nil {
+		return err
+	}
+	return removeNodeFromIgroup(node, getNodeIgroupName(d.Config.IgroupName, node.Name), d.API)
+}
+
 func (d *SANStorageDriver) ReconcileNodeAccess(nodes []*utils.Node, backendUUID string) error {
 
 	nodeNames := make([]string, 0)
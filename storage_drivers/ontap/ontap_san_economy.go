@@ -3,6 +3,7 @@
 package ontap
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"math/rand"
@@ -362,9 +363,11 @@ func (d *SANEconomyStorageDriver) validate() error {
 
 // Create a volume+LUN with the specified options
 func (d *SANEconomyStorageDriver) Create(
-	volConfig *storage.VolumeConfig, storagePool *storage.Pool, volAttributes map[string]sa.Request,
+	ctx context.Context, volConfig *storage.VolumeConfig, storagePool *storage.Pool, volAttributes map[string]sa.Request,
 ) error {
 
+	client := d.API.WithContext(ctx)
+
 	name := volConfig.InternalName
 	if d.Config.DebugTraceFlags["method"] {
 		fields := log.Fields{
@@ -397,6 +400,9 @@ func (d *SANEconomyStorageDriver) Create(
 		return err
 	}
 
+	// Use the pool's mount options unless the volume or its storage class specified some
+	applyPoolMountOptions(volConfig, storagePool)
+
 	// Determine volume size in bytes
 	requestedSize, err := utils.ConvertSizeToBytes(volConfig.Size)
 	if err != nil {
@@ -443,7 +449,7 @@ func (d *SANEconomyStorageDriver) Create(
 	}
 
 	if tieringPolicy == "" {
-		tieringPolicy = d.API.TieringPolicyValue()
+		tieringPolicy = client.TieringPolicyValue()
 	}
 
 	createErrors := make([]error, 0)
@@ -487,7 +493,7 @@ func (d *SANEconomyStorageDriver) Create(
 		osType := "linux"
 
 		// Create the LUN
-		lunCreateResponse, err := d.API.LunCreate(lunPath, int(sizeBytes), osType, false, spaceAllocation)
+		lunCreateResponse, err := client.LunCreate(lunPath, int(sizeBytes), osType, false, spaceAllocation)
 		if err = api.GetError(lunCreateResponse, err); err != nil {
 			errMessage := fmt.Sprintf("ONTAP-SAN-ECONOMY pool %s/%s; error creating LUN %s/%s: %v", storagePool.Name,
 				aggregate, bucketVol, name, err)
@@ -497,14 +503,14 @@ func (d *SANEconomyStorageDriver) Create(
 		}
 
 		// Save the fstype in a LUN attribute so we know what to do in Attach
-		attrResponse, err := d.API.LunSetAttribute(lunPath, LUNAttributeFSType, fstype)
+		attrResponse, err := client.LunSetAttribute(lunPath, LUNAttributeFSType, fstype)
 		if err = api.GetError(attrResponse, err); err != nil {
-			d.API.LunDestroy(lunPath)
+			client.LunDestroy(lunPath)
 			return fmt.Errorf("ONTAP-SAN-ECONOMY pool %s/%s; error saving file system type for LUN %s/%s: %v",
 				storagePool.Name, aggregate, bucketVol, name, err)
 		}
 		// Save the context
-		attrResponse, err = d.API.LunSetAttribute(lunPath, "context", string(d.Config.DriverContext))
+		attrResponse, err = client.LunSetAttribute(lunPath, "context", string(d.Config.DriverContext))
 		if err = api.GetError(attrResponse, err); err != nil {
 			log.WithField("name", name).Warning("Failed to save the driver context attribute for new volume.")
 		}
@@ -513,7 +519,7 @@ func (d *SANEconomyStorageDriver) Create(
 		// larger LUNs sometimes based on internal geometry
 		lunSize := uint64(lunCreateResponse.Result.ActualSize())
 		if lunSize > sizeBytes {
-			if initialVolumeSize, err := d.API.VolumeSize(bucketVol); err != nil {
+			if initialVolumeSize, err := client.VolumeSize(bucketVol); err != nil {
 				log.WithField("name", bucketVol).Warning("Failed to get volume size.")
 			} else {
 				err = d.resizeFlexvol(bucketVol, 0)
@@ -525,7 +531,7 @@ func (d *SANEconomyStorageDriver) Create(
 						"adjustedVolumeSize": uint64(initialVolumeSize) + lunSize - sizeBytes,
 					}).Warning("Failed to resize new volume to exact sum of LUNs' size.")
 				} else {
-					if adjustedVolumeSize, err := d.API.VolumeSize(bucketVol); err != nil {
+					if adjustedVolumeSize, err := client.VolumeSize(bucketVol); err != nil {
 						log.WithField("name", bucketVol).
 							Warning("Failed to get volume size after the second resize operation.")
 					} else {
@@ -546,7 +552,11 @@ func (d *SANEconomyStorageDriver) Create(
 }
 
 // Create a volume clone
-func (d *SANEconomyStorageDriver) CreateClone(volConfig *storage.VolumeConfig, _ *storage.Pool) error {
+func (d *SANEconomyStorageDriver) CreateClone(
+	ctx context.Context, volConfig *storage.VolumeConfig, _ *storage.Pool,
+) error {
+
+	client := d.API.WithContext(ctx)
 
 	source := volConfig.CloneSourceVolumeInternal
 	name := volConfig.InternalName
@@ -565,7 +575,7 @@ func (d *SANEconomyStorageDriver) CreateClone(volConfig *storage.VolumeConfig, _
 		defer log.WithFields(fields).Debug("<<<< CreateClone")
 	}
 
-	return d.createLUNClone(name, source, snapshot, &d.Config, d.API, d.FlexvolNamePrefix(), isFromSnapshot)
+	return d.createLUNClone(name, source, snapshot, &d.Config, client, d.FlexvolNamePrefix(), isFromSnapshot)
 }
 
 // Create a volume clone
@@ -635,7 +645,9 @@ func (d *SANEconomyStorageDriver) createLUNClone(
 	return d.resizeFlexvol(flexvol, 0)
 }
 
-func (d *SANEconomyStorageDriver) Import(volConfig *storage.VolumeConfig, originalName string) error {
+func (d *SANEconomyStorageDriver) Import(
+	ctx context.Context, volConfig *storage.VolumeConfig, originalName string,
+) error {
 
 	if d.Config.DebugTraceFlags["method"] {
 		fields := log.Fields{
@@ -669,7 +681,7 @@ func (d *SANEconomyStorageDriver) Rename(name string, newName string) error {
 }
 
 // Destroy the LUN
-func (d *SANEconomyStorageDriver) Destroy(name string) error {
+func (d *SANEconomyStorageDriver) Destroy(ctx context.Context, name string) error {
 
 	if d.Config.DebugTraceFlags["method"] {
 		fields := log.Fields{
@@ -681,6 +693,8 @@ func (d *SANEconomyStorageDriver) Destroy(name string) error {
 		defer log.WithFields(fields).Debug("<<<< Destroy")
 	}
 
+	client := d.API.WithContext(ctx)
+
 	var (
 		err           error
 		iSCSINodeName string
@@ -704,14 +718,14 @@ func (d *SANEconomyStorageDriver) Destroy(name string) error {
 	if d.Config.DriverContext == tridentconfig.ContextDocker {
 
 		// Get target info
-		iSCSINodeName, _, err = GetISCSITargetInfo(d.API, &d.Config)
+		iSCSINodeName, _, err = GetISCSITargetInfo(client, &d.Config)
 		if err != nil {
 			log.WithField("error", err).Error("Could not get target info")
 			return err
 		}
 
 		// Get the LUN ID
-		lunMapResponse, err := d.API.LunMapListInfo(lunPath)
+		lunMapResponse, err := client.LunMapListInfo(lunPath)
 		if err != nil {
 			return fmt.Errorf("error reading LUN maps for volume %s, path %s: %v", name, lunPath, err)
 		}
@@ -737,14 +751,14 @@ func (d *SANEconomyStorageDriver) Destroy(name string) error {
 		return deleteError
 	}
 	for _, snap := range snapList {
-		err = d.DeleteSnapshot(snap.Config)
+		err = d.DeleteSnapshot(ctx, snap.Config)
 		if err != nil {
 			log.Errorf("Error snap-LUN delete failed: %v", err)
 			return err
 		}
 	}
 
-	offlineResponse, err := d.API.LunOffline(lunPath)
+	offlineResponse, err := client.LunOffline(lunPath)
 	if err != nil {
 		fields := log.Fields{
 			"Method":   "Destroy",
@@ -756,7 +770,7 @@ func (d *SANEconomyStorageDriver) Destroy(name string) error {
 		log.WithFields(fields)
 	}
 
-	destroyResponse, err := d.API.LunDestroy(lunPath)
+	destroyResponse, err := client.LunDestroy(lunPath)
 	if err = api.GetError(destroyResponse, err); err != nil {
 		log.Errorf("Error LUN delete failed: %v", err)
 		return deleteError
@@ -809,7 +823,11 @@ func (d *SANEconomyStorageDriver) DeleteBucketIfEmpty(bucketVol string) error {
 // Publish the volume to the host specified in publishInfo.  This method may or may not be running on the host
 // where the volume will be mounted, so it should limit itself to updating access rules, initiator groups, etc.
 // that require some host identity (but not locality) as well as storage controller API access.
-func (d *SANEconomyStorageDriver) Publish(volConfig *storage.VolumeConfig, publishInfo *utils.VolumePublishInfo) error {
+func (d *SANEconomyStorageDriver) Publish(
+	ctx context.Context, volConfig *storage.VolumeConfig, publishInfo *utils.VolumePublishInfo,
+) error {
+
+	client := d.API.WithContext(ctx)
 
 	name := volConfig.InternalName
 
@@ -837,16 +855,20 @@ func (d *SANEconomyStorageDriver) Publish(volConfig *storage.VolumeConfig, publi
 	igroupName := d.Config.IgroupName
 
 	// Get target info
-	iSCSINodeName, _, err := GetISCSITargetInfo(d.API, &d.Config)
+	iSCSINodeName, _, err := GetISCSITargetInfo(client, &d.Config)
 	if err != nil {
 		return err
 	}
 
-	err = PublishLUN(d.API, &d.Config, d.ips, publishInfo, lunPath, igroupName, iSCSINodeName)
+	err = PublishLUN(client, &d.Config, d.ips, publishInfo, lunPath, igroupName, iSCSINodeName, false)
 	if err != nil {
 		return fmt.Errorf("error publishing %s driver: %v", d.Name(), err)
 	}
 
+	if volConfig.MountOptions != "" {
+		publishInfo.MountOptions = volConfig.MountOptions
+	}
+
 	return nil
 }
 
@@ -988,7 +1010,9 @@ func (d *SANEconomyStorageDriver) getSnapshotsEconomy(
 }
 
 // CreateSnapshot creates a snapshot for the given volume.
-func (d *SANEconomyStorageDriver) CreateSnapshot(snapConfig *storage.SnapshotConfig) (*storage.Snapshot, error) {
+func (d *SANEconomyStorageDriver) CreateSnapshot(
+	ctx context.Context, snapConfig *storage.SnapshotConfig,
+) (*storage.Snapshot, error) {
 
 	if d.Config.DebugTraceFlags["method"] {
 		fields := log.Fields{
@@ -1002,6 +1026,8 @@ func (d *SANEconomyStorageDriver) CreateSnapshot(snapConfig *storage.SnapshotCon
 		defer log.WithFields(fields).Info("<<<< CreateSnapshot")
 	}
 
+	client := d.API.WithContext(ctx)
+
 	internalSnapName := snapConfig.InternalName
 	internalVolumeName := snapConfig.VolumeInternalName
 
@@ -1018,7 +1044,7 @@ func (d *SANEconomyStorageDriver) CreateSnapshot(snapConfig *storage.SnapshotCon
 	lunPath := GetLUNPathEconomy(bucketVol, internalVolumeName)
 
 	// If the specified volume doesn't exist, return error
-	lunInfo, err := d.API.LunGet(lunPath)
+	lunInfo, err := client.LunGet(lunPath)
 	if err != nil {
 		return nil, fmt.Errorf("error checking for existing volume: %v", err)
 	}
@@ -1033,7 +1059,7 @@ func (d *SANEconomyStorageDriver) CreateSnapshot(snapConfig *storage.SnapshotCon
 
 	// Create the "snap-LUN" where the snapshot is a LUN clone of the source LUN
 	err = d.createLUNClone(lunName, snapConfig.VolumeInternalName, snapConfig.Name,
-		&d.Config, d.API, d.FlexvolNamePrefix(), false)
+		&d.Config, client, d.FlexvolNamePrefix(), false)
 	if err != nil {
 		return nil, fmt.Errorf("could not create snapshot: %v", err)
 	}
@@ -1057,7 +1083,7 @@ func (d *SANEconomyStorageDriver) CreateSnapshot(snapConfig *storage.SnapshotCon
 }
 
 // RestoreSnapshot restores a volume (in place) from a snapshot.
-func (d *SANEconomyStorageDriver) RestoreSnapshot(snapConfig *storage.SnapshotConfig) error {
+func (d *SANEconomyStorageDriver) RestoreSnapshot(ctx context.Context, snapConfig *storage.SnapshotConfig) error {
 
 	if d.Config.DebugTraceFlags["method"] {
 		fields := log.Fields{
@@ -1074,7 +1100,7 @@ func (d *SANEconomyStorageDriver) RestoreSnapshot(snapConfig *storage.SnapshotCo
 }
 
 // DeleteSnapshot deletes a LUN snapshot.
-func (d *SANEconomyStorageDriver) DeleteSnapshot(snapConfig *storage.SnapshotConfig) error {
+func (d *SANEconomyStorageDriver) DeleteSnapshot(ctx context.Context, snapConfig *storage.SnapshotConfig) error {
 
 	if d.Config.DebugTraceFlags["method"] {
 		fields := log.Fields{
@@ -1088,6 +1114,8 @@ func (d *SANEconomyStorageDriver) DeleteSnapshot(snapConfig *storage.SnapshotCon
 		defer log.WithFields(fields).Debug("<<<< DeleteSnapshot")
 	}
 
+	client := d.API.WithContext(ctx)
+
 	internalSnapName := snapConfig.InternalName
 	// Creating the path string pattern
 	snapLunName := d.helper.GetSnapshotName(snapConfig.VolumeName, internalSnapName)
@@ -1104,7 +1132,7 @@ func (d *SANEconomyStorageDriver) DeleteSnapshot(snapConfig *storage.SnapshotCon
 
 	snapPath := GetLUNPathEconomy(bucketVol, snapLunName)
 
-	offlineResponse, err := d.API.LunOffline(snapPath)
+	offlineResponse, err := client.LunOffline(snapPath)
 	if err != nil {
 		log.WithFields(log.Fields{
 			"Method":   "DeleteSnapshot",
@@ -1115,7 +1143,7 @@ func (d *SANEconomyStorageDriver) DeleteSnapshot(snapConfig *storage.SnapshotCon
 		}).Warn("Error attempting to offline snap-LUN.")
 	}
 
-	destroyResponse, err := d.API.LunDestroy(snapPath)
+	destroyResponse, err := client.LunDestroy(snapPath)
 	if err = api.GetError(destroyResponse, err); err != nil {
 		log.Errorf("Snap-LUN delete failed: %v", err)
 		return fmt.Errorf("error deleting snapshot: %v", err)
@@ -1144,7 +1172,7 @@ func (d *SANEconomyStorageDriver) Get(name string) error {
 	}
 	if !exists {
 		log.WithField("LUN", name).Debug("LUN not found.")
-		return getError
+		return utils.NotFoundError(getError.Error())
 	}
 
 	log.WithFields(log.Fields{"LUN": name, "bucketVol": bucketVol}).Debug("Volume found.")
@@ -1387,6 +1415,11 @@ func (d *SANEconomyStorageDriver) GetStorageBackendSpecs(backend *storage.Backen
 	return getStorageBackendSpecsCommon(backend, d.physicalPools, d.virtualPools, d.backendName())
 }
 
+// GetStorageCapacity reports the free space in the aggregates behind the backend's pools
+func (d *SANEconomyStorageDriver) GetStorageCapacity() (*storage.StorageCapacity, error) {
+	return getStorageCapacityCommon(d, d.physicalPools, d.virtualPools)
+}
+
 // Retrieve storage backend physical pools
 func (d *SANEconomyStorageDriver) GetStorageBackendPhysicalPoolNames() []string {
 	return getStorageBackendPhysicalPoolNamesCommon(d.physicalPools)
@@ -1438,6 +1471,26 @@ func (d *SANEconomyStorageDriver) CreateFollowup(volConfig *storage.VolumeConfig
 	return d.mapOntapSANLUN(volConfig)
 }
 
+// GetVolumeCondition reports a volume whose Flexvol or LUN isn't online, or whose LUN isn't mapped,
+// as abnormal.
+func (d *SANEconomyStorageDriver) GetVolumeCondition(
+	volConfig *storage.VolumeConfig,
+) (*storage.VolumeCondition, error) {
+
+	exists, flexvol, err := d.LUNExists(volConfig.InternalName, d.FlexvolNamePrefix())
+	if err != nil {
+		return nil, fmt.Errorf("could not determine if LUN %s exists: %v", volConfig.InternalName, err)
+	}
+	if !exists {
+		return &storage.VolumeCondition{
+			Abnormal: true,
+			Message:  fmt.Sprintf("LUN %s not found", volConfig.InternalName),
+		}, nil
+	}
+	lunPath := GetLUNPathEconomy(flexvol, volConfig.InternalName)
+	return getOntapLUNCondition(volConfig, flexvol, lunPath, d.API)
+}
+
 func (d *SANEconomyStorageDriver) mapOntapSANLUN(volConfig *storage.VolumeConfig) error {
 
 	// Determine which flexvol contains the LUN
@@ -1618,6 +1671,9 @@ func (d *SANEconomyStorageDriver) GetUpdateType(driverOrig storage.Driver) *roar
 		bitmap.Add(storage.UsernameChange)
 	}
 
+	if drivers.StoragePrefixChanged(d.Config.CommonStorageDriverConfig, dOrig.Config.CommonStorageDriverConfig) {
+		bitmap.Add(storage.PrefixChange)
+	}
 	return bitmap
 }
 
@@ -1651,7 +1707,9 @@ func (d *SANEconomyStorageDriver) LUNExists(name, bucketPrefix string) (bool, st
 }
 
 // Create a LUN with the specified options and find (or create) a bucket volume for the LUN
-func (d *SANEconomyStorageDriver) Resize(volConfig *storage.VolumeConfig, sizeBytes uint64) error {
+func (d *SANEconomyStorageDriver) Resize(ctx context.Context, volConfig *storage.VolumeConfig, sizeBytes uint64) error {
+
+	client := d.API.WithContext(ctx)
 
 	name := volConfig.InternalName
 	if d.Config.DebugTraceFlags["method"] {
@@ -1731,9 +1789,9 @@ func (d *SANEconomyStorageDriver) Resize(volConfig *storage.VolumeConfig, sizeBy
 
 	// Resize operations
 	lunPath := d.helper.GetLUNPath(bucketVol, name)
-	if !d.API.SupportsFeature(api.LunGeometrySkip) {
+	if !client.SupportsFeature(api.LunGeometrySkip) {
 		// Check LUN geometry and verify LUN max size.
-		lunGeometry, err := d.API.LunGetGeometry(lunPath)
+		lunGeometry, err := client.LunGetGeometry(lunPath)
 		if err != nil {
 			log.WithField("error", err).Error("LUN resize failed.")
 			return fmt.Errorf("volume resize failed")
@@ -1752,14 +1810,14 @@ func (d *SANEconomyStorageDriver) Resize(volConfig *storage.VolumeConfig, sizeBy
 	}
 
 	// Resize FlexVol
-	response, err := d.API.VolumeSetSize(bucketVol, strconv.FormatUint(flexvolSize, 10))
+	response, err := client.VolumeSetSize(bucketVol, strconv.FormatUint(flexvolSize, 10))
 	if err = api.GetError(response, err); err != nil {
 		log.WithField("error", err).Error("Volume resize failed.")
 		return fmt.Errorf("volume resize failed")
 	}
 
 	// Resize LUN
-	returnSize, err := d.API.LunResize(lunPath, int(sizeBytes))
+	returnSize, err := client.LunResize(lunPath, int(sizeBytes))
 	if err = api.GetError(response, err); err != nil {
 		log.WithField("error", err).Error("LUN resize failed.")
 		return fmt.Errorf("volume resize failed")
@@ -1778,7 +1836,7 @@ func (d *SANEconomyStorageDriver) Resize(volConfig *storage.VolumeConfig, sizeBy
 				"adjustedVolumeSize": flexvolSize + returnSize - sizeBytes,
 			}).Warning("Failed to resize new volume to exact sum of LUNs' size.")
 		} else {
-			if adjustedVolumeSize, err := d.API.VolumeSize(bucketVol); err != nil {
+			if adjustedVolumeSize, err := client.VolumeSize(bucketVol); err != nil {
 				log.WithField("name", bucketVol).
 					Warning("Failed to get volume size after the second resize operation.")
 			} else {
@@ -1817,6 +1875,23 @@ func (d *SANEconomyStorageDriver) resizeFlexvol(flexvol string, sizeBytes uint64
 	return nil
 }
 
+// RevokeNodeAccess removes a node's initiator from the backend igroup, so that a node that was shut
+// down without unpublishing its volumes can no longer access their LUNs.
+func (d *SANEconomyStorageDriver) RevokeNodeAccess(node *utils.Node, backendUUID string) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method": "RevokeNodeAccess",
+			"Type":   "SANEconomyStorageDriver",
+			"Node":   node.Name,
+		}
+		log.WithFields(fields).Debug(">>>> RevokeNodeAccess")
+		defer log.WithFields(fields).Debug("<<<< RevokeNodeAccess")
+	}
+
+	return removeNodeFromIgroup(node, d.Config.IgroupName, d.API)
+}
+
 func (d *SANEconomyStorageDriver) ReconcileNodeAccess(nodes []*utils.Node, backendUUID string) error {
 
 	nodeNames := make([]string, 0)
@@ -0,0 +1,113 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package ontap
+
+import (
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netapp/trident/storage"
+	sa "github.com/netapp/trident/storage_attribute"
+	"github.com/netapp/trident/storage_drivers/ontap/api"
+)
+
+const (
+	VolumeMovePhaseCompleted = "completed"
+	VolumeMovePhaseFailed    = "failed"
+	VolumeMovePhaseAborted   = "aborted"
+	VolumeMoveStateFailed    = "failed"
+)
+
+// moveOntapVolume starts moving a Flexvol to one of the aggregates that can satisfy the storage pool.
+// A Flexvol already on such an aggregate isn't moved.  The move requires cluster admin credentials.
+func moveOntapVolume(
+	volConfig *storage.VolumeConfig, storagePool *storage.Pool,
+	physicalPools, virtualPools map[string]*storage.Pool, client *api.Client,
+) error {
+
+	name := volConfig.InternalName
+
+	flexvol, err := client.VolumeGet(name)
+	if err != nil {
+		return fmt.Errorf("error reading volume %s; %v", name, err)
+	}
+	if flexvol.VolumeIdAttributesPtr == nil || flexvol.VolumeIdAttributesPtr.ContainingAggregateNamePtr == nil {
+		return fmt.Errorf("could not determine the aggregate of volume %s", name)
+	}
+	currentAggregate := flexvol.VolumeIdAttributesPtr.ContainingAggregateName()
+
+	// The volume's pinned pool is the one it's moving from
+	moveConfig := volConfig.ConstructClone()
+	moveConfig.Pool = ""
+	physicalPoolsForMove, err := getPoolsForCreate(moveConfig, storagePool, map[string]sa.Request{},
+		physicalPools, virtualPools)
+	if err != nil {
+		return err
+	}
+	for _, physicalPool := range physicalPoolsForMove {
+		if physicalPool.Name == currentAggregate {
+			log.WithFields(log.Fields{
+				"volume":    name,
+				"aggregate": currentAggregate,
+			}).Debug("Volume is already on an aggregate of the storage pool.")
+			return nil
+		}
+	}
+
+	aggregate := physicalPoolsForMove[0].Name
+	moveResponse, err := client.VolumeMoveStart(name, aggregate)
+	if err = api.GetError(moveResponse, err); err != nil {
+		return fmt.Errorf("error moving volume %s to aggregate %s; %v", name, aggregate, err)
+	}
+
+	log.WithFields(log.Fields{
+		"volume":               name,
+		"sourceAggregate":      currentAggregate,
+		"destinationAggregate": aggregate,
+	}).Debug("Started volume move.")
+
+	return nil
+}
+
+// getOntapVolumeMoveProgress returns the number of bytes moved, the number of bytes to move, and whether
+// the most recent move of a Flexvol is complete.  A Flexvol that was never moved has nothing to wait for.
+func getOntapVolumeMoveProgress(volConfig *storage.VolumeConfig, client *api.Client) (uint64, uint64, bool, error) {
+
+	name := volConfig.InternalName
+
+	info, err := client.VolumeMoveGet(name)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return 0, 0, true, nil
+		}
+		return 0, 0, false, err
+	}
+
+	var sent, remaining uint64
+	if info.BytesSentPtr != nil && info.BytesSent() > 0 {
+		sent = uint64(info.BytesSent())
+	}
+	if info.BytesRemainingPtr != nil && info.BytesRemaining() > 0 {
+		remaining = uint64(info.BytesRemaining())
+	}
+
+	phase := ""
+	if info.PhasePtr != nil {
+		phase = info.Phase()
+	}
+	switch {
+	case phase == VolumeMovePhaseCompleted:
+		return sent, sent, true, nil
+	case phase == VolumeMovePhaseFailed || phase == VolumeMovePhaseAborted ||
+		(info.StatePtr != nil && info.State() == VolumeMoveStateFailed):
+		details := phase
+		if info.DetailsPtr != nil && info.Details() != "" {
+			details = info.Details()
+		}
+		return 0, 0, false, fmt.Errorf("move of volume %s failed; %s", name, details)
+	}
+
+	return sent, sent + remaining, false, nil
+}
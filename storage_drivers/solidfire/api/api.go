@@ -4,18 +4,21 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"io/ioutil"
 	"math/rand"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
 	log "github.com/sirupsen/logrus"
 
 	tridentconfig "github.com/netapp/trident/config"
+	"github.com/netapp/trident/storage_drivers/apimetrics"
 	"github.com/netapp/trident/utils"
 )
 
@@ -32,6 +35,9 @@ type Client struct {
 	DefaultBlockSize int64
 	DebugTraceFlags  map[string]bool
 	AccountID        int64
+	Metrics          *apimetrics.Recorder
+
+	ctx context.Context
 }
 
 // Config holds the configuration data for the Client to communicate with a SolidFire storage system
@@ -46,6 +52,10 @@ type Config struct {
 	AccessGroups     []int64
 	DefaultBlockSize int64
 	DebugTraceFlags  map[string]bool
+
+	// Identifies the backend in API metrics
+	BackendName       string
+	StorageDriverName string
 }
 
 // VolType holds quality of service configuration data
@@ -64,10 +74,25 @@ func NewFromParameters(pendpoint string, psvip string, pcfg Config) (c *Client,
 		VolumeTypes:      pcfg.Types,
 		DefaultBlockSize: pcfg.DefaultBlockSize,
 		DebugTraceFlags:  pcfg.DebugTraceFlags,
+		Metrics:          apimetrics.NewRecorder(pcfg.BackendName, pcfg.StorageDriverName),
 	}
 	return SFClient, nil
 }
 
+// WithContext returns a copy of this client whose requests are cancelled when the context is done.
+func (c *Client) WithContext(ctx context.Context) *Client {
+	clone := *c
+	clone.ctx = ctx
+	return &clone
+}
+
+func (c *Client) requestContext() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
 // Request performs a json-rpc POST to the configured endpoint
 func (c *Client) Request(method string, params interface{}, id int) ([]byte, error) {
 
@@ -90,7 +115,8 @@ func (c *Client) Request(method string, params interface{}, id int) ([]byte, err
 	})
 
 	// Create the request
-	request, err = http.NewRequest("POST", c.Endpoint, strings.NewReader(string(requestBody)))
+	request, err = http.NewRequestWithContext(c.requestContext(), "POST", c.Endpoint,
+		strings.NewReader(string(requestBody)))
 	if err != nil {
 		return nil, err
 	}
@@ -110,8 +136,10 @@ func (c *Client) Request(method string, params interface{}, id int) ([]byte, err
 		Transport: tr,
 		Timeout:   time.Duration(tridentconfig.StorageAPITimeoutSeconds * time.Second),
 	}
+	recordResult := c.Metrics.Start(method)
 	response, err = httpClient.Do(request)
 	if err != nil {
+		recordResult(apimetrics.ResultTransportError)
 		log.Errorf("Error response from SolidFire API request: %v", err)
 		return nil, errors.New("device API error")
 	}
@@ -119,6 +147,7 @@ func (c *Client) Request(method string, params interface{}, id int) ([]byte, err
 	// Handle HTTP errors such as 401 (Unauthorized)
 	httpError := utils.NewHTTPError(response)
 	if httpError != nil {
+		recordResult(apimetrics.ResultForHTTPStatus(response.StatusCode))
 		log.WithFields(log.Fields{
 			"request":        method,
 			"responseCode":   response.StatusCode,
@@ -130,6 +159,7 @@ func (c *Client) Request(method string, params interface{}, id int) ([]byte, err
 	defer response.Body.Close()
 	responseBody, err := ioutil.ReadAll(response.Body)
 	if err != nil {
+		recordResult(apimetrics.ResultTransportError)
 		return responseBody, err
 	}
 
@@ -148,6 +178,11 @@ func (c *Client) Request(method string, params interface{}, id int) ([]byte, err
 	apiError := Error{}
 	json.Unmarshal([]byte(responseBody), &apiError)
 	if apiError.Fields.Code != 0 {
+		if apiError.Fields.Name != "" {
+			recordResult(apiError.Fields.Name)
+		} else {
+			recordResult(strconv.Itoa(apiError.Fields.Code))
+		}
 		log.WithFields(log.Fields{
 			"ID":      apiError.ID,
 			"code":    apiError.Fields.Code,
@@ -157,6 +192,8 @@ func (c *Client) Request(method string, params interface{}, id int) ([]byte, err
 		return nil, apiError
 	}
 
+	recordResult(apimetrics.ResultSuccess)
+
 	return responseBody, nil
 }
 
@@ -69,6 +69,7 @@ func (c *Client) WaitForVolumeByID(volID int64) (Volume, error) {
 		return nil
 	}
 	volumeExistsNotify := func(err error, duration time.Duration) {
+		c.Metrics.RecordRetry("ListVolumes")
 		log.WithField("increment", duration).Debug("Volume not yet present, waiting.")
 	}
 	volumeBackoff := backoff.NewExponentialBackOff()
@@ -130,6 +131,7 @@ func (c *Client) CloneVolume(req *CloneVolumeRequest) (Volume, error) {
 		return nil
 	}
 	cloneExistsNotify := func(err error, duration time.Duration) {
+		c.Metrics.RecordRetry("CloneVolume")
 		log.WithField("increment", duration).Debugf("Clone not yet present, waiting; err: %+v", err)
 	}
 
@@ -3,6 +3,7 @@
 package solidfire
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -169,15 +170,17 @@ func (d *SANStorageDriver) Initialize(
 	// Create a new api.Config object from the JSON config file
 	svip := config.SVIP
 	cfg := api.Config{
-		TenantName:       config.TenantName,
-		EndPoint:         endpoint,
-		SVIP:             config.SVIP,
-		InitiatorIFace:   config.InitiatorIFace,
-		Types:            config.Types,
-		LegacyNamePrefix: config.LegacyNamePrefix,
-		AccessGroups:     config.AccessGroups,
-		DefaultBlockSize: defaultBlockSize,
-		DebugTraceFlags:  config.DebugTraceFlags,
+		TenantName:        config.TenantName,
+		EndPoint:          endpoint,
+		SVIP:              config.SVIP,
+		InitiatorIFace:    config.InitiatorIFace,
+		Types:             config.Types,
+		LegacyNamePrefix:  config.LegacyNamePrefix,
+		AccessGroups:      config.AccessGroups,
+		DefaultBlockSize:  defaultBlockSize,
+		DebugTraceFlags:   config.DebugTraceFlags,
+		BackendName:       config.BackendName,
+		StorageDriverName: config.StorageDriverName,
 	}
 
 	log.WithFields(log.Fields{
@@ -703,9 +706,11 @@ func MakeSolidFireName(name string) string {
 
 // Create a SolidFire volume
 func (d *SANStorageDriver) Create(
-	volConfig *storage.VolumeConfig, storagePool *storage.Pool, volAttributes map[string]sa.Request,
+	ctx context.Context, volConfig *storage.VolumeConfig, storagePool *storage.Pool, volAttributes map[string]sa.Request,
 ) error {
 
+	client := d.Client.WithContext(ctx)
+
 	name := volConfig.InternalName
 
 	if d.Config.DebugTraceFlags["method"] {
@@ -795,8 +800,8 @@ func (d *SANStorageDriver) Create(
 				MinIOPS: d.DefaultMinIOPS,
 				MaxIOPS: d.DefaultMaxIOPS,
 			}
-		} else if d.Client.VolumeTypes != nil {
-			qos, err = parseType(*d.Client.VolumeTypes, typeOpt)
+		} else if client.VolumeTypes != nil {
+			qos, err = parseType(*client.VolumeTypes, typeOpt)
 			if err != nil {
 				return err
 			}
@@ -806,7 +811,7 @@ func (d *SANStorageDriver) Create(
 	}
 
 	// Use whatever is set in the config as default
-	if d.Client.DefaultBlockSize == 4096 {
+	if client.DefaultBlockSize == 4096 {
 		req.Enable512e = false
 	} else {
 		req.Enable512e = true
@@ -838,7 +843,7 @@ func (d *SANStorageDriver) Create(
 	req.AccountID = d.AccountID
 	req.Name = MakeSolidFireName(name)
 	req.Attributes = meta
-	_, err = d.Client.CreateVolume(&req)
+	_, err = client.CreateVolume(&req)
 	if err != nil {
 		return err
 	}
@@ -846,7 +851,11 @@ func (d *SANStorageDriver) Create(
 }
 
 // Create a volume clone
-func (d *SANStorageDriver) CreateClone(volConfig *storage.VolumeConfig, storagePool *storage.Pool) error {
+func (d *SANStorageDriver) CreateClone(
+	ctx context.Context, volConfig *storage.VolumeConfig, storagePool *storage.Pool,
+) error {
+
+	client := d.Client.WithContext(ctx)
 
 	name := volConfig.InternalName
 	sourceName := volConfig.CloneSourceVolumeInternal
@@ -898,8 +907,8 @@ func (d *SANStorageDriver) CreateClone(volConfig *storage.VolumeConfig, storageP
 				MinIOPS: d.DefaultMinIOPS,
 				MaxIOPS: d.DefaultMaxIOPS,
 			}
-		} else if d.Client.VolumeTypes != nil {
-			qos, err = parseType(*d.Client.VolumeTypes, typeOpt)
+		} else if client.VolumeTypes != nil {
+			qos, err = parseType(*client.VolumeTypes, typeOpt)
 			if err != nil {
 				return err
 			}
@@ -937,7 +946,7 @@ func (d *SANStorageDriver) CreateClone(volConfig *storage.VolumeConfig, storageP
 
 	// If a snapshot was specified, use that
 	if snapshotName != "" {
-		s, err := d.Client.GetSnapshot(0, sourceVolume.VolumeID, snapshotName)
+		s, err := client.GetSnapshot(0, sourceVolume.VolumeID, snapshotName)
 		if err != nil || s.SnapshotID == 0 {
 			log.Errorf("Unable to locate requested source snapshot: %v", err)
 			return errors.New("error performing clone operation, source snapshot not found")
@@ -950,7 +959,7 @@ func (d *SANStorageDriver) CreateClone(volConfig *storage.VolumeConfig, storageP
 	req.Name = MakeSolidFireName(name)
 	req.Attributes = meta
 
-	cloneVolume, err := d.Client.CloneVolume(&req)
+	cloneVolume, err := client.CloneVolume(&req)
 	if err != nil {
 		log.Errorf("Failed to create clone: %v", err)
 		return errors.New("error performing clone operation")
@@ -962,7 +971,7 @@ func (d *SANStorageDriver) CreateClone(volConfig *storage.VolumeConfig, storageP
 
 	if doModify {
 		modifyReq.Qos = qos
-		err = d.Client.ModifyVolume(&modifyReq)
+		err = client.ModifyVolume(&modifyReq)
 		if err != nil {
 			log.Errorf("Failed to update QoS on clone: %v", err)
 			return errors.New("error performing clone operation")
@@ -971,7 +980,7 @@ func (d *SANStorageDriver) CreateClone(volConfig *storage.VolumeConfig, storageP
 	return nil
 }
 
-func (d *SANStorageDriver) Import(volConfig *storage.VolumeConfig, originalName string) error {
+func (d *SANStorageDriver) Import(ctx context.Context, volConfig *storage.VolumeConfig, originalName string) error {
 
 	if d.Config.DebugTraceFlags["method"] {
 		fields := log.Fields{
@@ -984,6 +993,8 @@ func (d *SANStorageDriver) Import(volConfig *storage.VolumeConfig, originalName
 		defer log.WithFields(fields).Debug("<<<< Import")
 	}
 
+	client := d.Client.WithContext(ctx)
+
 	volume, err := d.GetVolume(originalName)
 	if err != nil {
 		return fmt.Errorf("could not import volume %s; %v", originalName, err)
@@ -1012,7 +1023,7 @@ func (d *SANStorageDriver) Import(volConfig *storage.VolumeConfig, originalName
 		var req api.ModifyVolumeRequest
 		req.VolumeID = volume.VolumeID
 		req.Attributes = attrs
-		if err = d.Client.ModifyVolume(&req); err != nil {
+		if err = client.ModifyVolume(&req); err != nil {
 			return fmt.Errorf("could not import volume %s; %v", originalName, err)
 		}
 	}
@@ -1026,7 +1037,7 @@ func (d *SANStorageDriver) Rename(name string, newName string) error {
 }
 
 // Destroy the requested docker volume
-func (d *SANStorageDriver) Destroy(name string) error {
+func (d *SANStorageDriver) Destroy(ctx context.Context, name string) error {
 
 	if d.Config.DebugTraceFlags["method"] {
 		fields := log.Fields{
@@ -1038,6 +1049,8 @@ func (d *SANStorageDriver) Destroy(name string) error {
 		defer log.WithFields(fields).Debug("<<<< Destroy")
 	}
 
+	client := d.Client.WithContext(ctx)
+
 	v, err := d.GetVolume(name)
 	if err != nil && err.Error() != "volume not found" {
 		log.Errorf("Unable to locate volume for delete operation: %v", err)
@@ -1054,13 +1067,13 @@ func (d *SANStorageDriver) Destroy(name string) error {
 		utils.PrepareDeviceForRemoval(0, v.Iqn)
 
 		// Logout from the session
-		err = d.Client.DetachVolume(v)
+		err = client.DetachVolume(v)
 		if err != nil {
 			log.Warningf("Unable to detach volume, deleting anyway: %v", err)
 		}
 	}
 
-	err = d.Client.DeleteVolume(v.VolumeID)
+	err = client.DeleteVolume(v.VolumeID)
 	if err != nil {
 		log.Errorf("Error during delete operation: %v", err)
 		return err
@@ -1072,7 +1085,11 @@ func (d *SANStorageDriver) Destroy(name string) error {
 // Publish the volume to the host specified in publishInfo.  This method may or may not be running on the host
 // where the volume will be mounted, so it should limit itself to updating access rules, initiator groups, etc.
 // that require some host identity (but not locality) as well as storage controller API access.
-func (d *SANStorageDriver) Publish(volConfig *storage.VolumeConfig, publishInfo *utils.VolumePublishInfo) error {
+func (d *SANStorageDriver) Publish(
+	ctx context.Context, volConfig *storage.VolumeConfig, publishInfo *utils.VolumePublishInfo,
+) error {
+
+	client := d.Client.WithContext(ctx)
 
 	name := volConfig.InternalName
 
@@ -1102,7 +1119,7 @@ func (d *SANStorageDriver) Publish(volConfig *storage.VolumeConfig, publishInfo
 	// Get the account, which contains the iSCSI login credentials
 	var req api.GetAccountByIDRequest
 	req.AccountID = v.AccountID
-	account, err := d.Client.GetAccountByID(&req)
+	account, err := client.GetAccountByID(&req)
 	if err != nil {
 		log.Errorf("Failed to get account %v: %+v ", v.AccountID, err)
 		return errors.New("volume attach failure")
@@ -1237,7 +1254,11 @@ func (d *SANStorageDriver) GetSnapshots(volConfig *storage.VolumeConfig) ([]*sto
 }
 
 // CreateSnapshot creates a snapshot for the given volume
-func (d *SANStorageDriver) CreateSnapshot(snapConfig *storage.SnapshotConfig) (*storage.Snapshot, error) {
+func (d *SANStorageDriver) CreateSnapshot(
+	ctx context.Context, snapConfig *storage.SnapshotConfig,
+) (*storage.Snapshot, error) {
+
+	client := d.Client.WithContext(ctx)
 
 	internalSnapName := snapConfig.InternalName
 	internalVolName := snapConfig.VolumeInternalName
@@ -1264,7 +1285,7 @@ func (d *SANStorageDriver) CreateSnapshot(snapConfig *storage.SnapshotConfig) (*
 	req.VolumeID = sourceVolume.VolumeID
 	req.Name = internalSnapName
 
-	snapshot, err := d.Client.CreateSnapshot(&req)
+	snapshot, err := client.CreateSnapshot(&req)
 	if err != nil {
 		return nil, fmt.Errorf("could not create snapshot: %+v", err)
 	}
@@ -1277,7 +1298,9 @@ func (d *SANStorageDriver) CreateSnapshot(snapConfig *storage.SnapshotConfig) (*
 }
 
 // RestoreSnapshot restores a volume (in place) from a snapshot.
-func (d *SANStorageDriver) RestoreSnapshot(snapConfig *storage.SnapshotConfig) error {
+func (d *SANStorageDriver) RestoreSnapshot(ctx context.Context, snapConfig *storage.SnapshotConfig) error {
+
+	client := d.Client.WithContext(ctx)
 
 	internalSnapName := snapConfig.InternalName
 	internalVolName := snapConfig.VolumeInternalName
@@ -1298,7 +1321,7 @@ func (d *SANStorageDriver) RestoreSnapshot(snapConfig *storage.SnapshotConfig) e
 		return err
 	}
 
-	snapshot, err := d.Client.GetSnapshot(-1, volume.VolumeID, internalSnapName)
+	snapshot, err := client.GetSnapshot(-1, volume.VolumeID, internalSnapName)
 	if err != nil {
 		return err
 	}
@@ -1312,7 +1335,7 @@ func (d *SANStorageDriver) RestoreSnapshot(snapConfig *storage.SnapshotConfig) e
 		SnapshotID: snapshot.SnapshotID,
 	}
 
-	_, err = d.Client.RollbackToSnapshot(&req)
+	_, err = client.RollbackToSnapshot(&req)
 	if err != nil {
 		return fmt.Errorf("unable to rollback to snapshot. %v", err)
 	}
@@ -1321,7 +1344,9 @@ func (d *SANStorageDriver) RestoreSnapshot(snapConfig *storage.SnapshotConfig) e
 }
 
 // DeleteSnapshot deletes a snapshot of a volume.
-func (d *SANStorageDriver) DeleteSnapshot(snapConfig *storage.SnapshotConfig) error {
+func (d *SANStorageDriver) DeleteSnapshot(ctx context.Context, snapConfig *storage.SnapshotConfig) error {
+
+	client := d.Client.WithContext(ctx)
 
 	internalSnapName := snapConfig.InternalName
 	internalVolName := snapConfig.VolumeInternalName
@@ -1342,7 +1367,7 @@ func (d *SANStorageDriver) DeleteSnapshot(snapConfig *storage.SnapshotConfig) er
 		return err
 	}
 
-	snapshot, err := d.Client.GetSnapshot(-1, volume.VolumeID, internalSnapName)
+	snapshot, err := client.GetSnapshot(-1, volume.VolumeID, internalSnapName)
 	if err != nil {
 		return err
 	}
@@ -1351,7 +1376,7 @@ func (d *SANStorageDriver) DeleteSnapshot(snapConfig *storage.SnapshotConfig) er
 		return fmt.Errorf("unable to find snapshot %s", internalSnapName)
 	}
 
-	err = d.Client.DeleteSnapshot(snapshot.SnapshotID)
+	err = client.DeleteSnapshot(snapshot.SnapshotID)
 	return err
 }
 
@@ -1368,7 +1393,7 @@ func (d *SANStorageDriver) Get(name string) error {
 	if err != nil {
 		return fmt.Errorf("could not locate volume %s; %v", name, err)
 	} else if !exists {
-		return fmt.Errorf("could not locate volume %s", name)
+		return utils.NotFoundError(fmt.Sprintf("could not locate volume %s", name))
 	}
 	return nil
 }
@@ -1791,11 +1816,16 @@ func (d *SANStorageDriver) GetUpdateType(driverOrig storage.Driver) *roaring.Bit
 		}
 	}
 
+	if drivers.StoragePrefixChanged(d.Config.CommonStorageDriverConfig, dOrig.Config.CommonStorageDriverConfig) {
+		bitmap.Add(storage.PrefixChange)
+	}
 	return bitmap
 }
 
 // Resize expands the volume size.
-func (d *SANStorageDriver) Resize(volConfig *storage.VolumeConfig, sizeBytes uint64) error {
+func (d *SANStorageDriver) Resize(ctx context.Context, volConfig *storage.VolumeConfig, sizeBytes uint64) error {
+
+	client := d.Client.WithContext(ctx)
 
 	name := volConfig.InternalName
 	if d.Config.DebugTraceFlags["method"] {
@@ -1851,7 +1881,7 @@ func (d *SANStorageDriver) Resize(volConfig *storage.VolumeConfig, sizeBytes uin
 	req.VolumeID = volume.VolumeID
 	req.AccountID = volume.AccountID
 	req.TotalSize = int64(sizeBytes)
-	err = d.Client.ModifyVolume(&req)
+	err = client.ModifyVolume(&req)
 	if err != nil {
 		return err
 	}
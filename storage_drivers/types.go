@@ -24,6 +24,9 @@ type CommonStorageDriverConfig struct {
 	SerialNumbers     []string              `json:"serialNumbers,omitEmpty"`
 	DriverContext     trident.DriverContext `json:"-"`
 	LimitVolumeSize   string                `json:"limitVolumeSize"`
+	// AllowedNamespaces and NamespaceSelector restrict the namespaces whose volumes the backend may hold
+	AllowedNamespaces []string `json:"allowedNamespaces,omitempty"`
+	NamespaceSelector string   `json:"namespaceSelector,omitempty"`
 }
 
 type CommonStorageDriverConfigDefaults struct {
@@ -86,6 +89,7 @@ type OntapStorageDriverConfig struct {
 	QtreeQuotaResizePeriod           string   `json:"qtreeQuotaResizePeriod"`           // in seconds, default to 60
 	EmptyFlexvolDeferredDeletePeriod string   `json:"emptyFlexvolDeferredDeletePeriod"` // in seconds, default to 28800
 	NfsMountOptions                  string   `json:"nfsMountOptions"`
+	NASType                          string   `json:"nasType"` // nfs (default) or smb
 	LimitAggregateUsage              string   `json:"limitAggregateUsage"`
 	AutoExportPolicy                 bool     `json:"autoExportPolicy"`
 	AutoExportCIDRs                  []string `json:"autoExportCIDRs"`
@@ -96,12 +100,26 @@ type OntapStorageDriverConfig struct {
 	ChapInitiatorSecret       string                   `json:"chapInitiatorSecret"`
 	ChapTargetUsername        string                   `json:"chapTargetUsername"`
 	ChapTargetInitiatorSecret string                   `json:"chapTargetInitiatorSecret"`
+	IscsiNetworkInterface     string                   `json:"iscsiNetworkInterface,omitempty"`
+	AWSConfig                 *AWSConfig               `json:"aws,omitempty"`
+}
+
+// AWSConfig holds the settings needed to manage an Amazon FSx for NetApp ONTAP file system with
+// the ONTAP drivers.  Endpoints and credentials are discovered from AWS rather than set by hand.
+type AWSConfig struct {
+	APIRegion            string `json:"apiRegion"`
+	APIKey               string `json:"apiKey"`
+	SecretKey            string `json:"secretKey"`
+	FSxFilesystemID      string `json:"fsxFilesystemID"`
+	CredentialsSecretARN string `json:"credentialsSecretARN"`
 }
 
 type OntapStorageDriverPool struct {
-	Labels                           map[string]string `json:"labels"`
-	Region                           string            `json:"region"`
-	Zone                             string            `json:"zone"`
+	Labels map[string]string `json:"labels"`
+	Region string            `json:"region"`
+	Zone   string            `json:"zone"`
+	// SupportedTopologies are the topology segments, such as zones, the pool's volumes are accessible from
+	SupportedTopologies              []map[string]string `json:"supportedTopologies,omitempty"`
 	OntapStorageDriverConfigDefaults `json:"defaults"`
 }
 
@@ -118,6 +136,9 @@ type OntapStorageDriverConfigDefaults struct {
 	FileSystemType  string `json:"fileSystemType"`
 	Encryption      string `json:"encryption"`
 	TieringPolicy   string `json:"tieringPolicy"`
+	MountOptions    string `json:"mountOptions"`
+	// StoragePrefix names a virtual pool's volumes in place of the backend's storagePrefix
+	StoragePrefix string `json:"storagePrefix,omitempty"`
 	CommonStorageDriverConfigDefaults
 }
 
@@ -185,6 +206,7 @@ type AzureNFSStorageDriverConfig struct {
 	ClientID        string `json:"clientID"`
 	ClientSecret    string `json:"clientSecret"`
 	NfsMountOptions string `json:"nfsMountOptions"`
+	NASType         string `json:"nasType"` // nfs (default) or smb
 	AzureNFSStorageDriverPool
 	Storage             []AzureNFSStorageDriverPool `json:"storage"`
 	VolumeCreateTimeout string                      `json:"volumeCreateTimeout"`
@@ -0,0 +1,71 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCronScheduleErrors(t *testing.T) {
+
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"x * * * *",
+		"@fortnightly",
+	} {
+		_, err := ParseCronSchedule(spec)
+		assert.Error(t, err, "expected an error parsing '%s'", spec)
+	}
+}
+
+func TestCronScheduleNext(t *testing.T) {
+
+	// Thursday, 15 October 2020
+	start := time.Date(2020, 10, 15, 10, 17, 30, 0, time.UTC)
+
+	tests := []struct {
+		spec     string
+		expected time.Time
+	}{
+		{"* * * * *", time.Date(2020, 10, 15, 10, 18, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2020, 10, 15, 10, 30, 0, 0, time.UTC)},
+		{"0 * * * *", time.Date(2020, 10, 15, 11, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2020, 10, 15, 11, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2020, 10, 16, 0, 0, 0, 0, time.UTC)},
+		{"30 2 * * *", time.Date(2020, 10, 16, 2, 30, 0, 0, time.UTC)},
+		{"0 9-17/4 * * *", time.Date(2020, 10, 15, 13, 0, 0, 0, time.UTC)},
+		{"0 0 * * 0", time.Date(2020, 10, 18, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2020, 10, 18, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * mon-fri", time.Date(2020, 10, 16, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2020, 11, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 jan *", time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"15,45 10 * * *", time.Date(2020, 10, 15, 10, 45, 0, 0, time.UTC)},
+		// With both day fields restricted, either one matches
+		{"0 0 20 * 6", time.Date(2020, 10, 17, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, test := range tests {
+		schedule, err := ParseCronSchedule(test.spec)
+		if assert.NoError(t, err, "could not parse '%s'", test.spec) {
+			assert.Equal(t, test.expected, schedule.Next(start), "wrong next time for '%s'", test.spec)
+		}
+	}
+
+	// Impossible dates never match
+	schedule, err := ParseCronSchedule("0 0 30 2 *")
+	assert.NoError(t, err)
+	assert.True(t, schedule.Next(start).IsZero())
+}
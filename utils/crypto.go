@@ -28,8 +28,9 @@ type CertInfo struct {
 // other keys and certs, one for a TLS server and one for a TLS client. None of
 // the parameters are configurable...the serial numbers and principal names are
 // hardcoded, the validity period is hardcoded to 1970-2070, and the algorithm
-// and key size are hardcoded to 521-bit elliptic curve.
-func MakeHTTPCertInfo(caCertName, serverCertName, clientCertName string) (*CertInfo, error) {
+// and key size are hardcoded to 521-bit elliptic curve.  The server cert is also
+// valid for any DNS names given, such as those of the service in front of it.
+func MakeHTTPCertInfo(caCertName, serverCertName, clientCertName string, serverDNSNames []string) (*CertInfo, error) {
 
 	certInfo := &CertInfo{}
 
@@ -97,6 +98,7 @@ func MakeHTTPCertInfo(caCertName, serverCertName, clientCertName string) (*CertI
 		ExtKeyUsage:    []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
 		AuthorityKeyId: caCert.SubjectKeyId,
 		SubjectKeyId:   bigIntHash(serverKey.D),
+		DNSNames:       serverDNSNames,
 	}
 
 	derBytes, err = x509.CreateCertificate(rand.Reader, &serverCert, &caCert, &serverKey.PublicKey, caKey)
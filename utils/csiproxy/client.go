@@ -0,0 +1,258 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package csiproxy
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/golang/protobuf/proto"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+)
+
+// proxyAPI is one of the CSI Proxy API groups, each of which is served on its own named pipe.
+type proxyAPI struct {
+	pipe    string
+	service string
+}
+
+var (
+	filesystemAPI = proxyAPI{pipe: `\\.\pipe\csi-proxy-filesystem-v1`, service: "v1.Filesystem"}
+	smbAPI        = proxyAPI{pipe: `\\.\pipe\csi-proxy-smb-v1`, service: "v1.Smb"}
+	iscsiAPI      = proxyAPI{pipe: `\\.\pipe\csi-proxy-iscsi-v1alpha2`, service: "v1alpha2.Iscsi"}
+	diskAPI       = proxyAPI{pipe: `\\.\pipe\csi-proxy-disk-v1`, service: "v1.Disk"}
+	volumeAPI     = proxyAPI{pipe: `\\.\pipe\csi-proxy-volume-v1`, service: "v1.Volume"}
+
+	proxyAPIs = []proxyAPI{filesystemAPI, smbAPI, iscsiAPI, diskAPI, volumeAPI}
+)
+
+// Interface is the set of CSI Proxy operations used by the node plugin.
+type Interface interface {
+	Close()
+	PathExists(ctx context.Context, path string) (bool, error)
+	Mkdir(ctx context.Context, path string) error
+	Rmdir(ctx context.Context, path string, force bool) error
+	NewSMBGlobalMapping(ctx context.Context, remotePath, localPath, username, password string) error
+	RemoveSMBGlobalMapping(ctx context.Context, remotePath string) error
+	AddTargetPortal(ctx context.Context, portal *TargetPortal) error
+	ConnectTarget(ctx context.Context, request *ConnectTargetRequest) error
+	DisconnectTarget(ctx context.Context, portal *TargetPortal, iqn string) error
+	GetTargetDisks(ctx context.Context, portal *TargetPortal, iqn string) ([]uint32, error)
+	SetMutualCHAPSecret(ctx context.Context, secret string) error
+	ListDiskLocations(ctx context.Context) (map[uint32]*DiskLocation, error)
+	Rescan(ctx context.Context) error
+	PartitionDisk(ctx context.Context, diskNumber uint32) error
+	SetDiskOnline(ctx context.Context, diskNumber uint32) error
+	ListVolumesOnDisk(ctx context.Context, diskNumber uint32) ([]string, error)
+	IsVolumeFormatted(ctx context.Context, volumeID string) (bool, error)
+	FormatVolume(ctx context.Context, volumeID string) error
+	MountVolume(ctx context.Context, volumeID, targetPath string) error
+	UnmountVolume(ctx context.Context, volumeID, targetPath string) error
+	ResizeVolume(ctx context.Context, volumeID string) error
+	GetVolumeStats(ctx context.Context, volumeID string) (int64, int64, error)
+	GetVolumeIDFromTargetPath(ctx context.Context, targetPath string) (string, error)
+}
+
+// Client calls CSI Proxy, which runs on each Windows node and performs the privileged storage
+// operations that a node plugin in a Windows container can't do for itself.
+type Client struct {
+	conns map[proxyAPI]*grpc.ClientConn
+}
+
+// NewClient connects to the CSI Proxy named pipes on this host.
+func NewClient() (*Client, error) {
+	return newClient(dialPipe)
+}
+
+func newClient(dial func(context.Context, string) (net.Conn, error)) (*Client, error) {
+
+	c := &Client{conns: make(map[proxyAPI]*grpc.ClientConn)}
+	for _, api := range proxyAPIs {
+		conn, err := grpc.Dial(api.pipe, grpc.WithInsecure(), grpc.WithContextDialer(dial))
+		if err != nil {
+			c.Close()
+			return nil, fmt.Errorf("could not connect to CSI Proxy at %s; %v", api.pipe, err)
+		}
+		c.conns[api] = conn
+	}
+	return c, nil
+}
+
+// Close closes the connections to CSI Proxy.
+func (c *Client) Close() {
+	for api, conn := range c.conns {
+		if err := conn.Close(); err != nil {
+			log.WithField("pipe", api.pipe).Warnf("Could not close CSI Proxy connection; %v", err)
+		}
+	}
+}
+
+func (c *Client) invoke(ctx context.Context, api proxyAPI, method string, request, response proto.Message) error {
+
+	fields := log.Fields{"method": api.service + "." + method, "request": request.String()}
+	log.WithFields(fields).Debug(">>>> csiproxy.invoke")
+	defer log.WithFields(fields).Debug("<<<< csiproxy.invoke")
+
+	if err := c.conns[api].Invoke(ctx, "/"+api.service+"/"+method, request, response); err != nil {
+		return fmt.Errorf("CSI Proxy %s failed; %v", method, err)
+	}
+	return nil
+}
+
+// PathExists returns true if a path exists on the host.
+func (c *Client) PathExists(ctx context.Context, path string) (bool, error) {
+	response := &PathExistsResponse{}
+	err := c.invoke(ctx, filesystemAPI, "PathExists", &PathExistsRequest{Path: path}, response)
+	return response.Exists, err
+}
+
+// Mkdir creates a directory, and any missing parents, on the host.
+func (c *Client) Mkdir(ctx context.Context, path string) error {
+	return c.invoke(ctx, filesystemAPI, "Mkdir", &MkdirRequest{Path: path}, &MkdirResponse{})
+}
+
+// Rmdir removes a directory or link from the host.  If force is set, the directory's contents are
+// removed too.
+func (c *Client) Rmdir(ctx context.Context, path string, force bool) error {
+	return c.invoke(ctx, filesystemAPI, "Rmdir", &RmdirRequest{Path: path, Force: force}, &RmdirResponse{})
+}
+
+// NewSMBGlobalMapping maps an SMB share, such as \\server\share, for all users of the host.  If the
+// share is already mapped, the existing mapping is used.  If localPath is set, it is linked to the share.
+func (c *Client) NewSMBGlobalMapping(ctx context.Context, remotePath, localPath, username, password string) error {
+	request := &NewSMBGlobalMappingRequest{
+		RemotePath: remotePath,
+		LocalPath:  localPath,
+		Username:   username,
+		Password:   password,
+	}
+	return c.invoke(ctx, smbAPI, "NewSmbGlobalMapping", request, &NewSMBGlobalMappingResponse{})
+}
+
+// RemoveSMBGlobalMapping unmaps an SMB share from the host.
+func (c *Client) RemoveSMBGlobalMapping(ctx context.Context, remotePath string) error {
+	return c.invoke(ctx, smbAPI, "RemoveSmbGlobalMapping",
+		&RemoveSMBGlobalMappingRequest{RemotePath: remotePath}, &RemoveSMBGlobalMappingResponse{})
+}
+
+// AddTargetPortal adds an iSCSI target portal to the host's initiator.
+func (c *Client) AddTargetPortal(ctx context.Context, portal *TargetPortal) error {
+	return c.invoke(ctx, iscsiAPI, "AddTargetPortal",
+		&AddTargetPortalRequest{TargetPortal: portal}, &AddTargetPortalResponse{})
+}
+
+// ConnectTarget logs in to an iSCSI target through one of its portals.
+func (c *Client) ConnectTarget(ctx context.Context, request *ConnectTargetRequest) error {
+	return c.invoke(ctx, iscsiAPI, "ConnectTarget", request, &ConnectTargetResponse{})
+}
+
+// DisconnectTarget logs out of an iSCSI target.
+func (c *Client) DisconnectTarget(ctx context.Context, portal *TargetPortal, iqn string) error {
+	return c.invoke(ctx, iscsiAPI, "DisconnectTarget",
+		&DisconnectTargetRequest{TargetPortal: portal, IQN: iqn}, &DisconnectTargetResponse{})
+}
+
+// GetTargetDisks returns the numbers of the host disks backed by an iSCSI target.
+func (c *Client) GetTargetDisks(ctx context.Context, portal *TargetPortal, iqn string) ([]uint32, error) {
+
+	response := &GetTargetDisksResponse{}
+	if err := c.invoke(ctx, iscsiAPI, "GetTargetDisks",
+		&GetTargetDisksRequest{TargetPortal: portal, IQN: iqn}, response); err != nil {
+		return nil, err
+	}
+
+	diskNumbers := make([]uint32, 0, len(response.DiskIDs))
+	for _, diskID := range response.DiskIDs {
+		diskNumber, err := strconv.ParseUint(diskID, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid disk number %s for target %s", diskID, iqn)
+		}
+		diskNumbers = append(diskNumbers, uint32(diskNumber))
+	}
+	return diskNumbers, nil
+}
+
+// SetMutualCHAPSecret sets the secret the host's initiator expects iSCSI targets to present.
+func (c *Client) SetMutualCHAPSecret(ctx context.Context, secret string) error {
+	return c.invoke(ctx, iscsiAPI, "SetMutualChapSecret",
+		&SetMutualCHAPSecretRequest{MutualCHAPSecret: secret}, &SetMutualCHAPSecretResponse{})
+}
+
+// ListDiskLocations returns the SCSI location of each disk on the host, keyed by disk number.
+func (c *Client) ListDiskLocations(ctx context.Context) (map[uint32]*DiskLocation, error) {
+	response := &ListDiskLocationsResponse{}
+	err := c.invoke(ctx, diskAPI, "ListDiskLocations", &ListDiskLocationsRequest{}, response)
+	return response.DiskLocations, err
+}
+
+// Rescan makes the host look for new disks and for changes in disk sizes.
+func (c *Client) Rescan(ctx context.Context) error {
+	return c.invoke(ctx, diskAPI, "Rescan", &RescanRequest{}, &RescanResponse{})
+}
+
+// PartitionDisk initializes a disk with a GPT partition table and a single partition, unless the
+// disk is already partitioned.
+func (c *Client) PartitionDisk(ctx context.Context, diskNumber uint32) error {
+	return c.invoke(ctx, diskAPI, "PartitionDisk",
+		&PartitionDiskRequest{DiskNumber: diskNumber}, &PartitionDiskResponse{})
+}
+
+// SetDiskOnline brings a disk online, since Windows may leave new SAN disks offline.
+func (c *Client) SetDiskOnline(ctx context.Context, diskNumber uint32) error {
+	return c.invoke(ctx, diskAPI, "SetDiskState",
+		&SetDiskStateRequest{DiskNumber: diskNumber, IsOnline: true}, &SetDiskStateResponse{})
+}
+
+// ListVolumesOnDisk returns the IDs of the volumes on a disk.
+func (c *Client) ListVolumesOnDisk(ctx context.Context, diskNumber uint32) ([]string, error) {
+	response := &ListVolumesOnDiskResponse{}
+	err := c.invoke(ctx, volumeAPI, "ListVolumesOnDisk", &ListVolumesOnDiskRequest{DiskNumber: diskNumber}, response)
+	return response.VolumeIDs, err
+}
+
+// IsVolumeFormatted returns true if a volume has a filesystem.
+func (c *Client) IsVolumeFormatted(ctx context.Context, volumeID string) (bool, error) {
+	response := &IsVolumeFormattedResponse{}
+	err := c.invoke(ctx, volumeAPI, "IsVolumeFormatted", &IsVolumeFormattedRequest{VolumeID: volumeID}, response)
+	return response.Formatted, err
+}
+
+// FormatVolume formats a volume with NTFS.
+func (c *Client) FormatVolume(ctx context.Context, volumeID string) error {
+	return c.invoke(ctx, volumeAPI, "FormatVolume", &FormatVolumeRequest{VolumeID: volumeID}, &FormatVolumeResponse{})
+}
+
+// MountVolume makes a volume accessible at a path on the host.
+func (c *Client) MountVolume(ctx context.Context, volumeID, targetPath string) error {
+	return c.invoke(ctx, volumeAPI, "MountVolume",
+		&MountVolumeRequest{VolumeID: volumeID, TargetPath: targetPath}, &MountVolumeResponse{})
+}
+
+// UnmountVolume removes a volume's access path on the host.
+func (c *Client) UnmountVolume(ctx context.Context, volumeID, targetPath string) error {
+	return c.invoke(ctx, volumeAPI, "UnmountVolume",
+		&UnmountVolumeRequest{VolumeID: volumeID, TargetPath: targetPath}, &UnmountVolumeResponse{})
+}
+
+// ResizeVolume grows a volume's partition and filesystem to fill its disk.
+func (c *Client) ResizeVolume(ctx context.Context, volumeID string) error {
+	return c.invoke(ctx, volumeAPI, "ResizeVolume", &ResizeVolumeRequest{VolumeID: volumeID}, &ResizeVolumeResponse{})
+}
+
+// GetVolumeStats returns a volume's total and used bytes.
+func (c *Client) GetVolumeStats(ctx context.Context, volumeID string) (int64, int64, error) {
+	response := &GetVolumeStatsResponse{}
+	err := c.invoke(ctx, volumeAPI, "GetVolumeStats", &GetVolumeStatsRequest{VolumeID: volumeID}, response)
+	return response.TotalBytes, response.UsedBytes, err
+}
+
+// GetVolumeIDFromTargetPath returns the ID of the volume mounted at a path on the host.
+func (c *Client) GetVolumeIDFromTargetPath(ctx context.Context, targetPath string) (string, error) {
+	response := &GetVolumeIDFromTargetPathResponse{}
+	err := c.invoke(ctx, volumeAPI, "GetVolumeIDFromTargetPath",
+		&GetVolumeIDFromTargetPathRequest{TargetPath: targetPath}, response)
+	return response.VolumeID, err
+}
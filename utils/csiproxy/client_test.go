@@ -0,0 +1,117 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package csiproxy
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+)
+
+// fakeHandler returns the response to a request received by a fake CSI Proxy.
+type fakeHandler func(request proto.Message) proto.Message
+
+// newFakeClient returns a client connected to a fake CSI Proxy that serves the given methods.
+func newFakeClient(t *testing.T, service string, methods map[string]fakeHandler,
+	newRequest func(method string) proto.Message) *Client {
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	desc := &grpc.ServiceDesc{ServiceName: service, HandlerType: (*interface{})(nil)}
+	for name, handler := range methods {
+		name, handler := name, handler
+		desc.Methods = append(desc.Methods, grpc.MethodDesc{
+			MethodName: name,
+			Handler: func(_ interface{}, _ context.Context, decode func(interface{}) error,
+				_ grpc.UnaryServerInterceptor) (interface{}, error) {
+				request := newRequest(name)
+				if err := decode(request); err != nil {
+					return nil, err
+				}
+				return handler(request), nil
+			},
+		})
+	}
+
+	server := grpc.NewServer()
+	server.RegisterService(desc, struct{}{})
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	client, err := newClient(func(ctx context.Context, _ string) (net.Conn, error) {
+		return (&net.Dialer{}).DialContext(ctx, "tcp", listener.Addr().String())
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(client.Close)
+	return client
+}
+
+func TestClientISCSIDisks(t *testing.T) {
+
+	portal := &TargetPortal{TargetAddress: "10.0.0.1", TargetPort: 3260}
+	var received *GetTargetDisksRequest
+
+	client := newFakeClient(t, "v1alpha2.Iscsi", map[string]fakeHandler{
+		"GetTargetDisks": func(request proto.Message) proto.Message {
+			received = request.(*GetTargetDisksRequest)
+			return &GetTargetDisksResponse{DiskIDs: []string{"2", "3"}}
+		},
+	}, func(string) proto.Message { return &GetTargetDisksRequest{} })
+
+	disks, err := client.GetTargetDisks(context.Background(), portal, "iqn.1992-08.com.netapp:sn.1")
+	assert.NoError(t, err)
+	assert.Equal(t, []uint32{2, 3}, disks)
+	if assert.NotNil(t, received) {
+		assert.Equal(t, "iqn.1992-08.com.netapp:sn.1", received.IQN)
+		assert.Equal(t, "10.0.0.1", received.TargetPortal.TargetAddress)
+		assert.Equal(t, uint32(3260), received.TargetPortal.TargetPort)
+	}
+
+	// Calls to methods the proxy doesn't serve fail
+	err = client.DisconnectTarget(context.Background(), portal, "iqn.1992-08.com.netapp:sn.1")
+	assert.Error(t, err)
+}
+
+func TestClientDiskLocations(t *testing.T) {
+
+	client := newFakeClient(t, "v1.Disk", map[string]fakeHandler{
+		"ListDiskLocations": func(proto.Message) proto.Message {
+			return &ListDiskLocationsResponse{DiskLocations: map[uint32]*DiskLocation{
+				0: {Adapter: "0", Bus: "0", Target: "0", LUNID: "0"},
+				2: {Adapter: "3", Bus: "0", Target: "1", LUNID: "7"},
+			}}
+		},
+	}, func(string) proto.Message { return &ListDiskLocationsRequest{} })
+
+	locations, err := client.ListDiskLocations(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, locations, 2)
+	if assert.Contains(t, locations, uint32(2)) {
+		assert.Equal(t, "7", locations[2].LUNID)
+	}
+}
+
+func TestSecretsNotLogged(t *testing.T) {
+
+	smbRequest := &NewSMBGlobalMappingRequest{RemotePath: `\\server\share`, Username: "user", Password: "secret"}
+	assert.NotContains(t, smbRequest.String(), "secret")
+
+	connectRequest := &ConnectTargetRequest{IQN: "iqn", CHAPUsername: "user", CHAPSecret: "secret"}
+	assert.NotContains(t, connectRequest.String(), "secret")
+
+	// The secrets are still sent
+	data, err := proto.Marshal(smbRequest)
+	assert.NoError(t, err)
+	decoded := &NewSMBGlobalMappingRequest{}
+	assert.NoError(t, proto.Unmarshal(data, decoded))
+	assert.Equal(t, "secret", decoded.Password)
+}
@@ -0,0 +1,16 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+// +build !windows
+
+package csiproxy
+
+import (
+	"context"
+	"errors"
+	"net"
+)
+
+// CSI Proxy only runs on Windows hosts.
+func dialPipe(_ context.Context, _ string) (net.Conn, error) {
+	return nil, errors.New("CSI Proxy is only available on Windows")
+}
@@ -0,0 +1,14 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package csiproxy
+
+import (
+	"context"
+	"net"
+
+	winio "github.com/Microsoft/go-winio"
+)
+
+func dialPipe(ctx context.Context, pipe string) (net.Conn, error) {
+	return winio.DialPipeContext(ctx, pipe)
+}
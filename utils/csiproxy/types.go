@@ -0,0 +1,352 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package csiproxy
+
+import (
+	"github.com/golang/protobuf/proto"
+)
+
+/////////////////////////////////////////////////////////////////////////////
+//
+// This file contains the CSI Proxy request and response messages used by
+// Trident.  They match the field numbers of the CSI Proxy API definitions,
+// so they may be sent with the standard protobuf codec.
+//
+/////////////////////////////////////////////////////////////////////////////
+
+// Filesystem API (v1)
+
+type PathExistsRequest struct {
+	Path string `protobuf:"bytes,1,opt,name=path,proto3"`
+}
+
+type PathExistsResponse struct {
+	Exists bool `protobuf:"varint,1,opt,name=exists,proto3"`
+}
+
+type MkdirRequest struct {
+	Path string `protobuf:"bytes,1,opt,name=path,proto3"`
+}
+
+type MkdirResponse struct{}
+
+type RmdirRequest struct {
+	Path  string `protobuf:"bytes,1,opt,name=path,proto3"`
+	Force bool   `protobuf:"varint,2,opt,name=force,proto3"`
+}
+
+type RmdirResponse struct{}
+
+// SMB API (v1)
+
+// NewSMBGlobalMappingRequest maps a share for all users of the host, and links LocalPath to it if set.
+type NewSMBGlobalMappingRequest struct {
+	RemotePath string `protobuf:"bytes,1,opt,name=remote_path,json=remotePath,proto3"`
+	LocalPath  string `protobuf:"bytes,2,opt,name=local_path,json=localPath,proto3"`
+	Username   string `protobuf:"bytes,3,opt,name=username,proto3"`
+	Password   string `protobuf:"bytes,4,opt,name=password,proto3"`
+}
+
+type NewSMBGlobalMappingResponse struct{}
+
+type RemoveSMBGlobalMappingRequest struct {
+	RemotePath string `protobuf:"bytes,1,opt,name=remote_path,json=remotePath,proto3"`
+}
+
+type RemoveSMBGlobalMappingResponse struct{}
+
+// iSCSI API (v1alpha2)
+
+type TargetPortal struct {
+	TargetAddress string `protobuf:"bytes,1,opt,name=target_address,json=targetAddress,proto3"`
+	TargetPort    uint32 `protobuf:"varint,2,opt,name=target_port,json=targetPort,proto3"`
+}
+
+// AuthenticationType is how an iSCSI session authenticates with its target.
+type AuthenticationType int32
+
+const (
+	AuthenticationNone       AuthenticationType = 0
+	AuthenticationOneWayCHAP AuthenticationType = 1
+	AuthenticationMutualCHAP AuthenticationType = 2
+)
+
+type AddTargetPortalRequest struct {
+	TargetPortal *TargetPortal `protobuf:"bytes,1,opt,name=target_portal,json=targetPortal,proto3"`
+}
+
+type AddTargetPortalResponse struct{}
+
+type ConnectTargetRequest struct {
+	TargetPortal *TargetPortal      `protobuf:"bytes,1,opt,name=target_portal,json=targetPortal,proto3"`
+	IQN          string             `protobuf:"bytes,2,opt,name=iqn,proto3"`
+	AuthType     AuthenticationType `protobuf:"varint,3,opt,name=auth_type,json=authType,proto3"`
+	CHAPUsername string             `protobuf:"bytes,4,opt,name=chap_username,json=chapUsername,proto3"`
+	CHAPSecret   string             `protobuf:"bytes,5,opt,name=chap_secret,json=chapSecret,proto3"`
+}
+
+type ConnectTargetResponse struct{}
+
+type DisconnectTargetRequest struct {
+	TargetPortal *TargetPortal `protobuf:"bytes,1,opt,name=target_portal,json=targetPortal,proto3"`
+	IQN          string        `protobuf:"bytes,2,opt,name=iqn,proto3"`
+}
+
+type DisconnectTargetResponse struct{}
+
+type GetTargetDisksRequest struct {
+	TargetPortal *TargetPortal `protobuf:"bytes,1,opt,name=target_portal,json=targetPortal,proto3"`
+	IQN          string        `protobuf:"bytes,2,opt,name=iqn,proto3"`
+}
+
+type GetTargetDisksResponse struct {
+	DiskIDs []string `protobuf:"bytes,1,rep,name=diskIDs,proto3"`
+}
+
+type SetMutualCHAPSecretRequest struct {
+	MutualCHAPSecret string `protobuf:"bytes,1,opt,name=MutualChapSecret,proto3"`
+}
+
+type SetMutualCHAPSecretResponse struct{}
+
+// Disk API (v1)
+
+type DiskLocation struct {
+	Adapter string `protobuf:"bytes,1,opt,name=Adapter,proto3"`
+	Bus     string `protobuf:"bytes,2,opt,name=Bus,proto3"`
+	Target  string `protobuf:"bytes,3,opt,name=Target,proto3"`
+	LUNID   string `protobuf:"bytes,4,opt,name=LUNID,proto3"`
+}
+
+type ListDiskLocationsRequest struct{}
+
+type ListDiskLocationsResponse struct {
+	DiskLocations map[uint32]*DiskLocation `protobuf:"bytes,1,rep,name=disk_locations,json=diskLocations,proto3" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+type RescanRequest struct{}
+
+type RescanResponse struct{}
+
+type PartitionDiskRequest struct {
+	DiskNumber uint32 `protobuf:"varint,1,opt,name=disk_number,json=diskNumber,proto3"`
+}
+
+type PartitionDiskResponse struct{}
+
+type SetDiskStateRequest struct {
+	DiskNumber uint32 `protobuf:"varint,1,opt,name=disk_number,json=diskNumber,proto3"`
+	IsOnline   bool   `protobuf:"varint,2,opt,name=is_online,json=isOnline,proto3"`
+}
+
+type SetDiskStateResponse struct{}
+
+// Volume API (v1)
+
+type ListVolumesOnDiskRequest struct {
+	DiskNumber      uint32 `protobuf:"varint,1,opt,name=disk_number,json=diskNumber,proto3"`
+	PartitionNumber uint32 `protobuf:"varint,2,opt,name=partition_number,json=partitionNumber,proto3"`
+}
+
+type ListVolumesOnDiskResponse struct {
+	VolumeIDs []string `protobuf:"bytes,1,rep,name=volume_ids,json=volumeIds,proto3"`
+}
+
+type IsVolumeFormattedRequest struct {
+	VolumeID string `protobuf:"bytes,1,opt,name=volume_id,json=volumeId,proto3"`
+}
+
+type IsVolumeFormattedResponse struct {
+	Formatted bool `protobuf:"varint,1,opt,name=formatted,proto3"`
+}
+
+// FormatVolumeRequest formats a volume with NTFS.
+type FormatVolumeRequest struct {
+	VolumeID string `protobuf:"bytes,1,opt,name=volume_id,json=volumeId,proto3"`
+}
+
+type FormatVolumeResponse struct{}
+
+type MountVolumeRequest struct {
+	VolumeID   string `protobuf:"bytes,1,opt,name=volume_id,json=volumeId,proto3"`
+	TargetPath string `protobuf:"bytes,2,opt,name=target_path,json=targetPath,proto3"`
+}
+
+type MountVolumeResponse struct{}
+
+type UnmountVolumeRequest struct {
+	VolumeID   string `protobuf:"bytes,1,opt,name=volume_id,json=volumeId,proto3"`
+	TargetPath string `protobuf:"bytes,2,opt,name=target_path,json=targetPath,proto3"`
+}
+
+type UnmountVolumeResponse struct{}
+
+// ResizeVolumeRequest grows a volume's partition; a size of zero fills the disk.
+type ResizeVolumeRequest struct {
+	VolumeID  string `protobuf:"bytes,1,opt,name=volume_id,json=volumeId,proto3"`
+	SizeBytes int64  `protobuf:"varint,2,opt,name=size_bytes,json=sizeBytes,proto3"`
+}
+
+type ResizeVolumeResponse struct{}
+
+type GetVolumeStatsRequest struct {
+	VolumeID string `protobuf:"bytes,1,opt,name=volume_id,json=volumeId,proto3"`
+}
+
+type GetVolumeStatsResponse struct {
+	TotalBytes int64 `protobuf:"varint,1,opt,name=total_bytes,json=totalBytes,proto3"`
+	UsedBytes  int64 `protobuf:"varint,2,opt,name=used_bytes,json=usedBytes,proto3"`
+}
+
+type GetVolumeIDFromTargetPathRequest struct {
+	TargetPath string `protobuf:"bytes,1,opt,name=target_path,json=targetPath,proto3"`
+}
+
+type GetVolumeIDFromTargetPathResponse struct {
+	VolumeID string `protobuf:"bytes,1,opt,name=volume_id,json=volumeId,proto3"`
+}
+
+// The proto.Message methods below let the messages be marshaled by the protobuf codec.
+
+func (m *PathExistsRequest) Reset()                      { *m = PathExistsRequest{} }
+func (m *PathExistsRequest) String() string              { return proto.CompactTextString(m) }
+func (*PathExistsRequest) ProtoMessage()                 {}
+func (m *PathExistsResponse) Reset()                     { *m = PathExistsResponse{} }
+func (m *PathExistsResponse) String() string             { return proto.CompactTextString(m) }
+func (*PathExistsResponse) ProtoMessage()                {}
+func (m *MkdirRequest) Reset()                           { *m = MkdirRequest{} }
+func (m *MkdirRequest) String() string                   { return proto.CompactTextString(m) }
+func (*MkdirRequest) ProtoMessage()                      {}
+func (m *MkdirResponse) Reset()                          { *m = MkdirResponse{} }
+func (m *MkdirResponse) String() string                  { return proto.CompactTextString(m) }
+func (*MkdirResponse) ProtoMessage()                     {}
+func (m *RmdirRequest) Reset()                           { *m = RmdirRequest{} }
+func (m *RmdirRequest) String() string                   { return proto.CompactTextString(m) }
+func (*RmdirRequest) ProtoMessage()                      {}
+func (m *RmdirResponse) Reset()                          { *m = RmdirResponse{} }
+func (m *RmdirResponse) String() string                  { return proto.CompactTextString(m) }
+func (*RmdirResponse) ProtoMessage()                     {}
+func (m *NewSMBGlobalMappingRequest) Reset()             { *m = NewSMBGlobalMappingRequest{} }
+func (*NewSMBGlobalMappingRequest) String() string       { return "NewSMBGlobalMappingRequest{<suppressed>}" }
+func (*NewSMBGlobalMappingRequest) ProtoMessage()        {}
+func (m *NewSMBGlobalMappingResponse) Reset()            { *m = NewSMBGlobalMappingResponse{} }
+func (m *NewSMBGlobalMappingResponse) String() string    { return proto.CompactTextString(m) }
+func (*NewSMBGlobalMappingResponse) ProtoMessage()       {}
+func (m *RemoveSMBGlobalMappingRequest) Reset()          { *m = RemoveSMBGlobalMappingRequest{} }
+func (m *RemoveSMBGlobalMappingRequest) String() string  { return proto.CompactTextString(m) }
+func (*RemoveSMBGlobalMappingRequest) ProtoMessage()     {}
+func (m *RemoveSMBGlobalMappingResponse) Reset()         { *m = RemoveSMBGlobalMappingResponse{} }
+func (m *RemoveSMBGlobalMappingResponse) String() string { return proto.CompactTextString(m) }
+func (*RemoveSMBGlobalMappingResponse) ProtoMessage()    {}
+func (m *TargetPortal) Reset()                           { *m = TargetPortal{} }
+func (m *TargetPortal) String() string                   { return proto.CompactTextString(m) }
+func (*TargetPortal) ProtoMessage()                      {}
+func (m *AddTargetPortalRequest) Reset()                 { *m = AddTargetPortalRequest{} }
+func (m *AddTargetPortalRequest) String() string         { return proto.CompactTextString(m) }
+func (*AddTargetPortalRequest) ProtoMessage()            {}
+func (m *AddTargetPortalResponse) Reset()                { *m = AddTargetPortalResponse{} }
+func (m *AddTargetPortalResponse) String() string        { return proto.CompactTextString(m) }
+func (*AddTargetPortalResponse) ProtoMessage()           {}
+func (m *ConnectTargetRequest) Reset()                   { *m = ConnectTargetRequest{} }
+func (*ConnectTargetRequest) String() string             { return "ConnectTargetRequest{<suppressed>}" }
+func (*ConnectTargetRequest) ProtoMessage()              {}
+func (m *ConnectTargetResponse) Reset()                  { *m = ConnectTargetResponse{} }
+func (m *ConnectTargetResponse) String() string          { return proto.CompactTextString(m) }
+func (*ConnectTargetResponse) ProtoMessage()             {}
+func (m *DisconnectTargetRequest) Reset()                { *m = DisconnectTargetRequest{} }
+func (m *DisconnectTargetRequest) String() string        { return proto.CompactTextString(m) }
+func (*DisconnectTargetRequest) ProtoMessage()           {}
+func (m *DisconnectTargetResponse) Reset()               { *m = DisconnectTargetResponse{} }
+func (m *DisconnectTargetResponse) String() string       { return proto.CompactTextString(m) }
+func (*DisconnectTargetResponse) ProtoMessage()          {}
+func (m *GetTargetDisksRequest) Reset()                  { *m = GetTargetDisksRequest{} }
+func (m *GetTargetDisksRequest) String() string          { return proto.CompactTextString(m) }
+func (*GetTargetDisksRequest) ProtoMessage()             {}
+func (m *GetTargetDisksResponse) Reset()                 { *m = GetTargetDisksResponse{} }
+func (m *GetTargetDisksResponse) String() string         { return proto.CompactTextString(m) }
+func (*GetTargetDisksResponse) ProtoMessage()            {}
+func (m *SetMutualCHAPSecretRequest) Reset()             { *m = SetMutualCHAPSecretRequest{} }
+func (*SetMutualCHAPSecretRequest) String() string       { return "SetMutualCHAPSecretRequest{<suppressed>}" }
+func (*SetMutualCHAPSecretRequest) ProtoMessage()        {}
+func (m *SetMutualCHAPSecretResponse) Reset()            { *m = SetMutualCHAPSecretResponse{} }
+func (m *SetMutualCHAPSecretResponse) String() string    { return proto.CompactTextString(m) }
+func (*SetMutualCHAPSecretResponse) ProtoMessage()       {}
+func (m *DiskLocation) Reset()                           { *m = DiskLocation{} }
+func (m *DiskLocation) String() string                   { return proto.CompactTextString(m) }
+func (*DiskLocation) ProtoMessage()                      {}
+func (m *ListDiskLocationsRequest) Reset()               { *m = ListDiskLocationsRequest{} }
+func (m *ListDiskLocationsRequest) String() string       { return proto.CompactTextString(m) }
+func (*ListDiskLocationsRequest) ProtoMessage()          {}
+func (m *ListDiskLocationsResponse) Reset()              { *m = ListDiskLocationsResponse{} }
+func (m *ListDiskLocationsResponse) String() string      { return proto.CompactTextString(m) }
+func (*ListDiskLocationsResponse) ProtoMessage()         {}
+func (m *RescanRequest) Reset()                          { *m = RescanRequest{} }
+func (m *RescanRequest) String() string                  { return proto.CompactTextString(m) }
+func (*RescanRequest) ProtoMessage()                     {}
+func (m *RescanResponse) Reset()                         { *m = RescanResponse{} }
+func (m *RescanResponse) String() string                 { return proto.CompactTextString(m) }
+func (*RescanResponse) ProtoMessage()                    {}
+func (m *PartitionDiskRequest) Reset()                   { *m = PartitionDiskRequest{} }
+func (m *PartitionDiskRequest) String() string           { return proto.CompactTextString(m) }
+func (*PartitionDiskRequest) ProtoMessage()              {}
+func (m *PartitionDiskResponse) Reset()                  { *m = PartitionDiskResponse{} }
+func (m *PartitionDiskResponse) String() string          { return proto.CompactTextString(m) }
+func (*PartitionDiskResponse) ProtoMessage()             {}
+func (m *SetDiskStateRequest) Reset()                    { *m = SetDiskStateRequest{} }
+func (m *SetDiskStateRequest) String() string            { return proto.CompactTextString(m) }
+func (*SetDiskStateRequest) ProtoMessage()               {}
+func (m *SetDiskStateResponse) Reset()                   { *m = SetDiskStateResponse{} }
+func (m *SetDiskStateResponse) String() string           { return proto.CompactTextString(m) }
+func (*SetDiskStateResponse) ProtoMessage()              {}
+func (m *ListVolumesOnDiskRequest) Reset()               { *m = ListVolumesOnDiskRequest{} }
+func (m *ListVolumesOnDiskRequest) String() string       { return proto.CompactTextString(m) }
+func (*ListVolumesOnDiskRequest) ProtoMessage()          {}
+func (m *ListVolumesOnDiskResponse) Reset()              { *m = ListVolumesOnDiskResponse{} }
+func (m *ListVolumesOnDiskResponse) String() string      { return proto.CompactTextString(m) }
+func (*ListVolumesOnDiskResponse) ProtoMessage()         {}
+func (m *IsVolumeFormattedRequest) Reset()               { *m = IsVolumeFormattedRequest{} }
+func (m *IsVolumeFormattedRequest) String() string       { return proto.CompactTextString(m) }
+func (*IsVolumeFormattedRequest) ProtoMessage()          {}
+func (m *IsVolumeFormattedResponse) Reset()              { *m = IsVolumeFormattedResponse{} }
+func (m *IsVolumeFormattedResponse) String() string      { return proto.CompactTextString(m) }
+func (*IsVolumeFormattedResponse) ProtoMessage()         {}
+func (m *FormatVolumeRequest) Reset()                    { *m = FormatVolumeRequest{} }
+func (m *FormatVolumeRequest) String() string            { return proto.CompactTextString(m) }
+func (*FormatVolumeRequest) ProtoMessage()               {}
+func (m *FormatVolumeResponse) Reset()                   { *m = FormatVolumeResponse{} }
+func (m *FormatVolumeResponse) String() string           { return proto.CompactTextString(m) }
+func (*FormatVolumeResponse) ProtoMessage()              {}
+func (m *MountVolumeRequest) Reset()                     { *m = MountVolumeRequest{} }
+func (m *MountVolumeRequest) String() string             { return proto.CompactTextString(m) }
+func (*MountVolumeRequest) ProtoMessage()                {}
+func (m *MountVolumeResponse) Reset()                    { *m = MountVolumeResponse{} }
+func (m *MountVolumeResponse) String() string            { return proto.CompactTextString(m) }
+func (*MountVolumeResponse) ProtoMessage()               {}
+func (m *UnmountVolumeRequest) Reset()                   { *m = UnmountVolumeRequest{} }
+func (m *UnmountVolumeRequest) String() string           { return proto.CompactTextString(m) }
+func (*UnmountVolumeRequest) ProtoMessage()              {}
+func (m *UnmountVolumeResponse) Reset()                  { *m = UnmountVolumeResponse{} }
+func (m *UnmountVolumeResponse) String() string          { return proto.CompactTextString(m) }
+func (*UnmountVolumeResponse) ProtoMessage()             {}
+func (m *ResizeVolumeRequest) Reset()                    { *m = ResizeVolumeRequest{} }
+func (m *ResizeVolumeRequest) String() string            { return proto.CompactTextString(m) }
+func (*ResizeVolumeRequest) ProtoMessage()               {}
+func (m *ResizeVolumeResponse) Reset()                   { *m = ResizeVolumeResponse{} }
+func (m *ResizeVolumeResponse) String() string           { return proto.CompactTextString(m) }
+func (*ResizeVolumeResponse) ProtoMessage()              {}
+func (m *GetVolumeStatsRequest) Reset()                  { *m = GetVolumeStatsRequest{} }
+func (m *GetVolumeStatsRequest) String() string          { return proto.CompactTextString(m) }
+func (*GetVolumeStatsRequest) ProtoMessage()             {}
+func (m *GetVolumeStatsResponse) Reset()                 { *m = GetVolumeStatsResponse{} }
+func (m *GetVolumeStatsResponse) String() string         { return proto.CompactTextString(m) }
+func (*GetVolumeStatsResponse) ProtoMessage()            {}
+func (m *GetVolumeIDFromTargetPathRequest) Reset()       { *m = GetVolumeIDFromTargetPathRequest{} }
+func (m *GetVolumeIDFromTargetPathRequest) String() string {
+	return proto.CompactTextString(m)
+}
+func (*GetVolumeIDFromTargetPathRequest) ProtoMessage() {}
+func (m *GetVolumeIDFromTargetPathResponse) Reset()     { *m = GetVolumeIDFromTargetPathResponse{} }
+func (m *GetVolumeIDFromTargetPathResponse) String() string {
+	return proto.CompactTextString(m)
+}
+func (*GetVolumeIDFromTargetPathResponse) ProtoMessage() {}
@@ -279,3 +279,106 @@ func IsUnsupportedConfigError(err error) bool {
 	_, ok := err.(*unsupportedConfigError)
 	return ok
 }
+
+/////////////////////////////////////////////////////////////////////////////
+// volumeCreateFailedError
+/////////////////////////////////////////////////////////////////////////////
+
+type volumeCreateFailedError struct {
+	message       string
+	backendErrors map[string]string
+}
+
+func (e *volumeCreateFailedError) Error() string { return e.message }
+
+// VolumeCreateFailedError returns an error for a volume that couldn't be created on any backend,
+// recording the reason each backend that was tried or excluded couldn't create it.
+func VolumeCreateFailedError(message string, backendErrors map[string]string) error {
+	return &volumeCreateFailedError{message: message, backendErrors: backendErrors}
+}
+
+func IsVolumeCreateFailedError(err error) bool {
+	if err == nil {
+		return false
+	}
+	_, ok := err.(*volumeCreateFailedError)
+	return ok
+}
+
+// GetVolumeCreateBackendErrors returns the reasons, keyed by backend name, that a volume couldn't
+// be created, or nil if the error isn't a volumeCreateFailedError.
+func GetVolumeCreateBackendErrors(err error) map[string]string {
+	if createErr, ok := err.(*volumeCreateFailedError); ok {
+		return createErr.backendErrors
+	}
+	return nil
+}
+
+/////////////////////////////////////////////////////////////////////////////
+// quotaExceededError
+/////////////////////////////////////////////////////////////////////////////
+
+type quotaExceededError struct {
+	message string
+}
+
+func (e *quotaExceededError) Error() string { return e.message }
+
+func QuotaExceededError(message string) error {
+	return &quotaExceededError{message}
+}
+
+func IsQuotaExceededError(err error) bool {
+	if err == nil {
+		return false
+	}
+	_, ok := err.(*quotaExceededError)
+	return ok
+}
+
+/////////////////////////////////////////////////////////////////////////////
+// volumePublishedError
+/////////////////////////////////////////////////////////////////////////////
+
+type volumePublishedError struct {
+	message string
+}
+
+func (e *volumePublishedError) Error() string { return e.message }
+
+// VolumePublishedError is returned when a volume that may only be published to one node at a time
+// is already published to another node.
+func VolumePublishedError(message string) error {
+	return &volumePublishedError{message}
+}
+
+func IsVolumePublishedError(err error) bool {
+	if err == nil {
+		return false
+	}
+	_, ok := err.(*volumePublishedError)
+	return ok
+}
+
+/////////////////////////////////////////////////////////////////////////////
+// filesystemCorruptError
+/////////////////////////////////////////////////////////////////////////////
+
+type filesystemCorruptError struct {
+	message string
+}
+
+func (e *filesystemCorruptError) Error() string { return e.message }
+
+// FilesystemCorruptError is returned when a filesystem check finds errors in a volume's filesystem.
+func FilesystemCorruptError(message string) error {
+	return &filesystemCorruptError{message}
+}
+
+func IsFilesystemCorruptError(err error) bool {
+	if err == nil {
+		return false
+	}
+	_, ok := err.(*filesystemCorruptError)
+	return ok
+}
@@ -0,0 +1,155 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package utils
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const udevRulesDir = "/etc/udev/rules.d"
+
+// BlockDeviceTuning is the I/O settings applied to a volume's block device when it is staged.  Settings
+// that are empty are left as the node has them.
+type BlockDeviceTuning struct {
+	Scheduler   string
+	ReadAheadKB string
+	NrRequests  string
+}
+
+// IsEmpty reports whether the tuning leaves all of a device's settings alone.
+func (t *BlockDeviceTuning) IsEmpty() bool {
+	return t.Scheduler == "" && t.ReadAheadKB == "" && t.NrRequests == ""
+}
+
+// queueAttributes returns the sysfs queue attributes the tuning sets, with their values.  The scheduler
+// comes first, since changing it resets the device's request queue depth.
+func (t *BlockDeviceTuning) queueAttributes() [][2]string {
+	attributes := make([][2]string, 0)
+	if t.Scheduler != "" {
+		attributes = append(attributes, [2]string{"scheduler", t.Scheduler})
+	}
+	if t.ReadAheadKB != "" {
+		attributes = append(attributes, [2]string{"read_ahead_kb", t.ReadAheadKB})
+	}
+	if t.NrRequests != "" {
+		attributes = append(attributes, [2]string{"nr_requests", t.NrRequests})
+	}
+	return attributes
+}
+
+// udevRule returns a udev rule that applies the tuning to a multipath device and to its paths, which
+// are found by the WWID multipath names the device after.
+func (t *BlockDeviceTuning) udevRule(wwid string) string {
+
+	assignments := make([]string, 0)
+	for _, attribute := range t.queueAttributes() {
+		assignments = append(assignments, fmt.Sprintf(`ATTR{queue/%s}="%s"`, attribute[0], attribute[1]))
+	}
+	settings := strings.Join(assignments, ", ")
+
+	return "# Managed by Trident; changes will be overwritten.\n" +
+		fmt.Sprintf(`ACTION=="add|change", SUBSYSTEM=="block", ENV{DM_UUID}=="mpath-%s", %s`, wwid, settings) +
+		"\n" +
+		fmt.Sprintf(`ACTION=="add|change", SUBSYSTEM=="block", ENV{DEVTYPE}=="disk", ENV{ID_SERIAL}=="%s", %s`,
+			wwid, settings) +
+		"\n"
+}
+
+// blockDeviceTuningRulePath returns the path of the udev rule that tunes a volume's device.
+func blockDeviceTuningRulePath(name string) string {
+	return chrootPathPrefix + udevRulesDir + "/99-trident-" + name + ".rules"
+}
+
+// TuneBlockDevice applies a volume's I/O settings to its device, such as /dev/dm-0, and to the device's
+// paths through sysfs.  The settings of a multipath device are also written to a udev rule, so that
+// paths added later, such as when a lost iSCSI session is restored, get the same settings.
+func TuneBlockDevice(name, devicePath string, tuning BlockDeviceTuning) error {
+
+	fields := log.Fields{
+		"name":       name,
+		"devicePath": devicePath,
+		"tuning":     tuning,
+	}
+	log.WithFields(fields).Debug(">>>> iotuning.TuneBlockDevice")
+	defer log.WithFields(fields).Debug("<<<< iotuning.TuneBlockDevice")
+
+	if tuning.IsEmpty() {
+		return nil
+	}
+
+	device := strings.TrimPrefix(devicePath, "/dev/")
+	devices := append([]string{device}, findDevicesForMultipathDevice(device)...)
+
+	failures := make([]string, 0)
+	for _, d := range devices {
+		if err := setQueueAttributes(d, tuning); err != nil {
+			failures = append(failures, err.Error())
+		}
+	}
+
+	if uuid, err := ioutil.ReadFile(chrootPathPrefix + "/sys/block/" + device + "/dm/uuid"); err == nil &&
+		strings.HasPrefix(string(uuid), "mpath-") {
+		wwid := strings.TrimPrefix(strings.TrimSpace(string(uuid)), "mpath-")
+		rulePath := blockDeviceTuningRulePath(name)
+		if err = ioutil.WriteFile(rulePath, []byte(tuning.udevRule(wwid)), 0644); err != nil {
+			failures = append(failures, fmt.Sprintf("could not write udev rule %s; %v", rulePath, err))
+		}
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("could not tune device %s; %s", devicePath, strings.Join(failures, "; "))
+	}
+
+	log.WithFields(fields).WithField("devices", devices).Debug("Tuned block device.")
+	return nil
+}
+
+// setQueueAttributes writes a tuning's settings to a device's sysfs queue attributes.
+func setQueueAttributes(device string, tuning BlockDeviceTuning) error {
+
+	queueDir := chrootPathPrefix + "/sys/block/" + device + "/queue/"
+
+	for _, attribute := range tuning.queueAttributes() {
+		name, value := attribute[0], attribute[1]
+
+		if name == "scheduler" {
+			available, err := ioutil.ReadFile(queueDir + name)
+			if err != nil {
+				return fmt.Errorf("could not read schedulers of device %s; %v", device, err)
+			}
+			if !schedulerAvailable(string(available), value) {
+				return fmt.Errorf("scheduler %s is not available for device %s; the device has %s", value,
+					device, strings.TrimSpace(string(available)))
+			}
+		}
+
+		if err := ioutil.WriteFile(queueDir+name, []byte(value), 0644); err != nil {
+			return fmt.Errorf("could not set %s of device %s to %s; %v", name, device, value, err)
+		}
+	}
+	return nil
+}
+
+// schedulerAvailable reports whether a scheduler is in a device's list of schedulers, such as
+// "[mq-deadline] kyber none", in which the one in use is bracketed.
+func schedulerAvailable(available, scheduler string) bool {
+	for _, s := range strings.Fields(available) {
+		if strings.Trim(s, "[]") == scheduler {
+			return true
+		}
+	}
+	return false
+}
+
+// RemoveBlockDeviceTuning removes the udev rule that tunes a volume's device, if there is one.
+func RemoveBlockDeviceTuning(name string) error {
+	if err := os.Remove(blockDeviceTuningRulePath(name)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
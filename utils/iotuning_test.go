@@ -0,0 +1,73 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package utils
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTuneBlockDevice(t *testing.T) {
+
+	sysRoot, err := ioutil.TempDir("", "sysfs")
+	assert.NoError(t, err)
+	defer os.RemoveAll(sysRoot)
+
+	savedPrefix := chrootPathPrefix
+	chrootPathPrefix = sysRoot
+	defer func() { chrootPathPrefix = savedPrefix }()
+
+	assert.NoError(t, os.MkdirAll(filepath.Join(sysRoot, "etc", "udev", "rules.d"), 0755))
+
+	writeValue := func(value string, elem ...string) {
+		path := filepath.Join(append([]string{sysRoot, "sys", "block"}, elem...)...)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.NoError(t, ioutil.WriteFile(path, []byte(value+"\n"), 0644))
+	}
+	readValue := func(elem ...string) string {
+		data, err := ioutil.ReadFile(filepath.Join(append([]string{sysRoot, "sys", "block"}, elem...)...))
+		assert.NoError(t, err)
+		return string(data)
+	}
+
+	// sda and sdb are paths to multipath device dm-0
+	for _, device := range []string{"dm-0", "sda", "sdb"} {
+		writeValue("[mq-deadline] kyber none", device, "queue", "scheduler")
+		writeValue("128", device, "queue", "read_ahead_kb")
+		writeValue("64", device, "queue", "nr_requests")
+	}
+	writeValue("mpath-3600a0980", "dm-0", "dm", "uuid")
+	assert.NoError(t, os.MkdirAll(filepath.Join(sysRoot, "sys", "block", "dm-0", "slaves", "sda"), 0755))
+	assert.NoError(t, os.MkdirAll(filepath.Join(sysRoot, "sys", "block", "dm-0", "slaves", "sdb"), 0755))
+
+	tuning := BlockDeviceTuning{Scheduler: "none", ReadAheadKB: "4096"}
+	assert.NoError(t, TuneBlockDevice("pvc-1", "/dev/dm-0", tuning))
+	for _, device := range []string{"dm-0", "sda", "sdb"} {
+		assert.Equal(t, "none", readValue(device, "queue", "scheduler"), device)
+		assert.Equal(t, "4096", readValue(device, "queue", "read_ahead_kb"), device)
+		assert.Equal(t, "64\n", readValue(device, "queue", "nr_requests"), "unset values are left alone")
+	}
+
+	rule, err := ioutil.ReadFile(blockDeviceTuningRulePath("pvc-1"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(rule),
+		`ENV{DM_UUID}=="mpath-3600a0980", ATTR{queue/scheduler}="none", ATTR{queue/read_ahead_kb}="4096"`)
+	assert.Contains(t, string(rule), `ENV{ID_SERIAL}=="3600a0980", ATTR{queue/scheduler}="none"`)
+
+	assert.NoError(t, RemoveBlockDeviceTuning("pvc-1"))
+	_, err = os.Stat(blockDeviceTuningRulePath("pvc-1"))
+	assert.True(t, os.IsNotExist(err), "udev rule removed")
+	assert.NoError(t, RemoveBlockDeviceTuning("pvc-1"), "removing a missing rule")
+
+	// A scheduler the device doesn't have is an error
+	writeValue("[mq-deadline] none", "sdc", "queue", "scheduler")
+	assert.Error(t, TuneBlockDevice("pvc-2", "/dev/sdc", BlockDeviceTuning{Scheduler: "bfq"}))
+	_, err = os.Stat(blockDeviceTuningRulePath("pvc-2"))
+	assert.True(t, os.IsNotExist(err), "no udev rule without a multipath device")
+
+	assert.NoError(t, TuneBlockDevice("pvc-3", "/dev/sdd", BlockDeviceTuning{}), "nothing to tune")
+}
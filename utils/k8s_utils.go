@@ -12,7 +12,6 @@ import (
 	"strings"
 
 	"os"
-	"syscall"
 
 	log "github.com/sirupsen/logrus"
 )
@@ -75,7 +74,7 @@ func IsLikelyNotMountPoint(mountpoint string) (bool, error) {
 		return true, err
 	}
 	// If the directory has a different device as parent, then it is a mountpoint.
-	if stat.Sys().(*syscall.Stat_t).Dev != rootStat.Sys().(*syscall.Stat_t).Dev {
+	if !onSameDevice(stat, rootStat) {
 		return false, nil
 	}
 
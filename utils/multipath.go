@@ -0,0 +1,310 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package utils
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	multipathConfPath         = "/etc/multipath.conf"
+	multipathDefaultConfigDir = "/etc/multipath/conf.d"
+	tridentMultipathConfName  = "trident.conf"
+
+	// ontapLUNVendor, ontapLUNProduct, ontapLUNDevice and ontapLUNWWID stand for an ONTAP LUN when
+	// checking whether a multipath blacklist matches one
+	ontapLUNVendor  = "NETAPP"
+	ontapLUNProduct = "LUN C-Mode"
+	ontapLUNDevice  = "sdb"
+	ontapLUNWWID    = "3600a098038303634722b4d59646c4436"
+)
+
+// tridentMultipathConf is the configuration snippet Trident drops in so that multipath claims ONTAP
+// LUNs.  Snippets in the configuration directory are read after multipath.conf, so the snippet's
+// defaults take precedence, and blacklist exceptions take precedence over any blacklist.
+const tridentMultipathConf = `# Managed by Trident; changes will be overwritten.
+defaults {
+    find_multipaths no
+}
+blacklist_exceptions {
+    device {
+        vendor "NETAPP"
+        product "LUN.*"
+    }
+}
+`
+
+// multipathConfig is the part of a host's multipath configuration that decides whether multipath
+// claims ONTAP LUNs.
+type multipathConfig struct {
+	configDir      string
+	findMultipaths string
+	blacklist      multipathDeviceFilter
+	exceptions     multipathDeviceFilter
+}
+
+// multipathDeviceFilter is a blacklist, or the exceptions to it.
+type multipathDeviceFilter struct {
+	wwids    []string
+	devnodes []string
+	// devices are vendor and product patterns
+	devices [][2]string
+}
+
+// matchesONTAPLUN reports whether a filter matches an ONTAP LUN, and which entry does.
+func (f *multipathDeviceFilter) matchesONTAPLUN() (bool, string) {
+	for _, wwid := range f.wwids {
+		if multipathPatternMatches(wwid, ontapLUNWWID) {
+			return true, fmt.Sprintf("wwid %q", wwid)
+		}
+	}
+	for _, devnode := range f.devnodes {
+		if multipathPatternMatches(devnode, ontapLUNDevice) {
+			return true, fmt.Sprintf("devnode %q", devnode)
+		}
+	}
+	for _, device := range f.devices {
+		vendor, product := device[0], device[1]
+		if multipathPatternMatches(vendor, ontapLUNVendor) &&
+			(product == "" || multipathPatternMatches(product, ontapLUNProduct)) {
+			return true, fmt.Sprintf("device vendor %q product %q", vendor, product)
+		}
+	}
+	return false, ""
+}
+
+// multipathPatternMatches reports whether a multipath configuration regular expression matches a
+// value.  Multipath also accepts a lone "*" to match everything.
+func multipathPatternMatches(pattern, value string) bool {
+	if pattern == "*" {
+		return true
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return false
+	}
+	return re.MatchString(value)
+}
+
+// parseMultipathConfig adds the settings in a multipath configuration file to a configuration.
+// Settings in files parsed later replace those parsed earlier, as they do in multipath.
+func parseMultipathConfig(data string, config *multipathConfig) {
+
+	sections := make([]string, 0)
+	var vendor, product string
+
+	for _, line := range strings.Split(data, "\n") {
+		if i := strings.IndexAny(line, "#!"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		if fields[len(fields)-1] == "{" {
+			sections = append(sections, strings.Join(fields[:len(fields)-1], " "))
+			vendor, product = "", ""
+			continue
+		}
+		if fields[0] == "}" {
+			if len(sections) == 0 {
+				continue
+			}
+			if len(sections) == 2 && sections[1] == "device" {
+				switch sections[0] {
+				case "blacklist":
+					config.blacklist.devices = append(config.blacklist.devices, [2]string{vendor, product})
+				case "blacklist_exceptions":
+					config.exceptions.devices = append(config.exceptions.devices, [2]string{vendor, product})
+				}
+			}
+			sections = sections[:len(sections)-1]
+			continue
+		}
+		if len(fields) < 2 || len(sections) == 0 {
+			continue
+		}
+
+		key := fields[0]
+		value := strings.Trim(strings.Join(fields[1:], " "), `"`)
+		section := strings.Join(sections, "/")
+
+		switch section {
+		case "defaults":
+			switch key {
+			case "find_multipaths":
+				config.findMultipaths = value
+			case "config_dir":
+				config.configDir = value
+			}
+		case "blacklist", "blacklist_exceptions":
+			filter := &config.blacklist
+			if section == "blacklist_exceptions" {
+				filter = &config.exceptions
+			}
+			switch key {
+			case "wwid":
+				filter.wwids = append(filter.wwids, value)
+			case "devnode":
+				filter.devnodes = append(filter.devnodes, value)
+			}
+		case "blacklist/device", "blacklist_exceptions/device":
+			switch key {
+			case "vendor":
+				vendor = value
+			case "product":
+				product = value
+			}
+		}
+	}
+}
+
+// problems describes why a multipath configuration would keep multipath from claiming ONTAP LUNs.
+func (c *multipathConfig) problems() []string {
+
+	problems := make([]string, 0)
+
+	switch c.findMultipaths {
+	case "no", "off":
+	case "":
+		problems = append(problems, "find_multipaths is not set; it must be \"no\" so that multipath "+
+			"claims LUNs before a second path is found")
+	default:
+		problems = append(problems, fmt.Sprintf("find_multipaths is %q; it must be \"no\" so that multipath "+
+			"claims LUNs before a second path is found", c.findMultipaths))
+	}
+
+	if blacklisted, entry := c.blacklist.matchesONTAPLUN(); blacklisted {
+		if excepted, _ := c.exceptions.matchesONTAPLUN(); !excepted {
+			problems = append(problems, fmt.Sprintf("the blacklist entry %s matches ONTAP LUNs, and no "+
+				"blacklist exception matches them", entry))
+		}
+	}
+
+	return problems
+}
+
+// readMultipathConfig reads a host's multipath.conf, if it has one, and the snippets in its
+// configuration directory.
+func readMultipathConfig() (*multipathConfig, error) {
+
+	config := &multipathConfig{configDir: multipathDefaultConfigDir}
+
+	// Multipath reads its configuration directory even without a multipath.conf
+	data, err := ioutil.ReadFile(chrootPathPrefix + multipathConfPath)
+	if err == nil {
+		parseMultipathConfig(string(data), config)
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	snippets, _ := filepath.Glob(chrootPathPrefix + config.configDir + "/*.conf")
+	sort.Strings(snippets)
+	for _, snippet := range snippets {
+		if data, err = ioutil.ReadFile(snippet); err != nil {
+			log.WithFields(log.Fields{
+				"file":  snippet,
+				"error": err,
+			}).Warning("Could not read multipath configuration snippet.")
+			continue
+		}
+		parseMultipathConfig(string(data), config)
+	}
+
+	return config, nil
+}
+
+// isMultipathdRunning reports whether the multipath daemon is running on this host.
+func isMultipathdRunning() bool {
+	out, err := execCommandWithTimeout("multipathd", 10, "show", "daemon")
+	return err == nil && strings.HasPrefix(strings.TrimSpace(string(out)), "pid ")
+}
+
+// ValidateMultipath checks that the multipath daemon is running on this host, and that its
+// configuration lets multipath claim ONTAP LUNs.  If remediate is set and the configuration isn't
+// compatible, a Trident-managed configuration snippet is dropped in and the daemon reconfigured.
+func ValidateMultipath(remediate bool) *MultipathStatus {
+
+	log.WithField("remediate", remediate).Debug(">>>> multipath.ValidateMultipath")
+	defer log.Debug("<<<< multipath.ValidateMultipath")
+
+	status := validateMultipath()
+	if status.Compatible || !remediate {
+		return status
+	}
+
+	if !status.DaemonRunning {
+		log.Warning("Multipath configuration is incompatible with ONTAP LUNs, but multipathd is not " +
+			"running, so it can't be remediated.")
+		return status
+	}
+
+	if err := remediateMultipath(); err != nil {
+		log.WithField("error", err).Error("Could not remediate multipath configuration.")
+		status.Problems = append(status.Problems, fmt.Sprintf("could not remediate the configuration; %v", err))
+		return status
+	}
+
+	status = validateMultipath()
+	status.Remediated = true
+	return status
+}
+
+// validateMultipath returns the state of this host's multipath daemon and configuration.
+func validateMultipath() *MultipathStatus {
+
+	status := &MultipathStatus{DaemonRunning: isMultipathdRunning()}
+	problems := make([]string, 0)
+
+	if !status.DaemonRunning {
+		problems = append(problems, "multipathd is not running")
+	}
+
+	if config, err := readMultipathConfig(); err != nil {
+		problems = append(problems, fmt.Sprintf("could not read %s; %v", multipathConfPath, err))
+	} else {
+		status.FindMultipaths = config.findMultipaths
+		problems = append(problems, config.problems()...)
+	}
+
+	status.Compatible = len(problems) == 0
+	if !status.Compatible {
+		status.Problems = problems
+	}
+	return status
+}
+
+// remediateMultipath drops Trident's configuration snippet into the host's multipath configuration
+// directory and reconfigures the multipath daemon.  An existing multipath.conf is left alone.
+func remediateMultipath() error {
+
+	config, err := readMultipathConfig()
+	if err != nil {
+		return err
+	}
+
+	configDir := chrootPathPrefix + config.configDir
+	if err = os.MkdirAll(configDir, 0755); err != nil {
+		return err
+	}
+	snippet := filepath.Join(configDir, tridentMultipathConfName)
+	if err = ioutil.WriteFile(snippet, []byte(tridentMultipathConf), 0644); err != nil {
+		return err
+	}
+
+	if out, err := execCommandWithTimeout("multipathd", 30, "reconfigure"); err != nil {
+		return fmt.Errorf("could not reconfigure multipathd; %v; %s", err, strings.TrimSpace(string(out)))
+	}
+
+	log.WithField("file", snippet).Info("Dropped in multipath configuration for ONTAP LUNs.")
+	return nil
+}
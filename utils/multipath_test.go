@@ -0,0 +1,121 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMultipathConfigProblems(t *testing.T) {
+	tests := map[string]struct {
+		conf     string
+		problems int
+	}{
+		"Compatible": {
+			conf: `
+defaults {
+    user_friendly_names yes
+    find_multipaths no
+}`,
+			problems: 0,
+		},
+		"FindMultipathsUnset": {
+			conf:     "defaults {\n    user_friendly_names yes\n}\n",
+			problems: 1,
+		},
+		"FindMultipathsYes": {
+			conf:     "defaults {\n    find_multipaths \"yes\" # set by the installer\n}\n",
+			problems: 1,
+		},
+		"BlacklistAll": {
+			conf: `
+defaults {
+    find_multipaths no
+}
+blacklist {
+    wwid ".*"
+}`,
+			problems: 1,
+		},
+		"BlacklistAllWithException": {
+			conf: `
+defaults {
+    find_multipaths no
+}
+blacklist {
+    wwid ".*"
+}
+blacklist_exceptions {
+    device {
+        vendor "NETAPP"
+        product "LUN.*"
+    }
+}`,
+			problems: 0,
+		},
+		"BlacklistNetApp": {
+			conf: `
+defaults {
+    find_multipaths no
+}
+blacklist {
+    devnode "^(ram|loop|fd|md|dm-|sr|scd|st)[0-9]*"
+    device {
+        vendor "NETAPP"
+        product ".*"
+    }
+}`,
+			problems: 1,
+		},
+		"BlacklistOtherVendor": {
+			conf: `
+defaults {
+    find_multipaths no
+}
+blacklist {
+    device {
+        vendor "IBM"
+    }
+}`,
+			problems: 0,
+		},
+		"BlacklistSCSIDisks": {
+			conf: `
+defaults {
+    find_multipaths off
+}
+blacklist {
+    devnode "^sd[a-z]"
+}`,
+			problems: 1,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			config := &multipathConfig{configDir: multipathDefaultConfigDir}
+			parseMultipathConfig(test.conf, config)
+			assert.Len(t, config.problems(), test.problems)
+		})
+	}
+}
+
+func TestTridentMultipathConfRemediates(t *testing.T) {
+	config := &multipathConfig{configDir: multipathDefaultConfigDir}
+	parseMultipathConfig(`
+defaults {
+    find_multipaths yes
+    config_dir /etc/multipath/custom.d
+}
+blacklist {
+    wwid "*"
+}`, config)
+	assert.Len(t, config.problems(), 2)
+	assert.Equal(t, "/etc/multipath/custom.d", config.configDir)
+
+	parseMultipathConfig(tridentMultipathConf, config)
+	assert.Empty(t, config.problems())
+	assert.Equal(t, "no", config.findMultipaths)
+}
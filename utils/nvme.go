@@ -0,0 +1,422 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	nvmeTransportTCP      = "tcp"
+	nvmeTCPDiscoveryPort  = "8009"
+	nvmeTCPDataPort       = "4420"
+	nvmeCommandTimeout    = 30
+	nvmeDeviceTimeoutSecs = 90
+
+	// ANA states of the paths to a namespace, as reported by the kernel
+	NVMeANAOptimized      = "optimized"
+	NVMeANANonOptimized   = "non-optimized"
+	NVMeANAInaccessible   = "inaccessible"
+	NVMeANAPersistentLoss = "persistent-loss"
+	NVMeANAChange         = "change"
+
+	// nvmeControllerLive is the state of a connected controller
+	nvmeControllerLive = "live"
+)
+
+var (
+	// nvmeHeadDeviceRegex matches the multipath devices of namespaces, such as nvme0n1
+	nvmeHeadDeviceRegex = regexp.MustCompile(`^nvme(\d+)n(\d+)$`)
+	// nvmeControllerRegex matches controllers, such as nvme0
+	nvmeControllerRegex = regexp.MustCompile(`^nvme(\d+)$`)
+)
+
+// NVMeDiscoveryEntry is a subsystem port returned by NVMe discovery.
+type NVMeDiscoveryEntry struct {
+	Transport    string `json:"trtype"`
+	Address      string `json:"traddr"`
+	ServiceID    string `json:"trsvcid"`
+	SubsystemNQN string `json:"subnqn"`
+	SubType      string `json:"subtype"`
+}
+
+// NVMeSubsystem is an NVMe subsystem this host is connected to.
+type NVMeSubsystem struct {
+	// Name is the subsystem's name in sysfs, such as nvme-subsys0
+	Name        string
+	NQN         string
+	Controllers []NVMeController
+}
+
+// NVMeController is a connection from this host to a subsystem, through one of its ports.
+type NVMeController struct {
+	// Name is the controller's name in sysfs, such as nvme0
+	Name      string
+	Transport string
+	Address   string
+	ServiceID string
+	// State is the controller's state, such as live, connecting or resetting
+	State string
+}
+
+// NVMeNamespace is a namespace of a subsystem, which the kernel's native NVMe multipathing presents as
+// one block device with a path through each of the subsystem's controllers.
+type NVMeNamespace struct {
+	// Device is the namespace's multipath block device, such as /dev/nvme0n1
+	Device string
+	NSID   string
+	UUID   string
+	Paths  []NVMePath
+}
+
+// NVMePath is the path to a namespace through one controller.
+type NVMePath struct {
+	// Name is the path's name in sysfs, such as nvme0c1n1
+	Name            string
+	Controller      string
+	ControllerState string
+	// ANAState is the path's asymmetric namespace access state, such as optimized or inaccessible
+	ANAState string
+}
+
+// Accessible reports whether I/O to the namespace may use the path.
+func (p *NVMePath) Accessible() bool {
+	return p.ControllerState == nvmeControllerLive &&
+		(p.ANAState == NVMeANAOptimized || p.ANAState == NVMeANANonOptimized)
+}
+
+// IsAccessible reports whether the namespace has a path that I/O may use.
+func (n *NVMeNamespace) IsAccessible() bool {
+	for _, path := range n.Paths {
+		if path.Accessible() {
+			return true
+		}
+	}
+	return false
+}
+
+// IsDegraded reports whether a path to the namespace can't be used, or no path is optimized, so that
+// I/O is slower or less resilient than it should be.
+func (n *NVMeNamespace) IsDegraded() bool {
+	optimized := false
+	for _, path := range n.Paths {
+		if !path.Accessible() {
+			return true
+		}
+		if path.ANAState == NVMeANAOptimized {
+			optimized = true
+		}
+	}
+	return !optimized
+}
+
+// NVMeActiveOnHost reports whether nvme-cli is installed and the NVMe/TCP module is loaded on this host.
+func NVMeActiveOnHost() bool {
+	if _, err := execCommandWithTimeout("nvme", nvmeCommandTimeout, "version"); err != nil {
+		log.WithError(err).Debug("Could not run nvme; perhaps nvme-cli is not installed?")
+		return false
+	}
+	if !PathExists(chrootPathPrefix + "/sys/module/nvme_tcp") {
+		log.Debug("The nvme_tcp module is not loaded.")
+		return false
+	}
+	return true
+}
+
+// NVMeDiscover returns the subsystem ports that the NVMe/TCP discovery controller at an address reports.
+func NVMeDiscover(address string) ([]NVMeDiscoveryEntry, error) {
+
+	log.WithField("address", address).Debug(">>>> nvme.NVMeDiscover")
+	defer log.Debug("<<<< nvme.NVMeDiscover")
+
+	out, err := execCommandWithTimeout("nvme", nvmeCommandTimeout, "discover", "-t", nvmeTransportTCP,
+		"-a", address, "-s", nvmeTCPDiscoveryPort, "-o", "json")
+	if err != nil {
+		return nil, fmt.Errorf("could not discover NVMe subsystems at %s; %v; %s", address, err,
+			strings.TrimSpace(string(out)))
+	}
+	return parseNVMeDiscovery(out)
+}
+
+// parseNVMeDiscovery parses the JSON output of nvme discover, leaving out referrals to other
+// discovery controllers.
+func parseNVMeDiscovery(out []byte) ([]NVMeDiscoveryEntry, error) {
+
+	var discovery struct {
+		Records []NVMeDiscoveryEntry `json:"records"`
+	}
+	if err := json.Unmarshal(out, &discovery); err != nil {
+		return nil, fmt.Errorf("could not parse NVMe discovery; %v", err)
+	}
+
+	entries := make([]NVMeDiscoveryEntry, 0, len(discovery.Records))
+	for _, record := range discovery.Records {
+		if strings.Contains(record.SubType, "discovery") {
+			continue
+		}
+		entries = append(entries, record)
+	}
+	return entries, nil
+}
+
+// NVMeConnect connects this host to a subsystem through each of its portals that it isn't already
+// connected through.  The controllers reconnect indefinitely after losing their connection, so a path
+// that fails over comes back by itself.  An error is returned only if the subsystem can't be reached
+// through any portal.
+func NVMeConnect(subsystemNQN string, portals []string) error {
+
+	fields := log.Fields{"subsystemNQN": subsystemNQN, "portals": portals}
+	log.WithFields(fields).Debug(">>>> nvme.NVMeConnect")
+	defer log.WithFields(fields).Debug("<<<< nvme.NVMeConnect")
+
+	connected := make(map[string]bool)
+	subsystem, err := GetNVMeSubsystem(subsystemNQN)
+	if err != nil && !IsNotFoundError(err) {
+		return err
+	}
+	if subsystem != nil {
+		for _, controller := range subsystem.Controllers {
+			connected[controller.Address] = true
+		}
+	}
+
+	var lastErr error
+	reachable := 0
+	for _, portal := range portals {
+		address := strings.Trim(ISCSIPortalIP(portal), "[]")
+		if connected[address] {
+			reachable++
+			continue
+		}
+
+		out, err := execCommandWithTimeout("nvme", nvmeCommandTimeout, "connect", "-t", nvmeTransportTCP,
+			"-n", subsystemNQN, "-a", address, "-s", nvmeTCPDataPort, "-l", "-1")
+		if err != nil && !strings.Contains(string(out), "already connected") {
+			lastErr = fmt.Errorf("could not connect to NVMe subsystem %s at %s; %v; %s", subsystemNQN,
+				address, err, strings.TrimSpace(string(out)))
+			log.WithFields(fields).WithField("address", address).WithError(lastErr).Warning(
+				"Could not connect to NVMe portal.")
+			continue
+		}
+		reachable++
+	}
+
+	if reachable == 0 {
+		if lastErr == nil {
+			lastErr = fmt.Errorf("no portals given for NVMe subsystem %s", subsystemNQN)
+		}
+		return lastErr
+	}
+	return nil
+}
+
+// NVMeDisconnect disconnects this host from a subsystem, through all of its controllers.
+func NVMeDisconnect(subsystemNQN string) error {
+
+	log.WithField("subsystemNQN", subsystemNQN).Debug(">>>> nvme.NVMeDisconnect")
+	defer log.Debug("<<<< nvme.NVMeDisconnect")
+
+	if _, err := GetNVMeSubsystem(subsystemNQN); IsNotFoundError(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	out, err := execCommandWithTimeout("nvme", nvmeCommandTimeout, "disconnect", "-n", subsystemNQN)
+	if err != nil {
+		return fmt.Errorf("could not disconnect from NVMe subsystem %s; %v; %s", subsystemNQN, err,
+			strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// NVMeRescanNamespaces asks the controllers of a subsystem to rescan for namespaces, so that a namespace
+// mapped to this host after it connected to the subsystem is found.
+func NVMeRescanNamespaces(subsystem *NVMeSubsystem) {
+	for _, controller := range subsystem.Controllers {
+		if controller.State != nvmeControllerLive {
+			continue
+		}
+		if out, err := execCommandWithTimeout("nvme", nvmeCommandTimeout, "ns-rescan",
+			"/dev/"+controller.Name); err != nil {
+			log.WithFields(log.Fields{
+				"controller": controller.Name,
+				"output":     strings.TrimSpace(string(out)),
+				"error":      err,
+			}).Warning("Could not rescan NVMe controller for namespaces.")
+		}
+	}
+}
+
+// GetNVMeSubsystem returns the subsystem with an NQN that this host is connected to, or a NotFoundError
+// if there is none.
+func GetNVMeSubsystem(subsystemNQN string) (*NVMeSubsystem, error) {
+
+	subsystemDirs, err := filepath.Glob(chrootPathPrefix + "/sys/class/nvme-subsystem/nvme-subsys*")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, subsystemDir := range subsystemDirs {
+		if readSysfsValue(filepath.Join(subsystemDir, "subsysnqn")) != subsystemNQN {
+			continue
+		}
+
+		subsystem := &NVMeSubsystem{Name: filepath.Base(subsystemDir), NQN: subsystemNQN}
+		entries, err := ioutil.ReadDir(subsystemDir)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if !nvmeControllerRegex.MatchString(entry.Name()) {
+				continue
+			}
+			subsystem.Controllers = append(subsystem.Controllers, getNVMeController(entry.Name()))
+		}
+		return subsystem, nil
+	}
+
+	return nil, NotFoundError(fmt.Sprintf("NVMe subsystem %s not found", subsystemNQN))
+}
+
+// getNVMeController returns the transport, address and state of a controller.
+func getNVMeController(name string) NVMeController {
+
+	controllerDir := chrootPathPrefix + "/sys/class/nvme/" + name
+	controller := NVMeController{
+		Name:      name,
+		Transport: readSysfsValue(controllerDir + "/transport"),
+		State:     readSysfsValue(controllerDir + "/state"),
+	}
+	controller.Address, controller.ServiceID = parseNVMeControllerAddress(readSysfsValue(controllerDir + "/address"))
+	return controller
+}
+
+// parseNVMeControllerAddress returns the transport address and service ID from a controller's address,
+// such as "traddr=10.0.0.1,trsvcid=4420".  Older kernels separate the fields with spaces.
+func parseNVMeControllerAddress(address string) (traddr, trsvcid string) {
+	for _, field := range strings.FieldsFunc(address, func(r rune) bool { return r == ',' || r == ' ' }) {
+		parts := strings.SplitN(field, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		switch parts[0] {
+		case "traddr":
+			traddr = parts[1]
+		case "trsvcid":
+			trsvcid = parts[1]
+		}
+	}
+	return traddr, trsvcid
+}
+
+// GetNVMeNamespace returns the namespace of a subsystem with a UUID, along with the ANA state of each
+// of its paths, or a NotFoundError if the host has no device for it.  Native NVMe multipathing must be
+// enabled, as it is by default, so that each namespace has a multipath device.
+func GetNVMeNamespace(subsystem *NVMeSubsystem, namespaceUUID string) (*NVMeNamespace, error) {
+
+	subsystemDir := chrootPathPrefix + "/sys/class/nvme-subsystem/" + subsystem.Name
+	entries, err := ioutil.ReadDir(subsystemDir)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		match := nvmeHeadDeviceRegex.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+		namespaceDir := filepath.Join(subsystemDir, entry.Name())
+		if !strings.EqualFold(readSysfsValue(namespaceDir+"/uuid"), namespaceUUID) {
+			continue
+		}
+
+		namespace := &NVMeNamespace{
+			Device: "/dev/" + entry.Name(),
+			NSID:   readSysfsValue(namespaceDir + "/nsid"),
+			UUID:   namespaceUUID,
+		}
+
+		// Each controller's path to the namespace is named for the namespace's device and the controller
+		for _, controller := range subsystem.Controllers {
+			controllerNumber := nvmeControllerRegex.FindStringSubmatch(controller.Name)[1]
+			pathName := fmt.Sprintf("nvme%sc%sn%s", match[1], controllerNumber, match[2])
+			pathDir := chrootPathPrefix + "/sys/class/nvme/" + controller.Name + "/" + pathName
+			if !PathExists(pathDir) {
+				continue
+			}
+			namespace.Paths = append(namespace.Paths, NVMePath{
+				Name:            pathName,
+				Controller:      controller.Name,
+				ControllerState: controller.State,
+				ANAState:        readSysfsValue(pathDir + "/ana_state"),
+			})
+		}
+		sort.Slice(namespace.Paths, func(i, j int) bool { return namespace.Paths[i].Name < namespace.Paths[j].Name })
+
+		return namespace, nil
+	}
+
+	return nil, NotFoundError(fmt.Sprintf("NVMe namespace %s of subsystem %s not found", namespaceUUID,
+		subsystem.NQN))
+}
+
+// WaitForNVMeNamespace waits for this host to find a namespace of a subsystem it is connected to, and
+// for the namespace to have a path that I/O may use, rescanning the subsystem's controllers while it
+// waits.  The namespace is returned even if some of its paths can't be used.
+func WaitForNVMeNamespace(subsystemNQN, namespaceUUID string) (*NVMeNamespace, error) {
+
+	fields := log.Fields{"subsystemNQN": subsystemNQN, "namespaceUUID": namespaceUUID}
+	log.WithFields(fields).Debug(">>>> nvme.WaitForNVMeNamespace")
+	defer log.WithFields(fields).Debug("<<<< nvme.WaitForNVMeNamespace")
+
+	maxDuration := nvmeDeviceTimeoutSecs * time.Second
+	var namespace *NVMeNamespace
+
+	checkNamespace := func() error {
+		subsystem, err := GetNVMeSubsystem(subsystemNQN)
+		if err != nil {
+			return err
+		}
+		if namespace, err = GetNVMeNamespace(subsystem, namespaceUUID); err != nil {
+			NVMeRescanNamespaces(subsystem)
+			return err
+		}
+		if !namespace.IsAccessible() {
+			return errors.New("no accessible path to the namespace")
+		}
+		return nil
+	}
+
+	namespaceNotify := func(err error, duration time.Duration) {
+		log.WithFields(fields).WithField("increment", duration).WithError(err).Debug(
+			"NVMe namespace not yet usable, waiting.")
+	}
+
+	namespaceBackoff := backoff.NewExponentialBackOff()
+	namespaceBackoff.InitialInterval = 1 * time.Second
+	namespaceBackoff.Multiplier = 1.414 // approx sqrt(2)
+	namespaceBackoff.RandomizationFactor = 0.1
+	namespaceBackoff.MaxElapsedTime = maxDuration
+
+	if err := backoff.RetryNotify(checkNamespace, namespaceBackoff, namespaceNotify); err != nil {
+		return nil, fmt.Errorf("could not find a usable device for NVMe namespace %s after %3.2f seconds; %v",
+			namespaceUUID, maxDuration.Seconds(), err)
+	}
+
+	if namespace.IsDegraded() {
+		log.WithFields(fields).WithField("paths", namespace.Paths).Warning("NVMe namespace paths are degraded.")
+	}
+	return namespace, nil
+}
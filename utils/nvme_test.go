@@ -0,0 +1,123 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package utils
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseNVMeDiscovery(t *testing.T) {
+
+	out := []byte(`{
+  "device": "nvme0",
+  "records": [
+    {"trtype": "tcp", "adrfam": "ipv4", "subtype": "current discovery subsystem", "trsvcid": "8009",
+     "subnqn": "nqn.2014-08.org.nvmexpress.discovery", "traddr": "10.0.0.1"},
+    {"trtype": "tcp", "adrfam": "ipv4", "subtype": "nvme subsystem", "trsvcid": "4420",
+     "subnqn": "nqn.1992-08.com.netapp:sn.1:subsystem.trident", "traddr": "10.0.0.1"},
+    {"trtype": "tcp", "adrfam": "ipv4", "subtype": "nvme subsystem", "trsvcid": "4420",
+     "subnqn": "nqn.1992-08.com.netapp:sn.1:subsystem.trident", "traddr": "10.0.0.2"}
+  ]
+}`)
+
+	entries, err := parseNVMeDiscovery(out)
+	assert.NoError(t, err)
+	assert.Equal(t, []NVMeDiscoveryEntry{
+		{"tcp", "10.0.0.1", "4420", "nqn.1992-08.com.netapp:sn.1:subsystem.trident", "nvme subsystem"},
+		{"tcp", "10.0.0.2", "4420", "nqn.1992-08.com.netapp:sn.1:subsystem.trident", "nvme subsystem"},
+	}, entries)
+
+	_, err = parseNVMeDiscovery([]byte("Failed to connect"))
+	assert.Error(t, err)
+}
+
+func TestParseNVMeControllerAddress(t *testing.T) {
+	for address, expected := range map[string][2]string{
+		"traddr=10.0.0.1,trsvcid=4420":           {"10.0.0.1", "4420"},
+		"traddr=10.0.0.1 trsvcid=4420":           {"10.0.0.1", "4420"},
+		"traddr=fd20::1,trsvcid=4420,src_addr=x": {"fd20::1", "4420"},
+		"":                                       {"", ""},
+	} {
+		traddr, trsvcid := parseNVMeControllerAddress(address)
+		assert.Equal(t, expected, [2]string{traddr, trsvcid}, address)
+	}
+}
+
+func TestGetNVMeNamespace(t *testing.T) {
+
+	sysRoot, err := ioutil.TempDir("", "sysfs")
+	assert.NoError(t, err)
+	defer os.RemoveAll(sysRoot)
+
+	savedPrefix := chrootPathPrefix
+	chrootPathPrefix = sysRoot
+	defer func() { chrootPathPrefix = savedPrefix }()
+
+	writeValue := func(value string, elem ...string) {
+		path := filepath.Join(append([]string{sysRoot, "sys", "class"}, elem...)...)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.NoError(t, ioutil.WriteFile(path, []byte(value+"\n"), 0644))
+	}
+
+	const (
+		nqn  = "nqn.1992-08.com.netapp:sn.1:subsystem.trident"
+		uuid = "1b1f1e33-7ab4-4f5c-9f8d-0c1c2a7b5e01"
+	)
+
+	_, err = GetNVMeSubsystem(nqn)
+	assert.True(t, IsNotFoundError(err), "no subsystems")
+
+	// Subsystem 0 is connected through controllers 0 and 1, and has namespace 1
+	writeValue(nqn, "nvme-subsystem", "nvme-subsys0", "subsysnqn")
+	writeValue("", "nvme-subsystem", "nvme-subsys0", "nvme0", "dev")
+	writeValue("", "nvme-subsystem", "nvme-subsys0", "nvme1", "dev")
+	writeValue(uuid, "nvme-subsystem", "nvme-subsys0", "nvme0n1", "uuid")
+	writeValue("1", "nvme-subsystem", "nvme-subsys0", "nvme0n1", "nsid")
+	writeValue("tcp", "nvme", "nvme0", "transport")
+	writeValue("traddr=10.0.0.1,trsvcid=4420", "nvme", "nvme0", "address")
+	writeValue("live", "nvme", "nvme0", "state")
+	writeValue("optimized", "nvme", "nvme0", "nvme0c0n1", "ana_state")
+	writeValue("tcp", "nvme", "nvme1", "transport")
+	writeValue("traddr=10.0.0.2,trsvcid=4420", "nvme", "nvme1", "address")
+	writeValue("live", "nvme", "nvme1", "state")
+	writeValue("non-optimized", "nvme", "nvme1", "nvme0c1n1", "ana_state")
+
+	subsystem, err := GetNVMeSubsystem(nqn)
+	assert.NoError(t, err)
+	assert.Equal(t, &NVMeSubsystem{
+		Name: "nvme-subsys0",
+		NQN:  nqn,
+		Controllers: []NVMeController{
+			{Name: "nvme0", Transport: "tcp", Address: "10.0.0.1", ServiceID: "4420", State: "live"},
+			{Name: "nvme1", Transport: "tcp", Address: "10.0.0.2", ServiceID: "4420", State: "live"},
+		},
+	}, subsystem)
+
+	_, err = GetNVMeNamespace(subsystem, "00000000-0000-0000-0000-000000000000")
+	assert.True(t, IsNotFoundError(err), "unknown namespace")
+
+	namespace, err := GetNVMeNamespace(subsystem, uuid)
+	assert.NoError(t, err)
+	assert.Equal(t, "/dev/nvme0n1", namespace.Device)
+	assert.Equal(t, "1", namespace.NSID)
+	assert.Equal(t, []NVMePath{
+		{Name: "nvme0c0n1", Controller: "nvme0", ControllerState: "live", ANAState: NVMeANAOptimized},
+		{Name: "nvme0c1n1", Controller: "nvme1", ControllerState: "live", ANAState: NVMeANANonOptimized},
+	}, namespace.Paths)
+	assert.True(t, namespace.IsAccessible())
+	assert.False(t, namespace.IsDegraded())
+
+	// The optimized path's controller loses its connection
+	namespace.Paths[0].ControllerState = "connecting"
+	assert.True(t, namespace.IsAccessible())
+	assert.True(t, namespace.IsDegraded())
+
+	// The remaining path becomes inaccessible
+	namespace.Paths[1].ANAState = NVMeANAInaccessible
+	assert.False(t, namespace.IsAccessible())
+}
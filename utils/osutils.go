@@ -5,6 +5,7 @@ package utils
 import (
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net"
 	"os"
@@ -27,8 +28,14 @@ const (
 	iSCSIDeviceDiscoveryTimeoutSecs     = 90
 	multipathDeviceDiscoveryTimeoutSecs = 90
 	resourceDeletionTimeoutSecs         = 40
+	deviceRemovalTimeoutSecs            = 10
+	multipathFlushAttempts              = 3
+	deviceResizeTimeoutSecs             = 30
 	fsRaw                               = "raw"
 	temporaryMountDir                   = "/tmp_mnt"
+
+	// tridentISCSIInterfacePrefix names the iSCSI interfaces Trident manages for network interfaces
+	tridentISCSIInterfacePrefix = "trident-"
 )
 
 var xtermControlRegex = regexp.MustCompile(`\x1B\[[0-9;]*[a-zA-Z]`)
@@ -139,7 +146,7 @@ func AttachISCSIVolume(name, mountpoint string, publishInfo *VolumePublishInfo)
 				}
 			}
 		} else {
-			err = EnsureISCSISessions(portalIps)
+			err = EnsureISCSISessions(portalIps, iscsiInterface)
 			if err != nil {
 				return fmt.Errorf("iSCSI session error: %v", err)
 			}
@@ -199,7 +206,7 @@ func AttachISCSIVolume(name, mountpoint string, publishInfo *VolumePublishInfo)
 	existingFstype := deviceInfo.Filesystem
 	if existingFstype == "" {
 		log.WithFields(log.Fields{"volume": name, "fstype": fstype}).Debug("Formatting LUN.")
-		err := formatVolume(devicePath, fstype)
+		err := formatVolume(devicePath, fstype, publishInfo.FormatOptions)
 		if err != nil {
 			return fmt.Errorf("error formatting LUN %s, device %s: %v", name, deviceToUse, err)
 		}
@@ -292,6 +299,16 @@ func GetInitiatorIqns() ([]string, error) {
 	return iqns, nil
 }
 
+// GetHostNQN returns the NVMe host NQN of this host, or an empty string if NVMe isn't installed.
+func GetHostNQN() string {
+	nqn, err := ioutil.ReadFile(chrootPathPrefix + "/etc/nvme/hostnqn")
+	if err != nil {
+		log.WithField("error", err).Debug("Could not read hostnqn; perhaps NVMe is not installed?")
+		return ""
+	}
+	return strings.TrimSpace(string(nqn))
+}
+
 // GetIPAddresses returns the sorted list of Global Unicast IP addresses available to Trident
 func GetIPAddresses() ([]string, error) {
 	ipAddrs := make([]string, 0)
@@ -859,6 +876,120 @@ func findDevicesForMultipathDevice(device string) []string {
 	return devices
 }
 
+// getDeviceMapperName returns the name of a devicemapper device like dm-0, or an empty string if
+// the device isn't a devicemapper device.
+func getDeviceMapperName(device string) string {
+	name, err := ioutil.ReadFile(chrootPathPrefix + "/sys/block/" + device + "/dm/name")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(name))
+}
+
+// findLUKSDeviceForDevice finds the LUKS devicemapper holder of a device like sdx or dm-0, and returns
+// its mapping name, or an empty string if the device isn't LUKS encrypted.
+func findLUKSDeviceForDevice(device string) string {
+
+	log.WithField("device", device).Debug(">>>> osutils.findLUKSDeviceForDevice")
+	defer log.WithField("device", device).Debug("<<<< osutils.findLUKSDeviceForDevice")
+
+	holdersDir := chrootPathPrefix + "/sys/block/" + device + "/holders"
+	if dirs, err := ioutil.ReadDir(holdersDir); err == nil {
+		for _, f := range dirs {
+			name := f.Name()
+			if !strings.HasPrefix(name, "dm-") {
+				continue
+			}
+			uuid, err := ioutil.ReadFile(chrootPathPrefix + "/sys/block/" + name + "/dm/uuid")
+			if err == nil && strings.HasPrefix(string(uuid), "CRYPT-LUKS") {
+				return getDeviceMapperName(name)
+			}
+		}
+	}
+
+	return ""
+}
+
+// getLUKSDevicePath returns the path of the LUKS mapping opened on a device like /dev/dm-0, or the
+// device path itself if the device isn't LUKS encrypted.
+func getLUKSDevicePath(devicePath string) string {
+	device := devicePath
+	if resolved, err := filepath.EvalSymlinks(chrootPathPrefix + devicePath); err == nil {
+		device = resolved
+	}
+	if luksDevice := findLUKSDeviceForDevice(filepath.Base(device)); luksDevice != "" {
+		return "/dev/mapper/" + luksDevice
+	}
+	return devicePath
+}
+
+// GetNodeHealth returns the state of this host's iSCSI sessions and of the paths to its multipath
+// devices, so that the controller can report dead paths before volumes on them fail.
+func GetNodeHealth() *NodeHealth {
+
+	log.Debug(">>>> osutils.GetNodeHealth")
+	defer log.Debug("<<<< osutils.GetNodeHealth")
+
+	health := &NodeHealth{}
+
+	sessionDirs, _ := filepath.Glob(chrootPathPrefix + "/sys/class/iscsi_session/session*")
+	for _, sessionDir := range sessionDirs {
+		health.ISCSISessions++
+		if state := readSysfsValue(sessionDir + "/state"); state != "LOGGED_IN" {
+			health.ISCSISessionsDown++
+			health.Problems = append(health.Problems, fmt.Sprintf("iSCSI %s to %s is %s",
+				filepath.Base(sessionDir), readSysfsValue(sessionDir+"/targetname"), state))
+		}
+	}
+
+	dmDirs, _ := filepath.Glob(chrootPathPrefix + "/sys/block/dm-*")
+	for _, dmDir := range dmDirs {
+		if !strings.HasPrefix(readSysfsValue(dmDir+"/dm/uuid"), "mpath-") {
+			continue
+		}
+		health.MultipathDevices++
+		device := filepath.Base(dmDir)
+		slaves, _ := ioutil.ReadDir(dmDir + "/slaves")
+		for _, slave := range slaves {
+			path := slave.Name()
+			if state := readSysfsValue(chrootPathPrefix + "/sys/block/" + path + "/device/state"); state != "running" {
+				health.FailedPaths++
+				health.Problems = append(health.Problems, fmt.Sprintf("path %s of multipath device %s (%s) is %s",
+					path, getDeviceMapperName(device), device, state))
+			}
+		}
+	}
+
+	return health
+}
+
+// readSysfsValue returns the trimmed contents of a sysfs attribute, or "unknown" if it can't be read.
+func readSysfsValue(path string) string {
+	value, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "unknown"
+	}
+	return strings.TrimSpace(string(value))
+}
+
+// resizeLUKSDevice grows an open LUKS mapping to fill its underlying device.
+func resizeLUKSDevice(luksDevice string) error {
+	fields := log.Fields{"luksDevice": luksDevice}
+	log.WithFields(fields).Debug(">>>> osutils.resizeLUKSDevice")
+	defer log.WithFields(fields).Debug("<<<< osutils.resizeLUKSDevice")
+
+	if _, err := execCommandWithTimeout("cryptsetup", 30, "resize", luksDevice); err != nil {
+		log.WithFields(log.Fields{
+			"device": luksDevice,
+			"error":  err,
+		}).Error("Failed to resize LUKS device.")
+		return fmt.Errorf("failed to resize LUKS device %s: %s", luksDevice, err)
+	}
+
+	log.WithFields(fields).Debug("LUKS device resized.")
+	return nil
+}
+
 // PrepareDeviceForRemoval informs Linux that a device will be removed.
 func PrepareDeviceForRemoval(lunID int, iSCSINodeName string) {
 
@@ -879,7 +1010,62 @@ func PrepareDeviceForRemoval(lunID int, iSCSINodeName string) {
 		return
 	}
 
-	removeSCSIDevice(deviceInfo)
+	if err := removeSCSIDevice(deviceInfo); err != nil {
+		log.WithFields(fields).WithError(err).Warning("Could not remove SCSI device.")
+	}
+}
+
+// RemoveISCSIDevice removes a LUN's multipath device and SCSI devices from the host, and returns an
+// error if any of them remain, so that the removal may be retried.  The LUN's sessions may already
+// be gone after an earlier attempt failed, so devicePath, the device the LUN was staged on, is used
+// to find a multipath device left behind without paths.
+func RemoveISCSIDevice(lunID int, iSCSINodeName, devicePath string) error {
+
+	fields := log.Fields{
+		"lunID":         lunID,
+		"iSCSINodeName": iSCSINodeName,
+		"devicePath":    devicePath,
+	}
+	log.WithFields(fields).Debug(">>>> osutils.RemoveISCSIDevice")
+	defer log.WithFields(fields).Debug("<<<< osutils.RemoveISCSIDevice")
+
+	deviceInfo, err := getDeviceInfoForLUN(lunID, iSCSINodeName, false)
+	if err != nil {
+		multipathDevice := findStaleMultipathDevice(devicePath)
+		if multipathDevice == "" {
+			log.WithFields(fields).WithError(err).Debug("No devices found for LUN, nothing to remove.")
+			return nil
+		}
+		log.WithFields(fields).WithField("multipathDevice", multipathDevice).Warning(
+			"Found multipath device without paths.")
+		deviceInfo = &ScsiDeviceInfo{
+			LUN:             strconv.Itoa(lunID),
+			MultipathDevice: multipathDevice,
+			Devices:         []string{},
+			IQN:             iSCSINodeName,
+		}
+	}
+
+	return removeSCSIDevice(deviceInfo)
+}
+
+// findStaleMultipathDevice returns the name of the multipath device at a path, such as /dev/dm-0, if
+// it is left with no paths.
+func findStaleMultipathDevice(devicePath string) string {
+
+	if !strings.HasPrefix(devicePath, "/dev/dm-") {
+		return ""
+	}
+	device := strings.TrimPrefix(devicePath, "/dev/")
+
+	uuid, err := ioutil.ReadFile(chrootPathPrefix + "/sys/block/" + device + "/dm/uuid")
+	if err != nil || !strings.HasPrefix(strings.TrimSpace(string(uuid)), "mpath-") {
+		return ""
+	}
+	if len(findDevicesForMultipathDevice(device)) != 0 {
+		return ""
+	}
+	return device
 }
 
 // PrepareDeviceAtMountPathForRemoval informs Linux that a device will be removed.
@@ -900,17 +1086,22 @@ func PrepareDeviceAtMountPathForRemoval(mountpoint string, unmount bool) error {
 		}
 	}
 
-	removeSCSIDevice(deviceInfo)
-	return nil
+	return removeSCSIDevice(deviceInfo)
 }
 
-// removeSCSIDevice informs Linux that a device will be removed.  The deviceInfo provided only needs
-// the devices and multipathDevice fields set.
-func removeSCSIDevice(deviceInfo *ScsiDeviceInfo) {
+// removeSCSIDevice informs Linux that a device will be removed, and returns an error if any of its
+// devices remain.  The deviceInfo provided only needs the devices and multipathDevice fields set.
+// The SCSI devices are only deleted once the multipath device is gone, since deleting the paths
+// under a map that is still open leaves the map behind with no paths.
+func removeSCSIDevice(deviceInfo *ScsiDeviceInfo) error {
 
 	listAllISCSIDevices()
+	flushDevice(deviceInfo)
+
 	// Flush multipath device
-	multipathFlushDevice(deviceInfo)
+	if err := multipathFlushDevice(deviceInfo); err != nil {
+		return err
+	}
 
 	// Remove device
 	removeDevice(deviceInfo)
@@ -918,6 +1109,8 @@ func removeSCSIDevice(deviceInfo *ScsiDeviceInfo) {
 	// Give the host a chance to fully process the removal
 	time.Sleep(time.Second)
 	listAllISCSIDevices()
+
+	return waitForDevicesRemoval(deviceInfo.Devices)
 }
 
 // ISCSISupported returns true if iscsiadm is installed and in the PATH.
@@ -941,13 +1134,14 @@ type ISCSIDiscoveryInfo struct {
 	TargetName string
 }
 
-// iSCSIDiscovery uses the 'iscsiadm' command to perform discovery.
-func iSCSIDiscovery(portal string) ([]ISCSIDiscoveryInfo, error) {
+// iSCSIDiscovery uses the 'iscsiadm' command to perform discovery through an iSCSI interface.
+func iSCSIDiscovery(portal, iface string) ([]ISCSIDiscoveryInfo, error) {
 
-	log.WithField("portal", portal).Debug(">>>> osutils.iSCSIDiscovery")
+	log.WithFields(log.Fields{"portal": portal, "iface": iface}).Debug(">>>> osutils.iSCSIDiscovery")
 	defer log.Debug("<<<< osutils.iSCSIDiscovery")
 
-	out, err := execIscsiadmCommand("-m", "discovery", "-t", "sendtargets", "-p", portal)
+	args := append([]string{"-m", "discovery", "-t", "sendtargets", "-p", portal}, iscsiInterfaceArgs(iface)...)
+	out, err := execIscsiadmCommand(args...)
 	if err != nil {
 		return nil, err
 	}
@@ -1157,7 +1351,8 @@ func mountFilesystemForResize(devicePath string, stagedTargetPath string, mountO
 
 // ExpandISCSIFilesystem will expand the filesystem of an already expanded volume.
 func ExpandISCSIFilesystem(publishInfo *VolumePublishInfo, stagedTargetPath string) (int64, error) {
-	devicePath := publishInfo.DevicePath
+	// A LUKS-encrypted LUN holds its filesystem in the LUKS mapping
+	devicePath := getLUKSDevicePath(publishInfo.DevicePath)
 	logFields := log.Fields{
 		"devicePath":       devicePath,
 		"stagedTargetPath": stagedTargetPath,
@@ -1167,7 +1362,7 @@ func ExpandISCSIFilesystem(publishInfo *VolumePublishInfo, stagedTargetPath stri
 	log.WithFields(logFields).Debug(">>>> osutils.ExpandISCSIFilesystem")
 	defer log.WithFields(logFields).Debug("<<<< osutils.ExpandISCSIFilesystem")
 
-	tmpMountPoint, err := mountFilesystemForResize(publishInfo.DevicePath, stagedTargetPath, publishInfo.MountOptions)
+	tmpMountPoint, err := mountFilesystemForResize(devicePath, stagedTargetPath, publishInfo.MountOptions)
 	if err != nil {
 		return 0, err
 	}
@@ -1267,6 +1462,107 @@ func iSCSISessionExistsToTargetIQN(targetIQN string) (bool, error) {
 	return false, nil
 }
 
+// ISCSISessionState is the state of a session between this host and a portal of an iSCSI target.
+type ISCSISessionState struct {
+	SID        string
+	TargetName string
+	PortalIP   string
+	// State is the session's state in sysfs, such as LOGGED_IN or FAILED
+	State string
+}
+
+// GetISCSISessionStates returns the state of each of this host's iSCSI sessions.
+func GetISCSISessionStates() ([]ISCSISessionState, error) {
+
+	log.Debug(">>>> osutils.GetISCSISessionStates")
+	defer log.Debug("<<<< osutils.GetISCSISessionStates")
+
+	sessionInfo, err := getISCSISessionInfo()
+	if err != nil {
+		return nil, err
+	}
+
+	states := make([]ISCSISessionState, 0, len(sessionInfo))
+	for _, e := range sessionInfo {
+		states = append(states, ISCSISessionState{
+			SID:        e.SID,
+			TargetName: e.TargetName,
+			PortalIP:   e.PortalIP,
+			State:      readSysfsValue(chrootPathPrefix + "/sys/class/iscsi_session/session" + e.SID + "/state"),
+		})
+	}
+
+	return states, nil
+}
+
+// ISCSIPortalIP returns the address of an iSCSI portal without its port, as iscsiadm reports it.
+func ISCSIPortalIP(portal string) string {
+	if IPv6Check(portal) {
+		return strings.Split(portal, "]")[0] + "]"
+	}
+	return strings.Split(portal, ":")[0]
+}
+
+// LoginISCSIPortal logs in to one portal of the target a volume was published from, with the
+// volume's CHAP credentials if it uses CHAP.
+func LoginISCSIPortal(publishInfo *VolumePublishInfo, portal string) error {
+
+	fields := log.Fields{"targetIQN": publishInfo.IscsiTargetIQN, "portal": portal}
+	log.WithFields(fields).Debug(">>>> osutils.LoginISCSIPortal")
+	defer log.WithFields(fields).Debug("<<<< osutils.LoginISCSIPortal")
+
+	if publishInfo.UseCHAP {
+		iscsiInterface := publishInfo.IscsiInterface
+		if iscsiInterface == "" {
+			iscsiInterface = "default"
+		}
+		return loginWithChap(publishInfo.IscsiTargetIQN, portal, publishInfo.IscsiUsername,
+			publishInfo.IscsiInitiatorSecret, publishInfo.IscsiTargetUsername, publishInfo.IscsiTargetSecret,
+			iscsiInterface, false)
+	}
+	return loginISCSITarget(publishInfo.IscsiTargetIQN, ISCSIPortalIP(portal), publishInfo.IscsiInterface)
+}
+
+// ISCSIScanSessionLUNs scans an iSCSI session for LUNs, so that paths to LUNs that were lost while
+// the session was down are found again.  Only the listed LUNs are scanned, since Trident's targets
+// don't scan automatically.
+func ISCSIScanSessionLUNs(targetIQN, sid string, lunIDs []int) error {
+
+	fields := log.Fields{"targetIQN": targetIQN, "sid": sid, "lunIDs": lunIDs}
+	log.WithFields(fields).Debug(">>>> osutils.ISCSIScanSessionLUNs")
+	defer log.WithFields(fields).Debug("<<<< osutils.ISCSIScanSessionLUNs")
+
+	sessionNumber, err := strconv.Atoi(sid)
+	if err != nil {
+		return fmt.Errorf("invalid iSCSI session ID %s; %v", sid, err)
+	}
+
+	hosts := make([]int, 0)
+	for hostNumber, session := range GetISCSIHostSessionMapForTarget(targetIQN) {
+		if session == sessionNumber {
+			hosts = append(hosts, hostNumber)
+		}
+	}
+	if len(hosts) == 0 {
+		return fmt.Errorf("no iSCSI host found for session %s", sid)
+	}
+
+	for _, lunID := range lunIDs {
+		if err = iSCSIScanTargetLUN(lunID, hosts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetBlockDeviceSize returns the size in bytes of a block device, such as the device file through which a
+// raw-block volume is published to a pod.
+func GetBlockDeviceSize(devicePath string) (int64, error) {
+	return getISCSIDiskSize(devicePath)
+}
+
+// ISCSIRescanDevices rescans every path to an iSCSI LUN, resizes the multipath map and any LUKS mapping
+// stacked on the LUN, and waits until each of them reports at least the minimum size.
 func ISCSIRescanDevices(targetIQN string, lunID int32, minSize int64) error {
 	fields := log.Fields{"targetIQN": targetIQN, "lunID": lunID}
 	log.WithFields(fields).Debug(">>>> osutils.ISCSIRescanDevices")
@@ -1279,15 +1575,13 @@ func ISCSIRescanDevices(targetIQN string, lunID int32, minSize int64) error {
 		return fmt.Errorf("could not get iSCSI device information for LUN: %d", lunID)
 	}
 
-	allLargeEnough := true
+	// Rescan all paths, since the multipath map can't grow beyond its smallest path
 	for _, diskDevice := range deviceInfo.Devices {
 		size, err := getISCSIDiskSize("/dev/" + diskDevice)
 		if err != nil {
 			return err
 		}
-		if size < minSize {
-			allLargeEnough = false
-		} else {
+		if size >= minSize {
 			continue
 		}
 
@@ -1297,52 +1591,104 @@ func ISCSIRescanDevices(targetIQN string, lunID int32, minSize int64) error {
 			return fmt.Errorf("failed to rescan disk %s: %s", diskDevice, err)
 		}
 	}
-
-	if !allLargeEnough {
-		time.Sleep(time.Second)
-		for _, diskDevice := range deviceInfo.Devices {
-			size, err := getISCSIDiskSize("/dev/" + diskDevice)
-			if err != nil {
-				return err
-			}
-			if size < minSize {
-				log.Error("Disk size not large enough after resize.")
-				return fmt.Errorf("disk size not large enough after resize: %d, %d", size, minSize)
-			}
+	for _, diskDevice := range deviceInfo.Devices {
+		if err := waitForDeviceSize(diskDevice, minSize); err != nil {
+			log.Error("Disk size not large enough after resize.")
+			return fmt.Errorf("disk size not large enough after resize: %s", err)
 		}
 	}
 
+	deviceToUse := deviceInfo.Devices[0]
 	if deviceInfo.MultipathDevice != "" {
-		multipathDevice := deviceInfo.MultipathDevice
-		size, err := getISCSIDiskSize("/dev/" + multipathDevice)
+		deviceToUse = deviceInfo.MultipathDevice
+
+		size, err := getISCSIDiskSize("/dev/" + deviceToUse)
 		if err != nil {
 			return err
 		}
 
 		fields = log.Fields{"size": size, "minSize": minSize}
 		if size < minSize {
-			log.WithFields(fields).Debug("Reloading the multipath device.")
-			err := reloadMultipathDevice(multipathDevice)
-			if err != nil {
-				return err
-			}
-			time.Sleep(time.Second)
-			size, err = getISCSIDiskSize("/dev/" + multipathDevice)
-			if err != nil {
+			log.WithFields(fields).Debug("Resizing the multipath device.")
+			if err := resizeMultipathDevice(deviceToUse); err != nil {
 				return err
 			}
-			if size < minSize {
+			if err := waitForDeviceSize(deviceToUse, minSize); err != nil {
 				log.Error("Multipath device not large enough after resize.")
-				return fmt.Errorf("multipath device not large enough after resize: %d < %d", size, minSize)
+				return fmt.Errorf("multipath device not large enough after resize: %s", err)
 			}
 		} else {
-			log.WithFields(fields).Debug("Not reloading the multipath device because the size is greater than or equal to the minimum size.")
+			log.WithFields(fields).Debug("Not resizing the multipath device because the size is greater than or equal to the minimum size.")
+		}
+	}
+
+	// Grow any LUKS mapping on the LUN so the filesystem inside it can be expanded
+	if luksDevice := findLUKSDeviceForDevice(deviceToUse); luksDevice != "" {
+		if err := resizeLUKSDevice(luksDevice); err != nil {
+			return err
 		}
 	}
 
 	return nil
 }
 
+// waitForDeviceSize waits until a block device like sdx or dm-0 reports at least the minimum size.
+func waitForDeviceSize(device string, minSize int64) error {
+
+	fields := log.Fields{"device": device, "minSize": minSize}
+	log.WithFields(fields).Debug(">>>> osutils.waitForDeviceSize")
+	defer log.WithFields(fields).Debug("<<<< osutils.waitForDeviceSize")
+
+	checkDeviceSize := func() error {
+		size, err := getISCSIDiskSize("/dev/" + device)
+		if err != nil {
+			return backoff.Permanent(err)
+		}
+		if size < minSize {
+			return fmt.Errorf("%s size %d is less than %d", device, size, minSize)
+		}
+		return nil
+	}
+
+	sizeNotify := func(err error, duration time.Duration) {
+		log.WithField("increment", duration).Debug("Device not yet resized, waiting.")
+	}
+
+	sizeBackoff := backoff.NewExponentialBackOff()
+	sizeBackoff.InitialInterval = 1 * time.Second
+	sizeBackoff.Multiplier = 1.414 // approx sqrt(2)
+	sizeBackoff.RandomizationFactor = 0.1
+	sizeBackoff.MaxElapsedTime = deviceResizeTimeoutSecs * time.Second
+
+	return backoff.RetryNotify(checkDeviceSize, sizeBackoff, sizeNotify)
+}
+
+// resizeMultipathDevice makes multipathd grow a map to the size of its paths, falling back to
+// reloading the map if multipathd can't resize it.
+func resizeMultipathDevice(multipathDevice string) error {
+	fields := log.Fields{"multipathDevice": multipathDevice}
+	log.WithFields(fields).Debug(">>>> osutils.resizeMultipathDevice")
+	defer log.WithFields(fields).Debug("<<<< osutils.resizeMultipathDevice")
+
+	mapName := getDeviceMapperName(multipathDevice)
+	if mapName == "" {
+		return reloadMultipathDevice(multipathDevice)
+	}
+
+	out, err := execCommandWithTimeout("multipathd", 30, "resize", "map", mapName)
+	if err == nil && !strings.Contains(strings.ToLower(string(out)), "fail") {
+		log.WithFields(fields).Debug("Multipath device resized.")
+		return nil
+	}
+
+	log.WithFields(log.Fields{
+		"map":    mapName,
+		"output": strings.TrimSpace(string(out)),
+		"error":  err,
+	}).Warning("Could not resize multipath map, reloading it.")
+	return reloadMultipathDevice(multipathDevice)
+}
+
 func reloadMultipathDevice(multipathDevice string) error {
 	fields := log.Fields{"multipathDevice": multipathDevice}
 	log.WithFields(fields).Debug(">>>> osutils.reloadMultipathDevice")
@@ -1865,24 +2211,48 @@ func ISCSITargetHasMountedDevice(targetIQN string) (bool, error) {
 	return false, nil
 }
 
-// multipathFlushDevice invokes the 'multipath' commands to flush paths for a single device.
-func multipathFlushDevice(deviceInfo *ScsiDeviceInfo) {
+// multipathFlushDevice invokes the 'multipath' commands to flush paths for a single device.  A busy
+// map is flushed again a few times before falling back to removing it with dmsetup, and an error is
+// returned if the map remains.
+func multipathFlushDevice(deviceInfo *ScsiDeviceInfo) error {
 
 	log.WithField("device", deviceInfo.MultipathDevice).Debug(">>>> osutils.multipathFlushDevice")
 	defer log.Debug("<<<< osutils.multipathFlushDevice")
 
-	if deviceInfo.MultipathDevice == "" {
-		return
+	device := deviceInfo.MultipathDevice
+	if device == "" {
+		return nil
 	}
 
-	_, err := execCommandWithTimeout("multipath", 30, "-f", "/dev/"+deviceInfo.MultipathDevice)
-	if err != nil {
-		// nothing to do if it generates an error but log it
+	mapExists := func() bool {
+		_, err := os.Stat(chrootPathPrefix + "/sys/block/" + device)
+		return !os.IsNotExist(err)
+	}
+
+	for attempt := 1; attempt <= multipathFlushAttempts && mapExists(); attempt++ {
+		out, err := execCommandWithTimeout("multipath", 30, "-f", "/dev/"+device)
+		if err == nil {
+			return nil
+		}
 		log.WithFields(log.Fields{
-			"device": deviceInfo.MultipathDevice,
-			"error":  err,
+			"device":  device,
+			"attempt": attempt,
+			"output":  strings.TrimSpace(string(out)),
+			"error":   err,
 		}).Warning("Error encountered in multipath flush device command.")
+		time.Sleep(time.Second)
 	}
+
+	if !mapExists() {
+		return nil
+	}
+	if out, err := execCommandWithTimeout("dmsetup", 30, "remove", "--retry", "/dev/"+device); err != nil {
+		return fmt.Errorf("could not flush multipath device %s; %v; %s", device, err,
+			strings.TrimSpace(string(out)))
+	}
+
+	log.WithField("device", device).Info("Removed multipath device with dmsetup.")
+	return nil
 }
 
 // flushDevice flushes any outstanding I/O to all paths to a device.
@@ -1903,34 +2273,33 @@ func flushDevice(deviceInfo *ScsiDeviceInfo) {
 	}
 }
 
-// removeDevice tells Linux that a device will be removed.
+// removeDevice tells Linux that a device will be removed.  A device that can't be deleted doesn't
+// keep the others from being deleted.
 func removeDevice(deviceInfo *ScsiDeviceInfo) {
 
 	log.Debug(">>>> osutils.removeDevice")
 	defer log.Debug("<<<< osutils.removeDevice")
 
-	var (
-		f   *os.File
-		err error
-	)
-
 	listAllISCSIDevices()
 	for _, deviceName := range deviceInfo.Devices {
 
 		filename := fmt.Sprintf(chrootPathPrefix+"/sys/block/%s/device/delete", deviceName)
-		if f, err = os.OpenFile(filename, os.O_APPEND|os.O_WRONLY, 0200); err != nil {
-			log.WithField("file", filename).Warning("Could not open file for writing.")
-			return
+		f, err := os.OpenFile(filename, os.O_APPEND|os.O_WRONLY, 0200)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				log.WithField("file", filename).Warning("Could not open file for writing.")
+			}
+			continue
 		}
 
 		if written, err := f.WriteString("1"); err != nil {
 			log.WithFields(log.Fields{"file": filename, "error": err}).Warning("Could not write to file.")
 			f.Close()
-			return
+			continue
 		} else if written == 0 {
 			log.WithField("file", filename).Warning("No data written to file.")
 			f.Close()
-			return
+			continue
 		}
 
 		f.Close()
@@ -1940,6 +2309,39 @@ func removeDevice(deviceInfo *ScsiDeviceInfo) {
 	}
 }
 
+// waitForDevicesRemoval waits for SCSI devices to be gone from sysfs, and returns an error naming
+// any that remain.
+func waitForDevicesRemoval(devices []string) error {
+
+	remaining := func() []string {
+		found := make([]string, 0)
+		for _, device := range devices {
+			if _, err := os.Stat(chrootPathPrefix + "/sys/block/" + device); !os.IsNotExist(err) {
+				found = append(found, device)
+			}
+		}
+		return found
+	}
+
+	checkDevicesRemoved := func() error {
+		if found := remaining(); len(found) > 0 {
+			return fmt.Errorf("devices %v not removed yet", found)
+		}
+		return nil
+	}
+
+	removeBackoff := backoff.NewExponentialBackOff()
+	removeBackoff.InitialInterval = 500 * time.Millisecond
+	removeBackoff.Multiplier = 2
+	removeBackoff.RandomizationFactor = 0.1
+	removeBackoff.MaxElapsedTime = deviceRemovalTimeoutSecs * time.Second
+
+	if err := backoff.Retry(checkDevicesRemoved, removeBackoff); err != nil {
+		return fmt.Errorf("could not remove SCSI devices %v", remaining())
+	}
+	return nil
+}
+
 // multipathdIsRunning returns true if the multipath daemon is running.
 func multipathdIsRunning() bool {
 
@@ -2002,9 +2404,9 @@ func getFSType(device string) (string, error) {
 }
 
 // formatVolume creates a filesystem for the supplied device of the supplied type.
-func formatVolume(device, fstype string) error {
+func formatVolume(device, fstype, formatOptions string) error {
 
-	logFields := log.Fields{"device": device, "fsType": fstype}
+	logFields := log.Fields{"device": device, "fsType": fstype, "formatOptions": formatOptions}
 	log.WithFields(logFields).Debug(">>>> osutils.formatVolume")
 	defer log.WithFields(logFields).Debug("<<<< osutils.formatVolume")
 
@@ -2014,13 +2416,16 @@ func formatVolume(device, fstype string) error {
 
 		var err error
 
+		// The options go before the device, which mkfs expects last
+		options := strings.Fields(formatOptions)
+
 		switch fstype {
 		case "xfs":
-			_, err = execCommand("mkfs.xfs", "-f", device)
+			_, err = execCommand("mkfs.xfs", append(append([]string{"-f"}, options...), device)...)
 		case "ext3":
-			_, err = execCommand("mkfs.ext3", "-F", device)
+			_, err = execCommand("mkfs.ext3", append(append([]string{"-F"}, options...), device)...)
 		case "ext4":
-			_, err = execCommand("mkfs.ext4", "-F", device)
+			_, err = execCommand("mkfs.ext4", append(append([]string{"-F"}, options...), device)...)
 		default:
 			return fmt.Errorf("unsupported file system type: %s", fstype)
 		}
@@ -2048,6 +2453,105 @@ func formatVolume(device, fstype string) error {
 	return nil
 }
 
+// CheckFilesystem checks the filesystem on a device, without repairing it, as a filesystem check policy
+// requires, and returns a FilesystemCorruptError if it has errors.  The device must not be mounted.
+// The auto policy lets e2fsck skip filesystems marked clean; XFS has no such mark and replays its log
+// when mounted, so only the force policy checks XFS filesystems.  The filesystem's journal is replayed
+// first, since a checker run with -n doesn't replay it and so reports a filesystem that was merely
+// not unmounted cleanly as damaged.
+func CheckFilesystem(device, fstype, policy string) error {
+
+	logFields := log.Fields{"device": device, "fsType": fstype, "policy": policy}
+	log.WithFields(logFields).Debug(">>>> osutils.CheckFilesystem")
+	defer log.WithFields(logFields).Debug("<<<< osutils.CheckFilesystem")
+
+	var command string
+	var args []string
+
+	switch {
+	case policy == "" || policy == FsckPolicyNever:
+		return nil
+	case fstype == "ext3" || fstype == "ext4":
+		command = "e2fsck"
+		args = []string{"-n"}
+		if policy == FsckPolicyForce {
+			args = append(args, "-f")
+		}
+	case fstype == "xfs" && policy == FsckPolicyForce:
+		command = "xfs_repair"
+		args = []string{"-n"}
+	default:
+		return nil
+	}
+
+	if err := replayFilesystemJournal(device, fstype); err != nil {
+		return err
+	}
+
+	out, err := execCommand(command, append(args, device)...)
+	if err == nil {
+		log.WithFields(logFields).Info("Filesystem check found no errors.")
+		return nil
+	}
+
+	exitErr, ok := err.(*exec.ExitError)
+	if !ok {
+		return fmt.Errorf("could not check the %s filesystem on %s; %v", fstype, device, err)
+	}
+	status := exitErr.ProcessState.Sys().(syscall.WaitStatus).ExitStatus()
+
+	// e2fsck's exit status is a bitmask, in which 4 means errors were left uncorrected.  xfs_repair
+	// exits with 1 if it found corruption, and with 2 if the log is dirty and must be replayed first.
+	corrupt := false
+	switch command {
+	case "e2fsck":
+		corrupt = status&4 != 0
+	case "xfs_repair":
+		if status == 2 {
+			return fmt.Errorf("could not check the %s filesystem on %s; its log could not be replayed; %s",
+				fstype, device, strings.TrimSpace(string(out)))
+		}
+		corrupt = status == 1
+	}
+	if corrupt {
+		log.WithFields(logFields).WithField("output", string(out)).Error("Filesystem check found errors.")
+		return FilesystemCorruptError(fmt.Sprintf("%s found errors in the %s filesystem on %s; repair it "+
+			"before using the volume", command, fstype, device))
+	}
+	return fmt.Errorf("could not check the %s filesystem on %s; %v; %s", fstype, device, err,
+		strings.TrimSpace(string(out)))
+}
+
+// replayFilesystemJournal mounts a device's filesystem on a temporary directory and unmounts it again,
+// which replays its journal.  A filesystem that can't be mounted is left for the filesystem check to
+// report, so only a failure to unmount it is returned.
+func replayFilesystemJournal(device, fstype string) error {
+
+	logFields := log.Fields{"device": device, "fsType": fstype}
+
+	mountpoint, err := ioutil.TempDir("", "trident-fsck-")
+	if err != nil {
+		log.WithFields(logFields).Warningf("Could not create a mount point to replay the journal; %v", err)
+		return nil
+	}
+	defer func() {
+		if err := os.Remove(mountpoint); err != nil {
+			log.WithField("mountpoint", mountpoint).Warningf("Could not remove mount point; %v", err)
+		}
+	}()
+
+	if out, err := execCommand("mount", "-t", fstype, device, mountpoint); err != nil {
+		log.WithFields(logFields).Warningf("Could not mount the filesystem to replay its journal; %v; %s",
+			err, strings.TrimSpace(string(out)))
+		return nil
+	}
+	if err := Umount(mountpoint); err != nil {
+		return fmt.Errorf("could not unmount the %s filesystem on %s after replaying its journal; %v",
+			fstype, device, err)
+	}
+	return nil
+}
+
 // MountDevice attaches the supplied device at the supplied location.  Use this for iSCSI devices.
 func MountDevice(device, mountpoint, options string, isMountPointFile bool) (err error) {
 
@@ -2142,18 +2646,46 @@ func Umount(mountpoint string) (err error) {
 	return
 }
 
-// loginISCSITarget logs in to an iSCSI target.
-func configureISCSITarget(iqn, portal, name, value string) error {
+// FreezeFilesystem suspends writes to the filesystem mounted on the supplied location, so its files can
+// be copied consistently.  Writers block until the filesystem is thawed.
+func FreezeFilesystem(mountpoint string) error {
+
+	log.WithField("mountpoint", mountpoint).Debug(">>>> osutils.FreezeFilesystem")
+	defer log.Debug("<<<< osutils.FreezeFilesystem")
+
+	if out, err := execCommand("fsfreeze", "--freeze", mountpoint); err != nil {
+		return fmt.Errorf("error freezing filesystem on %s; %s (%v)", mountpoint, strings.TrimSpace(string(out)), err)
+	}
+	return nil
+}
+
+// ThawFilesystem resumes writes to a filesystem frozen by FreezeFilesystem.
+func ThawFilesystem(mountpoint string) error {
+
+	log.WithField("mountpoint", mountpoint).Debug(">>>> osutils.ThawFilesystem")
+	defer log.Debug("<<<< osutils.ThawFilesystem")
+
+	if out, err := execCommand("fsfreeze", "--unfreeze", mountpoint); err != nil {
+		return fmt.Errorf("error thawing filesystem on %s; %s (%v)", mountpoint, strings.TrimSpace(string(out)), err)
+	}
+	return nil
+}
+
+// configureISCSITarget sets a parameter of the node record of an iSCSI target.
+func configureISCSITarget(iqn, portal, iface, name, value string) error {
 
 	log.WithFields(log.Fields{
 		"IQN":    iqn,
 		"Portal": portal,
+		"Iface":  iface,
 		"Name":   name,
 		"Value":  value,
 	}).Debug(">>>> osutils.configureISCSITarget")
 	defer log.Debug("<<<< osutils.configureISCSITarget")
 
-	args := []string{"-m", "node", "-T", iqn, "-p", portal + ":3260", "-o", "update", "-n", name, "-v", value}
+	args := []string{"-m", "node", "-T", iqn, "-p", portal + ":3260"}
+	args = append(args, iscsiInterfaceArgs(iface)...)
+	args = append(args, "-o", "update", "-n", name, "-v", value)
 	if _, err := execIscsiadmCommand(args...); err != nil {
 		log.WithField("error", err).Warn("Error configuring iSCSI target.")
 		return err
@@ -2161,16 +2693,17 @@ func configureISCSITarget(iqn, portal, name, value string) error {
 	return nil
 }
 
-// loginISCSITarget logs in to an iSCSI target.
-func loginISCSITarget(iqn, portal string) error {
+// loginISCSITarget logs in to an iSCSI target through an iSCSI interface.
+func loginISCSITarget(iqn, portal, iface string) error {
 
 	log.WithFields(log.Fields{
 		"IQN":    iqn,
 		"Portal": portal,
+		"Iface":  iface,
 	}).Debug(">>>> osutils.loginISCSITarget")
 	defer log.Debug("<<<< osutils.loginISCSITarget")
 
-	args := []string{"-m", "node", "-T", iqn, "-l", "-p", portal + ":3260"}
+	args := append([]string{"-m", "node", "-T", iqn, "-l", "-p", portal + ":3260"}, iscsiInterfaceArgs(iface)...)
 	listAllISCSIDevices()
 	if _, err := execIscsiadmCommand(args...); err != nil {
 		log.WithField("error", err).Error("Error logging in to iSCSI target.")
@@ -2249,9 +2782,11 @@ func loginWithChap(tiqn, portal, username, password, targetUsername, targetIniti
 	return nil
 }
 
-func EnsureISCSISessions(hostDataIPs []string) error {
+// EnsureISCSISessions logs in to the iSCSI portals through an iSCSI interface, if there are no sessions
+// to them yet.  With no interface, or the default one, every interface is used.
+func EnsureISCSISessions(hostDataIPs []string, iface string) error {
 	for _, ip := range hostDataIPs {
-		if err := EnsureISCSISession(ip); nil != err {
+		if err := ensureISCSISession(ip, iface); nil != err {
 			return err
 		}
 	}
@@ -2259,9 +2794,16 @@ func EnsureISCSISessions(hostDataIPs []string) error {
 }
 
 func EnsureISCSISession(hostDataIP string) error {
+	return ensureISCSISession(hostDataIP, "")
+}
 
-	log.WithField("hostDataIP", hostDataIP).Debug(">>>> osutils.EnsureISCSISession")
-	defer log.Debug("<<<< osutils.EnsureISCSISession")
+func ensureISCSISession(hostDataIP, iface string) error {
+
+	log.WithFields(log.Fields{
+		"hostDataIP": hostDataIP,
+		"iface":      iface,
+	}).Debug(">>>> osutils.ensureISCSISession")
+	defer log.Debug("<<<< osutils.ensureISCSISession")
 
 	// Ensure iSCSI is supported on system
 	if !ISCSISupported() {
@@ -2276,7 +2818,7 @@ func EnsureISCSISession(hostDataIP string) error {
 	if !sessionExists {
 
 		// Run discovery in case we haven't seen this target from this host
-		targets, err := iSCSIDiscovery(hostDataIP)
+		targets, err := iSCSIDiscovery(hostDataIP, iface)
 		if err != nil {
 			return fmt.Errorf("could not run iSCSI discovery: %v", err)
 		}
@@ -2306,17 +2848,18 @@ func EnsureISCSISession(hostDataIP string) error {
 		for _, target := range targets {
 			if target.TargetName == targetName {
 				// Set scan to manual
-				err = configureISCSITarget(target.TargetName, target.PortalIP, "node.session.scan", "manual")
+				err = configureISCSITarget(target.TargetName, target.PortalIP, iface, "node.session.scan", "manual")
 				if err != nil {
 					// Swallow this error, someone is running an old version of Debian/Ubuntu
 				}
 				// Update replacement timeout
-				err = configureISCSITarget(target.TargetName, target.PortalIP, "node.session.timeo.replacement_timeout", "5")
+				err = configureISCSITarget(target.TargetName, target.PortalIP, iface,
+					"node.session.timeo.replacement_timeout", "5")
 				if err != nil {
 					return fmt.Errorf("set replacement timeout failed: %v", err)
 				}
 				// Log in to target
-				err = loginISCSITarget(target.TargetName, target.PortalIP)
+				err = loginISCSITarget(target.TargetName, target.PortalIP, iface)
 				if err != nil {
 					return fmt.Errorf("login to iSCSI target failed: %v", err)
 				}
@@ -2338,6 +2881,52 @@ func EnsureISCSISession(hostDataIP string) error {
 	return nil
 }
 
+// iscsiInterfaceArgs returns the iscsiadm arguments that bind an operation to an iSCSI interface.  No
+// arguments are needed for the default interface, and iscsiadm uses every interface without them.
+func iscsiInterfaceArgs(iface string) []string {
+	if iface == "" || iface == "default" {
+		return nil
+	}
+	return []string{"-I", iface}
+}
+
+// ISCSIInterfaceName returns the name of the iSCSI interface Trident manages for a network interface.
+func ISCSIInterfaceName(netInterface string) string {
+	return tridentISCSIInterfacePrefix + netInterface
+}
+
+// EnsureISCSIInterface creates or updates the iSCSI interface Trident manages for a network interface,
+// so that iSCSI logins through it stay on that network interface, and returns the iSCSI interface's name.
+func EnsureISCSIInterface(netInterface string) (string, error) {
+
+	iface := ISCSIInterfaceName(netInterface)
+
+	fields := log.Fields{"netInterface": netInterface, "iface": iface}
+	log.WithFields(fields).Debug(">>>> osutils.EnsureISCSIInterface")
+	defer log.WithFields(fields).Debug("<<<< osutils.EnsureISCSIInterface")
+
+	if _, err := net.InterfaceByName(netInterface); err != nil {
+		return "", fmt.Errorf("network interface %s not found; %v", netInterface, err)
+	}
+
+	if _, err := execIscsiadmCommand("-m", "iface", "-I", iface, "-o", "show"); err != nil {
+		if out, err := execIscsiadmCommand("-m", "iface", "-I", iface, "-o", "new"); err != nil {
+			return "", fmt.Errorf("could not create iSCSI interface %s; %v; %s", iface, err,
+				strings.TrimSpace(string(out)))
+		}
+		log.WithFields(fields).Info("Created iSCSI interface.")
+	}
+
+	out, err := execIscsiadmCommand("-m", "iface", "-I", iface, "-o", "update", "-n", "iface.net_ifacename",
+		"-v", netInterface)
+	if err != nil {
+		return "", fmt.Errorf("could not bind iSCSI interface %s to network interface %s; %v; %s", iface,
+			netInterface, err, strings.TrimSpace(string(out)))
+	}
+
+	return iface, nil
+}
+
 // execIscsiadmCommand uses the 'iscsiadm' command to perform operations
 func execIscsiadmCommand(args ...string) ([]byte, error) {
 	return execCommand("iscsiadm", args...)
@@ -2490,3 +3079,107 @@ func listAllISCSIDevices() {
 		"iscsiadm -m session output": out2,
 	}).Debug("Listing all iSCSI Devices.")
 }
+
+// DirectorySize returns the total size of the regular files beneath a directory.
+func DirectorySize(dir string) (int64, error) {
+	var size int64
+	err := filepath.Walk(dir, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
+
+// CopyDirectory copies the contents of the source directory into the destination directory, preserving
+// modes, ownership, modification times, and symbolic links.  The progress function, if supplied, is
+// called with the number of bytes copied after each regular file is copied.
+func CopyDirectory(sourceDir, destinationDir string, progress func(bytesCopied int64)) error {
+
+	log.WithFields(log.Fields{
+		"source":      sourceDir,
+		"destination": destinationDir,
+	}).Debug(">>>> osutils.CopyDirectory")
+	defer log.Debug("<<<< osutils.CopyDirectory")
+
+	var bytesCopied int64
+
+	return filepath.Walk(sourceDir, func(sourcePath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relativePath, err := filepath.Rel(sourceDir, sourcePath)
+		if err != nil {
+			return err
+		}
+		destinationPath := filepath.Join(destinationDir, relativePath)
+
+		switch mode := info.Mode(); {
+		case mode.IsDir():
+			if err = os.MkdirAll(destinationPath, mode.Perm()); err != nil {
+				return err
+			}
+		case mode&os.ModeSymlink != 0:
+			target, err := os.Readlink(sourcePath)
+			if err != nil {
+				return err
+			}
+			if err = os.Symlink(target, destinationPath); err != nil && !os.IsExist(err) {
+				return err
+			}
+		case mode.IsRegular():
+			written, err := copyFile(sourcePath, destinationPath, mode.Perm())
+			if err != nil {
+				return err
+			}
+			bytesCopied += written
+			if progress != nil {
+				progress(bytesCopied)
+			}
+		default:
+			log.WithField("path", sourcePath).Warn("Skipping special file.")
+			return nil
+		}
+
+		if uid, gid, ok := getFileOwner(info); ok {
+			if err = os.Lchown(destinationPath, uid, gid); err != nil {
+				log.WithField("path", destinationPath).Warnf("Could not set ownership; %v", err)
+			}
+		}
+		if info.Mode()&os.ModeSymlink == 0 {
+			if err = os.Chmod(destinationPath, info.Mode().Perm()); err != nil {
+				return err
+			}
+			if err = os.Chtimes(destinationPath, info.ModTime(), info.ModTime()); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// copyFile copies a regular file, returning the number of bytes copied.
+func copyFile(sourcePath, destinationPath string, perm os.FileMode) (int64, error) {
+
+	source, err := os.Open(sourcePath)
+	if err != nil {
+		return 0, err
+	}
+	defer source.Close()
+
+	destination, err := os.OpenFile(destinationPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return 0, err
+	}
+
+	written, err := io.Copy(destination, source)
+	if closeErr := destination.Close(); err == nil {
+		err = closeErr
+	}
+	return written, err
+}
@@ -4,6 +4,8 @@ package utils
 
 import (
 	"errors"
+	"os"
+	"syscall"
 
 	log "github.com/sirupsen/logrus"
 )
@@ -29,3 +31,16 @@ func getISCSIDiskSize(devicePath string) (int64, error) {
 	defer log.Debug("<<<< osutils_darwin.getISCSIDiskSize")
 	return 0, errors.New("getBlockSize is not supported for darwin")
 }
+
+// onSameDevice returns true if two files are on the same device.
+func onSameDevice(a, b os.FileInfo) bool {
+	return a.Sys().(*syscall.Stat_t).Dev == b.Sys().(*syscall.Stat_t).Dev
+}
+
+// getFileOwner returns the user and group IDs that own a file.
+func getFileOwner(info os.FileInfo) (int, int, bool) {
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		return int(stat.Uid), int(stat.Gid), true
+	}
+	return 0, 0, false
+}
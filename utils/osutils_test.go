@@ -3,6 +3,9 @@
 package utils
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
 	log "github.com/sirupsen/logrus"
@@ -117,3 +120,179 @@ func TestParseIPv4Valid(t *testing.T) {
 		assert.False(t, test.predicate(test.input), "Predicate failed")
 	}
 }
+
+func TestCopyDirectory(t *testing.T) {
+
+	sourceDir, err := ioutil.TempDir("", "copy-source")
+	assert.NoError(t, err)
+	defer os.RemoveAll(sourceDir)
+	destinationDir, err := ioutil.TempDir("", "copy-destination")
+	assert.NoError(t, err)
+	defer os.RemoveAll(destinationDir)
+
+	assert.NoError(t, os.MkdirAll(filepath.Join(sourceDir, "dir1", "dir2"), 0750))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(sourceDir, "file1"), []byte("hello"), 0600))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(sourceDir, "dir1", "dir2", "file2"), []byte("world!"), 0644))
+	assert.NoError(t, os.Symlink("dir1/dir2/file2", filepath.Join(sourceDir, "link")))
+
+	size, err := DirectorySize(sourceDir)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(11), size)
+
+	var copied int64
+	err = CopyDirectory(sourceDir, destinationDir, func(bytesCopied int64) { copied = bytesCopied })
+	assert.NoError(t, err)
+	assert.Equal(t, int64(11), copied)
+
+	content, err := ioutil.ReadFile(filepath.Join(destinationDir, "dir1", "dir2", "file2"))
+	assert.NoError(t, err)
+	assert.Equal(t, "world!", string(content))
+
+	info, err := os.Stat(filepath.Join(destinationDir, "file1"))
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	target, err := os.Readlink(filepath.Join(destinationDir, "link"))
+	assert.NoError(t, err)
+	assert.Equal(t, "dir1/dir2/file2", target)
+}
+
+func TestFindLUKSDeviceForDevice(t *testing.T) {
+
+	sysRoot, err := ioutil.TempDir("", "sysfs")
+	assert.NoError(t, err)
+	defer os.RemoveAll(sysRoot)
+
+	savedPrefix := chrootPathPrefix
+	chrootPathPrefix = sysRoot
+	defer func() { chrootPathPrefix = savedPrefix }()
+
+	addDevice := func(device, name, uuid string, holders ...string) {
+		assert.NoError(t, os.MkdirAll(filepath.Join(sysRoot, "sys", "block", device, "holders"), 0755))
+		for _, holder := range holders {
+			assert.NoError(t, os.MkdirAll(filepath.Join(sysRoot, "sys", "block", device, "holders", holder), 0755))
+		}
+		if name != "" {
+			dmDir := filepath.Join(sysRoot, "sys", "block", device, "dm")
+			assert.NoError(t, os.MkdirAll(dmDir, 0755))
+			assert.NoError(t, ioutil.WriteFile(filepath.Join(dmDir, "name"), []byte(name+"\n"), 0644))
+			assert.NoError(t, ioutil.WriteFile(filepath.Join(dmDir, "uuid"), []byte(uuid+"\n"), 0644))
+		}
+	}
+
+	// sda and sdb are paths to multipath device dm-0, which holds LUKS mapping dm-1
+	addDevice("sda", "", "", "dm-0")
+	addDevice("sdb", "", "", "dm-0")
+	addDevice("dm-0", "3600a0980", "mpath-3600a0980", "dm-1")
+	addDevice("dm-1", "luks-pvc-1", "CRYPT-LUKS2-0123456789abcdef-luks-pvc-1")
+	addDevice("sdc", "", "")
+
+	assert.Equal(t, "3600a0980", getDeviceMapperName("dm-0"))
+	assert.Equal(t, "", getDeviceMapperName("sda"))
+	assert.Equal(t, "luks-pvc-1", findLUKSDeviceForDevice("dm-0"))
+	assert.Equal(t, "", findLUKSDeviceForDevice("sda"), "multipath map is not a LUKS device")
+	assert.Equal(t, "", findLUKSDeviceForDevice("sdc"))
+
+	assert.Equal(t, "/dev/mapper/luks-pvc-1", getLUKSDevicePath("/dev/dm-0"))
+	assert.Equal(t, "/dev/sdc", getLUKSDevicePath("/dev/sdc"))
+}
+
+func TestFindStaleMultipathDevice(t *testing.T) {
+
+	sysRoot, err := ioutil.TempDir("", "sysfs")
+	assert.NoError(t, err)
+	defer os.RemoveAll(sysRoot)
+
+	savedPrefix := chrootPathPrefix
+	chrootPathPrefix = sysRoot
+	defer func() { chrootPathPrefix = savedPrefix }()
+
+	addDevice := func(device, uuid string, slaves ...string) {
+		assert.NoError(t, os.MkdirAll(filepath.Join(sysRoot, "sys", "block", device, "slaves"), 0755))
+		for _, slave := range slaves {
+			assert.NoError(t, os.MkdirAll(filepath.Join(sysRoot, "sys", "block", device, "slaves", slave), 0755))
+		}
+		if uuid != "" {
+			dmDir := filepath.Join(sysRoot, "sys", "block", device, "dm")
+			assert.NoError(t, os.MkdirAll(dmDir, 0755))
+			assert.NoError(t, ioutil.WriteFile(filepath.Join(dmDir, "uuid"), []byte(uuid+"\n"), 0644))
+		}
+	}
+
+	// dm-0 has paths, dm-1 has lost its paths, and dm-2 is a LUKS mapping
+	addDevice("dm-0", "mpath-3600a0980", "sda", "sdb")
+	addDevice("dm-1", "mpath-3600a0981")
+	addDevice("dm-2", "CRYPT-LUKS2-0123456789abcdef-luks-pvc-1")
+	addDevice("sdc", "")
+
+	assert.Equal(t, "", findStaleMultipathDevice("/dev/dm-0"), "multipath device has paths")
+	assert.Equal(t, "dm-1", findStaleMultipathDevice("/dev/dm-1"))
+	assert.Equal(t, "", findStaleMultipathDevice("/dev/dm-2"), "not a multipath device")
+	assert.Equal(t, "", findStaleMultipathDevice("/dev/dm-3"), "device is gone")
+	assert.Equal(t, "", findStaleMultipathDevice("/dev/sdc"))
+	assert.Equal(t, "", findStaleMultipathDevice(""))
+
+	assert.NoError(t, waitForDevicesRemoval([]string{"sdd", "sde"}))
+}
+
+func TestGetNodeHealth(t *testing.T) {
+
+	sysRoot, err := ioutil.TempDir("", "sysfs")
+	assert.NoError(t, err)
+	defer os.RemoveAll(sysRoot)
+
+	savedPrefix := chrootPathPrefix
+	chrootPathPrefix = sysRoot
+	defer func() { chrootPathPrefix = savedPrefix }()
+
+	writeValue := func(value string, elem ...string) {
+		path := filepath.Join(append([]string{sysRoot, "sys"}, elem...)...)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.NoError(t, ioutil.WriteFile(path, []byte(value+"\n"), 0644))
+	}
+
+	assert.Equal(t, &NodeHealth{}, GetNodeHealth(), "no sessions or multipath devices")
+
+	writeValue("LOGGED_IN", "class", "iscsi_session", "session1", "state")
+	writeValue("iqn.1992-08.com.netapp:sn.1", "class", "iscsi_session", "session1", "targetname")
+	writeValue("FAILED", "class", "iscsi_session", "session2", "state")
+	writeValue("iqn.1992-08.com.netapp:sn.2", "class", "iscsi_session", "session2", "targetname")
+
+	// dm-0 is a multipath device with a dead path, sdb; dm-1 is a LUKS mapping, not a multipath device
+	writeValue("3600a0980", "block", "dm-0", "dm", "name")
+	writeValue("mpath-3600a0980", "block", "dm-0", "dm", "uuid")
+	writeValue("CRYPT-LUKS2-0123456789abcdef-luks-pvc-1", "block", "dm-1", "dm", "uuid")
+	writeValue("running", "block", "sda", "device", "state")
+	writeValue("offline", "block", "sdb", "device", "state")
+	for _, path := range []string{"sda", "sdb"} {
+		assert.NoError(t, os.MkdirAll(filepath.Join(sysRoot, "sys", "block", "dm-0", "slaves", path), 0755))
+	}
+
+	health := GetNodeHealth()
+	assert.Equal(t, 2, health.ISCSISessions)
+	assert.Equal(t, 1, health.ISCSISessionsDown)
+	assert.Equal(t, 1, health.MultipathDevices)
+	assert.Equal(t, 1, health.FailedPaths)
+	assert.Equal(t, []string{
+		"iSCSI session2 to iqn.1992-08.com.netapp:sn.2 is FAILED",
+		"path sdb of multipath device 3600a0980 (dm-0) is offline",
+	}, health.Problems)
+}
+
+func TestISCSIPortalIP(t *testing.T) {
+	tests := map[string]string{
+		"10.0.207.7":                    "10.0.207.7",
+		"10.0.207.7:3260":               "10.0.207.7",
+		"[fd20:8b1e:b258:2000::1]":      "[fd20:8b1e:b258:2000::1]",
+		"[fd20:8b1e:b258:2000::1]:3260": "[fd20:8b1e:b258:2000::1]",
+	}
+	for portal, portalIP := range tests {
+		assert.Equal(t, portalIP, ISCSIPortalIP(portal), portal)
+	}
+}
+
+func TestISCSIInterfaceArgs(t *testing.T) {
+	assert.Nil(t, iscsiInterfaceArgs(""))
+	assert.Nil(t, iscsiInterfaceArgs("default"))
+	assert.Equal(t, []string{"-I", "trident-eth1"}, iscsiInterfaceArgs(ISCSIInterfaceName("eth1")))
+}
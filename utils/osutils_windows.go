@@ -0,0 +1,43 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package utils
+
+import (
+	"errors"
+	"os"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// The Trident node plugin runs on Windows nodes, where host storage is managed through CSI Proxy
+// rather than with the Linux tools used elsewhere in this package.  This file exists to handle
+// the Windows specific code needed to build it.
+
+func getFilesystemSize(path string) (int64, error) {
+	log.Debug(">>>> osutils_windows.getFilesystemSize")
+	defer log.Debug("<<<< osutils_windows.getFilesystemSize")
+	return 0, errors.New("getFilesystemSize is not supported for windows")
+}
+
+func GetFilesystemStats(path string) (int64, int64, int64, int64, int64, int64, error) {
+	log.Debug(">>>> osutils_windows.GetFilesystemStats")
+	defer log.Debug("<<<< osutils_windows.GetFilesystemStats")
+	return 0, 0, 0, 0, 0, 0, errors.New("GetFilesystemStats is not supported for windows")
+}
+
+func getISCSIDiskSize(devicePath string) (int64, error) {
+	log.Debug(">>>> osutils_windows.getISCSIDiskSize")
+	defer log.Debug("<<<< osutils_windows.getISCSIDiskSize")
+	return 0, errors.New("getBlockSize is not supported for windows")
+}
+
+// onSameDevice returns true if two files are on the same device.  Windows mount points are
+// managed through CSI Proxy, so files are always treated as being on the same device.
+func onSameDevice(a, b os.FileInfo) bool {
+	return true
+}
+
+// getFileOwner returns the user and group IDs that own a file, which Windows doesn't have.
+func getFileOwner(info os.FileInfo) (int, int, bool) {
+	return 0, 0, false
+}
@@ -0,0 +1,217 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package utils
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	s3Service            = "s3"
+	s3HTTPTimeoutSeconds = 60
+
+	envAWSAccessKeyID     = "AWS_ACCESS_KEY_ID"
+	envAWSSecretAccessKey = "AWS_SECRET_ACCESS_KEY"
+	envAWSSessionToken    = "AWS_SESSION_TOKEN"
+	envAWSRegion          = "AWS_REGION"
+	envAWSDefaultRegion   = "AWS_DEFAULT_REGION"
+)
+
+// S3Config holds the location of an S3-compatible bucket and the credentials with which to access it.
+type S3Config struct {
+
+	// Endpoint is the URL of the object store, such as https://s3.us-east-1.amazonaws.com or the
+	// address of an S3-compatible store.  Buckets are addressed by path, which all such stores accept.
+	Endpoint string
+	Bucket   string
+
+	// If the region or keys are not specified, the standard AWS environment variables are used
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+// S3Client is a minimal client of the S3 object API, providing just the calls needed to keep
+// files such as backups in a bucket.
+type S3Client struct {
+	config     S3Config
+	endpoint   *url.URL
+	httpClient *http.Client
+	now        func() time.Time
+}
+
+// NewS3Client is a factory method for creating a new instance.
+func NewS3Client(config S3Config) (*S3Client, error) {
+
+	if config.Bucket == "" {
+		return nil, errors.New("S3 bucket must be specified")
+	}
+	if config.Region == "" {
+		config.Region = os.Getenv(envAWSRegion)
+	}
+	if config.Region == "" {
+		config.Region = os.Getenv(envAWSDefaultRegion)
+	}
+	if config.Region == "" {
+		return nil, errors.New("S3 region must be specified")
+	}
+
+	if config.AccessKeyID == "" && config.SecretAccessKey == "" {
+		config.AccessKeyID = os.Getenv(envAWSAccessKeyID)
+		config.SecretAccessKey = os.Getenv(envAWSSecretAccessKey)
+		config.SessionToken = os.Getenv(envAWSSessionToken)
+	}
+	if config.AccessKeyID == "" || config.SecretAccessKey == "" {
+		return nil, errors.New("S3 credentials must be specified in the config or the environment")
+	}
+
+	if config.Endpoint == "" {
+		config.Endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", config.Region)
+	}
+	endpoint, err := url.Parse(config.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid S3 endpoint; %v", err)
+	}
+	if endpoint.Scheme != "http" && endpoint.Scheme != "https" {
+		return nil, fmt.Errorf("invalid S3 endpoint %s; the scheme must be http or https", config.Endpoint)
+	}
+
+	return &S3Client{
+		config:     config,
+		endpoint:   endpoint,
+		httpClient: &http.Client{Timeout: s3HTTPTimeoutSeconds * time.Second},
+		now:        time.Now,
+	}, nil
+}
+
+// S3Error is returned when an S3 call completes with a non-success HTTP status.
+type S3Error struct {
+	StatusCode int    `xml:"-"`
+	Code       string `xml:"Code"`
+	Message    string `xml:"Message"`
+}
+
+func (e S3Error) Error() string {
+	return fmt.Sprintf("S3 API failed (%d). Code: %s. Message: %s", e.StatusCode, e.Code, e.Message)
+}
+
+// IsS3NotFoundError returns true if the error indicates the requested object or bucket does not exist.
+func IsS3NotFoundError(err error) bool {
+	if s3Err, ok := err.(S3Error); ok {
+		return s3Err.StatusCode == http.StatusNotFound
+	}
+	return false
+}
+
+// objectURL returns the URL of an object, or of the bucket if the key is empty.
+func (c *S3Client) objectURL(key string, query url.Values) *url.URL {
+	u := *c.endpoint
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + c.config.Bucket
+	if key != "" {
+		u.Path += "/" + key
+	}
+	u.RawPath = ""
+	u.RawQuery = query.Encode()
+	return &u
+}
+
+// invoke makes a signed call to the object store and returns the body of a successful response.
+func (c *S3Client) invoke(method string, u *url.URL, body []byte) ([]byte, error) {
+
+	request, err := http.NewRequest(method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	// S3 requires the payload hash as a header as well as in the signature
+	request.Header.Set("X-Amz-Content-Sha256", hashHex(body))
+	credentials := AWSCredentials{
+		AccessKeyID:     c.config.AccessKeyID,
+		SecretAccessKey: c.config.SecretAccessKey,
+		SessionToken:    c.config.SessionToken,
+	}
+	SignAWSRequest(request, body, credentials, c.config.Region, s3Service, c.now())
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = response.Body.Close() }()
+
+	responseBody, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode >= 300 {
+		s3Err := S3Error{StatusCode: response.StatusCode}
+		if xmlErr := xml.Unmarshal(responseBody, &s3Err); xmlErr != nil {
+			s3Err.Message = string(responseBody)
+		}
+		return nil, s3Err
+	}
+
+	return responseBody, nil
+}
+
+// PutObject writes an object, replacing any object with the same key.
+func (c *S3Client) PutObject(key string, data []byte) error {
+	_, err := c.invoke(http.MethodPut, c.objectURL(key, nil), data)
+	return err
+}
+
+// GetObject returns the contents of an object.
+func (c *S3Client) GetObject(key string) ([]byte, error) {
+	return c.invoke(http.MethodGet, c.objectURL(key, nil), nil)
+}
+
+// DeleteObject deletes an object.  Deleting an object that doesn't exist succeeds.
+func (c *S3Client) DeleteObject(key string) error {
+	_, err := c.invoke(http.MethodDelete, c.objectURL(key, nil), nil)
+	return err
+}
+
+type s3ListBucketResult struct {
+	Contents []struct {
+		Key string `xml:"Key"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// ListObjects returns the keys of the objects whose keys start with a prefix, in lexical order.
+func (c *S3Client) ListObjects(prefix string) ([]string, error) {
+
+	keys := make([]string, 0)
+	query := url.Values{"list-type": {"2"}, "prefix": {prefix}}
+
+	for {
+		responseBody, err := c.invoke(http.MethodGet, c.objectURL("", query), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var result s3ListBucketResult
+		if err = xml.Unmarshal(responseBody, &result); err != nil {
+			return nil, fmt.Errorf("could not parse S3 object list; %v", err)
+		}
+		for _, object := range result.Contents {
+			keys = append(keys, object.Key)
+		}
+
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return keys, nil
+		}
+		query.Set("continuation-token", result.NextContinuationToken)
+	}
+}
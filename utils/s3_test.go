@@ -0,0 +1,132 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package utils
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeS3Server is an in-memory S3 bucket that lists two objects per page.
+type fakeS3Server struct {
+	objects map[string][]byte
+	mutex   sync.Mutex
+}
+
+func (f *fakeS3Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if !strings.HasPrefix(r.Header.Get("Authorization"), SigV4Algorithm) ||
+		r.Header.Get("X-Amz-Content-Sha256") == "" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	key := strings.TrimPrefix(r.URL.Path, "/bucket")
+	key = strings.TrimPrefix(key, "/")
+
+	switch {
+	case r.Method == http.MethodGet && key == "":
+		keys := make([]string, 0)
+		for k := range f.objects {
+			if strings.HasPrefix(k, r.URL.Query().Get("prefix")) && k > r.URL.Query().Get("continuation-token") {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		truncated := len(keys) > 2
+		if truncated {
+			keys = keys[:2]
+		}
+		body := "<ListBucketResult>"
+		for _, k := range keys {
+			body += fmt.Sprintf("<Contents><Key>%s</Key></Contents>", k)
+		}
+		if truncated {
+			body += fmt.Sprintf("<IsTruncated>true</IsTruncated><NextContinuationToken>%s</NextContinuationToken>",
+				keys[1])
+		}
+		_, _ = w.Write([]byte(body + "</ListBucketResult>"))
+	case r.Method == http.MethodPut:
+		f.objects[key], _ = ioutil.ReadAll(r.Body)
+	case r.Method == http.MethodGet:
+		data, ok := f.objects[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte("<Error><Code>NoSuchKey</Code><Message>not found</Message></Error>"))
+			return
+		}
+		_, _ = w.Write(data)
+	case r.Method == http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestS3Client(t *testing.T) {
+
+	server := httptest.NewServer(&fakeS3Server{objects: make(map[string][]byte)})
+	defer server.Close()
+
+	client, err := NewS3Client(S3Config{
+		Endpoint:        server.URL,
+		Bucket:          "bucket",
+		Region:          "us-east-1",
+		AccessKeyID:     "key",
+		SecretAccessKey: "secret",
+	})
+	require.NoError(t, err)
+
+	for _, key := range []string{"backups/c", "backups/a", "backups/b", "other/d"} {
+		require.NoError(t, client.PutObject(key, []byte(key)))
+	}
+
+	keys, err := client.ListObjects("backups/")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"backups/a", "backups/b", "backups/c"}, keys)
+
+	data, err := client.GetObject("backups/b")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("backups/b"), data)
+
+	require.NoError(t, client.DeleteObject("backups/b"))
+	_, err = client.GetObject("backups/b")
+	assert.True(t, IsS3NotFoundError(err))
+	assert.Contains(t, err.Error(), "NoSuchKey")
+}
+
+func TestNewS3Client(t *testing.T) {
+
+	_, err := NewS3Client(S3Config{Region: "us-east-1", AccessKeyID: "key", SecretAccessKey: "secret"})
+	assert.Error(t, err, "the bucket is required")
+
+	_, err = NewS3Client(S3Config{
+		Endpoint:        "ftp://s3.example.com",
+		Bucket:          "bucket",
+		Region:          "us-east-1",
+		AccessKeyID:     "key",
+		SecretAccessKey: "secret",
+	})
+	assert.Error(t, err, "only HTTP endpoints are supported")
+
+	client, err := NewS3Client(S3Config{
+		Bucket:          "bucket",
+		Region:          "eu-west-1",
+		AccessKeyID:     "key",
+		SecretAccessKey: "secret",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "https://s3.eu-west-1.amazonaws.com/bucket/backups/a",
+		client.objectURL("backups/a", nil).String())
+}
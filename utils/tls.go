@@ -0,0 +1,299 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package utils
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// TLSOptions configure the TLS connections of Trident's HTTPS endpoints.
+type TLSOptions struct {
+	// MinVersion is the lowest TLS version accepted, such as tls.VersionTLS12
+	MinVersion uint16
+	// CipherSuites are the TLS 1.2 cipher suites accepted, or empty for Go's defaults.  TLS 1.3 cipher
+	// suites aren't configurable.
+	CipherSuites []uint16
+	// RequireClientCert rejects clients that don't present a certificate signed by the CA
+	RequireClientCert bool
+	// ReloadInterval is how often the certificate files are checked for changes, or zero to never reload them
+	ReloadInterval time.Duration
+}
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// ParseTLSVersion returns the TLS version named by a string such as "1.2".
+func ParseTLSVersion(version string) (uint16, error) {
+	if v, ok := tlsVersions[strings.TrimPrefix(version, "TLS")]; ok {
+		return v, nil
+	}
+	return 0, fmt.Errorf("unsupported TLS version %s; valid versions are 1.0, 1.1, 1.2 and 1.3", version)
+}
+
+// ParseCipherSuites returns the cipher suites named in a comma-separated list, such as
+// "TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384".  Only the cipher
+// suites Go considers secure are accepted.
+func ParseCipherSuites(names string) ([]uint16, error) {
+
+	suites := make([]uint16, 0)
+	if strings.TrimSpace(names) == "" {
+		return suites, nil
+	}
+
+	secure := make(map[string]uint16)
+	for _, suite := range tls.CipherSuites() {
+		secure[suite.Name] = suite.ID
+	}
+	insecure := make(map[string]bool)
+	for _, suite := range tls.InsecureCipherSuites() {
+		insecure[suite.Name] = true
+	}
+
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+		if id, ok := secure[name]; ok {
+			suites = append(suites, id)
+		} else if insecure[name] {
+			return nil, fmt.Errorf("cipher suite %s is insecure", name)
+		} else {
+			return nil, fmt.Errorf("unknown cipher suite %s", name)
+		}
+	}
+	return suites, nil
+}
+
+// CertReloader serves a certificate, its key and a CA bundle read from files, such as those of a
+// mounted Kubernetes secret, and rereads them when they change so certificates may be rotated
+// without restarting Trident.  A certificate and key that don't match, as may be seen while a
+// secret is being updated, are ignored until the files are consistent again.
+type CertReloader struct {
+	caCertFile string
+	certFile   string
+	keyFile    string
+
+	mutex    sync.RWMutex
+	cert     *tls.Certificate
+	caPool   *x509.CertPool
+	checksum [sha256.Size]byte
+
+	stopOnce sync.Once
+	stop     chan struct{}
+}
+
+// NewCertReloader reads a certificate, key and CA bundle.  Any of the files may be left empty, but
+// the certificate and key must be given together.
+func NewCertReloader(caCertFile, certFile, keyFile string) (*CertReloader, error) {
+
+	if (certFile == "") != (keyFile == "") {
+		return nil, errors.New("a certificate and its key must be given together")
+	}
+
+	r := &CertReloader{
+		caCertFile: caCertFile,
+		certFile:   certFile,
+		keyFile:    keyFile,
+		stop:       make(chan struct{}),
+	}
+	if _, err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Reload rereads the files, and reports whether they had changed.
+func (r *CertReloader) Reload() (bool, error) {
+
+	var contents [3][]byte
+	for i, file := range []string{r.caCertFile, r.certFile, r.keyFile} {
+		if file == "" {
+			continue
+		}
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return false, fmt.Errorf("could not read %s: %v", file, err)
+		}
+		contents[i] = data
+	}
+
+	checksum := sha256.Sum256(bytes.Join(contents[:], []byte{0}))
+
+	r.mutex.RLock()
+	unchanged := checksum == r.checksum
+	r.mutex.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	var caPool *x509.CertPool
+	if r.caCertFile != "" {
+		caPool = x509.NewCertPool()
+		if !caPool.AppendCertsFromPEM(contents[0]) {
+			return false, fmt.Errorf("no certificates found in CA certificate file %s", r.caCertFile)
+		}
+	}
+
+	var cert *tls.Certificate
+	if r.certFile != "" {
+		keyPair, err := tls.X509KeyPair(contents[1], contents[2])
+		if err != nil {
+			return false, fmt.Errorf("could not load certificate %s: %v", r.certFile, err)
+		}
+		cert = &keyPair
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.caPool = caPool
+	r.cert = cert
+	r.checksum = checksum
+	return true, nil
+}
+
+// Start rereads the files periodically until Stop is called.
+func (r *CertReloader) Start(interval time.Duration) {
+
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-r.stop:
+				return
+			case <-ticker.C:
+				changed, err := r.Reload()
+				if err != nil {
+					log.WithFields(log.Fields{
+						"certFile": r.certFile,
+						"error":    err,
+					}).Warning("Could not reload TLS certificates; the previous certificates are still in use.")
+				} else if changed {
+					log.WithFields(log.Fields{
+						"caCertFile": r.caCertFile,
+						"certFile":   r.certFile,
+					}).Info("Reloaded TLS certificates.")
+				}
+			}
+		}
+	}()
+}
+
+// Stop ends the periodic rereading of the files.
+func (r *CertReloader) Stop() {
+	r.stopOnce.Do(func() { close(r.stop) })
+}
+
+// Certificate returns the current certificate, or nil if there is none.
+func (r *CertReloader) Certificate() *tls.Certificate {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.cert
+}
+
+// CAPool returns the current CA bundle, or nil if there is none.
+func (r *CertReloader) CAPool() *x509.CertPool {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.caPool
+}
+
+// ServerTLSConfig returns the TLS config of a server that presents the current certificate and, if
+// there is a CA bundle, verifies client certificates against it.
+func (r *CertReloader) ServerTLSConfig(options TLSOptions) *tls.Config {
+
+	getCertificate := func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+		if cert := r.Certificate(); cert != nil {
+			return cert, nil
+		}
+		return nil, errors.New("no server certificate")
+	}
+
+	return &tls.Config{
+		GetCertificate: getCertificate,
+		MinVersion:     options.MinVersion,
+		CipherSuites:   options.CipherSuites,
+		// The CA bundle may change, so each connection gets a config with the current one
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			clientAuth := tls.NoClientCert
+			caPool := r.CAPool()
+			if options.RequireClientCert {
+				clientAuth = tls.RequireAndVerifyClientCert
+			} else if caPool != nil {
+				clientAuth = tls.VerifyClientCertIfGiven
+			}
+			return &tls.Config{
+				GetCertificate: getCertificate,
+				ClientAuth:     clientAuth,
+				ClientCAs:      caPool,
+				MinVersion:     options.MinVersion,
+				CipherSuites:   options.CipherSuites,
+			}, nil
+		},
+	}
+}
+
+// ClientTLSConfig returns the TLS config of a client that presents the current certificate, if any,
+// and verifies the server's certificate for serverName against the current CA bundle.  Without a CA
+// bundle the server's certificate isn't verified.
+func (r *CertReloader) ClientTLSConfig(serverName string, options TLSOptions) *tls.Config {
+
+	tlsConfig := &tls.Config{
+		MinVersion:   options.MinVersion,
+		CipherSuites: options.CipherSuites,
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			if cert := r.Certificate(); cert != nil {
+				return cert, nil
+			}
+			return &tls.Certificate{}, nil
+		},
+		// The server's certificate is verified below, as the CA bundle may change
+		InsecureSkipVerify: true,
+	}
+
+	if r.caCertFile == "" {
+		return tlsConfig
+	}
+
+	tlsConfig.VerifyPeerCertificate = func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+		if len(rawCerts) == 0 {
+			return errors.New("server presented no certificate")
+		}
+		certs := make([]*x509.Certificate, 0, len(rawCerts))
+		for _, rawCert := range rawCerts {
+			cert, err := x509.ParseCertificate(rawCert)
+			if err != nil {
+				return err
+			}
+			certs = append(certs, cert)
+		}
+		intermediates := x509.NewCertPool()
+		for _, cert := range certs[1:] {
+			intermediates.AddCert(cert)
+		}
+		_, err := certs[0].Verify(x509.VerifyOptions{
+			DNSName:       serverName,
+			Roots:         r.CAPool(),
+			Intermediates: intermediates,
+		})
+		return err
+	}
+	return tlsConfig
+}
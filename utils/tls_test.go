@@ -0,0 +1,177 @@
+// Copyright 2020 NetApp, Inc. All Rights Reserved.
+
+package utils
+
+import (
+	"crypto/tls"
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testServerName = "trident-test"
+
+// writeCerts writes a CA certificate, and the server and client certificates and keys it signed, to
+// files in dir, as a mounted secret would hold them.
+func writeCerts(t *testing.T, dir string, certInfo *CertInfo) {
+	for name, value := range map[string]string{
+		"caCert":     certInfo.CACert,
+		"serverCert": certInfo.ServerCert,
+		"serverKey":  certInfo.ServerKey,
+		"clientCert": certInfo.ClientCert,
+		"clientKey":  certInfo.ClientKey,
+	} {
+		data, err := base64.StdEncoding.DecodeString(value)
+		require.NoError(t, err)
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), data, 0600))
+	}
+}
+
+func makeCerts(t *testing.T) *CertInfo {
+	certInfo, err := MakeHTTPCertInfo("test-ca", testServerName, "test-client", []string{testServerName})
+	require.NoError(t, err)
+	return certInfo
+}
+
+// handshake connects a client to a server and reports whether they agreed on a TLS session.
+func handshake(serverConfig, clientConfig *tls.Config) error {
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", serverConfig)
+	if err != nil {
+		return err
+	}
+	defer listener.Close()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			serverErr <- err
+			return
+		}
+		defer conn.Close()
+		serverErr <- conn.(*tls.Conn).Handshake()
+	}()
+
+	conn, err := tls.Dial("tcp", listener.Addr().String(), clientConfig)
+	if err != nil {
+		<-serverErr
+		return err
+	}
+	defer conn.Close()
+	return <-serverErr
+}
+
+func TestCertReloader(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "certs")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	writeCerts(t, dir, makeCerts(t))
+	path := func(name string) string { return filepath.Join(dir, name) }
+
+	server, err := NewCertReloader(path("caCert"), path("serverCert"), path("serverKey"))
+	require.NoError(t, err)
+	client, err := NewCertReloader(path("caCert"), path("clientCert"), path("clientKey"))
+	require.NoError(t, err)
+
+	options := TLSOptions{MinVersion: tls.VersionTLS12, RequireClientCert: true}
+	serverConfig := server.ServerTLSConfig(options)
+	clientConfig := client.ClientTLSConfig(testServerName, options)
+	assert.NoError(t, handshake(serverConfig, clientConfig))
+
+	changed, err := server.Reload()
+	assert.NoError(t, err)
+	assert.False(t, changed, "unchanged files should not be reloaded")
+
+	// After a rotation, clients that still trust the old CA can't connect until they reload too
+	oldCert := server.Certificate()
+	writeCerts(t, dir, makeCerts(t))
+	changed, err = server.Reload()
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.NotEqual(t, oldCert, server.Certificate())
+	assert.Error(t, handshake(serverConfig, clientConfig))
+
+	changed, err = client.Reload()
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.NoError(t, handshake(serverConfig, clientConfig), "the configs should use the reloaded certificates")
+
+	// A key that doesn't match its certificate is ignored, as while a secret is being rewritten
+	rotatedCert := server.Certificate()
+	newCerts := makeCerts(t)
+	key, err := base64.StdEncoding.DecodeString(newCerts.ServerKey)
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(path("serverKey"), key, 0600))
+	_, err = server.Reload()
+	assert.Error(t, err)
+	assert.Equal(t, rotatedCert, server.Certificate(), "the previous certificate should still be served")
+}
+
+func TestCertReloaderClientCert(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "certs")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	writeCerts(t, dir, makeCerts(t))
+	path := func(name string) string { return filepath.Join(dir, name) }
+
+	server, err := NewCertReloader(path("caCert"), path("serverCert"), path("serverKey"))
+	require.NoError(t, err)
+	anonymous, err := NewCertReloader(path("caCert"), "", "")
+	require.NoError(t, err)
+
+	options := TLSOptions{MinVersion: tls.VersionTLS12}
+	clientConfig := anonymous.ClientTLSConfig(testServerName, options)
+
+	options.RequireClientCert = true
+	assert.Error(t, handshake(server.ServerTLSConfig(options), clientConfig),
+		"clients without certificates should be rejected")
+
+	options.RequireClientCert = false
+	assert.NoError(t, handshake(server.ServerTLSConfig(options), clientConfig),
+		"clients without certificates should be accepted")
+
+	_, err = NewCertReloader("", path("serverCert"), "")
+	assert.Error(t, err, "a certificate requires its key")
+}
+
+func TestParseTLSVersion(t *testing.T) {
+	for version, expected := range map[string]uint16{
+		"1.2":    tls.VersionTLS12,
+		"TLS1.3": tls.VersionTLS13,
+	} {
+		actual, err := ParseTLSVersion(version)
+		assert.NoError(t, err, version)
+		assert.Equal(t, expected, actual, version)
+	}
+
+	_, err := ParseTLSVersion("2.0")
+	assert.Error(t, err)
+}
+
+func TestParseCipherSuites(t *testing.T) {
+	suites, err := ParseCipherSuites("")
+	assert.NoError(t, err)
+	assert.Empty(t, suites)
+
+	suites, err = ParseCipherSuites(
+		"TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384, TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256")
+	assert.NoError(t, err)
+	assert.Equal(t, []uint16{tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+		tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256}, suites)
+
+	_, err = ParseCipherSuites("TLS_RSA_WITH_RC4_128_SHA")
+	assert.Error(t, err, "insecure cipher suites should be rejected")
+
+	_, err = ParseCipherSuites("TLS_NOT_A_CIPHER")
+	assert.Error(t, err)
+}
@@ -5,6 +5,7 @@ package utils
 type VolumeAccessInfo struct {
 	IscsiAccessInfo
 	NfsAccessInfo
+	SMBAccessInfo
 	MountOptions string `json:"mountOptions,omitempty"`
 }
 
@@ -20,6 +21,9 @@ type IscsiAccessInfo struct {
 	IscsiInitiatorSecret string   `json:"iscsiInitiatorSecret,omitempty"`
 	IscsiTargetUsername  string   `json:"iscsiTargetUsername,omitempty"`
 	IscsiTargetSecret    string   `json:"iscsiTargetSecret,omitempty"`
+
+	// IscsiNetworkInterface is the host network interface the volume's iSCSI logins are bound to, if any
+	IscsiNetworkInterface string `json:"iscsiNetworkInterface,omitempty"`
 }
 
 type NfsAccessInfo struct {
@@ -27,6 +31,11 @@ type NfsAccessInfo struct {
 	NfsPath     string `json:"nfsPath,omitempty"`
 }
 
+type SMBAccessInfo struct {
+	// SMBPath is the UNC path of the volume's share, such as \\server\share
+	SMBPath string `json:"smbPath,omitempty"`
+}
+
 type VolumePublishInfo struct {
 	Localhost      bool     `json:"localhost,omitempty"`
 	HostIQN        []string `json:"hostIQN,omitempty"`
@@ -35,7 +44,9 @@ type VolumePublishInfo struct {
 	Nodes          []*Node  `json:"nodes,omitempty"`
 	HostName       string   `json:"hostName,omitempty"`
 	FilesystemType string   `json:"fstype,omitempty"`
+	FormatOptions  string   `json:"formatOptions,omitempty"`
 	UseCHAP        bool     `json:"useCHAP,omitempty"`
+	VolumeCHAP     bool     `json:"volumeCHAP,omitempty"`
 	SharedTarget   bool     `json:"sharedTarget,omitempty"`
 	DevicePath     string   `json:"devicePath,omitempty"`
 	Unmanaged      bool     `json:"unmanaged,omitempty"`
@@ -50,4 +61,38 @@ type Node struct {
 	Name string   `json:"name"`
 	IQN  string   `json:"iqn,omitempty"`
 	IPs  []string `json:"ips,omitempty"`
+	// LastSeen is the UTC time, in RFC3339 format, that the node last registered with the controller
+	LastSeen string `json:"lastSeen,omitempty"`
+	// TopologyLabels are the node's topology segments, such as its zone, as labeled by the container orchestrator
+	TopologyLabels map[string]string `json:"topologyLabels,omitempty"`
+	// NQN is the node's NVMe host qualified name, if NVMe is installed
+	NQN string `json:"nqn,omitempty"`
+	// Health is the state of the node's storage connections, as last reported by the node
+	Health *NodeHealth `json:"health,omitempty"`
+	// PublishedVolumes is the number of volumes published to the node, as counted by the controller
+	PublishedVolumes int `json:"publishedVolumes,omitempty"`
+	// Multipath is the state of the node's multipath configuration, as checked when the node registered
+	Multipath *MultipathStatus `json:"multipath,omitempty"`
+}
+
+// MultipathStatus describes whether a node's multipath daemon is running and its configuration lets
+// multipath claim ONTAP LUNs.
+type MultipathStatus struct {
+	DaemonRunning  bool   `json:"daemonRunning"`
+	FindMultipaths string `json:"findMultipaths,omitempty"`
+	Compatible     bool   `json:"compatible"`
+	// Remediated is set if Trident dropped in its multipath configuration snippet
+	Remediated bool `json:"remediated,omitempty"`
+	// Problems describes why the configuration isn't compatible
+	Problems []string `json:"problems,omitempty"`
+}
+
+// NodeHealth describes the state of a node's iSCSI sessions and of the paths to its multipath devices.
+type NodeHealth struct {
+	ISCSISessions     int `json:"iscsiSessions"`
+	ISCSISessionsDown int `json:"iscsiSessionsDown"`
+	MultipathDevices  int `json:"multipathDevices"`
+	FailedPaths       int `json:"failedPaths"`
+	// Problems describes each session or path that is down
+	Problems []string `json:"problems,omitempty"`
 }
@@ -306,6 +306,7 @@ func LogHTTPRequest(request *http.Request, requestBody []byte) {
 	delete(headers, "Authorization")
 	delete(headers, "Api-Key")
 	delete(headers, "Secret-Key")
+	delete(headers, "X-Amz-Security-Token")
 
 	var body string
 	if requestBody == nil {
@@ -519,6 +520,177 @@ func GetNFSVersionFromMountOptions(mountOptions, defaultVersion string, supporte
 	}
 }
 
+// conflictingMountOptions maps each mount option to the option that contradicts it.
+var conflictingMountOptions = map[string]string{
+	"ro": "rw", "rw": "ro",
+	"sync": "async", "async": "sync",
+	"hard": "soft", "soft": "hard",
+	"atime": "noatime", "noatime": "atime",
+	"exec": "noexec", "noexec": "exec",
+	"suid": "nosuid", "nosuid": "suid",
+	"dev": "nodev", "nodev": "dev",
+	"ac": "noac", "noac": "ac",
+	"lock": "nolock", "nolock": "lock",
+	"bind": "rbind", "rbind": "bind",
+}
+
+// ValidateMountOptions accepts a set of mount options, with or without a leading "-o ", and returns
+// an error if any of them contradict each other, such as "ro" and "rw", or set the same option to
+// different values, such as "nfsvers=3" and "vers=4".
+func ValidateMountOptions(mountOptions string) error {
+
+	mountOptions = strings.TrimPrefix(strings.TrimSpace(mountOptions), "-o ")
+	if mountOptions == "" {
+		return nil
+	}
+
+	flags := make(map[string]bool)
+	values := make(map[string]string)
+
+	for _, mountOption := range strings.Split(mountOptions, ",") {
+
+		mountOption = strings.TrimSpace(mountOption)
+		if mountOption == "" {
+			return fmt.Errorf("empty mount option in %s", mountOptions)
+		}
+
+		if strings.Contains(mountOption, "=") {
+			parts := strings.SplitN(mountOption, "=", 2)
+			key, value := parts[0], parts[1]
+			// vers is an alias for nfsvers
+			if key == "vers" {
+				key = "nfsvers"
+			}
+			if previous, ok := values[key]; ok && previous != value {
+				return fmt.Errorf("mount option %s is set to both %s and %s", key, previous, value)
+			}
+			values[key] = value
+			continue
+		}
+
+		if conflict, ok := conflictingMountOptions[mountOption]; ok && flags[conflict] {
+			return fmt.Errorf("mount options %s and %s conflict", conflict, mountOption)
+		}
+		flags[mountOption] = true
+	}
+
+	return nil
+}
+
+// ParseOwnership accepts an ownership of the form "uid:gid" and returns its numeric user and group IDs.
+func ParseOwnership(ownership string) (uid, gid int, err error) {
+
+	parts := strings.Split(ownership, ":")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("ownership %s is not of the form uid:gid", ownership)
+	}
+	if uid, err = strconv.Atoi(strings.TrimSpace(parts[0])); err != nil || uid < 0 {
+		return 0, 0, fmt.Errorf("invalid user ID in ownership %s", ownership)
+	}
+	if gid, err = strconv.Atoi(strings.TrimSpace(parts[1])); err != nil || gid < 0 {
+		return 0, 0, fmt.Errorf("invalid group ID in ownership %s", ownership)
+	}
+
+	return uid, gid, nil
+}
+
+var (
+	exportPolicyNameRegex = regexp.MustCompile(`^[a-zA-Z0-9_.-]{1,256}$`)
+	igroupNameRegex       = regexp.MustCompile(`^[a-zA-Z0-9_.:-]{1,96}$`)
+)
+
+// ValidateExportPolicyName checks that a name is usable for an ONTAP export policy, which may have up to
+// 256 letters, digits, underscores, periods, and hyphens.
+func ValidateExportPolicyName(name string) error {
+	if !exportPolicyNameRegex.MatchString(name) {
+		return fmt.Errorf("invalid export policy name '%s'", name)
+	}
+	return nil
+}
+
+// ValidateIgroupName checks that a name is usable for an ONTAP igroup, which may have up to 96 letters,
+// digits, underscores, periods, hyphens, and colons.
+func ValidateIgroupName(name string) error {
+	if !igroupNameRegex.MatchString(name) {
+		return fmt.Errorf("invalid igroup name '%s'", name)
+	}
+	return nil
+}
+
+// ValidateFormatOptions checks that a set of mkfs options, such as "-E nodiscard -b 4096", is a list of
+// options, each followed by at most one value.  The device to format is added by Trident.
+func ValidateFormatOptions(formatOptions string) error {
+
+	previousIsOption := false
+	for i, arg := range strings.Fields(formatOptions) {
+		isOption := strings.HasPrefix(arg, "-") && len(arg) > 1
+		if !isOption && !previousIsOption {
+			if i == 0 {
+				return fmt.Errorf("format options %s must start with an option", formatOptions)
+			}
+			return fmt.Errorf("format option value %s does not follow an option", arg)
+		}
+		previousIsOption = isOption
+	}
+	return nil
+}
+
+const (
+	// FsckPolicyNever mounts a volume's filesystem without checking it, which is the default
+	FsckPolicyNever = "never"
+	// FsckPolicyAuto checks a volume's filesystem if it isn't marked clean
+	FsckPolicyAuto = "auto"
+	// FsckPolicyForce checks a volume's filesystem every time the volume is staged
+	FsckPolicyForce = "force"
+)
+
+// ValidateFsckPolicy checks that a filesystem check policy is known.  An empty policy is the default.
+func ValidateFsckPolicy(policy string) error {
+	switch policy {
+	case "", FsckPolicyNever, FsckPolicyAuto, FsckPolicyForce:
+		return nil
+	default:
+		return fmt.Errorf("invalid fsck policy '%s'; must be %s, %s or %s", policy, FsckPolicyNever,
+			FsckPolicyAuto, FsckPolicyForce)
+	}
+}
+
+// ioSchedulers are the I/O schedulers a storage class may ask for.  Whether a node has one depends on
+// its kernel, so a scheduler the node lacks fails when the volume is staged.
+var ioSchedulers = []string{"none", "mq-deadline", "kyber", "bfq", "noop", "deadline", "cfq"}
+
+// ValidateIOScheduler checks that an I/O scheduler is known.  An empty scheduler is the node's default.
+func ValidateIOScheduler(scheduler string) error {
+	if scheduler == "" || SliceContainsString(ioSchedulers, scheduler) {
+		return nil
+	}
+	return fmt.Errorf("invalid I/O scheduler '%s'; must be one of %s", scheduler, strings.Join(ioSchedulers, ", "))
+}
+
+// ValidateReadAheadKB checks that a block device read-ahead is a number of KiB.  An empty read-ahead is
+// the node's default.
+func ValidateReadAheadKB(readAheadKB string) error {
+	if readAheadKB == "" {
+		return nil
+	}
+	if _, err := strconv.ParseUint(readAheadKB, 10, 32); err != nil {
+		return fmt.Errorf("invalid read-ahead '%s'; must be a number of KiB", readAheadKB)
+	}
+	return nil
+}
+
+// ValidateNrRequests checks that a block device request queue depth is a positive number.  An empty
+// depth is the node's default.
+func ValidateNrRequests(nrRequests string) error {
+	if nrRequests == "" {
+		return nil
+	}
+	if n, err := strconv.ParseUint(nrRequests, 10, 32); err != nil || n == 0 {
+		return fmt.Errorf("invalid request queue depth '%s'; must be a positive number", nrRequests)
+	}
+	return nil
+}
+
 // GetRegexSubmatches accepts a regular expression with one or more groups and returns a map
 // of the group matches found in the supplied string.
 func GetRegexSubmatches(r *regexp.Regexp, s string) map[string]string {
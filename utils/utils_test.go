@@ -355,3 +355,112 @@ func TestGetNFSVersionFromMountOptions(t *testing.T) {
 		assert.Equal(t, test.errNotNil, err != nil)
 	}
 }
+
+func TestParseOwnership(t *testing.T) {
+	log.Debug("Running TestParseOwnership...")
+
+	uid, gid, err := ParseOwnership("0:2000")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, uid)
+	assert.Equal(t, 2000, gid)
+
+	for _, ownership := range []string{"", "1000", "a:b", "1000:", "-1:0", "1:2:3"} {
+		_, _, err = ParseOwnership(ownership)
+		assert.Error(t, err, ownership)
+	}
+}
+
+func TestValidateExportPolicyName(t *testing.T) {
+	log.Debug("Running TestValidateExportPolicyName...")
+
+	for _, name := range []string{"default", "trident-1234", "team_a.policy"} {
+		assert.NoError(t, ValidateExportPolicyName(name), name)
+	}
+	for _, name := range []string{"", "a b", "policy/1", strings.Repeat("a", 257)} {
+		assert.Error(t, ValidateExportPolicyName(name), name)
+	}
+}
+
+func TestValidateIgroupName(t *testing.T) {
+	log.Debug("Running TestValidateIgroupName...")
+
+	for _, name := range []string{"trident", "trident-1234", "team:a_igroup.1"} {
+		assert.NoError(t, ValidateIgroupName(name), name)
+	}
+	for _, name := range []string{"", "a b", "igroup/1", strings.Repeat("a", 97)} {
+		assert.Error(t, ValidateIgroupName(name), name)
+	}
+}
+
+func TestValidateFormatOptions(t *testing.T) {
+	log.Debug("Running TestValidateFormatOptions...")
+
+	for _, options := range []string{"", "-E nodiscard", "-b 4096 -E nodiscard,lazy_itable_init=0", "-K -d agcount=8"} {
+		assert.NoError(t, ValidateFormatOptions(options), options)
+	}
+	for _, options := range []string{"nodiscard", "-E nodiscard lazy_itable_init=0", "- 4096"} {
+		assert.Error(t, ValidateFormatOptions(options), options)
+	}
+}
+
+func TestValidateFsckPolicy(t *testing.T) {
+	log.Debug("Running TestValidateFsckPolicy...")
+
+	for _, policy := range []string{"", FsckPolicyNever, FsckPolicyAuto, FsckPolicyForce} {
+		assert.NoError(t, ValidateFsckPolicy(policy), policy)
+	}
+	assert.Error(t, ValidateFsckPolicy("always"))
+}
+
+func TestValidateBlockDeviceTuning(t *testing.T) {
+	log.Debug("Running TestValidateBlockDeviceTuning...")
+
+	for _, scheduler := range []string{"", "none", "mq-deadline"} {
+		assert.NoError(t, ValidateIOScheduler(scheduler), scheduler)
+	}
+	assert.Error(t, ValidateIOScheduler("fastest"))
+
+	for _, readAheadKB := range []string{"", "0", "4096"} {
+		assert.NoError(t, ValidateReadAheadKB(readAheadKB), readAheadKB)
+	}
+	for _, readAheadKB := range []string{"-1", "128k", "1.5"} {
+		assert.Error(t, ValidateReadAheadKB(readAheadKB), readAheadKB)
+	}
+
+	for _, nrRequests := range []string{"", "4", "1024"} {
+		assert.NoError(t, ValidateNrRequests(nrRequests), nrRequests)
+	}
+	for _, nrRequests := range []string{"0", "-64", "many"} {
+		assert.Error(t, ValidateNrRequests(nrRequests), nrRequests)
+	}
+}
+
+func TestValidateMountOptions(t *testing.T) {
+	log.Debug("Running TestValidateMountOptions...")
+
+	var tests = []struct {
+		mountOptions string
+		errNotNil    bool
+	}{
+		// Positive tests
+		{"", false},
+		{"-o hard,vers=4", false},
+		{"nfsvers=3 , timeo=300", false},
+		{"ro,noatime,nodev", false},
+		{"vers=4,nfsvers=4", false},
+		{"hard,hard", false},
+
+		// Negative tests
+		{"ro,rw", true},
+		{"-o hard,soft", true},
+		{"noatime, atime", true},
+		{"nfsvers=3,vers=4", true},
+		{"timeo=300,timeo=600", true},
+		{"hard,,vers=3", true},
+	}
+
+	for _, test := range tests {
+		err := ValidateMountOptions(test.mountOptions)
+		assert.Equal(t, test.errNotNil, err != nil, test.mountOptions)
+	}
+}